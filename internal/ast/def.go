@@ -11,12 +11,23 @@ type Definition interface {
 type DataType string
 
 const (
-	DataTypeVarchar DataType = "VARCHAR"
+	DataTypeVarchar  DataType = "VARCHAR"
+	DataTypeInt      DataType = "INT"
+	DataTypeBigInt   DataType = "BIGINT"
+	DataTypeDecimal  DataType = "DECIMAL"
+	DataTypeDate     DataType = "DATE"
+	DataTypeDatetime DataType = "DATETIME"
 )
 
 type ColumnDef struct {
-	ColName  string
-	DataType DataType
+	ColName       string
+	DataType      DataType
+	Precision     int    // DECIMAL(p, s) の p (DECIMAL 以外では 0)
+	Scale         int    // DECIMAL(p, s) の s (DECIMAL 以外では 0)
+	NotNull       bool   // NOT NULL 制約の有無
+	AutoIncrement bool   // AUTO_INCREMENT 属性の有無
+	Default       Expr   // DEFAULT 句の式 (DEFAULT 句を指定していない場合は nil)
+	DefaultText   string // DEFAULT 句の式の SQL 表現 (DEFAULT 句を指定していない場合は空文字)
 }
 
 func (*ColumnDef) isDefinition() {}
@@ -33,23 +44,42 @@ func (*ConstraintPrimaryKeyDef) isDefinition() {}
 
 type ConstraintUniqueKeyDef struct {
 	KeyName string
-	Column  ColumnId
+	Columns []ColumnId // インデックスのカラム (複数の場合は複合インデックス)
 }
 
 func (*ConstraintUniqueKeyDef) isDefinition() {}
 
 type ConstraintKeyDef struct {
 	KeyName string
-	Column  ColumnId
+	Columns []ColumnId // インデックスのカラム (複数の場合は複合インデックス)
 }
 
 func (*ConstraintKeyDef) isDefinition() {}
 
 type ConstraintForeignKeyDef struct {
-	KeyName   string   // FK 制約名 (必須)
-	Column    ColumnId // FK カラム
-	RefTable  string   // 参照先テーブル名
-	RefColumn string   // 参照先カラム名
+	KeyName    string            // FK 制約名 (必須)
+	Columns    []ColumnId        // FK カラム (複合外部キーの場合はキーの順)
+	RefTable   string            // 参照先テーブル名
+	RefColumns []string          // 参照先カラム名 (Columns と同じ順)
+	OnDelete   ReferentialAction // ON DELETE 句の参照アクション (省略した場合は空文字)
+	OnUpdate   ReferentialAction // ON UPDATE 句の参照アクション (省略した場合は空文字)
 }
 
 func (*ConstraintForeignKeyDef) isDefinition() {}
+
+// ReferentialAction は外部キーの参照アクション (ON DELETE / ON UPDATE 句) を表す
+type ReferentialAction string
+
+const (
+	ReferentialActionRestrict ReferentialAction = "RESTRICT"
+	ReferentialActionCascade  ReferentialAction = "CASCADE"
+	ReferentialActionSetNull  ReferentialAction = "SET NULL"
+)
+
+type ConstraintCheckDef struct {
+	KeyName  string // CHECK 制約名 (省略した場合は空文字)
+	Expr     Expr   // 条件式
+	ExprText string // 条件式の SQL 表現
+}
+
+func (*ConstraintCheckDef) isDefinition() {}
@@ -1,5 +1,31 @@
 package ast
 
+import (
+	"strconv"
+	"strings"
+)
+
+// Expr は値を持つ式 (カラム、リテラル、演算、関数呼び出し、CASE 式など) を表す
+type Expr interface {
+	isExpr()
+}
+
+func (ColumnId) isExpr()        {}
+func (*StringLiteral) isExpr()  {}
+func (*NumberLiteral) isExpr()  {}
+func (*NullLiteral) isExpr()    {}
+func (*BinaryExpr) isExpr()     {}
+func (*AggregateExpr) isExpr()  {}
+func (*UnaryExpr) isExpr()      {}
+func (*FuncCallExpr) isExpr()   {}
+func (*CaseExpr) isExpr()       {}
+func (*InExpr) isExpr()         {}
+func (*BetweenExpr) isExpr()    {}
+func (*SubqueryExpr) isExpr()   {}
+func (*ExistsExpr) isExpr()     {}
+func (*WindowFuncExpr) isExpr() {}
+
+// BinaryExpr は二項演算 (比較演算、LIKE / NOT LIKE、論理演算 AND / OR、算術演算 + - * / %) を表す
 type BinaryExpr struct {
 	Operator string
 	Left     LHS
@@ -44,6 +70,30 @@ func NewLhsExpr(expr *BinaryExpr) *LhsExpr {
 	}
 }
 
+type LhsAggregate struct {
+	Aggregate *AggregateExpr
+}
+
+func (*LhsAggregate) isLHS() {}
+
+func NewLhsAggregate(agg *AggregateExpr) *LhsAggregate {
+	return &LhsAggregate{
+		Aggregate: agg,
+	}
+}
+
+type LhsLiteral struct {
+	Literal Literal
+}
+
+func (*LhsLiteral) isLHS() {}
+
+func NewLhsLiteral(lit Literal) *LhsLiteral {
+	return &LhsLiteral{
+		Literal: lit,
+	}
+}
+
 // -- RHS --
 
 type RHS interface {
@@ -85,3 +135,520 @@ func NewRhsExpr(expr *BinaryExpr) *RhsExpr {
 		Expr: expr,
 	}
 }
+
+type RhsAggregate struct {
+	Aggregate *AggregateExpr
+}
+
+func (*RhsAggregate) isRHS() {}
+
+func NewRhsAggregate(agg *AggregateExpr) *RhsAggregate {
+	return &RhsAggregate{
+		Aggregate: agg,
+	}
+}
+
+// LeftOperand は BinaryExpr の左辺を式として取り出す
+func LeftOperand(lhs LHS) Expr {
+	switch v := lhs.(type) {
+	case *LhsColumn:
+		return v.Column
+	case *LhsLiteral:
+		return v.Literal
+	case *LhsExpr:
+		return v.Expr
+	case *LhsAggregate:
+		return v.Aggregate
+	case Expr:
+		return v
+	}
+	return nil
+}
+
+// RightOperand は BinaryExpr の右辺を式として取り出す
+func RightOperand(rhs RHS) Expr {
+	switch v := rhs.(type) {
+	case *RhsColumn:
+		return v.Column
+	case *RhsLiteral:
+		return v.Literal
+	case *RhsExpr:
+		return v.Expr
+	case *RhsAggregate:
+		return v.Aggregate
+	case Expr:
+		return v
+	}
+	return nil
+}
+
+// -- Unary --
+
+// UnaryExpr は単項演算 (符号反転 -x、論理否定 NOT x) を表す
+//
+// BinaryExpr の左辺・右辺にそのまま指定できる
+type UnaryExpr struct {
+	Operator string
+	Operand  Expr
+}
+
+func (*UnaryExpr) isLHS() {}
+func (*UnaryExpr) isRHS() {}
+
+func NewUnaryExpr(operator string, operand Expr) *UnaryExpr {
+	return &UnaryExpr{
+		Operator: operator,
+		Operand:  operand,
+	}
+}
+
+// -- IN / BETWEEN --
+
+// InExpr は IN 述語 (x IN (v1, v2, ...)、x IN (SELECT ...)、x NOT IN (...)) を表す
+//
+// BinaryExpr の左辺・右辺にそのまま指定できる
+type InExpr struct {
+	Operand  Expr
+	Values   []Expr
+	Subquery *SelectStmt // IN (SELECT ...) のサブクエリ (nil なら Values の値のリスト)
+	Not      bool        // true なら NOT IN
+}
+
+func (*InExpr) isLHS() {}
+func (*InExpr) isRHS() {}
+
+func NewInExpr(operand Expr, values []Expr, not bool) *InExpr {
+	return &InExpr{
+		Operand: operand,
+		Values:  values,
+		Not:     not,
+	}
+}
+
+func NewInSubqueryExpr(operand Expr, subquery *SelectStmt, not bool) *InExpr {
+	return &InExpr{
+		Operand:  operand,
+		Subquery: subquery,
+		Not:      not,
+	}
+}
+
+// BetweenExpr は BETWEEN 述語 (x BETWEEN lower AND upper、x NOT BETWEEN ...) を表す
+//
+// BinaryExpr の左辺・右辺にそのまま指定できる
+type BetweenExpr struct {
+	Operand Expr
+	Lower   Expr
+	Upper   Expr
+	Not     bool // true なら NOT BETWEEN
+}
+
+func (*BetweenExpr) isLHS() {}
+func (*BetweenExpr) isRHS() {}
+
+func NewBetweenExpr(operand, lower, upper Expr, not bool) *BetweenExpr {
+	return &BetweenExpr{
+		Operand: operand,
+		Lower:   lower,
+		Upper:   upper,
+		Not:     not,
+	}
+}
+
+// -- Subquery --
+
+// SubqueryExpr は括弧で囲まれたサブクエリ (スカラーサブクエリ) を表す | `(SELECT MAX(price) FROM products)`
+//
+// サブクエリの結果は 1 行 1 カラムである必要がある (0 行の場合は NULL)
+// BinaryExpr の左辺・右辺にそのまま指定できる
+type SubqueryExpr struct {
+	Select *SelectStmt
+}
+
+func (*SubqueryExpr) isLHS() {}
+func (*SubqueryExpr) isRHS() {}
+
+func NewSubqueryExpr(stmt *SelectStmt) *SubqueryExpr {
+	return &SubqueryExpr{
+		Select: stmt,
+	}
+}
+
+// ExistsExpr は EXISTS 述語 (EXISTS (SELECT ...)) を表す
+//
+// NOT EXISTS は UnaryExpr("NOT", ExistsExpr) として表す
+// BinaryExpr の左辺・右辺にそのまま指定できる
+type ExistsExpr struct {
+	Subquery *SelectStmt
+}
+
+func (*ExistsExpr) isLHS() {}
+func (*ExistsExpr) isRHS() {}
+
+func NewExistsExpr(subquery *SelectStmt) *ExistsExpr {
+	return &ExistsExpr{
+		Subquery: subquery,
+	}
+}
+
+// -- Function call --
+
+// FuncCallExpr はスカラー関数の呼び出し (UPPER(name), ABS(x) など) を表す
+//
+// BinaryExpr の左辺・右辺にそのまま指定できる
+type FuncCallExpr struct {
+	Name string // 関数名 (大文字)
+	Args []Expr
+}
+
+func (*FuncCallExpr) isLHS() {}
+func (*FuncCallExpr) isRHS() {}
+
+func NewFuncCallExpr(name string, args []Expr) *FuncCallExpr {
+	return &FuncCallExpr{
+		Name: strings.ToUpper(name),
+		Args: args,
+	}
+}
+
+// -- CASE --
+
+// CaseExpr は CASE 式を表す
+//
+//   - Operand が nil の場合: CASE WHEN cond THEN result ... [ELSE result] END
+//   - Operand がある場合: CASE operand WHEN value THEN result ... [ELSE result] END
+//
+// BinaryExpr の左辺・右辺にそのまま指定できる
+type CaseExpr struct {
+	Operand Expr
+	Whens   []*WhenClause
+	Else    Expr // ELSE を省略した場合は nil (NULL を返す)
+}
+
+func (*CaseExpr) isLHS() {}
+func (*CaseExpr) isRHS() {}
+
+type WhenClause struct {
+	Condition Expr // 単純 CASE 式の場合は Operand と比較する値
+	Result    Expr
+}
+
+// -- Aggregate --
+
+type AggregateFunc string
+
+const (
+	AggregateCount AggregateFunc = "COUNT"
+	AggregateSum   AggregateFunc = "SUM"
+	AggregateMin   AggregateFunc = "MIN"
+	AggregateMax   AggregateFunc = "MAX"
+	AggregateAvg   AggregateFunc = "AVG"
+)
+
+// AggregateExpr は集約関数の呼び出し (COUNT(*), SUM(col) など) を表す
+type AggregateExpr struct {
+	Func   AggregateFunc
+	Column *ColumnId // 集約対象のカラム (COUNT(*) の場合は nil)
+	Pos    int       // SELECT リスト内での位置 (HAVING 句で指定された場合は 0)
+}
+
+func NewAggregateExpr(fn AggregateFunc, col *ColumnId) *AggregateExpr {
+	return &AggregateExpr{
+		Func:   fn,
+		Column: col,
+	}
+}
+
+// String は集約関数の表記 (結果セットのカラム名) を返す (e.g. "COUNT(*)", "SUM(orders.amount)")
+func (a *AggregateExpr) String() string {
+	if a.Column == nil {
+		return string(a.Func) + "(*)"
+	}
+	if a.Column.TableName != "" {
+		return string(a.Func) + "(" + a.Column.TableName + "." + a.Column.ColName + ")"
+	}
+	return string(a.Func) + "(" + a.Column.ColName + ")"
+}
+
+// -- Window function --
+
+type WindowFunc string
+
+const (
+	WindowRowNumber WindowFunc = "ROW_NUMBER"
+	WindowRank      WindowFunc = "RANK"
+	WindowDenseRank WindowFunc = "DENSE_RANK"
+	WindowLag       WindowFunc = "LAG"
+	WindowLead      WindowFunc = "LEAD"
+)
+
+// WindowFuncExpr はウィンドウ関数の呼び出し (ROW_NUMBER() OVER (...), SUM(col) OVER (...) など) を表す
+//
+// 集約関数を OVER 句と共に指定した場合は、Func は空で Aggregate に集約関数を持つ
+// BinaryExpr の左辺・右辺にそのまま指定できる
+type WindowFuncExpr struct {
+	Func      WindowFunc     // ウィンドウ関数 (集約関数の場合は空)
+	Aggregate *AggregateExpr // 集約関数 (ウィンドウ関数の場合は nil)
+	Column    *ColumnId      // LAG / LEAD の対象のカラム
+	Offset    uint64         // LAG / LEAD で参照する行の距離 (省略した場合は 1)
+	Default   Literal        // LAG / LEAD で参照する行がない場合の値 (nil なら NULL)
+	Window    *WindowSpec    // OVER 句
+}
+
+func (*WindowFuncExpr) isLHS() {}
+func (*WindowFuncExpr) isRHS() {}
+
+// String はウィンドウ関数の表記 (結果セットのカラム名) を返す (e.g. "ROW_NUMBER() OVER (PARTITION BY category ORDER BY price DESC)")
+func (w *WindowFuncExpr) String() string {
+	var call string
+	switch {
+	case w.Aggregate != nil:
+		call = w.Aggregate.String()
+	case w.Column != nil:
+		args := []string{ExprString(*w.Column)}
+		if w.Offset != 1 || w.Default != nil {
+			args = append(args, strconv.FormatUint(w.Offset, 10))
+		}
+		if w.Default != nil {
+			args = append(args, ExprString(w.Default))
+		}
+		call = string(w.Func) + "(" + strings.Join(args, ", ") + ")"
+	default:
+		call = string(w.Func) + "()"
+	}
+	return call + " OVER (" + w.Window.String() + ")"
+}
+
+// ReferencedColumns はウィンドウ関数で参照されるカラム (引数・PARTITION BY・ORDER BY のカラム) を返す
+func (w *WindowFuncExpr) ReferencedColumns() []ColumnId {
+	var columns []ColumnId
+	if w.Aggregate != nil && w.Aggregate.Column != nil {
+		columns = append(columns, *w.Aggregate.Column)
+	}
+	if w.Column != nil {
+		columns = append(columns, *w.Column)
+	}
+	columns = append(columns, w.Window.PartitionBy...)
+	for _, item := range w.Window.OrderBy {
+		columns = append(columns, item.Column)
+	}
+	return columns
+}
+
+// WindowSpec は OVER 句のウィンドウの指定 (パーティション・並び順・フレーム) を表す
+type WindowSpec struct {
+	PartitionBy []ColumnId    // PARTITION BY で指定されたカラム (nil なら全体を 1 つのパーティションとする)
+	OrderBy     []OrderByItem // パーティション内の並び順 (nil なら並べ替えない)
+	Frame       *WindowFrame  // ウィンドウフレーム (nil なら省略時のフレーム)
+}
+
+// String はウィンドウの指定の表記 (OVER の括弧の中) を返す
+func (s *WindowSpec) String() string {
+	var parts []string
+	if len(s.PartitionBy) > 0 {
+		keys := make([]string, len(s.PartitionBy))
+		for i, col := range s.PartitionBy {
+			keys[i] = ExprString(col)
+		}
+		parts = append(parts, "PARTITION BY "+strings.Join(keys, ", "))
+	}
+	if len(s.OrderBy) > 0 {
+		parts = append(parts, "ORDER BY "+orderByString(s.OrderBy))
+	}
+	if s.Frame != nil {
+		parts = append(parts, "ROWS BETWEEN "+s.Frame.Start.String()+" AND "+s.Frame.End.String())
+	}
+	return strings.Join(parts, " ")
+}
+
+// WindowFrame は ROWS で指定したウィンドウフレーム (集約関数が対象とする行の範囲) を表す
+type WindowFrame struct {
+	Start FrameBound
+	End   FrameBound
+}
+
+type FrameBoundType string
+
+const (
+	FrameUnboundedPreceding FrameBoundType = "UNBOUNDED PRECEDING"
+	FramePreceding          FrameBoundType = "PRECEDING"
+	FrameCurrentRow         FrameBoundType = "CURRENT ROW"
+	FrameFollowing          FrameBoundType = "FOLLOWING"
+	FrameUnboundedFollowing FrameBoundType = "UNBOUNDED FOLLOWING"
+)
+
+// FrameBound はウィンドウフレームの境界を表す
+type FrameBound struct {
+	Type   FrameBoundType
+	Offset uint64 // n PRECEDING / n FOLLOWING の n
+}
+
+// String は境界の表記を返す (e.g. "2 PRECEDING", "CURRENT ROW")
+func (b FrameBound) String() string {
+	if b.Type == FramePreceding || b.Type == FrameFollowing {
+		return strconv.FormatUint(b.Offset, 10) + " " + string(b.Type)
+	}
+	return string(b.Type)
+}
+
+// ExprString は式の表記 (結果セットのカラム名) を返す (e.g. "price * qty", "UPPER(name)")
+func ExprString(expr Expr) string {
+	switch v := expr.(type) {
+	case ColumnId:
+		if v.TableName != "" {
+			return v.TableName + "." + v.ColName
+		}
+		return v.ColName
+	case *StringLiteral:
+		return v.Value
+	case *NumberLiteral:
+		return v.Value
+	case *NullLiteral:
+		return v.ToString()
+	case *AggregateExpr:
+		return v.String()
+	case *WindowFuncExpr:
+		return v.String()
+	case *BinaryExpr:
+		return operandString(LeftOperand(v.Left)) + " " + v.Operator + " " + operandString(RightOperand(v.Right))
+	case *UnaryExpr:
+		if v.Operator == "NOT" {
+			return "NOT " + operandString(v.Operand)
+		}
+		return v.Operator + operandString(v.Operand)
+	case *InExpr:
+		if v.Subquery != nil {
+			return operandString(v.Operand) + notString(v.Not) + " IN (" + SelectString(v.Subquery) + ")"
+		}
+		values := make([]string, len(v.Values))
+		for i, value := range v.Values {
+			values[i] = ExprString(value)
+		}
+		return operandString(v.Operand) + notString(v.Not) + " IN (" + strings.Join(values, ", ") + ")"
+	case *BetweenExpr:
+		return operandString(v.Operand) + notString(v.Not) + " BETWEEN " + operandString(v.Lower) + " AND " + operandString(v.Upper)
+	case *SubqueryExpr:
+		return "(" + SelectString(v.Select) + ")"
+	case *ExistsExpr:
+		return "EXISTS (" + SelectString(v.Subquery) + ")"
+	case *FuncCallExpr:
+		args := make([]string, len(v.Args))
+		for i, arg := range v.Args {
+			args[i] = ExprString(arg)
+		}
+		return v.Name + "(" + strings.Join(args, ", ") + ")"
+	case *CaseExpr:
+		var sb strings.Builder
+		sb.WriteString("CASE")
+		if v.Operand != nil {
+			sb.WriteString(" " + ExprString(v.Operand))
+		}
+		for _, when := range v.Whens {
+			sb.WriteString(" WHEN " + ExprString(when.Condition) + " THEN " + ExprString(when.Result))
+		}
+		if v.Else != nil {
+			sb.WriteString(" ELSE " + ExprString(v.Else))
+		}
+		sb.WriteString(" END")
+		return sb.String()
+	}
+	return ""
+}
+
+// operandString は演算子の被演算子の表記を返す (二項演算・述語は括弧で囲む)
+func operandString(expr Expr) string {
+	switch v := expr.(type) {
+	case *BinaryExpr, *InExpr, *BetweenExpr:
+		return "(" + ExprString(expr) + ")"
+	case *UnaryExpr:
+		if v.Operator == "NOT" {
+			return "(" + ExprString(expr) + ")"
+		}
+	}
+	return ExprString(expr)
+}
+
+// notString は NOT IN / NOT BETWEEN の "NOT" の表記を返す
+func notString(not bool) string {
+	if not {
+		return " NOT"
+	}
+	return ""
+}
+
+// WalkExpr は式を深さ優先で走査し、各ノードに fn を適用する
+//
+// fn が false を返した場合、そのノードの子ノードは走査しない
+// サブクエリ (SubqueryExpr・ExistsExpr・InExpr.Subquery) の中は別のスコープのため走査しない
+// ウィンドウ関数 (WindowFuncExpr) の引数の集約関数は GROUP BY の集約とは別に評価するため走査しない
+func WalkExpr(expr Expr, fn func(Expr) bool) {
+	if expr == nil || !fn(expr) {
+		return
+	}
+	switch v := expr.(type) {
+	case *BinaryExpr:
+		WalkExpr(LeftOperand(v.Left), fn)
+		WalkExpr(RightOperand(v.Right), fn)
+	case *UnaryExpr:
+		WalkExpr(v.Operand, fn)
+	case *InExpr:
+		WalkExpr(v.Operand, fn)
+		for _, value := range v.Values {
+			WalkExpr(value, fn)
+		}
+	case *BetweenExpr:
+		WalkExpr(v.Operand, fn)
+		WalkExpr(v.Lower, fn)
+		WalkExpr(v.Upper, fn)
+	case *FuncCallExpr:
+		for _, arg := range v.Args {
+			WalkExpr(arg, fn)
+		}
+	case *CaseExpr:
+		WalkExpr(v.Operand, fn)
+		for _, when := range v.Whens {
+			WalkExpr(when.Condition, fn)
+			WalkExpr(when.Result, fn)
+		}
+		WalkExpr(v.Else, fn)
+	}
+}
+
+// ContainsAggregate は式に集約関数が含まれるかどうかを返す
+func ContainsAggregate(expr Expr) bool {
+	found := false
+	WalkExpr(expr, func(e Expr) bool {
+		if _, ok := e.(*AggregateExpr); ok {
+			found = true
+		}
+		return !found
+	})
+	return found
+}
+
+// ContainsWindow は式にウィンドウ関数が含まれるかどうかを返す
+func ContainsWindow(expr Expr) bool {
+	found := false
+	WalkExpr(expr, func(e Expr) bool {
+		if _, ok := e.(*WindowFuncExpr); ok {
+			found = true
+		}
+		return !found
+	})
+	return found
+}
+
+// ContainsSubquery は式にサブクエリ (スカラーサブクエリ・IN (SELECT ...)・EXISTS) が含まれるかどうかを返す
+func ContainsSubquery(expr Expr) bool {
+	found := false
+	WalkExpr(expr, func(e Expr) bool {
+		switch v := e.(type) {
+		case *SubqueryExpr, *ExistsExpr:
+			found = true
+		case *InExpr:
+			found = v.Subquery != nil
+		}
+		return !found
+	})
+	return found
+}
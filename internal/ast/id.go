@@ -19,8 +19,15 @@ func NewColumnId(name string) *ColumnId {
 // Table
 // --------------------------------
 
+// TableId はテーブルを表す
+//
+// データベース名で修飾されたテーブル (`db.table`) の場合、TableName は修飾した名前になる
+// 導出テーブル (`FROM (SELECT ...) AS alias`) の場合、TableName は別名、Subquery はサブクエリになる
+// 共通テーブル式を参照する場合、CTE は参照先の共通テーブル式になる (プランナーが WITH 句を解決して設定する)
 type TableId struct {
 	TableName string
+	Subquery  *SelectStmt      // 導出テーブルのサブクエリ (nil なら通常のテーブル)
+	CTE       *CommonTableExpr // 参照する共通テーブル式 (nil なら共通テーブル式以外)
 }
 
 func NewTableId(name string) *TableId {
@@ -28,3 +35,8 @@ func NewTableId(name string) *TableId {
 		TableName: name,
 	}
 }
+
+// IsDerived は導出テーブルまたは共通テーブル式 (テーブルの代わりに問い合わせの結果を使うもの) かどうかを返す
+func (t TableId) IsDerived() bool {
+	return t.Subquery != nil || t.CTE != nil
+}
@@ -1,6 +1,10 @@
 package ast
 
+// Literal はリテラル (文字列・数値・NULL) を表す
+//
+// リテラルはそのまま式 (Expr) として使用できる
 type Literal interface {
+	Expr
 	ToString() string
 	ToBytes() []byte
 }
@@ -26,3 +30,47 @@ func (sl *StringLiteral) ToBytes() []byte {
 func (sl *StringLiteral) ToString() string {
 	return sl.Value
 }
+
+// ---------------------------------------
+// Number
+// ---------------------------------------
+
+// NumberLiteral は引用符で囲まれていない数値のリテラル (e.g. `42`, `-3.14`)
+//
+// 文字列のリテラルは比較相手のデータ型で解釈するが、数値のリテラルは数値としてのみ解釈する
+type NumberLiteral struct {
+	Value string
+}
+
+func NewNumberLiteral(value string) *NumberLiteral {
+	return &NumberLiteral{
+		Value: value,
+	}
+}
+
+func (nl *NumberLiteral) ToBytes() []byte {
+	return []byte(nl.Value)
+}
+
+func (nl *NumberLiteral) ToString() string {
+	return nl.Value
+}
+
+// ---------------------------------------
+// NULL
+// ---------------------------------------
+
+type NullLiteral struct{}
+
+func NewNullLiteral() *NullLiteral {
+	return &NullLiteral{}
+}
+
+// ToBytes は NULL を表す nil を返す
+func (nl *NullLiteral) ToBytes() []byte {
+	return nil
+}
+
+func (nl *NullLiteral) ToString() string {
+	return "NULL"
+}
@@ -1,5 +1,10 @@
 package ast
 
+import (
+	"strconv"
+	"strings"
+)
+
 type Statement interface {
 	isStatement()
 }
@@ -15,26 +20,466 @@ type CreateTableStmt struct {
 
 func (*CreateTableStmt) isStatement() {}
 
+// ---------------------------------------
+// Alter Table
+// ---------------------------------------
+
+type AlterTableStmt struct {
+	TableName string
+	Action    AlterTableAction // テーブルに対する変更 (1 文につき 1 つ)
+}
+
+func (*AlterTableStmt) isStatement() {}
+
+type AlterTableAction interface {
+	isAlterTableAction()
+}
+
+// AddColumnAction は ADD [COLUMN] col_def を表す (カラムはテーブルの末尾に追加する)
+type AddColumnAction struct {
+	Column *ColumnDef
+}
+
+func (*AddColumnAction) isAlterTableAction() {}
+
+// DropColumnAction は DROP [COLUMN] col_name を表す
+type DropColumnAction struct {
+	ColName string
+}
+
+func (*DropColumnAction) isAlterTableAction() {}
+
+// AddConstraintAction は ADD UNIQUE KEY / ADD KEY / ADD FOREIGN KEY を表す
+type AddConstraintAction struct {
+	Constraint Definition // ConstraintUniqueKeyDef, ConstraintKeyDef, ConstraintForeignKeyDef のいずれか
+}
+
+func (*AddConstraintAction) isAlterTableAction() {}
+
+// DropIndexAction は DROP {KEY | INDEX} index_name を表す
+type DropIndexAction struct {
+	IndexName string
+}
+
+func (*DropIndexAction) isAlterTableAction() {}
+
+// DropForeignKeyAction は DROP FOREIGN KEY constraint_name を表す
+type DropForeignKeyAction struct {
+	ConstraintName string
+}
+
+func (*DropForeignKeyAction) isAlterTableAction() {}
+
+// ---------------------------------------
+// Drop Table
+// ---------------------------------------
+
+type DropTableStmt struct {
+	TableName string
+	IfExists  bool // IF EXISTS が指定された場合は true (テーブルが存在しなくてもエラーにしない)
+}
+
+func (*DropTableStmt) isStatement() {}
+
+// ---------------------------------------
+// Create Index
+// ---------------------------------------
+
+type CreateIndexStmt struct {
+	IndexName string
+	TableName string
+	Unique    bool       // CREATE UNIQUE INDEX の場合は true
+	Columns   []ColumnId // インデックスのカラム (複数の場合は複合インデックス)
+}
+
+func (*CreateIndexStmt) isStatement() {}
+
+// ---------------------------------------
+// Drop Index
+// ---------------------------------------
+
+type DropIndexStmt struct {
+	IndexName string
+	TableName string
+}
+
+func (*DropIndexStmt) isStatement() {}
+
+// ---------------------------------------
+// Truncate Table
+// ---------------------------------------
+
+type TruncateTableStmt struct {
+	TableName string
+}
+
+func (*TruncateTableStmt) isStatement() {}
+
+// ---------------------------------------
+// Create View
+// ---------------------------------------
+
+// CreateViewStmt は CREATE [OR REPLACE] VIEW 文を表す
+type CreateViewStmt struct {
+	ViewName   string
+	OrReplace  bool      // OR REPLACE が指定された場合は true (同じ名前のビューがあれば定義を置き換える)
+	Columns    []string  // カラム名のリスト (nil なら問い合わせの結果セットのカラム名を使う)
+	Query      Statement // ビューの問い合わせ (*SelectStmt または *SetOperationStmt)
+	Definition string    // 問い合わせの SQL 表現 (カタログに格納し、参照されるたびにパースする)
+}
+
+func (*CreateViewStmt) isStatement() {}
+
+// ---------------------------------------
+// Drop View
+// ---------------------------------------
+
+type DropViewStmt struct {
+	ViewName string
+	IfExists bool // IF EXISTS が指定された場合は true (ビューが存在しなくてもエラーにしない)
+}
+
+func (*DropViewStmt) isStatement() {}
+
+// ---------------------------------------
+// Create Database
+// ---------------------------------------
+
+// CreateDatabaseStmt は CREATE DATABASE 文を表す
+type CreateDatabaseStmt struct {
+	DatabaseName string
+	IfNotExists  bool // IF NOT EXISTS が指定された場合は true (データベースが既に存在してもエラーにしない)
+}
+
+func (*CreateDatabaseStmt) isStatement() {}
+
+// ---------------------------------------
+// Drop Database
+// ---------------------------------------
+
+// DropDatabaseStmt は DROP DATABASE 文を表す
+type DropDatabaseStmt struct {
+	DatabaseName string
+	IfExists     bool // IF EXISTS が指定された場合は true (データベースが存在しなくてもエラーにしない)
+}
+
+func (*DropDatabaseStmt) isStatement() {}
+
+// ---------------------------------------
+// Use
+// ---------------------------------------
+
+// UseStmt は USE 文 (セッションのデフォルトのデータベースの切り替え) を表す
+type UseStmt struct {
+	DatabaseName string
+}
+
+func (*UseStmt) isStatement() {}
+
 // ---------------------------------------
 // Select
 // ---------------------------------------
 
 type SelectStmt struct {
-	Columns []ColumnId // SELECT で指定されたカラム (nil なら SELECT *)
-	From    TableId
-	Joins   []*JoinClause
-	Where   *WhereClause
+	With       *WithClause      // WITH 句 (nil なら共通テーブル式なし)
+	Distinct   bool             // SELECT DISTINCT の場合は true (重複する行を除く)
+	Columns    []ColumnId       // SELECT で指定されたカラム (Columns, Aggregates, Exprs がすべて nil なら SELECT *)
+	Aggregates []*AggregateExpr // SELECT で指定された集約関数 (nil なら集約関数なし)
+	Exprs      []*SelectExpr    // SELECT で指定された式 (カラム・集約関数以外) (nil なら式なし)
+	From       TableId
+	Joins      []*JoinClause
+	Where      *WhereClause
+	GroupBy    []ColumnId     // GROUP BY で指定されたカラム (nil ならグループ化しない)
+	Having     *HavingClause  // HAVING 句 (nil なら集約後の絞り込みなし)
+	OrderBy    []OrderByItem  // ORDER BY で指定されたソートキー (nil なら並べ替えない)
+	Limit      *LimitClause   // LIMIT 句 (nil なら件数を制限しない)
+	Aliases    map[int]string // SELECT リストの項目の別名 (`expr AS alias`) で、キーは SELECT リスト内の位置 (nil なら別名なし)
 }
 
 func (*SelectStmt) isStatement() {}
 
+// IsSelectAll は SELECT * (全カラム) かどうかを返す
+func (s *SelectStmt) IsSelectAll() bool {
+	return s.Columns == nil && s.Aggregates == nil && s.Exprs == nil
+}
+
+// HasAggregation は集約 (集約関数・GROUP BY・HAVING のいずれか) を含むかどうかを返す
+func (s *SelectStmt) HasAggregation() bool {
+	if len(s.Aggregates) > 0 || len(s.GroupBy) > 0 || s.Having != nil {
+		return true
+	}
+	for _, e := range s.Exprs {
+		if ContainsAggregate(e.Expr) {
+			return true
+		}
+	}
+	return false
+}
+
+// HasWindow は SELECT リストにウィンドウ関数を含むかどうかを返す
+func (s *SelectStmt) HasWindow() bool {
+	for _, e := range s.Exprs {
+		if ContainsWindow(e.Expr) {
+			return true
+		}
+	}
+	return false
+}
+
+// SelectItems は SELECT リストの項目 (カラム・集約関数・式) を SELECT リストの順に返す
+//
+// SELECT * の場合は nil を返す
+func (s *SelectStmt) SelectItems() []Expr {
+	if s.IsSelectAll() {
+		return nil
+	}
+	items := make([]Expr, len(s.Columns)+len(s.Aggregates)+len(s.Exprs))
+	for _, a := range s.Aggregates {
+		items[a.Pos] = a
+	}
+	for _, e := range s.Exprs {
+		items[e.Pos] = e.Expr
+	}
+	// カラムは集約関数・式の位置以外に前から順に並ぶ
+	next := 0
+	for _, col := range s.Columns {
+		for items[next] != nil {
+			next++
+		}
+		items[next] = col
+		next++
+	}
+	return items
+}
+
+// SelectString は SELECT 文の表記を返す (e.g. "SELECT MAX(price) FROM products WHERE qty > 0")
+func SelectString(s *SelectStmt) string {
+	var sb strings.Builder
+	sb.WriteString(withString(s.With))
+	sb.WriteString("SELECT ")
+	if s.Distinct {
+		sb.WriteString("DISTINCT ")
+	}
+	if s.IsSelectAll() {
+		sb.WriteString("*")
+	}
+	for i, item := range s.SelectItems() {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		sb.WriteString(ExprString(item))
+		if alias, ok := s.Aliases[i]; ok {
+			sb.WriteString(" AS " + alias)
+		}
+	}
+	// FROM 句のない SELECT 文 (集合演算のオペランド) では FROM を書かない
+	if s.From.TableName != "" {
+		sb.WriteString(" FROM " + tableString(s.From))
+	}
+	for _, join := range s.Joins {
+		switch join.Type {
+		case JoinTypeLeft:
+			sb.WriteString(" LEFT")
+		case JoinTypeRight:
+			sb.WriteString(" RIGHT")
+		}
+		sb.WriteString(" JOIN " + tableString(join.Table) + " ON " + ExprString(join.Condition))
+	}
+	if s.Where != nil {
+		sb.WriteString(" WHERE " + ExprString(s.Where.Condition))
+	}
+	if len(s.GroupBy) > 0 {
+		keys := make([]string, len(s.GroupBy))
+		for i, col := range s.GroupBy {
+			keys[i] = ExprString(col)
+		}
+		sb.WriteString(" GROUP BY " + strings.Join(keys, ", "))
+	}
+	if s.Having != nil {
+		sb.WriteString(" HAVING " + ExprString(s.Having.Condition))
+	}
+	if len(s.OrderBy) > 0 {
+		sb.WriteString(" ORDER BY " + orderByString(s.OrderBy))
+	}
+	if s.Limit != nil {
+		sb.WriteString(" LIMIT " + strconv.FormatUint(s.Limit.Count, 10))
+		if s.Limit.Offset > 0 {
+			sb.WriteString(" OFFSET " + strconv.FormatUint(s.Limit.Offset, 10))
+		}
+	}
+	return sb.String()
+}
+
+// orderByString は ORDER BY のソートキーの表記を返す (e.g. "price DESC, id")
+func orderByString(items []OrderByItem) string {
+	keys := make([]string, len(items))
+	for i, item := range items {
+		keys[i] = ExprString(item.Column)
+		if item.Desc {
+			keys[i] += " DESC"
+		}
+	}
+	return strings.Join(keys, ", ")
+}
+
+// withString は WITH 句の表記を返す (WITH 句がない場合は空文字列)
+func withString(with *WithClause) string {
+	if with == nil {
+		return ""
+	}
+	var sb strings.Builder
+	sb.WriteString("WITH ")
+	if with.Recursive {
+		sb.WriteString("RECURSIVE ")
+	}
+	for i, cte := range with.CTEs {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		sb.WriteString(cte.Name)
+		if len(cte.Columns) > 0 {
+			sb.WriteString("(" + strings.Join(cte.Columns, ", ") + ")")
+		}
+		sb.WriteString(" AS (" + StatementString(cte.Query) + ")")
+	}
+	sb.WriteString(" ")
+	return sb.String()
+}
+
+// StatementString は SELECT 文または集合演算の表記を返す
+func StatementString(stmt Statement) string {
+	switch s := stmt.(type) {
+	case *SelectStmt:
+		return SelectString(s)
+	case *SetOperationStmt:
+		return SetOperationString(s)
+	}
+	return ""
+}
+
+// tableString は FROM 句・JOIN 句のテーブルの表記を返す
+func tableString(table TableId) string {
+	if table.Subquery != nil {
+		return "(" + SelectString(table.Subquery) + ") AS " + table.TableName
+	}
+	return table.TableName
+}
+
+// SelectExpr は SELECT リストで指定された式 (e.g. `price * qty`, `UPPER(name)`) を表す
+type SelectExpr struct {
+	Expr Expr
+	Pos  int // SELECT リスト内での位置
+}
+
+type OrderByItem struct {
+	Column ColumnId
+	Desc   bool // true なら降順 (DESC)
+}
+
+type LimitClause struct {
+	Count  uint64 // 返す最大件数
+	Offset uint64 // 読み飛ばす件数 (OFFSET を省略した場合は 0)
+}
+
+// JoinType は JOIN の種類
+type JoinType int
+
+const (
+	JoinTypeInner JoinType = iota // INNER JOIN (JOIN)
+	JoinTypeLeft                  // LEFT [OUTER] JOIN
+	JoinTypeRight                 // RIGHT [OUTER] JOIN
+)
+
 type JoinClause struct {
+	Type      JoinType
 	Table     TableId
-	Condition *BinaryExpr
+	Condition Expr
 }
 
 type WhereClause struct {
-	Condition *BinaryExpr
+	Condition Expr
+}
+
+type HavingClause struct {
+	Condition Expr
+}
+
+// ---------------------------------------
+// Set Operation
+// ---------------------------------------
+
+// SetOperator は集合演算の種類
+type SetOperator int
+
+const (
+	SetOpUnion     SetOperator = iota // UNION
+	SetOpIntersect                    // INTERSECT
+	SetOpExcept                       // EXCEPT
+)
+
+func (op SetOperator) String() string {
+	switch op {
+	case SetOpIntersect:
+		return "INTERSECT"
+	case SetOpExcept:
+		return "EXCEPT"
+	default:
+		return "UNION"
+	}
+}
+
+// SetOperationStmt は SELECT 文の集合演算 (`SELECT ... UNION [ALL | DISTINCT] SELECT ...` など) を表す
+//
+// INTERSECT は UNION・EXCEPT より優先して結合し、同じ優先順位の演算は左から順に結合する
+// (e.g. `A UNION B INTERSECT C EXCEPT D` は `(A UNION (B INTERSECT C)) EXCEPT D`)
+type SetOperationStmt struct {
+	With    *WithClause // WITH 句 (nil なら共通テーブル式なし)
+	Op      SetOperator
+	All     bool          // ALL が指定された場合は true (重複を除かない)
+	Left    Statement     // 左側のオペランド (*SelectStmt または *SetOperationStmt)
+	Right   Statement     // 右側のオペランド (*SelectStmt または *SetOperationStmt)
+	OrderBy []OrderByItem // 集合演算の結果に対する ORDER BY (最も外側の集合演算のみ。nil なら並べ替えない)
+	Limit   *LimitClause  // 集合演算の結果に対する LIMIT (最も外側の集合演算のみ。nil なら件数を制限しない)
+}
+
+func (*SetOperationStmt) isStatement() {}
+
+// SetOperationString は集合演算の表記を返す (e.g. "SELECT id FROM a UNION ALL SELECT id FROM b")
+func SetOperationString(s *SetOperationStmt) string {
+	var sb strings.Builder
+	sb.WriteString(withString(s.With))
+	sb.WriteString(StatementString(s.Left) + " " + s.Op.String())
+	if s.All {
+		sb.WriteString(" ALL")
+	}
+	sb.WriteString(" " + StatementString(s.Right))
+	if len(s.OrderBy) > 0 {
+		sb.WriteString(" ORDER BY " + orderByString(s.OrderBy))
+	}
+	if s.Limit != nil {
+		sb.WriteString(" LIMIT " + strconv.FormatUint(s.Limit.Count, 10))
+		if s.Limit.Offset > 0 {
+			sb.WriteString(" OFFSET " + strconv.FormatUint(s.Limit.Offset, 10))
+		}
+	}
+	return sb.String()
+}
+
+// ---------------------------------------
+// With (Common Table Expression)
+// ---------------------------------------
+
+// WithClause は WITH 句 (`WITH [RECURSIVE] cte1 AS (...), cte2 AS (...)`) を表す
+type WithClause struct {
+	Recursive bool               // WITH RECURSIVE の場合は true (共通テーブル式が自身を参照できる)
+	CTEs      []*CommonTableExpr // 共通テーブル式 (定義順。後の共通テーブル式は前の共通テーブル式を参照できる)
+}
+
+// CommonTableExpr は共通テーブル式 (`name [(col1, col2, ...)] AS (SELECT ...)`) を表す
+type CommonTableExpr struct {
+	Name    string
+	Columns []string  // カラム名のリスト (nil なら SELECT 文の結果セットのカラム名を使う)
+	Query   Statement // 共通テーブル式の問い合わせ (*SelectStmt または *SetOperationStmt)
 }
 
 // ---------------------------------------
@@ -42,9 +487,13 @@ type WhereClause struct {
 // ---------------------------------------
 
 type InsertStmt struct {
-	Table  TableId
-	Cols   []ColumnId
-	Values [][]Literal
+	Table                TableId
+	Cols                 []ColumnId   // カラムリスト (INSERT ... SELECT で省略した場合は nil)
+	Values               [][]Literal  // VALUES の値リスト (INSERT ... SELECT の場合は nil)
+	Select               *SelectStmt  // INSERT ... SELECT の SELECT 文 (VALUES の場合は nil)
+	Ignore               bool         // INSERT IGNORE の場合は true (キーが重複する行・制約に違反する行を追加しない)
+	Replace              bool         // REPLACE の場合は true (キーが重複する既存の行を削除してから追加する)
+	OnDuplicateKeyUpdate []*SetClause // ON DUPLICATE KEY UPDATE 句 (キーが重複する既存の行に適用する)
 }
 
 func (*InsertStmt) isStatement() {}
@@ -54,8 +503,10 @@ func (*InsertStmt) isStatement() {}
 // ---------------------------------------
 
 type DeleteStmt struct {
-	From  TableId
-	Where *WhereClause
+	Target string // 削除対象のテーブル名 (`DELETE t FROM ...` の t。空の場合は From のテーブル)
+	From   TableId
+	Joins  []*JoinClause // JOIN 句 (nil なら単一テーブルの DELETE)
+	Where  *WhereClause
 }
 
 func (*DeleteStmt) isStatement() {}
@@ -66,6 +517,7 @@ func (*DeleteStmt) isStatement() {}
 
 type UpdateStmt struct {
 	Table      TableId
+	Joins      []*JoinClause // JOIN 句 (nil なら単一テーブルの UPDATE)
 	SetClauses []*SetClause
 	Where      *WhereClause
 }
@@ -74,7 +526,7 @@ func (*UpdateStmt) isStatement() {}
 
 type SetClause struct {
 	Column ColumnId
-	Value  Literal
+	Value  Expr // 更新後の値 (e.g. `'foo'`, `n + 1`)
 }
 
 // ---------------------------------------
@@ -89,6 +541,17 @@ type AlterUserStmt struct {
 
 func (*AlterUserStmt) isStatement() {}
 
+// ---------------------------------------
+// Explain
+// ---------------------------------------
+
+type ExplainStmt struct {
+	Analyze bool      // EXPLAIN ANALYZE の場合は true (実際に実行し、Executor ごとの行数と時間を計測する)
+	Stmt    Statement // 実行計画を表示する文 (SELECT, UPDATE, DELETE)
+}
+
+func (*ExplainStmt) isStatement() {}
+
 // ---------------------------------------
 // Transaction
 // ---------------------------------------
@@ -0,0 +1,240 @@
+package executor
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+	"slices"
+	"strings"
+
+	"github.com/ren-yamanashi/minesql/internal/storage/encode"
+)
+
+// AggregateFunc は集約関数の種類を表す
+type AggregateFunc uint8
+
+const (
+	AggregateCount AggregateFunc = iota // COUNT: NULL でない値の件数 (COUNT(*) の場合は行数)
+	AggregateSum                        // SUM: 値の合計
+	AggregateMin                        // MIN: 最小値
+	AggregateMax                        // MAX: 最大値
+	AggregateAvg                        // AVG: 値の平均
+)
+
+func (f AggregateFunc) String() string {
+	switch f {
+	case AggregateSum:
+		return "SUM"
+	case AggregateMin:
+		return "MIN"
+	case AggregateMax:
+		return "MAX"
+	case AggregateAvg:
+		return "AVG"
+	default:
+		return "COUNT"
+	}
+}
+
+// AvgScaleIncrement は AVG の結果に追加する小数部の桁数 (MySQL の div_precision_increment のデフォルト値)
+//
+// 例: INT カラムの AVG は小数部 4 桁、DECIMAL(10, 2) カラムの AVG は小数部 6 桁になる
+const AvgScaleIncrement = 4
+
+// AggregateSpec は集約関数と集約対象のカラムを表す
+//
+// SUM / AVG の対象カラムは数値型 (格納形式が encode.EncodeInt64 の 8 バイト) であること
+type AggregateSpec struct {
+	Func AggregateFunc
+	Pos  int // 集約対象のカラム位置 (COUNT(*) の場合は -1)
+}
+
+// aggregatesString は集約関数の名前をカンマ区切りで返す (EXPLAIN で表示する)
+func aggregatesString(specs []AggregateSpec) string {
+	names := make([]string, len(specs))
+	for i, spec := range specs {
+		names[i] = spec.Func.String()
+	}
+	return strings.Join(names, ", ")
+}
+
+var (
+	errSumOutOfRange = errors.New("sum value is out of range")
+	errAvgOutOfRange = errors.New("avg value is out of range")
+)
+
+// aggregateGroup は 1 グループ分の集約の途中結果
+type aggregateGroup struct {
+	key    Record           // グループ化キーの値
+	states []aggregateState // 集約関数ごとの途中結果
+}
+
+// newAggregateGroup はレコードのグループ化キーの値から空のグループを作成する
+func newAggregateGroup(record Record, groupBy []int, nAggregates int) *aggregateGroup {
+	key := make(Record, len(groupBy))
+	for i, pos := range groupBy {
+		key[i] = record[pos]
+	}
+	return &aggregateGroup{key: key, states: make([]aggregateState, nAggregates)}
+}
+
+// add はレコードをグループの集約結果に加える
+func (g *aggregateGroup) add(specs []AggregateSpec, record Record) error {
+	for i, spec := range specs {
+		if err := g.states[i].add(spec, record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// merge は同じグループ化キーを持つ別のグループの途中結果を統合する
+func (g *aggregateGroup) merge(specs []AggregateSpec, other *aggregateGroup) error {
+	for i, spec := range specs {
+		if err := g.states[i].merge(spec, other.states[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// output はグループの集約結果を [グループ化キー..., 集約関数の結果...] のレコードとして返す
+func (g *aggregateGroup) output(specs []AggregateSpec) (Record, error) {
+	record := make(Record, 0, len(g.key)+len(specs))
+	record = append(record, g.key...)
+	for i, spec := range specs {
+		value, err := g.states[i].result(spec)
+		if err != nil {
+			return nil, err
+		}
+		record = append(record, value)
+	}
+	return record, nil
+}
+
+// toRunRecord は途中結果を一時ファイルに書き出すためのレコード [グループ化キー..., (件数, 累積値)...] に変換する
+func (g *aggregateGroup) toRunRecord() Record {
+	record := make(Record, 0, len(g.key)+2*len(g.states))
+	record = append(record, g.key...)
+	for _, st := range g.states {
+		record = append(record, encode.EncodeInt64(st.count), st.acc)
+	}
+	return record
+}
+
+// aggregateGroupFromRunRecord は toRunRecord で変換したレコードからグループを復元する
+func aggregateGroupFromRunRecord(record Record, nKeys int) *aggregateGroup {
+	g := &aggregateGroup{key: record[:nKeys]}
+	for i := nKeys; i+1 < len(record); i += 2 {
+		g.states = append(g.states, aggregateState{count: encode.DecodeInt64(record[i]), acc: record[i+1]})
+	}
+	return g
+}
+
+// sameGroupKey は 2 つのグループ化キーが等しいかを判定する (GROUP BY では NULL 同士も同じグループとして扱う)
+func sameGroupKey(a, b Record) bool {
+	for i := range a {
+		if compareColumn(a[i], b[i]) != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// aggregateState は 1 つの集約関数の途中結果
+//
+// NULL の値は集約の対象にしない (COUNT(*) を除く)
+type aggregateState struct {
+	count int64  // 集約した値の件数
+	acc   []byte // 累積値 (SUM / AVG は合計値を encode.EncodeInt64 でエンコードした値、MIN / MAX は現在の最小値・最大値)。値がない場合は nil
+}
+
+// add はレコードの値を途中結果に加える
+func (st *aggregateState) add(spec AggregateSpec, record Record) error {
+	if spec.Pos < 0 {
+		st.count++
+		return nil
+	}
+	value := record[spec.Pos]
+	if value == nil {
+		return nil
+	}
+	return st.merge(spec, aggregateState{count: 1, acc: value})
+}
+
+// merge は別の途中結果を統合する
+func (st *aggregateState) merge(spec AggregateSpec, other aggregateState) error {
+	if other.count == 0 {
+		return nil
+	}
+	st.count += other.count
+
+	switch spec.Func {
+	case AggregateSum, AggregateAvg:
+		if other.acc == nil {
+			return nil
+		}
+		if st.acc == nil {
+			st.acc = other.acc
+			return nil
+		}
+		a, b := encode.DecodeInt64(st.acc), encode.DecodeInt64(other.acc)
+		sum := a + b
+		if (b > 0 && sum < a) || (b < 0 && sum > a) {
+			return errSumOutOfRange
+		}
+		st.acc = encode.EncodeInt64(sum)
+	case AggregateMin:
+		if st.acc == nil || bytes.Compare(other.acc, st.acc) < 0 {
+			st.acc = slices.Clone(other.acc)
+		}
+	case AggregateMax:
+		if st.acc == nil || bytes.Compare(other.acc, st.acc) > 0 {
+			st.acc = slices.Clone(other.acc)
+		}
+	}
+	return nil
+}
+
+// result は集約関数の結果 (格納形式) を返す
+//
+// COUNT 以外は、集約した値が 1 件もない場合は NULL (nil) を返す
+func (st *aggregateState) result(spec AggregateSpec) ([]byte, error) {
+	switch spec.Func {
+	case AggregateCount:
+		return encode.EncodeInt64(st.count), nil
+	case AggregateAvg:
+		if st.acc == nil {
+			return nil, nil
+		}
+		return averageOf(encode.DecodeInt64(st.acc), st.count)
+	default:
+		return st.acc, nil
+	}
+}
+
+// averageOf は合計値と件数から平均値を求め、小数部を AvgScaleIncrement 桁増やした整数として返す (端数は四捨五入)
+func averageOf(sum, count int64) ([]byte, error) {
+	// sum * 10^AvgScaleIncrement は int64 に収まらない場合があるため big.Int で計算する
+	numerator := new(big.Int).Mul(big.NewInt(sum), new(big.Int).Exp(big.NewInt(10), big.NewInt(AvgScaleIncrement), nil))
+	numerator.Mul(numerator, big.NewInt(2))
+	if numerator.Sign() >= 0 {
+		numerator.Add(numerator, big.NewInt(count))
+	} else {
+		numerator.Sub(numerator, big.NewInt(count))
+	}
+	avg := numerator.Quo(numerator, big.NewInt(2*count)) // (2 * sum * 10^n ± count) / (2 * count) で 0 から遠い方向に丸める
+	if !avg.IsInt64() {
+		return nil, errAvgOutOfRange
+	}
+	return encode.EncodeInt64(avg.Int64()), nil
+}
+
+// aggregateGroupSize はグループがメモリ上で占めるおおよそのサイズ (バイト) を返す
+func aggregateGroupSize(g *aggregateGroup) int {
+	size := 64 + recordSize(g.key) // map のエントリ + グループ化キー
+	for _, st := range g.states {
+		size += 32 + len(st.acc)
+	}
+	return size
+}
@@ -0,0 +1,41 @@
+package executor
+
+import (
+	"math"
+	"testing"
+
+	"github.com/ren-yamanashi/minesql/internal/storage/encode"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAverageOf(t *testing.T) {
+	tests := []struct {
+		name     string
+		sum      int64
+		count    int64
+		expected int64
+	}{
+		{"割り切れる場合", 9, 3, 30000},
+		{"小数部 5 桁目を四捨五入する (切り上げ)", 2, 3, 6667},
+		{"小数部 5 桁目を四捨五入する (切り捨て)", 1, 3, 3333},
+		{"負の値は 0 から遠い方向に丸める", -2, 3, -6667},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// WHEN
+			result, err := averageOf(tt.sum, tt.count)
+
+			// THEN
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, encode.DecodeInt64(result))
+		})
+	}
+
+	t.Run("結果が BIGINT の範囲を超える場合はエラーを返す", func(t *testing.T) {
+		// WHEN
+		_, err := averageOf(math.MaxInt64, 1)
+
+		// THEN
+		assert.ErrorContains(t, err, "avg value is out of range")
+	})
+}
@@ -0,0 +1,34 @@
+package executor
+
+import "fmt"
+
+// CheckConstraint は CHECK 制約の条件式
+type CheckConstraint struct {
+	Name string     // 制約名
+	Expr Expression // 条件式 (テーブルのレコードに対して評価する)
+}
+
+// CheckConstraintViolationError は CHECK 制約を満たさないレコードを追加・更新しようとした場合のエラー
+type CheckConstraintViolationError struct {
+	Name string // 違反した制約名
+}
+
+func (e *CheckConstraintViolationError) Error() string {
+	return fmt.Sprintf("check constraint '%s' is violated", e.Name)
+}
+
+// checkConstraints はレコードが CHECK 制約をすべて満たすかを確認する
+//
+// MySQL と同様に、条件式の結果が NULL の場合は制約を満たすものとして扱う (偽の場合のみ違反とする)
+func checkConstraints(checks []CheckConstraint, record Record) error {
+	for _, check := range checks {
+		value, err := check.Expr.Eval(record)
+		if err != nil {
+			return err
+		}
+		if value != nil && !isTrue(value) {
+			return &CheckConstraintViolationError{Name: check.Name}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package executor
+
+import (
+	"testing"
+
+	"github.com/ren-yamanashi/minesql/internal/storage/encode"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckConstraints(t *testing.T) {
+	t.Run("すべての条件式が真の場合はエラーを返さない", func(t *testing.T) {
+		// GIVEN
+		checks := []CheckConstraint{
+			{Name: "chk_min", Expr: NewCompare(">", NewColumnRef(0), intConst(0))},
+			{Name: "chk_max", Expr: NewCompare("<", NewColumnRef(0), intConst(100))},
+		}
+
+		// WHEN
+		err := checkConstraints(checks, Record{encode.EncodeInt64(10)})
+
+		// THEN
+		assert.NoError(t, err)
+	})
+
+	t.Run("条件式が偽の場合は、違反した制約名を含むエラーを返す", func(t *testing.T) {
+		// GIVEN
+		checks := []CheckConstraint{
+			{Name: "chk_min", Expr: NewCompare(">", NewColumnRef(0), intConst(0))},
+			{Name: "chk_max", Expr: NewCompare("<", NewColumnRef(0), intConst(100))},
+		}
+
+		// WHEN
+		err := checkConstraints(checks, Record{encode.EncodeInt64(100)})
+
+		// THEN
+		var checkErr *CheckConstraintViolationError
+		assert.ErrorAs(t, err, &checkErr)
+		assert.Equal(t, "chk_max", checkErr.Name)
+		assert.EqualError(t, err, "check constraint 'chk_max' is violated")
+	})
+
+	t.Run("条件式の結果が NULL の場合は制約を満たすものとして扱う", func(t *testing.T) {
+		// GIVEN
+		checks := []CheckConstraint{
+			{Name: "chk_min", Expr: NewCompare(">", NewColumnRef(0), intConst(0))},
+		}
+
+		// WHEN
+		err := checkConstraints(checks, Record{nil})
+
+		// THEN
+		assert.NoError(t, err)
+	})
+
+	t.Run("CHECK 制約がない場合はエラーを返さない", func(t *testing.T) {
+		// WHEN
+		err := checkConstraints(nil, Record{encode.EncodeInt64(-1)})
+
+		// THEN
+		assert.NoError(t, err)
+	})
+}
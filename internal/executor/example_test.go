@@ -388,7 +388,7 @@ func ExampleNestedLoopJoin() {
 		"orders",
 		1,
 		[]handler.CreateIndexParam{
-			{Name: "idx_user_id", ColName: "user_id", ColIdx: 1, Unique: false},
+			{Name: "idx_user_id", ColNames: []string{"user_id"}, ColIdxs: []uint16{1}, Unique: false},
 		},
 		[]handler.CreateColumnParam{
 			{Name: "id", Type: handler.ColumnTypeString},
@@ -457,7 +457,7 @@ func ExampleNestedLoopJoin_withFilter() {
 		"orders",
 		1,
 		[]handler.CreateIndexParam{
-			{Name: "idx_user_id", ColName: "user_id", ColIdx: 1, Unique: false},
+			{Name: "idx_user_id", ColNames: []string{"user_id"}, ColIdxs: []uint16{1}, Unique: false},
 		},
 		[]handler.CreateColumnParam{
 			{Name: "id", Type: handler.ColumnTypeString},
@@ -553,8 +553,8 @@ func setupExample() (*access.Table, func()) {
 		"users",
 		1,
 		[]handler.CreateIndexParam{
-			{Name: "idx_first_name", ColName: "first_name", ColIdx: 1, Unique: true},
-			{Name: "idx_last_name", ColName: "last_name", ColIdx: 2, Unique: true},
+			{Name: "idx_first_name", ColNames: []string{"first_name"}, ColIdxs: []uint16{1}, Unique: true},
+			{Name: "idx_last_name", ColNames: []string{"last_name"}, ColIdxs: []uint16{2}, Unique: true},
 		},
 		[]handler.CreateColumnParam{
 			{Name: "id", Type: handler.ColumnTypeString},
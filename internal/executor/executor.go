@@ -1,5 +1,6 @@
 package executor
 
+// Record はレコードのカラム値 (格納形式) の並び。NULL のカラムは nil で表す
 type Record [][]byte
 
 type Executor interface {
@@ -7,4 +8,9 @@ type Executor interface {
 	//
 	// データがない場合、継続条件を満たさない場合は (nil, nil) を返す
 	Next() (Record, error)
+
+	// 保持している資源 (一時ファイルなど) を解放し、子の Executor も閉じる
+	//
+	// 最後まで読み取る前や、Next がエラーを返した後にも呼ぶ。複数回呼んでもよい
+	Close()
 }
@@ -0,0 +1,72 @@
+package executor
+
+import "github.com/ren-yamanashi/minesql/internal/storage/handler"
+
+// AlterTable はテーブル定義を変更する
+//
+// alter にテーブルに対する変更 (カラム・インデックス・外部キー制約の追加や削除) を保持する
+type AlterTable struct {
+	trxId     handler.TrxId
+	tableName string                                                                  // 変更するテーブル名
+	alter     func(hdl *handler.Handler, trxId handler.TrxId, tableName string) error // テーブルに対する変更
+}
+
+// NewAddColumn はテーブルの末尾にカラムを追加する AlterTable を生成する
+func NewAddColumn(trxId handler.TrxId, tableName string, colParam handler.CreateColumnParam) *AlterTable {
+	return newAlterTable(trxId, tableName, func(hdl *handler.Handler, trxId handler.TrxId, tableName string) error {
+		return hdl.AddColumn(trxId, tableName, colParam)
+	})
+}
+
+// NewDropColumn はテーブルからカラムを削除する AlterTable を生成する
+func NewDropColumn(trxId handler.TrxId, tableName string, colName string) *AlterTable {
+	return newAlterTable(trxId, tableName, func(hdl *handler.Handler, trxId handler.TrxId, tableName string) error {
+		return hdl.DropColumn(trxId, tableName, colName)
+	})
+}
+
+// NewAddIndex はテーブルにセカンダリインデックスを追加する AlterTable を生成する
+func NewAddIndex(trxId handler.TrxId, tableName string, idxParam handler.CreateIndexParam) *AlterTable {
+	return newAlterTable(trxId, tableName, func(hdl *handler.Handler, trxId handler.TrxId, tableName string) error {
+		return hdl.AddIndex(trxId, tableName, idxParam)
+	})
+}
+
+// NewDropIndex はテーブルからセカンダリインデックスを削除する AlterTable を生成する
+func NewDropIndex(trxId handler.TrxId, tableName string, indexName string) *AlterTable {
+	return newAlterTable(trxId, tableName, func(hdl *handler.Handler, trxId handler.TrxId, tableName string) error {
+		return hdl.DropIndex(trxId, tableName, indexName)
+	})
+}
+
+// NewAddForeignKey はテーブルに外部キー制約を追加する AlterTable を生成する
+func NewAddForeignKey(trxId handler.TrxId, tableName string, conParam handler.CreateConstraintParam) *AlterTable {
+	return newAlterTable(trxId, tableName, func(hdl *handler.Handler, trxId handler.TrxId, tableName string) error {
+		return hdl.AddForeignKey(trxId, tableName, conParam)
+	})
+}
+
+// NewDropForeignKey はテーブルから外部キー制約を削除する AlterTable を生成する
+func NewDropForeignKey(trxId handler.TrxId, tableName string, constraintName string) *AlterTable {
+	return newAlterTable(trxId, tableName, func(hdl *handler.Handler, trxId handler.TrxId, tableName string) error {
+		return hdl.DropForeignKey(trxId, tableName, constraintName)
+	})
+}
+
+func newAlterTable(trxId handler.TrxId, tableName string, alter func(hdl *handler.Handler, trxId handler.TrxId, tableName string) error) *AlterTable {
+	return &AlterTable{
+		trxId:     trxId,
+		tableName: tableName,
+		alter:     alter,
+	}
+}
+
+func (at *AlterTable) Next() (Record, error) {
+	hdl := handler.Get()
+	if err := at.alter(hdl, at.trxId, at.tableName); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
+
+func (at *AlterTable) Close() {}
@@ -0,0 +1,65 @@
+package executor
+
+import (
+	"testing"
+
+	"github.com/ren-yamanashi/minesql/internal/storage/handler"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAlterTable_Next(t *testing.T) {
+	t.Run("カラムを追加できる", func(t *testing.T) {
+		// GIVEN
+		tmpdir := t.TempDir()
+		hdl := InitStorageEngineForTest(t, tmpdir)
+		defer handler.Reset()
+		trxId := hdl.BeginTrx()
+		alterTable := NewAddColumn(trxId, "users", handler.CreateColumnParam{Name: "email", Type: handler.ColumnTypeString})
+
+		// WHEN
+		_, err := alterTable.Next()
+
+		// THEN
+		assert.NoError(t, err)
+		assert.NoError(t, hdl.CommitTrx(trxId))
+		tblMeta, _ := hdl.Catalog.GetTableMetaByName("users")
+		col, ok := tblMeta.GetColByName("email")
+		assert.True(t, ok)
+		assert.Equal(t, uint16(3), col.Pos)
+	})
+
+	t.Run("インデックスを削除できる", func(t *testing.T) {
+		// GIVEN
+		tmpdir := t.TempDir()
+		hdl := InitStorageEngineForTest(t, tmpdir)
+		defer handler.Reset()
+		trxId := hdl.BeginTrx()
+		alterTable := NewDropIndex(trxId, "users", "last_name")
+
+		// WHEN
+		_, err := alterTable.Next()
+
+		// THEN
+		assert.NoError(t, err)
+		assert.NoError(t, hdl.CommitTrx(trxId))
+		tblMeta, _ := hdl.Catalog.GetTableMetaByName("users")
+		_, ok := tblMeta.GetIndexByColName("last_name")
+		assert.False(t, ok)
+	})
+
+	t.Run("ハンドラのエラーをそのまま返す", func(t *testing.T) {
+		// GIVEN
+		tmpdir := t.TempDir()
+		hdl := InitStorageEngineForTest(t, tmpdir)
+		defer handler.Reset()
+		trxId := hdl.BeginTrx()
+		alterTable := NewAddColumn(trxId, "users", handler.CreateColumnParam{Name: "email", Type: handler.ColumnTypeString, NotNull: true})
+
+		// WHEN
+		_, err := alterTable.Next()
+
+		// THEN
+		assert.ErrorContains(t, err, "cannot add NOT NULL column email: table users is not empty")
+		assert.NoError(t, hdl.RollbackTrx(trxId))
+	})
+}
@@ -24,3 +24,5 @@ func (au *AlterUser) Next() (Record, error) {
 	}
 	return nil, nil
 }
+
+func (au *AlterUser) Close() {}
@@ -0,0 +1,26 @@
+package executor
+
+import "github.com/ren-yamanashi/minesql/internal/storage/handler"
+
+// CreateDatabase はデータベースを作成する
+type CreateDatabase struct {
+	databaseName string // 作成するデータベース名
+	ifNotExists  bool   // true の場合、データベースが既に存在してもエラーにしない
+}
+
+func NewCreateDatabase(databaseName string, ifNotExists bool) *CreateDatabase {
+	return &CreateDatabase{
+		databaseName: databaseName,
+		ifNotExists:  ifNotExists,
+	}
+}
+
+func (cd *CreateDatabase) Next() (Record, error) {
+	hdl := handler.Get()
+	if err := hdl.CreateDatabase(cd.databaseName, cd.ifNotExists); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
+
+func (cd *CreateDatabase) Close() {}
@@ -0,0 +1,57 @@
+package executor
+
+import (
+	"testing"
+
+	"github.com/ren-yamanashi/minesql/internal/storage/handler"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateDatabase_Next(t *testing.T) {
+	t.Run("データベースを作成できる", func(t *testing.T) {
+		// GIVEN
+		tmpdir := t.TempDir()
+		hdl := InitStorageEngineForTest(t, tmpdir)
+		defer handler.Reset()
+		createDatabase := NewCreateDatabase("app", false)
+
+		// WHEN
+		_, err := createDatabase.Next()
+
+		// THEN
+		assert.NoError(t, err)
+		_, ok := hdl.Catalog.GetDatabaseByName("app")
+		assert.True(t, ok)
+	})
+
+	t.Run("既に存在するデータベースの作成はエラーになる", func(t *testing.T) {
+		// GIVEN
+		tmpdir := t.TempDir()
+		hdl := InitStorageEngineForTest(t, tmpdir)
+		defer handler.Reset()
+		require.NoError(t, hdl.CreateDatabase("app", false))
+		createDatabase := NewCreateDatabase("app", false)
+
+		// WHEN
+		_, err := createDatabase.Next()
+
+		// THEN
+		assert.ErrorContains(t, err, "database app already exists")
+	})
+
+	t.Run("IF NOT EXISTS の場合は既に存在するデータベースの作成でもエラーにならない", func(t *testing.T) {
+		// GIVEN
+		tmpdir := t.TempDir()
+		hdl := InitStorageEngineForTest(t, tmpdir)
+		defer handler.Reset()
+		require.NoError(t, hdl.CreateDatabase("app", false))
+		createDatabase := NewCreateDatabase("app", true)
+
+		// WHEN
+		_, err := createDatabase.Next()
+
+		// THEN
+		assert.NoError(t, err)
+	})
+}
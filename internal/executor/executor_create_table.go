@@ -37,3 +37,5 @@ func (ct *CreateTable) Next() (Record, error) {
 	}
 	return nil, nil
 }
+
+func (ct *CreateTable) Close() {}
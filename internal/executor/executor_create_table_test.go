@@ -89,7 +89,7 @@ func TestCreateTable_Next(t *testing.T) {
 		handler.Init()
 		hdl := handler.Get()
 		createTable := NewCreateTable("users", 1, []handler.CreateIndexParam{
-			{Name: "email", ColName: "email", ColIdx: 1, Unique: true},
+			{Name: "email", ColNames: []string{"email"}, ColIdxs: []uint16{1}, Unique: true},
 		}, nil, nil)
 
 		// WHEN
@@ -101,7 +101,7 @@ func TestCreateTable_Next(t *testing.T) {
 		assert.True(t, ok)
 		assert.NotNil(t, tblMeta)
 		assert.Equal(t, 1, len(tblMeta.Indexes))
-		assert.Equal(t, "email", tblMeta.Indexes[0].ColName)
+		assert.Equal(t, []string{"email"}, tblMeta.Indexes[0].ColNames)
 	})
 
 	t.Run("テーブルファイルが作成される", func(t *testing.T) {
@@ -164,7 +164,7 @@ func TestCreateTable_Next(t *testing.T) {
 		hdl := handler.Get()
 		createTable := NewCreateTable("users", 1,
 			[]handler.CreateIndexParam{
-				{Name: "idx_email", ColName: "email", ColIdx: 1, Unique: true},
+				{Name: "idx_email", ColNames: []string{"email"}, ColIdxs: []uint16{1}, Unique: true},
 			},
 			[]handler.CreateColumnParam{
 				{Name: "id", Type: "string"},
@@ -205,14 +205,14 @@ func TestCreateTable_Next(t *testing.T) {
 		// WHEN: FK 制約付きの子テーブルを作成
 		childTable := NewCreateTable("orders", 1,
 			[]handler.CreateIndexParam{
-				{Name: "idx_user_id", ColName: "user_id", ColIdx: 1, Unique: false},
+				{Name: "idx_user_id", ColNames: []string{"user_id"}, ColIdxs: []uint16{1}, Unique: false},
 			},
 			[]handler.CreateColumnParam{
 				{Name: "id", Type: "string"},
 				{Name: "user_id", Type: "string"},
 			},
 			[]handler.CreateConstraintParam{
-				{ConstraintName: "fk_user", ColName: "user_id", RefTableName: "users", RefColName: "id"},
+				{ConstraintName: "fk_user", ColNames: []string{"user_id"}, RefTableName: "users", RefColNames: []string{"id"}},
 			},
 		)
 		_, err = childTable.Next()
@@ -252,8 +252,8 @@ func TestCreateTable_Next(t *testing.T) {
 		// WHEN: PK + UK + FK が混在するテーブルを作成
 		childTable := NewCreateTable("orders", 1,
 			[]handler.CreateIndexParam{
-				{Name: "idx_code", ColName: "code", ColIdx: 1, Unique: true},
-				{Name: "idx_user_id", ColName: "user_id", ColIdx: 2, Unique: false},
+				{Name: "idx_code", ColNames: []string{"code"}, ColIdxs: []uint16{1}, Unique: true},
+				{Name: "idx_user_id", ColNames: []string{"user_id"}, ColIdxs: []uint16{2}, Unique: false},
 			},
 			[]handler.CreateColumnParam{
 				{Name: "id", Type: "string"},
@@ -261,7 +261,7 @@ func TestCreateTable_Next(t *testing.T) {
 				{Name: "user_id", Type: "string"},
 			},
 			[]handler.CreateConstraintParam{
-				{ConstraintName: "fk_user", ColName: "user_id", RefTableName: "users", RefColName: "id"},
+				{ConstraintName: "fk_user", ColNames: []string{"user_id"}, RefTableName: "users", RefColNames: []string{"id"}},
 			},
 		)
 		_, err = childTable.Next()
@@ -0,0 +1,30 @@
+package executor
+
+import "github.com/ren-yamanashi/minesql/internal/storage/handler"
+
+// CreateView はビューを作成する
+type CreateView struct {
+	viewName   string   // 作成するビュー名
+	columns    []string // カラム名のリスト (nil なら問い合わせの結果セットのカラム名を使う)
+	definition string   // 問い合わせの SQL 表現
+	orReplace  bool     // true の場合、同じ名前のビューがあれば定義を置き換える
+}
+
+func NewCreateView(viewName string, columns []string, definition string, orReplace bool) *CreateView {
+	return &CreateView{
+		viewName:   viewName,
+		columns:    columns,
+		definition: definition,
+		orReplace:  orReplace,
+	}
+}
+
+func (cv *CreateView) Next() (Record, error) {
+	hdl := handler.Get()
+	if err := hdl.CreateView(cv.viewName, cv.columns, cv.definition, cv.orReplace); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
+
+func (cv *CreateView) Close() {}
@@ -0,0 +1,42 @@
+package executor
+
+import (
+	"testing"
+
+	"github.com/ren-yamanashi/minesql/internal/storage/handler"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateView_Next(t *testing.T) {
+	t.Run("ビューを作成できる", func(t *testing.T) {
+		// GIVEN
+		tmpdir := t.TempDir()
+		hdl := InitStorageEngineForTest(t, tmpdir)
+		defer handler.Reset()
+		createView := NewCreateView("user_names", []string{"n"}, "SELECT first_name FROM users", false)
+
+		// WHEN
+		_, err := createView.Next()
+
+		// THEN
+		assert.NoError(t, err)
+		view, ok := hdl.Catalog.GetViewByName("user_names")
+		assert.True(t, ok)
+		assert.Equal(t, []string{"n"}, view.Columns)
+		assert.Equal(t, "SELECT first_name FROM users", view.Definition)
+	})
+
+	t.Run("テーブルと同じ名前のビューは作成できない", func(t *testing.T) {
+		// GIVEN
+		tmpdir := t.TempDir()
+		InitStorageEngineForTest(t, tmpdir)
+		defer handler.Reset()
+		createView := NewCreateView("users", nil, "SELECT first_name FROM users", true)
+
+		// WHEN
+		_, err := createView.Next()
+
+		// THEN
+		assert.ErrorContains(t, err, "table users already exists")
+	})
+}
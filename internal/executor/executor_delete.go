@@ -2,9 +2,7 @@ package executor
 
 import (
 	"github.com/ren-yamanashi/minesql/internal/storage/access"
-	"github.com/ren-yamanashi/minesql/internal/storage/btree"
 	"github.com/ren-yamanashi/minesql/internal/storage/handler"
-	"github.com/ren-yamanashi/minesql/internal/storage/lock"
 )
 
 // Delete は InnerExecutor の結果を元にレコードを削除する
@@ -12,6 +10,7 @@ type Delete struct {
 	trxId         handler.TrxId
 	table         *access.Table
 	innerExecutor Executor
+	target        *joinTarget // 複数テーブルの DELETE の場合に、結合レコード内の削除対象テーブルのカラムの位置 (nil なら単一テーブル)
 }
 
 func NewDelete(trxId handler.TrxId, table *access.Table, innerExecutor Executor) *Delete {
@@ -22,52 +21,55 @@ func NewDelete(trxId handler.TrxId, table *access.Table, innerExecutor Executor)
 	}
 }
 
+// SetJoinTarget は InnerExecutor が結合レコードを返す場合 (複数テーブルの DELETE の場合) に、結合レコード内の削除対象テーブルのカラムの位置を設定する
+func (del *Delete) SetJoinTarget(offset int, nCols int) {
+	del.target = &joinTarget{offset: offset, nCols: nCols}
+}
+
 func (del *Delete) Next() (Record, error) {
 	h := handler.Get()
 
 	// 削除対象のレコードを先にすべて取得する
 	// (削除により Iterator が参照するページデータが破壊されるのを防ぐ)
-	var records []Record
-	for {
-		record, err := del.innerExecutor.Next()
-		if err != nil {
-			return nil, err
-		}
-		if record == nil {
-			break
-		}
-		records = append(records, record)
+	records, _, err := collectTargetRecords(del.innerExecutor, del.table, del.target)
+	if err != nil {
+		return nil, err
 	}
 
-	// テーブルの FK 制約を確認
-	tableMeta, _ := h.Catalog.GetTableMetaByName(del.table.Name)
+	// このテーブルを参照する FK 制約を確認
 	refFKs := h.Catalog.GetForeignKeysReferencingTable(del.table.Name)
 
 	// 取得したレコードを削除
 	for _, record := range records {
-		// 排他ロックを先に取得してから FK チェックを行う
-		// (ロック未保持の状態で FK チェックすると、並行する INSERT との競合で子行が孤立する可能性がある)
-		if len(refFKs) > 0 {
-			encodedKey := del.table.EncodeKey(record)
-			btr := btree.NewBTree(del.table.MetaPageId)
-			_, pos, err := btr.FindByKey(h.BufferPool, encodedKey)
-			if err != nil {
-				return nil, err
-			}
-			if err := h.LockMgr.Lock(del.trxId, pos, lock.Exclusive); err != nil {
-				return nil, err
-			}
-
-			// FK チェック: 参照元テーブルからの参照がないことを確認
-			if err := checkFKOnDelete(h.BufferPool, tableMeta, refFKs, record); err != nil {
+		if len(refFKs) == 0 {
+			if err := del.table.SoftDelete(h.BufferPool, del.trxId, h.LockMgr, record); err != nil {
 				return nil, err
 			}
+			continue
 		}
 
-		if err := del.table.SoftDelete(h.BufferPool, del.trxId, h.LockMgr, record); err != nil {
+		// 排他ロックを先に取得してから FK チェックを行う
+		// (ロック未保持の状態で FK チェックすると、並行する INSERT との競合で子行が孤立する可能性がある)
+		current, ok, err := lockLatestRow(h, del.trxId, del.table, record)
+		if err != nil {
+			return nil, err
+		}
+		// 先に削除した行の参照アクション (ON DELETE CASCADE) で削除済みの場合はスキップ
+		if !ok {
+			continue
+		}
+		if err := deleteRow(h, del.trxId, del.table, current, 0); err != nil {
 			return nil, err
 		}
 	}
 
 	return nil, nil
 }
+
+func (del *Delete) Close() {
+	del.innerExecutor.Close()
+}
+
+func (del *Delete) Explain() ExplainInfo {
+	return ExplainInfo{Name: "Delete", Detail: del.table.Name, Children: []*Executor{&del.innerExecutor}}
+}
@@ -232,12 +232,12 @@ func TestDelete(t *testing.T) {
 		assert.NoError(t, err)
 
 		childCt := NewCreateTable("orders", 1,
-			[]handler.CreateIndexParam{{Name: "idx_user_id", ColName: "user_id", ColIdx: 1, Unique: false}},
+			[]handler.CreateIndexParam{{Name: "idx_user_id", ColNames: []string{"user_id"}, ColIdxs: []uint16{1}, Unique: false}},
 			[]handler.CreateColumnParam{
 				{Name: "id", Type: handler.ColumnTypeString},
 				{Name: "user_id", Type: handler.ColumnTypeString},
 			},
-			[]handler.CreateConstraintParam{{ConstraintName: "fk_user", ColName: "user_id", RefTableName: "users", RefColName: "id"}},
+			[]handler.CreateConstraintParam{{ConstraintName: "fk_user", ColNames: []string{"user_id"}, RefTableName: "users", RefColNames: []string{"id"}}},
 		)
 		_, err = childCt.Next()
 		assert.NoError(t, err)
@@ -286,12 +286,12 @@ func TestDelete(t *testing.T) {
 		assert.NoError(t, err)
 
 		childCt := NewCreateTable("orders", 1,
-			[]handler.CreateIndexParam{{Name: "idx_user_id", ColName: "user_id", ColIdx: 1, Unique: false}},
+			[]handler.CreateIndexParam{{Name: "idx_user_id", ColNames: []string{"user_id"}, ColIdxs: []uint16{1}, Unique: false}},
 			[]handler.CreateColumnParam{
 				{Name: "id", Type: handler.ColumnTypeString},
 				{Name: "user_id", Type: handler.ColumnTypeString},
 			},
-			[]handler.CreateConstraintParam{{ConstraintName: "fk_user", ColName: "user_id", RefTableName: "users", RefColName: "id"}},
+			[]handler.CreateConstraintParam{{ConstraintName: "fk_user", ColNames: []string{"user_id"}, RefTableName: "users", RefColNames: []string{"id"}}},
 		)
 		_, err = childCt.Next()
 		assert.NoError(t, err)
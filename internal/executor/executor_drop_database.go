@@ -0,0 +1,28 @@
+package executor
+
+import "github.com/ren-yamanashi/minesql/internal/storage/handler"
+
+// DropDatabase はデータベースと、データベースに属するテーブル・ビューを削除する
+type DropDatabase struct {
+	trxId        handler.TrxId
+	databaseName string // 削除するデータベース名
+	ifExists     bool   // true の場合、データベースが存在しなくてもエラーにしない
+}
+
+func NewDropDatabase(trxId handler.TrxId, databaseName string, ifExists bool) *DropDatabase {
+	return &DropDatabase{
+		trxId:        trxId,
+		databaseName: databaseName,
+		ifExists:     ifExists,
+	}
+}
+
+func (dd *DropDatabase) Next() (Record, error) {
+	hdl := handler.Get()
+	if err := hdl.DropDatabase(dd.trxId, dd.databaseName, dd.ifExists); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
+
+func (dd *DropDatabase) Close() {}
@@ -0,0 +1,56 @@
+package executor
+
+import (
+	"testing"
+
+	"github.com/ren-yamanashi/minesql/internal/storage/handler"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDropDatabase_Next(t *testing.T) {
+	t.Run("データベースを削除できる", func(t *testing.T) {
+		// GIVEN
+		tmpdir := t.TempDir()
+		hdl := InitStorageEngineForTest(t, tmpdir)
+		defer handler.Reset()
+		require.NoError(t, hdl.CreateDatabase("app", false))
+		dropDatabase := NewDropDatabase(0, "app", false)
+
+		// WHEN
+		_, err := dropDatabase.Next()
+
+		// THEN
+		assert.NoError(t, err)
+		_, ok := hdl.Catalog.GetDatabaseByName("app")
+		assert.False(t, ok)
+	})
+
+	t.Run("存在しないデータベースの削除はエラーになる", func(t *testing.T) {
+		// GIVEN
+		tmpdir := t.TempDir()
+		InitStorageEngineForTest(t, tmpdir)
+		defer handler.Reset()
+		dropDatabase := NewDropDatabase(0, "app", false)
+
+		// WHEN
+		_, err := dropDatabase.Next()
+
+		// THEN
+		assert.ErrorContains(t, err, "database app not found")
+	})
+
+	t.Run("IF EXISTS の場合は存在しないデータベースの削除でもエラーにならない", func(t *testing.T) {
+		// GIVEN
+		tmpdir := t.TempDir()
+		InitStorageEngineForTest(t, tmpdir)
+		defer handler.Reset()
+		dropDatabase := NewDropDatabase(0, "app", true)
+
+		// WHEN
+		_, err := dropDatabase.Next()
+
+		// THEN
+		assert.NoError(t, err)
+	})
+}
@@ -0,0 +1,28 @@
+package executor
+
+import "github.com/ren-yamanashi/minesql/internal/storage/handler"
+
+// DropTable はテーブルを削除する
+type DropTable struct {
+	trxId     handler.TrxId
+	tableName string // 削除するテーブル名
+	ifExists  bool   // true の場合、テーブルが存在しなくてもエラーにしない
+}
+
+func NewDropTable(trxId handler.TrxId, tableName string, ifExists bool) *DropTable {
+	return &DropTable{
+		trxId:     trxId,
+		tableName: tableName,
+		ifExists:  ifExists,
+	}
+}
+
+func (dt *DropTable) Next() (Record, error) {
+	hdl := handler.Get()
+	if err := hdl.DropTable(dt.trxId, dt.tableName, dt.ifExists); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
+
+func (dt *DropTable) Close() {}
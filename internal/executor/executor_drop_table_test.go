@@ -0,0 +1,54 @@
+package executor
+
+import (
+	"testing"
+
+	"github.com/ren-yamanashi/minesql/internal/storage/handler"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDropTable_Next(t *testing.T) {
+	t.Run("テーブルを削除できる", func(t *testing.T) {
+		// GIVEN
+		tmpdir := t.TempDir()
+		hdl := InitStorageEngineForTest(t, tmpdir)
+		defer handler.Reset()
+		dropTable := NewDropTable(0, "users", false)
+
+		// WHEN
+		_, err := dropTable.Next()
+
+		// THEN
+		assert.NoError(t, err)
+		_, ok := hdl.Catalog.GetTableMetaByName("users")
+		assert.False(t, ok)
+	})
+
+	t.Run("存在しないテーブルの削除はエラーになる", func(t *testing.T) {
+		// GIVEN
+		tmpdir := t.TempDir()
+		InitStorageEngineForTest(t, tmpdir)
+		defer handler.Reset()
+		dropTable := NewDropTable(0, "orders", false)
+
+		// WHEN
+		_, err := dropTable.Next()
+
+		// THEN
+		assert.ErrorContains(t, err, "table orders not found")
+	})
+
+	t.Run("IF EXISTS の場合は存在しないテーブルの削除でもエラーにならない", func(t *testing.T) {
+		// GIVEN
+		tmpdir := t.TempDir()
+		InitStorageEngineForTest(t, tmpdir)
+		defer handler.Reset()
+		dropTable := NewDropTable(0, "orders", true)
+
+		// WHEN
+		_, err := dropTable.Next()
+
+		// THEN
+		assert.NoError(t, err)
+	})
+}
@@ -0,0 +1,26 @@
+package executor
+
+import "github.com/ren-yamanashi/minesql/internal/storage/handler"
+
+// DropView はビューを削除する
+type DropView struct {
+	viewName string // 削除するビュー名
+	ifExists bool   // true の場合、ビューが存在しなくてもエラーにしない
+}
+
+func NewDropView(viewName string, ifExists bool) *DropView {
+	return &DropView{
+		viewName: viewName,
+		ifExists: ifExists,
+	}
+}
+
+func (dv *DropView) Next() (Record, error) {
+	hdl := handler.Get()
+	if err := hdl.DropView(dv.viewName, dv.ifExists); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
+
+func (dv *DropView) Close() {}
@@ -0,0 +1,56 @@
+package executor
+
+import (
+	"testing"
+
+	"github.com/ren-yamanashi/minesql/internal/storage/handler"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDropView_Next(t *testing.T) {
+	t.Run("ビューを削除できる", func(t *testing.T) {
+		// GIVEN
+		tmpdir := t.TempDir()
+		hdl := InitStorageEngineForTest(t, tmpdir)
+		defer handler.Reset()
+		require.NoError(t, hdl.CreateView("v", nil, "SELECT first_name FROM users", false))
+		dropView := NewDropView("v", false)
+
+		// WHEN
+		_, err := dropView.Next()
+
+		// THEN
+		assert.NoError(t, err)
+		_, ok := hdl.Catalog.GetViewByName("v")
+		assert.False(t, ok)
+	})
+
+	t.Run("存在しないビューの削除はエラーになる", func(t *testing.T) {
+		// GIVEN
+		tmpdir := t.TempDir()
+		InitStorageEngineForTest(t, tmpdir)
+		defer handler.Reset()
+		dropView := NewDropView("v", false)
+
+		// WHEN
+		_, err := dropView.Next()
+
+		// THEN
+		assert.ErrorContains(t, err, "view v not found")
+	})
+
+	t.Run("IF EXISTS の場合は存在しないビューの削除でもエラーにならない", func(t *testing.T) {
+		// GIVEN
+		tmpdir := t.TempDir()
+		InitStorageEngineForTest(t, tmpdir)
+		defer handler.Reset()
+		dropView := NewDropView("v", true)
+
+		// WHEN
+		_, err := dropView.Next()
+
+		// THEN
+		assert.NoError(t, err)
+	})
+}
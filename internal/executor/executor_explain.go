@@ -0,0 +1,166 @@
+package executor
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Explain は Executor のツリーを 1 ノード 1 行のレコードとして返す (EXPLAIN / EXPLAIN ANALYZE)
+//
+// レコードは [operation, detail, access, est_rows, est_cost] の順に並ぶ (該当する情報がないカラムは NULL)
+// EXPLAIN ANALYZE の場合は、ツリーを実際に実行し [actual_rows, loops, actual_time_ms] を続けて返す
+type Explain struct {
+	root    Executor
+	analyze bool
+	records []Record
+	pos     int
+	built   bool // レコードを作成済みか (初回の Next で作成する)
+}
+
+func NewExplain(root Executor, analyze bool) *Explain {
+	return &Explain{root: root, analyze: analyze}
+}
+
+func (e *Explain) Next() (Record, error) {
+	if !e.built {
+		if err := e.build(); err != nil {
+			return nil, err
+		}
+		e.built = true
+	}
+	if e.pos >= len(e.records) {
+		return nil, nil
+	}
+	record := e.records[e.pos]
+	e.pos++
+	return record, nil
+}
+
+func (e *Explain) Close() {
+	e.root.Close()
+}
+
+// build は (EXPLAIN ANALYZE の場合はツリーを最後まで実行してから) ツリーの各ノードのレコードを作成する
+func (e *Explain) build() error {
+	var stats *analyzeStats
+	if e.analyze {
+		// 見積もりは実行前のテーブルの状態で算出する (UPDATE・DELETE の実行後では行数が変わるため)
+		if err := resolveEstimates(e.root); err != nil {
+			return err
+		}
+		stats = &analyzeStats{}
+		root := instrument(e.root, stats)
+		for {
+			record, err := root.Next()
+			if err != nil {
+				return err
+			}
+			if record == nil {
+				break
+			}
+		}
+	}
+	return e.render(e.root, stats, "", "")
+}
+
+// render はノードのレコードを追加し、子のノードを再帰的に追加する
+//
+// prefix はノードの行の先頭に付ける罫線、childPrefix は子のノードの行の先頭に付ける罫線
+func (e *Explain) render(exec Executor, stats *analyzeStats, prefix, childPrefix string) error {
+	if a, ok := exec.(*analyzed); ok {
+		exec = a.inner
+	}
+
+	var info ExplainInfo
+	if explainer, ok := exec.(Explainer); ok {
+		info = explainer.Explain()
+	} else {
+		info.Name = strings.TrimPrefix(fmt.Sprintf("%T", exec), "*executor.")
+	}
+
+	record := Record{[]byte(prefix + info.Name), nil, nil, nil, nil}
+	if info.Detail != "" {
+		record[1] = []byte(info.Detail)
+	}
+	if noter, ok := exec.(planNoter); ok {
+		note := noter.planNote()
+		if note.Access != "" {
+			record[2] = []byte(note.Access)
+		}
+		if note.Estimate != nil {
+			rows, cost, err := note.Estimate()
+			if err != nil {
+				return err
+			}
+			record[3] = []byte(strconv.FormatFloat(rows, 'f', 2, 64))
+			record[4] = []byte(strconv.FormatFloat(cost, 'f', 2, 64))
+		}
+	}
+	if e.analyze {
+		// 実行されなかったノード (e.g. 外側の行がない Nested Loop Join の内部表) は 0 行・0 ループ
+		var rows, loops uint64
+		var elapsed time.Duration
+		if stats != nil {
+			rows, loops, elapsed = stats.rows, stats.loops, stats.elapsed
+		}
+		record = append(record,
+			[]byte(strconv.FormatUint(rows, 10)),
+			[]byte(strconv.FormatUint(loops, 10)),
+			[]byte(strconv.FormatFloat(float64(elapsed)/float64(time.Millisecond), 'f', 3, 64)),
+		)
+	}
+	e.records = append(e.records, record)
+
+	children := make([]Executor, 0, len(info.Children)+1)
+	for _, child := range info.Children {
+		children = append(children, *child)
+	}
+	if info.InnerPlan != nil {
+		children = append(children, info.InnerPlan)
+	}
+	for i, child := range children {
+		var childStats *analyzeStats
+		if stats != nil && i < len(stats.children) {
+			childStats = stats.children[i]
+		}
+		branch, indent := "├── ", "│   "
+		if i == len(children)-1 {
+			branch, indent = "└── ", "    "
+		}
+		if err := e.render(child, childStats, childPrefix+branch, childPrefix+indent); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resolveEstimates はツリーの各ノードの見積もりを算出し、算出済みの値を返す関数に差し替える
+func resolveEstimates(exec Executor) error {
+	if noter, ok := exec.(planNoter); ok {
+		note := noter.planNote()
+		if note.Estimate != nil {
+			rows, cost, err := note.Estimate()
+			if err != nil {
+				return err
+			}
+			note.Estimate = func() (float64, float64, error) { return rows, cost, nil }
+			noter.SetPlanNote(note)
+		}
+	}
+	explainer, ok := exec.(Explainer)
+	if !ok {
+		return nil
+	}
+	info := explainer.Explain()
+	for _, child := range info.Children {
+		if err := resolveEstimates(*child); err != nil {
+			return err
+		}
+	}
+	if info.InnerPlan != nil {
+		return resolveEstimates(info.InnerPlan)
+	}
+	return nil
+}
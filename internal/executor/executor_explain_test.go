@@ -0,0 +1,113 @@
+package executor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// explainRows は Explain の結果を文字列の 2 次元配列に変換する (NULL は空文字列)
+func explainRows(t *testing.T, exec Executor) [][]string {
+	t.Helper()
+	var rows [][]string
+	for _, record := range collectAll(t, exec) {
+		row := make([]string, len(record))
+		for i, value := range record {
+			row[i] = string(value)
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+func TestExplain(t *testing.T) {
+	t.Run("Executor のツリーを 1 ノード 1 行で罫線付きで返す", func(t *testing.T) {
+		// GIVEN
+		scan1 := &mockExecutor{}
+		scan2 := &mockExecutor{}
+		union := NewUnion([]Executor{scan1, scan2})
+		union.SetPlanNote(PlanNote{
+			Access:   "union (OR)",
+			Estimate: func() (float64, float64, error) { return 2, 1.5, nil },
+		})
+		root := NewLimit(NewFilter(union, func(Record) bool { return true }), 10, 5)
+
+		// WHEN
+		rows := explainRows(t, NewExplain(root, false))
+
+		// THEN
+		assert.Equal(t, [][]string{
+			{"Limit", "LIMIT 10 OFFSET 5", "", "", ""},
+			{"└── Filter", "", "", "", ""},
+			{"    └── Union", "", "union (OR)", "2.00", "1.50"},
+			{"        ├── mockExecutor", "", "", "", ""},
+			{"        └── mockExecutor", "", "", "", ""},
+		}, rows)
+	})
+
+	t.Run("EXPLAIN ではツリーを実行しない", func(t *testing.T) {
+		// GIVEN
+		scan := &mockExecutor{records: []Record{{[]byte("a")}}}
+
+		// WHEN
+		rows := explainRows(t, NewExplain(NewFilter(scan, func(Record) bool { return true }), false))
+
+		// THEN
+		assert.Len(t, rows, 2)
+		assert.Equal(t, 0, scan.index)
+	})
+
+	t.Run("EXPLAIN ANALYZE では各ノードの行数・ループ回数・時間を返す", func(t *testing.T) {
+		// GIVEN
+		scan := &mockExecutor{records: []Record{{[]byte("a")}, {[]byte("b")}, {[]byte("c")}}}
+		filter := NewFilter(scan, func(record Record) bool { return string(record[0]) != "b" })
+
+		// WHEN
+		rows := explainRows(t, NewExplain(filter, true))
+
+		// THEN
+		require.Len(t, rows, 2)
+		assert.Equal(t, []string{"Filter", "", "", "", "", "2", "1"}, rows[0][:7])
+		assert.Equal(t, []string{"└── mockExecutor", "", "", "", "", "3", "1"}, rows[1][:7])
+		assert.Regexp(t, `^\d+\.\d{3}$`, rows[0][7])
+	})
+
+	t.Run("EXPLAIN ANALYZE では Nested Loop Join の内部表の行数とループ回数を合算する", func(t *testing.T) {
+		// GIVEN
+		left := newMockExecutor([]Record{{[]byte("a")}, {[]byte("b")}, {[]byte("c")}})
+		buildRight := func(Record) (Executor, error) {
+			return newMockExecutor([]Record{{[]byte("x")}, {[]byte("y")}}), nil
+		}
+		nlj := NewNestedLoopJoin(left, buildRight)
+		nlj.SetInnerPlan(newMockExecutor(nil))
+
+		// WHEN
+		rows := explainRows(t, NewExplain(nlj, true))
+
+		// THEN
+		require.Len(t, rows, 3)
+		assert.Equal(t, []string{"NestedLoopJoin", "INNER", "", "", "", "6", "1"}, rows[0][:7])
+		assert.Equal(t, []string{"├── nljMockExecutor", "", "", "", "", "3", "1"}, rows[1][:7])
+		assert.Equal(t, []string{"└── nljMockExecutor", "", "", "", "", "6", "3"}, rows[2][:7])
+	})
+
+	t.Run("EXPLAIN ANALYZE では見積もりをツリーの実行前に算出する", func(t *testing.T) {
+		// GIVEN
+		scan := &mockExecutor{records: []Record{{[]byte("a")}, {[]byte("b")}}}
+		union := NewUnion([]Executor{scan})
+		union.SetPlanNote(PlanNote{
+			Estimate: func() (float64, float64, error) {
+				// 実行前に算出すれば、まだ読み取っていないレコードの数を返す
+				return float64(len(scan.records) - scan.index), 1, nil
+			},
+		})
+
+		// WHEN
+		rows := explainRows(t, NewExplain(union, true))
+
+		// THEN
+		require.Len(t, rows, 2)
+		assert.Equal(t, []string{"Union", "", "", "2.00", "1.00", "2", "1"}, rows[0][:7])
+	})
+}
@@ -2,14 +2,20 @@ package executor
 
 // Filter は InnerExecutor の結果から条件に合う行だけを返す
 type Filter struct {
-	whileCondition func(Record) bool
-	innerExecutor  Executor
+	condition     Expression
+	innerExecutor Executor
 }
 
+// NewFilter は条件関数が true を返す行だけを返す Filter を作成する
 func NewFilter(innerExecutor Executor, condition func(Record) bool) *Filter {
+	return NewFilterWithExpression(innerExecutor, ConditionFunc(condition))
+}
+
+// NewFilterWithExpression は条件式の値が真 (NULL でなく 0 でない) の行だけを返す Filter を作成する
+func NewFilterWithExpression(innerExecutor Executor, condition Expression) *Filter {
 	return &Filter{
-		innerExecutor:  innerExecutor,
-		whileCondition: condition,
+		innerExecutor: innerExecutor,
+		condition:     condition,
 	}
 }
 
@@ -27,8 +33,20 @@ func (f *Filter) Next() (Record, error) {
 		}
 
 		// 条件を満たす場合はレコードを返す
-		if f.whileCondition(record) {
+		value, err := f.condition.Eval(record)
+		if err != nil {
+			return nil, err
+		}
+		if isTrue(value) {
 			return record, nil
 		}
 	}
 }
+
+func (f *Filter) Close() {
+	f.innerExecutor.Close()
+}
+
+func (f *Filter) Explain() ExplainInfo {
+	return ExplainInfo{Name: "Filter", Children: []*Executor{&f.innerExecutor}}
+}
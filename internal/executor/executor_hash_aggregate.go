@@ -0,0 +1,249 @@
+package executor
+
+import (
+	"bufio"
+	"encoding/binary"
+	"slices"
+
+	"github.com/ren-yamanashi/minesql/internal/storage/config"
+)
+
+// HashAggregateParams は NewHashAggregateWithParams の引数
+type HashAggregateParams struct {
+	InnerExecutor Executor
+	GroupBy       []int           // グループ化キーのカラム位置
+	Aggregates    []AggregateSpec // 集約関数
+	BufferSize    int             // メモリ上に保持するグループの合計サイズの上限 (バイト)
+	TmpDir        string          // 途中結果を書き出すディレクトリ
+	MergeFanIn    int             // 一度にマージするランの数の上限 (2 未満の場合は defaultMergeFanIn)
+}
+
+// HashAggregate は InnerExecutor の結果をハッシュテーブルでグループ化して集約する
+//
+// InnerExecutor の並び順に依存しない代わりに、全グループの途中結果をメモリ上に保持する
+// グループの合計サイズが BufferSize を超えた場合、その時点までの途中結果をグループ化キーの順に並べ替えてラン (一時ファイル) として書き出し、
+// 最後に全ランをグループ化キーの順にマージしながら、同じグループの途中結果を統合して返す
+// ランの数が MergeFanIn を超える場合は、Sort と同様に多段マージでランの数を減らしてから最後のマージを行う
+//
+// 結果のレコードは [グループ化キー..., 集約関数の結果...] の順に並ぶ
+// ランを書き出していない場合はグループが最初に現れた順、書き出した場合はグループ化キーの順に返す
+type HashAggregate struct {
+	InnerExecutor Executor
+	groupBy       []int
+	aggregates    []AggregateSpec
+	bufferSize    int
+	tmpDir        string
+	mergeFanIn    int
+	aggregated    bool                       // 集約済みか (初回の Next で集約する)
+	groups        map[string]*aggregateGroup // グループ化キー → 途中結果
+	order         []*aggregateGroup          // グループが最初に現れた順
+	pos           int                        // order の読み取り位置
+	runs          []*sortRun                 // 書き出したラン
+	merger        *runMerger                 // ランのマージ
+}
+
+func NewHashAggregate(innerExecutor Executor, groupBy []int, aggregates []AggregateSpec) *HashAggregate {
+	return NewHashAggregateWithParams(HashAggregateParams{
+		InnerExecutor: innerExecutor,
+		GroupBy:       groupBy,
+		Aggregates:    aggregates,
+		BufferSize:    config.GetAggregateBufferSize(),
+		TmpDir:        config.GetDataDirectory(),
+		MergeFanIn:    defaultMergeFanIn,
+	})
+}
+
+func NewHashAggregateWithParams(params HashAggregateParams) *HashAggregate {
+	return &HashAggregate{
+		InnerExecutor: params.InnerExecutor,
+		groupBy:       params.GroupBy,
+		aggregates:    params.Aggregates,
+		bufferSize:    params.BufferSize,
+		tmpDir:        params.TmpDir,
+		mergeFanIn:    mergeFanInOrDefault(params.MergeFanIn),
+		groups:        make(map[string]*aggregateGroup),
+	}
+}
+
+func (ha *HashAggregate) Next() (Record, error) {
+	// 初回実行時に全レコードを読み込んで集約する
+	if !ha.aggregated {
+		if err := ha.aggregate(); err != nil {
+			ha.removeRuns()
+			return nil, err
+		}
+		ha.aggregated = true
+	}
+
+	// ランを書き出していない場合はメモリ上のグループを順に返す
+	if ha.merger == nil {
+		if ha.pos >= len(ha.order) {
+			return nil, nil
+		}
+		group := ha.order[ha.pos]
+		ha.pos++
+		return group.output(ha.aggregates)
+	}
+
+	record, err := ha.nextMerged()
+	if err != nil || record == nil {
+		ha.removeRuns()
+	}
+	return record, err
+}
+
+func (ha *HashAggregate) Close() {
+	ha.removeRuns()
+	ha.InnerExecutor.Close()
+}
+
+func (ha *HashAggregate) Explain() ExplainInfo {
+	return ExplainInfo{Name: "HashAggregate", Detail: aggregatesString(ha.aggregates), Children: []*Executor{&ha.InnerExecutor}}
+}
+
+// aggregate は InnerExecutor の全レコードを読み込み、必要に応じてランを書き出しながら集約する
+func (ha *HashAggregate) aggregate() error {
+	size := 0
+	for {
+		record, err := ha.InnerExecutor.Next()
+		if err != nil {
+			return err
+		}
+		if record == nil {
+			break
+		}
+
+		group := newAggregateGroup(record, ha.groupBy, len(ha.aggregates))
+		key := groupHashKey(group.key)
+		if existing, ok := ha.groups[key]; ok {
+			group = existing
+		} else {
+			ha.groups[key] = group
+			ha.order = append(ha.order, group)
+			size += aggregateGroupSize(group)
+		}
+		if err := group.add(ha.aggregates, record); err != nil {
+			return err
+		}
+
+		// バッファサイズを超えたらランとして書き出す
+		if size > ha.bufferSize {
+			if err := ha.spill(); err != nil {
+				return err
+			}
+			size = 0
+		}
+	}
+
+	// GROUP BY がない場合は、レコードが 0 件でも 1 件の結果を返す (COUNT(*) = 0 など)
+	if len(ha.groupBy) == 0 && len(ha.order) == 0 && len(ha.runs) == 0 {
+		ha.order = append(ha.order, newAggregateGroup(nil, nil, len(ha.aggregates)))
+	}
+	if len(ha.runs) == 0 {
+		return nil
+	}
+
+	// ランを書き出している場合は残りのグループもランとして書き出し、全ランをマージする
+	if len(ha.order) > 0 {
+		if err := ha.spill(); err != nil {
+			return err
+		}
+	}
+	return ha.startMerge()
+}
+
+// spill はメモリ上のグループをグループ化キーの順に並べ替え、ランとして一時ファイルに書き出す
+func (ha *HashAggregate) spill() error {
+	slices.SortFunc(ha.order, func(a, b *aggregateGroup) int { return ha.compareKey(a.key, b.key) })
+
+	run, err := createRun(ha.tmpDir, "minesql-aggregate-*.tmp", func(w *bufio.Writer) error {
+		for _, group := range ha.order {
+			if err := writeRunRecord(w, group.toRunRecord()); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	ha.runs = append(ha.runs, run)
+
+	ha.groups = make(map[string]*aggregateGroup)
+	ha.order = nil
+	return nil
+}
+
+// startMerge は必要に応じてランの数を減らした後、各ランをグループ化キーの順にマージし始める
+//
+// 多段マージの途中では同じグループの途中結果を統合せず、最後のマージ (nextMerged) でまとめて統合する
+func (ha *HashAggregate) startMerge() error {
+	compare := func(a, b Record) int { return ha.compareKey(a[:len(ha.groupBy)], b[:len(ha.groupBy)]) }
+	runs, err := reduceRuns(ha.runs, ha.mergeFanIn, compare, ha.tmpDir, "minesql-aggregate-*.tmp")
+	ha.runs = runs
+	if err != nil {
+		return err
+	}
+	ha.merger, err = newRunMerger(ha.runs, compare)
+	return err
+}
+
+// nextMerged は最小のグループ化キーを持つ途中結果をランから取り出し、同じグループ化キーの途中結果をすべて統合して返す
+func (ha *HashAggregate) nextMerged() (Record, error) {
+	var group *aggregateGroup
+	for {
+		record := ha.merger.peek()
+		if record == nil {
+			break
+		}
+		next := aggregateGroupFromRunRecord(record, len(ha.groupBy))
+		if group != nil && !sameGroupKey(group.key, next.key) {
+			break
+		}
+		if _, err := ha.merger.next(); err != nil {
+			return nil, err
+		}
+
+		if group == nil {
+			group = next
+		} else if err := group.merge(ha.aggregates, next); err != nil {
+			return nil, err
+		}
+	}
+	if group == nil {
+		return nil, nil
+	}
+	return group.output(ha.aggregates)
+}
+
+// removeRuns はランの一時ファイルを閉じて削除する
+func (ha *HashAggregate) removeRuns() {
+	removeRuns(ha.runs)
+	ha.runs = nil
+}
+
+// compareKey はグループ化キーを比較する (NULL は最小値として扱う)
+func (ha *HashAggregate) compareKey(a, b Record) int {
+	for i := range a {
+		if c := compareColumn(a[i], b[i]); c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+// groupHashKey はグループ化キーをハッシュテーブルのキーに変換する
+//
+// 各カラムを [NULL なら 0 / 値があれば (カラム長 + 1) (uvarint)][カラム値] の形式で連結し、NULL と空文字列を区別する
+func groupHashKey(key Record) string {
+	var buf []byte
+	for _, col := range key {
+		if col == nil {
+			buf = binary.AppendUvarint(buf, 0)
+			continue
+		}
+		buf = binary.AppendUvarint(buf, uint64(len(col))+1)
+		buf = append(buf, col...)
+	}
+	return string(buf)
+}
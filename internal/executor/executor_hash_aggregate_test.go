@@ -0,0 +1,266 @@
+package executor
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"testing"
+
+	"github.com/ren-yamanashi/minesql/internal/storage/encode"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHashAggregate(t *testing.T) {
+	// (category, price) のレコード
+	newInner := func() *mockExecutor {
+		return &mockExecutor{records: []Record{
+			{[]byte("b"), encode.EncodeInt64(300)},
+			{[]byte("a"), encode.EncodeInt64(100)},
+			{[]byte("b"), nil},
+			{[]byte("a"), encode.EncodeInt64(250)},
+			{[]byte("b"), encode.EncodeInt64(-50)},
+		}}
+	}
+	allAggregates := []AggregateSpec{
+		{Func: AggregateCount, Pos: -1},
+		{Func: AggregateCount, Pos: 1},
+		{Func: AggregateSum, Pos: 1},
+		{Func: AggregateMin, Pos: 1},
+		{Func: AggregateMax, Pos: 1},
+		{Func: AggregateAvg, Pos: 1},
+	}
+
+	t.Run("グループごとに集約関数を計算し、グループが最初に現れた順に返す", func(t *testing.T) {
+		// GIVEN
+		agg := NewHashAggregateWithParams(HashAggregateParams{
+			InnerExecutor: newInner(),
+			GroupBy:       []int{0},
+			Aggregates:    allAggregates,
+			BufferSize:    1024,
+			TmpDir:        t.TempDir(),
+		})
+
+		// WHEN
+		results := collectAll(t, agg)
+
+		// THEN: NULL は COUNT(*) 以外の集約対象にならない。AVG は小数部 4 桁の整数で返る
+		assert.Equal(t, [][]any{
+			{"b", int64(3), int64(2), int64(250), int64(-50), int64(300), int64(1250000)},
+			{"a", int64(2), int64(2), int64(350), int64(100), int64(250), int64(1750000)},
+		}, decodeAggregateResults(results, 1))
+	})
+
+	t.Run("GROUP BY がない場合は全レコードを 1 グループとして集約する", func(t *testing.T) {
+		// GIVEN
+		agg := NewHashAggregateWithParams(HashAggregateParams{
+			InnerExecutor: newInner(),
+			Aggregates:    allAggregates,
+			BufferSize:    1024,
+			TmpDir:        t.TempDir(),
+		})
+
+		// WHEN
+		results := collectAll(t, agg)
+
+		// THEN
+		assert.Equal(t, [][]any{
+			{int64(5), int64(4), int64(600), int64(-50), int64(300), int64(1500000)},
+		}, decodeAggregateResults(results, 0))
+	})
+
+	t.Run("GROUP BY がない場合はレコードが 0 件でも 1 件の結果を返す", func(t *testing.T) {
+		// GIVEN
+		agg := NewHashAggregateWithParams(HashAggregateParams{
+			InnerExecutor: &mockExecutor{},
+			Aggregates:    allAggregates,
+			BufferSize:    1024,
+			TmpDir:        t.TempDir(),
+		})
+
+		// WHEN
+		results := collectAll(t, agg)
+
+		// THEN: COUNT は 0、それ以外は NULL
+		assert.Equal(t, [][]any{
+			{int64(0), int64(0), nil, nil, nil, nil},
+		}, decodeAggregateResults(results, 0))
+	})
+
+	t.Run("GROUP BY がありレコードが 0 件の場合は何も返さない", func(t *testing.T) {
+		// GIVEN
+		agg := NewHashAggregateWithParams(HashAggregateParams{
+			InnerExecutor: &mockExecutor{},
+			GroupBy:       []int{0},
+			Aggregates:    allAggregates,
+			BufferSize:    1024,
+			TmpDir:        t.TempDir(),
+		})
+
+		// WHEN
+		results := collectAll(t, agg)
+
+		// THEN
+		assert.Empty(t, results)
+	})
+
+	t.Run("グループ化キーが NULL のレコードは 1 つのグループにまとめる", func(t *testing.T) {
+		// GIVEN
+		inner := &mockExecutor{records: []Record{
+			{nil, []byte("1")},
+			{[]byte(""), []byte("2")},
+			{nil, []byte("3")},
+		}}
+		agg := NewHashAggregateWithParams(HashAggregateParams{
+			InnerExecutor: inner,
+			GroupBy:       []int{0},
+			Aggregates:    []AggregateSpec{{Func: AggregateCount, Pos: -1}},
+			BufferSize:    1024,
+			TmpDir:        t.TempDir(),
+		})
+
+		// WHEN
+		results := collectAll(t, agg)
+
+		// THEN: NULL と空文字列は別のグループになる
+		assert.Equal(t, [][]any{{nil, int64(2)}, {"", int64(1)}}, decodeAggregateResults(results, 1))
+	})
+
+	t.Run("バッファサイズを超えた場合、途中結果を一時ファイルに書き出してマージする", func(t *testing.T) {
+		// GIVEN: 1 グループごとにランが書き出されるほど小さいバッファサイズ
+		var records []Record
+		for i := range 30 {
+			records = append(records, Record{[]byte(fmt.Sprintf("k%d", i%3)), encode.EncodeInt64(int64(i))})
+		}
+		tmpDir := t.TempDir()
+		agg := NewHashAggregateWithParams(HashAggregateParams{
+			InnerExecutor: &mockExecutor{records: records},
+			GroupBy:       []int{0},
+			Aggregates:    []AggregateSpec{{Func: AggregateCount, Pos: -1}, {Func: AggregateSum, Pos: 1}, {Func: AggregateMax, Pos: 1}},
+			BufferSize:    1,
+			TmpDir:        tmpDir,
+		})
+
+		// WHEN
+		first, err := agg.Next()
+		require.NoError(t, err)
+		entries, err := os.ReadDir(tmpDir)
+		require.NoError(t, err)
+		rest := collectAll(t, agg)
+
+		// THEN: 同じグループの途中結果が統合され、グループ化キーの順に返り、一時ファイルは削除される
+		assert.NotEmpty(t, entries)
+		assert.Equal(t, [][]any{
+			{"k0", int64(10), int64(135), int64(27)},
+			{"k1", int64(10), int64(145), int64(28)},
+			{"k2", int64(10), int64(155), int64(29)},
+		}, decodeAggregateResults(append([]Record{first}, rest...), 1))
+		entries, err = os.ReadDir(tmpDir)
+		require.NoError(t, err)
+		assert.Empty(t, entries)
+	})
+
+	t.Run("ランの数が MergeFanIn を超える場合、多段にマージしてから同じグループの途中結果を統合する", func(t *testing.T) {
+		// GIVEN: 30 個のランが書き出される
+		var records []Record
+		for i := range 30 {
+			records = append(records, Record{[]byte(fmt.Sprintf("k%d", i%3)), encode.EncodeInt64(int64(i))})
+		}
+		tmpDir := t.TempDir()
+		agg := NewHashAggregateWithParams(HashAggregateParams{
+			InnerExecutor: &mockExecutor{records: records},
+			GroupBy:       []int{0},
+			Aggregates:    []AggregateSpec{{Func: AggregateCount, Pos: -1}, {Func: AggregateSum, Pos: 1}, {Func: AggregateMax, Pos: 1}},
+			BufferSize:    1,
+			TmpDir:        tmpDir,
+			MergeFanIn:    4,
+		})
+
+		// WHEN
+		first, err := agg.Next()
+		require.NoError(t, err)
+		entries, err := os.ReadDir(tmpDir)
+		require.NoError(t, err)
+		rest := collectAll(t, agg)
+
+		// THEN: 最後のマージで読み込むランは 4 個以下で、集約結果は 1 段のマージと変わらない
+		assert.LessOrEqual(t, len(entries), 4)
+		assert.Equal(t, [][]any{
+			{"k0", int64(10), int64(135), int64(27)},
+			{"k1", int64(10), int64(145), int64(28)},
+			{"k2", int64(10), int64(155), int64(29)},
+		}, decodeAggregateResults(append([]Record{first}, rest...), 1))
+		entries, err = os.ReadDir(tmpDir)
+		require.NoError(t, err)
+		assert.Empty(t, entries)
+	})
+
+	t.Run("最後まで読み取る前に Close すると、ランの一時ファイルを削除し InnerExecutor を閉じる", func(t *testing.T) {
+		// GIVEN: ランを書き出した後、先頭の 1 グループだけ読み取った状態
+		var records []Record
+		for i := range 30 {
+			records = append(records, Record{[]byte(fmt.Sprintf("k%d", i%3)), encode.EncodeInt64(int64(i))})
+		}
+		inner := &mockExecutor{records: records}
+		tmpDir := t.TempDir()
+		agg := NewHashAggregateWithParams(HashAggregateParams{
+			InnerExecutor: inner,
+			GroupBy:       []int{0},
+			Aggregates:    []AggregateSpec{{Func: AggregateCount, Pos: -1}},
+			BufferSize:    1,
+			TmpDir:        tmpDir,
+		})
+		_, err := agg.Next()
+		require.NoError(t, err)
+		entries, err := os.ReadDir(tmpDir)
+		require.NoError(t, err)
+		require.NotEmpty(t, entries)
+
+		// WHEN
+		agg.Close()
+
+		// THEN
+		entries, err = os.ReadDir(tmpDir)
+		require.NoError(t, err)
+		assert.Empty(t, entries)
+		assert.True(t, inner.closed)
+	})
+
+	t.Run("SUM が BIGINT の範囲を超える場合はエラーを返す", func(t *testing.T) {
+		// GIVEN
+		inner := &mockExecutor{records: []Record{
+			{encode.EncodeInt64(math.MaxInt64)},
+			{encode.EncodeInt64(1)},
+		}}
+		agg := NewHashAggregateWithParams(HashAggregateParams{
+			InnerExecutor: inner,
+			Aggregates:    []AggregateSpec{{Func: AggregateSum, Pos: 0}},
+			BufferSize:    1024,
+			TmpDir:        t.TempDir(),
+		})
+
+		// WHEN
+		_, err := agg.Next()
+
+		// THEN
+		assert.ErrorContains(t, err, "sum value is out of range")
+	})
+}
+
+// decodeAggregateResults は集約結果のレコードを、先頭 nKeys 個のグループ化キーは文字列、集約関数の結果は int64 に変換する (NULL は nil)
+func decodeAggregateResults(records []Record, nKeys int) [][]any {
+	results := make([][]any, len(records))
+	for i, record := range records {
+		for j, col := range record {
+			switch {
+			case col == nil:
+				results[i] = append(results[i], nil)
+			case j < nKeys:
+				results[i] = append(results[i], string(col))
+			default:
+				results[i] = append(results[i], encode.DecodeInt64(col))
+			}
+		}
+	}
+	return results
+}
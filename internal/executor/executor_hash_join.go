@@ -0,0 +1,393 @@
+package executor
+
+import (
+	"github.com/ren-yamanashi/minesql/internal/storage/config"
+)
+
+// HashJoinType はハッシュ結合の種類
+type HashJoinType int
+
+const (
+	HashJoinInner HashJoinType = iota // 内部結合
+	HashJoinOuter                     // 外部結合 (一致する行がないプローブ側の行は、ビルド側のカラムを NULL で補完して返す)
+	HashJoinSemi                      // 準結合 (一致する行があるプローブ側の行を 1 回だけ返す) | EXISTS, IN (SELECT ...)
+	HashJoinAnti                      // 反結合 (一致する行がないプローブ側の行を返す) | NOT EXISTS
+)
+
+func (t HashJoinType) String() string {
+	switch t {
+	case HashJoinOuter:
+		return "LEFT OUTER"
+	case HashJoinSemi:
+		return "SEMI"
+	case HashJoinAnti:
+		return "ANTI"
+	default:
+		return "INNER"
+	}
+}
+
+// HashJoinParams は NewHashJoinWithParams の引数
+type HashJoinParams struct {
+	ProbeExecutor Executor     // プローブ側 (左)。結合レコードの先頭に並ぶ
+	BuildExecutor Executor     // ビルド側 (右)。ハッシュテーブルを構築する
+	ProbeKeys     []int        // プローブ側のレコードの結合キーのカラム位置
+	BuildKeys     []int        // ビルド側のレコードの結合キーのカラム位置
+	Condition     Expression   // 結合キー以外の結合条件 (結合レコードに対して評価する。nil なら結合キーのみ)
+	Type          HashJoinType // 結合の種類
+	BuildColCount int          // ビルド側のレコードのカラム数 (外部結合で NULL で補完するカラム数)
+	BufferSize    int          // メモリ上に保持するビルド側のレコードの合計サイズの上限 (バイト)
+	TmpDir        string       // 分割したレコードを書き出すディレクトリ
+}
+
+// HashJoin はハッシュ結合を実行する
+//
+// ビルド側 (右) の全レコードを結合キーでハッシュテーブルに格納し、プローブ側 (左) の各行の結合キーでハッシュテーブルを検索して、左右のレコードを結合して返す
+// NestedLoopJoin と異なり、右側の Executor は 1 回だけ走査する
+//
+// ビルド側のレコードの合計サイズが BufferSize を超えた場合、両側のレコードを結合キーのハッシュ値で分割して一時ファイルに書き出し (gracePartitioner)、
+// 分割ごとに結合する (Grace Hash Join)。分割がまだ BufferSize を超える場合は、別のハッシュ関数で再分割する
+//
+// 結合キーが NULL の行は (NULL = NULL も含めて) どの行とも結合しない
+// 準結合・反結合の場合は、結合レコードではなくプローブ側のレコードをそのまま返す
+// 分割していない場合はプローブ側の順序を保つが、分割した場合は順序を保証しない
+type HashJoin struct {
+	probeExec     Executor
+	buildExec     Executor
+	probeKeys     []int
+	buildKeys     []int
+	condition     Expression
+	joinType      HashJoinType
+	buildColCount int
+	bufferSize    int
+
+	built        bool                   // ハッシュテーブルを構築済みか (初回の Next で構築する)
+	table        map[string][]Record    // 結合キー → ビルド側のレコード
+	probe        func() (Record, error) // 現在のプローブ側のレコードの読み取り元 (Executor または分割の一時ファイル)
+	partitioner  *gracePartitioner      // 分割 (table にはビルド側、stream にはプローブ側のレコードを書き出す)
+	currentProbe Record                 // 処理中のプローブ側のレコード
+	candidates   []Record               // currentProbe と結合キーが一致する未出力のビルド側のレコード
+	matched      bool                   // currentProbe に一致するビルド側のレコードがあったか
+
+	explainNote // プランナーが選んだアクセス方法と見積もり (EXPLAIN で表示する)
+}
+
+func NewHashJoin(probeExec, buildExec Executor, probeKeys, buildKeys []int) *HashJoin {
+	return NewHashJoinWithParams(HashJoinParams{
+		ProbeExecutor: probeExec,
+		BuildExecutor: buildExec,
+		ProbeKeys:     probeKeys,
+		BuildKeys:     buildKeys,
+		BufferSize:    config.GetJoinBufferSize(),
+		TmpDir:        config.GetDataDirectory(),
+	})
+}
+
+func NewHashJoinWithParams(params HashJoinParams) *HashJoin {
+	return &HashJoin{
+		probeExec:     params.ProbeExecutor,
+		buildExec:     params.BuildExecutor,
+		probeKeys:     params.ProbeKeys,
+		buildKeys:     params.BuildKeys,
+		condition:     params.Condition,
+		joinType:      params.Type,
+		buildColCount: params.BuildColCount,
+		bufferSize:    params.BufferSize,
+		partitioner:   newGracePartitioner(params.TmpDir, "minesql-join-*.tmp"),
+	}
+}
+
+func (hj *HashJoin) Next() (Record, error) {
+	// 初回実行時にビルド側の全レコードを読み込んでハッシュテーブルを構築する
+	if !hj.built {
+		if err := hj.build(); err != nil {
+			hj.partitioner.removeAll()
+			return nil, err
+		}
+		hj.built = true
+	}
+
+	record, err := hj.next()
+	if err != nil || record == nil {
+		hj.partitioner.removeAll()
+	}
+	return record, err
+}
+
+func (hj *HashJoin) Close() {
+	hj.partitioner.removeAll()
+	hj.probeExec.Close()
+	hj.buildExec.Close()
+}
+
+func (hj *HashJoin) Explain() ExplainInfo {
+	return ExplainInfo{
+		Name:     "HashJoin",
+		Detail:   hj.joinType.String(),
+		Children: []*Executor{&hj.probeExec, &hj.buildExec},
+	}
+}
+
+// next は結合したレコードを 1 件返す
+func (hj *HashJoin) next() (Record, error) {
+	for {
+		// 処理中のプローブ側のレコードと結合キーが一致するビルド側のレコードを返す
+		for len(hj.candidates) > 0 {
+			buildRecord := hj.candidates[0]
+			hj.candidates = hj.candidates[1:]
+			record := concatRecords(hj.currentProbe, buildRecord)
+			if hj.condition != nil {
+				value, err := hj.condition.Eval(record)
+				if err != nil {
+					return nil, err
+				}
+				if !isTrue(value) {
+					continue
+				}
+			}
+			hj.matched = true
+			switch hj.joinType {
+			case HashJoinSemi:
+				// 準結合は最初に一致した時点でプローブ側の行を返し、残りの候補は読み飛ばす
+				hj.candidates = nil
+				return hj.currentProbe, nil
+			case HashJoinAnti:
+				// 反結合は一致した時点でプローブ側の行を返さないことが確定する
+				hj.candidates = nil
+			default:
+				return record, nil
+			}
+		}
+
+		// 一致する行がなかった場合、外部結合ではビルド側のカラムを NULL で補完した行を、反結合ではプローブ側の行を返す
+		if hj.currentProbe != nil && !hj.matched {
+			probeRecord := hj.currentProbe
+			hj.currentProbe = nil
+			switch hj.joinType {
+			case HashJoinOuter:
+				return concatRecords(probeRecord, make(Record, hj.buildColCount)), nil
+			case HashJoinAnti:
+				return probeRecord, nil
+			}
+		}
+		hj.currentProbe = nil
+
+		// プローブ側の次の行を取得
+		probeRecord, err := hj.probe()
+		if err != nil {
+			return nil, err
+		}
+		if probeRecord == nil {
+			// 現在の読み取り元を読み終えたら次の分割に進む
+			ok, err := hj.nextPartition()
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				return nil, nil
+			}
+			continue
+		}
+
+		hj.currentProbe = probeRecord
+		hj.matched = false
+		hj.candidates = nil
+		if key, ok := joinKey(probeRecord, hj.probeKeys); ok {
+			hj.candidates = hj.table[key]
+		}
+	}
+}
+
+// build はビルド側の全レコードを読み込んでハッシュテーブルを構築する
+//
+// ビルド側のレコードの合計サイズが BufferSize を超えた場合は、両側のレコードを分割して一時ファイルに書き出す
+func (hj *HashJoin) build() error {
+	hj.table = make(map[string][]Record)
+	size := 0
+	var partitions []*gracePartition
+	for {
+		record, err := hj.buildExec.Next()
+		if err != nil {
+			return err
+		}
+		if record == nil {
+			break
+		}
+		// 結合キーが NULL の行はどの行とも結合しないため格納しない
+		key, ok := joinKey(record, hj.buildKeys)
+		if !ok {
+			continue
+		}
+
+		if partitions != nil {
+			if err := partitionFor(partitions, key, 0).table.write(record); err != nil {
+				return err
+			}
+			continue
+		}
+
+		hj.table[key] = append(hj.table[key], record)
+		size += recordSize(record)
+
+		// バッファサイズを超えたらメモリ上のレコードを分割して書き出し、以降のレコードも分割に書き出す
+		if size > hj.bufferSize {
+			partitions, err = hj.partitioner.create(0)
+			if err != nil {
+				return err
+			}
+			if err := hj.spillTable(partitions, 0); err != nil {
+				return err
+			}
+		}
+	}
+
+	if partitions == nil {
+		hj.probe = hj.probeExec.Next
+		return nil
+	}
+
+	// プローブ側の全レコードも同じハッシュ関数で分割して書き出す
+	for {
+		record, err := hj.probeExec.Next()
+		if err != nil {
+			return err
+		}
+		if record == nil {
+			break
+		}
+		key, _ := joinKey(record, hj.probeKeys)
+		if err := partitionFor(partitions, key, 0).stream.write(record); err != nil {
+			return err
+		}
+	}
+	hj.partitioner.push(partitions)
+	hj.probe = func() (Record, error) { return nil, nil }
+	return nil
+}
+
+// nextPartition は次の分割のビルド側のレコードでハッシュテーブルを構築し、その分割のプローブ側のレコードを読み取り元にする
+//
+// 未処理の分割がない場合は false を返す
+func (hj *HashJoin) nextPartition() (bool, error) {
+	for {
+		partition, err := hj.partitioner.next()
+		if err != nil {
+			return false, err
+		}
+		if partition == nil {
+			hj.probe = func() (Record, error) { return nil, nil }
+			return false, nil
+		}
+
+		// 分割のビルド側のレコードでハッシュテーブルを構築
+		// 分割がまだ BufferSize を超える場合は、次のレベルのハッシュ関数で再分割する
+		repartitioned, err := hj.loadPartition(partition)
+		if err != nil {
+			return false, err
+		}
+		if repartitioned {
+			continue
+		}
+
+		hj.probe = partition.stream.read
+		return true, nil
+	}
+}
+
+// loadPartition は分割のビルド側のレコードでハッシュテーブルを構築する
+//
+// 途中で BufferSize を超えた場合は (再分割の最大回数に達していなければ) 分割を再分割して true を返す
+func (hj *HashJoin) loadPartition(partition *gracePartition) (bool, error) {
+	hj.table = make(map[string][]Record)
+	size := 0
+	for {
+		record, err := partition.table.read()
+		if err != nil {
+			return false, err
+		}
+		if record == nil {
+			return false, nil
+		}
+		key, _ := joinKey(record, hj.buildKeys)
+		hj.table[key] = append(hj.table[key], record)
+		size += recordSize(record)
+
+		if size > hj.bufferSize && partition.level < graceMaxLevel {
+			return true, hj.repartition(partition)
+		}
+	}
+}
+
+// repartition は分割の両側のレコード (ハッシュテーブルに読み込み済みのレコードと未読のレコード) を次のレベルのハッシュ関数で再分割する
+func (hj *HashJoin) repartition(partition *gracePartition) error {
+	level := partition.level + 1
+	partitions, err := hj.partitioner.create(level)
+	if err != nil {
+		return err
+	}
+	if err := hj.spillTable(partitions, level); err != nil {
+		return err
+	}
+
+	for _, side := range []struct {
+		from *partitionFile
+		keys []int
+		to   func(*gracePartition) *partitionFile
+	}{
+		{partition.table, hj.buildKeys, func(p *gracePartition) *partitionFile { return p.table }},
+		{partition.stream, hj.probeKeys, func(p *gracePartition) *partitionFile { return p.stream }},
+	} {
+		for {
+			record, err := side.from.read()
+			if err != nil {
+				return err
+			}
+			if record == nil {
+				break
+			}
+			key, _ := joinKey(record, side.keys)
+			if err := side.to(partitionFor(partitions, key, level)).write(record); err != nil {
+				return err
+			}
+		}
+	}
+
+	// 再分割した分割を未処理の分割の先頭に積む
+	hj.partitioner.push(partitions)
+	return nil
+}
+
+// spillTable はハッシュテーブルのレコードを分割に書き出し、ハッシュテーブルを空にする
+func (hj *HashJoin) spillTable(partitions []*gracePartition, level int) error {
+	for key, records := range hj.table {
+		for _, record := range records {
+			if err := partitionFor(partitions, key, level).table.write(record); err != nil {
+				return err
+			}
+		}
+	}
+	hj.table = make(map[string][]Record)
+	return nil
+}
+
+// joinKey は結合キーをハッシュテーブルのキーに変換する
+//
+// 結合キーのいずれかのカラムが NULL の場合は false を返す
+func joinKey(record Record, keys []int) (string, bool) {
+	key := make(Record, len(keys))
+	for i, pos := range keys {
+		if record[pos] == nil {
+			return "", false
+		}
+		key[i] = record[pos]
+	}
+	return groupHashKey(key), true
+}
+
+// concatRecords は左右のレコードを結合する
+func concatRecords(left, right Record) Record {
+	result := make(Record, len(left)+len(right))
+	copy(result, left)
+	copy(result[len(left):], right)
+	return result
+}
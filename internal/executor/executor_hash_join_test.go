@@ -0,0 +1,241 @@
+package executor
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHashJoin(t *testing.T) {
+	// users (id, name) と orders (id, user_id) のレコード
+	newUsers := func() *mockExecutor {
+		return &mockExecutor{records: []Record{
+			{[]byte("1"), []byte("Alice")},
+			{[]byte("2"), []byte("Bob")},
+			{nil, []byte("Carol")},
+			{[]byte("3"), []byte("Dave")},
+		}}
+	}
+	newOrders := func() *mockExecutor {
+		return &mockExecutor{records: []Record{
+			{[]byte("o1"), []byte("3")},
+			{[]byte("o2"), []byte("1")},
+			{[]byte("o3"), nil},
+			{[]byte("o4"), []byte("1")},
+		}}
+	}
+
+	t.Run("結合キーが一致する左右のレコードを、プローブ側の順に結合して返す", func(t *testing.T) {
+		// GIVEN
+		hj := NewHashJoinWithParams(HashJoinParams{
+			ProbeExecutor: newUsers(),
+			BuildExecutor: newOrders(),
+			ProbeKeys:     []int{0},
+			BuildKeys:     []int{1},
+			BufferSize:    1024,
+			TmpDir:        t.TempDir(),
+		})
+
+		// WHEN
+		results := collectAll(t, hj)
+
+		// THEN: 結合キーが NULL の行はどの行とも結合しない
+		assert.Equal(t, []Record{
+			{[]byte("1"), []byte("Alice"), []byte("o2"), []byte("1")},
+			{[]byte("1"), []byte("Alice"), []byte("o4"), []byte("1")},
+			{[]byte("3"), []byte("Dave"), []byte("o1"), []byte("3")},
+		}, results)
+	})
+
+	t.Run("外部結合の場合、一致する行がないプローブ側の行はビルド側のカラムを NULL で補完して返す", func(t *testing.T) {
+		// GIVEN
+		hj := NewHashJoinWithParams(HashJoinParams{
+			ProbeExecutor: newUsers(),
+			BuildExecutor: newOrders(),
+			ProbeKeys:     []int{0},
+			BuildKeys:     []int{1},
+			Type:          HashJoinOuter,
+			BuildColCount: 2,
+			BufferSize:    1024,
+			TmpDir:        t.TempDir(),
+		})
+
+		// WHEN
+		results := collectAll(t, hj)
+
+		// THEN
+		assert.Equal(t, []Record{
+			{[]byte("1"), []byte("Alice"), []byte("o2"), []byte("1")},
+			{[]byte("1"), []byte("Alice"), []byte("o4"), []byte("1")},
+			{[]byte("2"), []byte("Bob"), nil, nil},
+			{nil, []byte("Carol"), nil, nil},
+			{[]byte("3"), []byte("Dave"), []byte("o1"), []byte("3")},
+		}, results)
+	})
+
+	t.Run("結合キー以外の結合条件を満たさない行は、外部結合では一致しない行として扱う", func(t *testing.T) {
+		// GIVEN: orders.id = 'o4' の行のみ結合条件を満たす
+		hj := NewHashJoinWithParams(HashJoinParams{
+			ProbeExecutor: newUsers(),
+			BuildExecutor: newOrders(),
+			ProbeKeys:     []int{0},
+			BuildKeys:     []int{1},
+			Condition:     ConditionFunc(func(r Record) bool { return string(r[2]) == "o4" }),
+			Type:          HashJoinOuter,
+			BuildColCount: 2,
+			BufferSize:    1024,
+			TmpDir:        t.TempDir(),
+		})
+
+		// WHEN
+		results := collectAll(t, hj)
+
+		// THEN
+		assert.Equal(t, []Record{
+			{[]byte("1"), []byte("Alice"), []byte("o4"), []byte("1")},
+			{[]byte("2"), []byte("Bob"), nil, nil},
+			{nil, []byte("Carol"), nil, nil},
+			{[]byte("3"), []byte("Dave"), nil, nil},
+		}, results)
+	})
+
+	t.Run("準結合の場合、一致する行があるプローブ側の行を 1 回だけ返す", func(t *testing.T) {
+		// GIVEN
+		hj := NewHashJoinWithParams(HashJoinParams{
+			ProbeExecutor: newUsers(),
+			BuildExecutor: newOrders(),
+			ProbeKeys:     []int{0},
+			BuildKeys:     []int{1},
+			Type:          HashJoinSemi,
+			BufferSize:    1024,
+			TmpDir:        t.TempDir(),
+		})
+
+		// WHEN
+		results := collectAll(t, hj)
+
+		// THEN: Alice は 2 行と一致するが 1 回だけ返す
+		assert.Equal(t, []Record{
+			{[]byte("1"), []byte("Alice")},
+			{[]byte("3"), []byte("Dave")},
+		}, results)
+	})
+
+	t.Run("反結合の場合、一致する行がないプローブ側の行を返す (結合キーが NULL の行を含む)", func(t *testing.T) {
+		// GIVEN: orders.id = 'o1' の行のみ結合条件を満たす
+		hj := NewHashJoinWithParams(HashJoinParams{
+			ProbeExecutor: newUsers(),
+			BuildExecutor: newOrders(),
+			ProbeKeys:     []int{0},
+			BuildKeys:     []int{1},
+			Condition:     ConditionFunc(func(r Record) bool { return string(r[2]) == "o1" }),
+			Type:          HashJoinAnti,
+			BufferSize:    1024,
+			TmpDir:        t.TempDir(),
+		})
+
+		// WHEN
+		results := collectAll(t, hj)
+
+		// THEN
+		assert.Equal(t, []Record{
+			{[]byte("1"), []byte("Alice")},
+			{[]byte("2"), []byte("Bob")},
+			{nil, []byte("Carol")},
+		}, results)
+	})
+
+	t.Run("ビルド側がバッファサイズを超えた場合、両側を分割して一時ファイルに書き出して結合する", func(t *testing.T) {
+		// GIVEN: 左 50 行 (id = 0..49)、右 100 行 (key = 0..49 を 2 行ずつ) で、1 行ごとに再分割が必要になるほど小さいバッファサイズ
+		var probeRecords, buildRecords, expected []Record
+		for i := range 50 {
+			probeRecords = append(probeRecords, Record{[]byte(fmt.Sprintf("%02d", i))})
+		}
+		for i := range 100 {
+			buildRecords = append(buildRecords, Record{[]byte(fmt.Sprintf("%02d", i%50)), []byte(fmt.Sprintf("r%03d", i))})
+			expected = append(expected, Record{[]byte(fmt.Sprintf("%02d", i%50)), []byte(fmt.Sprintf("%02d", i%50)), []byte(fmt.Sprintf("r%03d", i))})
+		}
+		probeRecords = append(probeRecords, Record{[]byte("99")})
+		expected = append(expected, Record{[]byte("99"), nil, nil})
+		tmpDir := t.TempDir()
+		hj := NewHashJoinWithParams(HashJoinParams{
+			ProbeExecutor: &mockExecutor{records: probeRecords},
+			BuildExecutor: &mockExecutor{records: buildRecords},
+			ProbeKeys:     []int{0},
+			BuildKeys:     []int{0},
+			Type:          HashJoinOuter,
+			BuildColCount: 2,
+			BufferSize:    1,
+			TmpDir:        tmpDir,
+		})
+
+		// WHEN
+		first, err := hj.Next()
+		require.NoError(t, err)
+		entries, err := os.ReadDir(tmpDir)
+		require.NoError(t, err)
+		results := append([]Record{first}, collectAll(t, hj)...)
+
+		// THEN: 分割した場合はプローブ側の順序を保たないため、並べ替えて比較する。一時ファイルは削除される
+		assert.NotEmpty(t, entries)
+		sortRecords := func(records []Record) {
+			slices.SortFunc(records, func(a, b Record) int {
+				if c := bytes.Compare(a[0], b[0]); c != 0 {
+					return c
+				}
+				return bytes.Compare(a[2], b[2])
+			})
+		}
+		sortRecords(results)
+		sortRecords(expected)
+		assert.Equal(t, expected, results)
+		entries, err = os.ReadDir(tmpDir)
+		require.NoError(t, err)
+		assert.Empty(t, entries)
+
+		// 読み終えた後も nil を返し続ける
+		record, err := hj.Next()
+		assert.NoError(t, err)
+		assert.Nil(t, record)
+	})
+
+	t.Run("最後まで読み取る前に Close すると、分割の一時ファイルを削除し左右の Executor を閉じる", func(t *testing.T) {
+		// GIVEN: 両側を分割して書き出した後、先頭の 1 件だけ読み取った状態
+		var probeRecords, buildRecords []Record
+		for i := range 20 {
+			probeRecords = append(probeRecords, Record{[]byte(fmt.Sprintf("%02d", i))})
+			buildRecords = append(buildRecords, Record{[]byte(fmt.Sprintf("%02d", i))})
+		}
+		probe := &mockExecutor{records: probeRecords}
+		build := &mockExecutor{records: buildRecords}
+		tmpDir := t.TempDir()
+		hj := NewHashJoinWithParams(HashJoinParams{
+			ProbeExecutor: probe,
+			BuildExecutor: build,
+			ProbeKeys:     []int{0},
+			BuildKeys:     []int{0},
+			BufferSize:    1,
+			TmpDir:        tmpDir,
+		})
+		_, err := hj.Next()
+		require.NoError(t, err)
+		entries, err := os.ReadDir(tmpDir)
+		require.NoError(t, err)
+		require.NotEmpty(t, entries)
+
+		// WHEN
+		hj.Close()
+
+		// THEN
+		entries, err = os.ReadDir(tmpDir)
+		require.NoError(t, err)
+		assert.Empty(t, entries)
+		assert.True(t, probe.closed)
+		assert.True(t, build.closed)
+	})
+}
@@ -0,0 +1,398 @@
+package executor
+
+import (
+	"encoding/binary"
+	"errors"
+
+	"github.com/ren-yamanashi/minesql/internal/storage/config"
+)
+
+// SetOperationType は HashSetOperation が行う演算の種類
+type SetOperationType int
+
+const (
+	SetOperationDistinct  SetOperationType = iota // 重複の除去 | SELECT DISTINCT, UNION
+	SetOperationIntersect                         // 積集合 (右側にもある左側の行を返す) | INTERSECT
+	SetOperationExcept                            // 差集合 (右側にない左側の行を返す) | EXCEPT
+)
+
+func (t SetOperationType) String() string {
+	switch t {
+	case SetOperationIntersect:
+		return "HashIntersect"
+	case SetOperationExcept:
+		return "HashExcept"
+	default:
+		return "HashDistinct"
+	}
+}
+
+// HashSetOperationParams は NewHashSetOperationWithParams の引数
+type HashSetOperationParams struct {
+	LeftExecutor  Executor         // 左側。結果のレコードは左側のレコード
+	RightExecutor Executor         // 右側 (SetOperationDistinct の場合は nil)
+	Type          SetOperationType // 演算の種類
+	All           bool             // INTERSECT ALL / EXCEPT ALL の場合は true (重複を除かず、行の数を数えて比較する)
+	BufferSize    int              // メモリ上に保持するハッシュテーブルの合計サイズの上限 (バイト)
+	TmpDir        string           // 分割したレコードを書き出すディレクトリ
+}
+
+// HashSetOperation はハッシュテーブルを使って DISTINCT・INTERSECT・EXCEPT を実行する
+//
+// 右側の全レコードを行ごとの件数としてハッシュテーブルに格納し、左側の各行をハッシュテーブルと照合して返すかどうかを決める
+// 重複を除く場合は、返した行もハッシュテーブルに記録して 2 回目以降は返さない
+//   - DISTINCT: まだ返していない行を返す
+//   - INTERSECT: 右側にあり、まだ返していない行を返す (ALL の場合は右側の件数を上限に返す)
+//   - EXCEPT: 右側になく、まだ返していない行を返す (ALL の場合は右側の件数分だけ読み飛ばす)
+//
+// ハッシュテーブルの合計サイズが BufferSize を超えた場合、ハッシュテーブルの内容と未読の両側のレコードを行のハッシュ値で分割して一時ファイルに書き出し (gracePartitioner)、
+// 分割ごとに続きを処理する (同じ行は必ず同じ分割に入る)。分割がまだ BufferSize を超える場合は、別のハッシュ関数で再分割する
+//
+// NULL 同士は等しい行として扱う
+// 分割していない場合は左側の順序を保つが、分割した場合は順序を保証しない
+type HashSetOperation struct {
+	leftExec   Executor
+	rightExec  Executor
+	opType     SetOperationType
+	all        bool
+	bufferSize int
+
+	built       bool                          // ハッシュテーブルを構築済みか (初回の Next で構築する)
+	table       map[string]*setOperationEntry // 行のキー → 行ごとの状態
+	size        int                           // ハッシュテーブルの合計サイズ
+	level       int                           // 現在処理中の分割のレベル (分割していない場合は -1)
+	left        func() (Record, error)        // 現在の左側のレコードの読み取り元 (Executor または分割の一時ファイル)
+	partitioner *gracePartitioner             // 分割 (table にはハッシュテーブルのエントリ、stream には左側のレコードを書き出す)
+
+	explainNote // プランナーが選んだアクセス方法と見積もり (EXPLAIN で表示する)
+}
+
+// setOperationEntry はハッシュテーブルに格納する行ごとの状態
+type setOperationEntry struct {
+	count    uint64 // 右側の行の残り件数
+	returned bool   // 既に結果として返したか
+}
+
+// NewHashDistinct は InnerExecutor の結果から重複する行を除く HashSetOperation を作成する
+func NewHashDistinct(innerExecutor Executor) *HashSetOperation {
+	return NewHashSetOperation(innerExecutor, nil, SetOperationDistinct, false)
+}
+
+func NewHashSetOperation(leftExec, rightExec Executor, opType SetOperationType, all bool) *HashSetOperation {
+	return NewHashSetOperationWithParams(HashSetOperationParams{
+		LeftExecutor:  leftExec,
+		RightExecutor: rightExec,
+		Type:          opType,
+		All:           all,
+		BufferSize:    config.GetSetOperationBufferSize(),
+		TmpDir:        config.GetDataDirectory(),
+	})
+}
+
+func NewHashSetOperationWithParams(params HashSetOperationParams) *HashSetOperation {
+	return &HashSetOperation{
+		leftExec:    params.LeftExecutor,
+		rightExec:   params.RightExecutor,
+		opType:      params.Type,
+		all:         params.All,
+		bufferSize:  params.BufferSize,
+		partitioner: newGracePartitioner(params.TmpDir, "minesql-set-operation-*.tmp"),
+	}
+}
+
+func (so *HashSetOperation) Next() (Record, error) {
+	// 初回実行時に右側の全レコードを読み込んでハッシュテーブルを構築する
+	if !so.built {
+		if err := so.build(); err != nil {
+			so.partitioner.removeAll()
+			return nil, err
+		}
+		so.built = true
+	}
+
+	record, err := so.next()
+	if err != nil || record == nil {
+		so.partitioner.removeAll()
+	}
+	return record, err
+}
+
+func (so *HashSetOperation) Close() {
+	so.partitioner.removeAll()
+	so.leftExec.Close()
+	if so.rightExec != nil {
+		so.rightExec.Close()
+	}
+}
+
+func (so *HashSetOperation) Explain() ExplainInfo {
+	info := ExplainInfo{Name: so.opType.String(), Children: []*Executor{&so.leftExec}}
+	if so.rightExec != nil {
+		info.Children = append(info.Children, &so.rightExec)
+	}
+	if so.all {
+		info.Detail = "ALL"
+	}
+	return info
+}
+
+// next は結果のレコードを 1 件返す
+func (so *HashSetOperation) next() (Record, error) {
+	for {
+		record, err := so.left()
+		if err != nil {
+			return nil, err
+		}
+		if record == nil {
+			// 現在の読み取り元を読み終えたら次の分割に進む
+			ok, err := so.nextPartition()
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				return nil, nil
+			}
+			continue
+		}
+
+		ok := so.apply(groupHashKey(record))
+
+		// 返した行を記録してバッファサイズを超えた場合は、ハッシュテーブルと未読の左側のレコードを分割して書き出す
+		if so.size > so.bufferSize && so.level < graceMaxLevel {
+			if err := so.spill(nil); err != nil {
+				return nil, err
+			}
+		}
+		if ok {
+			return record, nil
+		}
+	}
+}
+
+// apply は左側の行をハッシュテーブルと照合し、結果として返すかどうかを返す (ハッシュテーブルの状態も更新する)
+func (so *HashSetOperation) apply(key string) bool {
+	entry := so.table[key]
+	switch so.opType {
+	case SetOperationIntersect:
+		if entry == nil || entry.count == 0 {
+			return false
+		}
+		if so.all {
+			entry.count--
+			return true
+		}
+		if entry.returned {
+			return false
+		}
+		entry.returned = true
+		return true
+
+	case SetOperationExcept:
+		if entry != nil && entry.count > 0 {
+			if so.all {
+				entry.count--
+			}
+			return false
+		}
+		if so.all {
+			return true
+		}
+	}
+
+	// DISTINCT・EXCEPT (重複を除く場合) は、まだ返していない行を返す
+	if entry == nil {
+		entry = so.addEntry(key)
+	}
+	if entry.returned {
+		return false
+	}
+	entry.returned = true
+	return true
+}
+
+// build は右側の全レコードを読み込み、行ごとの件数をハッシュテーブルに格納する
+//
+// ハッシュテーブルの合計サイズが BufferSize を超えた場合は、両側のレコードを分割して一時ファイルに書き出す
+func (so *HashSetOperation) build() error {
+	so.table = make(map[string]*setOperationEntry)
+	so.size = 0
+	so.level = -1
+	so.left = so.leftExec.Next
+	if so.rightExec == nil {
+		return nil
+	}
+
+	// 未読の右側のレコードは、件数 1 のエントリとして分割に書き出す
+	rightEntries := func() (Record, error) {
+		record, err := so.rightExec.Next()
+		if err != nil || record == nil {
+			return nil, err
+		}
+		return encodeSetOperationEntry(groupHashKey(record), &setOperationEntry{count: 1}), nil
+	}
+	for {
+		record, err := so.rightExec.Next()
+		if err != nil {
+			return err
+		}
+		if record == nil {
+			return nil
+		}
+		key := groupHashKey(record)
+		entry := so.table[key]
+		if entry == nil {
+			entry = so.addEntry(key)
+		}
+		entry.count++
+
+		if so.size > so.bufferSize {
+			return so.spill(rightEntries)
+		}
+	}
+}
+
+// nextPartition は次の分割のエントリでハッシュテーブルを構築し、その分割の左側のレコードを読み取り元にする
+//
+// 未処理の分割がない場合は false を返す
+func (so *HashSetOperation) nextPartition() (bool, error) {
+	for {
+		partition, err := so.partitioner.next()
+		if err != nil {
+			return false, err
+		}
+		if partition == nil {
+			so.left = func() (Record, error) { return nil, nil }
+			return false, nil
+		}
+
+		// 分割のエントリでハッシュテーブルを構築
+		// 分割がまだ BufferSize を超える場合は、次のレベルのハッシュ関数で再分割する
+		repartitioned, err := so.loadPartition(partition)
+		if err != nil {
+			return false, err
+		}
+		if !repartitioned {
+			return true, nil
+		}
+	}
+}
+
+// loadPartition は分割のエントリでハッシュテーブルを構築し、分割の左側のレコードを読み取り元にする (同じ行のエントリは件数を合算する)
+//
+// 途中で BufferSize を超えた場合は (再分割の最大回数に達していなければ) 分割を再分割して true を返す
+func (so *HashSetOperation) loadPartition(partition *gracePartition) (bool, error) {
+	so.table = make(map[string]*setOperationEntry)
+	so.size = 0
+	so.level = partition.level
+	so.left = partition.stream.read
+
+	entries := partition.table.read
+	for {
+		record, err := entries()
+		if err != nil {
+			return false, err
+		}
+		if record == nil {
+			return false, nil
+		}
+		key, loaded, err := decodeSetOperationEntry(record)
+		if err != nil {
+			return false, err
+		}
+		entry := so.table[key]
+		if entry == nil {
+			entry = so.addEntry(key)
+		}
+		entry.count += loaded.count
+		entry.returned = entry.returned || loaded.returned
+
+		if so.size > so.bufferSize && so.level < graceMaxLevel {
+			return true, so.spill(entries)
+		}
+	}
+}
+
+// spill はハッシュテーブルのエントリ、未読のエントリ (entries が nil でない場合)、未読の左側のレコードを次のレベルのハッシュ関数で分割して書き出す
+//
+// 分割した分割は未処理の分割の先頭に積み、以降は分割ごとに処理する
+func (so *HashSetOperation) spill(entries func() (Record, error)) error {
+	level := so.level + 1
+	partitions, err := so.partitioner.create(level)
+	if err != nil {
+		return err
+	}
+
+	for key, entry := range so.table {
+		if err := partitionFor(partitions, key, level).table.write(encodeSetOperationEntry(key, entry)); err != nil {
+			return err
+		}
+	}
+	so.table = make(map[string]*setOperationEntry)
+	so.size = 0
+
+	if entries != nil {
+		for {
+			record, err := entries()
+			if err != nil {
+				return err
+			}
+			if record == nil {
+				break
+			}
+			if err := partitionFor(partitions, string(record[0]), level).table.write(record); err != nil {
+				return err
+			}
+		}
+	}
+
+	for {
+		record, err := so.left()
+		if err != nil {
+			return err
+		}
+		if record == nil {
+			break
+		}
+		if err := partitionFor(partitions, groupHashKey(record), level).stream.write(record); err != nil {
+			return err
+		}
+	}
+
+	so.partitioner.push(partitions)
+	so.left = func() (Record, error) { return nil, nil }
+	return nil
+}
+
+// addEntry はハッシュテーブルに空のエントリを追加する
+func (so *HashSetOperation) addEntry(key string) *setOperationEntry {
+	entry := &setOperationEntry{}
+	so.table[key] = entry
+	so.size += setOperationEntrySize(key)
+	return entry
+}
+
+// setOperationEntrySize はハッシュテーブルのエントリがメモリ上で占めるおおよそのサイズ (バイト) を返す
+func setOperationEntrySize(key string) int {
+	return 16 + len(key) + 16 // キーの文字列ヘッダー + データ + エントリ
+}
+
+// encodeSetOperationEntry はハッシュテーブルのエントリを分割に書き出すレコード ([キー, 件数, 返したか]) に変換する
+func encodeSetOperationEntry(key string, entry *setOperationEntry) Record {
+	returned := byte(0)
+	if entry.returned {
+		returned = 1
+	}
+	return Record{[]byte(key), binary.AppendUvarint(nil, entry.count), {returned}}
+}
+
+// decodeSetOperationEntry は分割から読み込んだレコードをハッシュテーブルのエントリに戻す
+func decodeSetOperationEntry(record Record) (string, *setOperationEntry, error) {
+	if len(record) != 3 || len(record[2]) != 1 {
+		return "", nil, errors.New("invalid set operation entry")
+	}
+	count, n := binary.Uvarint(record[1])
+	if n <= 0 {
+		return "", nil, errors.New("invalid set operation entry")
+	}
+	return string(record[0]), &setOperationEntry{count: count, returned: record[2][0] == 1}, nil
+}
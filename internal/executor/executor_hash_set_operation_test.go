@@ -0,0 +1,192 @@
+package executor
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHashSetOperation(t *testing.T) {
+	// 左側: a, b, b, c, NULL, NULL / 右側: b, c, c, d, NULL
+	left := func() Executor {
+		return &mockExecutor{records: []Record{
+			{[]byte("a")}, {[]byte("b")}, {[]byte("b")}, {[]byte("c")}, {nil}, {nil},
+		}}
+	}
+	right := func() Executor {
+		return &mockExecutor{records: []Record{
+			{[]byte("b")}, {[]byte("c")}, {[]byte("c")}, {[]byte("d")}, {nil},
+		}}
+	}
+
+	t.Run("DISTINCT は重複する行を除き、最初に現れた順に返す", func(t *testing.T) {
+		// GIVEN
+		so := NewHashSetOperationWithParams(HashSetOperationParams{
+			LeftExecutor: left(),
+			Type:         SetOperationDistinct,
+			BufferSize:   1024,
+			TmpDir:       t.TempDir(),
+		})
+
+		// WHEN
+		results := collectAll(t, so)
+
+		// THEN
+		assert.Equal(t, []Record{{[]byte("a")}, {[]byte("b")}, {[]byte("c")}, {nil}}, results)
+	})
+
+	tests := []struct {
+		name     string
+		opType   SetOperationType
+		all      bool
+		expected []Record
+	}{
+		{"INTERSECT は右側にもある行を重複を除いて返す", SetOperationIntersect, false, []Record{{[]byte("b")}, {[]byte("c")}, {nil}}},
+		{"INTERSECT ALL は右側の件数を上限に返す", SetOperationIntersect, true, []Record{{[]byte("b")}, {[]byte("c")}, {nil}}},
+		{"EXCEPT は右側にない行を重複を除いて返す", SetOperationExcept, false, []Record{{[]byte("a")}}},
+		{"EXCEPT ALL は右側の件数分だけ読み飛ばして返す", SetOperationExcept, true, []Record{{[]byte("a")}, {[]byte("b")}, {nil}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// GIVEN
+			so := NewHashSetOperationWithParams(HashSetOperationParams{
+				LeftExecutor:  left(),
+				RightExecutor: right(),
+				Type:          tt.opType,
+				All:           tt.all,
+				BufferSize:    1024,
+				TmpDir:        t.TempDir(),
+			})
+
+			// WHEN
+			results := collectAll(t, so)
+
+			// THEN
+			assert.Equal(t, tt.expected, results)
+		})
+	}
+
+	t.Run("ハッシュテーブルがバッファサイズを超えた場合、分割して一時ファイルに書き出して処理する", func(t *testing.T) {
+		// GIVEN: 左側 200 行 (0..99 を 2 行ずつ)、右側 100 行 (50..99 を 2 行ずつ) で、1 行ごとに再分割が必要になるほど小さいバッファサイズ
+		var leftRecords, rightRecords []Record
+		for i := range 200 {
+			leftRecords = append(leftRecords, Record{[]byte(fmt.Sprintf("%02d", i%100))})
+		}
+		for i := range 100 {
+			rightRecords = append(rightRecords, Record{[]byte(fmt.Sprintf("%02d", 50+i%50))})
+		}
+		tmpDir := t.TempDir()
+		newOperation := func(opType SetOperationType, all bool) *HashSetOperation {
+			var rightExec Executor
+			if opType != SetOperationDistinct {
+				rightExec = &mockExecutor{records: rightRecords}
+			}
+			return NewHashSetOperationWithParams(HashSetOperationParams{
+				LeftExecutor:  &mockExecutor{records: leftRecords},
+				RightExecutor: rightExec,
+				Type:          opType,
+				All:           all,
+				BufferSize:    1,
+				TmpDir:        tmpDir,
+			})
+		}
+		keys := func(from, to, times int) []Record {
+			var records []Record
+			for i := from; i < to; i++ {
+				for range times {
+					records = append(records, Record{[]byte(fmt.Sprintf("%02d", i))})
+				}
+			}
+			return records
+		}
+		sortRecords := func(records []Record) []Record {
+			slices.SortFunc(records, func(a, b Record) int { return bytes.Compare(a[0], b[0]) })
+			return records
+		}
+
+		for _, tc := range []struct {
+			opType   SetOperationType
+			all      bool
+			expected []Record
+		}{
+			{SetOperationDistinct, false, keys(0, 100, 1)},
+			{SetOperationIntersect, false, keys(50, 100, 1)},
+			{SetOperationIntersect, true, keys(50, 100, 2)},
+			{SetOperationExcept, false, keys(0, 50, 1)},
+			{SetOperationExcept, true, keys(0, 50, 2)},
+		} {
+			so := newOperation(tc.opType, tc.all)
+
+			// WHEN
+			first, err := so.Next()
+			require.NoError(t, err)
+			entries, err := os.ReadDir(tmpDir)
+			require.NoError(t, err)
+			results := append([]Record{first}, collectAll(t, so)...)
+
+			// THEN: 分割した場合は順序を保たないため、並べ替えて比較する。一時ファイルは削除される
+			assert.NotEmpty(t, entries)
+			assert.Equal(t, tc.expected, sortRecords(results), "%s ALL=%v", tc.opType, tc.all)
+			entries, err = os.ReadDir(tmpDir)
+			require.NoError(t, err)
+			assert.Empty(t, entries)
+
+			// 読み終えた後も nil を返し続ける
+			record, err := so.Next()
+			assert.NoError(t, err)
+			assert.Nil(t, record)
+		}
+	})
+
+	t.Run("最後まで読み取る前に Close すると、分割の一時ファイルを削除し左右の Executor を閉じる", func(t *testing.T) {
+		// GIVEN: 分割して書き出した後、先頭の 1 件だけ読み取った状態
+		var leftRecords, rightRecords []Record
+		for i := range 20 {
+			leftRecords = append(leftRecords, Record{[]byte(fmt.Sprintf("%02d", i))})
+			rightRecords = append(rightRecords, Record{[]byte(fmt.Sprintf("%02d", i+10))})
+		}
+		leftExec := &mockExecutor{records: leftRecords}
+		rightExec := &mockExecutor{records: rightRecords}
+		tmpDir := t.TempDir()
+		so := NewHashSetOperationWithParams(HashSetOperationParams{
+			LeftExecutor:  leftExec,
+			RightExecutor: rightExec,
+			Type:          SetOperationExcept,
+			BufferSize:    1,
+			TmpDir:        tmpDir,
+		})
+		_, err := so.Next()
+		require.NoError(t, err)
+		entries, err := os.ReadDir(tmpDir)
+		require.NoError(t, err)
+		require.NotEmpty(t, entries)
+
+		// WHEN
+		so.Close()
+
+		// THEN
+		entries, err = os.ReadDir(tmpDir)
+		require.NoError(t, err)
+		assert.Empty(t, entries)
+		assert.True(t, leftExec.closed)
+		assert.True(t, rightExec.closed)
+	})
+
+	t.Run("Explain は演算の種類と ALL を表示する", func(t *testing.T) {
+		// GIVEN
+		so := NewHashSetOperation(left(), right(), SetOperationExcept, true)
+
+		// WHEN
+		info := so.Explain()
+
+		// THEN
+		assert.Equal(t, "HashExcept", info.Name)
+		assert.Equal(t, "ALL", info.Detail)
+		assert.Len(t, info.Children, 2)
+	})
+}
@@ -13,8 +13,8 @@ type IndexScan struct {
 	whileCondition func(Record) bool
 	indexOnly      bool // true の場合、テーブル本体の検索をスキップし index データのみで結果を返す
 	nCols          int  // テーブルのカラム数 (indexOnly 時のレコード構築用)
-	secColPos      int  // セカンダリキーのカラム位置 (indexOnly 時のレコード構築用)
 	iterator       *access.SecondaryIndexIterator
+	explainNote    // プランナーが選んだアクセス方法と見積もり (EXPLAIN で表示する)
 }
 
 type IndexScanParams struct {
@@ -24,7 +24,6 @@ type IndexScanParams struct {
 	WhileCondition func(Record) bool
 	IndexOnly      bool
 	NCols          int
-	SecColPos      int
 }
 
 func NewIndexScan(
@@ -49,7 +48,6 @@ func NewIndexScanWithParams(params IndexScanParams) *IndexScan {
 		whileCondition: params.WhileCondition,
 		indexOnly:      params.IndexOnly,
 		nCols:          params.NCols,
-		secColPos:      params.SecColPos,
 	}
 }
 
@@ -71,6 +69,8 @@ func (is *IndexScan) Next() (Record, error) {
 	return is.nextWithPrimaryLookup()
 }
 
+func (is *IndexScan) Close() {}
+
 // nextWithPrimaryLookup はインデックスから取得後、PK でテーブル本体を検索して全カラムを返す (従来の動作)
 func (is *IndexScan) nextWithPrimaryLookup() (Record, error) {
 	result, ok, err := is.iterator.Next()
@@ -88,7 +88,7 @@ func (is *IndexScan) nextWithPrimaryLookup() (Record, error) {
 
 // nextIndexOnly はインデックスデータのみからレコードを構築する (テーブル本体の検索なし)
 //
-// PK カラムはインデックスキーに含まれるため取得可能。UK カラムも同様。
+// PK カラムはインデックスキーに含まれるため取得可能。インデックスのカラムも同様。
 // その他のカラムは nil を設定し、Project で必要なカラムのみ取り出す
 func (is *IndexScan) nextIndexOnly() (Record, error) {
 	result, ok, err := is.iterator.NextIndexOnly()
@@ -106,10 +106,21 @@ func (is *IndexScan) nextIndexOnly() (Record, error) {
 	record := make(Record, is.nCols)
 	// PK カラム (先頭から pkCount 個)
 	copy(record, result.PKValues)
-	// UK カラム
-	if is.secColPos < is.nCols && len(result.SecondaryKey) > 0 {
-		record[is.secColPos] = result.SecondaryKey[0]
+	// インデックスのカラム
+	for i, colIdx := range is.index.ColIdxs {
+		if int(colIdx) < is.nCols && i < len(result.SecondaryKey) {
+			record[colIdx] = result.SecondaryKey[i]
+		}
 	}
 
 	return record, nil
 }
+
+func (is *IndexScan) Explain() ExplainInfo {
+	// 名前を指定せずに作成した UNIQUE KEY は (MySQL と同様に) カラム名で表示する
+	name := is.index.Name
+	if name == "" {
+		name = is.index.ColNames[0]
+	}
+	return ExplainInfo{Name: "IndexScan", Detail: is.table.Name + "." + name}
+}
@@ -81,7 +81,6 @@ func TestIndexScan(t *testing.T) {
 			WhileCondition: func(record Record) bool { return true },
 			IndexOnly:      true,
 			NCols:          3,
-			SecColPos:      2,
 		}
 
 		// WHEN
@@ -91,7 +90,6 @@ func TestIndexScan(t *testing.T) {
 		assert.NotNil(t, indexScan)
 		assert.True(t, indexScan.indexOnly)
 		assert.Equal(t, 3, indexScan.nCols)
-		assert.Equal(t, 2, indexScan.secColPos)
 	})
 
 	t.Run("index-only scan で PK と UK のみ取得できる", func(t *testing.T) {
@@ -114,7 +112,6 @@ func TestIndexScan(t *testing.T) {
 			WhileCondition: func(record Record) bool { return true },
 			IndexOnly:      true,
 			NCols:          3,
-			SecColPos:      2,
 		})
 
 		// WHEN
@@ -168,7 +165,6 @@ func TestIndexScan(t *testing.T) {
 			},
 			IndexOnly: true,
 			NCols:     3,
-			SecColPos: 2,
 		})
 
 		// WHEN
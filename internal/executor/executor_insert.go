@@ -1,15 +1,36 @@
 package executor
 
 import (
+	"bytes"
+	"errors"
+	"fmt"
+	"slices"
+
 	"github.com/ren-yamanashi/minesql/internal/storage/access"
+	"github.com/ren-yamanashi/minesql/internal/storage/btree"
+	"github.com/ren-yamanashi/minesql/internal/storage/encode"
 	"github.com/ren-yamanashi/minesql/internal/storage/handler"
 )
 
 // Insert はレコードを追加する
+//
+// AUTO_INCREMENT カラムが NULL または 0 のレコードには、採番した値を設定してから追加する
+//
+// プライマリキーまたは UNIQUE インデックスのキーが既存の行と重複する場合の動作は、以下の設定に従う (設定しない場合はエラーにする)
+//   - SetIgnore: 追加しない (INSERT IGNORE)
+//   - SetOnDuplicateKeyUpdate: 既存の行を更新する (INSERT ... ON DUPLICATE KEY UPDATE)
+//   - SetReplace: 既存の行を削除してから追加する (REPLACE)
 type Insert struct {
-	trxId   handler.TrxId
-	table   *access.Table
-	records []Record
+	trxId         handler.TrxId
+	table         *access.Table
+	records       []Record
+	innerExecutor Executor          // INSERT ... SELECT の場合に追加するレコードを返す Executor (nil の場合は records を追加する)
+	checks        []CheckConstraint // 追加するレコードが満たすべき CHECK 制約
+	ignore        bool              // キーが重複する行・制約に違反する行を追加せずに読み飛ばすかどうか
+	replace       bool              // キーが重複する既存の行を削除してから追加するかどうか
+	onDuplicate   []SetColumn       // キーが重複する既存の行に適用する SET 句 (nil の場合は更新しない)
+	lastInsertId  int64             // 最初に採番した AUTO_INCREMENT の値 (採番していない場合は 0)
+	affectedRows  uint64            // 影響を受けた行数 (MySQL と同じ数え方)
 }
 
 func NewInsert(trxId handler.TrxId, table *access.Table, records []Record) *Insert {
@@ -20,23 +41,326 @@ func NewInsert(trxId handler.TrxId, table *access.Table, records []Record) *Inse
 	}
 }
 
+// NewInsertSelect は innerExecutor が返すレコードを追加する Insert を作成する (INSERT ... SELECT)
+//
+// innerExecutor はテーブルのカラム順に並んだレコードを返す必要がある
+func NewInsertSelect(trxId handler.TrxId, table *access.Table, innerExecutor Executor) *Insert {
+	return &Insert{
+		trxId:         trxId,
+		table:         table,
+		innerExecutor: innerExecutor,
+	}
+}
+
+// SetCheckConstraints は追加するレコードが満たすべき CHECK 制約を設定する
+func (ins *Insert) SetCheckConstraints(checks []CheckConstraint) {
+	ins.checks = checks
+}
+
+// SetIgnore はキーが重複する行と、CHECK 制約・FK 制約に違反する行を追加せずに読み飛ばすように設定する (INSERT IGNORE)
+func (ins *Insert) SetIgnore() {
+	ins.ignore = true
+}
+
+// SetReplace はキーが重複する既存の行を削除してから追加するように設定する (REPLACE)
+func (ins *Insert) SetReplace() {
+	ins.replace = true
+}
+
+// SetOnDuplicateKeyUpdate はキーが重複する既存の行に setColumns を適用するように設定する (INSERT ... ON DUPLICATE KEY UPDATE)
+//
+// 式は既存の行の後ろに追加しようとした行を連結したレコードに対して評価する (VALUES(col) は追加しようとした行のカラムを参照する)
+func (ins *Insert) SetOnDuplicateKeyUpdate(setColumns []SetColumn) {
+	ins.onDuplicate = setColumns
+}
+
 func (ins *Insert) Next() (Record, error) {
 	hdl := handler.Get()
 
-	// テーブルの FK 制約を確認
+	records, err := ins.sourceRecords()
+	if err != nil {
+		return nil, err
+	}
+
+	// テーブルの AUTO_INCREMENT カラム・CHECK 制約・FK 制約を確認
 	tableMeta, _ := hdl.Catalog.GetTableMetaByName(ins.table.Name)
 
-	for _, record := range ins.records {
-		// FK チェック: 参照先テーブルに値が存在するか確認 + Shared Lock 取得
-		if tableMeta != nil {
-			if err := checkFKOnInsert(hdl.BufferPool, ins.trxId, hdl.LockMgr, tableMeta, record); err != nil {
+	for _, record := range records {
+		if tableMeta == nil {
+			if err := ins.table.Insert(hdl.BufferPool, ins.trxId, hdl.LockMgr, record); err != nil {
+				return nil, err
+			}
+			ins.affectedRows++
+			continue
+		}
+
+		if err := ins.assignAutoIncrement(hdl, tableMeta, record); err != nil {
+			return nil, err
+		}
+
+		// INSERT ... SELECT の場合、SELECT の結果の値は実行時に決まるため、追加する前に NOT NULL 制約を確認する
+		if ins.innerExecutor != nil {
+			if err := checkNotNullColumns(tableMeta, record); err != nil {
 				return nil, err
 			}
 		}
 
-		if err := ins.table.Insert(hdl.BufferPool, ins.trxId, hdl.LockMgr, record); err != nil {
+		var err error
+		switch {
+		case ins.onDuplicate != nil:
+			err = ins.upsertRow(hdl, tableMeta, record)
+		case ins.replace:
+			err = ins.replaceRow(hdl, tableMeta, record)
+		case ins.ignore:
+			err = ins.insertRowIfNotDuplicate(hdl, tableMeta, record)
+		default:
+			err = ins.insertRow(hdl, tableMeta, record)
+		}
+		if err != nil && !(ins.ignore && isIgnorableError(err)) {
 			return nil, err
 		}
 	}
 	return nil, nil
 }
+
+func (ins *Insert) Close() {
+	if ins.innerExecutor != nil {
+		ins.innerExecutor.Close()
+	}
+}
+
+// LastInsertId は INSERT で最初に採番した AUTO_INCREMENT の値を返す (採番していない場合は 0)
+//
+// 複数行の INSERT の場合も、MySQL と同様に最初の行で採番した値を返す
+func (ins *Insert) LastInsertId() int64 {
+	return ins.lastInsertId
+}
+
+// AffectedRows は INSERT で影響を受けた行数を返す
+//
+// MySQL と同様に、以下のように数える
+//   - 追加した行: 1
+//   - ON DUPLICATE KEY UPDATE で更新した行: 2 (既存の行と同じ値に更新した場合は 0)
+//   - REPLACE で削除した行: 1 (追加した行とは別に数える)
+//   - INSERT IGNORE で読み飛ばした行: 0
+func (ins *Insert) AffectedRows() uint64 {
+	return ins.affectedRows
+}
+
+// sourceRecords は追加するレコードを返す
+//
+// INSERT ... SELECT の場合は、innerExecutor の結果を先にすべて取得する
+// (同じテーブルから SELECT する場合に、追加により Iterator が参照するページデータが破壊されるのを防ぐ)
+func (ins *Insert) sourceRecords() ([]Record, error) {
+	if ins.innerExecutor == nil {
+		return ins.records, nil
+	}
+	var records []Record
+	for {
+		record, err := ins.innerExecutor.Next()
+		if err != nil {
+			return nil, err
+		}
+		if record == nil {
+			break
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// insertRow は CHECK 制約・FK 制約を確認してから行を追加する
+func (ins *Insert) insertRow(hdl *handler.Handler, tableMeta *handler.TableMetadata, record Record) error {
+	// CHECK 制約: 採番した AUTO_INCREMENT の値を含むレコードで評価する
+	if err := checkConstraints(ins.checks, record); err != nil {
+		return err
+	}
+
+	// FK チェック: 参照先テーブルに値が存在するか確認 + Shared Lock 取得
+	if err := checkFKOnInsert(hdl.BufferPool, ins.trxId, hdl.LockMgr, tableMeta, record); err != nil {
+		return err
+	}
+
+	if err := ins.table.Insert(hdl.BufferPool, ins.trxId, hdl.LockMgr, record); err != nil {
+		return err
+	}
+	ins.affectedRows++
+	return nil
+}
+
+// insertRowIfNotDuplicate はキーが重複する行がない場合のみ行を追加する (INSERT IGNORE)
+func (ins *Insert) insertRowIfNotDuplicate(hdl *handler.Handler, tableMeta *handler.TableMetadata, record Record) error {
+	_, found, err := findDuplicateRow(hdl, ins.trxId, ins.table, record)
+	if err != nil || found {
+		return err
+	}
+	return ins.insertRow(hdl, tableMeta, record)
+}
+
+// upsertRow はキーが重複する行がある場合はその行を更新し、ない場合は行を追加する (INSERT ... ON DUPLICATE KEY UPDATE)
+//
+// 重複する行は排他ロックを取得してから最新の行を読むため、並行する更新と競合しない
+func (ins *Insert) upsertRow(hdl *handler.Handler, tableMeta *handler.TableMetadata, record Record) error {
+	current, found, err := findDuplicateRow(hdl, ins.trxId, ins.table, record)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return ins.insertRow(hdl, tableMeta, record)
+	}
+
+	updatedRecord, err := applySetColumns(tableMeta, ins.onDuplicate, ins.checks, current, record)
+	if err != nil {
+		return err
+	}
+	// 既存の行と同じ値に更新する場合は、行を変更しない (影響を受けた行数は 0)
+	if slices.EqualFunc(current, updatedRecord, bytes.Equal) {
+		return nil
+	}
+	if err := updateRow(hdl, ins.trxId, ins.table, current, updatedRecord, 0); err != nil {
+		return err
+	}
+	ins.affectedRows += 2
+	return nil
+}
+
+// replaceRow はキーが重複する行をすべて削除してから行を追加する (REPLACE)
+//
+// 削除は DELETE と同様に、参照元の行に ON DELETE の参照アクションを適用する
+func (ins *Insert) replaceRow(hdl *handler.Handler, tableMeta *handler.TableMetadata, record Record) error {
+	// 既存の行を削除する前に、追加する行が CHECK 制約を満たすことを確認する
+	if err := checkConstraints(ins.checks, record); err != nil {
+		return err
+	}
+
+	// プライマリキーと複数の UNIQUE インデックスで、別々の行と重複する場合がある
+	for {
+		current, found, err := findDuplicateRow(hdl, ins.trxId, ins.table, record)
+		if err != nil {
+			return err
+		}
+		if !found {
+			break
+		}
+		if err := deleteRow(hdl, ins.trxId, ins.table, current, 0); err != nil {
+			return err
+		}
+		ins.affectedRows++
+	}
+	return ins.insertRow(hdl, tableMeta, record)
+}
+
+// findDuplicateRow は record とプライマリキーまたは UNIQUE インデックスのキーが重複する行を探し、排他ロックを取得した最新の行を返す
+//
+// NULL を含む UNIQUE インデックスのキーはどの行とも重複しない
+func findDuplicateRow(hdl *handler.Handler, trxId handler.TrxId, table *access.Table, record Record) (Record, bool, error) {
+	// プライマリキー
+	_, _, err := btree.NewBTree(table.MetaPageId).FindByKey(hdl.BufferPool, table.EncodeKey(record))
+	if err != nil && !errors.Is(err, btree.ErrKeyNotFound) {
+		return nil, false, err
+	}
+	if err == nil {
+		current, ok, err := lockLatestRow(hdl, trxId, table, record)
+		if err != nil || ok {
+			return current, ok, err
+		}
+	}
+
+	// UNIQUE インデックス
+	for _, si := range table.SecondaryIndexes {
+		if !si.Unique {
+			continue
+		}
+		values := getFKValues(record, si.ColIdxs)
+		if hasNullValue(values) {
+			continue
+		}
+		pk, ok, err := findUniqueKeyOwner(hdl, si, values)
+		if err != nil {
+			return nil, false, err
+		}
+		if !ok {
+			continue
+		}
+		current, ok, err := lockLatestRow(hdl, trxId, table, pk)
+		if err != nil {
+			return nil, false, err
+		}
+		// ロック取得までに他のトランザクションが削除・更新した場合は重複しない
+		if ok && equalFKValues(getFKValues(current, si.ColIdxs), values) {
+			return current, true, nil
+		}
+	}
+	return nil, false, nil
+}
+
+// findUniqueKeyOwner は UNIQUE インデックスで values をキーとする active な行を探し、そのプライマリキーのカラム値を返す
+func findUniqueKeyOwner(hdl *handler.Handler, si *access.SecondaryIndex, values [][]byte) (Record, bool, error) {
+	var encodedSecKey []byte
+	encode.EncodeNullable(values, &encodedSecKey)
+
+	iter, err := btree.NewBTree(si.MetaPageId).Search(hdl.BufferPool, btree.SearchModeKey{Key: encodedSecKey})
+	if err != nil {
+		return nil, false, err
+	}
+	for {
+		record, ok := iter.Get()
+		if !ok || !hasSecondaryKeyPrefix(record.KeyBytes(), values) {
+			return nil, false, nil
+		}
+		if record.HeaderBytes()[0] != 1 {
+			_, encodedPK := encode.DecodeNullableFirstN(record.KeyBytes(), len(si.ColIdxs))
+			var pk [][]byte
+			encode.Decode(encodedPK, &pk)
+			return pk, true, nil
+		}
+		if err := iter.Advance(hdl.BufferPool); err != nil {
+			return nil, false, err
+		}
+	}
+}
+
+// isIgnorableError は INSERT IGNORE で行を読み飛ばすエラーかどうかを返す
+//
+// MySQL と同様に、CHECK 制約違反・FK 制約違反の行を読み飛ばす (キーの重複は追加する前に確認する)
+// いずれも行を変更する前に検出するため、読み飛ばしても途中まで変更した行は残らない
+func isIgnorableError(err error) bool {
+	var checkErr *CheckConstraintViolationError
+	return errors.As(err, &checkErr) || errors.Is(err, errFKNoParentRow) || errors.Is(err, errFKDeleteRestrict)
+}
+
+// checkNotNullColumns は NOT NULL のカラムに NULL が含まれないことを確認する
+func checkNotNullColumns(tableMeta *handler.TableMetadata, record Record) error {
+	for pos, value := range record {
+		if value == nil && isNotNullColumn(tableMeta, uint16(pos)) {
+			return fmt.Errorf("column '%s' cannot be null", tableMeta.GetSortedCols()[pos].Name)
+		}
+	}
+	return nil
+}
+
+// assignAutoIncrement はレコードの AUTO_INCREMENT カラムの値を決める
+//
+// 値が NULL または 0 の場合は採番した値を設定し、それ以外の場合は指定された値を以降の採番に反映する
+func (ins *Insert) assignAutoIncrement(hdl *handler.Handler, tableMeta *handler.TableMetadata, record Record) error {
+	colMeta, ok := tableMeta.GetAutoIncrementCol()
+	if !ok {
+		return nil
+	}
+
+	value := record[colMeta.Pos]
+	if value != nil && encode.DecodeInt64(value) != 0 {
+		hdl.UpdateAutoIncrement(tableMeta.Name, encode.DecodeInt64(value))
+		return nil
+	}
+
+	id, err := hdl.NextAutoIncrement(tableMeta.Name, colMeta)
+	if err != nil {
+		return err
+	}
+	record[colMeta.Pos] = encode.EncodeInt64(id)
+	if ins.lastInsertId == 0 {
+		ins.lastInsertId = id
+	}
+	return nil
+}
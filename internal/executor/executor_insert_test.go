@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/ren-yamanashi/minesql/internal/storage/access"
+	"github.com/ren-yamanashi/minesql/internal/storage/encode"
 	"github.com/ren-yamanashi/minesql/internal/storage/handler"
 	"github.com/ren-yamanashi/minesql/internal/storage/lock"
 	"github.com/stretchr/testify/assert"
@@ -35,7 +36,7 @@ func TestInsert_Next(t *testing.T) {
 
 		tableName := "users"
 		createTableForTest(t, tableName, []handler.CreateIndexParam{
-			{Name: "name", ColName: "name", ColIdx: 1, Unique: true},
+			{Name: "name", ColNames: []string{"name"}, ColIdxs: []uint16{1}, Unique: true},
 		}, []handler.CreateColumnParam{
 			{Name: "id", Type: handler.ColumnTypeString},
 			{Name: "name", Type: handler.ColumnTypeString},
@@ -122,12 +123,12 @@ func TestInsert_Next(t *testing.T) {
 		assert.NoError(t, err)
 
 		childCt := NewCreateTable("orders", 1,
-			[]handler.CreateIndexParam{{Name: "idx_user_id", ColName: "user_id", ColIdx: 1, Unique: false}},
+			[]handler.CreateIndexParam{{Name: "idx_user_id", ColNames: []string{"user_id"}, ColIdxs: []uint16{1}, Unique: false}},
 			[]handler.CreateColumnParam{
 				{Name: "id", Type: handler.ColumnTypeString},
 				{Name: "user_id", Type: handler.ColumnTypeString},
 			},
-			[]handler.CreateConstraintParam{{ConstraintName: "fk_user", ColName: "user_id", RefTableName: "users", RefColName: "id"}},
+			[]handler.CreateConstraintParam{{ConstraintName: "fk_user", ColNames: []string{"user_id"}, RefTableName: "users", RefColNames: []string{"id"}}},
 		)
 		_, err = childCt.Next()
 		assert.NoError(t, err)
@@ -145,6 +146,315 @@ func TestInsert_Next(t *testing.T) {
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "foreign key constraint fails")
 	})
+
+	t.Run("AUTO_INCREMENT カラムが NULL または 0 の場合、採番した値を設定して挿入する", func(t *testing.T) {
+		// GIVEN
+		initStorageManagerForTest(t)
+		defer handler.Reset()
+		createAutoIncrementTableForTest(t)
+		hdl := handler.Get()
+		tbl, err := hdl.GetTable("items")
+		assert.NoError(t, err)
+
+		// WHEN
+		ins := NewInsert(hdl.BeginTrx(), tbl, []Record{
+			{nil, []byte("a")},
+			{encode.EncodeInt64(0), []byte("b")},
+		})
+		_, err = ins.Next()
+
+		// THEN
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), ins.LastInsertId())
+		res, err := fetchAll(testTableScan(tbl, access.RecordSearchModeStart{}, func(record Record) bool { return true }))
+		assert.NoError(t, err)
+		assert.Equal(t, []Record{
+			{encode.EncodeInt64(1), []byte("a")},
+			{encode.EncodeInt64(2), []byte("b")},
+		}, res)
+	})
+
+	t.Run("AUTO_INCREMENT カラムに値を指定した場合、以降はその次の値から採番する", func(t *testing.T) {
+		// GIVEN
+		initStorageManagerForTest(t)
+		defer handler.Reset()
+		createAutoIncrementTableForTest(t)
+		hdl := handler.Get()
+		tbl, err := hdl.GetTable("items")
+		assert.NoError(t, err)
+
+		// WHEN
+		ins := NewInsert(hdl.BeginTrx(), tbl, []Record{
+			{encode.EncodeInt64(10), []byte("a")},
+			{nil, []byte("b")},
+		})
+		_, err = ins.Next()
+
+		// THEN
+		assert.NoError(t, err)
+		assert.Equal(t, int64(11), ins.LastInsertId())
+		res, err := fetchAll(testTableScan(tbl, access.RecordSearchModeStart{}, func(record Record) bool { return true }))
+		assert.NoError(t, err)
+		assert.Equal(t, []Record{
+			{encode.EncodeInt64(10), []byte("a")},
+			{encode.EncodeInt64(11), []byte("b")},
+		}, res)
+	})
+
+	t.Run("AUTO_INCREMENT の値を採番しない場合、LastInsertId は 0 を返す", func(t *testing.T) {
+		// GIVEN
+		initStorageManagerForTest(t)
+		defer handler.Reset()
+		createAutoIncrementTableForTest(t)
+		hdl := handler.Get()
+		tbl, err := hdl.GetTable("items")
+		assert.NoError(t, err)
+
+		// WHEN
+		ins := NewInsert(hdl.BeginTrx(), tbl, []Record{{encode.EncodeInt64(5), []byte("a")}})
+		_, err = ins.Next()
+
+		// THEN
+		assert.NoError(t, err)
+		assert.Equal(t, int64(0), ins.LastInsertId())
+	})
+
+	t.Run("CHECK 制約を満たさないレコードがある場合、エラーを返す", func(t *testing.T) {
+		// GIVEN
+		initStorageManagerForTest(t)
+		defer handler.Reset()
+		createAutoIncrementTableForTest(t)
+		hdl := handler.Get()
+		tbl, err := hdl.GetTable("items")
+		assert.NoError(t, err)
+
+		// WHEN: id < 100 の CHECK 制約に対して id = 100 を挿入
+		ins := NewInsert(hdl.BeginTrx(), tbl, []Record{{encode.EncodeInt64(100), []byte("a")}})
+		ins.SetCheckConstraints([]CheckConstraint{
+			{Name: "items_chk_1", Expr: NewCompare("<", NewColumnRef(0), intConst(100))},
+		})
+		_, err = ins.Next()
+
+		// THEN
+		var checkErr *CheckConstraintViolationError
+		assert.ErrorAs(t, err, &checkErr)
+		assert.Equal(t, "items_chk_1", checkErr.Name)
+		res, err := fetchAll(testTableScan(tbl, access.RecordSearchModeStart{}, func(record Record) bool { return true }))
+		assert.NoError(t, err)
+		assert.Empty(t, res)
+	})
+
+	t.Run("CHECK 制約は採番した AUTO_INCREMENT の値を含むレコードで評価する", func(t *testing.T) {
+		// GIVEN
+		initStorageManagerForTest(t)
+		defer handler.Reset()
+		createAutoIncrementTableForTest(t)
+		hdl := handler.Get()
+		tbl, err := hdl.GetTable("items")
+		assert.NoError(t, err)
+
+		// WHEN: id > 0 の CHECK 制約に対して id を省略 (NULL) して挿入
+		ins := NewInsert(hdl.BeginTrx(), tbl, []Record{{nil, []byte("a")}})
+		ins.SetCheckConstraints([]CheckConstraint{
+			{Name: "items_chk_1", Expr: NewCompare(">", NewColumnRef(0), intConst(0))},
+		})
+		_, err = ins.Next()
+
+		// THEN
+		assert.NoError(t, err)
+		res, err := fetchAll(testTableScan(tbl, access.RecordSearchModeStart{}, func(record Record) bool { return true }))
+		assert.NoError(t, err)
+		assert.Equal(t, []Record{{encode.EncodeInt64(1), []byte("a")}}, res)
+	})
+}
+
+func TestInsert_DuplicateKey(t *testing.T) {
+	t.Run("NewInsertSelect は InnerExecutor の結果を追加する", func(t *testing.T) {
+		// GIVEN
+		initStorageManagerForTest(t)
+		defer handler.Reset()
+		tbl := createDuplicateKeyTableForTest(t)
+		inner := &mockExecutor{records: []Record{
+			{[]byte("3"), []byte("c@example.com"), []byte("Carol")},
+			{[]byte("4"), []byte("d@example.com"), []byte("Dave")},
+		}}
+
+		// WHEN
+		ins := NewInsertSelect(handler.Get().BeginTrx(), tbl, inner)
+		_, err := ins.Next()
+
+		// THEN
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(2), ins.AffectedRows())
+		assert.Equal(t, []string{"1:Alice", "2:Bob", "3:Carol", "4:Dave"}, scanDuplicateKeyTableForTest(t, tbl))
+	})
+
+	t.Run("SetIgnore を設定した場合、プライマリキーまたは UNIQUE インデックスのキーが重複する行を読み飛ばす", func(t *testing.T) {
+		// GIVEN
+		initStorageManagerForTest(t)
+		defer handler.Reset()
+		tbl := createDuplicateKeyTableForTest(t)
+
+		// WHEN
+		ins := NewInsert(handler.Get().BeginTrx(), tbl, []Record{
+			{[]byte("1"), []byte("x@example.com"), []byte("X")},
+			{[]byte("3"), []byte("b@example.com"), []byte("Y")},
+			{[]byte("4"), []byte("d@example.com"), []byte("Dave")},
+		})
+		ins.SetIgnore()
+		_, err := ins.Next()
+
+		// THEN
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(1), ins.AffectedRows())
+		assert.Equal(t, []string{"1:Alice", "2:Bob", "4:Dave"}, scanDuplicateKeyTableForTest(t, tbl))
+	})
+
+	t.Run("SetIgnore を設定した場合、CHECK 制約を満たさない行を読み飛ばす", func(t *testing.T) {
+		// GIVEN
+		initStorageManagerForTest(t)
+		defer handler.Reset()
+		tbl := createDuplicateKeyTableForTest(t)
+
+		// WHEN: name <> 'X' の CHECK 制約
+		ins := NewInsert(handler.Get().BeginTrx(), tbl, []Record{
+			{[]byte("3"), []byte("c@example.com"), []byte("X")},
+			{[]byte("4"), []byte("d@example.com"), []byte("Dave")},
+		})
+		ins.SetCheckConstraints([]CheckConstraint{
+			{Name: "users_chk_1", Expr: NewCompare("!=", NewColumnRef(2), NewConstant([]byte("X")))},
+		})
+		ins.SetIgnore()
+		_, err := ins.Next()
+
+		// THEN
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(1), ins.AffectedRows())
+		assert.Equal(t, []string{"1:Alice", "2:Bob", "4:Dave"}, scanDuplicateKeyTableForTest(t, tbl))
+	})
+
+	t.Run("SetOnDuplicateKeyUpdate を設定した場合、キーが重複する既存の行を更新する", func(t *testing.T) {
+		// GIVEN
+		initStorageManagerForTest(t)
+		defer handler.Reset()
+		tbl := createDuplicateKeyTableForTest(t)
+
+		// WHEN: name = VALUES(name) (追加しようとした行の name は既存の行のカラムの後ろの位置 3 + 2)
+		ins := NewInsert(handler.Get().BeginTrx(), tbl, []Record{
+			{[]byte("9"), []byte("b@example.com"), []byte("Bobby")},
+			{[]byte("3"), []byte("c@example.com"), []byte("Carol")},
+		})
+		ins.SetOnDuplicateKeyUpdate([]SetColumn{{Pos: 2, Expr: NewColumnRef(5)}})
+		_, err := ins.Next()
+
+		// THEN: 更新した行は 2、追加した行は 1 として数える
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(3), ins.AffectedRows())
+		assert.Equal(t, []string{"1:Alice", "2:Bobby", "3:Carol"}, scanDuplicateKeyTableForTest(t, tbl))
+	})
+
+	t.Run("SetOnDuplicateKeyUpdate で既存の行と同じ値に更新する場合、影響を受けた行数に数えない", func(t *testing.T) {
+		// GIVEN
+		initStorageManagerForTest(t)
+		defer handler.Reset()
+		tbl := createDuplicateKeyTableForTest(t)
+
+		// WHEN
+		ins := NewInsert(handler.Get().BeginTrx(), tbl, []Record{
+			{[]byte("1"), []byte("a@example.com"), []byte("Alice")},
+		})
+		ins.SetOnDuplicateKeyUpdate([]SetColumn{{Pos: 2, Expr: NewColumnRef(5)}})
+		_, err := ins.Next()
+
+		// THEN
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(0), ins.AffectedRows())
+	})
+
+	t.Run("SetReplace を設定した場合、キーが重複する既存の行をすべて削除してから追加する", func(t *testing.T) {
+		// GIVEN
+		initStorageManagerForTest(t)
+		defer handler.Reset()
+		tbl := createDuplicateKeyTableForTest(t)
+
+		// WHEN: id が 1 の行と、email が 2 の行と重複する
+		ins := NewInsert(handler.Get().BeginTrx(), tbl, []Record{
+			{[]byte("1"), []byte("b@example.com"), []byte("Carol")},
+		})
+		ins.SetReplace()
+		_, err := ins.Next()
+
+		// THEN: 削除した 2 行と追加した 1 行を数える
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(3), ins.AffectedRows())
+		assert.Equal(t, []string{"1:Carol"}, scanDuplicateKeyTableForTest(t, tbl))
+	})
+
+	t.Run("SetOnDuplicateKeyUpdate は重複する行の排他ロックを取得する", func(t *testing.T) {
+		// GIVEN
+		initLockTestHandler(t)
+		defer handler.Reset()
+		hdl := handler.Get()
+		tbl := createLockTestTable(t)
+		setup := hdl.BeginTrx()
+		_, err := NewInsert(setup, tbl, []Record{{[]byte("a"), []byte("Alice")}}).Next()
+		assert.NoError(t, err)
+		assert.NoError(t, hdl.CommitTrx(setup))
+
+		// trx1 が UPDATE で行の排他ロックを取得
+		trx1 := hdl.BeginTrx()
+		upd := NewUpdate(trx1, tbl, []SetColumn{
+			{Pos: 1, Value: []byte("Updated")},
+		}, testTableScan(tbl,
+			access.RecordSearchModeStart{},
+			func(record Record) bool { return string(record[0]) == "a" },
+		))
+		_, err = upd.Next()
+		assert.NoError(t, err)
+
+		// WHEN: trx2 が同じキーの行を INSERT ... ON DUPLICATE KEY UPDATE
+		trx2 := hdl.BeginTrx()
+		ins := NewInsert(trx2, tbl, []Record{{[]byte("a"), []byte("Bob")}})
+		ins.SetOnDuplicateKeyUpdate([]SetColumn{{Pos: 1, Expr: NewColumnRef(3)}})
+		_, err = ins.Next()
+
+		// THEN
+		assert.ErrorIs(t, err, lock.ErrTimeout)
+		assert.NoError(t, hdl.CommitTrx(trx1))
+	})
+}
+
+// createDuplicateKeyTableForTest は users (id PK, email UNIQUE, name) テーブルを作成し、2 行を追加する
+func createDuplicateKeyTableForTest(t *testing.T) *access.Table {
+	createTableForTest(t, "users", []handler.CreateIndexParam{
+		{Name: "uk_email", ColNames: []string{"email"}, ColIdxs: []uint16{1}, Unique: true},
+	}, []handler.CreateColumnParam{
+		{Name: "id", Type: handler.ColumnTypeString},
+		{Name: "email", Type: handler.ColumnTypeString},
+		{Name: "name", Type: handler.ColumnTypeString},
+	})
+	hdl := handler.Get()
+	tbl, err := hdl.GetTable("users")
+	assert.NoError(t, err)
+	trxId := hdl.BeginTrx()
+	_, err = NewInsert(trxId, tbl, []Record{
+		{[]byte("1"), []byte("a@example.com"), []byte("Alice")},
+		{[]byte("2"), []byte("b@example.com"), []byte("Bob")},
+	}).Next()
+	assert.NoError(t, err)
+	assert.NoError(t, hdl.CommitTrx(trxId))
+	return tbl
+}
+
+// scanDuplicateKeyTableForTest は users テーブルの最新の行を "id:name" の形式で返す
+func scanDuplicateKeyTableForTest(t *testing.T, tbl *access.Table) []string {
+	res, err := fetchAll(testTableScan(tbl, access.RecordSearchModeStart{}, func(record Record) bool { return true }))
+	assert.NoError(t, err)
+	var rows []string
+	for _, record := range res {
+		rows = append(rows, string(record[0])+":"+string(record[2]))
+	}
+	return rows
 }
 
 func initStorageManagerForTest(t *testing.T) {
@@ -155,6 +465,14 @@ func initStorageManagerForTest(t *testing.T) {
 	handler.Init()
 }
 
+// createAutoIncrementTableForTest は items (id INT AUTO_INCREMENT PK, name) テーブルを作成する
+func createAutoIncrementTableForTest(t *testing.T) {
+	createTableForTest(t, "items", nil, []handler.CreateColumnParam{
+		{Name: "id", Type: handler.ColumnTypeInt, NotNull: true, AutoIncrement: true},
+		{Name: "name", Type: handler.ColumnTypeString},
+	})
+}
+
 func createTableForTest(t *testing.T, tableName string, indexes []handler.CreateIndexParam, columns []handler.CreateColumnParam) {
 	createTable := NewCreateTable(tableName, 1, indexes, columns, nil)
 	_, err := createTable.Next()
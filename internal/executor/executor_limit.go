@@ -0,0 +1,67 @@
+package executor
+
+import "fmt"
+
+// Limit は InnerExecutor の結果から先頭 offset 件を読み飛ばし、最大 count 件を返す
+//
+// count 件を返した後は InnerExecutor からレコードを取得せず、その時点で InnerExecutor を閉じる (それ以降の走査を打ち切り、一時ファイルなどをすぐに解放する)
+type Limit struct {
+	InnerExecutor Executor
+	count         uint64
+	offset        uint64
+	skipped       uint64 // 読み飛ばした件数
+	returned      uint64 // 返した件数
+}
+
+func NewLimit(innerExecutor Executor, count, offset uint64) *Limit {
+	return &Limit{
+		InnerExecutor: innerExecutor,
+		count:         count,
+		offset:        offset,
+	}
+}
+
+func (l *Limit) Next() (Record, error) {
+	// count 件返したら終了
+	if l.returned >= l.count {
+		l.InnerExecutor.Close()
+		return nil, nil
+	}
+
+	// 先頭 offset 件を読み飛ばす
+	for l.skipped < l.offset {
+		record, err := l.InnerExecutor.Next()
+		if err != nil {
+			return nil, err
+		}
+		if record == nil {
+			return nil, nil
+		}
+		l.skipped++
+	}
+
+	record, err := l.InnerExecutor.Next()
+	if err != nil {
+		return nil, err
+	}
+	if record == nil {
+		return nil, nil
+	}
+	l.returned++
+	if l.returned >= l.count {
+		l.InnerExecutor.Close()
+	}
+	return record, nil
+}
+
+func (l *Limit) Close() {
+	l.InnerExecutor.Close()
+}
+
+func (l *Limit) Explain() ExplainInfo {
+	detail := fmt.Sprintf("LIMIT %d", l.count)
+	if l.offset > 0 {
+		detail += fmt.Sprintf(" OFFSET %d", l.offset)
+	}
+	return ExplainInfo{Name: "Limit", Detail: detail, Children: []*Executor{&l.InnerExecutor}}
+}
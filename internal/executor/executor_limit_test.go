@@ -0,0 +1,142 @@
+package executor
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingExecutor は Next が呼ばれた回数を数える Executor
+type countingExecutor struct {
+	mockExecutor
+	calls int
+}
+
+func (c *countingExecutor) Next() (Record, error) {
+	c.calls++
+	return c.mockExecutor.Next()
+}
+
+func TestLimit(t *testing.T) {
+	newInner := func() *countingExecutor {
+		var records []Record
+		for _, id := range []string{"1", "2", "3", "4", "5"} {
+			records = append(records, Record{[]byte(id)})
+		}
+		return &countingExecutor{mockExecutor: mockExecutor{records: records}}
+	}
+
+	t.Run("先頭から count 件を返し、それ以降は InnerExecutor から取得しない", func(t *testing.T) {
+		// GIVEN
+		inner := newInner()
+		limit := NewLimit(inner, 2, 0)
+
+		// WHEN
+		results := collectAll(t, limit)
+
+		// THEN
+		assert.Equal(t, []string{"1", "2"}, columnValues(results, 0))
+		assert.Equal(t, 2, inner.calls)
+	})
+
+	t.Run("offset 件を読み飛ばしてから count 件を返す", func(t *testing.T) {
+		// GIVEN
+		inner := newInner()
+		limit := NewLimit(inner, 2, 3)
+
+		// WHEN
+		results := collectAll(t, limit)
+
+		// THEN
+		assert.Equal(t, []string{"4", "5"}, columnValues(results, 0))
+	})
+
+	t.Run("レコード数が offset + count に満たない場合は残りをすべて返す", func(t *testing.T) {
+		// GIVEN
+		inner := newInner()
+		limit := NewLimit(inner, 10, 4)
+
+		// WHEN
+		results := collectAll(t, limit)
+
+		// THEN
+		assert.Equal(t, []string{"5"}, columnValues(results, 0))
+	})
+
+	t.Run("offset がレコード数以上の場合は何も返さない", func(t *testing.T) {
+		// GIVEN
+		inner := newInner()
+		limit := NewLimit(inner, 2, 5)
+
+		// WHEN
+		results := collectAll(t, limit)
+
+		// THEN
+		assert.Empty(t, results)
+	})
+
+	t.Run("count が 0 の場合は InnerExecutor から取得しない", func(t *testing.T) {
+		// GIVEN
+		inner := newInner()
+		limit := NewLimit(inner, 0, 0)
+
+		// WHEN
+		results := collectAll(t, limit)
+
+		// THEN
+		assert.Empty(t, results)
+		assert.Equal(t, 0, inner.calls)
+	})
+
+	t.Run("count 件返した時点で InnerExecutor を閉じる", func(t *testing.T) {
+		// GIVEN
+		inner := newInner()
+		limit := NewLimit(inner, 2, 1)
+
+		// WHEN
+		_, err := limit.Next()
+		require.NoError(t, err)
+		closedBefore := inner.closed
+		_, err = limit.Next()
+		require.NoError(t, err)
+
+		// THEN
+		assert.False(t, closedBefore)
+		assert.True(t, inner.closed)
+	})
+
+	t.Run("ランを書き出したソートに対して count 件読み取ると、Close を呼ぶ前に一時ファイルが削除される", func(t *testing.T) {
+		// GIVEN
+		var records []Record
+		for i := range 10 {
+			records = append(records, Record{[]byte(fmt.Sprintf("%d", i))})
+		}
+		tmpDir := t.TempDir()
+		sort := NewSortWithParams(SortParams{
+			InnerExecutor: &mockExecutor{records: records},
+			Keys:          []SortKey{{Pos: 0}},
+			BufferSize:    100,
+			TmpDir:        tmpDir,
+		})
+		limit := NewLimit(sort, 2, 1)
+
+		// WHEN
+		first, err := limit.Next()
+		require.NoError(t, err)
+		during, err := os.ReadDir(tmpDir)
+		require.NoError(t, err)
+		second, err := limit.Next()
+		require.NoError(t, err)
+
+		// THEN
+		assert.NotEmpty(t, during)
+		assert.Equal(t, Record{[]byte("1")}, first)
+		assert.Equal(t, Record{[]byte("2")}, second)
+		entries, err := os.ReadDir(tmpDir)
+		require.NoError(t, err)
+		assert.Empty(t, entries)
+	})
+}
@@ -3,11 +3,20 @@ package executor
 // NestedLoopJoin は Nested Loop Join を実行する
 //
 // 外側 (左) の各行に対して、buildRightExec で内側 (右) の Executor を生成し、左右のレコードを結合して返す
+//
+// 外部結合 (LEFT OUTER JOIN) の場合、内側に一致する行がない外側の行は、内側のカラムを NULL で補完して返す
+// 外部結合の condition は NULL で補完する前に結合レコードに対して評価し、満たさない内側の行は一致しなかったものとして扱う
 type NestedLoopJoin struct {
 	leftExec       Executor
 	buildRightExec func(leftRecord Record) (Executor, error)
 	currentLeft    Record
 	currentRight   Executor
+	outer          bool       // 外部結合かどうか
+	rightColCount  int        // 内側のレコードのカラム数 (NULL で補完するカラム数)
+	condition      Expression // 外部結合の結合条件のうち、内側の Executor で絞り込まない条件 (nil なら条件なし)
+	matched        bool       // 現在の外側の行に一致する内側の行があったかどうか
+	innerPlan      Executor   // EXPLAIN で内側として表示する Executor
+	explainNote               // プランナーが選んだアクセス方法と見積もり (EXPLAIN で表示する)
 }
 
 func NewNestedLoopJoin(
@@ -20,6 +29,25 @@ func NewNestedLoopJoin(
 	}
 }
 
+// NewNestedLoopOuterJoin は外側 (左) の行をすべて残す LEFT OUTER JOIN を実行する NestedLoopJoin を作成する
+//
+// rightColCount は内側のレコードのカラム数 (一致する行がない場合に NULL で補完するカラム数)
+// condition は結合レコードに対して評価する結合条件 (nil なら内側の Executor が返す行をすべて一致した行とする)
+func NewNestedLoopOuterJoin(
+	leftExec Executor,
+	buildRightExec func(leftRecord Record) (Executor, error),
+	rightColCount int,
+	condition Expression,
+) *NestedLoopJoin {
+	return &NestedLoopJoin{
+		leftExec:       leftExec,
+		buildRightExec: buildRightExec,
+		outer:          true,
+		rightColCount:  rightColCount,
+		condition:      condition,
+	}
+}
+
 func (nlj *NestedLoopJoin) Next() (Record, error) {
 	for {
 		// 現在の右 Executor からレコード取得を試みる (初回は nil なのでスキップ)
@@ -29,7 +57,25 @@ func (nlj *NestedLoopJoin) Next() (Record, error) {
 				return nil, err
 			}
 			if rightRecord != nil {
-				return nlj.concatenate(nlj.currentLeft, rightRecord), nil
+				record := nlj.concatenate(nlj.currentLeft, rightRecord)
+				if nlj.condition != nil {
+					value, err := nlj.condition.Eval(record)
+					if err != nil {
+						return nil, err
+					}
+					if !isTrue(value) {
+						continue
+					}
+				}
+				nlj.matched = true
+				return record, nil
+			}
+
+			// 外部結合で一致する行がなかった場合、内側のカラムを NULL で補完した行を返す
+			nlj.currentRight.Close()
+			nlj.currentRight = nil
+			if nlj.outer && !nlj.matched {
+				return nlj.concatenate(nlj.currentLeft, make(Record, nlj.rightColCount)), nil
 			}
 		}
 
@@ -44,6 +90,7 @@ func (nlj *NestedLoopJoin) Next() (Record, error) {
 
 		// 新しい右 Executor を生成
 		nlj.currentLeft = leftRecord
+		nlj.matched = false
 		nlj.currentRight, err = nlj.buildRightExec(leftRecord)
 		if err != nil {
 			return nil, err
@@ -51,6 +98,13 @@ func (nlj *NestedLoopJoin) Next() (Record, error) {
 	}
 }
 
+func (nlj *NestedLoopJoin) Close() {
+	nlj.leftExec.Close()
+	if nlj.currentRight != nil {
+		nlj.currentRight.Close()
+	}
+}
+
 // concatenate は左右のレコードを結合する
 func (nlj *NestedLoopJoin) concatenate(left, right Record) Record {
 	result := make(Record, len(left)+len(right))
@@ -58,3 +112,24 @@ func (nlj *NestedLoopJoin) concatenate(left, right Record) Record {
 	copy(result[len(left):], right)
 	return result
 }
+
+// SetInnerPlan は EXPLAIN で内側として表示する Executor を設定する
+//
+// 内側の Executor は外側の行ごとに生成するため、プランナーが代表として生成したものを保持する (実行には使わない)
+func (nlj *NestedLoopJoin) SetInnerPlan(exec Executor) {
+	nlj.innerPlan = exec
+}
+
+func (nlj *NestedLoopJoin) Explain() ExplainInfo {
+	detail := "INNER"
+	if nlj.outer {
+		detail = "LEFT OUTER"
+	}
+	return ExplainInfo{
+		Name:      "NestedLoopJoin",
+		Detail:    detail,
+		Children:  []*Executor{&nlj.leftExec},
+		Inner:     &nlj.buildRightExec,
+		InnerPlan: nlj.innerPlan,
+	}
+}
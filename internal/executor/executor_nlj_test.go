@@ -11,6 +11,7 @@ import (
 type nljMockExecutor struct {
 	records []Record
 	pos     int
+	closed  bool // Close が呼ばれたか
 }
 
 func newMockExecutor(records []Record) *nljMockExecutor {
@@ -26,6 +27,10 @@ func (m *nljMockExecutor) Next() (Record, error) {
 	return r, nil
 }
 
+func (m *nljMockExecutor) Close() {
+	m.closed = true
+}
+
 func TestNestedLoopJoin(t *testing.T) {
 	t.Run("左 3 行 × 右 2 行で 6 行出力される", func(t *testing.T) {
 		// GIVEN
@@ -179,3 +184,94 @@ func TestNestedLoopJoin(t *testing.T) {
 		assert.Equal(t, "match_3", string(results[1][1]))
 	})
 }
+
+func TestNestedLoopOuterJoin(t *testing.T) {
+	t.Run("右に一致する行がない左行は、右のカラムを NULL で補完して出力される", func(t *testing.T) {
+		// GIVEN: 左 3 行のうち id=2 のみ右と一致しない
+		leftRecords := []Record{
+			{[]byte("1")},
+			{[]byte("2")},
+			{[]byte("3")},
+		}
+		buildRight := func(leftRecord Record) (Executor, error) {
+			id := string(leftRecord[0])
+			if id == "2" {
+				return newMockExecutor(nil), nil
+			}
+			return newMockExecutor([]Record{{[]byte("match_" + id), []byte(id)}}), nil
+		}
+		nlj := NewNestedLoopOuterJoin(newMockExecutor(leftRecords), buildRight, 2, nil)
+
+		// WHEN
+		var results []Record
+		for {
+			r, err := nlj.Next()
+			require.NoError(t, err)
+			if r == nil {
+				break
+			}
+			results = append(results, r)
+		}
+
+		// THEN: 3 行とも出力され、id=2 の右のカラムは NULL
+		assert.Equal(t, []Record{
+			{[]byte("1"), []byte("match_1"), []byte("1")},
+			{[]byte("2"), nil, nil},
+			{[]byte("3"), []byte("match_3"), []byte("3")},
+		}, results)
+	})
+
+	t.Run("右に複数行が一致する場合は、NULL で補完した行を出力しない", func(t *testing.T) {
+		// GIVEN
+		buildRight := func(leftRecord Record) (Executor, error) {
+			return newMockExecutor([]Record{{[]byte("x1")}, {[]byte("x2")}}), nil
+		}
+		nlj := NewNestedLoopOuterJoin(newMockExecutor([]Record{{[]byte("a")}}), buildRight, 1, nil)
+
+		// WHEN
+		var results []Record
+		for {
+			r, err := nlj.Next()
+			require.NoError(t, err)
+			if r == nil {
+				break
+			}
+			results = append(results, r)
+		}
+
+		// THEN
+		assert.Equal(t, []Record{
+			{[]byte("a"), []byte("x1")},
+			{[]byte("a"), []byte("x2")},
+		}, results)
+	})
+
+	t.Run("結合条件を満たさない右の行は一致しなかったものとして扱い、一致する行がなければ NULL で補完する", func(t *testing.T) {
+		// GIVEN: 右の 2 カラム目が "ok" の行のみ結合条件を満たす
+		buildRight := func(leftRecord Record) (Executor, error) {
+			if string(leftRecord[0]) == "a" {
+				return newMockExecutor([]Record{{[]byte("x1"), []byte("ng")}, {[]byte("x2"), []byte("ok")}}), nil
+			}
+			return newMockExecutor([]Record{{[]byte("y1"), []byte("ng")}}), nil
+		}
+		condition := ConditionFunc(func(r Record) bool { return string(r[2]) == "ok" })
+		nlj := NewNestedLoopOuterJoin(newMockExecutor([]Record{{[]byte("a")}, {[]byte("b")}}), buildRight, 2, condition)
+
+		// WHEN
+		var results []Record
+		for {
+			r, err := nlj.Next()
+			require.NoError(t, err)
+			if r == nil {
+				break
+			}
+			results = append(results, r)
+		}
+
+		// THEN
+		assert.Equal(t, []Record{
+			{[]byte("a"), []byte("x2"), []byte("ok")},
+			{[]byte("b"), nil, nil},
+		}, results)
+	})
+}
@@ -1,9 +1,12 @@
 package executor
 
 // Project は InnerExecutor の結果から特定の列だけを返す
+//
+// Exprs が設定されている場合は、列の代わりに各式の評価結果を返す (e.g. `SELECT price * qty`)
 type Project struct {
 	InnerExecutor Executor
-	ColPos        []uint16 // 返す列の位置 (インデックス)
+	ColPos        []uint16     // 返す列の位置 (インデックス)
+	Exprs         []Expression // 返す値を計算する式 (nil なら ColPos の列を返す)
 }
 
 func NewProject(innerExecutor Executor, colPos []uint16) *Project {
@@ -13,6 +16,14 @@ func NewProject(innerExecutor Executor, colPos []uint16) *Project {
 	}
 }
 
+// NewProjectWithExprs は各式の評価結果を返す Project を作成する
+func NewProjectWithExprs(innerExecutor Executor, exprs []Expression) *Project {
+	return &Project{
+		InnerExecutor: innerExecutor,
+		Exprs:         exprs,
+	}
+}
+
 func (p *Project) Next() (Record, error) {
 	// InnerExecutor からレコードを取得
 	record, err := p.InnerExecutor.Next()
@@ -25,6 +36,19 @@ func (p *Project) Next() (Record, error) {
 		return nil, nil
 	}
 
+	// 式が指定されている場合は評価結果を返す
+	if p.Exprs != nil {
+		projectedRecord := make(Record, len(p.Exprs))
+		for i, expr := range p.Exprs {
+			value, err := expr.Eval(record)
+			if err != nil {
+				return nil, err
+			}
+			projectedRecord[i] = value
+		}
+		return projectedRecord, nil
+	}
+
 	// 指定された列だけを返す
 	projectedRecord := make(Record, len(p.ColPos))
 	for i, pos := range p.ColPos {
@@ -32,3 +56,11 @@ func (p *Project) Next() (Record, error) {
 	}
 	return projectedRecord, nil
 }
+
+func (p *Project) Close() {
+	p.InnerExecutor.Close()
+}
+
+func (p *Project) Explain() ExplainInfo {
+	return ExplainInfo{Name: "Project", Children: []*Executor{&p.InnerExecutor}}
+}
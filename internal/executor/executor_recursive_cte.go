@@ -0,0 +1,152 @@
+package executor
+
+import (
+	"fmt"
+
+	"github.com/ren-yamanashi/minesql/internal/storage/config"
+)
+
+// RecursiveCTEParams は NewRecursiveCTEWithParams の引数
+type RecursiveCTEParams struct {
+	Name           string   // 共通テーブル式の名前 (EXPLAIN で表示する)
+	AnchorExecutor Executor // 非再帰部分 (最初の作業テーブルになるレコードを返す)
+	// 作業テーブルを読む再帰部分の Executor を生成する (反復ごとに呼ぶ。引数のレコードは使わない)
+	BuildRecursiveExec func(Record) (Executor, error)
+	WorkingTable       *WorkingTable // 再帰部分が読む作業テーブル
+	Distinct           bool          // UNION (重複を除く) の場合は true
+	MaxDepth           int           // 再帰の深さ (行を返した反復の回数) の上限
+}
+
+// RecursiveCTE は再帰 CTE (WITH RECURSIVE) を実行する
+//
+// 非再帰部分のレコードを返した後、直前の反復で返したレコードを作業テーブルとして再帰部分を実行することを、
+// 再帰部分がレコードを返さなくなるまで繰り返す
+// 各反復で返したレコードは一度だけ作業テーブルに書き出し、次の反復の再帰部分はそれを読み取る
+//
+// Distinct の場合は返したレコードを記録し、既に返したレコードは返さず作業テーブルにも加えない (循環する参照でも終了する)
+// 再帰の深さが MaxDepth を超える場合はエラーを返す
+type RecursiveCTE struct {
+	name               string
+	anchorExec         Executor
+	buildRecursiveExec func(Record) (Executor, error)
+	working            *WorkingTable
+	distinct           bool
+	maxDepth           int
+
+	started       bool                // 非再帰部分の読み取りを始めたか
+	done          bool                // 再帰部分がレコードを返さなくなったか
+	current       Executor            // 現在の反復で読み取り中の Executor (最初は非再帰部分)
+	produced      []Record            // 現在の反復で返したレコード (次の反復の作業テーブル)
+	depth         int                 // 現在の反復の回数 (非再帰部分は 0)
+	seen          map[string]struct{} // 返したレコード (Distinct の場合のみ)
+	recursivePlan Executor            // EXPLAIN で再帰部分として表示する Executor
+}
+
+func NewRecursiveCTE(name string, anchorExec Executor, buildRecursiveExec func(Record) (Executor, error), working *WorkingTable, distinct bool) *RecursiveCTE {
+	return NewRecursiveCTEWithParams(RecursiveCTEParams{
+		Name:               name,
+		AnchorExecutor:     anchorExec,
+		BuildRecursiveExec: buildRecursiveExec,
+		WorkingTable:       working,
+		Distinct:           distinct,
+		MaxDepth:           config.GetCTEMaxRecursionDepth(),
+	})
+}
+
+func NewRecursiveCTEWithParams(params RecursiveCTEParams) *RecursiveCTE {
+	rc := &RecursiveCTE{
+		name:               params.Name,
+		anchorExec:         params.AnchorExecutor,
+		buildRecursiveExec: params.BuildRecursiveExec,
+		working:            params.WorkingTable,
+		distinct:           params.Distinct,
+		maxDepth:           params.MaxDepth,
+	}
+	if rc.distinct {
+		rc.seen = make(map[string]struct{})
+	}
+	return rc
+}
+
+// SetRecursivePlan は EXPLAIN で再帰部分として表示する Executor を設定する
+func (rc *RecursiveCTE) SetRecursivePlan(exec Executor) {
+	rc.recursivePlan = exec
+}
+
+func (rc *RecursiveCTE) Next() (Record, error) {
+	if !rc.started {
+		rc.current = rc.anchorExec
+		rc.started = true
+	}
+
+	for !rc.done {
+		record, err := rc.current.Next()
+		if err != nil {
+			return nil, err
+		}
+		if record == nil {
+			if err := rc.nextIteration(); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		if rc.distinct {
+			key := groupHashKey(record)
+			if _, ok := rc.seen[key]; ok {
+				continue
+			}
+			rc.seen[key] = struct{}{}
+		}
+		if rc.depth > rc.maxDepth {
+			return nil, fmt.Errorf("recursive query %s aborted after %d iterations: try increasing MINESQL_CTE_MAX_RECURSION_DEPTH", rc.name, rc.depth)
+		}
+		rc.produced = append(rc.produced, record)
+		return record, nil
+	}
+	return nil, nil
+}
+
+func (rc *RecursiveCTE) Close() {
+	rc.anchorExec.Close()
+	if rc.current != nil && rc.current != rc.anchorExec {
+		rc.current.Close()
+	}
+}
+
+func (rc *RecursiveCTE) Explain() ExplainInfo {
+	detail := rc.name + " (UNION ALL)"
+	if rc.distinct {
+		detail = rc.name + " (UNION)"
+	}
+	return ExplainInfo{
+		Name:      "RecursiveCTE",
+		Detail:    detail,
+		Children:  []*Executor{&rc.anchorExec},
+		Inner:     &rc.buildRecursiveExec,
+		InnerPlan: rc.recursivePlan,
+	}
+}
+
+// nextIteration は現在の反復で返したレコードを作業テーブルにして、次の反復の再帰部分の Executor を生成する
+//
+// 現在の反復でレコードを返さなかった場合は終了する
+func (rc *RecursiveCTE) nextIteration() error {
+	rc.working.records = rc.produced
+	rc.produced = nil
+	if len(rc.working.records) == 0 {
+		rc.done = true
+		return nil
+	}
+
+	if rc.current != rc.anchorExec {
+		rc.current.Close()
+	}
+	rc.depth++
+	exec, err := rc.buildRecursiveExec(nil)
+	if err != nil {
+		return err
+	}
+	rc.current = exec
+	return nil
+}
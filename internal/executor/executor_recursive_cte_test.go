@@ -0,0 +1,176 @@
+package executor
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecursiveCTE(t *testing.T) {
+	// successor は作業テーブルの各行 n について、n < limit なら n+1 を返す再帰部分を生成する
+	successor := func(working *WorkingTable, limit int) func(Record) (Executor, error) {
+		return func(Record) (Executor, error) {
+			scan := NewWorkingTableScan("seq", working)
+			var records []Record
+			for {
+				record, err := scan.Next()
+				if err != nil || record == nil {
+					return &mockExecutor{records: records}, err
+				}
+				n, _ := strconv.Atoi(string(record[0]))
+				if n < limit {
+					records = append(records, Record{[]byte(strconv.Itoa(n + 1))})
+				}
+			}
+		}
+	}
+
+	t.Run("非再帰部分の結果から始めて、再帰部分がレコードを返さなくなるまで繰り返す", func(t *testing.T) {
+		// GIVEN
+		working := NewWorkingTable()
+		anchor := &mockExecutor{records: []Record{{[]byte("1")}}}
+		cte := NewRecursiveCTEWithParams(RecursiveCTEParams{
+			Name:               "seq",
+			AnchorExecutor:     anchor,
+			BuildRecursiveExec: successor(working, 5),
+			WorkingTable:       working,
+			MaxDepth:           100,
+		})
+
+		// WHEN
+		results := collectAll(t, cte)
+
+		// THEN
+		assert.Equal(t, []Record{{[]byte("1")}, {[]byte("2")}, {[]byte("3")}, {[]byte("4")}, {[]byte("5")}}, results)
+	})
+
+	t.Run("UNION ALL の場合は重複するレコードも返す", func(t *testing.T) {
+		// GIVEN
+		working := NewWorkingTable()
+		anchor := &mockExecutor{records: []Record{{[]byte("1")}, {[]byte("2")}}}
+		cte := NewRecursiveCTEWithParams(RecursiveCTEParams{
+			Name:               "seq",
+			AnchorExecutor:     anchor,
+			BuildRecursiveExec: successor(working, 3),
+			WorkingTable:       working,
+			MaxDepth:           100,
+		})
+
+		// WHEN
+		results := collectAll(t, cte)
+
+		// THEN
+		assert.Equal(t, []Record{{[]byte("1")}, {[]byte("2")}, {[]byte("2")}, {[]byte("3")}, {[]byte("3")}}, results)
+	})
+
+	t.Run("Distinct の場合は既に返したレコードを除き、循環する参照でも終了する", func(t *testing.T) {
+		// GIVEN: a -> b -> c -> a の循環
+		edges := map[string]string{"a": "b", "b": "c", "c": "a"}
+		working := NewWorkingTable()
+		build := func(Record) (Executor, error) {
+			scan := NewWorkingTableScan("graph", working)
+			var records []Record
+			for {
+				record, err := scan.Next()
+				if err != nil || record == nil {
+					return &mockExecutor{records: records}, err
+				}
+				records = append(records, Record{[]byte(edges[string(record[0])])})
+			}
+		}
+		cte := NewRecursiveCTEWithParams(RecursiveCTEParams{
+			Name:               "graph",
+			AnchorExecutor:     &mockExecutor{records: []Record{{[]byte("a")}}},
+			BuildRecursiveExec: build,
+			WorkingTable:       working,
+			Distinct:           true,
+			MaxDepth:           100,
+		})
+
+		// WHEN
+		results := collectAll(t, cte)
+
+		// THEN
+		assert.Equal(t, []Record{{[]byte("a")}, {[]byte("b")}, {[]byte("c")}}, results)
+	})
+
+	t.Run("再帰の深さが上限ちょうどの場合は最後まで返す", func(t *testing.T) {
+		// GIVEN: 2, 3, 4, 5 を返す 4 回の反復
+		working := NewWorkingTable()
+		cte := NewRecursiveCTEWithParams(RecursiveCTEParams{
+			Name:               "seq",
+			AnchorExecutor:     &mockExecutor{records: []Record{{[]byte("1")}}},
+			BuildRecursiveExec: successor(working, 5),
+			WorkingTable:       working,
+			MaxDepth:           4,
+		})
+
+		// WHEN
+		results := collectAll(t, cte)
+
+		// THEN
+		assert.Len(t, results, 5)
+	})
+
+	t.Run("再帰の深さが上限を超える場合はエラーを返す", func(t *testing.T) {
+		// GIVEN
+		working := NewWorkingTable()
+		cte := NewRecursiveCTEWithParams(RecursiveCTEParams{
+			Name:               "seq",
+			AnchorExecutor:     &mockExecutor{records: []Record{{[]byte("1")}}},
+			BuildRecursiveExec: successor(working, 5),
+			WorkingTable:       working,
+			MaxDepth:           3,
+		})
+
+		// WHEN
+		_, err := fetchAll(cte)
+
+		// THEN
+		assert.EqualError(t, err, "recursive query seq aborted after 4 iterations: try increasing MINESQL_CTE_MAX_RECURSION_DEPTH")
+	})
+
+	t.Run("非再帰部分が空の場合は何も返さない", func(t *testing.T) {
+		// GIVEN
+		working := NewWorkingTable()
+		calls := 0
+		cte := NewRecursiveCTEWithParams(RecursiveCTEParams{
+			Name:           "seq",
+			AnchorExecutor: &mockExecutor{},
+			BuildRecursiveExec: func(Record) (Executor, error) {
+				calls++
+				return &mockExecutor{}, nil
+			},
+			WorkingTable: working,
+			MaxDepth:     100,
+		})
+
+		// WHEN
+		record, err := cte.Next()
+
+		// THEN
+		require.NoError(t, err)
+		assert.Nil(t, record)
+		assert.Equal(t, 0, calls)
+	})
+
+	t.Run("EXPLAIN では非再帰部分と再帰部分を子として表示する", func(t *testing.T) {
+		// GIVEN
+		working := NewWorkingTable()
+		cte := NewRecursiveCTE("seq", &mockExecutor{}, successor(working, 5), working, true)
+		plan := NewWorkingTableScan("seq", working)
+		cte.SetRecursivePlan(plan)
+
+		// WHEN
+		info := cte.Explain()
+
+		// THEN
+		assert.Equal(t, "RecursiveCTE", info.Name)
+		assert.Equal(t, "seq (UNION)", info.Detail)
+		assert.Len(t, info.Children, 1)
+		assert.NotNil(t, info.Inner)
+		assert.Equal(t, plan, info.InnerPlan)
+	})
+}
@@ -0,0 +1,26 @@
+package executor
+
+// SingleRow はカラムを持たないレコードを 1 件だけ返す
+//
+// FROM 句のない SELECT 文 (e.g. `SELECT 1`) で、SELECT リストの式を 1 回だけ評価するために Project の下に置く
+type SingleRow struct {
+	returned bool
+}
+
+func NewSingleRow() *SingleRow {
+	return &SingleRow{}
+}
+
+func (sr *SingleRow) Next() (Record, error) {
+	if sr.returned {
+		return nil, nil
+	}
+	sr.returned = true
+	return Record{}, nil
+}
+
+func (sr *SingleRow) Close() {}
+
+func (sr *SingleRow) Explain() ExplainInfo {
+	return ExplainInfo{Name: "SingleRow"}
+}
@@ -0,0 +1,31 @@
+package executor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSingleRow(t *testing.T) {
+	t.Run("カラムを持たないレコードを 1 件だけ返す", func(t *testing.T) {
+		// GIVEN
+		sr := NewSingleRow()
+
+		// WHEN
+		results := collectAll(t, sr)
+
+		// THEN
+		assert.Equal(t, []Record{{}}, results)
+	})
+
+	t.Run("Project の下に置くと、SELECT リストの式を 1 回だけ評価する", func(t *testing.T) {
+		// GIVEN
+		project := NewProjectWithExprs(NewSingleRow(), []Expression{NewConstant([]byte("1"))})
+
+		// WHEN
+		results := collectAll(t, project)
+
+		// THEN
+		assert.Equal(t, []Record{{[]byte("1")}}, results)
+	})
+}
@@ -0,0 +1,455 @@
+package executor
+
+import (
+	"bufio"
+	"bytes"
+	"container/heap"
+	"encoding/binary"
+	"errors"
+	"io"
+	"os"
+	"slices"
+
+	"github.com/ren-yamanashi/minesql/internal/storage/config"
+)
+
+// SortKey はソートキー (カラム位置と並び順) を表す
+type SortKey struct {
+	Pos  int  // ソートに使うカラムの位置
+	Desc bool // true なら降順
+}
+
+// defaultMergeFanIn は一度にマージするランの数の上限の既定値
+const defaultMergeFanIn = 64
+
+// SortParams は NewSortWithParams の引数
+type SortParams struct {
+	InnerExecutor Executor
+	Keys          []SortKey
+	BufferSize    int    // メモリ上に保持するレコードの合計サイズの上限 (バイト)
+	TmpDir        string // ソート済みランを書き出すディレクトリ
+	MergeFanIn    int    // 一度にマージするランの数の上限 (2 未満の場合は defaultMergeFanIn)
+}
+
+// Sort は InnerExecutor の結果を Keys の順に並べ替えて返す
+//
+// レコードの合計サイズが BufferSize を超えた場合、その時点までのレコードをソートしてラン (ソート済みの一時ファイル) として書き出し、
+// 最後に全ランをマージしながら返す (外部マージソート)
+// ランの数が MergeFanIn を超える場合は、MergeFanIn 個ずつマージしたランを書き出し直してから最後のマージを行う (多段マージ)
+//
+// カラム値は順序保存の格納形式なのでバイト列の比較で並べ替える。NULL は最小値として扱う (ASC では先頭、DESC では末尾)
+type Sort struct {
+	innerExecutor Executor
+	keys          []SortKey
+	bufferSize    int
+	tmpDir        string
+	mergeFanIn    int
+	sorted        bool       // ソート済みか (初回の Next でソートする)
+	records       []Record   // メモリ上のレコード (ランを書き出していない場合の結果)
+	pos           int        // records の読み取り位置
+	runs          []*sortRun // 書き出したラン
+	merger        *runMerger // ランのマージ
+}
+
+func NewSort(innerExecutor Executor, keys []SortKey) *Sort {
+	return NewSortWithParams(SortParams{
+		InnerExecutor: innerExecutor,
+		Keys:          keys,
+		BufferSize:    config.GetSortBufferSize(),
+		TmpDir:        config.GetDataDirectory(),
+		MergeFanIn:    defaultMergeFanIn,
+	})
+}
+
+func NewSortWithParams(params SortParams) *Sort {
+	return &Sort{
+		innerExecutor: params.InnerExecutor,
+		keys:          params.Keys,
+		bufferSize:    params.BufferSize,
+		tmpDir:        params.TmpDir,
+		mergeFanIn:    mergeFanInOrDefault(params.MergeFanIn),
+	}
+}
+
+func (s *Sort) Next() (Record, error) {
+	// 初回実行時に全レコードを読み込んでソートする
+	if !s.sorted {
+		if err := s.sort(); err != nil {
+			s.removeRuns()
+			return nil, err
+		}
+		s.sorted = true
+	}
+
+	// ランを書き出していない場合はメモリ上のレコードを順に返す
+	if s.merger == nil {
+		if s.pos >= len(s.records) {
+			return nil, nil
+		}
+		record := s.records[s.pos]
+		s.pos++
+		return record, nil
+	}
+
+	record, err := s.merger.next()
+	if err != nil {
+		s.removeRuns()
+		return nil, err
+	}
+	if record == nil {
+		s.removeRuns()
+	}
+	return record, nil
+}
+
+func (s *Sort) Close() {
+	s.removeRuns()
+	s.innerExecutor.Close()
+}
+
+func (s *Sort) Explain() ExplainInfo {
+	return ExplainInfo{Name: "Sort", Children: []*Executor{&s.innerExecutor}}
+}
+
+// sort は InnerExecutor の全レコードを読み込み、必要に応じてランを書き出しながらソートする
+func (s *Sort) sort() error {
+	size := 0
+	for {
+		record, err := s.innerExecutor.Next()
+		if err != nil {
+			return err
+		}
+		if record == nil {
+			break
+		}
+		s.records = append(s.records, record)
+		size += recordSize(record)
+
+		// バッファサイズを超えたらランとして書き出す
+		if size > s.bufferSize {
+			if err := s.spill(); err != nil {
+				return err
+			}
+			size = 0
+		}
+	}
+
+	slices.SortStableFunc(s.records, s.compare)
+	if len(s.runs) == 0 {
+		return nil
+	}
+
+	// ランを書き出している場合は残りのレコードもランとして書き出し、全ランをマージする
+	if len(s.records) > 0 {
+		if err := s.spill(); err != nil {
+			return err
+		}
+	}
+	runs, err := reduceRuns(s.runs, s.mergeFanIn, s.compare, s.tmpDir, "minesql-sort-*.tmp")
+	s.runs = runs
+	if err != nil {
+		return err
+	}
+	s.merger, err = newRunMerger(s.runs, s.compare)
+	return err
+}
+
+// spill はメモリ上のレコードをソートし、ランとして一時ファイルに書き出す
+func (s *Sort) spill() error {
+	slices.SortStableFunc(s.records, s.compare)
+
+	run, err := createRun(s.tmpDir, "minesql-sort-*.tmp", func(w *bufio.Writer) error {
+		for _, record := range s.records {
+			if err := writeRunRecord(w, record); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	s.runs = append(s.runs, run)
+
+	s.records = nil
+	return nil
+}
+
+// removeRuns はランの一時ファイルを閉じて削除する
+func (s *Sort) removeRuns() {
+	removeRuns(s.runs)
+	s.runs = nil
+}
+
+// compare は Keys に従って 2 つのレコードを比較する
+func (s *Sort) compare(a, b Record) int {
+	for _, key := range s.keys {
+		c := compareColumn(a[key.Pos], b[key.Pos])
+		if key.Desc {
+			c = -c
+		}
+		if c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+// compareColumn はカラム値を比較する (NULL は最小値として扱う)
+func compareColumn(a, b []byte) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return -1
+	case b == nil:
+		return 1
+	}
+	return bytes.Compare(a, b)
+}
+
+// recordSize はレコードがメモリ上で占めるおおよそのサイズ (バイト) を返す
+func recordSize(record Record) int {
+	size := 24 // Record のスライスヘッダー
+	for _, col := range record {
+		size += 24 + len(col) // カラムのスライスヘッダー + データ
+	}
+	return size
+}
+
+// -------------------------------------------------
+// ラン (ソート済みの一時ファイル)
+// -------------------------------------------------
+
+// sortRun はソート済みのレコードを書き出した一時ファイル
+//
+// 書き出した後はファイルを閉じておき、マージするときに開く (開いたままのファイルの数を一度にマージするランの数までに抑える)
+// 各レコードは [カラム数 (uvarint)][カラム長 + 1 (uvarint)][カラム値]... の形式で格納する (カラム長 + 1 が 0 の場合は NULL)
+type sortRun struct {
+	path   string
+	file   *os.File      // マージ中のみ開く
+	reader *bufio.Reader // マージ中のみ設定する
+}
+
+// createRun は tmpDir に一時ファイルを作成し、write で書き込んだ内容をランとして返す
+func createRun(tmpDir, pattern string, write func(w *bufio.Writer) error) (*sortRun, error) {
+	file, err := os.CreateTemp(tmpDir, pattern)
+	if err != nil {
+		return nil, err
+	}
+	run := &sortRun{path: file.Name()}
+
+	writer := bufio.NewWriter(file)
+	err = write(writer)
+	if err == nil {
+		err = writer.Flush()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(run.path)
+		return nil, err
+	}
+	return run, nil
+}
+
+// open はランを先頭から読み込めるように開く
+func (r *sortRun) open() error {
+	file, err := os.Open(r.path)
+	if err != nil {
+		return err
+	}
+	r.file = file
+	r.reader = bufio.NewReader(file)
+	return nil
+}
+
+// removeRuns はランの一時ファイルを閉じて削除する
+func removeRuns(runs []*sortRun) {
+	for _, run := range runs {
+		if run.file != nil {
+			_ = run.file.Close()
+		}
+		_ = os.Remove(run.path)
+	}
+}
+
+// reduceRuns はランの数が fanIn 以下になるまで、先頭から fanIn 個ずつマージしたランを書き出し直す (多段マージ)
+//
+// 隣り合うランを順にマージするので、比較結果が等しいレコードは元のランの順を保つ
+// 戻り値は残っているランで、エラーの場合も削除していないランをすべて返す
+func reduceRuns(runs []*sortRun, fanIn int, compare func(a, b Record) int, tmpDir, pattern string) ([]*sortRun, error) {
+	for len(runs) > fanIn {
+		var merged []*sortRun
+		for len(runs) > 0 {
+			n := min(fanIn, len(runs))
+			if n == 1 {
+				merged = append(merged, runs[0])
+				runs = runs[1:]
+				continue
+			}
+
+			run, err := mergeRuns(runs[:n], compare, tmpDir, pattern)
+			if err != nil {
+				return append(merged, runs...), err
+			}
+			removeRuns(runs[:n])
+			runs = runs[n:]
+			merged = append(merged, run)
+		}
+		runs = merged
+	}
+	return runs, nil
+}
+
+// mergeRuns は runs をマージした結果を 1 つのランとして書き出す
+func mergeRuns(runs []*sortRun, compare func(a, b Record) int, tmpDir, pattern string) (*sortRun, error) {
+	merger, err := newRunMerger(runs, compare)
+	if err != nil {
+		return nil, err
+	}
+	return createRun(tmpDir, pattern, func(w *bufio.Writer) error {
+		for {
+			record, err := merger.next()
+			if err != nil {
+				return err
+			}
+			if record == nil {
+				return nil
+			}
+			if err := writeRunRecord(w, record); err != nil {
+				return err
+			}
+		}
+	})
+}
+
+// writeRunRecord はレコードをランに書き込む
+func writeRunRecord(w *bufio.Writer, record Record) error {
+	buf := binary.AppendUvarint(nil, uint64(len(record)))
+	for _, col := range record {
+		if col == nil {
+			buf = binary.AppendUvarint(buf, 0)
+			continue
+		}
+		buf = binary.AppendUvarint(buf, uint64(len(col))+1)
+		buf = append(buf, col...)
+	}
+	_, err := w.Write(buf)
+	return err
+}
+
+// readRunRecord はランから次のレコードを読み込む (ランの終端に達した場合は nil を返す)
+func readRunRecord(r *bufio.Reader) (Record, error) {
+	nCols, err := binary.ReadUvarint(r)
+	if errors.Is(err, io.EOF) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	record := make(Record, nCols)
+	for i := range record {
+		colLen, err := binary.ReadUvarint(r)
+		if err != nil {
+			return nil, err
+		}
+		if colLen == 0 {
+			continue // NULL
+		}
+		col := make([]byte, colLen-1)
+		if _, err := io.ReadFull(r, col); err != nil {
+			return nil, err
+		}
+		record[i] = col
+	}
+	return record, nil
+}
+
+// runMerger は複数のランを compare の順にマージしながら読み込む (k-way マージ)
+type runMerger struct {
+	runs  []*sortRun
+	heads *runHeap // 各ランの先頭レコード
+}
+
+// newRunMerger は各ランを開き、先頭レコードをヒープに積む
+func newRunMerger(runs []*sortRun, compare func(a, b Record) int) (*runMerger, error) {
+	m := &runMerger{runs: runs, heads: &runHeap{compare: compare}}
+	for i, run := range runs {
+		if err := run.open(); err != nil {
+			return nil, err
+		}
+		record, err := readRunRecord(run.reader)
+		if err != nil {
+			return nil, err
+		}
+		if record != nil {
+			heap.Push(m.heads, runHead{record: record, runIdx: i})
+		}
+	}
+	return m, nil
+}
+
+// peek は次に返すレコードを取り出さずに返す (全ランを読み終えた場合は nil を返す)
+func (m *runMerger) peek() Record {
+	if m.heads.Len() == 0 {
+		return nil
+	}
+	return m.heads.heads[0].record
+}
+
+// next はヒープから最小のレコードを取り出し、そのランの次のレコードをヒープに積む
+func (m *runMerger) next() (Record, error) {
+	if m.heads.Len() == 0 {
+		return nil, nil
+	}
+	head := heap.Pop(m.heads).(runHead)
+
+	next, err := readRunRecord(m.runs[head.runIdx].reader)
+	if err != nil {
+		return nil, err
+	}
+	if next != nil {
+		heap.Push(m.heads, runHead{record: next, runIdx: head.runIdx})
+	}
+	return head.record, nil
+}
+
+// mergeFanInOrDefault は一度にマージするランの数の上限を返す (2 未満の場合は defaultMergeFanIn)
+func mergeFanInOrDefault(fanIn int) int {
+	if fanIn < 2 {
+		return defaultMergeFanIn
+	}
+	return fanIn
+}
+
+// runHead はマージ中の各ランの先頭レコード
+type runHead struct {
+	record Record
+	runIdx int
+}
+
+// runHeap は各ランの先頭レコードを保持する最小ヒープ (container/heap.Interface を実装する)
+//
+// 比較結果が等しい場合はランの番号が小さい (先に読み込んだ) 方を先に返し、安定ソートにする
+type runHeap struct {
+	heads   []runHead
+	compare func(a, b Record) int
+}
+
+func (h *runHeap) Len() int { return len(h.heads) }
+func (h *runHeap) Less(i, j int) bool {
+	if c := h.compare(h.heads[i].record, h.heads[j].record); c != 0 {
+		return c < 0
+	}
+	return h.heads[i].runIdx < h.heads[j].runIdx
+}
+func (h *runHeap) Swap(i, j int) { h.heads[i], h.heads[j] = h.heads[j], h.heads[i] }
+func (h *runHeap) Push(x any)    { h.heads = append(h.heads, x.(runHead)) }
+func (h *runHeap) Pop() any {
+	last := h.heads[len(h.heads)-1]
+	h.heads = h.heads[:len(h.heads)-1]
+	return last
+}
@@ -0,0 +1,229 @@
+package executor
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSort(t *testing.T) {
+	t.Run("指定したカラムの昇順に並べ替える", func(t *testing.T) {
+		// GIVEN
+		inner := &mockExecutor{records: []Record{
+			{[]byte("2"), []byte("Bob")},
+			{[]byte("3"), []byte("Alice")},
+			{[]byte("1"), []byte("Charlie")},
+		}}
+		sort := NewSortWithParams(SortParams{
+			InnerExecutor: inner,
+			Keys:          []SortKey{{Pos: 1}},
+			BufferSize:    1024,
+			TmpDir:        t.TempDir(),
+		})
+
+		// WHEN
+		results := collectAll(t, sort)
+
+		// THEN
+		assert.Equal(t, []string{"Alice", "Bob", "Charlie"}, columnValues(results, 1))
+	})
+
+	t.Run("複数カラムと降順を組み合わせて並べ替える", func(t *testing.T) {
+		// GIVEN
+		inner := &mockExecutor{records: []Record{
+			{[]byte("a"), []byte("1")},
+			{[]byte("b"), []byte("2")},
+			{[]byte("a"), []byte("3")},
+			{[]byte("b"), []byte("1")},
+		}}
+		sort := NewSortWithParams(SortParams{
+			InnerExecutor: inner,
+			Keys:          []SortKey{{Pos: 0}, {Pos: 1, Desc: true}},
+			BufferSize:    1024,
+			TmpDir:        t.TempDir(),
+		})
+
+		// WHEN
+		results := collectAll(t, sort)
+
+		// THEN: 1 列目の昇順、同じ値の中では 2 列目の降順
+		assert.Equal(t, []string{"a3", "a1", "b2", "b1"}, concatValues(results))
+	})
+
+	t.Run("NULL は昇順では先頭、降順では末尾に並ぶ", func(t *testing.T) {
+		// GIVEN
+		records := []Record{
+			{[]byte("1"), []byte("b")},
+			{[]byte("2"), nil},
+			{[]byte("3"), []byte("a")},
+		}
+
+		// WHEN
+		asc := collectAll(t, NewSortWithParams(SortParams{
+			InnerExecutor: &mockExecutor{records: records},
+			Keys:          []SortKey{{Pos: 1}},
+			BufferSize:    1024,
+			TmpDir:        t.TempDir(),
+		}))
+		desc := collectAll(t, NewSortWithParams(SortParams{
+			InnerExecutor: &mockExecutor{records: records},
+			Keys:          []SortKey{{Pos: 1, Desc: true}},
+			BufferSize:    1024,
+			TmpDir:        t.TempDir(),
+		}))
+
+		// THEN
+		assert.Equal(t, []string{"2", "3", "1"}, columnValues(asc, 0))
+		assert.Equal(t, []string{"1", "3", "2"}, columnValues(desc, 0))
+	})
+
+	t.Run("バッファサイズを超えた場合、ランを一時ファイルに書き出してマージする", func(t *testing.T) {
+		// GIVEN: 1 レコードごとにランが書き出されるほど小さいバッファサイズ
+		var records []Record
+		for _, i := range []int{5, 3, 9, 1, 7, 2, 8, 4, 6, 0} {
+			records = append(records, Record{[]byte(fmt.Sprintf("%d", i)), nil})
+		}
+		tmpDir := t.TempDir()
+		sort := NewSortWithParams(SortParams{
+			InnerExecutor: &mockExecutor{records: records},
+			Keys:          []SortKey{{Pos: 0}},
+			BufferSize:    100,
+			TmpDir:        tmpDir,
+		})
+
+		// WHEN
+		first, err := sort.Next()
+		require.NoError(t, err)
+		entries, err := os.ReadDir(tmpDir)
+		require.NoError(t, err)
+		rest := collectAll(t, sort)
+
+		// THEN: ランが書き出され、全件が昇順 (NULL も保持) で返り、一時ファイルは削除される
+		assert.NotEmpty(t, entries)
+		assert.Equal(t, Record{[]byte("0"), nil}, first)
+		assert.Equal(t, []string{"1", "2", "3", "4", "5", "6", "7", "8", "9"}, columnValues(rest, 0))
+		entries, err = os.ReadDir(tmpDir)
+		require.NoError(t, err)
+		assert.Empty(t, entries)
+	})
+
+	t.Run("最後まで読み取る前に Close すると、ランの一時ファイルを削除し InnerExecutor を閉じる", func(t *testing.T) {
+		// GIVEN: ランを書き出した後、先頭の 1 件だけ読み取った状態
+		var records []Record
+		for i := range 10 {
+			records = append(records, Record{[]byte(fmt.Sprintf("%d", i))})
+		}
+		inner := &mockExecutor{records: records}
+		tmpDir := t.TempDir()
+		sort := NewSortWithParams(SortParams{
+			InnerExecutor: inner,
+			Keys:          []SortKey{{Pos: 0}},
+			BufferSize:    100,
+			TmpDir:        tmpDir,
+		})
+		_, err := sort.Next()
+		require.NoError(t, err)
+
+		// WHEN
+		sort.Close()
+
+		// THEN
+		entries, err := os.ReadDir(tmpDir)
+		require.NoError(t, err)
+		assert.Empty(t, entries)
+		assert.True(t, inner.closed)
+	})
+
+	t.Run("ランをマージしても同じキーのレコードは読み込んだ順を保つ", func(t *testing.T) {
+		// GIVEN
+		var records []Record
+		for i := range 6 {
+			records = append(records, Record{[]byte("k"), []byte(fmt.Sprintf("%d", i))})
+		}
+		sort := NewSortWithParams(SortParams{
+			InnerExecutor: &mockExecutor{records: records},
+			Keys:          []SortKey{{Pos: 0}},
+			BufferSize:    100,
+			TmpDir:        t.TempDir(),
+		})
+
+		// WHEN
+		results := collectAll(t, sort)
+
+		// THEN
+		assert.Equal(t, []string{"0", "1", "2", "3", "4", "5"}, columnValues(results, 1))
+	})
+
+	t.Run("ランの数が MergeFanIn を超える場合、多段にマージしてマージ中のランを MergeFanIn 個以下に抑える", func(t *testing.T) {
+		// GIVEN: 1 レコードごとにランが書き出され、同じキーのレコードが複数のランに分かれる
+		var records []Record
+		for i, key := range []string{"5", "3", "9", "1", "7", "3", "8", "4", "6", "0", "3"} {
+			records = append(records, Record{[]byte(key), []byte(fmt.Sprintf("%d", i))})
+		}
+		tmpDir := t.TempDir()
+		sort := NewSortWithParams(SortParams{
+			InnerExecutor: &mockExecutor{records: records},
+			Keys:          []SortKey{{Pos: 0}},
+			BufferSize:    100,
+			TmpDir:        tmpDir,
+			MergeFanIn:    3,
+		})
+
+		// WHEN
+		first, err := sort.Next()
+		require.NoError(t, err)
+		entries, err := os.ReadDir(tmpDir)
+		require.NoError(t, err)
+		rest := collectAll(t, sort)
+
+		// THEN: 最後のマージで読み込むランは 3 個以下で、全件が昇順 (同じキーは読み込んだ順) で返り、一時ファイルは削除される
+		assert.NotEmpty(t, entries)
+		assert.LessOrEqual(t, len(entries), 3)
+		results := append([]Record{first}, rest...)
+		assert.Equal(t, []string{"0", "1", "3", "3", "3", "4", "5", "6", "7", "8", "9"}, columnValues(results, 0))
+		assert.Equal(t, []string{"9", "3", "1", "5", "10", "7", "0", "8", "4", "6", "2"}, columnValues(results, 1))
+		entries, err = os.ReadDir(tmpDir)
+		require.NoError(t, err)
+		assert.Empty(t, entries)
+	})
+
+	t.Run("レコードがない場合は nil を返す", func(t *testing.T) {
+		// GIVEN
+		sort := NewSortWithParams(SortParams{
+			InnerExecutor: &mockExecutor{},
+			Keys:          []SortKey{{Pos: 0}},
+			BufferSize:    1024,
+			TmpDir:        t.TempDir(),
+		})
+
+		// WHEN
+		record, err := sort.Next()
+
+		// THEN
+		assert.NoError(t, err)
+		assert.Nil(t, record)
+	})
+}
+
+// columnValues はレコード群から指定位置のカラム値を文字列として取り出す
+func columnValues(records []Record, pos int) []string {
+	values := make([]string, len(records))
+	for i, record := range records {
+		values[i] = string(record[pos])
+	}
+	return values
+}
+
+// concatValues は各レコードのカラム値を連結した文字列を返す
+func concatValues(records []Record) []string {
+	values := make([]string, len(records))
+	for i, record := range records {
+		for _, col := range record {
+			values[i] += string(col)
+		}
+	}
+	return values
+}
@@ -0,0 +1,71 @@
+package executor
+
+// StreamAggregate はグループ化キーの順に並んだ InnerExecutor の結果を集約する
+//
+// 同じグループのレコードは連続して現れるため、グループ化キーが変わった時点でそのグループの結果を返す (保持するのは 1 グループ分のみ)
+// 結果のレコードは [グループ化キー..., 集約関数の結果...] の順に並ぶ
+type StreamAggregate struct {
+	InnerExecutor Executor
+	groupBy       []int           // グループ化キーのカラム位置
+	aggregates    []AggregateSpec // 集約関数
+	current       *aggregateGroup // 集約中のグループ
+	done          bool            // InnerExecutor を最後まで読んだか
+}
+
+func NewStreamAggregate(innerExecutor Executor, groupBy []int, aggregates []AggregateSpec) *StreamAggregate {
+	return &StreamAggregate{
+		InnerExecutor: innerExecutor,
+		groupBy:       groupBy,
+		aggregates:    aggregates,
+	}
+}
+
+func (sa *StreamAggregate) Next() (Record, error) {
+	if sa.done {
+		return nil, nil
+	}
+
+	for {
+		record, err := sa.InnerExecutor.Next()
+		if err != nil {
+			return nil, err
+		}
+
+		// 最後まで読んだら集約中のグループの結果を返す
+		if record == nil {
+			sa.done = true
+			if sa.current == nil {
+				// GROUP BY がない場合は、レコードが 0 件でも 1 件の結果を返す (COUNT(*) = 0 など)
+				if len(sa.groupBy) > 0 {
+					return nil, nil
+				}
+				sa.current = newAggregateGroup(nil, nil, len(sa.aggregates))
+			}
+			return sa.current.output(sa.aggregates)
+		}
+
+		// グループ化キーが変わったら、それまでのグループの結果を返して次のグループを開始する
+		var finished *aggregateGroup
+		next := newAggregateGroup(record, sa.groupBy, len(sa.aggregates))
+		if sa.current == nil {
+			sa.current = next
+		} else if !sameGroupKey(sa.current.key, next.key) {
+			finished = sa.current
+			sa.current = next
+		}
+		if err := sa.current.add(sa.aggregates, record); err != nil {
+			return nil, err
+		}
+		if finished != nil {
+			return finished.output(sa.aggregates)
+		}
+	}
+}
+
+func (sa *StreamAggregate) Close() {
+	sa.InnerExecutor.Close()
+}
+
+func (sa *StreamAggregate) Explain() ExplainInfo {
+	return ExplainInfo{Name: "StreamAggregate", Detail: aggregatesString(sa.aggregates), Children: []*Executor{&sa.InnerExecutor}}
+}
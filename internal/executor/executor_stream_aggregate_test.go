@@ -0,0 +1,74 @@
+package executor
+
+import (
+	"testing"
+
+	"github.com/ren-yamanashi/minesql/internal/storage/encode"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStreamAggregate(t *testing.T) {
+	t.Run("グループ化キーの順に並んだレコードをグループごとに集約する", func(t *testing.T) {
+		// GIVEN: (category, id, price) のレコードが category の順に並んでいる
+		inner := &mockExecutor{records: []Record{
+			{[]byte("a"), []byte("1"), encode.EncodeInt64(100)},
+			{[]byte("a"), []byte("3"), encode.EncodeInt64(250)},
+			{[]byte("b"), []byte("2"), encode.EncodeInt64(300)},
+			{[]byte("c"), []byte("4"), nil},
+			{[]byte("c"), []byte("5"), encode.EncodeInt64(10)},
+		}}
+		agg := NewStreamAggregate(inner, []int{0}, []AggregateSpec{
+			{Func: AggregateCount, Pos: -1},
+			{Func: AggregateSum, Pos: 2},
+			{Func: AggregateMin, Pos: 2},
+		})
+
+		// WHEN
+		results := collectAll(t, agg)
+
+		// THEN
+		assert.Equal(t, [][]any{
+			{"a", int64(2), int64(350), int64(100)},
+			{"b", int64(1), int64(300), int64(300)},
+			{"c", int64(2), int64(10), int64(10)},
+		}, decodeAggregateResults(results, 1))
+	})
+
+	t.Run("グループ化キーが NULL のレコードは 1 つのグループにまとめる", func(t *testing.T) {
+		// GIVEN
+		inner := &mockExecutor{records: []Record{
+			{nil, []byte("1")},
+			{nil, []byte("2")},
+			{[]byte("a"), []byte("3")},
+		}}
+		agg := NewStreamAggregate(inner, []int{0}, []AggregateSpec{{Func: AggregateCount, Pos: -1}})
+
+		// WHEN
+		results := collectAll(t, agg)
+
+		// THEN
+		assert.Equal(t, [][]any{{nil, int64(2)}, {"a", int64(1)}}, decodeAggregateResults(results, 1))
+	})
+
+	t.Run("GROUP BY がない場合はレコードが 0 件でも 1 件の結果を返す", func(t *testing.T) {
+		// GIVEN
+		agg := NewStreamAggregate(&mockExecutor{}, nil, []AggregateSpec{{Func: AggregateCount, Pos: -1}, {Func: AggregateMax, Pos: 0}})
+
+		// WHEN
+		results := collectAll(t, agg)
+
+		// THEN
+		assert.Equal(t, [][]any{{int64(0), nil}}, decodeAggregateResults(results, 0))
+	})
+
+	t.Run("GROUP BY がありレコードが 0 件の場合は何も返さない", func(t *testing.T) {
+		// GIVEN
+		agg := NewStreamAggregate(&mockExecutor{}, []int{0}, []AggregateSpec{{Func: AggregateCount, Pos: -1}})
+
+		// WHEN
+		results := collectAll(t, agg)
+
+		// THEN
+		assert.Empty(t, results)
+	})
+}
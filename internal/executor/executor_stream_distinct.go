@@ -0,0 +1,38 @@
+package executor
+
+// StreamDistinct は同じ行が連続して並ぶ InnerExecutor の結果 (全カラムの順に並んだ結果など) から重複する行を除く
+//
+// 直前に返した行と同じ行を読み飛ばすため、保持するのは 1 行分のみ
+type StreamDistinct struct {
+	InnerExecutor Executor
+	prevKey       string // 直前に返した行のキー
+	started       bool   // 1 行以上返したか
+}
+
+func NewStreamDistinct(innerExecutor Executor) *StreamDistinct {
+	return &StreamDistinct{InnerExecutor: innerExecutor}
+}
+
+func (sd *StreamDistinct) Next() (Record, error) {
+	for {
+		record, err := sd.InnerExecutor.Next()
+		if err != nil || record == nil {
+			return nil, err
+		}
+		key := groupHashKey(record)
+		if sd.started && key == sd.prevKey {
+			continue
+		}
+		sd.prevKey = key
+		sd.started = true
+		return record, nil
+	}
+}
+
+func (sd *StreamDistinct) Close() {
+	sd.InnerExecutor.Close()
+}
+
+func (sd *StreamDistinct) Explain() ExplainInfo {
+	return ExplainInfo{Name: "StreamDistinct", Children: []*Executor{&sd.InnerExecutor}}
+}
@@ -0,0 +1,47 @@
+package executor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStreamDistinct(t *testing.T) {
+	t.Run("連続する同じ行を除く", func(t *testing.T) {
+		// GIVEN
+		inner := &mockExecutor{records: []Record{
+			{[]byte("a"), []byte("1")},
+			{[]byte("a"), []byte("1")},
+			{[]byte("a"), []byte("2")},
+			{[]byte("b"), []byte("1")},
+			{[]byte("b"), []byte("1")},
+		}}
+		distinct := NewStreamDistinct(inner)
+
+		// WHEN
+		results := collectAll(t, distinct)
+
+		// THEN
+		assert.Equal(t, []Record{
+			{[]byte("a"), []byte("1")},
+			{[]byte("a"), []byte("2")},
+			{[]byte("b"), []byte("1")},
+		}, results)
+	})
+
+	t.Run("NULL 同士は同じ値、NULL と空文字列は異なる値として扱う", func(t *testing.T) {
+		// GIVEN
+		inner := &mockExecutor{records: []Record{
+			{nil},
+			{nil},
+			{[]byte("")},
+		}}
+		distinct := NewStreamDistinct(inner)
+
+		// WHEN
+		results := collectAll(t, distinct)
+
+		// THEN
+		assert.Equal(t, []Record{{nil}, {[]byte("")}}, results)
+	})
+}
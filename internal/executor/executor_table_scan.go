@@ -22,6 +22,7 @@ type TableScan struct {
 	searchMode     access.RecordSearchMode
 	whileCondition func(Record) bool
 	iterator       *access.TableIterator
+	explainNote    // プランナーが選んだアクセス方法と見積もり (EXPLAIN で表示する)
 }
 
 func NewTableScan(params TableScanParams) *TableScan {
@@ -68,3 +69,9 @@ func (ss *TableScan) Next() (Record, error) {
 
 	return record, nil
 }
+
+func (ss *TableScan) Close() {}
+
+func (ss *TableScan) Explain() ExplainInfo {
+	return ExplainInfo{Name: "TableScan", Detail: ss.table.Name}
+}
@@ -124,7 +124,7 @@ func InitStorageEngineForTest(t *testing.T, dataDir string) *handler.Handler {
 
 	// テーブルを作成
 	createTable := NewCreateTable("users", 1, []handler.CreateIndexParam{
-		{Name: "last_name", ColName: "last_name", ColIdx: 2, Unique: true},
+		{Name: "last_name", ColNames: []string{"last_name"}, ColIdxs: []uint16{2}, Unique: true},
 	}, []handler.CreateColumnParam{
 		{Name: "id", Type: handler.ColumnTypeString},
 		{Name: "first_name", Type: handler.ColumnTypeString},
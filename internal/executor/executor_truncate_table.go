@@ -0,0 +1,23 @@
+package executor
+
+import "github.com/ren-yamanashi/minesql/internal/storage/handler"
+
+// TruncateTable はテーブルの全レコードを削除する
+type TruncateTable struct {
+	trxId     handler.TrxId
+	tableName string // 対象のテーブル名
+}
+
+func NewTruncateTable(trxId handler.TrxId, tableName string) *TruncateTable {
+	return &TruncateTable{trxId: trxId, tableName: tableName}
+}
+
+func (tt *TruncateTable) Next() (Record, error) {
+	hdl := handler.Get()
+	if err := hdl.TruncateTable(tt.trxId, tt.tableName); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
+
+func (tt *TruncateTable) Close() {}
@@ -0,0 +1,49 @@
+package executor
+
+import (
+	"testing"
+
+	"github.com/ren-yamanashi/minesql/internal/storage/access"
+	"github.com/ren-yamanashi/minesql/internal/storage/handler"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTruncateTable_Next(t *testing.T) {
+	t.Run("テーブルの全レコードを削除できる", func(t *testing.T) {
+		// GIVEN
+		tmpdir := t.TempDir()
+		hdl := InitStorageEngineForTest(t, tmpdir)
+		defer handler.Reset()
+		truncateTable := NewTruncateTable(0, "users")
+
+		// WHEN
+		_, err := truncateTable.Next()
+
+		// THEN
+		assert.NoError(t, err)
+		_, ok := hdl.Catalog.GetTableMetaByName("users")
+		assert.True(t, ok)
+		tbl, err := hdl.GetTable("users")
+		assert.NoError(t, err)
+		results, err := fetchAll(testTableScan(tbl,
+			access.RecordSearchModeStart{},
+			func(record Record) bool { return true },
+		))
+		assert.NoError(t, err)
+		assert.Equal(t, 0, len(results))
+	})
+
+	t.Run("存在しないテーブルの切り詰めはエラーになる", func(t *testing.T) {
+		// GIVEN
+		tmpdir := t.TempDir()
+		InitStorageEngineForTest(t, tmpdir)
+		defer handler.Reset()
+		truncateTable := NewTruncateTable(0, "orders")
+
+		// WHEN
+		_, err := truncateTable.Next()
+
+		// THEN
+		assert.ErrorContains(t, err, "table orders not found")
+	})
+}
@@ -7,6 +7,7 @@ type Union struct {
 	current        int
 	seen           map[string]struct{}
 	innerExecutors []Executor
+	explainNote    // プランナーが選んだアクセス方法と見積もり (EXPLAIN で表示する)
 }
 
 func NewUnion(innerExecutors []Executor) *Union {
@@ -37,6 +38,12 @@ func (u *Union) Next() (Record, error) {
 	return nil, nil
 }
 
+func (u *Union) Close() {
+	for _, exec := range u.innerExecutors {
+		exec.Close()
+	}
+}
+
 // recordKey はレコードから重複判定用のキーを生成する
 //
 // 各カラムの長さとデータを連結した文字列を返す
@@ -48,3 +55,11 @@ func recordKey(record Record) string {
 	}
 	return string(buf)
 }
+
+func (u *Union) Explain() ExplainInfo {
+	children := make([]*Executor, len(u.innerExecutors))
+	for i := range u.innerExecutors {
+		children[i] = &u.innerExecutors[i]
+	}
+	return ExplainInfo{Name: "Union", Children: children}
+}
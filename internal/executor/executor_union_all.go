@@ -0,0 +1,39 @@
+package executor
+
+// UnionAll は複数の Executor の結果を順に連結して返す (重複を除かない)
+type UnionAll struct {
+	current        int
+	innerExecutors []Executor
+}
+
+func NewUnionAll(innerExecutors []Executor) *UnionAll {
+	return &UnionAll{innerExecutors: innerExecutors}
+}
+
+func (u *UnionAll) Next() (Record, error) {
+	for u.current < len(u.innerExecutors) {
+		record, err := u.innerExecutors[u.current].Next()
+		if err != nil {
+			return nil, err
+		}
+		if record != nil {
+			return record, nil
+		}
+		u.current++
+	}
+	return nil, nil
+}
+
+func (u *UnionAll) Close() {
+	for _, exec := range u.innerExecutors {
+		exec.Close()
+	}
+}
+
+func (u *UnionAll) Explain() ExplainInfo {
+	children := make([]*Executor, len(u.innerExecutors))
+	for i := range u.innerExecutors {
+		children[i] = &u.innerExecutors[i]
+	}
+	return ExplainInfo{Name: "UnionAll", Children: children}
+}
@@ -0,0 +1,46 @@
+package executor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnionAll(t *testing.T) {
+	t.Run("複数の Executor の結果を重複を除かずに順に連結する", func(t *testing.T) {
+		// GIVEN
+		exec1 := &mockExecutor{records: []Record{
+			{[]byte("1"), []byte("Alice")},
+			{[]byte("2"), []byte("Bob")},
+		}}
+		exec2 := &mockExecutor{records: []Record{
+			{[]byte("2"), []byte("Bob")},
+		}}
+		union := NewUnionAll([]Executor{exec1, exec2})
+
+		// WHEN
+		results := collectAll(t, union)
+
+		// THEN
+		assert.Equal(t, []Record{
+			{[]byte("1"), []byte("Alice")},
+			{[]byte("2"), []byte("Bob")},
+			{[]byte("2"), []byte("Bob")},
+		}, results)
+	})
+
+	t.Run("空の Executor は読み飛ばす", func(t *testing.T) {
+		// GIVEN
+		union := NewUnionAll([]Executor{
+			&mockExecutor{},
+			&mockExecutor{records: []Record{{[]byte("1")}}},
+			&mockExecutor{},
+		})
+
+		// WHEN
+		results := collectAll(t, union)
+
+		// THEN
+		assert.Equal(t, []Record{{[]byte("1")}}, results)
+	})
+}
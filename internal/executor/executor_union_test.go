@@ -10,6 +10,7 @@ import (
 type mockExecutor struct {
 	records []Record
 	index   int
+	closed  bool // Close が呼ばれたか
 }
 
 func (m *mockExecutor) Next() (Record, error) {
@@ -21,6 +22,10 @@ func (m *mockExecutor) Next() (Record, error) {
 	return r, nil
 }
 
+func (m *mockExecutor) Close() {
+	m.closed = true
+}
+
 func TestUnion(t *testing.T) {
 	t.Run("複数の Executor の結果を結合する", func(t *testing.T) {
 		// GIVEN
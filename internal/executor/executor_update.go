@@ -2,16 +2,17 @@ package executor
 
 import (
 	"bytes"
+	"fmt"
+	"slices"
 
 	"github.com/ren-yamanashi/minesql/internal/storage/access"
-	"github.com/ren-yamanashi/minesql/internal/storage/btree"
 	"github.com/ren-yamanashi/minesql/internal/storage/handler"
-	"github.com/ren-yamanashi/minesql/internal/storage/lock"
 )
 
 type SetColumn struct {
-	Pos   uint16 // 更新対象のカラムの位置 (インデックス)
-	Value []byte // 更新後の値
+	Pos   uint16     // 更新対象のカラムの位置 (インデックス)
+	Value []byte     // 更新後の値 (nil の場合は NULL)
+	Expr  Expression // 更新後の値を計算する式 (nil の場合は Value を使う)
 }
 
 // Update は InnerExecutor の結果を元にレコードを更新する
@@ -20,6 +21,8 @@ type Update struct {
 	table         *access.Table
 	setColumns    []SetColumn
 	innerExecutor Executor
+	checks        []CheckConstraint // 更新後のレコードが満たすべき CHECK 制約
+	target        *joinTarget       // 複数テーブルの UPDATE の場合に、結合レコード内の更新対象テーブルのカラムの位置 (nil なら単一テーブル)
 }
 
 func NewUpdate(trxId handler.TrxId, table *access.Table, setColumns []SetColumn, innerExecutor Executor) *Update {
@@ -31,77 +34,123 @@ func NewUpdate(trxId handler.TrxId, table *access.Table, setColumns []SetColumn,
 	}
 }
 
+// SetCheckConstraints は更新後のレコードが満たすべき CHECK 制約を設定する
+func (upd *Update) SetCheckConstraints(checks []CheckConstraint) {
+	upd.checks = checks
+}
+
+// SetJoinTarget は InnerExecutor が結合レコードを返す場合 (複数テーブルの UPDATE の場合) に、結合レコード内の更新対象テーブルのカラムの位置を設定する
+//
+// SET 句の式は、更新対象の行の後ろに結合レコードを連結したレコードに対して評価する
+func (upd *Update) SetJoinTarget(offset int, nCols int) {
+	upd.target = &joinTarget{offset: offset, nCols: nCols}
+}
+
 func (upd *Update) Next() (Record, error) {
 	hdl := handler.Get()
 
 	// 更新対象のレコードを先にすべて収集する
 	// (更新により Iterator が参照するページデータが破壊されるのを防ぐ)
-	var records []Record
-	for {
-		record, err := upd.innerExecutor.Next()
-		if err != nil {
-			return nil, err
-		}
-		if record == nil {
-			break
-		}
-		records = append(records, record)
+	records, sources, err := collectTargetRecords(upd.innerExecutor, upd.table, upd.target)
+	if err != nil {
+		return nil, err
 	}
 
+	tableMeta, _ := hdl.Catalog.GetTableMetaByName(upd.table.Name)
+
 	// 更新後のレコードを作成
 	var updatedRecords []Record
-	for _, record := range records {
-		updatedRecord := make(Record, len(record))
-		copy(updatedRecord, record)
-
-		for _, setCol := range upd.setColumns {
-			updatedRecord[setCol.Pos] = setCol.Value
+	for i, record := range records {
+		updatedRecord, err := upd.buildUpdatedRecord(tableMeta, record, sourceAt(sources, i))
+		if err != nil {
+			return nil, err
 		}
 		updatedRecords = append(updatedRecords, updatedRecord)
 	}
 
 	// テーブルの FK 制約を確認
-	tableMeta, _ := hdl.Catalog.GetTableMetaByName(upd.table.Name)
 	fks := tableMeta.GetForeignKeyConstraints()
 	refFKs := hdl.Catalog.GetForeignKeysReferencingTable(upd.table.Name)
 	hasFKChecks := len(fks) > 0 || len(refFKs) > 0
 
 	// 更新後のレコードで更新を実行
 	for i, record := range records {
-		encodedOldKey := upd.table.EncodeKey(record)
-		encodedNewKey := upd.table.EncodeKey(updatedRecords[i])
+		updatedRecord := updatedRecords[i]
 
 		// FK チェック: 排他ロックを先に取得してから FK 制約を検証する
 		if hasFKChecks {
-			btr := btree.NewBTree(upd.table.MetaPageId)
-			_, pos, err := btr.FindByKey(hdl.BufferPool, encodedOldKey)
+			current, ok, err := lockLatestRow(hdl, upd.trxId, upd.table, record)
 			if err != nil {
 				return nil, err
 			}
-			if err := hdl.LockMgr.Lock(upd.trxId, pos, lock.Exclusive); err != nil {
-				return nil, err
+			if !ok {
+				continue
 			}
-
-			if err := checkFKOnUpdate(hdl.BufferPool, upd.trxId, hdl.LockMgr, tableMeta, refFKs, fks, record, updatedRecords[i]); err != nil {
-				return nil, err
+			// 先に更新した行の参照アクション (ON UPDATE CASCADE / SET NULL) で変更済みの場合は、最新の行から更新後のレコードを作り直す
+			if !slices.EqualFunc(current, record, bytes.Equal) {
+				if updatedRecord, err = upd.buildUpdatedRecord(tableMeta, current, sourceAt(sources, i)); err != nil {
+					return nil, err
+				}
 			}
+			record = current
 		}
 
-		if bytes.Equal(encodedOldKey, encodedNewKey) {
-			// プライマリキーが変わらない場合はインプレース更新
-			if err := upd.table.UpdateInplace(hdl.BufferPool, upd.trxId, hdl.LockMgr, record, updatedRecords[i]); err != nil {
-				return nil, err
-			}
-		} else {
-			// プライマリキーが変わる場合はソフトデリート + Insert
-			if err := upd.table.SoftDelete(hdl.BufferPool, upd.trxId, hdl.LockMgr, record); err != nil {
-				return nil, err
-			}
-			if err := upd.table.Insert(hdl.BufferPool, upd.trxId, hdl.LockMgr, updatedRecords[i]); err != nil {
-				return nil, err
-			}
+		if err := updateRow(hdl, upd.trxId, upd.table, record, updatedRecord, 0); err != nil {
+			return nil, err
 		}
 	}
 
 	return nil, nil
 }
+
+func (upd *Update) Close() {
+	upd.innerExecutor.Close()
+}
+
+// buildUpdatedRecord は SET 句を適用した更新後のレコードを作成し、CHECK 制約を満たすことを確認する
+//
+// source は複数テーブルの UPDATE の場合の取り出し元の結合レコード (単一テーブルの場合は nil)
+func (upd *Update) buildUpdatedRecord(tableMeta *handler.TableMetadata, record Record, source Record) (Record, error) {
+	return applySetColumns(tableMeta, upd.setColumns, upd.checks, record, source)
+}
+
+// applySetColumns は SET 句を適用した更新後のレコードを作成し、CHECK 制約を満たすことを確認する
+//
+// 式は SET 句の順に、それまでの SET 句を適用したレコードに対して評価する (e.g. `SET a = a + 1, b = a` の b は更新後の a)
+// extra を指定した場合は、レコードの後ろに extra を連結して評価する
+//   - ON DUPLICATE KEY UPDATE: 追加しようとした行 (VALUES(col) は extra のカラムを参照する)
+//   - 複数テーブルの UPDATE: 取り出し元の結合レコード (カラムは extra のカラムを参照する)
+func applySetColumns(tableMeta *handler.TableMetadata, setColumns []SetColumn, checks []CheckConstraint, record Record, extra Record) (Record, error) {
+	updatedRecord := make(Record, len(record))
+	copy(updatedRecord, record)
+
+	for _, setCol := range setColumns {
+		if setCol.Expr == nil {
+			updatedRecord[setCol.Pos] = setCol.Value
+			continue
+		}
+		value, err := setCol.Expr.Eval(append(slices.Clip(updatedRecord), extra...))
+		if err != nil {
+			return nil, err
+		}
+		if value == nil && isNotNullColumn(tableMeta, setCol.Pos) {
+			return nil, fmt.Errorf("column '%s' cannot be null", tableMeta.GetSortedCols()[setCol.Pos].Name)
+		}
+		updatedRecord[setCol.Pos] = value
+	}
+	if err := checkConstraints(checks, updatedRecord); err != nil {
+		return nil, err
+	}
+	return updatedRecord, nil
+}
+
+func (upd *Update) Explain() ExplainInfo {
+	return ExplainInfo{Name: "Update", Detail: upd.table.Name, Children: []*Executor{&upd.innerExecutor}}
+}
+
+// isNotNullColumn はカラムが NULL を許容しないかどうかを返す
+//
+// プライマリキーのカラムは NOT NULL の指定がなくても NULL を許容しない
+func isNotNullColumn(tableMeta *handler.TableMetadata, pos uint16) bool {
+	return tableMeta.GetSortedCols()[pos].NotNull || pos < uint16(tableMeta.PKCount)
+}
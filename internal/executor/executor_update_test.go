@@ -311,6 +311,47 @@ func TestUpdate_Next(t *testing.T) {
 		assert.Equal(t, []byte("Brown"), results[4][2])
 	})
 
+	t.Run("更新後のレコードが CHECK 制約を満たさない場合、エラーを返し更新しない", func(t *testing.T) {
+		// GIVEN
+		tmpdir := t.TempDir()
+		InitStorageEngineForTest(t, tmpdir)
+		defer handler.Reset()
+
+		var trxId handler.TrxId = 1
+
+		// テーブルアクセスメソッドを取得
+		tbl, err := handler.Get().GetTable("users")
+		assert.NoError(t, err)
+
+		// first_name != 'Banned' の CHECK 制約に対して、first_name を 'Banned' に更新
+		upd := NewUpdate(trxId, tbl, []SetColumn{
+			{Pos: 1, Value: []byte("Banned")},
+		}, testTableScan(tbl,
+			access.RecordSearchModeStart{},
+			func(record Record) bool { return true },
+		))
+		upd.SetCheckConstraints([]CheckConstraint{
+			{Name: "users_chk_1", Expr: NewCompare("!=", NewColumnRef(1), NewConstant([]byte("Banned")))},
+		})
+
+		// WHEN
+		_, err = upd.Next()
+
+		// THEN
+		var checkErr *CheckConstraintViolationError
+		assert.ErrorAs(t, err, &checkErr)
+		assert.Equal(t, "users_chk_1", checkErr.Name)
+
+		// THEN: レコードは更新されていない
+		scan := testTableScan(tbl,
+			access.RecordSearchModeStart{},
+			func(record Record) bool { return true },
+		)
+		results, err := fetchAll(scan)
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("John"), results[0][1])
+	})
+
 	t.Run("空のテーブルに対して更新しても正常に動作する", func(t *testing.T) {
 		// GIVEN
 		tmpdir := t.TempDir()
@@ -537,12 +578,12 @@ func TestUpdate_Next(t *testing.T) {
 		assert.NoError(t, err)
 
 		childCt := NewCreateTable("orders", 1,
-			[]handler.CreateIndexParam{{Name: "idx_user_id", ColName: "user_id", ColIdx: 1, Unique: false}},
+			[]handler.CreateIndexParam{{Name: "idx_user_id", ColNames: []string{"user_id"}, ColIdxs: []uint16{1}, Unique: false}},
 			[]handler.CreateColumnParam{
 				{Name: "id", Type: handler.ColumnTypeString},
 				{Name: "user_id", Type: handler.ColumnTypeString},
 			},
-			[]handler.CreateConstraintParam{{ConstraintName: "fk_user", ColName: "user_id", RefTableName: "users", RefColName: "id"}},
+			[]handler.CreateConstraintParam{{ConstraintName: "fk_user", ColNames: []string{"user_id"}, RefTableName: "users", RefColNames: []string{"id"}}},
 		)
 		_, err = childCt.Next()
 		assert.NoError(t, err)
@@ -594,12 +635,12 @@ func TestUpdate_Next(t *testing.T) {
 		assert.NoError(t, err)
 
 		childCt := NewCreateTable("orders", 1,
-			[]handler.CreateIndexParam{{Name: "idx_user_id", ColName: "user_id", ColIdx: 1, Unique: false}},
+			[]handler.CreateIndexParam{{Name: "idx_user_id", ColNames: []string{"user_id"}, ColIdxs: []uint16{1}, Unique: false}},
 			[]handler.CreateColumnParam{
 				{Name: "id", Type: handler.ColumnTypeString},
 				{Name: "user_id", Type: handler.ColumnTypeString},
 			},
-			[]handler.CreateConstraintParam{{ConstraintName: "fk_user", ColName: "user_id", RefTableName: "users", RefColName: "id"}},
+			[]handler.CreateConstraintParam{{ConstraintName: "fk_user", ColNames: []string{"user_id"}, RefTableName: "users", RefColNames: []string{"id"}}},
 		)
 		_, err = childCt.Next()
 		assert.NoError(t, err)
@@ -0,0 +1,300 @@
+package executor
+
+import (
+	"strings"
+
+	"github.com/ren-yamanashi/minesql/internal/storage/encode"
+)
+
+// WindowFunc はウィンドウ関数の種類を表す
+type WindowFunc uint8
+
+const (
+	WindowRowNumber WindowFunc = iota // ROW_NUMBER: パーティション内の行番号 (1 から)
+	WindowRank                        // RANK: 順位 (同順位の行があると次の順位は飛ぶ)
+	WindowDenseRank                   // DENSE_RANK: 順位 (同順位の行があっても次の順位は飛ばない)
+	WindowLag                         // LAG: Offset 行前の行の値
+	WindowLead                        // LEAD: Offset 行後の行の値
+	WindowAggregate                   // 集約関数 (フレーム内の行を集約する)
+)
+
+// FrameBoundType はウィンドウフレームの境界の種類を表す
+type FrameBoundType uint8
+
+const (
+	FrameUnboundedPreceding FrameBoundType = iota // UNBOUNDED PRECEDING: パーティションの先頭
+	FramePreceding                                // n PRECEDING: 現在行の n 行前
+	FrameCurrentRow                               // CURRENT ROW: 現在行
+	FrameFollowing                                // n FOLLOWING: 現在行の n 行後
+	FrameUnboundedFollowing                       // UNBOUNDED FOLLOWING: パーティションの末尾
+)
+
+// FrameBound はウィンドウフレームの境界を表す
+type FrameBound struct {
+	Type   FrameBoundType
+	Offset int // n PRECEDING / n FOLLOWING の n
+}
+
+// WindowFrame は集約関数が対象とする行の範囲 (ウィンドウフレーム) を表す
+type WindowFrame struct {
+	Start FrameBound
+	End   FrameBound
+	// true なら終端を現在行のピア (ORDER BY の値が等しい行) の最後まで広げる
+	// (フレームを省略した場合のデフォルト `RANGE BETWEEN UNBOUNDED PRECEDING AND CURRENT ROW` に相当する)
+	Peers bool
+}
+
+// WindowFuncSpec はウィンドウ関数と、その引数を表す
+type WindowFuncSpec struct {
+	Func      WindowFunc
+	Aggregate AggregateSpec // 集約関数 (WindowAggregate の場合)
+	Pos       int           // LAG / LEAD の対象のカラム位置
+	Offset    int           // LAG / LEAD で参照する行の距離
+	Default   []byte        // LAG / LEAD で参照する行がパーティションの外の場合の値 (nil なら NULL)
+	Frame     WindowFrame   // ウィンドウフレーム (WindowAggregate の場合)
+}
+
+func (spec WindowFuncSpec) String() string {
+	switch spec.Func {
+	case WindowRowNumber:
+		return "ROW_NUMBER"
+	case WindowRank:
+		return "RANK"
+	case WindowDenseRank:
+		return "DENSE_RANK"
+	case WindowLag:
+		return "LAG"
+	case WindowLead:
+		return "LEAD"
+	default:
+		return spec.Aggregate.Func.String()
+	}
+}
+
+// Window は InnerExecutor の結果に、ウィンドウ関数の結果のカラムを追加する
+//
+// InnerExecutor の結果はパーティションキー・ORDER BY のキーの順に並んでいること (Sort を重ねて並べ替える)
+// 同じパーティションのレコードは連続して現れるため、1 パーティション分のレコードをメモリ上に保持して計算する
+// 結果のレコードは [InnerExecutor のレコード..., ウィンドウ関数の結果...] の順に並ぶ
+type Window struct {
+	InnerExecutor Executor
+	partitionBy   []int            // パーティションキーのカラム位置
+	orderBy       []SortKey        // パーティション内の並び順 (ピアの判定に使う)
+	funcs         []WindowFuncSpec // ウィンドウ関数
+	partition     []Record         // 計算中のパーティションのレコード
+	results       [][][]byte       // partition の各レコードのウィンドウ関数の結果
+	pos           int              // partition の読み取り位置
+	lookahead     Record           // 次のパーティションの最初のレコード
+	done          bool             // InnerExecutor を最後まで読んだか
+}
+
+func NewWindow(innerExecutor Executor, partitionBy []int, orderBy []SortKey, funcs []WindowFuncSpec) *Window {
+	return &Window{
+		InnerExecutor: innerExecutor,
+		partitionBy:   partitionBy,
+		orderBy:       orderBy,
+		funcs:         funcs,
+	}
+}
+
+func (w *Window) Next() (Record, error) {
+	if w.pos >= len(w.partition) {
+		if err := w.readPartition(); err != nil {
+			return nil, err
+		}
+		if len(w.partition) == 0 {
+			return nil, nil
+		}
+	}
+
+	record := w.partition[w.pos]
+	output := make(Record, 0, len(record)+len(w.funcs))
+	output = append(output, record...)
+	output = append(output, w.results[w.pos]...)
+	w.pos++
+	return output, nil
+}
+
+func (w *Window) Close() {
+	w.InnerExecutor.Close()
+}
+
+// readPartition は次のパーティションのレコードを読み込み、ウィンドウ関数の結果を計算する
+//
+// パーティションキーが変わった最初のレコードは次のパーティションのために lookahead に保持する
+func (w *Window) readPartition() error {
+	w.partition = w.partition[:0]
+	w.pos = 0
+	if w.lookahead != nil {
+		w.partition = append(w.partition, w.lookahead)
+		w.lookahead = nil
+	}
+	for !w.done {
+		record, err := w.InnerExecutor.Next()
+		if err != nil {
+			return err
+		}
+		if record == nil {
+			w.done = true
+			break
+		}
+		if len(w.partition) > 0 && !w.samePartition(w.partition[0], record) {
+			w.lookahead = record
+			break
+		}
+		w.partition = append(w.partition, record)
+	}
+	return w.compute()
+}
+
+// samePartition は 2 つのレコードが同じパーティションに属するかを判定する (NULL 同士も同じパーティションとして扱う)
+func (w *Window) samePartition(a, b Record) bool {
+	for _, pos := range w.partitionBy {
+		if compareColumn(a[pos], b[pos]) != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// samePeer は 2 つのレコードがピア (ORDER BY のキーの値が等しい行) かを判定する
+//
+// ORDER BY がない場合はパーティション内のすべての行がピアになる
+func (w *Window) samePeer(a, b Record) bool {
+	for _, key := range w.orderBy {
+		if compareColumn(a[key.Pos], b[key.Pos]) != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// compute はパーティション内の各レコードのウィンドウ関数の結果を計算する
+func (w *Window) compute() error {
+	n := len(w.partition)
+	w.results = make([][][]byte, n)
+	for i := range w.results {
+		w.results[i] = make([][]byte, len(w.funcs))
+	}
+
+	// ピアのグループ: peerStart[i] / peerEnd[i] は i 行目と同じピアの最初・最後の行の位置
+	peerStart, peerEnd := make([]int, n), make([]int, n)
+	for i := range n {
+		if i > 0 && w.samePeer(w.partition[i-1], w.partition[i]) {
+			peerStart[i] = peerStart[i-1]
+		} else {
+			peerStart[i] = i
+		}
+	}
+	for i := n - 1; i >= 0; i-- {
+		if i < n-1 && peerStart[i+1] == peerStart[i] {
+			peerEnd[i] = peerEnd[i+1]
+		} else {
+			peerEnd[i] = i
+		}
+	}
+
+	for f, spec := range w.funcs {
+		switch spec.Func {
+		case WindowRowNumber:
+			for i := range n {
+				w.results[i][f] = encode.EncodeInt64(int64(i + 1))
+			}
+		case WindowRank:
+			for i := range n {
+				w.results[i][f] = encode.EncodeInt64(int64(peerStart[i] + 1))
+			}
+		case WindowDenseRank:
+			rank := int64(0)
+			for i := range n {
+				if peerStart[i] == i {
+					rank++
+				}
+				w.results[i][f] = encode.EncodeInt64(rank)
+			}
+		case WindowLag, WindowLead:
+			for i := range n {
+				j := i - spec.Offset
+				if spec.Func == WindowLead {
+					j = i + spec.Offset
+				}
+				if j >= 0 && j < n {
+					w.results[i][f] = w.partition[j][spec.Pos]
+				} else {
+					w.results[i][f] = spec.Default
+				}
+			}
+		case WindowAggregate:
+			if err := w.computeAggregate(f, spec, peerEnd); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// computeAggregate は各レコードのフレーム内の行を集約する
+//
+// フレームの始端が UNBOUNDED PRECEDING の場合は、終端までの行を前の行の途中結果に加えていく (累計)
+// それ以外の場合は行ごとにフレーム内の行を集約し直す
+func (w *Window) computeAggregate(f int, spec WindowFuncSpec, peerEnd []int) error {
+	n := len(w.partition)
+	var running aggregateState
+	added := 0 // running に加えた行数
+	for i := range n {
+		start, end := w.frameRange(spec.Frame, i, n, peerEnd)
+
+		state := &running
+		if spec.Frame.Start.Type != FrameUnboundedPreceding {
+			state = &aggregateState{}
+			added = start
+		}
+		for ; added <= end; added++ {
+			if err := state.add(spec.Aggregate, w.partition[added]); err != nil {
+				return err
+			}
+		}
+		value, err := state.result(spec.Aggregate)
+		if err != nil {
+			return err
+		}
+		w.results[i][f] = value
+	}
+	return nil
+}
+
+// frameRange は i 行目のフレームの範囲 [start, end] (パーティション内の位置) を返す
+//
+// フレームに行がない場合は start > end になる
+func (w *Window) frameRange(frame WindowFrame, i, n int, peerEnd []int) (int, int) {
+	start := max(boundPos(frame.Start, i, n), 0)
+	end := min(boundPos(frame.End, i, n), n-1)
+	if frame.Peers {
+		end = peerEnd[i]
+	}
+	return start, end
+}
+
+// boundPos はフレームの境界の、i 行目に対するパーティション内の位置を返す (パーティションの範囲外の位置も返す)
+func boundPos(bound FrameBound, i, n int) int {
+	switch bound.Type {
+	case FrameUnboundedPreceding:
+		return 0
+	case FramePreceding:
+		return i - bound.Offset
+	case FrameFollowing:
+		return i + bound.Offset
+	case FrameUnboundedFollowing:
+		return n - 1
+	default:
+		return i
+	}
+}
+
+func (w *Window) Explain() ExplainInfo {
+	names := make([]string, len(w.funcs))
+	for i, spec := range w.funcs {
+		names[i] = spec.String()
+	}
+	return ExplainInfo{Name: "Window", Detail: strings.Join(names, ", "), Children: []*Executor{&w.InnerExecutor}}
+}
@@ -0,0 +1,156 @@
+package executor
+
+import (
+	"testing"
+
+	"github.com/ren-yamanashi/minesql/internal/storage/encode"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWindow(t *testing.T) {
+	// (category, price) のレコードが category, price の順に並んでいる
+	sortedRecords := func() []Record {
+		return []Record{
+			{[]byte("a"), encode.EncodeInt64(100)},
+			{[]byte("a"), encode.EncodeInt64(200)},
+			{[]byte("a"), encode.EncodeInt64(200)},
+			{[]byte("a"), encode.EncodeInt64(300)},
+			{[]byte("b"), encode.EncodeInt64(50)},
+			{[]byte("b"), nil},
+		}
+	}
+	orderByPrice := []SortKey{{Pos: 1}}
+
+	t.Run("パーティションごとに ROW_NUMBER・RANK・DENSE_RANK を計算する", func(t *testing.T) {
+		// GIVEN
+		inner := &mockExecutor{records: sortedRecords()[:5]}
+		window := NewWindow(inner, []int{0}, orderByPrice, []WindowFuncSpec{
+			{Func: WindowRowNumber},
+			{Func: WindowRank},
+			{Func: WindowDenseRank},
+		})
+
+		// WHEN
+		results := collectAll(t, window)
+
+		// THEN
+		assert.Equal(t, [][]any{
+			{"a", int64(100), int64(1), int64(1), int64(1)},
+			{"a", int64(200), int64(2), int64(2), int64(2)},
+			{"a", int64(200), int64(3), int64(2), int64(2)},
+			{"a", int64(300), int64(4), int64(4), int64(3)},
+			{"b", int64(50), int64(1), int64(1), int64(1)},
+		}, decodeAggregateResults(results, 1))
+	})
+
+	t.Run("LAG・LEAD はパーティション内の前後の行の値を返し、範囲外の場合はデフォルト値を返す", func(t *testing.T) {
+		// GIVEN
+		inner := &mockExecutor{records: sortedRecords()[:5]}
+		window := NewWindow(inner, []int{0}, orderByPrice, []WindowFuncSpec{
+			{Func: WindowLag, Pos: 1, Offset: 1},
+			{Func: WindowLead, Pos: 1, Offset: 2, Default: encode.EncodeInt64(0)},
+		})
+
+		// WHEN
+		results := collectAll(t, window)
+
+		// THEN
+		assert.Equal(t, [][]any{
+			{"a", int64(100), nil, int64(200)},
+			{"a", int64(200), int64(100), int64(300)},
+			{"a", int64(200), int64(200), int64(0)},
+			{"a", int64(300), int64(200), int64(0)},
+			{"b", int64(50), nil, int64(0)},
+		}, decodeAggregateResults(results, 1))
+	})
+
+	t.Run("フレームを省略した集約関数は、パーティションの先頭から現在行のピアまでを集約する", func(t *testing.T) {
+		// GIVEN
+		inner := &mockExecutor{records: sortedRecords()}
+		defaultFrame := WindowFrame{Start: FrameBound{Type: FrameUnboundedPreceding}, End: FrameBound{Type: FrameCurrentRow}, Peers: true}
+		window := NewWindow(inner, []int{0}, orderByPrice, []WindowFuncSpec{
+			{Func: WindowAggregate, Aggregate: AggregateSpec{Func: AggregateSum, Pos: 1}, Frame: defaultFrame},
+			{Func: WindowAggregate, Aggregate: AggregateSpec{Func: AggregateCount, Pos: 1}, Frame: defaultFrame},
+		})
+
+		// WHEN
+		results := collectAll(t, window)
+
+		// THEN: 同じ price の行 (ピア) は同じ累計になる
+		assert.Equal(t, [][]any{
+			{"a", int64(100), int64(100), int64(1)},
+			{"a", int64(200), int64(500), int64(3)},
+			{"a", int64(200), int64(500), int64(3)},
+			{"a", int64(300), int64(800), int64(4)},
+			{"b", int64(50), int64(50), int64(1)},
+			{"b", nil, int64(50), int64(1)},
+		}, decodeAggregateResults(results, 1))
+	})
+
+	t.Run("ORDER BY がない場合は、パーティション内のすべての行を集約する", func(t *testing.T) {
+		// GIVEN
+		inner := &mockExecutor{records: sortedRecords()}
+		defaultFrame := WindowFrame{Start: FrameBound{Type: FrameUnboundedPreceding}, End: FrameBound{Type: FrameCurrentRow}, Peers: true}
+		window := NewWindow(inner, []int{0}, nil, []WindowFuncSpec{
+			{Func: WindowAggregate, Aggregate: AggregateSpec{Func: AggregateMax, Pos: 1}, Frame: defaultFrame},
+			{Func: WindowRank},
+		})
+
+		// WHEN
+		results := collectAll(t, window)
+
+		// THEN
+		assert.Equal(t, [][]any{
+			{"a", int64(100), int64(300), int64(1)},
+			{"a", int64(200), int64(300), int64(1)},
+			{"a", int64(200), int64(300), int64(1)},
+			{"a", int64(300), int64(300), int64(1)},
+			{"b", int64(50), int64(50), int64(1)},
+			{"b", nil, int64(50), int64(1)},
+		}, decodeAggregateResults(results, 1))
+	})
+
+	t.Run("ROWS のフレームで前後の行数を指定して集約する", func(t *testing.T) {
+		// GIVEN
+		inner := &mockExecutor{records: sortedRecords()[:4]}
+		window := NewWindow(inner, nil, orderByPrice, []WindowFuncSpec{
+			{
+				Func:      WindowAggregate,
+				Aggregate: AggregateSpec{Func: AggregateSum, Pos: 1},
+				Frame:     WindowFrame{Start: FrameBound{Type: FramePreceding, Offset: 1}, End: FrameBound{Type: FrameFollowing, Offset: 1}},
+			},
+			{
+				Func:      WindowAggregate,
+				Aggregate: AggregateSpec{Func: AggregateCount, Pos: -1},
+				Frame:     WindowFrame{Start: FrameBound{Type: FrameUnboundedPreceding}, End: FrameBound{Type: FramePreceding, Offset: 1}},
+			},
+			{
+				Func:      WindowAggregate,
+				Aggregate: AggregateSpec{Func: AggregateMin, Pos: 1},
+				Frame:     WindowFrame{Start: FrameBound{Type: FrameFollowing, Offset: 1}, End: FrameBound{Type: FrameUnboundedFollowing}},
+			},
+		})
+
+		// WHEN
+		results := collectAll(t, window)
+
+		// THEN: フレームに行がない場合、COUNT は 0、それ以外は NULL になる
+		assert.Equal(t, [][]any{
+			{"a", int64(100), int64(300), int64(0), int64(200)},
+			{"a", int64(200), int64(500), int64(1), int64(200)},
+			{"a", int64(200), int64(700), int64(2), int64(300)},
+			{"a", int64(300), int64(500), int64(3), nil},
+		}, decodeAggregateResults(results, 1))
+	})
+
+	t.Run("レコードが 0 件の場合は何も返さない", func(t *testing.T) {
+		// GIVEN
+		window := NewWindow(&mockExecutor{}, nil, nil, []WindowFuncSpec{{Func: WindowRowNumber}})
+
+		// WHEN
+		results := collectAll(t, window)
+
+		// THEN
+		assert.Empty(t, results)
+	})
+}
@@ -0,0 +1,48 @@
+package executor
+
+// WorkingTable は再帰 CTE の作業テーブル (直前の反復で生成したレコード)
+//
+// RecursiveCTE が反復ごとに内容を入れ替え、再帰部分の WorkingTableScan が読み取る
+type WorkingTable struct {
+	records []Record
+}
+
+func NewWorkingTable() *WorkingTable {
+	return &WorkingTable{}
+}
+
+// Len は作業テーブルのレコード数を返す
+func (wt *WorkingTable) Len() int {
+	return len(wt.records)
+}
+
+// WorkingTableScan は再帰 CTE の作業テーブルのレコードを順に返す
+//
+// 作成した時点の作業テーブルの内容を読み取る (再帰部分の Executor は反復ごとに作成する)
+type WorkingTableScan struct {
+	name    string // 共通テーブル式の名前
+	records []Record
+	current int
+}
+
+func NewWorkingTableScan(name string, working *WorkingTable) *WorkingTableScan {
+	return &WorkingTableScan{
+		name:    name,
+		records: working.records,
+	}
+}
+
+func (ws *WorkingTableScan) Next() (Record, error) {
+	if ws.current >= len(ws.records) {
+		return nil, nil
+	}
+	record := ws.records[ws.current]
+	ws.current++
+	return record, nil
+}
+
+func (ws *WorkingTableScan) Close() {}
+
+func (ws *WorkingTableScan) Explain() ExplainInfo {
+	return ExplainInfo{Name: "WorkingTableScan", Detail: ws.name}
+}
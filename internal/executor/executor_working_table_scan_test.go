@@ -0,0 +1,48 @@
+package executor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorkingTableScan(t *testing.T) {
+	t.Run("作業テーブルのレコードを順に返す", func(t *testing.T) {
+		// GIVEN
+		working := NewWorkingTable()
+		working.records = []Record{{[]byte("1")}, {[]byte("2")}}
+		scan := NewWorkingTableScan("seq", working)
+
+		// WHEN
+		results := collectAll(t, scan)
+
+		// THEN
+		assert.Equal(t, []Record{{[]byte("1")}, {[]byte("2")}}, results)
+		assert.Equal(t, 2, working.Len())
+	})
+
+	t.Run("作成した時点の作業テーブルの内容を読み取る", func(t *testing.T) {
+		// GIVEN
+		working := NewWorkingTable()
+		working.records = []Record{{[]byte("1")}}
+		scan := NewWorkingTableScan("seq", working)
+
+		// WHEN: 作成後に作業テーブルを入れ替える
+		working.records = []Record{{[]byte("2")}, {[]byte("3")}}
+		results := collectAll(t, scan)
+
+		// THEN
+		assert.Equal(t, []Record{{[]byte("1")}}, results)
+	})
+
+	t.Run("空の作業テーブルの場合は何も返さない", func(t *testing.T) {
+		// GIVEN
+		scan := NewWorkingTableScan("seq", NewWorkingTable())
+
+		// WHEN
+		results := collectAll(t, scan)
+
+		// THEN
+		assert.Empty(t, results)
+	})
+}
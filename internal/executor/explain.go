@@ -0,0 +1,116 @@
+package executor
+
+import (
+	"time"
+)
+
+// ExplainInfo は EXPLAIN で表示する Executor のノードの情報
+type ExplainInfo struct {
+	Name     string      // Executor の種類 (e.g. TableScan)
+	Detail   string      // テーブル名・結合の種類など、Executor 固有の情報
+	Children []*Executor // 子の Executor への参照 (EXPLAIN ANALYZE では計測用の Executor に差し替える)
+
+	// 外側の行ごとに子の Executor を生成する場合 (Nested Loop Join の内部表) のファクトリ関数への参照
+	// 子は Children の後に並ぶ
+	Inner *func(Record) (Executor, error)
+	// EXPLAIN で Inner の代わりに表示する Executor (外側の行によらず同じ構造のため、代表として 1 つ保持する)
+	InnerPlan Executor
+}
+
+// Explainer は EXPLAIN で実行計画のノードとして表示できる Executor
+type Explainer interface {
+	Explain() ExplainInfo
+}
+
+// PlanNote はプランナーが選んだアクセス方法とその見積もり (EXPLAIN で表示する)
+type PlanNote struct {
+	Access string // アクセス方法 (e.g. "PK range", "index lookup")
+	// 見積もり行数とコストを返す (EXPLAIN の表示時に呼ぶ。nil の場合は見積もりなし)
+	//
+	// Nested Loop Join の内部表では、外側の 1 行あたりの見積もりを返す
+	Estimate func() (rows float64, cost float64, err error)
+}
+
+// planNoter は PlanNote を保持できる Executor
+type planNoter interface {
+	SetPlanNote(note PlanNote)
+	planNote() PlanNote
+}
+
+// explainNote は Executor に埋め込んで PlanNote を保持する
+type explainNote struct {
+	note PlanNote
+}
+
+// SetPlanNote はプランナーが選んだアクセス方法と見積もりを設定する
+func (n *explainNote) SetPlanNote(note PlanNote) {
+	n.note = note
+}
+
+func (n *explainNote) planNote() PlanNote {
+	return n.note
+}
+
+// analyzeStats は EXPLAIN ANALYZE で計測した Executor の実行結果
+//
+// 子のノードの計測結果は ExplainInfo の子 (Children, Inner) と同じ順に並ぶ
+// Nested Loop Join の内部表のように外側の行ごとに生成される Executor は、同じ位置の analyzeStats に合算する
+type analyzeStats struct {
+	rows     uint64        // 返した行数 (全ループの合計)
+	loops    uint64        // Executor を生成した回数
+	elapsed  time.Duration // Next にかかった時間の合計 (子の Executor の時間を含む)
+	children []*analyzeStats
+}
+
+// child は i 番目の子のノードの計測結果を返す (なければ作成する)
+func (s *analyzeStats) child(i int) *analyzeStats {
+	for len(s.children) <= i {
+		s.children = append(s.children, &analyzeStats{})
+	}
+	return s.children[i]
+}
+
+// analyzed は Executor の返した行数と時間を計測する
+type analyzed struct {
+	inner Executor
+	stats *analyzeStats
+}
+
+func (a *analyzed) Next() (Record, error) {
+	start := time.Now()
+	record, err := a.inner.Next()
+	a.stats.elapsed += time.Since(start)
+	if record != nil {
+		a.stats.rows++
+	}
+	return record, err
+}
+
+func (a *analyzed) Close() {
+	a.inner.Close()
+}
+
+// instrument は Executor のツリーの各ノードを、計測用の Executor で包んで返す
+//
+// 外側の行ごとに生成される子は、ファクトリ関数を生成のたびに計測用の Executor で包む関数に差し替える
+func instrument(exec Executor, stats *analyzeStats) Executor {
+	stats.loops++
+	if explainer, ok := exec.(Explainer); ok {
+		info := explainer.Explain()
+		for i, child := range info.Children {
+			*child = instrument(*child, stats.child(i))
+		}
+		if info.Inner != nil {
+			build := *info.Inner
+			innerStats := stats.child(len(info.Children))
+			*info.Inner = func(record Record) (Executor, error) {
+				inner, err := build(record)
+				if err != nil {
+					return nil, err
+				}
+				return instrument(inner, innerStats), nil
+			}
+		}
+	}
+	return &analyzed{inner: exec, stats: stats}
+}
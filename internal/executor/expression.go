@@ -0,0 +1,799 @@
+package executor
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+
+	"github.com/ren-yamanashi/minesql/internal/storage/encode"
+	"github.com/ren-yamanashi/minesql/internal/storage/handler"
+)
+
+// Expression はレコードから値を計算する式
+//
+// 値はカラムの格納形式 (handler.ColumnMetadata.ParseValue の結果と同じ形式) で扱い、NULL は nil で表す
+// 比較演算・論理演算の結果は BIGINT の 1 (真) または 0 (偽) で、UNKNOWN は NULL で表す
+//
+// 式の各ノードは被演算子の形式が揃っている前提で計算するため、データ型の解決 (スケールの調整、型変換の挿入) は planner で行う
+type Expression interface {
+	Eval(record Record) ([]byte, error)
+}
+
+var errValueOutOfRange = errors.New("numeric value is out of range")
+
+var (
+	trueValue  = encode.EncodeInt64(1)
+	falseValue = encode.EncodeInt64(0)
+)
+
+// boolValue は bool を条件式の値 (1 または 0) に変換する
+func boolValue(b bool) []byte {
+	if b {
+		return trueValue
+	}
+	return falseValue
+}
+
+// isTrue は条件式の値が真 (NULL でなく 0 でない数値) かどうかを判定する
+func isTrue(value []byte) bool {
+	return value != nil && encode.DecodeInt64(value) != 0
+}
+
+// -------------------------------------------------
+// カラム参照・定数
+// -------------------------------------------------
+
+// ColumnRef はレコード内のカラムの値を返す
+type ColumnRef struct {
+	Pos int // レコード内のカラム位置
+}
+
+func NewColumnRef(pos int) *ColumnRef {
+	return &ColumnRef{Pos: pos}
+}
+
+func (c *ColumnRef) Eval(record Record) ([]byte, error) {
+	return record[c.Pos], nil
+}
+
+// Constant は定数 (レコードに依存しない値) を返す
+type Constant struct {
+	Value []byte // 格納形式の値 (nil の場合は NULL)
+}
+
+func NewConstant(value []byte) *Constant {
+	return &Constant{Value: value}
+}
+
+func (c *Constant) Eval(_ Record) ([]byte, error) {
+	return c.Value, nil
+}
+
+// ConditionFunc は条件関数を Expression として扱うためのアダプタ (条件を満たす場合は 1、満たさない場合は 0 を返す)
+type ConditionFunc func(Record) bool
+
+func (f ConditionFunc) Eval(record Record) ([]byte, error) {
+	return boolValue(f(record)), nil
+}
+
+// -------------------------------------------------
+// 算術演算
+// -------------------------------------------------
+
+// Arithmetic は数値 (INT / BIGINT / DECIMAL) の算術演算 (+, -, *, /, %) を行う
+//
+// 被演算子は 10^scale 倍した整数として計算する。planner は事前に以下のように被演算子を揃えておく
+//   - +, -, %: 両辺のスケールを揃える (結果も同じスケール)
+//   - *: 両辺のスケールはそのまま (結果のスケールは両辺のスケールの和)
+//   - /: 左辺を 10^divShift 倍してから割る (結果のスケールは 左辺のスケール + divShift - 右辺のスケール)
+//
+// いずれかが NULL の場合と 0 で割った場合は NULL を返し、結果が int64 の範囲を超える場合はエラーを返す
+type Arithmetic struct {
+	operator string
+	left     Expression
+	right    Expression
+	divShift int
+}
+
+func NewArithmetic(operator string, left, right Expression) *Arithmetic {
+	return &Arithmetic{operator: operator, left: left, right: right}
+}
+
+// NewDivision は左辺を 10^divShift 倍してから割る除算を作成する (商は四捨五入する)
+func NewDivision(left, right Expression, divShift int) *Arithmetic {
+	return &Arithmetic{operator: "/", left: left, right: right, divShift: divShift}
+}
+
+func (a *Arithmetic) Eval(record Record) ([]byte, error) {
+	lv, err := a.left.Eval(record)
+	if err != nil || lv == nil {
+		return nil, err
+	}
+	rv, err := a.right.Eval(record)
+	if err != nil || rv == nil {
+		return nil, err
+	}
+	l := big.NewInt(encode.DecodeInt64(lv))
+	r := big.NewInt(encode.DecodeInt64(rv))
+
+	result := new(big.Int)
+	switch a.operator {
+	case "+":
+		result.Add(l, r)
+	case "-":
+		result.Sub(l, r)
+	case "*":
+		result.Mul(l, r)
+	case "/":
+		if r.Sign() == 0 {
+			return nil, nil
+		}
+		l.Mul(l, pow10(a.divShift))
+		result = roundedQuo(l, r)
+	case "%":
+		if r.Sign() == 0 {
+			return nil, nil
+		}
+		// 剰余の符号は左辺に合わせる (MySQL の MOD と同じ)
+		result.Rem(l, r)
+	default:
+		return nil, fmt.Errorf("unsupported arithmetic operator: %s", a.operator)
+	}
+
+	if !result.IsInt64() {
+		return nil, errValueOutOfRange
+	}
+	return encode.EncodeInt64(result.Int64()), nil
+}
+
+// roundedQuo は a / b を計算し、小数部を四捨五入 (0 から遠い方向に丸める) した商を返す
+func roundedQuo(a, b *big.Int) *big.Int {
+	q, m := new(big.Int).QuoRem(a, b, new(big.Int))
+	if m.Sign() == 0 {
+		return q
+	}
+	// |余り| * 2 >= |除数| なら切り上げる
+	if new(big.Int).Mul(new(big.Int).Abs(m), big.NewInt(2)).Cmp(new(big.Int).Abs(b)) >= 0 {
+		if (a.Sign() < 0) != (b.Sign() < 0) {
+			q.Sub(q, big.NewInt(1))
+		} else {
+			q.Add(q, big.NewInt(1))
+		}
+	}
+	return q
+}
+
+// Negate は数値の符号を反転する (NULL の場合は NULL を返す)
+type Negate struct {
+	operand Expression
+}
+
+func NewNegate(operand Expression) *Negate {
+	return &Negate{operand: operand}
+}
+
+func (n *Negate) Eval(record Record) ([]byte, error) {
+	v, err := n.operand.Eval(record)
+	if err != nil || v == nil {
+		return nil, err
+	}
+	i := encode.DecodeInt64(v)
+	if i == math.MinInt64 {
+		return nil, errValueOutOfRange
+	}
+	return encode.EncodeInt64(-i), nil
+}
+
+// -------------------------------------------------
+// 比較演算・論理演算
+// -------------------------------------------------
+
+// Compare は 2 つの値を比較する (=, !=, <, <=, >, >=)
+//
+// 両辺は同じデータ型の格納形式に揃えておく (格納形式はバイト列の辞書順と値の大小関係が一致する)
+// いずれかが NULL の場合は NULL (UNKNOWN) を返す
+type Compare struct {
+	operator string
+	left     Expression
+	right    Expression
+}
+
+func NewCompare(operator string, left, right Expression) *Compare {
+	return &Compare{operator: operator, left: left, right: right}
+}
+
+func (c *Compare) Eval(record Record) ([]byte, error) {
+	lv, err := c.left.Eval(record)
+	if err != nil || lv == nil {
+		return nil, err
+	}
+	rv, err := c.right.Eval(record)
+	if err != nil || rv == nil {
+		return nil, err
+	}
+
+	cmp := bytes.Compare(lv, rv)
+	switch c.operator {
+	case "=":
+		return boolValue(cmp == 0), nil
+	case "!=":
+		return boolValue(cmp != 0), nil
+	case "<":
+		return boolValue(cmp < 0), nil
+	case "<=":
+		return boolValue(cmp <= 0), nil
+	case ">":
+		return boolValue(cmp > 0), nil
+	case ">=":
+		return boolValue(cmp >= 0), nil
+	default:
+		return nil, fmt.Errorf("unsupported comparison operator: %s", c.operator)
+	}
+}
+
+// Logical は 2 つの条件を AND / OR で結合する
+//
+// 3 値論理に従い、AND は一方が偽なら偽、OR は一方が真なら真、それ以外で NULL (UNKNOWN) を含む場合は NULL を返す
+// 左辺で結果が確定する場合、右辺は評価しない
+type Logical struct {
+	operator string
+	left     Expression
+	right    Expression
+}
+
+func NewLogical(operator string, left, right Expression) *Logical {
+	return &Logical{operator: operator, left: left, right: right}
+}
+
+func (l *Logical) Eval(record Record) ([]byte, error) {
+	// AND は偽、OR は真で結果が確定する
+	var decisive bool
+	switch l.operator {
+	case "AND":
+		decisive = false
+	case "OR":
+		decisive = true
+	default:
+		return nil, fmt.Errorf("unsupported logical operator: %s", l.operator)
+	}
+
+	lv, err := l.left.Eval(record)
+	if err != nil {
+		return nil, err
+	}
+	if lv != nil && isTrue(lv) == decisive {
+		return boolValue(decisive), nil
+	}
+	rv, err := l.right.Eval(record)
+	if err != nil {
+		return nil, err
+	}
+	if rv != nil && isTrue(rv) == decisive {
+		return boolValue(decisive), nil
+	}
+	if lv == nil || rv == nil {
+		return nil, nil
+	}
+	return boolValue(!decisive), nil
+}
+
+// Not は条件を否定する (NOT)
+//
+// 条件が NULL (UNKNOWN) の場合は NULL を返す
+type Not struct {
+	operand Expression
+}
+
+func NewNot(operand Expression) *Not {
+	return &Not{operand: operand}
+}
+
+func (n *Not) Eval(record Record) ([]byte, error) {
+	v, err := n.operand.Eval(record)
+	if err != nil || v == nil {
+		return nil, err
+	}
+	return boolValue(!isTrue(v)), nil
+}
+
+// In は値がリストのいずれかと等しいかどうかを判定する (IN / NOT IN)
+//
+// 値とリストの各値は同じデータ型の格納形式に揃えておく
+// 値が NULL の場合と、一致する値がなくリストに NULL を含む場合は NULL (UNKNOWN) を返す
+type In struct {
+	operand Expression
+	values  []Expression
+	negate  bool // true なら NOT IN
+}
+
+func NewIn(operand Expression, values []Expression, negate bool) *In {
+	return &In{operand: operand, values: values, negate: negate}
+}
+
+func (in *In) Eval(record Record) ([]byte, error) {
+	v, err := in.operand.Eval(record)
+	if err != nil || v == nil {
+		return nil, err
+	}
+	hasNull := false
+	for _, value := range in.values {
+		x, err := value.Eval(record)
+		if err != nil {
+			return nil, err
+		}
+		if x == nil {
+			hasNull = true
+			continue
+		}
+		if bytes.Equal(v, x) {
+			return boolValue(!in.negate), nil
+		}
+	}
+	if hasNull {
+		return nil, nil
+	}
+	return boolValue(in.negate), nil
+}
+
+// Like は文字列がパターンに一致するかどうかを判定する (LIKE / NOT LIKE)
+//
+// パターンの "%" は 0 文字以上の任意の文字列、"_" は任意の 1 文字に一致し、"\" は直後の文字をエスケープする
+// 大文字・小文字は区別する (= と同じくバイト列として比較する)
+// いずれかが NULL の場合は NULL (UNKNOWN) を返す
+type Like struct {
+	operand Expression
+	pattern Expression
+	negate  bool // true なら NOT LIKE
+}
+
+func NewLike(operand, pattern Expression, negate bool) *Like {
+	return &Like{operand: operand, pattern: pattern, negate: negate}
+}
+
+func (l *Like) Eval(record Record) ([]byte, error) {
+	v, err := l.operand.Eval(record)
+	if err != nil || v == nil {
+		return nil, err
+	}
+	p, err := l.pattern.Eval(record)
+	if err != nil || p == nil {
+		return nil, err
+	}
+	return boolValue(matchLike([]rune(string(v)), []rune(string(p))) != l.negate), nil
+}
+
+// matchLike は文字列 s が LIKE のパターンに一致するかどうかを判定する
+//
+// "%" に一致させる文字数を最小から順に試し、以降が一致しなければ直前の "%" まで戻って 1 文字ずつ増やす
+func matchLike(s, pattern []rune) bool {
+	si, pi := 0, 0
+	percentPi, percentSi := -1, 0 // 直前の "%" の位置と、その "%" に一致させ始めた s の位置
+	for si < len(s) {
+		if pi < len(pattern) {
+			switch ch := pattern[pi]; ch {
+			case '%':
+				percentPi, percentSi = pi, si
+				pi++
+				continue
+			case '_':
+				si++
+				pi++
+				continue
+			default:
+				next := pi + 1
+				if ch == '\\' && next < len(pattern) {
+					ch = pattern[next]
+					next++
+				}
+				if s[si] == ch {
+					si++
+					pi = next
+					continue
+				}
+			}
+		}
+		// 一致しない場合は、直前の "%" に一致させる文字数を 1 つ増やしてやり直す
+		if percentPi < 0 {
+			return false
+		}
+		percentSi++
+		si, pi = percentSi, percentPi+1
+	}
+	// 残りのパターンは "%" のみであれば一致する
+	for pi < len(pattern) && pattern[pi] == '%' {
+		pi++
+	}
+	return pi == len(pattern)
+}
+
+// IsNull は値が NULL かどうかを判定する (IS NULL / IS NOT NULL)
+//
+// 結果は常に真か偽 (NULL にはならない)
+type IsNull struct {
+	operand Expression
+	negate  bool // true なら IS NOT NULL
+}
+
+func NewIsNull(operand Expression, negate bool) *IsNull {
+	return &IsNull{operand: operand, negate: negate}
+}
+
+func (n *IsNull) Eval(record Record) ([]byte, error) {
+	v, err := n.operand.Eval(record)
+	if err != nil {
+		return nil, err
+	}
+	return boolValue((v == nil) != n.negate), nil
+}
+
+// -------------------------------------------------
+// CASE 式
+// -------------------------------------------------
+
+// CaseWhen は CASE 式の WHEN 句 (条件と結果)
+type CaseWhen struct {
+	Condition Expression
+	Result    Expression
+}
+
+// Case は最初に真となる WHEN 句の結果を返す (該当しない場合は ELSE の結果、ELSE がない場合は NULL)
+//
+// 各 WHEN 句の結果と ELSE の結果は同じデータ型の格納形式に揃えておく
+type Case struct {
+	whens    []CaseWhen
+	elseExpr Expression
+}
+
+func NewCase(whens []CaseWhen, elseExpr Expression) *Case {
+	return &Case{whens: whens, elseExpr: elseExpr}
+}
+
+func (c *Case) Eval(record Record) ([]byte, error) {
+	for _, when := range c.whens {
+		cond, err := when.Condition.Eval(record)
+		if err != nil {
+			return nil, err
+		}
+		if isTrue(cond) {
+			return when.Result.Eval(record)
+		}
+	}
+	if c.elseExpr == nil {
+		return nil, nil
+	}
+	return c.elseExpr.Eval(record)
+}
+
+// -------------------------------------------------
+// サブクエリ
+// -------------------------------------------------
+
+// SubqueryPlan はサブクエリを実行する Executor を作成する
+//
+// record は外側のクエリのレコードで、相関サブクエリは外側のレコードの値を使って Executor を作成する
+type SubqueryPlan func(record Record) (Executor, error)
+
+// subqueryCache は相関のないサブクエリの結果を保持する
+//
+// 相関のないサブクエリは外側のレコードによらず結果が同じため、最初の評価時にだけ実行する
+type subqueryCache struct {
+	correlated bool // 相関サブクエリかどうか (true なら外側のレコードごとに実行する)
+	evaluated  bool // 結果を保持しているか
+}
+
+// needsEval はサブクエリを (再) 実行する必要があるかどうかを返す
+func (c *subqueryCache) needsEval() bool {
+	return c.correlated || !c.evaluated
+}
+
+// ScalarSubquery はスカラーサブクエリの結果 (1 行目の 1 カラム目の値) を返す
+//
+// サブクエリが 0 行の場合は NULL を返し、2 行以上の場合はエラーを返す
+type ScalarSubquery struct {
+	plan  SubqueryPlan
+	cache subqueryCache
+	value []byte
+}
+
+func NewScalarSubquery(plan SubqueryPlan, correlated bool) *ScalarSubquery {
+	return &ScalarSubquery{plan: plan, cache: subqueryCache{correlated: correlated}}
+}
+
+func (s *ScalarSubquery) Eval(record Record) ([]byte, error) {
+	if !s.cache.needsEval() {
+		return s.value, nil
+	}
+	exec, err := s.plan(record)
+	if err != nil {
+		return nil, err
+	}
+	defer exec.Close()
+	first, err := exec.Next()
+	if err != nil {
+		return nil, err
+	}
+	var value []byte
+	if first != nil {
+		second, err := exec.Next()
+		if err != nil {
+			return nil, err
+		}
+		if second != nil {
+			return nil, errors.New("subquery returns more than 1 row")
+		}
+		value = first[0]
+	}
+	s.value = value
+	s.cache.evaluated = true
+	return value, nil
+}
+
+// Exists はサブクエリが 1 行以上を返すかどうかを判定する (EXISTS)
+//
+// 結果は常に真か偽 (NULL にはならない)。NOT EXISTS は Not で否定する
+type Exists struct {
+	plan   SubqueryPlan
+	cache  subqueryCache
+	exists bool
+}
+
+func NewExists(plan SubqueryPlan, correlated bool) *Exists {
+	return &Exists{plan: plan, cache: subqueryCache{correlated: correlated}}
+}
+
+func (e *Exists) Eval(record Record) ([]byte, error) {
+	if !e.cache.needsEval() {
+		return boolValue(e.exists), nil
+	}
+	exec, err := e.plan(record)
+	if err != nil {
+		return nil, err
+	}
+	defer exec.Close()
+	first, err := exec.Next()
+	if err != nil {
+		return nil, err
+	}
+	e.exists = first != nil
+	e.cache.evaluated = true
+	return boolValue(e.exists), nil
+}
+
+// InSubquery は値がサブクエリの結果のいずれかと等しいかどうかを判定する (IN (SELECT ...) / NOT IN (SELECT ...))
+//
+// 値と、value でサブクエリの各行から取り出す値は同じデータ型の格納形式に揃えておく
+// NULL の扱いは In と同じで、サブクエリが 0 行の場合は値が NULL でも IN は偽 (NOT IN は真) を返す
+type InSubquery struct {
+	operand Expression
+	plan    SubqueryPlan
+	value   Expression // サブクエリの行から比較する値を取り出す式
+	negate  bool       // true なら NOT IN
+	cache   subqueryCache
+	values  map[string]struct{} // サブクエリの結果の値 (NULL を除く)
+	hasNull bool                // サブクエリの結果に NULL を含むか
+}
+
+func NewInSubquery(operand Expression, plan SubqueryPlan, value Expression, negate, correlated bool) *InSubquery {
+	return &InSubquery{
+		operand: operand,
+		plan:    plan,
+		value:   value,
+		negate:  negate,
+		cache:   subqueryCache{correlated: correlated},
+	}
+}
+
+func (in *InSubquery) Eval(record Record) ([]byte, error) {
+	if in.cache.needsEval() {
+		if err := in.load(record); err != nil {
+			return nil, err
+		}
+	}
+	if len(in.values) == 0 && !in.hasNull {
+		return boolValue(in.negate), nil
+	}
+	v, err := in.operand.Eval(record)
+	if err != nil || v == nil {
+		return nil, err
+	}
+	if _, ok := in.values[string(v)]; ok {
+		return boolValue(!in.negate), nil
+	}
+	if in.hasNull {
+		return nil, nil
+	}
+	return boolValue(in.negate), nil
+}
+
+// load はサブクエリを実行し、結果の値をハッシュセットに格納する
+func (in *InSubquery) load(record Record) error {
+	exec, err := in.plan(record)
+	if err != nil {
+		return err
+	}
+	defer exec.Close()
+	in.values = map[string]struct{}{}
+	in.hasNull = false
+	for {
+		row, err := exec.Next()
+		if err != nil {
+			return err
+		}
+		if row == nil {
+			break
+		}
+		x, err := in.value.Eval(row)
+		if err != nil {
+			return err
+		}
+		if x == nil {
+			in.hasNull = true
+			continue
+		}
+		in.values[string(x)] = struct{}{}
+	}
+	in.cache.evaluated = true
+	return nil
+}
+
+// -------------------------------------------------
+// 関数呼び出し
+// -------------------------------------------------
+
+// ScalarFunc はスカラー関数の種類を表す
+type ScalarFunc uint8
+
+const (
+	FuncAbs      ScalarFunc = iota // ABS(x): 数値の絶対値
+	FuncUpper                      // UPPER(s): 文字列を大文字に変換
+	FuncLower                      // LOWER(s): 文字列を小文字に変換
+	FuncLength                     // LENGTH(s): 文字列のバイト長 (BIGINT)
+	FuncConcat                     // CONCAT(s, ...): 文字列の連結 (いずれかが NULL なら NULL)
+	FuncCoalesce                   // COALESCE(x, ...): 最初の NULL でない値
+)
+
+// Function はスカラー関数を呼び出す
+//
+// 引数は関数が受け付けるデータ型の格納形式に揃えておく (CONCAT の引数は文字列、COALESCE の引数は同じデータ型など)
+type Function struct {
+	fn   ScalarFunc
+	args []Expression
+}
+
+func NewFunction(fn ScalarFunc, args []Expression) *Function {
+	return &Function{fn: fn, args: args}
+}
+
+func (f *Function) Eval(record Record) ([]byte, error) {
+	// COALESCE は NULL でない値が見つかるまで順に評価する
+	if f.fn == FuncCoalesce {
+		for _, arg := range f.args {
+			v, err := arg.Eval(record)
+			if err != nil || v != nil {
+				return v, err
+			}
+		}
+		return nil, nil
+	}
+
+	args := make([][]byte, len(f.args))
+	for i, arg := range f.args {
+		v, err := arg.Eval(record)
+		if err != nil {
+			return nil, err
+		}
+		// COALESCE 以外は引数に NULL を含む場合 NULL を返す
+		if v == nil {
+			return nil, nil
+		}
+		args[i] = v
+	}
+
+	switch f.fn {
+	case FuncAbs:
+		i := encode.DecodeInt64(args[0])
+		if i == math.MinInt64 {
+			return nil, errValueOutOfRange
+		}
+		if i < 0 {
+			i = -i
+		}
+		return encode.EncodeInt64(i), nil
+	case FuncUpper:
+		return []byte(strings.ToUpper(string(args[0]))), nil
+	case FuncLower:
+		return []byte(strings.ToLower(string(args[0]))), nil
+	case FuncLength:
+		return encode.EncodeInt64(int64(len(args[0]))), nil
+	case FuncConcat:
+		return bytes.Join(args, nil), nil
+	default:
+		return nil, fmt.Errorf("unsupported function: %d", f.fn)
+	}
+}
+
+// -------------------------------------------------
+// 型変換
+// -------------------------------------------------
+
+// Cast は値をあるデータ型 (from) から別のデータ型 (to) の格納形式に変換する
+//
+//   - 数値 → 数値: スケールを揃え (小数部を減らす場合は四捨五入)、to の範囲 (INT の範囲、DECIMAL の精度) を確認する
+//   - DATETIME → DATE: 時刻部分を切り捨てる
+//   - それ以外: from のテキスト表現を to の格納形式に変換する (e.g. 数値 → 文字列、文字列 → 数値)
+type Cast struct {
+	operand Expression
+	from    *handler.ColumnMetadata
+	to      *handler.ColumnMetadata
+}
+
+func NewCast(operand Expression, from, to *handler.ColumnMetadata) *Cast {
+	return &Cast{operand: operand, from: from, to: to}
+}
+
+func (c *Cast) Eval(record Record) ([]byte, error) {
+	v, err := c.operand.Eval(record)
+	if err != nil || v == nil {
+		return nil, err
+	}
+
+	switch {
+	case isNumericType(c.from.Type) && isNumericType(c.to.Type):
+		return c.castNumeric(encode.DecodeInt64(v))
+	case c.from.Type == handler.ColumnTypeDatetime && c.to.Type == handler.ColumnTypeDate:
+		// UNIX 時間 (秒) を日の境界に切り捨てる
+		sec := encode.DecodeInt64(v)
+		day := sec - sec%86400
+		if sec%86400 < 0 {
+			day -= 86400
+		}
+		return encode.EncodeInt64(day), nil
+	case c.from.Type == handler.ColumnTypeDate && c.to.Type == handler.ColumnTypeDatetime:
+		return v, nil
+	case c.from.Type == c.to.Type && !c.to.Type.IsFixedSize():
+		return v, nil
+	default:
+		return c.to.ParseValue(c.from.FormatValue(v))
+	}
+}
+
+// castNumeric は 10^from.Scale 倍された整数を、to のスケールの整数に変換する
+func (c *Cast) castNumeric(v int64) ([]byte, error) {
+	n := big.NewInt(v)
+	switch {
+	case c.to.Scale > c.from.Scale:
+		n.Mul(n, pow10(int(c.to.Scale-c.from.Scale)))
+	case c.to.Scale < c.from.Scale:
+		n = roundedQuo(n, pow10(int(c.from.Scale-c.to.Scale)))
+	}
+
+	outOfRange := !n.IsInt64()
+	if !outOfRange {
+		switch c.to.Type {
+		case handler.ColumnTypeInt:
+			outOfRange = n.Int64() < math.MinInt32 || n.Int64() > math.MaxInt32
+		case handler.ColumnTypeDecimal:
+			outOfRange = c.to.Precision > 0 && new(big.Int).Abs(n).Cmp(pow10(int(c.to.Precision))) >= 0
+		}
+	}
+	if outOfRange {
+		if c.to.Name == "" {
+			return nil, errValueOutOfRange
+		}
+		return nil, fmt.Errorf("out of range value for column '%s'", c.to.Name)
+	}
+	return encode.EncodeInt64(n.Int64()), nil
+}
+
+// isNumericType は数値型 (INT / BIGINT / DECIMAL) かどうかを返す
+func isNumericType(t handler.ColumnType) bool {
+	return t == handler.ColumnTypeInt || t == handler.ColumnTypeBigInt || t == handler.ColumnTypeDecimal
+}
+
+// pow10 は 10^n を返す
+func pow10(n int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
@@ -0,0 +1,548 @@
+package executor
+
+import (
+	"math"
+	"testing"
+
+	"github.com/ren-yamanashi/minesql/internal/storage/encode"
+	"github.com/ren-yamanashi/minesql/internal/storage/handler"
+	"github.com/stretchr/testify/assert"
+)
+
+// intConst は BIGINT の定数を作成する
+func intConst(v int64) *Constant {
+	return NewConstant(encode.EncodeInt64(v))
+}
+
+// nullConst は NULL の定数を作成する
+func nullConst() *Constant {
+	return NewConstant(nil)
+}
+
+func TestArithmetic(t *testing.T) {
+	tests := []struct {
+		name     string
+		operator string
+		left     int64
+		right    int64
+		expected int64
+	}{
+		{"加算", "+", 3, 4, 7},
+		{"減算", "-", 3, 4, -1},
+		{"乗算", "*", 3, 4, 12},
+		{"剰余の符号は左辺に合わせる", "%", -7, 3, -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// GIVEN
+			expr := NewArithmetic(tt.operator, intConst(tt.left), intConst(tt.right))
+
+			// WHEN
+			result, err := expr.Eval(nil)
+
+			// THEN
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, encode.DecodeInt64(result))
+		})
+	}
+
+	t.Run("除算は左辺を 10^divShift 倍してから割り、商を四捨五入する", func(t *testing.T) {
+		// GIVEN: 2 / 3 をスケール 4 で計算する
+		expr := NewDivision(intConst(2), intConst(3), 4)
+
+		// WHEN
+		result, err := expr.Eval(nil)
+
+		// THEN
+		assert.NoError(t, err)
+		assert.Equal(t, int64(6667), encode.DecodeInt64(result))
+	})
+
+	t.Run("0 で割った場合は NULL を返す", func(t *testing.T) {
+		for _, expr := range []Expression{
+			NewDivision(intConst(1), intConst(0), 4),
+			NewArithmetic("%", intConst(1), intConst(0)),
+		} {
+			// WHEN
+			result, err := expr.Eval(nil)
+
+			// THEN
+			assert.NoError(t, err)
+			assert.Nil(t, result)
+		}
+	})
+
+	t.Run("被演算子に NULL を含む場合は NULL を返す", func(t *testing.T) {
+		// GIVEN
+		expr := NewArithmetic("+", intConst(1), nullConst())
+
+		// WHEN
+		result, err := expr.Eval(nil)
+
+		// THEN
+		assert.NoError(t, err)
+		assert.Nil(t, result)
+	})
+
+	t.Run("結果が BIGINT の範囲を超える場合はエラーを返す", func(t *testing.T) {
+		// GIVEN
+		expr := NewArithmetic("+", intConst(math.MaxInt64), intConst(1))
+
+		// WHEN
+		_, err := expr.Eval(nil)
+
+		// THEN
+		assert.ErrorContains(t, err, "numeric value is out of range")
+	})
+}
+
+func TestNegate(t *testing.T) {
+	t.Run("符号を反転する", func(t *testing.T) {
+		// WHEN
+		result, err := NewNegate(intConst(5)).Eval(nil)
+
+		// THEN
+		assert.NoError(t, err)
+		assert.Equal(t, int64(-5), encode.DecodeInt64(result))
+	})
+
+	t.Run("BIGINT の最小値を反転する場合はエラーを返す", func(t *testing.T) {
+		// WHEN
+		_, err := NewNegate(intConst(math.MinInt64)).Eval(nil)
+
+		// THEN
+		assert.ErrorContains(t, err, "numeric value is out of range")
+	})
+}
+
+func TestCompare(t *testing.T) {
+	t.Run("カラム同士を比較できる", func(t *testing.T) {
+		// GIVEN
+		record := Record{encode.EncodeInt64(-1), encode.EncodeInt64(2)}
+		expr := NewCompare("<", NewColumnRef(0), NewColumnRef(1))
+
+		// WHEN
+		result, err := expr.Eval(record)
+
+		// THEN
+		assert.NoError(t, err)
+		assert.True(t, isTrue(result))
+	})
+
+	t.Run("いずれかが NULL の場合は NULL (UNKNOWN) を返す", func(t *testing.T) {
+		// GIVEN
+		expr := NewCompare("=", nullConst(), intConst(1))
+
+		// WHEN
+		result, err := expr.Eval(nil)
+
+		// THEN
+		assert.NoError(t, err)
+		assert.Nil(t, result)
+	})
+}
+
+func TestLogical(t *testing.T) {
+	tests := []struct {
+		name     string
+		operator string
+		left     Expression
+		right    Expression
+		expected []byte
+	}{
+		{"AND: 一方が偽なら NULL を含んでいても偽", "AND", nullConst(), intConst(0), falseValue},
+		{"AND: 真と NULL は NULL", "AND", intConst(1), nullConst(), nil},
+		{"OR: 一方が真なら NULL を含んでいても真", "OR", nullConst(), intConst(1), trueValue},
+		{"OR: 偽と NULL は NULL", "OR", intConst(0), nullConst(), nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// GIVEN
+			expr := NewLogical(tt.operator, tt.left, tt.right)
+
+			// WHEN
+			result, err := expr.Eval(nil)
+
+			// THEN
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+
+	t.Run("左辺で結果が確定する場合、右辺は評価しない", func(t *testing.T) {
+		// GIVEN: 右辺を評価するとエラーになる
+		right := NewNegate(intConst(math.MinInt64))
+		expr := NewLogical("OR", intConst(1), right)
+
+		// WHEN
+		result, err := expr.Eval(nil)
+
+		// THEN
+		assert.NoError(t, err)
+		assert.Equal(t, trueValue, result)
+	})
+}
+
+func TestIsNull(t *testing.T) {
+	t.Run("IS NULL / IS NOT NULL を判定できる", func(t *testing.T) {
+		// WHEN
+		isNull, err1 := NewIsNull(nullConst(), false).Eval(nil)
+		isNotNull, err2 := NewIsNull(nullConst(), true).Eval(nil)
+
+		// THEN
+		assert.NoError(t, err1)
+		assert.NoError(t, err2)
+		assert.Equal(t, trueValue, isNull)
+		assert.Equal(t, falseValue, isNotNull)
+	})
+}
+
+func TestNot(t *testing.T) {
+	tests := []struct {
+		name     string
+		operand  Expression
+		expected []byte
+	}{
+		{"真を偽にする", intConst(1), falseValue},
+		{"偽を真にする", intConst(0), trueValue},
+		{"NULL は NULL のまま", nullConst(), nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// WHEN
+			result, err := NewNot(tt.operand).Eval(nil)
+
+			// THEN
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestIn(t *testing.T) {
+	tests := []struct {
+		name     string
+		operand  Expression
+		values   []Expression
+		negate   bool
+		expected []byte
+	}{
+		{"いずれかと等しい場合は真", intConst(2), []Expression{intConst(1), intConst(2)}, false, trueValue},
+		{"いずれとも等しくない場合は偽", intConst(3), []Expression{intConst(1), intConst(2)}, false, falseValue},
+		{"一致する値がなくリストに NULL を含む場合は NULL", intConst(3), []Expression{intConst(1), nullConst()}, false, nil},
+		{"一致する値があればリストに NULL を含んでいても真", intConst(1), []Expression{nullConst(), intConst(1)}, false, trueValue},
+		{"値が NULL の場合は NULL", nullConst(), []Expression{intConst(1)}, false, nil},
+		{"NOT IN: いずれとも等しくない場合は真", intConst(3), []Expression{intConst(1), intConst(2)}, true, trueValue},
+		{"NOT IN: リストに NULL を含む場合は NULL", intConst(3), []Expression{intConst(1), nullConst()}, true, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// WHEN
+			result, err := NewIn(tt.operand, tt.values, tt.negate).Eval(nil)
+
+			// THEN
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestLike(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		pattern  string
+		expected bool
+	}{
+		{"前方一致", "apple", "app%", true},
+		{"後方一致", "apple", "%ple", true},
+		{"部分一致", "apple", "%pp%", true},
+		{"_ は任意の 1 文字に一致する", "apple", "a_ple", true},
+		{"_ は 0 文字には一致しない", "aple", "a_ple", false},
+		{"% は 0 文字にも一致する", "apple", "apple%", true},
+		{"% を複数含む", "banana", "b%n%a", true},
+		{"パターン全体に一致しなければ偽", "apple", "app", false},
+		{"大文字・小文字を区別する", "Apple", "app%", false},
+		{"マルチバイト文字を 1 文字として扱う", "りんご", "り_ご", true},
+		{"エスケープした % は文字として比較する", "100%", "100\\%", true},
+		{"エスケープした _ は文字として比較する", "a_b", "a\\_b", true},
+		{"エスケープした _ は他の文字に一致しない", "axb", "a\\_b", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// GIVEN
+			expr := NewLike(NewConstant([]byte(tt.value)), NewConstant([]byte(tt.pattern)), false)
+
+			// WHEN
+			result, err := expr.Eval(nil)
+
+			// THEN
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, isTrue(result))
+		})
+	}
+
+	t.Run("NOT LIKE は結果を反転する", func(t *testing.T) {
+		// GIVEN
+		expr := NewLike(NewConstant([]byte("apple")), NewConstant([]byte("b%")), true)
+
+		// WHEN
+		result, err := expr.Eval(nil)
+
+		// THEN
+		assert.NoError(t, err)
+		assert.Equal(t, trueValue, result)
+	})
+
+	t.Run("いずれかが NULL の場合は NULL を返す", func(t *testing.T) {
+		for _, expr := range []Expression{
+			NewLike(nullConst(), NewConstant([]byte("a%")), false),
+			NewLike(NewConstant([]byte("a")), nullConst(), true),
+		} {
+			// WHEN
+			result, err := expr.Eval(nil)
+
+			// THEN
+			assert.NoError(t, err)
+			assert.Nil(t, result)
+		}
+	})
+}
+
+func TestCase(t *testing.T) {
+	whens := []CaseWhen{
+		{Condition: NewCompare("<", NewColumnRef(0), intConst(0)), Result: NewConstant([]byte("negative"))},
+		{Condition: NewCompare("=", NewColumnRef(0), intConst(0)), Result: NewConstant([]byte("zero"))},
+	}
+
+	t.Run("最初に真となる WHEN 句の結果を返す", func(t *testing.T) {
+		// GIVEN
+		expr := NewCase(whens, NewConstant([]byte("positive")))
+
+		// WHEN
+		result, err := expr.Eval(Record{encode.EncodeInt64(0)})
+
+		// THEN
+		assert.NoError(t, err)
+		assert.Equal(t, "zero", string(result))
+	})
+
+	t.Run("該当する WHEN 句がない場合は ELSE の結果を返す", func(t *testing.T) {
+		// GIVEN
+		expr := NewCase(whens, NewConstant([]byte("positive")))
+
+		// WHEN
+		result, err := expr.Eval(Record{encode.EncodeInt64(3)})
+
+		// THEN
+		assert.NoError(t, err)
+		assert.Equal(t, "positive", string(result))
+	})
+
+	t.Run("条件が NULL の WHEN 句は該当しない。ELSE がない場合は NULL を返す", func(t *testing.T) {
+		// GIVEN
+		expr := NewCase(whens, nil)
+
+		// WHEN
+		result, err := expr.Eval(Record{nil})
+
+		// THEN
+		assert.NoError(t, err)
+		assert.Nil(t, result)
+	})
+}
+
+// subqueryOf は records を返すサブクエリを作成し、実行回数を count に記録する
+func subqueryOf(count *int, records ...Record) SubqueryPlan {
+	return func(_ Record) (Executor, error) {
+		*count++
+		return &mockExecutor{records: records}, nil
+	}
+}
+
+func TestScalarSubquery(t *testing.T) {
+	t.Run("1 行目の 1 カラム目の値を返し、相関がない場合は 1 回だけ実行する", func(t *testing.T) {
+		// GIVEN
+		count := 0
+		expr := NewScalarSubquery(subqueryOf(&count, Record{encode.EncodeInt64(5)}), false)
+
+		// WHEN
+		first, err1 := expr.Eval(Record{})
+		second, err2 := expr.Eval(Record{})
+
+		// THEN
+		assert.NoError(t, err1)
+		assert.NoError(t, err2)
+		assert.Equal(t, encode.EncodeInt64(5), first)
+		assert.Equal(t, encode.EncodeInt64(5), second)
+		assert.Equal(t, 1, count)
+	})
+
+	t.Run("相関サブクエリは外側のレコードごとに実行する", func(t *testing.T) {
+		// GIVEN: 外側のレコードの値をそのまま返すサブクエリ
+		expr := NewScalarSubquery(func(record Record) (Executor, error) {
+			return &mockExecutor{records: []Record{{record[0]}}}, nil
+		}, true)
+
+		// WHEN
+		first, err1 := expr.Eval(Record{encode.EncodeInt64(1)})
+		second, err2 := expr.Eval(Record{encode.EncodeInt64(2)})
+
+		// THEN
+		assert.NoError(t, err1)
+		assert.NoError(t, err2)
+		assert.Equal(t, encode.EncodeInt64(1), first)
+		assert.Equal(t, encode.EncodeInt64(2), second)
+	})
+
+	t.Run("0 行の場合は NULL を返す", func(t *testing.T) {
+		// GIVEN
+		count := 0
+		expr := NewScalarSubquery(subqueryOf(&count), false)
+
+		// WHEN
+		result, err := expr.Eval(Record{})
+
+		// THEN
+		assert.NoError(t, err)
+		assert.Nil(t, result)
+	})
+
+	t.Run("2 行以上の場合はエラーを返す", func(t *testing.T) {
+		// GIVEN
+		count := 0
+		expr := NewScalarSubquery(subqueryOf(&count, Record{intConst(1).Value}, Record{intConst(2).Value}), false)
+
+		// WHEN
+		_, err := expr.Eval(Record{})
+
+		// THEN
+		assert.EqualError(t, err, "subquery returns more than 1 row")
+	})
+}
+
+func TestExists(t *testing.T) {
+	t.Run("サブクエリが 1 行以上を返す場合は真、0 行の場合は偽を返す", func(t *testing.T) {
+		// GIVEN
+		count := 0
+		exists := NewExists(subqueryOf(&count, Record{nil}), false)
+		notExists := NewExists(subqueryOf(&count), false)
+
+		// WHEN
+		result1, err1 := exists.Eval(Record{})
+		result2, err2 := notExists.Eval(Record{})
+
+		// THEN: 結果が NULL だけの行も 1 行として数える
+		assert.NoError(t, err1)
+		assert.NoError(t, err2)
+		assert.Equal(t, trueValue, result1)
+		assert.Equal(t, falseValue, result2)
+	})
+}
+
+func TestInSubquery(t *testing.T) {
+	tests := []struct {
+		name     string
+		operand  Expression
+		records  []Record
+		negate   bool
+		expected []byte
+	}{
+		{"いずれかと等しい場合は真", intConst(2), []Record{{intConst(1).Value}, {intConst(2).Value}}, false, trueValue},
+		{"いずれとも等しくない場合は偽", intConst(3), []Record{{intConst(1).Value}, {intConst(2).Value}}, false, falseValue},
+		{"一致する値がなく結果に NULL を含む場合は NULL", intConst(3), []Record{{intConst(1).Value}, {nil}}, false, nil},
+		{"値が NULL の場合は NULL", nullConst(), []Record{{intConst(1).Value}}, false, nil},
+		{"サブクエリが 0 行の場合は値が NULL でも偽", nullConst(), nil, false, falseValue},
+		{"NOT IN: いずれとも等しくない場合は真", intConst(3), []Record{{intConst(1).Value}}, true, trueValue},
+		{"NOT IN: 結果に NULL を含む場合は NULL", intConst(3), []Record{{intConst(1).Value}, {nil}}, true, nil},
+		{"NOT IN: サブクエリが 0 行の場合は値が NULL でも真", nullConst(), nil, true, trueValue},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// GIVEN
+			count := 0
+			expr := NewInSubquery(tt.operand, subqueryOf(&count, tt.records...), NewColumnRef(0), tt.negate, false)
+
+			// WHEN
+			result, err := expr.Eval(Record{})
+
+			// THEN
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestFunction(t *testing.T) {
+	tests := []struct {
+		name     string
+		fn       ScalarFunc
+		args     []Expression
+		expected []byte
+	}{
+		{"ABS", FuncAbs, []Expression{intConst(-3)}, encode.EncodeInt64(3)},
+		{"UPPER", FuncUpper, []Expression{NewConstant([]byte("abc"))}, []byte("ABC")},
+		{"LOWER", FuncLower, []Expression{NewConstant([]byte("ABC"))}, []byte("abc")},
+		{"LENGTH", FuncLength, []Expression{NewConstant([]byte("abc"))}, encode.EncodeInt64(3)},
+		{"CONCAT", FuncConcat, []Expression{NewConstant([]byte("a")), NewConstant([]byte("b"))}, []byte("ab")},
+		{"CONCAT は引数に NULL を含む場合 NULL を返す", FuncConcat, []Expression{NewConstant([]byte("a")), nullConst()}, nil},
+		{"COALESCE は最初の NULL でない値を返す", FuncCoalesce, []Expression{nullConst(), intConst(2), intConst(3)}, encode.EncodeInt64(2)},
+		{"COALESCE はすべて NULL の場合 NULL を返す", FuncCoalesce, []Expression{nullConst(), nullConst()}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// WHEN
+			result, err := NewFunction(tt.fn, tt.args).Eval(nil)
+
+			// THEN
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestCast(t *testing.T) {
+	bigint := &handler.ColumnMetadata{Type: handler.ColumnTypeBigInt}
+	decimal := &handler.ColumnMetadata{Type: handler.ColumnTypeDecimal, Precision: 5, Scale: 2}
+
+	t.Run("数値のスケールを揃える", func(t *testing.T) {
+		// WHEN
+		result, err := NewCast(intConst(3), bigint, decimal).Eval(nil)
+
+		// THEN
+		assert.NoError(t, err)
+		assert.Equal(t, int64(300), encode.DecodeInt64(result))
+	})
+
+	t.Run("小数部を減らす場合は四捨五入する", func(t *testing.T) {
+		// WHEN: 1.25 → BIGINT
+		result, err := NewCast(intConst(125), decimal, bigint).Eval(nil)
+
+		// THEN
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), encode.DecodeInt64(result))
+	})
+
+	t.Run("数値を文字列に変換する", func(t *testing.T) {
+		// GIVEN
+		str := &handler.ColumnMetadata{Type: handler.ColumnTypeString}
+
+		// WHEN
+		result, err := NewCast(intConst(125), decimal, str).Eval(nil)
+
+		// THEN
+		assert.NoError(t, err)
+		assert.Equal(t, "1.25", string(result))
+	})
+
+	t.Run("変換先のカラムの範囲を超える場合、カラム名を含むエラーを返す", func(t *testing.T) {
+		// GIVEN
+		to := &handler.ColumnMetadata{Name: "price", Type: handler.ColumnTypeDecimal, Precision: 5, Scale: 2}
+
+		// WHEN
+		_, err := NewCast(intConst(1000), bigint, to).Eval(nil)
+
+		// THEN
+		assert.ErrorContains(t, err, "out of range value for column 'price'")
+	})
+}
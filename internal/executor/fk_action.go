@@ -0,0 +1,270 @@
+package executor
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/ren-yamanashi/minesql/internal/storage/access"
+	"github.com/ren-yamanashi/minesql/internal/storage/btree"
+	"github.com/ren-yamanashi/minesql/internal/storage/dictionary"
+	"github.com/ren-yamanashi/minesql/internal/storage/handler"
+	"github.com/ren-yamanashi/minesql/internal/storage/lock"
+)
+
+// maxFKCascadeDepth は参照アクション (CASCADE / SET NULL) を連鎖して適用できる深さの上限 (MySQL と同じ)
+const maxFKCascadeDepth = 15
+
+var errFKCascadeDepth = fmt.Errorf("foreign key cascade delete/update exceeds max depth of %d", maxFKCascadeDepth)
+
+// deleteRow は行を削除し、参照元テーブルの行に ON DELETE の参照アクションを適用する
+//
+// 呼び出し側で行の排他ロックを取得し、record に最新の行を渡す必要がある。
+//
+//  1. RESTRICT の FK で参照されていないことを確認する
+//  2. 行を削除する
+//  3. CASCADE の FK で参照している行を削除し、SET NULL の FK で参照している行の FK カラムを NULL に更新する
+//
+// 参照元の行より先に行を削除するため、循環する参照でも削除済みの行を再び処理することはない
+func deleteRow(hdl *handler.Handler, trxId handler.TrxId, table *access.Table, record Record, depth int) error {
+	if depth > maxFKCascadeDepth {
+		return errFKCascadeDepth
+	}
+
+	tableMeta, _ := hdl.Catalog.GetTableMetaByName(table.Name)
+	refFKs := hdl.Catalog.GetForeignKeysReferencingTable(table.Name)
+
+	// 1. RESTRICT の FK で参照されていないことを確認
+	for _, childFK := range refFKs {
+		if childFK.Constraint.OnDelete != dictionary.ReferentialActionRestrict {
+			continue
+		}
+		refValues, ok := getRefValues(tableMeta, childFK, record)
+		if !ok {
+			continue
+		}
+		if childFK.TableName != table.Name {
+			if err := checkChildRecordExists(hdl.BufferPool, trxId, hdl, childFK, refValues); err != nil {
+				return err
+			}
+			continue
+		}
+
+		// 自己参照の場合、自分自身からの参照は除く
+		children, err := findChildRecords(hdl, table, childFK.Constraint.ColNames, refValues)
+		if err != nil {
+			return err
+		}
+		encodedKey := table.EncodeKey(record)
+		for _, child := range children {
+			if !bytes.Equal(table.EncodeKey(child), encodedKey) {
+				return errFKDeleteRestrict
+			}
+		}
+	}
+
+	// 2. 行を削除
+	if err := table.SoftDelete(hdl.BufferPool, trxId, hdl.LockMgr, record); err != nil {
+		return err
+	}
+
+	// 3. CASCADE / SET NULL の FK で参照している行に参照アクションを適用
+	for _, childFK := range refFKs {
+		action := childFK.Constraint.OnDelete
+		if action == dictionary.ReferentialActionRestrict {
+			continue
+		}
+		refValues, ok := getRefValues(tableMeta, childFK, record)
+		if !ok {
+			continue
+		}
+		err := applyFKAction(hdl, trxId, childFK, refValues, func(childTable *access.Table, child Record, positions []uint16) error {
+			if action == dictionary.ReferentialActionCascade {
+				return deleteRow(hdl, trxId, childTable, child, depth+1)
+			}
+			newChild := make(Record, len(child))
+			copy(newChild, child)
+			for _, pos := range positions {
+				newChild[pos] = nil
+			}
+			return updateRow(hdl, trxId, childTable, child, newChild, depth+1)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// updateRow は行を更新し、参照元テーブルの行に ON UPDATE の参照アクションを適用する
+//
+// FK 制約のあるテーブルでは、呼び出し側で行の排他ロックを取得し、oldRecord に最新の行を渡す必要がある。
+//
+//  1. RESTRICT の FK で旧値が参照されていないこと、FK カラムの新しい値が参照先に存在することを確認する
+//  2. 行を更新する (プライマリキーが変わる場合はソフトデリート + Insert)
+//  3. CASCADE の FK で旧値を参照している行の FK カラムを新しい値に、SET NULL の FK で参照している行の FK カラム (複合外部キーの場合はすべてのカラム) を NULL に更新する
+func updateRow(hdl *handler.Handler, trxId handler.TrxId, table *access.Table, oldRecord Record, newRecord Record, depth int) error {
+	if depth > maxFKCascadeDepth {
+		return errFKCascadeDepth
+	}
+
+	tableMeta, _ := hdl.Catalog.GetTableMetaByName(table.Name)
+	fks := tableMeta.GetForeignKeyConstraints()
+	refFKs := hdl.Catalog.GetForeignKeysReferencingTable(table.Name)
+
+	// 1. RESTRICT の FK と、FK カラムの新しい値を確認
+	var restrictFKs []dictionary.ChildForeignKey
+	for _, childFK := range refFKs {
+		if childFK.Constraint.OnUpdate == dictionary.ReferentialActionRestrict {
+			restrictFKs = append(restrictFKs, childFK)
+		}
+	}
+	if err := checkFKOnUpdate(hdl.BufferPool, trxId, hdl.LockMgr, tableMeta, restrictFKs, fks, oldRecord, newRecord); err != nil {
+		return err
+	}
+
+	// 2. 行を更新
+	if bytes.Equal(table.EncodeKey(oldRecord), table.EncodeKey(newRecord)) {
+		// プライマリキーが変わらない場合はインプレース更新
+		if err := table.UpdateInplace(hdl.BufferPool, trxId, hdl.LockMgr, oldRecord, newRecord); err != nil {
+			return err
+		}
+	} else {
+		// プライマリキーが変わる場合はソフトデリート + Insert
+		if err := table.SoftDelete(hdl.BufferPool, trxId, hdl.LockMgr, oldRecord); err != nil {
+			return err
+		}
+		if err := table.Insert(hdl.BufferPool, trxId, hdl.LockMgr, newRecord); err != nil {
+			return err
+		}
+	}
+
+	// 3. CASCADE / SET NULL の FK で旧値を参照している行に参照アクションを適用
+	for _, childFK := range refFKs {
+		action := childFK.Constraint.OnUpdate
+		if action == dictionary.ReferentialActionRestrict {
+			continue
+		}
+		oldValues, ok := getRefValues(tableMeta, childFK, oldRecord)
+		if !ok {
+			continue
+		}
+		newValues, _ := getRefValues(tableMeta, childFK, newRecord)
+		if equalFKValues(oldValues, newValues) {
+			continue
+		}
+		if action == dictionary.ReferentialActionSetNull {
+			newValues = make([][]byte, len(oldValues))
+		}
+		err := applyFKAction(hdl, trxId, childFK, oldValues, func(childTable *access.Table, child Record, positions []uint16) error {
+			newChild := make(Record, len(child))
+			copy(newChild, child)
+			for i, pos := range positions {
+				newChild[pos] = newValues[i]
+			}
+			return updateRow(hdl, trxId, childTable, child, newChild, depth+1)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applyFKAction は参照元テーブルの FK カラムの値の組が values の行ごとに、排他ロックを取得してから action を実行する
+//
+// action には最新の行と FK カラムの位置 (キーの順) を渡す。ロックの取得を待つ間に削除された行や、FK カラムの値が変わった行はスキップする
+func applyFKAction(hdl *handler.Handler, trxId handler.TrxId, childFK dictionary.ChildForeignKey, values [][]byte, action func(childTable *access.Table, child Record, positions []uint16) error) error {
+	childTable, err := hdl.OpenTable(trxId, childFK.TableName)
+	if err != nil {
+		return err
+	}
+	childMeta, _ := hdl.Catalog.GetTableMetaByName(childFK.TableName)
+	positions, err := childMeta.GetColPositions(childFK.Constraint.ColNames)
+	if err != nil {
+		return err
+	}
+
+	// 行の変更によりインデックスのページデータが変わるため、先に対象の行をすべて取得する
+	children, err := findChildRecords(hdl, childTable, childFK.Constraint.ColNames, values)
+	if err != nil {
+		return err
+	}
+	for _, child := range children {
+		current, ok, err := lockLatestRow(hdl, trxId, childTable, child)
+		if err != nil {
+			return err
+		}
+		if !ok || !equalFKValues(getFKValues(current, positions), values) {
+			continue
+		}
+		if err := action(childTable, current, positions); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// findChildRecords は参照元テーブルから、FK カラムの値の組が values の行 (最新のバージョン) をすべて返す
+func findChildRecords(hdl *handler.Handler, childTable *access.Table, colNames []string, values [][]byte) ([]Record, error) {
+	si, err := getFKIndex(childTable, colNames)
+	if err != nil {
+		return nil, err
+	}
+	iter, err := si.Search(hdl.BufferPool, childTable, access.RecordSearchModeKey{Key: values})
+	if err != nil {
+		return nil, err
+	}
+
+	var records []Record
+	for {
+		result, ok, err := iter.Next()
+		if err != nil {
+			return nil, err
+		}
+		if !ok || !equalFKValues(result.SecondaryKey[:len(values)], values) {
+			return records, nil
+		}
+		records = append(records, result.Record)
+	}
+}
+
+// getRefValues は参照先の行から、childFK の参照先カラムの値の組を取得する
+//
+// 参照先カラムが見つからない場合や、値に NULL を含む (参照されない) 場合は false を返す
+func getRefValues(tableMeta *dictionary.TableMeta, childFK dictionary.ChildForeignKey, record Record) ([][]byte, bool) {
+	positions, err := tableMeta.GetColPositions(childFK.Constraint.RefColNames)
+	if err != nil {
+		return nil, false
+	}
+	values := getFKValues(record, positions)
+	return values, !hasNullValue(values)
+}
+
+// lockLatestRow は行の排他ロックを取得し、最新の行を読み直して返す
+//
+// 行が削除されている場合は false を返す
+func lockLatestRow(hdl *handler.Handler, trxId handler.TrxId, table *access.Table, record Record) (Record, bool, error) {
+	encodedKey := table.EncodeKey(record)
+	_, pos, err := btree.NewBTree(table.MetaPageId).FindByKey(hdl.BufferPool, encodedKey)
+	if err != nil {
+		return nil, false, err
+	}
+	if err := hdl.LockMgr.Lock(trxId, pos, lock.Exclusive); err != nil {
+		return nil, false, err
+	}
+
+	// Current Read で最新の行を読む (DeleteMark が設定された行はスキップされる)
+	rv := access.NewReadView(0, nil, ^uint64(0))
+	iter, err := table.Search(hdl.BufferPool, rv, access.NewVersionReader(nil), access.RecordSearchModeKey{Key: record[:table.PrimaryKeyCount]})
+	if err != nil {
+		return nil, false, err
+	}
+	latest, ok, err := iter.Next()
+	if err != nil || !ok {
+		return nil, false, err
+	}
+	if !bytes.Equal(table.EncodeKey(latest), encodedKey) {
+		return nil, false, nil
+	}
+	return latest, true, nil
+}
@@ -0,0 +1,407 @@
+package executor
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/ren-yamanashi/minesql/internal/storage/access"
+	"github.com/ren-yamanashi/minesql/internal/storage/handler"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFKAction_Delete(t *testing.T) {
+	t.Run("ON DELETE CASCADE で複数階層の参照元の行が削除される", func(t *testing.T) {
+		// GIVEN: users <- orders (CASCADE) <- order_items (CASCADE)
+		hdl := initFKActionTest(t)
+		trxId := hdl.BeginTrx()
+		createFKActionTable(t, "users", "", handler.CreateConstraintParam{})
+		createFKActionTable(t, "orders", "user_id", handler.CreateConstraintParam{
+			ConstraintName: "fk_user", RefTableName: "users", OnDelete: handler.ReferentialActionCascade,
+		})
+		createFKActionTable(t, "order_items", "order_id", handler.CreateConstraintParam{
+			ConstraintName: "fk_order", RefTableName: "orders", OnDelete: handler.ReferentialActionCascade,
+		})
+		insertFKActionRecords(t, trxId, "users", Record{[]byte("1"), nil}, Record{[]byte("2"), nil})
+		insertFKActionRecords(t, trxId, "orders", Record{[]byte("10"), []byte("1")}, Record{[]byte("20"), []byte("2")})
+		insertFKActionRecords(t, trxId, "order_items", Record{[]byte("100"), []byte("10")}, Record{[]byte("101"), []byte("10")}, Record{[]byte("200"), []byte("20")})
+
+		// WHEN: users の id = 1 を削除
+		usersTable, err := hdl.GetTable("users")
+		assert.NoError(t, err)
+		scan := testTableScan(usersTable, access.RecordSearchModeStart{}, func(r Record) bool { return string(r[0]) == "1" })
+		_, err = NewDelete(trxId, usersTable, scan).Next()
+
+		// THEN
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"2"}, scanFKActionColumn(t, "users", 0))
+		assert.Equal(t, []string{"20"}, scanFKActionColumn(t, "orders", 0))
+		assert.Equal(t, []string{"200"}, scanFKActionColumn(t, "order_items", 0))
+	})
+
+	t.Run("ON DELETE SET NULL で参照元の行の FK カラムが NULL になる", func(t *testing.T) {
+		// GIVEN
+		hdl := initFKActionTest(t)
+		trxId := hdl.BeginTrx()
+		createFKActionTable(t, "users", "", handler.CreateConstraintParam{})
+		createFKActionTable(t, "orders", "user_id", handler.CreateConstraintParam{
+			ConstraintName: "fk_user", RefTableName: "users", OnDelete: handler.ReferentialActionSetNull,
+		})
+		insertFKActionRecords(t, trxId, "users", Record{[]byte("1"), nil})
+		insertFKActionRecords(t, trxId, "orders", Record{[]byte("10"), []byte("1")}, Record{[]byte("11"), []byte("1")})
+
+		// WHEN
+		usersTable, err := hdl.GetTable("users")
+		assert.NoError(t, err)
+		scan := testTableScan(usersTable, access.RecordSearchModeStart{}, func(Record) bool { return true })
+		_, err = NewDelete(trxId, usersTable, scan).Next()
+
+		// THEN
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"10", "11"}, scanFKActionColumn(t, "orders", 0))
+		assert.Equal(t, []string{"<NULL>", "<NULL>"}, scanFKActionColumn(t, "orders", 1))
+	})
+
+	t.Run("自己参照の ON DELETE CASCADE で子孫の行がすべて削除される", func(t *testing.T) {
+		// GIVEN: 1 <- 2 <- 3, 4 (親なし)
+		hdl := initFKActionTest(t)
+		trxId := hdl.BeginTrx()
+		createFKActionTable(t, "categories", "parent_id", handler.CreateConstraintParam{
+			ConstraintName: "fk_parent", RefTableName: "categories", OnDelete: handler.ReferentialActionCascade,
+		})
+		insertFKActionRecords(t, trxId, "categories",
+			Record{[]byte("1"), nil}, Record{[]byte("2"), []byte("1")}, Record{[]byte("3"), []byte("2")}, Record{[]byte("4"), nil},
+		)
+
+		// WHEN: 削除対象の行 (1, 2) が先に参照アクションで削除されても、二重に削除しない
+		categoriesTable, err := hdl.GetTable("categories")
+		assert.NoError(t, err)
+		scan := testTableScan(categoriesTable, access.RecordSearchModeStart{}, func(r Record) bool { return string(r[0]) <= "2" })
+		_, err = NewDelete(trxId, categoriesTable, scan).Next()
+
+		// THEN
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"4"}, scanFKActionColumn(t, "categories", 0))
+	})
+
+	t.Run("自分自身だけを参照している行は RESTRICT でも削除できる", func(t *testing.T) {
+		// GIVEN
+		hdl := initFKActionTest(t)
+		trxId := hdl.BeginTrx()
+		createFKActionTable(t, "categories", "parent_id", handler.CreateConstraintParam{
+			ConstraintName: "fk_parent", RefTableName: "categories",
+		})
+		insertFKActionRecords(t, trxId, "categories", Record{[]byte("1"), nil})
+		categoriesTable, err := hdl.GetTable("categories")
+		assert.NoError(t, err)
+		scan := testTableScan(categoriesTable, access.RecordSearchModeStart{}, func(Record) bool { return true })
+		_, err = NewUpdate(trxId, categoriesTable, []SetColumn{{Pos: 1, Value: []byte("1")}}, scan).Next()
+		assert.NoError(t, err)
+
+		// WHEN
+		scan = testTableScan(categoriesTable, access.RecordSearchModeStart{}, func(Record) bool { return true })
+		_, err = NewDelete(trxId, categoriesTable, scan).Next()
+
+		// THEN
+		assert.NoError(t, err)
+		assert.Empty(t, scanFKActionColumn(t, "categories", 0))
+	})
+
+	t.Run("参照アクションの連鎖が上限を超える場合、エラーを返す", func(t *testing.T) {
+		// GIVEN: 深さ 17 の自己参照の連鎖
+		hdl := initFKActionTest(t)
+		trxId := hdl.BeginTrx()
+		createFKActionTable(t, "categories", "parent_id", handler.CreateConstraintParam{
+			ConstraintName: "fk_parent", RefTableName: "categories", OnDelete: handler.ReferentialActionCascade,
+		})
+		insertFKActionRecords(t, trxId, "categories", Record{[]byte("c00"), nil})
+		for i := 1; i <= maxFKCascadeDepth+1; i++ {
+			insertFKActionRecords(t, trxId, "categories", Record{fmt.Appendf(nil, "c%02d", i), fmt.Appendf(nil, "c%02d", i-1)})
+		}
+
+		// WHEN
+		categoriesTable, err := hdl.GetTable("categories")
+		assert.NoError(t, err)
+		scan := testTableScan(categoriesTable, access.RecordSearchModeStart{}, func(r Record) bool { return string(r[0]) == "c00" })
+		_, err = NewDelete(trxId, categoriesTable, scan).Next()
+
+		// THEN
+		assert.EqualError(t, err, "foreign key cascade delete/update exceeds max depth of 15")
+	})
+
+	t.Run("ロールバックすると参照アクションによる変更も元に戻る", func(t *testing.T) {
+		// GIVEN
+		hdl := initFKActionTest(t)
+		setupTrxId := hdl.BeginTrx()
+		createFKActionTable(t, "users", "", handler.CreateConstraintParam{})
+		createFKActionTable(t, "orders", "user_id", handler.CreateConstraintParam{
+			ConstraintName: "fk_user", RefTableName: "users", OnDelete: handler.ReferentialActionCascade,
+		})
+		insertFKActionRecords(t, setupTrxId, "users", Record{[]byte("1"), nil})
+		insertFKActionRecords(t, setupTrxId, "orders", Record{[]byte("10"), []byte("1")})
+		assert.NoError(t, hdl.CommitTrx(setupTrxId))
+
+		trxId := hdl.BeginTrx()
+		usersTable, err := hdl.GetTable("users")
+		assert.NoError(t, err)
+		scan := testTableScan(usersTable, access.RecordSearchModeStart{}, func(Record) bool { return true })
+		_, err = NewDelete(trxId, usersTable, scan).Next()
+		assert.NoError(t, err)
+		assert.Empty(t, scanFKActionColumn(t, "orders", 0))
+
+		// WHEN
+		err = hdl.RollbackTrx(trxId)
+
+		// THEN
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"1"}, scanFKActionColumn(t, "users", 0))
+		assert.Equal(t, []string{"10"}, scanFKActionColumn(t, "orders", 0))
+	})
+}
+
+func TestFKAction_Update(t *testing.T) {
+	t.Run("ON UPDATE CASCADE で複数階層の参照元の行の FK カラムが新しい値になる", func(t *testing.T) {
+		// GIVEN: users <- orders (CASCADE, FK カラムは UNIQUE) <- order_items (CASCADE)
+		hdl := initFKActionTest(t)
+		trxId := hdl.BeginTrx()
+		createFKActionTable(t, "users", "", handler.CreateConstraintParam{})
+		_, err := NewCreateTable("orders", 1,
+			[]handler.CreateIndexParam{{Name: "idx_user_id", ColNames: []string{"user_id"}, ColIdxs: []uint16{1}, Unique: true}},
+			[]handler.CreateColumnParam{{Name: "id", Type: "string"}, {Name: "user_id", Type: "string"}},
+			[]handler.CreateConstraintParam{{ConstraintName: "fk_user", ColNames: []string{"user_id"}, RefTableName: "users", RefColNames: []string{"id"}, OnUpdate: handler.ReferentialActionCascade}},
+		).Next()
+		assert.NoError(t, err)
+		createFKActionTable(t, "order_items", "order_user_id", handler.CreateConstraintParam{
+			ConstraintName: "fk_order", RefTableName: "orders", RefColNames: []string{"user_id"}, OnUpdate: handler.ReferentialActionCascade,
+		})
+		insertFKActionRecords(t, trxId, "users", Record{[]byte("1"), nil})
+		insertFKActionRecords(t, trxId, "orders", Record{[]byte("10"), []byte("1")})
+		insertFKActionRecords(t, trxId, "order_items", Record{[]byte("100"), []byte("1")})
+
+		// WHEN: users の id を 1 から 9 に変更
+		usersTable, err := hdl.GetTable("users")
+		assert.NoError(t, err)
+		scan := testTableScan(usersTable, access.RecordSearchModeStart{}, func(Record) bool { return true })
+		_, err = NewUpdate(trxId, usersTable, []SetColumn{{Pos: 0, Value: []byte("9")}}, scan).Next()
+
+		// THEN
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"9"}, scanFKActionColumn(t, "users", 0))
+		assert.Equal(t, []string{"9"}, scanFKActionColumn(t, "orders", 1))
+		assert.Equal(t, []string{"9"}, scanFKActionColumn(t, "order_items", 1))
+	})
+
+	t.Run("ON UPDATE SET NULL で参照元の行の FK カラムが NULL になる", func(t *testing.T) {
+		// GIVEN
+		hdl := initFKActionTest(t)
+		trxId := hdl.BeginTrx()
+		createFKActionTable(t, "users", "", handler.CreateConstraintParam{})
+		createFKActionTable(t, "orders", "user_id", handler.CreateConstraintParam{
+			ConstraintName: "fk_user", RefTableName: "users", OnUpdate: handler.ReferentialActionSetNull,
+		})
+		insertFKActionRecords(t, trxId, "users", Record{[]byte("1"), nil})
+		insertFKActionRecords(t, trxId, "orders", Record{[]byte("10"), []byte("1")})
+
+		// WHEN
+		usersTable, err := hdl.GetTable("users")
+		assert.NoError(t, err)
+		scan := testTableScan(usersTable, access.RecordSearchModeStart{}, func(Record) bool { return true })
+		_, err = NewUpdate(trxId, usersTable, []SetColumn{{Pos: 0, Value: []byte("9")}}, scan).Next()
+
+		// THEN
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"<NULL>"}, scanFKActionColumn(t, "orders", 1))
+	})
+
+	t.Run("自己参照の ON UPDATE CASCADE で自分自身を参照している行も新しい値になる", func(t *testing.T) {
+		// GIVEN: 1 (親は自分自身) <- 2
+		hdl := initFKActionTest(t)
+		trxId := hdl.BeginTrx()
+		createFKActionTable(t, "categories", "parent_id", handler.CreateConstraintParam{
+			ConstraintName: "fk_parent", RefTableName: "categories", OnUpdate: handler.ReferentialActionCascade,
+		})
+		insertFKActionRecords(t, trxId, "categories", Record{[]byte("1"), nil})
+		categoriesTable, err := hdl.GetTable("categories")
+		assert.NoError(t, err)
+		scan := testTableScan(categoriesTable, access.RecordSearchModeStart{}, func(Record) bool { return true })
+		_, err = NewUpdate(trxId, categoriesTable, []SetColumn{{Pos: 1, Value: []byte("1")}}, scan).Next()
+		assert.NoError(t, err)
+		insertFKActionRecords(t, trxId, "categories", Record{[]byte("2"), []byte("1")})
+
+		// WHEN: id = 1 を 5 に変更
+		scan = testTableScan(categoriesTable, access.RecordSearchModeStart{}, func(r Record) bool { return string(r[0]) == "1" })
+		_, err = NewUpdate(trxId, categoriesTable, []SetColumn{{Pos: 0, Value: []byte("5")}}, scan).Next()
+
+		// THEN
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"2", "5"}, scanFKActionColumn(t, "categories", 0))
+		assert.Equal(t, []string{"5", "5"}, scanFKActionColumn(t, "categories", 1))
+	})
+
+	t.Run("RESTRICT の FK で参照されている場合は CASCADE の FK があってもエラーになる", func(t *testing.T) {
+		// GIVEN
+		hdl := initFKActionTest(t)
+		trxId := hdl.BeginTrx()
+		createFKActionTable(t, "users", "", handler.CreateConstraintParam{})
+		createFKActionTable(t, "orders", "user_id", handler.CreateConstraintParam{
+			ConstraintName: "fk_user", RefTableName: "users", OnUpdate: handler.ReferentialActionCascade,
+		})
+		createFKActionTable(t, "payments", "user_id", handler.CreateConstraintParam{
+			ConstraintName: "fk_payment_user", RefTableName: "users",
+		})
+		insertFKActionRecords(t, trxId, "users", Record{[]byte("1"), nil})
+		insertFKActionRecords(t, trxId, "orders", Record{[]byte("10"), []byte("1")})
+		insertFKActionRecords(t, trxId, "payments", Record{[]byte("20"), []byte("1")})
+
+		// WHEN
+		usersTable, err := hdl.GetTable("users")
+		assert.NoError(t, err)
+		scan := testTableScan(usersTable, access.RecordSearchModeStart{}, func(Record) bool { return true })
+		_, err = NewUpdate(trxId, usersTable, []SetColumn{{Pos: 0, Value: []byte("9")}}, scan).Next()
+
+		// THEN
+		assert.ErrorIs(t, err, errFKDeleteRestrict)
+		assert.Equal(t, []string{"1"}, scanFKActionColumn(t, "orders", 1))
+	})
+}
+
+func TestFKAction_CompositeKey(t *testing.T) {
+	t.Run("複合外部キーの ON DELETE CASCADE で値の組が一致する参照元の行だけが削除される", func(t *testing.T) {
+		// GIVEN: orders は (1, 1) と (1, 2) を参照する
+		hdl := initFKActionTest(t)
+		trxId := hdl.BeginTrx()
+		createCompositeFKActionTables(t, handler.CreateConstraintParam{OnDelete: handler.ReferentialActionCascade})
+		insertFKActionRecords(t, trxId, "users", Record{[]byte("1"), []byte("1")}, Record{[]byte("1"), []byte("2")})
+		insertFKActionRecords(t, trxId, "orders", Record{[]byte("10"), []byte("1"), []byte("1")}, Record{[]byte("20"), []byte("1"), []byte("2")})
+
+		// WHEN: users の (1, 1) を削除
+		usersTable, err := hdl.GetTable("users")
+		assert.NoError(t, err)
+		scan := testTableScan(usersTable, access.RecordSearchModeKey{Key: [][]byte{[]byte("1"), []byte("1")}}, func(r Record) bool { return string(r[1]) == "1" })
+		_, err = NewDelete(trxId, usersTable, scan).Next()
+
+		// THEN
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"20"}, scanFKActionColumn(t, "orders", 0))
+	})
+
+	t.Run("複合外部キーの ON DELETE SET NULL で FK カラムがすべて NULL になる", func(t *testing.T) {
+		// GIVEN
+		hdl := initFKActionTest(t)
+		trxId := hdl.BeginTrx()
+		createCompositeFKActionTables(t, handler.CreateConstraintParam{OnDelete: handler.ReferentialActionSetNull})
+		insertFKActionRecords(t, trxId, "users", Record{[]byte("1"), []byte("1")})
+		insertFKActionRecords(t, trxId, "orders", Record{[]byte("10"), []byte("1"), []byte("1")})
+
+		// WHEN
+		usersTable, err := hdl.GetTable("users")
+		assert.NoError(t, err)
+		scan := testTableScan(usersTable, access.RecordSearchModeStart{}, func(Record) bool { return true })
+		_, err = NewDelete(trxId, usersTable, scan).Next()
+
+		// THEN
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"<NULL>"}, scanFKActionColumn(t, "orders", 1))
+		assert.Equal(t, []string{"<NULL>"}, scanFKActionColumn(t, "orders", 2))
+	})
+
+	t.Run("複合外部キーの ON UPDATE CASCADE で参照先カラムに対応する FK カラムが新しい値になる", func(t *testing.T) {
+		// GIVEN
+		hdl := initFKActionTest(t)
+		trxId := hdl.BeginTrx()
+		createCompositeFKActionTables(t, handler.CreateConstraintParam{OnUpdate: handler.ReferentialActionCascade})
+		insertFKActionRecords(t, trxId, "users", Record{[]byte("1"), []byte("1")})
+		insertFKActionRecords(t, trxId, "orders", Record{[]byte("10"), []byte("1"), []byte("1")})
+
+		// WHEN: users の id を 1 から 9 に変更
+		usersTable, err := hdl.GetTable("users")
+		assert.NoError(t, err)
+		scan := testTableScan(usersTable, access.RecordSearchModeStart{}, func(Record) bool { return true })
+		_, err = NewUpdate(trxId, usersTable, []SetColumn{{Pos: 1, Value: []byte("9")}}, scan).Next()
+
+		// THEN: tenant_id は変わらず、user_id が 9 になる
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"1"}, scanFKActionColumn(t, "orders", 1))
+		assert.Equal(t, []string{"9"}, scanFKActionColumn(t, "orders", 2))
+	})
+}
+
+// initFKActionTest はテスト用のデータディレクトリでハンドラーを初期化する
+func initFKActionTest(t *testing.T) *handler.Handler {
+	t.Helper()
+	t.Setenv("MINESQL_DATA_DIR", t.TempDir())
+	t.Setenv("MINESQL_BUFFER_SIZE", "100")
+	handler.Reset()
+	handler.Init()
+	t.Cleanup(handler.Reset)
+	return handler.Get()
+}
+
+// createFKActionTable は (id PK, fkCol) の 2 カラムのテーブルを作成する
+//
+// fkCol が空でない場合、fkCol にインデックスと FK 制約 (con の参照先カラムを省略した場合は id) を作成する
+func createFKActionTable(t *testing.T, tableName string, fkCol string, con handler.CreateConstraintParam) {
+	t.Helper()
+	colName := fkCol
+	if colName == "" {
+		colName = "name"
+	}
+	cols := []handler.CreateColumnParam{{Name: "id", Type: "string"}, {Name: colName, Type: "string"}}
+
+	var idxParams []handler.CreateIndexParam
+	var conParams []handler.CreateConstraintParam
+	if fkCol != "" {
+		idxParams = []handler.CreateIndexParam{{Name: "idx_" + fkCol, ColNames: []string{fkCol}, ColIdxs: []uint16{1}}}
+		con.ColNames = []string{fkCol}
+		if con.RefColNames == nil {
+			con.RefColNames = []string{"id"}
+		}
+		conParams = []handler.CreateConstraintParam{con}
+	}
+	_, err := NewCreateTable(tableName, 1, idxParams, cols, conParams).Next()
+	assert.NoError(t, err)
+}
+
+// insertFKActionRecords はテーブルにレコードを挿入する
+func insertFKActionRecords(t *testing.T, trxId handler.TrxId, tableName string, records ...Record) {
+	t.Helper()
+	tbl, err := handler.Get().GetTable(tableName)
+	assert.NoError(t, err)
+	_, err = NewInsert(trxId, tbl, records).Next()
+	assert.NoError(t, err)
+}
+
+// scanFKActionColumn はテーブルの全行 (最新のバージョン) の指定したカラムの値を返す (NULL は "<NULL>")
+func scanFKActionColumn(t *testing.T, tableName string, pos int) []string {
+	t.Helper()
+	tbl, err := handler.Get().GetTable(tableName)
+	assert.NoError(t, err)
+	var values []string
+	for _, record := range collectAll(t, testTableScan(tbl, access.RecordSearchModeStart{}, func(Record) bool { return true })) {
+		if record[pos] == nil {
+			values = append(values, "<NULL>")
+			continue
+		}
+		values = append(values, string(record[pos]))
+	}
+	return values
+}
+
+// createCompositeFKActionTables は複合主キー (tenant_id, id) の users と、それを (tenant_id, user_id) で参照する orders (id PK) を作成する
+//
+// con には参照アクションを指定する
+func createCompositeFKActionTables(t *testing.T, con handler.CreateConstraintParam) {
+	t.Helper()
+	_, err := NewCreateTable("users", 2, nil, []handler.CreateColumnParam{{Name: "tenant_id", Type: "string"}, {Name: "id", Type: "string"}}, nil).Next()
+	assert.NoError(t, err)
+
+	con.ConstraintName = "fk_user"
+	con.ColNames = []string{"tenant_id", "user_id"}
+	con.RefTableName = "users"
+	con.RefColNames = []string{"tenant_id", "id"}
+	_, err = NewCreateTable("orders", 1,
+		[]handler.CreateIndexParam{{Name: "idx_tenant_user", ColNames: []string{"tenant_id", "user_id"}, ColIdxs: []uint16{1, 2}}},
+		[]handler.CreateColumnParam{{Name: "id", Type: "string"}, {Name: "tenant_id", Type: "string"}, {Name: "user_id", Type: "string"}},
+		[]handler.CreateConstraintParam{con},
+	).Next()
+	assert.NoError(t, err)
+}
@@ -1,7 +1,10 @@
 package executor
 
 import (
+	"bytes"
 	"fmt"
+	"slices"
+	"strings"
 
 	"github.com/ren-yamanashi/minesql/internal/storage/access"
 	"github.com/ren-yamanashi/minesql/internal/storage/btree"
@@ -12,11 +15,14 @@ import (
 	"github.com/ren-yamanashi/minesql/internal/storage/lock"
 )
 
-var errFKDeleteRestrict = fmt.Errorf("cannot delete or update a parent row: a foreign key constraint fails")
+var (
+	errFKDeleteRestrict = fmt.Errorf("cannot delete or update a parent row: a foreign key constraint fails")
+	errFKNoParentRow    = fmt.Errorf("cannot add or update a child row: a foreign key constraint fails")
+)
 
 // checkFKOnInsert は INSERT 時に参照先テーブルに値が存在するかを確認する
 //
-// FK 制約ごとに、参照先テーブルの PK/インデックスで FK カラムの値を検索し、
+// FK 制約ごとに、参照先テーブルの PK/インデックスで FK カラムの値の組を検索し、
 // 見つかれば Shared Lock を取得して DELETE との競合を防ぐ。
 // Current Read を使用するため、同一トランザクション内の未コミット変更も可視。
 func checkFKOnInsert(bp *buffer.BufferPool, trxId handler.TrxId, lockMgr *lock.Manager, tableMeta *dictionary.TableMeta, record Record) error {
@@ -27,36 +33,17 @@ func checkFKOnInsert(bp *buffer.BufferPool, trxId handler.TrxId, lockMgr *lock.M
 
 	hdl := handler.Get()
 	for _, fk := range fks {
-		col, ok := tableMeta.GetColByName(fk.ColName)
-		if !ok {
-			return fmt.Errorf("foreign key column '%s' not found in table '%s'", fk.ColName, tableMeta.Name)
-		}
-
-		fkValue := record[col.Pos]
-		if err := checkRefValueExists(bp, trxId, lockMgr, hdl, fk, fkValue); err != nil {
+		positions, err := tableMeta.GetColPositions(fk.ColNames)
+		if err != nil {
 			return err
 		}
-	}
-	return nil
-}
-
-// checkFKOnDelete は DELETE 時に参照元テーブルからの参照がないことを確認する
-//
-// refFKs は呼び出し側で GetForeignKeysReferencingTable で取得した FK 制約のリスト。
-// 参照元テーブルの FK カラムのインデックスで削除対象の値を検索する。
-func checkFKOnDelete(bp *buffer.BufferPool, tableMeta *dictionary.TableMeta, refFKs []dictionary.ChildForeignKey, record Record) error {
-	hdl := handler.Get()
 
-	for _, childFK := range refFKs {
-		// 参照先カラム (このテーブル側) の値を取得
-		refCol, ok := tableMeta.GetColByName(childFK.Constraint.RefColName)
-		if !ok {
+		// FK カラムのいずれかが NULL の場合は参照先の存在チェックを行わない
+		fkValues := getFKValues(record, positions)
+		if hasNullValue(fkValues) || refersToOwnRow(tableMeta, fk, record, fkValues) {
 			continue
 		}
-		refValue := record[refCol.Pos]
-
-		// 参照元テーブルの FK カラムのインデックスで検索
-		if err := checkChildRecordExists(bp, hdl, childFK, refValue); err != nil {
+		if err := checkRefValueExists(bp, trxId, lockMgr, hdl, fk, getFKCols(tableMeta, positions), fkValues); err != nil {
 			return err
 		}
 	}
@@ -72,34 +59,36 @@ func checkFKOnUpdate(bp *buffer.BufferPool, trxId handler.TrxId, lockMgr *lock.M
 
 	// 1. 親テーブルとして: 旧値が参照されていないか確認
 	for _, childFK := range refFKs {
-		refCol, ok := tableMeta.GetColByName(childFK.Constraint.RefColName)
-		if !ok {
+		positions, err := tableMeta.GetColPositions(childFK.Constraint.RefColNames)
+		if err != nil {
 			continue
 		}
 
-		// 更新対象カラムの値が変わらない場合はチェック不要
-		if string(oldRecord[refCol.Pos]) == string(newRecord[refCol.Pos]) {
+		// 参照先カラムの値が変わらない場合や、旧値に NULL を含む (参照されない) 場合はチェック不要
+		oldValues := getFKValues(oldRecord, positions)
+		if hasNullValue(oldValues) || equalFKValues(oldValues, getFKValues(newRecord, positions)) {
 			continue
 		}
 
-		if err := checkChildRecordExists(bp, hdl, childFK, oldRecord[refCol.Pos]); err != nil {
+		if err := checkChildRecordExists(bp, trxId, hdl, childFK, oldValues); err != nil {
 			return err
 		}
 	}
 
 	// 2. 子テーブルとして: 新値が参照先に存在するか確認
 	for _, fk := range fks {
-		col, ok := tableMeta.GetColByName(fk.ColName)
-		if !ok {
+		positions, err := tableMeta.GetColPositions(fk.ColNames)
+		if err != nil {
 			continue
 		}
 
-		// FK カラムの値が変わらない場合はチェック不要
-		if string(oldRecord[col.Pos]) == string(newRecord[col.Pos]) {
+		// FK カラムの値が変わらない場合や、新値に NULL を含む場合はチェック不要
+		newValues := getFKValues(newRecord, positions)
+		if hasNullValue(newValues) || equalFKValues(getFKValues(oldRecord, positions), newValues) || refersToOwnRow(tableMeta, fk, newRecord, newValues) {
 			continue
 		}
 
-		if err := checkRefValueExists(bp, trxId, lockMgr, hdl, fk, newRecord[col.Pos]); err != nil {
+		if err := checkRefValueExists(bp, trxId, lockMgr, hdl, fk, getFKCols(tableMeta, positions), newValues); err != nil {
 			return err
 		}
 	}
@@ -107,43 +96,41 @@ func checkFKOnUpdate(bp *buffer.BufferPool, trxId handler.TrxId, lockMgr *lock.M
 	return nil
 }
 
-// checkRefValueExists は参照先テーブルに値が存在するかを確認し、Shared Lock を取得する
-func checkRefValueExists(bp *buffer.BufferPool, trxId handler.TrxId, lockMgr *lock.Manager, hdl *handler.Handler, fk *dictionary.ConstraintMeta, value []byte) error {
+// checkRefValueExists は参照先テーブルに値の組が存在するかを確認し、Shared Lock を取得する
+//
+// cols は values に対応する FK カラムのメタデータ (エラーメッセージで値を表示するために使う)
+func checkRefValueExists(bp *buffer.BufferPool, trxId handler.TrxId, lockMgr *lock.Manager, hdl *handler.Handler, fk *dictionary.ConstraintMeta, cols []*dictionary.ColumnMeta, values [][]byte) error {
 	refTableMeta, ok := hdl.Catalog.GetTableMetaByName(fk.RefTableName)
 	if !ok {
 		return fmt.Errorf("referenced table '%s' not found", fk.RefTableName)
 	}
 
-	refCol, ok := refTableMeta.GetColByName(fk.RefColName)
-	if !ok {
-		return fmt.Errorf("referenced column '%s' not found in table '%s'", fk.RefColName, fk.RefTableName)
-	}
-
 	// 参照先カラムが PK の場合、クラスタ化インデックスで検索
-	isPK := refCol.Pos < uint16(refTableMeta.PKCount)
-	if isPK {
-		return checkRefValueInPK(bp, trxId, lockMgr, hdl, refTableMeta, value)
+	if refTableMeta.IsPrimaryKey(fk.RefColNames) {
+		return checkRefValueInPK(bp, trxId, lockMgr, hdl, refTableMeta, cols, values)
 	}
 
 	// 参照先カラムが UK の場合、セカンダリインデックスで検索
-	return checkRefValueInUniqueIndex(bp, trxId, lockMgr, hdl, refTableMeta, fk.RefColName, value)
+	return checkRefValueInUniqueIndex(bp, trxId, lockMgr, hdl, refTableMeta, fk.RefColNames, cols, values)
 }
 
-// checkRefValueInPK はクラスタ化インデックス (PK) で値の存在を確認する
-func checkRefValueInPK(bp *buffer.BufferPool, trxId handler.TrxId, lockMgr *lock.Manager, hdl *handler.Handler, refTableMeta *dictionary.TableMeta, value []byte) error {
-	refTable, err := hdl.GetTable(refTableMeta.Name)
+// checkRefValueInPK はクラスタ化インデックス (PK) で値の組の存在を確認する
+//
+// values はプライマリキーのカラムの順に並んだ値
+func checkRefValueInPK(bp *buffer.BufferPool, trxId handler.TrxId, lockMgr *lock.Manager, hdl *handler.Handler, refTableMeta *dictionary.TableMeta, cols []*dictionary.ColumnMeta, values [][]byte) error {
+	refTable, err := hdl.OpenTable(trxId, refTableMeta.Name)
 	if err != nil {
 		return err
 	}
 
 	// PK でエンコードして検索
 	var encodedKey []byte
-	encode.Encode([][]byte{value}, &encodedKey)
+	encode.Encode(values, &encodedKey)
 
 	btr := btree.NewBTree(refTable.MetaPageId)
 	_, pos, err := btr.FindByKey(bp, encodedKey)
 	if err != nil {
-		return fkConstraintError(value)
+		return fkConstraintError(cols, values)
 	}
 
 	// Shared Lock を先に取得して、他トランザクションの SoftDelete 完了を待つ
@@ -155,61 +142,54 @@ func checkRefValueInPK(bp *buffer.BufferPool, trxId handler.TrxId, lockMgr *lock
 	// ロック取得後にレコードを再読み込みしてソフトデリート状態を確認
 	record, _, err := btr.FindByKey(bp, encodedKey)
 	if err != nil {
-		return fkConstraintError(value)
+		return fkConstraintError(cols, values)
 	}
 	if record.HeaderBytes()[0] == 1 {
-		return fkConstraintError(value)
+		return fkConstraintError(cols, values)
 	}
 
 	return nil
 }
 
-// checkRefValueInUniqueIndex はセカンダリインデックス (UK) で値の存在を確認する
-func checkRefValueInUniqueIndex(bp *buffer.BufferPool, trxId handler.TrxId, lockMgr *lock.Manager, hdl *handler.Handler, refTableMeta *dictionary.TableMeta, refColName string, value []byte) error {
-	refTable, err := hdl.GetTable(refTableMeta.Name)
+// checkRefValueInUniqueIndex はセカンダリインデックス (UK) で値の組の存在を確認する
+//
+// values は refColNames の順に並んだ値
+func checkRefValueInUniqueIndex(bp *buffer.BufferPool, trxId handler.TrxId, lockMgr *lock.Manager, hdl *handler.Handler, refTableMeta *dictionary.TableMeta, refColNames []string, cols []*dictionary.ColumnMeta, values [][]byte) error {
+	refTable, err := hdl.OpenTable(trxId, refTableMeta.Name)
 	if err != nil {
 		return err
 	}
 
-	// セカンダリインデックスを取得
+	// 参照先カラムで構成される UNIQUE インデックスを取得
 	var si *access.SecondaryIndex
 	for _, idx := range refTable.SecondaryIndexes {
-		if idx.ColName == refColName {
+		if idx.Unique && slices.Equal(idx.ColNames, refColNames) {
 			si = idx
 			break
 		}
 	}
 	if si == nil {
-		return fmt.Errorf("unique index for column '%s' not found", refColName)
+		return fmt.Errorf("unique index for column '%s' not found", strings.Join(refColNames, ", "))
 	}
 
 	// セカンダリキーでエンコードして検索
 	var encodedSecKey []byte
-	encode.Encode([][]byte{value}, &encodedSecKey)
+	encode.EncodeNullable(values, &encodedSecKey)
 
 	btr := btree.NewBTree(si.MetaPageId)
 	iter, err := btr.Search(bp, btree.SearchModeKey{Key: encodedSecKey})
 	if err != nil {
-		return fkConstraintError(value)
+		return fkConstraintError(cols, values)
 	}
 
 	// セカンダリキーが一致する active レコードを探す
 	for {
 		record, ok := iter.Get()
 		if !ok {
-			return fkConstraintError(value)
-		}
-
-		var keyColumns [][]byte
-		encode.Decode(record.KeyBytes(), &keyColumns)
-		if len(keyColumns) == 0 {
-			return fkConstraintError(value)
+			return fkConstraintError(cols, values)
 		}
-
-		var existingSecKey []byte
-		encode.Encode(keyColumns[:1], &existingSecKey)
-		if len(existingSecKey) != len(encodedSecKey) || string(existingSecKey) != string(encodedSecKey) {
-			return fkConstraintError(value)
+		if !hasSecondaryKeyPrefix(record.KeyBytes(), values) {
+			return fkConstraintError(cols, values)
 		}
 
 		// Shared Lock を先に取得して、他トランザクションの SoftDelete 完了を待つ
@@ -223,34 +203,28 @@ func checkRefValueInUniqueIndex(bp *buffer.BufferPool, trxId handler.TrxId, lock
 		}
 
 		if err := iter.Advance(bp); err != nil {
-			return fkConstraintError(value)
+			return fkConstraintError(cols, values)
 		}
 	}
 }
 
-// checkChildRecordExists は参照元テーブルに指定された値を参照するレコードが存在するかチェックする
+// checkChildRecordExists は参照元テーブルに指定された値の組を参照するレコードが存在するかチェックする
 //
-// 存在する場合はエラーを返す (RESTRICT)
-func checkChildRecordExists(bp *buffer.BufferPool, hdl *handler.Handler, childFK dictionary.ChildForeignKey, value []byte) error {
-	childTable, err := hdl.GetTable(childFK.TableName)
+// 存在する場合はエラーを返す (RESTRICT)。参照アクションが CASCADE / SET NULL の場合は fk_action.go で参照元の行を変更する
+func checkChildRecordExists(bp *buffer.BufferPool, trxId handler.TrxId, hdl *handler.Handler, childFK dictionary.ChildForeignKey, values [][]byte) error {
+	childTable, err := hdl.OpenTable(trxId, childFK.TableName)
 	if err != nil {
 		return err
 	}
 
-	// FK カラムのインデックスで検索
-	var si *access.SecondaryIndex
-	for _, idx := range childTable.SecondaryIndexes {
-		if idx.ColName == childFK.Constraint.ColName {
-			si = idx
-			break
-		}
-	}
-	if si == nil {
-		return fmt.Errorf("index for foreign key column '%s' not found in table '%s'", childFK.Constraint.ColName, childFK.TableName)
+	// FK カラムを先頭のカラムとするインデックスで検索
+	si, err := getFKIndex(childTable, childFK.Constraint.ColNames)
+	if err != nil {
+		return err
 	}
 
 	var encodedSecKey []byte
-	encode.Encode([][]byte{value}, &encodedSecKey)
+	encode.EncodeNullable(values, &encodedSecKey)
 
 	btr := btree.NewBTree(si.MetaPageId)
 	iter, err := btr.Search(bp, btree.SearchModeKey{Key: encodedSecKey})
@@ -264,16 +238,7 @@ func checkChildRecordExists(bp *buffer.BufferPool, hdl *handler.Handler, childFK
 		if !ok {
 			return nil
 		}
-
-		var keyColumns [][]byte
-		encode.Decode(record.KeyBytes(), &keyColumns)
-		if len(keyColumns) == 0 {
-			return nil
-		}
-
-		var existingSecKey []byte
-		encode.Encode(keyColumns[:1], &existingSecKey)
-		if len(existingSecKey) != len(encodedSecKey) || string(existingSecKey) != string(encodedSecKey) {
+		if !hasSecondaryKeyPrefix(record.KeyBytes(), values) {
 			return nil
 		}
 
@@ -289,7 +254,76 @@ func checkChildRecordExists(bp *buffer.BufferPool, hdl *handler.Handler, childFK
 	}
 }
 
+// getFKIndex は参照元テーブルの FK カラムを先頭のカラム (キーの順) とするインデックスを返す
+func getFKIndex(childTable *access.Table, colNames []string) (*access.SecondaryIndex, error) {
+	for _, idx := range childTable.SecondaryIndexes {
+		if len(colNames) <= len(idx.ColNames) && slices.Equal(idx.ColNames[:len(colNames)], colNames) {
+			return idx, nil
+		}
+	}
+	return nil, fmt.Errorf("index for foreign key column '%s' not found in table '%s'", strings.Join(colNames, ", "), childTable.Name)
+}
+
+// hasSecondaryKeyPrefix はセカンダリインデックスのキーの先頭のカラムが values と一致するかを判定する
+func hasSecondaryKeyPrefix(encodedKey []byte, values [][]byte) bool {
+	keyColumns, _ := encode.DecodeNullableFirstN(encodedKey, len(values))
+	return len(keyColumns) == len(values) && equalFKValues(keyColumns, values)
+}
+
+// refersToOwnRow は自己参照 FK の値の組が、挿入・更新するレコード自身の参照先カラムの値の組と一致するかを判定する
+//
+// MySQL と同様に、行が自分自身を参照する場合 (e.g. 自己参照 FK の根の行) は参照先が存在するものとして扱う
+func refersToOwnRow(tableMeta *dictionary.TableMeta, fk *dictionary.ConstraintMeta, record Record, values [][]byte) bool {
+	if fk.RefTableName != tableMeta.Name {
+		return false
+	}
+	refPositions, err := tableMeta.GetColPositions(fk.RefColNames)
+	if err != nil {
+		return false
+	}
+	return equalFKValues(getFKValues(record, refPositions), values)
+}
+
+// getFKCols はテーブルから指定された位置のカラムのメタデータを取得する
+func getFKCols(tableMeta *dictionary.TableMeta, positions []uint16) []*dictionary.ColumnMeta {
+	sortedCols := tableMeta.GetSortedCols()
+	cols := make([]*dictionary.ColumnMeta, len(positions))
+	for i, pos := range positions {
+		cols[i] = sortedCols[pos]
+	}
+	return cols
+}
+
+// getFKValues はレコードから FK カラム (または参照先カラム) の値の組を取得する
+func getFKValues(record Record, positions []uint16) [][]byte {
+	values := make([][]byte, len(positions))
+	for i, pos := range positions {
+		values[i] = record[pos]
+	}
+	return values
+}
+
+// hasNullValue は値の組に NULL が含まれるかを判定する (NULL を含む FK は何も参照しない)
+func hasNullValue(values [][]byte) bool {
+	return slices.ContainsFunc(values, func(v []byte) bool { return v == nil })
+}
+
+// equalFKValues は値の組が等しいかを判定する
+//
+// NULL (nil) は空文字列と区別する (セカンダリインデックスのキーには NULL も登録されるため)
+func equalFKValues(a, b [][]byte) bool {
+	return slices.EqualFunc(a, b, func(x, y []byte) bool {
+		return (x == nil) == (y == nil) && bytes.Equal(x, y)
+	})
+}
+
 // fkConstraintError は FK 制約違反のエラーメッセージを生成する
-func fkConstraintError(value []byte) error {
-	return fmt.Errorf("cannot add or update a child row: a foreign key constraint fails (value '%s')", string(value))
+//
+// 値は格納形式のままでは読めないため、FK カラムのデータ型に従って文字列に変換する
+func fkConstraintError(cols []*dictionary.ColumnMeta, values [][]byte) error {
+	formatted := make([]string, len(values))
+	for i, v := range values {
+		formatted[i] = cols[i].FormatValue(v)
+	}
+	return fmt.Errorf("%w (value '%s')", errFKNoParentRow, strings.Join(formatted, ", "))
 }
@@ -2,8 +2,10 @@ package executor
 
 import (
 	"testing"
+	"time"
 
 	"github.com/ren-yamanashi/minesql/internal/storage/access"
+	"github.com/ren-yamanashi/minesql/internal/storage/encode"
 	"github.com/ren-yamanashi/minesql/internal/storage/handler"
 	"github.com/stretchr/testify/assert"
 )
@@ -27,12 +29,12 @@ func TestFKChecker_Insert(t *testing.T) {
 		assert.NoError(t, err)
 
 		childTable := NewCreateTable("orders", 1,
-			[]handler.CreateIndexParam{{Name: "idx_user_id", ColName: "user_id", ColIdx: 1, Unique: false}},
+			[]handler.CreateIndexParam{{Name: "idx_user_id", ColNames: []string{"user_id"}, ColIdxs: []uint16{1}, Unique: false}},
 			[]handler.CreateColumnParam{
 				{Name: "id", Type: "string"},
 				{Name: "user_id", Type: "string"},
 			},
-			[]handler.CreateConstraintParam{{ConstraintName: "fk_user", ColName: "user_id", RefTableName: "users", RefColName: "id"}},
+			[]handler.CreateConstraintParam{{ConstraintName: "fk_user", ColNames: []string{"user_id"}, RefTableName: "users", RefColNames: []string{"id"}}},
 		)
 		_, err = childTable.Next()
 		assert.NoError(t, err)
@@ -75,12 +77,12 @@ func TestFKChecker_Insert(t *testing.T) {
 		assert.NoError(t, err)
 
 		childTable := NewCreateTable("orders", 1,
-			[]handler.CreateIndexParam{{Name: "idx_user_id", ColName: "user_id", ColIdx: 1, Unique: false}},
+			[]handler.CreateIndexParam{{Name: "idx_user_id", ColNames: []string{"user_id"}, ColIdxs: []uint16{1}, Unique: false}},
 			[]handler.CreateColumnParam{
 				{Name: "id", Type: "string"},
 				{Name: "user_id", Type: "string"},
 			},
-			[]handler.CreateConstraintParam{{ConstraintName: "fk_user", ColName: "user_id", RefTableName: "users", RefColName: "id"}},
+			[]handler.CreateConstraintParam{{ConstraintName: "fk_user", ColNames: []string{"user_id"}, RefTableName: "users", RefColNames: []string{"id"}}},
 		)
 		_, err = childTable.Next()
 		assert.NoError(t, err)
@@ -97,6 +99,41 @@ func TestFKChecker_Insert(t *testing.T) {
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "foreign key constraint fails")
 	})
+
+	t.Run("自己参照 FK で自分自身を参照する行は INSERT できる", func(t *testing.T) {
+		// GIVEN
+		hdl := initFKActionTest(t)
+		trxId := hdl.BeginTrx()
+		createFKActionTable(t, "categories", "parent_id", handler.CreateConstraintParam{
+			ConstraintName: "fk_parent", RefTableName: "categories",
+		})
+		categoriesTable, err := hdl.GetTable("categories")
+		assert.NoError(t, err)
+
+		// WHEN
+		_, err = NewInsert(trxId, categoriesTable, []Record{{[]byte("1"), []byte("1")}}).Next()
+
+		// THEN
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"1"}, scanFKActionColumn(t, "categories", 1))
+	})
+
+	t.Run("自己参照 FK で存在しない別の行を参照する行は INSERT できない", func(t *testing.T) {
+		// GIVEN
+		hdl := initFKActionTest(t)
+		trxId := hdl.BeginTrx()
+		createFKActionTable(t, "categories", "parent_id", handler.CreateConstraintParam{
+			ConstraintName: "fk_parent", RefTableName: "categories",
+		})
+		categoriesTable, err := hdl.GetTable("categories")
+		assert.NoError(t, err)
+
+		// WHEN
+		_, err = NewInsert(trxId, categoriesTable, []Record{{[]byte("1"), []byte("2")}}).Next()
+
+		// THEN
+		assert.ErrorIs(t, err, errFKNoParentRow)
+	})
 }
 
 func TestFKChecker_Delete(t *testing.T) {
@@ -118,12 +155,12 @@ func TestFKChecker_Delete(t *testing.T) {
 		assert.NoError(t, err)
 
 		childTable := NewCreateTable("orders", 1,
-			[]handler.CreateIndexParam{{Name: "idx_user_id", ColName: "user_id", ColIdx: 1, Unique: false}},
+			[]handler.CreateIndexParam{{Name: "idx_user_id", ColNames: []string{"user_id"}, ColIdxs: []uint16{1}, Unique: false}},
 			[]handler.CreateColumnParam{
 				{Name: "id", Type: "string"},
 				{Name: "user_id", Type: "string"},
 			},
-			[]handler.CreateConstraintParam{{ConstraintName: "fk_user", ColName: "user_id", RefTableName: "users", RefColName: "id"}},
+			[]handler.CreateConstraintParam{{ConstraintName: "fk_user", ColNames: []string{"user_id"}, RefTableName: "users", RefColNames: []string{"id"}}},
 		)
 		_, err = childTable.Next()
 		assert.NoError(t, err)
@@ -164,12 +201,12 @@ func TestFKChecker_Delete(t *testing.T) {
 		assert.NoError(t, err)
 
 		childTable := NewCreateTable("orders", 1,
-			[]handler.CreateIndexParam{{Name: "idx_user_id", ColName: "user_id", ColIdx: 1, Unique: false}},
+			[]handler.CreateIndexParam{{Name: "idx_user_id", ColNames: []string{"user_id"}, ColIdxs: []uint16{1}, Unique: false}},
 			[]handler.CreateColumnParam{
 				{Name: "id", Type: "string"},
 				{Name: "user_id", Type: "string"},
 			},
-			[]handler.CreateConstraintParam{{ConstraintName: "fk_user", ColName: "user_id", RefTableName: "users", RefColName: "id"}},
+			[]handler.CreateConstraintParam{{ConstraintName: "fk_user", ColNames: []string{"user_id"}, RefTableName: "users", RefColNames: []string{"id"}}},
 		)
 		_, err = childTable.Next()
 		assert.NoError(t, err)
@@ -220,12 +257,12 @@ func TestFKChecker_Update(t *testing.T) {
 		assert.NoError(t, err)
 
 		childTable := NewCreateTable("orders", 1,
-			[]handler.CreateIndexParam{{Name: "idx_user_id", ColName: "user_id", ColIdx: 1, Unique: false}},
+			[]handler.CreateIndexParam{{Name: "idx_user_id", ColNames: []string{"user_id"}, ColIdxs: []uint16{1}, Unique: false}},
 			[]handler.CreateColumnParam{
 				{Name: "id", Type: "string"},
 				{Name: "user_id", Type: "string"},
 			},
-			[]handler.CreateConstraintParam{{ConstraintName: "fk_user", ColName: "user_id", RefTableName: "users", RefColName: "id"}},
+			[]handler.CreateConstraintParam{{ConstraintName: "fk_user", ColNames: []string{"user_id"}, RefTableName: "users", RefColNames: []string{"id"}}},
 		)
 		_, err = childTable.Next()
 		assert.NoError(t, err)
@@ -275,12 +312,12 @@ func TestFKChecker_Update(t *testing.T) {
 		assert.NoError(t, err)
 
 		childTable := NewCreateTable("orders", 1,
-			[]handler.CreateIndexParam{{Name: "idx_user_id", ColName: "user_id", ColIdx: 1, Unique: false}},
+			[]handler.CreateIndexParam{{Name: "idx_user_id", ColNames: []string{"user_id"}, ColIdxs: []uint16{1}, Unique: false}},
 			[]handler.CreateColumnParam{
 				{Name: "id", Type: "string"},
 				{Name: "user_id", Type: "string"},
 			},
-			[]handler.CreateConstraintParam{{ConstraintName: "fk_user", ColName: "user_id", RefTableName: "users", RefColName: "id"}},
+			[]handler.CreateConstraintParam{{ConstraintName: "fk_user", ColNames: []string{"user_id"}, RefTableName: "users", RefColNames: []string{"id"}}},
 		)
 		_, err = childTable.Next()
 		assert.NoError(t, err)
@@ -311,3 +348,159 @@ func TestFKChecker_Update(t *testing.T) {
 		assert.Contains(t, err.Error(), "foreign key constraint fails")
 	})
 }
+
+func TestFKChecker_CompositeKey(t *testing.T) {
+	t.Run("FK カラムの値の組が参照先の複合主キーに存在する場合、INSERT が成功する", func(t *testing.T) {
+		// GIVEN
+		hdl := initCompositeFKTest(t)
+		trxId := hdl.BeginTrx()
+		insertFKActionRecords(t, trxId, "users", Record{[]byte("1"), []byte("1")}, Record{[]byte("2"), []byte("2")})
+
+		// WHEN
+		ordersTable, err := hdl.GetTable("orders")
+		assert.NoError(t, err)
+		_, err = NewInsert(trxId, ordersTable, []Record{{[]byte("1"), []byte("100"), []byte("1")}}).Next()
+
+		// THEN
+		assert.NoError(t, err)
+	})
+
+	t.Run("各カラムの値が参照先に存在しても、値の組が存在しない場合は INSERT がエラーになる", func(t *testing.T) {
+		// GIVEN: users には (1, 1) と (2, 2) のみ存在する
+		hdl := initCompositeFKTest(t)
+		trxId := hdl.BeginTrx()
+		insertFKActionRecords(t, trxId, "users", Record{[]byte("1"), []byte("1")}, Record{[]byte("2"), []byte("2")})
+
+		// WHEN: (1, 2) を参照する行を挿入
+		ordersTable, err := hdl.GetTable("orders")
+		assert.NoError(t, err)
+		_, err = NewInsert(trxId, ordersTable, []Record{{[]byte("1"), []byte("100"), []byte("2")}}).Next()
+
+		// THEN
+		assert.EqualError(t, err, "cannot add or update a child row: a foreign key constraint fails (value '1, 2')")
+	})
+
+	t.Run("FK カラムのいずれかが NULL の場合は参照先の存在チェックを行わない", func(t *testing.T) {
+		// GIVEN
+		hdl := initCompositeFKTest(t)
+		trxId := hdl.BeginTrx()
+
+		// WHEN
+		ordersTable, err := hdl.GetTable("orders")
+		assert.NoError(t, err)
+		_, err = NewInsert(trxId, ordersTable, []Record{{[]byte("1"), []byte("100"), nil}}).Next()
+
+		// THEN
+		assert.NoError(t, err)
+	})
+
+	t.Run("値の組で参照されている親レコードは削除できず、参照されていない親レコードは削除できる", func(t *testing.T) {
+		// GIVEN: orders は (1, 1) のみ参照する
+		hdl := initCompositeFKTest(t)
+		trxId := hdl.BeginTrx()
+		insertFKActionRecords(t, trxId, "users", Record{[]byte("1"), []byte("1")}, Record{[]byte("1"), []byte("2")})
+		insertFKActionRecords(t, trxId, "orders", Record{[]byte("1"), []byte("100"), []byte("1")})
+		usersTable, err := hdl.GetTable("users")
+		assert.NoError(t, err)
+
+		// WHEN
+		errReferenced := deleteCompositeFKUser(trxId, usersTable, "1")
+		errUnreferenced := deleteCompositeFKUser(trxId, usersTable, "2")
+
+		// THEN
+		assert.EqualError(t, errReferenced, "cannot delete or update a parent row: a foreign key constraint fails")
+		assert.NoError(t, errUnreferenced)
+		assert.Equal(t, []string{"1"}, scanFKActionColumn(t, "users", 1))
+	})
+
+	t.Run("FK カラムの値の組が参照先の複合 UNIQUE インデックスに存在するかを確認する", func(t *testing.T) {
+		// GIVEN: products (id, shop_id, code) の UNIQUE (shop_id, code) を参照する
+		hdl := initFKActionTest(t)
+		trxId := hdl.BeginTrx()
+		_, err := NewCreateTable("products", 1,
+			[]handler.CreateIndexParam{{Name: "uk_shop_code", ColNames: []string{"shop_id", "code"}, ColIdxs: []uint16{1, 2}, Unique: true}},
+			[]handler.CreateColumnParam{{Name: "id", Type: "string"}, {Name: "shop_id", Type: "string"}, {Name: "code", Type: "string"}},
+			nil,
+		).Next()
+		assert.NoError(t, err)
+		_, err = NewCreateTable("stocks", 1,
+			[]handler.CreateIndexParam{{Name: "idx_shop_code", ColNames: []string{"shop_id", "code"}, ColIdxs: []uint16{1, 2}}},
+			[]handler.CreateColumnParam{{Name: "id", Type: "string"}, {Name: "shop_id", Type: "string"}, {Name: "code", Type: "string"}},
+			[]handler.CreateConstraintParam{{ConstraintName: "fk_product", ColNames: []string{"shop_id", "code"}, RefTableName: "products", RefColNames: []string{"shop_id", "code"}}},
+		).Next()
+		assert.NoError(t, err)
+		insertFKActionRecords(t, trxId, "products", Record{[]byte("1"), []byte("s1"), []byte("A")})
+		stocksTable, err := hdl.GetTable("stocks")
+		assert.NoError(t, err)
+
+		// WHEN
+		_, errExists := NewInsert(trxId, stocksTable, []Record{{[]byte("10"), []byte("s1"), []byte("A")}}).Next()
+		_, errNotExists := NewInsert(trxId, stocksTable, []Record{{[]byte("20"), []byte("s1"), []byte("B")}}).Next()
+
+		// THEN
+		assert.NoError(t, errExists)
+		assert.EqualError(t, errNotExists, "cannot add or update a child row: a foreign key constraint fails (value 's1, B')")
+	})
+
+	t.Run("FK カラムが INT / DECIMAL / DATE の場合、エラーメッセージには値をデータ型に従った文字列で表示する", func(t *testing.T) {
+		// GIVEN: rates (id, rate, day) の複合主キーを stocks (id, rate, day) で参照する
+		hdl := initFKActionTest(t)
+		trxId := hdl.BeginTrx()
+		_, err := NewCreateTable("rates", 3, nil, []handler.CreateColumnParam{
+			{Name: "id", Type: handler.ColumnTypeInt},
+			{Name: "rate", Type: handler.ColumnTypeDecimal, Precision: 5, Scale: 2},
+			{Name: "day", Type: handler.ColumnTypeDate},
+		}, nil).Next()
+		assert.NoError(t, err)
+		_, err = NewCreateTable("stocks", 1,
+			[]handler.CreateIndexParam{{Name: "idx_rate", ColNames: []string{"rate_id", "rate", "day"}, ColIdxs: []uint16{1, 2, 3}}},
+			[]handler.CreateColumnParam{
+				{Name: "id", Type: handler.ColumnTypeString},
+				{Name: "rate_id", Type: handler.ColumnTypeInt},
+				{Name: "rate", Type: handler.ColumnTypeDecimal, Precision: 5, Scale: 2},
+				{Name: "day", Type: handler.ColumnTypeDate},
+			},
+			[]handler.CreateConstraintParam{{ConstraintName: "fk_rate", ColNames: []string{"rate_id", "rate", "day"}, RefTableName: "rates", RefColNames: []string{"id", "rate", "day"}}},
+		).Next()
+		assert.NoError(t, err)
+		stocksTable, err := hdl.GetTable("stocks")
+		assert.NoError(t, err)
+		record := Record{[]byte("1"), encode.EncodeInt64(7), encode.EncodeInt64(1250), encode.EncodeInt64(time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC).Unix())}
+
+		// WHEN
+		_, err = NewInsert(trxId, stocksTable, []Record{record}).Next()
+
+		// THEN
+		assert.EqualError(t, err, "cannot add or update a child row: a foreign key constraint fails (value '7, 12.50, 2024-01-02')")
+	})
+}
+
+// initCompositeFKTest は複合主キー (tenant_id, id) の users と、それを (tenant_id, user_id) で参照する orders を作成する
+func initCompositeFKTest(t *testing.T) *handler.Handler {
+	t.Helper()
+	hdl := initFKActionTest(t)
+	_, err := NewCreateTable("users", 2, nil, []handler.CreateColumnParam{
+		{Name: "tenant_id", Type: "string"},
+		{Name: "id", Type: "string"},
+	}, nil).Next()
+	assert.NoError(t, err)
+	_, err = NewCreateTable("orders", 2,
+		[]handler.CreateIndexParam{{Name: "idx_tenant_user", ColNames: []string{"tenant_id", "user_id"}, ColIdxs: []uint16{0, 2}}},
+		[]handler.CreateColumnParam{
+			{Name: "tenant_id", Type: "string"},
+			{Name: "id", Type: "string"},
+			{Name: "user_id", Type: "string"},
+		},
+		[]handler.CreateConstraintParam{{ConstraintName: "fk_user", ColNames: []string{"tenant_id", "user_id"}, RefTableName: "users", RefColNames: []string{"tenant_id", "id"}}},
+	).Next()
+	assert.NoError(t, err)
+	return hdl
+}
+
+// deleteCompositeFKUser は users から主キーが (1, id) の行を削除する
+func deleteCompositeFKUser(trxId handler.TrxId, usersTable *access.Table, id string) error {
+	key := [][]byte{[]byte("1"), []byte(id)}
+	scan := testTableScan(usersTable, access.RecordSearchModeKey{Key: key}, func(r Record) bool { return string(r[1]) == id })
+	_, err := NewDelete(trxId, usersTable, scan).Next()
+	return err
+}
@@ -0,0 +1,156 @@
+package executor
+
+import (
+	"bufio"
+	"hash/fnv"
+	"io"
+	"os"
+)
+
+const (
+	gracePartitionCount = 8 // ハッシュテーブルがメモリに収まらない場合の分割数
+	graceMaxLevel       = 3 // 再分割の最大回数 (同じキーの行が多く、分割しても収まらない場合に打ち切る)
+)
+
+// gracePartitioner は、ハッシュテーブルがメモリに収まらない場合に両側のレコードをキーのハッシュ値で分割して一時ファイルに書き出し (Grace Hash)、
+// 分割を 1 つずつ取り出して処理するための状態を管理する
+//
+// HashJoin・HashSetOperation で共有する
+// 分割には、ハッシュテーブルに読み込む側のレコード (table) と、ハッシュテーブルと照合する側のレコード (stream) を書き出す
+// 同じキーのレコードは必ず同じ分割に入るため、分割ごとに処理しても結果は変わらない
+type gracePartitioner struct {
+	tmpDir  string
+	pattern string            // 一時ファイル名のパターン (os.CreateTemp の pattern)
+	pending []*gracePartition // 未処理の分割
+	created []*gracePartition // 作成した全ての分割 (一時ファイルの削除用)
+	current *gracePartition   // 処理中の分割
+}
+
+func newGracePartitioner(tmpDir, pattern string) *gracePartitioner {
+	return &gracePartitioner{tmpDir: tmpDir, pattern: pattern}
+}
+
+// create は指定したレベルの分割 (一時ファイル) を作成する
+func (gp *gracePartitioner) create(level int) ([]*gracePartition, error) {
+	partitions := make([]*gracePartition, gracePartitionCount)
+	for i := range partitions {
+		partition := &gracePartition{level: level}
+		gp.created = append(gp.created, partition)
+		var err error
+		if partition.table, err = newPartitionFile(gp.tmpDir, gp.pattern); err != nil {
+			return nil, err
+		}
+		if partition.stream, err = newPartitionFile(gp.tmpDir, gp.pattern); err != nil {
+			return nil, err
+		}
+		partitions[i] = partition
+	}
+	return partitions, nil
+}
+
+// push は書き出し終えた分割を未処理の分割の先頭に積む (再分割した分割を、残りの分割より先に処理する)
+func (gp *gracePartitioner) push(partitions []*gracePartition) {
+	gp.pending = append(partitions, gp.pending...)
+}
+
+// next は処理済みの分割の一時ファイルを削除し、次の分割を先頭から読み込めるようにして返す
+//
+// 未処理の分割がない場合は nil を返す
+func (gp *gracePartitioner) next() (*gracePartition, error) {
+	if gp.current != nil {
+		gp.current.remove()
+		gp.current = nil
+	}
+	if len(gp.pending) == 0 {
+		return nil, nil
+	}
+	gp.current = gp.pending[0]
+	gp.pending = gp.pending[1:]
+	if err := gp.current.finishWrite(); err != nil {
+		return nil, err
+	}
+	return gp.current, nil
+}
+
+// removeAll は作成した全ての分割の一時ファイルを閉じて削除する
+func (gp *gracePartitioner) removeAll() {
+	for _, partition := range gp.created {
+		partition.remove()
+	}
+	gp.created = nil
+	gp.pending = nil
+	gp.current = nil
+}
+
+// gracePartition はキーのハッシュ値が同じ分割に属する両側のレコードを書き出した一時ファイルの組
+type gracePartition struct {
+	table  *partitionFile // ハッシュテーブルに読み込む側のレコード
+	stream *partitionFile // ハッシュテーブルと照合する側のレコード
+	level  int            // 分割に使ったハッシュ関数のレベル
+}
+
+// finishWrite は両側の書き込みを完了し、先頭から読み込めるようにする
+func (p *gracePartition) finishWrite() error {
+	if err := p.table.finishWrite(); err != nil {
+		return err
+	}
+	return p.stream.finishWrite()
+}
+
+// remove は両側の一時ファイルを閉じて削除する (削除済みの場合は何もしない)
+func (p *gracePartition) remove() {
+	for _, f := range []*partitionFile{p.table, p.stream} {
+		if f != nil {
+			_ = f.file.Close()
+			_ = os.Remove(f.file.Name())
+		}
+	}
+	p.table, p.stream = nil, nil
+}
+
+// partitionFor はキーが属する分割を返す
+//
+// レベルごとに異なるハッシュ関数 (レベルをシードとして先頭に加える) を使い、再分割でレコードが分散するようにする
+func partitionFor(partitions []*gracePartition, key string, level int) *gracePartition {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte{byte(level)})
+	_, _ = h.Write([]byte(key))
+	return partitions[h.Sum64()%gracePartitionCount]
+}
+
+// partitionFile は分割したレコードを書き出す一時ファイル (形式はソートのランと同じ)
+type partitionFile struct {
+	file   *os.File
+	writer *bufio.Writer
+	reader *bufio.Reader
+}
+
+func newPartitionFile(tmpDir, pattern string) (*partitionFile, error) {
+	file, err := os.CreateTemp(tmpDir, pattern)
+	if err != nil {
+		return nil, err
+	}
+	return &partitionFile{file: file, writer: bufio.NewWriter(file)}, nil
+}
+
+// write はレコードを書き込む
+func (f *partitionFile) write(record Record) error {
+	return writeRunRecord(f.writer, record)
+}
+
+// read はレコードを 1 件読み込む (読み終えた場合は nil を返す)
+func (f *partitionFile) read() (Record, error) {
+	return readRunRecord(f.reader)
+}
+
+// finishWrite は書き込みを完了し、先頭から読み込めるようにする
+func (f *partitionFile) finishWrite() error {
+	if err := f.writer.Flush(); err != nil {
+		return err
+	}
+	if _, err := f.file.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	f.reader = bufio.NewReader(f.file)
+	return nil
+}
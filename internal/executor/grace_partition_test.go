@@ -0,0 +1,105 @@
+package executor
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGracePartitioner(t *testing.T) {
+	t.Run("キーに対応する分割に書き出したレコードを、分割を取り出して両側とも読み込める", func(t *testing.T) {
+		// GIVEN
+		gp := newGracePartitioner(t.TempDir(), "minesql-test-*.tmp")
+		defer gp.removeAll()
+		partitions, err := gp.create(0)
+		require.NoError(t, err)
+		require.Len(t, partitions, gracePartitionCount)
+		target := partitionFor(partitions, "a", 0)
+		require.NoError(t, target.table.write(Record{[]byte("a"), []byte("table")}))
+		require.NoError(t, target.stream.write(Record{[]byte("a"), []byte("stream")}))
+		gp.push(partitions)
+
+		// WHEN
+		var partition *gracePartition
+		for partition != target {
+			partition, err = gp.next()
+			require.NoError(t, err)
+			require.NotNil(t, partition)
+		}
+		table, err := partition.table.read()
+		require.NoError(t, err)
+		stream, err := partition.stream.read()
+		require.NoError(t, err)
+
+		// THEN
+		assert.Equal(t, Record{[]byte("a"), []byte("table")}, table)
+		assert.Equal(t, Record{[]byte("a"), []byte("stream")}, stream)
+	})
+
+	t.Run("レベルが異なると異なるハッシュ関数で分割する", func(t *testing.T) {
+		// GIVEN
+		gp := newGracePartitioner(t.TempDir(), "minesql-test-*.tmp")
+		defer gp.removeAll()
+		partitions, err := gp.create(0)
+		require.NoError(t, err)
+		keys := []string{"00", "01", "02", "03", "04", "05", "06", "07", "08", "09"}
+
+		// WHEN
+		differs := false
+		for _, key := range keys {
+			if partitionFor(partitions, key, 0) != partitionFor(partitions, key, 1) {
+				differs = true
+			}
+		}
+
+		// THEN
+		assert.True(t, differs)
+	})
+
+	t.Run("push した分割は未処理の分割より先に取り出される", func(t *testing.T) {
+		// GIVEN
+		gp := newGracePartitioner(t.TempDir(), "minesql-test-*.tmp")
+		defer gp.removeAll()
+		first, err := gp.create(0)
+		require.NoError(t, err)
+		second, err := gp.create(1)
+		require.NoError(t, err)
+		gp.push(first)
+
+		// WHEN
+		_, err = gp.next()
+		require.NoError(t, err)
+		gp.push(second)
+		partition, err := gp.next()
+		require.NoError(t, err)
+
+		// THEN
+		assert.Equal(t, 1, partition.level)
+	})
+
+	t.Run("next で処理済みの分割の一時ファイルを削除し、removeAll で全ての一時ファイルを削除する", func(t *testing.T) {
+		// GIVEN
+		tmpDir := t.TempDir()
+		gp := newGracePartitioner(tmpDir, "minesql-test-*.tmp")
+		partitions, err := gp.create(0)
+		require.NoError(t, err)
+		gp.push(partitions)
+		_, err = gp.next()
+		require.NoError(t, err)
+		_, err = gp.next()
+		require.NoError(t, err)
+
+		// WHEN
+		entries, err := os.ReadDir(tmpDir)
+		require.NoError(t, err)
+		gp.removeAll()
+
+		// THEN: 取り出した 2 つのうち、処理済みの 1 つ目の分割の両側の一時ファイルが削除されている
+		assert.Len(t, entries, 2*(gracePartitionCount-1))
+		entries, err = os.ReadDir(tmpDir)
+		require.NoError(t, err)
+		assert.Empty(t, entries)
+	})
+}
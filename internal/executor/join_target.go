@@ -0,0 +1,56 @@
+package executor
+
+import (
+	"github.com/ren-yamanashi/minesql/internal/storage/access"
+)
+
+// joinTarget は複数テーブルの UPDATE / DELETE で、InnerExecutor が返す結合レコード内の更新・削除対象のテーブルのカラムの位置
+type joinTarget struct {
+	offset int // 結合レコード内の対象テーブルの先頭カラムの位置
+	nCols  int // 対象テーブルのカラム数
+}
+
+// collectTargetRecords は InnerExecutor の結果をすべて取得し、更新・削除対象の行を返す
+//
+// target が nil の場合 (単一テーブルの場合) は InnerExecutor の結果をそのまま返す (sources は nil)
+// target を指定した場合は結合レコードから対象テーブルの行を取り出し、行ごとに取り出し元の結合レコードを sources に返す
+//   - 同じ行が複数の結合レコードに含まれる場合は、最初の結合レコードのみを使う (同じ行を 2 回更新・削除しない)
+//   - 外部結合で NULL で補完された行 (対象テーブルに対応する行がない場合) は対象外
+func collectTargetRecords(innerExecutor Executor, table *access.Table, target *joinTarget) (records []Record, sources []Record, err error) {
+	seen := make(map[string]struct{})
+	for {
+		record, err := innerExecutor.Next()
+		if err != nil {
+			return nil, nil, err
+		}
+		if record == nil {
+			return records, sources, nil
+		}
+		if target == nil {
+			records = append(records, record)
+			continue
+		}
+
+		row := make(Record, target.nCols)
+		copy(row, record[target.offset:target.offset+target.nCols])
+		// プライマリキーは NULL にならないため、先頭のカラムが NULL の場合は NULL で補完された行
+		if row[0] == nil {
+			continue
+		}
+		key := string(table.EncodeKey(row))
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		records = append(records, row)
+		sources = append(sources, record)
+	}
+}
+
+// sourceAt は i 番目の行の取り出し元の結合レコードを返す (単一テーブルの場合は nil)
+func sourceAt(sources []Record, i int) Record {
+	if sources == nil {
+		return nil
+	}
+	return sources[i]
+}
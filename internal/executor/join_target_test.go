@@ -0,0 +1,47 @@
+package executor
+
+import (
+	"testing"
+
+	"github.com/ren-yamanashi/minesql/internal/storage/access"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCollectTargetRecords(t *testing.T) {
+	t.Run("target が nil の場合、InnerExecutor の結果をそのまま返す", func(t *testing.T) {
+		// GIVEN
+		inner := &mockExecutor{records: []Record{
+			{[]byte("1"), []byte("Alice")},
+			{[]byte("1"), []byte("Alice")},
+		}}
+
+		// WHEN
+		records, sources, err := collectTargetRecords(inner, &access.Table{PrimaryKeyCount: 1}, nil)
+
+		// THEN
+		assert.NoError(t, err)
+		assert.Equal(t, []Record{{[]byte("1"), []byte("Alice")}, {[]byte("1"), []byte("Alice")}}, records)
+		assert.Nil(t, sources)
+	})
+
+	t.Run("結合レコードから対象テーブルの行を取り出し、同じ行と NULL で補完された行を除く", func(t *testing.T) {
+		// GIVEN: orders (id, user_id) + users (id, name) の結合レコードから users の行を取り出す
+		inner := &mockExecutor{records: []Record{
+			{[]byte("10"), []byte("1"), []byte("1"), []byte("Alice")},
+			{[]byte("11"), []byte("1"), []byte("1"), []byte("Alice")},
+			{[]byte("12"), []byte("9"), nil, nil},
+			{[]byte("13"), []byte("2"), []byte("2"), []byte("Bob")},
+		}}
+
+		// WHEN
+		records, sources, err := collectTargetRecords(inner, &access.Table{PrimaryKeyCount: 1}, &joinTarget{offset: 2, nCols: 2})
+
+		// THEN
+		assert.NoError(t, err)
+		assert.Equal(t, []Record{{[]byte("1"), []byte("Alice")}, {[]byte("2"), []byte("Bob")}}, records)
+		assert.Equal(t, []Record{
+			{[]byte("10"), []byte("1"), []byte("1"), []byte("Alice")},
+			{[]byte("13"), []byte("2"), []byte("2"), []byte("Bob")},
+		}, sources)
+	})
+}
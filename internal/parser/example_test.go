@@ -68,6 +68,7 @@ func runSQL(trxId handler.TrxId, sql string) []executor.Record {
 	if err != nil {
 		panic(err)
 	}
+	defer plan.Exec.Close()
 
 	var records []executor.Record
 	for {
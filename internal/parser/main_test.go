@@ -1,4 +1,4 @@
-package parser
+package parser_test
 
 import (
 	"fmt"
@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/ren-yamanashi/minesql/internal/executor"
+	"github.com/ren-yamanashi/minesql/internal/parser"
 	"github.com/ren-yamanashi/minesql/internal/planner"
 	"github.com/ren-yamanashi/minesql/internal/storage/handler"
 	"github.com/stretchr/testify/assert"
@@ -319,6 +320,7 @@ INSERT INTO users (id, first_name, last_name, gender, username) VALUES
 // Executor から全レコードを取得する
 func fetchAll(t *testing.T, iter executor.Executor) []executor.Record {
 	t.Helper()
+	defer iter.Close()
 	var records []executor.Record
 	for {
 		record, err := iter.Next()
@@ -333,7 +335,7 @@ func fetchAll(t *testing.T, iter executor.Executor) []executor.Record {
 // SQL をパース → プラン → 実行して結果を返す
 func executeSql(t *testing.T, trxId handler.TrxId, sql string) []executor.Record {
 	t.Helper()
-	p := NewParser()
+	p := parser.NewParser()
 	result, err := p.Parse(sql)
 	assert.NoError(t, err)
 
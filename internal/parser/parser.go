@@ -14,14 +14,37 @@ const (
 
 	// -- SELECT Statement --
 
-	SelectStateColumns   // SELECT 中
-	SelectStateFrom      // FROM 中
-	SelectStateInner     // INNER キーワード後、JOIN キーワード待ち
-	SelectStateJoin      // JOIN キーワード後、テーブル名待ち
-	SelectStateJoinTable // JOIN テーブル名取得後、ON キーワード待ち
-	SelectStateOn        // ON 条件式の解析中
-	SelectStateWhere     // WHERE 中
-	SelectStateEnd       // SELECT Statement の終わり
+	SelectStateWith        // WITH 句の共通テーブル式の解析中 (共通テーブル式のパーサーにトークンを転送する)
+	SelectStateWithEnd     // 共通テーブル式の ")" の後、"," または SELECT キーワード待ちの状態
+	SelectStateColumns     // SELECT 中
+	SelectStateColumnAs    // SELECT リストの AS キーワード後、別名待ちの状態
+	SelectStateColumnAlias // SELECT リストの別名の後、"," / FROM 待ちの状態
+	SelectStateFrom        // FROM 中
+	SelectStateDerived     // FROM / JOIN の "(" の後、導出テーブルのサブクエリ (SELECT キーワード) 待ちの状態
+	SelectStateDerivedEnd  // 導出テーブルのサブクエリの ")" の後、AS キーワードまたは別名待ちの状態
+	SelectStateDerivedAs   // 導出テーブルの AS キーワード後、別名待ちの状態
+	SelectStateInner       // INNER キーワード後、JOIN キーワード待ち
+	SelectStateOuter       // LEFT / RIGHT キーワード後、OUTER または JOIN キーワード待ち
+	SelectStateOuterKw     // LEFT / RIGHT OUTER キーワード後、JOIN キーワード待ち
+	SelectStateJoin        // JOIN キーワード後、テーブル名待ち
+	SelectStateJoinTable   // JOIN テーブル名取得後、ON キーワード待ち
+	SelectStateOn          // ON 条件式の解析中
+	SelectStateWhere       // WHERE 中
+	SelectStateGroup       // GROUP キーワード後、BY キーワード待ち
+	SelectStateGroupBy     // GROUP BY 中であり、カラム名待ちの状態 | `GROUP BY col1, col2` の "col1" または "," の後の "col2" 待ち
+	SelectStateGroupByCol  // GROUP BY のカラム名の後、"," / HAVING / ORDER / LIMIT / ";" 待ちの状態
+	SelectStateHaving      // HAVING 中
+	SelectStateOrder       // ORDER キーワード後、BY キーワード待ち
+	SelectStateOrderBy     // ORDER BY 中であり、カラム名待ちの状態 | `ORDER BY col1, col2` の "col1" または "," の後の "col2" 待ち
+	SelectStateOrderByCol  // ORDER BY のカラム名の後、ASC / DESC / "," / ";" 待ちの状態
+	SelectStateOrderByDir  // ORDER BY の ASC / DESC の後、"," / ";" 待ちの状態
+	SelectStateLimit       // LIMIT キーワード後、件数待ちの状態
+	SelectStateLimitCount  // LIMIT の件数の後、OFFSET / ";" 待ちの状態
+	SelectStateOffset      // OFFSET キーワード後、件数待ちの状態
+	SelectStateOffsetEnd   // OFFSET の件数の後、";" 待ちの状態
+	SelectStateSetOp       // UNION / INTERSECT / EXCEPT キーワード後、ALL / DISTINCT / SELECT キーワード待ちの状態
+	SelectStateSetOpAll    // 集合演算の ALL / DISTINCT キーワード後、SELECT キーワード待ちの状態
+	SelectStateEnd         // SELECT Statement の終わり
 
 	// -- INSERT Statement --
 
@@ -33,6 +56,13 @@ const (
 	InsertStateEndCols   // INSERT のカラムリストが修了し、VALUES キーワード待ちの状態
 	InsertStateValues    // INSERT の VALUES の値指定中 (`INSERT INTO ... VALUES ( ... )` の "(" 中) の状態
 	InsertStateValueList // INSERT の値リスト中 (`INSERT INTO ... VALUES (val1, val2, ...)` の各値 (val1, val2, ...) の指定中の状態)
+	InsertStateSelect    // INSERT ... SELECT の SELECT 文の解析中 (SELECT 文のパーサーにトークンを転送する)
+	InsertStateOn        // ON キーワード後、DUPLICATE キーワード待ちの状態
+	InsertStateDuplicate // ON DUPLICATE キーワード後、KEY キーワード待ちの状態
+	InsertStateDupKey    // ON DUPLICATE KEY キーワード後、UPDATE キーワード待ちの状態
+	InsertStateSet       // ON DUPLICATE KEY UPDATE 句のカラム名待ちの状態
+	InsertStateSetCol    // ON DUPLICATE KEY UPDATE 句のカラム名の後、"=" 待ちの状態
+	InsertStateSetEq     // ON DUPLICATE KEY UPDATE 句の "=" の後、値の式を解析中の状態 | "," or ";" で値を確定する
 	InsertStateEnd       // INSERT Statement の終わり
 
 	// -- CREATE Statement --
@@ -44,6 +74,13 @@ const (
 	CreateStateBody                    // CREATE TABLE の Body 部中
 	CreateStateColDef                  // CREATE TABLE のカラム指定中であり、データ型定義待ちの状態
 	CreateStateColWaitDefEnd           // CREATE TABLE のカラム定義修了待ち
+	CreateStateColTypeArgPrecision     // CREATE TABLE のデータ型の引数中であり、精度待ちの状態 | `DECIMAL(p, s)` の "p" 待ち
+	CreateStateColTypeArgSeparator     // CREATE TABLE のデータ型の引数中であり、区切り文字 ("," または ")") 待ちの状態
+	CreateStateColTypeArgScale         // CREATE TABLE のデータ型の引数中であり、スケール待ちの状態 | `DECIMAL(p, s)` の "s" 待ち
+	CreateStateColTypeArgEnd           // CREATE TABLE のデータ型の引数中であり、")" 待ちの状態
+	CreateStateColNot                  // CREATE TABLE のカラム定義で NOT キーワードの後、NULL キーワード待ちの状態
+	CreateStateColDefault              // CREATE TABLE のカラム定義で DEFAULT キーワードの後、デフォルト値待ちの状態 | `DEFAULT 0`, `DEFAULT 'a'`, `DEFAULT NULL` の値または `DEFAULT (expr)` の "(" 待ち
+	CreateStateColDefaultExpr          // CREATE TABLE のカラム定義で DEFAULT (expr) の式を解析中の状態 | `DEFAULT (expr)` の ")" で式を確定する
 	CreateStateConstraint              // CREATE TABLE の KEY 制約中
 	CreateStateConstraintKey           // CREATE TABLE の PRIMARY KEY または UNIQUE KEY (現在は KEY キーワードの直後) の状態 | `UNIQUE KEY index_name` の "index_name" または `PRIMARY KEY (...)` の "(" 待ち
 	CreateStateConstraintCol           // CREATE TABLE の KEY 制約のカラム名を指定中 (または指定待ち) の状態 | `PRIMARY KEY (col1, col2, ...)` または `UNIQUE KEY index_name (col1, col2, ...)` の "(" の直後か、"," の直後の状態
@@ -57,33 +94,117 @@ const (
 	CreateStateConstraintFKRefColOpen  // CREATE TABLE の FOREIGN KEY 制約中であり、参照先カラムリスト開始待ちの状態 | `REFERENCES ref_table (...)` の "(" 待ち
 	CreateStateConstraintFKRefColName  // CREATE TABLE の FOREIGN KEY 制約の参照先カラム名を指定中の状態 | `REFERENCES ref_table (ref_col)` の "ref_col" 待ち
 	CreateStateConstraintFKRefColEnd   // CREATE TABLE の FOREIGN KEY 制約の参照先カラムリスト終了待ちの状態 | `REFERENCES ref_table (ref_col)` の ")" 待ち
+	CreateStateConstraintFKAction      // CREATE TABLE の FOREIGN KEY 制約の参照先カラムリストの後、ON キーワード (または制約定義の終了) 待ちの状態
+	CreateStateConstraintFKOn          // CREATE TABLE の FOREIGN KEY 制約の ON キーワードの後、DELETE または UPDATE キーワード待ちの状態
+	CreateStateConstraintFKActionType  // CREATE TABLE の FOREIGN KEY 制約の参照アクション待ちの状態 | `ON DELETE CASCADE` の "CASCADE" 待ち
+	CreateStateConstraintFKSetNull     // CREATE TABLE の FOREIGN KEY 制約の SET キーワードの後、NULL キーワード待ちの状態 | `ON DELETE SET NULL` の "NULL" 待ち
+	CreateStateConstraintName          // CREATE TABLE の CONSTRAINT キーワードの後、制約名待ちの状態 | `CONSTRAINT chk_name CHECK (...)` の "chk_name" 待ち
+	CreateStateConstraintCheck         // CREATE TABLE の制約名の後、CHECK キーワード待ちの状態
+	CreateStateConstraintCheckOpen     // CREATE TABLE の CHECK 制約中であり、条件式の開始待ちの状態 | `CHECK (expr)` の "(" 待ち
+	CreateStateConstraintCheckExpr     // CREATE TABLE の CHECK 制約の条件式を解析中の状態 | `CHECK (expr)` の ")" で条件式を確定する
 	CreateStateEnd                     // CREATE Statement の終わり
 
 	// -- DELETE Statement --
 
-	DeleteStateDelete // DELETE 中であり、FROM キーワード待ちの状態
+	DeleteStateDelete // DELETE 中であり、FROM キーワード (または削除対象のテーブル名) 待ちの状態
+	DeleteStateTarget // DELETE <table_name> 中であり、FROM キーワード待ちの状態
 	DeleteStateFrom   // DELETE FROM 中であり、テーブル名待ちの状態
+	DeleteStateJoin   // DELETE の JOIN 句の解析中 (JOIN 句のパーサーにトークンを転送する)
 	DeleteStateWhere  // DELETE の WHERE 中
 	DeleteStateEnd    // DELETE Statement の終わり
 
 	// -- UPDATE Statement --
 
 	UpdateStateUpdate // UPDATE 中であり、テーブル名待ちの状態
-	UpdateStateTable  // UPDATE <table_name> 中であり、SET キーワード待ちの状態
+	UpdateStateTable  // UPDATE <table_name> 中であり、SET キーワード (または JOIN 句) 待ちの状態
+	UpdateStateJoin   // UPDATE の JOIN 句の解析中 (JOIN 句のパーサーにトークンを転送する)
 	UpdateStateSet    // SET 句のカラム名待ちの状態
 	UpdateStateSetCol // SET 句のカラム名の後、"=" 待ちの状態
-	UpdateStateSetEq  // SET 句の "=" の後、値待ちの状態
-	UpdateStateSetVal // SET 句の値の後、"," or WHERE or ";" 待ちの状態
+	UpdateStateSetEq  // SET 句の "=" の後、値の式を解析中の状態 | "," or WHERE or ";" で値を確定する
 	UpdateStateWhere  // UPDATE の WHERE 中
 	UpdateStateEnd    // UPDATE Statement の終わり
 
-	// -- ALTER USER Statement --
+	// -- JOIN 句 (UPDATE / DELETE) --
+
+	JoinStateStart   // JOIN 句の開始状態 (INNER / LEFT / RIGHT / JOIN キーワード待ち)
+	JoinStateInner   // INNER キーワード後、JOIN キーワード待ち
+	JoinStateOuter   // LEFT / RIGHT キーワード後、OUTER または JOIN キーワード待ち
+	JoinStateOuterKw // LEFT / RIGHT OUTER キーワード後、JOIN キーワード待ち
+	JoinStateJoin    // JOIN キーワード後、テーブル名待ち
+	JoinStateTable   // JOIN テーブル名取得後、ON キーワード待ち
+	JoinStateOn      // ON 条件式の解析中
+
+	// -- WITH 句 (共通テーブル式) --
+
+	CTEStateName      // 共通テーブル式の名前待ち
+	CTEStateNamed     // 名前の後、カラム名のリストの "(" または AS キーワード待ち
+	CTEStateColumn    // カラム名のリストの "(" または "," の後、カラム名待ち
+	CTEStateColumnSep // カラム名のリストのカラム名の後、"," または ")" 待ち
+	CTEStateColumnEnd // カラム名のリストの ")" の後、AS キーワード待ち
+	CTEStateAs        // AS キーワード後、"(" 待ち
+	CTEStateOpen      // "(" の後、SELECT キーワード待ち
+	CTEStateQuery     // 問い合わせの解析中 (問い合わせのパーサーにトークンを転送する)
+	CTEStateEnd       // 問い合わせの ")" の後 (共通テーブル式の終わり)
+
+	// -- OVER 句 (ウィンドウ関数) --
+
+	WindowStateOver         // OVER キーワード後、"(" 待ち
+	WindowStateOpen         // "(" の後、PARTITION / ORDER / ROWS キーワードまたは ")" 待ち
+	WindowStatePartition    // PARTITION キーワード後、BY キーワード待ち
+	WindowStatePartitionBy  // PARTITION BY または "," の後、カラム名待ち
+	WindowStatePartitionCol // PARTITION BY のカラム名の後、"," / ORDER / ROWS キーワードまたは ")" 待ち
+	WindowStateOrder        // ORDER キーワード後、BY キーワード待ち
+	WindowStateOrderBy      // ORDER BY または "," の後、カラム名待ち
+	WindowStateOrderByCol   // ORDER BY のカラム名の後、ASC / DESC / "," / ROWS キーワードまたは ")" 待ち
+	WindowStateOrderByDir   // ASC / DESC キーワード後、"," / ROWS キーワードまたは ")" 待ち
+	WindowStateRows         // ROWS キーワード後、BETWEEN キーワードまたはフレームの始端待ち
+	WindowStateBound        // フレームの境界 (UNBOUNDED / 数値 / CURRENT) 待ち
+	WindowStateUnbounded    // UNBOUNDED キーワード後、PRECEDING / FOLLOWING キーワード待ち
+	WindowStateOffset       // 境界の行数の後、PRECEDING / FOLLOWING キーワード待ち
+	WindowStateCurrent      // CURRENT キーワード後、ROW キーワード待ち
+	WindowStateBoundEnd     // フレームの境界の後、AND キーワード (BETWEEN の始端の後) または ")" 待ち
+	WindowStateEnd          // OVER 句の ")" の後 (OVER 句の終わり)
 
 	// -- START TRANSACTION Statement --
 
 	StartTxStateStart // START キーワード後、TRANSACTION キーワード待ち
 	StartTxStateEnd   // START TRANSACTION の終わり
 
+	// -- CREATE TABLE / INDEX Statement --
+
+	CreateStateDispatch // CREATE キーワード後、TABLE / UNIQUE / INDEX / VIEW / OR / DATABASE / SCHEMA キーワード待ち
+	CreateStateInner    // CREATE TABLE / CREATE INDEX / CREATE VIEW / CREATE DATABASE の解析中
+
+	// -- CREATE INDEX Statement --
+
+	CreateIndexStateCreate    // CREATE キーワード後、UNIQUE / INDEX キーワード待ち
+	CreateIndexStateUnique    // UNIQUE キーワード後、INDEX キーワード待ち
+	CreateIndexStateIndex     // INDEX キーワード後、インデックス名待ち
+	CreateIndexStateIndexName // インデックス名取得後、ON キーワード待ち
+	CreateIndexStateOn        // ON キーワード後、テーブル名待ち
+	CreateIndexStateTableName // テーブル名取得後、カラムリスト開始 ("(") 待ち
+	CreateIndexStateCol       // "(" または "," の後、カラム名待ち
+	CreateIndexStateColSep    // カラム名の後、"," または ")" 待ち
+	CreateIndexStateEnd       // CREATE INDEX Statement の終わり (";" 待ち)
+
+	// -- DROP TABLE / INDEX Statement --
+
+	DropStateDispatch // DROP キーワード後、TABLE / INDEX / VIEW / DATABASE / SCHEMA キーワード待ち
+	DropStateInner    // DROP TABLE / DROP INDEX / DROP VIEW / DROP DATABASE の解析中
+
+	// -- DROP INDEX Statement --
+
+	DropIndexStateDrop      // DROP キーワード後、INDEX キーワード待ち
+	DropIndexStateIndex     // INDEX キーワード後、インデックス名待ち
+	DropIndexStateIndexName // インデックス名取得後、ON キーワード待ち
+	DropIndexStateOn        // ON キーワード後、テーブル名待ち
+	DropIndexStateEnd       // DROP INDEX Statement の終わり (";" 待ち)
+
+	// -- ALTER Statement --
+
+	AlterStateAlter // ALTER キーワード後、USER または TABLE キーワード待ち
+	AlterStateInner // ALTER USER / ALTER TABLE の解析中
+
 	// -- ALTER USER Statement --
 
 	AlterUserStateAlter      // ALTER キーワード後、USER キーワード待ち
@@ -94,6 +215,69 @@ const (
 	AlterUserStateIdentified // IDENTIFIED キーワード後、BY キー��ード待ち
 	AlterUserStateBy         // BY キーワード後、パスワード (文字列リテラル) 待ち
 	AlterUserStateEnd        // ALTER USER Statement の終わり
+
+	// -- EXPLAIN Statement --
+
+	ExplainStateExplain // EXPLAIN キーワード後、ANALYZE キーワードまたは対象の文 (SELECT, UPDATE, DELETE) 待ち
+	ExplainStateAnalyze // EXPLAIN ANALYZE キーワード後、対象の文待ち
+	ExplainStateStmt    // 対象の文の解析中
+
+	// -- DROP TABLE / VIEW / DATABASE Statement --
+
+	DropObjectStateDrop   // DROP キーワード後、対象を表すキーワード (TABLE / VIEW / DATABASE / SCHEMA) 待ち
+	DropObjectStateKind   // 対象を表すキーワード後、IF キーワードまたは対象の名前待ち
+	DropObjectStateIf     // IF キーワード後、EXISTS キーワード待ち
+	DropObjectStateExists // IF EXISTS キーワード後、対象の名前待ち
+	DropObjectStateEnd    // DROP TABLE / VIEW / DATABASE Statement の終わり
+
+	// -- CREATE VIEW Statement --
+
+	CreateViewStateCreate    // CREATE キーワード後、OR / VIEW キーワード待ち
+	CreateViewStateOr        // OR キーワード後、REPLACE キーワード待ち
+	CreateViewStateReplace   // OR REPLACE キーワード後、VIEW キーワード待ち
+	CreateViewStateView      // VIEW キーワード後、ビュー名待ち
+	CreateViewStateName      // ビュー名取得後、カラム名のリストの "(" または AS キーワード待ち
+	CreateViewStateColumn    // カラム名のリストの "(" または "," の後、カラム名待ち
+	CreateViewStateColumnSep // カラム名のリストのカラム名の後、"," または ")" 待ち
+	CreateViewStateColumnEnd // カラム名のリストの ")" の後、AS キーワード待ち
+	CreateViewStateAs        // AS キーワード後、SELECT / WITH キーワード待ち
+	CreateViewStateQuery     // 問い合わせの解析中 (問い合わせのパーサーにトークンを転送する)
+	CreateViewStateEnd       // CREATE VIEW Statement の終わり
+
+	// -- CREATE DATABASE Statement --
+
+	CreateDatabaseStateCreate   // CREATE キーワード後、DATABASE / SCHEMA キーワード待ち
+	CreateDatabaseStateDatabase // DATABASE キーワード後、IF キーワードまたはデータベース名待ち
+	CreateDatabaseStateIf       // IF キーワード後、NOT キーワード待ち
+	CreateDatabaseStateNot      // IF NOT キーワード後、EXISTS キーワード待ち
+	CreateDatabaseStateExists   // IF NOT EXISTS キーワード後、データベース名待ち
+	CreateDatabaseStateEnd      // CREATE DATABASE Statement の終わり
+
+	// -- USE Statement --
+
+	UseStateUse // USE キーワード後、データベース名待ち
+	UseStateEnd // USE Statement の終わり
+
+	// -- TRUNCATE TABLE Statement --
+
+	TruncateStateTruncate // TRUNCATE キーワード後、TABLE キーワードまたはテーブル名待ち
+	TruncateStateTable    // TABLE キーワード後、テーブル名待ち
+	TruncateStateEnd      // TRUNCATE TABLE Statement の終わり
+
+	// -- ALTER TABLE Statement --
+
+	AlterTableStateAlter       // ALTER キーワード後、TABLE キーワード待ち
+	AlterTableStateTable       // TABLE キーワード後、テーブル名待ち
+	AlterTableStateTableName   // テーブル名取得後、ADD / DROP キーワード待ち
+	AlterTableStateAdd         // ADD キーワード後、COLUMN / UNIQUE / KEY / INDEX / FOREIGN キーワードまたはカラム名待ち
+	AlterTableStateAddColumn   // ADD COLUMN キーワード後、カラム名待ち
+	AlterTableStateAddDef      // ADD のカラム定義・制約定義の解析中
+	AlterTableStateDrop        // DROP キーワード後、COLUMN / KEY / INDEX / FOREIGN キーワードまたはカラム名待ち
+	AlterTableStateDropColumn  // DROP COLUMN キーワード後、カラム名待ち
+	AlterTableStateDropIndex   // DROP KEY / INDEX キーワード後、インデックス名待ち
+	AlterTableStateDropForeign // DROP FOREIGN キーワード後、KEY キーワード待ち
+	AlterTableStateDropFKName  // DROP FOREIGN KEY キーワード後、制約名待ち
+	AlterTableStateEnd         // ALTER TABLE Statement の終わり (";" 待ち)
 )
 
 type Parser struct {
@@ -133,7 +317,7 @@ func (p *Parser) onKeyword(word string) {
 
 	// 最初のキーワードに応じて、適切な StatementParser を生成してデリゲートする
 	switch upper {
-	case KSelect:
+	case KSelect, KWith:
 		p.currentParser = NewSelectParser()
 		p.currentParser.onKeyword(word)
 		return
@@ -143,7 +327,7 @@ func (p *Parser) onKeyword(word string) {
 		p.currentParser.onKeyword(word)
 		return
 
-	case KInsert:
+	case KInsert, KReplace:
 		p.currentParser = NewInsertParser()
 		p.currentParser.onKeyword(word)
 		return
@@ -159,7 +343,27 @@ func (p *Parser) onKeyword(word string) {
 		return
 
 	case KAlter:
-		p.currentParser = NewAlterUserParser()
+		p.currentParser = NewAlterParser()
+		p.currentParser.onKeyword(word)
+		return
+
+	case KExplain:
+		p.currentParser = NewExplainParser()
+		p.currentParser.onKeyword(word)
+		return
+
+	case KDrop:
+		p.currentParser = NewDropParser()
+		p.currentParser.onKeyword(word)
+		return
+
+	case KTruncate:
+		p.currentParser = NewTruncateTableParser()
+		p.currentParser.onKeyword(word)
+		return
+
+	case KUse:
+		p.currentParser = NewUseParser()
 		p.currentParser.onKeyword(word)
 		return
 
@@ -80,9 +80,9 @@ func TestParse(t *testing.T) {
 		fkDef, ok := stmt.CreateDefinitions[4].(*ast.ConstraintForeignKeyDef)
 		assert.True(t, ok)
 		assert.Equal(t, "fk_user", fkDef.KeyName)
-		assert.Equal(t, "user_id", fkDef.Column.ColName)
+		assert.Equal(t, []ast.ColumnId{*ast.NewColumnId("user_id")}, fkDef.Columns)
 		assert.Equal(t, "users", fkDef.RefTable)
-		assert.Equal(t, "id", fkDef.RefColumn)
+		assert.Equal(t, []string{"id"}, fkDef.RefColumns)
 	})
 
 	t.Run("DELETE 文をパースできる", func(t *testing.T) {
@@ -384,7 +384,7 @@ func TestOnKeyword(t *testing.T) {
 		assert.True(t, ok)
 	})
 
-	t.Run("ALTER で AlterUserParser がセットされる", func(t *testing.T) {
+	t.Run("ALTER で AlterParser がセットされる", func(t *testing.T) {
 		// GIVEN
 		p := NewParser()
 
@@ -392,7 +392,7 @@ func TestOnKeyword(t *testing.T) {
 		p.onKeyword("ALTER")
 
 		// THEN
-		_, ok := p.currentParser.(*AlterUserParser)
+		_, ok := p.currentParser.(*AlterParser)
 		assert.True(t, ok)
 	})
 
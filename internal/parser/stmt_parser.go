@@ -16,9 +16,20 @@ type StatementParser interface {
 // parseColumnId は識別子を ColumnId に変換する
 //
 // "table.column" 形式の修飾名の場合、TableName と ColName に分割する
+// "db.table.column" 形式の場合、最後の "." で分割し TableName は "db.table" になる
 func parseColumnId(ident string) ast.ColumnId {
-	if idx := strings.Index(ident, "."); idx > 0 && idx < len(ident)-1 {
+	if idx := strings.LastIndex(ident, "."); idx > 0 && idx < len(ident)-1 {
 		return ast.ColumnId{TableName: ident[:idx], ColName: ident[idx+1:]}
 	}
 	return ast.ColumnId{ColName: ident}
 }
+
+// parseAggregateFunc は識別子が集約関数名 (大文字・小文字を区別しない) の場合に、集約関数の種類を返す
+func parseAggregateFunc(ident string) (ast.AggregateFunc, bool) {
+	switch fn := ast.AggregateFunc(strings.ToUpper(ident)); fn {
+	case ast.AggregateCount, ast.AggregateSum, ast.AggregateMin, ast.AggregateMax, ast.AggregateAvg:
+		return fn, true
+	default:
+		return "", false
+	}
+}
@@ -0,0 +1,136 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ren-yamanashi/minesql/internal/ast"
+)
+
+// AlterParser は ALTER USER / ALTER TABLE をパースする
+//
+// ALTER の次のキーワードを検出した時点で対象の文のパーサーを生成し、ALTER キーワードと以降のトークンをそのまま転送する
+type AlterParser struct {
+	state parserState
+	inner StatementParser // 対象の文のパーサー
+	err   error
+}
+
+func NewAlterParser() *AlterParser {
+	return &AlterParser{state: StateInitial}
+}
+
+func (ap *AlterParser) getResult() ast.Statement {
+	if ap.err != nil || ap.inner == nil {
+		return nil
+	}
+	return ap.inner.getResult()
+}
+
+func (ap *AlterParser) getError() error {
+	if ap.err != nil {
+		return ap.err
+	}
+	if ap.inner != nil {
+		return ap.inner.getError()
+	}
+	return nil
+}
+
+func (ap *AlterParser) finalize() {
+	if ap.err != nil {
+		return
+	}
+	if ap.inner == nil {
+		ap.err = fmt.Errorf("[parse error] expected USER or TABLE after ALTER")
+		return
+	}
+	ap.inner.finalize()
+}
+
+func (ap *AlterParser) onKeyword(word string) {
+	if ap.err != nil {
+		return
+	}
+	if ap.state == AlterStateInner {
+		ap.inner.onKeyword(word)
+		return
+	}
+
+	upper := strings.ToUpper(word)
+	switch {
+	case ap.state == StateInitial && upper == KAlter:
+		ap.state = AlterStateAlter
+	case ap.state == AlterStateAlter && upper == KUser:
+		ap.startInner(NewAlterUserParser(), word)
+	case ap.state == AlterStateAlter && upper == KTable:
+		ap.startInner(NewAlterTableParser(), word)
+	default:
+		ap.err = fmt.Errorf("[parse error] expected USER or TABLE after ALTER, got %q", word)
+	}
+}
+
+// startInner は対象の文のパーサーを生成し、ALTER キーワードと 2 番目のキーワードを転送する
+func (ap *AlterParser) startInner(inner StatementParser, word string) {
+	ap.inner = inner
+	ap.state = AlterStateInner
+	ap.inner.onKeyword(KAlter)
+	ap.inner.onKeyword(word)
+}
+
+func (ap *AlterParser) onIdentifier(ident string) {
+	if ap.err != nil {
+		return
+	}
+	if ap.state != AlterStateInner {
+		ap.err = fmt.Errorf("[parse error] expected USER or TABLE after ALTER, got %q", ident)
+		return
+	}
+	ap.inner.onIdentifier(ident)
+}
+
+func (ap *AlterParser) onString(value string) {
+	if ap.err != nil {
+		return
+	}
+	if ap.state != AlterStateInner {
+		ap.err = fmt.Errorf("[parse error] expected USER or TABLE after ALTER, got %q", value)
+		return
+	}
+	ap.inner.onString(value)
+}
+
+func (ap *AlterParser) onNumber(num string) {
+	if ap.err != nil {
+		return
+	}
+	if ap.state != AlterStateInner {
+		ap.err = fmt.Errorf("[parse error] expected USER or TABLE after ALTER, got %q", num)
+		return
+	}
+	ap.inner.onNumber(num)
+}
+
+func (ap *AlterParser) onSymbol(symbol string) {
+	if ap.err != nil {
+		return
+	}
+	if ap.state != AlterStateInner {
+		ap.err = fmt.Errorf("[parse error] expected USER or TABLE after ALTER, got %q", symbol)
+		return
+	}
+	ap.inner.onSymbol(symbol)
+}
+
+func (ap *AlterParser) onComment(text string) {
+	if ap.inner != nil {
+		ap.inner.onComment(text)
+	}
+}
+
+func (ap *AlterParser) onError(err error) {
+	if ap.err != nil {
+		return
+	}
+	ap.err = err
+}
@@ -0,0 +1,295 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ren-yamanashi/minesql/internal/ast"
+)
+
+// AlterTableParser は ALTER TABLE 文をパースする
+//
+// 構文:
+//
+//	ALTER TABLE table_name ADD [COLUMN] col_def;
+//	ALTER TABLE table_name ADD {UNIQUE [KEY | INDEX] | KEY | INDEX} index_name (col);
+//	ALTER TABLE table_name ADD FOREIGN KEY fk_name (col) REFERENCES ref_table (ref_col) [ON DELETE action] [ON UPDATE action];
+//	ALTER TABLE table_name DROP [COLUMN] col_name;
+//	ALTER TABLE table_name DROP {KEY | INDEX} index_name;
+//	ALTER TABLE table_name DROP FOREIGN KEY fk_name;
+//
+// 1 文につき変更は 1 つのみ
+type AlterTableParser struct {
+	state     parserState
+	tableName string
+	action    ast.AlterTableAction
+	colParser *ColumnDefParser     // ADD COLUMN のカラム定義のサブパーサー
+	conParser *ConstraintDefParser // ADD UNIQUE KEY / KEY / FOREIGN KEY の制約定義のサブパーサー
+	err       error
+}
+
+func NewAlterTableParser() *AlterTableParser {
+	return &AlterTableParser{}
+}
+
+func (p *AlterTableParser) getResult() ast.Statement {
+	if p.err != nil {
+		return nil
+	}
+	return &ast.AlterTableStmt{
+		TableName: p.tableName,
+		Action:    p.action,
+	}
+}
+
+func (p *AlterTableParser) getError() error { return p.err }
+
+func (p *AlterTableParser) finalize() {
+	if p.err != nil {
+		return
+	}
+	if p.state == AlterTableStateAddDef {
+		p.flushActiveParser()
+		if p.err != nil {
+			return
+		}
+	}
+	if p.state != AlterTableStateEnd {
+		p.setError(fmt.Errorf("[parse error] incomplete ALTER TABLE statement"))
+	}
+}
+
+func (p *AlterTableParser) onKeyword(word string) {
+	if p.err != nil {
+		return
+	}
+
+	upper := strings.ToUpper(word)
+
+	switch p.state {
+	case AlterTableStateAlter:
+		// ALTER の次は TABLE のみ
+		if upper != KTable {
+			p.setError(fmt.Errorf("[parse error] expected TABLE after ALTER, got %q", word))
+		}
+		p.state = AlterTableStateTable
+
+	case AlterTableStateTableName:
+		// テーブル名の次は ADD または DROP
+		switch upper {
+		case KAdd:
+			p.state = AlterTableStateAdd
+		case KDrop:
+			p.state = AlterTableStateDrop
+		default:
+			p.setError(fmt.Errorf("[parse error] expected ADD or DROP after table name, got %q", word))
+		}
+
+	case AlterTableStateAdd:
+		switch upper {
+		case KColumn:
+			p.state = AlterTableStateAddColumn
+		case KUnique, KKey, KIndex, KForeign:
+			p.conParser = NewConstraintDefParser()
+			p.forwardConstraintKeyword(upper)
+			p.state = AlterTableStateAddDef
+		case KPrimary:
+			p.setError(fmt.Errorf("[parse error] adding PRIMARY KEY is not supported"))
+		default:
+			p.setError(fmt.Errorf("[parse error] expected COLUMN, UNIQUE, KEY, INDEX, FOREIGN or column name after ADD, got %q", word))
+		}
+
+	case AlterTableStateAddDef:
+		switch {
+		case p.colParser != nil:
+			p.colParser.onKeyword(word)
+		case p.conParser != nil:
+			p.forwardConstraintKeyword(upper)
+		}
+
+	case AlterTableStateDrop:
+		switch upper {
+		case KColumn:
+			p.state = AlterTableStateDropColumn
+		case KKey, KIndex:
+			p.state = AlterTableStateDropIndex
+		case KForeign:
+			p.state = AlterTableStateDropForeign
+		case KPrimary:
+			p.setError(fmt.Errorf("[parse error] dropping PRIMARY KEY is not supported"))
+		default:
+			p.setError(fmt.Errorf("[parse error] expected COLUMN, KEY, INDEX, FOREIGN or column name after DROP, got %q", word))
+		}
+
+	case AlterTableStateDropForeign:
+		// DROP FOREIGN の次は KEY のみ
+		if upper != KKey {
+			p.setError(fmt.Errorf("[parse error] expected KEY after FOREIGN, got %q", word))
+		}
+		p.state = AlterTableStateDropFKName
+
+	default:
+		if p.state == StateInitial && upper == KAlter {
+			p.state = AlterTableStateAlter
+			return
+		}
+		p.setError(fmt.Errorf("[parse error] unexpected keyword %q in ALTER TABLE statement", word))
+	}
+}
+
+func (p *AlterTableParser) onIdentifier(ident string) {
+	if p.err != nil {
+		return
+	}
+
+	switch p.state {
+	case AlterTableStateTable:
+		p.tableName = ident
+		p.state = AlterTableStateTableName
+
+	case AlterTableStateAdd, AlterTableStateAddColumn:
+		p.colParser = NewColumnDefParser(ident)
+		p.state = AlterTableStateAddDef
+
+	case AlterTableStateAddDef:
+		if p.conParser != nil {
+			p.conParser.onIdentifier(ident)
+			return
+		}
+		if p.colParser != nil {
+			p.colParser.onIdentifier(ident)
+			return
+		}
+		p.setError(fmt.Errorf("[parse error] unexpected identifier %q in ALTER TABLE statement", ident))
+
+	case AlterTableStateDrop, AlterTableStateDropColumn:
+		p.action = &ast.DropColumnAction{ColName: ident}
+		p.state = AlterTableStateEnd
+
+	case AlterTableStateDropIndex:
+		p.action = &ast.DropIndexAction{IndexName: ident}
+		p.state = AlterTableStateEnd
+
+	case AlterTableStateDropFKName:
+		p.action = &ast.DropForeignKeyAction{ConstraintName: ident}
+		p.state = AlterTableStateEnd
+
+	default:
+		p.setError(fmt.Errorf("[parse error] unexpected identifier %q in ALTER TABLE statement", ident))
+	}
+}
+
+func (p *AlterTableParser) onSymbol(symbol string) {
+	if p.err != nil {
+		return
+	}
+
+	switch p.state {
+	case AlterTableStateAddDef:
+		if symbol == string(SSemicolon) {
+			p.flushActiveParser()
+			return
+		}
+		// ColumnDefParser がデータ型の引数を解析中ならシンボルを委譲 (e.g. DECIMAL(10, 2))
+		if p.colParser != nil && p.colParser.acceptsSymbol(symbol) {
+			p.colParser.onSymbol(symbol)
+			return
+		}
+		// FK の参照アクション待ちの場合、制約定義を終了させてから記号を処理する
+		if p.conParser != nil && p.conParser.awaitsFKAction() {
+			p.flushActiveParser()
+			p.onSymbol(symbol)
+			return
+		}
+		if p.conParser != nil {
+			p.conParser.onSymbol(symbol)
+			// FK では FOREIGN KEY fk (col) の ")" の後に REFERENCES ... が続くため、done の時だけ flush する
+			if p.conParser != nil && p.conParser.done {
+				p.flushActiveParser()
+			}
+			return
+		}
+		if symbol == string(SComma) {
+			p.setError(fmt.Errorf("[parse error] multiple actions in ALTER TABLE statement are not supported"))
+			return
+		}
+		p.setError(fmt.Errorf("[parse error] unexpected symbol %q in ALTER TABLE statement", symbol))
+
+	case AlterTableStateEnd:
+		if symbol == string(SSemicolon) {
+			return
+		}
+		if symbol == string(SComma) {
+			p.setError(fmt.Errorf("[parse error] multiple actions in ALTER TABLE statement are not supported"))
+			return
+		}
+		p.setError(fmt.Errorf("[parse error] expected ';' at end of ALTER TABLE statement, got %q", symbol))
+
+	default:
+		p.setError(fmt.Errorf("[parse error] unexpected symbol %q in ALTER TABLE statement", symbol))
+	}
+}
+
+func (p *AlterTableParser) onString(value string) {
+	if p.err != nil {
+		return
+	}
+	if p.colParser != nil {
+		p.colParser.onString(value)
+		return
+	}
+	p.setError(fmt.Errorf("[parse error] unexpected string %q in ALTER TABLE statement", value))
+}
+
+func (p *AlterTableParser) onNumber(num string) {
+	if p.err != nil {
+		return
+	}
+	if p.colParser != nil {
+		p.colParser.onNumber(num)
+		return
+	}
+	p.setError(fmt.Errorf("[parse error] unexpected number %q in ALTER TABLE statement", num))
+}
+
+func (p *AlterTableParser) onComment(_ string) {}
+
+func (p *AlterTableParser) onError(err error) { p.setError(err) }
+
+// エラーを設定する (既にエラーが設定されている場合は無視する)
+func (p *AlterTableParser) setError(err error) {
+	if p.err == nil {
+		p.err = err
+	}
+}
+
+// forwardConstraintKeyword は制約定義のキーワードを ConstraintDefParser に転送する
+//
+// INDEX は KEY の同義語として扱う
+func (p *AlterTableParser) forwardConstraintKeyword(upper string) {
+	if upper == KIndex {
+		upper = KKey
+	}
+	p.conParser.onKeyword(upper)
+}
+
+// flushActiveParser はサブパーサーを正常終了させ、結果を action に取り込む
+func (p *AlterTableParser) flushActiveParser() {
+	switch {
+	case p.colParser != nil:
+		if err := p.colParser.finalize(); err != nil {
+			p.setError(err)
+		} else {
+			p.action = &ast.AddColumnAction{Column: p.colParser.getDef().(*ast.ColumnDef)}
+		}
+		p.colParser = nil
+	case p.conParser != nil:
+		if err := p.conParser.finalize(); err != nil {
+			p.setError(err)
+		} else {
+			p.action = &ast.AddConstraintAction{Constraint: p.conParser.getDef()}
+		}
+		p.conParser = nil
+	}
+	p.state = AlterTableStateEnd
+}
@@ -0,0 +1,217 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/ren-yamanashi/minesql/internal/ast"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParserAlterTable(t *testing.T) {
+	t.Run("ADD COLUMN をパースできる", func(t *testing.T) {
+		// GIVEN
+		sql := "ALTER TABLE users ADD COLUMN price DECIMAL(10, 2) NOT NULL;"
+		parser := NewParser()
+
+		// WHEN
+		result, err := parser.Parse(sql)
+
+		// THEN
+		assert.NoError(t, err)
+		stmt, ok := result.(*ast.AlterTableStmt)
+		assert.True(t, ok)
+		assert.Equal(t, "users", stmt.TableName)
+		assert.Equal(t, &ast.AddColumnAction{
+			Column: &ast.ColumnDef{ColName: "price", DataType: ast.DataTypeDecimal, Precision: 10, Scale: 2, NotNull: true},
+		}, stmt.Action)
+	})
+
+	t.Run("COLUMN を省略した ADD をパースできる", func(t *testing.T) {
+		// GIVEN
+		sql := "alter table users add email VARCHAR"
+		parser := NewParser()
+
+		// WHEN
+		result, err := parser.Parse(sql)
+
+		// THEN
+		assert.NoError(t, err)
+		stmt := result.(*ast.AlterTableStmt)
+		assert.Equal(t, &ast.AddColumnAction{
+			Column: &ast.ColumnDef{ColName: "email", DataType: ast.DataTypeVarchar},
+		}, stmt.Action)
+	})
+
+	t.Run("DEFAULT 句を含む ADD COLUMN をパースできる", func(t *testing.T) {
+		// GIVEN
+		sql := "ALTER TABLE users ADD COLUMN status VARCHAR NOT NULL DEFAULT 'active';"
+		parser := NewParser()
+
+		// WHEN
+		result, err := parser.Parse(sql)
+
+		// THEN
+		assert.NoError(t, err)
+		stmt := result.(*ast.AlterTableStmt)
+		assert.Equal(t, &ast.AddColumnAction{
+			Column: &ast.ColumnDef{ColName: "status", DataType: ast.DataTypeVarchar, NotNull: true, Default: ast.NewStringLiteral("active"), DefaultText: "'active'"},
+		}, stmt.Action)
+	})
+
+	t.Run("ADD UNIQUE KEY をパースできる", func(t *testing.T) {
+		// GIVEN
+		sql := "ALTER TABLE users ADD UNIQUE KEY uk_email (email);"
+		parser := NewParser()
+
+		// WHEN
+		result, err := parser.Parse(sql)
+
+		// THEN
+		assert.NoError(t, err)
+		stmt := result.(*ast.AlterTableStmt)
+		assert.Equal(t, &ast.AddConstraintAction{
+			Constraint: &ast.ConstraintUniqueKeyDef{KeyName: "uk_email", Columns: []ast.ColumnId{*ast.NewColumnId("email")}},
+		}, stmt.Action)
+	})
+
+	t.Run("ADD UNIQUE INDEX をパースできる", func(t *testing.T) {
+		// GIVEN
+		sql := "ALTER TABLE users ADD UNIQUE INDEX uk_email (email);"
+		parser := NewParser()
+
+		// WHEN
+		result, err := parser.Parse(sql)
+
+		// THEN
+		assert.NoError(t, err)
+		stmt := result.(*ast.AlterTableStmt)
+		assert.Equal(t, &ast.AddConstraintAction{
+			Constraint: &ast.ConstraintUniqueKeyDef{KeyName: "uk_email", Columns: []ast.ColumnId{*ast.NewColumnId("email")}},
+		}, stmt.Action)
+	})
+
+	t.Run("ADD INDEX をパースできる", func(t *testing.T) {
+		// GIVEN
+		sql := "ALTER TABLE users ADD INDEX idx_name (name);"
+		parser := NewParser()
+
+		// WHEN
+		result, err := parser.Parse(sql)
+
+		// THEN
+		assert.NoError(t, err)
+		stmt := result.(*ast.AlterTableStmt)
+		assert.Equal(t, &ast.AddConstraintAction{
+			Constraint: &ast.ConstraintKeyDef{KeyName: "idx_name", Columns: []ast.ColumnId{*ast.NewColumnId("name")}},
+		}, stmt.Action)
+	})
+
+	t.Run("ADD FOREIGN KEY をパースできる", func(t *testing.T) {
+		// GIVEN
+		sql := "ALTER TABLE orders ADD FOREIGN KEY fk_user (user_id) REFERENCES users (id);"
+		parser := NewParser()
+
+		// WHEN
+		result, err := parser.Parse(sql)
+
+		// THEN
+		assert.NoError(t, err)
+		stmt := result.(*ast.AlterTableStmt)
+		assert.Equal(t, "orders", stmt.TableName)
+		assert.Equal(t, &ast.AddConstraintAction{
+			Constraint: &ast.ConstraintForeignKeyDef{
+				KeyName:    "fk_user",
+				Columns:    []ast.ColumnId{*ast.NewColumnId("user_id")},
+				RefTable:   "users",
+				RefColumns: []string{"id"},
+			},
+		}, stmt.Action)
+	})
+
+	t.Run("参照アクション付きの ADD FOREIGN KEY をパースできる", func(t *testing.T) {
+		// GIVEN
+		sql := "ALTER TABLE orders ADD FOREIGN KEY fk_user (user_id) REFERENCES users (id) ON UPDATE CASCADE ON DELETE RESTRICT;"
+		parser := NewParser()
+
+		// WHEN
+		result, err := parser.Parse(sql)
+
+		// THEN
+		assert.NoError(t, err)
+		stmt := result.(*ast.AlterTableStmt)
+		assert.Equal(t, &ast.AddConstraintAction{
+			Constraint: &ast.ConstraintForeignKeyDef{
+				KeyName:    "fk_user",
+				Columns:    []ast.ColumnId{*ast.NewColumnId("user_id")},
+				RefTable:   "users",
+				RefColumns: []string{"id"},
+				OnDelete:   ast.ReferentialActionRestrict,
+				OnUpdate:   ast.ReferentialActionCascade,
+			},
+		}, stmt.Action)
+	})
+
+	t.Run("DROP 系の文をパースできる", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			sql      string
+			expected ast.AlterTableAction
+		}{
+			{"DROP COLUMN", "ALTER TABLE users DROP COLUMN email;", &ast.DropColumnAction{ColName: "email"}},
+			{"COLUMN を省略した DROP", "ALTER TABLE users DROP email;", &ast.DropColumnAction{ColName: "email"}},
+			{"DROP KEY", "ALTER TABLE users DROP KEY idx_name;", &ast.DropIndexAction{IndexName: "idx_name"}},
+			{"DROP INDEX", "ALTER TABLE users DROP INDEX idx_name;", &ast.DropIndexAction{IndexName: "idx_name"}},
+			{"DROP FOREIGN KEY", "ALTER TABLE orders DROP FOREIGN KEY fk_user;", &ast.DropForeignKeyAction{ConstraintName: "fk_user"}},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				// GIVEN
+				parser := NewParser()
+
+				// WHEN
+				result, err := parser.Parse(tt.sql)
+
+				// THEN
+				assert.NoError(t, err)
+				stmt, ok := result.(*ast.AlterTableStmt)
+				assert.True(t, ok)
+				assert.Equal(t, tt.expected, stmt.Action)
+			})
+		}
+	})
+
+	t.Run("不正な ALTER TABLE 文でエラーになる", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			sql      string
+			expected string
+		}{
+			{"テーブル名がない場合", "ALTER TABLE;", "unexpected symbol \";\" in ALTER TABLE statement"},
+			{"変更内容がない場合", "ALTER TABLE users;", "unexpected symbol \";\" in ALTER TABLE statement"},
+			{"ADD / DROP 以外が来た場合", "ALTER TABLE users SET;", "expected ADD or DROP after table name"},
+			{"データ型がない場合", "ALTER TABLE users ADD COLUMN email;", "data type is required for column: email"},
+			{"PRIMARY KEY を追加する場合", "ALTER TABLE users ADD PRIMARY KEY (id);", "adding PRIMARY KEY is not supported"},
+			{"PRIMARY KEY を削除する場合", "ALTER TABLE users DROP PRIMARY KEY;", "dropping PRIMARY KEY is not supported"},
+			{"FOREIGN の後に KEY 以外が来た場合", "ALTER TABLE orders DROP FOREIGN INDEX fk_user;", "expected KEY after FOREIGN"},
+			{"REFERENCES がない場合", "ALTER TABLE orders ADD FOREIGN KEY fk_user (user_id);", "REFERENCES table is required"},
+			{"複数の変更を指定した場合", "ALTER TABLE users DROP COLUMN a, DROP COLUMN b;", "multiple actions in ALTER TABLE statement are not supported"},
+			{"ADD FOREIGN KEY の後に複数の変更を指定した場合", "ALTER TABLE orders ADD FOREIGN KEY fk_user (user_id) REFERENCES users (id) ON DELETE CASCADE, DROP COLUMN a;", "multiple actions in ALTER TABLE statement are not supported"},
+			{"参照アクションが途中の場合", "ALTER TABLE orders ADD FOREIGN KEY fk_user (user_id) REFERENCES users (id) ON DELETE;", "incomplete ON DELETE / ON UPDATE clause"},
+			{"ADD COLUMN の後に複数の変更を指定した場合", "ALTER TABLE users ADD a INT, ADD b INT;", "multiple actions in ALTER TABLE statement are not supported"},
+			{"インデックス名がない場合", "ALTER TABLE users DROP INDEX;", "unexpected symbol \";\" in ALTER TABLE statement"},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				// GIVEN
+				parser := NewParser()
+
+				// WHEN
+				result, err := parser.Parse(tt.sql)
+
+				// THEN
+				assert.Nil(t, result)
+				assert.ErrorContains(t, err, tt.expected)
+			})
+		}
+	})
+}
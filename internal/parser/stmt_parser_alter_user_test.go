@@ -61,9 +61,9 @@ func TestParserAlterUser(t *testing.T) {
 	})
 
 	t.Run("不正な ALTER USER 文でエラーになる", func(t *testing.T) {
-		t.Run("ALTER の後に USER 以外が来た場合", func(t *testing.T) {
+		t.Run("ALTER の後に USER / TABLE 以外が来た場合", func(t *testing.T) {
 			// GIVEN
-			sql := "ALTER TABLE users;"
+			sql := "ALTER INDEX users;"
 			parser := NewParser()
 
 			// WHEN
@@ -72,7 +72,7 @@ func TestParserAlterUser(t *testing.T) {
 			// THEN
 			assert.Error(t, err)
 			assert.Nil(t, result)
-			assert.Contains(t, err.Error(), "expected USER after ALTER")
+			assert.Contains(t, err.Error(), "expected USER or TABLE after ALTER")
 		})
 
 		t.Run("ユーザー名がない場合", func(t *testing.T) {
@@ -1,204 +1,140 @@
 package parser
 
 import (
-	"errors"
+	"fmt"
 	"strings"
 
 	"github.com/ren-yamanashi/minesql/internal/ast"
 )
 
+// CreateParser は CREATE TABLE / CREATE [UNIQUE] INDEX / CREATE [OR REPLACE] VIEW をパースする
+//
+// CREATE の次のキーワードを検出した時点で対象の文のパーサーを生成し、CREATE キーワードと以降のトークンをそのまま転送する
 type CreateParser struct {
-	state     parserState          // 現在のステート
-	stmt      *ast.CreateTableStmt // 現在構築中の CREATE TABLE 文
-	err       error                // エラー情報
-	colParser *ColumnDefParser     // カラム定義のサブパーサー
-	conParser *ConstraintDefParser // 制約定義のサブパーサー
+	state parserState
+	inner StatementParser // 対象の文のパーサー
+	err   error
 }
 
 func NewCreateParser() *CreateParser {
-	return &CreateParser{
-		state: CreateStateStart,
-		stmt:  &ast.CreateTableStmt{},
+	return &CreateParser{state: StateInitial}
+}
+
+func (cp *CreateParser) getResult() ast.Statement {
+	if cp.err != nil || cp.inner == nil {
+		return nil
 	}
+	return cp.inner.getResult()
 }
 
-func (cp *CreateParser) getResult() ast.Statement { return cp.stmt }
-func (cp *CreateParser) getError() error          { return cp.err }
-func (cp *CreateParser) finalize() {
+func (cp *CreateParser) getError() error {
 	if cp.err != nil {
-		return
+		return cp.err
 	}
-
-	// テーブル名が空の場合はエラー
-	if cp.stmt.TableName == "" {
-		cp.setError(errors.New("[parse error] table name is required"))
-		return
+	if cp.inner != nil {
+		return cp.inner.getError()
 	}
-	cp.flushActiveParser()
+	return nil
+}
 
-	// カラム定義が空の場合はエラー
-	if len(cp.stmt.CreateDefinitions) == 0 {
-		cp.setError(errors.New("[parse error] at least one column definition is required"))
+func (cp *CreateParser) finalize() {
+	if cp.err != nil {
 		return
 	}
-
-	// ステートが End でない場合はエラー
-	if cp.state != CreateStateEnd {
-		cp.setError(errors.New("[parse error] incomplete CREATE TABLE statement"))
+	if cp.inner == nil {
+		cp.err = fmt.Errorf("[parse error] expected TABLE, UNIQUE, INDEX, VIEW or DATABASE after CREATE")
 		return
 	}
+	cp.inner.finalize()
 }
 
 func (cp *CreateParser) onKeyword(word string) {
 	if cp.err != nil {
 		return
 	}
-	if cp.colParser != nil {
-		cp.colParser.onKeyword(word)
-		return
-	}
-	if cp.conParser != nil {
-		cp.conParser.onKeyword(word)
+	if cp.state == CreateStateInner {
+		cp.inner.onKeyword(word)
 		return
 	}
 
 	upper := strings.ToUpper(word)
-	switch cp.state {
-	case CreateStateStart:
-		if upper == KCreate {
-			cp.state = CreateStateCreate
-			return
-		}
-	case CreateStateCreate:
-		if upper == KTable {
-			cp.state = CreateStateTable
-			return
-		}
-	case CreateStateBody:
-		if upper == KPrimary || upper == KUnique || upper == KKey || upper == KForeign {
-			cp.conParser = NewConstraintDefParser()
-			cp.conParser.onKeyword(word)
-			return
-		}
-		cp.setError(errors.New("[parse error] unexpected keyword: " + word))
-		return
+	switch {
+	case cp.state == StateInitial && upper == KCreate:
+		cp.state = CreateStateDispatch
+	case cp.state == CreateStateDispatch && upper == KTable:
+		cp.startInner(NewCreateTableParser(), word)
+	case cp.state == CreateStateDispatch && (upper == KUnique || upper == KIndex):
+		cp.startInner(NewCreateIndexParser(), word)
+	case cp.state == CreateStateDispatch && (upper == KView || upper == KOr):
+		cp.startInner(NewCreateViewParser(), word)
+	case cp.state == CreateStateDispatch && (upper == KDatabase || upper == KSchema):
+		cp.startInner(NewCreateDatabaseParser(), word)
 	default:
-		cp.setError(errors.New("[parse error] unexpected keyword: " + word))
-		return
+		cp.err = fmt.Errorf("[parse error] expected TABLE, UNIQUE, INDEX, VIEW or DATABASE after CREATE, got %q", word)
 	}
 }
 
+// startInner は対象の文のパーサーを生成し、CREATE キーワードと 2 番目のキーワードを転送する
+func (cp *CreateParser) startInner(inner StatementParser, word string) {
+	cp.inner = inner
+	cp.state = CreateStateInner
+	cp.inner.onKeyword(KCreate)
+	cp.inner.onKeyword(word)
+}
+
 func (cp *CreateParser) onIdentifier(ident string) {
 	if cp.err != nil {
 		return
 	}
-	if cp.conParser != nil {
-		cp.conParser.onIdentifier(ident)
-		return
-	}
-	if cp.colParser != nil {
-		cp.setError(errors.New("[parse error] unexpected identifier: " + ident))
-		return
-	}
-
-	switch cp.state {
-	case CreateStateTable:
-		cp.stmt.TableName = ident
-		cp.state = CreateStateBodyStart
-		return
-	case CreateStateBody:
-		cp.colParser = NewColumnDefParser(ident)
-		return
-	default:
-		cp.setError(errors.New("[parse error] unexpected identifier: " + ident))
+	if cp.state != CreateStateInner {
+		cp.err = fmt.Errorf("[parse error] expected TABLE, UNIQUE, INDEX, VIEW or DATABASE after CREATE, got %q", ident)
 		return
 	}
+	cp.inner.onIdentifier(ident)
 }
 
-func (cp *CreateParser) onSymbol(symbol string) {
+func (cp *CreateParser) onString(value string) {
 	if cp.err != nil {
 		return
 	}
-
-	// ";" が来たら state を End にする
-	if symbol == string(SSemicolon) {
-		cp.flushActiveParser()
-		cp.state = CreateStateEnd
+	if cp.state != CreateStateInner {
+		cp.err = fmt.Errorf("[parse error] expected TABLE, UNIQUE, INDEX, VIEW or DATABASE after CREATE, got %q", value)
 		return
 	}
+	cp.inner.onString(value)
+}
 
-	// ConstraintDefParser がアクティブならシンボルを委譲
-	if cp.conParser != nil {
-		if symbol == string(SComma) || symbol == string(SLeftParen) {
-			cp.conParser.onSymbol(symbol)
-			return
-		}
-		if symbol == string(SRightParen) {
-			cp.conParser.onSymbol(symbol)
-			// FK では FOREIGN KEY fk (col) の ")" の後に REFERENCES ... が続くため、
-			// isDone() が true の時だけ flush する
-			if cp.conParser.done {
-				cp.flushActiveParser()
-			}
-			return
-		}
-	}
-
-	// "," と ")" は区切りなので、親が SubParser を終了させる
-	if symbol == string(SComma) || symbol == string(SRightParen) {
-		cp.flushActiveParser()
+func (cp *CreateParser) onNumber(num string) {
+	if cp.err != nil {
 		return
 	}
-
-	// Body 開始の "(" を処理
-	if cp.state == CreateStateBodyStart && symbol == string(SLeftParen) {
-		cp.state = CreateStateBody
+	if cp.state != CreateStateInner {
+		cp.err = fmt.Errorf("[parse error] expected TABLE, UNIQUE, INDEX, VIEW or DATABASE after CREATE, got %q", num)
 		return
 	}
-
-	cp.setError(errors.New("[parse error] unexpected symbol: " + symbol))
+	cp.inner.onNumber(num)
 }
 
-func (cp *CreateParser) onString(value string) {
+func (cp *CreateParser) onSymbol(symbol string) {
 	if cp.err != nil {
 		return
 	}
-	cp.setError(errors.New("[parse error] unexpected string: " + value))
-}
-
-func (cp *CreateParser) onNumber(num string) {
-	if cp.err != nil {
+	if cp.state != CreateStateInner {
+		cp.err = fmt.Errorf("[parse error] expected TABLE, UNIQUE, INDEX, VIEW or DATABASE after CREATE, got %q", symbol)
 		return
 	}
-	cp.setError(errors.New("[parse error] unexpected number: " + num))
+	cp.inner.onSymbol(symbol)
 }
 
-func (cp *CreateParser) onComment(_ string) {}
-func (cp *CreateParser) onError(err error)  { cp.setError(err) }
-
-// エラーを設定する (既にエラーが設定されている場合は無視する)
-func (cp *CreateParser) setError(err error) {
-	if cp.err == nil {
-		cp.err = err
+func (cp *CreateParser) onComment(text string) {
+	if cp.inner != nil {
+		cp.inner.onComment(text)
 	}
 }
 
-// flushActiveParser はサブパーサーを正常終了させ、結果を stmt に取り込む
-func (cp *CreateParser) flushActiveParser() {
-	switch {
-	case cp.colParser != nil:
-		if err := cp.colParser.finalize(); err != nil {
-			cp.setError(err)
-		} else {
-			cp.stmt.CreateDefinitions = append(cp.stmt.CreateDefinitions, cp.colParser.getDef())
-		}
-		cp.colParser = nil
-	case cp.conParser != nil:
-		if err := cp.conParser.finalize(); err != nil {
-			cp.setError(err)
-		} else {
-			cp.stmt.CreateDefinitions = append(cp.stmt.CreateDefinitions, cp.conParser.getDef())
-		}
-		cp.conParser = nil
+func (cp *CreateParser) onError(err error) {
+	if cp.err != nil {
+		return
 	}
+	cp.err = err
 }
@@ -0,0 +1,147 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ren-yamanashi/minesql/internal/ast"
+)
+
+// CreateDatabaseParser は CREATE DATABASE 文をパースする
+//
+// 構文: CREATE {DATABASE | SCHEMA} [IF NOT EXISTS] db_name;
+type CreateDatabaseParser struct {
+	state        parserState
+	databaseName string
+	ifNotExists  bool
+	err          error
+}
+
+func NewCreateDatabaseParser() *CreateDatabaseParser {
+	return &CreateDatabaseParser{}
+}
+
+func (p *CreateDatabaseParser) getResult() ast.Statement {
+	if p.err != nil {
+		return nil
+	}
+	return &ast.CreateDatabaseStmt{
+		DatabaseName: p.databaseName,
+		IfNotExists:  p.ifNotExists,
+	}
+}
+
+func (p *CreateDatabaseParser) getError() error { return p.err }
+
+func (p *CreateDatabaseParser) finalize() {
+	if p.err != nil {
+		return
+	}
+	if p.state != CreateDatabaseStateEnd {
+		p.err = fmt.Errorf("[parse error] incomplete CREATE DATABASE statement")
+	}
+}
+
+func (p *CreateDatabaseParser) onKeyword(word string) {
+	if p.err != nil {
+		return
+	}
+
+	upper := strings.ToUpper(word)
+
+	switch p.state {
+	case CreateDatabaseStateCreate:
+		// CREATE の次は DATABASE または SCHEMA (SCHEMA は DATABASE の別名)
+		if upper != KDatabase && upper != KSchema {
+			p.err = fmt.Errorf("[parse error] expected DATABASE or SCHEMA after CREATE, got %q", word)
+		}
+		p.state = CreateDatabaseStateDatabase
+
+	case CreateDatabaseStateDatabase:
+		// DATABASE の次は IF またはデータベース名
+		if upper != KIf {
+			p.err = fmt.Errorf("[parse error] expected IF or database name after DATABASE, got %q", word)
+		}
+		p.state = CreateDatabaseStateIf
+
+	case CreateDatabaseStateIf:
+		// IF の次は NOT のみ
+		if upper != KNot {
+			p.err = fmt.Errorf("[parse error] expected NOT after IF, got %q", word)
+		}
+		p.state = CreateDatabaseStateNot
+
+	case CreateDatabaseStateNot:
+		// IF NOT の次は EXISTS のみ
+		if upper != KExists {
+			p.err = fmt.Errorf("[parse error] expected EXISTS after IF NOT, got %q", word)
+		}
+		p.ifNotExists = true
+		p.state = CreateDatabaseStateExists
+
+	default:
+		if upper == KCreate {
+			p.state = CreateDatabaseStateCreate
+			return
+		}
+		p.err = fmt.Errorf("[parse error] unexpected keyword %q in CREATE DATABASE statement", word)
+	}
+}
+
+func (p *CreateDatabaseParser) onIdentifier(ident string) {
+	if p.err != nil {
+		return
+	}
+
+	switch p.state {
+	case CreateDatabaseStateDatabase, CreateDatabaseStateExists:
+		p.databaseName = ident
+		p.state = CreateDatabaseStateEnd
+
+	case CreateDatabaseStateIf:
+		p.err = fmt.Errorf("[parse error] expected NOT after IF, got %q", ident)
+
+	case CreateDatabaseStateNot:
+		p.err = fmt.Errorf("[parse error] expected EXISTS after IF NOT, got %q", ident)
+
+	default:
+		p.err = fmt.Errorf("[parse error] unexpected identifier %q in CREATE DATABASE statement", ident)
+	}
+}
+
+func (p *CreateDatabaseParser) onSymbol(symbol string) {
+	if p.err != nil {
+		return
+	}
+
+	switch p.state {
+	case CreateDatabaseStateEnd:
+		if symbol == ";" {
+			return
+		}
+		p.err = fmt.Errorf("[parse error] expected ';' at end of CREATE DATABASE statement, got %q", symbol)
+
+	default:
+		p.err = fmt.Errorf("[parse error] unexpected symbol %q in CREATE DATABASE statement", symbol)
+	}
+}
+
+func (p *CreateDatabaseParser) onString(value string) {
+	if p.err != nil {
+		return
+	}
+	p.err = fmt.Errorf("[parse error] unexpected string %q in CREATE DATABASE statement", value)
+}
+
+func (p *CreateDatabaseParser) onNumber(_ string) {
+	if p.err != nil {
+		return
+	}
+	p.err = fmt.Errorf("[parse error] unexpected number in CREATE DATABASE statement")
+}
+
+func (p *CreateDatabaseParser) onComment(_ string) {}
+
+func (p *CreateDatabaseParser) onError(err error) {
+	p.err = err
+}
@@ -0,0 +1,69 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/ren-yamanashi/minesql/internal/ast"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParserCreateDatabase(t *testing.T) {
+	t.Run("CREATE DATABASE 文をパースできる", func(t *testing.T) {
+		// GIVEN
+		sql := "CREATE DATABASE app;"
+		parser := NewParser()
+
+		// WHEN
+		result, err := parser.Parse(sql)
+
+		// THEN
+		assert.NoError(t, err)
+		stmt, ok := result.(*ast.CreateDatabaseStmt)
+		assert.True(t, ok)
+		assert.Equal(t, "app", stmt.DatabaseName)
+		assert.False(t, stmt.IfNotExists)
+	})
+
+	t.Run("IF NOT EXISTS 付きの CREATE SCHEMA 文をパースできる", func(t *testing.T) {
+		// GIVEN
+		sql := "create schema if not exists app"
+		parser := NewParser()
+
+		// WHEN
+		result, err := parser.Parse(sql)
+
+		// THEN
+		assert.NoError(t, err)
+		stmt, ok := result.(*ast.CreateDatabaseStmt)
+		assert.True(t, ok)
+		assert.Equal(t, "app", stmt.DatabaseName)
+		assert.True(t, stmt.IfNotExists)
+	})
+
+	t.Run("不正な CREATE DATABASE 文でエラーになる", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			sql      string
+			expected string
+		}{
+			{"IF の後に NOT 以外が来た場合", "CREATE DATABASE IF EXISTS app;", "expected NOT after IF"},
+			{"IF NOT の後に EXISTS 以外が来た場合", "CREATE DATABASE IF NOT app;", "expected EXISTS after IF NOT"},
+			{"データベース名がない場合", "CREATE DATABASE;", "unexpected symbol \";\" in CREATE DATABASE statement"},
+			{"IF NOT EXISTS の後にデータベース名がない場合", "CREATE DATABASE IF NOT EXISTS", "incomplete CREATE DATABASE statement"},
+			{"複数のデータベース名を指定した場合", "CREATE DATABASE a, b;", "expected ';' at end of CREATE DATABASE statement"},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				// GIVEN
+				parser := NewParser()
+
+				// WHEN
+				result, err := parser.Parse(tt.sql)
+
+				// THEN
+				assert.Nil(t, result)
+				assert.ErrorContains(t, err, tt.expected)
+			})
+		}
+	})
+}
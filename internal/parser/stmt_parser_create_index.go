@@ -0,0 +1,168 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ren-yamanashi/minesql/internal/ast"
+)
+
+// CreateIndexParser は CREATE INDEX 文をパースする
+//
+// 構文: CREATE [UNIQUE] INDEX index_name ON table_name (col1, col2, ...);
+type CreateIndexParser struct {
+	state parserState
+	stmt  ast.CreateIndexStmt
+	err   error
+}
+
+func NewCreateIndexParser() *CreateIndexParser {
+	return &CreateIndexParser{}
+}
+
+func (p *CreateIndexParser) getResult() ast.Statement {
+	if p.err != nil {
+		return nil
+	}
+	return &p.stmt
+}
+
+func (p *CreateIndexParser) getError() error { return p.err }
+
+func (p *CreateIndexParser) finalize() {
+	if p.err != nil {
+		return
+	}
+	if p.state != CreateIndexStateEnd {
+		p.setError(fmt.Errorf("[parse error] incomplete CREATE INDEX statement"))
+	}
+}
+
+func (p *CreateIndexParser) onKeyword(word string) {
+	if p.err != nil {
+		return
+	}
+
+	upper := strings.ToUpper(word)
+
+	switch p.state {
+	case CreateIndexStateCreate:
+		// CREATE の次は UNIQUE または INDEX
+		switch upper {
+		case KUnique:
+			p.stmt.Unique = true
+			p.state = CreateIndexStateUnique
+		case KIndex:
+			p.state = CreateIndexStateIndex
+		default:
+			p.setError(fmt.Errorf("[parse error] expected UNIQUE or INDEX after CREATE, got %q", word))
+		}
+
+	case CreateIndexStateUnique:
+		// UNIQUE の次は INDEX のみ
+		if upper != KIndex {
+			p.setError(fmt.Errorf("[parse error] expected INDEX after UNIQUE, got %q", word))
+		}
+		p.state = CreateIndexStateIndex
+
+	case CreateIndexStateIndexName:
+		// インデックス名の次は ON のみ
+		if upper != KOn {
+			p.setError(fmt.Errorf("[parse error] expected ON after index name, got %q", word))
+		}
+		p.state = CreateIndexStateOn
+
+	default:
+		if p.state == StateInitial && upper == KCreate {
+			p.state = CreateIndexStateCreate
+			return
+		}
+		p.setError(fmt.Errorf("[parse error] unexpected keyword %q in CREATE INDEX statement", word))
+	}
+}
+
+func (p *CreateIndexParser) onIdentifier(ident string) {
+	if p.err != nil {
+		return
+	}
+
+	switch p.state {
+	case CreateIndexStateIndex:
+		p.stmt.IndexName = ident
+		p.state = CreateIndexStateIndexName
+
+	case CreateIndexStateOn:
+		p.stmt.TableName = ident
+		p.state = CreateIndexStateTableName
+
+	case CreateIndexStateCol:
+		p.stmt.Columns = append(p.stmt.Columns, *ast.NewColumnId(ident))
+		p.state = CreateIndexStateColSep
+
+	default:
+		p.setError(fmt.Errorf("[parse error] unexpected identifier %q in CREATE INDEX statement", ident))
+	}
+}
+
+func (p *CreateIndexParser) onSymbol(symbol string) {
+	if p.err != nil {
+		return
+	}
+
+	switch p.state {
+	case CreateIndexStateTableName:
+		if symbol == string(SLeftParen) {
+			p.state = CreateIndexStateCol
+			return
+		}
+		p.setError(fmt.Errorf("[parse error] expected '(' after table name, got %q", symbol))
+
+	case CreateIndexStateColSep:
+		// "," が来たら次のカラム待ち、")" が来たらカラムリスト終了
+		switch symbol {
+		case string(SComma):
+			p.state = CreateIndexStateCol
+		case string(SRightParen):
+			p.state = CreateIndexStateEnd
+		default:
+			p.setError(fmt.Errorf("[parse error] expected ',' or ')' after column name, got %q", symbol))
+		}
+
+	case CreateIndexStateCol:
+		p.setError(fmt.Errorf("[parse error] expected column name in CREATE INDEX statement, got %q", symbol))
+
+	case CreateIndexStateEnd:
+		if symbol == string(SSemicolon) {
+			return
+		}
+		p.setError(fmt.Errorf("[parse error] expected ';' at end of CREATE INDEX statement, got %q", symbol))
+
+	default:
+		p.setError(fmt.Errorf("[parse error] unexpected symbol %q in CREATE INDEX statement", symbol))
+	}
+}
+
+func (p *CreateIndexParser) onString(value string) {
+	if p.err != nil {
+		return
+	}
+	p.setError(fmt.Errorf("[parse error] unexpected string %q in CREATE INDEX statement", value))
+}
+
+func (p *CreateIndexParser) onNumber(num string) {
+	if p.err != nil {
+		return
+	}
+	p.setError(fmt.Errorf("[parse error] unexpected number %q in CREATE INDEX statement", num))
+}
+
+func (p *CreateIndexParser) onComment(_ string) {}
+
+func (p *CreateIndexParser) onError(err error) { p.setError(err) }
+
+// エラーを設定する (既にエラーが設定されている場合は無視する)
+func (p *CreateIndexParser) setError(err error) {
+	if p.err == nil {
+		p.err = err
+	}
+}
@@ -0,0 +1,74 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/ren-yamanashi/minesql/internal/ast"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParserCreateIndex(t *testing.T) {
+	t.Run("CREATE INDEX 文をパースできる", func(t *testing.T) {
+		// GIVEN
+		sql := "CREATE INDEX idx_name ON users (name);"
+		parser := NewParser()
+
+		// WHEN
+		result, err := parser.Parse(sql)
+
+		// THEN
+		assert.NoError(t, err)
+		assert.Equal(t, &ast.CreateIndexStmt{
+			IndexName: "idx_name",
+			TableName: "users",
+			Columns:   []ast.ColumnId{*ast.NewColumnId("name")},
+		}, result)
+	})
+
+	t.Run("複数カラムの CREATE UNIQUE INDEX 文をパースできる", func(t *testing.T) {
+		// GIVEN
+		sql := "create unique index uk_name_age on users (name, age)"
+		parser := NewParser()
+
+		// WHEN
+		result, err := parser.Parse(sql)
+
+		// THEN
+		assert.NoError(t, err)
+		assert.Equal(t, &ast.CreateIndexStmt{
+			IndexName: "uk_name_age",
+			TableName: "users",
+			Unique:    true,
+			Columns:   []ast.ColumnId{*ast.NewColumnId("name"), *ast.NewColumnId("age")},
+		}, result)
+	})
+
+	t.Run("不正な CREATE INDEX 文でエラーになる", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			sql      string
+			expected string
+		}{
+			{"UNIQUE の後に INDEX 以外が来た場合", "CREATE UNIQUE KEY idx ON users (name);", "expected INDEX after UNIQUE"},
+			{"インデックス名の後に ON 以外が来た場合", "CREATE INDEX idx FROM users (name);", "expected ON after index name"},
+			{"テーブル名の後に '(' 以外が来た場合", "CREATE INDEX idx ON users name;", "unexpected identifier \"name\" in CREATE INDEX statement"},
+			{"カラム名がない場合", "CREATE INDEX idx ON users ();", "expected column name in CREATE INDEX statement"},
+			{"カラム名の後に ',' または ')' 以外が来た場合", "CREATE INDEX idx ON users (name;", "expected ',' or ')' after column name"},
+			{"カラムリストが閉じられていない場合", "CREATE INDEX idx ON users (name", "incomplete CREATE INDEX statement"},
+			{"カラムリストの後に余分なトークンがある場合", "CREATE INDEX idx ON users (name) (age);", "expected ';' at end of CREATE INDEX statement"},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				// GIVEN
+				parser := NewParser()
+
+				// WHEN
+				result, err := parser.Parse(tt.sql)
+
+				// THEN
+				assert.Nil(t, result)
+				assert.ErrorContains(t, err, tt.expected)
+			})
+		}
+	})
+}
@@ -38,6 +38,121 @@ func TestParserCreateTable(t *testing.T) {
 		assert.Equal(t, ast.DataTypeVarchar, colDef2.DataType)
 	})
 
+	t.Run("型付きカラムを含む CREATE TABLE 文をパースできる", func(t *testing.T) {
+		// GIVEN
+		sql := "CREATE TABLE orders (id BIGINT, qty INT, price DECIMAL(10, 2), ordered_on DATE, created_at DATETIME, PRIMARY KEY (id));"
+		parser := NewParser()
+
+		// WHEN
+		result, err := parser.Parse(sql)
+
+		// THEN
+		assert.NoError(t, err)
+		createStmt, ok := result.(*ast.CreateTableStmt)
+		assert.True(t, ok)
+		assert.Equal(t, 6, len(createStmt.CreateDefinitions))
+
+		expected := []ast.ColumnDef{
+			{ColName: "id", DataType: ast.DataTypeBigInt},
+			{ColName: "qty", DataType: ast.DataTypeInt},
+			{ColName: "price", DataType: ast.DataTypeDecimal, Precision: 10, Scale: 2},
+			{ColName: "ordered_on", DataType: ast.DataTypeDate},
+			{ColName: "created_at", DataType: ast.DataTypeDatetime},
+		}
+		for i, exp := range expected {
+			colDef, ok := createStmt.CreateDefinitions[i].(*ast.ColumnDef)
+			assert.True(t, ok)
+			assert.Equal(t, exp, *colDef)
+		}
+		_, ok = createStmt.CreateDefinitions[5].(*ast.ConstraintPrimaryKeyDef)
+		assert.True(t, ok)
+	})
+
+	t.Run("NOT NULL 制約を含む CREATE TABLE 文をパースできる", func(t *testing.T) {
+		// GIVEN
+		sql := "CREATE TABLE users (id INT NOT NULL, price DECIMAL(5, 2) NOT NULL, email VARCHAR NULL, PRIMARY KEY (id));"
+		parser := NewParser()
+
+		// WHEN
+		result, err := parser.Parse(sql)
+
+		// THEN
+		assert.NoError(t, err)
+		createStmt, ok := result.(*ast.CreateTableStmt)
+		assert.True(t, ok)
+		assert.Equal(t, 4, len(createStmt.CreateDefinitions))
+
+		expected := []ast.ColumnDef{
+			{ColName: "id", DataType: ast.DataTypeInt, NotNull: true},
+			{ColName: "price", DataType: ast.DataTypeDecimal, Precision: 5, Scale: 2, NotNull: true},
+			{ColName: "email", DataType: ast.DataTypeVarchar},
+		}
+		for i, exp := range expected {
+			colDef, ok := createStmt.CreateDefinitions[i].(*ast.ColumnDef)
+			assert.True(t, ok)
+			assert.Equal(t, exp, *colDef)
+		}
+	})
+
+	t.Run("AUTO_INCREMENT を含む CREATE TABLE 文をパースできる", func(t *testing.T) {
+		// GIVEN
+		sql := "CREATE TABLE users (id INT AUTO_INCREMENT, name VARCHAR, PRIMARY KEY (id));"
+		parser := NewParser()
+
+		// WHEN
+		result, err := parser.Parse(sql)
+
+		// THEN
+		assert.NoError(t, err)
+		createStmt, ok := result.(*ast.CreateTableStmt)
+		assert.True(t, ok)
+		assert.Equal(t, &ast.ColumnDef{ColName: "id", DataType: ast.DataTypeInt, AutoIncrement: true}, createStmt.CreateDefinitions[0])
+		assert.Equal(t, &ast.ColumnDef{ColName: "name", DataType: ast.DataTypeVarchar}, createStmt.CreateDefinitions[1])
+	})
+
+	t.Run("DEFAULT 句を含む CREATE TABLE 文をパースできる", func(t *testing.T) {
+		// GIVEN
+		sql := "CREATE TABLE users (id INT, name VARCHAR DEFAULT 'guest' NOT NULL, score DECIMAL(5, 2) DEFAULT (1.5 * 2), PRIMARY KEY (id));"
+		parser := NewParser()
+
+		// WHEN
+		result, err := parser.Parse(sql)
+
+		// THEN
+		assert.NoError(t, err)
+		createStmt, ok := result.(*ast.CreateTableStmt)
+		assert.True(t, ok)
+		assert.Equal(t, 4, len(createStmt.CreateDefinitions))
+		nameDef := createStmt.CreateDefinitions[1].(*ast.ColumnDef)
+		assert.Equal(t, "'guest'", nameDef.DefaultText)
+		assert.True(t, nameDef.NotNull)
+		scoreDef := createStmt.CreateDefinitions[2].(*ast.ColumnDef)
+		assert.Equal(t, 5, scoreDef.Precision)
+		assert.Equal(t, 2, scoreDef.Scale)
+		assert.Equal(t, "1.5 * 2", scoreDef.DefaultText)
+	})
+
+	t.Run("CHECK 制約を含む CREATE TABLE 文をパースできる", func(t *testing.T) {
+		// GIVEN
+		sql := "CREATE TABLE items (id INT, price INT, status VARCHAR, PRIMARY KEY (id), CHECK (price >= 0), CONSTRAINT chk_status CHECK (status IN ('a', 'b')));"
+		parser := NewParser()
+
+		// WHEN
+		result, err := parser.Parse(sql)
+
+		// THEN
+		assert.NoError(t, err)
+		createStmt, ok := result.(*ast.CreateTableStmt)
+		assert.True(t, ok)
+		assert.Equal(t, 6, len(createStmt.CreateDefinitions))
+		checkDef := createStmt.CreateDefinitions[4].(*ast.ConstraintCheckDef)
+		assert.Equal(t, "", checkDef.KeyName)
+		assert.Equal(t, "price >= 0", checkDef.ExprText)
+		namedCheckDef := createStmt.CreateDefinitions[5].(*ast.ConstraintCheckDef)
+		assert.Equal(t, "chk_status", namedCheckDef.KeyName)
+		assert.Equal(t, "status IN ('a', 'b')", namedCheckDef.ExprText)
+	})
+
 	t.Run("PRIMARY KEY 制約を含む CREATE TABLE 文をパースできる", func(t *testing.T) {
 		// GIVEN
 		sql := "CREATE TABLE users (id VARCHAR, name VARCHAR, PRIMARY KEY (id));"
@@ -106,7 +221,7 @@ func TestParserCreateTable(t *testing.T) {
 		ukDef, ok := createStmt.CreateDefinitions[2].(*ast.ConstraintUniqueKeyDef)
 		assert.True(t, ok)
 		assert.Equal(t, "email_idx", ukDef.KeyName)
-		assert.Equal(t, "email", ukDef.Column.ColName)
+		assert.Equal(t, "email", ukDef.Columns[0].ColName)
 	})
 
 	t.Run("複数の制約を含む CREATE TABLE 文をパースできる", func(t *testing.T) {
@@ -150,7 +265,7 @@ func TestParserCreateTable(t *testing.T) {
 		ukDef, ok := createStmt.CreateDefinitions[4].(*ast.ConstraintUniqueKeyDef)
 		assert.True(t, ok)
 		assert.Equal(t, "email_idx", ukDef.KeyName)
-		assert.Equal(t, "email", ukDef.Column.ColName)
+		assert.Equal(t, "email", ukDef.Columns[0].ColName)
 	})
 
 	t.Run("コメント付きの CREATE TABLE 文をパースできる", func(t *testing.T) {
@@ -271,7 +386,7 @@ CREATE TABLE users ( /* テーブル定義開始 */
 			// THEN
 			assert.Error(t, err)
 			assert.Nil(t, result)
-			assert.Contains(t, err.Error(), "unexpected identifier")
+			assert.Contains(t, err.Error(), "expected TABLE, UNIQUE, INDEX, VIEW or DATABASE after CREATE")
 		})
 
 		t.Run("テーブル名がない場合", func(t *testing.T) {
@@ -407,7 +522,7 @@ CREATE TABLE users ( /* テーブル定義開始 */
 		keyDef, ok := createStmt.CreateDefinitions[3].(*ast.ConstraintKeyDef)
 		assert.True(t, ok)
 		assert.Equal(t, "idx_category", keyDef.KeyName)
-		assert.Equal(t, "category", keyDef.Column.ColName)
+		assert.Equal(t, "category", keyDef.Columns[0].ColName)
 	})
 
 	t.Run("UNIQUE KEY と KEY が混在する CREATE TABLE 文をパースできる", func(t *testing.T) {
@@ -454,9 +569,9 @@ CREATE TABLE users ( /* テーブル定義開始 */
 		fkDef, ok := createStmt.CreateDefinitions[4].(*ast.ConstraintForeignKeyDef)
 		assert.True(t, ok)
 		assert.Equal(t, "fk_user", fkDef.KeyName)
-		assert.Equal(t, "user_id", fkDef.Column.ColName)
+		assert.Equal(t, []ast.ColumnId{*ast.NewColumnId("user_id")}, fkDef.Columns)
 		assert.Equal(t, "users", fkDef.RefTable)
-		assert.Equal(t, "id", fkDef.RefColumn)
+		assert.Equal(t, []string{"id"}, fkDef.RefColumns)
 	})
 
 	t.Run("複数の FOREIGN KEY 付きの CREATE TABLE 文をパースできる", func(t *testing.T) {
@@ -477,17 +592,79 @@ CREATE TABLE users ( /* テーブル定義開始 */
 		fk1, ok := createStmt.CreateDefinitions[5].(*ast.ConstraintForeignKeyDef)
 		assert.True(t, ok)
 		assert.Equal(t, "fk_order", fk1.KeyName)
-		assert.Equal(t, "order_id", fk1.Column.ColName)
+		assert.Equal(t, "order_id", fk1.Columns[0].ColName)
 		assert.Equal(t, "orders", fk1.RefTable)
 
 		// 2 つ目の FK
 		fk2, ok := createStmt.CreateDefinitions[6].(*ast.ConstraintForeignKeyDef)
 		assert.True(t, ok)
 		assert.Equal(t, "fk_product", fk2.KeyName)
-		assert.Equal(t, "product_id", fk2.Column.ColName)
+		assert.Equal(t, "product_id", fk2.Columns[0].ColName)
 		assert.Equal(t, "products", fk2.RefTable)
 	})
 
+	t.Run("複合外部キーを含む CREATE TABLE 文をパースできる", func(t *testing.T) {
+		// GIVEN
+		sql := "CREATE TABLE orders (tenant_id INT, id INT, user_id INT, PRIMARY KEY (tenant_id, id), KEY idx_tenant_user (tenant_id, user_id), FOREIGN KEY fk_user (tenant_id, user_id) REFERENCES users (tenant_id, id) ON DELETE CASCADE);"
+		parser := NewParser()
+
+		// WHEN
+		result, err := parser.Parse(sql)
+
+		// THEN
+		assert.NoError(t, err)
+		createStmt, ok := result.(*ast.CreateTableStmt)
+		assert.True(t, ok)
+		assert.Equal(t, 6, len(createStmt.CreateDefinitions))
+		fkDef, ok := createStmt.CreateDefinitions[5].(*ast.ConstraintForeignKeyDef)
+		assert.True(t, ok)
+		assert.Equal(t, []ast.ColumnId{*ast.NewColumnId("tenant_id"), *ast.NewColumnId("user_id")}, fkDef.Columns)
+		assert.Equal(t, "users", fkDef.RefTable)
+		assert.Equal(t, []string{"tenant_id", "id"}, fkDef.RefColumns)
+		assert.Equal(t, ast.ReferentialActionCascade, fkDef.OnDelete)
+	})
+
+	t.Run("参照アクション付きの FOREIGN KEY の後に定義が続く CREATE TABLE 文をパースできる", func(t *testing.T) {
+		// GIVEN
+		sql := "CREATE TABLE employees (id INT, manager_id INT, PRIMARY KEY (id), KEY idx_manager_id (manager_id), FOREIGN KEY fk_manager (manager_id) REFERENCES employees (id) ON DELETE SET NULL ON UPDATE CASCADE, KEY idx_id_manager (id, manager_id));"
+		parser := NewParser()
+
+		// WHEN
+		result, err := parser.Parse(sql)
+
+		// THEN
+		assert.NoError(t, err)
+		createStmt, ok := result.(*ast.CreateTableStmt)
+		assert.True(t, ok)
+		assert.Equal(t, 6, len(createStmt.CreateDefinitions))
+
+		fkDef, ok := createStmt.CreateDefinitions[4].(*ast.ConstraintForeignKeyDef)
+		assert.True(t, ok)
+		assert.Equal(t, "employees", fkDef.RefTable)
+		assert.Equal(t, ast.ReferentialActionSetNull, fkDef.OnDelete)
+		assert.Equal(t, ast.ReferentialActionCascade, fkDef.OnUpdate)
+
+		_, ok = createStmt.CreateDefinitions[5].(*ast.ConstraintKeyDef)
+		assert.True(t, ok)
+	})
+
+	t.Run("参照アクション付きの FOREIGN KEY で終わる CREATE TABLE 文をパースできる", func(t *testing.T) {
+		// GIVEN
+		sql := "CREATE TABLE orders (id VARCHAR, user_id VARCHAR, PRIMARY KEY (id), KEY idx_user_id (user_id), FOREIGN KEY fk_user (user_id) REFERENCES users (id) ON DELETE CASCADE);"
+		parser := NewParser()
+
+		// WHEN
+		result, err := parser.Parse(sql)
+
+		// THEN
+		assert.NoError(t, err)
+		createStmt := result.(*ast.CreateTableStmt)
+		fkDef, ok := createStmt.CreateDefinitions[4].(*ast.ConstraintForeignKeyDef)
+		assert.True(t, ok)
+		assert.Equal(t, ast.ReferentialActionCascade, fkDef.OnDelete)
+		assert.Equal(t, ast.ReferentialAction(""), fkDef.OnUpdate)
+	})
+
 	t.Run("PK, UK, KEY, FK が混在する CREATE TABLE 文をパースできる", func(t *testing.T) {
 		// GIVEN
 		sql := "CREATE TABLE orders (id VARCHAR, code VARCHAR, user_id VARCHAR, PRIMARY KEY (id), UNIQUE KEY idx_code (code), KEY idx_user_id (user_id), FOREIGN KEY fk_user (user_id) REFERENCES users (id));"
@@ -0,0 +1,222 @@
+package parser
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/ren-yamanashi/minesql/internal/ast"
+)
+
+// CreateViewParser は CREATE VIEW 文をパースする
+//
+// 構文: CREATE [OR REPLACE] VIEW view_name [(col1, col2, ...)] AS select_statement;
+//
+// 問い合わせの SELECT / WITH キーワード以降のトークンは問い合わせのパーサーに転送し、同時に問い合わせの SQL 表現を組み立てる
+type CreateViewParser struct {
+	state     parserState
+	stmt      *ast.CreateViewStmt
+	query     *SelectParser   // 問い合わせのパーサー
+	text      strings.Builder // 問い合わせの SQL 表現
+	prev      string          // 直前に text に追加したトークン
+	prevIdent bool            // 直前に text に追加したトークンが識別子かどうか
+	err       error
+}
+
+func NewCreateViewParser() *CreateViewParser {
+	return &CreateViewParser{stmt: &ast.CreateViewStmt{}}
+}
+
+func (p *CreateViewParser) getResult() ast.Statement {
+	if p.err != nil {
+		return nil
+	}
+	return p.stmt
+}
+
+func (p *CreateViewParser) getError() error { return p.err }
+
+func (p *CreateViewParser) finalize() {
+	if p.err != nil {
+		return
+	}
+	// ";" を省略した場合は、ここで問い合わせを確定する
+	if p.state == CreateViewStateQuery {
+		p.finalizeQuery()
+	}
+	if p.err == nil && p.state != CreateViewStateEnd {
+		p.setError(errors.New("[parse error] incomplete CREATE VIEW statement"))
+	}
+}
+
+// finalizeQuery は問い合わせを確定する (";" または文の終わりの時点で呼ぶ)
+func (p *CreateViewParser) finalizeQuery() {
+	p.query.onSymbol(string(SSemicolon))
+	p.query.finalize()
+	if err := p.query.getError(); err != nil {
+		p.setError(err)
+		return
+	}
+	p.stmt.Query = p.query.getResult()
+	p.stmt.Definition = p.text.String()
+	p.state = CreateViewStateEnd
+}
+
+func (p *CreateViewParser) onKeyword(word string) {
+	if p.err != nil {
+		return
+	}
+	if p.state == CreateViewStateQuery {
+		p.query.onKeyword(word)
+		p.setError(p.query.getError())
+		p.appendText(strings.ToUpper(word), false)
+		return
+	}
+
+	upper := strings.ToUpper(word)
+	switch {
+	case p.state == StateInitial && upper == KCreate:
+		p.state = CreateViewStateCreate
+	case p.state == CreateViewStateCreate && upper == KOr:
+		p.state = CreateViewStateOr
+	case p.state == CreateViewStateOr && upper == KReplace:
+		p.stmt.OrReplace = true
+		p.state = CreateViewStateReplace
+	case (p.state == CreateViewStateCreate || p.state == CreateViewStateReplace) && upper == KView:
+		p.state = CreateViewStateView
+	case p.state == CreateViewStateOr:
+		p.setError(errors.New("[parse error] expected REPLACE after OR, got " + word))
+	case (p.state == CreateViewStateName || p.state == CreateViewStateColumnEnd) && upper == KAs:
+		p.state = CreateViewStateAs
+	case p.state == CreateViewStateAs && (upper == KSelect || upper == KWith):
+		p.query = NewSelectParser()
+		p.state = CreateViewStateQuery
+		p.query.onKeyword(word)
+		p.setError(p.query.getError())
+		p.appendText(upper, false)
+	case p.state == CreateViewStateAs:
+		p.setError(errors.New("[parse error] view must be defined by a SELECT statement"))
+	case p.state == CreateViewStateView || p.state == CreateViewStateColumn:
+		p.setError(errors.New("[parse error] unexpected keyword " + upper + " in CREATE VIEW statement"))
+	case p.state == CreateViewStateName || p.state == CreateViewStateColumnEnd:
+		p.setError(errors.New("[parse error] missing AS after view name " + p.stmt.ViewName))
+	default:
+		p.setError(errors.New("[parse error] unexpected keyword " + upper + " in CREATE VIEW statement"))
+	}
+}
+
+func (p *CreateViewParser) onIdentifier(ident string) {
+	if p.err != nil {
+		return
+	}
+
+	switch p.state {
+	case CreateViewStateQuery:
+		p.query.onIdentifier(ident)
+		p.setError(p.query.getError())
+		p.appendText(quoteIdentifier(ident), true)
+	case CreateViewStateView:
+		p.stmt.ViewName = ident
+		p.state = CreateViewStateName
+	case CreateViewStateColumn:
+		p.stmt.Columns = append(p.stmt.Columns, ident)
+		p.state = CreateViewStateColumnSep
+	default:
+		p.setError(errors.New("[parse error] unexpected identifier " + ident + " in CREATE VIEW statement"))
+	}
+}
+
+func (p *CreateViewParser) onSymbol(symbol string) {
+	if p.err != nil {
+		return
+	}
+
+	switch {
+	case p.state == CreateViewStateQuery && symbol == string(SSemicolon) && p.query.sub == nil:
+		// 文末の ";" は SQL 表現に含めない
+		p.finalizeQuery()
+	case p.state == CreateViewStateQuery:
+		p.query.onSymbol(symbol)
+		p.setError(p.query.getError())
+		p.appendText(symbol, false)
+	case p.state == CreateViewStateEnd && symbol == string(SSemicolon):
+		return
+	case p.state == CreateViewStateName && symbol == string(SLeftParen):
+		p.state = CreateViewStateColumn
+	case p.state == CreateViewStateColumnSep && symbol == string(SComma):
+		p.state = CreateViewStateColumn
+	case p.state == CreateViewStateColumnSep && symbol == string(SRightParen):
+		p.state = CreateViewStateColumnEnd
+	case p.state == CreateViewStateColumn:
+		p.setError(errors.New("[parse error] missing column name in column list of view " + p.stmt.ViewName))
+	default:
+		p.setError(errors.New("[parse error] unexpected symbol " + symbol + " in CREATE VIEW statement"))
+	}
+}
+
+func (p *CreateViewParser) onString(value string) {
+	if p.err != nil {
+		return
+	}
+	if p.state != CreateViewStateQuery {
+		p.setError(errors.New("[parse error] unexpected string " + value + " in CREATE VIEW statement"))
+		return
+	}
+	p.query.onString(value)
+	p.setError(p.query.getError())
+	p.appendText("'"+value+"'", false)
+}
+
+func (p *CreateViewParser) onNumber(num string) {
+	if p.err != nil {
+		return
+	}
+	if p.state != CreateViewStateQuery {
+		p.setError(errors.New("[parse error] unexpected number " + num + " in CREATE VIEW statement"))
+		return
+	}
+	p.query.onNumber(num)
+	p.setError(p.query.getError())
+	p.appendText(num, false)
+}
+
+func (p *CreateViewParser) onComment(_ string) {}
+
+func (p *CreateViewParser) onError(err error) { p.setError(err) }
+
+// appendText はトークンを問い合わせの SQL 表現に追加する
+//
+// "(" の後と ")" / "," の前、関数名 (識別子) と "(" の間には空白を入れない
+func (p *CreateViewParser) appendText(token string, ident bool) {
+	if p.text.Len() > 0 {
+		noSpace := p.prev == string(SLeftParen) ||
+			token == string(SRightParen) || token == string(SComma) ||
+			(token == string(SLeftParen) && p.prevIdent)
+		if !noSpace {
+			p.text.WriteByte(' ')
+		}
+	}
+	p.text.WriteString(token)
+	p.prev = token
+	p.prevIdent = ident
+}
+
+func (p *CreateViewParser) setError(err error) {
+	if p.err == nil && err != nil {
+		p.err = err
+	}
+}
+
+// quoteIdentifier は識別子を SQL 表現に変換する
+//
+// キーワードと同じ識別子や、英数字・"_"・"." 以外の文字を含む識別子 (ダブルクォートで囲まれていた識別子) はダブルクォートで囲む
+func quoteIdentifier(ident string) string {
+	if (&Tokenizer{}).isKeyword(ident) {
+		return "\"" + ident + "\""
+	}
+	for _, ch := range ident {
+		if ch != '_' && ch != '.' && (ch < '0' || ch > '9') && (ch < 'a' || ch > 'z') && (ch < 'A' || ch > 'Z') {
+			return "\"" + ident + "\""
+		}
+	}
+	return ident
+}
@@ -0,0 +1,110 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/ren-yamanashi/minesql/internal/ast"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParserCreateView(t *testing.T) {
+	t.Run("CREATE VIEW 文をパースできる", func(t *testing.T) {
+		// GIVEN
+		sql := "CREATE VIEW adults AS SELECT id, name FROM users WHERE age >= 20;"
+		parser := NewParser()
+
+		// WHEN
+		result, err := parser.Parse(sql)
+
+		// THEN
+		assert.NoError(t, err)
+		stmt, ok := result.(*ast.CreateViewStmt)
+		assert.True(t, ok)
+		assert.Equal(t, "adults", stmt.ViewName)
+		assert.False(t, stmt.OrReplace)
+		assert.Nil(t, stmt.Columns)
+		query, ok := stmt.Query.(*ast.SelectStmt)
+		assert.True(t, ok)
+		assert.Equal(t, "users", query.From.TableName)
+		assert.Equal(t, "SELECT id, name FROM users WHERE age >= 20", stmt.Definition)
+	})
+
+	t.Run("OR REPLACE とカラム名のリストを指定できる", func(t *testing.T) {
+		// GIVEN
+		sql := "create or replace view v (user_id, total) as select user_id, sum(price) from orders group by user_id"
+		parser := NewParser()
+
+		// WHEN
+		result, err := parser.Parse(sql)
+
+		// THEN
+		assert.NoError(t, err)
+		stmt, ok := result.(*ast.CreateViewStmt)
+		assert.True(t, ok)
+		assert.Equal(t, "v", stmt.ViewName)
+		assert.True(t, stmt.OrReplace)
+		assert.Equal(t, []string{"user_id", "total"}, stmt.Columns)
+		assert.Equal(t, "SELECT user_id, sum(price) FROM orders GROUP BY user_id", stmt.Definition)
+	})
+
+	t.Run("問い合わせの SQL 表現は文字列リテラルと識別子をクォートして組み立てる", func(t *testing.T) {
+		// GIVEN
+		sql := `CREATE VIEW v AS SELECT "user name", COUNT(*) FROM users WHERE name IN ('a', 'b') AND "order" = 1;`
+		parser := NewParser()
+
+		// WHEN
+		result, err := parser.Parse(sql)
+
+		// THEN
+		assert.NoError(t, err)
+		stmt := result.(*ast.CreateViewStmt)
+		assert.Equal(t, `SELECT "user name", COUNT(*) FROM users WHERE name IN ('a', 'b') AND "order" = 1`, stmt.Definition)
+		reparsed, err := NewParser().Parse(stmt.Definition + ";")
+		assert.NoError(t, err)
+		assert.Equal(t, stmt.Query, reparsed)
+	})
+
+	t.Run("WITH 句・集合演算を含む問い合わせを指定できる", func(t *testing.T) {
+		// GIVEN
+		sql := "CREATE VIEW v AS WITH t AS (SELECT id FROM a) SELECT id FROM t UNION SELECT id FROM b;"
+		parser := NewParser()
+
+		// WHEN
+		result, err := parser.Parse(sql)
+
+		// THEN
+		assert.NoError(t, err)
+		stmt := result.(*ast.CreateViewStmt)
+		_, ok := stmt.Query.(*ast.SetOperationStmt)
+		assert.True(t, ok)
+		assert.Equal(t, "WITH t AS (SELECT id FROM a) SELECT id FROM t UNION SELECT id FROM b", stmt.Definition)
+	})
+
+	t.Run("不正な CREATE VIEW 文でエラーになる", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			sql      string
+			expected string
+		}{
+			{"OR の後に REPLACE 以外が来た場合", "CREATE OR VIEW v AS SELECT id FROM users;", "expected REPLACE after OR"},
+			{"AS がない場合", "CREATE VIEW v SELECT id FROM users;", "missing AS after view name v"},
+			{"問い合わせが SELECT 文でない場合", "CREATE VIEW v AS DELETE FROM users;", "view must be defined by a SELECT statement"},
+			{"カラム名のリストが空の場合", "CREATE VIEW v () AS SELECT id FROM users;", "missing column name in column list of view v"},
+			{"問い合わせがない場合", "CREATE VIEW v AS", "incomplete CREATE VIEW statement"},
+			{"問い合わせが不正な場合", "CREATE VIEW v AS SELECT id;", "missing FROM clause"},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				// GIVEN
+				parser := NewParser()
+
+				// WHEN
+				result, err := parser.Parse(tt.sql)
+
+				// THEN
+				assert.Nil(t, result)
+				assert.ErrorContains(t, err, tt.expected)
+			})
+		}
+	})
+}
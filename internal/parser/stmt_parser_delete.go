@@ -14,6 +14,7 @@ var (
 type DeleteParser struct {
 	state parserState     // 現在のステート
 	stmt  *ast.DeleteStmt // 現在構築中の DELETE 文
+	join  *JoinParser     // JOIN 句パーサー (nil なら JOIN 句なし)
 	where WhereParser     // WHERE 句パーサー
 	err   error           // エラー情報
 }
@@ -49,6 +50,12 @@ func (dp *DeleteParser) finalize() {
 		return
 	}
 
+	// 複数テーブルの DELETE では削除対象のテーブルの指定が必須
+	if len(dp.stmt.Joins) > 0 && dp.stmt.Target == "" {
+		dp.setError(errors.New("[parse error] multi-table DELETE requires target table before FROM"))
+		return
+	}
+
 	// WHERE 句を確定
 	whereClause, err := dp.where.finalizeWhere()
 	if err != nil {
@@ -71,14 +78,41 @@ func (dp *DeleteParser) onKeyword(word string) {
 		return
 
 	case KFrom:
-		if dp.state == DeleteStateDelete {
+		if dp.state == DeleteStateDelete || dp.state == DeleteStateTarget {
 			dp.state = DeleteStateFrom
 			return
 		}
 		dp.setError(errors.New("[parse error] FROM clause is in invalid position"))
 		return
 
+	case KInner, KLeft, KRight, KJoin:
+		// JOIN 句は FROM のテーブル名の後に来る (以降は JOIN 句のパーサーに転送する)
+		if dp.state == DeleteStateFrom && dp.stmt.From.TableName != "" {
+			dp.join = NewJoinParser()
+			dp.state = DeleteStateJoin
+		}
+		if dp.state == DeleteStateJoin {
+			dp.setError(dp.join.onKeyword(upperWord))
+			return
+		}
+		dp.setError(errors.New("[parse error] " + upperWord + " keyword is in invalid position"))
+		return
+
+	case KOuter, KOn:
+		if dp.state == DeleteStateJoin {
+			dp.setError(dp.join.onKeyword(upperWord))
+			return
+		}
+		dp.setError(errors.New("[parse error] " + upperWord + " keyword is in invalid position"))
+		return
+
 	case KWhere:
+		if dp.state == DeleteStateJoin && !dp.join.inNested() {
+			if !dp.finalizeJoin() {
+				return
+			}
+			dp.state = DeleteStateFrom
+		}
 		if dp.state == DeleteStateFrom {
 			dp.stmt.Where = dp.where.initWhere()
 			dp.state = DeleteStateWhere
@@ -88,6 +122,10 @@ func (dp *DeleteParser) onKeyword(word string) {
 		return
 
 	case KAnd, KOr:
+		if dp.state == DeleteStateJoin {
+			dp.setError(dp.join.onKeyword(upperWord))
+			return
+		}
 		if dp.state == DeleteStateWhere {
 			if err := dp.where.handleOperator(upperWord); err != nil {
 				dp.setError(err)
@@ -97,6 +135,20 @@ func (dp *DeleteParser) onKeyword(word string) {
 		dp.setError(errors.New("[parse error] " + upperWord + " operator is in invalid position"))
 		return
 
+	case KIs, KNot, KNull, KIn, KBetween, KLike, KCase, KWhen, KThen, KElse, KEnd:
+		if dp.state == DeleteStateJoin {
+			dp.setError(dp.join.onKeyword(upperWord))
+			return
+		}
+		if dp.state == DeleteStateWhere {
+			if err := dp.where.handleKeyword(upperWord); err != nil {
+				dp.setError(err)
+			}
+			return
+		}
+		dp.setError(errors.New("[parse error] " + upperWord + " is in invalid position"))
+		return
+
 	default:
 		dp.setError(errors.New("[parse error] unsupported keyword: " + word))
 		return
@@ -109,10 +161,18 @@ func (dp *DeleteParser) onIdentifier(ident string) {
 	}
 
 	switch dp.state {
+	case DeleteStateDelete:
+		// 削除対象のテーブル名 (`DELETE t FROM ...`)
+		dp.stmt.Target = ident
+		dp.state = DeleteStateTarget
 	case DeleteStateFrom:
 		dp.stmt.From = *ast.NewTableId(ident)
+	case DeleteStateJoin:
+		dp.setError(dp.join.onIdentifier(ident))
 	case DeleteStateWhere:
-		dp.where.pushColumn(ident)
+		if err := dp.where.pushColumn(ident); err != nil {
+			dp.setError(err)
+		}
 	default:
 		dp.setError(errors.New("[parse error] unexpected identifier: " + ident))
 	}
@@ -125,11 +185,16 @@ func (dp *DeleteParser) onSymbol(symbol string) {
 
 	// ";" が来たら state を End にする
 	if symbol == string(SSemicolon) {
+		if dp.state == DeleteStateJoin && !dp.finalizeJoin() {
+			return
+		}
 		dp.state = DeleteStateEnd
 		return
 	}
 
 	switch dp.state {
+	case DeleteStateJoin:
+		dp.setError(dp.join.onSymbol(symbol))
 	case DeleteStateWhere:
 		if err := dp.where.handleOperator(symbol); err != nil {
 			dp.setError(err)
@@ -143,8 +208,14 @@ func (dp *DeleteParser) onString(value string) {
 	if dp.err != nil {
 		return
 	}
+	if dp.state == DeleteStateJoin {
+		dp.setError(dp.join.onString(value))
+		return
+	}
 	if dp.state == DeleteStateWhere {
-		dp.where.pushLiteral(ast.NewStringLiteral(value))
+		if err := dp.where.pushLiteral(ast.NewStringLiteral(value)); err != nil {
+			dp.setError(err)
+		}
 	}
 }
 
@@ -152,14 +223,33 @@ func (dp *DeleteParser) onNumber(num string) {
 	if dp.err != nil {
 		return
 	}
+	if dp.state == DeleteStateJoin {
+		dp.setError(dp.join.onNumber(num))
+		return
+	}
 	if dp.state == DeleteStateWhere {
-		dp.where.pushLiteral(ast.NewStringLiteral(num))
+		if err := dp.where.pushLiteral(ast.NewNumberLiteral(num)); err != nil {
+			dp.setError(err)
+		}
 	}
 }
 
 func (dp *DeleteParser) onComment(text string) {}
 func (dp *DeleteParser) onError(err error)     { dp.setError(err) }
 
+// finalizeJoin は JOIN 句を確定して DeleteStmt に設定する (WHERE or ";" の時点で呼ぶ)
+//
+// エラーの場合は false を返す
+func (dp *DeleteParser) finalizeJoin() bool {
+	joins, err := dp.join.finalize()
+	if err != nil {
+		dp.setError(err)
+		return false
+	}
+	dp.stmt.Joins = joins
+	return true
+}
+
 // setError はエラーを設定する (既にエラーが設定されている場合は無視する)
 func (dp *DeleteParser) setError(err error) {
 	if dp.err == nil {
@@ -47,7 +47,7 @@ func TestParserDelete(t *testing.T) {
 		assert.NotNil(t, deleteStmt.Where)
 		assert.NotNil(t, deleteStmt.Where.Condition)
 
-		binaryExpr := deleteStmt.Where.Condition
+		binaryExpr := deleteStmt.Where.Condition.(*ast.BinaryExpr)
 		assert.Equal(t, "=", binaryExpr.Operator)
 
 		lhsCol, ok := binaryExpr.Left.(*ast.LhsColumn)
@@ -160,7 +160,7 @@ func TestParserDelete(t *testing.T) {
 
 		t.Run("不正な位置での識別子でエラーを返す", func(t *testing.T) {
 			// GIVEN
-			sql := "DELETE users FROM users;"
+			sql := "DELETE users users FROM users;"
 			parser := NewParser()
 
 			// WHEN
@@ -203,7 +203,75 @@ func TestParserDelete(t *testing.T) {
 		assert.True(t, ok)
 
 		assert.NotNil(t, deleteStmt.Where)
-		andExpr := deleteStmt.Where.Condition
+		andExpr := deleteStmt.Where.Condition.(*ast.BinaryExpr)
 		assert.Equal(t, "AND", andExpr.Operator)
 	})
 }
+
+func TestParserDeleteJoin(t *testing.T) {
+	t.Run("JOIN 句付きの DELETE 文をパースできる", func(t *testing.T) {
+		// GIVEN
+		sql := "DELETE orders FROM orders JOIN customers ON orders.customer_id = customers.id WHERE customers.active = 0;"
+		parser := NewParser()
+
+		// WHEN
+		result, err := parser.Parse(sql)
+
+		// THEN
+		assert.NoError(t, err)
+		deleteStmt, ok := result.(*ast.DeleteStmt)
+		assert.True(t, ok)
+		assert.Equal(t, "orders", deleteStmt.Target)
+		assert.Equal(t, "orders", deleteStmt.From.TableName)
+		assert.Equal(t, 1, len(deleteStmt.Joins))
+		assert.Equal(t, "customers", deleteStmt.Joins[0].Table.TableName)
+		assert.Equal(t, "orders.customer_id = customers.id", ast.ExprString(deleteStmt.Joins[0].Condition))
+		assert.Equal(t, "customers.active = 0", ast.ExprString(deleteStmt.Where.Condition))
+	})
+
+	t.Run("WHERE 句なしの JOIN 句付きの DELETE 文をパースできる", func(t *testing.T) {
+		// GIVEN
+		sql := "DELETE customers FROM orders RIGHT JOIN customers ON orders.customer_id = customers.id;"
+		parser := NewParser()
+
+		// WHEN
+		result, err := parser.Parse(sql)
+
+		// THEN
+		assert.NoError(t, err)
+		deleteStmt, ok := result.(*ast.DeleteStmt)
+		assert.True(t, ok)
+		assert.Equal(t, "customers", deleteStmt.Target)
+		assert.Equal(t, ast.JoinTypeRight, deleteStmt.Joins[0].Type)
+		assert.Nil(t, deleteStmt.Where)
+	})
+
+	t.Run("削除対象のテーブルを指定した単一テーブルの DELETE 文をパースできる", func(t *testing.T) {
+		// GIVEN
+		sql := "DELETE users FROM users WHERE id = 1;"
+		parser := NewParser()
+
+		// WHEN
+		result, err := parser.Parse(sql)
+
+		// THEN
+		assert.NoError(t, err)
+		deleteStmt, ok := result.(*ast.DeleteStmt)
+		assert.True(t, ok)
+		assert.Equal(t, "users", deleteStmt.Target)
+		assert.Nil(t, deleteStmt.Joins)
+	})
+
+	t.Run("削除対象のテーブルを指定しない JOIN 句付きの DELETE 文はエラーになる", func(t *testing.T) {
+		// GIVEN
+		sql := "DELETE FROM orders JOIN customers ON orders.customer_id = customers.id;"
+		parser := NewParser()
+
+		// WHEN
+		result, err := parser.Parse(sql)
+
+		// THEN
+		assert.Nil(t, result)
+		assert.ErrorContains(t, err, "multi-table DELETE requires target table before FROM")
+	})
+}
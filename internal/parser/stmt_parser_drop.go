@@ -0,0 +1,140 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ren-yamanashi/minesql/internal/ast"
+)
+
+// DropParser は DROP TABLE / DROP INDEX / DROP VIEW / DROP DATABASE をパースする
+//
+// DROP の次のキーワードを検出した時点で対象の文のパーサーを生成し、DROP キーワードと以降のトークンをそのまま転送する
+type DropParser struct {
+	state parserState
+	inner StatementParser // 対象の文のパーサー
+	err   error
+}
+
+func NewDropParser() *DropParser {
+	return &DropParser{state: StateInitial}
+}
+
+func (dp *DropParser) getResult() ast.Statement {
+	if dp.err != nil || dp.inner == nil {
+		return nil
+	}
+	return dp.inner.getResult()
+}
+
+func (dp *DropParser) getError() error {
+	if dp.err != nil {
+		return dp.err
+	}
+	if dp.inner != nil {
+		return dp.inner.getError()
+	}
+	return nil
+}
+
+func (dp *DropParser) finalize() {
+	if dp.err != nil {
+		return
+	}
+	if dp.inner == nil {
+		dp.err = fmt.Errorf("[parse error] expected TABLE, INDEX, VIEW or DATABASE after DROP")
+		return
+	}
+	dp.inner.finalize()
+}
+
+func (dp *DropParser) onKeyword(word string) {
+	if dp.err != nil {
+		return
+	}
+	if dp.state == DropStateInner {
+		dp.inner.onKeyword(word)
+		return
+	}
+
+	upper := strings.ToUpper(word)
+	switch {
+	case dp.state == StateInitial && upper == KDrop:
+		dp.state = DropStateDispatch
+	case dp.state == DropStateDispatch && upper == KTable:
+		dp.startInner(NewDropTableParser(), word)
+	case dp.state == DropStateDispatch && upper == KIndex:
+		dp.startInner(NewDropIndexParser(), word)
+	case dp.state == DropStateDispatch && upper == KView:
+		dp.startInner(NewDropViewParser(), word)
+	case dp.state == DropStateDispatch && (upper == KDatabase || upper == KSchema):
+		dp.startInner(NewDropDatabaseParser(), word)
+	default:
+		dp.err = fmt.Errorf("[parse error] expected TABLE, INDEX, VIEW or DATABASE after DROP, got %q", word)
+	}
+}
+
+// startInner は対象の文のパーサーを生成し、DROP キーワードと 2 番目のキーワードを転送する
+func (dp *DropParser) startInner(inner StatementParser, word string) {
+	dp.inner = inner
+	dp.state = DropStateInner
+	dp.inner.onKeyword(KDrop)
+	dp.inner.onKeyword(word)
+}
+
+func (dp *DropParser) onIdentifier(ident string) {
+	if dp.err != nil {
+		return
+	}
+	if dp.state != DropStateInner {
+		dp.err = fmt.Errorf("[parse error] expected TABLE, INDEX, VIEW or DATABASE after DROP, got %q", ident)
+		return
+	}
+	dp.inner.onIdentifier(ident)
+}
+
+func (dp *DropParser) onString(value string) {
+	if dp.err != nil {
+		return
+	}
+	if dp.state != DropStateInner {
+		dp.err = fmt.Errorf("[parse error] expected TABLE, INDEX, VIEW or DATABASE after DROP, got %q", value)
+		return
+	}
+	dp.inner.onString(value)
+}
+
+func (dp *DropParser) onNumber(num string) {
+	if dp.err != nil {
+		return
+	}
+	if dp.state != DropStateInner {
+		dp.err = fmt.Errorf("[parse error] expected TABLE, INDEX, VIEW or DATABASE after DROP, got %q", num)
+		return
+	}
+	dp.inner.onNumber(num)
+}
+
+func (dp *DropParser) onSymbol(symbol string) {
+	if dp.err != nil {
+		return
+	}
+	if dp.state != DropStateInner {
+		dp.err = fmt.Errorf("[parse error] expected TABLE, INDEX, VIEW or DATABASE after DROP, got %q", symbol)
+		return
+	}
+	dp.inner.onSymbol(symbol)
+}
+
+func (dp *DropParser) onComment(text string) {
+	if dp.inner != nil {
+		dp.inner.onComment(text)
+	}
+}
+
+func (dp *DropParser) onError(err error) {
+	if dp.err != nil {
+		return
+	}
+	dp.err = err
+}
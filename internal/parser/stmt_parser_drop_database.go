@@ -0,0 +1,22 @@
+package parser
+
+import (
+	"github.com/ren-yamanashi/minesql/internal/ast"
+)
+
+// dropDatabaseKind は DROP DATABASE 文の対象
+//
+// 構文: DROP {DATABASE | SCHEMA} [IF EXISTS] db_name;
+var dropDatabaseKind = dropObjectKind{
+	keyword: KDatabase,
+	aliases: []string{KSchema},
+	noun:    "database",
+	newStmt: func(name string, ifExists bool) ast.Statement {
+		return &ast.DropDatabaseStmt{DatabaseName: name, IfExists: ifExists}
+	},
+}
+
+// NewDropDatabaseParser は DROP DATABASE 文をパースする DropObjectParser を作成する
+func NewDropDatabaseParser() *DropObjectParser {
+	return newDropObjectParser(dropDatabaseKind)
+}
@@ -0,0 +1,68 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/ren-yamanashi/minesql/internal/ast"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParserDropDatabase(t *testing.T) {
+	t.Run("DROP DATABASE 文をパースできる", func(t *testing.T) {
+		// GIVEN
+		sql := "DROP DATABASE app;"
+		parser := NewParser()
+
+		// WHEN
+		result, err := parser.Parse(sql)
+
+		// THEN
+		assert.NoError(t, err)
+		stmt, ok := result.(*ast.DropDatabaseStmt)
+		assert.True(t, ok)
+		assert.Equal(t, "app", stmt.DatabaseName)
+		assert.False(t, stmt.IfExists)
+	})
+
+	t.Run("IF EXISTS 付きの DROP SCHEMA 文をパースできる", func(t *testing.T) {
+		// GIVEN
+		sql := "drop schema if exists app"
+		parser := NewParser()
+
+		// WHEN
+		result, err := parser.Parse(sql)
+
+		// THEN
+		assert.NoError(t, err)
+		stmt, ok := result.(*ast.DropDatabaseStmt)
+		assert.True(t, ok)
+		assert.Equal(t, "app", stmt.DatabaseName)
+		assert.True(t, stmt.IfExists)
+	})
+
+	t.Run("不正な DROP DATABASE 文でエラーになる", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			sql      string
+			expected string
+		}{
+			{"IF の後に EXISTS 以外が来た場合", "DROP DATABASE IF app;", "expected EXISTS after IF"},
+			{"データベース名がない場合", "DROP DATABASE;", "unexpected symbol \";\" in DROP DATABASE statement"},
+			{"IF EXISTS の後にデータベース名がない場合", "DROP DATABASE IF EXISTS", "incomplete DROP DATABASE statement"},
+			{"複数のデータベース名を指定した場合", "DROP DATABASE a, b;", "expected ';' at end of DROP DATABASE statement"},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				// GIVEN
+				parser := NewParser()
+
+				// WHEN
+				result, err := parser.Parse(tt.sql)
+
+				// THEN
+				assert.Nil(t, result)
+				assert.ErrorContains(t, err, tt.expected)
+			})
+		}
+	})
+}
@@ -0,0 +1,131 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ren-yamanashi/minesql/internal/ast"
+)
+
+// DropIndexParser は DROP INDEX 文をパースする
+//
+// 構文: DROP INDEX index_name ON table_name;
+type DropIndexParser struct {
+	state parserState
+	stmt  ast.DropIndexStmt
+	err   error
+}
+
+func NewDropIndexParser() *DropIndexParser {
+	return &DropIndexParser{}
+}
+
+func (p *DropIndexParser) getResult() ast.Statement {
+	if p.err != nil {
+		return nil
+	}
+	return &p.stmt
+}
+
+func (p *DropIndexParser) getError() error { return p.err }
+
+func (p *DropIndexParser) finalize() {
+	if p.err != nil {
+		return
+	}
+	if p.state != DropIndexStateEnd {
+		p.setError(fmt.Errorf("[parse error] incomplete DROP INDEX statement"))
+	}
+}
+
+func (p *DropIndexParser) onKeyword(word string) {
+	if p.err != nil {
+		return
+	}
+
+	upper := strings.ToUpper(word)
+
+	switch p.state {
+	case DropIndexStateDrop:
+		// DROP の次は INDEX のみ
+		if upper != KIndex {
+			p.setError(fmt.Errorf("[parse error] expected INDEX after DROP, got %q", word))
+		}
+		p.state = DropIndexStateIndex
+
+	case DropIndexStateIndexName:
+		// インデックス名の次は ON のみ
+		if upper != KOn {
+			p.setError(fmt.Errorf("[parse error] expected ON after index name, got %q", word))
+		}
+		p.state = DropIndexStateOn
+
+	default:
+		if p.state == StateInitial && upper == KDrop {
+			p.state = DropIndexStateDrop
+			return
+		}
+		p.setError(fmt.Errorf("[parse error] unexpected keyword %q in DROP INDEX statement", word))
+	}
+}
+
+func (p *DropIndexParser) onIdentifier(ident string) {
+	if p.err != nil {
+		return
+	}
+
+	switch p.state {
+	case DropIndexStateIndex:
+		p.stmt.IndexName = ident
+		p.state = DropIndexStateIndexName
+
+	case DropIndexStateOn:
+		p.stmt.TableName = ident
+		p.state = DropIndexStateEnd
+
+	default:
+		p.setError(fmt.Errorf("[parse error] unexpected identifier %q in DROP INDEX statement", ident))
+	}
+}
+
+func (p *DropIndexParser) onSymbol(symbol string) {
+	if p.err != nil {
+		return
+	}
+
+	switch p.state {
+	case DropIndexStateEnd:
+		if symbol == string(SSemicolon) {
+			return
+		}
+		p.setError(fmt.Errorf("[parse error] expected ';' at end of DROP INDEX statement, got %q", symbol))
+
+	default:
+		p.setError(fmt.Errorf("[parse error] unexpected symbol %q in DROP INDEX statement", symbol))
+	}
+}
+
+func (p *DropIndexParser) onString(value string) {
+	if p.err != nil {
+		return
+	}
+	p.setError(fmt.Errorf("[parse error] unexpected string %q in DROP INDEX statement", value))
+}
+
+func (p *DropIndexParser) onNumber(num string) {
+	if p.err != nil {
+		return
+	}
+	p.setError(fmt.Errorf("[parse error] unexpected number %q in DROP INDEX statement", num))
+}
+
+func (p *DropIndexParser) onComment(_ string) {}
+
+func (p *DropIndexParser) onError(err error) { p.setError(err) }
+
+// エラーを設定する (既にエラーが設定されている場合は無視する)
+func (p *DropIndexParser) setError(err error) {
+	if p.err == nil {
+		p.err = err
+	}
+}
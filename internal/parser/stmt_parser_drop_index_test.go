@@ -0,0 +1,50 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/ren-yamanashi/minesql/internal/ast"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParserDropIndex(t *testing.T) {
+	t.Run("DROP INDEX 文をパースできる", func(t *testing.T) {
+		// GIVEN
+		sql := "DROP INDEX idx_name ON users;"
+		parser := NewParser()
+
+		// WHEN
+		result, err := parser.Parse(sql)
+
+		// THEN
+		assert.NoError(t, err)
+		assert.Equal(t, &ast.DropIndexStmt{IndexName: "idx_name", TableName: "users"}, result)
+	})
+
+	t.Run("不正な DROP INDEX 文でエラーになる", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			sql      string
+			expected string
+		}{
+			{"インデックス名がない場合", "DROP INDEX ON users;", "unexpected keyword \"ON\" in DROP INDEX statement"},
+			{"インデックス名の後に ON 以外が来た場合", "DROP INDEX idx FROM users;", "expected ON after index name"},
+			{"テーブル名がない場合", "DROP INDEX idx ON;", "unexpected symbol \";\" in DROP INDEX statement"},
+			{"ON の後で終わっている場合", "DROP INDEX idx ON", "incomplete DROP INDEX statement"},
+			{"複数のテーブル名を指定した場合", "DROP INDEX idx ON users, orders;", "expected ';' at end of DROP INDEX statement"},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				// GIVEN
+				parser := NewParser()
+
+				// WHEN
+				result, err := parser.Parse(tt.sql)
+
+				// THEN
+				assert.Nil(t, result)
+				assert.ErrorContains(t, err, tt.expected)
+			})
+		}
+	})
+}
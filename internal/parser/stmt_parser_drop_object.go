@@ -0,0 +1,150 @@
+package parser
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/ren-yamanashi/minesql/internal/ast"
+)
+
+// dropObjectKind は DropObjectParser がパースする DROP 文の対象 (テーブル・ビューなど) を表す
+type dropObjectKind struct {
+	keyword string                                         // 対象を表すキーワード (e.g. TABLE)。エラーメッセージにも使う
+	aliases []string                                       // keyword の別名 (e.g. DATABASE に対する SCHEMA)
+	noun    string                                         // 対象の名前の呼び方 (e.g. table)。エラーメッセージに使う
+	newStmt func(name string, ifExists bool) ast.Statement // パース結果の文を作成する
+}
+
+// accepts は DROP の次のキーワードが対象を表すかどうかを返す
+func (k dropObjectKind) accepts(upper string) bool {
+	return upper == k.keyword || slices.Contains(k.aliases, upper)
+}
+
+// DropObjectParser は DROP TABLE / DROP VIEW / DROP DATABASE のように、対象の名前 1 つを削除する DROP 文をパースする
+//
+// 構文: DROP {TABLE | VIEW | DATABASE | SCHEMA} [IF EXISTS] name;
+// 対象 (kind) によって変わるのはキーワードとパース結果の文のみで、状態遷移は共通
+type DropObjectParser struct {
+	kind     dropObjectKind
+	state    parserState
+	name     string
+	ifExists bool
+	err      error
+}
+
+func newDropObjectParser(kind dropObjectKind) *DropObjectParser {
+	return &DropObjectParser{kind: kind}
+}
+
+func (p *DropObjectParser) getResult() ast.Statement {
+	if p.err != nil {
+		return nil
+	}
+	return p.kind.newStmt(p.name, p.ifExists)
+}
+
+func (p *DropObjectParser) getError() error { return p.err }
+
+func (p *DropObjectParser) finalize() {
+	if p.err != nil {
+		return
+	}
+	if p.state != DropObjectStateEnd {
+		p.err = fmt.Errorf("[parse error] incomplete DROP %s statement", p.kind.keyword)
+	}
+}
+
+func (p *DropObjectParser) onKeyword(word string) {
+	if p.err != nil {
+		return
+	}
+
+	upper := strings.ToUpper(word)
+
+	switch p.state {
+	case DropObjectStateDrop:
+		// DROP の次は対象を表すキーワードのみ
+		if !p.kind.accepts(upper) {
+			p.err = fmt.Errorf("[parse error] expected %s after DROP, got %q", strings.Join(append([]string{p.kind.keyword}, p.kind.aliases...), " or "), word)
+		}
+		p.state = DropObjectStateKind
+
+	case DropObjectStateKind:
+		// 対象を表すキーワードの次は IF または対象の名前
+		if upper != KIf {
+			p.err = fmt.Errorf("[parse error] expected IF or %s name after %s, got %q", p.kind.noun, p.kind.keyword, word)
+		}
+		p.state = DropObjectStateIf
+
+	case DropObjectStateIf:
+		// IF の次は EXISTS のみ
+		if upper != KExists {
+			p.err = fmt.Errorf("[parse error] expected EXISTS after IF, got %q", word)
+		}
+		p.ifExists = true
+		p.state = DropObjectStateExists
+
+	default:
+		if upper == KDrop {
+			p.state = DropObjectStateDrop
+			return
+		}
+		p.err = fmt.Errorf("[parse error] unexpected keyword %q in DROP %s statement", word, p.kind.keyword)
+	}
+}
+
+func (p *DropObjectParser) onIdentifier(ident string) {
+	if p.err != nil {
+		return
+	}
+
+	switch p.state {
+	case DropObjectStateKind, DropObjectStateExists:
+		p.name = ident
+		p.state = DropObjectStateEnd
+
+	case DropObjectStateIf:
+		p.err = fmt.Errorf("[parse error] expected EXISTS after IF, got %q", ident)
+
+	default:
+		p.err = fmt.Errorf("[parse error] unexpected identifier %q in DROP %s statement", ident, p.kind.keyword)
+	}
+}
+
+func (p *DropObjectParser) onSymbol(symbol string) {
+	if p.err != nil {
+		return
+	}
+
+	switch p.state {
+	case DropObjectStateEnd:
+		if symbol == ";" {
+			return
+		}
+		p.err = fmt.Errorf("[parse error] expected ';' at end of DROP %s statement, got %q", p.kind.keyword, symbol)
+
+	default:
+		p.err = fmt.Errorf("[parse error] unexpected symbol %q in DROP %s statement", symbol, p.kind.keyword)
+	}
+}
+
+func (p *DropObjectParser) onString(value string) {
+	if p.err != nil {
+		return
+	}
+	p.err = fmt.Errorf("[parse error] unexpected string %q in DROP %s statement", value, p.kind.keyword)
+}
+
+func (p *DropObjectParser) onNumber(_ string) {
+	if p.err != nil {
+		return
+	}
+	p.err = fmt.Errorf("[parse error] unexpected number in DROP %s statement", p.kind.keyword)
+}
+
+func (p *DropObjectParser) onComment(_ string) {}
+
+func (p *DropObjectParser) onError(err error) {
+	p.err = err
+}
@@ -0,0 +1,21 @@
+package parser
+
+import (
+	"github.com/ren-yamanashi/minesql/internal/ast"
+)
+
+// dropTableKind は DROP TABLE 文の対象
+//
+// 構文: DROP TABLE [IF EXISTS] table_name;
+var dropTableKind = dropObjectKind{
+	keyword: KTable,
+	noun:    "table",
+	newStmt: func(name string, ifExists bool) ast.Statement {
+		return &ast.DropTableStmt{TableName: name, IfExists: ifExists}
+	},
+}
+
+// NewDropTableParser は DROP TABLE 文をパースする DropObjectParser を作成する
+func NewDropTableParser() *DropObjectParser {
+	return newDropObjectParser(dropTableKind)
+}
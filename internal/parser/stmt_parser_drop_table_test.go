@@ -0,0 +1,84 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/ren-yamanashi/minesql/internal/ast"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParserDropTable(t *testing.T) {
+	t.Run("DROP TABLE 文をパースできる", func(t *testing.T) {
+		// GIVEN
+		sql := "DROP TABLE users;"
+		parser := NewParser()
+
+		// WHEN
+		result, err := parser.Parse(sql)
+
+		// THEN
+		assert.NoError(t, err)
+		stmt, ok := result.(*ast.DropTableStmt)
+		assert.True(t, ok)
+		assert.Equal(t, "users", stmt.TableName)
+		assert.False(t, stmt.IfExists)
+	})
+
+	t.Run("IF EXISTS 付きの DROP TABLE 文をパースできる", func(t *testing.T) {
+		// GIVEN
+		sql := "drop table if exists users;"
+		parser := NewParser()
+
+		// WHEN
+		result, err := parser.Parse(sql)
+
+		// THEN
+		assert.NoError(t, err)
+		stmt, ok := result.(*ast.DropTableStmt)
+		assert.True(t, ok)
+		assert.Equal(t, "users", stmt.TableName)
+		assert.True(t, stmt.IfExists)
+	})
+
+	t.Run("セミコロンなしでもパースできる", func(t *testing.T) {
+		// GIVEN
+		sql := "DROP TABLE users"
+		parser := NewParser()
+
+		// WHEN
+		result, err := parser.Parse(sql)
+
+		// THEN
+		assert.NoError(t, err)
+		stmt, ok := result.(*ast.DropTableStmt)
+		assert.True(t, ok)
+		assert.Equal(t, "users", stmt.TableName)
+	})
+
+	t.Run("不正な DROP TABLE 文でエラーになる", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			sql      string
+			expected string
+		}{
+			{"DROP の後に TABLE 以外が来た場合", "DROP USER users;", "expected TABLE, INDEX, VIEW or DATABASE after DROP"},
+			{"IF の後に EXISTS 以外が来た場合", "DROP TABLE IF users;", "expected EXISTS after IF"},
+			{"テーブル名がない場合", "DROP TABLE;", "unexpected symbol \";\" in DROP TABLE statement"},
+			{"IF EXISTS の後にテーブル名がない場合", "DROP TABLE IF EXISTS", "incomplete DROP TABLE statement"},
+			{"複数のテーブル名を指定した場合", "DROP TABLE users, orders;", "expected ';' at end of DROP TABLE statement"},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				// GIVEN
+				parser := NewParser()
+
+				// WHEN
+				result, err := parser.Parse(tt.sql)
+
+				// THEN
+				assert.Nil(t, result)
+				assert.ErrorContains(t, err, tt.expected)
+			})
+		}
+	})
+}
@@ -0,0 +1,21 @@
+package parser
+
+import (
+	"github.com/ren-yamanashi/minesql/internal/ast"
+)
+
+// dropViewKind は DROP VIEW 文の対象
+//
+// 構文: DROP VIEW [IF EXISTS] view_name;
+var dropViewKind = dropObjectKind{
+	keyword: KView,
+	noun:    "view",
+	newStmt: func(name string, ifExists bool) ast.Statement {
+		return &ast.DropViewStmt{ViewName: name, IfExists: ifExists}
+	},
+}
+
+// NewDropViewParser は DROP VIEW 文をパースする DropObjectParser を作成する
+func NewDropViewParser() *DropObjectParser {
+	return newDropObjectParser(dropViewKind)
+}
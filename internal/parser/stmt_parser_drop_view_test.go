@@ -0,0 +1,68 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/ren-yamanashi/minesql/internal/ast"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParserDropView(t *testing.T) {
+	t.Run("DROP VIEW 文をパースできる", func(t *testing.T) {
+		// GIVEN
+		sql := "DROP VIEW adults;"
+		parser := NewParser()
+
+		// WHEN
+		result, err := parser.Parse(sql)
+
+		// THEN
+		assert.NoError(t, err)
+		stmt, ok := result.(*ast.DropViewStmt)
+		assert.True(t, ok)
+		assert.Equal(t, "adults", stmt.ViewName)
+		assert.False(t, stmt.IfExists)
+	})
+
+	t.Run("IF EXISTS 付きの DROP VIEW 文をパースできる", func(t *testing.T) {
+		// GIVEN
+		sql := "drop view if exists adults"
+		parser := NewParser()
+
+		// WHEN
+		result, err := parser.Parse(sql)
+
+		// THEN
+		assert.NoError(t, err)
+		stmt, ok := result.(*ast.DropViewStmt)
+		assert.True(t, ok)
+		assert.Equal(t, "adults", stmt.ViewName)
+		assert.True(t, stmt.IfExists)
+	})
+
+	t.Run("不正な DROP VIEW 文でエラーになる", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			sql      string
+			expected string
+		}{
+			{"IF の後に EXISTS 以外が来た場合", "DROP VIEW IF adults;", "expected EXISTS after IF"},
+			{"ビュー名がない場合", "DROP VIEW;", "unexpected symbol \";\" in DROP VIEW statement"},
+			{"IF EXISTS の後にビュー名がない場合", "DROP VIEW IF EXISTS", "incomplete DROP VIEW statement"},
+			{"複数のビュー名を指定した場合", "DROP VIEW a, b;", "expected ';' at end of DROP VIEW statement"},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				// GIVEN
+				parser := NewParser()
+
+				// WHEN
+				result, err := parser.Parse(tt.sql)
+
+				// THEN
+				assert.Nil(t, result)
+				assert.ErrorContains(t, err, tt.expected)
+			})
+		}
+	})
+}
@@ -0,0 +1,148 @@
+package parser
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/ren-yamanashi/minesql/internal/ast"
+)
+
+// ExplainParser は EXPLAIN [ANALYZE] <SELECT | UPDATE | DELETE> をパースする
+//
+// 対象の文のキーワードを検出した時点で対象の文のパーサーを生成し、それ以降のトークンをそのまま転送する
+type ExplainParser struct {
+	state   parserState
+	analyze bool
+	inner   StatementParser // 対象の文のパーサー
+	err     error
+}
+
+func NewExplainParser() *ExplainParser {
+	return &ExplainParser{state: StateInitial}
+}
+
+func (ep *ExplainParser) getResult() ast.Statement {
+	if ep.err != nil || ep.inner == nil {
+		return nil
+	}
+	return &ast.ExplainStmt{Analyze: ep.analyze, Stmt: ep.inner.getResult()}
+}
+
+func (ep *ExplainParser) getError() error {
+	if ep.err != nil {
+		return ep.err
+	}
+	if ep.inner != nil {
+		return ep.inner.getError()
+	}
+	return nil
+}
+
+func (ep *ExplainParser) finalize() {
+	if ep.err != nil {
+		return
+	}
+	if ep.inner == nil {
+		ep.err = errors.New("[parse error] EXPLAIN must be followed by SELECT, UPDATE or DELETE statement")
+		return
+	}
+	ep.inner.finalize()
+}
+
+func (ep *ExplainParser) onKeyword(word string) {
+	if ep.err != nil {
+		return
+	}
+	if ep.state == ExplainStateStmt {
+		ep.inner.onKeyword(word)
+		return
+	}
+
+	switch strings.ToUpper(word) {
+	case KExplain:
+		if ep.state == StateInitial {
+			ep.state = ExplainStateExplain
+			return
+		}
+	case KAnalyze:
+		if ep.state == ExplainStateExplain {
+			ep.analyze = true
+			ep.state = ExplainStateAnalyze
+			return
+		}
+	case KSelect, KWith:
+		ep.startInner(NewSelectParser(), word)
+		return
+	case KUpdate:
+		ep.startInner(NewUpdateParser(), word)
+		return
+	case KDelete:
+		ep.startInner(NewDeleteParser(), word)
+		return
+	}
+	ep.err = errors.New("[parse error] EXPLAIN must be followed by SELECT, UPDATE or DELETE statement")
+}
+
+// startInner は対象の文のパーサーを生成し、最初のキーワードを転送する
+func (ep *ExplainParser) startInner(inner StatementParser, word string) {
+	ep.inner = inner
+	ep.state = ExplainStateStmt
+	ep.inner.onKeyword(word)
+}
+
+func (ep *ExplainParser) onIdentifier(ident string) {
+	if ep.err != nil {
+		return
+	}
+	if ep.state != ExplainStateStmt {
+		ep.err = errors.New("[parse error] EXPLAIN must be followed by SELECT, UPDATE or DELETE statement")
+		return
+	}
+	ep.inner.onIdentifier(ident)
+}
+
+func (ep *ExplainParser) onString(value string) {
+	if ep.err != nil {
+		return
+	}
+	if ep.state != ExplainStateStmt {
+		ep.err = errors.New("[parse error] EXPLAIN must be followed by SELECT, UPDATE or DELETE statement")
+		return
+	}
+	ep.inner.onString(value)
+}
+
+func (ep *ExplainParser) onNumber(num string) {
+	if ep.err != nil {
+		return
+	}
+	if ep.state != ExplainStateStmt {
+		ep.err = errors.New("[parse error] EXPLAIN must be followed by SELECT, UPDATE or DELETE statement")
+		return
+	}
+	ep.inner.onNumber(num)
+}
+
+func (ep *ExplainParser) onSymbol(symbol string) {
+	if ep.err != nil {
+		return
+	}
+	if ep.state != ExplainStateStmt {
+		ep.err = errors.New("[parse error] EXPLAIN must be followed by SELECT, UPDATE or DELETE statement")
+		return
+	}
+	ep.inner.onSymbol(symbol)
+}
+
+func (ep *ExplainParser) onComment(text string) {
+	if ep.inner != nil {
+		ep.inner.onComment(text)
+	}
+}
+
+func (ep *ExplainParser) onError(err error) {
+	if ep.err != nil {
+		return
+	}
+	ep.err = err
+}
@@ -0,0 +1,93 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/ren-yamanashi/minesql/internal/ast"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParserExplain(t *testing.T) {
+	t.Run("EXPLAIN SELECT 文をパースできる", func(t *testing.T) {
+		// GIVEN
+		sql := "EXPLAIN SELECT id FROM users WHERE id > 3 ORDER BY id LIMIT 2;"
+		parser := NewParser()
+
+		// WHEN
+		result, err := parser.Parse(sql)
+
+		// THEN
+		require.NoError(t, err)
+		explainStmt, ok := result.(*ast.ExplainStmt)
+		require.True(t, ok)
+		assert.False(t, explainStmt.Analyze)
+
+		selectStmt, ok := explainStmt.Stmt.(*ast.SelectStmt)
+		require.True(t, ok)
+		assert.Equal(t, "SELECT id FROM users WHERE id > 3 ORDER BY id LIMIT 2", ast.SelectString(selectStmt))
+	})
+
+	t.Run("EXPLAIN ANALYZE で UPDATE 文・DELETE 文をパースできる", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			sql      string
+			expected ast.Statement
+		}{
+			{
+				"UPDATE 文",
+				"explain analyze UPDATE users SET name = 'Bob';",
+				&ast.UpdateStmt{
+					Table:      *ast.NewTableId("users"),
+					SetClauses: []*ast.SetClause{{Column: *ast.NewColumnId("name"), Value: ast.NewStringLiteral("Bob")}},
+				},
+			},
+			{
+				"DELETE 文",
+				"EXPLAIN ANALYZE DELETE FROM users;",
+				&ast.DeleteStmt{From: *ast.NewTableId("users")},
+			},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				// GIVEN
+				parser := NewParser()
+
+				// WHEN
+				result, err := parser.Parse(tt.sql)
+
+				// THEN
+				require.NoError(t, err)
+				assert.Equal(t, &ast.ExplainStmt{Analyze: true, Stmt: tt.expected}, result)
+			})
+		}
+	})
+
+	t.Run("不正な EXPLAIN 文でエラーになる", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			sql      string
+			expected string
+		}{
+			{"対象の文がない場合", "EXPLAIN;", "EXPLAIN must be followed by SELECT, UPDATE or DELETE statement"},
+			{"ANALYZE の後に対象の文がない場合", "EXPLAIN ANALYZE;", "EXPLAIN must be followed by SELECT, UPDATE or DELETE statement"},
+			{"対象の文が INSERT 文の場合", "EXPLAIN INSERT INTO users (id) VALUES ('1');", "EXPLAIN must be followed by SELECT, UPDATE or DELETE statement"},
+			{"ANALYZE を重ねた場合", "EXPLAIN ANALYZE ANALYZE SELECT * FROM users;", "EXPLAIN must be followed by SELECT, UPDATE or DELETE statement"},
+			{"EXPLAIN を重ねた場合", "EXPLAIN EXPLAIN SELECT * FROM users;", "EXPLAIN must be followed by SELECT, UPDATE or DELETE statement"},
+			{"対象の文の中でエラーがある場合", "EXPLAIN SELECT * FROM;", "missing FROM clause"},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				// GIVEN
+				parser := NewParser()
+
+				// WHEN
+				result, err := parser.Parse(tt.sql)
+
+				// THEN
+				assert.Nil(t, result)
+				assert.ErrorContains(t, err, tt.expected)
+			})
+		}
+	})
+}
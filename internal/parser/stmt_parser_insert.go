@@ -8,12 +8,16 @@ import (
 )
 
 type InsertParser struct {
-	state      parserState     // 現在のステート
-	stmt       *ast.InsertStmt // 現在構築中の INSERT 文
-	cols       []ast.ColumnId  // カラムリスト
-	currentRow []ast.Literal   // 値リスト (現在構築中の行)
-	allRows    [][]ast.Literal // 全ての値リスト
-	err        error           // エラー情報
+	state         parserState     // 現在のステート
+	stmt          *ast.InsertStmt // 現在構築中の INSERT 文
+	cols          []ast.ColumnId  // カラムリスト
+	hasColList    bool            // カラムリストの "(" が指定されたかどうか
+	currentRow    []ast.Literal   // 値リスト (現在構築中の行)
+	allRows       [][]ast.Literal // 全ての値リスト
+	sel           *SelectParser   // INSERT ... SELECT の SELECT 文のパーサー
+	value         WhereParser     // ON DUPLICATE KEY UPDATE 句の値の式のパーサー
+	currentSetCol string          // 現在構築中の SetClause のカラム名
+	err           error           // エラー情報
 }
 
 func NewInsertParser() *InsertParser {
@@ -36,8 +40,8 @@ func (ip *InsertParser) finalize() {
 		return
 	}
 
-	// カラムリストが空の場合はエラー
-	if len(ip.cols) == 0 {
+	// カラムリストが空の場合はエラー (INSERT ... SELECT の場合はカラムリストを省略できる)
+	if len(ip.cols) == 0 && (ip.hasColList || ip.stmt.Select == nil) {
 		ip.setError(errors.New("[parse error] column list is required"))
 		return
 	}
@@ -46,7 +50,7 @@ func (ip *InsertParser) finalize() {
 	ip.flushCurrentRow()
 
 	// 値が未設定の場合はエラー
-	if len(ip.allRows) == 0 {
+	if len(ip.allRows) == 0 && ip.stmt.Select == nil {
 		ip.setError(errors.New("[parse error] at least one row of values is required"))
 		return
 	}
@@ -57,6 +61,12 @@ func (ip *InsertParser) finalize() {
 		return
 	}
 
+	// REPLACE には ON DUPLICATE KEY UPDATE 句を指定できない
+	if ip.stmt.Replace && len(ip.stmt.OnDuplicateKeyUpdate) > 0 {
+		ip.setError(errors.New("[parse error] ON DUPLICATE KEY UPDATE cannot be used with REPLACE"))
+		return
+	}
+
 	// stmt に設定
 	ip.stmt.Cols = ip.cols
 	ip.stmt.Values = ip.allRows
@@ -68,19 +78,44 @@ func (ip *InsertParser) onKeyword(word string) {
 	}
 
 	upper := strings.ToUpper(word)
+
+	// SELECT 文の解析中は SELECT 文のパーサーに転送する
+	// (JOIN の ON 以外の ON キーワードは、SELECT 文の後の ON DUPLICATE KEY UPDATE 句の開始)
+	if ip.state == InsertStateSelect {
+		if upper != KOn || ip.sel.sub != nil || ip.sel.state == SelectStateJoinTable {
+			ip.sel.onKeyword(word)
+			ip.setError(ip.sel.getError())
+			return
+		}
+		if !ip.finalizeSelect() {
+			return
+		}
+		ip.state = InsertStateOn
+		return
+	}
+
 	switch ip.state {
 	case InsertStateStart:
-		// 最初のキーワードは INSERT である必要がある
+		// 最初のキーワードは INSERT または REPLACE である必要がある
 		if upper == KInsert {
 			ip.state = InsertStateInsert
 			return
 		}
-		// 最初のキーワードが INSERT でない場合はエラー
+		if upper == KReplace {
+			ip.stmt.Replace = true
+			ip.state = InsertStateInsert
+			return
+		}
+		// 最初のキーワードが INSERT / REPLACE でない場合はエラー
 		ip.setError(errors.New("[parse error] expected INSERT, got: " + word))
 		return
 
 	case InsertStateInsert:
-		// INSERT の次のキーワードは INTO である必要がある
+		// INSERT の次のキーワードは IGNORE (INSERT IGNORE の場合) または INTO である必要がある
+		if upper == KIgnore && !ip.stmt.Ignore && !ip.stmt.Replace {
+			ip.stmt.Ignore = true
+			return
+		}
 		if upper == KInto {
 			ip.state = InsertStateInto
 			return
@@ -90,19 +125,90 @@ func (ip *InsertParser) onKeyword(word string) {
 		return
 
 	case InsertStateTbName:
+		// カラムリストを省略した INSERT ... SELECT
+		if upper == KSelect {
+			ip.beginSelect(word)
+			return
+		}
 		// カラムリスト開始前にキーワードが来た場合はエラー
 		ip.setError(errors.New("[parse error] column list is required"))
 		return
 
+	case InsertStateValueList:
+		// 値としての NULL
+		if upper == KNull {
+			ip.currentRow = append(ip.currentRow, ast.NewNullLiteral())
+			return
+		}
+		ip.setError(errors.New("[parse error] unexpected keyword: " + word))
+		return
+
 	case InsertStateColumns, InsertStateEndCols:
 		if upper == KValues {
 			ip.state = InsertStateValues
 			return
 		}
+		if upper == KSelect && ip.state == InsertStateEndCols {
+			ip.beginSelect(word)
+			return
+		}
 		// VALUES が来ない場合はエラー
 		ip.setError(errors.New("[parse error] unexpected keyword: " + word))
 		return
 
+	case InsertStateValues:
+		// 値リストの後の ON DUPLICATE KEY UPDATE 句の開始
+		if upper == KOn && len(ip.allRows) > 0 {
+			ip.state = InsertStateOn
+			return
+		}
+		ip.setError(errors.New("[parse error] unexpected keyword: " + word))
+		return
+
+	case InsertStateOn:
+		if upper == KDuplicate {
+			ip.state = InsertStateDuplicate
+			return
+		}
+		ip.setError(errors.New("[parse error] expected DUPLICATE, got: " + word))
+		return
+
+	case InsertStateDuplicate:
+		if upper == KKey {
+			ip.state = InsertStateDupKey
+			return
+		}
+		ip.setError(errors.New("[parse error] expected KEY, got: " + word))
+		return
+
+	case InsertStateDupKey:
+		if upper == KUpdate {
+			ip.state = InsertStateSet
+			return
+		}
+		ip.setError(errors.New("[parse error] expected UPDATE, got: " + word))
+		return
+
+	case InsertStateSetEq:
+		switch upper {
+		case KValues:
+			// VALUES(col) は追加しようとした行の値を参照する関数として扱う
+			if err := ip.value.pushColumn(upper); err != nil {
+				ip.setError(err)
+			}
+		case KAnd, KOr:
+			if err := ip.value.handleOperator(upper); err != nil {
+				ip.setError(err)
+			}
+		case KIs, KNot, KNull, KIn, KBetween, KLike, KCase, KWhen, KThen, KElse, KEnd:
+			if err := ip.value.handleKeyword(upper); err != nil {
+				ip.setError(err)
+			}
+		default:
+			ip.setError(errors.New("[parse error] unexpected keyword: " + word))
+		}
+		return
+
 	default:
 		ip.setError(errors.New("[parse error] unexpected keyword: " + word))
 		return
@@ -122,6 +228,20 @@ func (ip *InsertParser) onIdentifier(ident string) {
 	case InsertStateColumns:
 		ip.cols = append(ip.cols, *ast.NewColumnId(ident))
 		return
+	case InsertStateSelect:
+		ip.sel.onIdentifier(ident)
+		ip.setError(ip.sel.getError())
+		return
+	case InsertStateSet:
+		// ON DUPLICATE KEY UPDATE 句のカラム名
+		ip.currentSetCol = ident
+		ip.state = InsertStateSetCol
+		return
+	case InsertStateSetEq:
+		if err := ip.value.pushColumn(ident); err != nil {
+			ip.setError(err)
+		}
+		return
 	default:
 		ip.setError(errors.New("[parse error] unexpected identifier: " + ident))
 		return
@@ -133,8 +253,31 @@ func (ip *InsertParser) onSymbol(symbol string) {
 		return
 	}
 
+	if ip.state == InsertStateSelect {
+		// ";" が来たら SELECT 文を確定する
+		if symbol == string(SSemicolon) && ip.sel.sub == nil {
+			if ip.finalizeSelect() {
+				ip.state = InsertStateEnd
+			}
+			return
+		}
+		ip.sel.onSymbol(symbol)
+		ip.setError(ip.sel.getError())
+		return
+	}
+
 	// ";" が来たら state を End にする
 	if symbol == string(SSemicolon) {
+		switch ip.state {
+		case InsertStateOn, InsertStateDuplicate, InsertStateDupKey, InsertStateSet, InsertStateSetCol:
+			ip.setError(errors.New("[parse error] incomplete ON DUPLICATE KEY UPDATE clause"))
+			return
+		case InsertStateSetEq:
+			if err := ip.appendSetClause(); err != nil {
+				ip.setError(err)
+				return
+			}
+		}
 		ip.state = InsertStateEnd
 		return
 	}
@@ -143,6 +286,7 @@ func (ip *InsertParser) onSymbol(symbol string) {
 	case InsertStateTbName:
 		// テーブル名の後は必ずカラムリストの開始 "(" が来る
 		if symbol == string(SLeftParen) {
+			ip.hasColList = true
 			ip.state = InsertStateColumns
 			return
 		}
@@ -190,6 +334,37 @@ func (ip *InsertParser) onSymbol(symbol string) {
 		ip.setError(errors.New("[parse error] unexpected symbol in values: " + symbol))
 		return
 
+	case InsertStateSetCol:
+		// "=" のみ受け付ける
+		if symbol == string(SEqual) {
+			ip.value.initExpr()
+			ip.state = InsertStateSetEq
+			return
+		}
+		ip.setError(errors.New("[parse error] expected '=' after column name in ON DUPLICATE KEY UPDATE clause"))
+		return
+
+	case InsertStateSetEq:
+		if !ip.value.inNested() {
+			switch symbol {
+			case string(SComma):
+				// "," が来たら値を確定し、次の SetClause のカラム名待ち
+				if err := ip.appendSetClause(); err != nil {
+					ip.setError(err)
+					return
+				}
+				ip.state = InsertStateSet
+				return
+			case string(SRightParen):
+				ip.setError(errors.New("[parse error] unexpected symbol in ON DUPLICATE KEY UPDATE clause: " + symbol))
+				return
+			}
+		}
+		if err := ip.value.handleOperator(symbol); err != nil {
+			ip.setError(err)
+		}
+		return
+
 	default:
 		ip.setError(errors.New("[parse error] unexpected symbol: " + symbol))
 		return
@@ -200,22 +375,38 @@ func (ip *InsertParser) onString(value string) {
 	if ip.err != nil {
 		return
 	}
-	if ip.state == InsertStateValueList {
+	switch ip.state {
+	case InsertStateValueList:
 		ip.currentRow = append(ip.currentRow, ast.NewStringLiteral(value))
-		return
+	case InsertStateSelect:
+		ip.sel.onString(value)
+		ip.setError(ip.sel.getError())
+	case InsertStateSetEq:
+		if err := ip.value.pushLiteral(ast.NewStringLiteral(value)); err != nil {
+			ip.setError(err)
+		}
+	default:
+		ip.setError(errors.New("[parse error] unexpected string: " + value))
 	}
-	ip.setError(errors.New("[parse error] unexpected string: " + value))
 }
 
 func (ip *InsertParser) onNumber(num string) {
 	if ip.err != nil {
 		return
 	}
-	if ip.state == InsertStateValueList {
-		ip.currentRow = append(ip.currentRow, ast.NewStringLiteral(num))
-		return
+	switch ip.state {
+	case InsertStateValueList:
+		ip.currentRow = append(ip.currentRow, ast.NewNumberLiteral(num))
+	case InsertStateSelect:
+		ip.sel.onNumber(num)
+		ip.setError(ip.sel.getError())
+	case InsertStateSetEq:
+		if err := ip.value.pushLiteral(ast.NewNumberLiteral(num)); err != nil {
+			ip.setError(err)
+		}
+	default:
+		ip.setError(errors.New("[parse error] unexpected number: " + num))
 	}
-	ip.setError(errors.New("[parse error] unexpected number: " + num))
 }
 
 func (ip *InsertParser) onComment(text string) {}
@@ -228,6 +419,43 @@ func (ip *InsertParser) setError(err error) {
 	}
 }
 
+// beginSelect は INSERT ... SELECT の SELECT 文の解析を開始する (SELECT キーワードの時点で呼ぶ)
+func (ip *InsertParser) beginSelect(word string) {
+	ip.sel = NewSelectParser()
+	ip.sel.embedded = true
+	ip.sel.onKeyword(word)
+	ip.state = InsertStateSelect
+}
+
+// finalizeSelect は INSERT ... SELECT の SELECT 文を確定する (";" または ON DUPLICATE KEY UPDATE 句の ON の時点で呼ぶ)
+func (ip *InsertParser) finalizeSelect() bool {
+	ip.sel.onSymbol(string(SSemicolon))
+	ip.sel.finalize()
+	if err := ip.sel.getError(); err != nil {
+		ip.setError(err)
+		return false
+	}
+	ip.stmt.Select = ip.sel.stmt
+	return true
+}
+
+// appendSetClause は現在のカラムに対する ON DUPLICATE KEY UPDATE 句を、解析した値の式で確定して追加する ("," or ";" の時点で呼ぶ)
+func (ip *InsertParser) appendSetClause() error {
+	if ip.value.isEmpty() {
+		return errors.New("[parse error] missing ON DUPLICATE KEY UPDATE value for column " + ip.currentSetCol)
+	}
+	value, err := ip.value.finalizeExpr("ON DUPLICATE KEY UPDATE")
+	if err != nil {
+		return err
+	}
+	ip.stmt.OnDuplicateKeyUpdate = append(ip.stmt.OnDuplicateKeyUpdate, &ast.SetClause{
+		Column: *ast.NewColumnId(ip.currentSetCol),
+		Value:  value,
+	})
+	ip.currentSetCol = ""
+	return nil
+}
+
 // 現在の行を確定して allRows に追加する
 func (ip *InsertParser) flushCurrentRow() {
 	if len(ip.currentRow) > 0 {
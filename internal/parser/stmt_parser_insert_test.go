@@ -122,6 +122,24 @@ func TestParserInsert(t *testing.T) {
 		assert.Equal(t, "25", insertStmt.Values[0][1].ToString())
 	})
 
+	t.Run("NULL を含む INSERT 文をパースできる", func(t *testing.T) {
+		// GIVEN
+		sql := "INSERT INTO users (id, email) VALUES ('1', NULL);"
+		parser := NewParser()
+
+		// WHEN
+		result, err := parser.Parse(sql)
+
+		// THEN
+		assert.NoError(t, err)
+		insertStmt, ok := result.(*ast.InsertStmt)
+		assert.True(t, ok)
+		assert.Equal(t, 1, len(insertStmt.Values))
+		assert.Equal(t, "1", insertStmt.Values[0][0].ToString())
+		assert.IsType(t, &ast.NullLiteral{}, insertStmt.Values[0][1])
+		assert.Nil(t, insertStmt.Values[0][1].ToBytes())
+	})
+
 	t.Run("コメント付きの INSERT 文をパースできる", func(t *testing.T) {
 		t.Run("行コメント付き", func(t *testing.T) {
 			// GIVEN
@@ -291,3 +309,216 @@ VALUES ('1', 'John') /* 値リスト */
 		})
 	})
 }
+
+func TestParserInsertSelect(t *testing.T) {
+	t.Run("INSERT ... SELECT 文をパースできる", func(t *testing.T) {
+		// GIVEN
+		sql := "INSERT INTO archived_users (id, name) SELECT id, name FROM users WHERE id > 10;"
+		parser := NewParser()
+
+		// WHEN
+		result, err := parser.Parse(sql)
+
+		// THEN
+		assert.NoError(t, err)
+		insertStmt, ok := result.(*ast.InsertStmt)
+		assert.True(t, ok)
+		assert.Equal(t, "archived_users", insertStmt.Table.TableName)
+		assert.Equal(t, []ast.ColumnId{*ast.NewColumnId("id"), *ast.NewColumnId("name")}, insertStmt.Cols)
+		assert.Nil(t, insertStmt.Values)
+		assert.NotNil(t, insertStmt.Select)
+		assert.Equal(t, "SELECT id, name FROM users WHERE id > 10", ast.SelectString(insertStmt.Select))
+	})
+
+	t.Run("カラムリストを省略した INSERT ... SELECT 文をパースできる", func(t *testing.T) {
+		// GIVEN
+		sql := "INSERT INTO archived_users SELECT * FROM users;"
+		parser := NewParser()
+
+		// WHEN
+		result, err := parser.Parse(sql)
+
+		// THEN
+		assert.NoError(t, err)
+		insertStmt, ok := result.(*ast.InsertStmt)
+		assert.True(t, ok)
+		assert.Nil(t, insertStmt.Cols)
+		assert.NotNil(t, insertStmt.Select)
+		assert.Equal(t, "users", insertStmt.Select.From.TableName)
+	})
+
+	t.Run("JOIN を含む INSERT ... SELECT 文に ON DUPLICATE KEY UPDATE 句を指定できる", func(t *testing.T) {
+		// GIVEN: JOIN の ON と ON DUPLICATE KEY UPDATE の ON を区別する
+		sql := "INSERT INTO totals (user_id, total) SELECT u.id, o.amount FROM users u JOIN orders o ON u.id = o.user_id ON DUPLICATE KEY UPDATE total = total + VALUES(total);"
+		parser := NewParser()
+
+		// WHEN
+		result, err := parser.Parse(sql)
+
+		// THEN
+		assert.NoError(t, err)
+		insertStmt, ok := result.(*ast.InsertStmt)
+		assert.True(t, ok)
+		assert.NotNil(t, insertStmt.Select)
+		assert.Equal(t, 1, len(insertStmt.Select.Joins))
+		assert.Equal(t, 1, len(insertStmt.OnDuplicateKeyUpdate))
+		assert.Equal(t, "total", insertStmt.OnDuplicateKeyUpdate[0].Column.ColName)
+		assert.Equal(t, "total + VALUES(total)", ast.ExprString(insertStmt.OnDuplicateKeyUpdate[0].Value))
+	})
+
+	t.Run("不正な INSERT ... SELECT 文でエラーになる", func(t *testing.T) {
+		t.Run("カラムリストが空の場合", func(t *testing.T) {
+			// GIVEN
+			sql := "INSERT INTO archived_users () SELECT * FROM users;"
+			parser := NewParser()
+
+			// WHEN
+			result, err := parser.Parse(sql)
+
+			// THEN
+			assert.Error(t, err)
+			assert.Nil(t, result)
+			assert.Contains(t, err.Error(), "column list is required")
+		})
+
+		t.Run("SELECT 文が不完全な場合", func(t *testing.T) {
+			// GIVEN
+			sql := "INSERT INTO archived_users (id) SELECT id;"
+			parser := NewParser()
+
+			// WHEN
+			result, err := parser.Parse(sql)
+
+			// THEN
+			assert.Error(t, err)
+			assert.Nil(t, result)
+			assert.Contains(t, err.Error(), "missing FROM clause")
+		})
+	})
+}
+
+func TestParserInsertDuplicateKey(t *testing.T) {
+	t.Run("INSERT IGNORE 文をパースできる", func(t *testing.T) {
+		// GIVEN
+		sql := "INSERT IGNORE INTO users (id, name) VALUES ('1', 'John');"
+		parser := NewParser()
+
+		// WHEN
+		result, err := parser.Parse(sql)
+
+		// THEN
+		assert.NoError(t, err)
+		insertStmt, ok := result.(*ast.InsertStmt)
+		assert.True(t, ok)
+		assert.True(t, insertStmt.Ignore)
+		assert.False(t, insertStmt.Replace)
+		assert.Equal(t, 1, len(insertStmt.Values))
+	})
+
+	t.Run("REPLACE 文をパースできる", func(t *testing.T) {
+		// GIVEN
+		sql := "REPLACE INTO users (id, name) VALUES ('1', 'John'), ('2', 'Jane');"
+		parser := NewParser()
+
+		// WHEN
+		result, err := parser.Parse(sql)
+
+		// THEN
+		assert.NoError(t, err)
+		insertStmt, ok := result.(*ast.InsertStmt)
+		assert.True(t, ok)
+		assert.True(t, insertStmt.Replace)
+		assert.False(t, insertStmt.Ignore)
+		assert.Equal(t, "users", insertStmt.Table.TableName)
+		assert.Equal(t, 2, len(insertStmt.Values))
+	})
+
+	t.Run("REPLACE ... SELECT 文をパースできる", func(t *testing.T) {
+		// GIVEN
+		sql := "REPLACE INTO users SELECT * FROM new_users;"
+		parser := NewParser()
+
+		// WHEN
+		result, err := parser.Parse(sql)
+
+		// THEN
+		assert.NoError(t, err)
+		insertStmt, ok := result.(*ast.InsertStmt)
+		assert.True(t, ok)
+		assert.True(t, insertStmt.Replace)
+		assert.Equal(t, "new_users", insertStmt.Select.From.TableName)
+	})
+
+	t.Run("ON DUPLICATE KEY UPDATE 句をパースできる", func(t *testing.T) {
+		// GIVEN
+		sql := "INSERT INTO counters (id, n) VALUES ('1', 1) ON DUPLICATE KEY UPDATE n = n + 1, name = VALUES(name);"
+		parser := NewParser()
+
+		// WHEN
+		result, err := parser.Parse(sql)
+
+		// THEN
+		assert.NoError(t, err)
+		insertStmt, ok := result.(*ast.InsertStmt)
+		assert.True(t, ok)
+		assert.Equal(t, 1, len(insertStmt.Values))
+		assert.Equal(t, 2, len(insertStmt.OnDuplicateKeyUpdate))
+		assert.Equal(t, "n", insertStmt.OnDuplicateKeyUpdate[0].Column.ColName)
+		assert.Equal(t, "n + 1", ast.ExprString(insertStmt.OnDuplicateKeyUpdate[0].Value))
+		assert.Equal(t, "name", insertStmt.OnDuplicateKeyUpdate[1].Column.ColName)
+		assert.Equal(t, "VALUES(name)", ast.ExprString(insertStmt.OnDuplicateKeyUpdate[1].Value))
+	})
+
+	t.Run("不正な文でエラーになる", func(t *testing.T) {
+		tests := []struct {
+			name string
+			sql  string
+			want string
+		}{
+			{
+				name: "REPLACE に ON DUPLICATE KEY UPDATE 句を指定した場合",
+				sql:  "REPLACE INTO users (id) VALUES ('1') ON DUPLICATE KEY UPDATE id = 2;",
+				want: "ON DUPLICATE KEY UPDATE cannot be used with REPLACE",
+			},
+			{
+				name: "REPLACE に IGNORE を指定した場合",
+				sql:  "REPLACE IGNORE INTO users (id) VALUES ('1');",
+				want: "expected INTO",
+			},
+			{
+				name: "DUPLICATE キーワードがない場合",
+				sql:  "INSERT INTO users (id) VALUES ('1') ON KEY UPDATE id = 2;",
+				want: "expected DUPLICATE",
+			},
+			{
+				name: "UPDATE の後にカラムがない場合",
+				sql:  "INSERT INTO users (id) VALUES ('1') ON DUPLICATE KEY UPDATE;",
+				want: "incomplete ON DUPLICATE KEY UPDATE clause",
+			},
+			{
+				name: "値の式がない場合",
+				sql:  "INSERT INTO users (id) VALUES ('1') ON DUPLICATE KEY UPDATE id = ;",
+				want: "missing ON DUPLICATE KEY UPDATE value for column id",
+			},
+			{
+				name: "末尾にセミコロンがない場合",
+				sql:  "INSERT INTO users (id) VALUES ('1') ON DUPLICATE KEY UPDATE id = 2",
+				want: "incomplete INSERT statement",
+			},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				// GIVEN
+				parser := NewParser()
+
+				// WHEN
+				result, err := parser.Parse(tt.sql)
+
+				// THEN
+				assert.Error(t, err)
+				assert.Nil(t, result)
+				assert.Contains(t, err.Error(), tt.want)
+			})
+		}
+	})
+}
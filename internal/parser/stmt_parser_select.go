@@ -2,6 +2,7 @@ package parser
 
 import (
 	"errors"
+	"strconv"
 	"strings"
 
 	"github.com/ren-yamanashi/minesql/internal/ast"
@@ -10,15 +11,36 @@ import (
 var (
 	ErrSelectStmtIsNil  error = errors.New("[internal error] SelectStmt is nil")
 	ErrWhereClauseIsNil error = errors.New("[internal error] WhereClause is nil")
+
+	errDerivedTableAlias = errors.New("[parse error] every derived table must have its own alias")
+	errMissingSetOperand = errors.New("[parse error] missing SELECT statement after set operator")
+	errMissingWithQuery  = errors.New("[parse error] missing SELECT statement after WITH clause")
 )
 
 type SelectParser struct {
-	state       parserState     // 現在のステート
-	stmt        *ast.SelectStmt // 現在構築中の SELECT 文
-	where       WhereParser     // WHERE 句パーサー
-	on          WhereParser     // ON 句パーサー (JOIN 条件)
-	currentJoin *ast.JoinClause // 現在パース中の JOIN 句
-	err         error           // エラー情報
+	state       parserState           // 現在のステート
+	with        *ast.WithClause       // WITH 句 (nil なら WITH 句なし)
+	cte         *CTEParser            // パース中の共通テーブル式 (nil なら共通テーブル式の外)
+	stmt        *ast.SelectStmt       // 現在構築中の SELECT 文
+	item        WhereParser           // SELECT リストの項目 (カラム・集約関数・式) のパーサー
+	where       WhereParser           // WHERE 句パーサー
+	on          WhereParser           // ON 句パーサー (JOIN 条件)
+	having      WhereParser           // HAVING 句パーサー
+	currentJoin *ast.JoinClause       // 現在パース中の JOIN 句
+	joinType    ast.JoinType          // JOIN の前置キーワード (INNER / LEFT / RIGHT) で指定された JOIN の種類
+	sub         *subqueryParser       // パース中のサブクエリ (nil ならサブクエリの外)
+	embedded    bool                  // サブクエリ・INSERT ... SELECT の SELECT 文の場合は true (集合演算を指定できない)
+	noFrom      bool                  // 現在構築中の SELECT 文が FROM 句を持たずに終わった場合は true (集合演算のオペランドでのみ許可する)
+	operands    []*ast.SelectStmt     // 集合演算の確定したオペランド (現在構築中の SELECT 文より前の SELECT 文)
+	operators   []setOperator         // 集合演算子 (operands[i] と次のオペランドの間の演算子)
+	result      *ast.SetOperationStmt // 確定した集合演算 (集合演算がない場合は nil)
+	err         error                 // エラー情報
+}
+
+// setOperator はオペランドの間の集合演算子
+type setOperator struct {
+	op  ast.SetOperator
+	all bool
 }
 
 func NewSelectParser() *SelectParser {
@@ -27,21 +49,44 @@ func NewSelectParser() *SelectParser {
 	}
 }
 
-func (sp *SelectParser) getResult() ast.Statement { return sp.stmt }
-func (sp *SelectParser) getError() error          { return sp.err }
+func (sp *SelectParser) getResult() ast.Statement {
+	if sp.result != nil {
+		return sp.result
+	}
+	return sp.stmt
+}
+func (sp *SelectParser) getError() error { return sp.err }
 func (sp *SelectParser) finalize() {
 	if sp.err != nil {
 		return
 	}
 
+	// 閉じられていないサブクエリがある場合はエラー
+	if sp.sub != nil {
+		sp.setError(errors.New("[parse error] missing ')' after subquery"))
+		return
+	}
+
+	// WITH 句の後に SELECT 文がない場合はエラー
+	if sp.with != nil && sp.stmt == nil {
+		sp.setError(errMissingWithQuery)
+		return
+	}
+
 	// SELECT 文がない場合はエラー
 	if sp.stmt == nil {
 		sp.setError(errors.New("[parse error] must have SELECT statement"))
 		return
 	}
 
-	// テーブル名が空の場合はエラー (FROM 句がない場合を含む)
-	if sp.stmt.From.TableName == "" {
+	// 集合演算子の後に SELECT 文がない場合はエラー
+	if sp.state == SelectStateSetOp || sp.state == SelectStateSetOpAll {
+		sp.setError(errMissingSetOperand)
+		return
+	}
+
+	// テーブル名が空の場合はエラー (FROM 句がない場合を含む。ただし集合演算のオペランドは FROM 句を省略できる)
+	if sp.stmt.From.TableName == "" && (!sp.noFrom || len(sp.operands) == 0) {
 		sp.setError(errors.New("[parse error] missing FROM clause"))
 		return
 	}
@@ -52,35 +97,146 @@ func (sp *SelectParser) finalize() {
 		return
 	}
 
-	// 未確定の JOIN 句があれば確定する
-	if err := sp.finalizeCurrentJoin(); err != nil {
+	if err := sp.finalizeClauses(); err != nil {
 		sp.setError(err)
 		return
 	}
 
-	// WHERE 句を確定
-	whereClause, err := sp.where.finalizeWhere()
-	if err != nil {
-		sp.setError(err)
+	if len(sp.operands) > 0 {
+		sp.result = sp.buildSetOperation()
+		sp.result.With = sp.with
 		return
 	}
-	sp.stmt.Where = whereClause
+	sp.stmt.With = sp.with
 }
 
 func (sp *SelectParser) onKeyword(word string) {
 	if sp.err != nil {
 		return
 	}
+	if sp.sub != nil {
+		if err := sp.sub.onKeyword(word); err != nil {
+			sp.setError(err)
+		}
+		return
+	}
 	upperWord := strings.ToUpper(word)
 
+	// WITH 句の共通テーブル式のトークンは共通テーブル式のパーサーに転送する
+	if sp.cte != nil {
+		// RECURSIVE は WITH の直後にのみ来る (WITH RECURSIVE)
+		if upperWord == KRecursive && len(sp.with.CTEs) == 0 && sp.cte.state == CTEStateName && !sp.with.Recursive {
+			sp.with.Recursive = true
+			return
+		}
+		if err := sp.cte.onKeyword(word); err != nil {
+			sp.setError(err)
+		}
+		return
+	}
+	if sp.state == SelectStateWithEnd && upperWord != KSelect {
+		sp.setError(errMissingWithQuery)
+		return
+	}
+
+	// AS の後には別名が必要
+	if sp.state == SelectStateColumnAs {
+		sp.setError(errors.New("[parse error] missing alias after AS"))
+		return
+	}
+
+	// 導出テーブルのサブクエリの後には別名が必要
+	if sp.state == SelectStateDerivedEnd && upperWord != KAs {
+		sp.setError(errDerivedTableAlias)
+		return
+	}
+
+	// OVER 句の中のキーワードはウィンドウ関数のパーサーに転送する
+	if sp.state == SelectStateColumns && sp.item.inWindow() {
+		if err := sp.item.handleKeyword(upperWord); err != nil {
+			sp.setError(err)
+		}
+		return
+	}
+
 	switch upperWord {
+	case KWith:
+		// WITH 句は文の先頭にのみ来る (サブクエリ・INSERT ... SELECT では指定できない)
+		if sp.stmt != nil || sp.with != nil || sp.embedded {
+			sp.setError(errors.New("[parse error] WITH keyword is in invalid position"))
+			return
+		}
+		sp.with = &ast.WithClause{}
+		sp.cte = NewCTEParser()
+		sp.state = SelectStateWith
+		return
+
 	case KSelect:
+		// 集合演算子の後の SELECT は次のオペランドの開始
+		if sp.stmt != nil && sp.state != SelectStateSetOp && sp.state != SelectStateSetOpAll {
+			sp.beginSubquery()
+			return
+		}
 		sp.stmt = &ast.SelectStmt{}
+		sp.item.initExpr()
+		sp.noFrom = false
 		sp.state = SelectStateColumns
 		return
 
+	case KDistinct:
+		// DISTINCT は SELECT キーワードの直後 (SELECT DISTINCT) または集合演算子の直後 (UNION DISTINCT) に来る
+		if sp.state == SelectStateColumns && sp.item.isEmpty() && !sp.stmt.Distinct && sp.stmt.IsSelectAll() {
+			sp.stmt.Distinct = true
+			return
+		}
+		if sp.state == SelectStateSetOp {
+			sp.state = SelectStateSetOpAll
+			return
+		}
+		sp.setError(errors.New("[parse error] DISTINCT keyword is in invalid position"))
+		return
+
+	case KUnion, KIntersect, KExcept:
+		sp.beginSetOperation(upperWord)
+		return
+
+	case KAll:
+		// ALL は集合演算子の直後にのみ来る (UNION ALL, INTERSECT ALL, EXCEPT ALL)
+		if sp.state == SelectStateSetOp {
+			sp.operators[len(sp.operators)-1].all = true
+			sp.state = SelectStateSetOpAll
+			return
+		}
+		sp.setError(errors.New("[parse error] ALL keyword is in invalid position"))
+		return
+
+	case KAs:
+		switch {
+		case sp.state == SelectStateColumns && !sp.item.inNested() && !sp.item.isEmpty():
+			// SELECT リストの項目の別名 (expr AS alias)
+			if err := sp.finalizeSelectItem(); err != nil {
+				sp.setError(err)
+				return
+			}
+			sp.state = SelectStateColumnAs
+		case sp.state == SelectStateDerivedEnd:
+			sp.state = SelectStateDerivedAs
+		default:
+			sp.setError(errors.New("[parse error] AS keyword is in invalid position"))
+		}
+		return
+
 	case KFrom:
-		if sp.state == SelectStateColumns {
+		// 関数呼び出しなどの括弧の中に FROM が来た場合は不正な位置として扱う
+		if sp.state == SelectStateColumns && !sp.item.inNested() {
+			if err := sp.finalizeSelectItem(); err != nil {
+				sp.setError(err)
+				return
+			}
+			sp.state = SelectStateFrom
+			return
+		}
+		if sp.state == SelectStateColumnAlias {
 			sp.state = SelectStateFrom
 			return
 		}
@@ -88,24 +244,43 @@ func (sp *SelectParser) onKeyword(word string) {
 		sp.setError(errors.New("[parse error] FROM clause is in invalid position"))
 		return
 
-	case KInner:
-		// INNER は JOIN の前置キーワード。FROM 後または ON 条件後に来る
+	case KInner, KLeft, KRight:
+		// INNER / LEFT / RIGHT は JOIN の前置キーワード。FROM 後または ON 条件後に来る
 		if sp.state == SelectStateFrom || sp.state == SelectStateOn {
 			if err := sp.finalizeCurrentJoin(); err != nil {
 				sp.setError(err)
 				return
 			}
-			sp.state = SelectStateInner
+			switch upperWord {
+			case KLeft:
+				sp.joinType = ast.JoinTypeLeft
+				sp.state = SelectStateOuter
+			case KRight:
+				sp.joinType = ast.JoinTypeRight
+				sp.state = SelectStateOuter
+			default:
+				sp.joinType = ast.JoinTypeInner
+				sp.state = SelectStateInner
+			}
+			return
+		}
+		sp.setError(errors.New("[parse error] " + upperWord + " keyword is in invalid position"))
+		return
+
+	case KOuter:
+		// OUTER は LEFT / RIGHT の後にのみ来る (LEFT OUTER JOIN, RIGHT OUTER JOIN)
+		if sp.state == SelectStateOuter {
+			sp.state = SelectStateOuterKw
 			return
 		}
-		sp.setError(errors.New("[parse error] INNER keyword is in invalid position"))
+		sp.setError(errors.New("[parse error] OUTER keyword is in invalid position"))
 		return
 
 	case KJoin:
-		// JOIN は FROM 後、ON 条件後、または INNER 後に来る
-		if sp.state == SelectStateInner {
-			// INNER JOIN の場合: INNER で既に SelectStateInner に遷移済み
-			sp.currentJoin = &ast.JoinClause{}
+		// JOIN は FROM 後、ON 条件後、または INNER / LEFT / RIGHT [OUTER] 後に来る
+		if sp.state == SelectStateInner || sp.state == SelectStateOuter || sp.state == SelectStateOuterKw {
+			// 前置キーワードで既に JOIN の種類を確定済み
+			sp.currentJoin = &ast.JoinClause{Type: sp.joinType}
 			sp.state = SelectStateJoin
 			return
 		}
@@ -140,20 +315,137 @@ func (sp *SelectParser) onKeyword(word string) {
 		return
 
 	case KAnd, KOr:
-		if sp.state == SelectStateWhere {
-			if err := sp.where.handleOperator(upperWord); err != nil {
+		if wp := sp.exprParser(); wp != nil {
+			if err := wp.handleOperator(upperWord); err != nil {
 				sp.setError(err)
 			}
 			return
 		}
-		if sp.state == SelectStateOn {
-			if err := sp.on.handleOperator(upperWord); err != nil {
+		// AND/OR が予期しない位置に来た場合はエラー
+		sp.setError(errors.New("[parse error] " + upperWord + " operator is in invalid position"))
+		return
+
+	case KIs, KNot, KNull, KIn, KBetween, KLike, KExists, KCase, KWhen, KThen, KElse, KEnd:
+		if wp := sp.exprParser(); wp != nil {
+			if err := wp.handleKeyword(upperWord); err != nil {
 				sp.setError(err)
 			}
 			return
 		}
-		// AND/OR が予期しない位置に来た場合はエラー
-		sp.setError(errors.New("[parse error] " + upperWord + " operator is in invalid position"))
+		sp.setError(errors.New("[parse error] " + upperWord + " is in invalid position"))
+		return
+
+	case KOver:
+		// ウィンドウ関数は SELECT リストにのみ指定できる
+		if sp.state != SelectStateColumns {
+			sp.setError(errors.New("[parse error] window function is allowed only in SELECT list"))
+			return
+		}
+		if err := sp.item.handleKeyword(upperWord); err != nil {
+			sp.setError(err)
+		}
+		return
+
+	case KGroup:
+		if sp.state == SelectStateFrom || sp.state == SelectStateOn || sp.state == SelectStateWhere {
+			if err := sp.finalizeCurrentJoin(); err != nil {
+				sp.setError(err)
+				return
+			}
+			sp.state = SelectStateGroup
+			return
+		}
+		sp.setError(errors.New("[parse error] GROUP BY clause is in invalid position"))
+		return
+
+	case KHaving:
+		switch sp.state {
+		case SelectStateFrom, SelectStateOn, SelectStateWhere, SelectStateGroupByCol:
+			if err := sp.finalizeCurrentJoin(); err != nil {
+				sp.setError(err)
+				return
+			}
+			sp.having.initWhere()
+			sp.state = SelectStateHaving
+			return
+		}
+		sp.setError(errors.New("[parse error] HAVING clause is in invalid position"))
+		return
+
+	case KOrder:
+		// FROM 句のない最後のオペランドの後には、集合演算の結果に対する ORDER BY を指定できる
+		if sp.atSelectListEnd() && len(sp.operands) > 0 {
+			if err := sp.endSelectList(); err != nil {
+				sp.setError(err)
+				return
+			}
+			sp.state = SelectStateOrder
+			return
+		}
+		switch sp.state {
+		case SelectStateFrom, SelectStateOn, SelectStateWhere, SelectStateGroupByCol, SelectStateHaving:
+			if err := sp.finalizeCurrentJoin(); err != nil {
+				sp.setError(err)
+				return
+			}
+			sp.state = SelectStateOrder
+			return
+		}
+		sp.setError(errors.New("[parse error] ORDER BY clause is in invalid position"))
+		return
+
+	case KBy:
+		if sp.state == SelectStateGroup {
+			sp.state = SelectStateGroupBy
+			return
+		}
+		if sp.state == SelectStateOrder {
+			sp.state = SelectStateOrderBy
+			return
+		}
+		sp.setError(errors.New("[parse error] BY keyword is in invalid position"))
+		return
+
+	case KAsc, KDesc:
+		// ASC / DESC は ORDER BY のカラム名の直後にのみ指定できる
+		if sp.state == SelectStateOrderByCol {
+			sp.stmt.OrderBy[len(sp.stmt.OrderBy)-1].Desc = upperWord == KDesc
+			sp.state = SelectStateOrderByDir
+			return
+		}
+		sp.setError(errors.New("[parse error] " + upperWord + " keyword is in invalid position"))
+		return
+
+	case KLimit:
+		// FROM 句のない最後のオペランドの後には、集合演算の結果に対する LIMIT を指定できる
+		if sp.atSelectListEnd() && len(sp.operands) > 0 {
+			if err := sp.endSelectList(); err != nil {
+				sp.setError(err)
+				return
+			}
+			sp.stmt.Limit = &ast.LimitClause{}
+			sp.state = SelectStateLimit
+			return
+		}
+		switch sp.state {
+		case SelectStateFrom, SelectStateOn, SelectStateWhere, SelectStateGroupByCol, SelectStateHaving, SelectStateOrderByCol, SelectStateOrderByDir:
+			if err := sp.finalizeCurrentJoin(); err != nil {
+				sp.setError(err)
+				return
+			}
+			sp.stmt.Limit = &ast.LimitClause{}
+			sp.state = SelectStateLimit
+			return
+		}
+		sp.setError(errors.New("[parse error] LIMIT clause is in invalid position"))
+		return
+
+	case KOffset:
+		if sp.state == SelectStateLimitCount {
+			sp.state = SelectStateOffset
+			return
+		}
+		sp.setError(errors.New("[parse error] OFFSET keyword is in invalid position"))
 		return
 
 	default:
@@ -166,22 +458,69 @@ func (sp *SelectParser) onIdentifier(ident string) {
 	if sp.err != nil {
 		return
 	}
+	if sp.sub != nil {
+		if err := sp.sub.onIdentifier(ident); err != nil {
+			sp.setError(err)
+		}
+		return
+	}
+	if sp.cte != nil {
+		if err := sp.cte.onIdentifier(ident); err != nil {
+			sp.setError(err)
+		}
+		return
+	}
 
-	switch sp.state {
-	case SelectStateColumns:
-		// カラム名を追加 (修飾名 "table.column" にも対応)
-		colId := parseColumnId(ident)
-		sp.stmt.Columns = append(sp.stmt.Columns, colId)
+	// SELECT リスト・ON・WHERE・HAVING 句の式 (修飾名 "table.column" にも対応)
+	if wp := sp.exprParser(); wp != nil {
+		if err := wp.pushColumn(ident); err != nil {
+			sp.setError(err)
+		}
 		return
+	}
+
+	switch sp.state {
+	case SelectStateWithEnd:
+		sp.setError(errMissingWithQuery)
+	case SelectStateColumnAs:
+		sp.setSelectAlias(ident)
+		sp.state = SelectStateColumnAlias
+	case SelectStateColumnAlias:
+		sp.setError(errors.New("[parse error] unexpected identifier in SELECT list: " + ident))
 	case SelectStateFrom:
+		if sp.stmt.From.Subquery != nil {
+			sp.setError(errors.New("[parse error] unexpected identifier in FROM clause: " + ident))
+			return
+		}
 		sp.stmt.From = *ast.NewTableId(ident)
+	case SelectStateDerived:
+		sp.setError(errors.New("[parse error] derived table must be a SELECT statement"))
+	case SelectStateDerivedEnd, SelectStateDerivedAs:
+		// 導出テーブルの別名
+		if sp.currentJoin != nil {
+			sp.currentJoin.Table.TableName = ident
+			sp.state = SelectStateJoinTable
+			return
+		}
+		sp.stmt.From.TableName = ident
+		sp.state = SelectStateFrom
 	case SelectStateJoin:
 		sp.currentJoin.Table = *ast.NewTableId(ident)
 		sp.state = SelectStateJoinTable
-	case SelectStateOn:
-		sp.on.pushColumn(ident)
-	case SelectStateWhere:
-		sp.where.pushColumn(ident)
+	case SelectStateGroupBy:
+		sp.stmt.GroupBy = append(sp.stmt.GroupBy, parseColumnId(ident))
+		sp.state = SelectStateGroupByCol
+	case SelectStateGroup, SelectStateGroupByCol:
+		sp.setError(errors.New("[parse error] unexpected identifier in GROUP BY clause: " + ident))
+	case SelectStateOrderBy:
+		sp.stmt.OrderBy = append(sp.stmt.OrderBy, ast.OrderByItem{Column: parseColumnId(ident)})
+		sp.state = SelectStateOrderByCol
+	case SelectStateOrder, SelectStateOrderByCol, SelectStateOrderByDir:
+		sp.setError(errors.New("[parse error] unexpected identifier in ORDER BY clause: " + ident))
+	case SelectStateLimit, SelectStateLimitCount, SelectStateOffset, SelectStateOffsetEnd:
+		sp.setError(errors.New("[parse error] unexpected identifier in LIMIT clause: " + ident))
+	case SelectStateSetOp, SelectStateSetOpAll:
+		sp.setError(errors.New("[parse error] expected SELECT after set operator: " + ident))
 	}
 }
 
@@ -189,29 +528,123 @@ func (sp *SelectParser) onSymbol(symbol string) {
 	if sp.err != nil {
 		return
 	}
+	if sp.sub != nil {
+		stmt, err := sp.sub.onSymbol(symbol)
+		if err != nil {
+			sp.setError(err)
+			return
+		}
+		if stmt != nil {
+			sp.sub = nil
+			sp.endSubquery(stmt)
+		}
+		return
+	}
+	if sp.cte != nil {
+		if err := sp.cte.onSymbol(symbol); err != nil {
+			sp.setError(err)
+			return
+		}
+		if sp.cte.done() {
+			sp.with.CTEs = append(sp.with.CTEs, sp.cte.result())
+			sp.cte = nil
+			sp.state = SelectStateWithEnd
+		}
+		return
+	}
+
+	// 共通テーブル式の後には "," (次の共通テーブル式) か SELECT 文が来る
+	if sp.state == SelectStateWithEnd {
+		if symbol != string(SComma) {
+			sp.setError(errMissingWithQuery)
+			return
+		}
+		sp.cte = NewCTEParser()
+		sp.state = SelectStateWith
+		return
+	}
 
 	// ";" が来たら state を End にする
 	if symbol == string(SSemicolon) {
+		// ORDER BY のカラム名が指定されていない場合はエラー
+		if sp.state == SelectStateOrder || sp.state == SelectStateOrderBy {
+			sp.setError(errors.New("[parse error] incomplete ORDER BY clause"))
+			return
+		}
+		// GROUP BY のカラム名が指定されていない場合はエラー
+		if sp.state == SelectStateGroup || sp.state == SelectStateGroupBy {
+			sp.setError(errors.New("[parse error] incomplete GROUP BY clause"))
+			return
+		}
+		// LIMIT / OFFSET の件数が指定されていない場合はエラー
+		if sp.state == SelectStateLimit || sp.state == SelectStateOffset {
+			sp.setError(errors.New("[parse error] incomplete LIMIT clause"))
+			return
+		}
+		// 導出テーブルの別名が指定されていない場合はエラー
+		if sp.state == SelectStateDerivedEnd || sp.state == SelectStateDerivedAs {
+			sp.setError(errDerivedTableAlias)
+			return
+		}
+		// 集合演算子の後に SELECT 文がない場合はエラー
+		if sp.state == SelectStateSetOp || sp.state == SelectStateSetOpAll {
+			sp.setError(errMissingSetOperand)
+			return
+		}
+		// FROM 句のない SELECT 文は SELECT リストを確定する
+		if sp.atSelectListEnd() {
+			if err := sp.endSelectList(); err != nil {
+				sp.setError(err)
+				return
+			}
+		}
 		sp.state = SelectStateEnd
 		return
 	}
 
 	switch sp.state {
 	case SelectStateColumns:
-		if symbol == string(CAsterisk) {
-			// SELECT * → Columns は nil のまま (全カラム)
+		if symbol == string(SAsterisk) && sp.item.isEmpty() {
+			// SELECT * → Columns・Aggregates・Exprs は nil のまま (全カラム)
 			return
 		}
+		if symbol == string(SComma) && !sp.item.inNested() {
+			// SELECT リストの区切り → 項目を確定して次の項目を待つ
+			if err := sp.finalizeSelectItem(); err != nil {
+				sp.setError(err)
+			}
+			return
+		}
+		if err := sp.item.handleOperator(symbol); err != nil {
+			sp.setError(err)
+		}
+		return
+	case SelectStateColumnAlias:
 		if symbol == string(SComma) {
-			// カラム名の区切り → 次のカラム名を待つ
+			// SELECT リストの区切り → 次の項目を待つ
+			sp.state = SelectStateColumns
 			return
 		}
-		sp.setError(errors.New("[parse error] unexpected symbol in SELECT clause: " + symbol))
+		sp.setError(errors.New("[parse error] unexpected symbol in SELECT list: " + symbol))
 		return
-	case SelectStateFrom:
-		// FROM 句ではシンボルは来ないはずなのでエラー
+	case SelectStateColumnAs:
+		sp.setError(errors.New("[parse error] missing alias after AS"))
+		return
+	case SelectStateFrom, SelectStateJoin:
+		// テーブル名の位置の "(" は導出テーブルのサブクエリの開始
+		if symbol == string(SLeftParen) && (sp.state == SelectStateJoin || sp.stmt.From.TableName == "") {
+			sp.state = SelectStateDerived
+			return
+		}
+		// FROM 句ではそれ以外のシンボルは来ないはずなのでエラー
 		sp.setError(errors.New("[parse error] unexpected symbol in FROM clause: " + symbol))
 		return
+	case SelectStateDerived:
+		sp.setError(errors.New("[parse error] derived table must be a SELECT statement"))
+		return
+	case SelectStateDerivedEnd, SelectStateDerivedAs:
+		sp.setError(errDerivedTableAlias)
+		return
 	case SelectStateOn:
 		if err := sp.on.handleOperator(symbol); err != nil {
 			sp.setError(err)
@@ -220,6 +653,32 @@ func (sp *SelectParser) onSymbol(symbol string) {
 		if err := sp.where.handleOperator(symbol); err != nil {
 			sp.setError(err)
 		}
+	case SelectStateHaving:
+		if err := sp.having.handleOperator(symbol); err != nil {
+			sp.setError(err)
+		}
+	case SelectStateGroupByCol:
+		if symbol == string(SComma) {
+			// グループ化キーの区切り → 次のカラム名を待つ
+			sp.state = SelectStateGroupBy
+			return
+		}
+		sp.setError(errors.New("[parse error] unexpected symbol in GROUP BY clause: " + symbol))
+	case SelectStateGroup, SelectStateGroupBy:
+		sp.setError(errors.New("[parse error] unexpected symbol in GROUP BY clause: " + symbol))
+	case SelectStateOrderByCol, SelectStateOrderByDir:
+		if symbol == string(SComma) {
+			// ソートキーの区切り → 次のカラム名を待つ
+			sp.state = SelectStateOrderBy
+			return
+		}
+		sp.setError(errors.New("[parse error] unexpected symbol in ORDER BY clause: " + symbol))
+	case SelectStateOrder, SelectStateOrderBy:
+		sp.setError(errors.New("[parse error] unexpected symbol in ORDER BY clause: " + symbol))
+	case SelectStateLimit, SelectStateLimitCount, SelectStateOffset, SelectStateOffsetEnd:
+		sp.setError(errors.New("[parse error] unexpected symbol in LIMIT clause: " + symbol))
+	case SelectStateSetOp, SelectStateSetOpAll:
+		sp.setError(errors.New("[parse error] expected SELECT after set operator: " + symbol))
 	}
 }
 
@@ -227,11 +686,26 @@ func (sp *SelectParser) onString(value string) {
 	if sp.err != nil {
 		return
 	}
-	switch sp.state {
-	case SelectStateOn:
-		sp.on.pushLiteral(ast.NewStringLiteral(value))
-	case SelectStateWhere:
-		sp.where.pushLiteral(ast.NewStringLiteral(value))
+	if sp.sub != nil {
+		if err := sp.sub.onString(value); err != nil {
+			sp.setError(err)
+		}
+		return
+	}
+	if sp.cte != nil {
+		if err := sp.cte.onString(value); err != nil {
+			sp.setError(err)
+		}
+		return
+	}
+	if sp.state == SelectStateColumns && sp.item.inWindow() {
+		sp.setError(errors.New("[parse error] unexpected string in OVER clause: '" + value + "'"))
+		return
+	}
+	if wp := sp.exprParser(); wp != nil {
+		if err := wp.pushLiteral(ast.NewStringLiteral(value)); err != nil {
+			sp.setError(err)
+		}
 	}
 }
 
@@ -239,17 +713,84 @@ func (sp *SelectParser) onNumber(num string) {
 	if sp.err != nil {
 		return
 	}
+	if sp.sub != nil {
+		if err := sp.sub.onNumber(num); err != nil {
+			sp.setError(err)
+		}
+		return
+	}
+	if sp.cte != nil {
+		if err := sp.cte.onNumber(num); err != nil {
+			sp.setError(err)
+		}
+		return
+	}
 	switch sp.state {
-	case SelectStateOn:
-		sp.on.pushLiteral(ast.NewStringLiteral(num))
-	case SelectStateWhere:
-		sp.where.pushLiteral(ast.NewStringLiteral(num))
+	case SelectStateLimit:
+		count, err := parseLimitValue(num)
+		if err != nil {
+			sp.setError(err)
+			return
+		}
+		sp.stmt.Limit.Count = count
+		sp.state = SelectStateLimitCount
+	case SelectStateOffset:
+		offset, err := parseLimitValue(num)
+		if err != nil {
+			sp.setError(err)
+			return
+		}
+		sp.stmt.Limit.Offset = offset
+		sp.state = SelectStateOffsetEnd
+	case SelectStateLimitCount, SelectStateOffsetEnd:
+		sp.setError(errors.New("[parse error] unexpected number in LIMIT clause: " + num))
+	default:
+		if wp := sp.exprParser(); wp != nil {
+			if err := wp.pushLiteral(ast.NewNumberLiteral(num)); err != nil {
+				sp.setError(err)
+			}
+		}
 	}
 }
 
 func (sp *SelectParser) onComment(text string) {}
 func (sp *SelectParser) onError(err error)     { sp.setError(err) }
 
+// beginSubquery はサブクエリのパースを開始する (サブクエリの SELECT キーワードを検出した時点で呼ぶ)
+//
+// サブクエリは導出テーブル (FROM / JOIN の "(" の直後) と、式の中の括弧・IN リスト・EXISTS の "(" の直後に指定できる
+// サブクエリの ")" までのトークンはサブクエリのパーサーに転送する
+func (sp *SelectParser) beginSubquery() {
+	if sp.state != SelectStateDerived {
+		wp := sp.exprParser()
+		if wp == nil {
+			sp.setError(errors.New("[parse error] SELECT keyword is in invalid position"))
+			return
+		}
+		if err := wp.beginSubquery(); err != nil {
+			sp.setError(err)
+			return
+		}
+	}
+	sp.sub = newSubqueryParser()
+}
+
+// endSubquery はサブクエリの ")" の時点で、確定したサブクエリを導出テーブルまたは式として追加する
+func (sp *SelectParser) endSubquery(stmt *ast.SelectStmt) {
+	if sp.state != SelectStateDerived {
+		sp.exprParser().endSubquery(stmt)
+		return
+	}
+	// 導出テーブルの別名は続く AS / 識別子で設定する
+	table := ast.TableId{Subquery: stmt}
+	if sp.currentJoin != nil {
+		sp.currentJoin.Table = table
+	} else {
+		sp.stmt.From = table
+	}
+	sp.state = SelectStateDerivedEnd
+}
+
 // beginJoin は新しい JOIN 句のパースを開始する
 //
 // 既にパース中の JOIN 句がある場合は先に確定する
@@ -281,6 +822,207 @@ func (sp *SelectParser) finalizeCurrentJoin() error {
 	return nil
 }
 
+// finalizeClauses は現在構築中の SELECT 文の JOIN 句・WHERE 句・HAVING 句を確定する
+func (sp *SelectParser) finalizeClauses() error {
+	// 未確定の JOIN 句があれば確定する
+	if err := sp.finalizeCurrentJoin(); err != nil {
+		return err
+	}
+
+	// WHERE 句を確定
+	whereClause, err := sp.where.finalizeWhere()
+	if err != nil {
+		return err
+	}
+	sp.stmt.Where = whereClause
+
+	// HAVING 句を確定
+	havingClause, err := sp.having.finalizeWhere()
+	if err != nil {
+		return err
+	}
+	if havingClause != nil {
+		sp.stmt.Having = &ast.HavingClause{Condition: havingClause.Condition}
+	}
+	return nil
+}
+
+// beginSetOperation は集合演算子 (UNION / INTERSECT / EXCEPT) の時点で、現在構築中の SELECT 文を確定してオペランドに追加する
+//
+// ORDER BY・LIMIT は集合演算の結果に対して指定するため、最後の SELECT 文の後にのみ指定できる
+func (sp *SelectParser) beginSetOperation(keyword string) {
+	if sp.embedded {
+		sp.setError(errors.New("[parse error] " + keyword + " is not supported in subquery or INSERT ... SELECT"))
+		return
+	}
+
+	switch sp.state {
+	case SelectStateFrom, SelectStateGroupByCol:
+	case SelectStateColumns, SelectStateColumnAlias:
+		// FROM 句のない SELECT 文 (e.g. `SELECT 1 UNION ...`)
+		if !sp.atSelectListEnd() {
+			sp.setError(errors.New("[parse error] " + keyword + " keyword is in invalid position"))
+			return
+		}
+		if err := sp.endSelectList(); err != nil {
+			sp.setError(err)
+			return
+		}
+	case SelectStateOn, SelectStateWhere, SelectStateHaving:
+		if sp.exprParser().inNested() {
+			sp.setError(errors.New("[parse error] " + keyword + " keyword is in invalid position"))
+			return
+		}
+	case SelectStateOrderByCol, SelectStateOrderByDir, SelectStateLimitCount, SelectStateOffsetEnd:
+		sp.setError(errors.New("[parse error] ORDER BY and LIMIT must follow the last SELECT of " + keyword))
+		return
+	default:
+		sp.setError(errors.New("[parse error] " + keyword + " keyword is in invalid position"))
+		return
+	}
+	if sp.stmt.From.TableName == "" && !sp.noFrom {
+		sp.setError(errors.New("[parse error] missing FROM clause"))
+		return
+	}
+	if err := sp.finalizeClauses(); err != nil {
+		sp.setError(err)
+		return
+	}
+
+	op := ast.SetOpUnion
+	switch keyword {
+	case KIntersect:
+		op = ast.SetOpIntersect
+	case KExcept:
+		op = ast.SetOpExcept
+	}
+	sp.operands = append(sp.operands, sp.stmt)
+	sp.operators = append(sp.operators, setOperator{op: op})
+
+	// 次のオペランドの SELECT 文のために、句のパーサーを初期化する
+	sp.item = WhereParser{}
+	sp.where = WhereParser{}
+	sp.on = WhereParser{}
+	sp.having = WhereParser{}
+	sp.joinType = ast.JoinTypeInner
+	sp.state = SelectStateSetOp
+}
+
+// buildSetOperation は確定したオペランドと集合演算子から、集合演算の構文木を構築する
+//
+// INTERSECT を UNION・EXCEPT より優先して結合し、同じ優先順位の演算は左から順に結合する
+// 最後の SELECT 文の ORDER BY・LIMIT は、集合演算の結果に対する指定として最も外側の集合演算に移す
+func (sp *SelectParser) buildSetOperation() *ast.SetOperationStmt {
+	last := sp.stmt
+	orderBy, limit := last.OrderBy, last.Limit
+	last.OrderBy, last.Limit = nil, nil
+	operands := append(sp.operands, last)
+
+	// INTERSECT で結合するオペランドを先にまとめる
+	terms := []ast.Statement{operands[0]}
+	var operators []setOperator
+	for i, op := range sp.operators {
+		if op.op == ast.SetOpIntersect {
+			terms[len(terms)-1] = &ast.SetOperationStmt{Op: op.op, All: op.all, Left: terms[len(terms)-1], Right: operands[i+1]}
+			continue
+		}
+		terms = append(terms, operands[i+1])
+		operators = append(operators, op)
+	}
+
+	// UNION・EXCEPT を左から順に結合する
+	result := terms[0]
+	for i, op := range operators {
+		result = &ast.SetOperationStmt{Op: op.op, All: op.all, Left: result, Right: terms[i+1]}
+	}
+
+	stmt := result.(*ast.SetOperationStmt)
+	stmt.OrderBy = orderBy
+	stmt.Limit = limit
+	return stmt
+}
+
+// exprParser は現在のステートで式を解析するパーサー (SELECT リスト・ON・WHERE・HAVING 句) を返す
+//
+// 式を解析するステートでない場合は nil を返す
+func (sp *SelectParser) exprParser() *WhereParser {
+	switch sp.state {
+	case SelectStateColumns:
+		return &sp.item
+	case SelectStateOn:
+		return &sp.on
+	case SelectStateWhere:
+		return &sp.where
+	case SelectStateHaving:
+		return &sp.having
+	default:
+		return nil
+	}
+}
+
+// finalizeSelectItem は SELECT リストの項目を確定し、SelectStmt に追加する ("," または FROM の時点で呼ぶ)
+//
+// 項目の種類に応じて Columns (カラム)、Aggregates (集約関数)、Exprs (それ以外の式) のいずれかに追加する
+func (sp *SelectParser) finalizeSelectItem() error {
+	if sp.item.isEmpty() {
+		return nil
+	}
+	expr, err := sp.item.finalizeExpr("SELECT")
+	if err != nil {
+		return err
+	}
+	sp.item.initExpr()
+
+	pos := len(sp.stmt.Columns) + len(sp.stmt.Aggregates) + len(sp.stmt.Exprs)
+	switch v := expr.(type) {
+	case ast.ColumnId:
+		sp.stmt.Columns = append(sp.stmt.Columns, v)
+	case *ast.AggregateExpr:
+		v.Pos = pos
+		sp.stmt.Aggregates = append(sp.stmt.Aggregates, v)
+	default:
+		sp.stmt.Exprs = append(sp.stmt.Exprs, &ast.SelectExpr{Expr: expr, Pos: pos})
+	}
+	return nil
+}
+
+// atSelectListEnd は SELECT リストの項目の区切りの位置 (関数呼び出しなどの括弧の外) にいるかどうかを返す
+func (sp *SelectParser) atSelectListEnd() bool {
+	return (sp.state == SelectStateColumns && !sp.item.inNested()) || sp.state == SelectStateColumnAlias
+}
+
+// endSelectList は FROM 句のない SELECT 文の SELECT リストを確定する (集合演算子・ORDER BY・LIMIT・";" の時点で呼ぶ)
+//
+// FROM 句のない SELECT 文は集合演算のオペランドとしてのみ指定でき、SELECT * は指定できない (finalize で検証する)
+func (sp *SelectParser) endSelectList() error {
+	if err := sp.finalizeSelectItem(); err != nil {
+		return err
+	}
+	if sp.stmt.IsSelectAll() {
+		return errors.New("[parse error] missing FROM clause")
+	}
+	sp.noFrom = true
+	return nil
+}
+
+// setSelectAlias は直前に確定した SELECT リストの項目に別名を設定する
+func (sp *SelectParser) setSelectAlias(alias string) {
+	if sp.stmt.Aliases == nil {
+		sp.stmt.Aliases = map[int]string{}
+	}
+	pos := len(sp.stmt.Columns) + len(sp.stmt.Aggregates) + len(sp.stmt.Exprs) - 1
+	sp.stmt.Aliases[pos] = alias
+}
+
+// parseLimitValue は LIMIT / OFFSET の件数 (0 以上の整数) をパースする
+func parseLimitValue(num string) (uint64, error) {
+	value, err := strconv.ParseUint(num, 10, 64)
+	if err != nil {
+		return 0, errors.New("[parse error] LIMIT and OFFSET must be non-negative integers: " + num)
+	}
+	return value, nil
+}
+
 // setError はエラーを設定する (既にエラーが設定されている場合は無視する)
 func (sp *SelectParser) setError(err error) {
 	if sp.err == nil {
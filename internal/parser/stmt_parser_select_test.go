@@ -47,7 +47,7 @@ func TestParserSelect(t *testing.T) {
 		assert.NotNil(t, selectStmt.Where)
 		assert.NotNil(t, selectStmt.Where.Condition)
 
-		binaryExpr := selectStmt.Where.Condition
+		binaryExpr := selectStmt.Where.Condition.(*ast.BinaryExpr)
 		assert.Equal(t, "=", binaryExpr.Operator)
 
 		lhsCol, ok := binaryExpr.Left.(*ast.LhsColumn)
@@ -262,6 +262,26 @@ WHERE id = '1' /* id が 1 のレコード */
 		assert.Equal(t, "name", selectStmt.Columns[1].ColName)
 	})
 
+	t.Run("データベース名で修飾したテーブル・カラムを指定できる", func(t *testing.T) {
+		// GIVEN
+		sql := "SELECT app.users.id, users.name FROM app.users;"
+		parser := NewParser()
+
+		// WHEN
+		result, err := parser.Parse(sql)
+
+		// THEN
+		assert.NoError(t, err)
+		selectStmt, ok := result.(*ast.SelectStmt)
+		assert.True(t, ok)
+		assert.Equal(t, "app.users", selectStmt.From.TableName)
+		assert.Len(t, selectStmt.Columns, 2)
+		assert.Equal(t, "app.users", selectStmt.Columns[0].TableName)
+		assert.Equal(t, "id", selectStmt.Columns[0].ColName)
+		assert.Equal(t, "users", selectStmt.Columns[1].TableName)
+		assert.Equal(t, "name", selectStmt.Columns[1].ColName)
+	})
+
 	t.Run("SELECT * の場合 Columns は nil", func(t *testing.T) {
 		// GIVEN
 		sql := "SELECT * FROM users;"
@@ -293,7 +313,7 @@ WHERE id = '1' /* id が 1 のレコード */
 		assert.True(t, ok)
 
 		assert.NotNil(t, selectStmt.Where)
-		binaryExpr := selectStmt.Where.Condition
+		binaryExpr := selectStmt.Where.Condition.(*ast.BinaryExpr)
 		assert.Equal(t, "=", binaryExpr.Operator)
 
 		rhsLit, ok := binaryExpr.Right.(*ast.RhsLiteral)
@@ -323,14 +343,14 @@ WHERE id = '1' /* id が 1 のレコード */
 		assert.Equal(t, "orders", join.Table.TableName)
 
 		assert.NotNil(t, join.Condition)
-		assert.Equal(t, "=", join.Condition.Operator)
+		assert.Equal(t, "=", join.Condition.(*ast.BinaryExpr).Operator)
 
-		lhsCol, ok := join.Condition.Left.(*ast.LhsColumn)
+		lhsCol, ok := join.Condition.(*ast.BinaryExpr).Left.(*ast.LhsColumn)
 		assert.True(t, ok)
 		assert.Equal(t, "users", lhsCol.Column.TableName)
 		assert.Equal(t, "id", lhsCol.Column.ColName)
 
-		rhsCol, ok := join.Condition.Right.(*ast.RhsColumn)
+		rhsCol, ok := join.Condition.(*ast.BinaryExpr).Right.(*ast.RhsColumn)
 		assert.True(t, ok)
 		assert.Equal(t, "orders", rhsCol.Column.TableName)
 		assert.Equal(t, "user_id", rhsCol.Column.ColName)
@@ -356,7 +376,43 @@ WHERE id = '1' /* id が 1 のレコード */
 
 		join := selectStmt.Joins[0]
 		assert.Equal(t, "orders", join.Table.TableName)
-		assert.Equal(t, "=", join.Condition.Operator)
+		assert.Equal(t, "=", join.Condition.(*ast.BinaryExpr).Operator)
+	})
+
+	t.Run("JOIN の種類をパースできる", func(t *testing.T) {
+		tests := []struct {
+			sql      string
+			expected []ast.JoinType
+		}{
+			{"SELECT * FROM users JOIN orders ON users.id = orders.user_id;", []ast.JoinType{ast.JoinTypeInner}},
+			{"SELECT * FROM users LEFT JOIN orders ON users.id = orders.user_id;", []ast.JoinType{ast.JoinTypeLeft}},
+			{"SELECT * FROM users left outer join orders ON users.id = orders.user_id;", []ast.JoinType{ast.JoinTypeLeft}},
+			{"SELECT * FROM users RIGHT JOIN orders ON users.id = orders.user_id;", []ast.JoinType{ast.JoinTypeRight}},
+			{"SELECT * FROM users RIGHT OUTER JOIN orders ON users.id = orders.user_id;", []ast.JoinType{ast.JoinTypeRight}},
+			{
+				"SELECT * FROM users LEFT JOIN orders ON users.id = orders.user_id INNER JOIN items ON orders.item_id = items.id;",
+				[]ast.JoinType{ast.JoinTypeLeft, ast.JoinTypeInner},
+			},
+		}
+		for _, tt := range tests {
+			t.Run(tt.sql, func(t *testing.T) {
+				// GIVEN
+				parser := NewParser()
+
+				// WHEN
+				result, err := parser.Parse(tt.sql)
+
+				// THEN
+				assert.NoError(t, err)
+				selectStmt, ok := result.(*ast.SelectStmt)
+				assert.True(t, ok)
+				var types []ast.JoinType
+				for _, join := range selectStmt.Joins {
+					types = append(types, join.Type)
+				}
+				assert.Equal(t, tt.expected, types)
+			})
+		}
 	})
 
 	t.Run("JOIN + WHERE 付きの SELECT 文をパースできる", func(t *testing.T) {
@@ -381,7 +437,7 @@ WHERE id = '1' /* id が 1 のレコード */
 		assert.Equal(t, "orders", join.Table.TableName)
 
 		assert.NotNil(t, selectStmt.Where)
-		whereExpr := selectStmt.Where.Condition
+		whereExpr := selectStmt.Where.Condition.(*ast.BinaryExpr)
 		assert.Equal(t, "=", whereExpr.Operator)
 
 		lhsCol, ok := whereExpr.Left.(*ast.LhsColumn)
@@ -409,10 +465,10 @@ WHERE id = '1' /* id が 1 のレコード */
 		assert.Len(t, selectStmt.Joins, 2)
 
 		assert.Equal(t, "orders", selectStmt.Joins[0].Table.TableName)
-		assert.Equal(t, "=", selectStmt.Joins[0].Condition.Operator)
+		assert.Equal(t, "=", selectStmt.Joins[0].Condition.(*ast.BinaryExpr).Operator)
 
 		assert.Equal(t, "items", selectStmt.Joins[1].Table.TableName)
-		assert.Equal(t, "=", selectStmt.Joins[1].Condition.Operator)
+		assert.Equal(t, "=", selectStmt.Joins[1].Condition.(*ast.BinaryExpr).Operator)
 	})
 
 	t.Run("ON 条件で AND を使った複合条件をパースできる", func(t *testing.T) {
@@ -432,7 +488,7 @@ WHERE id = '1' /* id が 1 のレコード */
 
 		assert.Len(t, selectStmt.Joins, 1)
 		join := selectStmt.Joins[0]
-		assert.Equal(t, "AND", join.Condition.Operator)
+		assert.Equal(t, "AND", join.Condition.(*ast.BinaryExpr).Operator)
 	})
 
 	t.Run("不正な JOIN 文でエラーになる", func(t *testing.T) {
@@ -491,6 +547,25 @@ WHERE id = '1' /* id が 1 のレコード */
 			assert.Nil(t, result)
 		})
 
+		t.Run("LEFT / RIGHT / OUTER の後に JOIN がない場合", func(t *testing.T) {
+			for _, sql := range []string{
+				"SELECT * FROM users LEFT orders ON users.id = orders.user_id;",
+				"SELECT * FROM users RIGHT OUTER orders ON users.id = orders.user_id;",
+				"SELECT * FROM users OUTER JOIN orders ON users.id = orders.user_id;",
+				"SELECT * FROM users INNER OUTER JOIN orders ON users.id = orders.user_id;",
+			} {
+				// GIVEN
+				parser := NewParser()
+
+				// WHEN
+				result, err := parser.Parse(sql)
+
+				// THEN
+				assert.Error(t, err, sql)
+				assert.Nil(t, result)
+			}
+		})
+
 		t.Run("FROM 句の前に JOIN がある場合", func(t *testing.T) {
 			// GIVEN
 			sql := "SELECT * JOIN orders ON users.id = orders.user_id;"
@@ -521,7 +596,7 @@ WHERE id = '1' /* id が 1 のレコード */
 		assert.True(t, ok)
 
 		assert.NotNil(t, selectStmt.Where)
-		binaryExpr := selectStmt.Where.Condition
+		binaryExpr := selectStmt.Where.Condition.(*ast.BinaryExpr)
 		assert.Equal(t, "AND", binaryExpr.Operator)
 
 		lhsExpr, ok := binaryExpr.Left.(*ast.LhsExpr)
@@ -532,4 +607,596 @@ WHERE id = '1' /* id が 1 のレコード */
 		assert.True(t, ok)
 		assert.Equal(t, "=", rhsExpr.Expr.Operator)
 	})
+
+	t.Run("ORDER BY 付きの SELECT 文をパースできる", func(t *testing.T) {
+		// GIVEN
+		sql := "SELECT * FROM users WHERE age > 20 ORDER BY name, users.id DESC, age ASC;"
+		parser := NewParser()
+
+		// WHEN
+		result, err := parser.Parse(sql)
+
+		// THEN
+		assert.NoError(t, err)
+		selectStmt, ok := result.(*ast.SelectStmt)
+		assert.True(t, ok)
+
+		assert.NotNil(t, selectStmt.Where)
+		assert.Equal(t, ">", selectStmt.Where.Condition.(*ast.BinaryExpr).Operator)
+		assert.Equal(t, []ast.OrderByItem{
+			{Column: ast.ColumnId{ColName: "name"}},
+			{Column: ast.ColumnId{TableName: "users", ColName: "id"}, Desc: true},
+			{Column: ast.ColumnId{ColName: "age"}},
+		}, selectStmt.OrderBy)
+	})
+
+	t.Run("JOIN の後に ORDER BY を指定できる", func(t *testing.T) {
+		// GIVEN
+		sql := "SELECT * FROM users JOIN orders ON users.id = orders.user_id ORDER BY orders.id;"
+		parser := NewParser()
+
+		// WHEN
+		result, err := parser.Parse(sql)
+
+		// THEN
+		assert.NoError(t, err)
+		selectStmt, ok := result.(*ast.SelectStmt)
+		assert.True(t, ok)
+
+		assert.Len(t, selectStmt.Joins, 1)
+		assert.Equal(t, []ast.OrderByItem{
+			{Column: ast.ColumnId{TableName: "orders", ColName: "id"}},
+		}, selectStmt.OrderBy)
+	})
+
+	t.Run("不正な ORDER BY でエラーになる", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			sql      string
+			expected string
+		}{
+			{"BY がない場合", "SELECT * FROM users ORDER name;", "unexpected identifier in ORDER BY clause"},
+			{"カラム名がない場合", "SELECT * FROM users ORDER BY;", "incomplete ORDER BY clause"},
+			{"カンマの後にカラム名がない場合", "SELECT * FROM users ORDER BY name,;", "incomplete ORDER BY clause"},
+			{"カラム名の前に DESC がある場合", "SELECT * FROM users ORDER BY DESC name;", "DESC keyword is in invalid position"},
+			{"カラム名がカンマで区切られていない場合", "SELECT * FROM users ORDER BY name id;", "unexpected identifier in ORDER BY clause"},
+			{"WHERE 句より前にある場合", "SELECT * FROM users ORDER BY name WHERE id = 1;", "WHERE clause is in invalid position"},
+			{"FROM 句より前にある場合", "SELECT * ORDER BY name FROM users;", "ORDER BY clause is in invalid position"},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				// GIVEN
+				parser := NewParser()
+
+				// WHEN
+				result, err := parser.Parse(tt.sql)
+
+				// THEN
+				assert.Error(t, err)
+				assert.Nil(t, result)
+				assert.Contains(t, err.Error(), tt.expected)
+			})
+		}
+	})
+
+	t.Run("LIMIT 付きの SELECT 文をパースできる", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			sql      string
+			expected ast.LimitClause
+		}{
+			{"LIMIT のみ", "SELECT * FROM users LIMIT 10;", ast.LimitClause{Count: 10}},
+			{"LIMIT と OFFSET", "SELECT * FROM users LIMIT 10 OFFSET 20;", ast.LimitClause{Count: 10, Offset: 20}},
+			{"WHERE 句の後", "SELECT * FROM users WHERE id > 1 LIMIT 5;", ast.LimitClause{Count: 5}},
+			{"ORDER BY の後", "SELECT * FROM users ORDER BY name DESC LIMIT 0 OFFSET 3;", ast.LimitClause{Count: 0, Offset: 3}},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				// GIVEN
+				parser := NewParser()
+
+				// WHEN
+				result, err := parser.Parse(tt.sql)
+
+				// THEN
+				assert.NoError(t, err)
+				selectStmt, ok := result.(*ast.SelectStmt)
+				assert.True(t, ok)
+				assert.Equal(t, &tt.expected, selectStmt.Limit)
+			})
+		}
+	})
+
+	t.Run("不正な LIMIT でエラーになる", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			sql      string
+			expected string
+		}{
+			{"件数がない場合", "SELECT * FROM users LIMIT;", "incomplete LIMIT clause"},
+			{"OFFSET の件数がない場合", "SELECT * FROM users LIMIT 10 OFFSET;", "incomplete LIMIT clause"},
+			{"件数が負数の場合", "SELECT * FROM users LIMIT -1;", "LIMIT and OFFSET must be non-negative integers"},
+			{"件数が小数の場合", "SELECT * FROM users LIMIT 1.5;", "LIMIT and OFFSET must be non-negative integers"},
+			{"LIMIT なしで OFFSET を指定した場合", "SELECT * FROM users OFFSET 10;", "OFFSET keyword is in invalid position"},
+			{"LIMIT の後に ORDER BY がある場合", "SELECT * FROM users LIMIT 10 ORDER BY id;", "ORDER BY clause is in invalid position"},
+			{"LIMIT の後に WHERE 句がある場合", "SELECT * FROM users LIMIT 10 WHERE id = 1;", "WHERE clause is in invalid position"},
+			{"件数が 2 つ続く場合", "SELECT * FROM users LIMIT 10 20;", "unexpected number in LIMIT clause"},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				// GIVEN
+				parser := NewParser()
+
+				// WHEN
+				result, err := parser.Parse(tt.sql)
+
+				// THEN
+				assert.Error(t, err)
+				assert.Nil(t, result)
+				assert.Contains(t, err.Error(), tt.expected)
+			})
+		}
+	})
+
+	t.Run("集約関数付きの SELECT 文をパースできる", func(t *testing.T) {
+		// GIVEN
+		sql := "SELECT category, COUNT(*), sum(products.price), MAX(price) FROM products;"
+		parser := NewParser()
+
+		// WHEN
+		result, err := parser.Parse(sql)
+
+		// THEN
+		assert.NoError(t, err)
+		selectStmt, ok := result.(*ast.SelectStmt)
+		assert.True(t, ok)
+
+		assert.Equal(t, []ast.ColumnId{{ColName: "category"}}, selectStmt.Columns)
+		assert.Equal(t, []*ast.AggregateExpr{
+			{Func: ast.AggregateCount, Pos: 1},
+			{Func: ast.AggregateSum, Column: &ast.ColumnId{TableName: "products", ColName: "price"}, Pos: 2},
+			{Func: ast.AggregateMax, Column: &ast.ColumnId{ColName: "price"}, Pos: 3},
+		}, selectStmt.Aggregates)
+		assert.True(t, selectStmt.HasAggregation())
+	})
+
+	t.Run("GROUP BY と HAVING 付きの SELECT 文をパースできる", func(t *testing.T) {
+		// GIVEN
+		sql := "SELECT category, AVG(price) FROM products WHERE stock > 0 GROUP BY category, products.maker HAVING COUNT(*) >= 2 AND category != 'toy' ORDER BY category LIMIT 3;"
+		parser := NewParser()
+
+		// WHEN
+		result, err := parser.Parse(sql)
+
+		// THEN
+		assert.NoError(t, err)
+		selectStmt, ok := result.(*ast.SelectStmt)
+		assert.True(t, ok)
+
+		assert.NotNil(t, selectStmt.Where)
+		assert.Equal(t, []ast.ColumnId{{ColName: "category"}, {TableName: "products", ColName: "maker"}}, selectStmt.GroupBy)
+		assert.Equal(t, []ast.OrderByItem{{Column: ast.ColumnId{ColName: "category"}}}, selectStmt.OrderBy)
+		assert.Equal(t, &ast.LimitClause{Count: 3}, selectStmt.Limit)
+
+		assert.NotNil(t, selectStmt.Having)
+		having := selectStmt.Having.Condition.(*ast.BinaryExpr)
+		assert.Equal(t, "AND", having.Operator)
+
+		left := having.Left.(*ast.LhsExpr).Expr
+		assert.Equal(t, ">=", left.Operator)
+		assert.Equal(t, &ast.LhsAggregate{Aggregate: &ast.AggregateExpr{Func: ast.AggregateCount}}, left.Left)
+		assert.Equal(t, "2", left.Right.(*ast.RhsLiteral).Literal.ToString())
+
+		right := having.Right.(*ast.RhsExpr).Expr
+		assert.Equal(t, "!=", right.Operator)
+		assert.Equal(t, "category", right.Left.(*ast.LhsColumn).Column.ColName)
+	})
+
+	t.Run("集約関数のみの SELECT 文では Columns は SELECT * と区別される", func(t *testing.T) {
+		// GIVEN
+		sql := "SELECT COUNT(*) FROM products;"
+		parser := NewParser()
+
+		// WHEN
+		result, err := parser.Parse(sql)
+
+		// THEN
+		assert.NoError(t, err)
+		selectStmt, ok := result.(*ast.SelectStmt)
+		assert.True(t, ok)
+		assert.Empty(t, selectStmt.Columns)
+		assert.Len(t, selectStmt.Aggregates, 1)
+	})
+
+	t.Run("SELECT リストに式を指定できる", func(t *testing.T) {
+		// GIVEN
+		sql := "SELECT name, price * (qty - 1), UPPER(name), CASE WHEN qty > 0 THEN 'in stock' END FROM products;"
+		parser := NewParser()
+
+		// WHEN
+		result, err := parser.Parse(sql)
+
+		// THEN
+		assert.NoError(t, err)
+		selectStmt, ok := result.(*ast.SelectStmt)
+		assert.True(t, ok)
+
+		assert.Equal(t, []ast.ColumnId{{ColName: "name"}}, selectStmt.Columns)
+		assert.Len(t, selectStmt.Exprs, 3)
+		assert.Equal(t, "price * (qty - 1)", ast.ExprString(selectStmt.Exprs[0].Expr))
+		assert.Equal(t, 1, selectStmt.Exprs[0].Pos)
+		assert.Equal(t, "UPPER(name)", ast.ExprString(selectStmt.Exprs[1].Expr))
+		assert.Equal(t, 2, selectStmt.Exprs[1].Pos)
+		assert.Equal(t, "CASE WHEN qty > 0 THEN in stock END", ast.ExprString(selectStmt.Exprs[2].Expr))
+		assert.Equal(t, 3, selectStmt.Exprs[2].Pos)
+		assert.False(t, selectStmt.HasAggregation())
+	})
+
+	t.Run("集約関数を含む式を SELECT リストに指定できる", func(t *testing.T) {
+		// GIVEN
+		sql := "SELECT category, SUM(price) / COUNT(*) FROM products GROUP BY category;"
+		parser := NewParser()
+
+		// WHEN
+		result, err := parser.Parse(sql)
+
+		// THEN
+		assert.NoError(t, err)
+		selectStmt, ok := result.(*ast.SelectStmt)
+		assert.True(t, ok)
+
+		assert.Empty(t, selectStmt.Aggregates)
+		assert.Len(t, selectStmt.Exprs, 1)
+		assert.Equal(t, "SUM(price) / COUNT(*)", ast.ExprString(selectStmt.Exprs[0].Expr))
+		assert.True(t, selectStmt.HasAggregation())
+	})
+
+	t.Run("SELECT リストの項目に AS で別名を指定できる", func(t *testing.T) {
+		// GIVEN
+		sql := "SELECT user_id AS uid, COUNT(*) AS cnt, name FROM orders GROUP BY user_id, name;"
+		parser := NewParser()
+
+		// WHEN
+		result, err := parser.Parse(sql)
+
+		// THEN
+		assert.NoError(t, err)
+		selectStmt, ok := result.(*ast.SelectStmt)
+		assert.True(t, ok)
+
+		assert.Equal(t, []ast.ColumnId{{ColName: "user_id"}, {ColName: "name"}}, selectStmt.Columns)
+		assert.Equal(t, map[int]string{0: "uid", 1: "cnt"}, selectStmt.Aliases)
+		assert.Equal(t, "SELECT user_id AS uid, COUNT(*) AS cnt, name FROM orders GROUP BY user_id, name", ast.SelectString(selectStmt))
+	})
+
+	t.Run("不正な別名でエラーになる", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			sql      string
+			expected string
+		}{
+			{"AS の後に別名がない場合", "SELECT id AS FROM users;", "missing alias after AS"},
+			{"AS の前に項目がない場合", "SELECT AS id FROM users;", "AS keyword is in invalid position"},
+			{"別名の後に識別子が続く場合", "SELECT id AS a b FROM users;", "unexpected identifier in SELECT list"},
+			{"FROM 句のテーブルに AS を指定した場合", "SELECT id FROM users AS u;", "AS keyword is in invalid position"},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				// GIVEN
+				parser := NewParser()
+
+				// WHEN
+				result, err := parser.Parse(tt.sql)
+
+				// THEN
+				assert.Error(t, err)
+				assert.Nil(t, result)
+				assert.Contains(t, err.Error(), tt.expected)
+			})
+		}
+	})
+
+	t.Run("不正な集約関数・GROUP BY・HAVING でエラーになる", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			sql      string
+			expected string
+		}{
+			{"COUNT 以外で * を指定した場合", "SELECT SUM(*) FROM products;", "SUM(*) is not supported"},
+			{"引数がない場合", "SELECT COUNT() FROM products;", "missing argument in COUNT()"},
+			{"閉じ括弧がない場合", "SELECT COUNT(id FROM products;", "FROM clause is in invalid position"},
+			{"引数の後に識別子が続く場合", "SELECT COUNT(id name) FROM products;", "unexpected identifier in aggregate function"},
+			{"引数が 2 つある場合", "SELECT MAX(id, price) FROM products;", "unexpected symbol in aggregate function"},
+			{"集約関数の引数が式の場合", "SELECT SUM(price * 2) FROM products;", "unexpected symbol in aggregate function"},
+			{"GROUP の後に BY がない場合", "SELECT COUNT(*) FROM products GROUP category;", "unexpected identifier in GROUP BY clause"},
+			{"GROUP BY のカラム名がない場合", "SELECT COUNT(*) FROM products GROUP BY;", "incomplete GROUP BY clause"},
+			{"FROM 句より前に GROUP BY がある場合", "SELECT COUNT(*) GROUP BY category FROM products;", "GROUP BY clause is in invalid position"},
+			{"ORDER BY の後に GROUP BY がある場合", "SELECT COUNT(*) FROM products ORDER BY id GROUP BY category;", "GROUP BY clause is in invalid position"},
+			{"ORDER BY の後に HAVING がある場合", "SELECT COUNT(*) FROM products ORDER BY id HAVING COUNT(*) > 1;", "HAVING clause is in invalid position"},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				// GIVEN
+				parser := NewParser()
+
+				// WHEN
+				result, err := parser.Parse(tt.sql)
+
+				// THEN
+				assert.Error(t, err)
+				assert.Nil(t, result)
+				assert.Contains(t, err.Error(), tt.expected)
+			})
+		}
+	})
+}
+
+func TestParserSelectDistinct(t *testing.T) {
+	t.Run("SELECT DISTINCT をパースできる", func(t *testing.T) {
+		// GIVEN
+		sql := "SELECT DISTINCT name, age FROM users ORDER BY name LIMIT 3;"
+		parser := NewParser()
+
+		// WHEN
+		result, err := parser.Parse(sql)
+
+		// THEN
+		assert.NoError(t, err)
+		stmt := result.(*ast.SelectStmt)
+		assert.True(t, stmt.Distinct)
+		assert.Equal(t, []ast.ColumnId{*ast.NewColumnId("name"), *ast.NewColumnId("age")}, stmt.Columns)
+		assert.Equal(t, []ast.OrderByItem{{Column: *ast.NewColumnId("name")}}, stmt.OrderBy)
+		assert.Equal(t, &ast.LimitClause{Count: 3}, stmt.Limit)
+		assert.Equal(t, "SELECT DISTINCT name, age FROM users ORDER BY name LIMIT 3", ast.SelectString(stmt))
+	})
+
+	t.Run("SELECT DISTINCT * をパースできる", func(t *testing.T) {
+		// GIVEN
+		sql := "SELECT DISTINCT * FROM users;"
+		parser := NewParser()
+
+		// WHEN
+		result, err := parser.Parse(sql)
+
+		// THEN
+		assert.NoError(t, err)
+		stmt := result.(*ast.SelectStmt)
+		assert.True(t, stmt.Distinct)
+		assert.True(t, stmt.IsSelectAll())
+	})
+
+	t.Run("サブクエリの SELECT DISTINCT をパースできる", func(t *testing.T) {
+		// GIVEN
+		sql := "SELECT * FROM users WHERE id IN (SELECT DISTINCT user_id FROM orders);"
+		parser := NewParser()
+
+		// WHEN
+		result, err := parser.Parse(sql)
+
+		// THEN
+		assert.NoError(t, err)
+		in := result.(*ast.SelectStmt).Where.Condition.(*ast.InExpr)
+		assert.True(t, in.Subquery.Distinct)
+	})
+
+	t.Run("DISTINCT が SELECT の直後にない場合はエラーになる", func(t *testing.T) {
+		tests := []struct {
+			name string
+			sql  string
+		}{
+			{"カラムの後", "SELECT name DISTINCT FROM users;"},
+			{"DISTINCT が重複している場合", "SELECT DISTINCT DISTINCT name FROM users;"},
+			{"WHERE 句の中", "SELECT name FROM users WHERE DISTINCT id = 1;"},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				// GIVEN
+				parser := NewParser()
+
+				// WHEN
+				_, err := parser.Parse(tt.sql)
+
+				// THEN
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), "DISTINCT keyword is in invalid position")
+			})
+		}
+	})
+}
+
+func TestParserSetOperation(t *testing.T) {
+	t.Run("UNION をパースできる", func(t *testing.T) {
+		// GIVEN
+		sql := "SELECT id FROM users WHERE age > 20 UNION SELECT user_id FROM orders;"
+		parser := NewParser()
+
+		// WHEN
+		result, err := parser.Parse(sql)
+
+		// THEN
+		assert.NoError(t, err)
+		stmt, ok := result.(*ast.SetOperationStmt)
+		assert.True(t, ok)
+		assert.Equal(t, ast.SetOpUnion, stmt.Op)
+		assert.False(t, stmt.All)
+
+		left := stmt.Left.(*ast.SelectStmt)
+		assert.Equal(t, "SELECT id FROM users WHERE age > 20", ast.SelectString(left))
+		right := stmt.Right.(*ast.SelectStmt)
+		assert.Equal(t, "SELECT user_id FROM orders", ast.SelectString(right))
+	})
+
+	t.Run("ALL / DISTINCT を指定できる", func(t *testing.T) {
+		tests := []struct {
+			name string
+			sql  string
+			op   ast.SetOperator
+			all  bool
+		}{
+			{"UNION ALL", "SELECT id FROM a UNION ALL SELECT id FROM b;", ast.SetOpUnion, true},
+			{"UNION DISTINCT", "SELECT id FROM a UNION DISTINCT SELECT id FROM b;", ast.SetOpUnion, false},
+			{"INTERSECT", "SELECT id FROM a INTERSECT SELECT id FROM b;", ast.SetOpIntersect, false},
+			{"INTERSECT ALL", "SELECT id FROM a INTERSECT ALL SELECT id FROM b;", ast.SetOpIntersect, true},
+			{"EXCEPT", "SELECT id FROM a EXCEPT SELECT id FROM b;", ast.SetOpExcept, false},
+			{"EXCEPT ALL", "SELECT id FROM a EXCEPT ALL SELECT id FROM b;", ast.SetOpExcept, true},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				// GIVEN
+				parser := NewParser()
+
+				// WHEN
+				result, err := parser.Parse(tt.sql)
+
+				// THEN
+				assert.NoError(t, err)
+				stmt := result.(*ast.SetOperationStmt)
+				assert.Equal(t, tt.op, stmt.Op)
+				assert.Equal(t, tt.all, stmt.All)
+				assert.Equal(t, "a", stmt.Left.(*ast.SelectStmt).From.TableName)
+				assert.Equal(t, "b", stmt.Right.(*ast.SelectStmt).From.TableName)
+			})
+		}
+	})
+
+	t.Run("INTERSECT は UNION・EXCEPT より優先して結合し、同じ優先順位の演算は左から結合する", func(t *testing.T) {
+		// GIVEN
+		sql := "SELECT id FROM a UNION SELECT id FROM b INTERSECT SELECT id FROM c EXCEPT SELECT id FROM d;"
+		parser := NewParser()
+
+		// WHEN
+		result, err := parser.Parse(sql)
+
+		// THEN: (a UNION (b INTERSECT c)) EXCEPT d
+		assert.NoError(t, err)
+		except := result.(*ast.SetOperationStmt)
+		assert.Equal(t, ast.SetOpExcept, except.Op)
+		assert.Equal(t, "d", except.Right.(*ast.SelectStmt).From.TableName)
+
+		union := except.Left.(*ast.SetOperationStmt)
+		assert.Equal(t, ast.SetOpUnion, union.Op)
+		assert.Equal(t, "a", union.Left.(*ast.SelectStmt).From.TableName)
+
+		intersect := union.Right.(*ast.SetOperationStmt)
+		assert.Equal(t, ast.SetOpIntersect, intersect.Op)
+		assert.Equal(t, "b", intersect.Left.(*ast.SelectStmt).From.TableName)
+		assert.Equal(t, "c", intersect.Right.(*ast.SelectStmt).From.TableName)
+	})
+
+	t.Run("最後の SELECT 文の ORDER BY・LIMIT は集合演算の結果に対する指定になる", func(t *testing.T) {
+		// GIVEN
+		sql := "SELECT id, name FROM a UNION ALL SELECT id, name FROM b WHERE id > 1 ORDER BY name DESC LIMIT 5 OFFSET 2;"
+		parser := NewParser()
+
+		// WHEN
+		result, err := parser.Parse(sql)
+
+		// THEN
+		assert.NoError(t, err)
+		stmt := result.(*ast.SetOperationStmt)
+		assert.Equal(t, []ast.OrderByItem{{Column: *ast.NewColumnId("name"), Desc: true}}, stmt.OrderBy)
+		assert.Equal(t, &ast.LimitClause{Count: 5, Offset: 2}, stmt.Limit)
+
+		right := stmt.Right.(*ast.SelectStmt)
+		assert.Nil(t, right.OrderBy)
+		assert.Nil(t, right.Limit)
+		assert.NotNil(t, right.Where)
+	})
+
+	t.Run("オペランドに JOIN・GROUP BY・HAVING・SELECT DISTINCT・サブクエリを指定できる", func(t *testing.T) {
+		// GIVEN
+		sql := "SELECT DISTINCT u.name FROM users u JOIN orders o ON u.id = o.user_id WHERE o.total > 10 " +
+			"UNION SELECT name FROM users GROUP BY name HAVING COUNT(*) > 1 " +
+			"EXCEPT SELECT name FROM users WHERE id IN (SELECT user_id FROM banned);"
+		parser := NewParser()
+
+		// WHEN
+		result, err := parser.Parse(sql)
+
+		// THEN
+		assert.NoError(t, err)
+		except := result.(*ast.SetOperationStmt)
+		union := except.Left.(*ast.SetOperationStmt)
+		first := union.Left.(*ast.SelectStmt)
+		assert.True(t, first.Distinct)
+		assert.Len(t, first.Joins, 1)
+		assert.NotNil(t, first.Where)
+		second := union.Right.(*ast.SelectStmt)
+		assert.Equal(t, []ast.ColumnId{*ast.NewColumnId("name")}, second.GroupBy)
+		assert.NotNil(t, second.Having)
+		third := except.Right.(*ast.SelectStmt)
+		assert.IsType(t, &ast.InExpr{}, third.Where.Condition)
+	})
+
+	t.Run("オペランドの FROM 句を省略できる", func(t *testing.T) {
+		// GIVEN
+		sql := "SELECT 1 AS n, 'a' UNION ALL SELECT id, name FROM users UNION SELECT 2 + 3, NULL ORDER BY n LIMIT 2;"
+		parser := NewParser()
+
+		// WHEN
+		result, err := parser.Parse(sql)
+
+		// THEN
+		assert.NoError(t, err)
+		stmt := result.(*ast.SetOperationStmt)
+		assert.Equal(t, "SELECT 1 AS n, a UNION ALL SELECT id, name FROM users UNION SELECT 2 + 3, NULL ORDER BY n LIMIT 2", ast.StatementString(stmt))
+		first := stmt.Left.(*ast.SetOperationStmt).Left.(*ast.SelectStmt)
+		assert.Equal(t, "", first.From.TableName)
+		assert.Len(t, first.Exprs, 2)
+		last := stmt.Right.(*ast.SelectStmt)
+		assert.Equal(t, "", last.From.TableName)
+		assert.Nil(t, last.OrderBy)
+		assert.Equal(t, &ast.LimitClause{Count: 2}, stmt.Limit)
+	})
+
+	t.Run("EXPLAIN で集合演算を指定できる", func(t *testing.T) {
+		// GIVEN
+		sql := "EXPLAIN SELECT id FROM a UNION SELECT id FROM b;"
+		parser := NewParser()
+
+		// WHEN
+		result, err := parser.Parse(sql)
+
+		// THEN
+		assert.NoError(t, err)
+		explain := result.(*ast.ExplainStmt)
+		assert.IsType(t, &ast.SetOperationStmt{}, explain.Stmt)
+	})
+
+	t.Run("不正な集合演算でエラーになる", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			sql      string
+			expected string
+		}{
+			{"集合演算子の後に SELECT 文がない場合", "SELECT id FROM a UNION;", "missing SELECT statement after set operator"},
+			{"集合演算子の後に識別子がある場合", "SELECT id FROM a UNION b;", "expected SELECT after set operator: b"},
+			{"ALL が重複している場合", "SELECT id FROM a UNION ALL ALL SELECT id FROM b;", "ALL keyword is in invalid position"},
+			{"ALL が集合演算子の後以外にある場合", "SELECT ALL id FROM a;", "ALL keyword is in invalid position"},
+			{"FROM 句のないオペランドが SELECT * の場合", "SELECT * UNION SELECT id FROM b;", "missing FROM clause"},
+			{"SELECT リストの式の途中に集合演算子がある場合", "SELECT id + UNION SELECT id FROM b;", "invalid expression syntax"},
+			{"集合演算でない SELECT 文に FROM 句がない場合", "SELECT 1;", "missing FROM clause"},
+			{"FROM 句のないオペランドに WHERE 句がある場合", "SELECT 1 UNION SELECT 2 WHERE 1 = 1;", "WHERE clause is in invalid position"},
+			{"左側のオペランドに FROM 句がない場合", "SELECT id FROM UNION SELECT id FROM b;", "missing FROM clause"},
+			{"左側のオペランドに ORDER BY がある場合", "SELECT id FROM a ORDER BY id UNION SELECT id FROM b;", "ORDER BY and LIMIT must follow the last SELECT of UNION"},
+			{"左側のオペランドに LIMIT がある場合", "SELECT id FROM a LIMIT 1 EXCEPT SELECT id FROM b;", "ORDER BY and LIMIT must follow the last SELECT of EXCEPT"},
+			{"WHERE 句の括弧の中にある場合", "SELECT id FROM a WHERE (id = 1 UNION SELECT id FROM b);", "UNION keyword is in invalid position"},
+			{"サブクエリの中にある場合", "SELECT * FROM a WHERE id IN (SELECT id FROM b UNION SELECT id FROM c);", "UNION is not supported in subquery or INSERT ... SELECT"},
+			{"導出テーブルの中にある場合", "SELECT * FROM (SELECT id FROM b INTERSECT SELECT id FROM c) AS t;", "INTERSECT is not supported in subquery or INSERT ... SELECT"},
+			{"INSERT ... SELECT の中にある場合", "INSERT INTO a SELECT id FROM b UNION SELECT id FROM c;", "UNION is not supported in subquery or INSERT ... SELECT"},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				// GIVEN
+				parser := NewParser()
+
+				// WHEN
+				_, err := parser.Parse(tt.sql)
+
+				// THEN
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expected)
+			})
+		}
+	})
 }
@@ -0,0 +1,115 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ren-yamanashi/minesql/internal/ast"
+)
+
+// TruncateTableParser は TRUNCATE TABLE 文をパースする
+//
+// 構文: TRUNCATE [TABLE] table_name;
+type TruncateTableParser struct {
+	state     parserState
+	tableName string
+	err       error
+}
+
+func NewTruncateTableParser() *TruncateTableParser {
+	return &TruncateTableParser{}
+}
+
+func (p *TruncateTableParser) getResult() ast.Statement {
+	if p.err != nil {
+		return nil
+	}
+	return &ast.TruncateTableStmt{TableName: p.tableName}
+}
+
+func (p *TruncateTableParser) getError() error { return p.err }
+
+func (p *TruncateTableParser) finalize() {
+	if p.err != nil {
+		return
+	}
+	if p.state != TruncateStateEnd {
+		p.err = fmt.Errorf("[parse error] incomplete TRUNCATE TABLE statement")
+	}
+}
+
+func (p *TruncateTableParser) onKeyword(word string) {
+	if p.err != nil {
+		return
+	}
+
+	upper := strings.ToUpper(word)
+
+	switch p.state {
+	case TruncateStateTruncate:
+		// TRUNCATE の次は TABLE またはテーブル名 (TABLE は省略可能)
+		if upper != KTable {
+			p.err = fmt.Errorf("[parse error] expected table name after TRUNCATE, got %q", word)
+		}
+		p.state = TruncateStateTable
+
+	default:
+		if upper == KTruncate {
+			p.state = TruncateStateTruncate
+			return
+		}
+		p.err = fmt.Errorf("[parse error] unexpected keyword %q in TRUNCATE TABLE statement", word)
+	}
+}
+
+func (p *TruncateTableParser) onIdentifier(ident string) {
+	if p.err != nil {
+		return
+	}
+
+	switch p.state {
+	case TruncateStateTruncate, TruncateStateTable:
+		p.tableName = ident
+		p.state = TruncateStateEnd
+
+	default:
+		p.err = fmt.Errorf("[parse error] unexpected identifier %q in TRUNCATE TABLE statement", ident)
+	}
+}
+
+func (p *TruncateTableParser) onSymbol(symbol string) {
+	if p.err != nil {
+		return
+	}
+
+	switch p.state {
+	case TruncateStateEnd:
+		if symbol == ";" {
+			return
+		}
+		p.err = fmt.Errorf("[parse error] expected ';' at end of TRUNCATE TABLE statement, got %q", symbol)
+
+	default:
+		p.err = fmt.Errorf("[parse error] unexpected symbol %q in TRUNCATE TABLE statement", symbol)
+	}
+}
+
+func (p *TruncateTableParser) onString(value string) {
+	if p.err != nil {
+		return
+	}
+	p.err = fmt.Errorf("[parse error] unexpected string %q in TRUNCATE TABLE statement", value)
+}
+
+func (p *TruncateTableParser) onNumber(_ string) {
+	if p.err != nil {
+		return
+	}
+	p.err = fmt.Errorf("[parse error] unexpected number in TRUNCATE TABLE statement")
+}
+
+func (p *TruncateTableParser) onComment(_ string) {}
+
+func (p *TruncateTableParser) onError(err error) {
+	p.err = err
+}
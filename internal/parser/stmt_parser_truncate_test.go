@@ -0,0 +1,65 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/ren-yamanashi/minesql/internal/ast"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParserTruncateTable(t *testing.T) {
+	t.Run("TRUNCATE TABLE 文をパースできる", func(t *testing.T) {
+		// GIVEN
+		sql := "TRUNCATE TABLE users;"
+		parser := NewParser()
+
+		// WHEN
+		result, err := parser.Parse(sql)
+
+		// THEN
+		assert.NoError(t, err)
+		stmt, ok := result.(*ast.TruncateTableStmt)
+		assert.True(t, ok)
+		assert.Equal(t, "users", stmt.TableName)
+	})
+
+	t.Run("TABLE キーワードを省略できる", func(t *testing.T) {
+		// GIVEN
+		sql := "truncate users"
+		parser := NewParser()
+
+		// WHEN
+		result, err := parser.Parse(sql)
+
+		// THEN
+		assert.NoError(t, err)
+		stmt, ok := result.(*ast.TruncateTableStmt)
+		assert.True(t, ok)
+		assert.Equal(t, "users", stmt.TableName)
+	})
+
+	t.Run("不正な TRUNCATE TABLE 文でエラーになる", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			sql      string
+			expected string
+		}{
+			{"TRUNCATE の後にテーブル名以外のキーワードが来た場合", "TRUNCATE USER users;", "expected table name after TRUNCATE"},
+			{"テーブル名がない場合", "TRUNCATE TABLE;", "unexpected symbol \";\" in TRUNCATE TABLE statement"},
+			{"TABLE の後で終わった場合", "TRUNCATE TABLE", "incomplete TRUNCATE TABLE statement"},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				// GIVEN
+				parser := NewParser()
+
+				// WHEN
+				result, err := parser.Parse(tt.sql)
+
+				// THEN
+				assert.Nil(t, result)
+				assert.ErrorContains(t, err, tt.expected)
+			})
+		}
+	})
+}
@@ -10,7 +10,9 @@ import (
 type UpdateParser struct {
 	state         parserState     // 現在のステート
 	stmt          *ast.UpdateStmt // 現在構築中の UPDATE 文
+	join          *JoinParser     // JOIN 句パーサー (nil なら JOIN 句なし)
 	where         WhereParser     // WHERE 句パーサー
+	value         WhereParser     // SET 句の値の式のパーサー
 	currentSetCol string          // 現在構築中の SetClause のカラム名
 	err           error           // エラー情報
 }
@@ -40,6 +42,12 @@ func (up *UpdateParser) finalize() {
 		return
 	}
 
+	// SET 句の値の解析中に終わった場合 (末尾にセミコロンがない場合) はエラー
+	if up.state == UpdateStateSetEq {
+		up.setError(errors.New("[parse error] incomplete UPDATE statement"))
+		return
+	}
+
 	// SET 句がない場合はエラー
 	if len(up.stmt.SetClauses) == 0 {
 		up.setError(errors.New("[parse error] missing SET clause"))
@@ -73,7 +81,34 @@ func (up *UpdateParser) onKeyword(word string) {
 		up.state = UpdateStateUpdate
 		return
 
+	case KInner, KLeft, KRight, KJoin:
+		// JOIN 句はテーブル名の後に来る (以降は JOIN 句のパーサーに転送する)
+		if up.state == UpdateStateTable {
+			up.join = NewJoinParser()
+			up.state = UpdateStateJoin
+		}
+		if up.state == UpdateStateJoin {
+			up.setError(up.join.onKeyword(upperWord))
+			return
+		}
+		up.setError(errors.New("[parse error] " + upperWord + " keyword is in invalid position"))
+		return
+
+	case KOuter, KOn:
+		if up.state == UpdateStateJoin {
+			up.setError(up.join.onKeyword(upperWord))
+			return
+		}
+		up.setError(errors.New("[parse error] " + upperWord + " keyword is in invalid position"))
+		return
+
 	case KSet:
+		if up.state == UpdateStateJoin && !up.join.inNested() {
+			if !up.finalizeJoin() {
+				return
+			}
+			up.state = UpdateStateTable
+		}
 		if up.state == UpdateStateTable {
 			up.state = UpdateStateSet
 			return
@@ -83,7 +118,11 @@ func (up *UpdateParser) onKeyword(word string) {
 		return
 
 	case KWhere:
-		if up.state == UpdateStateSetVal {
+		if up.state == UpdateStateSetEq && !up.value.inNested() {
+			if err := up.appendSetClause(); err != nil {
+				up.setError(err)
+				return
+			}
 			up.stmt.Where = up.where.initWhere()
 			up.state = UpdateStateWhere
 			return
@@ -93,8 +132,12 @@ func (up *UpdateParser) onKeyword(word string) {
 		return
 
 	case KAnd, KOr:
-		if up.state == UpdateStateWhere {
-			if err := up.where.handleOperator(upperWord); err != nil {
+		if up.state == UpdateStateJoin {
+			up.setError(up.join.onKeyword(upperWord))
+			return
+		}
+		if wp := up.exprParser(); wp != nil {
+			if err := wp.handleOperator(upperWord); err != nil {
 				up.setError(err)
 			}
 			return
@@ -103,6 +146,20 @@ func (up *UpdateParser) onKeyword(word string) {
 		up.setError(errors.New("[parse error] " + upperWord + " operator is in invalid position"))
 		return
 
+	case KIs, KNot, KNull, KIn, KBetween, KLike, KCase, KWhen, KThen, KElse, KEnd:
+		if up.state == UpdateStateJoin {
+			up.setError(up.join.onKeyword(upperWord))
+			return
+		}
+		if wp := up.exprParser(); wp != nil {
+			if err := wp.handleKeyword(upperWord); err != nil {
+				up.setError(err)
+			}
+			return
+		}
+		up.setError(errors.New("[parse error] " + upperWord + " is in invalid position"))
+		return
+
 	default:
 		up.setError(errors.New("[parse error] unsupported keyword: " + word))
 		return
@@ -119,12 +176,16 @@ func (up *UpdateParser) onIdentifier(ident string) {
 		// テーブル名
 		up.stmt.Table = *ast.NewTableId(ident)
 		up.state = UpdateStateTable
+	case UpdateStateJoin:
+		up.setError(up.join.onIdentifier(ident))
 	case UpdateStateSet:
 		// SET 句のカラム名
 		up.currentSetCol = ident
 		up.state = UpdateStateSetCol
-	case UpdateStateWhere:
-		up.where.pushColumn(ident)
+	case UpdateStateSetEq, UpdateStateWhere:
+		if err := up.exprParser().pushColumn(ident); err != nil {
+			up.setError(err)
+		}
 	default:
 		up.setError(errors.New("[parse error] unexpected identifier: " + ident))
 	}
@@ -137,26 +198,51 @@ func (up *UpdateParser) onSymbol(symbol string) {
 
 	// ";" が来たら state を End にする
 	if symbol == string(SSemicolon) {
+		if up.state == UpdateStateJoin && !up.finalizeJoin() {
+			return
+		}
+		if up.state == UpdateStateSetEq {
+			if err := up.appendSetClause(); err != nil {
+				up.setError(err)
+				return
+			}
+		}
 		up.state = UpdateStateEnd
 		return
 	}
 
 	switch up.state {
+	case UpdateStateJoin:
+		up.setError(up.join.onSymbol(symbol))
+
 	case UpdateStateSetCol:
 		// "=" のみ受け付ける
 		if symbol == string(SEqual) {
+			up.value.initExpr()
 			up.state = UpdateStateSetEq
 			return
 		}
 		up.setError(errors.New("[parse error] expected '=' after column name in SET clause"))
 
-	case UpdateStateSetVal:
-		// "," が来たら次の SetClause のカラム名待ち
-		if symbol == string(SComma) {
-			up.state = UpdateStateSet
-			return
+	case UpdateStateSetEq:
+		if !up.value.inNested() {
+			switch symbol {
+			case string(SComma):
+				// "," が来たら値を確定し、次の SetClause のカラム名待ち
+				if err := up.appendSetClause(); err != nil {
+					up.setError(err)
+					return
+				}
+				up.state = UpdateStateSet
+				return
+			case string(SRightParen):
+				up.setError(errors.New("[parse error] unexpected symbol in SET clause: " + symbol))
+				return
+			}
+		}
+		if err := up.value.handleOperator(symbol); err != nil {
+			up.setError(err)
 		}
-		up.setError(errors.New("[parse error] unexpected symbol in SET clause: " + symbol))
 
 	case UpdateStateWhere:
 		if err := up.where.handleOperator(symbol); err != nil {
@@ -174,16 +260,12 @@ func (up *UpdateParser) onString(value string) {
 	}
 
 	switch up.state {
-	case UpdateStateSetEq:
-		// SET 句の値
-		up.stmt.SetClauses = append(up.stmt.SetClauses, &ast.SetClause{
-			Column: *ast.NewColumnId(up.currentSetCol),
-			Value:  ast.NewStringLiteral(value),
-		})
-		up.currentSetCol = ""
-		up.state = UpdateStateSetVal
-	case UpdateStateWhere:
-		up.where.pushLiteral(ast.NewStringLiteral(value))
+	case UpdateStateJoin:
+		up.setError(up.join.onString(value))
+	case UpdateStateSetEq, UpdateStateWhere:
+		if err := up.exprParser().pushLiteral(ast.NewStringLiteral(value)); err != nil {
+			up.setError(err)
+		}
 	default:
 		up.setError(errors.New("[parse error] unexpected string: " + value))
 	}
@@ -195,16 +277,12 @@ func (up *UpdateParser) onNumber(num string) {
 	}
 
 	switch up.state {
-	case UpdateStateSetEq:
-		// SET 句の値 (数値)
-		up.stmt.SetClauses = append(up.stmt.SetClauses, &ast.SetClause{
-			Column: *ast.NewColumnId(up.currentSetCol),
-			Value:  ast.NewStringLiteral(num),
-		})
-		up.currentSetCol = ""
-		up.state = UpdateStateSetVal
-	case UpdateStateWhere:
-		up.where.pushLiteral(ast.NewStringLiteral(num))
+	case UpdateStateJoin:
+		up.setError(up.join.onNumber(num))
+	case UpdateStateSetEq, UpdateStateWhere:
+		if err := up.exprParser().pushLiteral(ast.NewNumberLiteral(num)); err != nil {
+			up.setError(err)
+		}
 	default:
 		up.setError(errors.New("[parse error] unexpected number: " + num))
 	}
@@ -213,6 +291,50 @@ func (up *UpdateParser) onNumber(num string) {
 func (up *UpdateParser) onComment(text string) {}
 func (up *UpdateParser) onError(err error)     { up.setError(err) }
 
+// exprParser は現在のステートで式を解析するパーサー (SET 句の値・WHERE 句) を返す
+//
+// 式を解析するステートでない場合は nil を返す
+func (up *UpdateParser) exprParser() *WhereParser {
+	switch up.state {
+	case UpdateStateSetEq:
+		return &up.value
+	case UpdateStateWhere:
+		return &up.where
+	default:
+		return nil
+	}
+}
+
+// appendSetClause は現在のカラムに対する SET 句を、解析した値の式で確定して追加する ("," or WHERE or ";" の時点で呼ぶ)
+func (up *UpdateParser) appendSetClause() error {
+	if up.value.isEmpty() {
+		return errors.New("[parse error] missing SET clause value for column " + up.currentSetCol)
+	}
+	value, err := up.value.finalizeExpr("SET")
+	if err != nil {
+		return err
+	}
+	up.stmt.SetClauses = append(up.stmt.SetClauses, &ast.SetClause{
+		Column: parseColumnId(up.currentSetCol),
+		Value:  value,
+	})
+	up.currentSetCol = ""
+	return nil
+}
+
+// finalizeJoin は JOIN 句を確定して UpdateStmt に設定する (SET or ";" の時点で呼ぶ)
+//
+// エラーの場合は false を返す
+func (up *UpdateParser) finalizeJoin() bool {
+	joins, err := up.join.finalize()
+	if err != nil {
+		up.setError(err)
+		return false
+	}
+	up.stmt.Joins = joins
+	return true
+}
+
 // setError はエラーを設定する (既にエラーが設定されている場合は無視する)
 func (up *UpdateParser) setError(err error) {
 	if up.err == nil {
@@ -26,7 +26,7 @@ func TestParserUpdate(t *testing.T) {
 		assert.Equal(t, "users", updateStmt.Table.TableName)
 		assert.Equal(t, 1, len(updateStmt.SetClauses))
 		assert.Equal(t, "first_name", updateStmt.SetClauses[0].Column.ColName)
-		assert.Equal(t, "Jane", updateStmt.SetClauses[0].Value.ToString())
+		assert.Equal(t, "Jane", ast.ExprString(updateStmt.SetClauses[0].Value))
 		assert.Nil(t, updateStmt.Where)
 	})
 
@@ -48,9 +48,9 @@ func TestParserUpdate(t *testing.T) {
 		assert.Equal(t, "users", updateStmt.Table.TableName)
 		assert.Equal(t, 2, len(updateStmt.SetClauses))
 		assert.Equal(t, "first_name", updateStmt.SetClauses[0].Column.ColName)
-		assert.Equal(t, "Jane", updateStmt.SetClauses[0].Value.ToString())
+		assert.Equal(t, "Jane", ast.ExprString(updateStmt.SetClauses[0].Value))
 		assert.Equal(t, "last_name", updateStmt.SetClauses[1].Column.ColName)
-		assert.Equal(t, "Doe", updateStmt.SetClauses[1].Value.ToString())
+		assert.Equal(t, "Doe", ast.ExprString(updateStmt.SetClauses[1].Value))
 	})
 
 	t.Run("WHERE 句付きの UPDATE 文をパースできる", func(t *testing.T) {
@@ -71,11 +71,11 @@ func TestParserUpdate(t *testing.T) {
 		assert.Equal(t, "users", updateStmt.Table.TableName)
 		assert.Equal(t, 1, len(updateStmt.SetClauses))
 		assert.Equal(t, "first_name", updateStmt.SetClauses[0].Column.ColName)
-		assert.Equal(t, "Jane", updateStmt.SetClauses[0].Value.ToString())
+		assert.Equal(t, "Jane", ast.ExprString(updateStmt.SetClauses[0].Value))
 
 		assert.NotNil(t, updateStmt.Where)
 
-		binaryExpr := updateStmt.Where.Condition
+		binaryExpr := updateStmt.Where.Condition.(*ast.BinaryExpr)
 		assert.Equal(t, "=", binaryExpr.Operator)
 
 		lhsCol, ok := binaryExpr.Left.(*ast.LhsColumn)
@@ -110,7 +110,7 @@ func TestParserUpdate(t *testing.T) {
 		assert.NotNil(t, updateStmt.Where.Condition)
 
 		// AND で結合された式
-		andExpr := updateStmt.Where.Condition
+		andExpr := updateStmt.Where.Condition.(*ast.BinaryExpr)
 		assert.Equal(t, "AND", andExpr.Operator)
 	})
 
@@ -131,7 +131,46 @@ func TestParserUpdate(t *testing.T) {
 
 		assert.Equal(t, 1, len(updateStmt.SetClauses))
 		assert.Equal(t, "age", updateStmt.SetClauses[0].Column.ColName)
-		assert.Equal(t, "30", updateStmt.SetClauses[0].Value.ToString())
+		assert.Equal(t, "30", ast.ExprString(updateStmt.SetClauses[0].Value))
+	})
+
+	t.Run("式を SET 句の値に使用できる", func(t *testing.T) {
+		// GIVEN
+		sql := "UPDATE products SET price = price * 1.1 + 5, name = CONCAT(name, '!') WHERE id = 1;"
+		parser := NewParser()
+
+		// WHEN
+		result, err := parser.Parse(sql)
+
+		// THEN
+		assert.NoError(t, err)
+		updateStmt, ok := result.(*ast.UpdateStmt)
+		assert.True(t, ok)
+
+		assert.Equal(t, 2, len(updateStmt.SetClauses))
+		assert.Equal(t, "price", updateStmt.SetClauses[0].Column.ColName)
+		assert.Equal(t, "(price * 1.1) + 5", ast.ExprString(updateStmt.SetClauses[0].Value))
+		assert.Equal(t, "name", updateStmt.SetClauses[1].Column.ColName)
+		assert.Equal(t, "CONCAT(name, !)", ast.ExprString(updateStmt.SetClauses[1].Value))
+		assert.NotNil(t, updateStmt.Where)
+	})
+
+	t.Run("NULL を SET 句の値に使用し、WHERE 句で IS NOT NULL を使用できる", func(t *testing.T) {
+		// GIVEN
+		sql := "UPDATE users SET email = NULL, name = 'John' WHERE email IS NOT NULL;"
+		parser := NewParser()
+
+		// WHEN
+		result, err := parser.Parse(sql)
+
+		// THEN
+		assert.NoError(t, err)
+		updateStmt, ok := result.(*ast.UpdateStmt)
+		assert.True(t, ok)
+		assert.Equal(t, 2, len(updateStmt.SetClauses))
+		assert.IsType(t, &ast.NullLiteral{}, updateStmt.SetClauses[0].Value)
+		assert.Equal(t, "John", ast.ExprString(updateStmt.SetClauses[1].Value))
+		assert.Equal(t, "IS NOT", updateStmt.Where.Condition.(*ast.BinaryExpr).Operator)
 	})
 
 	t.Run("WHERE 句で OR 演算子を使った UPDATE 文をパースできる", func(t *testing.T) {
@@ -151,7 +190,7 @@ func TestParserUpdate(t *testing.T) {
 
 		assert.NotNil(t, updateStmt.Where)
 
-		orExpr := updateStmt.Where.Condition
+		orExpr := updateStmt.Where.Condition.(*ast.BinaryExpr)
 		assert.Equal(t, "OR", orExpr.Operator)
 	})
 
@@ -171,7 +210,7 @@ func TestParserUpdate(t *testing.T) {
 		assert.True(t, ok)
 
 		// ルートは OR
-		orExpr := updateStmt.Where.Condition
+		orExpr := updateStmt.Where.Condition.(*ast.BinaryExpr)
 		assert.Equal(t, "OR", orExpr.Operator)
 
 		// OR の左辺は a = '1'
@@ -204,7 +243,7 @@ func TestParserUpdate(t *testing.T) {
 
 		assert.NotNil(t, updateStmt.Where)
 
-		binaryExpr := updateStmt.Where.Condition
+		binaryExpr := updateStmt.Where.Condition.(*ast.BinaryExpr)
 
 		rhsLit, ok := binaryExpr.Right.(*ast.RhsLiteral)
 		assert.True(t, ok)
@@ -354,7 +393,7 @@ func TestParserUpdate(t *testing.T) {
 
 		t.Run("SET 句の値の後に不正なシンボルが来た場合", func(t *testing.T) {
 			// GIVEN
-			sql := "UPDATE users SET first_name = 'Jane' > last_name = 'Doe';"
+			sql := "UPDATE users SET first_name = 'Jane') last_name = 'Doe';"
 			parser := NewParser()
 
 			// WHEN
@@ -409,3 +448,61 @@ func TestParserUpdate(t *testing.T) {
 		})
 	})
 }
+
+func TestParserUpdateJoin(t *testing.T) {
+	t.Run("JOIN 句付きの UPDATE 文をパースできる", func(t *testing.T) {
+		// GIVEN
+		sql := "UPDATE orders JOIN customers ON orders.customer_id = customers.id SET orders.status = customers.status WHERE customers.id = 1;"
+		parser := NewParser()
+
+		// WHEN
+		result, err := parser.Parse(sql)
+
+		// THEN
+		assert.NoError(t, err)
+		updateStmt, ok := result.(*ast.UpdateStmt)
+		assert.True(t, ok)
+		assert.Equal(t, "orders", updateStmt.Table.TableName)
+		assert.Equal(t, 1, len(updateStmt.Joins))
+		assert.Equal(t, ast.JoinTypeInner, updateStmt.Joins[0].Type)
+		assert.Equal(t, "customers", updateStmt.Joins[0].Table.TableName)
+		assert.Equal(t, "orders.customer_id = customers.id", ast.ExprString(updateStmt.Joins[0].Condition))
+		assert.Equal(t, ast.ColumnId{TableName: "orders", ColName: "status"}, updateStmt.SetClauses[0].Column)
+		assert.Equal(t, "customers.status", ast.ExprString(updateStmt.SetClauses[0].Value))
+		assert.Equal(t, "customers.id = 1", ast.ExprString(updateStmt.Where.Condition))
+	})
+
+	t.Run("複数の JOIN 句と LEFT JOIN をパースできる", func(t *testing.T) {
+		// GIVEN
+		sql := "UPDATE orders INNER JOIN customers ON orders.customer_id = customers.id AND customers.active = 0 LEFT OUTER JOIN coupons ON orders.coupon_id = coupons.id SET status = 'hold';"
+		parser := NewParser()
+
+		// WHEN
+		result, err := parser.Parse(sql)
+
+		// THEN
+		assert.NoError(t, err)
+		updateStmt, ok := result.(*ast.UpdateStmt)
+		assert.True(t, ok)
+		assert.Equal(t, 2, len(updateStmt.Joins))
+		assert.Equal(t, ast.JoinTypeInner, updateStmt.Joins[0].Type)
+		assert.Equal(t, "(orders.customer_id = customers.id) AND (customers.active = 0)", ast.ExprString(updateStmt.Joins[0].Condition))
+		assert.Equal(t, ast.JoinTypeLeft, updateStmt.Joins[1].Type)
+		assert.Equal(t, "coupons", updateStmt.Joins[1].Table.TableName)
+		assert.Equal(t, ast.ColumnId{ColName: "status"}, updateStmt.SetClauses[0].Column)
+		assert.Nil(t, updateStmt.Where)
+	})
+
+	t.Run("ON 句のない JOIN 句はエラーになる", func(t *testing.T) {
+		// GIVEN
+		sql := "UPDATE orders JOIN customers SET status = 'hold';"
+		parser := NewParser()
+
+		// WHEN
+		result, err := parser.Parse(sql)
+
+		// THEN
+		assert.Nil(t, result)
+		assert.ErrorContains(t, err, "missing ON clause in JOIN")
+	})
+}
@@ -0,0 +1,102 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ren-yamanashi/minesql/internal/ast"
+)
+
+// UseParser は USE 文をパースする
+//
+// 構文: USE db_name;
+type UseParser struct {
+	state        parserState
+	databaseName string
+	err          error
+}
+
+func NewUseParser() *UseParser {
+	return &UseParser{}
+}
+
+func (p *UseParser) getResult() ast.Statement {
+	if p.err != nil {
+		return nil
+	}
+	return &ast.UseStmt{DatabaseName: p.databaseName}
+}
+
+func (p *UseParser) getError() error { return p.err }
+
+func (p *UseParser) finalize() {
+	if p.err != nil {
+		return
+	}
+	if p.state != UseStateEnd {
+		p.err = fmt.Errorf("[parse error] incomplete USE statement")
+	}
+}
+
+func (p *UseParser) onKeyword(word string) {
+	if p.err != nil {
+		return
+	}
+	if p.state == StateInitial && strings.ToUpper(word) == KUse {
+		p.state = UseStateUse
+		return
+	}
+	p.err = fmt.Errorf("[parse error] unexpected keyword %q in USE statement", word)
+}
+
+func (p *UseParser) onIdentifier(ident string) {
+	if p.err != nil {
+		return
+	}
+
+	switch p.state {
+	case UseStateUse:
+		p.databaseName = ident
+		p.state = UseStateEnd
+
+	default:
+		p.err = fmt.Errorf("[parse error] unexpected identifier %q in USE statement", ident)
+	}
+}
+
+func (p *UseParser) onSymbol(symbol string) {
+	if p.err != nil {
+		return
+	}
+
+	switch p.state {
+	case UseStateEnd:
+		if symbol == ";" {
+			return
+		}
+		p.err = fmt.Errorf("[parse error] expected ';' at end of USE statement, got %q", symbol)
+
+	default:
+		p.err = fmt.Errorf("[parse error] unexpected symbol %q in USE statement", symbol)
+	}
+}
+
+func (p *UseParser) onString(value string) {
+	if p.err != nil {
+		return
+	}
+	p.err = fmt.Errorf("[parse error] unexpected string %q in USE statement", value)
+}
+
+func (p *UseParser) onNumber(_ string) {
+	if p.err != nil {
+		return
+	}
+	p.err = fmt.Errorf("[parse error] unexpected number in USE statement")
+}
+
+func (p *UseParser) onComment(_ string) {}
+
+func (p *UseParser) onError(err error) {
+	p.err = err
+}
@@ -0,0 +1,66 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/ren-yamanashi/minesql/internal/ast"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParserUse(t *testing.T) {
+	t.Run("USE 文をパースできる", func(t *testing.T) {
+		// GIVEN
+		sql := "USE app;"
+		parser := NewParser()
+
+		// WHEN
+		result, err := parser.Parse(sql)
+
+		// THEN
+		assert.NoError(t, err)
+		stmt, ok := result.(*ast.UseStmt)
+		assert.True(t, ok)
+		assert.Equal(t, "app", stmt.DatabaseName)
+	})
+
+	t.Run("末尾の ';' を省略できる", func(t *testing.T) {
+		// GIVEN
+		sql := "use app"
+		parser := NewParser()
+
+		// WHEN
+		result, err := parser.Parse(sql)
+
+		// THEN
+		assert.NoError(t, err)
+		stmt, ok := result.(*ast.UseStmt)
+		assert.True(t, ok)
+		assert.Equal(t, "app", stmt.DatabaseName)
+	})
+
+	t.Run("不正な USE 文でエラーになる", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			sql      string
+			expected string
+		}{
+			{"データベース名がない場合", "USE;", "unexpected symbol \";\" in USE statement"},
+			{"USE のみの場合", "USE", "incomplete USE statement"},
+			{"複数のデータベース名を指定した場合", "USE a, b;", "expected ';' at end of USE statement"},
+			{"データベース名が文字列の場合", "USE 'app';", "unexpected string \"app\" in USE statement"},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				// GIVEN
+				parser := NewParser()
+
+				// WHEN
+				result, err := parser.Parse(tt.sql)
+
+				// THEN
+				assert.Nil(t, result)
+				assert.ErrorContains(t, err, tt.expected)
+			})
+		}
+	})
+}
@@ -2,15 +2,33 @@ package parser
 
 import (
 	"errors"
+	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/ren-yamanashi/minesql/internal/ast"
 )
 
+const (
+	defaultDecimalPrecision = 10 // DECIMAL の精度を省略した場合のデフォルト値
+	maxDecimalPrecision     = 18 // DECIMAL で指定できる最大の精度
+)
+
+// dataTypes はデータ型のキーワードと AST のデータ型の対応
+var dataTypes = map[string]ast.DataType{
+	KVarchar:  ast.DataTypeVarchar,
+	KInt:      ast.DataTypeInt,
+	KBigint:   ast.DataTypeBigInt,
+	KDecimal:  ast.DataTypeDecimal,
+	KDate:     ast.DataTypeDate,
+	KDatetime: ast.DataTypeDatetime,
+}
+
 type ColumnDefParser struct {
-	state  parserState    // 現在のステート
-	colDef *ast.ColumnDef // 構築中のカラム定義
-	err    error          // エラー情報
+	state   parserState    // 現在のステート
+	colDef  *ast.ColumnDef // 構築中のカラム定義
+	defExpr *ExprDefParser // DEFAULT (expr) の式のパーサー (DEFAULT (expr) を解析中でない場合は nil)
+	err     error          // エラー情報
 }
 
 func NewColumnDefParser(colName string) *ColumnDefParser {
@@ -27,6 +45,15 @@ func (cp *ColumnDefParser) finalize() error {
 	if cp.colDef.DataType == "" {
 		return errors.New("[parse error] data type is required for column: " + cp.colDef.ColName)
 	}
+	if cp.inTypeArgs() {
+		return errors.New("[parse error] incomplete data type arguments for column: " + cp.colDef.ColName)
+	}
+	if cp.state == CreateStateColNot {
+		return errors.New("[parse error] expected NULL after NOT for column: " + cp.colDef.ColName)
+	}
+	if cp.inDefault() {
+		return errors.New("[parse error] incomplete DEFAULT value for column: " + cp.colDef.ColName)
+	}
 	return nil
 }
 
@@ -41,23 +68,198 @@ func (cp *ColumnDefParser) onKeyword(word string) {
 
 	upper := strings.ToUpper(word)
 	switch cp.state {
+	case CreateStateColDefaultExpr:
+		cp.defExpr.onKeyword(word)
+		return
+
+	case CreateStateColDefault:
+		if upper != KNull {
+			cp.setError(errors.New("[parse error] unexpected keyword in DEFAULT value: " + word))
+			return
+		}
+		cp.setDefault(ast.NewNullLiteral(), KNull)
+		return
+
 	case CreateStateColDef:
-		// 現状、カラムのデータ型は VARCHAR のみ対応
-		if upper == KVarchar {
-			cp.colDef.DataType = ast.DataTypeVarchar
-			cp.state = CreateStateColWaitDefEnd
+		dataType, ok := dataTypes[upper]
+		if !ok {
+			cp.setError(errors.New("[parse error] unsupported data type: " + word))
 			return
 		}
-		cp.setError(errors.New("[parse error] only VARCHAR is supported, got: " + word))
+		cp.colDef.DataType = dataType
+		if dataType == ast.DataTypeDecimal {
+			// DECIMAL の精度とスケールを省略した場合は DECIMAL(10, 0) として扱う
+			cp.colDef.Precision = defaultDecimalPrecision
+		}
+		cp.state = CreateStateColWaitDefEnd
 		return
 
 	case CreateStateColWaitDefEnd:
-		// 型が決まった後にさらにキーワードが来た場合 (e.g. "col1 VARCHAR INT")
-		cp.setError(errors.New("[parse error] unexpected keyword after data type"))
+		switch upper {
+		case KNot:
+			cp.state = CreateStateColNot
+		case KNull:
+			// NULL を明示した場合は NULL を許容する (MySQL と同様に後から指定したものを優先する)
+			cp.colDef.NotNull = false
+		case KAutoIncrement:
+			cp.colDef.AutoIncrement = true
+		case KDefault:
+			cp.state = CreateStateColDefault
+		default:
+			// 型が決まった後にさらにキーワードが来た場合 (e.g. "col1 VARCHAR INT")
+			cp.setError(errors.New("[parse error] unexpected keyword after data type"))
+		}
+		return
+
+	case CreateStateColNot:
+		if upper != KNull {
+			cp.setError(errors.New("[parse error] expected NULL after NOT, got: " + word))
+			return
+		}
+		cp.colDef.NotNull = true
+		cp.state = CreateStateColWaitDefEnd
 		return
 	}
 }
 
+// acceptsSymbol はシンボルをこのサブパーサーで処理すべきかどうかを返す
+//
+// データ型の引数 (`DECIMAL(10, 2)` の "(", ",", ")") と DEFAULT (expr) の式はカラム定義の一部として扱い、
+// それ以外の "," や ")" はカラム定義の区切りとして親パーサーが処理する
+func (cp *ColumnDefParser) acceptsSymbol(symbol string) bool {
+	if cp.inTypeArgs() || cp.inDefault() {
+		return true
+	}
+	return cp.state == CreateStateColWaitDefEnd &&
+		cp.colDef.DataType == ast.DataTypeDecimal &&
+		symbol == string(SLeftParen)
+}
+
+func (cp *ColumnDefParser) onSymbol(symbol string) {
+	if cp.err != nil {
+		return
+	}
+
+	switch {
+	case cp.state == CreateStateColDefault && symbol == string(SLeftParen):
+		cp.defExpr = NewExprDefParser()
+		cp.state = CreateStateColDefaultExpr
+	case cp.state == CreateStateColDefault:
+		cp.setError(errors.New("[parse error] unexpected symbol in DEFAULT value: " + symbol))
+	case cp.state == CreateStateColDefaultExpr:
+		// DEFAULT (expr) の外側の ")" で式を確定する
+		if symbol == string(SRightParen) && !cp.defExpr.inNested() {
+			expr, text, err := cp.defExpr.finalize(KDefault)
+			if err != nil {
+				cp.setError(err)
+				return
+			}
+			cp.defExpr = nil
+			cp.setDefault(expr, text)
+			return
+		}
+		cp.defExpr.onSymbol(symbol)
+	case cp.state == CreateStateColWaitDefEnd && symbol == string(SLeftParen):
+		cp.state = CreateStateColTypeArgPrecision
+	case cp.state == CreateStateColTypeArgSeparator && symbol == string(SComma):
+		cp.state = CreateStateColTypeArgScale
+	case (cp.state == CreateStateColTypeArgSeparator || cp.state == CreateStateColTypeArgEnd) && symbol == string(SRightParen):
+		cp.state = CreateStateColWaitDefEnd
+		cp.validateDecimal()
+	default:
+		cp.setError(errors.New("[parse error] unexpected symbol in data type: " + symbol))
+	}
+}
+
+func (cp *ColumnDefParser) onNumber(num string) {
+	if cp.err != nil {
+		return
+	}
+
+	switch cp.state {
+	case CreateStateColDefault:
+		cp.setDefault(ast.NewNumberLiteral(num), num)
+		return
+	case CreateStateColDefaultExpr:
+		cp.defExpr.onNumber(num)
+		return
+	}
+
+	n, err := strconv.Atoi(num)
+	if err != nil || n < 0 {
+		cp.setError(errors.New("[parse error] invalid data type argument: " + num))
+		return
+	}
+	switch cp.state {
+	case CreateStateColTypeArgPrecision:
+		cp.colDef.Precision = n
+		cp.state = CreateStateColTypeArgSeparator
+	case CreateStateColTypeArgScale:
+		cp.colDef.Scale = n
+		cp.state = CreateStateColTypeArgEnd
+	default:
+		cp.setError(errors.New("[parse error] unexpected number: " + num))
+	}
+}
+
+func (cp *ColumnDefParser) onIdentifier(ident string) {
+	if cp.err != nil {
+		return
+	}
+	if cp.state == CreateStateColDefaultExpr {
+		cp.defExpr.onIdentifier(ident)
+		return
+	}
+	cp.setError(errors.New("[parse error] unexpected identifier: " + ident))
+}
+
+func (cp *ColumnDefParser) onString(value string) {
+	if cp.err != nil {
+		return
+	}
+	switch cp.state {
+	case CreateStateColDefault:
+		cp.setDefault(ast.NewStringLiteral(value), "'"+value+"'")
+	case CreateStateColDefaultExpr:
+		cp.defExpr.onString(value)
+	default:
+		cp.setError(errors.New("[parse error] unexpected string: " + value))
+	}
+}
+
+// setDefault は DEFAULT 句の式を設定し、カラム定義の続きの解析に戻る
+func (cp *ColumnDefParser) setDefault(expr ast.Expr, text string) {
+	cp.colDef.Default = expr
+	cp.colDef.DefaultText = text
+	cp.state = CreateStateColWaitDefEnd
+}
+
+// inDefault は DEFAULT 句の値を解析中かどうかを返す
+func (cp *ColumnDefParser) inDefault() bool {
+	return cp.state == CreateStateColDefault || cp.state == CreateStateColDefaultExpr
+}
+
+// inTypeArgs はデータ型の引数 ("(" から ")" まで) を解析中かどうかを返す
+func (cp *ColumnDefParser) inTypeArgs() bool {
+	switch cp.state {
+	case CreateStateColTypeArgPrecision, CreateStateColTypeArgSeparator, CreateStateColTypeArgScale, CreateStateColTypeArgEnd:
+		return true
+	default:
+		return false
+	}
+}
+
+// validateDecimal は DECIMAL の精度とスケールの範囲を検証する
+func (cp *ColumnDefParser) validateDecimal() {
+	if cp.colDef.Precision < 1 || cp.colDef.Precision > maxDecimalPrecision {
+		cp.setError(fmt.Errorf("[parse error] DECIMAL precision must be between 1 and %d", maxDecimalPrecision))
+		return
+	}
+	if cp.colDef.Scale > cp.colDef.Precision {
+		cp.setError(errors.New("[parse error] DECIMAL scale must not be greater than precision"))
+	}
+}
+
 func (cp *ColumnDefParser) setError(err error) {
 	if cp.err == nil {
 		cp.err = err
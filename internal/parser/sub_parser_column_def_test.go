@@ -55,15 +55,15 @@ func TestColumnDefParser(t *testing.T) {
 
 	t.Run("サポートされていないデータ型の場合、エラーを返す", func(t *testing.T) {
 		// GIVEN
-		cp := NewColumnDefParser("age")
+		cp := NewColumnDefParser("data")
 
 		// WHEN
-		cp.onKeyword("INT")
+		cp.onKeyword("BLOB")
 		err := cp.finalize()
 
 		// THEN
 		assert.Error(t, err)
-		assert.Contains(t, err.Error(), "only VARCHAR is supported")
+		assert.Contains(t, err.Error(), "unsupported data type: BLOB")
 	})
 
 	t.Run("データ型の後にさらにキーワードが来た場合、エラーを返す", func(t *testing.T) {
@@ -72,7 +72,7 @@ func TestColumnDefParser(t *testing.T) {
 
 		// WHEN
 		cp.onKeyword("VARCHAR")
-		cp.onKeyword("NOT")
+		cp.onKeyword("INT")
 		err := cp.finalize()
 
 		// THEN
@@ -80,18 +80,290 @@ func TestColumnDefParser(t *testing.T) {
 		assert.Contains(t, err.Error(), "unexpected keyword after data type")
 	})
 
+	t.Run("NOT NULL を指定できる", func(t *testing.T) {
+		// GIVEN
+		cp := NewColumnDefParser("id")
+
+		// WHEN
+		cp.onKeyword("INT")
+		cp.onKeyword("NOT")
+		cp.onKeyword("NULL")
+		err := cp.finalize()
+
+		// THEN
+		assert.NoError(t, err)
+		assert.True(t, cp.getDef().(*ast.ColumnDef).NotNull)
+	})
+
+	t.Run("NULL を指定した場合は NULL を許容する", func(t *testing.T) {
+		// GIVEN
+		cp := NewColumnDefParser("name")
+
+		// WHEN
+		cp.onKeyword("VARCHAR")
+		cp.onKeyword("NULL")
+		err := cp.finalize()
+
+		// THEN
+		assert.NoError(t, err)
+		assert.False(t, cp.getDef().(*ast.ColumnDef).NotNull)
+	})
+
+	t.Run("AUTO_INCREMENT を指定できる", func(t *testing.T) {
+		// GIVEN
+		cp := NewColumnDefParser("id")
+
+		// WHEN
+		cp.onKeyword("BIGINT")
+		cp.onKeyword("NOT")
+		cp.onKeyword("NULL")
+		cp.onKeyword("AUTO_INCREMENT")
+		err := cp.finalize()
+
+		// THEN
+		assert.NoError(t, err)
+		assert.Equal(t, &ast.ColumnDef{ColName: "id", DataType: ast.DataTypeBigInt, NotNull: true, AutoIncrement: true}, cp.getDef())
+	})
+
+	t.Run("NOT の後に NULL 以外のキーワードが来た場合、エラーを返す", func(t *testing.T) {
+		// GIVEN
+		cp := NewColumnDefParser("id")
+
+		// WHEN
+		cp.onKeyword("INT")
+		cp.onKeyword("NOT")
+		cp.onKeyword("INT")
+		err := cp.finalize()
+
+		// THEN
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "expected NULL after NOT")
+	})
+
+	t.Run("NOT の後に NULL がない場合、エラーを返す", func(t *testing.T) {
+		// GIVEN
+		cp := NewColumnDefParser("id")
+
+		// WHEN
+		cp.onKeyword("INT")
+		cp.onKeyword("NOT")
+		err := cp.finalize()
+
+		// THEN
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "expected NULL after NOT")
+	})
+
 	t.Run("エラー発生後のキーワードは無視される", func(t *testing.T) {
 		// GIVEN
 		cp := NewColumnDefParser("id")
 
 		// WHEN
-		cp.onKeyword("INT")     // エラー発生
+		cp.onKeyword("BLOB")    // エラー発生
 		cp.onKeyword("VARCHAR") // 無視される
 
 		err := cp.finalize()
 
 		// THEN
 		assert.Error(t, err)
-		assert.Contains(t, err.Error(), "only VARCHAR is supported")
+		assert.Contains(t, err.Error(), "unsupported data type: BLOB")
+	})
+
+	t.Run("INT, BIGINT, DATE, DATETIME カラムをパースできる", func(t *testing.T) {
+		tests := []struct {
+			keyword  string
+			expected ast.DataType
+		}{
+			{"INT", ast.DataTypeInt},
+			{"bigint", ast.DataTypeBigInt},
+			{"DATE", ast.DataTypeDate},
+			{"DATETIME", ast.DataTypeDatetime},
+		}
+		for _, tt := range tests {
+			// GIVEN
+			cp := NewColumnDefParser("col")
+
+			// WHEN
+			cp.onKeyword(tt.keyword)
+			err := cp.finalize()
+
+			// THEN
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, cp.getDef().(*ast.ColumnDef).DataType)
+		}
+	})
+
+	t.Run("DECIMAL の精度とスケールをパースできる", func(t *testing.T) {
+		// GIVEN
+		cp := NewColumnDefParser("price")
+
+		// WHEN: DECIMAL(10, 2)
+		cp.onKeyword("DECIMAL")
+		cp.onSymbol("(")
+		cp.onNumber("10")
+		cp.onSymbol(",")
+		cp.onNumber("2")
+		cp.onSymbol(")")
+		err := cp.finalize()
+
+		// THEN
+		assert.NoError(t, err)
+		colDef := cp.getDef().(*ast.ColumnDef)
+		assert.Equal(t, ast.DataTypeDecimal, colDef.DataType)
+		assert.Equal(t, 10, colDef.Precision)
+		assert.Equal(t, 2, colDef.Scale)
+	})
+
+	t.Run("DECIMAL の引数を省略した場合、DECIMAL(10, 0) になる", func(t *testing.T) {
+		// GIVEN
+		cp := NewColumnDefParser("amount")
+
+		// WHEN
+		cp.onKeyword("DECIMAL")
+		err := cp.finalize()
+
+		// THEN
+		assert.NoError(t, err)
+		colDef := cp.getDef().(*ast.ColumnDef)
+		assert.Equal(t, 10, colDef.Precision)
+		assert.Equal(t, 0, colDef.Scale)
+	})
+
+	t.Run("DECIMAL のスケールが精度を超える場合、エラーを返す", func(t *testing.T) {
+		// GIVEN
+		cp := NewColumnDefParser("price")
+
+		// WHEN: DECIMAL(2, 5)
+		cp.onKeyword("DECIMAL")
+		cp.onSymbol("(")
+		cp.onNumber("2")
+		cp.onSymbol(",")
+		cp.onNumber("5")
+		cp.onSymbol(")")
+		err := cp.finalize()
+
+		// THEN
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "scale must not be greater than precision")
+	})
+
+	t.Run("DECIMAL の精度が上限を超える場合、エラーを返す", func(t *testing.T) {
+		// GIVEN
+		cp := NewColumnDefParser("price")
+
+		// WHEN: DECIMAL(19)
+		cp.onKeyword("DECIMAL")
+		cp.onSymbol("(")
+		cp.onNumber("19")
+		cp.onSymbol(")")
+		err := cp.finalize()
+
+		// THEN
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "precision must be between 1 and 18")
+	})
+
+	t.Run("DEFAULT に数値・文字列・NULL を指定できる", func(t *testing.T) {
+		tests := []struct {
+			name  string
+			feed  func(cp *ColumnDefParser)
+			value ast.Expr
+			text  string
+		}{
+			{name: "数値", feed: func(cp *ColumnDefParser) { cp.onNumber("-1") }, value: ast.NewNumberLiteral("-1"), text: "-1"},
+			{name: "文字列", feed: func(cp *ColumnDefParser) { cp.onString("none") }, value: ast.NewStringLiteral("none"), text: "'none'"},
+			{name: "NULL", feed: func(cp *ColumnDefParser) { cp.onKeyword("NULL") }, value: ast.NewNullLiteral(), text: "NULL"},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				// GIVEN
+				cp := NewColumnDefParser("col")
+
+				// WHEN
+				cp.onKeyword("VARCHAR")
+				cp.onKeyword("DEFAULT")
+				tt.feed(cp)
+				err := cp.finalize()
+
+				// THEN
+				assert.NoError(t, err)
+				colDef := cp.getDef().(*ast.ColumnDef)
+				assert.Equal(t, tt.value, colDef.Default)
+				assert.Equal(t, tt.text, colDef.DefaultText)
+			})
+		}
+	})
+
+	t.Run("DEFAULT (expr) で式を指定できる", func(t *testing.T) {
+		// GIVEN
+		cp := NewColumnDefParser("total")
+
+		// WHEN: INT DEFAULT (1 + 2) NOT NULL
+		cp.onKeyword("INT")
+		cp.onKeyword("DEFAULT")
+		assert.True(t, cp.acceptsSymbol("("))
+		cp.onSymbol("(")
+		cp.onNumber("1")
+		assert.True(t, cp.acceptsSymbol("+"))
+		cp.onSymbol("+")
+		cp.onNumber("2")
+		assert.True(t, cp.acceptsSymbol(")"))
+		cp.onSymbol(")")
+		cp.onKeyword("NOT")
+		cp.onKeyword("NULL")
+		err := cp.finalize()
+
+		// THEN
+		assert.NoError(t, err)
+		colDef := cp.getDef().(*ast.ColumnDef)
+		assert.Equal(t, "1 + 2", colDef.DefaultText)
+		assert.Equal(t, "1 + 2", ast.ExprString(colDef.Default))
+		assert.True(t, colDef.NotNull)
+		assert.False(t, cp.acceptsSymbol(","))
+	})
+
+	t.Run("DEFAULT の値がない場合、エラーを返す", func(t *testing.T) {
+		// GIVEN
+		cp := NewColumnDefParser("col")
+
+		// WHEN
+		cp.onKeyword("INT")
+		cp.onKeyword("DEFAULT")
+		err := cp.finalize()
+
+		// THEN
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "incomplete DEFAULT value for column: col")
+	})
+
+	t.Run("DEFAULT (expr) の閉じ括弧がない場合、エラーを返す", func(t *testing.T) {
+		// GIVEN
+		cp := NewColumnDefParser("col")
+
+		// WHEN
+		cp.onKeyword("INT")
+		cp.onKeyword("DEFAULT")
+		cp.onSymbol("(")
+		cp.onNumber("1")
+		err := cp.finalize()
+
+		// THEN
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "incomplete DEFAULT value for column: col")
+	})
+
+	t.Run("DEFAULT の後にカラム名が来た場合、エラーを返す", func(t *testing.T) {
+		// GIVEN
+		cp := NewColumnDefParser("col")
+
+		// WHEN
+		cp.onKeyword("INT")
+		cp.onKeyword("DEFAULT")
+		cp.onIdentifier("other")
+		err := cp.finalize()
+
+		// THEN
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "unexpected identifier: other")
 	})
 }
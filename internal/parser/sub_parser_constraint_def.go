@@ -2,6 +2,7 @@ package parser
 
 import (
 	"errors"
+	"fmt"
 	"strings"
 
 	"github.com/ren-yamanashi/minesql/internal/ast"
@@ -16,17 +17,21 @@ const (
 	constraintKindUniqueKey                       // UNIQUE KEY
 	constraintKindKey                             // KEY (非ユニーク)
 	constraintKindFK                              // FOREIGN KEY
+	constraintKindCheck                           // CHECK
 )
 
 type ConstraintDefParser struct {
-	state  parserState                 // 現在のステート
-	kind   constraintKind              // 制約の種類
-	pkDef  ast.ConstraintPrimaryKeyDef // 生成される PK 定義
-	ukDef  ast.ConstraintUniqueKeyDef  // 生成される UK 定義
-	keyDef ast.ConstraintKeyDef        // 生成される KEY 定義 (非ユニーク)
-	fkDef  ast.ConstraintForeignKeyDef // 生成される FK 定義
-	done   bool                        // パースが完了したかどうか (PK/UK/KEY ではカラムリストの ")" 受信時、FK では参照先カラムの ")" 受信時に true になる)
-	err    error                       // エラー情報
+	state      parserState                 // 現在のステート
+	kind       constraintKind              // 制約の種類
+	pkDef      ast.ConstraintPrimaryKeyDef // 生成される PK 定義
+	ukDef      ast.ConstraintUniqueKeyDef  // 生成される UK 定義
+	keyDef     ast.ConstraintKeyDef        // 生成される KEY 定義 (非ユニーク)
+	fkDef      ast.ConstraintForeignKeyDef // 生成される FK 定義
+	checkDef   ast.ConstraintCheckDef      // 生成される CHECK 定義
+	checkExpr  *ExprDefParser              // CHECK 制約の条件式のパーサー (条件式を解析中でない場合は nil)
+	fkOnUpdate bool                        // 解析中の FK の参照アクションが ON UPDATE 句のものかどうか (false の場合は ON DELETE 句)
+	done       bool                        // パースが完了したかどうか (PK/UK/KEY ではカラムリストの ")" 受信時、CHECK では条件式の ")" 受信時に true になる。FK は参照アクションが続く可能性があるため、親パーサーが区切り文字で終了させる)
+	err        error                       // エラー情報
 }
 
 func NewConstraintDefParser() *ConstraintDefParser {
@@ -52,32 +57,40 @@ func (cp *ConstraintDefParser) finalize() error {
 		}
 	}
 
+	// CHECK 制約はカラムリストを持たない
+	if cp.kind == constraintKindCheck {
+		if cp.checkDef.Expr == nil {
+			return errors.New("[parse error] CHECK constraint requires a condition")
+		}
+		return nil
+	}
+
 	// カラムが 1 つも指定されていない場合はエラー
 	colCount := 0
 	switch cp.kind {
 	case constraintKindPK:
 		colCount = len(cp.pkDef.Columns)
 	case constraintKindUniqueKey:
-		if cp.ukDef.Column.ColName != "" {
-			colCount = 1
-		}
+		colCount = len(cp.ukDef.Columns)
 	case constraintKindKey:
-		if cp.keyDef.Column.ColName != "" {
-			colCount = 1
-		}
+		colCount = len(cp.keyDef.Columns)
 	case constraintKindFK:
-		if cp.fkDef.Column.ColName != "" {
-			colCount = 1
-		}
+		colCount = len(cp.fkDef.Columns)
 		if cp.fkDef.KeyName == "" {
 			return errors.New("[parse error] foreign key name is required")
 		}
 		if cp.fkDef.RefTable == "" {
 			return errors.New("[parse error] REFERENCES table is required")
 		}
-		if cp.fkDef.RefColumn == "" {
+		if len(cp.fkDef.RefColumns) == 0 {
 			return errors.New("[parse error] REFERENCES column is required")
 		}
+		if colCount != 0 && colCount != len(cp.fkDef.RefColumns) {
+			return fmt.Errorf("[parse error] foreign key has %d columns but REFERENCES has %d columns", colCount, len(cp.fkDef.RefColumns))
+		}
+		if cp.state == CreateStateConstraintFKOn || cp.state == CreateStateConstraintFKActionType || cp.state == CreateStateConstraintFKSetNull {
+			return errors.New("[parse error] incomplete ON DELETE / ON UPDATE clause")
+		}
 	}
 	if colCount == 0 {
 		return errors.New("[parse error] constraint definition requires at least one column")
@@ -96,6 +109,8 @@ func (cp *ConstraintDefParser) getDef() ast.Definition {
 		return &cp.keyDef
 	case constraintKindFK:
 		return &cp.fkDef
+	case constraintKindCheck:
+		return &cp.checkDef
 	default:
 		return nil
 	}
@@ -107,7 +122,12 @@ func (cp *ConstraintDefParser) onKeyword(word string) {
 	}
 
 	upper := strings.ToUpper(word)
-	// 開始時の PRIMARY / UNIQUE / KEY / FOREIGN 判定
+	if cp.state == CreateStateConstraintCheckExpr {
+		cp.checkExpr.onKeyword(word)
+		return
+	}
+
+	// 開始時の PRIMARY / UNIQUE / KEY / FOREIGN / CONSTRAINT / CHECK 判定
 	if cp.kind == constraintKindUnset {
 		switch upper {
 		case KPrimary:
@@ -128,8 +148,17 @@ func (cp *ConstraintDefParser) onKeyword(word string) {
 			cp.kind = constraintKindFK
 			cp.state = CreateStateConstraint
 			return
+		case KConstraint:
+			// CONSTRAINT chk_name CHECK (expr) の形式 (CONSTRAINT で名前を付けられるのは CHECK 制約のみ)
+			cp.kind = constraintKindCheck
+			cp.state = CreateStateConstraintName
+			return
+		case KCheck:
+			cp.kind = constraintKindCheck
+			cp.state = CreateStateConstraintCheckOpen
+			return
 		default:
-			cp.setError(errors.New("[parse error] expected 'PRIMARY', 'UNIQUE', 'KEY', or 'FOREIGN', got: " + word))
+			cp.setError(errors.New("[parse error] expected 'PRIMARY', 'UNIQUE', 'KEY', 'FOREIGN', 'CONSTRAINT' or 'CHECK', got: " + word))
 			return
 		}
 	}
@@ -148,6 +177,16 @@ func (cp *ConstraintDefParser) onKeyword(word string) {
 		return
 	}
 
+	// CHECK キーワードの処理 (CONSTRAINT chk_name の後)
+	if cp.state == CreateStateConstraintCheck {
+		if upper == KCheck {
+			cp.state = CreateStateConstraintCheckOpen
+			return
+		}
+		cp.setError(errors.New("[parse error] expected 'CHECK', got: " + word))
+		return
+	}
+
 	// REFERENCES キーワードの処理 (FK の参照先指定)
 	if cp.state == CreateStateConstraintFKReferences {
 		if upper == KReferences {
@@ -158,9 +197,69 @@ func (cp *ConstraintDefParser) onKeyword(word string) {
 		return
 	}
 
+	// ON DELETE / ON UPDATE 句の処理 (FK の参照アクション指定)
+	switch cp.state {
+	case CreateStateConstraintFKAction:
+		if upper == KOn {
+			cp.state = CreateStateConstraintFKOn
+			return
+		}
+		cp.setError(errors.New("[parse error] expected 'ON', got: " + word))
+		return
+	case CreateStateConstraintFKOn:
+		switch upper {
+		case KDelete:
+			if cp.fkDef.OnDelete != "" {
+				cp.setError(errors.New("[parse error] duplicate ON DELETE clause"))
+				return
+			}
+			cp.fkOnUpdate = false
+		case KUpdate:
+			if cp.fkDef.OnUpdate != "" {
+				cp.setError(errors.New("[parse error] duplicate ON UPDATE clause"))
+				return
+			}
+			cp.fkOnUpdate = true
+		default:
+			cp.setError(errors.New("[parse error] expected 'DELETE' or 'UPDATE' after 'ON', got: " + word))
+			return
+		}
+		cp.state = CreateStateConstraintFKActionType
+		return
+	case CreateStateConstraintFKActionType:
+		switch upper {
+		case KCascade:
+			cp.setFKAction(ast.ReferentialActionCascade)
+		case KRestrict:
+			cp.setFKAction(ast.ReferentialActionRestrict)
+		case KSet:
+			cp.state = CreateStateConstraintFKSetNull
+		default:
+			cp.setError(errors.New("[parse error] expected 'CASCADE', 'RESTRICT' or 'SET NULL', got: " + word))
+		}
+		return
+	case CreateStateConstraintFKSetNull:
+		if upper == KNull {
+			cp.setFKAction(ast.ReferentialActionSetNull)
+			return
+		}
+		cp.setError(errors.New("[parse error] expected 'NULL' after 'SET', got: " + word))
+		return
+	}
+
 	cp.setError(errors.New("[parse error] unexpected keyword in constraint: " + word))
 }
 
+// setFKAction は解析中の ON DELETE / ON UPDATE 句に参照アクションを設定し、次の句を待つ状態に戻す
+func (cp *ConstraintDefParser) setFKAction(action ast.ReferentialAction) {
+	if cp.fkOnUpdate {
+		cp.fkDef.OnUpdate = action
+	} else {
+		cp.fkDef.OnDelete = action
+	}
+	cp.state = CreateStateConstraintFKAction
+}
+
 func (cp *ConstraintDefParser) onIdentifier(ident string) {
 	if cp.err != nil {
 		return
@@ -195,9 +294,9 @@ func (cp *ConstraintDefParser) onIdentifier(ident string) {
 		case constraintKindPK:
 			cp.pkDef.Columns = append(cp.pkDef.Columns, colId)
 		case constraintKindUniqueKey:
-			cp.ukDef.Column = colId
+			cp.ukDef.Columns = append(cp.ukDef.Columns, colId)
 		case constraintKindKey:
-			cp.keyDef.Column = colId
+			cp.keyDef.Columns = append(cp.keyDef.Columns, colId)
 		}
 		cp.state = CreateStateConstraintWaitSeparator
 		return
@@ -208,7 +307,7 @@ func (cp *ConstraintDefParser) onIdentifier(ident string) {
 		return
 
 	case CreateStateConstraintFKColName:
-		cp.fkDef.Column = *ast.NewColumnId(ident)
+		cp.fkDef.Columns = append(cp.fkDef.Columns, *ast.NewColumnId(ident))
 		cp.state = CreateStateConstraintFKColEnd
 		return
 
@@ -218,9 +317,18 @@ func (cp *ConstraintDefParser) onIdentifier(ident string) {
 		return
 
 	case CreateStateConstraintFKRefColName:
-		cp.fkDef.RefColumn = ident
+		cp.fkDef.RefColumns = append(cp.fkDef.RefColumns, ident)
 		cp.state = CreateStateConstraintFKRefColEnd
 		return
+
+	case CreateStateConstraintName:
+		cp.checkDef.KeyName = ident
+		cp.state = CreateStateConstraintCheck
+		return
+
+	case CreateStateConstraintCheckExpr:
+		cp.checkExpr.onIdentifier(ident)
+		return
 	}
 
 	cp.setError(errors.New("[parse error] unexpected identifier: " + ident))
@@ -257,6 +365,10 @@ func (cp *ConstraintDefParser) onSymbol(symbol string) {
 			return
 		}
 	case CreateStateConstraintFKColEnd:
+		if symbol == string(SComma) {
+			cp.state = CreateStateConstraintFKColName
+			return
+		}
 		if symbol == string(SRightParen) {
 			cp.state = CreateStateConstraintFKReferences
 			return
@@ -267,14 +379,80 @@ func (cp *ConstraintDefParser) onSymbol(symbol string) {
 			return
 		}
 	case CreateStateConstraintFKRefColEnd:
+		if symbol == string(SComma) {
+			cp.state = CreateStateConstraintFKRefColName
+			return
+		}
+		// 参照先カラムリストの後には ON DELETE / ON UPDATE 句が続く可能性がある
 		if symbol == string(SRightParen) {
+			cp.state = CreateStateConstraintFKAction
+			return
+		}
+
+	// CHECK ステートのシンボル処理
+	case CreateStateConstraintCheckOpen:
+		if symbol == string(SLeftParen) {
+			cp.checkExpr = NewExprDefParser()
+			cp.state = CreateStateConstraintCheckExpr
+			return
+		}
+	case CreateStateConstraintCheckExpr:
+		// CHECK (expr) の外側の ")" で条件式を確定する
+		if symbol == string(SRightParen) && !cp.checkExpr.inNested() {
+			expr, text, err := cp.checkExpr.finalize(KCheck)
+			if err != nil {
+				cp.setError(err)
+				return
+			}
+			cp.checkDef.Expr = expr
+			cp.checkDef.ExprText = text
+			cp.checkExpr = nil
+			cp.state = CreateStateEnd
 			cp.done = true
 			return
 		}
+		cp.checkExpr.onSymbol(symbol)
+		return
 	}
 	cp.setError(errors.New("[parse error] unexpected symbol in constraint: " + symbol))
 }
 
+func (cp *ConstraintDefParser) onString(value string) {
+	if cp.err != nil {
+		return
+	}
+	if cp.state == CreateStateConstraintCheckExpr {
+		cp.checkExpr.onString(value)
+		return
+	}
+	cp.setError(errors.New("[parse error] unexpected string in constraint: " + value))
+}
+
+func (cp *ConstraintDefParser) onNumber(num string) {
+	if cp.err != nil {
+		return
+	}
+	if cp.state == CreateStateConstraintCheckExpr {
+		cp.checkExpr.onNumber(num)
+		return
+	}
+	cp.setError(errors.New("[parse error] unexpected number in constraint: " + num))
+}
+
+// acceptsSymbol はシンボルをこのサブパーサーで処理すべきかどうかを返す
+//
+// CHECK 制約の条件式の中の記号 (演算子や "," を含む) はすべて条件式の一部として扱う
+func (cp *ConstraintDefParser) acceptsSymbol(_ string) bool {
+	return cp.state == CreateStateConstraintCheckExpr
+}
+
+// awaitsFKAction は FK の参照先カラムリストの解析が終わり、ON DELETE / ON UPDATE 句を待っているかどうかを返す
+//
+// この状態で受け取った "," や ")" などの区切り文字は、親パーサーが制約定義の終了として扱う
+func (cp *ConstraintDefParser) awaitsFKAction() bool {
+	return cp.state == CreateStateConstraintFKAction
+}
+
 func (cp *ConstraintDefParser) setError(err error) {
 	if cp.err == nil {
 		cp.err = err
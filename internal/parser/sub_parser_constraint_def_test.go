@@ -103,7 +103,30 @@ func TestConstraintDefParser_UniqueKey(t *testing.T) {
 		ukDef, ok := def.(*ast.ConstraintUniqueKeyDef)
 		assert.True(t, ok)
 		assert.Equal(t, "idx_email", ukDef.KeyName)
-		assert.Equal(t, "email", ukDef.Column.ColName)
+		assert.Equal(t, "email", ukDef.Columns[0].ColName)
+	})
+
+	t.Run("複数カラムの UNIQUE KEY をパースできる", func(t *testing.T) {
+		// GIVEN
+		cp := NewConstraintDefParser()
+
+		// WHEN
+		cp.onKeyword("UNIQUE")
+		cp.onKeyword("KEY")
+		cp.onIdentifier("uk_tenant_email")
+		cp.onSymbol("(")
+		cp.onIdentifier("tenant_id")
+		cp.onSymbol(",")
+		cp.onIdentifier("email")
+		cp.onSymbol(")")
+		err := cp.finalize()
+
+		// THEN
+		assert.NoError(t, err)
+		ukDef := cp.getDef().(*ast.ConstraintUniqueKeyDef)
+		assert.Equal(t, 2, len(ukDef.Columns))
+		assert.Equal(t, "tenant_id", ukDef.Columns[0].ColName)
+		assert.Equal(t, "email", ukDef.Columns[1].ColName)
 	})
 
 	t.Run("UNIQUE KEY のカラムが指定されていない場合、エラーを返す", func(t *testing.T) {
@@ -159,7 +182,7 @@ func TestConstraintDefParser_Key(t *testing.T) {
 		keyDef, ok := def.(*ast.ConstraintKeyDef)
 		assert.True(t, ok)
 		assert.Equal(t, "idx_category", keyDef.KeyName)
-		assert.Equal(t, "category", keyDef.Column.ColName)
+		assert.Equal(t, "category", keyDef.Columns[0].ColName)
 	})
 
 	t.Run("KEY のカラムが指定されていない場合、エラーを返す", func(t *testing.T) {
@@ -249,9 +272,63 @@ func TestConstraintDefParser_ForeignKey(t *testing.T) {
 		fkDef, ok := def.(*ast.ConstraintForeignKeyDef)
 		assert.True(t, ok)
 		assert.Equal(t, "fk_user", fkDef.KeyName)
-		assert.Equal(t, "user_id", fkDef.Column.ColName)
+		assert.Equal(t, []ast.ColumnId{*ast.NewColumnId("user_id")}, fkDef.Columns)
 		assert.Equal(t, "users", fkDef.RefTable)
-		assert.Equal(t, "id", fkDef.RefColumn)
+		assert.Equal(t, []string{"id"}, fkDef.RefColumns)
+	})
+
+	t.Run("複合外部キーをパースできる", func(t *testing.T) {
+		// GIVEN
+		cp := NewConstraintDefParser()
+
+		// WHEN: FOREIGN KEY fk_user (tenant_id, user_id) REFERENCES users (tenant_id, id)
+		cp.onKeyword("FOREIGN")
+		cp.onKeyword("KEY")
+		cp.onIdentifier("fk_user")
+		cp.onSymbol("(")
+		cp.onIdentifier("tenant_id")
+		cp.onSymbol(",")
+		cp.onIdentifier("user_id")
+		cp.onSymbol(")")
+		cp.onKeyword("REFERENCES")
+		cp.onIdentifier("users")
+		cp.onSymbol("(")
+		cp.onIdentifier("tenant_id")
+		cp.onSymbol(",")
+		cp.onIdentifier("id")
+		cp.onSymbol(")")
+		err := cp.finalize()
+
+		// THEN
+		assert.NoError(t, err)
+		fkDef, ok := cp.getDef().(*ast.ConstraintForeignKeyDef)
+		assert.True(t, ok)
+		assert.Equal(t, []ast.ColumnId{*ast.NewColumnId("tenant_id"), *ast.NewColumnId("user_id")}, fkDef.Columns)
+		assert.Equal(t, []string{"tenant_id", "id"}, fkDef.RefColumns)
+	})
+
+	t.Run("FK カラムと参照先カラムの数が異なる場合はエラー", func(t *testing.T) {
+		// GIVEN
+		cp := NewConstraintDefParser()
+
+		// WHEN: FOREIGN KEY fk_user (tenant_id, user_id) REFERENCES users (id)
+		cp.onKeyword("FOREIGN")
+		cp.onKeyword("KEY")
+		cp.onIdentifier("fk_user")
+		cp.onSymbol("(")
+		cp.onIdentifier("tenant_id")
+		cp.onSymbol(",")
+		cp.onIdentifier("user_id")
+		cp.onSymbol(")")
+		cp.onKeyword("REFERENCES")
+		cp.onIdentifier("users")
+		cp.onSymbol("(")
+		cp.onIdentifier("id")
+		cp.onSymbol(")")
+		err := cp.finalize()
+
+		// THEN
+		assert.EqualError(t, err, "[parse error] foreign key has 2 columns but REFERENCES has 1 columns")
 	})
 
 	t.Run("FK パース途中で isDone は false を返す", func(t *testing.T) {
@@ -270,7 +347,7 @@ func TestConstraintDefParser_ForeignKey(t *testing.T) {
 		assert.False(t, cp.done)
 	})
 
-	t.Run("FK パース完了後に isDone は true を返す", func(t *testing.T) {
+	t.Run("FK の参照先カラムリストの後は参照アクション待ちになる", func(t *testing.T) {
 		// GIVEN
 		cp := NewConstraintDefParser()
 
@@ -287,8 +364,149 @@ func TestConstraintDefParser_ForeignKey(t *testing.T) {
 		cp.onIdentifier("id")
 		cp.onSymbol(")")
 
+		// THEN: ON DELETE / ON UPDATE 句が続く可能性があるため、done にはならない
+		assert.False(t, cp.done)
+		assert.True(t, cp.awaitsFKAction())
+	})
+
+	t.Run("ON DELETE / ON UPDATE 句をパースできる", func(t *testing.T) {
+		// GIVEN
+		cp := NewConstraintDefParser()
+
+		// WHEN: FOREIGN KEY fk_user (user_id) REFERENCES users (id) ON DELETE SET NULL ON UPDATE CASCADE
+		cp.onKeyword("FOREIGN")
+		cp.onKeyword("KEY")
+		cp.onIdentifier("fk_user")
+		cp.onSymbol("(")
+		cp.onIdentifier("user_id")
+		cp.onSymbol(")")
+		cp.onKeyword("REFERENCES")
+		cp.onIdentifier("users")
+		cp.onSymbol("(")
+		cp.onIdentifier("id")
+		cp.onSymbol(")")
+		cp.onKeyword("ON")
+		cp.onKeyword("DELETE")
+		cp.onKeyword("SET")
+		cp.onKeyword("NULL")
+		cp.onKeyword("ON")
+		cp.onKeyword("UPDATE")
+		cp.onKeyword("CASCADE")
+		err := cp.finalize()
+
 		// THEN
-		assert.True(t, cp.done)
+		assert.NoError(t, err)
+		fkDef := cp.getDef().(*ast.ConstraintForeignKeyDef)
+		assert.Equal(t, ast.ReferentialActionSetNull, fkDef.OnDelete)
+		assert.Equal(t, ast.ReferentialActionCascade, fkDef.OnUpdate)
+	})
+
+	t.Run("参照アクションを省略した場合は空になる", func(t *testing.T) {
+		// GIVEN
+		cp := NewConstraintDefParser()
+
+		// WHEN: FOREIGN KEY fk_user (user_id) REFERENCES users (id) ON DELETE RESTRICT
+		cp.onKeyword("FOREIGN")
+		cp.onKeyword("KEY")
+		cp.onIdentifier("fk_user")
+		cp.onSymbol("(")
+		cp.onIdentifier("user_id")
+		cp.onSymbol(")")
+		cp.onKeyword("REFERENCES")
+		cp.onIdentifier("users")
+		cp.onSymbol("(")
+		cp.onIdentifier("id")
+		cp.onSymbol(")")
+		cp.onKeyword("ON")
+		cp.onKeyword("DELETE")
+		cp.onKeyword("RESTRICT")
+		err := cp.finalize()
+
+		// THEN
+		assert.NoError(t, err)
+		fkDef := cp.getDef().(*ast.ConstraintForeignKeyDef)
+		assert.Equal(t, ast.ReferentialActionRestrict, fkDef.OnDelete)
+		assert.Equal(t, ast.ReferentialAction(""), fkDef.OnUpdate)
+	})
+
+	t.Run("ON DELETE 句が重複している場合、エラーを返す", func(t *testing.T) {
+		// GIVEN
+		cp := NewConstraintDefParser()
+
+		// WHEN
+		cp.onKeyword("FOREIGN")
+		cp.onKeyword("KEY")
+		cp.onIdentifier("fk_user")
+		cp.onSymbol("(")
+		cp.onIdentifier("user_id")
+		cp.onSymbol(")")
+		cp.onKeyword("REFERENCES")
+		cp.onIdentifier("users")
+		cp.onSymbol("(")
+		cp.onIdentifier("id")
+		cp.onSymbol(")")
+		cp.onKeyword("ON")
+		cp.onKeyword("DELETE")
+		cp.onKeyword("CASCADE")
+		cp.onKeyword("ON")
+		cp.onKeyword("DELETE")
+		err := cp.finalize()
+
+		// THEN
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "duplicate ON DELETE clause")
+	})
+
+	t.Run("参照アクションが途中の場合、エラーを返す", func(t *testing.T) {
+		// GIVEN
+		cp := NewConstraintDefParser()
+
+		// WHEN: ... ON DELETE SET で終了
+		cp.onKeyword("FOREIGN")
+		cp.onKeyword("KEY")
+		cp.onIdentifier("fk_user")
+		cp.onSymbol("(")
+		cp.onIdentifier("user_id")
+		cp.onSymbol(")")
+		cp.onKeyword("REFERENCES")
+		cp.onIdentifier("users")
+		cp.onSymbol("(")
+		cp.onIdentifier("id")
+		cp.onSymbol(")")
+		cp.onKeyword("ON")
+		cp.onKeyword("DELETE")
+		cp.onKeyword("SET")
+		err := cp.finalize()
+
+		// THEN
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "incomplete ON DELETE / ON UPDATE clause")
+	})
+
+	t.Run("サポートしていない参照アクションの場合、エラーを返す", func(t *testing.T) {
+		// GIVEN
+		cp := NewConstraintDefParser()
+
+		// WHEN: ... ON UPDATE NULL
+		cp.onKeyword("FOREIGN")
+		cp.onKeyword("KEY")
+		cp.onIdentifier("fk_user")
+		cp.onSymbol("(")
+		cp.onIdentifier("user_id")
+		cp.onSymbol(")")
+		cp.onKeyword("REFERENCES")
+		cp.onIdentifier("users")
+		cp.onSymbol("(")
+		cp.onIdentifier("id")
+		cp.onSymbol(")")
+		cp.onKeyword("ON")
+		cp.onKeyword("UPDATE")
+		cp.onKeyword("NULL")
+		err := cp.finalize()
+
+		// THEN
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "expected 'CASCADE', 'RESTRICT' or 'SET NULL'")
 	})
 
 	t.Run("FK 名がない場合、エラーを返す", func(t *testing.T) {
@@ -322,6 +540,104 @@ func TestConstraintDefParser_ForeignKey(t *testing.T) {
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "REFERENCES table is required")
 	})
+
+}
+
+func TestConstraintDefParser_Check(t *testing.T) {
+	t.Run("CHECK 制約をパースできる", func(t *testing.T) {
+		// GIVEN
+		cp := NewConstraintDefParser()
+
+		// WHEN: CHECK (price >= 0)
+		cp.onKeyword("CHECK")
+		cp.onSymbol("(")
+		cp.onIdentifier("price")
+		assert.True(t, cp.acceptsSymbol(">="))
+		cp.onSymbol(">=")
+		cp.onNumber("0")
+		cp.onSymbol(")")
+		err := cp.finalize()
+
+		// THEN
+		assert.NoError(t, err)
+		assert.True(t, cp.done)
+		checkDef, ok := cp.getDef().(*ast.ConstraintCheckDef)
+		assert.True(t, ok)
+		assert.Equal(t, "", checkDef.KeyName)
+		assert.Equal(t, "price >= 0", checkDef.ExprText)
+		assert.Equal(t, "price >= 0", ast.ExprString(checkDef.Expr))
+	})
+
+	t.Run("CONSTRAINT で CHECK 制約に名前を付けられる", func(t *testing.T) {
+		// GIVEN
+		cp := NewConstraintDefParser()
+
+		// WHEN: CONSTRAINT chk_status CHECK (status IN ('a', 'b'))
+		cp.onKeyword("CONSTRAINT")
+		cp.onIdentifier("chk_status")
+		cp.onKeyword("CHECK")
+		cp.onSymbol("(")
+		cp.onIdentifier("status")
+		cp.onKeyword("IN")
+		cp.onSymbol("(")
+		cp.onString("a")
+		cp.onSymbol(",")
+		cp.onString("b")
+		cp.onSymbol(")")
+		assert.False(t, cp.done)
+		cp.onSymbol(")")
+		err := cp.finalize()
+
+		// THEN
+		assert.NoError(t, err)
+		assert.True(t, cp.done)
+		checkDef := cp.getDef().(*ast.ConstraintCheckDef)
+		assert.Equal(t, "chk_status", checkDef.KeyName)
+		assert.Equal(t, "status IN ('a', 'b')", checkDef.ExprText)
+	})
+
+	t.Run("CONSTRAINT 名の後に CHECK がない場合、エラーを返す", func(t *testing.T) {
+		// GIVEN
+		cp := NewConstraintDefParser()
+
+		// WHEN
+		cp.onKeyword("CONSTRAINT")
+		cp.onIdentifier("fk_user")
+		cp.onKeyword("FOREIGN")
+		err := cp.finalize()
+
+		// THEN
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "expected 'CHECK', got: FOREIGN")
+	})
+
+	t.Run("CHECK の条件式がない場合、エラーを返す", func(t *testing.T) {
+		// GIVEN
+		cp := NewConstraintDefParser()
+
+		// WHEN
+		cp.onKeyword("CHECK")
+		err := cp.finalize()
+
+		// THEN
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "CHECK constraint requires a condition")
+	})
+
+	t.Run("CHECK の条件式が空の場合、エラーを返す", func(t *testing.T) {
+		// GIVEN
+		cp := NewConstraintDefParser()
+
+		// WHEN
+		cp.onKeyword("CHECK")
+		cp.onSymbol("(")
+		cp.onSymbol(")")
+		err := cp.finalize()
+
+		// THEN
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "empty expression in CHECK clause")
+	})
 }
 
 func TestConstraintDefParser_Error(t *testing.T) {
@@ -335,7 +651,7 @@ func TestConstraintDefParser_Error(t *testing.T) {
 
 		// THEN
 		assert.Error(t, err)
-		assert.Contains(t, err.Error(), "expected 'PRIMARY', 'UNIQUE', 'KEY', or 'FOREIGN'")
+		assert.Contains(t, err.Error(), "expected 'PRIMARY', 'UNIQUE', 'KEY', 'FOREIGN', 'CONSTRAINT' or 'CHECK'")
 	})
 
 	t.Run("KEY キーワードがない場合、エラーを返す", func(t *testing.T) {
@@ -397,7 +713,7 @@ func TestConstraintDefParser_Error(t *testing.T) {
 
 		// THEN
 		assert.Error(t, err)
-		assert.Contains(t, err.Error(), "expected 'PRIMARY', 'UNIQUE', 'KEY', or 'FOREIGN'")
+		assert.Contains(t, err.Error(), "expected 'PRIMARY', 'UNIQUE', 'KEY', 'FOREIGN', 'CONSTRAINT' or 'CHECK'")
 	})
 
 	t.Run("カラムリストが開かれたまま finalize された場合、エラーを返す", func(t *testing.T) {
@@ -0,0 +1,152 @@
+package parser
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/ren-yamanashi/minesql/internal/ast"
+)
+
+// CTEParser は WITH 句の共通テーブル式 (`name [(col1, col2, ...)] AS (SELECT ...)`) のパース処理を行う
+//
+// 問い合わせの SELECT キーワードから閉じ括弧の手前までのトークンは、問い合わせのパーサーに転送する
+// 問い合わせには集合演算 (UNION など) を指定できる
+type CTEParser struct {
+	state parserState          // 現在のステート
+	cte   *ast.CommonTableExpr // 構築中の共通テーブル式
+	query *SelectParser        // 問い合わせのパーサー
+	depth int                  // 問い合わせの中で開いている括弧の数
+}
+
+func NewCTEParser() *CTEParser {
+	return &CTEParser{
+		state: CTEStateName,
+		cte:   &ast.CommonTableExpr{},
+	}
+}
+
+// done は共通テーブル式の ")" まで解析し終えたかどうかを返す
+func (cp *CTEParser) done() bool {
+	return cp.state == CTEStateEnd
+}
+
+// result は確定した共通テーブル式を返す
+func (cp *CTEParser) result() *ast.CommonTableExpr {
+	return cp.cte
+}
+
+func (cp *CTEParser) onKeyword(word string) error {
+	if cp.state == CTEStateQuery {
+		cp.query.onKeyword(word)
+		return cp.query.getError()
+	}
+
+	upperWord := strings.ToUpper(word)
+	switch {
+	case upperWord == KAs && (cp.state == CTEStateNamed || cp.state == CTEStateColumnEnd):
+		cp.state = CTEStateAs
+		return nil
+	case upperWord == KSelect && cp.state == CTEStateOpen:
+		cp.query = NewSelectParser()
+		cp.query.onKeyword(word)
+		cp.state = CTEStateQuery
+		return cp.query.getError()
+	case cp.state == CTEStateName:
+		return errors.New("[parse error] missing CTE name after WITH")
+	case cp.state == CTEStateColumn:
+		return errors.New("[parse error] missing column name in column list of CTE " + cp.cte.Name)
+	case cp.state == CTEStateAs || cp.state == CTEStateOpen:
+		return errors.New("[parse error] CTE must be a SELECT statement enclosed in parentheses")
+	default:
+		return errors.New("[parse error] " + upperWord + " keyword is in invalid position in WITH clause")
+	}
+}
+
+func (cp *CTEParser) onIdentifier(ident string) error {
+	switch cp.state {
+	case CTEStateQuery:
+		cp.query.onIdentifier(ident)
+		return cp.query.getError()
+	case CTEStateName:
+		cp.cte.Name = ident
+		cp.state = CTEStateNamed
+		return nil
+	case CTEStateColumn:
+		cp.cte.Columns = append(cp.cte.Columns, ident)
+		cp.state = CTEStateColumnSep
+		return nil
+	default:
+		return errors.New("[parse error] unexpected identifier in WITH clause: " + ident)
+	}
+}
+
+func (cp *CTEParser) onSymbol(symbol string) error {
+	if cp.state == CTEStateQuery {
+		return cp.onQuerySymbol(symbol)
+	}
+
+	switch {
+	case cp.state == CTEStateNamed && symbol == string(SLeftParen):
+		cp.state = CTEStateColumn
+	case cp.state == CTEStateColumnSep && symbol == string(SComma):
+		cp.state = CTEStateColumn
+	case cp.state == CTEStateColumnSep && symbol == string(SRightParen):
+		cp.state = CTEStateColumnEnd
+	case cp.state == CTEStateAs && symbol == string(SLeftParen):
+		cp.state = CTEStateOpen
+	case cp.state == CTEStateColumn:
+		return errors.New("[parse error] missing column name in column list of CTE " + cp.cte.Name)
+	case cp.state == CTEStateNamed || cp.state == CTEStateColumnEnd:
+		return errors.New("[parse error] missing AS after CTE name " + cp.cte.Name)
+	default:
+		return errors.New("[parse error] unexpected symbol in WITH clause: " + symbol)
+	}
+	return nil
+}
+
+// onQuerySymbol は問い合わせの中のシンボルを転送する
+//
+// 問い合わせの閉じ括弧の場合は、転送せずに問い合わせを確定する
+func (cp *CTEParser) onQuerySymbol(symbol string) error {
+	switch symbol {
+	case string(SLeftParen):
+		cp.depth++
+	case string(SRightParen):
+		if cp.depth == 0 {
+			return cp.finalizeQuery()
+		}
+		cp.depth--
+	case string(SSemicolon):
+		return errors.New("[parse error] missing ')' after CTE " + cp.cte.Name)
+	}
+	cp.query.onSymbol(symbol)
+	return cp.query.getError()
+}
+
+func (cp *CTEParser) onString(value string) error {
+	if cp.state == CTEStateQuery {
+		cp.query.onString(value)
+		return cp.query.getError()
+	}
+	return errors.New("[parse error] unexpected string in WITH clause: " + value)
+}
+
+func (cp *CTEParser) onNumber(num string) error {
+	if cp.state == CTEStateQuery {
+		cp.query.onNumber(num)
+		return cp.query.getError()
+	}
+	return errors.New("[parse error] unexpected number in WITH clause: " + num)
+}
+
+// finalizeQuery は問い合わせを確定して共通テーブル式を終える
+func (cp *CTEParser) finalizeQuery() error {
+	cp.query.onSymbol(string(SSemicolon))
+	cp.query.finalize()
+	if err := cp.query.getError(); err != nil {
+		return err
+	}
+	cp.cte.Query = cp.query.getResult()
+	cp.state = CTEStateEnd
+	return nil
+}
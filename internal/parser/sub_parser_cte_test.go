@@ -0,0 +1,161 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/ren-yamanashi/minesql/internal/ast"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParserWith(t *testing.T) {
+	t.Run("WITH 句の共通テーブル式をパースできる", func(t *testing.T) {
+		// GIVEN
+		sql := "WITH active AS (SELECT id, name FROM users WHERE status = 'active') SELECT name FROM active WHERE id > 10;"
+		parser := NewParser()
+
+		// WHEN
+		result, err := parser.Parse(sql)
+
+		// THEN
+		assert.NoError(t, err)
+		stmt, ok := result.(*ast.SelectStmt)
+		assert.True(t, ok)
+		assert.False(t, stmt.With.Recursive)
+		assert.Len(t, stmt.With.CTEs, 1)
+
+		cte := stmt.With.CTEs[0]
+		assert.Equal(t, "active", cte.Name)
+		assert.Nil(t, cte.Columns)
+		assert.Equal(t, "SELECT id, name FROM users WHERE status = active", ast.StatementString(cte.Query))
+		assert.Equal(t, "active", stmt.From.TableName)
+		assert.Nil(t, stmt.From.CTE)
+	})
+
+	t.Run("複数の共通テーブル式とカラム名のリストを指定できる", func(t *testing.T) {
+		// GIVEN
+		sql := "WITH a (x, y) AS (SELECT id, name FROM users), b AS (SELECT x FROM a) SELECT * FROM b;"
+		parser := NewParser()
+
+		// WHEN
+		result, err := parser.Parse(sql)
+
+		// THEN
+		assert.NoError(t, err)
+		stmt := result.(*ast.SelectStmt)
+		assert.Len(t, stmt.With.CTEs, 2)
+		assert.Equal(t, "a", stmt.With.CTEs[0].Name)
+		assert.Equal(t, []string{"x", "y"}, stmt.With.CTEs[0].Columns)
+		assert.Equal(t, "b", stmt.With.CTEs[1].Name)
+		assert.Equal(t, "SELECT x FROM a", ast.StatementString(stmt.With.CTEs[1].Query))
+	})
+
+	t.Run("WITH RECURSIVE の問い合わせに集合演算を指定できる", func(t *testing.T) {
+		// GIVEN
+		sql := "WITH RECURSIVE tree AS (SELECT id, parent_id FROM categories WHERE parent_id IS NULL UNION ALL SELECT categories.id, categories.parent_id FROM categories JOIN tree ON categories.parent_id = tree.id) SELECT id FROM tree ORDER BY id;"
+		parser := NewParser()
+
+		// WHEN
+		result, err := parser.Parse(sql)
+
+		// THEN
+		assert.NoError(t, err)
+		stmt := result.(*ast.SelectStmt)
+		assert.True(t, stmt.With.Recursive)
+		query, ok := stmt.With.CTEs[0].Query.(*ast.SetOperationStmt)
+		assert.True(t, ok)
+		assert.Equal(t, ast.SetOpUnion, query.Op)
+		assert.True(t, query.All)
+		assert.Equal(t, "SELECT categories.id, categories.parent_id FROM categories JOIN tree ON categories.parent_id = tree.id", ast.StatementString(query.Right))
+		assert.Equal(t, []ast.OrderByItem{{Column: ast.ColumnId{ColName: "id"}}}, stmt.OrderBy)
+	})
+
+	t.Run("WITH RECURSIVE の非再帰部分に FROM 句のない SELECT 文を指定できる", func(t *testing.T) {
+		// GIVEN
+		sql := "WITH RECURSIVE c (n) AS (SELECT 1 UNION ALL SELECT n + 1 FROM c WHERE n < 5) SELECT n FROM c;"
+		parser := NewParser()
+
+		// WHEN
+		result, err := parser.Parse(sql)
+
+		// THEN
+		assert.NoError(t, err)
+		stmt := result.(*ast.SelectStmt)
+		query, ok := stmt.With.CTEs[0].Query.(*ast.SetOperationStmt)
+		assert.True(t, ok)
+		assert.Equal(t, "SELECT 1", ast.StatementString(query.Left))
+		assert.Equal(t, "SELECT n + 1 FROM c WHERE n < 5", ast.StatementString(query.Right))
+	})
+
+	t.Run("共通テーブル式の問い合わせの中の括弧・サブクエリをパースできる", func(t *testing.T) {
+		// GIVEN
+		sql := "WITH t AS (SELECT id FROM users WHERE (age > 20) AND id IN (SELECT user_id FROM orders)) SELECT * FROM t;"
+		parser := NewParser()
+
+		// WHEN
+		result, err := parser.Parse(sql)
+
+		// THEN
+		assert.NoError(t, err)
+		stmt := result.(*ast.SelectStmt)
+		assert.Equal(t, "SELECT id FROM users WHERE (age > 20) AND (id IN (SELECT user_id FROM orders))", ast.StatementString(stmt.With.CTEs[0].Query))
+	})
+
+	t.Run("WITH 句の後に集合演算を指定できる", func(t *testing.T) {
+		// GIVEN
+		sql := "WITH t AS (SELECT id FROM users) SELECT id FROM t UNION SELECT user_id FROM orders;"
+		parser := NewParser()
+
+		// WHEN
+		result, err := parser.Parse(sql)
+
+		// THEN
+		assert.NoError(t, err)
+		stmt, ok := result.(*ast.SetOperationStmt)
+		assert.True(t, ok)
+		assert.Len(t, stmt.With.CTEs, 1)
+		assert.Nil(t, stmt.Left.(*ast.SelectStmt).With)
+	})
+
+	t.Run("EXPLAIN の対象に WITH 句を指定できる", func(t *testing.T) {
+		// GIVEN
+		sql := "EXPLAIN WITH t AS (SELECT id FROM users) SELECT * FROM t;"
+		parser := NewParser()
+
+		// WHEN
+		result, err := parser.Parse(sql)
+
+		// THEN
+		assert.NoError(t, err)
+		explain := result.(*ast.ExplainStmt)
+		assert.Len(t, explain.Stmt.(*ast.SelectStmt).With.CTEs, 1)
+	})
+
+	t.Run("不正な WITH 句はエラーになる", func(t *testing.T) {
+		tests := []struct {
+			name string
+			sql  string
+			err  string
+		}{
+			{"WITH 句の後に SELECT 文がない", "WITH t AS (SELECT id FROM users);", "[parse error] missing SELECT statement after WITH clause"},
+			{"共通テーブル式の後に SELECT 以外が来る", "WITH t AS (SELECT id FROM users) FROM t;", "[parse error] missing SELECT statement after WITH clause"},
+			{"共通テーブル式の名前がない", "WITH AS (SELECT id FROM users) SELECT * FROM t;", "[parse error] missing CTE name after WITH"},
+			{"AS がない", "WITH t SELECT * FROM users;", "[parse error] SELECT keyword is in invalid position in WITH clause"},
+			{"問い合わせが括弧で囲まれていない", "WITH t AS SELECT id FROM users;", "[parse error] CTE must be a SELECT statement enclosed in parentheses"},
+			{"カラム名のリストが空", "WITH t () AS (SELECT id FROM users) SELECT * FROM t;", "[parse error] missing column name in column list of CTE t"},
+			{"問い合わせの括弧が閉じていない", "WITH t AS (SELECT id FROM users SELECT * FROM t;", "[parse error] SELECT keyword is in invalid position"},
+			{"サブクエリの中の WITH 句", "SELECT * FROM (WITH t AS (SELECT id FROM users) SELECT * FROM t) AS x;", "[parse error] WITH keyword is in invalid position"},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				// GIVEN
+				parser := NewParser()
+
+				// WHEN
+				_, err := parser.Parse(tt.sql)
+
+				// THEN
+				assert.EqualError(t, err, tt.err)
+			})
+		}
+	})
+}
@@ -0,0 +1,151 @@
+package parser
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/ren-yamanashi/minesql/internal/ast"
+)
+
+// ExprDefParser はテーブル定義に含まれる式 (DEFAULT 句・CHECK 制約の条件式) のパース処理を行う
+//
+// 式の AST に加えて、カタログに格納するための式の SQL 表現を構築する
+// SQL 表現は ParseExpr で再び AST に戻せる形式 (文字列リテラルはシングルクォートで囲む) にする
+// サブクエリは指定できない
+type ExprDefParser struct {
+	expr WhereParser     // 式のパーサー
+	text strings.Builder // 式の SQL 表現
+	prev string          // 直前のトークン (SQL 表現の空白の判定に使う)
+	err  error           // エラー情報
+}
+
+func NewExprDefParser() *ExprDefParser {
+	ep := &ExprDefParser{}
+	ep.expr.initExpr()
+	return ep
+}
+
+// ParseExpr は式の SQL 表現 (ExprDefParser で構築したもの) を解析し、式の AST を返す
+func ParseExpr(sql string) (ast.Expr, error) {
+	ep := NewExprDefParser()
+	NewTokenizer(sql, ep).Tokenize()
+	expr, _, err := ep.finalize("expression")
+	return expr, err
+}
+
+// finalize は式を確定し、式の AST と SQL 表現を返す
+//
+// clause はエラーメッセージに使う句の名前 (e.g. "DEFAULT", "CHECK")
+func (ep *ExprDefParser) finalize(clause string) (ast.Expr, string, error) {
+	if ep.err != nil {
+		return nil, "", ep.err
+	}
+	expr, err := ep.expr.finalizeExpr(clause)
+	if err != nil {
+		return nil, "", err
+	}
+	return expr, ep.text.String(), nil
+}
+
+// inNested は括弧・関数呼び出し・CASE 式の中を解析中かどうかを返す
+func (ep *ExprDefParser) inNested() bool {
+	return ep.expr.inNested()
+}
+
+// isEmpty はまだトークンを受け取っていないかどうかを返す
+func (ep *ExprDefParser) isEmpty() bool {
+	return ep.expr.isEmpty()
+}
+
+func (ep *ExprDefParser) onKeyword(word string) {
+	if ep.err != nil {
+		return
+	}
+
+	upper := strings.ToUpper(word)
+	switch upper {
+	case KAnd, KOr:
+		ep.setError(ep.expr.handleOperator(upper))
+	case KIs, KNot, KNull, KIn, KBetween, KLike, KCase, KWhen, KThen, KElse, KEnd:
+		ep.setError(ep.expr.handleKeyword(upper))
+	default:
+		ep.setError(errors.New("[parse error] unexpected keyword in expression: " + word))
+		return
+	}
+	ep.appendText(upper)
+}
+
+func (ep *ExprDefParser) onIdentifier(ident string) {
+	if ep.err != nil {
+		return
+	}
+	ep.setError(ep.expr.pushColumn(ident))
+	ep.appendText(ident)
+}
+
+func (ep *ExprDefParser) onSymbol(symbol string) {
+	if ep.err != nil {
+		return
+	}
+	if symbol == string(SSemicolon) {
+		ep.setError(errors.New("[parse error] unexpected symbol in expression: " + symbol))
+		return
+	}
+	ep.setError(ep.expr.handleOperator(symbol))
+	ep.appendText(symbol)
+}
+
+func (ep *ExprDefParser) onString(value string) {
+	if ep.err != nil {
+		return
+	}
+	ep.setError(ep.expr.pushLiteral(ast.NewStringLiteral(value)))
+	ep.appendText("'" + value + "'")
+}
+
+func (ep *ExprDefParser) onNumber(num string) {
+	if ep.err != nil {
+		return
+	}
+	ep.setError(ep.expr.pushLiteral(ast.NewNumberLiteral(num)))
+	ep.appendText(num)
+}
+
+func (ep *ExprDefParser) onComment(_ string) {}
+func (ep *ExprDefParser) onError(err error)  { ep.setError(err) }
+
+// appendText はトークンを式の SQL 表現に追加する
+//
+// "(" の後と ")" / "," の前、関数名と "(" の間には空白を入れない
+func (ep *ExprDefParser) appendText(token string) {
+	if ep.text.Len() > 0 {
+		noSpace := ep.prev == string(SLeftParen) ||
+			token == string(SRightParen) || token == string(SComma) ||
+			(token == string(SLeftParen) && ep.isFuncName(ep.prev))
+		if !noSpace {
+			ep.text.WriteByte(' ')
+		}
+	}
+	ep.text.WriteString(token)
+	ep.prev = token
+}
+
+// isFuncName は直前のトークンが関数名 (識別子) かどうかを返す
+func (ep *ExprDefParser) isFuncName(token string) bool {
+	if token == "" || strings.HasPrefix(token, "'") {
+		return false
+	}
+	upper := strings.ToUpper(token)
+	switch upper {
+	case KAnd, KOr, KIs, KNot, KNull, KIn, KBetween, KLike, KCase, KWhen, KThen, KElse, KEnd:
+		return false
+	}
+	ch := token[0]
+	return ch == '_' || (ch >= 'a' && ch <= 'z') || (ch >= 'A' && ch <= 'Z')
+}
+
+func (ep *ExprDefParser) setError(err error) {
+	if ep.err == nil && err != nil {
+		ep.err = err
+	}
+}
@@ -0,0 +1,96 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/ren-yamanashi/minesql/internal/ast"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExprDefParser(t *testing.T) {
+	t.Run("式の SQL 表現を構築できる", func(t *testing.T) {
+		tests := []struct {
+			name string
+			sql  string
+			text string
+		}{
+			{name: "比較演算", sql: "price>0", text: "price > 0"},
+			{name: "括弧と算術演算", sql: "price * ( 1 + rate )", text: "price * (1 + rate)"},
+			{name: "関数呼び出し", sql: "UPPER ( name )", text: "UPPER(name)"},
+			{name: "文字列リテラルの IN リスト", sql: "status in ('a','b')", text: "status IN ('a', 'b')"},
+			{name: "BETWEEN と論理演算", sql: "age between 0 and 150 or age is null", text: "age BETWEEN 0 AND 150 OR age IS NULL"},
+			{name: "CASE 式", sql: "case when qty > 0 then 1 else 0 end", text: "CASE WHEN qty > 0 THEN 1 ELSE 0 END"},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				// GIVEN
+				ep := NewExprDefParser()
+
+				// WHEN
+				NewTokenizer(tt.sql, ep).Tokenize()
+				_, text, err := ep.finalize("CHECK")
+
+				// THEN
+				assert.NoError(t, err)
+				assert.Equal(t, tt.text, text)
+			})
+		}
+	})
+
+	t.Run("構築した SQL 表現を ParseExpr で同じ式に戻せる", func(t *testing.T) {
+		// GIVEN
+		ep := NewExprDefParser()
+		NewTokenizer("qty * price <= 1000 AND name LIKE 'a%'", ep).Tokenize()
+		expr, text, err := ep.finalize("CHECK")
+		assert.NoError(t, err)
+
+		// WHEN
+		parsed, err := ParseExpr(text)
+
+		// THEN
+		assert.NoError(t, err)
+		assert.Equal(t, expr, parsed)
+	})
+
+	t.Run("サブクエリを含む場合、エラーを返す", func(t *testing.T) {
+		// GIVEN
+		ep := NewExprDefParser()
+
+		// WHEN
+		NewTokenizer("id IN (SELECT id FROM users)", ep).Tokenize()
+		_, _, err := ep.finalize("CHECK")
+
+		// THEN
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "unexpected keyword in expression: SELECT")
+	})
+}
+
+func TestParseExpr(t *testing.T) {
+	t.Run("式をパースできる", func(t *testing.T) {
+		// WHEN
+		expr, err := ParseExpr("price > 0")
+
+		// THEN
+		assert.NoError(t, err)
+		assert.Equal(t, "price > 0", ast.ExprString(expr))
+	})
+
+	t.Run("空文字の場合、エラーを返す", func(t *testing.T) {
+		// WHEN
+		_, err := ParseExpr("")
+
+		// THEN
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "empty expression")
+	})
+
+	t.Run("セミコロンを含む場合、エラーを返す", func(t *testing.T) {
+		// WHEN
+		_, err := ParseExpr("1; DROP TABLE users")
+
+		// THEN
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "unexpected symbol in expression: ;")
+	})
+}
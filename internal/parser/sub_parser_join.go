@@ -0,0 +1,181 @@
+package parser
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/ren-yamanashi/minesql/internal/ast"
+)
+
+// JoinParser は複数テーブルの UPDATE / DELETE の JOIN 句 (`[INNER | LEFT [OUTER] | RIGHT [OUTER]] JOIN table ON cond`) のパース処理を行う
+//
+// 親のパーサーは JOIN 句の後に続くキーワード (SET, WHERE) や ";" で JOIN 句を確定する
+// 導出テーブルは指定できない
+type JoinParser struct {
+	state    parserState       // 現在のステート
+	joins    []*ast.JoinClause // 確定した JOIN 句
+	current  *ast.JoinClause   // 現在パース中の JOIN 句
+	joinType ast.JoinType      // JOIN の前置キーワード (INNER / LEFT / RIGHT) で指定された JOIN の種類
+	on       WhereParser       // ON 句パーサー
+}
+
+func NewJoinParser() *JoinParser {
+	return &JoinParser{
+		state: JoinStateStart,
+	}
+}
+
+// finalize は現在パース中の JOIN 句を確定し、すべての JOIN 句を返す
+func (jp *JoinParser) finalize() ([]*ast.JoinClause, error) {
+	switch jp.state {
+	case JoinStateInner, JoinStateOuter, JoinStateOuterKw, JoinStateJoin:
+		return nil, errors.New("[parse error] incomplete JOIN clause")
+	}
+	if err := jp.finalizeCurrentJoin(); err != nil {
+		return nil, err
+	}
+	return jp.joins, nil
+}
+
+// inNested は ON 条件式の括弧・関数呼び出し・CASE 式の中を解析中かどうかを返す
+func (jp *JoinParser) inNested() bool {
+	return jp.state == JoinStateOn && jp.on.inNested()
+}
+
+func (jp *JoinParser) onKeyword(word string) error {
+	upperWord := strings.ToUpper(word)
+
+	switch upperWord {
+	case KInner, KLeft, KRight:
+		// INNER / LEFT / RIGHT は JOIN の前置キーワード。JOIN 句の先頭または ON 条件後に来る
+		if jp.state == JoinStateStart || jp.state == JoinStateOn {
+			if err := jp.finalizeCurrentJoin(); err != nil {
+				return err
+			}
+			switch upperWord {
+			case KLeft:
+				jp.joinType = ast.JoinTypeLeft
+				jp.state = JoinStateOuter
+			case KRight:
+				jp.joinType = ast.JoinTypeRight
+				jp.state = JoinStateOuter
+			default:
+				jp.joinType = ast.JoinTypeInner
+				jp.state = JoinStateInner
+			}
+			return nil
+		}
+		return errors.New("[parse error] " + upperWord + " keyword is in invalid position")
+
+	case KOuter:
+		// OUTER は LEFT / RIGHT の後にのみ来る (LEFT OUTER JOIN, RIGHT OUTER JOIN)
+		if jp.state == JoinStateOuter {
+			jp.state = JoinStateOuterKw
+			return nil
+		}
+		return errors.New("[parse error] OUTER keyword is in invalid position")
+
+	case KJoin:
+		switch jp.state {
+		case JoinStateInner, JoinStateOuter, JoinStateOuterKw:
+			// 前置キーワードで既に JOIN の種類を確定済み
+		case JoinStateStart, JoinStateOn:
+			if err := jp.finalizeCurrentJoin(); err != nil {
+				return err
+			}
+			jp.joinType = ast.JoinTypeInner
+		default:
+			return errors.New("[parse error] JOIN keyword is in invalid position")
+		}
+		jp.current = &ast.JoinClause{Type: jp.joinType}
+		jp.state = JoinStateJoin
+		return nil
+
+	case KOn:
+		if jp.state == JoinStateTable {
+			jp.on.initWhere()
+			jp.state = JoinStateOn
+			return nil
+		}
+		return errors.New("[parse error] ON keyword is in invalid position")
+
+	case KAnd, KOr:
+		if jp.state == JoinStateOn {
+			return jp.on.handleOperator(upperWord)
+		}
+		return errors.New("[parse error] " + upperWord + " operator is in invalid position")
+
+	case KIs, KNot, KNull, KIn, KBetween, KLike, KCase, KWhen, KThen, KElse, KEnd:
+		if jp.state == JoinStateOn {
+			return jp.on.handleKeyword(upperWord)
+		}
+		return errors.New("[parse error] " + upperWord + " is in invalid position")
+
+	default:
+		return errors.New("[parse error] unsupported keyword: " + word)
+	}
+}
+
+func (jp *JoinParser) onIdentifier(ident string) error {
+	switch jp.state {
+	case JoinStateJoin:
+		jp.current.Table = *ast.NewTableId(ident)
+		jp.state = JoinStateTable
+		return nil
+	case JoinStateOn:
+		return jp.on.pushColumn(ident)
+	default:
+		return errors.New("[parse error] unexpected identifier: " + ident)
+	}
+}
+
+func (jp *JoinParser) onSymbol(symbol string) error {
+	switch jp.state {
+	case JoinStateJoin:
+		if symbol == string(SLeftParen) {
+			return errors.New("[parse error] derived table is not supported in JOIN clause of UPDATE / DELETE")
+		}
+		return errors.New("[parse error] unexpected symbol: " + symbol)
+	case JoinStateOn:
+		return jp.on.handleOperator(symbol)
+	default:
+		return errors.New("[parse error] unexpected symbol: " + symbol)
+	}
+}
+
+func (jp *JoinParser) onString(value string) error {
+	return jp.onLiteral(ast.NewStringLiteral(value))
+}
+
+func (jp *JoinParser) onNumber(num string) error {
+	return jp.onLiteral(ast.NewNumberLiteral(num))
+}
+
+// onLiteral は ON 条件式のリテラルを積む
+func (jp *JoinParser) onLiteral(lit ast.Literal) error {
+	if jp.state == JoinStateOn {
+		return jp.on.pushLiteral(lit)
+	}
+	return errors.New("[parse error] unexpected literal: " + lit.ToString())
+}
+
+// finalizeCurrentJoin は現在パース中の JOIN 句を確定する
+func (jp *JoinParser) finalizeCurrentJoin() error {
+	if jp.current == nil {
+		return nil
+	}
+	if jp.state != JoinStateOn {
+		return errors.New("[parse error] missing ON clause in JOIN")
+	}
+	onClause, err := jp.on.finalizeWhere()
+	if err != nil {
+		return err
+	}
+	if onClause == nil {
+		return errors.New("[parse error] missing ON clause in JOIN")
+	}
+	jp.current.Condition = onClause.Condition
+	jp.joins = append(jp.joins, jp.current)
+	jp.current = nil
+	return nil
+}
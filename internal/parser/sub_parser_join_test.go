@@ -0,0 +1,37 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJoinParser(t *testing.T) {
+	t.Run("不正な JOIN 句でエラーになる", func(t *testing.T) {
+		tests := []struct {
+			name string
+			sql  string
+			err  string
+		}{
+			{name: "JOIN のテーブル名がない", sql: "UPDATE orders JOIN SET status = 'a';", err: "incomplete JOIN clause"},
+			{name: "LEFT の後に JOIN がない", sql: "DELETE orders FROM orders LEFT customers ON orders.id = customers.id;", err: "unexpected identifier: customers"},
+			{name: "OUTER が LEFT / RIGHT の後にない", sql: "DELETE orders FROM orders INNER OUTER JOIN customers ON orders.id = customers.id;", err: "OUTER keyword is in invalid position"},
+			{name: "ON がテーブル名の前にある", sql: "UPDATE orders JOIN ON orders.id = 1 SET status = 'a';", err: "ON keyword is in invalid position"},
+			{name: "ON 条件式が空", sql: "DELETE orders FROM orders JOIN customers ON;", err: "empty expression"},
+			{name: "導出テーブル", sql: "UPDATE orders JOIN (SELECT id FROM customers) AS c ON orders.id = c.id SET status = 'a';", err: "derived table is not supported"},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				// GIVEN
+				parser := NewParser()
+
+				// WHEN
+				result, err := parser.Parse(tt.sql)
+
+				// THEN
+				assert.Nil(t, result)
+				assert.ErrorContains(t, err, tt.err)
+			})
+		}
+	})
+}
@@ -0,0 +1,73 @@
+package parser
+
+import (
+	"errors"
+
+	"github.com/ren-yamanashi/minesql/internal/ast"
+)
+
+// subqueryParser は括弧で囲まれたサブクエリ (SELECT 文) のパース処理を行う
+//
+// 外側の SELECT 文のパーサーは、サブクエリの SELECT キーワードから閉じ括弧の手前までのトークンをそのまま転送する
+// サブクエリの中の括弧の数を数え、対応する閉じ括弧が来た時点でサブクエリを確定する
+type subqueryParser struct {
+	parser *SelectParser // サブクエリの SELECT 文のパーサー
+	depth  int           // サブクエリの中で開いている括弧の数
+}
+
+// newSubqueryParser はサブクエリのパースを開始する (サブクエリの SELECT キーワードを検出した時点で呼ぶ)
+func newSubqueryParser() *subqueryParser {
+	sq := &subqueryParser{parser: NewSelectParser()}
+	sq.parser.embedded = true
+	sq.parser.onKeyword(KSelect)
+	return sq
+}
+
+func (sq *subqueryParser) onKeyword(word string) error {
+	sq.parser.onKeyword(word)
+	return sq.parser.getError()
+}
+
+func (sq *subqueryParser) onIdentifier(ident string) error {
+	sq.parser.onIdentifier(ident)
+	return sq.parser.getError()
+}
+
+func (sq *subqueryParser) onString(value string) error {
+	sq.parser.onString(value)
+	return sq.parser.getError()
+}
+
+func (sq *subqueryParser) onNumber(num string) error {
+	sq.parser.onNumber(num)
+	return sq.parser.getError()
+}
+
+// onSymbol はシンボルを転送する
+//
+// サブクエリの閉じ括弧の場合は、転送せずにサブクエリを確定して返す (それ以外の場合は nil を返す)
+func (sq *subqueryParser) onSymbol(symbol string) (*ast.SelectStmt, error) {
+	switch symbol {
+	case string(SLeftParen):
+		sq.depth++
+	case string(SRightParen):
+		if sq.depth == 0 {
+			return sq.finalize()
+		}
+		sq.depth--
+	case string(SSemicolon):
+		return nil, errors.New("[parse error] missing ')' after subquery")
+	}
+	sq.parser.onSymbol(symbol)
+	return nil, sq.parser.getError()
+}
+
+// finalize はサブクエリの SELECT 文を確定する
+func (sq *subqueryParser) finalize() (*ast.SelectStmt, error) {
+	sq.parser.onSymbol(string(SSemicolon))
+	sq.parser.finalize()
+	if err := sq.parser.getError(); err != nil {
+		return nil, err
+	}
+	return sq.parser.stmt, nil
+}
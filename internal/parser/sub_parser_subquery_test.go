@@ -0,0 +1,149 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/ren-yamanashi/minesql/internal/ast"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParserSubquery(t *testing.T) {
+	parseSelect := func(t *testing.T, sql string) *ast.SelectStmt {
+		t.Helper()
+		result, err := NewParser().Parse(sql)
+		assert.NoError(t, err)
+		selectStmt, ok := result.(*ast.SelectStmt)
+		assert.True(t, ok)
+		return selectStmt
+	}
+
+	t.Run("WHERE 句でスカラーサブクエリと比較できる", func(t *testing.T) {
+		// GIVEN
+		sql := "SELECT name FROM products WHERE price > (SELECT AVG(price) FROM products WHERE qty > 0) AND qty > 1;"
+
+		// WHEN
+		selectStmt := parseSelect(t, sql)
+
+		// THEN
+		and, ok := selectStmt.Where.Condition.(*ast.BinaryExpr)
+		assert.True(t, ok)
+		assert.Equal(t, "AND", and.Operator)
+		cmp := ast.LeftOperand(and.Left).(*ast.BinaryExpr)
+		sub, ok := ast.RightOperand(cmp.Right).(*ast.SubqueryExpr)
+		assert.True(t, ok)
+		assert.Equal(t, "products", sub.Select.From.TableName)
+		assert.Equal(t, "SELECT AVG(price) FROM products WHERE qty > 0", ast.SelectString(sub.Select))
+		assert.Equal(t, "qty > 1", ast.ExprString(ast.RightOperand(and.Right)))
+	})
+
+	t.Run("SELECT リストにスカラーサブクエリを指定できる", func(t *testing.T) {
+		// GIVEN
+		sql := "SELECT name, (SELECT COUNT(*) FROM orders WHERE orders.user_id = users.id) AS cnt FROM users;"
+
+		// WHEN
+		selectStmt := parseSelect(t, sql)
+
+		// THEN
+		assert.Equal(t, []ast.ColumnId{{ColName: "name"}}, selectStmt.Columns)
+		assert.Len(t, selectStmt.Exprs, 1)
+		assert.IsType(t, &ast.SubqueryExpr{}, selectStmt.Exprs[0].Expr)
+		assert.Equal(t, map[int]string{1: "cnt"}, selectStmt.Aliases)
+		assert.Equal(t, "users", selectStmt.From.TableName)
+	})
+
+	t.Run("IN (SELECT ...) と NOT IN (SELECT ...) をパースできる", func(t *testing.T) {
+		// GIVEN
+		sql := "SELECT * FROM users WHERE id IN (SELECT user_id FROM orders) OR id NOT IN (SELECT user_id FROM banned);"
+
+		// WHEN
+		selectStmt := parseSelect(t, sql)
+
+		// THEN
+		or := selectStmt.Where.Condition.(*ast.BinaryExpr)
+		in := ast.LeftOperand(or.Left).(*ast.InExpr)
+		assert.Equal(t, ast.ColumnId{ColName: "id"}, in.Operand)
+		assert.Nil(t, in.Values)
+		assert.Equal(t, "orders", in.Subquery.From.TableName)
+		assert.False(t, in.Not)
+		notIn := ast.RightOperand(or.Right).(*ast.InExpr)
+		assert.Equal(t, "banned", notIn.Subquery.From.TableName)
+		assert.True(t, notIn.Not)
+	})
+
+	t.Run("EXISTS と NOT EXISTS をパースできる", func(t *testing.T) {
+		// GIVEN
+		sql := "SELECT * FROM users WHERE EXISTS (SELECT * FROM orders WHERE orders.user_id = users.id AND (amount > 10)) AND NOT EXISTS (SELECT * FROM banned);"
+
+		// WHEN
+		selectStmt := parseSelect(t, sql)
+
+		// THEN
+		and := selectStmt.Where.Condition.(*ast.BinaryExpr)
+		exists := ast.LeftOperand(and.Left).(*ast.ExistsExpr)
+		assert.Equal(t, "(orders.user_id = users.id) AND (amount > 10)", ast.ExprString(exists.Subquery.Where.Condition))
+		not := ast.RightOperand(and.Right).(*ast.UnaryExpr)
+		assert.Equal(t, "NOT", not.Operator)
+		assert.IsType(t, &ast.ExistsExpr{}, not.Operand)
+	})
+
+	t.Run("サブクエリを入れ子にできる", func(t *testing.T) {
+		// GIVEN
+		sql := "SELECT * FROM users WHERE id IN (SELECT user_id FROM orders WHERE amount = (SELECT MAX(amount) FROM orders));"
+
+		// WHEN
+		selectStmt := parseSelect(t, sql)
+
+		// THEN
+		in := selectStmt.Where.Condition.(*ast.InExpr)
+		cmp := in.Subquery.Where.Condition.(*ast.BinaryExpr)
+		inner := ast.RightOperand(cmp.Right).(*ast.SubqueryExpr)
+		assert.Equal(t, "SELECT MAX(amount) FROM orders", ast.SelectString(inner.Select))
+	})
+
+	t.Run("FROM 句・JOIN 句に導出テーブルを指定できる", func(t *testing.T) {
+		// GIVEN
+		sql := "SELECT t.user_id, s.cnt FROM (SELECT user_id FROM orders) AS t JOIN (SELECT user_id, COUNT(*) AS cnt FROM orders GROUP BY user_id) s ON t.user_id = s.user_id;"
+
+		// WHEN
+		selectStmt := parseSelect(t, sql)
+
+		// THEN
+		assert.Equal(t, "t", selectStmt.From.TableName)
+		assert.Equal(t, "SELECT user_id FROM orders", ast.SelectString(selectStmt.From.Subquery))
+		assert.Len(t, selectStmt.Joins, 1)
+		assert.Equal(t, "s", selectStmt.Joins[0].Table.TableName)
+		assert.Equal(t, map[int]string{1: "cnt"}, selectStmt.Joins[0].Table.Subquery.Aliases)
+		assert.Equal(t, "t.user_id = s.user_id", ast.ExprString(selectStmt.Joins[0].Condition))
+	})
+
+	t.Run("不正なサブクエリでエラーになる", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			sql      string
+			expected string
+		}{
+			{"導出テーブルに別名がない場合", "SELECT * FROM (SELECT * FROM users);", "every derived table must have its own alias"},
+			{"導出テーブルの後に別名以外が続く場合", "SELECT * FROM (SELECT * FROM users) WHERE id = 1;", "every derived table must have its own alias"},
+			{"導出テーブルが SELECT 文でない場合", "SELECT * FROM (users) AS t;", "derived table must be a SELECT statement"},
+			{"サブクエリの閉じ括弧がない場合", "SELECT * FROM users WHERE id IN (SELECT user_id FROM orders;", "missing ')' after subquery"},
+			{"EXISTS の後にサブクエリがない場合", "SELECT * FROM users WHERE EXISTS (1);", "EXISTS must be followed by a subquery"},
+			{"EXISTS の後に括弧がない場合", "SELECT * FROM users WHERE EXISTS id;", "EXISTS must be followed by '('"},
+			{"括弧の途中に SELECT がある場合", "SELECT * FROM users WHERE id = (1 + SELECT 1 FROM users);", "SELECT is in invalid position"},
+			{"サブクエリの中でエラーがある場合", "SELECT * FROM users WHERE id IN (SELECT user_id FROM orders GROUP BY);", "incomplete GROUP BY clause"},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				// GIVEN
+				parser := NewParser()
+
+				// WHEN
+				result, err := parser.Parse(tt.sql)
+
+				// THEN
+				assert.Error(t, err)
+				assert.Nil(t, result)
+				assert.Contains(t, err.Error(), tt.expected)
+			})
+		}
+	})
+}
@@ -7,51 +7,412 @@ import (
 	"github.com/ren-yamanashi/minesql/internal/ast"
 )
 
-// WhereParser は WHERE 句のパース処理を行う
+const (
+	// IS NOT NULL の演算子
+	opIsNot = "IS NOT"
+	// 単項マイナス (-x) の演算子 (二項演算の "-" と区別するために使う)
+	opNegate = "NEG"
+	// NOT LIKE の演算子
+	opNotLike = "NOT LIKE"
+	// 上限を待っている BETWEEN (下限の後の AND で opBetweenAnd に置き換える)
+	opBetween    = "BETWEEN"
+	opNotBetween = "NOT BETWEEN"
+	// 上限まで揃った BETWEEN (reduce で BetweenExpr を作る)
+	opBetweenAnd    = "BETWEEN AND"
+	opNotBetweenAnd = "NOT BETWEEN AND"
+)
+
+// precComparison は比較演算子 (=, IS, LIKE, BETWEEN など) の優先順位
+const precComparison = 3
+
+// exprFrameKind は括弧で囲まれた部分式の種類
+type exprFrameKind uint8
+
+const (
+	frameParen  exprFrameKind = iota // 括弧 | `(a + b)`
+	frameFunc                        // 関数呼び出しの引数 | `UPPER(name)`, `COUNT(*)`
+	frameCase                        // CASE 式 | `CASE WHEN ... THEN ... END`
+	frameIn                          // IN リスト | `id IN (1, 2, 3)`, `id IN (SELECT ...)`
+	frameExists                      // EXISTS のサブクエリ | `EXISTS (SELECT ...)`
+)
+
+// exprFrame は解析中の部分式 (括弧・関数呼び出し・CASE 式・IN リスト・EXISTS)
+//
+// 部分式の中の演算子・被演算子は、フレーム開始時のスタックの位置 (opBase, nodeBase) より上に積まれる
+type exprFrame struct {
+	kind     exprFrameKind
+	opBase   int           // フレーム開始時の opStack の長さ
+	nodeBase int           // フレーム開始時の nodeStack の長さ
+	name     string        // 関数名 (frameFunc)
+	args     []ast.Expr    // 確定した引数 (frameFunc) / IN リストの値 (frameIn)
+	operand  ast.Expr      // IN の左辺 (frameIn)
+	not      bool          // NOT IN かどうか (frameIn)
+	star     bool          // `COUNT(*)` の "*" を指定したかどうか (frameFunc)
+	caseExpr *ast.CaseExpr // 構築中の CASE 式 (frameCase)
+	casePart string        // CASE 式の解析中の部分 (CASE / WHEN / THEN / ELSE) (frameCase)
+}
+
+// isAggregate は集約関数の引数のフレームかどうかを返す
+func (f *exprFrame) isAggregate() bool {
+	if f.kind != frameFunc {
+		return false
+	}
+	_, ok := parseAggregateFunc(f.name)
+	return ok
+}
+
+// WhereParser は式のパース処理を行う
+//
+// WHERE / ON / HAVING 句の条件式のほか、SELECT リストや UPDATE の SET 句の値の式のパースにも使用する
+// 演算子の優先順位は操車場アルゴリズム (nodeStack と opStack) で処理する
 type WhereParser struct {
-	whereClause *ast.WhereClause // 現在構築中の WHERE 句
-	nodeStack   []any            // WHERE 句の AST ノードが格納されるスタック
-	opStack     []string         // WHERE 句の演算子が格納されるスタック
+	whereClause   *ast.WhereClause // 現在構築中の WHERE 句
+	nodeStack     []ast.Expr       // 式の AST ノードが格納されるスタック
+	opStack       []string         // 式の演算子が格納されるスタック
+	frames        []*exprFrame     // 解析中の部分式 (括弧・関数呼び出し・CASE 式・IN リスト) のスタック
+	expectOperand bool             // 次のトークンとして被演算子を期待しているかどうか
+	afterIdent    bool             // 直前のトークンが識別子かどうか (関数呼び出しの判定に使う)
+	pendingNot    bool             // 被演算子の後に NOT を受け取り、IN / BETWEEN / LIKE を待っているかどうか
+	pendingIn     *exprFrame       // IN / EXISTS を受け取り、"(" を待っている部分式 (IN リスト・EXISTS のサブクエリ)
+	window        *WindowParser    // 解析中のウィンドウ関数の OVER 句 (nil なら OVER 句の外)
 }
 
 // initWhere は WHERE 句のパースを開始する (WHERE キーワードを検出した時点で呼ぶ)
 func (wp *WhereParser) initWhere() *ast.WhereClause {
+	wp.initExpr()
 	wp.whereClause = &ast.WhereClause{}
-	wp.nodeStack = []any{}
-	wp.opStack = []string{}
 	return wp.whereClause
 }
 
+// initExpr は式のパースを開始する
+func (wp *WhereParser) initExpr() {
+	wp.whereClause = nil
+	wp.nodeStack = []ast.Expr{}
+	wp.opStack = []string{}
+	wp.frames = nil
+	wp.expectOperand = true
+	wp.afterIdent = false
+	wp.pendingNot = false
+	wp.pendingIn = nil
+	wp.window = nil
+}
+
+// isEmpty はまだトークンを受け取っていないかどうかを返す
+func (wp *WhereParser) isEmpty() bool {
+	return len(wp.nodeStack) == 0 && len(wp.opStack) == 0 && len(wp.frames) == 0 && wp.pendingIn == nil && wp.window == nil
+}
+
+// inNested は括弧・関数呼び出し・CASE 式・OVER 句の中を解析中かどうかを返す
+//
+// 部分式の中の "," や キーワードは式の区切りではないため、呼び出し元の判定に使う
+func (wp *WhereParser) inNested() bool {
+	return len(wp.frames) > 0 || wp.window != nil
+}
+
+// inWindow はウィンドウ関数の OVER 句を解析中かどうかを返す
+//
+// OVER 句の中のキーワード (PARTITION BY, ORDER BY, ROWS BETWEEN ... AND ... など) は句の区切りではないため、呼び出し元の判定に使う
+func (wp *WhereParser) inWindow() bool {
+	return wp.window != nil
+}
+
 // pushColumn は識別子をカラム名としてスタックに積む
 //
 // "table.column" 形式の修飾名にも対応する
-func (wp *WhereParser) pushColumn(ident string) {
-	colId := parseColumnId(ident)
-	wp.nodeStack = append(wp.nodeStack, colId)
+// 直後に "(" が来た場合は関数名として扱う
+func (wp *WhereParser) pushColumn(ident string) error {
+	if wp.window != nil {
+		return wp.window.onIdentifier(ident)
+	}
+	if err := wp.checkPending(); err != nil {
+		return err
+	}
+	if !wp.expectOperand {
+		if f := wp.currentFrame(); f != nil && f.isAggregate() {
+			return errors.New("[parse error] unexpected identifier in aggregate function: " + ident)
+		}
+		return errors.New("[parse error] unexpected identifier in expression: " + ident)
+	}
+	wp.pushOperand(parseColumnId(ident))
+	wp.afterIdent = true
+	return nil
 }
 
 // pushLiteral はリテラルをスタックに積む
-func (wp *WhereParser) pushLiteral(lit ast.Literal) {
-	wp.nodeStack = append(wp.nodeStack, lit)
+func (wp *WhereParser) pushLiteral(lit ast.Literal) error {
+	if wp.window != nil {
+		return wp.window.onNumber(lit.ToString())
+	}
+	if err := wp.checkPending(); err != nil {
+		return err
+	}
+	if !wp.expectOperand {
+		return errors.New("[parse error] unexpected literal in expression: " + lit.ToString())
+	}
+	wp.pushOperand(lit)
+	return nil
 }
 
-// handleOperator は演算子を処理する
+// pushOperand は被演算子をスタックに積む
+func (wp *WhereParser) pushOperand(expr ast.Expr) {
+	wp.nodeStack = append(wp.nodeStack, expr)
+	wp.expectOperand = false
+	wp.afterIdent = false
+}
+
+// handleOperator は演算子・括弧・"," を処理する
 func (wp *WhereParser) handleOperator(op string) error {
-	// 新しい演算子を積む前に、スタックにある「優先順位が高い or 同じ」演算子を処理する
-	for len(wp.opStack) > 0 {
-		topOp := wp.opStack[len(wp.opStack)-1]
-		if wp.precedence(topOp) >= wp.precedence(op) {
-			if err := wp.reduce(); err != nil {
-				return err
-			}
-		} else {
-			break
+	if wp.window != nil {
+		return wp.handleWindowSymbol(op)
+	}
+	afterIdent := wp.afterIdent
+	wp.afterIdent = false
+
+	// IN / EXISTS の直後の "(" は IN リスト・EXISTS のサブクエリの開始
+	if f := wp.pendingIn; f != nil && op == string(SLeftParen) {
+		wp.pendingIn = nil
+		f.opBase = len(wp.opStack)
+		f.nodeBase = len(wp.nodeStack)
+		wp.frames = append(wp.frames, f)
+		wp.expectOperand = true
+		return nil
+	}
+	if err := wp.checkPending(); err != nil {
+		return err
+	}
+
+	switch op {
+	case string(SLeftParen):
+		return wp.openParen(afterIdent)
+	case string(SRightParen):
+		return wp.closeParen()
+	case string(SComma):
+		if f := wp.currentFrame(); f != nil && f.isAggregate() {
+			// 集約関数の引数は 1 つのみ
+			return errors.New("[parse error] unexpected symbol in aggregate function: " + op)
+		}
+		return wp.nextArg()
+	}
+
+	// 集約関数の引数には "*" (COUNT(*)) とカラム名のみ指定できる
+	if f := wp.currentFrame(); f != nil && f.isAggregate() {
+		if op != string(SAsterisk) || !wp.expectOperand || len(wp.nodeStack) > f.nodeBase || f.star {
+			return errors.New("[parse error] unexpected symbol in aggregate function: " + op)
+		}
+		if fn, _ := parseAggregateFunc(f.name); fn != ast.AggregateCount {
+			return errors.New("[parse error] " + string(fn) + "(*) is not supported")
+		}
+		f.star = true
+		wp.expectOperand = false
+		return nil
+	}
+
+	if wp.expectOperand {
+		// 被演算子の位置の "-" / "+" は単項演算子 (e.g. `-price`, `+1`)
+		switch op {
+		case "-":
+			wp.opStack = append(wp.opStack, opNegate)
+			return nil
+		case "+":
+			return nil
+		}
+		return errors.New("[parse error] invalid expression syntax: missing operand before " + op)
+	}
+
+	if wp.precedence(op) < 0 {
+		return errors.New("[parse error] unexpected symbol in expression: " + op)
+	}
+
+	// BETWEEN の下限の後の AND は論理演算子ではなく、下限と上限の区切り
+	if op == KAnd {
+		if err := wp.reduceAbove(precComparison + 1); err != nil {
+			return err
+		}
+		if top := wp.topOp(); top == opBetween || top == opNotBetween {
+			wp.opStack[len(wp.opStack)-1] = top + " " + KAnd
+			wp.expectOperand = true
+			return nil
 		}
 	}
+
+	// 新しい演算子を積む前に、スタックにある「優先順位が高い or 同じ」演算子を処理する
+	if err := wp.reduceAbove(wp.precedence(op)); err != nil {
+		return err
+	}
 	wp.opStack = append(wp.opStack, op)
+	wp.expectOperand = true
+	return nil
+}
+
+// reduceAbove は最も内側の部分式の中で、スタックの上にある優先順位が prec 以上の演算子を処理する
+func (wp *WhereParser) reduceAbove(prec int) error {
+	for len(wp.opStack) > wp.opBase() && wp.precedence(wp.topOp()) >= prec {
+		if err := wp.reduce(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// topOp は最も内側の部分式の中でスタックの一番上にある演算子を返す (ない場合は空文字)
+func (wp *WhereParser) topOp() string {
+	if len(wp.opStack) <= wp.opBase() {
+		return ""
+	}
+	return wp.opStack[len(wp.opStack)-1]
+}
+
+// checkPending は NOT の後の IN / BETWEEN / LIKE や、IN の後の "(" を待っている状態でないことを確認する
+func (wp *WhereParser) checkPending() error {
+	if wp.pendingNot {
+		return errors.New("[parse error] NOT must be followed by IN, BETWEEN or LIKE")
+	}
+	if wp.pendingIn != nil {
+		if wp.pendingIn.kind == frameExists {
+			return errors.New("[parse error] EXISTS must be followed by '('")
+		}
+		return errors.New("[parse error] IN must be followed by '('")
+	}
+	return nil
+}
+
+// handleKeyword は式中のキーワード (IS, NOT, NULL, IN, BETWEEN, LIKE, EXISTS, CASE, WHEN, THEN, ELSE, END, OVER) を処理する
+//
+// `col IS NULL` は BinaryExpr("IS", col, NULL)、`col IS NOT NULL` は BinaryExpr("IS NOT", col, NULL) として扱う
+// `col LIKE 'a%'` は BinaryExpr("LIKE", col, 'a%')、`NOT cond` は UnaryExpr("NOT", cond) として扱う
+// OVER 句の解析中は、OVER 句のキーワードとして扱う
+func (wp *WhereParser) handleKeyword(word string) error {
+	if wp.window != nil {
+		return wp.window.onKeyword(word)
+	}
+	switch word {
+	case KIn, KBetween, KLike:
+		return wp.handlePredicateKeyword(word)
+	}
+	if err := wp.checkPending(); err != nil {
+		return err
+	}
+
+	switch word {
+	case KIs:
+		return wp.handleOperator(KIs)
+	case KNot:
+		switch {
+		case wp.expectOperand && wp.topOp() == KIs:
+			// IS NOT
+			wp.opStack[len(wp.opStack)-1] = opIsNot
+		case wp.expectOperand:
+			// 条件の否定 (NOT cond)
+			wp.opStack = append(wp.opStack, KNot)
+		default:
+			// 被演算子の後の NOT は NOT IN / NOT BETWEEN / NOT LIKE
+			wp.pendingNot = true
+		}
+		return nil
+	case KNull:
+		return wp.pushLiteral(ast.NewNullLiteral())
+	case KExists:
+		// 続く "(" から ")" までを EXISTS のサブクエリとして解析する
+		if !wp.expectOperand {
+			return errors.New("[parse error] unexpected EXISTS in expression")
+		}
+		wp.pendingIn = &exprFrame{kind: frameExists}
+		wp.afterIdent = false
+		return nil
+	case KCase:
+		if !wp.expectOperand {
+			return errors.New("[parse error] unexpected CASE in expression")
+		}
+		wp.frames = append(wp.frames, &exprFrame{
+			kind:     frameCase,
+			opBase:   len(wp.opStack),
+			nodeBase: len(wp.nodeStack),
+			caseExpr: &ast.CaseExpr{},
+			casePart: KCase,
+		})
+		wp.afterIdent = false
+		return nil
+	case KWhen, KThen, KElse, KEnd:
+		return wp.handleCaseKeyword(word)
+	case KOver:
+		return wp.beginWindow()
+	default:
+		return errors.New("[parse error] unexpected keyword in WHERE clause: " + word)
+	}
+}
+
+// beginWindow は OVER キーワードの時点で、直前の関数呼び出しをウィンドウ関数として OVER 句の解析を開始する
+func (wp *WhereParser) beginWindow() error {
+	if wp.expectOperand {
+		return errors.New("[parse error] OVER must follow a window function or an aggregate function")
+	}
+	window, err := NewWindowParser(wp.nodeStack[len(wp.nodeStack)-1])
+	if err != nil {
+		return err
+	}
+	wp.nodeStack = wp.nodeStack[:len(wp.nodeStack)-1]
+	wp.window = window
 	return nil
 }
 
+// handleWindowSymbol は OVER 句の中の記号を処理し、OVER 句の ")" の時点でウィンドウ関数を被演算子として積む
+func (wp *WhereParser) handleWindowSymbol(symbol string) error {
+	if err := wp.window.onSymbol(symbol); err != nil {
+		return err
+	}
+	if wp.window.done() {
+		expr := wp.window.result()
+		wp.window = nil
+		wp.pushOperand(expr)
+	}
+	return nil
+}
+
+// handlePredicateKeyword は IN / BETWEEN / LIKE (直前に NOT がある場合は NOT IN / NOT BETWEEN / NOT LIKE) を処理する
+//
+//   - IN: 左辺を確定して取り出し、続く "(" から ")" までを IN リストとして解析する
+//   - BETWEEN: 演算子として積み、下限の後の AND で上限を待つ演算子に置き換える (handleOperator を参照)
+//   - LIKE: 比較演算子と同じ二項演算子として扱う
+func (wp *WhereParser) handlePredicateKeyword(word string) error {
+	if wp.pendingIn != nil {
+		return wp.checkPending()
+	}
+	if wp.expectOperand {
+		return errors.New("[parse error] invalid expression syntax: missing operand before " + word)
+	}
+	not := wp.pendingNot
+	wp.pendingNot = false
+
+	switch word {
+	case KIn:
+		if err := wp.reduceAbove(precComparison); err != nil {
+			return err
+		}
+		operand := wp.nodeStack[len(wp.nodeStack)-1]
+		wp.nodeStack = wp.nodeStack[:len(wp.nodeStack)-1]
+		wp.pendingIn = &exprFrame{kind: frameIn, operand: operand, not: not}
+		wp.afterIdent = false
+		return nil
+	case KBetween:
+		if err := wp.reduceAbove(precComparison); err != nil {
+			return err
+		}
+		op := opBetween
+		if not {
+			op = opNotBetween
+		}
+		wp.opStack = append(wp.opStack, op)
+		wp.expectOperand = true
+		wp.afterIdent = false
+		return nil
+	default:
+		if not {
+			return wp.handleOperator(opNotLike)
+		}
+		return wp.handleOperator(KLike)
+	}
+}
+
 // finalizeWhere は WHERE 句を確定する (finalize 時に呼ぶ)
 //
 // WHERE 句が未設定の場合は nil を返す
@@ -61,6 +422,51 @@ func (wp *WhereParser) finalizeWhere() (*ast.WhereClause, error) {
 		return nil, nil //nolint:nilnil // WHERE 句なしは nil で表現する
 	}
 
+	expr, err := wp.finalizeExpr("WHERE")
+	if err != nil {
+		return nil, err
+	}
+
+	// 条件式のルートは述語 (比較演算・論理演算・IN・BETWEEN・NOT など) である必要がある
+	if !isPredicate(expr) {
+		return nil, errors.New("[parse error] incomplete expression in WHERE clause")
+	}
+	wp.whereClause.Condition = expr
+
+	return wp.whereClause, nil
+}
+
+// isPredicate は式が述語 (真偽値を返す式) の形をしているかどうかを返す
+func isPredicate(expr ast.Expr) bool {
+	switch e := expr.(type) {
+	case *ast.BinaryExpr, *ast.InExpr, *ast.BetweenExpr, *ast.ExistsExpr:
+		return true
+	case *ast.UnaryExpr:
+		return e.Operator == KNot
+	default:
+		return false
+	}
+}
+
+// finalizeExpr は式を確定して返す
+//
+// clause はエラーメッセージに使う句の名前 (e.g. "WHERE", "SELECT")
+func (wp *WhereParser) finalizeExpr(clause string) (ast.Expr, error) {
+	if err := wp.checkPending(); err != nil {
+		return nil, err
+	}
+	if wp.window != nil {
+		return nil, errors.New("[parse error] missing ')' in OVER clause")
+	}
+
+	// 閉じられていない部分式がある場合はエラー
+	if f := wp.currentFrame(); f != nil {
+		if f.kind == frameCase {
+			return nil, errors.New("[parse error] missing END in CASE expression")
+		}
+		return nil, errors.New("[parse error] missing ')' in " + clause + " clause")
+	}
+
 	// 残っている演算子をすべて処理
 	for len(wp.opStack) > 0 {
 		if err := wp.reduce(); err != nil {
@@ -68,33 +474,281 @@ func (wp *WhereParser) finalizeWhere() (*ast.WhereClause, error) {
 		}
 	}
 
-	// WHERE 句があるのに式が一つもない場合はエラー
+	// 式が一つもない場合はエラー
 	if len(wp.nodeStack) == 0 {
-		return nil, errors.New("[parse error] empty expression in WHERE clause")
+		return nil, errors.New("[parse error] empty expression in " + clause + " clause")
 	}
 
 	// スタックに複数の要素が残っている場合はエラー
 	if len(wp.nodeStack) != 1 {
-		return nil, errors.New("[parse error] incomplete expression in WHERE clause")
+		return nil, errors.New("[parse error] incomplete expression in " + clause + " clause")
 	}
+	return wp.nodeStack[0], nil
+}
 
-	// 最後に残った式を、WHERE 句のルートの式として設定
-	finalExpr, ok := wp.nodeStack[0].(*ast.BinaryExpr)
-	if !ok {
-		return nil, errors.New("[parse error] invalid expression result")
+// openParen は "(" を処理する
+//
+// 直前のトークンが識別子の場合は関数呼び出し、被演算子の位置の場合は括弧として扱う
+func (wp *WhereParser) openParen(afterIdent bool) error {
+	if afterIdent {
+		// 直前に積んだ識別子を関数名として取り出す
+		colId, ok := wp.nodeStack[len(wp.nodeStack)-1].(ast.ColumnId)
+		if !ok || colId.TableName != "" {
+			return errors.New("[parse error] invalid function name")
+		}
+		wp.nodeStack = wp.nodeStack[:len(wp.nodeStack)-1]
+		wp.frames = append(wp.frames, &exprFrame{
+			kind:     frameFunc,
+			opBase:   len(wp.opStack),
+			nodeBase: len(wp.nodeStack),
+			name:     colId.ColName,
+		})
+		wp.expectOperand = true
+		return nil
 	}
-	wp.whereClause.Condition = finalExpr
 
-	return wp.whereClause, nil
+	if !wp.expectOperand {
+		if f := wp.currentFrame(); f != nil && f.isAggregate() {
+			return errors.New("[parse error] unexpected symbol in aggregate function: " + string(SLeftParen))
+		}
+		return errors.New("[parse error] unexpected symbol in expression: " + string(SLeftParen))
+	}
+	wp.frames = append(wp.frames, &exprFrame{
+		kind:     frameParen,
+		opBase:   len(wp.opStack),
+		nodeBase: len(wp.nodeStack),
+	})
+	return nil
+}
+
+// closeParen は ")" を処理し、括弧・関数呼び出し・IN リストを 1 つの式にまとめる
+func (wp *WhereParser) closeParen() error {
+	f := wp.currentFrame()
+	if f == nil || f.kind == frameCase {
+		return errors.New("[parse error] unexpected symbol in expression: " + string(SRightParen))
+	}
+	if f.kind == frameExists {
+		return errors.New("[parse error] EXISTS must be followed by a subquery")
+	}
+
+	if f.kind == frameParen {
+		expr, err := wp.popFrameOperand(f)
+		if err != nil {
+			return err
+		}
+		wp.frames = wp.frames[:len(wp.frames)-1]
+		wp.pushOperand(expr)
+		return nil
+	}
+
+	if f.kind == frameIn {
+		if err := wp.nextArg(); err != nil {
+			return err
+		}
+		wp.frames = wp.frames[:len(wp.frames)-1]
+		wp.pushOperand(ast.NewInExpr(f.operand, f.args, f.not))
+		return nil
+	}
+
+	// 関数呼び出し: 最後の引数を確定する
+	if len(wp.nodeStack) > f.nodeBase || len(wp.opStack) > f.opBase || len(f.args) > 0 {
+		if err := wp.nextArg(); err != nil {
+			return err
+		}
+	}
+	wp.frames = wp.frames[:len(wp.frames)-1]
+
+	fn, isAggregate := parseAggregateFunc(f.name)
+	if !isAggregate {
+		wp.pushOperand(ast.NewFuncCallExpr(f.name, f.args))
+		return nil
+	}
+
+	// 集約関数: 引数は "*" (COUNT(*)) かカラム名 1 つ
+	if f.star {
+		wp.pushOperand(ast.NewAggregateExpr(fn, nil))
+		return nil
+	}
+	if len(f.args) == 0 {
+		return errors.New("[parse error] missing argument in " + string(fn) + "()")
+	}
+	colId, ok := f.args[0].(ast.ColumnId)
+	if !ok || len(f.args) != 1 {
+		return errors.New("[parse error] unexpected argument in aggregate function: " + ast.ExprString(f.args[0]))
+	}
+	wp.pushOperand(ast.NewAggregateExpr(fn, &colId))
+	return nil
+}
+
+// beginSubquery は部分式の中でサブクエリを開始できるかどうかを確認する (サブクエリの SELECT キーワードの時点で呼ぶ)
+//
+// サブクエリは開いたばかりの括弧・IN リスト・EXISTS の "(" の直後にのみ指定できる
+func (wp *WhereParser) beginSubquery() error {
+	f := wp.currentFrame()
+	if f == nil || (f.kind != frameParen && f.kind != frameIn && f.kind != frameExists) ||
+		!wp.expectOperand || len(wp.nodeStack) > f.nodeBase || len(wp.opStack) > f.opBase || len(f.args) > 0 {
+		return errors.New("[parse error] SELECT is in invalid position")
+	}
+	return nil
+}
+
+// endSubquery はサブクエリの ")" を処理し、サブクエリを開始した部分式を 1 つの式にまとめる
+//
+//   - 括弧: スカラーサブクエリ (SubqueryExpr)
+//   - IN リスト: IN (SELECT ...) (InExpr)
+//   - EXISTS: EXISTS (SELECT ...) (ExistsExpr)
+func (wp *WhereParser) endSubquery(stmt *ast.SelectStmt) {
+	f := wp.currentFrame()
+	wp.frames = wp.frames[:len(wp.frames)-1]
+	switch f.kind {
+	case frameIn:
+		wp.pushOperand(ast.NewInSubqueryExpr(f.operand, stmt, f.not))
+	case frameExists:
+		wp.pushOperand(ast.NewExistsExpr(stmt))
+	default:
+		wp.pushOperand(ast.NewSubqueryExpr(stmt))
+	}
+}
+
+// nextArg は関数呼び出しの引数・IN リストの値を 1 つ確定する ("," または ")" の時点で呼ぶ)
+func (wp *WhereParser) nextArg() error {
+	f := wp.currentFrame()
+	if f == nil || (f.kind != frameFunc && f.kind != frameIn) {
+		return errors.New("[parse error] unexpected symbol in expression: " + string(SComma))
+	}
+	arg, err := wp.popFrameOperand(f)
+	if err != nil {
+		if f.kind == frameIn {
+			return errors.New("[parse error] missing value in IN list")
+		}
+		return errors.New("[parse error] missing argument in " + strings.ToUpper(f.name) + "()")
+	}
+	f.args = append(f.args, arg)
+	wp.expectOperand = true
+	return nil
+}
+
+// handleCaseKeyword は CASE 式の WHEN / THEN / ELSE / END を処理する
+//
+// 各キーワードの時点で、直前の部分 (CASE の被演算子、WHEN の条件、THEN / ELSE の結果) を確定する
+func (wp *WhereParser) handleCaseKeyword(word string) error {
+	f := wp.currentFrame()
+	if f == nil || f.kind != frameCase {
+		return errors.New("[parse error] " + word + " is in invalid position")
+	}
+	invalid := errors.New("[parse error] invalid CASE expression: unexpected " + word)
+
+	// 直前の部分の式 (CASE の直後に WHEN が来た場合は被演算子なし)
+	var part ast.Expr
+	if f.casePart != KCase || len(wp.nodeStack) > f.nodeBase || len(wp.opStack) > f.opBase {
+		expr, err := wp.popFrameOperand(f)
+		if err != nil {
+			return invalid
+		}
+		part = expr
+	}
+
+	c := f.caseExpr
+	switch {
+	case word == KWhen && (f.casePart == KCase || f.casePart == KThen):
+		if f.casePart == KCase {
+			c.Operand = part
+		} else {
+			c.Whens[len(c.Whens)-1].Result = part
+		}
+	case word == KThen && f.casePart == KWhen:
+		c.Whens = append(c.Whens, &ast.WhenClause{Condition: part})
+	case word == KElse && f.casePart == KThen:
+		c.Whens[len(c.Whens)-1].Result = part
+	case word == KEnd && f.casePart == KThen:
+		c.Whens[len(c.Whens)-1].Result = part
+	case word == KEnd && f.casePart == KElse:
+		c.Else = part
+	default:
+		return invalid
+	}
+
+	if word == KEnd {
+		wp.frames = wp.frames[:len(wp.frames)-1]
+		wp.pushOperand(c)
+		return nil
+	}
+	f.casePart = word
+	wp.expectOperand = true
+	return nil
 }
 
-// スタックから要素を取り出し、1 つの BinaryExpr を作って nodeStack に戻す
+// popFrameOperand はフレーム内の演算子をすべて処理し、フレーム内の式 (1 つ) を取り出す
+func (wp *WhereParser) popFrameOperand(f *exprFrame) (ast.Expr, error) {
+	for len(wp.opStack) > f.opBase {
+		if err := wp.reduce(); err != nil {
+			return nil, err
+		}
+	}
+	if len(wp.nodeStack) != f.nodeBase+1 {
+		return nil, errors.New("[parse error] invalid expression syntax")
+	}
+	expr := wp.nodeStack[len(wp.nodeStack)-1]
+	wp.nodeStack = wp.nodeStack[:len(wp.nodeStack)-1]
+	return expr, nil
+}
+
+// currentFrame は解析中の最も内側の部分式を返す (部分式の外の場合は nil)
+func (wp *WhereParser) currentFrame() *exprFrame {
+	if len(wp.frames) == 0 {
+		return nil
+	}
+	return wp.frames[len(wp.frames)-1]
+}
+
+// opBase は最も内側の部分式の開始時の opStack の長さを返す
+func (wp *WhereParser) opBase() int {
+	if f := wp.currentFrame(); f != nil {
+		return f.opBase
+	}
+	return 0
+}
+
+// スタックから要素を取り出し、1 つの式を作って nodeStack に戻す
 //
 // e.g.
 //   - nodeStack: [name, "john"], opStack: ["="] -> nodeStack: [BinaryExpr(name = "john")]
 //   - nodeStack: [age, 30, BinaryExpr(name = "john")], opStack: [">", "AND"] -> nodeStack: [BinaryExpr(age > 30 AND name = "john")]
+//   - nodeStack: [price], opStack: ["NEG"] -> nodeStack: [UnaryExpr(-price)]
+//   - nodeStack: [age, 20, 30], opStack: ["BETWEEN AND"] -> nodeStack: [BetweenExpr(age BETWEEN 20 AND 30)]
 func (wp *WhereParser) reduce() error {
-	if len(wp.nodeStack) < 2 || len(wp.opStack) < 1 {
+	if len(wp.opStack) < 1 {
+		return errors.New("[parse error] invalid expression syntax")
+	}
+
+	switch top := wp.opStack[len(wp.opStack)-1]; top {
+	case opNegate, KNot:
+		// 単項演算子
+		if len(wp.nodeStack) < 1 {
+			return errors.New("[parse error] invalid expression syntax")
+		}
+		wp.opStack = wp.opStack[:len(wp.opStack)-1]
+		operator := "-"
+		if top == KNot {
+			operator = KNot
+		}
+		operand := wp.nodeStack[len(wp.nodeStack)-1]
+		wp.nodeStack[len(wp.nodeStack)-1] = ast.NewUnaryExpr(operator, operand)
+		return nil
+	case opBetween, opNotBetween:
+		return errors.New("[parse error] missing AND in BETWEEN")
+	case opBetweenAnd, opNotBetweenAnd:
+		if len(wp.nodeStack) < 3 {
+			return errors.New("[parse error] invalid expression syntax")
+		}
+		wp.opStack = wp.opStack[:len(wp.opStack)-1]
+		n := len(wp.nodeStack)
+		between := ast.NewBetweenExpr(wp.nodeStack[n-3], wp.nodeStack[n-2], wp.nodeStack[n-1], top == opNotBetweenAnd)
+		wp.nodeStack = append(wp.nodeStack[:n-3], between)
+		return nil
+	}
+
+	if len(wp.nodeStack) < 2 {
 		return errors.New("[parse error] invalid expression syntax")
 	}
 
@@ -117,8 +771,14 @@ func (wp *WhereParser) reduce() error {
 	switch v := leftRaw.(type) {
 	case ast.ColumnId:
 		lhs = ast.NewLhsColumn(v)
+	case ast.Literal:
+		lhs = ast.NewLhsLiteral(v)
+	case *ast.AggregateExpr:
+		lhs = ast.NewLhsAggregate(v)
 	case *ast.BinaryExpr:
 		lhs = ast.NewLhsExpr(v)
+	case ast.LHS:
+		lhs = v
 	default:
 		return errors.New("[parse error] invalid left operand type")
 	}
@@ -129,12 +789,23 @@ func (wp *WhereParser) reduce() error {
 		rhs = ast.NewRhsColumn(v)
 	case ast.Literal:
 		rhs = ast.NewRhsLiteral(v)
+	case *ast.AggregateExpr:
+		rhs = ast.NewRhsAggregate(v)
 	case *ast.BinaryExpr:
 		rhs = ast.NewRhsExpr(v)
+	case ast.RHS:
+		rhs = v
 	default:
 		return errors.New("[parse error] invalid right operand type")
 	}
 
+	// IS / IS NOT の右辺は NULL のみ
+	if op == KIs || op == opIsNot {
+		if _, ok := rightRaw.(*ast.NullLiteral); !ok {
+			return errors.New("[parse error] " + op + " must be followed by NULL")
+		}
+	}
+
 	// BinaryExpr を作成してスタックに積む (これが次の演算の左辺や右辺になる)
 	expr := ast.NewBinaryExpr(op, lhs, rhs)
 	wp.nodeStack = append(wp.nodeStack, expr)
@@ -143,15 +814,26 @@ func (wp *WhereParser) reduce() error {
 }
 
 // precedence は演算子の優先順位を定義 (数値が高いほど優先順位が高い)
+//
+// 演算子でない場合は -1 を返す
 func (wp *WhereParser) precedence(op string) int {
 	switch strings.ToUpper(op) {
-	case string(SEqual), string(SLessThan), string(SGreaterThan), "<=", ">=", "!=", "<>":
-		return 2 // 比較演算子
+	case opNegate:
+		return 6 // 単項演算子
+	case string(SAsterisk), string(SSlash), string(SPercent):
+		return 5 // 算術演算子 (乗除)
+	case string(SPlus), string(SMinus):
+		return 4 // 算術演算子 (加減)
+	case string(SEqual), string(SLessThan), string(SGreaterThan), "<=", ">=", "!=", "<>", KIs, opIsNot,
+		KLike, opNotLike, opBetween, opNotBetween, opBetweenAnd, opNotBetweenAnd:
+		return precComparison // 比較演算子
+	case KNot:
+		return 2 // 論理演算子 (否定)
 	case KAnd:
 		return 1 // 論理演算子
 	case KOr:
 		return 0 // 論理演算子
 	default:
-		return 0
+		return -1
 	}
 }
@@ -28,7 +28,7 @@ func TestParserWhere(t *testing.T) {
 
 		assert.NotNil(t, selectStmt.Where)
 
-		binaryExpr := selectStmt.Where.Condition
+		binaryExpr := selectStmt.Where.Condition.(*ast.BinaryExpr)
 		assert.Equal(t, "AND", binaryExpr.Operator)
 
 		// 左辺: id = '1'
@@ -75,7 +75,7 @@ func TestParserWhere(t *testing.T) {
 		selectStmt, ok := result.(*ast.SelectStmt)
 		assert.True(t, ok)
 
-		binaryExpr := selectStmt.Where.Condition
+		binaryExpr := selectStmt.Where.Condition.(*ast.BinaryExpr)
 		assert.Equal(t, "OR", binaryExpr.Operator)
 
 		// 左辺: id = '1'
@@ -124,7 +124,7 @@ func TestParserWhere(t *testing.T) {
 		assert.True(t, ok)
 
 		// ルートは OR
-		orExpr := selectStmt.Where.Condition
+		orExpr := selectStmt.Where.Condition.(*ast.BinaryExpr)
 		assert.Equal(t, "OR", orExpr.Operator)
 
 		// OR の左辺: a = '1'
@@ -180,7 +180,7 @@ func TestParserWhere(t *testing.T) {
 		assert.True(t, ok)
 
 		// ルートは AND (右側の AND)
-		rootExpr := selectStmt.Where.Condition
+		rootExpr := selectStmt.Where.Condition.(*ast.BinaryExpr)
 		assert.Equal(t, "AND", rootExpr.Operator)
 
 		// 左辺: (a = '1') AND (b = '2')
@@ -229,7 +229,7 @@ func TestParserWhere(t *testing.T) {
 		selectStmt, ok := result.(*ast.SelectStmt)
 		assert.True(t, ok)
 
-		binaryExpr := selectStmt.Where.Condition
+		binaryExpr := selectStmt.Where.Condition.(*ast.BinaryExpr)
 		assert.Equal(t, ">", binaryExpr.Operator)
 	})
 
@@ -248,7 +248,7 @@ func TestParserWhere(t *testing.T) {
 		selectStmt, ok := result.(*ast.SelectStmt)
 		assert.True(t, ok)
 
-		binaryExpr := selectStmt.Where.Condition
+		binaryExpr := selectStmt.Where.Condition.(*ast.BinaryExpr)
 		assert.Equal(t, "=", binaryExpr.Operator)
 
 		rhsLit, ok := binaryExpr.Right.(*ast.RhsLiteral)
@@ -272,7 +272,7 @@ func TestParserWhere(t *testing.T) {
 		assert.True(t, ok)
 
 		// ルートは AND
-		andExpr := selectStmt.Where.Condition
+		andExpr := selectStmt.Where.Condition.(*ast.BinaryExpr)
 		assert.Equal(t, "AND", andExpr.Operator)
 
 		// AND の左辺: a <> '1'
@@ -305,11 +305,204 @@ func TestParserWhere(t *testing.T) {
 		selectStmt, ok := result.(*ast.SelectStmt)
 		assert.True(t, ok)
 
-		binaryExpr := selectStmt.Where.Condition
+		binaryExpr := selectStmt.Where.Condition.(*ast.BinaryExpr)
 		assert.Equal(t, ">=", binaryExpr.Operator)
 	})
 
+	t.Run("IS NULL と IS NOT NULL を AND と組み合わせてパースできる", func(t *testing.T) {
+		// GIVEN
+		sql := "SELECT * FROM users WHERE email IS NULL AND name IS NOT NULL;"
+		parser := NewParser()
+
+		// WHEN
+		result, err := parser.Parse(sql)
+
+		// THEN
+		assert.NoError(t, err)
+		selectStmt, ok := result.(*ast.SelectStmt)
+		assert.True(t, ok)
+
+		andExpr := selectStmt.Where.Condition.(*ast.BinaryExpr)
+		assert.Equal(t, "AND", andExpr.Operator)
+
+		// AND の左辺: email IS NULL
+		leftExpr, ok := andExpr.Left.(*ast.LhsExpr)
+		assert.True(t, ok)
+		assert.Equal(t, "IS", leftExpr.Expr.Operator)
+		leftCol, ok := leftExpr.Expr.Left.(*ast.LhsColumn)
+		assert.True(t, ok)
+		assert.Equal(t, "email", leftCol.Column.ColName)
+		leftLit, ok := leftExpr.Expr.Right.(*ast.RhsLiteral)
+		assert.True(t, ok)
+		assert.IsType(t, &ast.NullLiteral{}, leftLit.Literal)
+
+		// AND の右辺: name IS NOT NULL
+		rightExpr, ok := andExpr.Right.(*ast.RhsExpr)
+		assert.True(t, ok)
+		assert.Equal(t, "IS NOT", rightExpr.Expr.Operator)
+		rightLit, ok := rightExpr.Expr.Right.(*ast.RhsLiteral)
+		assert.True(t, ok)
+		assert.IsType(t, &ast.NullLiteral{}, rightLit.Literal)
+	})
+
+	t.Run("比較演算子の右辺に NULL を指定できる", func(t *testing.T) {
+		// GIVEN
+		sql := "SELECT * FROM users WHERE email = NULL;"
+		parser := NewParser()
+
+		// WHEN
+		result, err := parser.Parse(sql)
+
+		// THEN
+		assert.NoError(t, err)
+		selectStmt, ok := result.(*ast.SelectStmt)
+		assert.True(t, ok)
+		binaryExpr := selectStmt.Where.Condition.(*ast.BinaryExpr)
+		assert.Equal(t, "=", binaryExpr.Operator)
+		rhsLit, ok := binaryExpr.Right.(*ast.RhsLiteral)
+		assert.True(t, ok)
+		assert.Equal(t, "NULL", rhsLit.Literal.ToString())
+	})
+
+	t.Run("算術演算・括弧・関数呼び出し・CASE 式を含む WHERE 句をパースできる", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			sql      string
+			expected string // 二項演算の被演算子は括弧で囲んだ表記
+		}{
+			{"* は + より優先される", "SELECT * FROM t WHERE a + b * 2 > c;", "(a + (b * 2)) > c"},
+			{"括弧で優先順位を変更できる", "SELECT * FROM t WHERE (a + b) * 2 > c;", "((a + b) * 2) > c"},
+			{"算術演算は比較演算より優先される", "SELECT * FROM t WHERE a = b - 1 AND c % 2 = 0;", "(a = (b - 1)) AND ((c % 2) = 0)"},
+			{"単項マイナス", "SELECT * FROM t WHERE -a < -(b + 1);", "-a < -(b + 1)"},
+			{"カラム同士の比較", "SELECT * FROM t WHERE t.a = t.b;", "t.a = t.b"},
+			{"関数呼び出し", "SELECT * FROM t WHERE UPPER(name) = CONCAT('A', 'B');", "UPPER(name) = CONCAT(A, B)"},
+			{"CASE 式", "SELECT * FROM t WHERE CASE WHEN a > 0 THEN 'pos' ELSE 'neg' END = 'pos';", "CASE WHEN a > 0 THEN pos ELSE neg END = pos"},
+			{"単純 CASE 式", "SELECT * FROM t WHERE CASE a WHEN 1 THEN b END IS NULL;", "CASE a WHEN 1 THEN b END IS NULL"},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				// GIVEN
+				parser := NewParser()
+
+				// WHEN
+				result, err := parser.Parse(tt.sql)
+
+				// THEN
+				assert.NoError(t, err)
+				selectStmt, ok := result.(*ast.SelectStmt)
+				assert.True(t, ok)
+				assert.Equal(t, tt.expected, ast.ExprString(selectStmt.Where.Condition))
+			})
+		}
+	})
+
+	t.Run("IN・BETWEEN・LIKE・NOT を含む WHERE 句をパースできる", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			sql      string
+			expected string
+		}{
+			{"IN", "SELECT * FROM t WHERE id IN (1, 2, 3);", "id IN (1, 2, 3)"},
+			{"NOT IN", "SELECT * FROM t WHERE id NOT IN (1, a + 1);", "id NOT IN (1, a + 1)"},
+			{"IN の左辺の算術演算は IN より優先される", "SELECT * FROM t WHERE a + 1 IN (2, 3);", "(a + 1) IN (2, 3)"},
+			{"BETWEEN", "SELECT * FROM t WHERE age BETWEEN 20 AND 30;", "age BETWEEN 20 AND 30"},
+			{"BETWEEN の後の AND は論理演算子", "SELECT * FROM t WHERE age BETWEEN 20 AND 30 AND name = 'a';", "(age BETWEEN 20 AND 30) AND (name = a)"},
+			{"NOT BETWEEN の境界に算術演算を指定できる", "SELECT * FROM t WHERE age NOT BETWEEN a - 1 AND a + 1;", "age NOT BETWEEN (a - 1) AND (a + 1)"},
+			{"LIKE", "SELECT * FROM t WHERE name LIKE 'ab%';", "name LIKE ab%"},
+			{"NOT LIKE", "SELECT * FROM t WHERE name NOT LIKE '%b' OR id = 1;", "(name NOT LIKE %b) OR (id = 1)"},
+			{"NOT は比較演算より優先順位が低い", "SELECT * FROM t WHERE NOT a = 1;", "NOT (a = 1)"},
+			{"NOT は AND より優先順位が高い", "SELECT * FROM t WHERE NOT a = 1 AND b = 2;", "(NOT (a = 1)) AND (b = 2)"},
+			{"NOT と括弧", "SELECT * FROM t WHERE NOT (a = 1 OR id IN (2));", "NOT ((a = 1) OR (id IN (2)))"},
+			{"IS NOT NULL", "SELECT * FROM t WHERE NOT a IS NOT NULL;", "NOT (a IS NOT NULL)"},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				// GIVEN
+				parser := NewParser()
+
+				// WHEN
+				result, err := parser.Parse(tt.sql)
+
+				// THEN
+				assert.NoError(t, err)
+				selectStmt, ok := result.(*ast.SelectStmt)
+				assert.True(t, ok)
+				assert.Equal(t, tt.expected, ast.ExprString(selectStmt.Where.Condition))
+			})
+		}
+	})
+
 	t.Run("不正な WHERE 句でエラーになる", func(t *testing.T) {
+		t.Run("IS の後が NULL でない場合", func(t *testing.T) {
+			// GIVEN
+			sql := "SELECT * FROM users WHERE email IS 'a';"
+			parser := NewParser()
+
+			// WHEN
+			result, err := parser.Parse(sql)
+
+			// THEN
+			assert.Error(t, err)
+			assert.Nil(t, result)
+			assert.Contains(t, err.Error(), "IS must be followed by NULL")
+		})
+
+		t.Run("被演算子の後の NOT に IN / BETWEEN / LIKE が続かない場合", func(t *testing.T) {
+			// GIVEN
+			sql := "SELECT * FROM users WHERE email NOT = 'a';"
+			parser := NewParser()
+
+			// WHEN
+			result, err := parser.Parse(sql)
+
+			// THEN
+			assert.Error(t, err)
+			assert.Nil(t, result)
+			assert.Contains(t, err.Error(), "NOT must be followed by IN, BETWEEN or LIKE")
+		})
+
+		t.Run("IN の後に括弧がない場合", func(t *testing.T) {
+			// GIVEN
+			sql := "SELECT * FROM users WHERE id IN 1;"
+			parser := NewParser()
+
+			// WHEN
+			result, err := parser.Parse(sql)
+
+			// THEN
+			assert.Error(t, err)
+			assert.Nil(t, result)
+			assert.Contains(t, err.Error(), "IN must be followed by '('")
+		})
+
+		t.Run("IN リストが空の場合", func(t *testing.T) {
+			// GIVEN
+			sql := "SELECT * FROM users WHERE id IN ();"
+			parser := NewParser()
+
+			// WHEN
+			result, err := parser.Parse(sql)
+
+			// THEN
+			assert.Error(t, err)
+			assert.Nil(t, result)
+			assert.Contains(t, err.Error(), "missing value in IN list")
+		})
+
+		t.Run("BETWEEN に AND がない場合", func(t *testing.T) {
+			// GIVEN
+			sql := "SELECT * FROM users WHERE id BETWEEN 1;"
+			parser := NewParser()
+
+			// WHEN
+			result, err := parser.Parse(sql)
+
+			// THEN
+			assert.Error(t, err)
+			assert.Nil(t, result)
+			assert.Contains(t, err.Error(), "missing AND in BETWEEN")
+		})
+
 		t.Run("WHERE 句の式が不完全な場合 (演算子のみ)", func(t *testing.T) {
 			// GIVEN
 			sql := "SELECT * FROM users WHERE id =;"
@@ -365,5 +558,33 @@ func TestParserWhere(t *testing.T) {
 			assert.Nil(t, result)
 			assert.Contains(t, err.Error(), "expression")
 		})
+
+		t.Run("閉じ括弧がない場合", func(t *testing.T) {
+			// GIVEN
+			sql := "SELECT * FROM users WHERE (id + 1 = 2;"
+			parser := NewParser()
+
+			// WHEN
+			result, err := parser.Parse(sql)
+
+			// THEN
+			assert.Error(t, err)
+			assert.Nil(t, result)
+			assert.Contains(t, err.Error(), "missing ')'")
+		})
+
+		t.Run("CASE 式に END がない場合", func(t *testing.T) {
+			// GIVEN
+			sql := "SELECT * FROM users WHERE CASE WHEN id = 1 THEN 1 ELSE 0 = 1;"
+			parser := NewParser()
+
+			// WHEN
+			result, err := parser.Parse(sql)
+
+			// THEN
+			assert.Error(t, err)
+			assert.Nil(t, result)
+			assert.Contains(t, err.Error(), "missing END")
+		})
 	})
 }
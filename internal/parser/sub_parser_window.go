@@ -0,0 +1,308 @@
+package parser
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/ren-yamanashi/minesql/internal/ast"
+)
+
+// WindowParser はウィンドウ関数の OVER 句 (`OVER ([PARTITION BY ...] [ORDER BY ...] [ROWS ...])`) のパース処理を行う
+//
+// ウィンドウフレームは ROWS のみ指定できる (RANGE は未対応)
+type WindowParser struct {
+	state   parserState         // 現在のステート
+	expr    *ast.WindowFuncExpr // 構築中のウィンドウ関数
+	between bool                // ROWS BETWEEN ... AND ... の形式かどうか
+	inEnd   bool                // フレームの終端 (BETWEEN の AND の後) を解析中かどうか
+}
+
+// NewWindowParser は OVER キーワードの直前の関数呼び出しから、OVER 句のパーサーを作成する
+//
+// 関数呼び出しがウィンドウ関数 (ROW_NUMBER / RANK / DENSE_RANK / LAG / LEAD) または集約関数でない場合はエラーを返す
+func NewWindowParser(fn ast.Expr) (*WindowParser, error) {
+	expr, err := newWindowFuncExpr(fn)
+	if err != nil {
+		return nil, err
+	}
+	expr.Window = &ast.WindowSpec{}
+	return &WindowParser{state: WindowStateOver, expr: expr}, nil
+}
+
+// newWindowFuncExpr は関数呼び出しをウィンドウ関数に変換し、引数を検証する
+//
+//   - ROW_NUMBER() / RANK() / DENSE_RANK(): 引数なし
+//   - LAG(col [, offset [, default]]) / LEAD(...): カラム名、0 以上の整数、リテラル
+func newWindowFuncExpr(fn ast.Expr) (*ast.WindowFuncExpr, error) {
+	switch v := fn.(type) {
+	case *ast.AggregateExpr:
+		return &ast.WindowFuncExpr{Aggregate: v}, nil
+	case *ast.FuncCallExpr:
+		name := ast.WindowFunc(v.Name)
+		switch name {
+		case ast.WindowRowNumber, ast.WindowRank, ast.WindowDenseRank:
+			if len(v.Args) > 0 {
+				return nil, errors.New("[parse error] " + v.Name + "() takes no arguments")
+			}
+			return &ast.WindowFuncExpr{Func: name}, nil
+		case ast.WindowLag, ast.WindowLead:
+			return newOffsetWindowFuncExpr(name, v.Args)
+		}
+		return nil, errors.New("[parse error] " + v.Name + " is not a window function")
+	default:
+		return nil, errors.New("[parse error] OVER must follow a window function or an aggregate function")
+	}
+}
+
+// newOffsetWindowFuncExpr は LAG / LEAD の引数を検証してウィンドウ関数を作成する
+func newOffsetWindowFuncExpr(name ast.WindowFunc, args []ast.Expr) (*ast.WindowFuncExpr, error) {
+	if len(args) < 1 || len(args) > 3 {
+		return nil, errors.New("[parse error] " + string(name) + "() requires 1 to 3 arguments")
+	}
+	col, ok := args[0].(ast.ColumnId)
+	if !ok {
+		return nil, errors.New("[parse error] first argument of " + string(name) + "() must be a column")
+	}
+	expr := &ast.WindowFuncExpr{Func: name, Column: &col, Offset: 1}
+	if len(args) >= 2 {
+		lit, ok := args[1].(*ast.NumberLiteral)
+		if !ok {
+			return nil, errors.New("[parse error] offset of " + string(name) + "() must be a non-negative integer")
+		}
+		offset, err := strconv.ParseUint(lit.Value, 10, 63)
+		if err != nil {
+			return nil, errors.New("[parse error] offset of " + string(name) + "() must be a non-negative integer")
+		}
+		expr.Offset = offset
+	}
+	if len(args) == 3 {
+		lit, ok := args[2].(ast.Literal)
+		if !ok {
+			return nil, errors.New("[parse error] default value of " + string(name) + "() must be a literal")
+		}
+		expr.Default = lit
+	}
+	return expr, nil
+}
+
+// done は OVER 句の ")" まで解析し終えたかどうかを返す
+func (wp *WindowParser) done() bool {
+	return wp.state == WindowStateEnd
+}
+
+// result は確定したウィンドウ関数を返す
+func (wp *WindowParser) result() *ast.WindowFuncExpr {
+	return wp.expr
+}
+
+func (wp *WindowParser) onKeyword(word string) error {
+	spec := wp.expr.Window
+	switch word {
+	case KPartition:
+		if wp.state == WindowStateOpen {
+			wp.state = WindowStatePartition
+			return nil
+		}
+	case KOrder:
+		if wp.state == WindowStateOpen || wp.state == WindowStatePartitionCol {
+			wp.state = WindowStateOrder
+			return nil
+		}
+	case KBy:
+		switch wp.state {
+		case WindowStatePartition:
+			wp.state = WindowStatePartitionBy
+			return nil
+		case WindowStateOrder:
+			wp.state = WindowStateOrderBy
+			return nil
+		}
+	case KAsc, KDesc:
+		if wp.state == WindowStateOrderByCol {
+			spec.OrderBy[len(spec.OrderBy)-1].Desc = word == KDesc
+			wp.state = WindowStateOrderByDir
+			return nil
+		}
+	case KRows, KRange:
+		switch wp.state {
+		case WindowStateOpen, WindowStatePartitionCol, WindowStateOrderByCol, WindowStateOrderByDir:
+			if word == KRange {
+				return errors.New("[parse error] RANGE window frame is not supported")
+			}
+			spec.Frame = &ast.WindowFrame{}
+			wp.state = WindowStateRows
+			return nil
+		}
+	case KBetween:
+		if wp.state == WindowStateRows {
+			wp.between = true
+			wp.state = WindowStateBound
+			return nil
+		}
+	case KUnbounded:
+		if wp.state == WindowStateRows || wp.state == WindowStateBound {
+			wp.state = WindowStateUnbounded
+			return nil
+		}
+	case KCurrent:
+		if wp.state == WindowStateRows || wp.state == WindowStateBound {
+			wp.state = WindowStateCurrent
+			return nil
+		}
+	case KRow:
+		if wp.state == WindowStateCurrent {
+			wp.setBound(ast.FrameBound{Type: ast.FrameCurrentRow})
+			return nil
+		}
+	case KPreceding, KFollowing:
+		switch wp.state {
+		case WindowStateUnbounded:
+			bound := ast.FrameBound{Type: ast.FrameUnboundedPreceding}
+			if word == KFollowing {
+				bound.Type = ast.FrameUnboundedFollowing
+			}
+			wp.setBound(bound)
+			return nil
+		case WindowStateOffset:
+			bound := *wp.pendingBound()
+			bound.Type = ast.FramePreceding
+			if word == KFollowing {
+				bound.Type = ast.FrameFollowing
+			}
+			wp.setBound(bound)
+			return nil
+		}
+	case KAnd:
+		if wp.state == WindowStateBoundEnd && wp.between && !wp.inEnd {
+			wp.inEnd = true
+			wp.state = WindowStateBound
+			return nil
+		}
+	}
+	return errors.New("[parse error] " + word + " keyword is in invalid position in OVER clause")
+}
+
+func (wp *WindowParser) onIdentifier(ident string) error {
+	spec := wp.expr.Window
+	switch wp.state {
+	case WindowStateOver:
+		return errors.New("[parse error] OVER must be followed by '('")
+	case WindowStatePartitionBy:
+		spec.PartitionBy = append(spec.PartitionBy, parseColumnId(ident))
+		wp.state = WindowStatePartitionCol
+		return nil
+	case WindowStateOrderBy:
+		spec.OrderBy = append(spec.OrderBy, ast.OrderByItem{Column: parseColumnId(ident)})
+		wp.state = WindowStateOrderByCol
+		return nil
+	default:
+		return errors.New("[parse error] unexpected identifier in OVER clause: " + ident)
+	}
+}
+
+// onNumber はフレームの境界の行数 (n PRECEDING / n FOLLOWING の n) を処理する
+func (wp *WindowParser) onNumber(num string) error {
+	if wp.state != WindowStateRows && wp.state != WindowStateBound {
+		return errors.New("[parse error] unexpected number in OVER clause: " + num)
+	}
+	offset, err := strconv.ParseUint(num, 10, 63)
+	if err != nil {
+		return errors.New("[parse error] window frame offset must be a non-negative integer: " + num)
+	}
+	wp.pendingBound().Offset = offset
+	wp.state = WindowStateOffset
+	return nil
+}
+
+func (wp *WindowParser) onSymbol(symbol string) error {
+	switch {
+	case wp.state == WindowStateOver:
+		if symbol != string(SLeftParen) {
+			return errors.New("[parse error] OVER must be followed by '('")
+		}
+		wp.state = WindowStateOpen
+		return nil
+	case symbol == string(SComma) && wp.state == WindowStatePartitionCol:
+		wp.state = WindowStatePartitionBy
+		return nil
+	case symbol == string(SComma) && (wp.state == WindowStateOrderByCol || wp.state == WindowStateOrderByDir):
+		wp.state = WindowStateOrderBy
+		return nil
+	case symbol == string(SRightParen):
+		if !wp.canEndSpec() {
+			return errors.New("[parse error] incomplete OVER clause")
+		}
+		if err := wp.validateFrame(); err != nil {
+			return err
+		}
+		wp.state = WindowStateEnd
+		return nil
+	default:
+		return errors.New("[parse error] unexpected symbol in OVER clause: " + symbol)
+	}
+}
+
+// canEndSpec は PARTITION BY / ORDER BY / フレームの指定がそれぞれ完結しているかどうかを返す
+func (wp *WindowParser) canEndSpec() bool {
+	switch wp.state {
+	case WindowStateOpen, WindowStatePartitionCol, WindowStateOrderByCol, WindowStateOrderByDir:
+		return true
+	case WindowStateBoundEnd:
+		// BETWEEN の場合は終端まで指定されている必要がある
+		return !wp.between || wp.inEnd
+	default:
+		return false
+	}
+}
+
+// pendingBound は解析中のフレームの境界 (始端または終端) を返す
+func (wp *WindowParser) pendingBound() *ast.FrameBound {
+	if wp.inEnd {
+		return &wp.expr.Window.Frame.End
+	}
+	return &wp.expr.Window.Frame.Start
+}
+
+// setBound はフレームの境界を確定する
+//
+// BETWEEN を省略した場合 (`ROWS 2 PRECEDING`) は、指定した境界を始端、現在行を終端とする
+func (wp *WindowParser) setBound(bound ast.FrameBound) {
+	*wp.pendingBound() = bound
+	if !wp.between {
+		wp.expr.Window.Frame.End = ast.FrameBound{Type: ast.FrameCurrentRow}
+	}
+	wp.state = WindowStateBoundEnd
+}
+
+// validateFrame はフレームの始端が終端より後ろにないことを確認する
+func (wp *WindowParser) validateFrame() error {
+	frame := wp.expr.Window.Frame
+	if frame == nil {
+		return nil
+	}
+	if frame.Start.Type == ast.FrameUnboundedFollowing {
+		return errors.New("[parse error] window frame cannot start with UNBOUNDED FOLLOWING")
+	}
+	if frame.End.Type == ast.FrameUnboundedPreceding {
+		return errors.New("[parse error] window frame cannot end with UNBOUNDED PRECEDING")
+	}
+	if frame.Start.Type == ast.FrameUnboundedPreceding || frame.End.Type == ast.FrameUnboundedFollowing {
+		return nil
+	}
+	if boundOffset(frame.Start) > boundOffset(frame.End) {
+		return errors.New("[parse error] window frame cannot start after its end: " + frame.Start.String() + " AND " + frame.End.String())
+	}
+	return nil
+}
+
+// boundOffset は現在行を基準とした境界の位置を返す (PRECEDING は負の値、FOLLOWING は正の値)
+func boundOffset(bound ast.FrameBound) int64 {
+	switch bound.Type {
+	case ast.FramePreceding:
+		return -int64(bound.Offset)
+	case ast.FrameFollowing:
+		return int64(bound.Offset)
+	default:
+		return 0
+	}
+}
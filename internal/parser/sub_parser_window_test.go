@@ -0,0 +1,117 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/ren-yamanashi/minesql/internal/ast"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParserWindow(t *testing.T) {
+	t.Run("ウィンドウ関数の PARTITION BY・ORDER BY をパースできる", func(t *testing.T) {
+		// GIVEN
+		sql := "SELECT id, ROW_NUMBER() OVER (PARTITION BY category, maker ORDER BY price DESC, id) FROM items;"
+		parser := NewParser()
+
+		// WHEN
+		result, err := parser.Parse(sql)
+
+		// THEN
+		assert.NoError(t, err)
+		stmt := result.(*ast.SelectStmt)
+		assert.Equal(t, []ast.ColumnId{*ast.NewColumnId("id")}, stmt.Columns)
+		assert.Len(t, stmt.Exprs, 1)
+		assert.Equal(t, 1, stmt.Exprs[0].Pos)
+
+		w := stmt.Exprs[0].Expr.(*ast.WindowFuncExpr)
+		assert.Equal(t, ast.WindowRowNumber, w.Func)
+		assert.Equal(t, []ast.ColumnId{*ast.NewColumnId("category"), *ast.NewColumnId("maker")}, w.Window.PartitionBy)
+		assert.Equal(t, []ast.OrderByItem{{Column: *ast.NewColumnId("price"), Desc: true}, {Column: *ast.NewColumnId("id")}}, w.Window.OrderBy)
+		assert.Nil(t, w.Window.Frame)
+		assert.True(t, stmt.HasWindow())
+		assert.False(t, stmt.HasAggregation())
+	})
+
+	t.Run("各ウィンドウ関数の表記をパースできる", func(t *testing.T) {
+		tests := []struct {
+			name string
+			sql  string
+			want string
+		}{
+			{"空の OVER 句", "SELECT RANK() OVER () FROM items;", "RANK() OVER ()"},
+			{"DENSE_RANK", "SELECT dense_rank() over (order by price) FROM items;", "DENSE_RANK() OVER (ORDER BY price)"},
+			{"LAG の引数を省略", "SELECT LAG(price) OVER (ORDER BY id) FROM items;", "LAG(price) OVER (ORDER BY id)"},
+			{"LEAD の距離とデフォルト値", "SELECT LEAD(items.price, 2, 0) OVER (ORDER BY id) FROM items;", "LEAD(items.price, 2, 0) OVER (ORDER BY id)"},
+			{"集約関数", "SELECT COUNT(*) OVER (PARTITION BY category) FROM items;", "COUNT(*) OVER (PARTITION BY category)"},
+			{"BETWEEN を省略したフレーム", "SELECT SUM(price) OVER (ORDER BY id ROWS 2 PRECEDING) FROM items;", "SUM(price) OVER (ORDER BY id ROWS BETWEEN 2 PRECEDING AND CURRENT ROW)"},
+			{"BETWEEN のフレーム", "SELECT AVG(price) OVER (ORDER BY id ROWS BETWEEN 1 PRECEDING AND 1 FOLLOWING) FROM items;", "AVG(price) OVER (ORDER BY id ROWS BETWEEN 1 PRECEDING AND 1 FOLLOWING)"},
+			{"UNBOUNDED のフレーム", "SELECT MAX(price) OVER (ROWS BETWEEN CURRENT ROW AND UNBOUNDED FOLLOWING) FROM items;", "MAX(price) OVER (ROWS BETWEEN CURRENT ROW AND UNBOUNDED FOLLOWING)"},
+			{"式の中のウィンドウ関数", "SELECT price - LAG(price) OVER (ORDER BY id) FROM items;", "price - LAG(price) OVER (ORDER BY id)"},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				// GIVEN
+				parser := NewParser()
+
+				// WHEN
+				result, err := parser.Parse(tt.sql)
+
+				// THEN
+				assert.NoError(t, err)
+				stmt := result.(*ast.SelectStmt)
+				assert.Len(t, stmt.Exprs, 1)
+				assert.Equal(t, tt.want, ast.ExprString(stmt.Exprs[0].Expr))
+			})
+		}
+	})
+
+	t.Run("ウィンドウ関数に別名を付け、続けて SELECT リストの項目を指定できる", func(t *testing.T) {
+		// GIVEN
+		sql := "SELECT SUM(price) OVER (PARTITION BY category ORDER BY id) AS running, id FROM items ORDER BY id;"
+		parser := NewParser()
+
+		// WHEN
+		result, err := parser.Parse(sql)
+
+		// THEN
+		assert.NoError(t, err)
+		stmt := result.(*ast.SelectStmt)
+		assert.Equal(t, map[int]string{0: "running"}, stmt.Aliases)
+		assert.Nil(t, stmt.Aggregates)
+		assert.Equal(t, "SELECT SUM(price) OVER (PARTITION BY category ORDER BY id) AS running, id FROM items ORDER BY id", ast.SelectString(stmt))
+	})
+
+	t.Run("不正なウィンドウ関数はエラーになる", func(t *testing.T) {
+		tests := []struct {
+			name string
+			sql  string
+			err  string
+		}{
+			{"ウィンドウ関数でない関数", "SELECT UPPER(name) OVER () FROM items;", "[parse error] UPPER is not a window function"},
+			{"関数呼び出しでない式", "SELECT id OVER () FROM items;", "[parse error] OVER must follow a window function or an aggregate function"},
+			{"ROW_NUMBER の引数", "SELECT ROW_NUMBER(id) OVER () FROM items;", "[parse error] ROW_NUMBER() takes no arguments"},
+			{"LAG の引数がない", "SELECT LAG() OVER () FROM items;", "[parse error] LAG() requires 1 to 3 arguments"},
+			{"LAG の距離が整数でない", "SELECT LAG(price, id) OVER () FROM items;", "[parse error] offset of LAG() must be a non-negative integer"},
+			{"OVER の後に括弧がない", "SELECT RANK() OVER w FROM items;", "[parse error] OVER must be followed by '('"},
+			{"OVER 句が閉じていない", "SELECT RANK() OVER (ORDER BY id FROM items;", "[parse error] FROM keyword is in invalid position in OVER clause"},
+			{"RANGE のフレーム", "SELECT SUM(price) OVER (ORDER BY id RANGE UNBOUNDED PRECEDING) FROM items;", "[parse error] RANGE window frame is not supported"},
+			{"BETWEEN の AND がない", "SELECT SUM(price) OVER (ROWS BETWEEN 1 PRECEDING) FROM items;", "[parse error] incomplete OVER clause"},
+			{"UNBOUNDED FOLLOWING で始まる", "SELECT SUM(price) OVER (ROWS BETWEEN UNBOUNDED FOLLOWING AND CURRENT ROW) FROM items;", "[parse error] window frame cannot start with UNBOUNDED FOLLOWING"},
+			{"UNBOUNDED PRECEDING で終わる", "SELECT SUM(price) OVER (ROWS BETWEEN CURRENT ROW AND UNBOUNDED PRECEDING) FROM items;", "[parse error] window frame cannot end with UNBOUNDED PRECEDING"},
+			{"始端が終端より後ろ", "SELECT SUM(price) OVER (ROWS BETWEEN 1 FOLLOWING AND CURRENT ROW) FROM items;", "[parse error] window frame cannot start after its end: 1 FOLLOWING AND CURRENT ROW"},
+			{"WHERE 句のウィンドウ関数", "SELECT id FROM items WHERE RANK() OVER () = 1;", "[parse error] window function is allowed only in SELECT list"},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				// GIVEN
+				parser := NewParser()
+
+				// WHEN
+				_, err := parser.Parse(tt.sql)
+
+				// THEN
+				assert.EqualError(t, err, tt.err)
+			})
+		}
+	})
+}
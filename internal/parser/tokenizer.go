@@ -36,41 +36,110 @@ const (
 	SGreaterThan rune = '>'
 	SExclamation rune = '!'
 	SAsterisk    rune = '*'
+	SPlus        rune = '+'
+	SMinus       rune = '-'
+	SSlash       rune = '/'
+	SPercent     rune = '%'
 )
 
 // Keyword
 const (
-	KSelect      = "SELECT"
-	KFrom        = "FROM"
-	KWhere       = "WHERE"
-	KInsert      = "INSERT"
-	KInto        = "INTO"
-	KValues      = "VALUES"
-	KCreate      = "CREATE"
-	KTable       = "TABLE"
-	KPrimary     = "PRIMARY"
-	KUnique      = "UNIQUE"
-	KKey         = "KEY"
-	KVarchar     = "VARCHAR"
-	KDelete      = "DELETE"
-	KUpdate      = "UPDATE"
-	KSet         = "SET"
-	KAnd         = "AND"
-	KOr          = "OR"
-	KJoin        = "JOIN"
-	KInner       = "INNER"
-	KOn          = "ON"
-	KBegin       = "BEGIN"
-	KCommit      = "COMMIT"
-	KRollback    = "ROLLBACK"
-	KForeign     = "FOREIGN"
-	KReferences  = "REFERENCES"
-	KAlter       = "ALTER"
-	KUser        = "USER"
-	KIdentified  = "IDENTIFIED"
-	KBy          = "BY"
-	KStart       = "START"
-	KTransaction = "TRANSACTION"
+	KSelect        = "SELECT"
+	KFrom          = "FROM"
+	KWhere         = "WHERE"
+	KInsert        = "INSERT"
+	KInto          = "INTO"
+	KValues        = "VALUES"
+	KCreate        = "CREATE"
+	KTable         = "TABLE"
+	KPrimary       = "PRIMARY"
+	KUnique        = "UNIQUE"
+	KKey           = "KEY"
+	KVarchar       = "VARCHAR"
+	KInt           = "INT"
+	KBigint        = "BIGINT"
+	KDecimal       = "DECIMAL"
+	KDate          = "DATE"
+	KDatetime      = "DATETIME"
+	KDelete        = "DELETE"
+	KUpdate        = "UPDATE"
+	KSet           = "SET"
+	KAnd           = "AND"
+	KOr            = "OR"
+	KNot           = "NOT"
+	KIs            = "IS"
+	KNull          = "NULL"
+	KIn            = "IN"
+	KBetween       = "BETWEEN"
+	KLike          = "LIKE"
+	KJoin          = "JOIN"
+	KInner         = "INNER"
+	KLeft          = "LEFT"
+	KRight         = "RIGHT"
+	KOuter         = "OUTER"
+	KOn            = "ON"
+	KBegin         = "BEGIN"
+	KCommit        = "COMMIT"
+	KRollback      = "ROLLBACK"
+	KForeign       = "FOREIGN"
+	KReferences    = "REFERENCES"
+	KAlter         = "ALTER"
+	KUser          = "USER"
+	KIdentified    = "IDENTIFIED"
+	KBy            = "BY"
+	KOrder         = "ORDER"
+	KAsc           = "ASC"
+	KDesc          = "DESC"
+	KLimit         = "LIMIT"
+	KOffset        = "OFFSET"
+	KGroup         = "GROUP"
+	KHaving        = "HAVING"
+	KStart         = "START"
+	KTransaction   = "TRANSACTION"
+	KCase          = "CASE"
+	KWhen          = "WHEN"
+	KThen          = "THEN"
+	KElse          = "ELSE"
+	KEnd           = "END"
+	KExists        = "EXISTS"
+	KAs            = "AS"
+	KExplain       = "EXPLAIN"
+	KAnalyze       = "ANALYZE"
+	KDrop          = "DROP"
+	KTruncate      = "TRUNCATE"
+	KIf            = "IF"
+	KAdd           = "ADD"
+	KColumn        = "COLUMN"
+	KIndex         = "INDEX"
+	KAutoIncrement = "AUTO_INCREMENT"
+	KDefault       = "DEFAULT"
+	KCheck         = "CHECK"
+	KConstraint    = "CONSTRAINT"
+	KCascade       = "CASCADE"
+	KRestrict      = "RESTRICT"
+	KReplace       = "REPLACE"
+	KIgnore        = "IGNORE"
+	KDuplicate     = "DUPLICATE"
+	KDistinct      = "DISTINCT"
+	KUnion         = "UNION"
+	KIntersect     = "INTERSECT"
+	KExcept        = "EXCEPT"
+	KAll           = "ALL"
+	KWith          = "WITH"
+	KRecursive     = "RECURSIVE"
+	KOver          = "OVER"
+	KPartition     = "PARTITION"
+	KRows          = "ROWS"
+	KRange         = "RANGE"
+	KUnbounded     = "UNBOUNDED"
+	KPreceding     = "PRECEDING"
+	KFollowing     = "FOLLOWING"
+	KCurrent       = "CURRENT"
+	KRow           = "ROW"
+	KView          = "VIEW"
+	KDatabase      = "DATABASE"
+	KSchema        = "SCHEMA"
+	KUse           = "USE"
 )
 
 type TokenHandler interface {
@@ -91,11 +160,12 @@ type TokenHandler interface {
 }
 
 type Tokenizer struct {
-	callbacks TokenHandler // イベントの通知先
-	state     state        // 現在のステート
-	input     []rune       // 解析対象の入力文字列
-	pos       int          // 現在の読み取り位置
-	start     int          // 現在のトークン開始位置
+	callbacks   TokenHandler // イベントの通知先
+	state       state        // 現在のステート
+	input       []rune       // 解析対象の入力文字列
+	pos         int          // 現在の読み取り位置
+	start       int          // 現在のトークン開始位置
+	lastOperand bool         // 直前のトークンが被演算子 (識別子、数値、文字列、")" など) かどうか
 }
 
 func NewTokenizer(input string, callbacks TokenHandler) *Tokenizer {
@@ -163,6 +233,13 @@ func (t *Tokenizer) handleData(char rune) {
 			t.start = currentPos + 2
 			return
 		}
+		// 被演算子の直後でない '-' の次に数字が来た場合は、符号付きの数値の一部として扱う (e.g. `VALUES (-5)`, `= -7`)
+		// 被演算子の直後の場合は減算の演算子として扱う (e.g. `a-1`, `price - 1`)
+		t.emitPendingToken()
+		t.start = currentPos
+		if !t.lastOperand && t.isDigit(string(t.peekChar())) {
+			return
+		}
 
 	case CSlash:
 		// `/*` ならブロックコメントの開始
@@ -186,7 +263,7 @@ func (t *Tokenizer) handleData(char rune) {
 			t.emitPendingToken()
 			t.pos++ // 次の文字も消費する
 			symbol := string([]rune{char, t.input[t.pos]})
-			t.callbacks.onSymbol(symbol)
+			t.emitSymbol(symbol)
 			t.start = currentPos + 2
 			return
 		}
@@ -197,7 +274,7 @@ func (t *Tokenizer) handleData(char rune) {
 			t.emitPendingToken()
 			t.pos++ // 次の文字も消費する
 			symbol := string([]rune{char, t.input[t.pos]})
-			t.callbacks.onSymbol(symbol)
+			t.emitSymbol(symbol)
 			t.start = currentPos + 2
 			return
 		}
@@ -210,7 +287,7 @@ func (t *Tokenizer) handleData(char rune) {
 			t.emitPendingToken()
 			t.pos++ // 次の文字も消費する
 			symbol := string([]rune{char, t.input[t.pos]})
-			t.callbacks.onSymbol(symbol)
+			t.emitSymbol(symbol)
 			t.start = currentPos + 2
 			return
 		}
@@ -218,7 +295,7 @@ func (t *Tokenizer) handleData(char rune) {
 	// 上記のいずれにも当てはまらない場合、記号文字かどうかをチェックする
 	if t.isSymbol(char) {
 		t.emitPendingToken()
-		t.callbacks.onSymbol(string(char))
+		t.emitSymbol(string(char))
 		t.start = currentPos + 1
 	}
 }
@@ -227,6 +304,7 @@ func (t *Tokenizer) handleData(char rune) {
 func (t *Tokenizer) handleSingleQuote(char rune) {
 	if char == CSingleQuote {
 		value := string(t.input[t.start:t.pos])
+		t.lastOperand = true
 		t.callbacks.onString(value)
 		t.state = StateData
 		t.start = t.pos + 1
@@ -237,6 +315,7 @@ func (t *Tokenizer) handleSingleQuote(char rune) {
 func (t *Tokenizer) handleDoubleQuote(char rune) {
 	if char == CDoubleQuote {
 		value := string(t.input[t.start:t.pos])
+		t.lastOperand = true
 		t.callbacks.onIdentifier(value)
 		t.state = StateData
 		t.start = t.pos + 1
@@ -279,15 +358,37 @@ func (t *Tokenizer) emitPendingToken() {
 
 	switch {
 	case t.isKeyword(value):
+		// NULL と (CASE 式の) END は値として扱う
+		upperValue := strings.ToUpper(value)
+		t.lastOperand = upperValue == KNull || upperValue == KEnd
 		t.callbacks.onKeyword(value)
-	case t.isDigit(value):
+	case t.isNumber(value):
+		t.lastOperand = true
 		t.callbacks.onNumber(value)
 	default:
+		t.lastOperand = true
 		t.callbacks.onIdentifier(value)
 	}
 }
 
-// isDigit は文字列が数字かどうかを判定する
+// emitSymbol は記号を通知する
+func (t *Tokenizer) emitSymbol(symbol string) {
+	t.lastOperand = symbol == string(SRightParen)
+	t.callbacks.onSymbol(symbol)
+}
+
+// isNumber は文字列が数値 (符号付きの整数または小数) かどうかを判定する
+//
+// e.g. "42", "-7", "3.14", "-0.5"
+func (t *Tokenizer) isNumber(word string) bool {
+	intPart, fracPart, hasPoint := strings.Cut(strings.TrimPrefix(word, string(CDash)), ".")
+	if intPart == "" || (hasPoint && fracPart == "") {
+		return false
+	}
+	return t.isDigit(intPart) && t.isDigit(fracPart)
+}
+
+// isDigit は文字列が数字のみで構成されているかどうかを判定する
 func (t *Tokenizer) isDigit(word string) bool {
 	for _, ch := range word {
 		if ch < '0' || ch > '9' {
@@ -300,18 +401,28 @@ func (t *Tokenizer) isDigit(word string) bool {
 // isKeyword は文字列がキーワードかどうかを判定する
 func (t *Tokenizer) isKeyword(word string) bool {
 	keywords := []string{
-		KSelect, KFrom, KWhere,
-		KInsert, KInto, KValues,
+		KSelect, KFrom, KWhere, KGroup, KHaving, KOrder, KAsc, KDesc, KLimit, KOffset,
+		KDistinct, KUnion, KIntersect, KExcept, KAll, KWith, KRecursive,
+		KOver, KPartition, KRows, KRange, KUnbounded, KPreceding, KFollowing, KCurrent, KRow,
+		KInsert, KInto, KValues, KReplace, KIgnore, KDuplicate,
 		KCreate, KTable, KPrimary, KUnique, KKey,
 		KDelete,
 		KUpdate, KSet,
-		KVarchar,
-		KAnd, KOr,
-		KJoin, KInner, KOn,
+		KVarchar, KInt, KBigint, KDecimal, KDate, KDatetime,
+		KAnd, KOr, KNot, KIs, KNull, KIn, KBetween, KLike, KExists,
+		KJoin, KInner, KLeft, KRight, KOuter, KOn, KAs,
 		KBegin, KCommit, KRollback,
 		KForeign, KReferences,
 		KAlter, KUser, KIdentified, KBy,
 		KStart, KTransaction,
+		KCase, KWhen, KThen, KElse, KEnd,
+		KExplain, KAnalyze,
+		KDrop, KTruncate, KIf, KView,
+		KDatabase, KSchema, KUse,
+		KAdd, KColumn, KIndex,
+		KAutoIncrement, KDefault,
+		KCheck, KConstraint,
+		KCascade, KRestrict,
 	}
 
 	upperWord := strings.ToUpper(word)
@@ -325,7 +436,11 @@ func (t *Tokenizer) isKeyword(word string) bool {
 
 // isSymbol は文字が記号かどうかを判定する
 func (t *Tokenizer) isSymbol(ch rune) bool {
-	symbols := []rune{SLeftParen, SRightParen, SComma, SSemicolon, SEqual, SLessThan, SGreaterThan, SExclamation, SAsterisk}
+	symbols := []rune{
+		SLeftParen, SRightParen, SComma, SSemicolon,
+		SEqual, SLessThan, SGreaterThan, SExclamation,
+		SAsterisk, SPlus, SMinus, SSlash, SPercent,
+	}
 	for _, sym := range symbols {
 		if ch == sym {
 			return true
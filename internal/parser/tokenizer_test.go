@@ -383,6 +383,29 @@ func TestTokenizerNumbers(t *testing.T) {
 		assert.Contains(t, c.symbols, "(")
 		assert.Contains(t, c.symbols, ")")
 	})
+
+	t.Run("負の数と小数を数値として認識する", func(t *testing.T) {
+		// GIVEN
+		sql := "VALUES (-42, 3.14, -0.5)"
+
+		// WHEN
+		c := tokenize(sql)
+
+		// THEN
+		assert.Equal(t, []string{"-42", "3.14", "-0.5"}, c.numbers)
+	})
+
+	t.Run("数値として不完全な文字列は識別子として扱う", func(t *testing.T) {
+		// GIVEN
+		sql := "SELECT 1. , .5 , 1.2.3"
+
+		// WHEN
+		c := tokenize(sql)
+
+		// THEN
+		assert.Empty(t, c.numbers)
+		assert.Equal(t, []string{"1.", ".5", "1.2.3"}, c.identifiers)
+	})
 }
 
 func TestTokenizerWhitespace(t *testing.T) {
@@ -468,26 +491,77 @@ func TestTokenizerEdgeCases(t *testing.T) {
 		assert.Equal(t, 0, len(c.identifiers)) // クォート内容が識別子として漏れない
 	})
 
-	t.Run("ハイフンが単独で出現した場合は識別子として扱われる", func(t *testing.T) {
+	t.Run("ハイフンが単独で出現した場合は記号として扱われる", func(t *testing.T) {
 		// GIVEN: - が単独で出現 (コメントではない)
 		sql := "a - b"
 
 		// WHEN
 		c := tokenize(sql)
 
-		// THEN: - は isSymbol に含まれないため、独立した識別子として扱われる
-		assert.Equal(t, []string{"a", "-", "b"}, c.identifiers)
+		// THEN: - は減算の演算子として扱われる
+		assert.Equal(t, []string{"a", "b"}, c.identifiers)
+		assert.Equal(t, []string{"-"}, c.symbols)
 	})
 
-	t.Run("スラッシュが単独で出現した場合は識別子として扱われる", func(t *testing.T) {
+	t.Run("スラッシュが単独で出現した場合は記号として扱われる", func(t *testing.T) {
 		// GIVEN: / が単独で出現 (ブロックコメント開始ではない)
 		sql := "a / b"
 
 		// WHEN
 		c := tokenize(sql)
 
-		// THEN: / は isSymbol に含まれないため、独立した識別子として扱われる
-		assert.Equal(t, []string{"a", "/", "b"}, c.identifiers)
+		// THEN: / は除算の演算子として扱われる
+		assert.Equal(t, []string{"a", "b"}, c.identifiers)
+		assert.Equal(t, []string{"/"}, c.symbols)
+	})
+
+	t.Run("被演算子の直後のハイフンは減算、それ以外は数値の符号として扱われる", func(t *testing.T) {
+		tests := []struct {
+			name    string
+			sql     string
+			numbers []string
+			symbols []string
+		}{
+			{"識別子の直後", "a-1", []string{"1"}, []string{"-"}},
+			{"数値の直後", "3-1", []string{"3", "1"}, []string{"-"}},
+			{"閉じ括弧の直後", "(a)-1", []string{"1"}, []string{"(", ")", "-"}},
+			{"演算子の直後", "a - -1", []string{"-1"}, []string{"-"}},
+			{"開き括弧の直後", "(-5)", []string{"-5"}, []string{"(", ")"}},
+			{"キーワードの直後", "LIMIT -1", []string{"-1"}, nil},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				// WHEN
+				c := tokenize(tt.sql)
+
+				// THEN
+				assert.Equal(t, tt.numbers, c.numbers)
+				assert.Equal(t, tt.symbols, c.symbols)
+			})
+		}
+	})
+
+	t.Run("算術演算子を記号として扱う", func(t *testing.T) {
+		// GIVEN
+		sql := "a+b*c%d"
+
+		// WHEN
+		c := tokenize(sql)
+
+		// THEN
+		assert.Equal(t, []string{"a", "b", "c", "d"}, c.identifiers)
+		assert.Equal(t, []string{"+", "*", "%"}, c.symbols)
+	})
+
+	t.Run("CASE 式のキーワードを認識する", func(t *testing.T) {
+		// GIVEN
+		sql := "CASE WHEN a THEN b ELSE c END"
+
+		// WHEN
+		c := tokenize(sql)
+
+		// THEN
+		assert.Equal(t, []string{"CASE", "WHEN", "THEN", "ELSE", "END"}, c.keywords)
 	})
 }
 
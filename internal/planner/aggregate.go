@@ -0,0 +1,355 @@
+package planner
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/ren-yamanashi/minesql/internal/ast"
+	"github.com/ren-yamanashi/minesql/internal/executor"
+	"github.com/ren-yamanashi/minesql/internal/storage/dictionary"
+	"github.com/ren-yamanashi/minesql/internal/storage/handler"
+)
+
+// aggregateKey は重複を除くための集約関数の識別子 (関数の種類 + 集約対象のカラム位置)
+type aggregateKey struct {
+	fn  ast.AggregateFunc
+	pos int // 集約対象のカラム位置 (COUNT(*) の場合は -1)
+}
+
+// aggregation は集約の計画に必要な情報
+type aggregation struct {
+	inputColumns []joinedColumn // 集約前のレコードのカラム
+	groupPos     []int          // グループ化キーのカラム位置 (集約前のレコード内の位置)
+	specs        []executor.AggregateSpec
+	keys         []aggregateKey            // specs と同じ順序の識別子
+	outputMetas  []*handler.ColumnMetadata // 集約関数の結果のカラムメタデータ (specs と同じ順序)
+}
+
+// planAggregateSelect は集約を含む SELECT の残りの処理 (集約 → HAVING → ウィンドウ関数 → ORDER BY → LIMIT → Project) を計画する
+//
+// exec は WHERE 句まで適用済みの Executor で、columns はそのレコードのカラム、sortOrder はその並び順
+//
+// 集約後のレコードは [グループ化キー..., 集約関数の結果...] の順に並ぶ
+// HAVING・ORDER BY・SELECT のカラムは、集約後のレコードの位置に変換して使用する
+func planAggregateSelect(trxId handler.TrxId, exec executor.Executor, stmt *ast.SelectStmt, columns []joinedColumn, sortOrder []int) (*PlanResult, error) {
+	if stmt.IsSelectAll() {
+		return nil, errors.New("SELECT * cannot be used with GROUP BY or HAVING")
+	}
+
+	agg, err := newAggregation(stmt, columns)
+	if err != nil {
+		return nil, err
+	}
+
+	// 集約
+	// 入力がグループ化キーの順に並んでいる場合 (GROUP BY がない場合を含む) は StreamAggregate、それ以外は HashAggregate を使う
+	var outputOrder []int
+	if agg.isSortedByGroupKey(sortOrder) {
+		exec = executor.NewStreamAggregate(exec, agg.groupPos, agg.specs)
+		outputOrder = agg.mapSortOrder(sortOrder)
+	} else {
+		exec = executor.NewHashAggregate(exec, agg.groupPos, agg.specs)
+	}
+	outputColumns := agg.outputColumns()
+	scope := agg.exprScope(outputColumns).withSubqueries(trxId)
+
+	// HAVING
+	if stmt.Having != nil {
+		cond, err := compileCondition(stmt.Having.Condition, scope)
+		if err != nil {
+			return nil, err
+		}
+		exec = executor.NewFilterWithExpression(exec, cond)
+	}
+
+	// ウィンドウ関数 (集約後のレコードに対して計算する)
+	exec, scope, outputOrder, err = planWindows(exec, stmt, scope, outputOrder)
+	if err != nil {
+		return nil, err
+	}
+
+	// ORDER BY (グループ化キーのカラムのみ指定できる)
+	sortKeys := make([]executor.SortKey, len(stmt.OrderBy))
+	for i, item := range stmt.OrderBy {
+		pos, err := agg.groupColumnPos(item.Column)
+		if err != nil {
+			return nil, err
+		}
+		sortKeys[i] = executor.SortKey{Pos: pos, Desc: item.Desc}
+	}
+	exec = planOrderBy(exec, sortKeys, outputOrder)
+
+	// LIMIT
+	exec = planLimit(exec, stmt.Limit)
+
+	// Project (SELECT のカラム・集約関数・式を SELECT リストの順に並べる)
+	if stmt.Exprs != nil {
+		return planSelectExprs(exec, stmt, scope)
+	}
+	colPos, err := agg.resolveSelectList(stmt)
+	if err != nil {
+		return nil, err
+	}
+	resultColumns := make([]ColumnMeta, len(colPos))
+	for i, pos := range colPos {
+		oc := outputColumns[pos]
+		resultColumns[i] = newColumnMeta(oc.tableName, oc.colMeta)
+	}
+
+	return &PlanResult{
+		Exec:    executor.NewProject(exec, colPos),
+		Columns: resultColumns,
+	}, nil
+}
+
+// aggregateInputColumns は集約を含む SELECT で、集約前のレコードに必要なカラム (index-only scan の判定用) を返す
+//
+// SELECT * の場合は nil、COUNT(*) のようにカラムを参照しない場合は空のスライスを返す
+func aggregateInputColumns(stmt *ast.SelectStmt) []ast.ColumnId {
+	if stmt.IsSelectAll() {
+		return nil
+	}
+	columns := slices.Clone(stmt.Columns)
+	if columns == nil {
+		columns = []ast.ColumnId{}
+	}
+	columns = append(columns, stmt.GroupBy...)
+	for _, a := range stmt.Aggregates {
+		if a.Column != nil {
+			columns = append(columns, *a.Column)
+		}
+	}
+	for _, e := range stmt.Exprs {
+		columns = append(columns, collectColumns(e.Expr)...)
+	}
+	if stmt.Having != nil {
+		columns = append(columns, collectColumns(stmt.Having.Condition)...)
+	}
+	for _, item := range stmt.OrderBy {
+		columns = append(columns, item.Column)
+	}
+	return columns
+}
+
+// newAggregation はグループ化キーと集約関数 (SELECT リストと HAVING 句で指定されたもの) を解決する
+func newAggregation(stmt *ast.SelectStmt, columns []joinedColumn) (*aggregation, error) {
+	agg := &aggregation{inputColumns: columns}
+
+	for _, col := range stmt.GroupBy {
+		pos, err := findColumnPos(columns, col.TableName, col.ColName)
+		if err != nil {
+			return nil, err
+		}
+		// 同じカラムを複数回指定した場合は 1 つにまとめる
+		if !slices.Contains(agg.groupPos, pos) {
+			agg.groupPos = append(agg.groupPos, pos)
+		}
+	}
+
+	for _, a := range stmt.Aggregates {
+		if _, err := agg.addAggregate(a); err != nil {
+			return nil, err
+		}
+	}
+	for _, e := range stmt.Exprs {
+		if err := agg.addNestedAggregates(e.Expr); err != nil {
+			return nil, err
+		}
+	}
+	if stmt.Having != nil {
+		if err := agg.addNestedAggregates(stmt.Having.Condition); err != nil {
+			return nil, err
+		}
+	}
+	return agg, nil
+}
+
+// addNestedAggregates は式 (SELECT リストの式や HAVING 句) に含まれる集約関数を追加する
+func (agg *aggregation) addNestedAggregates(expr ast.Expr) error {
+	var err error
+	ast.WalkExpr(expr, func(e ast.Expr) bool {
+		if a, ok := e.(*ast.AggregateExpr); ok && err == nil {
+			_, err = agg.addAggregate(a)
+		}
+		return err == nil
+	})
+	return err
+}
+
+// addAggregate は集約関数を追加し、集約関数の結果の位置 (specs 内の位置) を返す
+//
+// 同じ関数・同じカラムの集約関数が既にある場合は追加せず、既存の位置を返す
+func (agg *aggregation) addAggregate(a *ast.AggregateExpr) (int, error) {
+	key := aggregateKey{fn: a.Func, pos: -1}
+	var colMeta *handler.ColumnMetadata
+	if a.Column != nil {
+		pos, err := findColumnPos(agg.inputColumns, a.Column.TableName, a.Column.ColName)
+		if err != nil {
+			return -1, err
+		}
+		key.pos = pos
+		colMeta = agg.inputColumns[pos].colMeta
+	}
+	if i := slices.Index(agg.keys, key); i >= 0 {
+		return i, nil
+	}
+
+	fn, meta, err := aggregateOutput(a, colMeta)
+	if err != nil {
+		return -1, err
+	}
+	agg.specs = append(agg.specs, executor.AggregateSpec{Func: fn, Pos: key.pos})
+	agg.keys = append(agg.keys, key)
+	agg.outputMetas = append(agg.outputMetas, meta)
+	return len(agg.specs) - 1, nil
+}
+
+// aggregateOutput は集約関数を Executor の集約関数の種類に変換し、結果のカラムメタデータを返す
+//
+// 結果のデータ型:
+//   - COUNT: BIGINT
+//   - SUM: INT / BIGINT の場合は BIGINT、DECIMAL の場合は同じスケールの DECIMAL
+//   - MIN / MAX: 集約対象のカラムと同じデータ型
+//   - AVG: 小数部を executor.AvgScaleIncrement 桁増やした DECIMAL
+func aggregateOutput(a *ast.AggregateExpr, colMeta *handler.ColumnMetadata) (executor.AggregateFunc, *handler.ColumnMetadata, error) {
+	meta := &handler.ColumnMetadata{Name: a.String()}
+
+	switch a.Func {
+	case ast.AggregateCount:
+		meta.Type = handler.ColumnTypeBigInt
+		return executor.AggregateCount, meta, nil
+	case ast.AggregateMin, ast.AggregateMax:
+		meta.Type, meta.Precision, meta.Scale = colMeta.Type, colMeta.Precision, colMeta.Scale
+		if a.Func == ast.AggregateMin {
+			return executor.AggregateMin, meta, nil
+		}
+		return executor.AggregateMax, meta, nil
+	}
+
+	// SUM / AVG は数値型のカラムのみ集約できる
+	switch colMeta.Type {
+	case handler.ColumnTypeInt, handler.ColumnTypeBigInt, handler.ColumnTypeDecimal:
+	default:
+		return 0, nil, fmt.Errorf("%s requires a numeric column, but %s is %s", a.Func, colMeta.Name, colMeta.Type)
+	}
+
+	if a.Func == ast.AggregateSum {
+		meta.Type = handler.ColumnTypeBigInt
+		if colMeta.Type == handler.ColumnTypeDecimal {
+			meta.Type, meta.Precision, meta.Scale = handler.ColumnTypeDecimal, dictionary.MaxDecimalPrecision, colMeta.Scale
+		}
+		return executor.AggregateSum, meta, nil
+	}
+
+	// AVG の結果は小数部を増やした整数として格納するため、スケールが精度の上限を超える場合は計算できない
+	scale := int(colMeta.Scale) + executor.AvgScaleIncrement
+	if scale > dictionary.MaxDecimalPrecision {
+		return 0, nil, fmt.Errorf("AVG is not supported for %s: scale of the result exceeds %d", colMeta.Name, dictionary.MaxDecimalPrecision)
+	}
+	meta.Type, meta.Precision, meta.Scale = handler.ColumnTypeDecimal, dictionary.MaxDecimalPrecision, uint8(scale)
+	return executor.AggregateAvg, meta, nil
+}
+
+// isSortedByGroupKey は sortOrder の順に並んだ入力が、グループ化キーの順にも並んでいる (同じグループが連続する) かを判定する
+//
+// グループ化キーのカラムが sortOrder の先頭から同じ数のカラムと (順不同で) 一致すればよい
+func (agg *aggregation) isSortedByGroupKey(sortOrder []int) bool {
+	if len(agg.groupPos) > len(sortOrder) {
+		return false
+	}
+	for _, pos := range sortOrder[:len(agg.groupPos)] {
+		if !slices.Contains(agg.groupPos, pos) {
+			return false
+		}
+	}
+	return true
+}
+
+// mapSortOrder は StreamAggregate の入力の並び順を、集約後のレコードの並び順 (グループ化キーの位置) に変換する
+func (agg *aggregation) mapSortOrder(sortOrder []int) []int {
+	order := make([]int, len(agg.groupPos))
+	for i, pos := range sortOrder[:len(agg.groupPos)] {
+		order[i] = slices.Index(agg.groupPos, pos)
+	}
+	return order
+}
+
+// outputColumns は集約後のレコードのカラム ([グループ化キー..., 集約関数の結果...]) を返す
+func (agg *aggregation) outputColumns() []joinedColumn {
+	columns := make([]joinedColumn, 0, len(agg.groupPos)+len(agg.specs))
+	for i, pos := range agg.groupPos {
+		col := agg.inputColumns[pos]
+		col.pos = i
+		columns = append(columns, col)
+	}
+	for i, meta := range agg.outputMetas {
+		columns = append(columns, joinedColumn{colName: meta.Name, pos: len(agg.groupPos) + i, colMeta: meta})
+	}
+	return columns
+}
+
+// groupColumnPos はグループ化キーのカラムの、集約後のレコード内の位置を返す
+//
+// グループ化キーでないカラムは集約後のレコードに含まれないためエラーを返す
+func (agg *aggregation) groupColumnPos(col ast.ColumnId) (int, error) {
+	pos, err := findColumnPos(agg.inputColumns, col.TableName, col.ColName)
+	if err != nil {
+		return -1, err
+	}
+	i := slices.Index(agg.groupPos, pos)
+	if i < 0 {
+		return -1, fmt.Errorf("column %s must appear in the GROUP BY clause or be used in an aggregate function", col.ColName)
+	}
+	return i, nil
+}
+
+// exprScope は集約後のレコードに対する式のスコープを返す
+//
+// カラムはグループ化キーのみ参照でき、集約関数は集約後のレコード内の位置に解決する
+func (agg *aggregation) exprScope(outputColumns []joinedColumn) *exprScope {
+	return &exprScope{
+		columns:   outputColumns,
+		column:    agg.groupColumnPos,
+		aggregate: agg.aggregatePos,
+	}
+}
+
+// aggregatePos は集約関数の結果の、集約後のレコード内の位置を返す
+func (agg *aggregation) aggregatePos(a *ast.AggregateExpr) (int, error) {
+	i, err := agg.addAggregate(a)
+	if err != nil {
+		return -1, err
+	}
+	return len(agg.groupPos) + i, nil
+}
+
+// resolveSelectList は SELECT リストのカラムと集約関数を、集約後のレコード内の位置に変換する
+//
+// 集約関数は AggregateExpr.Pos の位置に、カラムは残りの位置に前から順に並べる
+func (agg *aggregation) resolveSelectList(stmt *ast.SelectStmt) ([]uint16, error) {
+	colPos := make([]uint16, len(stmt.Columns)+len(stmt.Aggregates))
+	isAggregate := make([]bool, len(colPos))
+	for _, a := range stmt.Aggregates {
+		pos, err := agg.aggregatePos(a)
+		if err != nil {
+			return nil, err
+		}
+		colPos[a.Pos] = uint16(pos)
+		isAggregate[a.Pos] = true
+	}
+
+	next := 0
+	for _, col := range stmt.Columns {
+		for isAggregate[next] {
+			next++
+		}
+		pos, err := agg.groupColumnPos(col)
+		if err != nil {
+			return nil, err
+		}
+		colPos[next] = uint16(pos)
+		next++
+	}
+	return colPos, nil
+}
@@ -0,0 +1,320 @@
+package planner
+
+import (
+	"testing"
+
+	"github.com/ren-yamanashi/minesql/internal/ast"
+	"github.com/ren-yamanashi/minesql/internal/executor"
+	"github.com/ren-yamanashi/minesql/internal/storage/encode"
+	"github.com/ren-yamanashi/minesql/internal/storage/handler"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlanSelectAggregate(t *testing.T) {
+	t.Run("COUNT(*) はインデックスを index-only scan で数える", func(t *testing.T) {
+		// GIVEN
+		setupItemsTable(t)
+		defer handler.Reset()
+
+		stmt := &ast.SelectStmt{
+			Aggregates: []*ast.AggregateExpr{ast.NewAggregateExpr(ast.AggregateCount, nil)},
+			From:       *ast.NewTableId("items"),
+		}
+
+		// WHEN
+		plan, err := PlanSelect(0, stmt)
+		require.NoError(t, err)
+		results := fetchAll(t, plan.Exec)
+
+		// THEN
+		proj := plan.Exec.(*executor.Project)
+		agg := proj.InnerExecutor.(*executor.StreamAggregate)
+		assert.IsType(t, &executor.IndexScan{}, agg.InnerExecutor)
+		assert.Equal(t, []int64{5}, int64Column(results, 0))
+		assert.Equal(t, "COUNT(*)", plan.Columns[0].ColName)
+		assert.Equal(t, handler.ColumnTypeBigInt, plan.Columns[0].Type)
+	})
+
+	t.Run("インデックスの順に読み取れるグループ化キーは StreamAggregate で集約する", func(t *testing.T) {
+		// GIVEN
+		setupItemsTable(t)
+		defer handler.Reset()
+
+		stmt := &ast.SelectStmt{
+			Columns:    []ast.ColumnId{*ast.NewColumnId("category")},
+			Aggregates: []*ast.AggregateExpr{{Func: ast.AggregateCount, Pos: 1}},
+			From:       *ast.NewTableId("items"),
+			GroupBy:    []ast.ColumnId{*ast.NewColumnId("category")},
+			OrderBy:    []ast.OrderByItem{{Column: *ast.NewColumnId("category")}},
+		}
+
+		// WHEN
+		plan, err := PlanSelect(0, stmt)
+		require.NoError(t, err)
+		results := fetchAll(t, plan.Exec)
+
+		// THEN: 集約結果もグループ化キーの順に並ぶので Sort も使わない
+		proj := plan.Exec.(*executor.Project)
+		agg := proj.InnerExecutor.(*executor.StreamAggregate)
+		assert.IsType(t, &executor.IndexScan{}, agg.InnerExecutor)
+		assert.Equal(t, []string{"a", "b", "c"}, columnStrings(results, 0))
+		assert.Equal(t, []int64{2, 2, 1}, int64Column(results, 1))
+	})
+
+	t.Run("PK でグループ化する場合はテーブルスキャンの順序のまま StreamAggregate で集約する", func(t *testing.T) {
+		// GIVEN
+		setupItemsTable(t)
+		defer handler.Reset()
+
+		stmt := &ast.SelectStmt{
+			Columns:    []ast.ColumnId{*ast.NewColumnId("id")},
+			Aggregates: []*ast.AggregateExpr{{Func: ast.AggregateMax, Column: ast.NewColumnId("price"), Pos: 1}},
+			From:       *ast.NewTableId("items"),
+			GroupBy:    []ast.ColumnId{*ast.NewColumnId("id")},
+		}
+
+		// WHEN
+		plan, err := PlanSelect(0, stmt)
+		require.NoError(t, err)
+
+		// THEN
+		proj := plan.Exec.(*executor.Project)
+		agg := proj.InnerExecutor.(*executor.StreamAggregate)
+		assert.IsType(t, &executor.TableScan{}, agg.InnerExecutor)
+	})
+
+	t.Run("入力がグループ化キーの順に並ばない場合は HashAggregate で集約する", func(t *testing.T) {
+		// GIVEN
+		setupItemsTable(t)
+		defer handler.Reset()
+
+		stmt := &ast.SelectStmt{
+			Columns:    []ast.ColumnId{*ast.NewColumnId("category")},
+			Aggregates: []*ast.AggregateExpr{{Func: ast.AggregateSum, Column: ast.NewColumnId("price"), Pos: 1}},
+			From:       *ast.NewTableId("items"),
+			GroupBy:    []ast.ColumnId{*ast.NewColumnId("category")},
+		}
+
+		// WHEN
+		plan, err := PlanSelect(0, stmt)
+		require.NoError(t, err)
+		results := fetchAll(t, plan.Exec)
+
+		// THEN: price はインデックスでカバーされないためテーブルスキャン (PK 順) になり、グループは最初に現れた順に返る
+		proj := plan.Exec.(*executor.Project)
+		agg := proj.InnerExecutor.(*executor.HashAggregate)
+		assert.IsType(t, &executor.TableScan{}, agg.InnerExecutor)
+		assert.Equal(t, []string{"b", "a", "c"}, columnStrings(results, 0))
+		assert.Equal(t, [][]byte{encode.EncodeInt64(40), encode.EncodeInt64(25), nil}, columnBytes(results, 1))
+	})
+
+	t.Run("グループ化キーと集約するカラムをカバーするインデックスがあれば、ORDER BY がなくても StreamAggregate で集約する", func(t *testing.T) {
+		// GIVEN
+		setupItemsTable(t)
+		defer handler.Reset()
+		executePlan(t, &ast.CreateIndexStmt{
+			IndexName: "category_price_idx",
+			TableName: "items",
+			Columns:   []ast.ColumnId{*ast.NewColumnId("category"), *ast.NewColumnId("price")},
+		})
+
+		stmt := &ast.SelectStmt{
+			Columns:    []ast.ColumnId{*ast.NewColumnId("category")},
+			Aggregates: []*ast.AggregateExpr{{Func: ast.AggregateSum, Column: ast.NewColumnId("price"), Pos: 1}},
+			From:       *ast.NewTableId("items"),
+			GroupBy:    []ast.ColumnId{*ast.NewColumnId("category")},
+		}
+
+		// WHEN
+		plan, err := PlanSelect(0, stmt)
+		require.NoError(t, err)
+		results := fetchAll(t, plan.Exec)
+
+		// THEN: price が NULL の行もインデックスで読み取れるため、グループ化キーの順に集約される
+		proj := plan.Exec.(*executor.Project)
+		agg := proj.InnerExecutor.(*executor.StreamAggregate)
+		assert.IsType(t, &executor.IndexScan{}, agg.InnerExecutor)
+		assert.Equal(t, []string{"a", "b", "c"}, columnStrings(results, 0))
+		assert.Equal(t, [][]byte{encode.EncodeInt64(25), encode.EncodeInt64(40), nil}, columnBytes(results, 1))
+	})
+
+	t.Run("NULL を許容するカラムのインデックスで StreamAggregate で集約でき、NULL のグループは先頭に並ぶ", func(t *testing.T) {
+		// GIVEN
+		setupItemsTable(t)
+		defer handler.Reset()
+		executePlan(t, &ast.CreateIndexStmt{
+			IndexName: "price_idx",
+			TableName: "items",
+			Columns:   []ast.ColumnId{*ast.NewColumnId("price")},
+		})
+
+		stmt := &ast.SelectStmt{
+			Columns:    []ast.ColumnId{*ast.NewColumnId("price")},
+			Aggregates: []*ast.AggregateExpr{{Func: ast.AggregateCount, Pos: 1}},
+			From:       *ast.NewTableId("items"),
+			GroupBy:    []ast.ColumnId{*ast.NewColumnId("price")},
+		}
+
+		// WHEN
+		plan, err := PlanSelect(0, stmt)
+		require.NoError(t, err)
+		results := fetchAll(t, plan.Exec)
+
+		// THEN
+		proj := plan.Exec.(*executor.Project)
+		agg := proj.InnerExecutor.(*executor.StreamAggregate)
+		assert.IsType(t, &executor.IndexScan{}, agg.InnerExecutor)
+		assert.Equal(t, [][]byte{nil, encode.EncodeInt64(5), encode.EncodeInt64(10), encode.EncodeInt64(20), encode.EncodeInt64(30)}, columnBytes(results, 0))
+		assert.Equal(t, []int64{1, 1, 1, 1, 1}, int64Column(results, 1))
+	})
+
+	t.Run("カバーするインデックスが複数ある場合は、グループ化キーの順に走査できるインデックスを優先する", func(t *testing.T) {
+		// GIVEN: どちらのインデックスも price をカバーするが、price の順に走査できるのは price_idx のみ
+		setupItemsTable(t)
+		defer handler.Reset()
+		executePlan(t, &ast.CreateIndexStmt{
+			IndexName: "category_price_idx",
+			TableName: "items",
+			Columns:   []ast.ColumnId{*ast.NewColumnId("category"), *ast.NewColumnId("price")},
+		})
+		executePlan(t, &ast.CreateIndexStmt{
+			IndexName: "price_idx",
+			TableName: "items",
+			Columns:   []ast.ColumnId{*ast.NewColumnId("price")},
+		})
+
+		stmt := &ast.SelectStmt{
+			Columns:    []ast.ColumnId{*ast.NewColumnId("price")},
+			Aggregates: []*ast.AggregateExpr{{Func: ast.AggregateCount, Pos: 1}},
+			From:       *ast.NewTableId("items"),
+			GroupBy:    []ast.ColumnId{*ast.NewColumnId("price")},
+		}
+
+		// WHEN
+		plan, err := PlanSelect(0, stmt)
+		require.NoError(t, err)
+		results := fetchAll(t, plan.Exec)
+
+		// THEN
+		proj := plan.Exec.(*executor.Project)
+		agg := proj.InnerExecutor.(*executor.StreamAggregate)
+		assert.IsType(t, &executor.IndexScan{}, agg.InnerExecutor)
+		assert.Equal(t, [][]byte{nil, encode.EncodeInt64(5), encode.EncodeInt64(10), encode.EncodeInt64(20), encode.EncodeInt64(30)}, columnBytes(results, 0))
+	})
+
+	t.Run("HAVING は集約後のレコードを Filter で絞り込む", func(t *testing.T) {
+		// GIVEN
+		setupItemsTable(t)
+		defer handler.Reset()
+
+		stmt := &ast.SelectStmt{
+			Columns: []ast.ColumnId{*ast.NewColumnId("category")},
+			From:    *ast.NewTableId("items"),
+			GroupBy: []ast.ColumnId{*ast.NewColumnId("category")},
+			Having: &ast.HavingClause{
+				Condition: ast.NewBinaryExpr(
+					">",
+					ast.NewLhsAggregate(ast.NewAggregateExpr(ast.AggregateSum, ast.NewColumnId("price"))),
+					ast.NewRhsLiteral(ast.NewStringLiteral("30")),
+				),
+			},
+		}
+
+		// WHEN
+		plan, err := PlanSelect(0, stmt)
+		require.NoError(t, err)
+		results := fetchAll(t, plan.Exec)
+
+		// THEN: HAVING の集約関数は SELECT リストに含めなくてもよい
+		proj := plan.Exec.(*executor.Project)
+		assert.IsType(t, &executor.Filter{}, proj.InnerExecutor)
+		assert.Equal(t, []string{"b"}, columnStrings(results, 0))
+		assert.Len(t, plan.Columns, 1)
+	})
+
+	t.Run("GROUP BY に含まれないカラムを SELECT するとエラーになる", func(t *testing.T) {
+		// GIVEN
+		setupItemsTable(t)
+		defer handler.Reset()
+
+		stmt := &ast.SelectStmt{
+			Columns:    []ast.ColumnId{*ast.NewColumnId("price")},
+			Aggregates: []*ast.AggregateExpr{{Func: ast.AggregateCount, Pos: 1}},
+			From:       *ast.NewTableId("items"),
+			GroupBy:    []ast.ColumnId{*ast.NewColumnId("category")},
+		}
+
+		// WHEN
+		_, err := PlanSelect(0, stmt)
+
+		// THEN
+		assert.EqualError(t, err, "column price must appear in the GROUP BY clause or be used in an aggregate function")
+	})
+
+	t.Run("SELECT * と GROUP BY を同時に指定するとエラーになる", func(t *testing.T) {
+		// GIVEN
+		setupItemsTable(t)
+		defer handler.Reset()
+
+		stmt := &ast.SelectStmt{
+			From:    *ast.NewTableId("items"),
+			GroupBy: []ast.ColumnId{*ast.NewColumnId("category")},
+		}
+
+		// WHEN
+		_, err := PlanSelect(0, stmt)
+
+		// THEN
+		assert.EqualError(t, err, "SELECT * cannot be used with GROUP BY or HAVING")
+	})
+}
+
+// setupItemsTable は items (id INT PK, category VARCHAR NOT NULL + インデックス, price INT) を作成してデータを挿入する
+func setupItemsTable(t *testing.T) {
+	t.Helper()
+
+	initStorageManagerForTest(t)
+
+	executePlan(t, &ast.CreateTableStmt{
+		TableName: "items",
+		CreateDefinitions: []ast.Definition{
+			&ast.ColumnDef{ColName: "id", DataType: ast.DataTypeInt},
+			&ast.ColumnDef{ColName: "category", DataType: ast.DataTypeVarchar, NotNull: true},
+			&ast.ColumnDef{ColName: "price", DataType: ast.DataTypeInt},
+			&ast.ConstraintPrimaryKeyDef{Columns: []ast.ColumnId{*ast.NewColumnId("id")}},
+			&ast.ConstraintKeyDef{KeyName: "category_idx", Columns: []ast.ColumnId{*ast.NewColumnId("category")}},
+		},
+	})
+
+	rows := [][]ast.Literal{
+		{ast.NewStringLiteral("1"), ast.NewStringLiteral("b"), ast.NewStringLiteral("10")},
+		{ast.NewStringLiteral("2"), ast.NewStringLiteral("a"), ast.NewStringLiteral("20")},
+		{ast.NewStringLiteral("3"), ast.NewStringLiteral("b"), ast.NewStringLiteral("30")},
+		{ast.NewStringLiteral("4"), ast.NewStringLiteral("c"), ast.NewNullLiteral()},
+		{ast.NewStringLiteral("5"), ast.NewStringLiteral("a"), ast.NewStringLiteral("5")},
+	}
+	executePlan(t, &ast.InsertStmt{
+		Table:  *ast.NewTableId("items"),
+		Cols:   []ast.ColumnId{*ast.NewColumnId("id"), *ast.NewColumnId("category"), *ast.NewColumnId("price")},
+		Values: rows,
+	})
+}
+
+// int64Column はレコード群から指定位置のカラム値を整数 (encode.DecodeInt64) として取り出す
+func int64Column(records []executor.Record, pos int) []int64 {
+	values := make([]int64, len(records))
+	for i, record := range records {
+		values[i] = encode.DecodeInt64(record[pos])
+	}
+	return values
+}
+
+// columnBytes はレコード群から指定位置のカラム値を格納形式のまま取り出す
+func columnBytes(records []executor.Record, pos int) [][]byte {
+	values := make([][]byte, len(records))
+	for i, record := range records {
+		values[i] = record[pos]
+	}
+	return values
+}
@@ -4,31 +4,64 @@ import (
 	"errors"
 
 	"github.com/ren-yamanashi/minesql/internal/ast"
+	"github.com/ren-yamanashi/minesql/internal/executor"
 	"github.com/ren-yamanashi/minesql/internal/storage/access"
 	"github.com/ren-yamanashi/minesql/internal/storage/btree"
 	"github.com/ren-yamanashi/minesql/internal/storage/buffer"
-	"github.com/ren-yamanashi/minesql/internal/storage/dictionary"
+	"github.com/ren-yamanashi/minesql/internal/storage/config"
 	"github.com/ren-yamanashi/minesql/internal/storage/handler"
+	"github.com/ren-yamanashi/minesql/internal/storage/page"
 )
 
 // joinCandidate は結合候補のテーブル情報
 type joinCandidate struct {
 	tblMeta *handler.TableMetadata
 	stats   *handler.TableStatistics
-	table   *access.Table
+	table   *access.Table     // 導出テーブルの場合は nil
+	derived executor.Executor // 導出テーブルのサブクエリの Executor (通常のテーブルの場合は nil)
+	method  joinMethod        // 内部表の結合方法 (optimizeJoinOrder が決定する。駆動表では使わない)
+	est     joinEstimate      // 結合の見積もり (optimizeJoinOrder が決定する。EXPLAIN で表示する)
 }
 
+// joinEstimate は optimizeJoinOrder がテーブルを結合順序に加えたときの見積もり
+type joinEstimate struct {
+	readCost   float64 // テーブルの読み取りコスト (外側の全行の合計)
+	fanout     float64 // 外側の 1 行あたりに結合する行数
+	prefixRows float64 // 結合前の行数 (外側の行数)
+	rows       float64 // 結合後の行数
+	cost       float64 // 結合後の累積コスト
+}
+
+// joinMethod は内部表の結合方法
+type joinMethod int
+
+const (
+	joinMethodNestedLoop joinMethod = iota // Nested Loop Join: 外側の各行に対して内部表を検索する
+	joinMethodHash                         // Hash Join: 内部表を 1 回だけ読み込んでハッシュテーブルを構築し、外側の各行で検索する
+)
+
 // joinPredicate は 2 テーブル間の結合条件 (ON 句から抽出)
 //
 // INNER JOIN は可換なので、ON 条件は特定テーブルに属さず、テーブルペアの関係として管理する
+// 外部結合の ON 条件は NULL で補完される側 (内部表) のテーブルに属し、そのテーブルを結合するときにのみ使う
 type joinPredicate struct {
 	leftTable  string
 	leftCol    string
 	rightTable string
 	rightCol   string
+	outerTable string // 外部結合の ON 条件の場合、NULL で補完される側のテーブル (INNER JOIN の場合は空)
+}
+
+// joinCondition は ON 条件のうち、カラム同士の等値条件 (joinPredicate) 以外の条件を表す
+type joinCondition struct {
+	expr       ast.Expr
+	outerTable string // 外部結合の ON 条件の場合、NULL で補完される側のテーブル (INNER JOIN の場合は空)
 }
 
 // optimizeJoinOrder は貪欲法で最小コストの結合順序を返す
+//
+// 外部結合の内部表は、ON 条件で参照する全てのテーブルが結合された後にのみ候補になる
+// (内部表を先に読むと、一致する行がない外側の行を NULL で補完できないため)
 func optimizeJoinOrder(
 	bp *buffer.BufferPool,
 	candidates []joinCandidate,
@@ -47,10 +80,19 @@ func optimizeJoinOrder(
 	for len(remaining) > 0 {
 		bestIdx := -1
 		bestCost := 0.0
+		bestReadCost := 0.0
 		bestFanout := 0.0
+		bestMethod := joinMethodNestedLoop
 
 		for i, candidate := range remaining {
 			// 2 回目以降: 結果セット内のテーブルとの結合条件が必要
+			if !outerJoinDependenciesMet(predicates, candidate.tblMeta.Name, resultTableNames) {
+				continue
+			}
+			// 外部結合の制約により、この候補を選ぶと残りのテーブルを結合できなくなる場合は除外する
+			if !canCompleteJoinOrder(predicates, remaining, candidate, resultTableNames) {
+				continue
+			}
 			var pred *joinPredicate
 			if len(resultTableNames) > 0 {
 				pred = findPredicate(predicates, candidate.tblMeta.Name, resultTableNames)
@@ -65,17 +107,23 @@ func optimizeJoinOrder(
 				drivingWhere, _ = splitWhereForTable(where, candidate.tblMeta, allTables)
 			}
 
-			readCost, fanout, err := calcTableJoinCost(bp, candidate, prefixRowcount, pred, drivingWhere)
+			readCost, fanout, method, err := calcJoinMethodCost(bp, candidate, prefixRowcount, pred, drivingWhere)
 			if err != nil {
 				return nil, err
 			}
+			// 外部結合は外側の各行に対して少なくとも 1 行を返す
+			if isOuterTable(predicates, candidate.tblMeta.Name) && fanout < 1.0 {
+				fanout = 1.0
+			}
 			newPrefixRowcount := prefixRowcount * fanout
 			newPrefixCost := prefixCost + readCost + newPrefixRowcount*RowEvaluateCost
 
 			if bestIdx == -1 || newPrefixCost < bestCost {
 				bestIdx = i
 				bestCost = newPrefixCost
+				bestReadCost = readCost
 				bestFanout = fanout
+				bestMethod = method
 			}
 		}
 
@@ -84,8 +132,8 @@ func optimizeJoinOrder(
 		}
 
 		bestCandidate := remaining[bestIdx]
-		result = append(result, bestCandidate)
-		resultTableNames[bestCandidate.tblMeta.Name] = struct{}{}
+		bestCandidate.method = bestMethod
+		bestCandidate.est = joinEstimate{readCost: bestReadCost, fanout: bestFanout, prefixRows: prefixRowcount}
 		prefixCost = bestCost
 		prefixRowcount *= bestFanout
 
@@ -97,6 +145,10 @@ func optimizeJoinOrder(
 				prefixRowcount *= filtered
 			}
 		}
+		bestCandidate.est.rows = prefixRowcount
+		bestCandidate.est.cost = prefixCost
+		result = append(result, bestCandidate)
+		resultTableNames[bestCandidate.tblMeta.Name] = struct{}{}
 
 		// remaining から bestIdx を削除
 		remaining = append(remaining[:bestIdx], remaining[bestIdx+1:]...)
@@ -105,6 +157,86 @@ func optimizeJoinOrder(
 	return result, nil
 }
 
+// calcJoinMethodCost はテーブルの結合コスト (readCost と fanout) と結合方法を返す
+//
+// 内部表の場合、Nested Loop Join (calcTableJoinCost) と Hash Join (calcHashJoinReadCost) のコストを比較し、低い方を選ぶ
+// 比較には readCost に結合結果の行の評価コスト (prefixRowcount × fanout × RowEvaluateCost) を加えたコストを使う
+// 導出テーブルは calcDerivedTableCost で算出する
+func calcJoinMethodCost(
+	bp *buffer.BufferPool,
+	candidate joinCandidate,
+	prefixRowcount float64,
+	pred *joinPredicate,
+	drivingWhere *ast.WhereClause,
+) (readCost float64, fanout float64, method joinMethod, err error) {
+	if candidate.derived != nil {
+		readCost, fanout, method = calcDerivedTableCost(candidate, prefixRowcount, pred)
+		return readCost, fanout, method, nil
+	}
+	readCost, fanout, err = calcTableJoinCost(bp, candidate, prefixRowcount, pred, drivingWhere)
+	if err != nil || pred == nil {
+		return readCost, fanout, joinMethodNestedLoop, err
+	}
+
+	pageReadCost, err := calcPageReadCost(bp, btree.NewBTree(candidate.table.MetaPageId))
+	if err != nil {
+		return 0, 0, joinMethodNestedLoop, err
+	}
+	hashReadCost := calcHashJoinReadCost(candidate.stats, prefixRowcount, pageReadCost, config.GetJoinBufferSize())
+	hashFanout := calcHashJoinFanout(candidate, pred)
+
+	nljCost := readCost + prefixRowcount*fanout*RowEvaluateCost
+	hashCost := hashReadCost + prefixRowcount*hashFanout*RowEvaluateCost
+	if hashCost < nljCost {
+		return hashReadCost, hashFanout, joinMethodHash, nil
+	}
+	return readCost, fanout, joinMethodNestedLoop, nil
+}
+
+// calcDerivedTableCost は導出テーブルの結合コスト (readCost と fanout) と結合方法を返す
+//
+// 導出テーブルはインデックスを持たず、サブクエリの結果は 1 回だけ読み込めるため、内部表の場合は常に Hash Join で結合する
+//   - 駆動表: readCost = RecordCount × RowEvaluateCost (サブクエリの結果の全行を評価する)
+//   - 内部表: calcHashJoinReadCost (ページの読み込みコストは 0 とする)
+func calcDerivedTableCost(candidate joinCandidate, prefixRowcount float64, pred *joinPredicate) (readCost float64, fanout float64, method joinMethod) {
+	if pred == nil {
+		return float64(candidate.stats.RecordCount) * RowEvaluateCost, float64(candidate.stats.RecordCount), joinMethodNestedLoop
+	}
+	return calcHashJoinReadCost(candidate.stats, prefixRowcount, 0, config.GetJoinBufferSize()), calcHashJoinFanout(candidate, pred), joinMethodHash
+}
+
+// calcHashJoinFanout は Hash Join で外側の 1 行あたりに結合される内部表の行数を推定する
+//
+// 結合カラムの統計情報があれば RecordCount / ユニーク値数 (1 キーあたりの平均行数) を返す
+// 統計情報がない場合は calcFiltered と同様に MySQL のデフォルト (COND_FILTER_EQUALITY = 0.1) を使う
+// (Nested Loop Join のフルスキャンは内部表の全行を評価するため fanout = RecordCount だが、Hash Join はキーが一致する行のみを評価する)
+func calcHashJoinFanout(candidate joinCandidate, pred *joinPredicate) float64 {
+	colStats, ok := candidate.stats.ColStats[resolveJoinCol(pred, candidate.tblMeta.Name)]
+	if !ok || colStats.UniqueValues == 0 {
+		return float64(candidate.stats.RecordCount) * 0.1
+	}
+	return float64(candidate.stats.RecordCount) / float64(colStats.UniqueValues)
+}
+
+// calcHashJoinReadCost は内部表を Hash Join で結合する場合の readCost を算出する
+//
+// buildCost = scanTime × pageReadCost + RecordCount × RowEvaluateCost (内部表を 1 回だけフルスキャンし、全行をハッシュテーブルに格納する)
+// probeCost = prefixRowcount × RowEvaluateCost (外側の各行でハッシュテーブルを検索する)
+//
+// 内部表のサイズ (LeafPageCount × PageSize) が bufferSize を超える場合は、両側を分割して一時ファイルに書き出し、読み直すコストを加える
+// spillCost = 2 × scanTime × pageReadCost + 2 × prefixRowcount × RowEvaluateCost
+func calcHashJoinReadCost(stats *handler.TableStatistics, prefixRowcount float64, pageReadCost float64, bufferSize int) float64 {
+	scanTime := float64(stats.LeafPageCount)
+	buildCost := scanTime*pageReadCost + float64(stats.RecordCount)*RowEvaluateCost
+	probeCost := prefixRowcount * RowEvaluateCost
+	cost := buildCost + probeCost
+
+	if stats.LeafPageCount*page.PageSize > uint64(bufferSize) {
+		cost += 2*scanTime*pageReadCost + 2*prefixRowcount*RowEvaluateCost
+	}
+	return cost
+}
+
 // calcTableJoinCost はテーブルの結合コスト (readCost と fanout) を算出する
 //
 // pred が nil の場合は駆動表。drivingWhere があればアクセスパスを最適化する
@@ -178,8 +310,9 @@ func calcDrivingTableCost(bp *buffer.BufferPool, candidate joinCandidate, pageRe
 // evalDrivingWhereConditions は WHERE 条件からベストなアクセスパスのコストを評価する
 //
 // AND の場合は各リーフ条件を個別に評価し、最もコストの低いものを返す
-func evalDrivingWhereConditions(bp *buffer.BufferPool, candidate joinCandidate, pageReadCost float64, expr *ast.BinaryExpr) (cost float64, fanout float64, ok bool, err error) {
-	if expr == nil {
+func evalDrivingWhereConditions(bp *buffer.BufferPool, candidate joinCandidate, pageReadCost float64, condition ast.Expr) (cost float64, fanout float64, ok bool, err error) {
+	expr, isBinary := condition.(*ast.BinaryExpr)
+	if !isBinary {
 		return 0, 0, false, nil
 	}
 
@@ -214,12 +347,25 @@ func evalDrivingWhereConditions(bp *buffer.BufferPool, candidate joinCandidate,
 
 	// リーフ条件: col op literal
 	lhs, lOk := expr.Left.(*ast.LhsColumn)
-	_, rOk := expr.Right.(*ast.RhsLiteral)
+	rhs, rOk := expr.Right.(*ast.RhsLiteral)
 	if !lOk || !rOk {
 		return 0, 0, false, nil
 	}
 
 	colName := lhs.Column.ColName
+	colMeta, exists := candidate.tblMeta.GetColByName(colName)
+	if !exists {
+		return 0, 0, false, nil
+	}
+	// データ型に合わない値はレンジ分析できない (エラーはアクセスパス構築時に報告される)
+	value, err := parseLiteral(colMeta, rhs.Literal)
+	if err != nil {
+		return 0, 0, false, nil
+	}
+	// IS [NOT] NULL や NULL との比較はレンジ分析できない
+	if value == nil {
+		return 0, 0, false, nil
+	}
 
 	// 等値検索: PK or INDEX
 	if expr.Operator == "=" {
@@ -229,7 +375,7 @@ func evalDrivingWhereConditions(bp *buffer.BufferPool, candidate joinCandidate,
 			}
 		}
 		if idxMeta, hasIdx := candidate.tblMeta.GetIndexByColName(colName); hasIdx {
-			if idxMeta.Type == dictionary.IndexTypeUnique {
+			if idxMeta.IsUniqueColumn() {
 				return calcUniqueScanCost(), 1.0, true, nil
 			}
 			// 非ユニークインデックス: read_cost = RecPerKey × pageReadCost (MySQL の find_cost_for_ref に対応)
@@ -245,9 +391,8 @@ func evalDrivingWhereConditions(bp *buffer.BufferPool, candidate joinCandidate,
 	if candidate.tblMeta.PKCount == 1 {
 		if colMeta, exists := candidate.tblMeta.GetColByName(colName); exists && colMeta.Pos == 0 {
 			if expr.Operator == ">" || expr.Operator == ">=" || expr.Operator == "<" || expr.Operator == "<=" {
-				rhs := expr.Right.(*ast.RhsLiteral)
 				primaryBTree := btree.NewBTree(candidate.table.MetaPageId)
-				lowerKey, upperKey, leftIncl, rightIncl := buildRangeKeys(expr.Operator, rhs.Literal)
+				lowerKey, upperKey, leftIncl, rightIncl := buildRangeKeys(expr.Operator, value)
 				foundRecords, err := primaryBTree.RecordsInRange(bp, lowerKey, upperKey, leftIncl, rightIncl)
 				if err != nil {
 					return 0, 0, false, err
@@ -264,13 +409,12 @@ func evalDrivingWhereConditions(bp *buffer.BufferPool, candidate joinCandidate,
 	// レンジスキャン: UNIQUE INDEX
 	if idxMeta, hasIdx := candidate.tblMeta.GetIndexByColName(colName); hasIdx {
 		if expr.Operator == ">" || expr.Operator == ">=" || expr.Operator == "<" || expr.Operator == "<=" {
-			rhs := expr.Right.(*ast.RhsLiteral)
 			index, err := candidate.table.GetSecondaryIndexByName(idxMeta.Name)
 			if err != nil {
 				return 0, 0, false, err
 			}
 			indexBTree := btree.NewBTree(index.MetaPageId)
-			lowerKey, upperKey, leftIncl, rightIncl := buildRangeKeys(expr.Operator, rhs.Literal)
+			lowerKey, upperKey, leftIncl, rightIncl := buildIndexRangeKeys(expr.Operator, value)
 			foundRecords, err := indexBTree.RecordsInRange(bp, lowerKey, upperKey, leftIncl, rightIncl)
 			if err != nil {
 				return 0, 0, false, err
@@ -289,8 +433,9 @@ func evalDrivingWhereConditions(bp *buffer.BufferPool, candidate joinCandidate,
 //
 // PK/UNIQUE INDEX の等値・レンジ条件は fanout に既に反映されるので除外し、
 // インデックスなしの条件のみ通過率として返す
-func calcFiltered(expr *ast.BinaryExpr, candidate joinCandidate) float64 {
-	if expr == nil {
+func calcFiltered(condition ast.Expr, candidate joinCandidate) float64 {
+	expr, ok := condition.(*ast.BinaryExpr)
+	if !ok {
 		return 1.0
 	}
 
@@ -338,12 +483,55 @@ func calcFiltered(expr *ast.BinaryExpr, candidate joinCandidate) float64 {
 	}
 }
 
+// canCompleteJoinOrder は candidate を結果セットに加えた後、残りの全テーブルを結合できる順序が存在するかを判定する
+//
+// 結合できるテーブルを結果セットに加える操作を繰り返し、全テーブルを加えられれば true を返す
+func canCompleteJoinOrder(predicates []joinPredicate, remaining []joinCandidate, candidate joinCandidate, resultTableNames map[string]struct{}) bool {
+	joined := make(map[string]struct{}, len(resultTableNames)+1)
+	for name := range resultTableNames {
+		joined[name] = struct{}{}
+	}
+	joined[candidate.tblMeta.Name] = struct{}{}
+
+	for {
+		added := false
+		for _, c := range remaining {
+			name := c.tblMeta.Name
+			if _, ok := joined[name]; ok {
+				continue
+			}
+			if !outerJoinDependenciesMet(predicates, name, joined) || findPredicate(predicates, name, joined) == nil {
+				continue
+			}
+			joined[name] = struct{}{}
+			added = true
+		}
+		if !added {
+			break
+		}
+	}
+	for _, c := range remaining {
+		if _, ok := joined[c.tblMeta.Name]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
 // findPredicate は predicates から候補テーブルと結果セット内のテーブルを結ぶ条件を探す
 //
 // INNER JOIN は可換なので、predicate の leftTable/rightTable のどちら側が候補でもマッチする
+// 候補が外部結合の内部表の場合はその ON 条件のみ、それ以外の場合は INNER JOIN の ON 条件のみを対象とする
 func findPredicate(predicates []joinPredicate, candidateTable string, resultTableNames map[string]struct{}) *joinPredicate {
+	outerTable := ""
+	if isOuterTable(predicates, candidateTable) {
+		outerTable = candidateTable
+	}
 	for i := range predicates {
 		pred := &predicates[i]
+		if pred.outerTable != outerTable {
+			continue
+		}
 		if pred.leftTable == candidateTable {
 			if _, ok := resultTableNames[pred.rightTable]; ok {
 				return pred
@@ -358,6 +546,36 @@ func findPredicate(predicates []joinPredicate, candidateTable string, resultTabl
 	return nil
 }
 
+// isOuterTable はテーブルが外部結合の内部表 (NULL で補完される側) かどうかを判定する
+func isOuterTable(predicates []joinPredicate, tableName string) bool {
+	for _, pred := range predicates {
+		if pred.outerTable == tableName {
+			return true
+		}
+	}
+	return false
+}
+
+// outerJoinDependenciesMet は外部結合の内部表の ON 条件で参照する全てのテーブルが結果セットに含まれるかを判定する
+//
+// 外部結合の内部表でない場合は常に true を返す
+func outerJoinDependenciesMet(predicates []joinPredicate, candidateTable string, resultTableNames map[string]struct{}) bool {
+	for _, pred := range predicates {
+		if pred.outerTable != candidateTable {
+			continue
+		}
+		for _, tableName := range []string{pred.leftTable, pred.rightTable} {
+			if tableName == candidateTable {
+				continue
+			}
+			if _, ok := resultTableNames[tableName]; !ok {
+				return false
+			}
+		}
+	}
+	return true
+}
+
 // resolveJoinCol は joinPredicate から候補テーブル側の結合カラム名を返す
 func resolveJoinCol(pred *joinPredicate, candidateTable string) string {
 	if pred.leftTable == candidateTable {
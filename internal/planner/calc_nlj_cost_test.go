@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/ren-yamanashi/minesql/internal/ast"
+	"github.com/ren-yamanashi/minesql/internal/executor"
 	"github.com/ren-yamanashi/minesql/internal/storage/dictionary"
 	"github.com/ren-yamanashi/minesql/internal/storage/handler"
 	"github.com/ren-yamanashi/minesql/internal/storage/page"
@@ -100,6 +101,38 @@ func TestCalcTableJoinCost(t *testing.T) {
 	})
 }
 
+func TestCalcDerivedTableCost(t *testing.T) {
+	// 推定行数が 50 行の導出テーブル t (user_id)
+	candidate := joinCandidate{
+		tblMeta: &handler.TableMetadata{Name: "t", NCols: 1, Cols: []*handler.ColumnMetadata{{Name: "user_id"}}},
+		stats:   &handler.TableStatistics{RecordCount: 50},
+		derived: &executor.Project{},
+	}
+
+	t.Run("駆動表の場合は推定行数分の行を評価するコストになる", func(t *testing.T) {
+		// WHEN
+		readCost, fanout, method := calcDerivedTableCost(candidate, 1.0, nil)
+
+		// THEN
+		assert.InDelta(t, 50*RowEvaluateCost, readCost, 1e-9)
+		assert.Equal(t, 50.0, fanout)
+		assert.Equal(t, joinMethodNestedLoop, method)
+	})
+
+	t.Run("内部表の場合は Hash Join で結合し、fanout は推定行数 × 0.1 になる", func(t *testing.T) {
+		// GIVEN
+		pred := &joinPredicate{leftTable: "users", leftCol: "id", rightTable: "t", rightCol: "user_id"}
+
+		// WHEN
+		readCost, fanout, method := calcDerivedTableCost(candidate, 10.0, pred)
+
+		// THEN: readCost = 50 × RowEvaluateCost (ビルド) + 10 × RowEvaluateCost (プローブ)
+		assert.InDelta(t, 60*RowEvaluateCost, readCost, 1e-9)
+		assert.InDelta(t, 5.0, fanout, 1e-9)
+		assert.Equal(t, joinMethodHash, method)
+	})
+}
+
 func TestCalcDrivingTableCostWithWhere(t *testing.T) {
 	t.Run("WHERE に PK 等値検索がある場合ユニークスキャンコストになる", func(t *testing.T) {
 		// GIVEN
@@ -382,7 +415,7 @@ func TestCalcFiltered(t *testing.T) {
 					{Name: "id", Pos: 0}, {Name: "name", Pos: 1}, {Name: "email", Pos: 2},
 				},
 				Indexes: []*dictionary.IndexMeta{
-					{Name: "idx_email", ColName: "email", Type: dictionary.IndexTypeUnique},
+					{Name: "idx_email", ColNames: []string{"email"}, Type: dictionary.IndexTypeUnique},
 				},
 			},
 			stats: &handler.TableStatistics{
@@ -545,7 +578,7 @@ func TestJoinPlanSelection(t *testing.T) {
 			Name: "small", NCols: 2, PKCount: 1,
 			Cols: []*dictionary.ColumnMeta{{Name: "id", Pos: 0}, {Name: "large_id", Pos: 1}},
 			Indexes: []*dictionary.IndexMeta{
-				{Name: "idx_large_id", ColName: "large_id", Type: dictionary.IndexTypeUnique},
+				{Name: "idx_large_id", ColNames: []string{"large_id"}, Type: dictionary.IndexTypeUnique},
 			},
 		}
 		largeStats := &handler.TableStatistics{
@@ -579,6 +612,91 @@ func TestJoinPlanSelection(t *testing.T) {
 		assert.Equal(t, "large", result[1].tblMeta.Name)
 	})
 
+	t.Run("外部結合の内部表はコストが低くても駆動表に選ばれない", func(t *testing.T) {
+		// GIVEN: large LEFT JOIN small (small の方がコストが低いが、NULL で補完される側)
+		setupUsersTable(t)
+		hdl := handler.Get()
+		usersTbl, err := hdl.GetTable("users")
+		require.NoError(t, err)
+
+		largeMeta := &handler.TableMetadata{
+			Name: "large", NCols: 2, PKCount: 1,
+			Cols: []*dictionary.ColumnMeta{{Name: "id", Pos: 0}, {Name: "val", Pos: 1}},
+		}
+		smallMeta := &handler.TableMetadata{
+			Name: "small", NCols: 2, PKCount: 1,
+			Cols: []*dictionary.ColumnMeta{{Name: "id", Pos: 0}, {Name: "large_id", Pos: 1}},
+			Indexes: []*dictionary.IndexMeta{
+				{Name: "idx_large_id", ColNames: []string{"large_id"}, Type: dictionary.IndexTypeUnique},
+			},
+		}
+		largeStats := &handler.TableStatistics{
+			RecordCount: 10000, LeafPageCount: 100, TreeHeight: 3,
+			ColStats: map[string]handler.ColumnStatistics{},
+			IdxStats: map[string]handler.IndexStatistics{},
+		}
+		smallStats := &handler.TableStatistics{
+			RecordCount: 10, LeafPageCount: 1, TreeHeight: 1,
+			ColStats: map[string]handler.ColumnStatistics{},
+			IdxStats: map[string]handler.IndexStatistics{
+				"idx_large_id": {Height: 1, LeafPageCount: 1, RecPerKey: 1.0},
+			},
+		}
+		candidates := []joinCandidate{
+			{tblMeta: largeMeta, stats: largeStats, table: usersTbl},
+			{tblMeta: smallMeta, stats: smallStats, table: usersTbl},
+		}
+		predicates := []joinPredicate{
+			{leftTable: "large", leftCol: "id", rightTable: "small", rightCol: "large_id", outerTable: "small"},
+		}
+
+		// WHEN
+		result, err := optimizeJoinOrder(hdl.BufferPool, candidates, predicates, nil, nil)
+
+		// THEN: 外部結合の外側の large が駆動表になる
+		require.NoError(t, err)
+		assert.Equal(t, "large", result[0].tblMeta.Name)
+		assert.Equal(t, "small", result[1].tblMeta.Name)
+	})
+
+	t.Run("残りのテーブルを結合できなくなる駆動表は選ばれない", func(t *testing.T) {
+		// GIVEN: a LEFT JOIN b ON a.id = b.a_id JOIN c ON b.id = c.b_id
+		// (c を駆動表にすると、b は a より後にしか結合できず、a は c と結合条件がないため結合できない)
+		setupUsersTable(t)
+		hdl := handler.Get()
+		usersTbl, err := hdl.GetTable("users")
+		require.NoError(t, err)
+
+		statsOf := func(records uint64) *handler.TableStatistics {
+			return &handler.TableStatistics{
+				RecordCount: records, LeafPageCount: records / 10, TreeHeight: 2,
+				ColStats: map[string]handler.ColumnStatistics{},
+				IdxStats: map[string]handler.IndexStatistics{},
+			}
+		}
+		metaA := &handler.TableMetadata{Name: "a", NCols: 1, PKCount: 1, Cols: []*dictionary.ColumnMeta{{Name: "id", Pos: 0}}}
+		metaB := &handler.TableMetadata{Name: "b", NCols: 2, PKCount: 1, Cols: []*dictionary.ColumnMeta{{Name: "id", Pos: 0}, {Name: "a_id", Pos: 1}}}
+		metaC := &handler.TableMetadata{Name: "c", NCols: 2, PKCount: 1, Cols: []*dictionary.ColumnMeta{{Name: "id", Pos: 0}, {Name: "b_id", Pos: 1}}}
+		candidates := []joinCandidate{
+			{tblMeta: metaA, stats: statsOf(1000), table: usersTbl},
+			{tblMeta: metaB, stats: statsOf(1000), table: usersTbl},
+			{tblMeta: metaC, stats: statsOf(10), table: usersTbl},
+		}
+		predicates := []joinPredicate{
+			{leftTable: "a", leftCol: "id", rightTable: "b", rightCol: "a_id", outerTable: "b"},
+			{leftTable: "b", leftCol: "id", rightTable: "c", rightCol: "b_id"},
+		}
+
+		// WHEN
+		result, err := optimizeJoinOrder(hdl.BufferPool, candidates, predicates, nil, nil)
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, "a", result[0].tblMeta.Name)
+		assert.Equal(t, "b", result[1].tblMeta.Name)
+		assert.Equal(t, "c", result[2].tblMeta.Name)
+	})
+
 	t.Run("内部表にインデックスがないテーブルはコストが高くなる", func(t *testing.T) {
 		// GIVEN
 		setupUsersTable(t)
@@ -610,7 +728,7 @@ func TestJoinPlanSelection(t *testing.T) {
 			Name: "b_idx", NCols: 2, PKCount: 1,
 			Cols: []*dictionary.ColumnMeta{{Name: "id", Pos: 0}, {Name: "a_val", Pos: 1}},
 			Indexes: []*dictionary.IndexMeta{
-				{Name: "idx_a_val", ColName: "a_val", Type: dictionary.IndexTypeUnique},
+				{Name: "idx_a_val", ColNames: []string{"a_val"}, Type: dictionary.IndexTypeUnique},
 			},
 		}
 		statsBWithIdx := &handler.TableStatistics{
@@ -632,6 +750,60 @@ func TestJoinPlanSelection(t *testing.T) {
 		assert.Equal(t, 1.0, fanoutWithIdx, "インデックスあり: fanout = 1 (eq_ref)")
 	})
 
+	t.Run("内部表の結合カラムにインデックスがない場合は Hash Join が選ばれる", func(t *testing.T) {
+		// GIVEN: a (10 行) JOIN b (1000 行、結合カラムにインデックスなし)
+		setupUsersTable(t)
+		hdl := handler.Get()
+		usersTbl, err := hdl.GetTable("users")
+		require.NoError(t, err)
+
+		metaA := &handler.TableMetadata{Name: "a", NCols: 2, PKCount: 1, Cols: []*dictionary.ColumnMeta{{Name: "id", Pos: 0}, {Name: "val", Pos: 1}}}
+		metaB := &handler.TableMetadata{Name: "b", NCols: 2, PKCount: 1, Cols: []*dictionary.ColumnMeta{{Name: "id", Pos: 0}, {Name: "a_val", Pos: 1}}}
+		statsA := &handler.TableStatistics{RecordCount: 10, LeafPageCount: 1, TreeHeight: 1, ColStats: map[string]handler.ColumnStatistics{}, IdxStats: map[string]handler.IndexStatistics{}}
+		statsB := &handler.TableStatistics{RecordCount: 1000, LeafPageCount: 10, TreeHeight: 2, ColStats: map[string]handler.ColumnStatistics{}, IdxStats: map[string]handler.IndexStatistics{}}
+		candidates := []joinCandidate{
+			{tblMeta: metaA, stats: statsA, table: usersTbl},
+			{tblMeta: metaB, stats: statsB, table: usersTbl},
+		}
+		predicates := []joinPredicate{{leftTable: "a", leftCol: "val", rightTable: "b", rightCol: "a_val"}}
+
+		// WHEN
+		result, err := optimizeJoinOrder(hdl.BufferPool, candidates, predicates, nil, nil)
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, "a", result[0].tblMeta.Name)
+		assert.Equal(t, "b", result[1].tblMeta.Name)
+		assert.Equal(t, joinMethodHash, result[1].method)
+	})
+
+	t.Run("内部表を少ない回数の eq_ref で検索できる場合は Nested Loop Join が選ばれる", func(t *testing.T) {
+		// GIVEN: a (10 行) JOIN b (1000 行、結合カラムは PK)
+		setupUsersTable(t)
+		hdl := handler.Get()
+		usersTbl, err := hdl.GetTable("users")
+		require.NoError(t, err)
+
+		metaA := &handler.TableMetadata{Name: "a", NCols: 2, PKCount: 1, Cols: []*dictionary.ColumnMeta{{Name: "id", Pos: 0}, {Name: "b_id", Pos: 1}}}
+		metaB := &handler.TableMetadata{Name: "b", NCols: 2, PKCount: 1, Cols: []*dictionary.ColumnMeta{{Name: "id", Pos: 0}, {Name: "val", Pos: 1}}}
+		statsA := &handler.TableStatistics{RecordCount: 10, LeafPageCount: 1, TreeHeight: 1, ColStats: map[string]handler.ColumnStatistics{}, IdxStats: map[string]handler.IndexStatistics{}}
+		statsB := &handler.TableStatistics{RecordCount: 1000, LeafPageCount: 10, TreeHeight: 2, ColStats: map[string]handler.ColumnStatistics{}, IdxStats: map[string]handler.IndexStatistics{}}
+		candidates := []joinCandidate{
+			{tblMeta: metaA, stats: statsA, table: usersTbl},
+			{tblMeta: metaB, stats: statsB, table: usersTbl},
+		}
+		predicates := []joinPredicate{{leftTable: "a", leftCol: "b_id", rightTable: "b", rightCol: "id"}}
+
+		// WHEN
+		result, err := optimizeJoinOrder(hdl.BufferPool, candidates, predicates, nil, nil)
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, "a", result[0].tblMeta.Name)
+		assert.Equal(t, "b", result[1].tblMeta.Name)
+		assert.Equal(t, joinMethodNestedLoop, result[1].method)
+	})
+
 	t.Run("3 テーブルの結合順序が貪欲法で決定される", func(t *testing.T) {
 		// GIVEN: A (100行), B (10行), C (1000行)
 		// B が最小なので駆動表、次に A (B→A の結合条件あり)、最後に C
@@ -644,7 +816,7 @@ func TestJoinPlanSelection(t *testing.T) {
 			Name: "a", NCols: 2, PKCount: 1,
 			Cols: []*dictionary.ColumnMeta{{Name: "id", Pos: 0}, {Name: "val", Pos: 1}},
 			Indexes: []*dictionary.IndexMeta{
-				{Name: "idx_a_val", ColName: "val", Type: dictionary.IndexTypeUnique},
+				{Name: "idx_a_val", ColNames: []string{"val"}, Type: dictionary.IndexTypeUnique},
 			},
 		}
 		metaB := &handler.TableMetadata{
@@ -655,7 +827,7 @@ func TestJoinPlanSelection(t *testing.T) {
 			Name: "c", NCols: 2, PKCount: 1,
 			Cols: []*dictionary.ColumnMeta{{Name: "id", Pos: 0}, {Name: "a_id", Pos: 1}},
 			Indexes: []*dictionary.IndexMeta{
-				{Name: "idx_c_a_id", ColName: "a_id", Type: dictionary.IndexTypeUnique},
+				{Name: "idx_c_a_id", ColNames: []string{"a_id"}, Type: dictionary.IndexTypeUnique},
 			},
 		}
 		statsA := &handler.TableStatistics{
@@ -738,7 +910,7 @@ func TestOptimizeJoinOrder(t *testing.T) {
 				{Name: "user_id", Pos: 1},
 			},
 			Indexes: []*dictionary.IndexMeta{
-				{Name: "idx_user_id", ColName: "user_id", Type: dictionary.IndexTypeUnique},
+				{Name: "idx_user_id", ColNames: []string{"user_id"}, Type: dictionary.IndexTypeUnique},
 			},
 		}
 
@@ -786,7 +958,7 @@ func TestOptimizeJoinOrder(t *testing.T) {
 			Name: "large", NCols: 2, PKCount: 1,
 			Cols: []*dictionary.ColumnMeta{{Name: "id", Pos: 0}, {Name: "small_id", Pos: 1}},
 			Indexes: []*dictionary.IndexMeta{
-				{Name: "idx_small_id", ColName: "small_id", Type: dictionary.IndexTypeUnique, DataMetaPageId: page.InvalidPageId},
+				{Name: "idx_small_id", ColNames: []string{"small_id"}, Type: dictionary.IndexTypeUnique, DataMetaPageId: page.InvalidPageId},
 			},
 		}
 		smallStats := &handler.TableStatistics{
@@ -854,6 +1026,26 @@ func TestOptimizeJoinOrder(t *testing.T) {
 	})
 }
 
+func TestCalcHashJoinReadCost(t *testing.T) {
+	stats := &handler.TableStatistics{RecordCount: 1000, LeafPageCount: 10}
+
+	t.Run("内部表を 1 回だけ読み込むコストと、外側の各行でハッシュテーブルを検索するコストの和になる", func(t *testing.T) {
+		// WHEN
+		cost := calcHashJoinReadCost(stats, 100, 1.0, 10*page.PageSize)
+
+		// THEN: (10 × 1.0 + 1000 × 0.1) + 100 × 0.1
+		assert.InDelta(t, 120.0, cost, 1e-9)
+	})
+
+	t.Run("内部表がバッファサイズを超える場合は、分割して書き出し読み直すコストを加える", func(t *testing.T) {
+		// WHEN
+		cost := calcHashJoinReadCost(stats, 100, 1.0, 10*page.PageSize-1)
+
+		// THEN: 120 + 2 × 10 × 1.0 + 2 × 100 × 0.1
+		assert.InDelta(t, 160.0, cost, 1e-9)
+	})
+}
+
 func TestFindPredicate(t *testing.T) {
 	predicates := []joinPredicate{
 		{leftTable: "users", leftCol: "id", rightTable: "orders", rightCol: "user_id"},
@@ -897,6 +1089,23 @@ func TestFindPredicate(t *testing.T) {
 		assert.Nil(t, pred)
 	})
 
+	t.Run("外部結合の内部表は自身の ON 条件のみにマッチし、それ以外のテーブルは外部結合の ON 条件にマッチしない", func(t *testing.T) {
+		// GIVEN: users LEFT JOIN orders ON users.id = orders.user_id
+		outerPredicates := []joinPredicate{
+			{leftTable: "users", leftCol: "id", rightTable: "orders", rightCol: "user_id", outerTable: "orders"},
+			{leftTable: "users", leftCol: "id", rightTable: "items", rightCol: "id"},
+		}
+
+		// WHEN
+		ordersPred := findPredicate(outerPredicates, "orders", map[string]struct{}{"users": {}})
+		usersPred := findPredicate(outerPredicates, "users", map[string]struct{}{"orders": {}})
+
+		// THEN
+		require.NotNil(t, ordersPred)
+		assert.Equal(t, "orders", ordersPred.outerTable)
+		assert.Nil(t, usersPred)
+	})
+
 	t.Run("resultTableNames が空の場合は nil を返す", func(t *testing.T) {
 		// GIVEN
 		resultTableNames := map[string]struct{}{}
@@ -0,0 +1,109 @@
+package planner
+
+import (
+	"fmt"
+
+	"github.com/ren-yamanashi/minesql/internal/ast"
+	"github.com/ren-yamanashi/minesql/internal/executor"
+	"github.com/ren-yamanashi/minesql/internal/parser"
+	"github.com/ren-yamanashi/minesql/internal/storage/handler"
+)
+
+// parseLiteral はリテラルをカラムのデータ型に応じた格納形式に変換する
+//
+// NULL リテラルの場合は nil を返す
+func parseLiteral(colMeta *handler.ColumnMetadata, lit ast.Literal) ([]byte, error) {
+	if _, ok := lit.(*ast.NullLiteral); ok {
+		return nil, nil
+	}
+	return colMeta.ParseValue(lit.ToString())
+}
+
+// isNotNullColumn はカラムが NULL を許容しないかどうかを返す
+//
+// プライマリキーのカラムは NOT NULL の指定がなくても NULL を許容しない
+func isNotNullColumn(tblMeta *handler.TableMetadata, colMeta *handler.ColumnMetadata) bool {
+	return colMeta.NotNull || colMeta.Pos < uint16(tblMeta.PKCount)
+}
+
+// checkNotNull は挿入するレコードが NOT NULL 制約を満たしているかを確認する
+//
+// AUTO_INCREMENT カラムの NULL は、実行時に採番した値に置き換えるため対象外とする
+func checkNotNull(tblMeta *handler.TableMetadata, record executor.Record) error {
+	for _, col := range tblMeta.GetSortedCols() {
+		if record[col.Pos] == nil && isNotNullColumn(tblMeta, col) && !col.AutoIncrement {
+			return notNullError(col.Name)
+		}
+	}
+	return nil
+}
+
+// notNullError は NOT NULL 制約のカラムに NULL を設定しようとした場合のエラーを返す
+func notNullError(colName string) error {
+	return fmt.Errorf("column '%s' cannot be null", colName)
+}
+
+// evalDefault はカラムの DEFAULT 句の式 (SQL 表現) を評価し、カラムのデータ型に応じた格納形式に変換する
+//
+// DEFAULT NULL の場合は nil を返す
+func evalDefault(colMeta *handler.ColumnMetadata) ([]byte, error) {
+	expr, err := parser.ParseExpr(colMeta.Default)
+	if err != nil {
+		return nil, err
+	}
+	return evalDefaultExpr(colMeta, expr)
+}
+
+// evalDefaultExpr は DEFAULT 句の式を評価し、カラムのデータ型に応じた格納形式に変換する
+//
+// DEFAULT 句の式はカラムを参照できない
+func evalDefaultExpr(colMeta *handler.ColumnMetadata, expr ast.Expr) ([]byte, error) {
+	if cols := collectColumns(expr); len(cols) > 0 {
+		return nil, fmt.Errorf("default value of column '%s' cannot refer to column '%s'", colMeta.Name, cols[0].ColName)
+	}
+	compiled, err := compileAssignment(expr, newExprScope(nil), colMeta)
+	if err != nil {
+		return nil, err
+	}
+	return compiled.Eval(nil)
+}
+
+// defaultRecord は INSERT で値を指定しなかったカラムの値 (DEFAULT 句の式を評価した値) を、テーブルのカラム順に並べて返す
+//
+// 値を指定したカラムと DEFAULT 句を指定していないカラムは nil (NULL) にする
+func defaultRecord(tblMeta *handler.TableMetadata, specified map[string]bool) (executor.Record, error) {
+	record := make(executor.Record, len(tblMeta.Cols))
+	for _, col := range tblMeta.GetSortedCols() {
+		if specified[col.Name] || col.Default == "" {
+			continue
+		}
+		value, err := evalDefault(col)
+		if err != nil {
+			return nil, err
+		}
+		record[col.Pos] = value
+	}
+	return record, nil
+}
+
+// compileCheckConstraints はテーブルの CHECK 制約の条件式 (SQL 表現) を、テーブルのレコードに対する式にコンパイルする
+func compileCheckConstraints(tblMeta *handler.TableMetadata) ([]executor.CheckConstraint, error) {
+	cons := tblMeta.GetCheckConstraints()
+	if len(cons) == 0 {
+		return nil, nil
+	}
+	scope := newTableExprScope(tblMeta)
+	checks := make([]executor.CheckConstraint, 0, len(cons))
+	for _, con := range cons {
+		expr, err := parser.ParseExpr(con.CheckExpr)
+		if err != nil {
+			return nil, err
+		}
+		cond, err := compileCondition(expr, scope)
+		if err != nil {
+			return nil, err
+		}
+		checks = append(checks, executor.CheckConstraint{Name: con.ConstraintName, Expr: cond})
+	}
+	return checks, nil
+}
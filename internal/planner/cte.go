@@ -0,0 +1,398 @@
+package planner
+
+import (
+	"fmt"
+
+	"github.com/ren-yamanashi/minesql/internal/ast"
+	"github.com/ren-yamanashi/minesql/internal/executor"
+	"github.com/ren-yamanashi/minesql/internal/storage/handler"
+)
+
+// bindWith は WITH 句の共通テーブル式への参照を解決する
+//
+// 共通テーブル式の問い合わせと文 (入れ子の集合演算・導出テーブル・サブクエリを含む) の FROM 句・JOIN 句のテーブルのうち、
+// 共通テーブル式と同じ名前のテーブルに参照先の共通テーブル式を設定する (同じ名前のテーブルより共通テーブル式を優先する)
+//   - 共通テーブル式は、それより前に定義した共通テーブル式を参照できる
+//   - WITH RECURSIVE の場合は、共通テーブル式自身も参照できる
+func bindWith(with *ast.WithClause, stmt ast.Statement) error {
+	if with == nil {
+		return nil
+	}
+	visible := make(map[string]*ast.CommonTableExpr, len(with.CTEs))
+	for _, cte := range with.CTEs {
+		if _, dup := visible[cte.Name]; dup {
+			return fmt.Errorf("duplicate CTE name %s", cte.Name)
+		}
+		if with.Recursive {
+			visible[cte.Name] = cte
+		}
+		bindCTEReferences(cte.Query, visible)
+		visible[cte.Name] = cte
+	}
+	bindCTEReferences(stmt, visible)
+	return nil
+}
+
+// bindCTEReferences は文のテーブルのうち、ctes にある名前のテーブルに参照先の共通テーブル式を設定する
+func bindCTEReferences(stmt ast.Statement, ctes map[string]*ast.CommonTableExpr) {
+	walkTables(stmt, func(table *ast.TableId) {
+		if table.Subquery != nil {
+			return
+		}
+		if cte, ok := ctes[table.TableName]; ok {
+			table.CTE = cte
+		}
+	})
+}
+
+// walkTables は文 (入れ子の集合演算・導出テーブル・サブクエリを含む) の FROM 句・JOIN 句のテーブルを走査し、fn を適用する
+//
+// 共通テーブル式を参照するテーブルの場合、共通テーブル式の問い合わせの中は走査しない
+func walkTables(stmt ast.Statement, fn func(table *ast.TableId)) {
+	switch s := stmt.(type) {
+	case *ast.SetOperationStmt:
+		walkTables(s.Left, fn)
+		walkTables(s.Right, fn)
+	case *ast.SelectStmt:
+		tables := []*ast.TableId{&s.From}
+		for _, join := range s.Joins {
+			tables = append(tables, &join.Table)
+		}
+		for _, table := range tables {
+			fn(table)
+			if table.Subquery != nil {
+				walkTables(table.Subquery, fn)
+			}
+		}
+
+		exprs := s.SelectItems()
+		for _, join := range s.Joins {
+			exprs = append(exprs, join.Condition)
+		}
+		if s.Where != nil {
+			exprs = append(exprs, s.Where.Condition)
+		}
+		if s.Having != nil {
+			exprs = append(exprs, s.Having.Condition)
+		}
+		for _, expr := range exprs {
+			ast.WalkExpr(expr, func(e ast.Expr) bool {
+				switch v := e.(type) {
+				case *ast.SubqueryExpr:
+					walkTables(v.Select, fn)
+				case *ast.ExistsExpr:
+					walkTables(v.Subquery, fn)
+				case *ast.InExpr:
+					if v.Subquery != nil {
+						walkTables(v.Subquery, fn)
+					}
+				}
+				return true
+			})
+		}
+	}
+}
+
+// countCTEReferences は文 (入れ子の集合演算・導出テーブル・サブクエリを含む) が共通テーブル式を参照する回数を返す
+func countCTEReferences(stmt ast.Statement, cte *ast.CommonTableExpr) int {
+	count := 0
+	walkTables(stmt, func(table *ast.TableId) {
+		if table.CTE == cte {
+			count++
+		}
+	})
+	return count
+}
+
+// isRecursiveCTE は共通テーブル式が自身を参照する (再帰 CTE である) かどうかを返す
+func isRecursiveCTE(cte *ast.CommonTableExpr) bool {
+	return countCTEReferences(cte.Query, cte) > 0
+}
+
+// planCTE は共通テーブル式の問い合わせを計画する
+//
+// 再帰しない共通テーブル式は、参照ごとに問い合わせを計画して参照元の Executor ツリーに組み込む (結果を一時テーブルに保持しない)
+func planCTE(trxId handler.TrxId, cte *ast.CommonTableExpr) (*PlanResult, error) {
+	if isRecursiveCTE(cte) {
+		return planRecursiveCTE(trxId, cte)
+	}
+
+	var result *PlanResult
+	var err error
+	switch q := cte.Query.(type) {
+	case *ast.SelectStmt:
+		result, err = PlanSelect(trxId, q)
+	case *ast.SetOperationStmt:
+		result, err = PlanSetOperation(trxId, q)
+	default:
+		return nil, fmt.Errorf("unsupported CTE query: %T", q)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := renameCTEColumns(cte, result.Columns); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// renameCTEColumns は共通テーブル式のカラム名のリストを結果セットのカラム名にする
+func renameCTEColumns(cte *ast.CommonTableExpr, columns []ColumnMeta) error {
+	if cte.Columns == nil {
+		return nil
+	}
+	if len(cte.Columns) != len(columns) {
+		return fmt.Errorf("the number of columns of CTE %s does not match its column list", cte.Name)
+	}
+	for i, name := range cte.Columns {
+		columns[i].ColName = name
+	}
+	return nil
+}
+
+// recursiveRef は再帰 CTE の再帰部分を計画する際の、共通テーブル式自身への参照
+type recursiveRef struct {
+	cte     *ast.CommonTableExpr
+	tblMeta *handler.TableMetadata // 作業テーブルのメタデータ (非再帰部分の結果セットのカラム)
+	working *executor.WorkingTable
+}
+
+// joinCandidate は作業テーブルを読み取る joinCandidate を返す (行数は現在の作業テーブルの行数)
+func (r *recursiveRef) joinCandidate() joinCandidate {
+	return joinCandidate{
+		tblMeta: r.tblMeta,
+		stats:   &handler.TableStatistics{RecordCount: uint64(r.working.Len())},
+		derived: executor.NewWorkingTableScan(r.cte.Name, r.working),
+	}
+}
+
+// planRecursiveCTE は再帰 CTE を計画する
+//
+// 問い合わせを UNION で結合した SELECT 文に分解し、自身を参照しない SELECT 文を非再帰部分、参照する SELECT 文を再帰部分とする
+// 結果セットのカラム名・データ型は非再帰部分のものを使い、再帰部分の結果は非再帰部分のデータ型に変換する
+// 再帰部分は反復ごとに作業テーブルの行数で計画し直す (計画時には空の作業テーブルで計画し、EXPLAIN で表示する)
+func planRecursiveCTE(trxId handler.TrxId, cte *ast.CommonTableExpr) (*PlanResult, error) {
+	query, ok := cte.Query.(*ast.SetOperationStmt)
+	if !ok {
+		return nil, fmt.Errorf("recursive CTE %s must be a UNION of a non-recursive SELECT and a recursive SELECT", cte.Name)
+	}
+	operands, all, err := recursiveCTEOperands(cte, query)
+	if err != nil {
+		return nil, err
+	}
+
+	var anchors, recursives []*ast.SelectStmt
+	for _, operand := range operands {
+		if countCTEReferences(operand, cte) == 0 {
+			anchors = append(anchors, operand)
+			continue
+		}
+		if err := validateRecursiveSelect(cte, operand); err != nil {
+			return nil, err
+		}
+		recursives = append(recursives, operand)
+	}
+	if len(anchors) == 0 {
+		return nil, fmt.Errorf("recursive CTE %s must have a non-recursive SELECT", cte.Name)
+	}
+
+	anchor, err := planUnionAllSelects(anchors, func(stmt *ast.SelectStmt) (*PlanResult, error) {
+		return PlanSelect(trxId, stmt)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := renameCTEColumns(cte, anchor.Columns); err != nil {
+		return nil, err
+	}
+	tblMeta, err := derivedTableMetadata(cte.Name, anchor.Columns)
+	if err != nil {
+		return nil, err
+	}
+
+	self := &recursiveRef{cte: cte, tblMeta: tblMeta, working: executor.NewWorkingTable()}
+	planRecursive := func() (*PlanResult, error) {
+		result, err := planUnionAllSelects(recursives, func(stmt *ast.SelectStmt) (*PlanResult, error) {
+			result, err := planSelectJoin(trxId, stmt, self)
+			if err != nil {
+				return nil, err
+			}
+			applySelectAliases(result, stmt)
+			return result, nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		if err := conformRecursiveColumns(anchor.Columns, result); err != nil {
+			return nil, err
+		}
+		return result, nil
+	}
+	recursivePlan, err := planRecursive()
+	if err != nil {
+		return nil, err
+	}
+
+	buildRecursiveExec := func(executor.Record) (executor.Executor, error) {
+		result, err := planRecursive()
+		if err != nil {
+			return nil, err
+		}
+		return result.Exec, nil
+	}
+	exec := executor.NewRecursiveCTE(cte.Name, anchor.Exec, buildRecursiveExec, self.working, !all)
+	exec.SetRecursivePlan(recursivePlan.Exec)
+	return planResultOrderBy(&PlanResult{Exec: exec, Columns: anchor.Columns}, query.OrderBy, query.Limit)
+}
+
+// recursiveCTEOperands は再帰 CTE の問い合わせを、UNION で結合した SELECT 文に分解する
+//
+// UNION と UNION ALL を混在させることはできない (all は UNION ALL の場合に true)
+func recursiveCTEOperands(cte *ast.CommonTableExpr, query *ast.SetOperationStmt) (operands []*ast.SelectStmt, all bool, err error) {
+	var collect func(stmt ast.Statement) error
+	collect = func(stmt ast.Statement) error {
+		switch s := stmt.(type) {
+		case *ast.SelectStmt:
+			operands = append(operands, s)
+			return nil
+		case *ast.SetOperationStmt:
+			if s.Op != ast.SetOpUnion {
+				return fmt.Errorf("recursive CTE %s must combine SELECT statements with UNION, not %s", cte.Name, s.Op)
+			}
+			if s.All != query.All {
+				return fmt.Errorf("recursive CTE %s must not mix UNION and UNION ALL", cte.Name)
+			}
+			if err := collect(s.Left); err != nil {
+				return err
+			}
+			return collect(s.Right)
+		default:
+			return fmt.Errorf("unsupported set operation operand: %T", s)
+		}
+	}
+	if err := collect(query); err != nil {
+		return nil, false, err
+	}
+	return operands, query.All, nil
+}
+
+// validateRecursiveSelect は再帰部分の SELECT 文が再帰 CTE の制約を満たすか検証する
+//   - 集約・ウィンドウ関数・DISTINCT・ORDER BY・LIMIT を含まない (反復ごとに評価するため、結果全体に対する処理はできない)
+//   - 共通テーブル式自身を FROM 句・JOIN 句で 1 回だけ参照する (サブクエリ・導出テーブルの中では参照できない)
+//   - 共通テーブル式自身が外部結合で NULL で補完される側にならない
+func validateRecursiveSelect(cte *ast.CommonTableExpr, stmt *ast.SelectStmt) error {
+	if stmt.HasAggregation() || stmt.HasWindow() || stmt.Distinct || len(stmt.OrderBy) > 0 || stmt.Limit != nil {
+		return fmt.Errorf("recursive SELECT of CTE %s must not contain aggregation, window functions, DISTINCT, ORDER BY or LIMIT", cte.Name)
+	}
+
+	direct := 0
+	for _, table := range collectTables(stmt) {
+		if table.CTE == cte {
+			direct++
+		}
+	}
+	if countCTEReferences(stmt, cte) != direct {
+		return fmt.Errorf("recursive CTE %s must not be referenced in a subquery or derived table", cte.Name)
+	}
+	if direct != 1 {
+		return fmt.Errorf("recursive CTE %s must be referenced only once in the recursive SELECT", cte.Name)
+	}
+
+	for i, join := range stmt.Joins {
+		inner := ast.TableId{}
+		switch join.Type {
+		case ast.JoinTypeLeft:
+			inner = join.Table
+		case ast.JoinTypeRight:
+			if i == 0 {
+				inner = stmt.From
+			}
+		}
+		if inner.CTE == cte {
+			return fmt.Errorf("recursive CTE %s must not be the NULL-supplemented side of an outer join", cte.Name)
+		}
+	}
+	return nil
+}
+
+// planUnionAllSelects は SELECT 文をそれぞれ計画し、共通のデータ型に揃えて UnionAll で連結する (SELECT 文が 1 つの場合はそのまま返す)
+func planUnionAllSelects(stmts []*ast.SelectStmt, plan func(stmt *ast.SelectStmt) (*PlanResult, error)) (*PlanResult, error) {
+	results := make([]*PlanResult, len(stmts))
+	for i, stmt := range stmts {
+		result, err := plan(stmt)
+		if err != nil {
+			return nil, err
+		}
+		results[i] = result
+	}
+	if len(results) == 1 {
+		return results[0], nil
+	}
+
+	columns, err := unifySetOperationColumns(ast.SetOpUnion, results)
+	if err != nil {
+		return nil, err
+	}
+	execs := make([]executor.Executor, len(results))
+	for i, result := range results {
+		execs[i] = result.Exec
+	}
+	return &PlanResult{Exec: executor.NewUnionAll(execs), Columns: columns}, nil
+}
+
+// conformRecursiveColumns は再帰部分の結果セットのカラムを、非再帰部分のデータ型 (作業テーブルのデータ型) に揃える
+//
+// 作業テーブルのデータ型は再帰部分を計画する前に決まるため、共通のデータ型ではなく非再帰部分のデータ型に変換する
+// (e.g. INT のカラムを `id + 1` で辿る場合、BIGINT の結果を INT に変換し、INT の範囲を超える値はエラーになる)
+func conformRecursiveColumns(columns []ColumnMeta, result *PlanResult) error {
+	if len(result.Columns) != len(columns) {
+		return fmt.Errorf("the SELECT statements of %s have a different number of columns", ast.SetOpUnion)
+	}
+	for i := range columns {
+		if _, ok := commonType(columns[i].metadata(), result.Columns[i].metadata()); !ok {
+			return fmt.Errorf("column %d of the SELECT statements of %s has different types", i+1, ast.SetOpUnion)
+		}
+	}
+	result.Exec = conformColumns(result, columns)
+	result.Columns = columns
+	return nil
+}
+
+// estimateCTERows は共通テーブル式の行数を推定する
+//
+// 再帰 CTE は反復の回数を見積もれないため、非再帰部分の行数とする
+func estimateCTERows(cte *ast.CommonTableExpr) (uint64, error) {
+	return estimateStatementRows(cte.Query, cte)
+}
+
+// estimateStatementRows は SELECT 文・集合演算の行数を推定する (cte を参照する SELECT 文は 0 行とする)
+//
+//   - UNION: 両側の行数の合計
+//   - INTERSECT・EXCEPT: 左側の行数
+func estimateStatementRows(stmt ast.Statement, cte *ast.CommonTableExpr) (uint64, error) {
+	switch s := stmt.(type) {
+	case *ast.SelectStmt:
+		if countCTEReferences(s, cte) > 0 {
+			return 0, nil
+		}
+		return estimateDerivedRows(s)
+	case *ast.SetOperationStmt:
+		rows, err := estimateStatementRows(s.Left, cte)
+		if err != nil {
+			return 0, err
+		}
+		if s.Op == ast.SetOpUnion {
+			right, err := estimateStatementRows(s.Right, cte)
+			if err != nil {
+				return 0, err
+			}
+			rows += right
+		}
+		if s.Limit != nil {
+			rows = min(rows, s.Limit.Count)
+		}
+		return rows, nil
+	default:
+		return 0, fmt.Errorf("unsupported CTE query: %T", s)
+	}
+}
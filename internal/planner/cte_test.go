@@ -0,0 +1,308 @@
+package planner
+
+import (
+	"testing"
+
+	"github.com/ren-yamanashi/minesql/internal/ast"
+	"github.com/ren-yamanashi/minesql/internal/executor"
+	"github.com/ren-yamanashi/minesql/internal/storage/handler"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlanSelectWith(t *testing.T) {
+	t.Run("共通テーブル式を FROM 句で参照できる", func(t *testing.T) {
+		// GIVEN
+		setupUsersTable(t)
+		defer handler.Reset()
+		stmt := parseStatementForTest(t, "WITH males AS (SELECT id, first_name FROM users WHERE gender = 'male') SELECT first_name FROM males WHERE id >= '3' ORDER BY first_name;").(*ast.SelectStmt)
+
+		// WHEN
+		plan, err := PlanSelect(0, stmt)
+		require.NoError(t, err)
+		results := fetchAll(t, plan.Exec)
+
+		// THEN
+		assert.Equal(t, []string{"John", "Jonathan", "Tom"}, columnStrings(results, 0))
+	})
+
+	t.Run("カラム名のリストで結果セットのカラム名を付け替える", func(t *testing.T) {
+		// GIVEN
+		setupUsersTable(t)
+		defer handler.Reset()
+		stmt := parseStatementForTest(t, "WITH u (uid, name) AS (SELECT id, first_name FROM users) SELECT name FROM u WHERE uid = '4';").(*ast.SelectStmt)
+
+		// WHEN
+		plan, err := PlanSelect(0, stmt)
+		require.NoError(t, err)
+		results := fetchAll(t, plan.Exec)
+
+		// THEN
+		assert.Equal(t, []string{"Jane"}, columnStrings(results, 0))
+		assert.Equal(t, "name", plan.Columns[0].ColName)
+	})
+
+	t.Run("前に定義した共通テーブル式を参照できる", func(t *testing.T) {
+		// GIVEN
+		setupUsersTable(t)
+		defer handler.Reset()
+		stmt := parseStatementForTest(t, "WITH males AS (SELECT id, first_name FROM users WHERE gender = 'male'), johns AS (SELECT id FROM males WHERE first_name = 'John') SELECT id FROM johns ORDER BY id;").(*ast.SelectStmt)
+
+		// WHEN
+		plan, err := PlanSelect(0, stmt)
+		require.NoError(t, err)
+		results := fetchAll(t, plan.Exec)
+
+		// THEN
+		assert.Equal(t, []string{"1", "2", "3"}, columnStrings(results, 0))
+	})
+
+	t.Run("サブクエリから共通テーブル式を参照できる", func(t *testing.T) {
+		// GIVEN
+		setupUsersTable(t)
+		defer handler.Reset()
+		stmt := parseStatementForTest(t, "WITH females AS (SELECT last_name FROM users WHERE gender = 'female') SELECT id FROM users WHERE last_name IN (SELECT last_name FROM females) ORDER BY id;").(*ast.SelectStmt)
+
+		// WHEN
+		plan, err := PlanSelect(0, stmt)
+		require.NoError(t, err)
+		results := fetchAll(t, plan.Exec)
+
+		// THEN
+		assert.Equal(t, []string{"2", "4"}, columnStrings(results, 0))
+	})
+
+	t.Run("集合演算から共通テーブル式を参照できる", func(t *testing.T) {
+		// GIVEN
+		setupUsersTable(t)
+		defer handler.Reset()
+		stmt := parseStatementForTest(t, "WITH males AS (SELECT first_name FROM users WHERE gender = 'male') SELECT first_name FROM males EXCEPT SELECT first_name FROM users WHERE id = '5' ORDER BY first_name;").(*ast.SetOperationStmt)
+
+		// WHEN
+		plan, err := PlanSetOperation(0, stmt)
+		require.NoError(t, err)
+		results := fetchAll(t, plan.Exec)
+
+		// THEN
+		assert.Equal(t, []string{"John", "Tom"}, columnStrings(results, 0))
+	})
+
+	t.Run("同じ名前の共通テーブル式を定義するとエラーになる", func(t *testing.T) {
+		// GIVEN
+		setupUsersTable(t)
+		defer handler.Reset()
+		stmt := parseStatementForTest(t, "WITH u AS (SELECT id FROM users), u AS (SELECT id FROM users) SELECT id FROM u;").(*ast.SelectStmt)
+
+		// WHEN
+		_, err := PlanSelect(0, stmt)
+
+		// THEN
+		assert.EqualError(t, err, "duplicate CTE name u")
+	})
+
+	t.Run("カラム名のリストの数が結果セットのカラム数と異なるとエラーになる", func(t *testing.T) {
+		// GIVEN
+		setupUsersTable(t)
+		defer handler.Reset()
+		stmt := parseStatementForTest(t, "WITH u (a, b) AS (SELECT id FROM users) SELECT a FROM u;").(*ast.SelectStmt)
+
+		// WHEN
+		_, err := PlanSelect(0, stmt)
+
+		// THEN
+		assert.EqualError(t, err, "the number of columns of CTE u does not match its column list")
+	})
+}
+
+func TestPlanSelectWithRecursive(t *testing.T) {
+	t.Run("階層構造を再帰的にたどる", func(t *testing.T) {
+		// GIVEN
+		setupCategoriesTable(t)
+		defer handler.Reset()
+		stmt := parseStatementForTest(t, "WITH RECURSIVE tree (id, name) AS (SELECT id, name FROM categories WHERE id = 2 UNION ALL SELECT categories.id, categories.name FROM categories JOIN tree ON categories.parent_id = tree.id) SELECT name FROM tree ORDER BY name;").(*ast.SelectStmt)
+
+		// WHEN
+		plan, err := PlanSelect(0, stmt)
+		require.NoError(t, err)
+		results := fetchAll(t, plan.Exec)
+
+		// THEN
+		assert.Equal(t, []string{"Apple", "Banana", "Fruit", "Green Apple"}, columnStrings(results, 0))
+	})
+
+	t.Run("UNION の場合は重複を除くため、循環があっても終了する", func(t *testing.T) {
+		// GIVEN
+		setupCategoriesTable(t)
+		defer handler.Reset()
+		stmt := parseStatementForTest(t, "WITH RECURSIVE reach (id) AS (SELECT dst FROM links WHERE src = 1 UNION SELECT links.dst FROM reach JOIN links ON links.src = reach.id) SELECT id FROM reach ORDER BY id;").(*ast.SelectStmt)
+
+		// WHEN
+		plan, err := PlanSelect(0, stmt)
+		require.NoError(t, err)
+		results := fetchAll(t, plan.Exec)
+
+		// THEN
+		assert.Equal(t, []int64{1, 2, 3}, int64Column(results, 0))
+	})
+
+	t.Run("非再帰部分に FROM 句のない SELECT 文を指定できる", func(t *testing.T) {
+		// GIVEN
+		setupCategoriesTable(t)
+		defer handler.Reset()
+		stmt := parseStatementForTest(t, "WITH RECURSIVE c (n) AS (SELECT 1 UNION ALL SELECT n + 1 FROM c WHERE n < 5) SELECT n FROM c;").(*ast.SelectStmt)
+
+		// WHEN
+		plan, err := PlanSelect(0, stmt)
+		require.NoError(t, err)
+		results := fetchAll(t, plan.Exec)
+
+		// THEN
+		assert.Equal(t, []int64{1, 2, 3, 4, 5}, int64Column(results, 0))
+	})
+
+	t.Run("テーブルの INT のカラムを式で辿る場合、再帰部分の結果を非再帰部分のデータ型に揃える", func(t *testing.T) {
+		// GIVEN
+		setupCategoriesTable(t)
+		defer handler.Reset()
+		stmt := parseStatementForTest(t, "WITH RECURSIVE r AS (SELECT id FROM categories WHERE id = 1 UNION ALL SELECT id + 1 FROM r WHERE id < 4) SELECT id FROM r;").(*ast.SelectStmt)
+
+		// WHEN
+		plan, err := PlanSelect(0, stmt)
+		require.NoError(t, err)
+		results := fetchAll(t, plan.Exec)
+
+		// THEN
+		assert.Equal(t, []int64{1, 2, 3, 4}, int64Column(results, 0))
+		assert.Equal(t, handler.ColumnTypeInt, plan.Columns[0].Type)
+	})
+
+	t.Run("再帰部分の結果が非再帰部分のデータ型の範囲を超える場合はエラーになる", func(t *testing.T) {
+		// GIVEN
+		setupCategoriesTable(t)
+		defer handler.Reset()
+		stmt := parseStatementForTest(t, "WITH RECURSIVE r AS (SELECT id FROM categories WHERE id = 5 UNION ALL SELECT id + 2147483643 FROM r WHERE id < 10) SELECT id FROM r;").(*ast.SelectStmt)
+
+		// WHEN
+		plan, err := PlanSelect(0, stmt)
+		require.NoError(t, err)
+		for {
+			var record executor.Record
+			record, err = plan.Exec.Next()
+			if err != nil || record == nil {
+				break
+			}
+		}
+
+		// THEN
+		assert.EqualError(t, err, "out of range value for column 'id'")
+	})
+
+	t.Run("反復回数が上限を超えるとエラーになる", func(t *testing.T) {
+		// GIVEN
+		setupCategoriesTable(t)
+		defer handler.Reset()
+		t.Setenv("MINESQL_CTE_MAX_RECURSION_DEPTH", "5")
+		stmt := parseStatementForTest(t, "WITH RECURSIVE reach (id) AS (SELECT dst FROM links WHERE src = 1 UNION ALL SELECT links.dst FROM reach JOIN links ON links.src = reach.id) SELECT id FROM reach;").(*ast.SelectStmt)
+
+		// WHEN
+		plan, err := PlanSelect(0, stmt)
+		require.NoError(t, err)
+		for {
+			var record executor.Record
+			record, err = plan.Exec.Next()
+			if err != nil || record == nil {
+				break
+			}
+		}
+
+		// THEN
+		assert.EqualError(t, err, "recursive query reach aborted after 6 iterations: try increasing MINESQL_CTE_MAX_RECURSION_DEPTH")
+	})
+
+	t.Run("再帰部分を外側の問い合わせで結合できる", func(t *testing.T) {
+		// GIVEN
+		setupCategoriesTable(t)
+		defer handler.Reset()
+		stmt := parseStatementForTest(t, "WITH RECURSIVE tree (id) AS (SELECT id FROM categories WHERE id = 3 UNION ALL SELECT categories.id FROM categories JOIN tree ON categories.parent_id = tree.id) SELECT categories.name FROM tree JOIN categories ON categories.id = tree.id ORDER BY categories.name;").(*ast.SelectStmt)
+
+		// WHEN
+		plan, err := PlanSelect(0, stmt)
+		require.NoError(t, err)
+		results := fetchAll(t, plan.Exec)
+
+		// THEN
+		assert.Equal(t, []string{"Apple", "Green Apple"}, columnStrings(results, 0))
+	})
+
+	t.Run("再帰 CTE の制約を満たさない場合はエラーになる", func(t *testing.T) {
+		tests := []struct {
+			name string
+			sql  string
+			err  string
+		}{
+			{
+				name: "UNION 以外で結合している",
+				sql:  "WITH RECURSIVE r (id) AS (SELECT id FROM categories EXCEPT SELECT id FROM r) SELECT id FROM r;",
+				err:  "recursive CTE r must combine SELECT statements with UNION, not EXCEPT",
+			},
+			{
+				name: "非再帰部分がない",
+				sql:  "WITH RECURSIVE r (id) AS (SELECT id FROM r UNION ALL SELECT id FROM r) SELECT id FROM r;",
+				err:  "recursive CTE r must have a non-recursive SELECT",
+			},
+			{
+				name: "再帰部分で集約している",
+				sql:  "WITH RECURSIVE r (id) AS (SELECT id FROM categories UNION ALL SELECT COUNT(*) FROM r) SELECT id FROM r;",
+				err:  "recursive SELECT of CTE r must not contain aggregation, window functions, DISTINCT, ORDER BY or LIMIT",
+			},
+			{
+				name: "再帰部分で自身を 2 回参照している",
+				sql:  "WITH RECURSIVE r (id) AS (SELECT id FROM categories UNION ALL SELECT r.id FROM r JOIN r ON r.id = r.id) SELECT id FROM r;",
+				err:  "recursive CTE r must be referenced only once in the recursive SELECT",
+			},
+			{
+				name: "サブクエリで自身を参照している",
+				sql:  "WITH RECURSIVE r (id) AS (SELECT id FROM categories UNION ALL SELECT id FROM categories WHERE id IN (SELECT id FROM r)) SELECT id FROM r;",
+				err:  "recursive CTE r must not be referenced in a subquery or derived table",
+			},
+			{
+				name: "自身が外部結合で NULL で補完される側になる",
+				sql:  "WITH RECURSIVE r (id) AS (SELECT id FROM categories UNION ALL SELECT categories.id FROM categories LEFT JOIN r ON categories.parent_id = r.id) SELECT id FROM r;",
+				err:  "recursive CTE r must not be the NULL-supplemented side of an outer join",
+			},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				// GIVEN
+				setupCategoriesTable(t)
+				defer handler.Reset()
+				stmt := parseStatementForTest(t, tt.sql).(*ast.SelectStmt)
+
+				// WHEN
+				_, err := PlanSelect(0, stmt)
+
+				// THEN
+				assert.EqualError(t, err, tt.err)
+			})
+		}
+	})
+}
+
+// setupCategoriesTable は再帰 CTE のテスト用に categories と links を作成してデータを挿入する
+//
+// categories (id INT PK, parent_id INT, name VARCHAR): Food(1) - Fruit(2) - {Apple(3) - Green Apple(5), Banana(4)}
+// links (src INT, dst INT, PK (src, dst)): 1 -> 2 -> 3 -> 1 の循環
+func setupCategoriesTable(t *testing.T) {
+	t.Helper()
+
+	initStorageManagerForTest(t)
+
+	for _, sql := range []string{
+		"CREATE TABLE categories (id INT, parent_id INT, name VARCHAR, PRIMARY KEY (id));",
+		"INSERT INTO categories (id, parent_id, name) VALUES (1, NULL, 'Food'), (2, 1, 'Fruit'), (3, 2, 'Apple'), (4, 2, 'Banana'), (5, 3, 'Green Apple');",
+		"CREATE TABLE links (src INT, dst INT, PRIMARY KEY (src, dst));",
+		"INSERT INTO links (src, dst) VALUES (1, 2), (2, 3), (3, 1);",
+	} {
+		executePlan(t, parseStatementForTest(t, sql))
+	}
+}
@@ -0,0 +1,217 @@
+package planner
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ren-yamanashi/minesql/internal/ast"
+	"github.com/ren-yamanashi/minesql/internal/executor"
+	"github.com/ren-yamanashi/minesql/internal/storage/dictionary"
+	"github.com/ren-yamanashi/minesql/internal/storage/handler"
+)
+
+// PlanCreateDatabase は CREATE DATABASE 文のバリデーションを行い、CreateDatabase executor を構築する
+func PlanCreateDatabase(stmt *ast.CreateDatabaseStmt) (executor.Executor, error) {
+	if err := dictionary.ValidateDatabaseName(stmt.DatabaseName); err != nil {
+		return nil, err
+	}
+
+	hdl := handler.Get()
+
+	// IF NOT EXISTS が指定されていない場合、同じ名前のデータベースが存在しないか検証
+	if _, ok := hdl.Catalog.GetDatabaseByName(stmt.DatabaseName); ok && !stmt.IfNotExists {
+		return nil, fmt.Errorf("database %s already exists", stmt.DatabaseName)
+	}
+
+	return executor.NewCreateDatabase(stmt.DatabaseName, stmt.IfNotExists), nil
+}
+
+// PlanDropDatabase は DROP DATABASE 文のバリデーションを行い、DropDatabase executor を構築する
+func PlanDropDatabase(trxId handler.TrxId, stmt *ast.DropDatabaseStmt) (executor.Executor, error) {
+	hdl := handler.Get()
+
+	// IF EXISTS が指定されていない場合、対象データベースがカタログに存在するか検証
+	if _, ok := hdl.Catalog.GetDatabaseByName(stmt.DatabaseName); !ok && !stmt.IfExists {
+		return nil, fmt.Errorf("database %s not found", stmt.DatabaseName)
+	}
+
+	return executor.NewDropDatabase(trxId, stmt.DatabaseName, stmt.IfExists), nil
+}
+
+// BindDatabase は文のテーブル名・ビュー名のうち、データベース名で修飾されていないものをデータベース名で修飾する
+//
+// database はセッションで選択中のデータベース (USE db) で、空の場合はテーブル名を修飾しない
+// ただし、データベース名で修飾したビューの問い合わせと、データベース名で修飾したテーブルの外部キーの参照先は、そのデータベースの名前で修飾する
+// 共通テーブル式への参照と導出テーブルの別名は修飾しない
+// カラムの修飾名 (`table.column` の table) は修飾しない (テーブルとの対応は refersToTable で判定する)
+func BindDatabase(stmt ast.Statement, database string) {
+	switch s := stmt.(type) {
+	case *ast.SelectStmt, *ast.SetOperationStmt:
+		bindQueryDatabase(s, database, nil)
+	case *ast.InsertStmt:
+		s.Table.TableName = dictionary.QualifyName(database, s.Table.TableName)
+		if s.Select != nil {
+			bindQueryDatabase(s.Select, database, nil)
+		}
+	case *ast.UpdateStmt:
+		tables := []*ast.TableId{&s.Table}
+		exprs := []ast.Expr{}
+		for _, join := range s.Joins {
+			tables = append(tables, &join.Table)
+			exprs = append(exprs, join.Condition)
+		}
+		for _, setClause := range s.SetClauses {
+			exprs = append(exprs, setClause.Value)
+		}
+		if s.Where != nil {
+			exprs = append(exprs, s.Where.Condition)
+		}
+		bindTablesDatabase(tables, exprs, database, nil)
+	case *ast.DeleteStmt:
+		if s.Target != "" {
+			s.Target = dictionary.QualifyName(database, s.Target)
+		}
+		tables := []*ast.TableId{&s.From}
+		exprs := []ast.Expr{}
+		for _, join := range s.Joins {
+			tables = append(tables, &join.Table)
+			exprs = append(exprs, join.Condition)
+		}
+		if s.Where != nil {
+			exprs = append(exprs, s.Where.Condition)
+		}
+		bindTablesDatabase(tables, exprs, database, nil)
+	case *ast.CreateTableStmt:
+		s.TableName = dictionary.QualifyName(database, s.TableName)
+		for _, def := range s.CreateDefinitions {
+			if fkDef, ok := def.(*ast.ConstraintForeignKeyDef); ok {
+				bindRefTableDatabase(fkDef, s.TableName)
+			}
+		}
+	case *ast.AlterTableStmt:
+		s.TableName = dictionary.QualifyName(database, s.TableName)
+		if action, ok := s.Action.(*ast.AddConstraintAction); ok {
+			if fkDef, ok := action.Constraint.(*ast.ConstraintForeignKeyDef); ok {
+				bindRefTableDatabase(fkDef, s.TableName)
+			}
+		}
+	case *ast.DropTableStmt:
+		s.TableName = dictionary.QualifyName(database, s.TableName)
+	case *ast.TruncateTableStmt:
+		s.TableName = dictionary.QualifyName(database, s.TableName)
+	case *ast.CreateIndexStmt:
+		s.TableName = dictionary.QualifyName(database, s.TableName)
+	case *ast.DropIndexStmt:
+		s.TableName = dictionary.QualifyName(database, s.TableName)
+	case *ast.CreateViewStmt:
+		s.ViewName = dictionary.QualifyName(database, s.ViewName)
+		// ビューの問い合わせは、ビューが属するデータベースのテーブルを参照する
+		viewDatabase, _ := dictionary.SplitName(s.ViewName)
+		BindDatabase(s.Query, viewDatabase)
+	case *ast.DropViewStmt:
+		s.ViewName = dictionary.QualifyName(database, s.ViewName)
+	case *ast.ExplainStmt:
+		BindDatabase(s.Stmt, database)
+	}
+}
+
+// bindRefTableDatabase は外部キーの参照先テーブルを、子テーブルと同じデータベースの名前で修飾する
+func bindRefTableDatabase(fkDef *ast.ConstraintForeignKeyDef, tableName string) {
+	database, _ := dictionary.SplitName(tableName)
+	fkDef.RefTable = dictionary.QualifyName(database, fkDef.RefTable)
+}
+
+// bindQueryDatabase は問い合わせ (入れ子の集合演算・導出テーブル・サブクエリ・WITH 句の共通テーブル式を含む) のテーブル名をデータベース名で修飾する
+//
+// ctes は参照できる共通テーブル式の名前で、同じ名前のテーブルは共通テーブル式への参照として修飾しない
+func bindQueryDatabase(stmt ast.Statement, database string, ctes map[string]bool) {
+	if with := withClause(stmt); with != nil {
+		scope := make(map[string]bool, len(ctes)+len(with.CTEs))
+		for name := range ctes {
+			scope[name] = true
+		}
+		for _, cte := range with.CTEs {
+			scope[cte.Name] = true
+		}
+		for _, cte := range with.CTEs {
+			bindQueryDatabase(cte.Query, database, scope)
+		}
+		ctes = scope
+	}
+
+	switch s := stmt.(type) {
+	case *ast.SetOperationStmt:
+		bindQueryDatabase(s.Left, database, ctes)
+		bindQueryDatabase(s.Right, database, ctes)
+	case *ast.SelectStmt:
+		tables := []*ast.TableId{&s.From}
+		exprs := s.SelectItems()
+		for _, join := range s.Joins {
+			tables = append(tables, &join.Table)
+			exprs = append(exprs, join.Condition)
+		}
+		if s.Where != nil {
+			exprs = append(exprs, s.Where.Condition)
+		}
+		if s.Having != nil {
+			exprs = append(exprs, s.Having.Condition)
+		}
+		bindTablesDatabase(tables, exprs, database, ctes)
+	}
+}
+
+// bindTablesDatabase は FROM 句・JOIN 句のテーブルと、式に含まれるサブクエリのテーブル名をデータベース名で修飾する
+func bindTablesDatabase(tables []*ast.TableId, exprs []ast.Expr, database string, ctes map[string]bool) {
+	for _, table := range tables {
+		switch {
+		case table.Subquery != nil:
+			bindQueryDatabase(table.Subquery, database, ctes)
+		case table.CTE == nil && table.TableName != "" && !ctes[table.TableName]:
+			table.TableName = dictionary.QualifyName(database, table.TableName)
+		}
+	}
+
+	for _, expr := range exprs {
+		if expr == nil {
+			continue
+		}
+		ast.WalkExpr(expr, func(e ast.Expr) bool {
+			switch v := e.(type) {
+			case *ast.SubqueryExpr:
+				bindQueryDatabase(v.Select, database, ctes)
+			case *ast.ExistsExpr:
+				bindQueryDatabase(v.Subquery, database, ctes)
+			case *ast.InExpr:
+				if v.Subquery != nil {
+					bindQueryDatabase(v.Subquery, database, ctes)
+				}
+			}
+			return true
+		})
+	}
+}
+
+// refersToTable はカラムの修飾名 (`table.column` の table) がテーブルを指すかどうかを判定する
+//
+// データベース名で修飾したテーブル (`db.table`) は、データベース名を省略した修飾名 (`table.column`) でも参照できる
+func refersToTable(qualifier, tableName string) bool {
+	if qualifier == tableName {
+		return true
+	}
+	return !strings.Contains(qualifier, ".") && strings.HasSuffix(tableName, "."+qualifier)
+}
+
+// validateUniqueTables は FROM 句・JOIN 句のテーブルが、データベース名を省略した名前で一意に区別できるか検証する
+//
+// カラムの修飾名はデータベース名を省略した名前でテーブルを参照できるため、MySQL と同様に同じ名前のテーブルを複数指定することはできない
+func validateUniqueTables(tables []ast.TableId) error {
+	seen := make(map[string]struct{}, len(tables))
+	for _, table := range tables {
+		_, name := dictionary.SplitName(table.TableName)
+		if _, ok := seen[name]; ok {
+			return fmt.Errorf("Not unique table/alias: '%s'", name)
+		}
+		seen[name] = struct{}{}
+	}
+	return nil
+}
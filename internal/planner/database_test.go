@@ -0,0 +1,293 @@
+package planner
+
+import (
+	"testing"
+
+	"github.com/ren-yamanashi/minesql/internal/ast"
+	"github.com/ren-yamanashi/minesql/internal/executor"
+	"github.com/ren-yamanashi/minesql/internal/storage/handler"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlanCreateDatabase(t *testing.T) {
+	t.Run("データベースを作成できる", func(t *testing.T) {
+		// GIVEN
+		initStorageManagerForTest(t)
+		defer handler.Reset()
+
+		// WHEN
+		executePlan(t, parseStatementForTest(t, "CREATE DATABASE app;"))
+
+		// THEN
+		_, ok := handler.Get().Catalog.GetDatabaseByName("app")
+		assert.True(t, ok)
+	})
+
+	t.Run("既に存在するデータベースの作成はエラーになる", func(t *testing.T) {
+		// GIVEN
+		initStorageManagerForTest(t)
+		defer handler.Reset()
+		executePlan(t, parseStatementForTest(t, "CREATE DATABASE app;"))
+
+		// WHEN
+		_, err := Start(0, parseStatementForTest(t, "CREATE DATABASE app;"))
+
+		// THEN
+		assert.EqualError(t, err, "database app already exists")
+	})
+
+	t.Run("IF NOT EXISTS の場合は既に存在するデータベースの作成でもエラーにならない", func(t *testing.T) {
+		// GIVEN
+		initStorageManagerForTest(t)
+		defer handler.Reset()
+		executePlan(t, parseStatementForTest(t, "CREATE DATABASE app;"))
+
+		// WHEN
+		_, err := Start(0, parseStatementForTest(t, "CREATE DATABASE IF NOT EXISTS app;"))
+
+		// THEN
+		assert.NoError(t, err)
+	})
+
+	t.Run("データベース名に '.' を含む場合はエラーになる", func(t *testing.T) {
+		// GIVEN
+		initStorageManagerForTest(t)
+		defer handler.Reset()
+
+		// WHEN
+		_, err := Start(0, parseStatementForTest(t, "CREATE DATABASE app.v1;"))
+
+		// THEN
+		assert.EqualError(t, err, "invalid database name app.v1")
+	})
+}
+
+func TestPlanDropDatabase(t *testing.T) {
+	t.Run("データベースとデータベースに属するテーブルを削除できる", func(t *testing.T) {
+		// GIVEN
+		initStorageManagerForTest(t)
+		defer handler.Reset()
+		executePlan(t, parseStatementForTest(t, "CREATE DATABASE app;"))
+		executeInDatabase(t, "app", "CREATE TABLE users (id INT, PRIMARY KEY (id));")
+
+		// WHEN
+		executePlan(t, parseStatementForTest(t, "DROP DATABASE app;"))
+
+		// THEN
+		_, ok := handler.Get().Catalog.GetDatabaseByName("app")
+		assert.False(t, ok)
+		_, ok = handler.Get().Catalog.GetTableMetaByName("app.users")
+		assert.False(t, ok)
+	})
+
+	t.Run("存在しないデータベースの削除はエラーになる", func(t *testing.T) {
+		// GIVEN
+		initStorageManagerForTest(t)
+		defer handler.Reset()
+
+		// WHEN
+		_, err := Start(0, parseStatementForTest(t, "DROP DATABASE app;"))
+
+		// THEN
+		assert.EqualError(t, err, "database app not found")
+	})
+
+	t.Run("IF EXISTS の場合は存在しないデータベースの削除でもエラーにならない", func(t *testing.T) {
+		// GIVEN
+		initStorageManagerForTest(t)
+		defer handler.Reset()
+
+		// WHEN
+		_, err := Start(0, parseStatementForTest(t, "DROP DATABASE IF EXISTS app;"))
+
+		// THEN
+		assert.NoError(t, err)
+	})
+}
+
+func TestBindDatabase(t *testing.T) {
+	t.Run("FROM 句・JOIN 句・サブクエリのテーブル名を修飾する", func(t *testing.T) {
+		// GIVEN
+		stmt := parseStatementForTest(t, "SELECT * FROM users JOIN other.orders ON users.id = orders.user_id WHERE users.id IN (SELECT user_id FROM items);").(*ast.SelectStmt)
+
+		// WHEN
+		BindDatabase(stmt, "app")
+
+		// THEN
+		assert.Equal(t, "app.users", stmt.From.TableName)
+		assert.Equal(t, "other.orders", stmt.Joins[0].Table.TableName)
+		in := stmt.Where.Condition.(*ast.InExpr)
+		assert.Equal(t, "app.items", in.Subquery.From.TableName)
+	})
+
+	t.Run("共通テーブル式への参照と導出テーブルの別名は修飾しない", func(t *testing.T) {
+		// GIVEN
+		stmt := parseStatementForTest(t, "WITH c AS (SELECT id FROM users) SELECT * FROM c JOIN (SELECT id FROM items) AS d ON c.id = d.id;").(*ast.SelectStmt)
+
+		// WHEN
+		BindDatabase(stmt, "app")
+
+		// THEN
+		assert.Equal(t, "c", stmt.From.TableName)
+		assert.Equal(t, "d", stmt.Joins[0].Table.TableName)
+		assert.Equal(t, "app.items", stmt.Joins[0].Table.Subquery.From.TableName)
+		assert.Equal(t, "app.users", stmt.With.CTEs[0].Query.(*ast.SelectStmt).From.TableName)
+	})
+
+	t.Run("DDL のテーブル名・外部キーの参照先・ビューの問い合わせを修飾する", func(t *testing.T) {
+		// GIVEN
+		createTable := parseStatementForTest(t, "CREATE TABLE orders (id INT, user_id INT, PRIMARY KEY (id), KEY user_idx (user_id), FOREIGN KEY fk_user (user_id) REFERENCES users (id));").(*ast.CreateTableStmt)
+		createView := parseStatementForTest(t, "CREATE VIEW other.v AS SELECT id FROM users;").(*ast.CreateViewStmt)
+		drop := parseStatementForTest(t, "DROP TABLE users;").(*ast.DropTableStmt)
+
+		// WHEN
+		BindDatabase(createTable, "app")
+		BindDatabase(createView, "app")
+		BindDatabase(drop, "app")
+
+		// THEN
+		assert.Equal(t, "app.orders", createTable.TableName)
+		assert.Equal(t, "app.users", createTable.CreateDefinitions[4].(*ast.ConstraintForeignKeyDef).RefTable)
+		assert.Equal(t, "other.v", createView.ViewName)
+		assert.Equal(t, "other.users", createView.Query.(*ast.SelectStmt).From.TableName)
+		assert.Equal(t, "app.users", drop.TableName)
+	})
+
+	t.Run("データベースを選択していない場合はテーブル名を修飾しない", func(t *testing.T) {
+		// GIVEN
+		stmt := parseStatementForTest(t, "SELECT * FROM users;").(*ast.SelectStmt)
+
+		// WHEN
+		BindDatabase(stmt, "")
+
+		// THEN
+		assert.Equal(t, "users", stmt.From.TableName)
+	})
+}
+
+func TestDatabaseQualifiedTables(t *testing.T) {
+	t.Run("データベースごとに同じ名前のテーブルを作成し、修飾名・非修飾名で参照できる", func(t *testing.T) {
+		// GIVEN
+		initStorageManagerForTest(t)
+		defer handler.Reset()
+		executePlan(t, parseStatementForTest(t, "CREATE DATABASE app;"))
+		executePlan(t, parseStatementForTest(t, "CREATE DATABASE other;"))
+		for _, db := range []string{"app", "other"} {
+			executeInDatabase(t, db, "CREATE TABLE users (id INT, name VARCHAR, PRIMARY KEY (id));")
+		}
+		executeInDatabase(t, "app", "INSERT INTO users (id, name) VALUES (1, 'a'), (2, 'b');")
+		executeInDatabase(t, "other", "INSERT INTO users (id, name) VALUES (3, 'c');")
+		tests := []struct {
+			name     string
+			database string
+			sql      string
+			ids      []int64
+		}{
+			{"非修飾名", "app", "SELECT id FROM users ORDER BY id;", []int64{1, 2}},
+			{"別のデータベースのテーブル", "app", "SELECT id FROM other.users;", []int64{3}},
+			{"データベースを選択していない場合の修飾名", "", "SELECT id FROM app.users WHERE app.users.id = 2;", []int64{2}},
+			{"データベース名を省略したカラムの修飾名", "app", "SELECT users.id FROM users WHERE users.name = 'a';", []int64{1}},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				// WHEN
+				results := executeInDatabase(t, tt.database, tt.sql)
+
+				// THEN
+				assert.Equal(t, tt.ids, int64Column(results, 0))
+			})
+		}
+	})
+
+	t.Run("データベースのテーブル同士を結合し、更新・削除できる", func(t *testing.T) {
+		// GIVEN
+		initStorageManagerForTest(t)
+		defer handler.Reset()
+		executePlan(t, parseStatementForTest(t, "CREATE DATABASE app;"))
+		executeInDatabase(t, "app", "CREATE TABLE users (id INT, name VARCHAR, PRIMARY KEY (id));")
+		executeInDatabase(t, "app", "CREATE TABLE orders (id INT, user_id INT, PRIMARY KEY (id), KEY user_idx (user_id), FOREIGN KEY fk_user (user_id) REFERENCES users (id));")
+		executeInDatabase(t, "app", "INSERT INTO users (id, name) VALUES (1, 'a'), (2, 'b');")
+		executeInDatabase(t, "app", "INSERT INTO orders (id, user_id) VALUES (10, 1), (11, 1), (12, 2);")
+
+		// WHEN
+		executeInDatabase(t, "app", "UPDATE users SET users.name = 'z' WHERE id = 2;")
+		executeInDatabase(t, "app", "DELETE orders FROM orders JOIN users ON orders.user_id = users.id WHERE users.name = 'z';")
+		results := executeInDatabase(t, "app", "SELECT orders.id FROM users JOIN orders ON users.id = orders.user_id ORDER BY orders.id;")
+
+		// THEN
+		assert.Equal(t, []int64{10, 11}, int64Column(results, 0))
+	})
+
+	t.Run("ビューの問い合わせはビューが属するデータベースのテーブルを参照する", func(t *testing.T) {
+		// GIVEN
+		initStorageManagerForTest(t)
+		defer handler.Reset()
+		executePlan(t, parseStatementForTest(t, "CREATE DATABASE app;"))
+		executePlan(t, parseStatementForTest(t, "CREATE TABLE users (id INT, PRIMARY KEY (id));"))
+		executeInDatabase(t, "app", "CREATE TABLE users (id INT, PRIMARY KEY (id));")
+		executePlan(t, parseStatementForTest(t, "INSERT INTO users (id) VALUES (1);"))
+		executeInDatabase(t, "app", "INSERT INTO users (id) VALUES (2);")
+		executeInDatabase(t, "app", "CREATE VIEW v AS SELECT id FROM users;")
+
+		// WHEN
+		results := executeInDatabase(t, "", "SELECT v.id FROM app.v;")
+
+		// THEN
+		assert.Equal(t, []int64{2}, int64Column(results, 0))
+	})
+
+	t.Run("データベース名を省略した名前が同じテーブルは結合できない", func(t *testing.T) {
+		// GIVEN
+		initStorageManagerForTest(t)
+		defer handler.Reset()
+		executePlan(t, parseStatementForTest(t, "CREATE DATABASE app;"))
+		executePlan(t, parseStatementForTest(t, "CREATE DATABASE other;"))
+		for _, db := range []string{"app", "other"} {
+			executeInDatabase(t, db, "CREATE TABLE users (id INT, PRIMARY KEY (id));")
+		}
+		tests := []struct {
+			name string
+			sql  string
+		}{
+			{"修飾名と非修飾名で同じテーブルを指定する", "SELECT * FROM app.users JOIN users ON app.users.id = users.id;"},
+			{"別のデータベースの同じ名前のテーブルを指定する", "SELECT * FROM users JOIN other.users ON users.id = other.users.id;"},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				stmt := parseStatementForTest(t, tt.sql)
+				BindDatabase(stmt, "app")
+
+				// WHEN
+				_, err := Start(0, stmt)
+
+				// THEN
+				assert.EqualError(t, err, "Not unique table/alias: 'users'")
+			})
+		}
+	})
+
+	t.Run("存在しないデータベースにはテーブルを作成できない", func(t *testing.T) {
+		// GIVEN
+		initStorageManagerForTest(t)
+		defer handler.Reset()
+		stmt := parseStatementForTest(t, "CREATE TABLE users (id INT, PRIMARY KEY (id));")
+		BindDatabase(stmt, "app")
+
+		// WHEN
+		plan, err := Start(0, stmt)
+		require.NoError(t, err)
+		_, err = plan.Exec.Next()
+
+		// THEN
+		assert.EqualError(t, err, "database app not found")
+	})
+}
+
+// executeInDatabase は SQL をパースし、テーブル名をデータベース名で修飾してから実行する
+func executeInDatabase(t *testing.T, database, sql string) []executor.Record {
+	t.Helper()
+	stmt := parseStatementForTest(t, sql)
+	BindDatabase(stmt, database)
+	return executePlan(t, stmt)
+}
@@ -0,0 +1,109 @@
+package planner
+
+import (
+	"fmt"
+
+	"github.com/ren-yamanashi/minesql/internal/ast"
+	"github.com/ren-yamanashi/minesql/internal/executor"
+	"github.com/ren-yamanashi/minesql/internal/storage/handler"
+)
+
+// planSelectDistinct は SELECT DISTINCT を計画する
+//
+// 重複は SELECT リストの値 (Project 後のレコード) で判定するため、ORDER BY・LIMIT は重複を除いた後の結果セットに適用する
+// (ORDER BY には結果セットのカラムのみ指定できる)
+//
+// 結果セットが全カラムの順に並ぶ場合 (SELECT のカラムを先頭に持つインデックスや PK の順に走査する場合) は、
+// 同じ行が連続して並ぶため StreamDistinct で除き、それ以外は HashDistinct で除く
+func planSelectDistinct(trxId handler.TrxId, stmt *ast.SelectStmt) (*PlanResult, error) {
+	inner := *stmt
+	inner.OrderBy = nil
+	inner.Limit = nil
+	result, err := planSelectBody(trxId, &inner)
+	if err != nil {
+		return nil, err
+	}
+
+	positions := make([]int, len(result.Columns))
+	for i := range positions {
+		positions[i] = i
+	}
+	if isDistinctOrder(result.sortOrder, positions) {
+		result.Exec = executor.NewStreamDistinct(result.Exec)
+	} else {
+		result.Exec = executor.NewHashDistinct(result.Exec)
+		result.sortOrder = nil
+	}
+	return planResultOrderBy(result, stmt.OrderBy, stmt.Limit)
+}
+
+// planResultOrderBy は結果セットに対する ORDER BY・LIMIT を計画する (SELECT DISTINCT・集合演算)
+//
+// ORDER BY のカラムは結果セットのカラム名 (別名を含む) で解決する
+func planResultOrderBy(result *PlanResult, orderBy []ast.OrderByItem, limit *ast.LimitClause) (*PlanResult, error) {
+	keys := make([]executor.SortKey, len(orderBy))
+	for i, item := range orderBy {
+		pos, err := findResultColumnPos(result.Columns, item.Column)
+		if err != nil {
+			return nil, err
+		}
+		keys[i] = executor.SortKey{Pos: pos, Desc: item.Desc}
+	}
+
+	exec := planOrderBy(result.Exec, keys, result.sortOrder)
+	exec = planLimit(exec, limit)
+	return &PlanResult{Exec: exec, Columns: result.Columns}, nil
+}
+
+// findResultColumnPos は結果セットのカラムの位置を返す
+//
+// 同じ名前のカラムが複数ある場合は最初のカラムを返す
+func findResultColumnPos(columns []ColumnMeta, col ast.ColumnId) (int, error) {
+	for i, c := range columns {
+		if c.ColName == col.ColName && (col.TableName == "" || refersToTable(col.TableName, c.TableName)) {
+			return i, nil
+		}
+	}
+	return -1, fmt.Errorf("ORDER BY column %s is not in the result columns", ast.ExprString(col))
+}
+
+// isDistinctOrder は sortOrder の順に並んだレコードで、positions のカラムの値が同じ行が連続して並ぶかを判定する
+//
+// sortOrder の先頭から positions のカラムだけが (順不同で) すべて現れる場合に true を返す
+func isDistinctOrder(sortOrder []int, positions []int) bool {
+	remaining := make(map[int]bool, len(positions))
+	for _, pos := range positions {
+		remaining[pos] = true
+	}
+	for _, pos := range sortOrder {
+		if len(remaining) == 0 {
+			return true
+		}
+		if !remaining[pos] {
+			return false
+		}
+		delete(remaining, pos)
+	}
+	return len(remaining) == 0
+}
+
+// projectSortOrder は Project 前のレコードの並び順 (カラムの位置) を、Project 後のレコードの位置に変換する
+//
+// Project 後のレコードにないカラムが現れた時点で打ち切る (それ以降のカラムの順序は保証されない)
+// 同じカラムを複数の位置に射影する場合は、そのすべての位置を並び順に加える
+func projectSortOrder(sortOrder []int, colPos []uint16) []int {
+	var order []int
+	for _, pos := range sortOrder {
+		found := false
+		for i, p := range colPos {
+			if int(p) == pos {
+				order = append(order, i)
+				found = true
+			}
+		}
+		if !found {
+			break
+		}
+	}
+	return order
+}
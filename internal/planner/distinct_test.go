@@ -0,0 +1,154 @@
+package planner
+
+import (
+	"testing"
+
+	"github.com/ren-yamanashi/minesql/internal/ast"
+	"github.com/ren-yamanashi/minesql/internal/executor"
+	"github.com/ren-yamanashi/minesql/internal/storage/handler"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlanSelectDistinct(t *testing.T) {
+	t.Run("SELECT のカラムを先頭に持つインデックスの順に読み取れる場合は StreamDistinct で重複を除く", func(t *testing.T) {
+		// GIVEN
+		setupItemsTable(t)
+		defer handler.Reset()
+		stmt := parseStatementForTest(t, "SELECT DISTINCT category FROM items;").(*ast.SelectStmt)
+
+		// WHEN
+		plan, err := PlanSelect(0, stmt)
+		require.NoError(t, err)
+		results := fetchAll(t, plan.Exec)
+
+		// THEN
+		assert.IsType(t, &executor.StreamDistinct{}, plan.Exec)
+		assert.Equal(t, []string{"a", "b", "c"}, columnStrings(results, 0))
+		assert.Equal(t, "category", plan.Columns[0].ColName)
+	})
+
+	t.Run("結果セットが全カラムの順に並ばない場合は HashDistinct で重複を除く", func(t *testing.T) {
+		// GIVEN
+		setupUsersTable(t)
+		defer handler.Reset()
+		stmt := parseStatementForTest(t, "SELECT DISTINCT first_name FROM users;").(*ast.SelectStmt)
+
+		// WHEN
+		plan, err := PlanSelect(0, stmt)
+		require.NoError(t, err)
+		results := fetchAll(t, plan.Exec)
+
+		// THEN
+		assert.IsType(t, &executor.HashSetOperation{}, plan.Exec)
+		assert.ElementsMatch(t, []string{"John", "Jane", "Jonathan", "Tom"}, columnStrings(results, 0))
+	})
+
+	t.Run("複数カラムの組み合わせで重複を判定する", func(t *testing.T) {
+		// GIVEN
+		setupUsersTable(t)
+		defer handler.Reset()
+		stmt := parseStatementForTest(t, "SELECT DISTINCT first_name, gender FROM users ORDER BY first_name;").(*ast.SelectStmt)
+
+		// WHEN
+		plan, err := PlanSelect(0, stmt)
+		require.NoError(t, err)
+		results := fetchAll(t, plan.Exec)
+
+		// THEN
+		assert.Equal(t, []string{"Jane", "John", "Jonathan", "Tom"}, columnStrings(results, 0))
+	})
+
+	t.Run("ORDER BY・LIMIT は重複を除いた後の結果セットに適用する", func(t *testing.T) {
+		// GIVEN
+		setupUsersTable(t)
+		defer handler.Reset()
+		stmt := parseStatementForTest(t, "SELECT DISTINCT last_name FROM users ORDER BY last_name DESC LIMIT 3;").(*ast.SelectStmt)
+
+		// WHEN
+		plan, err := PlanSelect(0, stmt)
+		require.NoError(t, err)
+		results := fetchAll(t, plan.Exec)
+
+		// THEN
+		assert.Equal(t, []string{"Doe3", "Doe2", "Doe"}, columnStrings(results, 0))
+	})
+
+	t.Run("インデックスの順に並ぶ結果セットの ORDER BY には Sort を使わない", func(t *testing.T) {
+		// GIVEN
+		setupItemsTable(t)
+		defer handler.Reset()
+		stmt := parseStatementForTest(t, "SELECT DISTINCT category FROM items ORDER BY category;").(*ast.SelectStmt)
+
+		// WHEN
+		plan, err := PlanSelect(0, stmt)
+		require.NoError(t, err)
+		results := fetchAll(t, plan.Exec)
+
+		// THEN
+		assert.IsType(t, &executor.StreamDistinct{}, plan.Exec)
+		assert.Equal(t, []string{"a", "b", "c"}, columnStrings(results, 0))
+	})
+
+	t.Run("ORDER BY に結果セットにないカラムを指定した場合はエラーを返す", func(t *testing.T) {
+		// GIVEN
+		setupUsersTable(t)
+		defer handler.Reset()
+		stmt := parseStatementForTest(t, "SELECT DISTINCT first_name FROM users ORDER BY last_name;").(*ast.SelectStmt)
+
+		// WHEN
+		_, err := PlanSelect(0, stmt)
+
+		// THEN
+		assert.EqualError(t, err, "ORDER BY column last_name is not in the result columns")
+	})
+}
+
+func TestIsDistinctOrder(t *testing.T) {
+	tests := []struct {
+		name      string
+		sortOrder []int
+		positions []int
+		expected  bool
+	}{
+		{"並び順の先頭に全カラムが現れる場合", []int{1, 0, 2}, []int{0, 1}, true},
+		{"並び順と全カラムが一致する場合", []int{0, 1}, []int{0, 1}, true},
+		{"並び順の先頭に他のカラムが現れる場合", []int{2, 0, 1}, []int{0, 1}, false},
+		{"並び順に含まれないカラムがある場合", []int{0}, []int{0, 1}, false},
+		{"並び順がない場合", nil, []int{0}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// WHEN
+			result := isDistinctOrder(tt.sortOrder, tt.positions)
+
+			// THEN
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestProjectSortOrder(t *testing.T) {
+	tests := []struct {
+		name      string
+		sortOrder []int
+		colPos    []uint16
+		expected  []int
+	}{
+		{"射影後の位置に変換する", []int{2, 0}, []uint16{0, 2}, []int{1, 0}},
+		{"射影後のレコードにないカラムで打ち切る", []int{2, 1, 0}, []uint16{0, 2}, []int{1}},
+		{"同じカラムを複数の位置に射影する場合はすべての位置を加える", []int{0}, []uint16{0, 0}, []int{0, 1}},
+		{"先頭のカラムが射影後のレコードにない場合", []int{1}, []uint16{0}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// WHEN
+			result := projectSortOrder(tt.sortOrder, tt.colPos)
+
+			// THEN
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
@@ -43,7 +43,7 @@ func setupPlannerExample() func() {
 			&ast.ConstraintPrimaryKeyDef{Columns: []ast.ColumnId{
 				*ast.NewColumnId("id"),
 			}},
-			&ast.ConstraintUniqueKeyDef{Column: *ast.NewColumnId("username")},
+			&ast.ConstraintUniqueKeyDef{Columns: []ast.ColumnId{*ast.NewColumnId("username")}},
 		},
 	})
 
@@ -253,7 +253,7 @@ func Example_join() {
 			&ast.ColumnDef{ColName: "user_id", DataType: ast.DataTypeVarchar},
 			&ast.ColumnDef{ColName: "item", DataType: ast.DataTypeVarchar},
 			&ast.ConstraintPrimaryKeyDef{Columns: []ast.ColumnId{*ast.NewColumnId("id")}},
-			&ast.ConstraintUniqueKeyDef{KeyName: "idx_user_id", Column: *ast.NewColumnId("user_id")},
+			&ast.ConstraintUniqueKeyDef{KeyName: "idx_user_id", Columns: []ast.ColumnId{*ast.NewColumnId("user_id")}},
 		},
 	})
 	runPlan(&ast.InsertStmt{
@@ -298,7 +298,7 @@ func Example_nonUniqueIndex() {
 			&ast.ColumnDef{ColName: "name", DataType: ast.DataTypeVarchar},
 			&ast.ColumnDef{ColName: "category", DataType: ast.DataTypeVarchar},
 			&ast.ConstraintPrimaryKeyDef{Columns: []ast.ColumnId{*ast.NewColumnId("id")}},
-			&ast.ConstraintKeyDef{KeyName: "idx_category", Column: *ast.NewColumnId("category")},
+			&ast.ConstraintKeyDef{KeyName: "idx_category", Columns: []ast.ColumnId{*ast.NewColumnId("category")}},
 		},
 	})
 	runPlan(&ast.InsertStmt{
@@ -0,0 +1,1015 @@
+package planner
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/ren-yamanashi/minesql/internal/ast"
+	"github.com/ren-yamanashi/minesql/internal/executor"
+	"github.com/ren-yamanashi/minesql/internal/storage/dictionary"
+	"github.com/ren-yamanashi/minesql/internal/storage/handler"
+)
+
+// 除算の結果に追加する小数部の桁数 (AVG と同じく MySQL の div_precision_increment のデフォルト値)
+const divScaleIncrement = executor.AvgScaleIncrement
+
+// exprScope は式中のカラム・集約関数を、評価対象のレコード内の位置に解決する
+type exprScope struct {
+	columns   []joinedColumn                            // 評価対象のレコードのカラム
+	column    func(col ast.ColumnId) (int, error)       // カラムの位置を返す
+	aggregate func(agg *ast.AggregateExpr) (int, error) // 集約関数の結果の位置を返す (nil の場合は集約関数を使用できない)
+	window    func(w *ast.WindowFuncExpr) (int, error)  // ウィンドウ関数の結果の位置を返す (nil の場合はウィンドウ関数を使用できない)
+	subquery  *subqueryPlanner                          // サブクエリを計画する (nil の場合はサブクエリを使用できない)
+	inserting *handler.TableMetadata                    // ON DUPLICATE KEY UPDATE で VALUES(col) が参照する、追加しようとした行のテーブル (nil の場合は VALUES() を使用できない)
+}
+
+// newExprScope は修飾名 / 非修飾名のカラムを columns から探すスコープを作成する
+func newExprScope(columns []joinedColumn) *exprScope {
+	return &exprScope{
+		columns: columns,
+		column: func(col ast.ColumnId) (int, error) {
+			return findColumnPos(columns, col.TableName, col.ColName)
+		},
+	}
+}
+
+// newTableExprScope は単一テーブルのレコードに対するスコープを作成する (カラム名のみで解決する)
+func newTableExprScope(tblMeta *handler.TableMetadata) *exprScope {
+	return &exprScope{
+		columns: resolveJoinedColumns([]*handler.TableMetadata{tblMeta}),
+		column: func(col ast.ColumnId) (int, error) {
+			colMeta, ok := tblMeta.GetColByName(col.ColName)
+			if !ok {
+				return -1, errors.New("column " + col.ColName + " does not exist in table " + tblMeta.Name)
+			}
+			return int(colMeta.Pos), nil
+		},
+	}
+}
+
+// newJoinTargetExprScope は更新対象の行の後ろに結合レコードを連結したレコードに対するスコープを作成する (複数テーブルの UPDATE の SET 句)
+//
+// カラムは結合レコードのカラムとして解決する (更新対象のテーブルのカラムは更新前の値を参照する)
+func newJoinTargetExprScope(tblMeta *handler.TableMetadata, joined []joinedColumn) *exprScope {
+	columns := resolveJoinedColumns([]*handler.TableMetadata{tblMeta})
+	offset := len(columns)
+	for _, col := range joined {
+		col.pos += offset
+		columns = append(columns, col)
+	}
+	return &exprScope{
+		columns: columns,
+		column: func(col ast.ColumnId) (int, error) {
+			pos, err := findColumnPos(joined, col.TableName, col.ColName)
+			if err != nil {
+				return -1, err
+			}
+			return offset + pos, nil
+		},
+	}
+}
+
+// typedExpr はコンパイルした式と、その結果のデータ型
+//
+// リテラルは比較相手などの文脈に応じてデータ型が決まるため、データ型が決まるまで lit に保持する (expr, meta は nil)
+// NULL リテラルはデータ型を持たない (meta は nil)
+type typedExpr struct {
+	expr executor.Expression
+	meta *handler.ColumnMetadata // 結果のデータ型 (Type, Precision, Scale)
+	lit  ast.Literal             // データ型が未確定のリテラル
+}
+
+// isNull は NULL リテラルかどうかを返す
+func (te typedExpr) isNull() bool {
+	if te.lit != nil {
+		_, ok := te.lit.(*ast.NullLiteral)
+		return ok
+	}
+	if c, ok := te.expr.(*executor.Constant); ok {
+		return c.Value == nil && te.meta == nil
+	}
+	return false
+}
+
+// isConstant はレコードに依存しない値 (定数) かどうかを返す
+func (te typedExpr) isConstant() bool {
+	if te.lit != nil {
+		return true
+	}
+	_, ok := te.expr.(*executor.Constant)
+	return ok
+}
+
+// compileCondition は条件式 (WHERE / HAVING など) をコンパイルする
+//
+// 条件式の結果は数値 (比較演算・論理演算の結果を含む) か NULL である必要がある
+func compileCondition(expr ast.Expr, scope *exprScope) (executor.Expression, error) {
+	te, err := compileBoolean(expr, scope)
+	if err != nil {
+		return nil, err
+	}
+	return te.expr, nil
+}
+
+// compileValue は値を返す式 (SELECT リストなど) をコンパイルし、結果のデータ型を確定する
+func compileValue(expr ast.Expr, scope *exprScope) (typedExpr, error) {
+	te, err := compileExpr(expr, scope)
+	if err != nil {
+		return typedExpr{}, err
+	}
+	return resolveLiteral(te, nil)
+}
+
+// compileAssignment は値を代入する式 (UPDATE の SET 句) をコンパイルし、代入先のカラムのデータ型に変換する
+func compileAssignment(expr ast.Expr, scope *exprScope, colMeta *handler.ColumnMetadata) (executor.Expression, error) {
+	te, err := compileExpr(expr, scope)
+	if err != nil {
+		return nil, err
+	}
+	te, err = castTo(te, colMeta)
+	if err != nil {
+		return nil, err
+	}
+	return te.expr, nil
+}
+
+// compileBoolean は条件として評価する式をコンパイルする
+func compileBoolean(expr ast.Expr, scope *exprScope) (typedExpr, error) {
+	te, err := compileValue(expr, scope)
+	if err != nil {
+		return typedExpr{}, err
+	}
+	if te.meta != nil && !isNumericType(te.meta.Type) {
+		return typedExpr{}, fmt.Errorf("expression %s cannot be used as a condition", ast.ExprString(expr))
+	}
+	return te, nil
+}
+
+// compileExpr は式を再帰的にコンパイルする
+//
+// すべての被演算子が定数の場合は、コンパイル時に評価して定数に置き換える (定数畳み込み)
+func compileExpr(expr ast.Expr, scope *exprScope) (typedExpr, error) {
+	switch e := expr.(type) {
+	case ast.ColumnId:
+		pos, err := scope.column(e)
+		if err != nil {
+			return typedExpr{}, err
+		}
+		return typedExpr{expr: executor.NewColumnRef(pos), meta: scope.columns[pos].colMeta}, nil
+	case ast.Literal:
+		return typedExpr{lit: e}, nil
+	case *ast.AggregateExpr:
+		if scope.aggregate == nil {
+			return typedExpr{}, fmt.Errorf("invalid use of aggregate function %s", e.String())
+		}
+		pos, err := scope.aggregate(e)
+		if err != nil {
+			return typedExpr{}, err
+		}
+		return typedExpr{expr: executor.NewColumnRef(pos), meta: scope.columns[pos].colMeta}, nil
+	case *ast.WindowFuncExpr:
+		if scope.window == nil {
+			return typedExpr{}, fmt.Errorf("invalid use of window function %s", e.String())
+		}
+		pos, err := scope.window(e)
+		if err != nil {
+			return typedExpr{}, err
+		}
+		return typedExpr{expr: executor.NewColumnRef(pos), meta: scope.columns[pos].colMeta}, nil
+	case *ast.BinaryExpr:
+		return compileBinary(e, scope)
+	case *ast.UnaryExpr:
+		return compileUnary(e, scope)
+	case *ast.FuncCallExpr:
+		return compileFuncCall(e, scope)
+	case *ast.CaseExpr:
+		return compileCase(e, scope)
+	case *ast.InExpr:
+		return compileIn(e, scope)
+	case *ast.BetweenExpr:
+		return compileBetween(e, scope)
+	case *ast.SubqueryExpr:
+		return compileScalarSubquery(e, scope)
+	case *ast.ExistsExpr:
+		return compileExists(e, scope)
+	default:
+		return typedExpr{}, fmt.Errorf("unsupported expression: %T", expr)
+	}
+}
+
+// compileBinary は二項演算をコンパイルする
+func compileBinary(expr *ast.BinaryExpr, scope *exprScope) (typedExpr, error) {
+	leftExpr, rightExpr := ast.LeftOperand(expr.Left), ast.RightOperand(expr.Right)
+	if leftExpr == nil {
+		return typedExpr{}, fmt.Errorf("unsupported LHS type: %T", expr.Left)
+	}
+	if rightExpr == nil {
+		return typedExpr{}, fmt.Errorf("unsupported RHS type: %T", expr.Right)
+	}
+
+	switch expr.Operator {
+	case "AND", "OR":
+		left, err := compileBoolean(leftExpr, scope)
+		if err != nil {
+			return typedExpr{}, err
+		}
+		right, err := compileBoolean(rightExpr, scope)
+		if err != nil {
+			return typedExpr{}, err
+		}
+		return foldConstant(executor.NewLogical(expr.Operator, left.expr, right.expr), bigintMeta(), left, right)
+
+	case "IS", "IS NOT":
+		operand, err := compileValue(leftExpr, scope)
+		if err != nil {
+			return typedExpr{}, err
+		}
+		return foldConstant(executor.NewIsNull(operand.expr, expr.Operator == "IS NOT"), bigintMeta(), operand)
+
+	case "=", "!=", "<>", "<", "<=", ">", ">=":
+		left, err := compileExpr(leftExpr, scope)
+		if err != nil {
+			return typedExpr{}, err
+		}
+		right, err := compileExpr(rightExpr, scope)
+		if err != nil {
+			return typedExpr{}, err
+		}
+		return compileCompare(expr.Operator, left, right)
+
+	case "+", "-", "*", "/", "%":
+		left, err := compileExpr(leftExpr, scope)
+		if err != nil {
+			return typedExpr{}, err
+		}
+		right, err := compileExpr(rightExpr, scope)
+		if err != nil {
+			return typedExpr{}, err
+		}
+		return compileArithmetic(expr.Operator, left, right)
+
+	case "LIKE", "NOT LIKE":
+		left, err := compileExpr(leftExpr, scope)
+		if err != nil {
+			return typedExpr{}, err
+		}
+		right, err := compileExpr(rightExpr, scope)
+		if err != nil {
+			return typedExpr{}, err
+		}
+		return compileLike(expr.Operator == "NOT LIKE", left, right)
+
+	default:
+		return typedExpr{}, fmt.Errorf("unsupported operator: %s", expr.Operator)
+	}
+}
+
+// compileCompare は比較演算をコンパイルする
+//
+// 両辺を共通のデータ型に揃えて比較する。データ型が未確定のリテラルは比較相手のデータ型を踏まえてリテラル自身のデータ型を決める (literalType を参照)
+func compileCompare(operator string, left, right typedExpr) (typedExpr, error) {
+	if operator == "<>" {
+		operator = "!="
+	}
+
+	left, right, err := unifyOperands(left, right)
+	if err != nil {
+		return typedExpr{}, err
+	}
+	return foldConstant(executor.NewCompare(operator, left.expr, right.expr), bigintMeta(), left, right)
+}
+
+// compileArithmetic は算術演算をコンパイルする
+//
+// 結果のデータ型:
+//   - 整数同士の +, -, *, %: BIGINT
+//   - それ以外の +, -, %: 両辺のスケールの大きい方をスケールとする DECIMAL
+//   - *: 両辺のスケールの和をスケールとする DECIMAL
+//   - /: 左辺のスケールに divScaleIncrement 桁を足したスケールの DECIMAL
+func compileArithmetic(operator string, left, right typedExpr) (typedExpr, error) {
+	left, err := resolveLiteral(left, nil)
+	if err != nil {
+		return typedExpr{}, err
+	}
+	right, err = resolveLiteral(right, nil)
+	if err != nil {
+		return typedExpr{}, err
+	}
+	// NULL との演算は NULL
+	if left.isNull() || right.isNull() {
+		return typedExpr{expr: executor.NewConstant(nil)}, nil
+	}
+	for _, operand := range []typedExpr{left, right} {
+		if !isNumericType(operand.meta.Type) {
+			return typedExpr{}, fmt.Errorf("arithmetic operator %s requires numeric operands, but got %s", operator, operand.meta.Type)
+		}
+	}
+
+	var node executor.Expression
+	var scale int
+	switch operator {
+	case "*":
+		scale = int(left.meta.Scale) + int(right.meta.Scale)
+		node = executor.NewArithmetic(operator, left.expr, right.expr)
+	case "/":
+		scale = int(left.meta.Scale) + divScaleIncrement
+		// 商のスケール = 左辺のスケール + divShift - 右辺のスケール
+		divShift := scale - int(left.meta.Scale) + int(right.meta.Scale)
+		node = executor.NewDivision(left.expr, right.expr, divShift)
+	default:
+		scale = int(max(left.meta.Scale, right.meta.Scale))
+		if left, err = rescale(left, uint8(scale)); err != nil {
+			return typedExpr{}, err
+		}
+		if right, err = rescale(right, uint8(scale)); err != nil {
+			return typedExpr{}, err
+		}
+		node = executor.NewArithmetic(operator, left.expr, right.expr)
+	}
+	if scale > dictionary.MaxDecimalPrecision {
+		return typedExpr{}, fmt.Errorf("scale of the result of %s exceeds %d", operator, dictionary.MaxDecimalPrecision)
+	}
+
+	meta := decimalMeta(uint8(scale))
+	if operator != "/" && isIntegerType(left.meta.Type) && isIntegerType(right.meta.Type) {
+		meta = bigintMeta()
+	}
+	return foldConstant(node, meta, left, right)
+}
+
+// compileUnary は単項演算をコンパイルする
+func compileUnary(expr *ast.UnaryExpr, scope *exprScope) (typedExpr, error) {
+	switch expr.Operator {
+	case "-":
+	case "NOT":
+		operand, err := compileBoolean(expr.Operand, scope)
+		if err != nil {
+			return typedExpr{}, err
+		}
+		return foldConstant(executor.NewNot(operand.expr), bigintMeta(), operand)
+	default:
+		return typedExpr{}, fmt.Errorf("unsupported operator: %s", expr.Operator)
+	}
+	operand, err := compileValue(expr.Operand, scope)
+	if err != nil {
+		return typedExpr{}, err
+	}
+	if operand.isNull() {
+		return operand, nil
+	}
+	if !isNumericType(operand.meta.Type) {
+		return typedExpr{}, fmt.Errorf("arithmetic operator - requires a numeric operand, but got %s", operand.meta.Type)
+	}
+	meta := operand.meta
+	if isIntegerType(meta.Type) {
+		meta = bigintMeta()
+	}
+	return foldConstant(executor.NewNegate(operand.expr), meta, operand)
+}
+
+// compileLike は LIKE / NOT LIKE をコンパイルする
+//
+// 両辺を文字列として比較する (数値・日付は文字列に変換する)
+func compileLike(negate bool, left, right typedExpr) (typedExpr, error) {
+	left, err := castTo(left, stringMeta())
+	if err != nil {
+		return typedExpr{}, err
+	}
+	right, err = castTo(right, stringMeta())
+	if err != nil {
+		return typedExpr{}, err
+	}
+	return foldConstant(executor.NewLike(left.expr, right.expr, negate), bigintMeta(), left, right)
+}
+
+// compileIn は IN / NOT IN をコンパイルする
+//
+// 値とリストの各値を共通のデータ型に揃えて比較する
+func compileIn(expr *ast.InExpr, scope *exprScope) (typedExpr, error) {
+	if expr.Subquery != nil {
+		return compileInSubquery(expr, scope)
+	}
+	values, err := compileExprs(append([]ast.Expr{expr.Operand}, expr.Values...), scope)
+	if err != nil {
+		return typedExpr{}, err
+	}
+	values, err = unifyComparands(values)
+	if err != nil {
+		return typedExpr{}, err
+	}
+	list := make([]executor.Expression, len(values)-1)
+	for i, v := range values[1:] {
+		list[i] = v.expr
+	}
+	return foldConstant(executor.NewIn(values[0].expr, list, expr.Not), bigintMeta(), values...)
+}
+
+// compileBetween は BETWEEN / NOT BETWEEN をコンパイルする
+//
+// x BETWEEN a AND b は x >= a AND x <= b として評価する (NOT BETWEEN はその否定)
+func compileBetween(expr *ast.BetweenExpr, scope *exprScope) (typedExpr, error) {
+	values, err := compileExprs([]ast.Expr{expr.Operand, expr.Lower, expr.Upper}, scope)
+	if err != nil {
+		return typedExpr{}, err
+	}
+	values, err = unifyComparands(values)
+	if err != nil {
+		return typedExpr{}, err
+	}
+	operand, lower, upper := values[0], values[1], values[2]
+	var node executor.Expression = executor.NewLogical("AND",
+		executor.NewCompare(">=", operand.expr, lower.expr),
+		executor.NewCompare("<=", operand.expr, upper.expr),
+	)
+	if expr.Not {
+		node = executor.NewNot(node)
+	}
+	return foldConstant(node, bigintMeta(), values...)
+}
+
+// compileExprs は複数の式をコンパイルする
+func compileExprs(exprs []ast.Expr, scope *exprScope) ([]typedExpr, error) {
+	values := make([]typedExpr, len(exprs))
+	for i, e := range exprs {
+		te, err := compileExpr(e, scope)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = te
+	}
+	return values, nil
+}
+
+// compileFuncCall はスカラー関数の呼び出しをコンパイルする
+//
+// サポートする関数:
+//   - ABS(x), ROUND(x [, d]): 数値
+//   - UPPER(s), LOWER(s), LENGTH(s), CONCAT(s, ...): 文字列 (数値・日付は文字列に変換する)
+//   - COALESCE(x, ...), IFNULL(x, y): 最初の NULL でない値
+//   - VALUES(col): ON DUPLICATE KEY UPDATE で追加しようとした行の値
+func compileFuncCall(expr *ast.FuncCallExpr, scope *exprScope) (typedExpr, error) {
+	if expr.Name == "VALUES" {
+		return compileInsertValue(expr, scope)
+	}
+	switch ast.WindowFunc(expr.Name) {
+	case ast.WindowRowNumber, ast.WindowRank, ast.WindowDenseRank, ast.WindowLag, ast.WindowLead:
+		return typedExpr{}, fmt.Errorf("window function %s requires an OVER clause", expr.Name)
+	}
+
+	args := make([]typedExpr, len(expr.Args))
+	for i, arg := range expr.Args {
+		te, err := compileExpr(arg, scope)
+		if err != nil {
+			return typedExpr{}, err
+		}
+		args[i] = te
+	}
+
+	switch expr.Name {
+	case "ABS":
+		if err := checkArgCount(expr, len(args) == 1); err != nil {
+			return typedExpr{}, err
+		}
+		arg, err := numericArg(expr, args[0])
+		if err != nil || arg.isNull() {
+			return arg, err
+		}
+		meta := arg.meta
+		if isIntegerType(meta.Type) {
+			meta = bigintMeta()
+		}
+		return foldConstant(executor.NewFunction(executor.FuncAbs, []executor.Expression{arg.expr}), meta, arg)
+
+	case "ROUND":
+		if err := checkArgCount(expr, len(args) == 1 || len(args) == 2); err != nil {
+			return typedExpr{}, err
+		}
+		arg, err := numericArg(expr, args[0])
+		if err != nil || arg.isNull() {
+			return arg, err
+		}
+		// 丸める桁数は 0 以上の整数リテラルのみ指定できる
+		digits := 0
+		if len(args) == 2 {
+			if args[1].lit == nil {
+				return typedExpr{}, errors.New("the second argument of ROUND must be a non-negative integer literal")
+			}
+			d, err := strconv.Atoi(args[1].lit.ToString())
+			if err != nil || d < 0 {
+				return typedExpr{}, errors.New("the second argument of ROUND must be a non-negative integer literal")
+			}
+			digits = d
+		}
+		if digits >= int(arg.meta.Scale) {
+			return arg, nil
+		}
+		meta := decimalMeta(uint8(digits))
+		if digits == 0 {
+			meta = bigintMeta()
+		}
+		return castTo(arg, meta)
+
+	case "UPPER", "LOWER", "LENGTH":
+		if err := checkArgCount(expr, len(args) == 1); err != nil {
+			return typedExpr{}, err
+		}
+		arg, err := castTo(args[0], stringMeta())
+		if err != nil {
+			return typedExpr{}, err
+		}
+		fn, meta := executor.FuncUpper, stringMeta()
+		switch expr.Name {
+		case "LOWER":
+			fn = executor.FuncLower
+		case "LENGTH":
+			fn, meta = executor.FuncLength, bigintMeta()
+		}
+		return foldConstant(executor.NewFunction(fn, []executor.Expression{arg.expr}), meta, arg)
+
+	case "CONCAT":
+		if err := checkArgCount(expr, len(args) >= 1); err != nil {
+			return typedExpr{}, err
+		}
+		exprs := make([]executor.Expression, len(args))
+		for i := range args {
+			arg, err := castTo(args[i], stringMeta())
+			if err != nil {
+				return typedExpr{}, err
+			}
+			args[i], exprs[i] = arg, arg.expr
+		}
+		return foldConstant(executor.NewFunction(executor.FuncConcat, exprs), stringMeta(), args...)
+
+	case "COALESCE", "IFNULL":
+		if err := checkArgCount(expr, (expr.Name == "COALESCE" && len(args) >= 1) || len(args) == 2); err != nil {
+			return typedExpr{}, err
+		}
+		args, meta, err := unifyValuesOrString(args)
+		if err != nil {
+			return typedExpr{}, err
+		}
+		exprs := make([]executor.Expression, len(args))
+		for i, arg := range args {
+			exprs[i] = arg.expr
+		}
+		return foldConstant(executor.NewFunction(executor.FuncCoalesce, exprs), meta, args...)
+
+	default:
+		return typedExpr{}, fmt.Errorf("unknown function: %s", expr.Name)
+	}
+}
+
+// compileInsertValue は ON DUPLICATE KEY UPDATE の VALUES(col) をコンパイルする
+//
+// VALUES(col) は評価対象のレコード (既存の行) の後ろに連結した、追加しようとした行のカラムを参照する
+func compileInsertValue(expr *ast.FuncCallExpr, scope *exprScope) (typedExpr, error) {
+	if scope.inserting == nil {
+		return typedExpr{}, errors.New("VALUES() can only be used in ON DUPLICATE KEY UPDATE clause")
+	}
+	if err := checkArgCount(expr, len(expr.Args) == 1); err != nil {
+		return typedExpr{}, err
+	}
+	col, ok := expr.Args[0].(ast.ColumnId)
+	if !ok {
+		return typedExpr{}, fmt.Errorf("VALUES() argument must be a column name: %s", ast.ExprString(expr.Args[0]))
+	}
+	colMeta, ok := scope.inserting.GetColByName(col.ColName)
+	if !ok {
+		return typedExpr{}, errors.New("column " + col.ColName + " does not exist in table " + scope.inserting.Name)
+	}
+	pos := len(scope.columns) + int(colMeta.Pos)
+	return typedExpr{expr: executor.NewColumnRef(pos), meta: colMeta}, nil
+}
+
+// checkArgCount は関数の引数の数が正しくない場合にエラーを返す
+func checkArgCount(expr *ast.FuncCallExpr, ok bool) error {
+	if !ok {
+		return fmt.Errorf("incorrect number of arguments to function %s", expr.Name)
+	}
+	return nil
+}
+
+// numericArg は数値を受け付ける関数の引数を検証する (NULL はそのまま返す)
+func numericArg(expr *ast.FuncCallExpr, arg typedExpr) (typedExpr, error) {
+	arg, err := resolveLiteral(arg, nil)
+	if err != nil || arg.isNull() {
+		return arg, err
+	}
+	if !isNumericType(arg.meta.Type) {
+		return typedExpr{}, fmt.Errorf("function %s requires a numeric argument, but got %s", expr.Name, arg.meta.Type)
+	}
+	return arg, nil
+}
+
+// compileCase は CASE 式をコンパイルする
+//
+// 単純 CASE 式 (CASE operand WHEN value ...) は、各 WHEN 句を operand = value の条件に変換する
+// 各 WHEN 句の結果と ELSE の結果は共通のデータ型に揃える
+func compileCase(expr *ast.CaseExpr, scope *exprScope) (typedExpr, error) {
+	var operand typedExpr
+	if expr.Operand != nil {
+		var err error
+		if operand, err = compileExpr(expr.Operand, scope); err != nil {
+			return typedExpr{}, err
+		}
+	}
+
+	conditions := make([]typedExpr, len(expr.Whens))
+	results := make([]typedExpr, 0, len(expr.Whens)+1)
+	for i, when := range expr.Whens {
+		cond, err := compileWhenCondition(expr, when, operand, scope)
+		if err != nil {
+			return typedExpr{}, err
+		}
+		conditions[i] = cond
+
+		result, err := compileExpr(when.Result, scope)
+		if err != nil {
+			return typedExpr{}, err
+		}
+		results = append(results, result)
+	}
+	if expr.Else != nil {
+		result, err := compileExpr(expr.Else, scope)
+		if err != nil {
+			return typedExpr{}, err
+		}
+		results = append(results, result)
+	}
+
+	results, meta, err := unifyValues(results)
+	if err != nil {
+		return typedExpr{}, err
+	}
+	whens := make([]executor.CaseWhen, len(conditions))
+	for i, cond := range conditions {
+		whens[i] = executor.CaseWhen{Condition: cond.expr, Result: results[i].expr}
+	}
+	var elseExpr executor.Expression
+	if expr.Else != nil {
+		elseExpr = results[len(results)-1].expr
+	}
+	return foldConstant(executor.NewCase(whens, elseExpr), meta, append(conditions, results...)...)
+}
+
+// compileWhenCondition は CASE 式の WHEN 句の条件をコンパイルする
+func compileWhenCondition(expr *ast.CaseExpr, when *ast.WhenClause, operand typedExpr, scope *exprScope) (typedExpr, error) {
+	if expr.Operand == nil {
+		return compileBoolean(when.Condition, scope)
+	}
+	value, err := compileExpr(when.Condition, scope)
+	if err != nil {
+		return typedExpr{}, err
+	}
+	return compileCompare("=", operand, value)
+}
+
+// -------------------------------------------------
+// データ型の解決
+// -------------------------------------------------
+
+// resolveLiteral はデータ型が未確定のリテラルを、target のデータ型の定数に変換する
+//
+// target が nil の場合 (文脈からデータ型が決まらない場合) は、数値として解釈できれば数値 (BIGINT / DECIMAL)、それ以外は文字列として扱う
+// リテラル以外はそのまま返す
+func resolveLiteral(te typedExpr, target *handler.ColumnMetadata) (typedExpr, error) {
+	if te.lit == nil {
+		return te, nil
+	}
+	if _, ok := te.lit.(*ast.NullLiteral); ok {
+		return typedExpr{expr: executor.NewConstant(nil), meta: target}, nil
+	}
+	if target == nil {
+		target = inferLiteralType(te.lit.ToString())
+		// 数値のリテラルを数値として解釈できないのは、DECIMAL の精度や BIGINT の範囲を超える場合
+		if _, ok := te.lit.(*ast.NumberLiteral); ok && !isNumericType(target.Type) {
+			return typedExpr{}, fmt.Errorf("numeric value %s is out of range", te.lit.ToString())
+		}
+	}
+	value, err := parseLiteral(target, te.lit)
+	if err != nil {
+		return typedExpr{}, err
+	}
+	return typedExpr{expr: executor.NewConstant(value), meta: target}, nil
+}
+
+// literalType はデータ型が未確定のリテラルを other のデータ型の値と揃えるときの、リテラル自身のデータ型を返す
+//
+//   - other が数値: other で正確に表せない数値 (小数部の桁数が多い、範囲外) であれば推論した数値型 (BIGINT / DECIMAL)
+//   - other が DATE: DATE として解釈できず、DATETIME として解釈できれば DATETIME
+//   - それ以外: other
+//
+// 呼び出し側は other とこのデータ型の共通のデータ型に揃えるので、INT と 9.5、DATE と '2023-12-31 10:00:00' を丸めずに比較できる
+func literalType(lit ast.Literal, other *handler.ColumnMetadata) *handler.ColumnMetadata {
+	if _, ok := lit.(*ast.NullLiteral); ok {
+		return other
+	}
+	switch {
+	case isNumericType(other.Type):
+		inferred := inferLiteralType(lit.ToString())
+		if !isNumericType(inferred.Type) {
+			break
+		}
+		if _, err := parseLiteral(other, lit); err != nil || inferred.Scale > other.Scale {
+			return inferred
+		}
+	case other.Type == handler.ColumnTypeDate:
+		if _, err := parseLiteral(other, lit); err != nil {
+			if _, err := parseLiteral(datetimeMeta(), lit); err == nil {
+				return datetimeMeta()
+			}
+		}
+	}
+	return other
+}
+
+// checkNumberLiteral は数値のリテラルを数値以外のデータ型の値と揃えようとした場合にエラーを返す
+//
+// 文字列のリテラル (e.g. '10') は相手のデータ型で解釈するが、数値のリテラル (e.g. 10) を文字列や日付として解釈すると
+// 数値としての大小と結果が変わるため、カラム同士と同様に数値と文字列の比較はエラーとする
+func checkNumberLiteral(lit ast.Literal, other *handler.ColumnMetadata) error {
+	if _, ok := lit.(*ast.NumberLiteral); !ok || isNumericType(other.Type) {
+		return nil
+	}
+	return fmt.Errorf("incompatible types: %s and %s", other.Type, inferLiteralType(lit.ToString()).Type)
+}
+
+// inferLiteralType は文脈からデータ型が決まらないリテラルのデータ型を推論する
+func inferLiteralType(text string) *handler.ColumnMetadata {
+	digits := strings.TrimPrefix(text, "-")
+	intPart, fracPart, hasPoint := strings.Cut(digits, ".")
+	if intPart == "" || !isDigits(intPart) || !isDigits(fracPart) || (hasPoint && fracPart == "") {
+		return stringMeta()
+	}
+	if !hasPoint {
+		// DECIMAL の精度を超える桁数でも、BIGINT の範囲に収まれば BIGINT として扱う
+		if len(intPart) > dictionary.MaxDecimalPrecision {
+			if _, err := strconv.ParseInt(text, 10, 64); err != nil {
+				return stringMeta()
+			}
+		}
+		return bigintMeta()
+	}
+	if len(intPart)+len(fracPart) > dictionary.MaxDecimalPrecision {
+		return stringMeta()
+	}
+	return decimalMeta(uint8(len(fracPart)))
+}
+
+// isDigits は文字列が数字のみで構成されているかどうかを判定する
+func isDigits(s string) bool {
+	for _, ch := range s {
+		if ch < '0' || ch > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// castTo は式の値を target のデータ型の格納形式に変換する
+func castTo(te typedExpr, target *handler.ColumnMetadata) (typedExpr, error) {
+	if te.lit != nil {
+		return resolveLiteral(te, target)
+	}
+	if te.isNull() {
+		return typedExpr{expr: te.expr, meta: target}, nil
+	}
+	if sameStorageType(te.meta, target) {
+		return typedExpr{expr: te.expr, meta: target}, nil
+	}
+	return foldConstant(executor.NewCast(te.expr, te.meta, target), target, te)
+}
+
+// rescale は数値のスケールを scale に揃える (スケールが同じ場合はそのまま返す)
+func rescale(te typedExpr, scale uint8) (typedExpr, error) {
+	if te.meta.Scale == scale {
+		return te, nil
+	}
+	return castTo(te, decimalMeta(scale))
+}
+
+// sameStorageType は 2 つのデータ型の格納形式が同じで、変換が不要かどうかを返す
+//
+// INT の値は BIGINT として扱えるが、その逆は範囲の確認が必要なため変換する
+func sameStorageType(from, to *handler.ColumnMetadata) bool {
+	switch {
+	case from.Type == to.Type:
+		return from.Type != handler.ColumnTypeDecimal || (from.Scale == to.Scale && from.Precision <= to.Precision)
+	case from.Type == handler.ColumnTypeInt && to.Type == handler.ColumnTypeBigInt:
+		return true
+	case from.Type == handler.ColumnTypeDate && to.Type == handler.ColumnTypeDatetime:
+		return true
+	}
+	return false
+}
+
+// unifyOperands は比較する 2 つの値を共通のデータ型に揃える
+func unifyOperands(left, right typedExpr) (typedExpr, typedExpr, error) {
+	values, err := unifyComparands([]typedExpr{left, right})
+	if err != nil {
+		return typedExpr{}, typedExpr{}, err
+	}
+	return values[0], values[1], nil
+}
+
+// unifyComparands は比較する値 (比較演算の両辺、IN の値とリスト、BETWEEN の値と両端) を共通のデータ型に揃える
+//
+// 数値のリテラルを数値以外の値と比較する場合はエラーを返す (checkNumberLiteral を参照)
+func unifyComparands(values []typedExpr) ([]typedExpr, error) {
+	unified, common, err := unifyValues(slices.Clone(values))
+	if err != nil {
+		return nil, err
+	}
+	if common == nil {
+		return unified, nil
+	}
+	for _, v := range values {
+		if v.lit == nil {
+			continue
+		}
+		if err := checkNumberLiteral(v.lit, common); err != nil {
+			return nil, err
+		}
+	}
+	return unified, nil
+}
+
+// unifyValues は複数の値 (CASE の結果、COALESCE の引数など) を共通のデータ型に揃え、そのデータ型を返す
+//
+//   - 数値同士: 整数のみなら BIGINT、それ以外はスケールの最大値をスケールとする DECIMAL
+//   - 日付同士: DATETIME を含めば DATETIME、それ以外は DATE
+//   - 文字列同士: 文字列
+//
+// データ型が未確定のリテラルは、他の値から決まった共通のデータ型を踏まえてリテラル自身のデータ型を決め (literalType を参照)、
+// そのデータ型も含めた共通のデータ型で解釈する (すべてリテラルの場合は推論する)
+func unifyValues(values []typedExpr) ([]typedExpr, *handler.ColumnMetadata, error) {
+	var common *handler.ColumnMetadata
+	for _, v := range values {
+		if v.lit != nil || v.isNull() {
+			continue
+		}
+		if common == nil {
+			common = v.meta
+			continue
+		}
+		merged, ok := commonType(common, v.meta)
+		if !ok {
+			return nil, nil, fmt.Errorf("incompatible types: %s and %s", common.Type, v.meta.Type)
+		}
+		common = merged
+	}
+	if common != nil {
+		for _, v := range values {
+			if v.lit == nil {
+				continue
+			}
+			if merged, ok := commonType(common, literalType(v.lit, common)); ok {
+				common = merged
+			}
+		}
+	}
+
+	// 型の決まった値がない場合は、リテラルから推論した型を共通のデータ型とする
+	if common == nil {
+		for i, v := range values {
+			if v.lit == nil {
+				continue
+			}
+			resolved, err := resolveLiteral(v, nil)
+			if err != nil {
+				return nil, nil, err
+			}
+			values[i] = resolved
+		}
+		return unifyLiteralTypes(values)
+	}
+
+	unified := make([]typedExpr, len(values))
+	for i, v := range values {
+		te, err := castTo(v, common)
+		if err != nil {
+			return nil, nil, err
+		}
+		unified[i] = te
+	}
+	return unified, common, nil
+}
+
+// unifyValuesOrString は unifyValues と同様に複数の値を共通のデータ型に揃えるが、共通のデータ型に揃えられない場合
+// (e.g. DECIMAL のカラムと 'none'、DATE のカラムと 'n') は MySQL と同様に文字列に揃える
+func unifyValuesOrString(values []typedExpr) ([]typedExpr, *handler.ColumnMetadata, error) {
+	if unified, meta, err := unifyValues(slices.Clone(values)); err == nil {
+		return unified, meta, nil
+	}
+	unified := make([]typedExpr, len(values))
+	for i, v := range values {
+		te, err := castTo(v, stringMeta())
+		if err != nil {
+			return nil, nil, err
+		}
+		unified[i] = te
+	}
+	return unified, stringMeta(), nil
+}
+
+// unifyLiteralTypes はリテラルから推論した型の値を共通のデータ型に揃える
+func unifyLiteralTypes(values []typedExpr) ([]typedExpr, *handler.ColumnMetadata, error) {
+	var common *handler.ColumnMetadata
+	for _, v := range values {
+		if v.meta == nil {
+			continue
+		}
+		if common == nil {
+			common = v.meta
+			continue
+		}
+		merged, ok := commonType(common, v.meta)
+		if !ok {
+			// 数値と文字列が混在する場合は文字列として扱う
+			merged = stringMeta()
+		}
+		common = merged
+	}
+	if common == nil {
+		return values, nil, nil
+	}
+	unified := make([]typedExpr, len(values))
+	for i, v := range values {
+		te, err := castTo(v, common)
+		if err != nil {
+			return nil, nil, err
+		}
+		unified[i] = te
+	}
+	return unified, common, nil
+}
+
+// commonType は 2 つのデータ型の共通のデータ型を返す (共通のデータ型がない場合は false)
+func commonType(a, b *handler.ColumnMetadata) (*handler.ColumnMetadata, bool) {
+	switch {
+	case isNumericType(a.Type) && isNumericType(b.Type):
+		if isIntegerType(a.Type) && isIntegerType(b.Type) {
+			if a.Type == handler.ColumnTypeInt && b.Type == handler.ColumnTypeInt {
+				return a, true
+			}
+			return bigintMeta(), true
+		}
+		if a.Type == handler.ColumnTypeDecimal && b.Type == handler.ColumnTypeDecimal && a.Scale == b.Scale && a.Precision == b.Precision {
+			return a, true
+		}
+		return decimalMeta(max(a.Scale, b.Scale)), true
+	case isTemporalType(a.Type) && isTemporalType(b.Type):
+		if a.Type == b.Type {
+			return a, true
+		}
+		return &handler.ColumnMetadata{Type: handler.ColumnTypeDatetime}, true
+	case a.Type == handler.ColumnTypeString && b.Type == handler.ColumnTypeString:
+		return a, true
+	}
+	return nil, false
+}
+
+// foldConstant は被演算子がすべて定数の場合に式をコンパイル時に評価し、定数に置き換える
+func foldConstant(node executor.Expression, meta *handler.ColumnMetadata, operands ...typedExpr) (typedExpr, error) {
+	for _, operand := range operands {
+		if !operand.isConstant() {
+			return typedExpr{expr: node, meta: meta}, nil
+		}
+	}
+	value, err := node.Eval(nil)
+	if err != nil {
+		return typedExpr{}, err
+	}
+	return typedExpr{expr: executor.NewConstant(value), meta: meta}, nil
+}
+
+func isNumericType(t handler.ColumnType) bool {
+	return t == handler.ColumnTypeInt || t == handler.ColumnTypeBigInt || t == handler.ColumnTypeDecimal
+}
+
+func isIntegerType(t handler.ColumnType) bool {
+	return t == handler.ColumnTypeInt || t == handler.ColumnTypeBigInt
+}
+
+func isTemporalType(t handler.ColumnType) bool {
+	return t == handler.ColumnTypeDate || t == handler.ColumnTypeDatetime
+}
+
+func bigintMeta() *handler.ColumnMetadata {
+	return &handler.ColumnMetadata{Type: handler.ColumnTypeBigInt}
+}
+
+func decimalMeta(scale uint8) *handler.ColumnMetadata {
+	return &handler.ColumnMetadata{Type: handler.ColumnTypeDecimal, Precision: dictionary.MaxDecimalPrecision, Scale: scale}
+}
+
+func datetimeMeta() *handler.ColumnMetadata {
+	return &handler.ColumnMetadata{Type: handler.ColumnTypeDatetime}
+}
+
+func stringMeta() *handler.ColumnMetadata {
+	return &handler.ColumnMetadata{Type: handler.ColumnTypeString}
+}
@@ -0,0 +1,408 @@
+package planner
+
+import (
+	"testing"
+
+	"github.com/ren-yamanashi/minesql/internal/ast"
+	"github.com/ren-yamanashi/minesql/internal/executor"
+	"github.com/ren-yamanashi/minesql/internal/storage/encode"
+	"github.com/ren-yamanashi/minesql/internal/storage/handler"
+	"github.com/stretchr/testify/assert"
+)
+
+// testExprScope は products(name STRING, qty INT, price DECIMAL(10, 2)) のレコードに対するスコープを作成する
+func testExprScope() *exprScope {
+	return newExprScope([]joinedColumn{
+		{tableName: "products", colName: "name", pos: 0, colMeta: &handler.ColumnMetadata{Name: "name", Type: handler.ColumnTypeString}},
+		{tableName: "products", colName: "qty", pos: 1, colMeta: &handler.ColumnMetadata{Name: "qty", Type: handler.ColumnTypeInt}},
+		{tableName: "products", colName: "price", pos: 2, colMeta: &handler.ColumnMetadata{Name: "price", Type: handler.ColumnTypeDecimal, Precision: 10, Scale: 2}},
+	})
+}
+
+// binary は parser と同様に左辺・右辺をラップして二項演算を作成する
+func binary(operator string, left, right ast.Expr) *ast.BinaryExpr {
+	var lhs ast.LHS
+	switch v := left.(type) {
+	case ast.ColumnId:
+		lhs = ast.NewLhsColumn(v)
+	case ast.Literal:
+		lhs = ast.NewLhsLiteral(v)
+	case *ast.BinaryExpr:
+		lhs = ast.NewLhsExpr(v)
+	default:
+		lhs = v.(ast.LHS)
+	}
+	var rhs ast.RHS
+	switch v := right.(type) {
+	case ast.ColumnId:
+		rhs = ast.NewRhsColumn(v)
+	case ast.Literal:
+		rhs = ast.NewRhsLiteral(v)
+	case *ast.BinaryExpr:
+		rhs = ast.NewRhsExpr(v)
+	default:
+		rhs = v.(ast.RHS)
+	}
+	return ast.NewBinaryExpr(operator, lhs, rhs)
+}
+
+func col(name string) ast.ColumnId {
+	return *ast.NewColumnId(name)
+}
+
+func lit(value string) *ast.StringLiteral {
+	return ast.NewStringLiteral(value)
+}
+
+func num(value string) *ast.NumberLiteral {
+	return ast.NewNumberLiteral(value)
+}
+
+func TestCompileValue(t *testing.T) {
+	// name = "apple", qty = 3, price = 1.25
+	record := executor.Record{[]byte("apple"), encode.EncodeInt64(3), encode.EncodeInt64(125)}
+
+	t.Run("整数と DECIMAL の乗算は、両辺のスケールの和をスケールとする DECIMAL になる", func(t *testing.T) {
+		// GIVEN
+		expr := binary("*", col("qty"), col("price"))
+
+		// WHEN
+		te, err := compileValue(expr, testExprScope())
+
+		// THEN
+		assert.NoError(t, err)
+		assert.Equal(t, handler.ColumnTypeDecimal, te.meta.Type)
+		assert.Equal(t, uint8(2), te.meta.Scale)
+		result, err := te.expr.Eval(record)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(375), encode.DecodeInt64(result))
+	})
+
+	t.Run("加算は両辺のスケールを揃える", func(t *testing.T) {
+		// GIVEN: price + 1
+		expr := binary("+", col("price"), lit("1"))
+
+		// WHEN
+		te, err := compileValue(expr, testExprScope())
+
+		// THEN
+		assert.NoError(t, err)
+		assert.Equal(t, uint8(2), te.meta.Scale)
+		result, err := te.expr.Eval(record)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(225), encode.DecodeInt64(result))
+	})
+
+	t.Run("整数同士の除算は DECIMAL になる", func(t *testing.T) {
+		// GIVEN: qty / 2
+		expr := binary("/", col("qty"), lit("2"))
+
+		// WHEN
+		te, err := compileValue(expr, testExprScope())
+
+		// THEN
+		assert.NoError(t, err)
+		assert.Equal(t, handler.ColumnTypeDecimal, te.meta.Type)
+		result, err := te.expr.Eval(record)
+		assert.NoError(t, err)
+		assert.Equal(t, "1.5000", te.meta.FormatValue(result))
+	})
+
+	t.Run("定数のみの式はコンパイル時に評価される", func(t *testing.T) {
+		// GIVEN: (1 + 2) * 3
+		expr := binary("*", binary("+", lit("1"), lit("2")), lit("3"))
+
+		// WHEN
+		te, err := compileValue(expr, testExprScope())
+
+		// THEN
+		assert.NoError(t, err)
+		constant, ok := te.expr.(*executor.Constant)
+		assert.True(t, ok)
+		assert.Equal(t, int64(9), encode.DecodeInt64(constant.Value))
+	})
+
+	t.Run("関数の引数の数値は文字列に変換される", func(t *testing.T) {
+		// GIVEN: CONCAT(name, '-', qty)
+		expr := ast.NewFuncCallExpr("concat", []ast.Expr{col("name"), lit("-"), col("qty")})
+
+		// WHEN
+		te, err := compileValue(expr, testExprScope())
+
+		// THEN
+		assert.NoError(t, err)
+		assert.Equal(t, handler.ColumnTypeString, te.meta.Type)
+		result, err := te.expr.Eval(record)
+		assert.NoError(t, err)
+		assert.Equal(t, "apple-3", string(result))
+	})
+
+	t.Run("文字列の算術演算はエラーを返す", func(t *testing.T) {
+		// GIVEN
+		expr := binary("+", col("name"), lit("1"))
+
+		// WHEN
+		_, err := compileValue(expr, testExprScope())
+
+		// THEN
+		assert.ErrorContains(t, err, "arithmetic operator + requires numeric operands, but got string")
+	})
+
+	t.Run("COALESCE・IFNULL の引数に共通のデータ型がない場合は文字列として返す", func(t *testing.T) {
+		// GIVEN: COALESCE(price, 'none')、price = NULL の行と price = 1.25 の行
+		expr := ast.NewFuncCallExpr("coalesce", []ast.Expr{col("price"), lit("none")})
+		nullRecord := executor.Record{[]byte("apple"), encode.EncodeInt64(3), nil}
+
+		// WHEN
+		te, err := compileValue(expr, testExprScope())
+
+		// THEN
+		assert.NoError(t, err)
+		assert.Equal(t, handler.ColumnTypeString, te.meta.Type)
+		result, err := te.expr.Eval(nullRecord)
+		assert.NoError(t, err)
+		assert.Equal(t, "none", string(result))
+		result, err = te.expr.Eval(record)
+		assert.NoError(t, err)
+		assert.Equal(t, "1.25", string(result))
+	})
+
+	t.Run("範囲を超える数値のリテラルはエラーを返す", func(t *testing.T) {
+		// GIVEN: 99999999999999999999 * 2
+		expr := binary("*", num("99999999999999999999"), num("2"))
+
+		// WHEN
+		_, err := compileValue(expr, testExprScope())
+
+		// THEN
+		assert.EqualError(t, err, "numeric value 99999999999999999999 is out of range")
+	})
+
+	t.Run("存在しない関数はエラーを返す", func(t *testing.T) {
+		// GIVEN
+		expr := ast.NewFuncCallExpr("unknown", []ast.Expr{col("name")})
+
+		// WHEN
+		_, err := compileValue(expr, testExprScope())
+
+		// THEN
+		assert.Error(t, err)
+	})
+
+	t.Run("集約関数を使用できないスコープで集約関数を使用した場合、エラーを返す", func(t *testing.T) {
+		// GIVEN
+		expr := ast.NewAggregateExpr(ast.AggregateCount, nil)
+
+		// WHEN
+		_, err := compileValue(expr, testExprScope())
+
+		// THEN
+		assert.ErrorContains(t, err, "invalid use of aggregate function COUNT(*)")
+	})
+}
+
+func TestCompileCondition(t *testing.T) {
+	t.Run("カラム同士を比較できる (数値同士はスケールを揃える)", func(t *testing.T) {
+		// GIVEN: qty > price
+		expr := binary(">", col("qty"), col("price"))
+		cond, err := compileCondition(expr, testExprScope())
+		assert.NoError(t, err)
+
+		// WHEN
+		greater, err1 := cond.Eval(executor.Record{[]byte("a"), encode.EncodeInt64(2), encode.EncodeInt64(150)})
+		less, err2 := cond.Eval(executor.Record{[]byte("a"), encode.EncodeInt64(1), encode.EncodeInt64(150)})
+
+		// THEN
+		assert.NoError(t, err1)
+		assert.NoError(t, err2)
+		assert.Equal(t, encode.EncodeInt64(1), greater)
+		assert.Equal(t, encode.EncodeInt64(0), less)
+	})
+
+	t.Run("CASE 式の結果と比較できる", func(t *testing.T) {
+		// GIVEN: CASE WHEN qty > 2 THEN 'many' ELSE 'few' END = 'many'
+		caseExpr := &ast.CaseExpr{
+			Whens: []*ast.WhenClause{{Condition: binary(">", col("qty"), lit("2")), Result: lit("many")}},
+			Else:  lit("few"),
+		}
+		cond, err := compileCondition(binary("=", caseExpr, lit("many")), testExprScope())
+		assert.NoError(t, err)
+
+		// WHEN
+		result, err := cond.Eval(executor.Record{[]byte("a"), encode.EncodeInt64(3), encode.EncodeInt64(0)})
+
+		// THEN
+		assert.NoError(t, err)
+		assert.Equal(t, encode.EncodeInt64(1), result)
+	})
+
+	t.Run("IN・BETWEEN・LIKE・NOT を評価できる", func(t *testing.T) {
+		// name = "apple", qty = 3, price = 1.25
+		record := executor.Record{[]byte("apple"), encode.EncodeInt64(3), encode.EncodeInt64(125)}
+		tests := []struct {
+			name     string
+			expr     ast.Expr
+			expected []byte
+		}{
+			{"IN のリテラルはカラムのデータ型で解釈する", ast.NewInExpr(col("price"), []ast.Expr{lit("1"), lit("1.25")}, false), encode.EncodeInt64(1)},
+			{"NOT IN", ast.NewInExpr(col("qty"), []ast.Expr{lit("1"), lit("2")}, true), encode.EncodeInt64(1)},
+			{"NOT IN のリストに NULL を含む場合は NULL", ast.NewInExpr(col("qty"), []ast.Expr{lit("1"), ast.NewNullLiteral()}, true), nil},
+			{"BETWEEN は両端を含む", ast.NewBetweenExpr(col("qty"), lit("1"), lit("3"), false), encode.EncodeInt64(1)},
+			{"NOT BETWEEN", ast.NewBetweenExpr(col("price"), lit("1.5"), lit("2"), true), encode.EncodeInt64(1)},
+			{"LIKE", binary("LIKE", col("name"), lit("a%e")), encode.EncodeInt64(1)},
+			{"数値の LIKE は文字列に変換して比較する", binary("LIKE", col("price"), lit("1.2_")), encode.EncodeInt64(1)},
+			{"NOT", ast.NewUnaryExpr("NOT", binary("=", col("qty"), lit("3"))), encode.EncodeInt64(0)},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				// GIVEN
+				cond, err := compileCondition(tt.expr, testExprScope())
+				assert.NoError(t, err)
+
+				// WHEN
+				result, err := cond.Eval(record)
+
+				// THEN
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, result)
+			})
+		}
+	})
+
+	t.Run("リテラルがカラムのデータ型で正確に表せない場合は、リテラル自身のデータ型に揃えて比較する", func(t *testing.T) {
+		// name = "apple", qty = 3, price = 1.25
+		record := executor.Record{[]byte("apple"), encode.EncodeInt64(3), encode.EncodeInt64(125)}
+		tests := []struct {
+			name     string
+			expr     ast.Expr
+			expected []byte
+		}{
+			{"INT のカラムと小数", binary(">", col("qty"), lit("2.5")), encode.EncodeInt64(1)},
+			{"INT のカラムと小数 (等値)", binary("=", col("qty"), lit("3.5")), encode.EncodeInt64(0)},
+			{"INT のカラムと INT の範囲外の整数", binary("<", col("qty"), lit("3000000000")), encode.EncodeInt64(1)},
+			{"DECIMAL のカラムとスケールより桁数の多い小数 (四捨五入しない)", binary("=", col("price"), lit("1.249")), encode.EncodeInt64(0)},
+			{"IN のリストの小数", ast.NewInExpr(col("qty"), []ast.Expr{lit("2.5"), lit("3")}, false), encode.EncodeInt64(1)},
+			{"BETWEEN の小数", ast.NewBetweenExpr(col("qty"), lit("2.5"), lit("3.5"), false), encode.EncodeInt64(1)},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				// GIVEN
+				cond, err := compileCondition(tt.expr, testExprScope())
+				assert.NoError(t, err)
+
+				// WHEN
+				result, err := cond.Eval(record)
+
+				// THEN
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, result)
+			})
+		}
+	})
+
+	t.Run("DATE のカラムと時刻を含むリテラルは DATETIME として比較する", func(t *testing.T) {
+		// GIVEN: day > '2023-12-31 10:00:00'
+		scope := newExprScope([]joinedColumn{
+			{tableName: "events", colName: "day", pos: 0, colMeta: &handler.ColumnMetadata{Name: "day", Type: handler.ColumnTypeDate}},
+		})
+		cond, err := compileCondition(binary(">", col("day"), lit("2023-12-31 10:00:00")), scope)
+		assert.NoError(t, err)
+		dateMeta := &handler.ColumnMetadata{Type: handler.ColumnTypeDate}
+		dec31, _ := dateMeta.ParseValue("2023-12-31")
+		jan1, _ := dateMeta.ParseValue("2024-01-01")
+
+		// WHEN
+		before, err1 := cond.Eval(executor.Record{dec31})
+		after, err2 := cond.Eval(executor.Record{jan1})
+
+		// THEN
+		assert.NoError(t, err1)
+		assert.NoError(t, err2)
+		assert.Equal(t, encode.EncodeInt64(0), before)
+		assert.Equal(t, encode.EncodeInt64(1), after)
+	})
+
+	t.Run("IN の値のデータ型に互換性がない場合、エラーを返す", func(t *testing.T) {
+		// GIVEN: qty IN (1, name)
+		expr := ast.NewInExpr(col("qty"), []ast.Expr{lit("1"), col("name")}, false)
+
+		// WHEN
+		_, err := compileCondition(expr, testExprScope())
+
+		// THEN
+		assert.ErrorContains(t, err, "incompatible types")
+	})
+
+	t.Run("文字列のカラムと数値のリテラルを比較した場合、エラーを返す", func(t *testing.T) {
+		tests := []struct {
+			name string
+			expr ast.Expr
+		}{
+			{"比較演算", binary(">", col("name"), num("9"))},
+			{"IN", ast.NewInExpr(col("name"), []ast.Expr{num("5"), num("7")}, false)},
+			{"BETWEEN", ast.NewBetweenExpr(col("name"), num("1"), num("9"), false)},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				// WHEN
+				_, err := compileCondition(tt.expr, testExprScope())
+
+				// THEN
+				assert.EqualError(t, err, "incompatible types: string and bigint")
+			})
+		}
+	})
+
+	t.Run("文字列のカラムと文字列のリテラルは文字列として比較する", func(t *testing.T) {
+		// GIVEN: name > '9' (name = "10")
+		cond, err := compileCondition(binary(">", col("name"), lit("9")), testExprScope())
+		assert.NoError(t, err)
+
+		// WHEN
+		result, err := cond.Eval(executor.Record{[]byte("10"), encode.EncodeInt64(3), encode.EncodeInt64(125)})
+
+		// THEN
+		assert.NoError(t, err)
+		assert.Equal(t, encode.EncodeInt64(0), result)
+	})
+
+	t.Run("文字列を条件として使用した場合、エラーを返す", func(t *testing.T) {
+		// GIVEN
+		expr := binary("AND", col("name"), binary("=", col("qty"), lit("1")))
+
+		// WHEN
+		_, err := compileCondition(expr, testExprScope())
+
+		// THEN
+		assert.ErrorContains(t, err, "expression name cannot be used as a condition")
+	})
+}
+
+func TestCompileAssignment(t *testing.T) {
+	t.Run("代入先のカラムのデータ型に変換する", func(t *testing.T) {
+		// GIVEN: price = qty * 2
+		colMeta := &handler.ColumnMetadata{Name: "price", Type: handler.ColumnTypeDecimal, Precision: 10, Scale: 2}
+		expr, err := compileAssignment(binary("*", col("qty"), lit("2")), testExprScope(), colMeta)
+		assert.NoError(t, err)
+
+		// WHEN
+		result, err := expr.Eval(executor.Record{[]byte("a"), encode.EncodeInt64(3), encode.EncodeInt64(0)})
+
+		// THEN
+		assert.NoError(t, err)
+		assert.Equal(t, int64(600), encode.DecodeInt64(result))
+	})
+
+	t.Run("代入先のカラムの範囲を超える場合、実行時にエラーを返す", func(t *testing.T) {
+		// GIVEN
+		colMeta := &handler.ColumnMetadata{Name: "qty", Type: handler.ColumnTypeInt}
+		expr, err := compileAssignment(binary("*", col("qty"), lit("10000000000")), testExprScope(), colMeta)
+		assert.NoError(t, err)
+
+		// WHEN
+		_, err = expr.Eval(executor.Record{[]byte("a"), encode.EncodeInt64(3), encode.EncodeInt64(0)})
+
+		// THEN
+		assert.ErrorContains(t, err, "out of range value for column 'qty'")
+	})
+}
@@ -11,7 +11,8 @@ import (
 type joinedColumn struct {
 	tableName string
 	colName   string
-	pos       int // 結合レコード内の位置
+	pos       int                     // 結合レコード内の位置
+	colMeta   *handler.ColumnMetadata // カラムのメタデータ (データ型の参照に使用)
 }
 
 // resolveJoinedColumns は参加テーブルの全カラムを結合順に並べ、位置マッピングを返す
@@ -29,6 +30,7 @@ func resolveJoinedColumns(tables []*handler.TableMetadata) []joinedColumn {
 				tableName: tbl.Name,
 				colName:   col.Name,
 				pos:       pos,
+				colMeta:   col,
 			})
 			pos++
 		}
@@ -44,7 +46,7 @@ func findColumnPos(columns []joinedColumn, tableName, colName string) (int, erro
 	if tableName != "" {
 		// 修飾名: テーブル名 + カラム名で一意特定
 		for _, col := range columns {
-			if col.tableName == tableName && col.colName == colName {
+			if refersToTable(tableName, col.tableName) && col.colName == colName {
 				return col.pos, nil
 			}
 		}
@@ -67,6 +69,16 @@ func findColumnPos(columns []joinedColumn, tableName, colName string) (int, erro
 	return found, nil
 }
 
+// findTableOffset は結合レコード内のテーブルの先頭カラムの位置を返す
+func findTableOffset(columns []joinedColumn, tableName string) (int, error) {
+	for _, col := range columns {
+		if col.tableName == tableName {
+			return col.pos, nil
+		}
+	}
+	return -1, fmt.Errorf("table %s not found in joined tables", tableName)
+}
+
 // resolveSelectColumns は単一テーブルの SELECT カラムをカラム位置に変換する
 //
 // columns が nil の場合は SELECT * (全カラム)
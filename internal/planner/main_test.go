@@ -14,6 +14,7 @@ import (
 // Executor から全レコードを取得する
 func fetchAll(t *testing.T, iter executor.Executor) []executor.Record {
 	t.Helper()
+	defer iter.Close()
 	var records []executor.Record
 	for {
 		record, err := iter.Next()
@@ -60,7 +61,7 @@ func setupUsersTable(t *testing.T) {
 			&ast.ConstraintPrimaryKeyDef{Columns: []ast.ColumnId{
 				*ast.NewColumnId("id"),
 			}},
-			&ast.ConstraintUniqueKeyDef{Column: *ast.NewColumnId("username")},
+			&ast.ConstraintUniqueKeyDef{Columns: []ast.ColumnId{*ast.NewColumnId("username")}},
 		},
 	})
 
@@ -121,6 +122,37 @@ func setupUsersTable(t *testing.T) {
 	})
 }
 
+// setupUsersTable で作成した users テーブルに加えて、orders テーブルを作成してデータを投入する
+//
+// テーブル: orders (id PK, user_id, status)
+// インデックス: KEY idx_user_id (user_id)
+// データ: ("1","1","open"), ("2","1","open"), ("3","4","open"), ("4","9","open") (user_id = 9 のユーザーは存在しない)
+func setupOrdersTable(t *testing.T) {
+	t.Helper()
+	setupUsersTable(t)
+
+	executePlan(t, &ast.CreateTableStmt{
+		TableName: "orders",
+		CreateDefinitions: []ast.Definition{
+			&ast.ColumnDef{ColName: "id", DataType: ast.DataTypeVarchar},
+			&ast.ColumnDef{ColName: "user_id", DataType: ast.DataTypeVarchar},
+			&ast.ColumnDef{ColName: "status", DataType: ast.DataTypeVarchar},
+			&ast.ConstraintPrimaryKeyDef{Columns: []ast.ColumnId{*ast.NewColumnId("id")}},
+			&ast.ConstraintKeyDef{KeyName: "idx_user_id", Columns: []ast.ColumnId{*ast.NewColumnId("user_id")}},
+		},
+	})
+	executePlan(t, &ast.InsertStmt{
+		Table: *ast.NewTableId("orders"),
+		Cols:  []ast.ColumnId{*ast.NewColumnId("id"), *ast.NewColumnId("user_id"), *ast.NewColumnId("status")},
+		Values: [][]ast.Literal{
+			{ast.NewStringLiteral("1"), ast.NewStringLiteral("1"), ast.NewStringLiteral("open")},
+			{ast.NewStringLiteral("2"), ast.NewStringLiteral("1"), ast.NewStringLiteral("open")},
+			{ast.NewStringLiteral("3"), ast.NewStringLiteral("4"), ast.NewStringLiteral("open")},
+			{ast.NewStringLiteral("4"), ast.NewStringLiteral("9"), ast.NewStringLiteral("open")},
+		},
+	})
+}
+
 // ストレージを初期化し、非ユニークインデックス付きの products テーブルを作成してデータを投入する
 //
 // テーブル: products (id PK, name, category)
@@ -142,7 +174,7 @@ func setupProductsTable(t *testing.T) {
 			&ast.ColumnDef{ColName: "name", DataType: ast.DataTypeVarchar},
 			&ast.ColumnDef{ColName: "category", DataType: ast.DataTypeVarchar},
 			&ast.ConstraintPrimaryKeyDef{Columns: []ast.ColumnId{*ast.NewColumnId("id")}},
-			&ast.ConstraintKeyDef{KeyName: "idx_category", Column: *ast.NewColumnId("category")},
+			&ast.ConstraintKeyDef{KeyName: "idx_category", Columns: []ast.ColumnId{*ast.NewColumnId("category")}},
 		},
 	})
 
@@ -512,7 +544,7 @@ func TestPlannerIntegration(t *testing.T) {
 				&ast.ConstraintPrimaryKeyDef{Columns: []ast.ColumnId{
 					*ast.NewColumnId("id"),
 				}},
-				&ast.ConstraintUniqueKeyDef{Column: *ast.NewColumnId("user_id")},
+				&ast.ConstraintUniqueKeyDef{Columns: []ast.ColumnId{*ast.NewColumnId("user_id")}},
 			},
 		})
 
@@ -572,7 +604,7 @@ func TestPlannerIntegration(t *testing.T) {
 				&ast.ColumnDef{ColName: "user_id", DataType: ast.DataTypeVarchar},
 				&ast.ColumnDef{ColName: "item", DataType: ast.DataTypeVarchar},
 				&ast.ConstraintPrimaryKeyDef{Columns: []ast.ColumnId{*ast.NewColumnId("id")}},
-				&ast.ConstraintUniqueKeyDef{Column: *ast.NewColumnId("user_id")},
+				&ast.ConstraintUniqueKeyDef{Columns: []ast.ColumnId{*ast.NewColumnId("user_id")}},
 			},
 		})
 
@@ -669,7 +701,7 @@ func TestPlannerIntegration(t *testing.T) {
 				&ast.ColumnDef{ColName: "user_id", DataType: ast.DataTypeVarchar},
 				&ast.ColumnDef{ColName: "item", DataType: ast.DataTypeVarchar},
 				&ast.ConstraintPrimaryKeyDef{Columns: []ast.ColumnId{*ast.NewColumnId("id")}},
-				&ast.ConstraintUniqueKeyDef{Column: *ast.NewColumnId("user_id")},
+				&ast.ConstraintUniqueKeyDef{Columns: []ast.ColumnId{*ast.NewColumnId("user_id")}},
 			},
 		})
 		for i := range 10 {
@@ -729,7 +761,7 @@ func TestPlannerIntegration(t *testing.T) {
 				&ast.ConstraintPrimaryKeyDef{Columns: []ast.ColumnId{
 					*ast.NewColumnId("id"),
 				}},
-				&ast.ConstraintUniqueKeyDef{Column: *ast.NewColumnId("user_id")},
+				&ast.ConstraintUniqueKeyDef{Columns: []ast.ColumnId{*ast.NewColumnId("user_id")}},
 			},
 		})
 		executePlan(t, &ast.InsertStmt{
@@ -790,7 +822,7 @@ func TestPlannerIntegration(t *testing.T) {
 				&ast.ColumnDef{ColName: "name", DataType: ast.DataTypeVarchar},
 				&ast.ColumnDef{ColName: "category", DataType: ast.DataTypeVarchar},
 				&ast.ConstraintPrimaryKeyDef{Columns: []ast.ColumnId{*ast.NewColumnId("id")}},
-				&ast.ConstraintKeyDef{KeyName: "idx_category", Column: *ast.NewColumnId("category")},
+				&ast.ConstraintKeyDef{KeyName: "idx_category", Columns: []ast.ColumnId{*ast.NewColumnId("category")}},
 			},
 		})
 		executePlan(t, &ast.InsertStmt{
@@ -0,0 +1,53 @@
+package planner
+
+import (
+	"github.com/ren-yamanashi/minesql/internal/ast"
+	"github.com/ren-yamanashi/minesql/internal/executor"
+)
+
+// planOrderBy は ORDER BY に応じて Sort を重ねる
+//
+// sortOrder は exec が返すレコードの並び順 (昇順に並ぶカラムの位置) で、keys がその並び順で満たされる場合は Sort を省略する
+func planOrderBy(exec executor.Executor, keys []executor.SortKey, sortOrder []int) executor.Executor {
+	if len(keys) == 0 || isSortedBy(keys, sortOrder) {
+		return exec
+	}
+	return executor.NewSort(exec, keys)
+}
+
+// planLimit は LIMIT 句に応じて Limit を重ねる
+func planLimit(exec executor.Executor, limit *ast.LimitClause) executor.Executor {
+	if limit == nil {
+		return exec
+	}
+	return executor.NewLimit(exec, limit.Count, limit.Offset)
+}
+
+// buildSortKeys は ORDER BY のカラムをレコード内の位置に変換し、ソートキーを構築する
+func buildSortKeys(orderBy []ast.OrderByItem, columns []joinedColumn) ([]executor.SortKey, error) {
+	keys := make([]executor.SortKey, len(orderBy))
+	for i, item := range orderBy {
+		pos, err := findColumnPos(columns, item.Column.TableName, item.Column.ColName)
+		if err != nil {
+			return nil, err
+		}
+		keys[i] = executor.SortKey{Pos: pos, Desc: item.Desc}
+	}
+	return keys, nil
+}
+
+// isSortedBy はレコードが sortOrder の順に並んでいる場合に、keys の順にも並んでいるかを判定する
+//
+// keys がすべて昇順で、sortOrder の先頭から順に一致する場合に true を返す
+// (B+Tree は前方向にしか走査しないため、降順のキーは必ず Sort で並べ替える)
+func isSortedBy(keys []executor.SortKey, sortOrder []int) bool {
+	if len(keys) > len(sortOrder) {
+		return false
+	}
+	for i, key := range keys {
+		if key.Desc || key.Pos != sortOrder[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,260 @@
+package planner
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/ren-yamanashi/minesql/internal/ast"
+	"github.com/ren-yamanashi/minesql/internal/executor"
+	"github.com/ren-yamanashi/minesql/internal/storage/handler"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlanSelectOrderBy(t *testing.T) {
+	t.Run("PK の昇順で並べる場合は Sort を使わずにテーブルスキャンの順序で返す", func(t *testing.T) {
+		// GIVEN
+		setupUsersTable(t)
+		defer handler.Reset()
+
+		stmt := &ast.SelectStmt{
+			From:    *ast.NewTableId("users"),
+			OrderBy: []ast.OrderByItem{{Column: *ast.NewColumnId("id")}},
+		}
+
+		// WHEN
+		plan, err := PlanSelect(0, stmt)
+		require.NoError(t, err)
+		results := fetchAll(t, plan.Exec)
+
+		// THEN
+		proj := plan.Exec.(*executor.Project)
+		assert.IsType(t, &executor.TableScan{}, proj.InnerExecutor)
+		assert.Equal(t, []string{"1", "2", "3", "4", "5", "6"}, columnStrings(results, 0))
+	})
+
+	t.Run("インデックススキャンのキーで並べる場合は Sort を使わない", func(t *testing.T) {
+		// GIVEN
+		setupUsersTable(t)
+		defer handler.Reset()
+
+		stmt := &ast.SelectStmt{
+			From: *ast.NewTableId("users"),
+			Where: &ast.WhereClause{
+				Condition: ast.NewBinaryExpr(
+					"=",
+					ast.NewLhsColumn(*ast.NewColumnId("username")),
+					ast.NewRhsLiteral(ast.NewStringLiteral("janedoe")),
+				),
+			},
+			OrderBy: []ast.OrderByItem{{Column: *ast.NewColumnId("username")}},
+		}
+
+		// WHEN
+		plan, err := PlanSelect(0, stmt)
+
+		// THEN
+		require.NoError(t, err)
+		proj := plan.Exec.(*executor.Project)
+		assert.IsType(t, &executor.IndexScan{}, proj.InnerExecutor)
+	})
+
+	t.Run("アクセスパスの順序と異なる場合は Sort で並べ替える", func(t *testing.T) {
+		// GIVEN
+		setupUsersTable(t)
+		defer handler.Reset()
+
+		stmt := &ast.SelectStmt{
+			Columns: []ast.ColumnId{*ast.NewColumnId("id")},
+			From:    *ast.NewTableId("users"),
+			OrderBy: []ast.OrderByItem{
+				{Column: *ast.NewColumnId("first_name"), Desc: true},
+				{Column: *ast.NewColumnId("last_name")},
+			},
+		}
+
+		// WHEN
+		plan, err := PlanSelect(0, stmt)
+		require.NoError(t, err)
+		results := fetchAll(t, plan.Exec)
+
+		// THEN: SELECT で指定していないカラムでも並べ替えられる
+		proj := plan.Exec.(*executor.Project)
+		assert.IsType(t, &executor.Sort{}, proj.InnerExecutor)
+		assert.Equal(t, []string{"6", "5", "1", "2", "3", "4"}, columnStrings(results, 0))
+	})
+
+	t.Run("PK の降順で並べる場合は Sort で並べ替える", func(t *testing.T) {
+		// GIVEN
+		setupUsersTable(t)
+		defer handler.Reset()
+
+		stmt := &ast.SelectStmt{
+			From:    *ast.NewTableId("users"),
+			OrderBy: []ast.OrderByItem{{Column: *ast.NewColumnId("id"), Desc: true}},
+		}
+
+		// WHEN
+		plan, err := PlanSelect(0, stmt)
+		require.NoError(t, err)
+		results := fetchAll(t, plan.Exec)
+
+		// THEN
+		proj := plan.Exec.(*executor.Project)
+		assert.IsType(t, &executor.Sort{}, proj.InnerExecutor)
+		assert.Equal(t, []string{"6", "5", "4", "3", "2", "1"}, columnStrings(results, 0))
+	})
+
+	t.Run("存在しないカラムで並べようとした場合はエラーになる", func(t *testing.T) {
+		// GIVEN
+		setupUsersTable(t)
+		defer handler.Reset()
+
+		stmt := &ast.SelectStmt{
+			From:    *ast.NewTableId("users"),
+			OrderBy: []ast.OrderByItem{{Column: *ast.NewColumnId("non_existent")}},
+		}
+
+		// WHEN
+		plan, err := PlanSelect(0, stmt)
+
+		// THEN
+		assert.Nil(t, plan)
+		assert.ErrorContains(t, err, "column non_existent not found")
+	})
+}
+
+func TestPlanSelectLimit(t *testing.T) {
+	// products (id PK, category) に 10 カテゴリ × 20 行を挿入し、KEY idx_category (category) を作成する
+	setupManyProducts := func(t *testing.T) {
+		t.Helper()
+		initStorageManagerForTest(t)
+		executePlan(t, &ast.CreateTableStmt{
+			TableName: "products",
+			CreateDefinitions: []ast.Definition{
+				&ast.ColumnDef{ColName: "id", DataType: ast.DataTypeVarchar},
+				&ast.ColumnDef{ColName: "category", DataType: ast.DataTypeVarchar},
+				&ast.ConstraintPrimaryKeyDef{Columns: []ast.ColumnId{*ast.NewColumnId("id")}},
+				&ast.ConstraintKeyDef{KeyName: "idx_category", Columns: []ast.ColumnId{*ast.NewColumnId("category")}},
+			},
+		})
+		var values [][]ast.Literal
+		for i := range 200 {
+			values = append(values, []ast.Literal{
+				ast.NewStringLiteral(fmt.Sprintf("%03d", i)),
+				ast.NewStringLiteral(fmt.Sprintf("c%02d", i%10)),
+			})
+		}
+		executePlan(t, &ast.InsertStmt{
+			Table:  *ast.NewTableId("products"),
+			Cols:   []ast.ColumnId{*ast.NewColumnId("id"), *ast.NewColumnId("category")},
+			Values: values,
+		})
+	}
+	selectByCategory := func(limit *ast.LimitClause) *ast.SelectStmt {
+		return &ast.SelectStmt{
+			From: *ast.NewTableId("products"),
+			Where: &ast.WhereClause{
+				Condition: ast.NewBinaryExpr(
+					"=",
+					ast.NewLhsColumn(*ast.NewColumnId("category")),
+					ast.NewRhsLiteral(ast.NewStringLiteral("c05")),
+				),
+			},
+			OrderBy: []ast.OrderByItem{{Column: *ast.NewColumnId("id")}},
+			Limit:   limit,
+		}
+	}
+
+	t.Run("LIMIT がない場合はインデックススキャンの結果を Sort で並べ替える", func(t *testing.T) {
+		// GIVEN
+		setupManyProducts(t)
+		defer handler.Reset()
+
+		// WHEN
+		plan, err := PlanSelect(0, selectByCategory(nil))
+		require.NoError(t, err)
+		results := fetchAll(t, plan.Exec)
+
+		// THEN
+		proj := plan.Exec.(*executor.Project)
+		assert.IsType(t, &executor.Sort{}, proj.InnerExecutor)
+		assert.Len(t, results, 20)
+	})
+
+	t.Run("ORDER BY pk LIMIT n の場合は Sort を使わず、PK 順のテーブルスキャンを n 行で打ち切る", func(t *testing.T) {
+		// GIVEN
+		setupManyProducts(t)
+		defer handler.Reset()
+
+		// WHEN
+		plan, err := PlanSelect(0, selectByCategory(&ast.LimitClause{Count: 2, Offset: 1}))
+		require.NoError(t, err)
+		results := fetchAll(t, plan.Exec)
+
+		// THEN
+		proj := plan.Exec.(*executor.Project)
+		limit, ok := proj.InnerExecutor.(*executor.Limit)
+		require.True(t, ok)
+		assert.IsType(t, &executor.Filter{}, limit.InnerExecutor)
+		assert.Equal(t, []string{"015", "025"}, columnStrings(results, 0))
+	})
+
+	t.Run("並べ替えが必要な場合は Sort の結果に LIMIT を適用する", func(t *testing.T) {
+		// GIVEN
+		setupUsersTable(t)
+		defer handler.Reset()
+
+		stmt := &ast.SelectStmt{
+			From:    *ast.NewTableId("users"),
+			OrderBy: []ast.OrderByItem{{Column: *ast.NewColumnId("id"), Desc: true}},
+			Limit:   &ast.LimitClause{Count: 2},
+		}
+
+		// WHEN
+		plan, err := PlanSelect(0, stmt)
+		require.NoError(t, err)
+		results := fetchAll(t, plan.Exec)
+
+		// THEN
+		proj := plan.Exec.(*executor.Project)
+		limit, ok := proj.InnerExecutor.(*executor.Limit)
+		require.True(t, ok)
+		assert.IsType(t, &executor.Sort{}, limit.InnerExecutor)
+		assert.Equal(t, []string{"6", "5"}, columnStrings(results, 0))
+	})
+}
+
+func TestIsSortedBy(t *testing.T) {
+	tests := []struct {
+		name      string
+		keys      []executor.SortKey
+		sortOrder []int
+		expected  bool
+	}{
+		{"並び順の先頭と一致する場合は true", []executor.SortKey{{Pos: 1}}, []int{1, 0}, true},
+		{"並び順と完全に一致する場合は true", []executor.SortKey{{Pos: 1}, {Pos: 0}}, []int{1, 0}, true},
+		{"先頭以外のカラムから始まる場合は false", []executor.SortKey{{Pos: 0}}, []int{1, 0}, false},
+		{"降順のキーを含む場合は false", []executor.SortKey{{Pos: 1, Desc: true}}, []int{1, 0}, false},
+		{"並び順より多くのキーがある場合は false", []executor.SortKey{{Pos: 0}, {Pos: 2}}, []int{0}, false},
+		{"並び順が保証されない場合は false", []executor.SortKey{{Pos: 0}}, nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// WHEN
+			result := isSortedBy(tt.keys, tt.sortOrder)
+
+			// THEN
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+// columnStrings はレコード群から指定位置のカラム値を文字列として取り出す
+func columnStrings(records []executor.Record, pos int) []string {
+	values := make([]string, len(records))
+	for i, record := range records {
+		values[i] = string(record[pos])
+	}
+	return values
+}
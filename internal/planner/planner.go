@@ -5,6 +5,7 @@ import (
 
 	"github.com/ren-yamanashi/minesql/internal/ast"
 	"github.com/ren-yamanashi/minesql/internal/executor"
+	"github.com/ren-yamanashi/minesql/internal/storage/dictionary"
 	"github.com/ren-yamanashi/minesql/internal/storage/handler"
 )
 
@@ -12,12 +13,43 @@ import (
 type ColumnMeta struct {
 	TableName string
 	ColName   string
+	Type      handler.ColumnType // カラムのデータ型
+	Precision uint8              // DECIMAL の精度 (DECIMAL 以外では 0)
+	Scale     uint8              // DECIMAL のスケール (DECIMAL 以外では 0)
+}
+
+// newColumnMeta はテーブルのカラムメタデータから結果セットのカラムメタデータを作成する
+func newColumnMeta(tableName string, col *handler.ColumnMetadata) ColumnMeta {
+	return ColumnMeta{
+		TableName: tableName,
+		ColName:   col.Name,
+		Type:      col.Type,
+		Precision: col.Precision,
+		Scale:     col.Scale,
+	}
+}
+
+// metadata は結果セットのカラムのデータ型をカラムメタデータとして返す
+func (cm ColumnMeta) metadata() *handler.ColumnMetadata {
+	return &handler.ColumnMetadata{
+		Name:      cm.ColName,
+		Type:      cm.Type,
+		Precision: cm.Precision,
+		Scale:     cm.Scale,
+	}
+}
+
+// FormatValue は結果セットの値 (格納形式) をテキスト表現に変換する
+func (cm ColumnMeta) FormatValue(value []byte) string {
+	return dictionary.FormatValue(cm.Type, cm.Scale, value)
 }
 
 // PlanResult はプランナーの実行結果
 type PlanResult struct {
 	Exec    executor.Executor // クエリ実行のための Executor
 	Columns []ColumnMeta      // SELECT の場合のみ設定。それ以外は nil
+
+	sortOrder []int // Exec が返すレコードの並び順 (昇順に並ぶ結果セットのカラムの位置)。順序が保証されない場合は nil
 }
 
 func Start(trxId handler.TrxId, stmt ast.Statement) (*PlanResult, error) {
@@ -30,6 +62,8 @@ func Start(trxId handler.TrxId, stmt ast.Statement) (*PlanResult, error) {
 		return &PlanResult{Exec: exec}, err
 	case *ast.SelectStmt:
 		return PlanSelect(trxId, s)
+	case *ast.SetOperationStmt:
+		return PlanSetOperation(trxId, s)
 	case *ast.DeleteStmt:
 		exec, err := PlanDelete(trxId, s)
 		return &PlanResult{Exec: exec}, err
@@ -39,6 +73,35 @@ func Start(trxId handler.TrxId, stmt ast.Statement) (*PlanResult, error) {
 	case *ast.AlterUserStmt:
 		exec, err := PlanAlterUser(s)
 		return &PlanResult{Exec: exec}, err
+	case *ast.AlterTableStmt:
+		exec, err := PlanAlterTable(trxId, s)
+		return &PlanResult{Exec: exec}, err
+	case *ast.CreateIndexStmt:
+		exec, err := PlanCreateIndex(trxId, s)
+		return &PlanResult{Exec: exec}, err
+	case *ast.DropIndexStmt:
+		exec, err := PlanDropIndex(trxId, s)
+		return &PlanResult{Exec: exec}, err
+	case *ast.DropTableStmt:
+		exec, err := PlanDropTable(trxId, s)
+		return &PlanResult{Exec: exec}, err
+	case *ast.CreateViewStmt:
+		exec, err := PlanCreateView(trxId, s)
+		return &PlanResult{Exec: exec}, err
+	case *ast.DropViewStmt:
+		exec, err := PlanDropView(s)
+		return &PlanResult{Exec: exec}, err
+	case *ast.CreateDatabaseStmt:
+		exec, err := PlanCreateDatabase(s)
+		return &PlanResult{Exec: exec}, err
+	case *ast.DropDatabaseStmt:
+		exec, err := PlanDropDatabase(trxId, s)
+		return &PlanResult{Exec: exec}, err
+	case *ast.TruncateTableStmt:
+		exec, err := PlanTruncateTable(trxId, s)
+		return &PlanResult{Exec: exec}, err
+	case *ast.ExplainStmt:
+		return PlanExplain(trxId, s)
 	default:
 		return nil, fmt.Errorf("unsupported statement: %T", s)
 	}
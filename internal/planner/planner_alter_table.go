@@ -0,0 +1,156 @@
+package planner
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ren-yamanashi/minesql/internal/ast"
+	"github.com/ren-yamanashi/minesql/internal/executor"
+	"github.com/ren-yamanashi/minesql/internal/parser"
+	"github.com/ren-yamanashi/minesql/internal/storage/dictionary"
+	"github.com/ren-yamanashi/minesql/internal/storage/handler"
+)
+
+// PlanAlterTable は ALTER TABLE 文のバリデーションを行い、AlterTable executor を構築する
+func PlanAlterTable(trxId handler.TrxId, stmt *ast.AlterTableStmt) (executor.Executor, error) {
+	hdl := handler.Get()
+
+	tblMeta, ok := hdl.Catalog.GetTableMetaByName(stmt.TableName)
+	if !ok {
+		return nil, fmt.Errorf("table %s not found", stmt.TableName)
+	}
+
+	switch action := stmt.Action.(type) {
+	case *ast.AddColumnAction:
+		if _, exists := tblMeta.GetColByName(action.Column.ColName); exists {
+			return nil, errors.New("duplicate column name: " + action.Column.ColName)
+		}
+		colParam, err := getColumnParam(action.Column)
+		if err != nil {
+			return nil, err
+		}
+		// 追加したカラムはテーブルの末尾に置かれ、プライマリキーの先頭のカラムにはならない
+		if colParam.AutoIncrement {
+			return nil, fmt.Errorf("AUTO_INCREMENT column '%s' must be the first column of the primary key", colParam.Name)
+		}
+		// 既存の行には DEFAULT 句の式を評価した値 (colParam.DefaultValue) を設定する
+		if err := validateDefault(colParam); err != nil {
+			return nil, err
+		}
+		return executor.NewAddColumn(trxId, stmt.TableName, colParam), nil
+
+	case *ast.DropColumnAction:
+		if err := checkColumnNotInCheckConstraint(tblMeta, action.ColName); err != nil {
+			return nil, err
+		}
+		return executor.NewDropColumn(trxId, stmt.TableName, action.ColName), nil
+
+	case *ast.AddConstraintAction:
+		return planAddConstraint(trxId, tblMeta, action.Constraint)
+
+	case *ast.DropIndexAction:
+		return executor.NewDropIndex(trxId, stmt.TableName, action.IndexName), nil
+
+	case *ast.DropForeignKeyAction:
+		return executor.NewDropForeignKey(trxId, stmt.TableName, action.ConstraintName), nil
+
+	default:
+		return nil, fmt.Errorf("unsupported ALTER TABLE action: %T", action)
+	}
+}
+
+// planAddConstraint は ADD UNIQUE KEY / ADD KEY / ADD FOREIGN KEY のバリデーションを行い、AlterTable executor を構築する
+//
+// CREATE TABLE と同じ検証を、既存のテーブル定義に対して行う
+func planAddConstraint(trxId handler.TrxId, tblMeta *dictionary.TableMeta, def ast.Definition) (executor.Executor, error) {
+	colIndexMap := map[string]int{} // key: column name, value: column index
+	colParams := make([]handler.CreateColumnParam, 0, len(tblMeta.Cols))
+	for _, col := range tblMeta.GetSortedCols() {
+		colIndexMap[col.Name] = int(col.Pos)
+		colParams = append(colParams, handler.CreateColumnParam{
+			Name:      col.Name,
+			Type:      col.Type,
+			Precision: col.Precision,
+			Scale:     col.Scale,
+			NotNull:   col.NotNull,
+		})
+	}
+	idxKeyNames := map[string]bool{} // 登録済みのインデックス名と制約名
+	var idxColNames [][]string       // 登録済みのインデックスのカラム名
+	for _, idx := range tblMeta.Indexes {
+		idxKeyNames[idx.Name] = true
+		idxColNames = append(idxColNames, idx.ColNames)
+	}
+	for _, con := range tblMeta.Constraints {
+		idxKeyNames[con.ConstraintName] = true
+	}
+
+	switch def := def.(type) {
+	case *ast.ConstraintUniqueKeyDef:
+		idxParam, err := getAddIndexParam(def.KeyName, def.Columns, true, colIndexMap, idxKeyNames)
+		if err != nil {
+			return nil, err
+		}
+		return executor.NewAddIndex(trxId, tblMeta.Name, idxParam), nil
+
+	case *ast.ConstraintKeyDef:
+		idxParam, err := getAddIndexParam(def.KeyName, def.Columns, false, colIndexMap, idxKeyNames)
+		if err != nil {
+			return nil, err
+		}
+		return executor.NewAddIndex(trxId, tblMeta.Name, idxParam), nil
+
+	case *ast.ConstraintForeignKeyDef:
+		conParams, err := getForeignKeyParams(tblMeta.Name, nil, []*ast.ConstraintForeignKeyDef{def}, colParams, colIndexMap, idxKeyNames, idxColNames)
+		if err != nil {
+			return nil, err
+		}
+		for _, con := range tblMeta.GetCheckConstraints() {
+			expr, err := parser.ParseExpr(con.CheckExpr)
+			if err != nil {
+				return nil, err
+			}
+			if err := checkColumnNotInFKAction(con.ConstraintName, expr, conParams); err != nil {
+				return nil, err
+			}
+		}
+		return executor.NewAddForeignKey(trxId, tblMeta.Name, conParams[0]), nil
+
+	default:
+		return nil, fmt.Errorf("unsupported constraint in ALTER TABLE: %T", def)
+	}
+}
+
+// getAddIndexParam は追加するインデックスの定義を検証し、インデックスのパラメータを返す
+//
+// 以下の場合はエラーを返す
+//   - インデックス名が既存のインデックス名・制約名と重複する場合
+//   - インデックスのカラムがテーブルのカラムに存在しない場合
+//   - 1 つのインデックスに同じカラムが複数回指定されている場合
+func getAddIndexParam(keyName string, cols []ast.ColumnId, unique bool, colIndexMap map[string]int, idxKeyNames map[string]bool) (handler.CreateIndexParam, error) {
+	if idxKeyNames[keyName] {
+		return handler.CreateIndexParam{}, errors.New("duplicate index name: " + keyName)
+	}
+	param, err := getIndexParam(keyName, cols, false, colIndexMap)
+	if err != nil {
+		return handler.CreateIndexParam{}, err
+	}
+	param.Unique = unique
+	return param, nil
+}
+
+// checkColumnNotInCheckConstraint は削除するカラムが CHECK 制約の条件式で使われていないことを確認する
+func checkColumnNotInCheckConstraint(tblMeta *dictionary.TableMeta, colName string) error {
+	for _, con := range tblMeta.GetCheckConstraints() {
+		expr, err := parser.ParseExpr(con.CheckExpr)
+		if err != nil {
+			return err
+		}
+		for _, col := range collectColumns(expr) {
+			if col.ColName == colName {
+				return fmt.Errorf("cannot drop column %s: used by check constraint %s", colName, con.ConstraintName)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,197 @@
+package planner
+
+import (
+	"testing"
+
+	"github.com/ren-yamanashi/minesql/internal/ast"
+	"github.com/ren-yamanashi/minesql/internal/executor"
+	"github.com/ren-yamanashi/minesql/internal/storage/handler"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlanAlterTable(t *testing.T) {
+	t.Run("存在しないテーブルの場合エラーを返す", func(t *testing.T) {
+		// GIVEN
+		setupUsersTable(t)
+		defer handler.Reset()
+		stmt := &ast.AlterTableStmt{TableName: "orders", Action: &ast.DropColumnAction{ColName: "user_id"}}
+
+		// WHEN
+		exec, err := PlanAlterTable(0, stmt)
+
+		// THEN
+		assert.Nil(t, exec)
+		assert.ErrorContains(t, err, "table orders not found")
+	})
+
+	t.Run("各変更に対して AlterTable executor を返す", func(t *testing.T) {
+		setupOrdersTable(t)
+		defer handler.Reset()
+
+		tests := []struct {
+			name string
+			stmt *ast.AlterTableStmt
+		}{
+			{"ADD COLUMN", &ast.AlterTableStmt{TableName: "users", Action: &ast.AddColumnAction{
+				Column: &ast.ColumnDef{ColName: "email", DataType: ast.DataTypeVarchar},
+			}}},
+			{"DROP COLUMN", &ast.AlterTableStmt{TableName: "users", Action: &ast.DropColumnAction{ColName: "gender"}}},
+			{"ADD UNIQUE KEY", &ast.AlterTableStmt{TableName: "users", Action: &ast.AddConstraintAction{
+				Constraint: &ast.ConstraintUniqueKeyDef{KeyName: "uk_last_name", Columns: []ast.ColumnId{*ast.NewColumnId("last_name")}},
+			}}},
+			{"ADD KEY", &ast.AlterTableStmt{TableName: "users", Action: &ast.AddConstraintAction{
+				Constraint: &ast.ConstraintKeyDef{KeyName: "idx_first_name", Columns: []ast.ColumnId{*ast.NewColumnId("first_name")}},
+			}}},
+			{"ADD FOREIGN KEY", &ast.AlterTableStmt{TableName: "orders", Action: &ast.AddConstraintAction{
+				Constraint: &ast.ConstraintForeignKeyDef{KeyName: "fk_user", Columns: []ast.ColumnId{*ast.NewColumnId("user_id")}, RefTable: "users", RefColumns: []string{"id"}},
+			}}},
+			{"DROP INDEX", &ast.AlterTableStmt{TableName: "orders", Action: &ast.DropIndexAction{IndexName: "idx_user_id"}}},
+			{"DROP FOREIGN KEY", &ast.AlterTableStmt{TableName: "orders", Action: &ast.DropForeignKeyAction{ConstraintName: "fk_user"}}},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				// WHEN
+				exec, err := PlanAlterTable(0, tt.stmt)
+
+				// THEN
+				assert.NoError(t, err)
+				assert.IsType(t, &executor.AlterTable{}, exec)
+			})
+		}
+	})
+
+	t.Run("不正な変更の場合エラーを返す", func(t *testing.T) {
+		setupOrdersTable(t)
+		defer handler.Reset()
+
+		tests := []struct {
+			name     string
+			stmt     *ast.AlterTableStmt
+			expected string
+		}{
+			{"既存のカラム名を追加する場合", &ast.AlterTableStmt{TableName: "users", Action: &ast.AddColumnAction{
+				Column: &ast.ColumnDef{ColName: "gender", DataType: ast.DataTypeVarchar},
+			}}, "duplicate column name: gender"},
+			{"DECIMAL の精度が範囲外の場合", &ast.AlterTableStmt{TableName: "users", Action: &ast.AddColumnAction{
+				Column: &ast.ColumnDef{ColName: "price", DataType: ast.DataTypeDecimal, Precision: 30},
+			}}, "invalid DECIMAL(30, 0) for column 'price'"},
+			{"AUTO_INCREMENT カラムを追加する場合", &ast.AlterTableStmt{TableName: "users", Action: &ast.AddColumnAction{
+				Column: &ast.ColumnDef{ColName: "seq", DataType: ast.DataTypeInt, AutoIncrement: true},
+			}}, "AUTO_INCREMENT column 'seq' must be the first column of the primary key"},
+			{"既存のインデックス名を追加する場合", &ast.AlterTableStmt{TableName: "orders", Action: &ast.AddConstraintAction{
+				Constraint: &ast.ConstraintKeyDef{KeyName: "idx_user_id", Columns: []ast.ColumnId{*ast.NewColumnId("id")}},
+			}}, "duplicate index name: idx_user_id"},
+			{"インデックスのカラムが存在しない場合", &ast.AlterTableStmt{TableName: "users", Action: &ast.AddConstraintAction{
+				Constraint: &ast.ConstraintKeyDef{KeyName: "idx_email", Columns: []ast.ColumnId{*ast.NewColumnId("email")}},
+			}}, "key column 'email' does not exist"},
+			{"インデックスに同じカラムを複数回指定した場合", &ast.AlterTableStmt{TableName: "orders", Action: &ast.AddConstraintAction{
+				Constraint: &ast.ConstraintUniqueKeyDef{KeyName: "uk_user_id", Columns: []ast.ColumnId{*ast.NewColumnId("user_id"), *ast.NewColumnId("user_id")}},
+			}}, "duplicate column 'user_id' in index 'uk_user_id'"},
+			{"外部キーのカラムにインデックスがない場合", &ast.AlterTableStmt{TableName: "orders", Action: &ast.AddConstraintAction{
+				Constraint: &ast.ConstraintForeignKeyDef{KeyName: "fk_user", Columns: []ast.ColumnId{*ast.NewColumnId("status")}, RefTable: "users", RefColumns: []string{"id"}},
+			}}, "foreign key column 'status' must have an index"},
+			{"外部キーの参照先テーブルが存在しない場合", &ast.AlterTableStmt{TableName: "orders", Action: &ast.AddConstraintAction{
+				Constraint: &ast.ConstraintForeignKeyDef{KeyName: "fk_user", Columns: []ast.ColumnId{*ast.NewColumnId("user_id")}, RefTable: "members", RefColumns: []string{"id"}},
+			}}, "referenced table 'members' does not exist"},
+			{"NOT NULL のカラムに DEFAULT NULL を指定して追加する場合", &ast.AlterTableStmt{TableName: "users", Action: &ast.AddColumnAction{
+				Column: &ast.ColumnDef{ColName: "age", DataType: ast.DataTypeInt, NotNull: true, Default: ast.NewNullLiteral(), DefaultText: "NULL"},
+			}}, "invalid default value for column 'age'"},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				// WHEN
+				exec, err := PlanAlterTable(0, tt.stmt)
+
+				// THEN
+				assert.Nil(t, exec)
+				assert.ErrorContains(t, err, tt.expected)
+			})
+		}
+	})
+
+	t.Run("実行するとテーブル定義が変更される", func(t *testing.T) {
+		// GIVEN
+		setupUsersTable(t)
+		defer handler.Reset()
+
+		// WHEN
+		executePlan(t, &ast.AlterTableStmt{TableName: "users", Action: &ast.AddColumnAction{
+			Column: &ast.ColumnDef{ColName: "email", DataType: ast.DataTypeVarchar},
+		}})
+
+		// THEN
+		records := executePlan(t, &ast.SelectStmt{From: *ast.NewTableId("users")})
+		assert.Equal(t, 6, len(records))
+		for _, record := range records {
+			assert.Equal(t, 6, len(record))
+			assert.Nil(t, record[5])
+		}
+	})
+
+	t.Run("DEFAULT 句を指定してカラムを追加すると既存の行に DEFAULT 値が設定される", func(t *testing.T) {
+		// GIVEN
+		setupUsersTable(t)
+		defer handler.Reset()
+
+		// WHEN
+		executePlan(t, &ast.AlterTableStmt{TableName: "users", Action: &ast.AddColumnAction{
+			Column: &ast.ColumnDef{ColName: "status", DataType: ast.DataTypeVarchar, NotNull: true, Default: ast.NewStringLiteral("active"), DefaultText: "'active'"},
+		}})
+
+		// THEN
+		records := executePlan(t, &ast.SelectStmt{From: *ast.NewTableId("users")})
+		assert.Equal(t, 6, len(records))
+		for _, record := range records {
+			assert.Equal(t, "active", string(record[5]))
+		}
+	})
+
+	t.Run("CHECK 制約の条件式で使われているカラムは削除できない", func(t *testing.T) {
+		// GIVEN
+		setupUsersTable(t)
+		defer handler.Reset()
+		executePlan(t, &ast.CreateTableStmt{
+			TableName: "items",
+			CreateDefinitions: []ast.Definition{
+				&ast.ColumnDef{ColName: "id", DataType: ast.DataTypeInt},
+				&ast.ColumnDef{ColName: "price", DataType: ast.DataTypeInt},
+				&ast.ConstraintPrimaryKeyDef{Columns: []ast.ColumnId{*ast.NewColumnId("id")}},
+				&ast.ConstraintCheckDef{KeyName: "chk_price", Expr: parseExprForTest(t, "price > 0"), ExprText: "price > 0"},
+			},
+		})
+		stmt := &ast.AlterTableStmt{TableName: "items", Action: &ast.DropColumnAction{ColName: "price"}}
+
+		// WHEN
+		exec, err := PlanAlterTable(0, stmt)
+
+		// THEN
+		assert.Nil(t, exec)
+		assert.EqualError(t, err, "cannot drop column price: used by check constraint chk_price")
+	})
+
+	t.Run("CHECK 制約の条件式で使われているカラムに参照アクション付きの FK は追加できない", func(t *testing.T) {
+		// GIVEN
+		setupUsersTable(t)
+		defer handler.Reset()
+		executePlan(t, &ast.CreateTableStmt{
+			TableName: "employees",
+			CreateDefinitions: []ast.Definition{
+				&ast.ColumnDef{ColName: "id", DataType: ast.DataTypeInt},
+				&ast.ColumnDef{ColName: "manager_id", DataType: ast.DataTypeInt},
+				&ast.ConstraintPrimaryKeyDef{Columns: []ast.ColumnId{*ast.NewColumnId("id")}},
+				&ast.ConstraintKeyDef{KeyName: "idx_manager_id", Columns: []ast.ColumnId{*ast.NewColumnId("manager_id")}},
+				&ast.ConstraintCheckDef{KeyName: "chk_manager", Expr: parseExprForTest(t, "manager_id <> id"), ExprText: "manager_id <> id"},
+			},
+		})
+		stmt := &ast.AlterTableStmt{TableName: "employees", Action: &ast.AddConstraintAction{
+			Constraint: &ast.ConstraintForeignKeyDef{KeyName: "fk_manager", Columns: []ast.ColumnId{*ast.NewColumnId("manager_id")}, RefTable: "employees", RefColumns: []string{"id"}, OnDelete: ast.ReferentialActionSetNull},
+		}}
+
+		// WHEN
+		exec, err := PlanAlterTable(0, stmt)
+
+		// THEN
+		assert.Nil(t, exec)
+		assert.EqualError(t, err, "column 'manager_id' cannot be used in a check constraint 'chk_manager': needed in a foreign key constraint 'fk_manager' referential action")
+	})
+}
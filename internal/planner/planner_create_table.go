@@ -3,6 +3,8 @@ package planner
 import (
 	"errors"
 	"fmt"
+	"slices"
+	"strings"
 
 	"github.com/ren-yamanashi/minesql/internal/ast"
 	"github.com/ren-yamanashi/minesql/internal/executor"
@@ -19,8 +21,9 @@ func PlanCreateTable(stmt *ast.CreateTableStmt) (executor.Executor, error) {
 	var ukDefs []*ast.ConstraintUniqueKeyDef
 	var keyDefs []*ast.ConstraintKeyDef
 	var fkDefs []*ast.ConstraintForeignKeyDef
+	var checkDefs []*ast.ConstraintCheckDef
 	idxKeyNames := map[string]bool{} // 登録済みのインデックス名 (UK/KEY/FK 名との重複チェックにも使用)
-	idxColNames := map[string]bool{} // 登録済みのインデックスカラム名 (UK/KEY 共通)
+	var idxColNames [][]string       // 登録済みのインデックスのカラム名 (UK/KEY 共通。FK カラムのインデックスの確認に使用)
 	currentColIdx := 0
 
 	// テーブル定義の検証とカラム・インデックスの収集
@@ -33,10 +36,11 @@ func PlanCreateTable(stmt *ast.CreateTableStmt) (executor.Executor, error) {
 			}
 			colIndexMap[def.ColName] = currentColIdx
 			currentColIdx++
-			colParams = append(colParams, handler.CreateColumnParam{
-				Name: def.ColName,
-				Type: handler.ColumnType(def.DataType),
-			})
+			colParam, err := getColumnParam(def)
+			if err != nil {
+				return nil, err
+			}
+			colParams = append(colParams, colParam)
 
 		case *ast.ConstraintPrimaryKeyDef:
 			// プライマリキー定義の検証
@@ -50,11 +54,8 @@ func PlanCreateTable(stmt *ast.CreateTableStmt) (executor.Executor, error) {
 			if _, exists := idxKeyNames[def.KeyName]; exists {
 				return nil, errors.New("duplicate index name: " + def.KeyName)
 			}
-			if _, exists := idxColNames[def.Column.ColName]; exists {
-				return nil, errors.New("column '" + def.Column.ColName + "' cannot have multiple indexes")
-			}
 			idxKeyNames[def.KeyName] = true
-			idxColNames[def.Column.ColName] = true
+			idxColNames = append(idxColNames, columnNames(def.Columns))
 			ukDefs = append(ukDefs, def)
 
 		case *ast.ConstraintKeyDef:
@@ -62,15 +63,15 @@ func PlanCreateTable(stmt *ast.CreateTableStmt) (executor.Executor, error) {
 			if _, exists := idxKeyNames[def.KeyName]; exists {
 				return nil, errors.New("duplicate index name: " + def.KeyName)
 			}
-			if _, exists := idxColNames[def.Column.ColName]; exists {
-				return nil, errors.New("column '" + def.Column.ColName + "' cannot have multiple indexes")
-			}
 			idxKeyNames[def.KeyName] = true
-			idxColNames[def.Column.ColName] = true
+			idxColNames = append(idxColNames, columnNames(def.Columns))
 			keyDefs = append(keyDefs, def)
 
 		case *ast.ConstraintForeignKeyDef:
 			fkDefs = append(fkDefs, def)
+
+		case *ast.ConstraintCheckDef:
+			checkDefs = append(checkDefs, def)
 		}
 	}
 
@@ -84,19 +85,108 @@ func PlanCreateTable(stmt *ast.CreateTableStmt) (executor.Executor, error) {
 		return nil, err
 	}
 
+	// プライマリキーのカラムは暗黙的に NOT NULL になる (プライマリキーは先頭から連続している)
+	for i := range pkCount {
+		colParams[i].NotNull = true
+	}
+
+	for _, colParam := range colParams {
+		if err := validateDefault(colParam); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := validateAutoIncrement(colParams); err != nil {
+		return nil, err
+	}
+
 	idxParams, err := getIndexParams(ukDefs, keyDefs, colIndexMap)
 	if err != nil {
 		return nil, err
 	}
 
-	constraintParams, err := getForeignKeyParams(stmt.TableName, fkDefs, colIndexMap, idxKeyNames, idxColNames)
+	// 自己参照の FK で参照できるカラムの組 (プライマリキーと UNIQUE KEY のカラム)
+	selfKeyColNames := [][]string{}
+	if pkCount > 0 {
+		pkColNames := make([]string, pkCount)
+		for i := range pkCount {
+			pkColNames[i] = colParams[i].Name
+		}
+		selfKeyColNames = append(selfKeyColNames, pkColNames)
+	}
+	for _, ukDef := range ukDefs {
+		selfKeyColNames = append(selfKeyColNames, columnNames(ukDef.Columns))
+	}
+
+	constraintParams, err := getForeignKeyParams(stmt.TableName, selfKeyColNames, fkDefs, colParams, colIndexMap, idxKeyNames, idxColNames)
+	if err != nil {
+		return nil, err
+	}
+
+	checkParams, err := getCheckParams(stmt.TableName, checkDefs, colParams, pkCount, idxKeyNames, constraintParams)
 	if err != nil {
 		return nil, err
 	}
+	constraintParams = append(constraintParams, checkParams...)
 
 	return executor.NewCreateTable(stmt.TableName, uint8(pkCount), idxParams, colParams, constraintParams), nil
 }
 
+// getColumnParam はカラム定義のデータ型をストレージのデータ型に変換し、カラムのパラメータを返す
+// (DEFAULT 句を指定した場合は、式を評価した値もパラメータに含める)
+//
+// 以下の場合はエラーを返す
+//   - サポートされていないデータ型の場合
+//   - DECIMAL の精度・スケールが範囲外の場合
+//   - DEFAULT 句の式がカラムを参照している場合や、カラムのデータ型に変換できない場合
+//   - AUTO_INCREMENT カラムに DEFAULT 句を指定した場合
+func getColumnParam(def *ast.ColumnDef) (handler.CreateColumnParam, error) {
+	param := handler.CreateColumnParam{Name: def.ColName, NotNull: def.NotNull, AutoIncrement: def.AutoIncrement}
+	switch def.DataType {
+	case ast.DataTypeVarchar:
+		param.Type = handler.ColumnTypeString
+	case ast.DataTypeInt:
+		param.Type = handler.ColumnTypeInt
+	case ast.DataTypeBigInt:
+		param.Type = handler.ColumnTypeBigInt
+	case ast.DataTypeDecimal:
+		if def.Precision < 1 || def.Precision > dictionary.MaxDecimalPrecision || def.Scale < 0 || def.Scale > def.Precision {
+			return param, fmt.Errorf("invalid DECIMAL(%d, %d) for column '%s'", def.Precision, def.Scale, def.ColName)
+		}
+		param.Type = handler.ColumnTypeDecimal
+		param.Precision = uint8(def.Precision)
+		param.Scale = uint8(def.Scale)
+	case ast.DataTypeDate:
+		param.Type = handler.ColumnTypeDate
+	case ast.DataTypeDatetime:
+		param.Type = handler.ColumnTypeDatetime
+	default:
+		return param, fmt.Errorf("unsupported data type '%s' for column '%s'", def.DataType, def.ColName)
+	}
+
+	if def.Default != nil {
+		if def.AutoIncrement {
+			return param, fmt.Errorf("invalid default value for column '%s'", def.ColName)
+		}
+		colMeta := &handler.ColumnMetadata{Name: param.Name, Type: param.Type, Precision: param.Precision, Scale: param.Scale}
+		value, err := evalDefaultExpr(colMeta, def.Default)
+		if err != nil {
+			return param, err
+		}
+		param.Default = def.DefaultText
+		param.DefaultValue = value
+	}
+	return param, nil
+}
+
+// validateDefault は NOT NULL のカラム (プライマリキーのカラムを含む) に DEFAULT NULL が指定されていないことを確認する
+func validateDefault(colParam handler.CreateColumnParam) error {
+	if colParam.Default != "" && colParam.DefaultValue == nil && colParam.NotNull {
+		return fmt.Errorf("invalid default value for column '%s'", colParam.Name)
+	}
+	return nil
+}
+
 // getPkCount はプライマリキーのカラム定義を検証し、プライマリキーのカラム数を返す
 //
 // 以下の場合はエラーを返す
@@ -128,46 +218,88 @@ func getPkCount(pkDef *ast.ConstraintPrimaryKeyDef, colIndexMap map[string]int)
 	return len(pkDef.Columns), nil
 }
 
+// validateAutoIncrement は AUTO_INCREMENT カラムの定義を検証する
+//
+// 以下の場合はエラーを返す
+//   - AUTO_INCREMENT カラムが複数ある場合
+//   - AUTO_INCREMENT カラムのデータ型が INT, BIGINT 以外の場合
+//   - AUTO_INCREMENT カラムがプライマリキーの先頭のカラムでない場合 (再起動時にプライマリキーの最大値から採番を復元するため)
+func validateAutoIncrement(colParams []handler.CreateColumnParam) error {
+	var autoIncrementCol *handler.CreateColumnParam
+	for i, colParam := range colParams {
+		if !colParam.AutoIncrement {
+			continue
+		}
+		if autoIncrementCol != nil {
+			return errors.New("only one AUTO_INCREMENT column is allowed")
+		}
+		autoIncrementCol = &colParams[i]
+		if colParam.Type != handler.ColumnTypeInt && colParam.Type != handler.ColumnTypeBigInt {
+			return fmt.Errorf("AUTO_INCREMENT column '%s' must be INT or BIGINT", colParam.Name)
+		}
+		if i != 0 {
+			return fmt.Errorf("AUTO_INCREMENT column '%s' must be the first column of the primary key", colParam.Name)
+		}
+	}
+	return nil
+}
+
 // getIndexParams はユニークキーと非ユニークキーの定義を検証し、インデックスのパラメータを返す
 //
 // 以下の場合はエラーを返す
 //   - インデックスのカラムがテーブルのカラムに存在しない場合
+//   - 1 つのインデックスに同じカラムが複数回指定されている場合
 func getIndexParams(ukDefs []*ast.ConstraintUniqueKeyDef, keyDefs []*ast.ConstraintKeyDef, colIndexMap map[string]int) ([]handler.CreateIndexParam, error) {
 	params := make([]handler.CreateIndexParam, 0, len(ukDefs)+len(keyDefs))
 
 	// ユニークキー
 	for _, ukDef := range ukDefs {
-		idx, exists := colIndexMap[ukDef.Column.ColName]
-		if !exists {
-			return nil, fmt.Errorf("unique key column '%s' does not exist", ukDef.Column.ColName)
+		param, err := getIndexParam(ukDef.KeyName, ukDef.Columns, true, colIndexMap)
+		if err != nil {
+			return nil, err
 		}
-		params = append(params, handler.CreateIndexParam{
-			Name:    ukDef.KeyName,
-			ColName: ukDef.Column.ColName,
-			ColIdx:  uint16(idx),
-			Unique:  true,
-		})
+		params = append(params, param)
 	}
 
 	// 非ユニークキー
 	for _, keyDef := range keyDefs {
-		idx, exists := colIndexMap[keyDef.Column.ColName]
-		if !exists {
-			return nil, fmt.Errorf("key column '%s' does not exist", keyDef.Column.ColName)
+		param, err := getIndexParam(keyDef.KeyName, keyDef.Columns, false, colIndexMap)
+		if err != nil {
+			return nil, err
 		}
-		params = append(params, handler.CreateIndexParam{
-			Name:    keyDef.KeyName,
-			ColName: keyDef.Column.ColName,
-			ColIdx:  uint16(idx),
-			Unique:  false,
-		})
+		params = append(params, param)
 	}
 
 	return params, nil
 }
 
+// getIndexParam はインデックスのカラムを検証し、インデックスのパラメータを返す
+func getIndexParam(keyName string, cols []ast.ColumnId, unique bool, colIndexMap map[string]int) (handler.CreateIndexParam, error) {
+	label := "key"
+	if unique {
+		label = "unique key"
+	}
+	param := handler.CreateIndexParam{Name: keyName, Unique: unique}
+	for _, col := range cols {
+		idx, exists := colIndexMap[col.ColName]
+		if !exists {
+			return handler.CreateIndexParam{}, fmt.Errorf("%s column '%s' does not exist", label, col.ColName)
+		}
+		if slices.Contains(param.ColNames, col.ColName) {
+			return handler.CreateIndexParam{}, fmt.Errorf("duplicate column '%s' in index '%s'", col.ColName, keyName)
+		}
+		param.ColNames = append(param.ColNames, col.ColName)
+		param.ColIdxs = append(param.ColIdxs, uint16(idx))
+	}
+	return param, nil
+}
+
 // getForeignKeyParams は外部キー定義を検証し、外部キー制約のパラメータを返す
-func getForeignKeyParams(tableName string, fkDefs []*ast.ConstraintForeignKeyDef, colIndexMap map[string]int, idxKeyNames map[string]bool, idxColNames map[string]bool) ([]handler.CreateConstraintParam, error) {
+//
+// selfKeyColNames は CREATE TABLE で作成中のテーブルを参照する (自己参照の) 場合に参照できるカラムの組 (プライマリキーと UNIQUE KEY)。
+// 既存のテーブルに FK を追加する場合は nil を渡し、自己参照の場合もカタログのテーブル定義で検証する。
+// idxColNames は FK カラムを先頭のカラム (キーの順) とするインデックスがあるかの確認に使用する
+func getForeignKeyParams(tableName string, selfKeyColNames [][]string, fkDefs []*ast.ConstraintForeignKeyDef, colParams []handler.CreateColumnParam, colIndexMap map[string]int, idxKeyNames map[string]bool, idxColNames [][]string) ([]handler.CreateConstraintParam, error) {
 	if len(fkDefs) == 0 {
 		return nil, nil
 	}
@@ -196,48 +328,198 @@ func getForeignKeyParams(tableName string, fkDefs []*ast.ConstraintForeignKeyDef
 
 		fkNames[fkDef.KeyName] = true
 
-		// FK カラムがテーブルのカラム定義に存在するか
-		if _, exists := colIndexMap[fkDef.Column.ColName]; !exists {
-			return nil, fmt.Errorf("foreign key column '%s' does not exist", fkDef.Column.ColName)
+		// FK カラムと参照先カラムの数が一致するか
+		fkColNames := columnNames(fkDef.Columns)
+		if len(fkColNames) != len(fkDef.RefColumns) {
+			return nil, fmt.Errorf("foreign key '%s' has %d columns but references %d columns", fkDef.KeyName, len(fkColNames), len(fkDef.RefColumns))
 		}
 
-		// 自己参照でないか
-		if fkDef.RefTable == tableName {
-			return nil, fmt.Errorf("self-referencing foreign key is not supported")
+		// 参照先テーブルがカタログに存在するか (自己参照の場合は作成中のテーブル定義を使用する)
+		isSelfRef := selfKeyColNames != nil && fkDef.RefTable == tableName
+		var refTableMeta *dictionary.TableMeta
+		if !isSelfRef {
+			var ok bool
+			refTableMeta, ok = hdl.Catalog.GetTableMetaByName(fkDef.RefTable)
+			if !ok {
+				return nil, fmt.Errorf("referenced table '%s' does not exist", fkDef.RefTable)
+			}
 		}
 
-		// 参照先テーブルがカタログに存在するか
-		refTableMeta, ok := hdl.Catalog.GetTableMetaByName(fkDef.RefTable)
-		if !ok {
-			return nil, fmt.Errorf("referenced table '%s' does not exist", fkDef.RefTable)
-		}
+		for i, fkColName := range fkColNames {
+			refColName := fkDef.RefColumns[i]
+
+			// FK カラムがテーブルのカラム定義に存在し、FK 内で重複していないか
+			fkColIdx, exists := colIndexMap[fkColName]
+			if !exists {
+				return nil, fmt.Errorf("foreign key column '%s' does not exist", fkColName)
+			}
+			if slices.Contains(fkColNames[:i], fkColName) {
+				return nil, fmt.Errorf("duplicate column '%s' in foreign key '%s'", fkColName, fkDef.KeyName)
+			}
+
+			// 参照先カラムの定義を取得する
+			var refType handler.ColumnType
+			var refScale uint8
+			if isSelfRef {
+				refColIdx, exists := colIndexMap[refColName]
+				if !exists {
+					return nil, fmt.Errorf("referenced column '%s' does not exist in table '%s'", refColName, fkDef.RefTable)
+				}
+				refType = colParams[refColIdx].Type
+				refScale = colParams[refColIdx].Scale
+			} else {
+				refCol, ok := refTableMeta.GetColByName(refColName)
+				if !ok {
+					return nil, fmt.Errorf("referenced column '%s' does not exist in table '%s'", refColName, fkDef.RefTable)
+				}
+				refType = refCol.Type
+				refScale = refCol.Scale
+			}
+
+			// FK カラムと参照先カラムのデータ型が一致するか (格納形式が異なると値を比較できないため)
+			fkCol := colParams[fkColIdx]
+			isStringPair := !fkCol.Type.IsFixedSize() && !refType.IsFixedSize()
+			if !isStringPair && (fkCol.Type != refType || fkCol.Scale != refScale) {
+				return nil, fmt.Errorf("foreign key column '%s' and referenced column '%s' in table '%s' are incompatible", fkColName, refColName, fkDef.RefTable)
+			}
 
-		// 参照先カラムが参照先テーブルに存在するか
-		refCol, ok := refTableMeta.GetColByName(fkDef.RefColumn)
-		if !ok {
-			return nil, fmt.Errorf("referenced column '%s' does not exist in table '%s'", fkDef.RefColumn, fkDef.RefTable)
+			// SET NULL の場合、FK カラムは NULL を許容する必要がある
+			if fkCol.NotNull && (fkDef.OnDelete == ast.ReferentialActionSetNull || fkDef.OnUpdate == ast.ReferentialActionSetNull) {
+				return nil, fmt.Errorf("column '%s' cannot be NOT NULL: needed in a foreign key constraint '%s' SET NULL", fkColName, fkDef.KeyName)
+			}
 		}
 
-		// 参照先カラムに PK または UNIQUE KEY があるか
-		isPK := refCol.Pos < uint16(refTableMeta.PKCount)
-		refIdx, hasIdx := refTableMeta.GetIndexByColName(fkDef.RefColumn)
-		hasUniqueIdx := hasIdx && refIdx.Type == dictionary.IndexTypeUnique
-		if !isPK && !hasUniqueIdx {
-			return nil, fmt.Errorf("referenced column '%s' in table '%s' must be a primary key or have a unique index", fkDef.RefColumn, fkDef.RefTable)
+		// 参照先カラムの組がプライマリキー、または UNIQUE KEY と (キーの順に) 一致するか
+		var isKey bool
+		if isSelfRef {
+			isKey = slices.ContainsFunc(selfKeyColNames, func(keyColNames []string) bool { return slices.Equal(keyColNames, fkDef.RefColumns) })
+		} else {
+			_, hasUnique := refTableMeta.GetUniqueIndexByColNames(fkDef.RefColumns)
+			isKey = refTableMeta.IsPrimaryKey(fkDef.RefColumns) || hasUnique
+		}
+		if !isKey {
+			return nil, fmt.Errorf("referenced column '%s' in table '%s' must be a primary key or have a unique index", strings.Join(fkDef.RefColumns, ", "), fkDef.RefTable)
 		}
 
-		// FK カラムにインデックスがあるか (同一 CREATE TABLE 内で定義されている必要あり)
-		if _, exists := idxColNames[fkDef.Column.ColName]; !exists {
-			return nil, fmt.Errorf("foreign key column '%s' must have an index", fkDef.Column.ColName)
+		// FK カラムを先頭のカラム (キーの順) とするインデックスがあるか (同一 CREATE TABLE 内で定義されている必要あり)
+		hasIndex := slices.ContainsFunc(idxColNames, func(colNames []string) bool {
+			return len(fkColNames) <= len(colNames) && slices.Equal(colNames[:len(fkColNames)], fkColNames)
+		})
+		if !hasIndex {
+			return nil, fmt.Errorf("foreign key column '%s' must have an index", strings.Join(fkColNames, ", "))
 		}
 
 		params = append(params, handler.CreateConstraintParam{
 			ConstraintName: fkDef.KeyName,
-			ColName:        fkDef.Column.ColName,
+			ColNames:       fkColNames,
 			RefTableName:   fkDef.RefTable,
-			RefColName:     fkDef.RefColumn,
+			RefColNames:    fkDef.RefColumns,
+			OnDelete:       getReferentialAction(fkDef.OnDelete),
+			OnUpdate:       getReferentialAction(fkDef.OnUpdate),
 		})
 	}
 
 	return params, nil
 }
+
+// columnNames はカラムのリストからカラム名のリストを返す
+func columnNames(cols []ast.ColumnId) []string {
+	names := make([]string, len(cols))
+	for i, col := range cols {
+		names[i] = col.ColName
+	}
+	return names
+}
+
+// getReferentialAction は ON DELETE / ON UPDATE 句の参照アクションをストレージの参照アクションに変換する (省略した場合は RESTRICT)
+func getReferentialAction(action ast.ReferentialAction) handler.ReferentialAction {
+	switch action {
+	case ast.ReferentialActionCascade:
+		return handler.ReferentialActionCascade
+	case ast.ReferentialActionSetNull:
+		return handler.ReferentialActionSetNull
+	default:
+		return handler.ReferentialActionRestrict
+	}
+}
+
+// checkColumnNotInFKAction は CHECK 制約の条件式のカラムが、CASCADE / SET NULL の参照アクションを持つ FK のカラムでないことを確認する
+//
+// 参照アクションによる変更では CHECK 制約を評価しないため、MySQL と同様に組み合わせを禁止する
+func checkColumnNotInFKAction(checkName string, expr ast.Expr, fkParams []handler.CreateConstraintParam) error {
+	for _, col := range collectColumns(expr) {
+		for _, fk := range fkParams {
+			if fk.RefTableName == "" || !slices.Contains(fk.ColNames, col.ColName) {
+				continue
+			}
+			if fk.OnDelete != handler.ReferentialActionRestrict || fk.OnUpdate != handler.ReferentialActionRestrict {
+				return fmt.Errorf("column '%s' cannot be used in a check constraint '%s': needed in a foreign key constraint '%s' referential action", col.ColName, checkName, fk.ConstraintName)
+			}
+		}
+	}
+	return nil
+}
+
+// getCheckParams は CHECK 制約の定義を検証し、CHECK 制約のパラメータを返す
+//
+// 制約名を省略した場合は、MySQL と同様に "<テーブル名>_chk_<連番>" を制約名にする
+//
+// 以下の場合はエラーを返す
+//   - 制約名が全テーブルの制約名や、同一 CREATE TABLE 内のインデックス名・制約名と重複する場合
+//   - 条件式がテーブルに存在しないカラムを参照している場合や、条件として評価できない場合
+func getCheckParams(tableName string, checkDefs []*ast.ConstraintCheckDef, colParams []handler.CreateColumnParam, pkCount int, idxKeyNames map[string]bool, fkParams []handler.CreateConstraintParam) ([]handler.CreateConstraintParam, error) {
+	if len(checkDefs) == 0 {
+		return nil, nil
+	}
+
+	// 登録済みの制約名
+	conNames := map[string]bool{}
+	for name := range idxKeyNames {
+		conNames[name] = true
+	}
+	for _, param := range fkParams {
+		conNames[param.ConstraintName] = true
+	}
+	for _, tblMeta := range handler.Get().Catalog.GetAllTables() {
+		for _, con := range tblMeta.Constraints {
+			conNames[con.ConstraintName] = true
+		}
+	}
+
+	// 条件式をコンパイルするためのテーブル定義 (カラムのみ)
+	cols := make([]*dictionary.ColumnMeta, len(colParams))
+	for i, colParam := range colParams {
+		cols[i] = dictionary.NewColumnMeta(0, colParam.Name, uint16(i), colParam.Type)
+		cols[i].Precision = colParam.Precision
+		cols[i].Scale = colParam.Scale
+		cols[i].NotNull = colParam.NotNull
+	}
+	tblMeta := &dictionary.TableMeta{Name: tableName, NCols: uint8(len(cols)), PKCount: uint8(pkCount), Cols: cols}
+	scope := newTableExprScope(tblMeta)
+
+	params := make([]handler.CreateConstraintParam, 0, len(checkDefs))
+	seq := 0
+	for _, checkDef := range checkDefs {
+		name := checkDef.KeyName
+		if name == "" {
+			seq++
+			name = fmt.Sprintf("%s_chk_%d", tableName, seq)
+		}
+		if conNames[name] {
+			return nil, fmt.Errorf("duplicate check constraint name: '%s'", name)
+		}
+		conNames[name] = true
+
+		if _, err := compileCondition(checkDef.Expr, scope); err != nil {
+			return nil, fmt.Errorf("invalid check constraint '%s': %w", name, err)
+		}
+		if err := checkColumnNotInFKAction(name, checkDef.Expr, fkParams); err != nil {
+			return nil, err
+		}
+		params = append(params, handler.CreateConstraintParam{
+			ConstraintName: name,
+			CheckExpr:      checkDef.ExprText,
+		})
+	}
+	return params, nil
+}
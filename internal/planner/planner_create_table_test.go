@@ -5,6 +5,7 @@ import (
 
 	"github.com/ren-yamanashi/minesql/internal/ast"
 	"github.com/ren-yamanashi/minesql/internal/executor"
+	"github.com/ren-yamanashi/minesql/internal/parser"
 	"github.com/ren-yamanashi/minesql/internal/storage/handler"
 	"github.com/stretchr/testify/assert"
 )
@@ -34,7 +35,7 @@ func TestPlanCreateTable(t *testing.T) {
 
 	t.Run("ユニークキーインデックスがあるテーブルを作成できる", func(t *testing.T) {
 		// GIVEN
-		ukDef := &ast.ConstraintUniqueKeyDef{Column: *ast.NewColumnId("email")}
+		ukDef := &ast.ConstraintUniqueKeyDef{Columns: []ast.ColumnId{*ast.NewColumnId("email")}}
 		ukDef.KeyName = "uk_email"
 
 		stmt := &ast.CreateTableStmt{
@@ -60,10 +61,10 @@ func TestPlanCreateTable(t *testing.T) {
 
 	t.Run("プライマリキー複数、ユニークインデックス複数のテーブルを作成できる", func(t *testing.T) {
 		// GIVEN
-		ukDef1 := &ast.ConstraintUniqueKeyDef{Column: *ast.NewColumnId("email")}
+		ukDef1 := &ast.ConstraintUniqueKeyDef{Columns: []ast.ColumnId{*ast.NewColumnId("email")}}
 		ukDef1.KeyName = "uk_email"
 
-		ukDef2 := &ast.ConstraintUniqueKeyDef{Column: *ast.NewColumnId("username")}
+		ukDef2 := &ast.ConstraintUniqueKeyDef{Columns: []ast.ColumnId{*ast.NewColumnId("username")}}
 		ukDef2.KeyName = "uk_username"
 
 		stmt := &ast.CreateTableStmt{
@@ -266,10 +267,10 @@ func TestPlanCreateTable(t *testing.T) {
 
 	t.Run("重複したユニークキー名がある場合、エラーを返す", func(t *testing.T) {
 		// GIVEN
-		ukDef1 := &ast.ConstraintUniqueKeyDef{Column: *ast.NewColumnId("email")}
+		ukDef1 := &ast.ConstraintUniqueKeyDef{Columns: []ast.ColumnId{*ast.NewColumnId("email")}}
 		ukDef1.KeyName = "uk_same"
 
-		ukDef2 := &ast.ConstraintUniqueKeyDef{Column: *ast.NewColumnId("username")}
+		ukDef2 := &ast.ConstraintUniqueKeyDef{Columns: []ast.ColumnId{*ast.NewColumnId("username")}}
 		ukDef2.KeyName = "uk_same"
 
 		stmt := &ast.CreateTableStmt{
@@ -295,24 +296,50 @@ func TestPlanCreateTable(t *testing.T) {
 		assert.Contains(t, err.Error(), "duplicate index name: uk_same")
 	})
 
-	t.Run("同一カラムが複数のユニークキーに指定されている場合、エラーを返す", func(t *testing.T) {
+	t.Run("同一カラムに複数のインデックスを定義できる", func(t *testing.T) {
 		// GIVEN
-		ukDef1 := &ast.ConstraintUniqueKeyDef{Column: *ast.NewColumnId("email")}
-		ukDef1.KeyName = "uk_email1"
+		stmt := &ast.CreateTableStmt{
+			TableName: "users",
+			CreateDefinitions: []ast.Definition{
+				&ast.ColumnDef{ColName: "id", DataType: ast.DataTypeVarchar},
+				&ast.ColumnDef{ColName: "email", DataType: ast.DataTypeVarchar},
+				&ast.ColumnDef{ColName: "name", DataType: ast.DataTypeVarchar},
+				&ast.ConstraintPrimaryKeyDef{Columns: []ast.ColumnId{*ast.NewColumnId("id")}},
+				&ast.ConstraintUniqueKeyDef{KeyName: "uk_email", Columns: []ast.ColumnId{*ast.NewColumnId("email")}},
+				&ast.ConstraintKeyDef{KeyName: "idx_email_name", Columns: []ast.ColumnId{*ast.NewColumnId("email"), *ast.NewColumnId("name")}},
+			},
+		}
 
-		ukDef2 := &ast.ConstraintUniqueKeyDef{Column: *ast.NewColumnId("email")}
-		ukDef2.KeyName = "uk_email2"
+		// WHEN
+		exec, err := PlanCreateTable(stmt)
 
+		// THEN
+		assert.NoError(t, err)
+		assert.Equal(t, executor.NewCreateTable(
+			"users",
+			1,
+			[]handler.CreateIndexParam{
+				{Name: "uk_email", ColNames: []string{"email"}, ColIdxs: []uint16{1}, Unique: true},
+				{Name: "idx_email_name", ColNames: []string{"email", "name"}, ColIdxs: []uint16{1, 2}, Unique: false},
+			},
+			[]handler.CreateColumnParam{
+				{Name: "id", Type: handler.ColumnTypeString, NotNull: true},
+				{Name: "email", Type: handler.ColumnTypeString},
+				{Name: "name", Type: handler.ColumnTypeString},
+			},
+			[]handler.CreateConstraintParam{},
+		), exec)
+	})
+
+	t.Run("1 つのインデックスに同じカラムが複数回指定されている場合、エラーを返す", func(t *testing.T) {
+		// GIVEN
 		stmt := &ast.CreateTableStmt{
 			TableName: "users",
 			CreateDefinitions: []ast.Definition{
 				&ast.ColumnDef{ColName: "id", DataType: ast.DataTypeVarchar},
 				&ast.ColumnDef{ColName: "email", DataType: ast.DataTypeVarchar},
-				&ast.ConstraintPrimaryKeyDef{Columns: []ast.ColumnId{
-					*ast.NewColumnId("id"),
-				}},
-				ukDef1,
-				ukDef2,
+				&ast.ConstraintPrimaryKeyDef{Columns: []ast.ColumnId{*ast.NewColumnId("id")}},
+				&ast.ConstraintUniqueKeyDef{KeyName: "uk_email", Columns: []ast.ColumnId{*ast.NewColumnId("email"), *ast.NewColumnId("email")}},
 			},
 		}
 
@@ -320,9 +347,8 @@ func TestPlanCreateTable(t *testing.T) {
 		exec, err := PlanCreateTable(stmt)
 
 		// THEN
-		assert.Error(t, err)
 		assert.Nil(t, exec)
-		assert.Contains(t, err.Error(), "column 'email' cannot have multiple indexes")
+		assert.ErrorContains(t, err, "duplicate column 'email' in index 'uk_email'")
 	})
 
 	t.Run("カラムが1つだけのテーブルを作成できる", func(t *testing.T) {
@@ -348,7 +374,7 @@ func TestPlanCreateTable(t *testing.T) {
 
 	t.Run("ユニークインデックスに指定されたカラムが存在しない場合、エラーを返す", func(t *testing.T) {
 		// GIVEN
-		ukDef := &ast.ConstraintUniqueKeyDef{Column: *ast.NewColumnId("non_existent_column")}
+		ukDef := &ast.ConstraintUniqueKeyDef{Columns: []ast.ColumnId{*ast.NewColumnId("non_existent_column")}}
 		ukDef.KeyName = "uk_test"
 
 		stmt := &ast.CreateTableStmt{
@@ -379,7 +405,7 @@ func TestPlanCreateTable(t *testing.T) {
 				&ast.ColumnDef{ColName: "id", DataType: ast.DataTypeVarchar},
 				&ast.ColumnDef{ColName: "category", DataType: ast.DataTypeVarchar},
 				&ast.ConstraintPrimaryKeyDef{Columns: []ast.ColumnId{*ast.NewColumnId("id")}},
-				&ast.ConstraintKeyDef{KeyName: "idx_category", Column: *ast.NewColumnId("category")},
+				&ast.ConstraintKeyDef{KeyName: "idx_category", Columns: []ast.ColumnId{*ast.NewColumnId("category")}},
 			},
 		}
 
@@ -400,8 +426,8 @@ func TestPlanCreateTable(t *testing.T) {
 				&ast.ColumnDef{ColName: "name", DataType: ast.DataTypeVarchar},
 				&ast.ColumnDef{ColName: "category", DataType: ast.DataTypeVarchar},
 				&ast.ConstraintPrimaryKeyDef{Columns: []ast.ColumnId{*ast.NewColumnId("id")}},
-				&ast.ConstraintUniqueKeyDef{KeyName: "idx_name", Column: *ast.NewColumnId("name")},
-				&ast.ConstraintKeyDef{KeyName: "idx_category", Column: *ast.NewColumnId("category")},
+				&ast.ConstraintUniqueKeyDef{KeyName: "idx_name", Columns: []ast.ColumnId{*ast.NewColumnId("name")}},
+				&ast.ConstraintKeyDef{KeyName: "idx_category", Columns: []ast.ColumnId{*ast.NewColumnId("category")}},
 			},
 		}
 
@@ -422,8 +448,8 @@ func TestPlanCreateTable(t *testing.T) {
 				&ast.ColumnDef{ColName: "name", DataType: ast.DataTypeVarchar},
 				&ast.ColumnDef{ColName: "category", DataType: ast.DataTypeVarchar},
 				&ast.ConstraintPrimaryKeyDef{Columns: []ast.ColumnId{*ast.NewColumnId("id")}},
-				&ast.ConstraintUniqueKeyDef{KeyName: "idx_same", Column: *ast.NewColumnId("name")},
-				&ast.ConstraintKeyDef{KeyName: "idx_same", Column: *ast.NewColumnId("category")},
+				&ast.ConstraintUniqueKeyDef{KeyName: "idx_same", Columns: []ast.ColumnId{*ast.NewColumnId("name")}},
+				&ast.ConstraintKeyDef{KeyName: "idx_same", Columns: []ast.ColumnId{*ast.NewColumnId("category")}},
 			},
 		}
 
@@ -443,7 +469,7 @@ func TestPlanCreateTable(t *testing.T) {
 			CreateDefinitions: []ast.Definition{
 				&ast.ColumnDef{ColName: "id", DataType: ast.DataTypeVarchar},
 				&ast.ConstraintPrimaryKeyDef{Columns: []ast.ColumnId{*ast.NewColumnId("id")}},
-				&ast.ConstraintKeyDef{KeyName: "idx_nonexistent", Column: *ast.NewColumnId("nonexistent")},
+				&ast.ConstraintKeyDef{KeyName: "idx_nonexistent", Columns: []ast.ColumnId{*ast.NewColumnId("nonexistent")}},
 			},
 		}
 
@@ -473,8 +499,8 @@ func TestPlanCreateTable(t *testing.T) {
 				&ast.ColumnDef{ColName: "id", DataType: ast.DataTypeVarchar},
 				&ast.ColumnDef{ColName: "user_id", DataType: ast.DataTypeVarchar},
 				&ast.ConstraintPrimaryKeyDef{Columns: []ast.ColumnId{*ast.NewColumnId("id")}},
-				&ast.ConstraintKeyDef{KeyName: "idx_user_id", Column: *ast.NewColumnId("user_id")},
-				&ast.ConstraintForeignKeyDef{KeyName: "fk_user", Column: *ast.NewColumnId("user_id"), RefTable: "users", RefColumn: "id"},
+				&ast.ConstraintKeyDef{KeyName: "idx_user_id", Columns: []ast.ColumnId{*ast.NewColumnId("user_id")}},
+				&ast.ConstraintForeignKeyDef{KeyName: "fk_user", Columns: []ast.ColumnId{*ast.NewColumnId("user_id")}, RefTable: "users", RefColumns: []string{"id"}},
 			},
 		}
 
@@ -497,8 +523,8 @@ func TestPlanCreateTable(t *testing.T) {
 				&ast.ColumnDef{ColName: "id", DataType: ast.DataTypeVarchar},
 				&ast.ColumnDef{ColName: "user_id", DataType: ast.DataTypeVarchar},
 				&ast.ConstraintPrimaryKeyDef{Columns: []ast.ColumnId{*ast.NewColumnId("id")}},
-				&ast.ConstraintKeyDef{KeyName: "idx_user_id", Column: *ast.NewColumnId("user_id")},
-				&ast.ConstraintForeignKeyDef{KeyName: "fk_user", Column: *ast.NewColumnId("user_id"), RefTable: "users", RefColumn: "id"},
+				&ast.ConstraintKeyDef{KeyName: "idx_user_id", Columns: []ast.ColumnId{*ast.NewColumnId("user_id")}},
+				&ast.ConstraintForeignKeyDef{KeyName: "fk_user", Columns: []ast.ColumnId{*ast.NewColumnId("user_id")}, RefTable: "users", RefColumns: []string{"id"}},
 			},
 		}
 
@@ -528,8 +554,8 @@ func TestPlanCreateTable(t *testing.T) {
 				&ast.ColumnDef{ColName: "id", DataType: ast.DataTypeVarchar},
 				&ast.ColumnDef{ColName: "user_id", DataType: ast.DataTypeVarchar},
 				&ast.ConstraintPrimaryKeyDef{Columns: []ast.ColumnId{*ast.NewColumnId("id")}},
-				&ast.ConstraintKeyDef{KeyName: "idx_user_id", Column: *ast.NewColumnId("user_id")},
-				&ast.ConstraintForeignKeyDef{KeyName: "fk_user", Column: *ast.NewColumnId("user_id"), RefTable: "users", RefColumn: "nonexistent"},
+				&ast.ConstraintKeyDef{KeyName: "idx_user_id", Columns: []ast.ColumnId{*ast.NewColumnId("user_id")}},
+				&ast.ConstraintForeignKeyDef{KeyName: "fk_user", Columns: []ast.ColumnId{*ast.NewColumnId("user_id")}, RefTable: "users", RefColumns: []string{"nonexistent"}},
 			},
 		}
 
@@ -548,7 +574,7 @@ func TestPlanCreateTable(t *testing.T) {
 		defer handler.Reset()
 
 		parentTable := executor.NewCreateTable("users", 1,
-			[]handler.CreateIndexParam{{Name: "idx_name", ColName: "name", ColIdx: 1, Unique: false}},
+			[]handler.CreateIndexParam{{Name: "idx_name", ColNames: []string{"name"}, ColIdxs: []uint16{1}, Unique: false}},
 			[]handler.CreateColumnParam{
 				{Name: "id", Type: "string"},
 				{Name: "name", Type: "string"},
@@ -562,8 +588,8 @@ func TestPlanCreateTable(t *testing.T) {
 				&ast.ColumnDef{ColName: "id", DataType: ast.DataTypeVarchar},
 				&ast.ColumnDef{ColName: "user_name", DataType: ast.DataTypeVarchar},
 				&ast.ConstraintPrimaryKeyDef{Columns: []ast.ColumnId{*ast.NewColumnId("id")}},
-				&ast.ConstraintKeyDef{KeyName: "idx_user_name", Column: *ast.NewColumnId("user_name")},
-				&ast.ConstraintForeignKeyDef{KeyName: "fk_user", Column: *ast.NewColumnId("user_name"), RefTable: "users", RefColumn: "name"},
+				&ast.ConstraintKeyDef{KeyName: "idx_user_name", Columns: []ast.ColumnId{*ast.NewColumnId("user_name")}},
+				&ast.ConstraintForeignKeyDef{KeyName: "fk_user", Columns: []ast.ColumnId{*ast.NewColumnId("user_name")}, RefTable: "users", RefColumns: []string{"name"}},
 			},
 		}
 
@@ -576,7 +602,90 @@ func TestPlanCreateTable(t *testing.T) {
 		assert.Contains(t, err.Error(), "must be a primary key or have a unique index")
 	})
 
-	t.Run("自己参照 FK の場合、エラーを返す", func(t *testing.T) {
+	t.Run("複合主キーを参照する複合外部キー付きのテーブルを作成できる", func(t *testing.T) {
+		// GIVEN
+		initStorageManagerForTest(t)
+		defer handler.Reset()
+		createCompositeKeyUsersForTest(t)
+
+		stmt := &ast.CreateTableStmt{
+			TableName: "orders",
+			CreateDefinitions: []ast.Definition{
+				&ast.ColumnDef{ColName: "id", DataType: ast.DataTypeVarchar},
+				&ast.ColumnDef{ColName: "tenant_id", DataType: ast.DataTypeVarchar},
+				&ast.ColumnDef{ColName: "user_id", DataType: ast.DataTypeVarchar},
+				&ast.ConstraintPrimaryKeyDef{Columns: []ast.ColumnId{*ast.NewColumnId("id")}},
+				&ast.ConstraintKeyDef{KeyName: "idx_tenant_user", Columns: []ast.ColumnId{*ast.NewColumnId("tenant_id"), *ast.NewColumnId("user_id"), *ast.NewColumnId("id")}},
+				&ast.ConstraintForeignKeyDef{KeyName: "fk_user", Columns: []ast.ColumnId{*ast.NewColumnId("tenant_id"), *ast.NewColumnId("user_id")}, RefTable: "users", RefColumns: []string{"tenant_id", "id"}},
+			},
+		}
+
+		// WHEN
+		exec, err := PlanCreateTable(stmt)
+		assert.NoError(t, err)
+		_, err = exec.Next()
+
+		// THEN
+		assert.NoError(t, err)
+		tblMeta, ok := handler.Get().Catalog.GetTableMetaByName("orders")
+		assert.True(t, ok)
+		fks := tblMeta.GetForeignKeyConstraints()
+		assert.Equal(t, 1, len(fks))
+		assert.Equal(t, []string{"tenant_id", "user_id"}, fks[0].ColNames)
+		assert.Equal(t, []string{"tenant_id", "id"}, fks[0].RefColNames)
+	})
+
+	t.Run("複合外部キーの検証でエラーを返す", func(t *testing.T) {
+		tests := []struct {
+			name       string
+			idxCols    []string
+			fkCols     []string
+			refCols    []string
+			errMessage string
+		}{
+			{"参照先カラムが複合主キーの一部だけの場合", []string{"user_id"}, []string{"user_id"}, []string{"id"}, "referenced column 'id' in table 'users' must be a primary key or have a unique index"},
+			{"参照先カラムの順序が複合主キーと異なる場合", []string{"user_id", "tenant_id"}, []string{"user_id", "tenant_id"}, []string{"id", "tenant_id"}, "referenced column 'id, tenant_id' in table 'users' must be a primary key or have a unique index"},
+			{"FK カラムを先頭に持つインデックスがない場合", []string{"user_id", "tenant_id"}, []string{"tenant_id", "user_id"}, []string{"tenant_id", "id"}, "foreign key column 'tenant_id, user_id' must have an index"},
+			{"FK カラムと参照先カラムの数が異なる場合", []string{"tenant_id", "user_id"}, []string{"tenant_id", "user_id"}, []string{"tenant_id"}, "foreign key 'fk_user' has 2 columns but references 1 columns"},
+			{"FK カラムが重複する場合", []string{"tenant_id"}, []string{"tenant_id", "tenant_id"}, []string{"tenant_id", "id"}, "duplicate column 'tenant_id' in foreign key 'fk_user'"},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				// GIVEN
+				initStorageManagerForTest(t)
+				defer handler.Reset()
+				createCompositeKeyUsersForTest(t)
+
+				var idxCols, fkCols []ast.ColumnId
+				for _, col := range tt.idxCols {
+					idxCols = append(idxCols, *ast.NewColumnId(col))
+				}
+				for _, col := range tt.fkCols {
+					fkCols = append(fkCols, *ast.NewColumnId(col))
+				}
+				stmt := &ast.CreateTableStmt{
+					TableName: "orders",
+					CreateDefinitions: []ast.Definition{
+						&ast.ColumnDef{ColName: "id", DataType: ast.DataTypeVarchar},
+						&ast.ColumnDef{ColName: "tenant_id", DataType: ast.DataTypeVarchar},
+						&ast.ColumnDef{ColName: "user_id", DataType: ast.DataTypeVarchar},
+						&ast.ConstraintPrimaryKeyDef{Columns: []ast.ColumnId{*ast.NewColumnId("id")}},
+						&ast.ConstraintKeyDef{KeyName: "idx_fk", Columns: idxCols},
+						&ast.ConstraintForeignKeyDef{KeyName: "fk_user", Columns: fkCols, RefTable: "users", RefColumns: tt.refCols},
+					},
+				}
+
+				// WHEN
+				exec, err := PlanCreateTable(stmt)
+
+				// THEN
+				assert.EqualError(t, err, tt.errMessage)
+				assert.Nil(t, exec)
+			})
+		}
+	})
+
+	t.Run("自己参照 FK 付きのテーブルを作成できる", func(t *testing.T) {
 		// GIVEN
 		initStorageManagerForTest(t)
 		defer handler.Reset()
@@ -587,8 +696,41 @@ func TestPlanCreateTable(t *testing.T) {
 				&ast.ColumnDef{ColName: "id", DataType: ast.DataTypeVarchar},
 				&ast.ColumnDef{ColName: "parent_id", DataType: ast.DataTypeVarchar},
 				&ast.ConstraintPrimaryKeyDef{Columns: []ast.ColumnId{*ast.NewColumnId("id")}},
-				&ast.ConstraintKeyDef{KeyName: "idx_parent_id", Column: *ast.NewColumnId("parent_id")},
-				&ast.ConstraintForeignKeyDef{KeyName: "fk_parent", Column: *ast.NewColumnId("parent_id"), RefTable: "categories", RefColumn: "id"},
+				&ast.ConstraintKeyDef{KeyName: "idx_parent_id", Columns: []ast.ColumnId{*ast.NewColumnId("parent_id")}},
+				&ast.ConstraintForeignKeyDef{KeyName: "fk_parent", Columns: []ast.ColumnId{*ast.NewColumnId("parent_id")}, RefTable: "categories", RefColumns: []string{"id"}, OnDelete: ast.ReferentialActionCascade},
+			},
+		}
+
+		// WHEN
+		exec, err := PlanCreateTable(stmt)
+		assert.NoError(t, err)
+		_, err = exec.Next()
+
+		// THEN
+		assert.NoError(t, err)
+		tblMeta, ok := handler.Get().Catalog.GetTableMetaByName("categories")
+		assert.True(t, ok)
+		fks := tblMeta.GetForeignKeyConstraints()
+		assert.Equal(t, 1, len(fks))
+		assert.Equal(t, "categories", fks[0].RefTableName)
+		assert.Equal(t, handler.ReferentialActionCascade, fks[0].OnDelete)
+		assert.Equal(t, handler.ReferentialActionRestrict, fks[0].OnUpdate)
+	})
+
+	t.Run("自己参照 FK の参照先カラムに PK も UNIQUE KEY もない場合、エラーを返す", func(t *testing.T) {
+		// GIVEN
+		initStorageManagerForTest(t)
+		defer handler.Reset()
+
+		stmt := &ast.CreateTableStmt{
+			TableName: "categories",
+			CreateDefinitions: []ast.Definition{
+				&ast.ColumnDef{ColName: "id", DataType: ast.DataTypeVarchar},
+				&ast.ColumnDef{ColName: "code", DataType: ast.DataTypeVarchar},
+				&ast.ColumnDef{ColName: "parent_code", DataType: ast.DataTypeVarchar},
+				&ast.ConstraintPrimaryKeyDef{Columns: []ast.ColumnId{*ast.NewColumnId("id")}},
+				&ast.ConstraintKeyDef{KeyName: "idx_parent_code", Columns: []ast.ColumnId{*ast.NewColumnId("parent_code")}},
+				&ast.ConstraintForeignKeyDef{KeyName: "fk_parent", Columns: []ast.ColumnId{*ast.NewColumnId("parent_code")}, RefTable: "categories", RefColumns: []string{"code"}},
 			},
 		}
 
@@ -598,7 +740,38 @@ func TestPlanCreateTable(t *testing.T) {
 		// THEN
 		assert.Error(t, err)
 		assert.Nil(t, exec)
-		assert.Contains(t, err.Error(), "self-referencing foreign key is not supported")
+		assert.Contains(t, err.Error(), "must be a primary key or have a unique index")
+	})
+
+	t.Run("SET NULL の FK カラムが NOT NULL の場合、エラーを返す", func(t *testing.T) {
+		// GIVEN
+		initStorageManagerForTest(t)
+		defer handler.Reset()
+
+		parentTable := executor.NewCreateTable("users", 1, nil, []handler.CreateColumnParam{
+			{Name: "id", Type: "string"},
+		}, nil)
+		_, err := parentTable.Next()
+		assert.NoError(t, err)
+
+		stmt := &ast.CreateTableStmt{
+			TableName: "orders",
+			CreateDefinitions: []ast.Definition{
+				&ast.ColumnDef{ColName: "id", DataType: ast.DataTypeVarchar},
+				&ast.ColumnDef{ColName: "user_id", DataType: ast.DataTypeVarchar, NotNull: true},
+				&ast.ConstraintPrimaryKeyDef{Columns: []ast.ColumnId{*ast.NewColumnId("id")}},
+				&ast.ConstraintKeyDef{KeyName: "idx_user_id", Columns: []ast.ColumnId{*ast.NewColumnId("user_id")}},
+				&ast.ConstraintForeignKeyDef{KeyName: "fk_user", Columns: []ast.ColumnId{*ast.NewColumnId("user_id")}, RefTable: "users", RefColumns: []string{"id"}, OnUpdate: ast.ReferentialActionSetNull},
+			},
+		}
+
+		// WHEN
+		exec, err := PlanCreateTable(stmt)
+
+		// THEN
+		assert.Error(t, err)
+		assert.Nil(t, exec)
+		assert.Contains(t, err.Error(), "column 'user_id' cannot be NOT NULL: needed in a foreign key constraint 'fk_user' SET NULL")
 	})
 
 	t.Run("FK カラムが存在しない場合、エラーを返す", func(t *testing.T) {
@@ -617,7 +790,7 @@ func TestPlanCreateTable(t *testing.T) {
 			CreateDefinitions: []ast.Definition{
 				&ast.ColumnDef{ColName: "id", DataType: ast.DataTypeVarchar},
 				&ast.ConstraintPrimaryKeyDef{Columns: []ast.ColumnId{*ast.NewColumnId("id")}},
-				&ast.ConstraintForeignKeyDef{KeyName: "fk_user", Column: *ast.NewColumnId("nonexistent"), RefTable: "users", RefColumn: "id"},
+				&ast.ConstraintForeignKeyDef{KeyName: "fk_user", Columns: []ast.ColumnId{*ast.NewColumnId("nonexistent")}, RefTable: "users", RefColumns: []string{"id"}},
 			},
 		}
 
@@ -653,10 +826,10 @@ func TestPlanCreateTable(t *testing.T) {
 				&ast.ColumnDef{ColName: "col1", DataType: ast.DataTypeVarchar},
 				&ast.ColumnDef{ColName: "col2", DataType: ast.DataTypeVarchar},
 				&ast.ConstraintPrimaryKeyDef{Columns: []ast.ColumnId{*ast.NewColumnId("id")}},
-				&ast.ConstraintKeyDef{KeyName: "idx_col1", Column: *ast.NewColumnId("col1")},
-				&ast.ConstraintKeyDef{KeyName: "idx_col2", Column: *ast.NewColumnId("col2")},
-				&ast.ConstraintForeignKeyDef{KeyName: "fk_dup", Column: *ast.NewColumnId("col1"), RefTable: "t1", RefColumn: "id"},
-				&ast.ConstraintForeignKeyDef{KeyName: "fk_dup", Column: *ast.NewColumnId("col2"), RefTable: "t2", RefColumn: "id"},
+				&ast.ConstraintKeyDef{KeyName: "idx_col1", Columns: []ast.ColumnId{*ast.NewColumnId("col1")}},
+				&ast.ConstraintKeyDef{KeyName: "idx_col2", Columns: []ast.ColumnId{*ast.NewColumnId("col2")}},
+				&ast.ConstraintForeignKeyDef{KeyName: "fk_dup", Columns: []ast.ColumnId{*ast.NewColumnId("col1")}, RefTable: "t1", RefColumns: []string{"id"}},
+				&ast.ConstraintForeignKeyDef{KeyName: "fk_dup", Columns: []ast.ColumnId{*ast.NewColumnId("col2")}, RefTable: "t2", RefColumns: []string{"id"}},
 			},
 		}
 
@@ -687,7 +860,7 @@ func TestPlanCreateTable(t *testing.T) {
 				&ast.ColumnDef{ColName: "user_id", DataType: ast.DataTypeVarchar},
 				&ast.ConstraintPrimaryKeyDef{Columns: []ast.ColumnId{*ast.NewColumnId("id")}},
 				// KEY idx_user_id (user_id) がない
-				&ast.ConstraintForeignKeyDef{KeyName: "fk_user", Column: *ast.NewColumnId("user_id"), RefTable: "users", RefColumn: "id"},
+				&ast.ConstraintForeignKeyDef{KeyName: "fk_user", Columns: []ast.ColumnId{*ast.NewColumnId("user_id")}, RefTable: "users", RefColumns: []string{"id"}},
 			},
 		}
 
@@ -699,4 +872,338 @@ func TestPlanCreateTable(t *testing.T) {
 		assert.Nil(t, exec)
 		assert.Contains(t, err.Error(), "foreign key column 'user_id' must have an index")
 	})
+
+	t.Run("プライマリキーの先頭カラムに AUTO_INCREMENT を指定したテーブルを作成できる", func(t *testing.T) {
+		// GIVEN
+		stmt := &ast.CreateTableStmt{
+			TableName: "users",
+			CreateDefinitions: []ast.Definition{
+				&ast.ColumnDef{ColName: "id", DataType: ast.DataTypeInt, AutoIncrement: true},
+				&ast.ColumnDef{ColName: "name", DataType: ast.DataTypeVarchar},
+				&ast.ConstraintPrimaryKeyDef{Columns: []ast.ColumnId{*ast.NewColumnId("id")}},
+			},
+		}
+
+		// WHEN
+		exec, err := PlanCreateTable(stmt)
+
+		// THEN
+		assert.NoError(t, err)
+		assert.IsType(t, &executor.CreateTable{}, exec)
+	})
+
+	t.Run("AUTO_INCREMENT カラムが複数ある場合、エラーを返す", func(t *testing.T) {
+		// GIVEN
+		stmt := &ast.CreateTableStmt{
+			TableName: "users",
+			CreateDefinitions: []ast.Definition{
+				&ast.ColumnDef{ColName: "id", DataType: ast.DataTypeInt, AutoIncrement: true},
+				&ast.ColumnDef{ColName: "seq", DataType: ast.DataTypeInt, AutoIncrement: true},
+				&ast.ConstraintPrimaryKeyDef{Columns: []ast.ColumnId{*ast.NewColumnId("id")}},
+			},
+		}
+
+		// WHEN
+		exec, err := PlanCreateTable(stmt)
+
+		// THEN
+		assert.Nil(t, exec)
+		assert.EqualError(t, err, "only one AUTO_INCREMENT column is allowed")
+	})
+
+	t.Run("AUTO_INCREMENT カラムが INT または BIGINT でない場合、エラーを返す", func(t *testing.T) {
+		// GIVEN
+		stmt := &ast.CreateTableStmt{
+			TableName: "users",
+			CreateDefinitions: []ast.Definition{
+				&ast.ColumnDef{ColName: "id", DataType: ast.DataTypeVarchar, AutoIncrement: true},
+				&ast.ConstraintPrimaryKeyDef{Columns: []ast.ColumnId{*ast.NewColumnId("id")}},
+			},
+		}
+
+		// WHEN
+		exec, err := PlanCreateTable(stmt)
+
+		// THEN
+		assert.Nil(t, exec)
+		assert.EqualError(t, err, "AUTO_INCREMENT column 'id' must be INT or BIGINT")
+	})
+
+	t.Run("AUTO_INCREMENT カラムがプライマリキーの先頭カラムでない場合、エラーを返す", func(t *testing.T) {
+		// GIVEN
+		stmt := &ast.CreateTableStmt{
+			TableName: "users",
+			CreateDefinitions: []ast.Definition{
+				&ast.ColumnDef{ColName: "id", DataType: ast.DataTypeInt},
+				&ast.ColumnDef{ColName: "seq", DataType: ast.DataTypeInt, AutoIncrement: true},
+				&ast.ConstraintPrimaryKeyDef{Columns: []ast.ColumnId{*ast.NewColumnId("id")}},
+			},
+		}
+
+		// WHEN
+		exec, err := PlanCreateTable(stmt)
+
+		// THEN
+		assert.Nil(t, exec)
+		assert.EqualError(t, err, "AUTO_INCREMENT column 'seq' must be the first column of the primary key")
+	})
+
+	t.Run("NOT NULL のカラムに DEFAULT NULL が指定されている場合、エラーを返す", func(t *testing.T) {
+		// GIVEN
+		stmt := &ast.CreateTableStmt{
+			TableName: "users",
+			CreateDefinitions: []ast.Definition{
+				&ast.ColumnDef{ColName: "id", DataType: ast.DataTypeInt},
+				&ast.ColumnDef{ColName: "name", DataType: ast.DataTypeVarchar, NotNull: true, Default: ast.NewNullLiteral(), DefaultText: "NULL"},
+				&ast.ConstraintPrimaryKeyDef{Columns: []ast.ColumnId{*ast.NewColumnId("id")}},
+			},
+		}
+
+		// WHEN
+		exec, err := PlanCreateTable(stmt)
+
+		// THEN
+		assert.Nil(t, exec)
+		assert.EqualError(t, err, "invalid default value for column 'name'")
+	})
+
+	t.Run("プライマリキーのカラムに DEFAULT NULL が指定されている場合、エラーを返す", func(t *testing.T) {
+		// GIVEN
+		stmt := &ast.CreateTableStmt{
+			TableName: "users",
+			CreateDefinitions: []ast.Definition{
+				&ast.ColumnDef{ColName: "id", DataType: ast.DataTypeInt, Default: ast.NewNullLiteral(), DefaultText: "NULL"},
+				&ast.ConstraintPrimaryKeyDef{Columns: []ast.ColumnId{*ast.NewColumnId("id")}},
+			},
+		}
+
+		// WHEN
+		exec, err := PlanCreateTable(stmt)
+
+		// THEN
+		assert.Nil(t, exec)
+		assert.EqualError(t, err, "invalid default value for column 'id'")
+	})
+
+	t.Run("CHECK 制約付きのテーブルを作成できる", func(t *testing.T) {
+		// GIVEN
+		initStorageManagerForTest(t)
+		defer handler.Reset()
+
+		stmt := &ast.CreateTableStmt{
+			TableName: "users",
+			CreateDefinitions: []ast.Definition{
+				&ast.ColumnDef{ColName: "id", DataType: ast.DataTypeInt},
+				&ast.ColumnDef{ColName: "age", DataType: ast.DataTypeInt},
+				&ast.ConstraintPrimaryKeyDef{Columns: []ast.ColumnId{*ast.NewColumnId("id")}},
+				&ast.ConstraintCheckDef{Expr: parseExprForTest(t, "age >= 0"), ExprText: "age >= 0"},
+			},
+		}
+
+		// WHEN
+		exec, err := PlanCreateTable(stmt)
+		assert.NoError(t, err)
+		_, err = exec.Next()
+
+		// THEN
+		assert.NoError(t, err)
+		tblMeta, ok := handler.Get().Catalog.GetTableMetaByName("users")
+		assert.True(t, ok)
+		checks := tblMeta.GetCheckConstraints()
+		assert.Equal(t, 1, len(checks))
+		assert.Equal(t, "users_chk_1", checks[0].ConstraintName)
+		assert.Equal(t, "age >= 0", checks[0].CheckExpr)
+	})
+
+	t.Run("CHECK 制約の条件式に存在しないカラムが含まれる場合、エラーを返す", func(t *testing.T) {
+		// GIVEN
+		initStorageManagerForTest(t)
+		defer handler.Reset()
+
+		stmt := &ast.CreateTableStmt{
+			TableName: "users",
+			CreateDefinitions: []ast.Definition{
+				&ast.ColumnDef{ColName: "id", DataType: ast.DataTypeInt},
+				&ast.ConstraintPrimaryKeyDef{Columns: []ast.ColumnId{*ast.NewColumnId("id")}},
+				&ast.ConstraintCheckDef{KeyName: "chk_age", Expr: parseExprForTest(t, "age >= 0"), ExprText: "age >= 0"},
+			},
+		}
+
+		// WHEN
+		exec, err := PlanCreateTable(stmt)
+
+		// THEN
+		assert.Nil(t, exec)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid check constraint 'chk_age'")
+	})
+
+	t.Run("CHECK 制約の条件式に CASCADE の FK カラムが含まれる場合、エラーを返す", func(t *testing.T) {
+		// GIVEN
+		initStorageManagerForTest(t)
+		defer handler.Reset()
+
+		stmt := &ast.CreateTableStmt{
+			TableName: "employees",
+			CreateDefinitions: []ast.Definition{
+				&ast.ColumnDef{ColName: "id", DataType: ast.DataTypeInt},
+				&ast.ColumnDef{ColName: "manager_id", DataType: ast.DataTypeInt},
+				&ast.ConstraintPrimaryKeyDef{Columns: []ast.ColumnId{*ast.NewColumnId("id")}},
+				&ast.ConstraintKeyDef{KeyName: "idx_manager_id", Columns: []ast.ColumnId{*ast.NewColumnId("manager_id")}},
+				&ast.ConstraintForeignKeyDef{KeyName: "fk_manager", Columns: []ast.ColumnId{*ast.NewColumnId("manager_id")}, RefTable: "employees", RefColumns: []string{"id"}, OnUpdate: ast.ReferentialActionCascade},
+				&ast.ConstraintCheckDef{KeyName: "chk_manager", Expr: parseExprForTest(t, "manager_id <> id"), ExprText: "manager_id <> id"},
+			},
+		}
+
+		// WHEN
+		exec, err := PlanCreateTable(stmt)
+
+		// THEN
+		assert.Error(t, err)
+		assert.Nil(t, exec)
+		assert.Contains(t, err.Error(), "column 'manager_id' cannot be used in a check constraint 'chk_manager': needed in a foreign key constraint 'fk_manager' referential action")
+	})
+
+	t.Run("CHECK 制約名が重複する場合、エラーを返す", func(t *testing.T) {
+		// GIVEN
+		initStorageManagerForTest(t)
+		defer handler.Reset()
+
+		check := &ast.ConstraintCheckDef{KeyName: "chk_id", Expr: parseExprForTest(t, "id > 0"), ExprText: "id > 0"}
+		stmt := &ast.CreateTableStmt{
+			TableName: "users",
+			CreateDefinitions: []ast.Definition{
+				&ast.ColumnDef{ColName: "id", DataType: ast.DataTypeInt},
+				&ast.ConstraintPrimaryKeyDef{Columns: []ast.ColumnId{*ast.NewColumnId("id")}},
+				check,
+				check,
+			},
+		}
+
+		// WHEN
+		exec, err := PlanCreateTable(stmt)
+
+		// THEN
+		assert.Nil(t, exec)
+		assert.EqualError(t, err, "duplicate check constraint name: 'chk_id'")
+	})
+}
+
+func TestGetColumnParam(t *testing.T) {
+	t.Run("AST のデータ型をストレージのデータ型に変換する", func(t *testing.T) {
+		tests := []struct {
+			def      *ast.ColumnDef
+			expected handler.CreateColumnParam
+		}{
+			{&ast.ColumnDef{ColName: "a", DataType: ast.DataTypeVarchar}, handler.CreateColumnParam{Name: "a", Type: handler.ColumnTypeString}},
+			{&ast.ColumnDef{ColName: "b", DataType: ast.DataTypeInt}, handler.CreateColumnParam{Name: "b", Type: handler.ColumnTypeInt}},
+			{&ast.ColumnDef{ColName: "c", DataType: ast.DataTypeBigInt}, handler.CreateColumnParam{Name: "c", Type: handler.ColumnTypeBigInt}},
+			{&ast.ColumnDef{ColName: "d", DataType: ast.DataTypeDecimal, Precision: 10, Scale: 2}, handler.CreateColumnParam{Name: "d", Type: handler.ColumnTypeDecimal, Precision: 10, Scale: 2}},
+			{&ast.ColumnDef{ColName: "e", DataType: ast.DataTypeDate}, handler.CreateColumnParam{Name: "e", Type: handler.ColumnTypeDate}},
+			{&ast.ColumnDef{ColName: "f", DataType: ast.DataTypeDatetime}, handler.CreateColumnParam{Name: "f", Type: handler.ColumnTypeDatetime}},
+		}
+		for _, tt := range tests {
+			// WHEN
+			param, err := getColumnParam(tt.def)
+
+			// THEN
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, param)
+		}
+	})
+
+	t.Run("DECIMAL の精度が範囲外の場合、エラーを返す", func(t *testing.T) {
+		// GIVEN
+		def := &ast.ColumnDef{ColName: "price", DataType: ast.DataTypeDecimal, Precision: 19}
+
+		// WHEN
+		_, err := getColumnParam(def)
+
+		// THEN
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid DECIMAL(19, 0) for column 'price'")
+	})
+
+	t.Run("DEFAULT 句の式を評価した値を設定する", func(t *testing.T) {
+		// GIVEN
+		def := &ast.ColumnDef{ColName: "status", DataType: ast.DataTypeVarchar, Default: parseExprForTest(t, "CONCAT('act', 'ive')"), DefaultText: "CONCAT('act', 'ive')"}
+
+		// WHEN
+		param, err := getColumnParam(def)
+
+		// THEN
+		assert.NoError(t, err)
+		assert.Equal(t, "CONCAT('act', 'ive')", param.Default)
+		assert.Equal(t, []byte("active"), param.DefaultValue)
+	})
+
+	t.Run("DEFAULT 句の式がカラムを参照している場合、エラーを返す", func(t *testing.T) {
+		// GIVEN
+		def := &ast.ColumnDef{ColName: "age", DataType: ast.DataTypeInt, Default: parseExprForTest(t, "id"), DefaultText: "id"}
+
+		// WHEN
+		_, err := getColumnParam(def)
+
+		// THEN
+		assert.EqualError(t, err, "default value of column 'age' cannot refer to column 'id'")
+	})
+
+	t.Run("AUTO_INCREMENT カラムに DEFAULT 句が指定されている場合、エラーを返す", func(t *testing.T) {
+		// GIVEN
+		def := &ast.ColumnDef{ColName: "id", DataType: ast.DataTypeInt, AutoIncrement: true, Default: ast.NewStringLiteral("1"), DefaultText: "1"}
+
+		// WHEN
+		_, err := getColumnParam(def)
+
+		// THEN
+		assert.EqualError(t, err, "invalid default value for column 'id'")
+	})
+
+	t.Run("FK カラムと参照先カラムのデータ型が異なる場合、エラーを返す", func(t *testing.T) {
+		// GIVEN
+		initStorageManagerForTest(t)
+		defer handler.Reset()
+
+		parentTable := executor.NewCreateTable("users", 1, nil, []handler.CreateColumnParam{
+			{Name: "id", Type: handler.ColumnTypeInt},
+		}, nil)
+		_, err := parentTable.Next()
+		assert.NoError(t, err)
+
+		stmt := &ast.CreateTableStmt{
+			TableName: "orders",
+			CreateDefinitions: []ast.Definition{
+				&ast.ColumnDef{ColName: "id", DataType: ast.DataTypeInt},
+				&ast.ColumnDef{ColName: "user_id", DataType: ast.DataTypeVarchar},
+				&ast.ConstraintPrimaryKeyDef{Columns: []ast.ColumnId{*ast.NewColumnId("id")}},
+				&ast.ConstraintKeyDef{KeyName: "idx_user_id", Columns: []ast.ColumnId{*ast.NewColumnId("user_id")}},
+				&ast.ConstraintForeignKeyDef{KeyName: "fk_user", Columns: []ast.ColumnId{*ast.NewColumnId("user_id")}, RefTable: "users", RefColumns: []string{"id"}},
+			},
+		}
+
+		// WHEN
+		exec, err := PlanCreateTable(stmt)
+
+		// THEN
+		assert.Error(t, err)
+		assert.Nil(t, exec)
+		assert.Contains(t, err.Error(), "are incompatible")
+	})
+}
+
+// createCompositeKeyUsersForTest は複合主キー (tenant_id, id) の users テーブルを作成する
+func createCompositeKeyUsersForTest(t *testing.T) {
+	t.Helper()
+	_, err := executor.NewCreateTable("users", 2, nil, []handler.CreateColumnParam{
+		{Name: "tenant_id", Type: "string"},
+		{Name: "id", Type: "string"},
+	}, nil).Next()
+	assert.NoError(t, err)
+}
+
+// 式の SQL 表現を AST に変換する
+func parseExprForTest(t *testing.T, sql string) ast.Expr {
+	expr, err := parser.ParseExpr(sql)
+	assert.NoError(t, err)
+	return expr
 }
@@ -2,6 +2,7 @@ package planner
 
 import (
 	"fmt"
+	"slices"
 
 	"github.com/ren-yamanashi/minesql/internal/ast"
 	"github.com/ren-yamanashi/minesql/internal/executor"
@@ -11,8 +12,26 @@ import (
 
 // PlanDelete は DELETE 文の実行計画を構築する
 func PlanDelete(trxId handler.TrxId, stmt *ast.DeleteStmt) (executor.Executor, error) {
+	if len(stmt.Joins) > 0 {
+		return planDeleteJoin(trxId, stmt)
+	}
+	if stmt.Target != "" && stmt.Target != stmt.From.TableName {
+		return nil, fmt.Errorf("unknown table '%s' in multi-table DELETE", stmt.Target)
+	}
+
 	hdl := handler.Get()
 
+	// 対象がビューの場合は、ビューが参照するテーブルに対する DELETE に変換する
+	if view, ok := hdl.Catalog.GetViewByName(stmt.From.TableName); ok {
+		updatable, err := resolveUpdatableView(view, nil)
+		if err != nil {
+			return nil, err
+		}
+		if stmt, err = updatable.rewriteDelete(stmt); err != nil {
+			return nil, err
+		}
+	}
+
 	// 対象テーブルのメタデータを取得
 	tblMeta, ok := hdl.Catalog.GetTableMetaByName(stmt.From.TableName)
 	if !ok {
@@ -29,10 +48,54 @@ func PlanDelete(trxId handler.TrxId, stmt *ast.DeleteStmt) (executor.Executor, e
 	}
 
 	// テーブルを取得
-	tbl, err := hdl.GetTable(stmt.From.TableName)
+	tbl, err := hdl.OpenTable(trxId, stmt.From.TableName)
 	if err != nil {
 		return nil, err
 	}
 
 	return executor.NewDelete(trxId, tbl, iterator), nil
 }
+
+// planDeleteJoin は JOIN 句を含む DELETE (複数テーブルの DELETE) の実行計画を構築する
+//
+// SELECT と同じ方法で結合レコードを返す Executor ツリーを構築し (Current Read)、結合レコードから取り出した削除対象のテーブルの行を Delete で削除する
+func planDeleteJoin(trxId handler.TrxId, stmt *ast.DeleteStmt) (executor.Executor, error) {
+	hdl := handler.Get()
+
+	// 削除対象のテーブルは FROM 句・JOIN 句のテーブルのいずれか
+	isJoinedTable := stmt.Target == stmt.From.TableName || slices.ContainsFunc(stmt.Joins, func(join *ast.JoinClause) bool {
+		return join.Table.TableName == stmt.Target
+	})
+	if !isJoinedTable {
+		return nil, fmt.Errorf("unknown table '%s' in multi-table DELETE", stmt.Target)
+	}
+
+	rv := access.NewReadView(0, nil, ^uint64(0))
+	vr := access.NewVersionReader(nil)
+	sel := &ast.SelectStmt{From: stmt.From, Joins: stmt.Joins, Where: stmt.Where}
+	if err := bindViews(sel, nil); err != nil {
+		return nil, err
+	}
+	join, err := planJoin(trxId, rv, vr, sel, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	tblMeta, ok := hdl.Catalog.GetTableMetaByName(stmt.Target)
+	if !ok {
+		return nil, fmt.Errorf("table %s not found", stmt.Target)
+	}
+	offset, err := findTableOffset(join.columns, stmt.Target)
+	if err != nil {
+		return nil, err
+	}
+
+	tbl, err := hdl.OpenTable(trxId, stmt.Target)
+	if err != nil {
+		return nil, err
+	}
+
+	del := executor.NewDelete(trxId, tbl, join.exec)
+	del.SetJoinTarget(offset, int(tblMeta.NCols))
+	return del, nil
+}
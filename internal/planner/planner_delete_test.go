@@ -159,3 +159,70 @@ func TestPlanDelete(t *testing.T) {
 		assert.False(t, ok)
 	})
 }
+
+func TestPlanDeleteJoin(t *testing.T) {
+	t.Run("結合したテーブルの条件で削除対象のテーブルの行を削除する", func(t *testing.T) {
+		// GIVEN
+		setupOrdersTable(t)
+		stmt := parseStatementForTest(t, "DELETE orders FROM orders JOIN users ON orders.user_id = users.id WHERE users.gender = 'female';")
+
+		// WHEN
+		executePlan(t, stmt)
+
+		// THEN
+		records := executePlan(t, &ast.SelectStmt{From: *ast.NewTableId("orders")})
+		assert.Equal(t, []string{"1", "2", "4"}, columnStrings(records, 0))
+	})
+
+	t.Run("LEFT JOIN で結合相手のない行を削除する", func(t *testing.T) {
+		// GIVEN
+		setupOrdersTable(t)
+		stmt := parseStatementForTest(t, "DELETE orders FROM orders LEFT JOIN users ON orders.user_id = users.id WHERE users.id IS NULL;")
+
+		// WHEN
+		executePlan(t, stmt)
+
+		// THEN
+		records := executePlan(t, &ast.SelectStmt{From: *ast.NewTableId("orders")})
+		assert.Equal(t, []string{"1", "2", "3"}, columnStrings(records, 0))
+	})
+
+	t.Run("複数の結合レコードに含まれる行は 1 回だけ削除する", func(t *testing.T) {
+		// GIVEN
+		setupOrdersTable(t)
+		stmt := parseStatementForTest(t, "DELETE users FROM users JOIN orders ON orders.user_id = users.id;")
+
+		// WHEN
+		executePlan(t, stmt)
+
+		// THEN
+		records := executePlan(t, &ast.SelectStmt{From: *ast.NewTableId("users")})
+		assert.Equal(t, []string{"2", "3", "5", "6"}, columnStrings(records, 0))
+	})
+
+	t.Run("外部結合で NULL で補完された削除対象のテーブルの行は削除しない", func(t *testing.T) {
+		// GIVEN
+		setupOrdersTable(t)
+		stmt := parseStatementForTest(t, "DELETE users FROM orders LEFT JOIN users ON orders.user_id = users.id;")
+
+		// WHEN
+		executePlan(t, stmt)
+
+		// THEN
+		records := executePlan(t, &ast.SelectStmt{From: *ast.NewTableId("users")})
+		assert.Equal(t, []string{"2", "3", "5", "6"}, columnStrings(records, 0))
+	})
+
+	t.Run("削除対象のテーブルが FROM 句・JOIN 句にない場合、エラーを返す", func(t *testing.T) {
+		// GIVEN
+		setupOrdersTable(t)
+		stmt := parseStatementForTest(t, "DELETE products FROM orders JOIN users ON orders.user_id = users.id;")
+
+		// WHEN
+		exec, err := PlanDelete(1, stmt.(*ast.DeleteStmt))
+
+		// THEN
+		assert.Nil(t, exec)
+		assert.ErrorContains(t, err, "unknown table 'products' in multi-table DELETE")
+	})
+}
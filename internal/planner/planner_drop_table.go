@@ -0,0 +1,21 @@
+package planner
+
+import (
+	"fmt"
+
+	"github.com/ren-yamanashi/minesql/internal/ast"
+	"github.com/ren-yamanashi/minesql/internal/executor"
+	"github.com/ren-yamanashi/minesql/internal/storage/handler"
+)
+
+// PlanDropTable は DROP TABLE 文のバリデーションを行い、DropTable executor を構築する
+func PlanDropTable(trxId handler.TrxId, stmt *ast.DropTableStmt) (executor.Executor, error) {
+	hdl := handler.Get()
+
+	// IF EXISTS が指定されていない場合、対象テーブルがカタログに存在するか検証
+	if _, ok := hdl.Catalog.GetTableMetaByName(stmt.TableName); !ok && !stmt.IfExists {
+		return nil, fmt.Errorf("table %s not found", stmt.TableName)
+	}
+
+	return executor.NewDropTable(trxId, stmt.TableName, stmt.IfExists), nil
+}
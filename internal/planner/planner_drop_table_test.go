@@ -0,0 +1,67 @@
+package planner
+
+import (
+	"testing"
+
+	"github.com/ren-yamanashi/minesql/internal/ast"
+	"github.com/ren-yamanashi/minesql/internal/executor"
+	"github.com/ren-yamanashi/minesql/internal/storage/handler"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlanDropTable(t *testing.T) {
+	t.Run("存在するテーブルに対して DropTable executor を返す", func(t *testing.T) {
+		// GIVEN
+		setupUsersTable(t)
+		defer handler.Reset()
+		stmt := &ast.DropTableStmt{TableName: "users"}
+
+		// WHEN
+		exec, err := PlanDropTable(0, stmt)
+
+		// THEN
+		assert.NoError(t, err)
+		assert.IsType(t, &executor.DropTable{}, exec)
+	})
+
+	t.Run("存在しないテーブルの場合エラーを返す", func(t *testing.T) {
+		// GIVEN
+		setupUsersTable(t)
+		defer handler.Reset()
+		stmt := &ast.DropTableStmt{TableName: "orders"}
+
+		// WHEN
+		exec, err := PlanDropTable(0, stmt)
+
+		// THEN
+		assert.Nil(t, exec)
+		assert.ErrorContains(t, err, "table orders not found")
+	})
+
+	t.Run("IF EXISTS の場合は存在しないテーブルでもエラーを返さない", func(t *testing.T) {
+		// GIVEN
+		setupUsersTable(t)
+		defer handler.Reset()
+		stmt := &ast.DropTableStmt{TableName: "orders", IfExists: true}
+
+		// WHEN
+		exec, err := PlanDropTable(0, stmt)
+
+		// THEN
+		assert.NoError(t, err)
+		assert.IsType(t, &executor.DropTable{}, exec)
+	})
+
+	t.Run("実行するとテーブルが削除される", func(t *testing.T) {
+		// GIVEN
+		setupUsersTable(t)
+		defer handler.Reset()
+
+		// WHEN
+		executePlan(t, &ast.DropTableStmt{TableName: "users"})
+
+		// THEN
+		_, ok := handler.Get().Catalog.GetTableMetaByName("users")
+		assert.False(t, ok)
+	})
+}
@@ -0,0 +1,74 @@
+package planner
+
+import (
+	"fmt"
+
+	"github.com/ren-yamanashi/minesql/internal/ast"
+	"github.com/ren-yamanashi/minesql/internal/executor"
+	"github.com/ren-yamanashi/minesql/internal/storage/handler"
+)
+
+// explainColumns は EXPLAIN の結果セットのカラム名
+var explainColumns = []string{"operation", "detail", "access", "est_rows", "est_cost"}
+
+// explainAnalyzeColumns は EXPLAIN ANALYZE で追加する結果セットのカラム名
+var explainAnalyzeColumns = []string{"actual_rows", "loops", "actual_time_ms"}
+
+// PlanExplain は EXPLAIN [ANALYZE] を計画する
+//
+// 対象の文を通常どおり計画し、その Executor のツリーを 1 ノード 1 行で返す Executor で包む
+func PlanExplain(trxId handler.TrxId, stmt *ast.ExplainStmt) (*PlanResult, error) {
+	switch stmt.Stmt.(type) {
+	case *ast.SelectStmt, *ast.SetOperationStmt, *ast.UpdateStmt, *ast.DeleteStmt:
+	default:
+		return nil, fmt.Errorf("EXPLAIN is not supported for %T", stmt.Stmt)
+	}
+
+	plan, err := Start(trxId, stmt.Stmt)
+	if err != nil {
+		return nil, err
+	}
+
+	names := explainColumns
+	if stmt.Analyze {
+		names = append(names[:len(names):len(names)], explainAnalyzeColumns...)
+	}
+	columns := make([]ColumnMeta, len(names))
+	for i, name := range names {
+		columns[i] = ColumnMeta{ColName: name, Type: handler.ColumnTypeString}
+	}
+
+	return &PlanResult{
+		Exec:    executor.NewExplain(plan.Exec, stmt.Analyze),
+		Columns: columns,
+	}, nil
+}
+
+// planEstimate はプランを選んだときの見積もり行数とコスト
+type planEstimate struct {
+	rows float64
+	cost float64
+}
+
+// estimated は計画時に算出済みの見積もりを返す関数を返す
+func estimated(rows, cost float64) func() (float64, float64, error) {
+	return func() (float64, float64, error) {
+		return rows, cost, nil
+	}
+}
+
+// joinNote は結合の Executor に設定する PlanNote を返す (結合後の行数と累積コスト)
+func (c joinCandidate) joinNote() executor.PlanNote {
+	return executor.PlanNote{Estimate: estimated(c.est.rows, c.est.cost)}
+}
+
+// innerNote は Nested Loop Join の内部表の Executor に設定する PlanNote を返す
+//
+// 見積もりは外側の 1 行あたりの行数 (fanout) とコスト (readCost を外側の行数で割ったもの)
+func (c joinCandidate) innerNote(access string) executor.PlanNote {
+	cost := c.est.readCost
+	if c.est.prefixRows > 0 {
+		cost /= c.est.prefixRows
+	}
+	return executor.PlanNote{Access: access, Estimate: estimated(c.est.fanout, cost)}
+}
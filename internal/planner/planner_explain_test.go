@@ -0,0 +1,211 @@
+package planner
+
+import (
+	"testing"
+
+	"github.com/ren-yamanashi/minesql/internal/ast"
+	"github.com/ren-yamanashi/minesql/internal/executor"
+	"github.com/ren-yamanashi/minesql/internal/storage/handler"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// explainRecords は EXPLAIN の結果を文字列の 2 次元配列で返す (NULL は空文字列)
+func explainRecords(t *testing.T, stmt *ast.ExplainStmt) [][]string {
+	t.Helper()
+	var rows [][]string
+	for _, record := range executePlan(t, stmt) {
+		row := make([]string, len(record))
+		for i, value := range record {
+			row[i] = string(value)
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+func TestPlanExplain(t *testing.T) {
+	t.Run("EXPLAIN の結果セットのカラムを返す", func(t *testing.T) {
+		// GIVEN
+		setupUsersTable(t)
+		defer handler.Reset()
+		stmt := &ast.ExplainStmt{Stmt: &ast.SelectStmt{From: *ast.NewTableId("users")}}
+
+		// WHEN
+		result, err := PlanExplain(0, stmt)
+
+		// THEN
+		require.NoError(t, err)
+		assert.IsType(t, &executor.Explain{}, result.Exec)
+		names := make([]string, len(result.Columns))
+		for i, col := range result.Columns {
+			names[i] = col.ColName
+			assert.Equal(t, handler.ColumnTypeString, col.Type)
+		}
+		assert.Equal(t, []string{"operation", "detail", "access", "est_rows", "est_cost"}, names)
+	})
+
+	t.Run("EXPLAIN ANALYZE では実行結果のカラムを追加する", func(t *testing.T) {
+		// GIVEN
+		setupUsersTable(t)
+		defer handler.Reset()
+		stmt := &ast.ExplainStmt{Analyze: true, Stmt: &ast.SelectStmt{From: *ast.NewTableId("users")}}
+
+		// WHEN
+		result, err := PlanExplain(0, stmt)
+
+		// THEN
+		require.NoError(t, err)
+		require.Len(t, result.Columns, 8)
+		assert.Equal(t, "actual_rows", result.Columns[5].ColName)
+		assert.Equal(t, "loops", result.Columns[6].ColName)
+		assert.Equal(t, "actual_time_ms", result.Columns[7].ColName)
+	})
+
+	t.Run("選択したアクセス方法と見積もりを返す", func(t *testing.T) {
+		eq := func(colName, value string) *ast.WhereClause {
+			return &ast.WhereClause{Condition: ast.NewBinaryExpr("=",
+				ast.NewLhsColumn(*ast.NewColumnId(colName)),
+				ast.NewRhsLiteral(ast.NewStringLiteral(value)),
+			)}
+		}
+		tests := []struct {
+			name     string
+			where    *ast.WhereClause
+			expected []string
+		}{
+			{"条件がない場合はフルスキャン", nil, []string{"└── TableScan", "users", "full scan", "6.00"}},
+			{"PK の等値条件の場合は PK lookup", eq("id", "3"), []string{"└── TableScan", "users", "PK lookup", "1.00", "1.00"}},
+			{"UNIQUE KEY の等値条件の場合は index lookup", eq("username", "janedoe"), []string{"└── IndexScan", "users.username", "index lookup", "1.00", "1.00"}},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				// GIVEN
+				setupUsersTable(t)
+				defer handler.Reset()
+				stmt := &ast.ExplainStmt{Stmt: &ast.SelectStmt{From: *ast.NewTableId("users"), Where: tt.where}}
+
+				// WHEN
+				rows := explainRecords(t, stmt)
+
+				// THEN
+				require.Len(t, rows, 2)
+				assert.Equal(t, "Project", rows[0][0])
+				assert.Equal(t, tt.expected, rows[1][:len(tt.expected)])
+			})
+		}
+	})
+
+	t.Run("定数式との比較でも、リテラルとの比較と同じように PK / インデックスのアクセス方法を選ぶ", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			setup    func(t *testing.T)
+			sql      string
+			literal  string
+			expected []string
+		}{
+			{"PK の等値条件", setupCategoriesTable, "EXPLAIN SELECT name FROM categories WHERE id = 1 + 2;", "EXPLAIN SELECT name FROM categories WHERE id = 3;", []string{"└── TableScan", "categories", "PK lookup", "1.00", "1.00"}},
+			{"UNIQUE KEY の等値条件", setupUsersTable, "EXPLAIN SELECT * FROM users WHERE username = CONCAT('jane', 'doe');", "EXPLAIN SELECT * FROM users WHERE username = 'janedoe';", []string{"└── IndexScan", "users.username", "index lookup", "1.00", "1.00"}},
+			{"PK の範囲条件", setupCategoriesTable, "EXPLAIN SELECT name FROM categories WHERE id BETWEEN 1 + 1 AND 2 * 2;", "EXPLAIN SELECT name FROM categories WHERE id BETWEEN 2 AND 4;", nil},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				// GIVEN
+				tt.setup(t)
+				defer handler.Reset()
+
+				// WHEN
+				rows := explainRecords(t, parseStatementForTest(t, tt.sql).(*ast.ExplainStmt))
+				literalRows := explainRecords(t, parseStatementForTest(t, tt.literal).(*ast.ExplainStmt))
+
+				// THEN
+				assert.Equal(t, literalRows, rows)
+				if tt.expected != nil {
+					require.Len(t, rows, 2)
+					assert.Equal(t, tt.expected, rows[1][:len(tt.expected)])
+				}
+			})
+		}
+	})
+
+	t.Run("結合順序と内部表のアクセス方法を返す", func(t *testing.T) {
+		// GIVEN
+		setupLoginsTable(t)
+		defer handler.Reset()
+		stmt := &ast.ExplainStmt{Stmt: &ast.SelectStmt{
+			From: *ast.NewTableId("logins"),
+			Joins: []*ast.JoinClause{{
+				Table: *ast.NewTableId("users"),
+				Condition: ast.NewBinaryExpr("=",
+					ast.NewLhsColumn(ast.ColumnId{TableName: "logins", ColName: "username"}),
+					ast.NewRhsColumn(ast.ColumnId{TableName: "users", ColName: "username"}),
+				),
+			}},
+		}}
+
+		// WHEN
+		rows := explainRecords(t, stmt)
+
+		// THEN: 内部表の見積もりは外側の 1 行あたりの値
+		require.Len(t, rows, 4)
+		assert.Equal(t, []string{"└── NestedLoopJoin", "INNER", "", "3.00"}, rows[1][:4])
+		assert.Equal(t, []string{"    ├── TableScan", "logins", "full scan", "3.00"}, rows[2][:4])
+		assert.Equal(t, []string{"    └── IndexScan", "users.username", "index lookup", "1.00"}, rows[3][:4])
+	})
+
+	t.Run("EXPLAIN ANALYZE では内部表のループ回数を返す", func(t *testing.T) {
+		// GIVEN
+		setupLoginsTable(t)
+		defer handler.Reset()
+		stmt := &ast.ExplainStmt{Analyze: true, Stmt: &ast.SelectStmt{
+			From: *ast.NewTableId("logins"),
+			Joins: []*ast.JoinClause{{
+				Table: *ast.NewTableId("users"),
+				Condition: ast.NewBinaryExpr("=",
+					ast.NewLhsColumn(ast.ColumnId{TableName: "logins", ColName: "username"}),
+					ast.NewRhsColumn(ast.ColumnId{TableName: "users", ColName: "username"}),
+				),
+			}},
+		}}
+
+		// WHEN
+		rows := explainRecords(t, stmt)
+
+		// THEN: logins の 3 行それぞれに対して users を 1 回ずつ検索する
+		require.Len(t, rows, 4)
+		assert.Equal(t, []string{"3", "1"}, rows[1][5:7])
+		assert.Equal(t, []string{"3", "1"}, rows[2][5:7])
+		assert.Equal(t, []string{"3", "3"}, rows[3][5:7])
+	})
+
+	t.Run("EXPLAIN ANALYZE DELETE は実際に削除し、見積もりは削除前の行数を返す", func(t *testing.T) {
+		// GIVEN
+		setupLoginsTable(t)
+		defer handler.Reset()
+		stmt := &ast.ExplainStmt{Analyze: true, Stmt: &ast.DeleteStmt{From: *ast.NewTableId("logins")}}
+
+		// WHEN
+		rows := explainRecords(t, stmt)
+
+		// THEN
+		require.Len(t, rows, 2)
+		assert.Equal(t, []string{"Delete", "logins"}, rows[0][:2])
+		assert.Equal(t, []string{"└── TableScan", "logins", "full scan", "3.00"}, rows[1][:4])
+		assert.Equal(t, "3", rows[1][5])
+		assert.Empty(t, executePlan(t, &ast.SelectStmt{From: *ast.NewTableId("logins")}))
+	})
+
+	t.Run("SELECT・UPDATE・DELETE 以外の文はエラーを返す", func(t *testing.T) {
+		// GIVEN
+		setupUsersTable(t)
+		defer handler.Reset()
+		stmt := &ast.ExplainStmt{Stmt: &ast.InsertStmt{Table: *ast.NewTableId("users")}}
+
+		// WHEN
+		result, err := PlanExplain(0, stmt)
+
+		// THEN
+		assert.Nil(t, result)
+		assert.ErrorContains(t, err, "EXPLAIN is not supported")
+	})
+}
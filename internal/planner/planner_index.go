@@ -0,0 +1,33 @@
+package planner
+
+import (
+	"fmt"
+
+	"github.com/ren-yamanashi/minesql/internal/ast"
+	"github.com/ren-yamanashi/minesql/internal/executor"
+	"github.com/ren-yamanashi/minesql/internal/storage/handler"
+)
+
+// PlanCreateIndex は CREATE INDEX 文のバリデーションを行い、AlterTable executor を構築する
+//
+// ALTER TABLE ... ADD [UNIQUE] KEY と同じ検証を行う
+func PlanCreateIndex(trxId handler.TrxId, stmt *ast.CreateIndexStmt) (executor.Executor, error) {
+	tblMeta, ok := handler.Get().Catalog.GetTableMetaByName(stmt.TableName)
+	if !ok {
+		return nil, fmt.Errorf("table %s not found", stmt.TableName)
+	}
+
+	var def ast.Definition = &ast.ConstraintKeyDef{KeyName: stmt.IndexName, Columns: stmt.Columns}
+	if stmt.Unique {
+		def = &ast.ConstraintUniqueKeyDef{KeyName: stmt.IndexName, Columns: stmt.Columns}
+	}
+	return planAddConstraint(trxId, tblMeta, def)
+}
+
+// PlanDropIndex は DROP INDEX 文のバリデーションを行い、AlterTable executor を構築する
+func PlanDropIndex(trxId handler.TrxId, stmt *ast.DropIndexStmt) (executor.Executor, error) {
+	if _, ok := handler.Get().Catalog.GetTableMetaByName(stmt.TableName); !ok {
+		return nil, fmt.Errorf("table %s not found", stmt.TableName)
+	}
+	return executor.NewDropIndex(trxId, stmt.TableName, stmt.IndexName), nil
+}
@@ -0,0 +1,126 @@
+package planner
+
+import (
+	"testing"
+
+	"github.com/ren-yamanashi/minesql/internal/ast"
+	"github.com/ren-yamanashi/minesql/internal/executor"
+	"github.com/ren-yamanashi/minesql/internal/storage/handler"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlanCreateIndex(t *testing.T) {
+	t.Run("AlterTable executor を返す", func(t *testing.T) {
+		// GIVEN
+		setupUsersTable(t)
+		defer handler.Reset()
+		stmt := &ast.CreateIndexStmt{
+			IndexName: "idx_last_first",
+			TableName: "users",
+			Columns:   []ast.ColumnId{*ast.NewColumnId("last_name"), *ast.NewColumnId("first_name")},
+		}
+
+		// WHEN
+		exec, err := PlanCreateIndex(0, stmt)
+
+		// THEN
+		assert.NoError(t, err)
+		assert.IsType(t, &executor.AlterTable{}, exec)
+	})
+
+	t.Run("不正な CREATE INDEX 文の場合エラーを返す", func(t *testing.T) {
+		setupUsersTable(t)
+		defer handler.Reset()
+
+		tests := []struct {
+			name     string
+			stmt     *ast.CreateIndexStmt
+			expected string
+		}{
+			{"テーブルが存在しない場合", &ast.CreateIndexStmt{
+				IndexName: "idx_user_id", TableName: "orders", Columns: []ast.ColumnId{*ast.NewColumnId("user_id")},
+			}, "table orders not found"},
+			{"カラムが存在しない場合", &ast.CreateIndexStmt{
+				IndexName: "idx_email", TableName: "users", Columns: []ast.ColumnId{*ast.NewColumnId("email")},
+			}, "key column 'email' does not exist"},
+			{"同じカラムを複数回指定した場合", &ast.CreateIndexStmt{
+				IndexName: "uk_name", TableName: "users", Unique: true,
+				Columns: []ast.ColumnId{*ast.NewColumnId("last_name"), *ast.NewColumnId("last_name")},
+			}, "duplicate column 'last_name' in index 'uk_name'"},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				// WHEN
+				exec, err := PlanCreateIndex(0, tt.stmt)
+
+				// THEN
+				assert.Nil(t, exec)
+				assert.ErrorContains(t, err, tt.expected)
+			})
+		}
+	})
+
+	t.Run("実行すると複数カラムのインデックスが作成され、先頭カラムの条件で検索に使われる", func(t *testing.T) {
+		// GIVEN
+		setupUsersTable(t)
+		defer handler.Reset()
+
+		// WHEN
+		executePlan(t, &ast.CreateIndexStmt{
+			IndexName: "idx_last_first",
+			TableName: "users",
+			Columns:   []ast.ColumnId{*ast.NewColumnId("last_name"), *ast.NewColumnId("first_name")},
+		})
+
+		// THEN
+		meta, ok := handler.Get().Catalog.GetTableMetaByName("users")
+		require.True(t, ok)
+		idx, ok := meta.GetIndexByName("idx_last_first")
+		require.True(t, ok)
+		assert.Equal(t, []string{"last_name", "first_name"}, idx.ColNames)
+
+		records := executePlan(t, &ast.SelectStmt{
+			From: *ast.NewTableId("users"),
+			Where: &ast.WhereClause{
+				Condition: ast.NewBinaryExpr(
+					"=",
+					ast.NewLhsColumn(*ast.NewColumnId("last_name")),
+					ast.NewRhsLiteral(ast.NewStringLiteral("Doe2")),
+				),
+			},
+		})
+		assert.Equal(t, []executor.Record{
+			{[]byte("4"), []byte("Jane"), []byte("Doe2"), []byte("female"), []byte("janedoe")},
+			{[]byte("2"), []byte("John"), []byte("Doe2"), []byte("male"), []byte("johndoe2")},
+		}, records)
+	})
+}
+
+func TestPlanDropIndex(t *testing.T) {
+	t.Run("AlterTable executor を返す", func(t *testing.T) {
+		// GIVEN
+		setupUsersTable(t)
+		defer handler.Reset()
+
+		// WHEN
+		exec, err := PlanDropIndex(0, &ast.DropIndexStmt{IndexName: "username", TableName: "users"})
+
+		// THEN
+		assert.NoError(t, err)
+		assert.IsType(t, &executor.AlterTable{}, exec)
+	})
+
+	t.Run("テーブルが存在しない場合エラーを返す", func(t *testing.T) {
+		// GIVEN
+		setupUsersTable(t)
+		defer handler.Reset()
+
+		// WHEN
+		exec, err := PlanDropIndex(0, &ast.DropIndexStmt{IndexName: "idx_user_id", TableName: "orders"})
+
+		// THEN
+		assert.Nil(t, exec)
+		assert.ErrorContains(t, err, "table orders not found")
+	})
+}
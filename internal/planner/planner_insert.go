@@ -3,20 +3,24 @@ package planner
 import (
 	"errors"
 	"fmt"
+	"slices"
 
 	"github.com/ren-yamanashi/minesql/internal/ast"
 	"github.com/ren-yamanashi/minesql/internal/executor"
+	"github.com/ren-yamanashi/minesql/internal/storage/access"
 	"github.com/ren-yamanashi/minesql/internal/storage/handler"
 )
 
-// PlanInsert は INSERT 文の実行計画を構築する
+// PlanInsert は INSERT 文 (INSERT ... SELECT, INSERT IGNORE, INSERT ... ON DUPLICATE KEY UPDATE, REPLACE を含む) の実行計画を構築する
 func PlanInsert(trxId handler.TrxId, stmt *ast.InsertStmt) (executor.Executor, error) {
-	if len(stmt.Cols) == 0 {
-		return nil, errors.New("column names cannot be empty")
-	}
+	if stmt.Select == nil {
+		if len(stmt.Cols) == 0 {
+			return nil, errors.New("column names cannot be empty")
+		}
 
-	if len(stmt.Values) == 0 {
-		return nil, errors.New("records cannot be empty")
+		if len(stmt.Values) == 0 {
+			return nil, errors.New("records cannot be empty")
+		}
 	}
 
 	// カラム名の重複チェック
@@ -40,35 +44,126 @@ func PlanInsert(trxId handler.TrxId, stmt *ast.InsertStmt) (executor.Executor, e
 	if !ok {
 		return nil, fmt.Errorf("table %s not found", stmt.Table.TableName)
 	}
-	tbl, err := hdl.GetTable(stmt.Table.TableName)
+	tbl, err := hdl.OpenTable(trxId, stmt.Table.TableName)
+	if err != nil {
+		return nil, err
+	}
+
+	// INSERT ... SELECT でカラムリストを省略した場合は、テーブルの全カラムに値を指定する
+	cols := stmt.Cols
+	if len(cols) == 0 {
+		for _, colMeta := range tblMeta.GetSortedCols() {
+			cols = append(cols, *ast.NewColumnId(colMeta.Name))
+			seenCols[colMeta.Name] = true
+		}
+	}
+
+	// 値が指定されなかったカラムには DEFAULT 句の式を評価した値を設定する
+	defaults, err := defaultRecord(tblMeta, seenCols)
+	if err != nil {
+		return nil, err
+	}
+
+	var ins *executor.Insert
+	if stmt.Select != nil {
+		ins, err = planInsertSelect(trxId, stmt.Select, tblMeta, tbl, cols, defaults)
+	} else {
+		ins, err = planInsertValues(trxId, stmt.Values, tblMeta, tbl, cols, defaults)
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	colPosMap := make(map[string]uint16)
-	for _, colMeta := range tblMeta.Cols {
-		colPosMap[colMeta.Name] = colMeta.Pos
+	checks, err := compileCheckConstraints(tblMeta)
+	if err != nil {
+		return nil, err
+	}
+	ins.SetCheckConstraints(checks)
+
+	if stmt.Ignore {
+		ins.SetIgnore()
+	}
+	if stmt.Replace {
+		ins.SetReplace()
 	}
+	if len(stmt.OnDuplicateKeyUpdate) > 0 {
+		setColumns, err := planOnDuplicateKeyUpdate(tblMeta, stmt.OnDuplicateKeyUpdate)
+		if err != nil {
+			return nil, err
+		}
+		ins.SetOnDuplicateKeyUpdate(setColumns)
+	}
+	return ins, nil
+}
 
-	// レコードをテーブルのカラム順序に並び替える
+// planInsertValues は VALUES の値リストを追加する Insert を構築する
+func planInsertValues(trxId handler.TrxId, values [][]ast.Literal, tblMeta *handler.TableMetadata, tbl *access.Table, cols []ast.ColumnId, defaults executor.Record) (*executor.Insert, error) {
+	// レコードをテーブルのカラム順序に並び替え、値をカラムのデータ型に応じた格納形式に変換する
 	records := []executor.Record{}
-	for _, valList := range stmt.Values {
-		record := make([][]byte, len(tblMeta.Cols))
+	for _, valList := range values {
+		record := slices.Clone(defaults)
 		for i, val := range valList {
-			colName := stmt.Cols[i].ColName
-			pos, ok := colPosMap[colName]
+			colName := cols[i].ColName
+			colMeta, ok := tblMeta.GetColByName(colName)
 			if !ok {
 				return nil, errors.New("column does not exist: " + colName)
 			}
-			switch v := val.(type) {
-			case *ast.StringLiteral:
-				record[pos] = []byte(v.Value)
+			switch val.(type) {
+			case *ast.StringLiteral, *ast.NumberLiteral, *ast.NullLiteral:
+				value, err := parseLiteral(colMeta, val)
+				if err != nil {
+					return nil, err
+				}
+				record[colMeta.Pos] = value
 			default:
 				return nil, errors.New("unsupported literal type in insert values")
 			}
 		}
+
+		// DEFAULT 句のないカラムに値が指定されなかった場合と NULL を指定した場合は NULL になる
+		if err := checkNotNull(tblMeta, record); err != nil {
+			return nil, err
+		}
 		records = append(records, record)
 	}
-
 	return executor.NewInsert(trxId, tbl, records), nil
 }
+
+// planInsertSelect は SELECT の結果を追加する Insert を構築する (INSERT ... SELECT)
+//
+// SELECT の結果の各カラムを追加先のカラムのデータ型に変換し、値が指定されなかったカラムに DEFAULT の値を設定したレコードを返す Project を挟む
+func planInsertSelect(trxId handler.TrxId, sel *ast.SelectStmt, tblMeta *handler.TableMetadata, tbl *access.Table, cols []ast.ColumnId, defaults executor.Record) (*executor.Insert, error) {
+	result, err := PlanSelect(trxId, sel)
+	if err != nil {
+		return nil, err
+	}
+	if len(result.Columns) != len(cols) {
+		return nil, errors.New("number of values does not match number of columns")
+	}
+
+	exprs := make([]executor.Expression, len(defaults))
+	for pos, value := range defaults {
+		exprs[pos] = executor.NewConstant(value)
+	}
+	for i, col := range cols {
+		colMeta, ok := tblMeta.GetColByName(col.ColName)
+		if !ok {
+			return nil, errors.New("column does not exist: " + col.ColName)
+		}
+		te, err := castTo(typedExpr{expr: executor.NewColumnRef(i), meta: result.Columns[i].metadata()}, colMeta)
+		if err != nil {
+			return nil, err
+		}
+		exprs[colMeta.Pos] = te.expr
+	}
+	return executor.NewInsertSelect(trxId, tbl, executor.NewProjectWithExprs(result.Exec, exprs)), nil
+}
+
+// planOnDuplicateKeyUpdate は ON DUPLICATE KEY UPDATE 句を Executor の SetColumn に変換する
+//
+// 式は既存の行に対して評価し、VALUES(col) は追加しようとした行のカラムを参照する
+func planOnDuplicateKeyUpdate(tblMeta *handler.TableMetadata, setClauses []*ast.SetClause) ([]executor.SetColumn, error) {
+	scope := newTableExprScope(tblMeta)
+	scope.inserting = tblMeta
+	return compileSetClauses(tblMeta, setClauses, scope)
+}
@@ -5,6 +5,8 @@ import (
 
 	"github.com/ren-yamanashi/minesql/internal/ast"
 	"github.com/ren-yamanashi/minesql/internal/executor"
+	"github.com/ren-yamanashi/minesql/internal/storage/access"
+	"github.com/ren-yamanashi/minesql/internal/storage/encode"
 	"github.com/ren-yamanashi/minesql/internal/storage/handler"
 	"github.com/stretchr/testify/assert"
 )
@@ -360,6 +362,230 @@ func TestPlanInsert(t *testing.T) {
 		assert.Nil(t, exec)
 		assert.Contains(t, err.Error(), "unsupported literal type")
 	})
+
+	t.Run("NOT NULL の AUTO_INCREMENT カラムを省略しても Executor が生成される", func(t *testing.T) {
+		// GIVEN
+		initStorageManagerForTest(t)
+		defer handler.Reset()
+
+		var trxId handler.TrxId = 1
+		createTableForTest(t, []handler.CreateColumnParam{
+			{Name: "id", Type: handler.ColumnTypeInt, NotNull: true, AutoIncrement: true},
+			{Name: "name", Type: handler.ColumnTypeString},
+		})
+
+		stmt := &ast.InsertStmt{
+			Table:  *ast.NewTableId("users"),
+			Cols:   []ast.ColumnId{*ast.NewColumnId("name")},
+			Values: [][]ast.Literal{{ast.NewStringLiteral("Alice")}},
+		}
+
+		// WHEN
+		exec, err := PlanInsert(trxId, stmt)
+
+		// THEN
+		assert.NoError(t, err)
+		assert.IsType(t, &executor.Insert{}, exec)
+	})
+
+	t.Run("省略したカラムには DEFAULT 句の値が挿入される", func(t *testing.T) {
+		// GIVEN
+		initStorageManagerForTest(t)
+		defer handler.Reset()
+
+		var trxId handler.TrxId = 1
+		createTableForTest(t, []handler.CreateColumnParam{
+			{Name: "id", Type: handler.ColumnTypeString},
+			{Name: "status", Type: handler.ColumnTypeString, Default: "'active'", DefaultValue: []byte("active")},
+			{Name: "name", Type: handler.ColumnTypeString},
+		})
+
+		stmt := &ast.InsertStmt{
+			Table:  *ast.NewTableId("users"),
+			Cols:   []ast.ColumnId{*ast.NewColumnId("id"), *ast.NewColumnId("name")},
+			Values: [][]ast.Literal{{ast.NewStringLiteral("1"), ast.NewStringLiteral("Alice")}},
+		}
+
+		// WHEN
+		exec, err := PlanInsert(trxId, stmt)
+		assert.NoError(t, err)
+		_, err = exec.Next()
+
+		// THEN
+		assert.NoError(t, err)
+		scan := executor.NewTableScan(executor.TableScanParams{
+			ReadView:       access.NewReadView(0, nil, ^uint64(0)),
+			VersionReader:  access.NewVersionReader(nil),
+			Table:          getPlannerTable(t, "users"),
+			SearchMode:     access.RecordSearchModeStart{},
+			WhileCondition: func(record executor.Record) bool { return true },
+		})
+		assert.Equal(t, []executor.Record{{[]byte("1"), []byte("active"), []byte("Alice")}}, fetchAll(t, scan))
+	})
+
+	t.Run("CHECK 制約を満たさないレコードを挿入するとエラーを返す", func(t *testing.T) {
+		// GIVEN
+		initStorageManagerForTest(t)
+		defer handler.Reset()
+
+		var trxId handler.TrxId = 1
+		createTable := executor.NewCreateTable("users", 1, nil, []handler.CreateColumnParam{
+			{Name: "id", Type: handler.ColumnTypeString},
+			{Name: "age", Type: handler.ColumnTypeInt},
+		}, []handler.CreateConstraintParam{
+			{ConstraintName: "users_chk_1", CheckExpr: "age >= 0"},
+		})
+		_, err := createTable.Next()
+		assert.NoError(t, err)
+
+		stmt := &ast.InsertStmt{
+			Table:  *ast.NewTableId("users"),
+			Cols:   []ast.ColumnId{*ast.NewColumnId("id"), *ast.NewColumnId("age")},
+			Values: [][]ast.Literal{{ast.NewStringLiteral("1"), ast.NewStringLiteral("-1")}},
+		}
+
+		// WHEN
+		exec, err := PlanInsert(trxId, stmt)
+		assert.NoError(t, err)
+		_, err = exec.Next()
+
+		// THEN
+		var checkErr *executor.CheckConstraintViolationError
+		assert.ErrorAs(t, err, &checkErr)
+		assert.Equal(t, "users_chk_1", checkErr.Name)
+	})
+}
+
+func TestPlanInsertSelect(t *testing.T) {
+	t.Run("SELECT の結果を追加先のカラムのデータ型に変換して追加する", func(t *testing.T) {
+		// GIVEN
+		initStorageManagerForTest(t)
+		defer handler.Reset()
+
+		createTableForTest(t, []handler.CreateColumnParam{
+			{Name: "id", Type: handler.ColumnTypeInt},
+			{Name: "name", Type: handler.ColumnTypeString},
+		})
+		createTable := executor.NewCreateTable("archived", 1, nil, []handler.CreateColumnParam{
+			{Name: "id", Type: handler.ColumnTypeBigInt},
+			{Name: "name", Type: handler.ColumnTypeString},
+			{Name: "status", Type: handler.ColumnTypeString, Default: "'archived'", DefaultValue: []byte("archived")},
+		}, nil)
+		_, err := createTable.Next()
+		assert.NoError(t, err)
+		insertUsers, err := PlanInsert(1, &ast.InsertStmt{
+			Table:  *ast.NewTableId("users"),
+			Cols:   []ast.ColumnId{*ast.NewColumnId("id"), *ast.NewColumnId("name")},
+			Values: [][]ast.Literal{{ast.NewStringLiteral("1"), ast.NewStringLiteral("Alice")}},
+		})
+		assert.NoError(t, err)
+		_, err = insertUsers.Next()
+		assert.NoError(t, err)
+
+		stmt := &ast.InsertStmt{
+			Table: *ast.NewTableId("archived"),
+			Cols:  []ast.ColumnId{*ast.NewColumnId("name"), *ast.NewColumnId("id")},
+			Select: &ast.SelectStmt{
+				From:    *ast.NewTableId("users"),
+				Columns: []ast.ColumnId{*ast.NewColumnId("name"), *ast.NewColumnId("id")},
+			},
+		}
+
+		// WHEN
+		exec, err := PlanInsert(1, stmt)
+		assert.NoError(t, err)
+		_, err = exec.Next()
+
+		// THEN
+		assert.NoError(t, err)
+		scan := executor.NewTableScan(executor.TableScanParams{
+			ReadView:       access.NewReadView(0, nil, ^uint64(0)),
+			VersionReader:  access.NewVersionReader(nil),
+			Table:          getPlannerTable(t, "archived"),
+			SearchMode:     access.RecordSearchModeStart{},
+			WhileCondition: func(record executor.Record) bool { return true },
+		})
+		assert.Equal(t, []executor.Record{{encode.EncodeInt64(1), []byte("Alice"), []byte("archived")}}, fetchAll(t, scan))
+	})
+
+	t.Run("SELECT の結果のカラム数がカラム数と一致しない場合、エラーを返す", func(t *testing.T) {
+		// GIVEN
+		initStorageManagerForTest(t)
+		defer handler.Reset()
+
+		createTableForTest(t, []handler.CreateColumnParam{
+			{Name: "id", Type: handler.ColumnTypeString},
+			{Name: "name", Type: handler.ColumnTypeString},
+		})
+		stmt := &ast.InsertStmt{
+			Table: *ast.NewTableId("users"),
+			Select: &ast.SelectStmt{
+				From:    *ast.NewTableId("users"),
+				Columns: []ast.ColumnId{*ast.NewColumnId("id")},
+			},
+		}
+
+		// WHEN
+		exec, err := PlanInsert(1, stmt)
+
+		// THEN
+		assert.Nil(t, exec)
+		assert.ErrorContains(t, err, "number of values does not match number of columns")
+	})
+}
+
+func TestPlanInsertOnDuplicateKeyUpdate(t *testing.T) {
+	t.Run("VALUES(col) の引数がテーブルに存在しないカラムの場合、エラーを返す", func(t *testing.T) {
+		// GIVEN
+		initStorageManagerForTest(t)
+		defer handler.Reset()
+
+		createTableForTest(t, []handler.CreateColumnParam{
+			{Name: "id", Type: handler.ColumnTypeString},
+			{Name: "name", Type: handler.ColumnTypeString},
+		})
+		stmt := &ast.InsertStmt{
+			Table:  *ast.NewTableId("users"),
+			Cols:   []ast.ColumnId{*ast.NewColumnId("id"), *ast.NewColumnId("name")},
+			Values: [][]ast.Literal{{ast.NewStringLiteral("1"), ast.NewStringLiteral("Alice")}},
+			OnDuplicateKeyUpdate: []*ast.SetClause{
+				{Column: *ast.NewColumnId("name"), Value: ast.NewFuncCallExpr("VALUES", []ast.Expr{*ast.NewColumnId("nickname")})},
+			},
+		}
+
+		// WHEN
+		exec, err := PlanInsert(1, stmt)
+
+		// THEN
+		assert.Nil(t, exec)
+		assert.ErrorContains(t, err, "column nickname does not exist in table users")
+	})
+
+	t.Run("NOT NULL のカラムを NULL に更新する場合、エラーを返す", func(t *testing.T) {
+		// GIVEN
+		initStorageManagerForTest(t)
+		defer handler.Reset()
+
+		createTableForTest(t, []handler.CreateColumnParam{
+			{Name: "id", Type: handler.ColumnTypeString},
+			{Name: "name", Type: handler.ColumnTypeString, NotNull: true},
+		})
+		stmt := &ast.InsertStmt{
+			Table:  *ast.NewTableId("users"),
+			Cols:   []ast.ColumnId{*ast.NewColumnId("id"), *ast.NewColumnId("name")},
+			Values: [][]ast.Literal{{ast.NewStringLiteral("1"), ast.NewStringLiteral("Alice")}},
+			OnDuplicateKeyUpdate: []*ast.SetClause{
+				{Column: *ast.NewColumnId("name"), Value: ast.NewNullLiteral()},
+			},
+		}
+
+		// WHEN
+		exec, err := PlanInsert(1, stmt)
+
+		// THEN
+		assert.Nil(t, exec)
+		assert.ErrorContains(t, err, "column 'name' cannot be null")
+	})
 }
 
 // StorageManager を初期化する
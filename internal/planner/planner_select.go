@@ -3,18 +3,66 @@ package planner
 import (
 	"bytes"
 	"fmt"
+	"slices"
 
 	"github.com/ren-yamanashi/minesql/internal/ast"
 	"github.com/ren-yamanashi/minesql/internal/executor"
 	"github.com/ren-yamanashi/minesql/internal/storage/access"
+	"github.com/ren-yamanashi/minesql/internal/storage/config"
 	"github.com/ren-yamanashi/minesql/internal/storage/handler"
 )
 
 func PlanSelect(trxId handler.TrxId, stmt *ast.SelectStmt) (*PlanResult, error) {
-	if len(stmt.Joins) > 0 {
-		return planSelectJoin(trxId, stmt)
+	if err := bindWith(stmt.With, stmt); err != nil {
+		return nil, err
+	}
+	if err := bindViews(stmt, nil); err != nil {
+		return nil, err
+	}
+	if stmt.Distinct {
+		return planSelectDistinct(trxId, stmt)
+	}
+	return planSelectBody(trxId, stmt)
+}
+
+// planSelectBody は SELECT を計画する (DISTINCT の場合は重複を除く前まで)
+func planSelectBody(trxId handler.TrxId, stmt *ast.SelectStmt) (*PlanResult, error) {
+	var result *PlanResult
+	var err error
+	switch {
+	case stmt.From.TableName == "":
+		result, err = planSelectWithoutFrom(trxId, stmt)
+	case len(stmt.Joins) > 0 || stmt.From.IsDerived():
+		result, err = planSelectJoin(trxId, stmt, nil)
+	default:
+		result, err = planSelectSingle(trxId, stmt)
+	}
+	if err != nil {
+		return nil, err
 	}
-	return planSelectSingle(trxId, stmt)
+	applySelectAliases(result, stmt)
+	return result, nil
+}
+
+// applySelectAliases は SELECT リストの別名 (`expr AS alias`) を結果セットのカラム名にする
+func applySelectAliases(result *PlanResult, stmt *ast.SelectStmt) {
+	for pos, alias := range stmt.Aliases {
+		result.Columns[pos].ColName = alias
+	}
+}
+
+// planSelectWithoutFrom は FROM 句のない SELECT 文 (集合演算のオペランド) を計画する
+//
+// SingleRow が返す 1 件のレコードに対して SELECT リストの式を評価する (カラム・集約関数・ウィンドウ関数は参照できない)
+func planSelectWithoutFrom(trxId handler.TrxId, stmt *ast.SelectStmt) (*PlanResult, error) {
+	if stmt.HasAggregation() || stmt.HasWindow() {
+		return nil, fmt.Errorf("SELECT without FROM clause must not contain aggregate or window functions")
+	}
+	scope := newExprScope(nil)
+	scope.column = func(col ast.ColumnId) (int, error) {
+		return 0, fmt.Errorf("column %s cannot be referenced in SELECT without FROM clause", ast.ExprString(col))
+	}
+	return planSelectExprs(executor.NewSingleRow(), stmt, scope.withSubqueries(trxId))
 }
 
 // planSelectSingle は単一テーブルの SELECT を計画する (従来の処理)
@@ -28,53 +76,190 @@ func planSelectSingle(trxId handler.TrxId, stmt *ast.SelectStmt) (*PlanResult, e
 
 	rv := hdl.CreateReadView(trxId)
 	vr := access.NewVersionReader(hdl.UndoLog())
-	search := NewSearch(rv, vr, tblMeta, stmt.Where, hdl.BufferPool)
-	search.SetSelectColumns(stmt.Columns)
+	// サブクエリを含む WHERE 条件は Search では扱わず、検索後に評価する
+	where, subqueryConds := splitSubqueryConditions(stmt.Where)
+	search := NewSearch(rv, vr, tblMeta, where, hdl.BufferPool)
+	columns := resolveJoinedColumns([]*handler.TableMetadata{tblMeta})
+	// 相関サブクエリは SELECT リストにないカラムも参照するため、サブクエリを含む場合は index-only scan を使わない
+	useIndexOnly := !hasSubquery(stmt)
+
+	// 集約を含む場合、ORDER BY・LIMIT・SELECT のカラムは集約後のレコードに対して適用する
+	if stmt.HasAggregation() {
+		if useIndexOnly {
+			search.SetSelectColumns(aggregateInputColumns(stmt))
+		}
+		// グループ化キーの順に並んでいれば StreamAggregate で集約できるため、グループ化キーの順に走査できるインデックスを優先する
+		if len(stmt.GroupBy) > 0 {
+			search.SetPreferredOrder(stmt.GroupBy)
+		}
+		iterator, err := search.Build()
+		if err != nil {
+			return nil, err
+		}
+		iterator, sortOrder, err := planSubqueryConditions(trxId, iterator, subqueryConds, columns, search.sortOrder)
+		if err != nil {
+			return nil, err
+		}
+		return planAggregateSelect(trxId, iterator, stmt, columns, sortOrder)
+	}
+
+	if useIndexOnly {
+		search.SetSelectColumns(selectColumnsWithOrderBy(stmt))
+	}
+	// DISTINCT は SELECT のカラムの順に並んでいれば連続する同じ行を除くだけで済むため、そのカラムの順に走査できるインデックスを優先する
+	if stmt.Distinct {
+		search.SetPreferredOrder(stmt.Columns)
+	}
+
+	sortKeys, err := buildSortKeys(stmt.OrderBy, columns)
+	if err != nil {
+		return nil, err
+	}
+	// ORDER BY が PK 順で LIMIT がある場合 (ORDER BY pk LIMIT n)、PK 順の走査は n 行で打ち切れることを Search に伝える
+	// (検索後にサブクエリの条件で絞り込む場合や、ウィンドウ関数で全行を参照する場合は n 行で足りるとは限らない)
+	if len(sortKeys) > 0 && stmt.Limit != nil && len(subqueryConds) == 0 && !stmt.HasWindow() && isSortedBy(sortKeys, search.pkOrder()) {
+		search.SetPKOrderLimit(stmt.Limit.Offset + stmt.Limit.Count)
+	}
+
 	iterator, err := search.Build()
 	if err != nil {
 		return nil, err
 	}
+	iterator, sortOrder, err := planSubqueryConditions(trxId, iterator, subqueryConds, columns, search.sortOrder)
+	if err != nil {
+		return nil, err
+	}
+
+	// ウィンドウ関数 (ORDER BY・LIMIT の前に、WHERE 句で絞り込んだすべての行を対象に計算する)
+	iterator, scope, sortOrder, err := planWindows(iterator, stmt, newExprScope(columns).withSubqueries(trxId), sortOrder)
+	if err != nil {
+		return nil, err
+	}
+
+	// ORDER BY (Project の前に並べ替えるため、SELECT で指定していないカラムでも並べ替えられる)
+	iterator = planOrderBy(iterator, sortKeys, sortOrder)
+
+	// LIMIT (必要な件数を返した時点で子の Executor の走査を打ち切る)
+	iterator = planLimit(iterator, stmt.Limit)
+
+	if stmt.Exprs != nil {
+		return planSelectExprs(iterator, stmt, scope)
+	}
 
 	colPos, err := resolveSelectColumns(stmt.Columns, []*handler.TableMetadata{tblMeta})
 	if err != nil {
 		return nil, err
 	}
 
-	// カラムメタデータを構築
-	columns := buildColumnMeta(colPos, []*handler.TableMetadata{tblMeta})
+	result := &PlanResult{
+		Exec:    executor.NewProject(iterator, colPos),
+		Columns: buildColumnMeta(colPos, []*handler.TableMetadata{tblMeta}),
+	}
+	// 並べ替えていない場合は、検索した順序を結果セットのカラムの位置に変換して伝える
+	if len(sortKeys) == 0 {
+		result.sortOrder = projectSortOrder(sortOrder, colPos)
+	}
+	return result, nil
+}
+
+// planSelectJoin は JOIN または導出テーブル・共通テーブル式を含む SELECT を計画する
+//
+// self は再帰 CTE の再帰部分を計画する場合の、共通テーブル式自身への参照 (それ以外は nil)
+func planSelectJoin(trxId handler.TrxId, stmt *ast.SelectStmt, self *recursiveRef) (*PlanResult, error) {
+	hdl := handler.Get()
+	rv := hdl.CreateReadView(trxId)
+	vr := access.NewVersionReader(hdl.UndoLog())
+
+	join, err := planJoin(trxId, rv, vr, stmt, self)
+	if err != nil {
+		return nil, err
+	}
+	exec, joinedColumns, sortOrder := join.exec, join.columns, join.sortOrder
+
+	// 集約を含む場合、ORDER BY・LIMIT・SELECT のカラムは集約後のレコードに対して適用する
+	if stmt.HasAggregation() {
+		return planAggregateSelect(trxId, exec, stmt, joinedColumns, sortOrder)
+	}
+
+	// 6. ウィンドウ関数
+	exec, scope, sortOrder, err := planWindows(exec, stmt, newExprScope(joinedColumns).withSubqueries(trxId), sortOrder)
+	if err != nil {
+		return nil, err
+	}
+
+	// 7. ORDER BY
+	// NestedLoopJoin は駆動表の並び順を保つ (駆動表のカラムは結合レコードの先頭に並ぶため、位置もそのまま使える)
+	sortKeys, err := buildSortKeys(stmt.OrderBy, joinedColumns)
+	if err != nil {
+		return nil, err
+	}
+	exec = planOrderBy(exec, sortKeys, sortOrder)
+
+	// 8. LIMIT
+	exec = planLimit(exec, stmt.Limit)
+
+	// 9. Project
+	if stmt.Exprs != nil {
+		return planSelectExprs(exec, stmt, scope)
+	}
+	colPos, err := resolveSelectColumnsForJoin(stmt.Columns, joinedColumns, len(joinedColumns))
+	if err != nil {
+		return nil, err
+	}
+
+	// カラムメタデータを構築 (colPos の順序で joinedColumns から取得)
+	columns := make([]ColumnMeta, len(colPos))
+	for i, pos := range colPos {
+		jc := joinedColumns[pos]
+		columns[i] = newColumnMeta(jc.tableName, jc.colMeta)
+	}
 
 	return &PlanResult{
-		Exec:    executor.NewProject(iterator, colPos),
+		Exec:    executor.NewProject(exec, colPos),
 		Columns: columns,
 	}, nil
 }
 
-// planSelectJoin は JOIN を含む SELECT を計画する
-func planSelectJoin(trxId handler.TrxId, stmt *ast.SelectStmt) (*PlanResult, error) {
+// joinPlan は FROM 句・JOIN 句・WHERE 句から構築した、結合レコードを返す Executor ツリー
+type joinPlan struct {
+	exec      executor.Executor
+	columns   []joinedColumn // 結合レコードのカラム (結合順)
+	sortOrder []int          // exec が返すレコードの並び順 (結合レコード内のカラム位置)
+}
+
+// planJoin は参加テーブルを結合し、WHERE 句で絞り込む Executor ツリーを構築する
+//
+// SELECT では rv に一貫性読み取りの ReadView を、複数テーブルの UPDATE / DELETE では Current Read の ReadView を指定する
+// self は再帰 CTE の再帰部分を計画する場合の、共通テーブル式自身への参照 (それ以外は nil)
+func planJoin(trxId handler.TrxId, rv *access.ReadView, vr *access.VersionReader, stmt *ast.SelectStmt, self *recursiveRef) (*joinPlan, error) {
 	hdl := handler.Get()
-	rv := hdl.CreateReadView(trxId)
-	vr := access.NewVersionReader(hdl.UndoLog())
+	tables := collectTables(stmt)
+	if err := validateUniqueTables(tables); err != nil {
+		return nil, err
+	}
 
-	// 1. 参加テーブルのメタデータ・統計情報・テーブルオブジェクトを収集
-	tableNames := collectTableNames(stmt)
-	candidates, err := buildJoinCandidates(hdl, tableNames)
+	// 1. ON 条件から joinPredicate とそれ以外の条件を抽出
+	predicates, conditions, err := extractJoinPredicates(stmt)
 	if err != nil {
 		return nil, err
 	}
 
-	// 2. ON 条件から joinPredicate を抽出
-	predicates, err := extractJoinPredicates(stmt.Joins)
+	// サブクエリを含む WHERE 条件は結合順序・アクセスパスの決定には使わず、結合後に評価する
+	where, subqueryConds := splitSubqueryConditions(withInnerJoinConditions(stmt.Where, conditions))
+
+	// 2. 参加テーブルのメタデータ・統計情報・テーブルオブジェクト (導出テーブルの場合は Executor) を収集
+	candidates, err := buildJoinCandidates(trxId, hdl, tables, self)
 	if err != nil {
 		return nil, err
 	}
 
-	// 3. 貪欲法で結合順序を決定
+	// 3. 貪欲法で結合順序を決定 (外部結合の内部表は ON 条件で参照するテーブルより後に結合する)
 	// 全テーブルのメタデータ (非修飾カラム名が複数テーブルに存在するか判定するために使用)
 	allMetas := make([]*handler.TableMetadata, len(candidates))
 	for i, c := range candidates {
 		allMetas[i] = c.tblMeta
 	}
-	ordered, err := optimizeJoinOrder(hdl.BufferPool, candidates, predicates, stmt.Where, allMetas)
+	ordered, err := optimizeJoinOrder(hdl.BufferPool, candidates, predicates, where, allMetas)
 	if err != nil {
 		return nil, err
 	}
@@ -88,75 +273,180 @@ func planSelectJoin(trxId handler.TrxId, stmt *ast.SelectStmt) (*PlanResult, err
 
 	// 駆動表のアクセスパスを決定
 	// WHERE 条件のうち駆動表のカラムのみに関係する条件を抽出し、Search で最適化する
+	// (導出テーブルはサブクエリの結果を Filter で絞り込む)
 	drivingTable := ordered[0]
-	drivingWhere, remainingWhere := splitWhereForTable(stmt.Where, drivingTable.tblMeta, orderedMetas)
-	search := NewSearch(rv, vr, drivingTable.tblMeta, drivingWhere, hdl.BufferPool)
-	exec, err := search.Build()
+	drivingWhere, remainingWhere := splitWhereForTable(where, drivingTable.tblMeta, orderedMetas)
+	exec, sortOrder, err := buildDrivingExec(rv, vr, hdl, drivingTable, drivingWhere)
 	if err != nil {
 		return nil, err
 	}
 
-	// 2 番目以降のテーブルを NestedLoopJoin で結合
+	// 2 番目以降のテーブルを NestedLoopJoin または HashJoin で結合
+	// HashJoin はビルド側を分割した場合に駆動表の並び順を保たないため、HashJoin を使う場合は駆動表の並び順を ORDER BY に利用しない
 	leftColCount := int(ordered[0].tblMeta.NCols)
+	applied := make(map[*joinPredicate]struct{})
 	for i := 1; i < len(ordered); i++ {
 		rightCandidate := ordered[i]
 		pred := findPredicateForTable(predicates, rightCandidate.tblMeta.Name, ordered[:i])
 
-		buildRight, err := buildRightExecFunc(rv, vr, rightCandidate, pred, joinedColumns)
-		if err != nil {
-			return nil, err
+		// 外部結合: ON 条件のうちアクセスパスに使わなかった条件は、内部表の行が一致するかの判定に使う
+		// (WHERE のように結合後に評価すると、NULL で補完すべき行が取り除かれてしまうため)
+		outer := isOuterTable(predicates, rightCandidate.tblMeta.Name)
+		var cond executor.Expression
+		if outer {
+			cond, err = buildOuterJoinCondition(predicates, pred, conditions, rightCandidate.tblMeta.Name, joinedColumns[:leftColCount+int(rightCandidate.tblMeta.NCols)])
+			if err != nil {
+				return nil, err
+			}
+		} else {
+			applied[pred] = struct{}{}
+		}
+
+		if rightCandidate.method == joinMethodHash {
+			exec, err = buildHashJoin(rv, vr, exec, rightCandidate, pred, cond, outer, joinedColumns)
+			if err != nil {
+				return nil, err
+			}
+			sortOrder = nil
+		} else {
+			buildRight, err := buildRightExecFunc(rv, vr, rightCandidate, pred, joinedColumns)
+			if err != nil {
+				return nil, err
+			}
+			// EXPLAIN で内部表として表示する Executor (外側の行の値によらず構造は同じため、空の行から生成する)
+			innerPlan, err := buildRight(make(executor.Record, leftColCount))
+			if err != nil {
+				return nil, err
+			}
+			var nlj *executor.NestedLoopJoin
+			if outer {
+				nlj = executor.NewNestedLoopOuterJoin(exec, buildRight, int(rightCandidate.tblMeta.NCols), cond)
+			} else {
+				nlj = executor.NewNestedLoopJoin(exec, buildRight)
+			}
+			nlj.SetInnerPlan(innerPlan)
+			nlj.SetPlanNote(rightCandidate.joinNote())
+			exec = nlj
 		}
 
-		exec = executor.NewNestedLoopJoin(exec, buildRight)
+		// INNER JOIN の ON 条件のうち、アクセスパスに使わなかった条件は参照するテーブルがすべて結合された時点で Filter で評価する
+		pending := pendingInnerPredicates(predicates, ordered[:i+1], applied)
+		if len(pending) > 0 {
+			cond, err := matchJoinPredicates(pending, joinedColumns)
+			if err != nil {
+				return nil, err
+			}
+			exec = executor.NewFilter(exec, cond)
+		}
 		leftColCount += int(rightCandidate.tblMeta.NCols)
 	}
 
 	// 5. 駆動表に分離されなかった WHERE 条件があれば Filter を重ねる
 	if remainingWhere != nil {
-		condFunc, err := buildJoinedConditionFunc(*remainingWhere.Condition, joinedColumns)
+		cond, err := buildJoinedCondition(remainingWhere.Condition, joinedColumns)
 		if err != nil {
 			return nil, err
 		}
-		exec = executor.NewFilter(exec, condFunc)
+		exec = executor.NewFilterWithExpression(exec, cond)
 	}
-
-	// 6. Project
-	colPos, err := resolveSelectColumnsForJoin(stmt.Columns, joinedColumns, leftColCount)
+	exec, sortOrder, err = planSubqueryConditions(trxId, exec, subqueryConds, joinedColumns, sortOrder)
 	if err != nil {
 		return nil, err
 	}
+	return &joinPlan{exec: exec, columns: joinedColumns, sortOrder: sortOrder}, nil
+}
 
-	// カラムメタデータを構築 (colPos の順序で joinedColumns から取得)
-	columns := make([]ColumnMeta, len(colPos))
-	for i, pos := range colPos {
-		jc := joinedColumns[pos]
-		columns[i] = ColumnMeta{TableName: jc.tableName, ColName: jc.colName}
+// selectColumnsWithOrderBy は index-only scan の判定に使うカラム (SELECT のカラム + ORDER BY のカラム) を返す
+//
+// SELECT * の場合は nil を返す
+func selectColumnsWithOrderBy(stmt *ast.SelectStmt) []ast.ColumnId {
+	if stmt.IsSelectAll() {
+		return nil
+	}
+	columns := slices.Clone(stmt.Columns)
+	if columns == nil {
+		columns = []ast.ColumnId{}
+	}
+	for _, e := range stmt.Exprs {
+		columns = append(columns, collectColumns(e.Expr)...)
+	}
+	for _, item := range stmt.OrderBy {
+		columns = append(columns, item.Column)
+	}
+	return columns
+}
+
+// planSelectExprs は式を含む SELECT リストを評価する Project を計画する
+//
+// scope は exec が返すレコードに対するスコープ
+// 式の結果のカラム名は式のテキスト表現 (e.g. `price * qty`) になる
+func planSelectExprs(exec executor.Executor, stmt *ast.SelectStmt, scope *exprScope) (*PlanResult, error) {
+	items := stmt.SelectItems()
+	exprs := make([]executor.Expression, len(items))
+	columns := make([]ColumnMeta, len(items))
+	for i, item := range items {
+		te, err := compileValue(item, scope)
+		if err != nil {
+			return nil, err
+		}
+		exprs[i] = te.expr
+
+		meta := te.meta
+		if meta == nil {
+			// NULL リテラルはデータ型を持たないため文字列として扱う
+			meta = stringMeta()
+		}
+		columns[i] = ColumnMeta{
+			ColName:   ast.ExprString(item),
+			Type:      meta.Type,
+			Precision: meta.Precision,
+			Scale:     meta.Scale,
+		}
+		if ref, ok := te.expr.(*executor.ColumnRef); ok {
+			// カラム・集約関数はそのままのカラムメタデータを使う
+			col := scope.columns[ref.Pos]
+			columns[i] = newColumnMeta(col.tableName, col.colMeta)
+		}
 	}
 
 	return &PlanResult{
-		Exec:    executor.NewProject(exec, colPos),
+		Exec:    executor.NewProjectWithExprs(exec, exprs),
 		Columns: columns,
 	}, nil
 }
 
+// collectColumns は式で参照されるカラム (集約関数・ウィンドウ関数の引数を含む) を返す
+func collectColumns(expr ast.Expr) []ast.ColumnId {
+	columns := []ast.ColumnId{}
+	ast.WalkExpr(expr, func(e ast.Expr) bool {
+		switch v := e.(type) {
+		case ast.ColumnId:
+			columns = append(columns, v)
+		case *ast.AggregateExpr:
+			if v.Column != nil {
+				columns = append(columns, *v.Column)
+			}
+		case *ast.WindowFuncExpr:
+			columns = append(columns, v.ReferencedColumns()...)
+		}
+		return true
+	})
+	return columns
+}
+
 // buildColumnMeta は ColPos とテーブルメタデータからカラムメタデータを構築する (単一テーブル用)
 func buildColumnMeta(colPos []uint16, tables []*handler.TableMetadata) []ColumnMeta {
 	// 全テーブルのカラムをフラットに並べる
-	type flatCol struct {
-		tableName string
-		colName   string
-	}
-	var allCols []flatCol
+	var allCols []ColumnMeta
 	for _, tbl := range tables {
 		for _, col := range tbl.GetSortedCols() {
-			allCols = append(allCols, flatCol{tableName: tbl.Name, colName: col.Name})
+			allCols = append(allCols, newColumnMeta(tbl.Name, col))
 		}
 	}
 
 	columns := make([]ColumnMeta, len(colPos))
 	for i, pos := range colPos {
-		fc := allCols[pos]
-		columns[i] = ColumnMeta{TableName: fc.tableName, ColName: fc.colName}
+		columns[i] = allCols[pos]
 	}
 	return columns
 }
@@ -187,87 +477,134 @@ func splitWhereForTable(where *ast.WhereClause, tblMeta *handler.TableMetadata,
 	return forTableWhere, remainingWhere
 }
 
-// splitExprForTable は BinaryExpr を再帰的に走査し、指定テーブルのカラムのみを参照する式を分離する
-func splitExprForTable(expr *ast.BinaryExpr, tblMeta *handler.TableMetadata, allTables []*handler.TableMetadata) (forTable []*ast.BinaryExpr, other []*ast.BinaryExpr) {
+// splitExprForTable は条件式を AND で再帰的に分解し、指定テーブルのカラムのみを参照する式を分離する
+func splitExprForTable(expr ast.Expr, tblMeta *handler.TableMetadata, allTables []*handler.TableMetadata) (forTable []ast.Expr, other []ast.Expr) {
 	if expr == nil {
 		return nil, nil
 	}
 
 	// AND の場合: 左右を再帰的に分離
-	if expr.Operator == "AND" {
-		if lhsExpr, ok := expr.Left.(*ast.LhsExpr); ok {
-			if rhsExpr, ok := expr.Right.(*ast.RhsExpr); ok {
-				lt, lo := splitExprForTable(lhsExpr.Expr, tblMeta, allTables)
-				rt, ro := splitExprForTable(rhsExpr.Expr, tblMeta, allTables)
-				return append(lt, rt...), append(lo, ro...)
-			}
-		}
+	if binExpr, ok := expr.(*ast.BinaryExpr); ok && binExpr.Operator == "AND" {
+		lt, lo := splitExprForTable(ast.LeftOperand(binExpr.Left), tblMeta, allTables)
+		rt, ro := splitExprForTable(ast.RightOperand(binExpr.Right), tblMeta, allTables)
+		return append(lt, rt...), append(lo, ro...)
 	}
 
 	// リーフ条件: テーブルに属するか判定
 	if belongsToTable(expr, tblMeta, allTables) {
-		return []*ast.BinaryExpr{expr}, nil
+		return []ast.Expr{expr}, nil
 	}
-	return nil, []*ast.BinaryExpr{expr}
+	return nil, []ast.Expr{expr}
 }
 
 // belongsToTable は式が指定テーブルのカラムのみを参照するか判定する
 //
 // allTables を渡した場合、非修飾名で同名カラムが複数テーブルにあるケースは
 // 曖昧と判定して false を返す (分離しない)
-func belongsToTable(expr *ast.BinaryExpr, tblMeta *handler.TableMetadata, allTables []*handler.TableMetadata) bool {
-	lhs, ok := expr.Left.(*ast.LhsColumn)
-	if !ok {
+func belongsToTable(expr ast.Expr, tblMeta *handler.TableMetadata, allTables []*handler.TableMetadata) bool {
+	columns := collectColumns(expr)
+	if len(columns) == 0 {
 		return false
 	}
+	for _, col := range columns {
+		if !columnBelongsToTable(col, tblMeta, allTables) {
+			return false
+		}
+	}
+	return true
+}
 
+// columnBelongsToTable はカラムが指定テーブルのカラムかどうかを判定する
+func columnBelongsToTable(col ast.ColumnId, tblMeta *handler.TableMetadata, allTables []*handler.TableMetadata) bool {
 	// 修飾名の場合: テーブル名が一致するか
-	if lhs.Column.TableName != "" {
-		return lhs.Column.TableName == tblMeta.Name
+	if col.TableName != "" {
+		return refersToTable(col.TableName, tblMeta.Name)
 	}
 
 	// 非修飾名の場合: そのカラムがテーブルに存在し、かつ他テーブルに同名カラムがないか
-	_, exists := tblMeta.GetColByName(lhs.Column.ColName)
-	if !exists {
+	if _, exists := tblMeta.GetColByName(col.ColName); !exists {
 		return false
 	}
 	for _, other := range allTables {
 		if other.Name == tblMeta.Name {
 			continue
 		}
-		if _, dup := other.GetColByName(lhs.Column.ColName); dup {
+		if _, dup := other.GetColByName(col.ColName); dup {
 			return false // 同名カラムが他テーブルにもある → 曖昧なので分離しない
 		}
 	}
 	return true
 }
 
-// combineExprsWithAND は複数の式を AND で結合する
-func combineExprsWithAND(exprs []*ast.BinaryExpr) *ast.BinaryExpr {
-	if len(exprs) == 1 {
-		return exprs[0]
-	}
+// combineExprsWithAND は複数の条件式を AND で結合する
+func combineExprsWithAND(exprs []ast.Expr) ast.Expr {
 	// 左から順に AND で結合
 	result := exprs[0]
 	for _, e := range exprs[1:] {
-		result = ast.NewBinaryExpr("AND", ast.NewLhsExpr(result), ast.NewRhsExpr(e))
+		result = ast.NewBinaryExpr("AND", conditionLHS(result), conditionRHS(e))
 	}
 	return result
 }
 
-// collectTableNames は SELECT 文から参加テーブル名を収集する
-func collectTableNames(stmt *ast.SelectStmt) []string {
-	names := []string{stmt.From.TableName}
+// conditionLHS は式を BinaryExpr の左辺に変換する
+func conditionLHS(expr ast.Expr) ast.LHS {
+	switch e := expr.(type) {
+	case *ast.BinaryExpr:
+		return ast.NewLhsExpr(e)
+	case ast.ColumnId:
+		return ast.NewLhsColumn(e)
+	case ast.Literal:
+		return ast.NewLhsLiteral(e)
+	case *ast.AggregateExpr:
+		return ast.NewLhsAggregate(e)
+	}
+	return expr.(ast.LHS)
+}
+
+// conditionRHS は式を BinaryExpr の右辺に変換する
+func conditionRHS(expr ast.Expr) ast.RHS {
+	switch e := expr.(type) {
+	case *ast.BinaryExpr:
+		return ast.NewRhsExpr(e)
+	case ast.ColumnId:
+		return ast.NewRhsColumn(e)
+	case ast.Literal:
+		return ast.NewRhsLiteral(e)
+	case *ast.AggregateExpr:
+		return ast.NewRhsAggregate(e)
+	}
+	return expr.(ast.RHS)
+}
+
+// collectTables は SELECT 文から参加テーブル (FROM 句・JOIN 句のテーブル) を収集する
+func collectTables(stmt *ast.SelectStmt) []ast.TableId {
+	tables := []ast.TableId{stmt.From}
 	for _, join := range stmt.Joins {
-		names = append(names, join.Table.TableName)
+		tables = append(tables, join.Table)
 	}
-	return names
+	return tables
 }
 
 // buildJoinCandidates は各テーブルの joinCandidate を構築する
-func buildJoinCandidates(hdl *handler.Handler, tableNames []string) ([]joinCandidate, error) {
-	candidates := make([]joinCandidate, 0, len(tableNames))
-	for _, name := range tableNames {
+//
+// 導出テーブル・共通テーブル式は問い合わせを計画し、その Executor を結合に使う
+// 再帰 CTE の再帰部分の共通テーブル式自身 (self) は、作業テーブルを読み取る Executor を結合に使う
+func buildJoinCandidates(trxId handler.TrxId, hdl *handler.Handler, tables []ast.TableId, self *recursiveRef) ([]joinCandidate, error) {
+	candidates := make([]joinCandidate, 0, len(tables))
+	for _, table := range tables {
+		if self != nil && table.CTE == self.cte {
+			candidates = append(candidates, self.joinCandidate())
+			continue
+		}
+		if table.IsDerived() {
+			derived, err := planDerivedTable(trxId, table)
+			if err != nil {
+				return nil, err
+			}
+			candidates = append(candidates, joinCandidate{tblMeta: derived.tblMeta, stats: derived.stats, derived: derived.exec})
+			continue
+		}
+		name := table.TableName
 		tblMeta, ok := hdl.Catalog.GetTableMetaByName(name)
 		if !ok {
 			return nil, fmt.Errorf("table %s not found", name)
@@ -276,7 +613,7 @@ func buildJoinCandidates(hdl *handler.Handler, tableNames []string) ([]joinCandi
 		if err != nil {
 			return nil, err
 		}
-		tbl, err := hdl.GetTable(name)
+		tbl, err := hdl.OpenTable(trxId, name)
 		if err != nil {
 			return nil, err
 		}
@@ -285,57 +622,140 @@ func buildJoinCandidates(hdl *handler.Handler, tableNames []string) ([]joinCandi
 	return candidates, nil
 }
 
-// extractJoinPredicates は JoinClause の ON 条件から joinPredicate を抽出する
-func extractJoinPredicates(joins []*ast.JoinClause) ([]joinPredicate, error) {
+// extractJoinPredicates は JoinClause の ON 条件から joinPredicate と、それ以外の条件 (joinCondition) を抽出する
+//
+// 外部結合の ON 条件には NULL で補完される側 (内部表) のテーブルを記録する
+// RIGHT JOIN は左右を入れ替えて LEFT JOIN として扱う (FROM 句のテーブルが NULL で補完される側になる)
+// 左側が複数テーブルの結合になる RIGHT JOIN (2 番目以降の JOIN での RIGHT JOIN) はサポートしない
+func extractJoinPredicates(stmt *ast.SelectStmt) ([]joinPredicate, []joinCondition, error) {
 	var predicates []joinPredicate
-	for _, join := range joins {
-		preds, err := extractPredicatesFromExpr(join.Condition)
-		if err != nil {
-			return nil, err
+	var conditions []joinCondition
+	// JOIN 句の時点で参照できるテーブル (FROM 句のテーブルとそれより前の JOIN 句のテーブル)
+	visible := map[string]struct{}{stmt.From.TableName: {}}
+	tableNames := []string{stmt.From.TableName}
+	for _, join := range stmt.Joins {
+		tableNames = append(tableNames, join.Table.TableName)
+	}
+	for i, join := range stmt.Joins {
+		preds, others := extractPredicatesFromExpr(join.Condition)
+		for j := range preds {
+			preds[j].leftTable = resolveTableName(preds[j].leftTable, tableNames)
+			preds[j].rightTable = resolveTableName(preds[j].rightTable, tableNames)
+		}
+		visible[join.Table.TableName] = struct{}{}
+
+		outerTable := ""
+		switch join.Type {
+		case ast.JoinTypeLeft:
+			outerTable = join.Table.TableName
+		case ast.JoinTypeRight:
+			if i > 0 {
+				return nil, nil, fmt.Errorf("RIGHT JOIN %s is not supported: the left side of RIGHT JOIN must be a single table", join.Table.TableName)
+			}
+			outerTable = stmt.From.TableName
+		}
+
+		if outerTable != "" {
+			if err := validateOuterJoinPredicates(preds, outerTable, visible); err != nil {
+				return nil, nil, err
+			}
+			if err := validateOuterJoinConditions(others, preds, outerTable, tableNames); err != nil {
+				return nil, nil, err
+			}
+			for j := range preds {
+				preds[j].outerTable = outerTable
+			}
 		}
 		predicates = append(predicates, preds...)
+		for _, expr := range others {
+			conditions = append(conditions, joinCondition{expr: expr, outerTable: outerTable})
+		}
 	}
-	return predicates, nil
+	return predicates, conditions, nil
 }
 
-// extractPredicatesFromExpr は BinaryExpr から joinPredicate を再帰的に抽出する
+// resolveTableName はカラムの修飾名を、修飾名が指す文のテーブルの名前 (データベース名で修飾した名前) に変換する
 //
-// AND で複数条件が結合されている場合も対応する
-func extractPredicatesFromExpr(expr *ast.BinaryExpr) ([]joinPredicate, error) {
-	if expr == nil {
-		return nil, nil
+// 修飾名が指すテーブルがない場合はそのまま返す
+func resolveTableName(qualifier string, tableNames []string) string {
+	for _, name := range tableNames {
+		if refersToTable(qualifier, name) {
+			return name
+		}
+	}
+	return qualifier
+}
+
+// validateOuterJoinPredicates は外部結合の ON 条件が結合順序を決定できる形になっているか検証する
+//
+// ON 条件はその JOIN 句の時点で参照できるテーブルのみを参照し、
+// NULL で補完される側のテーブルと他のテーブルを結ぶ条件を少なくとも 1 つ含む必要がある
+func validateOuterJoinPredicates(preds []joinPredicate, outerTable string, visible map[string]struct{}) error {
+	hasJoinCondition := false
+	for _, pred := range preds {
+		for _, tableName := range []string{pred.leftTable, pred.rightTable} {
+			if _, ok := visible[tableName]; !ok {
+				return fmt.Errorf("unknown table %s in ON clause of OUTER JOIN", tableName)
+			}
+		}
+		if (pred.leftTable == outerTable) != (pred.rightTable == outerTable) {
+			hasJoinCondition = true
+		}
+	}
+	if !hasJoinCondition {
+		return fmt.Errorf("no join predicate for table %s", outerTable)
 	}
+	return nil
+}
 
-	// LhsColumn = RhsColumn の場合 → joinPredicate
-	if lhs, ok := expr.Left.(*ast.LhsColumn); ok {
-		if rhs, ok := expr.Right.(*ast.RhsColumn); ok {
-			return []joinPredicate{{
-				leftTable:  lhs.Column.TableName,
-				leftCol:    lhs.Column.ColName,
-				rightTable: rhs.Column.TableName,
-				rightCol:   rhs.Column.ColName,
-			}}, nil
-		}
-	}
-
-	// AND の場合: 左右を再帰
-	if lhsExpr, ok := expr.Left.(*ast.LhsExpr); ok {
-		if rhsExpr, ok := expr.Right.(*ast.RhsExpr); ok {
-			if expr.Operator == "AND" {
-				left, err := extractPredicatesFromExpr(lhsExpr.Expr)
-				if err != nil {
-					return nil, err
-				}
-				right, err := extractPredicatesFromExpr(rhsExpr.Expr)
-				if err != nil {
-					return nil, err
-				}
-				return append(left, right...), nil
+// validateOuterJoinConditions は外部結合の ON 条件のうち、カラム同士の等値条件以外の条件が参照するテーブルを検証する
+//
+// 条件は内部表の行が一致するかの判定に使うため、NULL で補完される側のテーブルと、その ON 条件の joinPredicate で結ぶテーブルのみを参照できる
+// (それ以外のテーブルは内部表を結合する時点で結合済みとは限らないため)
+func validateOuterJoinConditions(conds []ast.Expr, preds []joinPredicate, outerTable string, tableNames []string) error {
+	allowed := map[string]struct{}{outerTable: {}}
+	for _, pred := range preds {
+		allowed[pred.leftTable] = struct{}{}
+		allowed[pred.rightTable] = struct{}{}
+	}
+	for _, cond := range conds {
+		for _, col := range collectColumns(cond) {
+			if col.TableName == "" {
+				continue
+			}
+			tableName := resolveTableName(col.TableName, tableNames)
+			if _, ok := allowed[tableName]; !ok {
+				return fmt.Errorf("ON clause of OUTER JOIN %s cannot refer to table %s outside its join predicates", outerTable, tableName)
 			}
 		}
 	}
+	return nil
+}
 
-	return nil, fmt.Errorf("unsupported ON condition structure")
+// extractPredicatesFromExpr は ON 条件を AND で分解し、カラム同士の等値条件を joinPredicate として抽出する
+//
+// それ以外の条件 (カラムと定数の比較など) は others として返す
+func extractPredicatesFromExpr(condition ast.Expr) (predicates []joinPredicate, others []ast.Expr) {
+	if condition == nil {
+		return nil, nil
+	}
+	for _, cond := range splitAND(condition) {
+		if expr, ok := cond.(*ast.BinaryExpr); ok && expr.Operator == "=" {
+			lhs, lok := ast.LeftOperand(expr.Left).(ast.ColumnId)
+			rhs, rok := ast.RightOperand(expr.Right).(ast.ColumnId)
+			if lok && rok {
+				predicates = append(predicates, joinPredicate{
+					leftTable:  lhs.TableName,
+					leftCol:    lhs.ColName,
+					rightTable: rhs.TableName,
+					rightCol:   rhs.ColName,
+				})
+				continue
+			}
+		}
+		others = append(others, cond)
+	}
+	return predicates, others
 }
 
 // findPredicateForTable は ordered テーブル群の中から候補テーブルとの結合条件を探す
@@ -347,7 +767,240 @@ func findPredicateForTable(predicates []joinPredicate, tableName string, ordered
 	return findPredicate(predicates, tableName, resultTableNames)
 }
 
+// pendingInnerPredicates は INNER JOIN の ON 条件のうち、joined のテーブルのみを参照する未適用の条件を返す
+//
+// 返した条件は applied に記録する
+func pendingInnerPredicates(predicates []joinPredicate, joined []joinCandidate, applied map[*joinPredicate]struct{}) []*joinPredicate {
+	joinedTableNames := make(map[string]struct{}, len(joined))
+	for _, c := range joined {
+		joinedTableNames[c.tblMeta.Name] = struct{}{}
+	}
+
+	var pending []*joinPredicate
+	for i := range predicates {
+		pred := &predicates[i]
+		if pred.outerTable != "" {
+			continue
+		}
+		if _, ok := applied[pred]; ok {
+			continue
+		}
+		_, leftOk := joinedTableNames[pred.leftTable]
+		_, rightOk := joinedTableNames[pred.rightTable]
+		if leftOk && rightOk {
+			applied[pred] = struct{}{}
+			pending = append(pending, pred)
+		}
+	}
+	return pending
+}
+
+// matchJoinPredicates は結合レコードが全ての結合条件を満たすか判定する関数を構築する
+//
+// 結合カラムが NULL の場合は (NULL = NULL も含めて) 条件を満たさない
+func matchJoinPredicates(predicates []*joinPredicate, columns []joinedColumn) (func(executor.Record) bool, error) {
+	type colPair struct{ left, right int }
+	pairs := make([]colPair, len(predicates))
+	for i, pred := range predicates {
+		leftPos, err := findColumnPos(columns, pred.leftTable, pred.leftCol)
+		if err != nil {
+			return nil, err
+		}
+		rightPos, err := findColumnPos(columns, pred.rightTable, pred.rightCol)
+		if err != nil {
+			return nil, err
+		}
+		pairs[i] = colPair{left: leftPos, right: rightPos}
+	}
+
+	return func(record executor.Record) bool {
+		for _, p := range pairs {
+			left, right := record[p.left], record[p.right]
+			if left == nil || right == nil || !bytes.Equal(left, right) {
+				return false
+			}
+		}
+		return true
+	}, nil
+}
+
+// buildOuterJoinCondition は外部結合の内部表 outerTable について、ON 条件のうちアクセスパス (pred) に使わなかった条件を 1 つの条件式にまとめる
+//
+// 条件式は左側のレコードと内部表のレコードを連結したレコード (columns) に対して評価する
+// 該当する条件がない場合は nil を返す
+func buildOuterJoinCondition(
+	predicates []joinPredicate,
+	pred *joinPredicate,
+	conditions []joinCondition,
+	outerTable string,
+	columns []joinedColumn,
+) (executor.Expression, error) {
+	var residual []*joinPredicate
+	for i := range predicates {
+		p := &predicates[i]
+		if p.outerTable == outerTable && p != pred {
+			residual = append(residual, p)
+		}
+	}
+	var exprs []ast.Expr
+	for _, c := range conditions {
+		if c.outerTable == outerTable {
+			exprs = append(exprs, c.expr)
+		}
+	}
+
+	var cond executor.Expression
+	if len(residual) > 0 {
+		match, err := matchJoinPredicates(residual, columns)
+		if err != nil {
+			return nil, err
+		}
+		cond = executor.ConditionFunc(match)
+	}
+	if len(exprs) > 0 {
+		exprCond, err := buildJoinedCondition(combineExprsWithAND(exprs), columns)
+		if err != nil {
+			return nil, err
+		}
+		if cond == nil {
+			cond = exprCond
+		} else {
+			cond = executor.NewLogical("AND", cond, exprCond)
+		}
+	}
+	return cond, nil
+}
+
+// withInnerJoinConditions は INNER JOIN の ON 条件のうち、カラム同士の等値条件以外の条件を WHERE 条件に加える
+//
+// INNER JOIN では ON 条件を結合後に評価しても結果は変わらないため、WHERE 条件と同様に駆動表の絞り込みやアクセスパスの決定に使う
+func withInnerJoinConditions(where *ast.WhereClause, conditions []joinCondition) *ast.WhereClause {
+	var exprs []ast.Expr
+	for _, c := range conditions {
+		if c.outerTable == "" {
+			exprs = append(exprs, c.expr)
+		}
+	}
+	if len(exprs) == 0 {
+		return where
+	}
+	if where != nil {
+		exprs = append([]ast.Expr{where.Condition}, exprs...)
+	}
+	return &ast.WhereClause{Condition: combineExprsWithAND(exprs)}
+}
+
+// resolveJoinColPos は結合条件の結合カラムの位置を返す
+//
+// leftPos は左側 (結合レコード全体) での位置、rightPos は内部表のレコードでの位置
+func resolveJoinColPos(candidate joinCandidate, pred *joinPredicate, columns []joinedColumn) (leftPos int, rightPos int, err error) {
+	joinCol := resolveJoinCol(pred, candidate.tblMeta.Name)
+	otherTable, otherCol := pred.leftTable, pred.leftCol
+	if pred.leftTable == candidate.tblMeta.Name {
+		otherTable, otherCol = pred.rightTable, pred.rightCol
+	}
+
+	leftPos, err = findColumnPos(columns, otherTable, otherCol)
+	if err != nil {
+		return 0, 0, err
+	}
+	rightColMeta, ok := candidate.tblMeta.GetColByName(joinCol)
+	if !ok {
+		return 0, 0, fmt.Errorf("column %s not found in table %s", joinCol, candidate.tblMeta.Name)
+	}
+	return leftPos, int(rightColMeta.Pos), nil
+}
+
+// buildDrivingExec は駆動表の Executor と、その Executor が返すレコードの並び順を返す
+//
+// 通常のテーブルは drivingWhere をもとに Search でアクセスパスを決定する
+// 導出テーブルはサブクエリの結果を drivingWhere で絞り込む (並び順は保証しない)
+func buildDrivingExec(
+	rv *access.ReadView,
+	vr *access.VersionReader,
+	hdl *handler.Handler,
+	candidate joinCandidate,
+	drivingWhere *ast.WhereClause,
+) (executor.Executor, []int, error) {
+	if candidate.derived == nil {
+		search := NewSearch(rv, vr, candidate.tblMeta, drivingWhere, hdl.BufferPool)
+		exec, err := search.Build()
+		if err != nil {
+			return nil, nil, err
+		}
+		return exec, search.sortOrder, nil
+	}
+	if drivingWhere == nil {
+		return candidate.derived, nil, nil
+	}
+	cond, err := buildJoinedCondition(drivingWhere.Condition, resolveJoinedColumns([]*handler.TableMetadata{candidate.tblMeta}))
+	if err != nil {
+		return nil, nil, err
+	}
+	return executor.NewFilterWithExpression(candidate.derived, cond), nil, nil
+}
+
+// buildHashJoin は左側の Executor と内部表を HashJoin で結合する
+//
+// 内部表はフルスキャンで 1 回だけ読み込み (導出テーブルの場合はサブクエリの結果を読み込み)、ビルド側としてハッシュテーブルを構築する
+// 外部結合の場合、cond (ON 条件のうち結合キーに使わなかった条件) は内部表の行が一致するかの判定に使う
+func buildHashJoin(
+	rv *access.ReadView,
+	vr *access.VersionReader,
+	left executor.Executor,
+	candidate joinCandidate,
+	pred *joinPredicate,
+	cond executor.Expression,
+	outer bool,
+	columns []joinedColumn,
+) (executor.Executor, error) {
+	if pred == nil {
+		return nil, fmt.Errorf("no join predicate for table %s", candidate.tblMeta.Name)
+	}
+	leftJoinColPos, rightJoinColPos, err := resolveJoinColPos(candidate, pred, columns)
+	if err != nil {
+		return nil, err
+	}
+
+	// 導出テーブルはサブクエリの結果をそのままビルド側にする
+	build := candidate.derived
+	if build == nil {
+		scan := executor.NewTableScan(executor.TableScanParams{
+			ReadView:       rv,
+			VersionReader:  vr,
+			Table:          candidate.table,
+			SearchMode:     access.RecordSearchModeStart{},
+			WhileCondition: func(record executor.Record) bool { return true },
+		})
+		scan.SetPlanNote(executor.PlanNote{
+			Access:   "full scan",
+			Estimate: estimated(float64(candidate.stats.RecordCount), candidate.est.readCost),
+		})
+		build = scan
+	}
+
+	joinType := executor.HashJoinInner
+	if outer {
+		joinType = executor.HashJoinOuter
+	}
+	hashJoin := executor.NewHashJoinWithParams(executor.HashJoinParams{
+		ProbeExecutor: left,
+		BuildExecutor: build,
+		ProbeKeys:     []int{leftJoinColPos},
+		BuildKeys:     []int{rightJoinColPos},
+		Condition:     cond,
+		Type:          joinType,
+		BuildColCount: int(candidate.tblMeta.NCols),
+		BufferSize:    config.GetJoinBufferSize(),
+		TmpDir:        config.GetDataDirectory(),
+	})
+	hashJoin.SetPlanNote(candidate.joinNote())
+	return hashJoin, nil
+}
+
 // buildRightExecFunc は内部表の Executor ファクトリ関数を構築する
+//
+// 結合カラムが NULL の行は (NULL = NULL も含めて) どの行とも結合しない
 func buildRightExecFunc(
 	rv *access.ReadView,
 	vr *access.VersionReader,
@@ -359,43 +1012,27 @@ func buildRightExecFunc(
 		return nil, fmt.Errorf("no join predicate for table %s", candidate.tblMeta.Name)
 	}
 
-	// 結合カラム: 左側 (結合レコード内の位置) と右側 (右テーブル内の位置)
 	joinCol := resolveJoinCol(pred, candidate.tblMeta.Name)
-	otherCol := resolveJoinCol(pred, pred.leftTable)
-	if pred.leftTable == candidate.tblMeta.Name {
-		otherCol = resolveJoinCol(pred, pred.rightTable)
-	}
-
-	// 左側の結合カラム位置 (結合レコード全体)
-	otherTable := pred.leftTable
-	if pred.leftTable == candidate.tblMeta.Name {
-		otherTable = pred.rightTable
-	}
-	leftJoinColPos, err := findColumnPos(columns, otherTable, otherCol)
+	leftJoinColPos, rightJoinColPos, err := resolveJoinColPos(candidate, pred, columns)
 	if err != nil {
 		return nil, err
 	}
 
-	// 右側の結合カラム位置 (右テーブル単体)
-	rightColMeta, ok := candidate.tblMeta.GetColByName(joinCol)
-	if !ok {
-		return nil, fmt.Errorf("column %s not found in table %s", joinCol, candidate.tblMeta.Name)
-	}
-	rightJoinColPos := int(rightColMeta.Pos)
-
 	// PK eq_ref
 	if candidate.tblMeta.PKCount == 1 && rightJoinColPos == 0 {
 		return func(leftRecord executor.Record) (executor.Executor, error) {
 			key := leftRecord[leftJoinColPos]
-			return executor.NewTableScan(executor.TableScanParams{
+			scan := executor.NewTableScan(executor.TableScanParams{
 				ReadView:      rv,
 				VersionReader: vr,
 				Table:         candidate.table,
 				SearchMode:    access.RecordSearchModeKey{Key: [][]byte{key}},
 				WhileCondition: func(r executor.Record) bool {
-					return bytes.Equal(r[0], key)
+					return key != nil && bytes.Equal(r[0], key)
 				},
-			}), nil
+			})
+			scan.SetPlanNote(candidate.innerNote("PK lookup (eq_ref)"))
+			return scan, nil
 		}, nil
 	}
 
@@ -407,32 +1044,36 @@ func buildRightExecFunc(
 		}
 		return func(leftRecord executor.Record) (executor.Executor, error) {
 			key := leftRecord[leftJoinColPos]
-			// IndexScan の条件関数が受け取る Record はセカンダリキー値のみの 1 要素なので position 0 を使う
+			// IndexScan の条件関数が受け取る Record はセカンダリキー値のみなので、先頭カラムの position 0 を使う
 			cond := func(r executor.Record) bool {
-				return bytes.Equal(r[0], key)
+				return key != nil && bytes.Equal(r[0], key)
 			}
-			return executor.NewIndexScan(
+			scan := executor.NewIndexScan(
 				candidate.table,
 				index,
 				access.RecordSearchModeKey{Key: [][]byte{key}},
 				cond,
-			), nil
+			)
+			scan.SetPlanNote(candidate.innerNote("index lookup"))
+			return scan, nil
 		}, nil
 	}
 
 	// フルスキャン
 	return func(leftRecord executor.Record) (executor.Executor, error) {
 		key := leftRecord[leftJoinColPos]
+		scan := executor.NewTableScan(executor.TableScanParams{
+			ReadView:       rv,
+			VersionReader:  vr,
+			Table:          candidate.table,
+			SearchMode:     access.RecordSearchModeStart{},
+			WhileCondition: func(record executor.Record) bool { return true },
+		})
+		scan.SetPlanNote(candidate.innerNote("full scan"))
 		return executor.NewFilter(
-			executor.NewTableScan(executor.TableScanParams{
-				ReadView:       rv,
-				VersionReader:  vr,
-				Table:          candidate.table,
-				SearchMode:     access.RecordSearchModeStart{},
-				WhileCondition: func(record executor.Record) bool { return true },
-			}),
+			scan,
 			func(rightRecord executor.Record) bool {
-				return bytes.Equal(rightRecord[rightJoinColPos], key)
+				return key != nil && rightRecord[rightJoinColPos] != nil && bytes.Equal(rightRecord[rightJoinColPos], key)
 			},
 		), nil
 	}, nil
@@ -212,7 +212,7 @@ func TestSplitWhereForTable(t *testing.T) {
 		// THEN
 		require.NotNil(t, forTable)
 		assert.Nil(t, remaining)
-		assert.Equal(t, "=", forTable.Condition.Operator)
+		assert.Equal(t, "=", forTable.Condition.(*ast.BinaryExpr).Operator)
 	})
 
 	t.Run("他テーブルの条件は分離されず remaining に残る", func(t *testing.T) {
@@ -299,16 +299,16 @@ func TestSplitWhereForTable(t *testing.T) {
 	})
 }
 
-func TestCollectTableNames(t *testing.T) {
+func TestCollectTables(t *testing.T) {
 	t.Run("FROM のみの場合は 1 テーブル", func(t *testing.T) {
 		// GIVEN
 		stmt := &ast.SelectStmt{From: *ast.NewTableId("users")}
 
 		// WHEN
-		names := collectTableNames(stmt)
+		tables := collectTables(stmt)
 
 		// THEN
-		assert.Equal(t, []string{"users"}, names)
+		assert.Equal(t, []ast.TableId{*ast.NewTableId("users")}, tables)
 	})
 
 	t.Run("JOIN がある場合は FROM + JOIN テーブル", func(t *testing.T) {
@@ -322,10 +322,10 @@ func TestCollectTableNames(t *testing.T) {
 		}
 
 		// WHEN
-		names := collectTableNames(stmt)
+		tables := collectTables(stmt)
 
 		// THEN
-		assert.Equal(t, []string{"users", "orders", "items"}, names)
+		assert.Equal(t, []ast.TableId{*ast.NewTableId("users"), *ast.NewTableId("orders"), *ast.NewTableId("items")}, tables)
 	})
 }
 
@@ -338,10 +338,10 @@ func TestExtractPredicatesFromExpr(t *testing.T) {
 		)
 
 		// WHEN
-		preds, err := extractPredicatesFromExpr(expr)
+		preds, others := extractPredicatesFromExpr(expr)
 
 		// THEN
-		require.NoError(t, err)
+		assert.Empty(t, others)
 		require.Len(t, preds, 1)
 		assert.Equal(t, "users", preds[0].leftTable)
 		assert.Equal(t, "id", preds[0].leftCol)
@@ -363,10 +363,10 @@ func TestExtractPredicatesFromExpr(t *testing.T) {
 		)
 
 		// WHEN
-		preds, err := extractPredicatesFromExpr(expr)
+		preds, others := extractPredicatesFromExpr(expr)
 
 		// THEN
-		require.NoError(t, err)
+		assert.Empty(t, others)
 		require.Len(t, preds, 2)
 		assert.Equal(t, "users", preds[0].leftTable)
 		assert.Equal(t, "orders", preds[1].leftTable)
@@ -374,26 +374,176 @@ func TestExtractPredicatesFromExpr(t *testing.T) {
 
 	t.Run("nil の場合は空スライスが返される", func(t *testing.T) {
 		// WHEN
-		preds, err := extractPredicatesFromExpr(nil)
+		preds, others := extractPredicatesFromExpr(nil)
 
 		// THEN
-		require.NoError(t, err)
 		assert.Nil(t, preds)
+		assert.Nil(t, others)
 	})
 
-	t.Run("サポートされていない構造でエラーになる", func(t *testing.T) {
-		// GIVEN: col = literal (結合条件ではない)
-		expr := ast.NewBinaryExpr("=",
+	t.Run("カラム同士の等値条件以外の条件は others として返される", func(t *testing.T) {
+		// GIVEN: (users.id = orders.user_id) AND (orders.price > 100) AND (users.id < orders.id)
+		colLiteral := ast.NewBinaryExpr(">",
+			ast.NewLhsColumn(ast.ColumnId{TableName: "orders", ColName: "price"}),
+			ast.NewRhsLiteral(ast.NewStringLiteral("100")),
+		)
+		colCol := ast.NewBinaryExpr("<",
+			ast.NewLhsColumn(ast.ColumnId{TableName: "users", ColName: "id"}),
+			ast.NewRhsColumn(ast.ColumnId{TableName: "orders", ColName: "id"}),
+		)
+		expr := ast.NewBinaryExpr("AND",
+			ast.NewLhsExpr(ast.NewBinaryExpr("AND",
+				ast.NewLhsExpr(ast.NewBinaryExpr("=",
+					ast.NewLhsColumn(ast.ColumnId{TableName: "users", ColName: "id"}),
+					ast.NewRhsColumn(ast.ColumnId{TableName: "orders", ColName: "user_id"}),
+				)),
+				ast.NewRhsExpr(colLiteral),
+			)),
+			ast.NewRhsExpr(colCol),
+		)
+
+		// WHEN
+		preds, others := extractPredicatesFromExpr(expr)
+
+		// THEN
+		require.Len(t, preds, 1)
+		assert.Equal(t, "user_id", preds[0].rightCol)
+		assert.Equal(t, []ast.Expr{colLiteral, colCol}, others)
+	})
+}
+
+func TestExtractJoinPredicates(t *testing.T) {
+	// eq は table1.col1 = table2.col2 の結合条件を作成する
+	eq := func(table1, col1, table2, col2 string) ast.Expr {
+		return ast.NewBinaryExpr("=",
+			ast.NewLhsColumn(ast.ColumnId{TableName: table1, ColName: col1}),
+			ast.NewRhsColumn(ast.ColumnId{TableName: table2, ColName: col2}),
+		)
+	}
+
+	t.Run("LEFT JOIN の ON 条件には JOIN 句のテーブルが NULL で補完される側として記録される", func(t *testing.T) {
+		// GIVEN: users JOIN orders ON ... LEFT JOIN items ON ...
+		stmt := &ast.SelectStmt{
+			From: *ast.NewTableId("users"),
+			Joins: []*ast.JoinClause{
+				{Type: ast.JoinTypeInner, Table: *ast.NewTableId("orders"), Condition: eq("users", "id", "orders", "user_id")},
+				{Type: ast.JoinTypeLeft, Table: *ast.NewTableId("items"), Condition: eq("orders", "item_id", "items", "id")},
+			},
+		}
+
+		// WHEN
+		preds, _, err := extractJoinPredicates(stmt)
+
+		// THEN
+		require.NoError(t, err)
+		require.Len(t, preds, 2)
+		assert.Equal(t, "", preds[0].outerTable)
+		assert.Equal(t, "items", preds[1].outerTable)
+	})
+
+	t.Run("RIGHT JOIN の ON 条件には FROM 句のテーブルが NULL で補完される側として記録される", func(t *testing.T) {
+		// GIVEN
+		stmt := &ast.SelectStmt{
+			From: *ast.NewTableId("users"),
+			Joins: []*ast.JoinClause{
+				{Type: ast.JoinTypeRight, Table: *ast.NewTableId("orders"), Condition: eq("users", "id", "orders", "user_id")},
+			},
+		}
+
+		// WHEN
+		preds, _, err := extractJoinPredicates(stmt)
+
+		// THEN
+		require.NoError(t, err)
+		require.Len(t, preds, 1)
+		assert.Equal(t, "users", preds[0].outerTable)
+	})
+
+	t.Run("外部結合の ON 条件で後続の JOIN 句のテーブルを参照するとエラーになる", func(t *testing.T) {
+		// GIVEN: users LEFT JOIN orders ON items.id = orders.item_id JOIN items ON ...
+		stmt := &ast.SelectStmt{
+			From: *ast.NewTableId("users"),
+			Joins: []*ast.JoinClause{
+				{Type: ast.JoinTypeLeft, Table: *ast.NewTableId("orders"), Condition: eq("items", "id", "orders", "item_id")},
+				{Type: ast.JoinTypeInner, Table: *ast.NewTableId("items"), Condition: eq("users", "id", "items", "id")},
+			},
+		}
+
+		// WHEN
+		_, _, err := extractJoinPredicates(stmt)
+
+		// THEN
+		assert.ErrorContains(t, err, "unknown table items in ON clause of OUTER JOIN")
+	})
+
+	t.Run("外部結合の ON 条件が NULL で補完される側のテーブルと他のテーブルを結ばない場合、エラーになる", func(t *testing.T) {
+		// GIVEN: users LEFT JOIN orders ON orders.id = orders.user_id
+		stmt := &ast.SelectStmt{
+			From: *ast.NewTableId("users"),
+			Joins: []*ast.JoinClause{
+				{Type: ast.JoinTypeLeft, Table: *ast.NewTableId("orders"), Condition: eq("orders", "id", "orders", "user_id")},
+			},
+		}
+
+		// WHEN
+		_, _, err := extractJoinPredicates(stmt)
+
+		// THEN
+		assert.ErrorContains(t, err, "no join predicate for table orders")
+	})
+
+	t.Run("カラム同士の等値条件以外の ON 条件は、NULL で補完される側のテーブルとともに記録される", func(t *testing.T) {
+		// GIVEN: users JOIN orders ON ... AND orders.price > 100 LEFT JOIN items ON ... AND items.stock > 0
+		orderCond := ast.NewBinaryExpr(">",
+			ast.NewLhsColumn(ast.ColumnId{TableName: "orders", ColName: "price"}),
+			ast.NewRhsLiteral(ast.NewStringLiteral("100")),
+		)
+		itemCond := ast.NewBinaryExpr(">",
+			ast.NewLhsColumn(ast.ColumnId{TableName: "items", ColName: "stock"}),
+			ast.NewRhsLiteral(ast.NewStringLiteral("0")),
+		)
+		stmt := &ast.SelectStmt{
+			From: *ast.NewTableId("users"),
+			Joins: []*ast.JoinClause{
+				{Type: ast.JoinTypeInner, Table: *ast.NewTableId("orders"), Condition: ast.NewBinaryExpr("AND",
+					ast.NewLhsExpr(eq("users", "id", "orders", "user_id").(*ast.BinaryExpr)), ast.NewRhsExpr(orderCond))},
+				{Type: ast.JoinTypeLeft, Table: *ast.NewTableId("items"), Condition: ast.NewBinaryExpr("AND",
+					ast.NewLhsExpr(eq("orders", "item_id", "items", "id").(*ast.BinaryExpr)), ast.NewRhsExpr(itemCond))},
+			},
+		}
+
+		// WHEN
+		preds, conds, err := extractJoinPredicates(stmt)
+
+		// THEN
+		require.NoError(t, err)
+		assert.Len(t, preds, 2)
+		assert.Equal(t, []joinCondition{
+			{expr: orderCond, outerTable: ""},
+			{expr: itemCond, outerTable: "items"},
+		}, conds)
+	})
+
+	t.Run("外部結合の ON 条件で、その ON 条件の結合条件で結ばないテーブルを参照するとエラーになる", func(t *testing.T) {
+		// GIVEN: users JOIN orders ON ... LEFT JOIN items ON orders.item_id = items.id AND users.id > 1
+		usersCond := ast.NewBinaryExpr(">",
 			ast.NewLhsColumn(ast.ColumnId{TableName: "users", ColName: "id"}),
 			ast.NewRhsLiteral(ast.NewStringLiteral("1")),
 		)
+		stmt := &ast.SelectStmt{
+			From: *ast.NewTableId("users"),
+			Joins: []*ast.JoinClause{
+				{Type: ast.JoinTypeInner, Table: *ast.NewTableId("orders"), Condition: eq("users", "id", "orders", "user_id")},
+				{Type: ast.JoinTypeLeft, Table: *ast.NewTableId("items"), Condition: ast.NewBinaryExpr("AND",
+					ast.NewLhsExpr(eq("orders", "item_id", "items", "id").(*ast.BinaryExpr)), ast.NewRhsExpr(usersCond))},
+			},
+		}
 
 		// WHEN
-		_, err := extractPredicatesFromExpr(expr)
+		_, _, err := extractJoinPredicates(stmt)
 
 		// THEN
-		assert.Error(t, err)
-		assert.Contains(t, err.Error(), "unsupported ON condition")
+		assert.ErrorContains(t, err, "ON clause of OUTER JOIN items cannot refer to table users outside its join predicates")
 	})
 }
 
@@ -406,7 +556,7 @@ func TestCombineExprsWithAND(t *testing.T) {
 		)
 
 		// WHEN
-		result := combineExprsWithAND([]*ast.BinaryExpr{expr})
+		result := combineExprsWithAND([]ast.Expr{expr})
 
 		// THEN: そのまま返される (ポインタ同一)
 		assert.Same(t, expr, result)
@@ -428,18 +578,20 @@ func TestCombineExprsWithAND(t *testing.T) {
 		)
 
 		// WHEN
-		result := combineExprsWithAND([]*ast.BinaryExpr{expr1, expr2, expr3})
+		result := combineExprsWithAND([]ast.Expr{expr1, expr2, expr3})
 
 		// THEN: 結果は AND ツリー
-		assert.Equal(t, "AND", result.Operator)
+		and, ok := result.(*ast.BinaryExpr)
+		require.True(t, ok)
+		assert.Equal(t, "AND", and.Operator)
 
 		// 左辺も AND (expr1 AND expr2)
-		lhs, ok := result.Left.(*ast.LhsExpr)
+		lhs, ok := and.Left.(*ast.LhsExpr)
 		require.True(t, ok)
 		assert.Equal(t, "AND", lhs.Expr.Operator)
 
 		// 右辺は expr3
-		rhs, ok := result.Right.(*ast.RhsExpr)
+		rhs, ok := and.Right.(*ast.RhsExpr)
 		require.True(t, ok)
 		assert.Equal(t, ">", rhs.Expr.Operator)
 	})
@@ -0,0 +1,98 @@
+package planner
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/ren-yamanashi/minesql/internal/ast"
+	"github.com/ren-yamanashi/minesql/internal/executor"
+	"github.com/ren-yamanashi/minesql/internal/storage/handler"
+)
+
+// PlanSetOperation は集合演算 (UNION / INTERSECT / EXCEPT) を計画する
+//
+// 各オペランドの SELECT 文を計画し、その結果セットを集合演算の Executor で組み合わせた後、ORDER BY・LIMIT を適用する
+// 結果セットのカラム名は最も左の SELECT 文のものを使い、データ型は各オペランドのデータ型の共通のデータ型とする
+func PlanSetOperation(trxId handler.TrxId, stmt *ast.SetOperationStmt) (*PlanResult, error) {
+	if err := bindWith(stmt.With, stmt); err != nil {
+		return nil, err
+	}
+	if err := bindViews(stmt, nil); err != nil {
+		return nil, err
+	}
+	result, err := planSetOperationOperands(trxId, stmt)
+	if err != nil {
+		return nil, err
+	}
+	return planResultOrderBy(result, stmt.OrderBy, stmt.Limit)
+}
+
+// planSetOperationOperands は集合演算のオペランドを計画し、集合演算の Executor で組み合わせる
+//   - UNION ALL: UnionAll で連結する
+//   - UNION: UnionAll で連結した結果から HashDistinct で重複を除く
+//   - INTERSECT [ALL] / EXCEPT [ALL]: HashSetOperation で左側の結果を右側の結果と照合する
+func planSetOperationOperands(trxId handler.TrxId, stmt *ast.SetOperationStmt) (*PlanResult, error) {
+	left, err := planSetOperand(trxId, stmt.Left)
+	if err != nil {
+		return nil, err
+	}
+	right, err := planSetOperand(trxId, stmt.Right)
+	if err != nil {
+		return nil, err
+	}
+	columns, err := unifySetOperationColumns(stmt.Op, []*PlanResult{left, right})
+	if err != nil {
+		return nil, err
+	}
+
+	var exec executor.Executor
+	switch stmt.Op {
+	case ast.SetOpIntersect:
+		exec = executor.NewHashSetOperation(left.Exec, right.Exec, executor.SetOperationIntersect, stmt.All)
+	case ast.SetOpExcept:
+		exec = executor.NewHashSetOperation(left.Exec, right.Exec, executor.SetOperationExcept, stmt.All)
+	default:
+		exec = executor.NewUnionAll([]executor.Executor{left.Exec, right.Exec})
+		if !stmt.All {
+			exec = executor.NewHashDistinct(exec)
+		}
+	}
+	return &PlanResult{Exec: exec, Columns: columns}, nil
+}
+
+// planSetOperand は集合演算のオペランド (SELECT 文または入れ子の集合演算) を計画する
+func planSetOperand(trxId handler.TrxId, operand ast.Statement) (*PlanResult, error) {
+	switch s := operand.(type) {
+	case *ast.SelectStmt:
+		return PlanSelect(trxId, s)
+	case *ast.SetOperationStmt:
+		return planSetOperationOperands(trxId, s)
+	default:
+		return nil, fmt.Errorf("unsupported set operation operand: %T", s)
+	}
+}
+
+// unifySetOperationColumns は集合演算のオペランドの結果セットのカラムを共通のデータ型に揃え、揃えた結果セットのカラムを返す
+//
+// 重複の判定・照合は格納形式のまま行うため、格納形式が共通のデータ型と異なるオペランドの Executor には変換 (Project) を重ねる
+// カラム数が異なる場合や、共通のデータ型がないカラム (e.g. INT と VARCHAR) がある場合はエラー
+func unifySetOperationColumns(op ast.SetOperator, results []*PlanResult) ([]ColumnMeta, error) {
+	columns := slices.Clone(results[0].Columns)
+	for _, result := range results[1:] {
+		if len(result.Columns) != len(columns) {
+			return nil, fmt.Errorf("the SELECT statements of %s have a different number of columns", op)
+		}
+		for i := range columns {
+			common, ok := commonType(columns[i].metadata(), result.Columns[i].metadata())
+			if !ok {
+				return nil, fmt.Errorf("column %d of the SELECT statements of %s has different types", i+1, op)
+			}
+			columns[i].Type, columns[i].Precision, columns[i].Scale = common.Type, common.Precision, common.Scale
+		}
+	}
+	for _, result := range results {
+		result.Exec = conformColumns(result, columns)
+		result.Columns = columns
+	}
+	return columns, nil
+}
@@ -0,0 +1,218 @@
+package planner
+
+import (
+	"testing"
+
+	"github.com/ren-yamanashi/minesql/internal/ast"
+	"github.com/ren-yamanashi/minesql/internal/executor"
+	"github.com/ren-yamanashi/minesql/internal/storage/handler"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlanSetOperation(t *testing.T) {
+	t.Run("UNION は両側の結果を連結して重複を除く", func(t *testing.T) {
+		// GIVEN
+		setupUsersTable(t)
+		defer handler.Reset()
+		stmt := parseStatementForTest(t, "SELECT first_name FROM users WHERE id <= '2' UNION SELECT last_name FROM users WHERE id <= '2';").(*ast.SetOperationStmt)
+
+		// WHEN
+		plan, err := PlanSetOperation(0, stmt)
+		require.NoError(t, err)
+		results := fetchAll(t, plan.Exec)
+
+		// THEN
+		assert.IsType(t, &executor.HashSetOperation{}, plan.Exec)
+		assert.ElementsMatch(t, []string{"John", "Doe", "Doe2"}, columnStrings(results, 0))
+		assert.Equal(t, "first_name", plan.Columns[0].ColName)
+	})
+
+	t.Run("UNION ALL は重複を除かずに連結する", func(t *testing.T) {
+		// GIVEN
+		setupUsersTable(t)
+		defer handler.Reset()
+		stmt := parseStatementForTest(t, "SELECT first_name FROM users WHERE id <= '2' UNION ALL SELECT last_name FROM users WHERE id <= '2';").(*ast.SetOperationStmt)
+
+		// WHEN
+		plan, err := PlanSetOperation(0, stmt)
+		require.NoError(t, err)
+		results := fetchAll(t, plan.Exec)
+
+		// THEN
+		assert.IsType(t, &executor.UnionAll{}, plan.Exec)
+		assert.Equal(t, []string{"John", "John", "Doe", "Doe2"}, columnStrings(results, 0))
+	})
+
+	t.Run("INTERSECT は両側に含まれる行を返す", func(t *testing.T) {
+		// GIVEN
+		setupUsersTable(t)
+		defer handler.Reset()
+		stmt := parseStatementForTest(t, "SELECT first_name FROM users WHERE gender = 'male' INTERSECT SELECT first_name FROM users WHERE id >= '3' ORDER BY first_name;").(*ast.SetOperationStmt)
+
+		// WHEN
+		plan, err := PlanSetOperation(0, stmt)
+		require.NoError(t, err)
+		results := fetchAll(t, plan.Exec)
+
+		// THEN
+		assert.Equal(t, []string{"John", "Jonathan", "Tom"}, columnStrings(results, 0))
+	})
+
+	t.Run("EXCEPT は右側に含まれない左側の行を返す", func(t *testing.T) {
+		// GIVEN
+		setupUsersTable(t)
+		defer handler.Reset()
+		stmt := parseStatementForTest(t, "SELECT first_name FROM users WHERE gender = 'male' EXCEPT SELECT first_name FROM users WHERE id = '5' ORDER BY first_name;").(*ast.SetOperationStmt)
+
+		// WHEN
+		plan, err := PlanSetOperation(0, stmt)
+		require.NoError(t, err)
+		results := fetchAll(t, plan.Exec)
+
+		// THEN
+		assert.Equal(t, []string{"John", "Tom"}, columnStrings(results, 0))
+	})
+
+	t.Run("EXCEPT ALL は右側に含まれる数だけ左側の行を除く", func(t *testing.T) {
+		// GIVEN
+		setupUsersTable(t)
+		defer handler.Reset()
+		stmt := parseStatementForTest(t, "SELECT first_name FROM users WHERE gender = 'male' EXCEPT ALL SELECT first_name FROM users WHERE id >= '3' ORDER BY first_name;").(*ast.SetOperationStmt)
+
+		// WHEN
+		plan, err := PlanSetOperation(0, stmt)
+		require.NoError(t, err)
+		results := fetchAll(t, plan.Exec)
+
+		// THEN
+		assert.Equal(t, []string{"John", "John"}, columnStrings(results, 0))
+	})
+
+	t.Run("3 つ以上の SELECT 文を組み合わせ、結果セットに ORDER BY・LIMIT を適用する", func(t *testing.T) {
+		// GIVEN
+		setupUsersTable(t)
+		defer handler.Reset()
+		stmt := parseStatementForTest(t, "SELECT first_name FROM users UNION SELECT last_name FROM users EXCEPT SELECT first_name FROM users WHERE id = '1' ORDER BY first_name DESC LIMIT 3;").(*ast.SetOperationStmt)
+
+		// WHEN
+		plan, err := PlanSetOperation(0, stmt)
+		require.NoError(t, err)
+		results := fetchAll(t, plan.Exec)
+
+		// THEN
+		assert.Equal(t, []string{"Tom", "Jonathan", "Jane"}, columnStrings(results, 0))
+	})
+
+	t.Run("FROM 句のないオペランドは SELECT リストの式を 1 行として返す", func(t *testing.T) {
+		// GIVEN
+		setupUsersTable(t)
+		defer handler.Reset()
+		stmt := parseStatementForTest(t, "SELECT first_name FROM users WHERE id = '1' UNION ALL SELECT 'Zed' ORDER BY first_name DESC;").(*ast.SetOperationStmt)
+
+		// WHEN
+		plan, err := PlanSetOperation(0, stmt)
+		require.NoError(t, err)
+		results := fetchAll(t, plan.Exec)
+
+		// THEN
+		assert.Equal(t, []string{"Zed", "John"}, columnStrings(results, 0))
+		assert.Equal(t, "first_name", plan.Columns[0].ColName)
+	})
+
+	t.Run("FROM 句のないオペランドでカラム・集約関数を参照した場合はエラーを返す", func(t *testing.T) {
+		tests := []struct {
+			name string
+			sql  string
+			err  string
+		}{
+			{"カラム", "SELECT first_name FROM users UNION SELECT users.first_name;", "column users.first_name cannot be referenced in SELECT without FROM clause"},
+			{"集約関数", "SELECT id FROM users UNION SELECT COUNT(*);", "SELECT without FROM clause must not contain aggregate or window functions"},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				// GIVEN
+				setupUsersTable(t)
+				defer handler.Reset()
+				stmt := parseStatementForTest(t, tt.sql).(*ast.SetOperationStmt)
+
+				// WHEN
+				_, err := PlanSetOperation(0, stmt)
+
+				// THEN
+				assert.EqualError(t, err, tt.err)
+			})
+		}
+	})
+
+	t.Run("カラム数が異なる場合はエラーを返す", func(t *testing.T) {
+		// GIVEN
+		setupUsersTable(t)
+		defer handler.Reset()
+		stmt := parseStatementForTest(t, "SELECT first_name FROM users UNION SELECT first_name, last_name FROM users;").(*ast.SetOperationStmt)
+
+		// WHEN
+		_, err := PlanSetOperation(0, stmt)
+
+		// THEN
+		assert.EqualError(t, err, "the SELECT statements of UNION have a different number of columns")
+	})
+
+	t.Run("格納形式が異なるカラムは共通のデータ型に揃えて組み合わせる", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			sql      string
+			expected []int64
+			colType  handler.ColumnType
+			scale    uint8
+		}{
+			{"INT と式の結果 (BIGINT)", "SELECT id FROM items WHERE id <= 2 UNION SELECT id + 1 FROM items WHERE id <= 2 ORDER BY id;", []int64{1, 2, 3}, handler.ColumnTypeBigInt, 0},
+			{"INT と DECIMAL", "SELECT price FROM items WHERE id = 1 UNION ALL SELECT 2.5 ORDER BY price;", []int64{25, 100}, handler.ColumnTypeDecimal, 1},
+			{"DECIMAL と INT", "SELECT 2.5 UNION SELECT price FROM items WHERE id = 1 EXCEPT SELECT price FROM items WHERE id = 1;", []int64{25}, handler.ColumnTypeDecimal, 1},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				// GIVEN
+				setupItemsTable(t)
+				defer handler.Reset()
+				stmt := parseStatementForTest(t, tt.sql).(*ast.SetOperationStmt)
+
+				// WHEN
+				plan, err := PlanSetOperation(0, stmt)
+				require.NoError(t, err)
+				results := fetchAll(t, plan.Exec)
+
+				// THEN
+				assert.Equal(t, tt.expected, int64Column(results, 0))
+				assert.Equal(t, tt.colType, plan.Columns[0].Type)
+				assert.Equal(t, tt.scale, plan.Columns[0].Scale)
+			})
+		}
+	})
+
+	t.Run("カラムのデータ型に共通のデータ型がない場合はエラーを返す", func(t *testing.T) {
+		// GIVEN
+		setupItemsTable(t)
+		defer handler.Reset()
+		stmt := parseStatementForTest(t, "SELECT category FROM items INTERSECT SELECT price FROM items;").(*ast.SetOperationStmt)
+
+		// WHEN
+		_, err := PlanSetOperation(0, stmt)
+
+		// THEN
+		assert.EqualError(t, err, "column 1 of the SELECT statements of INTERSECT has different types")
+	})
+
+	t.Run("ORDER BY に結果セットにないカラムを指定した場合はエラーを返す", func(t *testing.T) {
+		// GIVEN
+		setupUsersTable(t)
+		defer handler.Reset()
+		stmt := parseStatementForTest(t, "SELECT first_name FROM users UNION SELECT last_name FROM users ORDER BY last_name;").(*ast.SetOperationStmt)
+
+		// WHEN
+		_, err := PlanSetOperation(0, stmt)
+
+		// THEN
+		assert.EqualError(t, err, "ORDER BY column last_name is not in the result columns")
+	})
+}
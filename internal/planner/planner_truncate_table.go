@@ -0,0 +1,21 @@
+package planner
+
+import (
+	"fmt"
+
+	"github.com/ren-yamanashi/minesql/internal/ast"
+	"github.com/ren-yamanashi/minesql/internal/executor"
+	"github.com/ren-yamanashi/minesql/internal/storage/handler"
+)
+
+// PlanTruncateTable は TRUNCATE TABLE 文のバリデーションを行い、TruncateTable executor を構築する
+func PlanTruncateTable(trxId handler.TrxId, stmt *ast.TruncateTableStmt) (executor.Executor, error) {
+	hdl := handler.Get()
+
+	// 対象テーブルがカタログに存在するか検証
+	if _, ok := hdl.Catalog.GetTableMetaByName(stmt.TableName); !ok {
+		return nil, fmt.Errorf("table %s not found", stmt.TableName)
+	}
+
+	return executor.NewTruncateTable(trxId, stmt.TableName), nil
+}
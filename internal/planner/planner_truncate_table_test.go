@@ -0,0 +1,52 @@
+package planner
+
+import (
+	"testing"
+
+	"github.com/ren-yamanashi/minesql/internal/ast"
+	"github.com/ren-yamanashi/minesql/internal/executor"
+	"github.com/ren-yamanashi/minesql/internal/storage/handler"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlanTruncateTable(t *testing.T) {
+	t.Run("存在するテーブルに対して TruncateTable executor を返す", func(t *testing.T) {
+		// GIVEN
+		setupUsersTable(t)
+		defer handler.Reset()
+		stmt := &ast.TruncateTableStmt{TableName: "users"}
+
+		// WHEN
+		exec, err := PlanTruncateTable(0, stmt)
+
+		// THEN
+		assert.NoError(t, err)
+		assert.IsType(t, &executor.TruncateTable{}, exec)
+	})
+
+	t.Run("存在しないテーブルの場合エラーを返す", func(t *testing.T) {
+		// GIVEN
+		setupUsersTable(t)
+		defer handler.Reset()
+		stmt := &ast.TruncateTableStmt{TableName: "orders"}
+
+		// WHEN
+		exec, err := PlanTruncateTable(0, stmt)
+
+		// THEN
+		assert.Nil(t, exec)
+		assert.ErrorContains(t, err, "table orders not found")
+	})
+
+	t.Run("実行するとテーブルが空になる", func(t *testing.T) {
+		// GIVEN
+		setupUsersTable(t)
+		defer handler.Reset()
+
+		// WHEN
+		executePlan(t, &ast.TruncateTableStmt{TableName: "users"})
+
+		// THEN
+		assert.Empty(t, executePlan(t, &ast.SelectStmt{From: *ast.NewTableId("users")}))
+	})
+}
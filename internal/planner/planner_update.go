@@ -11,31 +11,34 @@ import (
 )
 
 func PlanUpdate(trxId handler.TrxId, stmt *ast.UpdateStmt) (executor.Executor, error) {
+	if len(stmt.Joins) > 0 {
+		return planUpdateJoin(trxId, stmt)
+	}
+
 	hdl := handler.Get()
 
+	// 対象がビューの場合は、ビューが参照するテーブルに対する UPDATE に変換する
+	if view, ok := hdl.Catalog.GetViewByName(stmt.Table.TableName); ok {
+		updatable, err := resolveUpdatableView(view, nil)
+		if err != nil {
+			return nil, err
+		}
+		if stmt, err = updatable.rewriteUpdate(stmt); err != nil {
+			return nil, err
+		}
+	}
+
 	// 対象テーブルのメタデータを取得
 	tblMeta, ok := hdl.Catalog.GetTableMetaByName(stmt.Table.TableName)
 	if !ok {
 		return nil, fmt.Errorf("table %s not found", stmt.Table.TableName)
 	}
 
-	// カラム名からカラム位置へのマッピングを作成
-	colPosMap := make(map[string]uint16)
-	for _, colMeta := range tblMeta.Cols {
-		colPosMap[colMeta.Name] = colMeta.Pos
-	}
-
-	// SetClause を Executor の SetColumn に変換
-	var setColumns []executor.SetColumn
-	for _, setClause := range stmt.SetClauses {
-		pos, ok := colPosMap[setClause.Column.ColName]
-		if !ok {
-			return nil, errors.New("column does not exist: " + setClause.Column.ColName)
-		}
-		setColumns = append(setColumns, executor.SetColumn{
-			Pos:   pos,
-			Value: setClause.Value.ToBytes(),
-		})
+	// SetClause を Executor の SetColumn に変換 (値はカラムのデータ型に応じた格納形式に変換する)
+	// 値が定数の場合 (e.g. `SET name = 'foo'`) はここで評価し、カラムを参照する場合 (e.g. `SET n = n + 1`) は更新時にレコードごとに評価する
+	setColumns, err := compileSetClauses(tblMeta, stmt.SetClauses, newTableExprScope(tblMeta))
+	if err != nil {
+		return nil, err
 	}
 
 	// WHERE 句を元に検索用の Executor を構築 (Current Read: 最新バージョンを読む)
@@ -48,10 +51,110 @@ func PlanUpdate(trxId handler.TrxId, stmt *ast.UpdateStmt) (executor.Executor, e
 	}
 
 	// テーブルを取得
-	tbl, err := hdl.GetTable(stmt.Table.TableName)
+	tbl, err := hdl.OpenTable(trxId, stmt.Table.TableName)
 	if err != nil {
 		return nil, err
 	}
 
-	return executor.NewUpdate(trxId, tbl, setColumns, iterator), nil
+	checks, err := compileCheckConstraints(tblMeta)
+	if err != nil {
+		return nil, err
+	}
+	upd := executor.NewUpdate(trxId, tbl, setColumns, iterator)
+	upd.SetCheckConstraints(checks)
+	return upd, nil
+}
+
+// planUpdateJoin は JOIN 句を含む UPDATE (複数テーブルの UPDATE) の実行計画を構築する
+//
+// SELECT と同じ方法で結合レコードを返す Executor ツリーを構築し (Current Read)、結合レコードから取り出した更新対象のテーブルの行を Update で更新する
+// 更新対象のテーブルは SET 句のカラムで決まり、SET 句で更新できるのは 1 つのテーブルのカラムのみ
+func planUpdateJoin(trxId handler.TrxId, stmt *ast.UpdateStmt) (executor.Executor, error) {
+	hdl := handler.Get()
+
+	rv := access.NewReadView(0, nil, ^uint64(0))
+	vr := access.NewVersionReader(nil)
+	sel := &ast.SelectStmt{From: stmt.Table, Joins: stmt.Joins, Where: stmt.Where}
+	if err := bindViews(sel, nil); err != nil {
+		return nil, err
+	}
+	join, err := planJoin(trxId, rv, vr, sel, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// SET 句のカラムから更新対象のテーブルを決定
+	targetName := ""
+	for _, setClause := range stmt.SetClauses {
+		pos, err := findColumnPos(join.columns, setClause.Column.TableName, setClause.Column.ColName)
+		if err != nil {
+			return nil, err
+		}
+		tableName := join.columns[pos].tableName
+		if targetName != "" && tableName != targetName {
+			return nil, errors.New("multi-table UPDATE can only update columns of one table")
+		}
+		targetName = tableName
+	}
+	tblMeta, ok := hdl.Catalog.GetTableMetaByName(targetName)
+	if !ok {
+		return nil, fmt.Errorf("table %s not found", targetName)
+	}
+	offset, err := findTableOffset(join.columns, targetName)
+	if err != nil {
+		return nil, err
+	}
+
+	// SET 句の値は、更新対象の行の後ろに結合レコードを連結したレコードに対して評価する
+	setColumns, err := compileSetClauses(tblMeta, stmt.SetClauses, newJoinTargetExprScope(tblMeta, join.columns))
+	if err != nil {
+		return nil, err
+	}
+
+	tbl, err := hdl.OpenTable(trxId, targetName)
+	if err != nil {
+		return nil, err
+	}
+
+	checks, err := compileCheckConstraints(tblMeta)
+	if err != nil {
+		return nil, err
+	}
+	upd := executor.NewUpdate(trxId, tbl, setColumns, join.exec)
+	upd.SetCheckConstraints(checks)
+	upd.SetJoinTarget(offset, int(tblMeta.NCols))
+	return upd, nil
+}
+
+// compileSetClauses は SET 句を Executor の SetColumn に変換する
+//
+// 値が定数の場合はここで格納形式に変換し、それ以外の場合は更新時にレコードごとに評価する式を設定する
+func compileSetClauses(tblMeta *handler.TableMetadata, setClauses []*ast.SetClause, scope *exprScope) ([]executor.SetColumn, error) {
+	var setColumns []executor.SetColumn
+	for _, setClause := range setClauses {
+		colMeta, ok := tblMeta.GetColByName(setClause.Column.ColName)
+		if setClause.Column.TableName != "" && !refersToTable(setClause.Column.TableName, tblMeta.Name) {
+			ok = false
+		}
+		if !ok {
+			return nil, errors.New("column does not exist: " + setClause.Column.ColName)
+		}
+		expr, err := compileAssignment(setClause.Value, scope, colMeta)
+		if err != nil {
+			return nil, err
+		}
+		constant, ok := expr.(*executor.Constant)
+		if !ok {
+			setColumns = append(setColumns, executor.SetColumn{Pos: colMeta.Pos, Expr: expr})
+			continue
+		}
+		if constant.Value == nil && isNotNullColumn(tblMeta, colMeta) {
+			return nil, notNullError(colMeta.Name)
+		}
+		setColumns = append(setColumns, executor.SetColumn{
+			Pos:   colMeta.Pos,
+			Value: constant.Value,
+		})
+	}
+	return setColumns, nil
 }
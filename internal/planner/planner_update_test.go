@@ -5,10 +5,12 @@ import (
 
 	"github.com/ren-yamanashi/minesql/internal/ast"
 	"github.com/ren-yamanashi/minesql/internal/executor"
+	"github.com/ren-yamanashi/minesql/internal/parser"
 	"github.com/ren-yamanashi/minesql/internal/storage/access"
 	"github.com/ren-yamanashi/minesql/internal/storage/handler"
 	"github.com/ren-yamanashi/minesql/internal/storage/lock"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestPlanUpdate(t *testing.T) {
@@ -332,9 +334,114 @@ func TestPlanUpdate(t *testing.T) {
 		assert.True(t, ok)
 		assert.Equal(t, "Bob", string(record[1]))
 	})
+	t.Run("CHECK 制約を満たさない値に更新するとエラーを返し、レコードは変更されない", func(t *testing.T) {
+		// GIVEN
+		initStorageManagerForTest(t)
+		defer handler.Reset()
+
+		hdl := handler.Get()
+		createTable := executor.NewCreateTable("users", 1, nil, []handler.CreateColumnParam{
+			{Name: "id", Type: handler.ColumnTypeString},
+			{Name: "name", Type: handler.ColumnTypeString},
+		}, []handler.CreateConstraintParam{
+			{ConstraintName: "chk_name", CheckExpr: "name <> ''"},
+		})
+		_, err := createTable.Next()
+		assert.NoError(t, err)
+		tbl := getPlannerTable(t, "users")
+		err = tbl.Insert(hdl.BufferPool, 0, lock.NewManager(5000), [][]byte{[]byte("1"), []byte("Alice")})
+		assert.NoError(t, err)
+
+		stmt := &ast.UpdateStmt{
+			Table: *ast.NewTableId("users"),
+			SetClauses: []*ast.SetClause{
+				{Column: *ast.NewColumnId("name"), Value: ast.NewStringLiteral("")},
+			},
+		}
+
+		// WHEN
+		trxId := hdl.BeginTrx()
+		exec, err := PlanUpdate(trxId, stmt)
+		assert.NoError(t, err)
+		_, err = exec.Next()
+		assert.NoError(t, hdl.RollbackTrx(trxId))
+
+		// THEN
+		var checkErr *executor.CheckConstraintViolationError
+		assert.ErrorAs(t, err, &checkErr)
+		assert.Equal(t, "chk_name", checkErr.Name)
+		iter, err := tbl.Search(hdl.BufferPool, access.NewReadView(0, nil, ^uint64(0)), access.NewVersionReader(nil), access.RecordSearchModeStart{})
+		assert.NoError(t, err)
+		record, ok, err := iter.Next()
+		assert.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, "Alice", string(record[1]))
+	})
 }
 
 //nolint:unparam // テーブル名は将来的に変わりうる
+func TestPlanUpdateJoin(t *testing.T) {
+	t.Run("結合したテーブルの値で更新対象のテーブルの行を更新する", func(t *testing.T) {
+		// GIVEN
+		setupOrdersTable(t)
+		stmt := parseStatementForTest(t, "UPDATE orders JOIN users ON orders.user_id = users.id SET orders.status = users.gender WHERE users.first_name = 'John';")
+
+		// WHEN
+		executePlan(t, stmt)
+
+		// THEN
+		records := executePlan(t, &ast.SelectStmt{From: *ast.NewTableId("orders")})
+		assert.Equal(t, []string{"male", "male", "open", "open"}, columnStrings(records, 2))
+	})
+
+	t.Run("複数の結合レコードに含まれる行は 1 回だけ更新する", func(t *testing.T) {
+		// GIVEN
+		setupOrdersTable(t)
+		stmt := parseStatementForTest(t, "UPDATE users JOIN orders ON orders.user_id = users.id SET users.last_name = orders.id;")
+
+		// WHEN
+		executePlan(t, stmt)
+
+		// THEN: users.id = 1 は orders.id = 1, 2 と結合するが、最初の結合レコードの値で 1 回だけ更新される
+		records := executePlan(t, &ast.SelectStmt{From: *ast.NewTableId("users")})
+		assert.Equal(t, []string{"1", "Doe2", "Doe3", "3", "Black", "Brown"}, columnStrings(records, 2))
+	})
+
+	t.Run("複数のテーブルのカラムを SET 句で更新する場合、エラーを返す", func(t *testing.T) {
+		// GIVEN
+		setupOrdersTable(t)
+		stmt := parseStatementForTest(t, "UPDATE orders JOIN users ON orders.user_id = users.id SET orders.status = 'a', users.gender = 'b';")
+
+		// WHEN
+		exec, err := PlanUpdate(1, stmt.(*ast.UpdateStmt))
+
+		// THEN
+		assert.Nil(t, exec)
+		assert.ErrorContains(t, err, "multi-table UPDATE can only update columns of one table")
+	})
+
+	t.Run("SET 句のカラムが複数のテーブルに存在する場合、エラーを返す", func(t *testing.T) {
+		// GIVEN
+		setupOrdersTable(t)
+		stmt := parseStatementForTest(t, "UPDATE orders JOIN users ON orders.user_id = users.id SET id = '10';")
+
+		// WHEN
+		exec, err := PlanUpdate(1, stmt.(*ast.UpdateStmt))
+
+		// THEN
+		assert.Nil(t, exec)
+		assert.ErrorContains(t, err, "ambiguous column name: id")
+	})
+}
+
+// SQL をパースして AST を返す
+func parseStatementForTest(t *testing.T, sql string) ast.Statement {
+	t.Helper()
+	stmt, err := parser.NewParser().Parse(sql)
+	require.NoError(t, err)
+	return stmt
+}
+
 func getPlannerTable(t *testing.T, tableName string) *access.Table {
 	t.Helper()
 	tbl, err := handler.Get().GetTable(tableName)
@@ -1,7 +1,10 @@
 package planner
 
 import (
+	"bytes"
 	"errors"
+	"math/big"
+	"slices"
 
 	"github.com/ren-yamanashi/minesql/internal/ast"
 	"github.com/ren-yamanashi/minesql/internal/executor"
@@ -21,6 +24,9 @@ type Search struct {
 	where         *ast.WhereClause
 	bufferPool    *buffer.BufferPool
 	selectColumns []ast.ColumnId // SELECT で指定されたカラム (nil なら SELECT *)
+	sortOrder     []int          // Build で構築した Executor が返すレコードの並び順 (昇順に並ぶカラムの位置)。順序が保証されない場合は nil
+	pkOrderLimit  uint64         // PK 順に先頭から何行あれば足りるか (ORDER BY pk LIMIT n の場合に設定)。0 なら制限なし
+	preferOrder   []ast.ColumnId // 返すレコードをこのカラムの順に並べたい場合に設定する (SELECT DISTINCT のカラム、GROUP BY のカラム)。nil なら指定なし
 }
 
 func NewSearch(readView *access.ReadView, versionReader *access.VersionReader, tblMeta *handler.TableMetadata, where *ast.WhereClause, bp *buffer.BufferPool) *Search {
@@ -28,7 +34,7 @@ func NewSearch(readView *access.ReadView, versionReader *access.VersionReader, t
 		readView:      readView,
 		versionReader: versionReader,
 		tblMeta:       tblMeta,
-		where:         where,
+		where:         foldSearchConstants(where, tblMeta),
 		bufferPool:    bp,
 	}
 }
@@ -38,82 +44,83 @@ func (s *Search) SetSelectColumns(columns []ast.ColumnId) {
 	s.selectColumns = columns
 }
 
+// SetPreferredOrder はレコードを columns の順に並べて返せるアクセス方法を優先することを設定する
+//
+// WHERE 句がない場合に、columns を先頭に持つインデックスを index-only scan に優先して使う
+func (s *Search) SetPreferredOrder(columns []ast.ColumnId) {
+	s.preferOrder = columns
+}
+
+// SetPKOrderLimit は PK 順に先頭から n 行あれば足りることを設定する (テーブルスキャンのコスト見積もり用)
+func (s *Search) SetPKOrderLimit(n uint64) {
+	s.pkOrderLimit = n
+}
+
 func (sp *Search) Build() (executor.Executor, error) {
-	tbl, err := handler.Get().GetTable(sp.tblMeta.Name)
+	tbl, err := handler.Get().OpenTable(sp.readView.TrxId, sp.tblMeta.Name)
 	if err != nil {
 		return nil, err
 	}
 
 	// WHERE 句が設定されていない場合フルテーブルスキャンを実行
 	if sp.where == nil {
-		return executor.NewTableScan(executor.TableScanParams{
+		// SELECT カラムをインデックスだけでカバーできる場合は、テーブル本体より小さいインデックスを先頭から走査する
+		if idxMeta, ok := sp.findFullIndexOnlyScanIndex(); ok {
+			return sp.buildFullIndexOnlyScan(tbl, idxMeta)
+		}
+		sp.sortOrder = sp.pkOrder()
+		scan := executor.NewTableScan(executor.TableScanParams{
 			ReadView:       sp.readView,
 			VersionReader:  sp.versionReader,
 			Table:          tbl,
 			SearchMode:     access.RecordSearchModeStart{},
 			WhileCondition: func(record executor.Record) bool { return true },
-		}), nil
+		})
+		scan.SetPlanNote(executor.PlanNote{Access: "full scan", Estimate: sp.fullScanEstimate(tbl)})
+		return scan, nil
 	}
 
 	// WHERE 句が設定されている場合
-	return sp.planForBinaryExpr(tbl, *sp.where.Condition)
+	return sp.planForCondition(tbl, sp.where.Condition)
 }
 
-// leafCondition は複合条件中の単一リーフ条件 (col op literal) を表す
+// leafCondition は複合条件中の単一リーフ条件 (col op literal, col IN (...), col BETWEEN ... AND ...) を表す
+//
+// レンジ分析に使えない場合 (NULL との比較、否定形、ワイルドカードで始まる LIKE など) は value が nil のままになる
 type leafCondition struct {
-	colName  string
-	operator string
-	literal  ast.Literal
+	colName      string
+	operator     string
+	literal      ast.Literal   // 比較する値 (BETWEEN では下限、LIKE ではパターン)
+	upperLiteral ast.Literal   // BETWEEN の上限
+	inLiterals   []ast.Literal // IN リストの値
+	value        []byte        // literal をカラムのデータ型に応じた格納形式に変換した値 (resolveLeafValues で設定)。NULL の場合は nil
+	upperValue   []byte        // BETWEEN の上限 / LIKE の接頭辞に一致する範囲の上限 (上限なしの場合は nil)
+	inValues     [][]byte      // IN リストの NULL 以外の値を昇順に並べ、重複を除いたもの
 }
 
-// planForBinaryExpr は二項演算式を解析して適切な検索用の Executor を構築する
+// planForCondition は WHERE 句の条件を解析して適切な検索用の Executor を構築する
 //
 // 式の構造に応じて以下のように分岐する:
-//   - 単一条件 (col op literal): chooseBestPlan でテーブルスキャン / PK / インデックスを比較
-//   - 純粋な AND 条件: extractANDLeaves でリーフを抽出 → chooseBestPlan
+//   - 単一条件 (リーフ条件) / 純粋な AND 条件: extractANDLeaves でリーフを抽出 → chooseBestPlan でテーブルスキャン / PK / インデックスを比較
 //   - OR を含む条件: planForORCondition で Union 最適化を試みる
-func (s *Search) planForBinaryExpr(tbl *access.Table, expr ast.BinaryExpr) (executor.Executor, error) {
-	switch lhs := expr.Left.(type) {
-
-	// 単一条件: LhsColumn op RhsLiteral (例: WHERE col = 5)
-	case *ast.LhsColumn:
-		colName := lhs.Column.ColName
-		switch rhs := expr.Right.(type) {
-		case *ast.RhsLiteral:
-			colMeta, ok := s.tblMeta.GetColByName(colName)
-			if !ok {
-				return nil, errors.New("column " + colName + " does not exist in table " + s.tblMeta.Name)
-			}
-			cond, err := operatorToCondition(expr.Operator, int(colMeta.Pos), rhs.Literal.ToString())
-			if err != nil {
-				return nil, err
-			}
-			leaves := []leafCondition{{colName: colName, operator: expr.Operator, literal: rhs.Literal}}
-			return s.chooseBestPlan(tbl, leaves, cond)
-		default:
-			// col1 = col2 のようなカラム同士の比較は未サポート
-			return nil, errors.New("when LHS is a column, RHS must be a literal")
-		}
+//   - リーフに分解できない条件 (col1 = col2, col + 1 > 5 など): テーブルスキャン + Filter
+func (s *Search) planForCondition(tbl *access.Table, expr ast.Expr) (executor.Executor, error) {
+	cond, err := s.buildCondition(expr)
+	if err != nil {
+		return nil, err
+	}
 
-	// 複合条件: LhsExpr AND/OR RhsExpr (例: WHERE col1 = 5 AND col2 > 10)
-	case *ast.LhsExpr:
-		cond, err := s.buildConditionFunc(expr)
-		if err != nil {
+	// AND ツリーからリーフ条件を抽出 (OR やリーフにできない条件を含む場合は nil が返る)
+	leaves := extractANDLeaves(expr)
+	if leaves != nil {
+		if err := s.resolveLeafValues(leaves); err != nil {
 			return nil, err
 		}
-
-		// AND ツリーからリーフ条件を抽出 (OR を含む場合は nil が返る)
-		leaves := extractANDLeaves(expr)
-		if leaves != nil {
-			return s.chooseBestPlan(tbl, leaves, cond)
-		}
-
-		// AND で分解できない(=OR を含む条件) → Union 最適化を試みる
-		return s.planForORCondition(tbl, expr, cond)
-
-	default:
-		return nil, errors.New("unsupported LHS type in binary expression")
+		return s.chooseBestPlan(tbl, leaves, cond)
 	}
+
+	// AND で分解できない → Union 最適化を試みる (最適化できない場合はテーブルスキャン + Filter)
+	return s.planForORCondition(tbl, expr, cond)
 }
 
 // -------------------------------------------------
@@ -125,17 +132,15 @@ func (s *Search) planForBinaryExpr(tbl *access.Table, expr ast.BinaryExpr) (exec
 //  1. テーブルスキャン + Filter (全条件をフィルタで適用)
 //  2. PK スキャン (+ Filter で残条件を適用)
 //  3. セカンダリインデックススキャン (+ Filter で残条件を適用)
-func (s *Search) chooseBestPlan(tbl *access.Table, leaves []leafCondition, cond func(executor.Record) bool) (executor.Executor, error) {
-	tableScanPlan := executor.NewFilter(
-		executor.NewTableScan(executor.TableScanParams{
-			ReadView:       s.readView,
-			VersionReader:  s.versionReader,
-			Table:          tbl,
-			SearchMode:     access.RecordSearchModeStart{},
-			WhileCondition: func(record executor.Record) bool { return true },
-		}),
-		cond,
-	)
+func (s *Search) chooseBestPlan(tbl *access.Table, leaves []leafCondition, cond executor.Expression) (executor.Executor, error) {
+	tableScan := executor.NewTableScan(executor.TableScanParams{
+		ReadView:       s.readView,
+		VersionReader:  s.versionReader,
+		Table:          tbl,
+		SearchMode:     access.RecordSearchModeStart{},
+		WhileCondition: func(record executor.Record) bool { return true },
+	})
+	tableScanPlan := executor.NewFilterWithExpression(tableScan, cond)
 
 	// 統計情報を取得
 	eng := handler.Get()
@@ -155,7 +160,8 @@ func (s *Search) chooseBestPlan(tbl *access.Table, leaves []leafCondition, cond
 	// 各リーフ条件について PK/インデックスのコストを算出し最安を記録
 	var bestLeaf *leafCondition
 	var bestCost float64
-	var bestPlan string // "PK", "Index", or ""
+	var bestPlan string  // "PK", "Index", or ""
+	var bestRows float64 // 最安プランで読み取る行数の見積もり
 	uniqueFound := false
 
 	for i := range leaves {
@@ -166,21 +172,40 @@ func (s *Search) chooseBestPlan(tbl *access.Table, leaves []leafCondition, cond
 			continue
 		}
 
+		// IN は各値の点検索を合計したコストで比較する
+		if leaf.operator == "IN" {
+			plan, cost, rows, ok := s.inListCost(leaf, stats, clusterPageReadCost)
+			if ok && (bestLeaf == nil || cost < bestCost) {
+				bestLeaf = leaf
+				bestCost = cost
+				bestPlan = plan
+				bestRows = rows
+			}
+			continue
+		}
+
+		// IS [NOT] NULL や NULL との比較、否定形などはレンジ分析の対象外
+		if leaf.value == nil {
+			continue
+		}
+
 		// ユニークスキャン判定: PK or UNIQUE INDEX の = 検索はコスト 1.0 で即確定
 		if leaf.operator == "=" {
 			if s.isPKLeadingColumn(leaf.colName) {
 				bestLeaf = leaf
 				bestCost = calcUniqueScanCost()
 				bestPlan = "PK"
+				bestRows = 1
 				uniqueFound = true
 				break
 			}
 			if idxMeta, hasIndex := s.tblMeta.GetIndexByColName(leaf.colName); hasIndex {
 				// ユニークインデックス
-				if idxMeta.Type == dictionary.IndexTypeUnique {
+				if idxMeta.IsUniqueColumn() {
 					bestLeaf = leaf
 					bestCost = calcUniqueScanCost()
 					bestPlan = "Index"
+					bestRows = 1
 					uniqueFound = true
 					break
 				}
@@ -193,6 +218,7 @@ func (s *Search) chooseBestPlan(tbl *access.Table, leaves []leafCondition, cond
 						bestLeaf = leaf
 						bestCost = cost
 						bestPlan = "Index"
+						bestRows = idxStats.RecPerKey
 					}
 				}
 			}
@@ -200,7 +226,7 @@ func (s *Search) chooseBestPlan(tbl *access.Table, leaves []leafCondition, cond
 
 		// PK レンジスキャン
 		if s.isPKLeadingColumn(leaf.colName) {
-			lowerKey, upperKey, leftIncl, rightIncl := buildRangeKeys(leaf.operator, leaf.literal)
+			lowerKey, upperKey, leftIncl, rightIncl := leaf.rangeKeys(buildRangeKeys)
 			foundRecords, err := primaryBTree.RecordsInRange(s.bufferPool, lowerKey, upperKey, leftIncl, rightIncl)
 			if err != nil {
 				return nil, err
@@ -213,6 +239,7 @@ func (s *Search) chooseBestPlan(tbl *access.Table, leaves []leafCondition, cond
 				bestLeaf = leaf
 				bestCost = cost
 				bestPlan = "PK"
+				bestRows = float64(foundRecords)
 			}
 		}
 
@@ -224,7 +251,7 @@ func (s *Search) chooseBestPlan(tbl *access.Table, leaves []leafCondition, cond
 				return nil, err
 			}
 			indexBTree := btree.NewBTree(index.MetaPageId)
-			lowerKey, upperKey, leftIncl, rightIncl := buildRangeKeys(leaf.operator, leaf.literal)
+			lowerKey, upperKey, leftIncl, rightIncl := leaf.rangeKeys(buildIndexRangeKeys)
 			foundRecords, err := indexBTree.RecordsInRange(s.bufferPool, lowerKey, upperKey, leftIncl, rightIncl)
 			if err != nil {
 				return nil, err
@@ -235,7 +262,7 @@ func (s *Search) chooseBestPlan(tbl *access.Table, leaves []leafCondition, cond
 			}
 
 			var readTime float64
-			if s.isIndexOnlyScan(idxMeta.ColName) {
+			if s.isIndexOnlyScan(idxMeta.ColNames...) {
 				idxStats := stats.IdxStats[idxMeta.Name]
 				keysPerBlock := float64(stats.RecordCount) / float64(max(idxStats.LeafPageCount, 1))
 				readTime = calcReadTimeForIndexOnly(float64(foundRecords), keysPerBlock, idxPageReadCost)
@@ -248,62 +275,163 @@ func (s *Search) chooseBestPlan(tbl *access.Table, leaves []leafCondition, cond
 				bestLeaf = leaf
 				bestCost = cost
 				bestPlan = "Index"
+				bestRows = float64(foundRecords)
 			}
 		}
 	}
 
+	// ORDER BY pk LIMIT n の場合、インデックススキャンは全件を読んでから Sort する必要があるが、
+	// テーブルスキャンは PK 順に条件に合う行を n 行見つけた時点で打ち切れる
+	// 条件に合う行 (bestRows 行) がテーブル全体に均等に分布していると仮定し、読み取る割合でフルスキャンのコストを按分する
+	if s.pkOrderLimit > 0 && bestPlan == "Index" && !uniqueFound {
+		fullScanCost *= min(1, float64(s.pkOrderLimit)/max(bestRows, 1))
+	}
+
 	// フルスキャン vs 最安プラン
+	// テーブルスキャン・PK スキャンは PK 順、インデックススキャンは (セカンダリキー, PK) 順にレコードを返す
+	s.sortOrder = s.pkOrder()
 	if bestLeaf == nil || (!uniqueFound && fullScanCost <= bestCost) {
+		tableScan.SetPlanNote(executor.PlanNote{
+			Access:   "full scan",
+			Estimate: estimated(float64(stats.RecordCount), fullScanCost),
+		})
 		return tableScanPlan, nil
 	}
 
-	switch bestPlan {
-	case "Index":
+	if bestPlan == "Index" {
 		idxMeta, _ := s.tblMeta.GetIndexByColName(bestLeaf.colName)
-		return s.buildIndexPlan(tbl, *bestLeaf, idxMeta, cond, len(leaves) > 1)
-	case "PK":
-		return s.buildPKScanPlan(tbl, *bestLeaf, cond, len(leaves) > 1), nil
-	default:
-		return tableScanPlan, nil
+		s.sortOrder = s.indexOrder(idxMeta)
 	}
+	return s.buildLeafPlan(tbl, *bestLeaf, bestPlan, cond, len(leaves) > 1, planEstimate{rows: bestRows, cost: bestCost})
+}
+
+// buildLeafPlan はリーフ条件と選択したプラン ("PK" or "Index") から Executor を構築する
+//
+// est はプランを選んだときの見積もり (EXPLAIN で表示する)
+func (s *Search) buildLeafPlan(tbl *access.Table, leaf leafCondition, plan string, cond executor.Expression, needsFilter bool, est planEstimate) (executor.Executor, error) {
+	if leaf.operator == "IN" {
+		return s.buildINPlan(tbl, leaf, plan, cond, needsFilter, est)
+	}
+	if plan == "PK" {
+		return s.buildPKScanPlan(tbl, leaf, cond, needsFilter, est), nil
+	}
+	idxMeta, _ := s.tblMeta.GetIndexByColName(leaf.colName)
+	return s.buildIndexPlan(tbl, leaf, idxMeta, cond, needsFilter, est)
+}
+
+// inListCost は IN リストの各値を PK / インデックスで点検索するコストを算出する
+//
+// 各値の点検索のコスト (ユニークスキャン or 非ユニークインデックスの = 検索) の合計をコストとする
+// PK / インデックスが利用できない場合や、NULL 以外の値がない場合は ok=false を返す
+func (s *Search) inListCost(leaf *leafCondition, stats *handler.TableStatistics, clusterPageReadCost float64) (plan string, cost float64, rows float64, ok bool) {
+	n := float64(len(leaf.inValues))
+	if n == 0 {
+		return "", 0, 0, false
+	}
+	if s.isPKLeadingColumn(leaf.colName) {
+		return "PK", n * calcUniqueScanCost(), n, true
+	}
+	idxMeta, hasIndex := s.tblMeta.GetIndexByColName(leaf.colName)
+	if !hasIndex {
+		return "", 0, 0, false
+	}
+	if idxMeta.IsUniqueColumn() {
+		return "Index", n * calcUniqueScanCost(), n, true
+	}
+	idxStats, ok := stats.IdxStats[idxMeta.Name]
+	if !ok {
+		return "", 0, 0, false
+	}
+	readCost := idxStats.RecPerKey * clusterPageReadCost
+	return "Index", n * (readCost + idxStats.RecPerKey*RowEvaluateCost), n * idxStats.RecPerKey, true
+}
+
+// buildINPlan は IN リストの各値を PK / インデックスで点検索する Executor を構築し、Union で結合する
+//
+// 値は昇順に並んでいるため、Union は PK / インデックスのキー順にレコードを返す
+//
+// 各値の点検索の見積もりは、IN リスト全体の見積もりを値の数で等分したものとする
+func (s *Search) buildINPlan(tbl *access.Table, leaf leafCondition, plan string, cond executor.Expression, needsFilter bool, est planEstimate) (executor.Executor, error) {
+	n := float64(len(leaf.inValues))
+	pointEst := planEstimate{rows: est.rows / n, cost: est.cost / n}
+	executors := make([]executor.Executor, 0, len(leaf.inValues))
+	for _, value := range leaf.inValues {
+		point := leafCondition{colName: leaf.colName, operator: "=", value: value}
+		exec, err := s.buildLeafPlan(tbl, point, plan, cond, needsFilter, pointEst)
+		if err != nil {
+			return nil, err
+		}
+		executors = append(executors, exec)
+	}
+	union := executor.NewUnion(executors)
+	union.SetPlanNote(executor.PlanNote{Access: "union (IN list)", Estimate: estimated(est.rows, est.cost)})
+	return union, nil
 }
 
 // buildIndexPlan はインデックスを使った Executor を構築する
 //
-// needsFilter が true の場合、IndexScan の上に Filter を重ねる (複合条件時)
-func (s *Search) buildIndexPlan(tbl *access.Table, leaf leafCondition, idxMeta *handler.IndexMetadata, cond func(executor.Record) bool, needsFilter bool) (executor.Executor, error) {
+// needsFilter が true の場合、IndexScan の上に Filter を重ねる (複合条件時や > 演算子時)
+func (s *Search) buildIndexPlan(tbl *access.Table, leaf leafCondition, idxMeta *handler.IndexMetadata, cond executor.Expression, needsFilter bool, est planEstimate) (executor.Executor, error) {
 	index, err := tbl.GetSecondaryIndexByName(idxMeta.Name)
 	if err != nil {
 		return nil, err
 	}
-	indexCond, err := operatorToCondition(leaf.operator, 0, leaf.literal.ToString())
-	if err != nil {
-		return nil, err
+
+	// 演算子ごとにインデックスのアクセスパターンを決定する (buildPKScanPlan と同様)
+	// - =, >=, >: キー位置にシークして末尾方向へ走査
+	// - <, <=: NULL 以外の最小のキー (空のバイト列) にシークして条件外で停止 (whileCondition)。NULL のキーは先頭に並ぶため読み飛ばす
+	// - BETWEEN: 下限にシークして上限を超えたら停止
+	// - LIKE: 接頭辞にシークして接頭辞の範囲を超えたら停止 (接頭辞以降のパターンは Filter で判定する)
+	var searchMode access.RecordSearchMode = access.RecordSearchModeKey{Key: [][]byte{leaf.value}}
+	whileOperator, whileValue := leaf.operator, leaf.value
+	switch leaf.operator {
+	case "<", "<=":
+		searchMode = access.RecordSearchModeKey{Key: [][]byte{{}}}
+	case ">":
+		whileOperator = ">="
+		needsFilter = true // 開始位置の等値レコードを除外するため Filter が必要
+	case "BETWEEN":
+		whileOperator, whileValue = "<=", leaf.upperValue
+	case "LIKE":
+		whileOperator, whileValue = "<", leaf.upperValue
+		needsFilter = true
+	}
+	indexCond := func(record executor.Record) bool { return true }
+	if whileValue != nil {
+		var err error
+		indexCond, err = operatorToCondition(whileOperator, 0, string(whileValue))
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	var scan executor.Executor
-	if s.isIndexOnlyScan(idxMeta.ColName) {
-		colMeta, _ := s.tblMeta.GetColByName(idxMeta.ColName)
+	accessMethod := "index range"
+	if leaf.operator == "=" {
+		accessMethod = "index lookup"
+	}
+	var scan *executor.IndexScan
+	if s.isIndexOnlyScan(idxMeta.ColNames...) {
 		scan = executor.NewIndexScanWithParams(executor.IndexScanParams{
 			Table:          tbl,
 			Index:          index,
-			SearchMode:     access.RecordSearchModeKey{Key: [][]byte{leaf.literal.ToBytes()}},
+			SearchMode:     searchMode,
 			WhileCondition: indexCond,
 			IndexOnly:      true,
 			NCols:          int(s.tblMeta.NCols),
-			SecColPos:      int(colMeta.Pos),
 		})
+		accessMethod += " (index-only)"
 	} else {
 		scan = executor.NewIndexScan(
 			tbl,
 			index,
-			access.RecordSearchModeKey{Key: [][]byte{leaf.literal.ToBytes()}},
+			searchMode,
 			indexCond,
 		)
 	}
+	scan.SetPlanNote(executor.PlanNote{Access: accessMethod, Estimate: estimated(est.rows, est.cost)})
 
 	if needsFilter {
-		return executor.NewFilter(scan, cond), nil
+		return executor.NewFilterWithExpression(scan, cond), nil
 	}
 	return scan, nil
 }
@@ -311,10 +439,10 @@ func (s *Search) buildIndexPlan(tbl *access.Table, leaf leafCondition, idxMeta *
 // buildPKScanPlan は PK カラムの条件を使った TableScan を構築する
 //
 // needsFilter が true の場合、TableScan の上に Filter を重ねる (複合条件時や > 演算子時)
-func (s *Search) buildPKScanPlan(tbl *access.Table, leaf leafCondition, cond func(executor.Record) bool, needsFilter bool) executor.Executor {
+func (s *Search) buildPKScanPlan(tbl *access.Table, leaf leafCondition, cond executor.Expression, needsFilter bool, est planEstimate) executor.Executor {
 	colMeta, _ := s.tblMeta.GetColByName(leaf.colName)
 	pos := int(colMeta.Pos)
-	value := leaf.literal.ToString()
+	value := string(leaf.value)
 
 	var searchMode access.RecordSearchMode
 	var whileCond func(executor.Record) bool
@@ -322,16 +450,17 @@ func (s *Search) buildPKScanPlan(tbl *access.Table, leaf leafCondition, cond fun
 
 	// 演算子ごとに B+Tree のアクセスパターンを決定する
 	// - =, >=, >: キー位置にシークして末尾方向へ走査
-	// - <, <=: 先頭から走査して条件外で停止 (whileCondition)
+	// - <, <=: NULL 以外の最小のキー (空のバイト列) にシークして条件外で停止 (whileCondition)。NULL のキーは先頭に並ぶため読み飛ばす
+	// - BETWEEN, LIKE: 下限 (LIKE は接頭辞) にシークして上限を超えたら停止
 	switch leaf.operator {
 	case "=":
-		searchMode = access.RecordSearchModeKey{Key: [][]byte{leaf.literal.ToBytes()}}
+		searchMode = access.RecordSearchModeKey{Key: [][]byte{leaf.value}}
 		whileCond = func(r executor.Record) bool { return string(r[pos]) == value }
 	case ">=":
-		searchMode = access.RecordSearchModeKey{Key: [][]byte{leaf.literal.ToBytes()}}
+		searchMode = access.RecordSearchModeKey{Key: [][]byte{leaf.value}}
 		whileCond = func(r executor.Record) bool { return true }
 	case ">":
-		searchMode = access.RecordSearchModeKey{Key: [][]byte{leaf.literal.ToBytes()}}
+		searchMode = access.RecordSearchModeKey{Key: [][]byte{leaf.value}}
 		whileCond = func(r executor.Record) bool { return true }
 		filterRequired = true // 開始位置の等値レコードを除外するため Filter が必要
 	case "<":
@@ -340,6 +469,18 @@ func (s *Search) buildPKScanPlan(tbl *access.Table, leaf leafCondition, cond fun
 	case "<=":
 		searchMode = access.RecordSearchModeStart{}
 		whileCond = func(r executor.Record) bool { return string(r[pos]) <= value }
+	case "BETWEEN":
+		searchMode = access.RecordSearchModeKey{Key: [][]byte{leaf.value}}
+		upper := string(leaf.upperValue)
+		whileCond = func(r executor.Record) bool { return string(r[pos]) <= upper }
+	case "LIKE":
+		searchMode = access.RecordSearchModeKey{Key: [][]byte{leaf.value}}
+		whileCond = func(r executor.Record) bool { return true }
+		if leaf.upperValue != nil {
+			upper := string(leaf.upperValue)
+			whileCond = func(r executor.Record) bool { return string(r[pos]) < upper }
+		}
+		filterRequired = true // 接頭辞以降のパターンは Filter で判定する
 	default:
 		// != などの場合はフルテーブルスキャン + Filter
 		searchMode = access.RecordSearchModeStart{}
@@ -354,8 +495,13 @@ func (s *Search) buildPKScanPlan(tbl *access.Table, leaf leafCondition, cond fun
 		SearchMode:     searchMode,
 		WhileCondition: whileCond,
 	})
+	accessMethod := "PK range"
+	if leaf.operator == "=" {
+		accessMethod = "PK lookup"
+	}
+	scan.SetPlanNote(executor.PlanNote{Access: accessMethod, Estimate: estimated(est.rows, est.cost)})
 	if filterRequired {
-		return executor.NewFilter(scan, cond)
+		return executor.NewFilterWithExpression(scan, cond)
 	}
 	return scan
 }
@@ -369,17 +515,19 @@ func (s *Search) buildPKScanPlan(tbl *access.Table, leaf leafCondition, cond fun
 // 各 OR ブランチが PK またはセカンダリインデックスを利用できる場合、各ブランチを個別にスキャンし Union で結合する
 //
 // 最適化できない場合はテーブルスキャン + Filter にフォールバックする
-func (s *Search) planForORCondition(tbl *access.Table, expr ast.BinaryExpr, cond func(executor.Record) bool) (executor.Executor, error) {
-	tableScanPlan := executor.NewFilter(
-		executor.NewTableScan(executor.TableScanParams{
-			ReadView:       s.readView,
-			VersionReader:  s.versionReader,
-			Table:          tbl,
-			SearchMode:     access.RecordSearchModeStart{},
-			WhileCondition: func(record executor.Record) bool { return true },
-		}),
-		cond,
-	)
+func (s *Search) planForORCondition(tbl *access.Table, expr ast.Expr, cond executor.Expression) (executor.Executor, error) {
+	tableScan := executor.NewTableScan(executor.TableScanParams{
+		ReadView:       s.readView,
+		VersionReader:  s.versionReader,
+		Table:          tbl,
+		SearchMode:     access.RecordSearchModeStart{},
+		WhileCondition: func(record executor.Record) bool { return true },
+	})
+	tableScan.SetPlanNote(executor.PlanNote{Access: "full scan", Estimate: s.fullScanEstimate(tbl)})
+	tableScanPlan := executor.NewFilterWithExpression(tableScan, cond)
+
+	// Union は各ブランチの結果を順に返すため、並び順が保証されるのはテーブルスキャンの場合のみ
+	s.sortOrder = s.pkOrder()
 
 	branches := extractORBranches(expr)
 	if branches == nil {
@@ -396,9 +544,9 @@ func (s *Search) planForORCondition(tbl *access.Table, expr ast.BinaryExpr, cond
 	// 各ブランチを PK/インデックスで個別にプラン構築する
 	// 1 つでも PK/インデックスが使えないブランチがあれば Union は不可
 	var executors []executor.Executor
-	var totalCost float64
+	var totalCost, totalRows float64
 	for _, branch := range branches {
-		exec, cost, ok, err := s.planORBranch(tbl, branch, stats)
+		exec, est, ok, err := s.planORBranch(tbl, branch, stats)
 		if err != nil {
 			return nil, err
 		}
@@ -406,7 +554,8 @@ func (s *Search) planForORCondition(tbl *access.Table, expr ast.BinaryExpr, cond
 			return tableScanPlan, nil
 		}
 		executors = append(executors, exec)
-		totalCost += cost
+		totalCost += est.cost
+		totalRows += est.rows
 	}
 
 	// Union の合計コスト < フルスキャンのコストなら Union を採用
@@ -417,7 +566,10 @@ func (s *Search) planForORCondition(tbl *access.Table, expr ast.BinaryExpr, cond
 	}
 	fullScanCost := calcFullScanCost(stats, clusterPageReadCost)
 	if totalCost < fullScanCost {
-		return executor.NewUnion(executors), nil
+		s.sortOrder = nil
+		union := executor.NewUnion(executors)
+		union.SetPlanNote(executor.PlanNote{Access: "union (OR)", Estimate: estimated(totalRows, totalCost)})
+		return union, nil
 	}
 
 	return tableScanPlan, nil
@@ -425,10 +577,10 @@ func (s *Search) planForORCondition(tbl *access.Table, expr ast.BinaryExpr, cond
 
 // orBranch は OR 条件の各ブランチを表す
 //
-// 単一条件 (col op literal) または複合 AND 条件 (複数の leafCondition) を保持する
+// 単一条件 (リーフ条件) または複合 AND 条件 (複数の leafCondition) を保持する
 type orBranch struct {
 	leaves []leafCondition // AND で結合されたリーフ条件群
-	expr   ast.BinaryExpr  // ブランチの元の AST (条件関数の構築に使用)
+	expr   ast.Expr        // ブランチの元の AST (条件関数の構築に使用)
 }
 
 // planORBranch は OR の各ブランチに対して PK またはインデックスを使ったプランを構築する
@@ -437,11 +589,14 @@ type orBranch struct {
 // 残りの条件は Filter で適用する
 //
 // PK/インデックスが利用できない場合は ok=false を返す
-func (s *Search) planORBranch(tbl *access.Table, branch orBranch, stats *handler.TableStatistics) (executor.Executor, float64, bool, error) {
+func (s *Search) planORBranch(tbl *access.Table, branch orBranch, stats *handler.TableStatistics) (executor.Executor, planEstimate, bool, error) {
 	// ブランチ全体の条件関数を構築 (複合 AND 条件時に Filter で使用)
-	branchCond, err := s.buildConditionFunc(branch.expr)
+	branchCond, err := s.buildCondition(branch.expr)
 	if err != nil {
-		return nil, 0, false, err
+		return nil, planEstimate{}, false, err
+	}
+	if err := s.resolveLeafValues(branch.leaves); err != nil {
+		return nil, planEstimate{}, false, err
 	}
 
 	// 複合条件の場合、最適な 1 条件で PK/インデックスを使い、残条件は Filter で適用
@@ -451,13 +606,14 @@ func (s *Search) planORBranch(tbl *access.Table, branch orBranch, stats *handler
 	primaryBTree := btree.NewBTree(tbl.MetaPageId)
 	clusterPRC, err := calcPageReadCost(s.bufferPool, primaryBTree)
 	if err != nil {
-		return nil, 0, false, err
+		return nil, planEstimate{}, false, err
 	}
 
 	// 各リーフ条件について PK/インデックスのコストを算出し、最安を選択
 	var bestLeaf *leafCondition
 	var bestCost float64
-	var bestPlan string // "PK" or "Index"
+	var bestPlan string  // "PK" or "Index"
+	var bestRows float64 // 最安プランで読み取る行数の見積もり
 
 	for i := range branch.leaves {
 		leaf := &branch.leaves[i]
@@ -467,19 +623,38 @@ func (s *Search) planORBranch(tbl *access.Table, branch orBranch, stats *handler
 			continue
 		}
 
+		// IN は各値の点検索を合計したコストで比較する
+		if leaf.operator == "IN" {
+			plan, cost, rows, ok := s.inListCost(leaf, stats, clusterPRC)
+			if ok && (bestLeaf == nil || cost < bestCost) {
+				bestLeaf = leaf
+				bestCost = cost
+				bestPlan = plan
+				bestRows = rows
+			}
+			continue
+		}
+
+		// IS [NOT] NULL や NULL との比較、否定形などはレンジ分析の対象外
+		if leaf.value == nil {
+			continue
+		}
+
 		// ユニークスキャン (= 検索) → コスト 1.0
 		if leaf.operator == "=" {
 			if s.isPKLeadingColumn(leaf.colName) {
 				bestLeaf = leaf
 				bestCost = calcUniqueScanCost()
 				bestPlan = "PK"
+				bestRows = 1
 				break
 			}
 			if idxMeta, hasIdx := s.tblMeta.GetIndexByColName(leaf.colName); hasIdx {
-				if idxMeta.Type == dictionary.IndexTypeUnique {
+				if idxMeta.IsUniqueColumn() {
 					bestLeaf = leaf
 					bestCost = calcUniqueScanCost()
 					bestPlan = "Index"
+					bestRows = 1
 					break
 				}
 				// 非ユニークインデックス: read_cost = RecPerKey × pageReadCost (MySQL の find_cost_for_ref に対応)
@@ -491,6 +666,7 @@ func (s *Search) planORBranch(tbl *access.Table, branch orBranch, stats *handler
 						bestLeaf = leaf
 						bestCost = cost
 						bestPlan = "Index"
+						bestRows = idxStats.RecPerKey
 					}
 				}
 			}
@@ -498,10 +674,10 @@ func (s *Search) planORBranch(tbl *access.Table, branch orBranch, stats *handler
 
 		// PK レンジスキャン
 		if s.isPKLeadingColumn(leaf.colName) {
-			lowerKey, upperKey, leftIncl, rightIncl := buildRangeKeys(leaf.operator, leaf.literal)
+			lowerKey, upperKey, leftIncl, rightIncl := leaf.rangeKeys(buildRangeKeys)
 			foundRecords, err := primaryBTree.RecordsInRange(s.bufferPool, lowerKey, upperKey, leftIncl, rightIncl)
 			if err != nil {
-				return nil, 0, false, err
+				return nil, planEstimate{}, false, err
 			}
 			readTime := calcReadTimeForClusteredIndex(float64(foundRecords), float64(stats.RecordCount), float64(stats.LeafPageCount), clusterPRC)
 			cost := calcRangeScanCost(readTime, float64(foundRecords))
@@ -509,6 +685,7 @@ func (s *Search) planORBranch(tbl *access.Table, branch orBranch, stats *handler
 				bestLeaf = leaf
 				bestCost = cost
 				bestPlan = "PK"
+				bestRows = float64(foundRecords)
 			}
 		}
 
@@ -517,13 +694,13 @@ func (s *Search) planORBranch(tbl *access.Table, branch orBranch, stats *handler
 		if hasIndex {
 			index, err := tbl.GetSecondaryIndexByName(idxMeta.Name)
 			if err != nil {
-				return nil, 0, false, err
+				return nil, planEstimate{}, false, err
 			}
 			indexBTree := btree.NewBTree(index.MetaPageId)
-			lowerKey, upperKey, leftIncl, rightIncl := buildRangeKeys(leaf.operator, leaf.literal)
+			lowerKey, upperKey, leftIncl, rightIncl := leaf.rangeKeys(buildIndexRangeKeys)
 			foundRecords, err := indexBTree.RecordsInRange(s.bufferPool, lowerKey, upperKey, leftIncl, rightIncl)
 			if err != nil {
-				return nil, 0, false, err
+				return nil, planEstimate{}, false, err
 			}
 			// 非 index-only scan ではクラスタ化インデックスの pageReadCost を使う
 			readTime := calcReadTimeForSecondaryIndex(float64(foundRecords), clusterPRC)
@@ -532,62 +709,324 @@ func (s *Search) planORBranch(tbl *access.Table, branch orBranch, stats *handler
 				bestLeaf = leaf
 				bestCost = cost
 				bestPlan = "Index"
+				bestRows = float64(foundRecords)
 			}
 		}
 	}
 
 	if bestLeaf == nil {
-		return nil, 0, false, nil
+		return nil, planEstimate{}, false, nil
 	}
 
-	switch bestPlan {
-	case "PK":
-		exec := s.buildPKScanPlan(tbl, *bestLeaf, branchCond, needsFilter)
-		return exec, bestCost, true, nil
-	case "Index":
-		idxMeta, _ := s.tblMeta.GetIndexByColName(bestLeaf.colName)
-		exec, err := s.buildIndexPlan(tbl, *bestLeaf, idxMeta, branchCond, needsFilter)
-		if err != nil {
-			return nil, 0, false, err
-		}
-		return exec, bestCost, true, nil
+	est := planEstimate{rows: bestRows, cost: bestCost}
+	exec, err := s.buildLeafPlan(tbl, *bestLeaf, bestPlan, branchCond, needsFilter, est)
+	if err != nil {
+		return nil, planEstimate{}, false, err
 	}
-
-	return nil, 0, false, nil
+	return exec, est, true, nil
 }
 
 // -------------------------------------------------
 // その他
 // -------------------------------------------------
 
-// buildRangeKeys は演算子とリテラルからレンジ分析用のキーを組み立てる
+// fullScanEstimate はフルスキャンの見積もり行数とコストを返す関数を返す
+//
+// 条件がない場合はコスト比較をしないため統計情報を取得していない。統計情報の取得はテーブル全体を読むため、EXPLAIN の表示時まで遅延させる
+func (s *Search) fullScanEstimate(tbl *access.Table) func() (float64, float64, error) {
+	return func() (float64, float64, error) {
+		stats, err := handler.Get().AnalyzeTable(s.tblMeta)
+		if err != nil {
+			return 0, 0, err
+		}
+		pageReadCost, err := calcPageReadCost(s.bufferPool, btree.NewBTree(tbl.MetaPageId))
+		if err != nil {
+			return 0, 0, err
+		}
+		return float64(stats.RecordCount), calcFullScanCost(stats, pageReadCost), nil
+	}
+}
+
+// resolveLeafValues は各リーフ条件のリテラルを、カラムのデータ型に応じた格納形式に変換して value に設定する
+//
+//   - IN: NULL 以外の値を昇順に並べ、重複を除いて inValues に設定する
+//   - BETWEEN: 下限・上限がともに NULL でない場合のみ value / upperValue に設定する
+//   - LIKE: 文字列カラムの場合のみ、パターンの接頭辞に一致する範囲を value / upperValue に設定する
+//   - 否定形 (NOT IN, NOT BETWEEN, NOT LIKE): レンジ分析の対象外のため設定しない
+//
+// カラムのデータ型で正確に表せない値 (INT のカラムと 9.5 など) は、同じ行に一致する条件に置き換える (parseRangeLiteral を参照)
+//   - >, >= / BETWEEN の下限: 値より大きい最小の値以上 (> 9.5 は >= 10)
+//   - <, <= / BETWEEN の上限: 値より小さい最大の値以下 (< 9.5 は <= 9)
+//   - =, !=: レンジ分析の対象外のため設定しない
+//   - IN: どの行にも一致しないため、その値を除く
+func (s *Search) resolveLeafValues(leaves []leafCondition) error {
+	for i := range leaves {
+		leaf := &leaves[i]
+		colMeta, ok := s.tblMeta.GetColByName(leaf.colName)
+		if !ok {
+			return errors.New("column " + leaf.colName + " does not exist in table " + s.tblMeta.Name)
+		}
+		switch leaf.operator {
+		case "IN":
+			values := make([][]byte, 0, len(leaf.inLiterals))
+			for _, lit := range leaf.inLiterals {
+				floor, ceil, err := parseRangeLiteral(colMeta, lit)
+				if err != nil {
+					return err
+				}
+				if floor != nil && bytes.Equal(floor, ceil) {
+					values = append(values, floor)
+				}
+			}
+			slices.SortFunc(values, bytes.Compare)
+			leaf.inValues = slices.CompactFunc(values, bytes.Equal)
+		case "BETWEEN":
+			_, lower, err := parseRangeLiteral(colMeta, leaf.literal)
+			if err != nil {
+				return err
+			}
+			upper, _, err := parseRangeLiteral(colMeta, leaf.upperLiteral)
+			if err != nil {
+				return err
+			}
+			if lower != nil && upper != nil {
+				leaf.value, leaf.upperValue = lower, upper
+			}
+		case "LIKE":
+			if colMeta.Type == handler.ColumnTypeString {
+				leaf.value, leaf.upperValue = likePrefixRange(leaf.literal.ToString())
+			}
+		case "NOT IN", "NOT BETWEEN", "NOT LIKE":
+		default:
+			floor, ceil, err := parseRangeLiteral(colMeta, leaf.literal)
+			if err != nil {
+				return err
+			}
+			switch {
+			case bytes.Equal(floor, ceil):
+				leaf.value = floor
+			case leaf.operator == ">" || leaf.operator == ">=":
+				leaf.operator, leaf.value = ">=", ceil
+			case leaf.operator == "<" || leaf.operator == "<=":
+				leaf.operator, leaf.value = "<=", floor
+			}
+		}
+	}
+	return nil
+}
+
+// parseRangeLiteral はリテラルをカラムの格納形式に変換し、リテラルの値以下で最大のカラムの値 (floor) と、以上で最小のカラムの値 (ceil) を返す
+//
+// 比較はリテラル自身のデータ型で行う (literalType を参照) ため、INT のカラムと 9.5、DATE のカラムと '2023-12-31 10:00:00' のように
+// カラムのデータ型で正確に表せない値は floor != ceil になる。正確に表せる値と NULL は floor == ceil になる
+func parseRangeLiteral(colMeta *handler.ColumnMetadata, lit ast.Literal) (floor, ceil []byte, err error) {
+	if err := checkNumberLiteral(lit, colMeta); err != nil {
+		return nil, nil, err
+	}
+	litMeta := literalType(lit, colMeta)
+	if litMeta == colMeta {
+		value, err := parseLiteral(colMeta, lit)
+		return value, value, err
+	}
+	value, err := parseLiteral(litMeta, lit)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// リテラルの値を、カラムの値の 1 単位 (DECIMAL の最小桁、DATE の 1 日) で切り捨てる
+	n := big.NewInt(encode.DecodeInt64(value))
+	unit, step := big.NewInt(1), big.NewInt(1)
+	switch {
+	case isTemporalType(colMeta.Type):
+		unit, step = big.NewInt(86400), big.NewInt(86400)
+	case litMeta.Scale > colMeta.Scale:
+		unit = new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(litMeta.Scale-colMeta.Scale)), nil)
+	case litMeta.Scale < colMeta.Scale:
+		n.Mul(n, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(colMeta.Scale-litMeta.Scale)), nil))
+	}
+	lower, rem := new(big.Int).DivMod(n, unit, new(big.Int))
+	lower.Mul(lower, step)
+	upper := new(big.Int).Set(lower)
+	if rem.Sign() != 0 {
+		upper.Add(upper, step)
+	}
+
+	// DECIMAL の値の絶対値は 10^MaxDecimalPrecision 未満なので、範囲外の値はそのすぐ外側の値に丸める
+	limit := new(big.Int).Exp(big.NewInt(10), big.NewInt(dictionary.MaxDecimalPrecision), nil)
+	if lower.CmpAbs(limit) > 0 || upper.CmpAbs(limit) > 0 {
+		if n.Sign() > 0 {
+			lower, upper = limit, new(big.Int).Add(limit, big.NewInt(1))
+		} else {
+			lower, upper = new(big.Int).Neg(new(big.Int).Add(limit, big.NewInt(1))), new(big.Int).Neg(limit)
+		}
+	}
+	return encode.EncodeInt64(lower.Int64()), encode.EncodeInt64(upper.Int64()), nil
+}
+
+// rangeKeys はリーフ条件からレンジ分析用のキーを組み立てる (buildRangeKeys, buildIndexRangeKeys を参照)
+//
+//   - BETWEEN: 下限以上かつ上限以下
+//   - LIKE: 接頭辞以上かつ接頭辞に一致する範囲の上限未満 (上限なしの場合は末尾まで)
+func (leaf *leafCondition) rangeKeys(build func(operator string, value []byte) (lowerKey, upperKey []byte, leftIncl, rightIncl bool)) (lowerKey, upperKey []byte, leftIncl, rightIncl bool) {
+	switch leaf.operator {
+	case "BETWEEN":
+		lowerKey, _, leftIncl, _ = build(">=", leaf.value)
+		_, upperKey, _, rightIncl = build("<=", leaf.upperValue)
+		return lowerKey, upperKey, leftIncl, rightIncl
+	case "LIKE":
+		lowerKey, _, leftIncl, _ = build(">=", leaf.value)
+		if leaf.upperValue == nil {
+			return lowerKey, nil, leftIncl, true
+		}
+		_, upperKey, _, rightIncl = build("<", leaf.upperValue)
+		return lowerKey, upperKey, leftIncl, rightIncl
+	default:
+		return build(leaf.operator, leaf.value)
+	}
+}
+
+// buildRangeKeys は演算子と値 (格納形式) からレンジ分析用のキーを組み立てる
 //
 // 非有界側は nil を返す。RecordsInRange は nil を「先頭から」「末尾まで」として扱う
-func buildRangeKeys(operator string, literal ast.Literal) (lowerKey, upperKey []byte, leftIncl, rightIncl bool) {
+//
+// セカンダリインデックスのキーは (セカンダリキー, PK) を連結したものなので、value と等しいキーは value のエンコード結果を接頭辞に持つ
+// そのため value と等しいキーの上限には、エンコード結果の後ろに 0xFF を 1 ブロック分続けたキーを使う
+// (各ブロックの末尾は BLOCK_SIZE 以下なので、後続カラムがどんな値でもこのキーより小さくなる)
+func buildRangeKeys(operator string, value []byte) (lowerKey, upperKey []byte, leftIncl, rightIncl bool) {
 	var encoded []byte
-	encode.Encode([][]byte{literal.ToBytes()}, &encoded)
+	encode.Encode([][]byte{value}, &encoded)
+	return rangeKeysOf(operator, encoded)
+}
+
+// buildIndexRangeKeys はセカンダリインデックスのレンジ分析用のキーを組み立てる (buildRangeKeys を参照)
+//
+// セカンダリキーは NULL を区別してエンコードされ、NULL のキーは先頭に並ぶ
+// 比較演算子は NULL に一致しないため、下限のない範囲は NULL 以外の最小のキー (空のバイト列) から数える
+func buildIndexRangeKeys(operator string, value []byte) (lowerKey, upperKey []byte, leftIncl, rightIncl bool) {
+	var encoded []byte
+	encode.EncodeNullable([][]byte{value}, &encoded)
+	lowerKey, upperKey, leftIncl, rightIncl = rangeKeysOf(operator, encoded)
+	if lowerKey == nil && upperKey != nil {
+		encode.EncodeNullable([][]byte{{}}, &lowerKey)
+		leftIncl = true
+	}
+	return lowerKey, upperKey, leftIncl, rightIncl
+}
+
+// rangeKeysOf は演算子とエンコード済みの値からレンジ分析用のキーを組み立てる
+func rangeKeysOf(operator string, encoded []byte) (lowerKey, upperKey []byte, leftIncl, rightIncl bool) {
+	upperOfValue := append(slices.Clone(encoded), bytes.Repeat([]byte{0xFF}, encode.BLOCK_SIZE)...)
 
 	switch operator {
 	case "=":
-		return encoded, encoded, true, true
+		return encoded, upperOfValue, true, true
 	case ">":
-		return encoded, nil, false, true
+		return upperOfValue, nil, false, true
 	case ">=":
 		return encoded, nil, true, true
 	case "<":
 		return nil, encoded, true, false
 	case "<=":
-		return nil, encoded, true, true
+		return nil, upperOfValue, true, true
 	default:
 		return nil, nil, true, true
 	}
 }
 
-// isIndexOnlyScan は SELECT カラムがインデックス (UK + PK) でカバーされるか判定する
+// likePrefixRange は LIKE のパターンの接頭辞 (最初のワイルドカードより前の部分) に一致する文字列の範囲 [lower, upper) を返す
+//
+// upper は接頭辞で始まる文字列より大きい最小の値 (末尾の 0xFF を取り除き、最後のバイトに 1 を足したもの)。存在しない場合は nil (上限なし)
+// パターンがワイルドカードで始まる場合はレンジにできないため、lower も nil を返す
+func likePrefixRange(pattern string) (lower, upper []byte) {
+	for i := 0; i < len(pattern); i++ {
+		ch := pattern[i]
+		if ch == '%' || ch == '_' {
+			break
+		}
+		if ch == '\\' && i+1 < len(pattern) {
+			i++
+			ch = pattern[i]
+		}
+		lower = append(lower, ch)
+	}
+	if len(lower) == 0 {
+		return nil, nil
+	}
+
+	upper = slices.Clone(lower)
+	for len(upper) > 0 && upper[len(upper)-1] == 0xFF {
+		upper = upper[:len(upper)-1]
+	}
+	if len(upper) == 0 {
+		return lower, nil
+	}
+	upper[len(upper)-1]++
+	return lower, upper
+}
+
+// findFullIndexOnlyScanIndex は WHERE 句がない場合に、全件を index-only scan で読み取れるインデックスを探す
+//
+// NULL を含む行もセカンダリインデックスに登録されるため、NULL を許容するカラムのインデックスも対象とする
+// SELECT カラムが PK カラムのみの場合はテーブルスキャンでも同じ順序で読み取れるため対象外とする (COUNT(*) のように SELECT カラムが空の場合は対象)
+func (s *Search) findFullIndexOnlyScanIndex() (*handler.IndexMetadata, bool) {
+	if s.selectColumns == nil {
+		return nil, false
+	}
+	if len(s.selectColumns) > 0 && !slices.ContainsFunc(s.selectColumns, func(col ast.ColumnId) bool { return !s.isPKColumn(col.ColName) }) {
+		return nil, false
+	}
+	var found *handler.IndexMetadata
+	for _, idxMeta := range s.tblMeta.Indexes {
+		if !s.isIndexOnlyScan(idxMeta.ColNames...) {
+			continue
+		}
+		// 並べたいカラムの順に走査できるインデックスを優先する
+		if s.preferOrder == nil || isDistinctOrder(s.indexOrder(idxMeta), s.columnPositions(s.preferOrder)) {
+			return idxMeta, true
+		}
+		if found == nil {
+			found = idxMeta
+		}
+	}
+	return found, found != nil
+}
+
+// columnPositions はカラムのテーブル内の位置を返す (テーブルにないカラムは除く)
+func (s *Search) columnPositions(columns []ast.ColumnId) []int {
+	positions := make([]int, 0, len(columns))
+	for _, col := range columns {
+		if colMeta, ok := s.tblMeta.GetColByName(col.ColName); ok {
+			positions = append(positions, int(colMeta.Pos))
+		}
+	}
+	return positions
+}
+
+// buildFullIndexOnlyScan はインデックスを先頭から末尾まで index-only scan で走査する Executor を構築する
+func (s *Search) buildFullIndexOnlyScan(tbl *access.Table, idxMeta *handler.IndexMetadata) (executor.Executor, error) {
+	index, err := tbl.GetSecondaryIndexByName(idxMeta.Name)
+	if err != nil {
+		return nil, err
+	}
+	s.sortOrder = s.indexOrder(idxMeta)
+	scan := executor.NewIndexScanWithParams(executor.IndexScanParams{
+		Table:          tbl,
+		Index:          index,
+		SearchMode:     access.RecordSearchModeStart{},
+		WhileCondition: func(record executor.Record) bool { return true },
+		IndexOnly:      true,
+		NCols:          int(s.tblMeta.NCols),
+	})
+	scan.SetPlanNote(executor.PlanNote{Access: "full index-only scan", Estimate: s.fullScanEstimate(tbl)})
+	return scan, nil
+}
+
+// isIndexOnlyScan は SELECT カラムがインデックス (インデックスの全カラム + PK) でカバーされるか判定する
 //
 // SELECT * の場合は false (全カラムが必要なので index-only にならない)
-func (s *Search) isIndexOnlyScan(indexColName string) bool {
-	if len(s.selectColumns) == 0 {
+// SELECT カラムが空の場合 (COUNT(*) のみの集約など) はどのインデックスでもカバーされる
+func (s *Search) isIndexOnlyScan(indexColNames ...string) bool {
+	if s.selectColumns == nil {
 		return false // SELECT * → index-only 不可
 	}
 
@@ -598,7 +1037,9 @@ func (s *Search) isIndexOnlyScan(indexColName string) bool {
 			covered[col.Name] = true
 		}
 	}
-	covered[indexColName] = true
+	for _, colName := range indexColNames {
+		covered[colName] = true
+	}
 
 	// 全 SELECT カラムがカバーされているか
 	for _, col := range s.selectColumns {
@@ -610,6 +1051,31 @@ func (s *Search) isIndexOnlyScan(indexColName string) bool {
 	return true
 }
 
+// indexOrder はセカンダリインデックスのレコードの並び順 (インデックスのカラム, PK) をカラムの位置で返す
+func (s *Search) indexOrder(idxMeta *handler.IndexMetadata) []int {
+	order := make([]int, 0, len(idxMeta.ColNames)+int(s.tblMeta.PKCount))
+	for _, colName := range idxMeta.ColNames {
+		colMeta, _ := s.tblMeta.GetColByName(colName)
+		order = append(order, int(colMeta.Pos))
+	}
+	return append(order, s.pkOrder()...)
+}
+
+// pkOrder は PK カラムの位置を PK の順に返す (クラスタ化インデックスのレコードの並び順)
+func (s *Search) pkOrder() []int {
+	order := make([]int, s.tblMeta.PKCount)
+	for i := range order {
+		order[i] = i
+	}
+	return order
+}
+
+// isPKColumn は指定カラムが PK を構成するカラムかどうかを判定する
+func (s *Search) isPKColumn(colName string) bool {
+	colMeta, ok := s.tblMeta.GetColByName(colName)
+	return ok && colMeta.Pos < uint16(s.tblMeta.PKCount)
+}
+
 // isPKLeadingColumn は指定カラムが単一カラムプライマリキーかどうかを判定する
 //
 // 複合 PK の先頭カラムだけでは一意にならないため、PKCount == 1 の場合のみ true を返す
@@ -1,11 +1,12 @@
 package planner
 
 import (
-	"errors"
 	"fmt"
 
 	"github.com/ren-yamanashi/minesql/internal/ast"
 	"github.com/ren-yamanashi/minesql/internal/executor"
+	"github.com/ren-yamanashi/minesql/internal/storage/dictionary"
+	"github.com/ren-yamanashi/minesql/internal/storage/handler"
 )
 
 // -------------------------------------------------
@@ -14,37 +15,23 @@ import (
 
 // extractANDLeaves は純粋な AND ツリーからリーフ条件を抽出する
 //
-// OR が含まれている場合は nil を返す
-func extractANDLeaves(expr ast.BinaryExpr) []leafCondition {
-	// リーフ: LhsColumn op RhsLiteral
-	if lhs, ok := expr.Left.(*ast.LhsColumn); ok {
-		if rhs, ok := expr.Right.(*ast.RhsLiteral); ok {
-			return []leafCondition{{
-				colName:  lhs.Column.ColName,
-				operator: expr.Operator,
-				literal:  rhs.Literal,
-			}}
-		}
-		return nil
+// OR やリーフ条件にできない条件が含まれている場合は nil を返す
+func extractANDLeaves(expr ast.Expr) []leafCondition {
+	if leaf, ok := leafOf(expr); ok {
+		return []leafCondition{leaf}
 	}
 
-	// ブランチ: LhsExpr AND/OR RhsExpr
-	lhsExpr, lhsOk := expr.Left.(*ast.LhsExpr)
-	rhsExpr, rhsOk := expr.Right.(*ast.RhsExpr)
-	if !lhsOk || !rhsOk {
+	// ブランチ: 左辺 AND 右辺 (OR が含まれていたら最適化不可)
+	binary, ok := expr.(*ast.BinaryExpr)
+	if !ok || binary.Operator != "AND" {
 		return nil
 	}
 
-	// OR が含まれていたら最適化不可
-	if expr.Operator != "AND" {
-		return nil
-	}
-
-	leftLeaves := extractANDLeaves(*lhsExpr.Expr)
+	leftLeaves := extractANDLeaves(ast.LeftOperand(binary.Left))
 	if leftLeaves == nil {
 		return nil
 	}
-	rightLeaves := extractANDLeaves(*rhsExpr.Expr)
+	rightLeaves := extractANDLeaves(ast.RightOperand(binary.Right))
 	if rightLeaves == nil {
 		return nil
 	}
@@ -52,39 +39,96 @@ func extractANDLeaves(expr ast.BinaryExpr) []leafCondition {
 	return append(leftLeaves, rightLeaves...)
 }
 
-// extractORBranches は OR ツリーから各ブランチを抽出する
+// leafOf は式をリーフ条件に変換する (リーフ条件にできない場合は false を返す)
 //
-// 各ブランチは単一条件 (col op literal) または複合 AND 条件を保持できる
-// AND サブツリーは extractANDLeaves でリーフ条件に分解する
-// 分解できないブランチがある場合は nil を返す
-func extractORBranches(expr ast.BinaryExpr) []orBranch {
-	// リーフ: LhsColumn op RhsLiteral → 単一条件のブランチ
-	if lhs, ok := expr.Left.(*ast.LhsColumn); ok {
-		if rhs, ok := expr.Right.(*ast.RhsLiteral); ok {
-			leaf := leafCondition{
-				colName:  lhs.Column.ColName,
-				operator: expr.Operator,
-				literal:  rhs.Literal,
+// リーフ条件にできる式:
+//   - col op literal (op は isLeafOperator の対象)
+//   - col [NOT] IN (literal, ...)
+//   - col [NOT] BETWEEN literal AND literal
+func leafOf(expr ast.Expr) (leafCondition, bool) {
+	switch e := expr.(type) {
+	case *ast.BinaryExpr:
+		lhs, ok := e.Left.(*ast.LhsColumn)
+		if !ok {
+			return leafCondition{}, false
+		}
+		rhs, ok := e.Right.(*ast.RhsLiteral)
+		if !ok || !isLeafOperator(e.Operator) {
+			return leafCondition{}, false
+		}
+		return leafCondition{colName: lhs.Column.ColName, operator: e.Operator, literal: rhs.Literal}, true
+
+	case *ast.InExpr:
+		col, ok := e.Operand.(ast.ColumnId)
+		if !ok || e.Subquery != nil {
+			return leafCondition{}, false
+		}
+		literals := make([]ast.Literal, len(e.Values))
+		for i, value := range e.Values {
+			lit, ok := value.(ast.Literal)
+			if !ok {
+				return leafCondition{}, false
 			}
-			return []orBranch{{leaves: []leafCondition{leaf}, expr: expr}}
+			literals[i] = lit
 		}
-		return nil
+		operator := "IN"
+		if e.Not {
+			operator = "NOT IN"
+		}
+		return leafCondition{colName: col.ColName, operator: operator, inLiterals: literals}, true
+
+	case *ast.BetweenExpr:
+		col, ok := e.Operand.(ast.ColumnId)
+		if !ok {
+			return leafCondition{}, false
+		}
+		lower, ok := e.Lower.(ast.Literal)
+		if !ok {
+			return leafCondition{}, false
+		}
+		upper, ok := e.Upper.(ast.Literal)
+		if !ok {
+			return leafCondition{}, false
+		}
+		operator := "BETWEEN"
+		if e.Not {
+			operator = "NOT BETWEEN"
+		}
+		return leafCondition{colName: col.ColName, operator: operator, literal: lower, upperLiteral: upper}, true
 	}
+	return leafCondition{}, false
+}
 
-	// ブランチ: LhsExpr op RhsExpr
-	lhsExpr, lhsOk := expr.Left.(*ast.LhsExpr)
-	rhsExpr, rhsOk := expr.Right.(*ast.RhsExpr)
-	if !lhsOk || !rhsOk {
-		return nil
+// isLeafOperator はリーフ条件 (PK / インデックスのレンジ分析の対象) にできる演算子かどうかを返す
+//
+// 算術演算子 (e.g. `col + 1`) はリーフ条件にしない
+func isLeafOperator(operator string) bool {
+	switch operator {
+	case "=", "!=", "<", "<=", ">", ">=", "IS", "IS NOT", "LIKE", "NOT LIKE":
+		return true
+	default:
+		return false
 	}
+}
 
-	if expr.Operator == "OR" {
-		// OR ノード: 左右を再帰して連結
-		leftBranches := extractORBranches(*lhsExpr.Expr)
+// extractORBranches は OR ツリーから各ブランチを抽出する
+//
+// 各ブランチは単一条件 (リーフ条件) または複合 AND 条件を保持できる
+// AND サブツリーは extractANDLeaves でリーフ条件に分解する
+// 分解できないブランチがある場合は nil を返す
+func extractORBranches(expr ast.Expr) []orBranch {
+	// リーフ → 単一条件のブランチ
+	if leaf, ok := leafOf(expr); ok {
+		return []orBranch{{leaves: []leafCondition{leaf}, expr: expr}}
+	}
+
+	// OR ノード: 左右を再帰して連結
+	if binary, ok := expr.(*ast.BinaryExpr); ok && binary.Operator == "OR" {
+		leftBranches := extractORBranches(ast.LeftOperand(binary.Left))
 		if leftBranches == nil {
 			return nil
 		}
-		rightBranches := extractORBranches(*rhsExpr.Expr)
+		rightBranches := extractORBranches(ast.RightOperand(binary.Right))
 		if rightBranches == nil {
 			return nil
 		}
@@ -100,138 +144,221 @@ func extractORBranches(expr ast.BinaryExpr) []orBranch {
 }
 
 // -------------------------------------------------
-// 条件関数の構築
+// 定数畳み込み
 // -------------------------------------------------
 
-// buildConditionFunc は式の木構造から単一テーブル用の条件関数を再帰的に構築する
-func (s *Search) buildConditionFunc(expr ast.BinaryExpr) (func(executor.Record) bool, error) {
-	switch lhs := expr.Left.(type) {
-
-	// リーフノード: col op literal のような単純な条件 (例: col1 = 5)
-	case *ast.LhsColumn:
-		colName := lhs.Column.ColName
-		colMeta, ok := s.tblMeta.GetColByName(colName)
-		if !ok {
-			return nil, errors.New("column " + colName + " does not exist in table " + s.tblMeta.Name)
-		}
+// foldSearchConstants はリーフ条件の値の位置にある定数式 (e.g. `id = 1 + 2` の `1 + 2`) をリテラルに置き換えた WHERE 句を返す
+//
+// リーフ条件は値がリテラルの場合のみ抽出するため、リーフ条件の抽出より前に畳み込んでおくことで、定数式との比較にも PK / インデックスのアクセス方法を使えるようにする
+// 元の WHERE 句は変更しない
+func foldSearchConstants(where *ast.WhereClause, tblMeta *handler.TableMetadata) *ast.WhereClause {
+	if where == nil {
+		return nil
+	}
+	return &ast.WhereClause{Condition: foldConditionConstants(where.Condition, tblMeta)}
+}
 
-		switch rhs := expr.Right.(type) {
-		case *ast.RhsLiteral:
-			return operatorToCondition(expr.Operator, int(colMeta.Pos), rhs.Literal.ToString())
-		default:
-			return nil, errors.New("when LHS is a column, RHS must be a literal")
+// foldConditionConstants は AND / OR / NOT をたどり、カラムとの比較・IN・BETWEEN の値の位置にある定数式をリテラルに置き換える
+func foldConditionConstants(expr ast.Expr, tblMeta *handler.TableMetadata) ast.Expr {
+	switch e := expr.(type) {
+	case *ast.BinaryExpr:
+		switch {
+		case e.Operator == "AND" || e.Operator == "OR":
+			left := foldConditionConstants(ast.LeftOperand(e.Left), tblMeta)
+			right := foldConditionConstants(ast.RightOperand(e.Right), tblMeta)
+			return ast.NewBinaryExpr(e.Operator, conditionLHS(left), conditionRHS(right))
+		case isLeafOperator(e.Operator):
+			if lhs, ok := e.Left.(*ast.LhsColumn); ok {
+				return ast.NewBinaryExpr(e.Operator, e.Left, conditionRHS(foldConstantExpr(ast.RightOperand(e.Right), lhs.Column, tblMeta)))
+			}
 		}
-
-	// ブランチノード: expr AND/OR expr (例: col1 = 5 AND col2 > 10 のような複合条件)
-	case *ast.LhsExpr:
-		leftCond, err := s.buildConditionFunc(*lhs.Expr)
-		if err != nil {
-			return nil, err
+	case *ast.UnaryExpr:
+		if e.Operator == "NOT" {
+			return ast.NewUnaryExpr(e.Operator, foldConditionConstants(e.Operand, tblMeta))
 		}
-
-		switch rhs := expr.Right.(type) {
-		case *ast.RhsExpr:
-			rightCond, err := s.buildConditionFunc(*rhs.Expr)
-			if err != nil {
-				return nil, err
+	case *ast.InExpr:
+		if col, ok := e.Operand.(ast.ColumnId); ok && e.Subquery == nil {
+			values := make([]ast.Expr, len(e.Values))
+			for i, value := range e.Values {
+				values[i] = foldConstantExpr(value, col, tblMeta)
 			}
-			return combineConditions(expr.Operator, leftCond, rightCond)
-		default:
-			return nil, errors.New("when LHS is an expression, RHS cannot be a literal")
+			return ast.NewInExpr(e.Operand, values, e.Not)
+		}
+	case *ast.BetweenExpr:
+		if col, ok := e.Operand.(ast.ColumnId); ok {
+			return ast.NewBetweenExpr(e.Operand, foldConstantExpr(e.Lower, col, tblMeta), foldConstantExpr(e.Upper, col, tblMeta), e.Not)
 		}
-
-	default:
-		panic("unsupported LHS type in buildConditionFunc")
 	}
+	return expr
 }
 
-// buildJoinedConditionFunc は結合レコードに対する条件関数を再帰的に構築する
+// foldConstantExpr は col と比較する定数式 (カラム・集約関数・ウィンドウ関数・サブクエリを含まない式) を評価したリテラルを返す
 //
-// カラム位置は resolveJoinedColumns で得た結合レコード全体の位置を使用する
-func buildJoinedConditionFunc(expr ast.BinaryExpr, columns []joinedColumn) (func(executor.Record) bool, error) {
-	switch lhs := expr.Left.(type) {
+// 以下の場合は式をそのまま返す (条件をコンパイルする時点で評価する)
+//   - 定数式でない場合や、評価できない場合 (e.g. 0 除算。エラーはコンパイルする時点で返す)
+//   - 値を col のデータ型に変換すると比較の結果が変わりうる場合 (e.g. INT のカラムと DECIMAL の値)
+func foldConstantExpr(expr ast.Expr, col ast.ColumnId, tblMeta *handler.TableMetadata) ast.Expr {
+	if _, ok := expr.(ast.Literal); ok || !isConstantExpr(expr) {
+		return expr
+	}
+	colMeta, ok := tblMeta.GetColByName(col.ColName)
+	if !ok {
+		return expr
+	}
+	te, err := compileValue(expr, newExprScope(nil))
+	if err != nil {
+		return expr
+	}
+	value, err := te.expr.Eval(nil)
+	if err != nil {
+		return expr
+	}
+	if value == nil {
+		return ast.NewNullLiteral()
+	}
+	if !isExactlyConvertible(te.meta, colMeta) {
+		return expr
+	}
+	lit := ast.NewStringLiteral(dictionary.FormatValue(te.meta.Type, te.meta.Scale, value))
+	if _, err := parseLiteral(colMeta, lit); err != nil {
+		return expr
+	}
+	return lit
+}
 
-	case *ast.LhsColumn:
-		pos, err := findColumnPos(columns, lhs.Column.TableName, lhs.Column.ColName)
-		if err != nil {
-			return nil, err
+// isConstantExpr は式がカラム・集約関数・ウィンドウ関数・サブクエリを含まないかどうかを返す
+func isConstantExpr(expr ast.Expr) bool {
+	constant := true
+	ast.WalkExpr(expr, func(e ast.Expr) bool {
+		switch v := e.(type) {
+		case ast.ColumnId, *ast.AggregateExpr, *ast.WindowFuncExpr, *ast.SubqueryExpr, *ast.ExistsExpr:
+			constant = false
+		case *ast.InExpr:
+			constant = v.Subquery == nil
 		}
+		return constant
+	})
+	return constant
+}
 
-		switch rhs := expr.Right.(type) {
-		case *ast.RhsLiteral:
-			return operatorToCondition(expr.Operator, pos, rhs.Literal.ToString())
-		default:
-			return nil, errors.New("unsupported RHS type in joined WHERE condition")
-		}
+// isExactlyConvertible はデータ型 from の値を、値を変えずにカラムのデータ型 to に変換できるかどうかを返す (値の範囲は検証しない)
+func isExactlyConvertible(from, to *handler.ColumnMetadata) bool {
+	switch {
+	case isIntegerType(to.Type):
+		return isIntegerType(from.Type)
+	case to.Type == handler.ColumnTypeDecimal:
+		return isIntegerType(from.Type) || (from.Type == handler.ColumnTypeDecimal && from.Scale <= to.Scale)
+	default:
+		return from.Type == to.Type
+	}
+}
 
-	case *ast.LhsExpr:
-		leftCond, err := buildJoinedConditionFunc(*lhs.Expr, columns)
-		if err != nil {
-			return nil, err
-		}
+// -------------------------------------------------
+// 条件関数の構築
+// -------------------------------------------------
 
-		switch rhs := expr.Right.(type) {
-		case *ast.RhsExpr:
-			rightCond, err := buildJoinedConditionFunc(*rhs.Expr, columns)
-			if err != nil {
-				return nil, err
-			}
-			return combineConditions(expr.Operator, leftCond, rightCond)
-		default:
-			return nil, errors.New("when LHS is an expression, RHS must also be an expression")
-		}
+// buildCondition は式の木構造から単一テーブル用の条件式を構築する
+//
+// 条件式は 3 値論理で評価され、Filter は結果が TRUE の行のみを返す (FALSE と UNKNOWN は除外する)
+func (s *Search) buildCondition(expr ast.Expr) (executor.Expression, error) {
+	return compileCondition(expr, newTableExprScope(s.tblMeta))
+}
 
-	default:
-		panic("unsupported LHS type in buildJoinedConditionFunc")
-	}
+// buildJoinedCondition は結合レコードに対する条件式を構築する
+//
+// カラム位置は resolveJoinedColumns で得た結合レコード全体の位置を使用する
+func buildJoinedCondition(expr ast.Expr, columns []joinedColumn) (executor.Expression, error) {
+	return compileCondition(expr, newExprScope(columns))
 }
 
 // -------------------------------------------------
 // 条件関数ヘルパー
 // -------------------------------------------------
 
+// sqlBool は SQL の 3 値論理における真理値 (TRUE / FALSE / UNKNOWN) を表す
+//
+// NULL との比較結果は UNKNOWN になる
+type sqlBool uint8
+
+const (
+	sqlFalse sqlBool = iota
+	sqlTrue
+	sqlUnknown
+)
+
+// toSQLBool は bool を sqlBool に変換する
+func toSQLBool(b bool) sqlBool {
+	if b {
+		return sqlTrue
+	}
+	return sqlFalse
+}
+
+// predicate はレコードを受け取り、条件を 3 値論理で評価した結果を返す関数
+type predicate func(executor.Record) sqlBool
+
+// toCondition は述語を条件関数に変換する (TRUE の場合のみ true を返す)
+func (p predicate) toCondition() func(executor.Record) bool {
+	return func(record executor.Record) bool {
+		return p(record) == sqlTrue
+	}
+}
+
 // operatorToCondition は二項演算子を条件関数に変換する
 //
 // 条件関数: レコードを受け取り、条件を満たすかどうか (bool) を返す関数
 func operatorToCondition(operator string, pos int, value string) (func(executor.Record) bool, error) {
+	pred, err := operatorToPredicate(operator, pos, []byte(value))
+	if err != nil {
+		return nil, err
+	}
+	return pred.toCondition(), nil
+}
+
+// operatorToPredicate は二項演算子を述語に変換する
+//
+// value が nil の場合は NULL との比較として扱う
+//   - IS / IS NOT: カラム値が NULL かどうかを判定する (結果は TRUE か FALSE)
+//   - 比較演算子: カラム値か value のいずれかが NULL の場合は UNKNOWN
+func operatorToPredicate(operator string, pos int, value []byte) (predicate, error) {
 	switch operator {
-	case "=":
-		return func(record executor.Record) bool {
-			return string(record[pos]) == value
+	case "IS":
+		return func(record executor.Record) sqlBool {
+			return toSQLBool(record[pos] == nil)
 		}, nil
-	case "!=":
-		return func(record executor.Record) bool {
-			return string(record[pos]) != value
+	case "IS NOT":
+		return func(record executor.Record) sqlBool {
+			return toSQLBool(record[pos] != nil)
 		}, nil
+	}
+
+	var compare func(col string) bool
+	v := string(value)
+	switch operator {
+	case "=":
+		compare = func(col string) bool { return col == v }
+	case "!=":
+		compare = func(col string) bool { return col != v }
 	case "<":
-		return func(record executor.Record) bool {
-			return string(record[pos]) < value
-		}, nil
+		compare = func(col string) bool { return col < v }
 	case "<=":
-		return func(record executor.Record) bool {
-			return string(record[pos]) <= value
-		}, nil
+		compare = func(col string) bool { return col <= v }
 	case ">":
-		return func(record executor.Record) bool {
-			return string(record[pos]) > value
-		}, nil
+		compare = func(col string) bool { return col > v }
 	case ">=":
-		return func(record executor.Record) bool {
-			return string(record[pos]) >= value
-		}, nil
+		compare = func(col string) bool { return col >= v }
 	default:
 		return nil, fmt.Errorf("unsupported operator in WHERE clause: %s", operator)
 	}
-}
 
-// combineConditions は 2 つの条件関数を論理演算子 (AND/OR) で結合する
-func combineConditions(operator string, left, right func(executor.Record) bool) (func(executor.Record) bool, error) {
-	switch operator {
-	case "AND":
-		return func(r executor.Record) bool { return left(r) && right(r) }, nil
-	case "OR":
-		return func(r executor.Record) bool { return left(r) || right(r) }, nil
-	default:
-		return nil, fmt.Errorf("unsupported logical operator: %s", operator)
+	if value == nil {
+		// NULL との比較は常に UNKNOWN
+		return func(record executor.Record) sqlBool { return sqlUnknown }, nil
 	}
+	return func(record executor.Record) sqlBool {
+		if record[pos] == nil {
+			return sqlUnknown
+		}
+		return toSQLBool(compare(string(record[pos])))
+	}, nil
 }
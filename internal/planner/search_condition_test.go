@@ -3,8 +3,11 @@ package planner
 import (
 	"testing"
 
+	"github.com/ren-yamanashi/minesql/internal/ast"
 	"github.com/ren-yamanashi/minesql/internal/executor"
+	"github.com/ren-yamanashi/minesql/internal/storage/handler"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestOperatorToCondition(t *testing.T) {
@@ -130,3 +133,39 @@ func TestOperatorToCondition(t *testing.T) {
 		assert.False(t, cond2(executor.Record{[]byte("1"), []byte("John"), []byte("Smith")}))
 	})
 }
+
+func TestFoldSearchConstants(t *testing.T) {
+	tests := []struct {
+		name     string
+		where    string
+		expected string // 置き換えた後の条件の表記 (空の場合は置き換えない)
+	}{
+		{"カラムと比較する定数式をリテラルに置き換える", "id = 1 + 2", "id = 3"},
+		{"AND / OR / NOT の中の IN・BETWEEN の値も置き換える", "NOT (id IN (1 + 1, 5) OR id BETWEEN 2 * 2 AND 10 - 1) AND name = CONCAT('App', 'le')", "(NOT ((id IN (2, 5)) OR (id BETWEEN 4 AND 9))) AND (name = Apple)"},
+		{"カラムを含む式は置き換えない", "id = parent_id + 1", ""},
+		{"値を変えずにカラムのデータ型に変換できない場合は置き換えない", "id = 6 / 2", ""},
+		{"評価できない場合は置き換えない", "id = 9223372036854775807 + 1", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// GIVEN
+			setupCategoriesTable(t)
+			defer handler.Reset()
+			stmt := parseStatementForTest(t, "SELECT * FROM categories WHERE "+tt.where+";").(*ast.SelectStmt)
+			original := ast.ExprString(stmt.Where.Condition)
+			tblMeta, ok := handler.Get().Catalog.GetTableMetaByName("categories")
+			require.True(t, ok)
+
+			// WHEN
+			folded := foldSearchConstants(stmt.Where, tblMeta)
+
+			// THEN: 元の WHERE 句は変更しない
+			expected := tt.expected
+			if expected == "" {
+				expected = original
+			}
+			assert.Equal(t, expected, ast.ExprString(folded.Condition))
+			assert.Equal(t, original, ast.ExprString(stmt.Where.Condition))
+		})
+	}
+}
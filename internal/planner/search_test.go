@@ -1,6 +1,9 @@
 package planner
 
 import (
+	"bytes"
+	"fmt"
+	"slices"
 	"testing"
 
 	"github.com/ren-yamanashi/minesql/internal/ast"
@@ -211,14 +214,14 @@ func TestSearch(t *testing.T) {
 		assert.IsType(t, &executor.Filter{}, exec)
 	})
 
-	t.Run("LHS がカラム、RHS が式の場合、エラーを返す", func(t *testing.T) {
+	t.Run("比較の両辺のデータ型に互換性がない場合、エラーを返す", func(t *testing.T) {
 		// GIVEN
 		tmpdir := t.TempDir()
 		initStorageManager(t, tmpdir)
 		defer handler.Reset()
 
 		tblMeta := getTableMetadata(t, "users")
-		// WHERE last_name = (first_name = 'John') のような不正な構造
+		// WHERE last_name = (first_name = 'John') のように文字列と比較結果 (BIGINT) を比較する
 		where := &ast.WhereClause{
 			Condition: ast.NewBinaryExpr(
 				"=",
@@ -240,7 +243,7 @@ func TestSearch(t *testing.T) {
 		// THEN
 		assert.Error(t, err)
 		assert.Nil(t, exec)
-		assert.Contains(t, err.Error(), "when LHS is a column, RHS must be a literal")
+		assert.Contains(t, err.Error(), "incompatible types: string and bigint")
 	})
 
 	t.Run("OR 演算子を使った場合、Filter が生成される", func(t *testing.T) {
@@ -319,7 +322,7 @@ func TestSearch(t *testing.T) {
 		assert.Contains(t, err.Error(), "does not exist in table")
 	})
 
-	t.Run("条件内で LHS がカラム、RHS が式の場合、エラーを返す", func(t *testing.T) {
+	t.Run("条件内で比較の両辺のデータ型に互換性がない場合、エラーを返す", func(t *testing.T) {
 		// GIVEN
 		tmpdir := t.TempDir()
 		initStorageManager(t, tmpdir)
@@ -360,7 +363,7 @@ func TestSearch(t *testing.T) {
 		// THEN
 		assert.Error(t, err)
 		assert.Nil(t, exec)
-		assert.Contains(t, err.Error(), "when LHS is a column, RHS must be a literal")
+		assert.Contains(t, err.Error(), "incompatible types: string and bigint")
 	})
 
 	t.Run("条件内でサポートされていない論理演算子の場合、エラーを返す", func(t *testing.T) {
@@ -398,7 +401,7 @@ func TestSearch(t *testing.T) {
 		// THEN
 		assert.Error(t, err)
 		assert.Nil(t, exec)
-		assert.Contains(t, err.Error(), "unsupported logical operator")
+		assert.Contains(t, err.Error(), "unsupported operator: XOR")
 	})
 
 	t.Run("BinaryExpr で LHS がサポートされていない型の場合、エラーを返す", func(t *testing.T) {
@@ -430,7 +433,7 @@ func TestSearch(t *testing.T) {
 		assert.Contains(t, err.Error(), "unsupported LHS type")
 	})
 
-	t.Run("条件内で LHS が式、RHS がリテラルの場合、エラーを返す", func(t *testing.T) {
+	t.Run("条件内で文字列リテラルを条件として使用した場合、エラーを返す", func(t *testing.T) {
 		// GIVEN
 		tmpdir := t.TempDir()
 		initStorageManager(t, tmpdir)
@@ -471,7 +474,7 @@ func TestSearch(t *testing.T) {
 		// THEN
 		assert.Error(t, err)
 		assert.Nil(t, exec)
-		assert.Contains(t, err.Error(), "when LHS is an expression, RHS cannot be a literal")
+		assert.Contains(t, err.Error(), "expression invalid cannot be used as a condition")
 	})
 }
 
@@ -704,7 +707,7 @@ func initStorageManager(t *testing.T, dataDir string) {
 
 	// テーブルを作成
 	createTable := executor.NewCreateTable("users", 1, []handler.CreateIndexParam{
-		{Name: "last_name", ColName: "last_name", ColIdx: 2, Unique: true},
+		{Name: "last_name", ColNames: []string{"last_name"}, ColIdxs: []uint16{2}, Unique: true},
 	}, []handler.CreateColumnParam{
 		{Name: "id", Type: handler.ColumnTypeString},
 		{Name: "first_name", Type: handler.ColumnTypeString},
@@ -1131,27 +1134,29 @@ func TestIsPKLeadingColumn(t *testing.T) {
 }
 
 func TestBuildRangeKeys(t *testing.T) {
-	literal := ast.NewStringLiteral("abc")
+	value := []byte("abc")
 	var expectedKey []byte
-	encode.Encode([][]byte{literal.ToBytes()}, &expectedKey)
+	encode.Encode([][]byte{value}, &expectedKey)
+	// value を接頭辞に持つすべてのキー (セカンダリキー + PK) より大きいキー
+	expectedUpperKey := append(slices.Clone(expectedKey), bytes.Repeat([]byte{0xFF}, encode.BLOCK_SIZE)...)
 
-	t.Run("= は lower=upper で両端を含む", func(t *testing.T) {
+	t.Run("= は lower=key, upper=key の上限 で両端を含む", func(t *testing.T) {
 		// WHEN
-		lower, upper, leftIncl, rightIncl := buildRangeKeys("=", literal)
+		lower, upper, leftIncl, rightIncl := buildRangeKeys("=", value)
 
 		// THEN
 		assert.Equal(t, expectedKey, lower)
-		assert.Equal(t, expectedKey, upper)
+		assert.Equal(t, expectedUpperKey, upper)
 		assert.True(t, leftIncl)
 		assert.True(t, rightIncl)
 	})
 
-	t.Run("> は lower=key, upper=nil で左端を含まない", func(t *testing.T) {
+	t.Run("> は lower=key の上限, upper=nil で左端を含まない", func(t *testing.T) {
 		// WHEN
-		lower, upper, leftIncl, rightIncl := buildRangeKeys(">", literal)
+		lower, upper, leftIncl, rightIncl := buildRangeKeys(">", value)
 
 		// THEN
-		assert.Equal(t, expectedKey, lower)
+		assert.Equal(t, expectedUpperKey, lower)
 		assert.Nil(t, upper)
 		assert.False(t, leftIncl)
 		assert.True(t, rightIncl)
@@ -1159,7 +1164,7 @@ func TestBuildRangeKeys(t *testing.T) {
 
 	t.Run(">= は lower=key, upper=nil で左端を含む", func(t *testing.T) {
 		// WHEN
-		lower, upper, leftIncl, rightIncl := buildRangeKeys(">=", literal)
+		lower, upper, leftIncl, rightIncl := buildRangeKeys(">=", value)
 
 		// THEN
 		assert.Equal(t, expectedKey, lower)
@@ -1170,7 +1175,7 @@ func TestBuildRangeKeys(t *testing.T) {
 
 	t.Run("< は lower=nil, upper=key で右端を含まない", func(t *testing.T) {
 		// WHEN
-		lower, upper, leftIncl, rightIncl := buildRangeKeys("<", literal)
+		lower, upper, leftIncl, rightIncl := buildRangeKeys("<", value)
 
 		// THEN
 		assert.Nil(t, lower)
@@ -1179,18 +1184,243 @@ func TestBuildRangeKeys(t *testing.T) {
 		assert.False(t, rightIncl)
 	})
 
-	t.Run("<= は lower=nil, upper=key で右端を含む", func(t *testing.T) {
+	t.Run("<= は lower=nil, upper=key の上限 で右端を含む", func(t *testing.T) {
 		// WHEN
-		lower, upper, leftIncl, rightIncl := buildRangeKeys("<=", literal)
+		lower, upper, leftIncl, rightIncl := buildRangeKeys("<=", value)
 
 		// THEN
 		assert.Nil(t, lower)
-		assert.Equal(t, expectedKey, upper)
+		assert.Equal(t, expectedUpperKey, upper)
 		assert.True(t, leftIncl)
 		assert.True(t, rightIncl)
 	})
 }
 
+func TestLikePrefixRange(t *testing.T) {
+	tests := []struct {
+		name          string
+		pattern       string
+		expectedLower []byte
+		expectedUpper []byte
+	}{
+		{"接頭辞の最後のバイトに 1 を足したものが上限になる", "abc%", []byte("abc"), []byte("abd")},
+		{"_ より前が接頭辞になる", "ab_d", []byte("ab"), []byte("ac")},
+		{"エスケープしたワイルドカードは接頭辞に含める", "a\\%b%", []byte("a%b"), []byte("a%c")},
+		{"末尾の 0xFF は取り除いてから 1 を足す", "a\xff%", []byte("a\xff"), []byte("b")},
+		{"接頭辞がすべて 0xFF の場合は上限なし", "\xff%", []byte("\xff"), nil},
+		{"ワイルドカードで始まる場合はレンジにできない", "%abc", nil, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// WHEN
+			lower, upper := likePrefixRange(tt.pattern)
+
+			// THEN
+			assert.Equal(t, tt.expectedLower, lower)
+			assert.Equal(t, tt.expectedUpper, upper)
+		})
+	}
+}
+
+func TestParseRangeLiteral(t *testing.T) {
+	intCol := &handler.ColumnMetadata{Name: "qty", Type: handler.ColumnTypeInt}
+	decimalCol := &handler.ColumnMetadata{Name: "price", Type: handler.ColumnTypeDecimal, Precision: 10, Scale: 2}
+	dateCol := &handler.ColumnMetadata{Name: "day", Type: handler.ColumnTypeDate}
+	dec31, _ := dateCol.ParseValue("2023-12-31")
+	jan1, _ := dateCol.ParseValue("2024-01-01")
+
+	tests := []struct {
+		name          string
+		colMeta       *handler.ColumnMetadata
+		literal       string
+		expectedFloor []byte
+		expectedCeil  []byte
+	}{
+		{"カラムのデータ型で表せる値は floor と ceil が等しい", intCol, "10", encode.EncodeInt64(10), encode.EncodeInt64(10)},
+		{"INT のカラムと小数は前後の整数になる", intCol, "9.5", encode.EncodeInt64(9), encode.EncodeInt64(10)},
+		{"負の小数は 0 から離れる方向に切り捨てる", intCol, "-9.5", encode.EncodeInt64(-10), encode.EncodeInt64(-9)},
+		{"小数部が 0 の小数は INT で表せる", intCol, "9.00", encode.EncodeInt64(9), encode.EncodeInt64(9)},
+		{"DECIMAL のカラムとスケールより桁数の多い小数は最小桁で丸める", decimalCol, "1.255", encode.EncodeInt64(125), encode.EncodeInt64(126)},
+		{"DATE のカラムと時刻を含む値は前後の日付になる", dateCol, "2023-12-31 10:00:00", dec31, jan1},
+		{"DATE のカラムと 00:00:00 の値は DATE で表せる", dateCol, "2024-01-01 00:00:00", jan1, jan1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// WHEN
+			floor, ceil, err := parseRangeLiteral(tt.colMeta, lit(tt.literal))
+
+			// THEN
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedFloor, floor)
+			assert.Equal(t, tt.expectedCeil, ceil)
+		})
+	}
+}
+
+func TestPredicatePlan(t *testing.T) {
+	// users に id = "001" ~ "100", last_name = "L001" ~ "L100" の 100 行を挿入する
+	// (フルスキャンのコストが点検索・レンジスキャンより大きくなる行数)
+	setup := func(t *testing.T) *handler.TableMetadata {
+		t.Helper()
+		initStorageManager(t, t.TempDir())
+		var values [][]ast.Literal
+		for i := 1; i <= 100; i++ {
+			id := fmt.Sprintf("%03d", i)
+			values = append(values, []ast.Literal{
+				ast.NewStringLiteral(id),
+				ast.NewStringLiteral("John"),
+				ast.NewStringLiteral("L" + id),
+			})
+		}
+		insertExec, err := PlanInsert(1, &ast.InsertStmt{
+			Table:  *ast.NewTableId("users"),
+			Cols:   []ast.ColumnId{*ast.NewColumnId("id"), *ast.NewColumnId("first_name"), *ast.NewColumnId("last_name")},
+			Values: values,
+		})
+		assert.NoError(t, err)
+		_, err = insertExec.Next()
+		assert.NoError(t, err)
+		return getTableMetadata(t, "users")
+	}
+
+	// build は条件から Search を構築し、Executor と検索結果の id を返す
+	build := func(t *testing.T, tblMeta *handler.TableMetadata, condition ast.Expr) (*Search, executor.Executor, []string) {
+		t.Helper()
+		search := NewSearch(access.NewReadView(0, nil, ^uint64(0)), access.NewVersionReader(nil), tblMeta, &ast.WhereClause{Condition: condition}, handler.Get().BufferPool)
+		exec, err := search.Build()
+		assert.NoError(t, err)
+		var ids []string
+		for _, record := range fetchAll(t, exec) {
+			ids = append(ids, string(record[0]))
+		}
+		return search, exec, ids
+	}
+
+	t.Run("PK の IN は各値の点検索を Union で結合する (NULL と重複は除き、PK 順に返す)", func(t *testing.T) {
+		// GIVEN
+		tblMeta := setup(t)
+		defer handler.Reset()
+		condition := ast.NewInExpr(*ast.NewColumnId("id"), []ast.Expr{
+			ast.NewStringLiteral("050"), ast.NewStringLiteral("003"), ast.NewNullLiteral(), ast.NewStringLiteral("050"),
+		}, false)
+
+		// WHEN
+		search, exec, ids := build(t, tblMeta, condition)
+
+		// THEN
+		assert.IsType(t, &executor.Union{}, exec)
+		assert.Equal(t, []string{"003", "050"}, ids)
+		assert.Equal(t, []int{0}, search.sortOrder)
+	})
+
+	t.Run("UNIQUE INDEX の IN は各値のインデックススキャンを Union で結合する", func(t *testing.T) {
+		// GIVEN
+		tblMeta := setup(t)
+		defer handler.Reset()
+		condition := ast.NewInExpr(*ast.NewColumnId("last_name"), []ast.Expr{
+			ast.NewStringLiteral("L007"), ast.NewStringLiteral("L002"), ast.NewStringLiteral("L999"),
+		}, false)
+
+		// WHEN
+		search, exec, ids := build(t, tblMeta, condition)
+
+		// THEN
+		assert.IsType(t, &executor.Union{}, exec)
+		assert.Equal(t, []string{"002", "007"}, ids)
+		assert.Equal(t, []int{2, 0}, search.sortOrder)
+	})
+
+	t.Run("NOT IN はフルスキャン + Filter になる", func(t *testing.T) {
+		// GIVEN
+		tblMeta := setup(t)
+		defer handler.Reset()
+		condition := ast.NewInExpr(*ast.NewColumnId("id"), []ast.Expr{ast.NewStringLiteral("001")}, true)
+
+		// WHEN
+		_, exec, ids := build(t, tblMeta, condition)
+
+		// THEN
+		assert.IsType(t, &executor.Filter{}, exec)
+		assert.Equal(t, 99, len(ids))
+		assert.Equal(t, "002", ids[0])
+	})
+
+	t.Run("PK の BETWEEN は 1 回のレンジスキャンになる", func(t *testing.T) {
+		// GIVEN
+		tblMeta := setup(t)
+		defer handler.Reset()
+		condition := ast.NewBetweenExpr(*ast.NewColumnId("id"), ast.NewStringLiteral("010"), ast.NewStringLiteral("015"), false)
+
+		// WHEN
+		_, exec, ids := build(t, tblMeta, condition)
+
+		// THEN: 下限にシークして上限を超えたら停止するため Filter は不要
+		assert.IsType(t, &executor.TableScan{}, exec)
+		assert.Equal(t, []string{"010", "011", "012", "013", "014", "015"}, ids)
+	})
+
+	t.Run("インデックスの BETWEEN はインデックスのレンジスキャンになる", func(t *testing.T) {
+		// GIVEN
+		tblMeta := setup(t)
+		defer handler.Reset()
+		condition := ast.NewBetweenExpr(*ast.NewColumnId("last_name"), ast.NewStringLiteral("L098"), ast.NewStringLiteral("L200"), false)
+
+		// WHEN
+		_, exec, ids := build(t, tblMeta, condition)
+
+		// THEN
+		assert.IsType(t, &executor.IndexScan{}, exec)
+		assert.Equal(t, []string{"098", "099", "100"}, ids)
+	})
+
+	t.Run("前方一致の LIKE はインデックスのレンジスキャン + Filter になる", func(t *testing.T) {
+		// GIVEN
+		tblMeta := setup(t)
+		defer handler.Reset()
+		condition := ast.NewBinaryExpr("LIKE", ast.NewLhsColumn(*ast.NewColumnId("last_name")), ast.NewRhsLiteral(ast.NewStringLiteral("L02_")))
+
+		// WHEN
+		search, exec, ids := build(t, tblMeta, condition)
+
+		// THEN: インデックスの並び順 (last_name, id) で返される
+		assert.IsType(t, &executor.Filter{}, exec)
+		assert.Equal(t, []int{2, 0}, search.sortOrder)
+		assert.Equal(t, []string{"020", "021", "022", "023", "024", "025", "026", "027", "028", "029"}, ids)
+	})
+
+	t.Run("ワイルドカードで始まる LIKE はフルスキャン + Filter になる", func(t *testing.T) {
+		// GIVEN
+		tblMeta := setup(t)
+		defer handler.Reset()
+		condition := ast.NewBinaryExpr("LIKE", ast.NewLhsColumn(*ast.NewColumnId("last_name")), ast.NewRhsLiteral(ast.NewStringLiteral("%00")))
+
+		// WHEN
+		search, exec, ids := build(t, tblMeta, condition)
+
+		// THEN
+		assert.IsType(t, &executor.Filter{}, exec)
+		assert.Equal(t, []int{0}, search.sortOrder)
+		assert.Equal(t, []string{"100"}, ids)
+	})
+
+	t.Run("OR のブランチの IN も Union で点検索できる", func(t *testing.T) {
+		// GIVEN: id IN ('001', '002') OR last_name = 'L099'
+		tblMeta := setup(t)
+		defer handler.Reset()
+		condition := ast.NewBinaryExpr("OR",
+			ast.NewInExpr(*ast.NewColumnId("id"), []ast.Expr{ast.NewStringLiteral("001"), ast.NewStringLiteral("002")}, false),
+			ast.NewRhsExpr(ast.NewBinaryExpr("=", ast.NewLhsColumn(*ast.NewColumnId("last_name")), ast.NewRhsLiteral(ast.NewStringLiteral("L099")))),
+		)
+
+		// WHEN
+		_, exec, ids := build(t, tblMeta, condition)
+
+		// THEN
+		assert.IsType(t, &executor.Union{}, exec)
+		assert.Equal(t, []string{"001", "002", "099"}, ids)
+	})
+}
+
 func TestIsIndexOnlyScan(t *testing.T) {
 	// GIVEN: users (id=PK, name, email=UK) のテーブルメタ
 	tblMeta := &handler.TableMetadata{
@@ -1199,7 +1429,7 @@ func TestIsIndexOnlyScan(t *testing.T) {
 			{Name: "id", Pos: 0}, {Name: "name", Pos: 1}, {Name: "email", Pos: 2},
 		},
 		Indexes: []*dictionary.IndexMeta{
-			{Name: "idx_email", ColName: "email", Type: dictionary.IndexTypeUnique},
+			{Name: "idx_email", ColNames: []string{"email"}, Type: dictionary.IndexTypeUnique},
 		},
 	}
 
@@ -1243,6 +1473,17 @@ func TestIsIndexOnlyScan(t *testing.T) {
 		// WHEN & THEN
 		assert.True(t, s.isIndexOnlyScan("email"))
 	})
+
+	t.Run("SELECT カラムが空の場合 (COUNT(*) のみの集約) は index-only になる", func(t *testing.T) {
+		// GIVEN
+		s := &Search{
+			tblMeta:       tblMeta,
+			selectColumns: []ast.ColumnId{},
+		}
+
+		// WHEN & THEN
+		assert.True(t, s.isIndexOnlyScan("email"))
+	})
 }
 
 func TestSetSelectColumns(t *testing.T) {
@@ -1269,7 +1510,7 @@ func initStorageManagerWithNonUniqueIndex(t *testing.T, dataDir string) {
 
 	// テーブルを作成 (category に非ユニークインデックス)
 	createTable := executor.NewCreateTable("products", 1, []handler.CreateIndexParam{
-		{Name: "idx_category", ColName: "category", ColIdx: 2, Unique: false},
+		{Name: "idx_category", ColNames: []string{"category"}, ColIdxs: []uint16{2}, Unique: false},
 	}, []handler.CreateColumnParam{
 		{Name: "id", Type: handler.ColumnTypeString},
 		{Name: "name", Type: handler.ColumnTypeString},
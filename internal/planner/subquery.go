@@ -0,0 +1,751 @@
+package planner
+
+import (
+	"fmt"
+
+	"github.com/ren-yamanashi/minesql/internal/ast"
+	"github.com/ren-yamanashi/minesql/internal/executor"
+	"github.com/ren-yamanashi/minesql/internal/storage/config"
+	"github.com/ren-yamanashi/minesql/internal/storage/dictionary"
+	"github.com/ren-yamanashi/minesql/internal/storage/encode"
+	"github.com/ren-yamanashi/minesql/internal/storage/handler"
+)
+
+// subqueryPlanner は式の中のサブクエリ (スカラーサブクエリ・IN (SELECT ...)・EXISTS) を計画する
+type subqueryPlanner struct {
+	trxId handler.TrxId
+}
+
+// withSubqueries はスコープの式でサブクエリを使用できるようにする
+func (s *exprScope) withSubqueries(trxId handler.TrxId) *exprScope {
+	s.subquery = &subqueryPlanner{trxId: trxId}
+	return s
+}
+
+// plannedSubquery は計画したサブクエリ
+type plannedSubquery struct {
+	plan       executor.SubqueryPlan
+	correlated bool         // 外側のクエリのカラムを参照するか
+	columns    []ColumnMeta // サブクエリの結果のカラム
+}
+
+// planSubquery はサブクエリを計画する
+//
+// 相関のないサブクエリはコンパイル時に 1 回だけ計画する
+// 相関サブクエリは外側のレコードごとに、外側のカラムへの参照をその値のリテラルに置き換えて計画する
+// (外側の値で PK・インデックスを検索できるようにするため)
+// 結果のカラムのデータ型は、外側のカラムをそのデータ型の値に置き換えて計画して決める
+func planSubquery(stmt *ast.SelectStmt, scope *exprScope) (*plannedSubquery, error) {
+	if scope.subquery == nil {
+		return nil, fmt.Errorf("subquery is not supported in this statement: (%s)", ast.SelectString(stmt))
+	}
+	trxId := scope.subquery.trxId
+	corr, err := newCorrelation(trxId, stmt, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	if !corr.correlated {
+		result, err := PlanSelect(trxId, stmt)
+		if err != nil {
+			return nil, err
+		}
+		// 相関のないサブクエリの結果は Expression が保持するため、通常はコンパイル時に計画した Executor を 1 回だけ使う
+		exec := result.Exec
+		plan := func(executor.Record) (executor.Executor, error) {
+			if exec != nil {
+				e := exec
+				exec = nil
+				return e, nil
+			}
+			r, err := PlanSelect(trxId, stmt)
+			if err != nil {
+				return nil, err
+			}
+			return r.Exec, nil
+		}
+		return &plannedSubquery{plan: plan, columns: result.Columns}, nil
+	}
+
+	sample, err := corr.bind(stmt, nil)
+	if err != nil {
+		return nil, err
+	}
+	result, err := PlanSelect(trxId, sample)
+	if err != nil {
+		return nil, err
+	}
+	columns := result.Columns
+	plan := func(record executor.Record) (executor.Executor, error) {
+		bound, err := corr.bind(stmt, record)
+		if err != nil {
+			return nil, err
+		}
+		r, err := PlanSelect(trxId, bound)
+		if err != nil {
+			return nil, err
+		}
+		return conformColumns(r, columns), nil
+	}
+	return &plannedSubquery{plan: plan, correlated: true, columns: columns}, nil
+}
+
+// conformColumns は結果セットのカラムのデータ型を columns のデータ型に揃える Executor を返す (変換が不要な場合はそのまま返す)
+//
+// 相関サブクエリでは、外側の値のリテラルはデータ型が文脈で決まるため、外側の値によって結果のデータ型が変わる場合がある (e.g. NULL との演算)
+func conformColumns(result *PlanResult, columns []ColumnMeta) executor.Executor {
+	exprs := make([]executor.Expression, len(result.Columns))
+	changed := false
+	for i, col := range result.Columns {
+		exprs[i] = executor.NewColumnRef(i)
+		from, to := col.metadata(), columns[i].metadata()
+		if !sameStorageType(from, to) {
+			exprs[i] = executor.NewCast(exprs[i], from, to)
+			changed = true
+		}
+	}
+	if !changed {
+		return result.Exec
+	}
+	return executor.NewProjectWithExprs(result.Exec, exprs)
+}
+
+// compileScalarSubquery はスカラーサブクエリをコンパイルする
+func compileScalarSubquery(expr *ast.SubqueryExpr, scope *exprScope) (typedExpr, error) {
+	sq, err := planSubquery(expr.Select, scope)
+	if err != nil {
+		return typedExpr{}, err
+	}
+	if len(sq.columns) != 1 {
+		return typedExpr{}, fmt.Errorf("subquery must return 1 column, but returns %d columns", len(sq.columns))
+	}
+	return typedExpr{expr: executor.NewScalarSubquery(sq.plan, sq.correlated), meta: sq.columns[0].metadata()}, nil
+}
+
+// compileExists は EXISTS をコンパイルする
+func compileExists(expr *ast.ExistsExpr, scope *exprScope) (typedExpr, error) {
+	sq, err := planSubquery(expr.Subquery, scope)
+	if err != nil {
+		return typedExpr{}, err
+	}
+	return typedExpr{expr: executor.NewExists(sq.plan, sq.correlated), meta: bigintMeta()}, nil
+}
+
+// compileInSubquery は IN (SELECT ...) / NOT IN (SELECT ...) をコンパイルする
+//
+// 値とサブクエリの結果のカラムを共通のデータ型に揃えて比較する
+func compileInSubquery(expr *ast.InExpr, scope *exprScope) (typedExpr, error) {
+	operand, err := compileExpr(expr.Operand, scope)
+	if err != nil {
+		return typedExpr{}, err
+	}
+	sq, err := planSubquery(expr.Subquery, scope)
+	if err != nil {
+		return typedExpr{}, err
+	}
+	if len(sq.columns) != 1 {
+		return typedExpr{}, fmt.Errorf("subquery must return 1 column, but returns %d columns", len(sq.columns))
+	}
+	// サブクエリの行の値は、サブクエリの結果のレコードの先頭のカラム
+	row := typedExpr{expr: executor.NewColumnRef(0), meta: sq.columns[0].metadata()}
+	values, err := unifyComparands([]typedExpr{operand, row})
+	if err != nil {
+		return typedExpr{}, err
+	}
+	return typedExpr{
+		expr: executor.NewInSubquery(values[0].expr, sq.plan, values[1].expr, expr.Not, sq.correlated),
+		meta: bigintMeta(),
+	}, nil
+}
+
+// -------------------------------------------------
+// 相関サブクエリ
+// -------------------------------------------------
+
+// correlation はサブクエリ (入れ子のサブクエリを含む) から外側のクエリのカラムへの参照 (外部参照) を解決する
+//
+// サブクエリの FROM 句・JOIN 句のテーブル (入れ子のサブクエリの場合はそれを囲むサブクエリのテーブルも含む) で
+// 解決できないカラムのうち、外側のクエリのスコープで解決できるものを外部参照とする
+type correlation struct {
+	trxId      handler.TrxId
+	outer      *exprScope                                   // 外側のクエリのスコープ
+	tables     map[*ast.SelectStmt][]*handler.TableMetadata // サブクエリごとの FROM 句・JOIN 句のテーブル
+	correlated bool                                         // 外部参照があるか
+}
+
+// newCorrelation はサブクエリの外部参照を解析する
+func newCorrelation(trxId handler.TrxId, stmt *ast.SelectStmt, outer *exprScope) (*correlation, error) {
+	c := &correlation{
+		trxId:  trxId,
+		outer:  outer,
+		tables: map[*ast.SelectStmt][]*handler.TableMetadata{},
+	}
+	_, err := c.rewriteSelect(stmt, nil, func(int) ast.Expr {
+		c.correlated = true
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// bind はサブクエリの外部参照を外側のレコードの値のリテラルに置き換えた SELECT 文を返す
+//
+// record が nil の場合は、外部参照をカラムのデータ型の値 (0 や空文字列) に置き換える (結果のデータ型を決めるため)
+func (c *correlation) bind(stmt *ast.SelectStmt, record executor.Record) (*ast.SelectStmt, error) {
+	return c.rewriteSelect(stmt, nil, func(pos int) ast.Expr {
+		meta := c.outer.columns[pos].colMeta
+		if record == nil {
+			if !meta.Type.IsFixedSize() {
+				return ast.NewStringLiteral("")
+			}
+			return ast.NewStringLiteral(dictionary.FormatValue(meta.Type, meta.Scale, encode.EncodeInt64(0)))
+		}
+		if record[pos] == nil {
+			return ast.NewNullLiteral()
+		}
+		return ast.NewStringLiteral(dictionary.FormatValue(meta.Type, meta.Scale, record[pos]))
+	})
+}
+
+// hasOuterRef は式 (入れ子のサブクエリを含む) が外部参照を含むかどうかを返す
+//
+// chain は式を評価するサブクエリ (とそれを囲むサブクエリ) のテーブル
+func (c *correlation) hasOuterRef(expr ast.Expr, chain [][]*handler.TableMetadata) (bool, error) {
+	found := false
+	_, err := c.rewriteExpr(expr, chain, func(int) ast.Expr {
+		found = true
+		return nil
+	})
+	return found, err
+}
+
+// outerColumnPos はカラムが外部参照の場合に、外側のレコード内の位置を返す
+func (c *correlation) outerColumnPos(col ast.ColumnId, chain [][]*handler.TableMetadata) (int, bool) {
+	for _, tables := range chain {
+		for _, tbl := range tables {
+			if col.TableName != "" && refersToTable(col.TableName, tbl.Name) {
+				return 0, false
+			}
+			if _, ok := tbl.GetColByName(col.ColName); ok && col.TableName == "" {
+				return 0, false
+			}
+		}
+	}
+	pos, err := c.outer.column(col)
+	if err != nil {
+		return 0, false
+	}
+	return pos, true
+}
+
+// tablesOf はサブクエリの FROM 句・JOIN 句のテーブルのメタデータを返す (導出テーブルは計画してメタデータを作成する)
+func (c *correlation) tablesOf(stmt *ast.SelectStmt) ([]*handler.TableMetadata, error) {
+	if tables, ok := c.tables[stmt]; ok {
+		return tables, nil
+	}
+	var tables []*handler.TableMetadata
+	for _, table := range collectTables(stmt) {
+		if table.IsDerived() {
+			derived, err := planDerivedTable(c.trxId, table)
+			if err != nil {
+				return nil, err
+			}
+			tables = append(tables, derived.tblMeta)
+			continue
+		}
+		tblMeta, ok := handler.Get().Catalog.GetTableMetaByName(table.TableName)
+		if !ok {
+			return nil, fmt.Errorf("table %s not found", table.TableName)
+		}
+		tables = append(tables, tblMeta)
+	}
+	c.tables[stmt] = tables
+	return tables, nil
+}
+
+// rewriteSelect は SELECT 文 (入れ子のサブクエリを含む) の外部参照を bind の返す式に置き換えた SELECT 文を返す
+//
+// bind は外部参照のカラムの外側のレコード内の位置を受け取り、nil を返した場合はカラムをそのまま残す
+// 外部参照を置き換えるのは SELECT リスト・WHERE 句・HAVING 句 (ON 句・GROUP BY・ORDER BY の外部参照はサポートしない)
+func (c *correlation) rewriteSelect(stmt *ast.SelectStmt, chain [][]*handler.TableMetadata, bind func(pos int) ast.Expr) (*ast.SelectStmt, error) {
+	tables, err := c.tablesOf(stmt)
+	if err != nil {
+		return nil, err
+	}
+	chain = append(chain[:len(chain):len(chain)], tables)
+
+	rewritten := *stmt
+	if items := stmt.SelectItems(); items != nil {
+		for i, item := range items {
+			if items[i], err = c.rewriteExpr(item, chain, bind); err != nil {
+				return nil, err
+			}
+		}
+		setSelectItems(&rewritten, items)
+	}
+	if stmt.Where != nil {
+		cond, err := c.rewriteExpr(stmt.Where.Condition, chain, bind)
+		if err != nil {
+			return nil, err
+		}
+		rewritten.Where = &ast.WhereClause{Condition: cond}
+	}
+	if stmt.Having != nil {
+		cond, err := c.rewriteExpr(stmt.Having.Condition, chain, bind)
+		if err != nil {
+			return nil, err
+		}
+		rewritten.Having = &ast.HavingClause{Condition: cond}
+	}
+	return &rewritten, nil
+}
+
+// rewriteExpr は式 (入れ子のサブクエリを含む) の外部参照を bind の返す式に置き換えた式を返す
+func (c *correlation) rewriteExpr(expr ast.Expr, chain [][]*handler.TableMetadata, bind func(pos int) ast.Expr) (ast.Expr, error) {
+	rewrite := func(e ast.Expr) (ast.Expr, error) {
+		if e == nil {
+			return nil, nil
+		}
+		return c.rewriteExpr(e, chain, bind)
+	}
+	rewriteAll := func(exprs []ast.Expr) ([]ast.Expr, error) {
+		result := make([]ast.Expr, len(exprs))
+		for i, e := range exprs {
+			r, err := rewrite(e)
+			if err != nil {
+				return nil, err
+			}
+			result[i] = r
+		}
+		return result, nil
+	}
+
+	switch e := expr.(type) {
+	case ast.ColumnId:
+		if pos, ok := c.outerColumnPos(e, chain); ok {
+			if bound := bind(pos); bound != nil {
+				return bound, nil
+			}
+		}
+		return e, nil
+	case *ast.BinaryExpr:
+		operands, err := rewriteAll([]ast.Expr{ast.LeftOperand(e.Left), ast.RightOperand(e.Right)})
+		if err != nil {
+			return nil, err
+		}
+		return ast.NewBinaryExpr(e.Operator, conditionLHS(operands[0]), conditionRHS(operands[1])), nil
+	case *ast.UnaryExpr:
+		operand, err := rewrite(e.Operand)
+		if err != nil {
+			return nil, err
+		}
+		return ast.NewUnaryExpr(e.Operator, operand), nil
+	case *ast.InExpr:
+		operand, err := rewrite(e.Operand)
+		if err != nil {
+			return nil, err
+		}
+		if e.Subquery != nil {
+			sub, err := c.rewriteSelect(e.Subquery, chain, bind)
+			if err != nil {
+				return nil, err
+			}
+			return ast.NewInSubqueryExpr(operand, sub, e.Not), nil
+		}
+		values, err := rewriteAll(e.Values)
+		if err != nil {
+			return nil, err
+		}
+		return ast.NewInExpr(operand, values, e.Not), nil
+	case *ast.BetweenExpr:
+		operands, err := rewriteAll([]ast.Expr{e.Operand, e.Lower, e.Upper})
+		if err != nil {
+			return nil, err
+		}
+		return ast.NewBetweenExpr(operands[0], operands[1], operands[2], e.Not), nil
+	case *ast.FuncCallExpr:
+		args, err := rewriteAll(e.Args)
+		if err != nil {
+			return nil, err
+		}
+		return ast.NewFuncCallExpr(e.Name, args), nil
+	case *ast.CaseExpr:
+		operand, err := rewrite(e.Operand)
+		if err != nil {
+			return nil, err
+		}
+		whens := make([]*ast.WhenClause, len(e.Whens))
+		for i, when := range e.Whens {
+			parts, err := rewriteAll([]ast.Expr{when.Condition, when.Result})
+			if err != nil {
+				return nil, err
+			}
+			whens[i] = &ast.WhenClause{Condition: parts[0], Result: parts[1]}
+		}
+		elseExpr, err := rewrite(e.Else)
+		if err != nil {
+			return nil, err
+		}
+		return &ast.CaseExpr{Operand: operand, Whens: whens, Else: elseExpr}, nil
+	case *ast.SubqueryExpr:
+		sub, err := c.rewriteSelect(e.Select, chain, bind)
+		if err != nil {
+			return nil, err
+		}
+		return ast.NewSubqueryExpr(sub), nil
+	case *ast.ExistsExpr:
+		sub, err := c.rewriteSelect(e.Subquery, chain, bind)
+		if err != nil {
+			return nil, err
+		}
+		return ast.NewExistsExpr(sub), nil
+	default:
+		// リテラル・集約関数 (集約関数の引数の外部参照はサポートしない)
+		return expr, nil
+	}
+}
+
+// setSelectItems は SELECT リストの項目を、種類ごと (カラム・集約関数・式) に SELECT 文に設定する
+func setSelectItems(stmt *ast.SelectStmt, items []ast.Expr) {
+	stmt.Columns, stmt.Aggregates, stmt.Exprs = nil, nil, nil
+	for i, item := range items {
+		switch v := item.(type) {
+		case ast.ColumnId:
+			stmt.Columns = append(stmt.Columns, v)
+		case *ast.AggregateExpr:
+			agg := *v
+			agg.Pos = i
+			stmt.Aggregates = append(stmt.Aggregates, &agg)
+		default:
+			stmt.Exprs = append(stmt.Exprs, &ast.SelectExpr{Expr: item, Pos: i})
+		}
+	}
+}
+
+// -------------------------------------------------
+// WHERE 句のサブクエリ
+// -------------------------------------------------
+
+// splitSubqueryConditions は WHERE 句を AND で分解し、サブクエリを含まない条件とサブクエリを含む条件に分離する
+func splitSubqueryConditions(where *ast.WhereClause) (*ast.WhereClause, []ast.Expr) {
+	if where == nil {
+		return nil, nil
+	}
+	var plain, subqueries []ast.Expr
+	for _, cond := range splitAND(where.Condition) {
+		if ast.ContainsSubquery(cond) {
+			subqueries = append(subqueries, cond)
+		} else {
+			plain = append(plain, cond)
+		}
+	}
+	if len(plain) == 0 {
+		return nil, subqueries
+	}
+	return &ast.WhereClause{Condition: combineExprsWithAND(plain)}, subqueries
+}
+
+// splitAND は条件式を AND で分解する
+func splitAND(expr ast.Expr) []ast.Expr {
+	if binExpr, ok := expr.(*ast.BinaryExpr); ok && binExpr.Operator == "AND" {
+		return append(splitAND(ast.LeftOperand(binExpr.Left)), splitAND(ast.RightOperand(binExpr.Right))...)
+	}
+	return []ast.Expr{expr}
+}
+
+// planSubqueryConditions は WHERE 句のサブクエリを含む条件を計画する
+//
+// exec は WHERE 句のサブクエリを含まない条件まで適用済みの Executor で、columns はそのレコードのカラム
+// 単純な相関サブクエリの EXISTS / NOT EXISTS / IN は準結合・反結合 (HashJoin) に変換し、それ以外は Filter で評価する
+// 準結合・反結合に変換した場合は exec の並び順を保たないため、sortOrder は nil になる
+func planSubqueryConditions(trxId handler.TrxId, exec executor.Executor, conds []ast.Expr, columns []joinedColumn, sortOrder []int) (executor.Executor, []int, error) {
+	if len(conds) == 0 {
+		return exec, sortOrder, nil
+	}
+	scope := newExprScope(columns).withSubqueries(trxId)
+
+	var filters []ast.Expr
+	for _, cond := range conds {
+		semi, err := decorrelate(trxId, cond, scope)
+		if err != nil {
+			return nil, nil, err
+		}
+		if semi == nil {
+			filters = append(filters, cond)
+			continue
+		}
+		joinType := executor.HashJoinSemi
+		if semi.anti {
+			joinType = executor.HashJoinAnti
+		}
+		exec = executor.NewHashJoinWithParams(executor.HashJoinParams{
+			ProbeExecutor: exec,
+			BuildExecutor: semi.inner,
+			ProbeKeys:     semi.probeKeys,
+			BuildKeys:     semi.buildKeys,
+			Type:          joinType,
+			BufferSize:    config.GetJoinBufferSize(),
+			TmpDir:        config.GetDataDirectory(),
+		})
+		sortOrder = nil
+	}
+
+	if len(filters) > 0 {
+		cond, err := compileCondition(combineExprsWithAND(filters), scope)
+		if err != nil {
+			return nil, nil, err
+		}
+		exec = executor.NewFilterWithExpression(exec, cond)
+	}
+	return exec, sortOrder, nil
+}
+
+// semiJoin は準結合・反結合に変換した相関サブクエリ
+type semiJoin struct {
+	inner     executor.Executor // サブクエリの行のうち、結合キーのカラムのみを返す Executor
+	probeKeys []int             // 外側のレコードの結合キーの位置
+	buildKeys []int             // inner のレコードの結合キーの位置
+	anti      bool              // true なら反結合 (NOT EXISTS)
+}
+
+// decorrelate は WHERE 句の条件 (AND で分解した 1 つの条件) を準結合・反結合に変換する
+//
+// 変換できるのは以下の条件 (変換できない場合は nil を返す):
+//   - EXISTS (subquery): 準結合
+//   - NOT EXISTS (subquery): 反結合
+//   - col IN (subquery): col とサブクエリの SELECT リストの値を結合キーに加えた準結合 (NOT IN は NULL の扱いが異なるため対象外)
+//
+// サブクエリは集約・LIMIT を含まず、外部参照は WHERE 句の AND で分解した `内側のカラム = 外側のカラム` の形の条件にのみ含む必要がある
+// この条件を結合キーに変換し、残りの条件で絞り込んだサブクエリの行とハッシュ結合する
+func decorrelate(trxId handler.TrxId, cond ast.Expr, scope *exprScope) (*semiJoin, error) {
+	var stmt *ast.SelectStmt
+	var operand *ast.ColumnId
+	anti := false
+	switch e := cond.(type) {
+	case *ast.ExistsExpr:
+		stmt = e.Subquery
+	case *ast.UnaryExpr:
+		exists, ok := e.Operand.(*ast.ExistsExpr)
+		if !ok || e.Operator != "NOT" {
+			return nil, nil
+		}
+		stmt, anti = exists.Subquery, true
+	case *ast.InExpr:
+		col, ok := e.Operand.(ast.ColumnId)
+		if !ok || e.Subquery == nil || e.Not {
+			return nil, nil
+		}
+		stmt, operand = e.Subquery, &col
+	default:
+		return nil, nil
+	}
+	if stmt.Where == nil || stmt.HasAggregation() || stmt.Limit != nil {
+		return nil, nil
+	}
+
+	corr, err := newCorrelation(trxId, stmt, scope)
+	if err != nil || !corr.correlated {
+		return nil, err
+	}
+	chain := [][]*handler.TableMetadata{corr.tables[stmt]}
+
+	// SELECT リストの値 (IN の場合) と、相関条件の内側のカラムを結合キーにする
+	var keys []ast.Expr
+	var probeKeys []int
+	if operand != nil {
+		items := stmt.SelectItems()
+		if len(items) != 1 {
+			return nil, nil
+		}
+		if found, err := corr.hasOuterRef(items[0], chain); err != nil || found {
+			return nil, err
+		}
+		pos, err := scope.column(*operand)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, items[0])
+		probeKeys = append(probeKeys, pos)
+	}
+	var remaining []ast.Expr
+	for _, conjunct := range splitAND(stmt.Where.Condition) {
+		found, err := corr.hasOuterRef(conjunct, chain)
+		if err != nil {
+			return nil, err
+		}
+		if !found {
+			remaining = append(remaining, conjunct)
+			continue
+		}
+		innerCol, outerPos, ok := corr.correlatedEquality(conjunct, chain)
+		if !ok {
+			return nil, nil
+		}
+		keys = append(keys, innerCol)
+		probeKeys = append(probeKeys, outerPos)
+	}
+
+	inner := *stmt
+	inner.Where = nil
+	if len(remaining) > 0 {
+		inner.Where = &ast.WhereClause{Condition: combineExprsWithAND(remaining)}
+	}
+	inner.OrderBy = nil
+	inner.Aliases = nil
+	setSelectItems(&inner, keys)
+	result, err := PlanSelect(trxId, &inner)
+	if err != nil {
+		return nil, err
+	}
+
+	// 結合キーは格納形式のまま比較するため、格納形式が異なるデータ型同士の場合は変換しない
+	buildKeys := make([]int, len(keys))
+	for i, pos := range probeKeys {
+		outerMeta, innerMeta := scope.columns[pos].colMeta, result.Columns[i].metadata()
+		if !sameStorageType(outerMeta, innerMeta) && !sameStorageType(innerMeta, outerMeta) {
+			return nil, nil
+		}
+		buildKeys[i] = i
+	}
+	return &semiJoin{inner: result.Exec, probeKeys: probeKeys, buildKeys: buildKeys, anti: anti}, nil
+}
+
+// correlatedEquality は条件が `内側のカラム = 外側のカラム` (左右は逆でもよい) の場合に、内側のカラムと外側のカラムの位置を返す
+func (c *correlation) correlatedEquality(cond ast.Expr, chain [][]*handler.TableMetadata) (ast.ColumnId, int, bool) {
+	binExpr, ok := cond.(*ast.BinaryExpr)
+	if !ok || binExpr.Operator != "=" {
+		return ast.ColumnId{}, 0, false
+	}
+	left, lOk := ast.LeftOperand(binExpr.Left).(ast.ColumnId)
+	right, rOk := ast.RightOperand(binExpr.Right).(ast.ColumnId)
+	if !lOk || !rOk {
+		return ast.ColumnId{}, 0, false
+	}
+	leftPos, leftOuter := c.outerColumnPos(left, chain)
+	rightPos, rightOuter := c.outerColumnPos(right, chain)
+	switch {
+	case leftOuter && !rightOuter:
+		return right, leftPos, true
+	case rightOuter && !leftOuter:
+		return left, rightPos, true
+	}
+	return ast.ColumnId{}, 0, false
+}
+
+// -------------------------------------------------
+// 導出テーブル
+// -------------------------------------------------
+
+// derivedTable は計画した導出テーブル (FROM 句・JOIN 句のサブクエリ)
+type derivedTable struct {
+	tblMeta *handler.TableMetadata   // 別名をテーブル名、サブクエリの結果のカラムをカラムとするメタデータ (PK・インデックスなし)
+	stats   *handler.TableStatistics // 推定した行数のみを持つ統計情報
+	exec    executor.Executor
+}
+
+// planDerivedTable は導出テーブルのサブクエリ (共通テーブル式の場合は共通テーブル式の問い合わせ) を計画する
+func planDerivedTable(trxId handler.TrxId, table ast.TableId) (*derivedTable, error) {
+	var result *PlanResult
+	var err error
+	if table.CTE != nil {
+		result, err = planCTE(trxId, table.CTE)
+	} else {
+		result, err = PlanSelect(trxId, table.Subquery)
+	}
+	if err != nil {
+		return nil, err
+	}
+	tblMeta, err := derivedTableMetadata(table.TableName, result.Columns)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := estimateDerivedTableRows(table)
+	if err != nil {
+		return nil, err
+	}
+	return &derivedTable{
+		tblMeta: tblMeta,
+		stats:   &handler.TableStatistics{RecordCount: rows},
+		exec:    result.Exec,
+	}, nil
+}
+
+// derivedTableMetadata は問い合わせの結果のカラムをカラムとするテーブルのメタデータ (PK・インデックスなし) を作成する
+func derivedTableMetadata(name string, columns []ColumnMeta) (*handler.TableMetadata, error) {
+	if len(columns) > int(^uint8(0)) {
+		return nil, fmt.Errorf("too many columns in derived table %s", name)
+	}
+
+	cols := make([]*handler.ColumnMetadata, len(columns))
+	names := make(map[string]struct{}, len(columns))
+	for i, col := range columns {
+		if _, dup := names[col.ColName]; dup {
+			return nil, fmt.Errorf("duplicate column name %s in derived table %s", col.ColName, name)
+		}
+		names[col.ColName] = struct{}{}
+		meta := col.metadata()
+		meta.Pos = uint16(i)
+		cols[i] = meta
+	}
+	return &handler.TableMetadata{Name: name, NCols: uint8(len(cols)), Cols: cols}, nil
+}
+
+// estimateDerivedTableRows は導出テーブル・共通テーブル式の行数を推定する
+func estimateDerivedTableRows(table ast.TableId) (uint64, error) {
+	if table.CTE != nil {
+		return estimateCTERows(table.CTE)
+	}
+	return estimateDerivedRows(table.Subquery)
+}
+
+// estimateDerivedRows は導出テーブルの行数を推定する
+//
+//   - GROUP BY のない集約: 1 行
+//   - それ以外: FROM 句のテーブルの行数 (WHERE 句・JOIN による増減は考慮しない)
+//
+// LIMIT がある場合は LIMIT の件数を上限とする
+func estimateDerivedRows(stmt *ast.SelectStmt) (uint64, error) {
+	var rows uint64
+	switch {
+	case stmt.HasAggregation() && len(stmt.GroupBy) == 0:
+		rows = 1
+	case stmt.From.TableName == "":
+		// FROM 句のない SELECT 文は 1 行を返す
+		rows = 1
+	case stmt.From.IsDerived():
+		r, err := estimateDerivedTableRows(stmt.From)
+		if err != nil {
+			return 0, err
+		}
+		rows = r
+	default:
+		hdl := handler.Get()
+		tblMeta, ok := hdl.Catalog.GetTableMetaByName(stmt.From.TableName)
+		if !ok {
+			return 0, fmt.Errorf("table %s not found", stmt.From.TableName)
+		}
+		stats, err := hdl.AnalyzeTable(tblMeta)
+		if err != nil {
+			return 0, err
+		}
+		rows = stats.RecordCount
+	}
+	if stmt.Limit != nil {
+		rows = min(rows, stmt.Limit.Count)
+	}
+	return rows, nil
+}
+
+// hasSubquery は SELECT 文の SELECT リスト・WHERE 句・HAVING 句にサブクエリが含まれるかどうかを返す
+func hasSubquery(stmt *ast.SelectStmt) bool {
+	for _, item := range stmt.SelectItems() {
+		if ast.ContainsSubquery(item) {
+			return true
+		}
+	}
+	return (stmt.Where != nil && ast.ContainsSubquery(stmt.Where.Condition)) ||
+		(stmt.Having != nil && ast.ContainsSubquery(stmt.Having.Condition))
+}
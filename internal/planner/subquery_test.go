@@ -0,0 +1,296 @@
+package planner
+
+import (
+	"testing"
+
+	"github.com/ren-yamanashi/minesql/internal/ast"
+	"github.com/ren-yamanashi/minesql/internal/executor"
+	"github.com/ren-yamanashi/minesql/internal/storage/handler"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ストレージを初期化し、users テーブル (setupUsersTable) と logins テーブルを作成してデータを投入する
+//
+// テーブル: logins (id PK, username)
+// データ: ("1","johndoe"), ("2","johndoe"), ("3","tombrown")
+func setupLoginsTable(t *testing.T) {
+	t.Helper()
+	setupUsersTable(t)
+
+	executePlan(t, &ast.CreateTableStmt{
+		TableName: "logins",
+		CreateDefinitions: []ast.Definition{
+			&ast.ColumnDef{ColName: "id", DataType: ast.DataTypeVarchar},
+			&ast.ColumnDef{ColName: "username", DataType: ast.DataTypeVarchar},
+			&ast.ConstraintPrimaryKeyDef{Columns: []ast.ColumnId{*ast.NewColumnId("id")}},
+		},
+	})
+	executePlan(t, &ast.InsertStmt{
+		Table: *ast.NewTableId("logins"),
+		Cols:  []ast.ColumnId{*ast.NewColumnId("id"), *ast.NewColumnId("username")},
+		Values: [][]ast.Literal{
+			{ast.NewStringLiteral("1"), ast.NewStringLiteral("johndoe")},
+			{ast.NewStringLiteral("2"), ast.NewStringLiteral("johndoe")},
+			{ast.NewStringLiteral("3"), ast.NewStringLiteral("tombrown")},
+		},
+	})
+}
+
+// usersScope は users テーブルのレコードに対するサブクエリを使用できるスコープを返す
+func usersScope(t *testing.T) *exprScope {
+	t.Helper()
+	usersMeta, ok := handler.Get().Catalog.GetTableMetaByName("users")
+	require.True(t, ok)
+	return newExprScope(resolveJoinedColumns([]*handler.TableMetadata{usersMeta})).withSubqueries(0)
+}
+
+// loginsOfUser は `SELECT * FROM logins WHERE logins.username = users.username [AND extra]` を返す
+func loginsOfUser(extra ast.Expr) *ast.SelectStmt {
+	var cond ast.Expr = ast.NewBinaryExpr("=",
+		ast.NewLhsColumn(ast.ColumnId{TableName: "logins", ColName: "username"}),
+		ast.NewRhsColumn(ast.ColumnId{TableName: "users", ColName: "username"}),
+	)
+	if extra != nil {
+		cond = combineExprsWithAND([]ast.Expr{cond, extra})
+	}
+	return &ast.SelectStmt{From: *ast.NewTableId("logins"), Where: &ast.WhereClause{Condition: cond}}
+}
+
+func TestSplitSubqueryConditions(t *testing.T) {
+	t.Run("AND で分解した条件のうちサブクエリを含む条件を分離する", func(t *testing.T) {
+		// GIVEN: gender = 'male' AND EXISTS (...) AND (id = '1' OR id IN (SELECT ...))
+		exists := ast.NewExistsExpr(&ast.SelectStmt{From: *ast.NewTableId("logins")})
+		or := ast.NewBinaryExpr("OR",
+			ast.NewLhsExpr(ast.NewBinaryExpr("=", ast.NewLhsColumn(*ast.NewColumnId("id")), ast.NewRhsLiteral(ast.NewStringLiteral("1")))),
+			ast.NewInSubqueryExpr(*ast.NewColumnId("id"), &ast.SelectStmt{From: *ast.NewTableId("logins")}, false),
+		)
+		gender := ast.NewBinaryExpr("=", ast.NewLhsColumn(*ast.NewColumnId("gender")), ast.NewRhsLiteral(ast.NewStringLiteral("male")))
+		where := &ast.WhereClause{Condition: combineExprsWithAND([]ast.Expr{gender, exists, or})}
+
+		// WHEN
+		plain, subqueries := splitSubqueryConditions(where)
+
+		// THEN
+		require.NotNil(t, plain)
+		assert.Equal(t, gender, plain.Condition)
+		assert.Equal(t, []ast.Expr{exists, or}, subqueries)
+	})
+
+	t.Run("すべての条件がサブクエリを含む場合、サブクエリを含まない条件は nil になる", func(t *testing.T) {
+		// GIVEN
+		exists := ast.NewExistsExpr(&ast.SelectStmt{From: *ast.NewTableId("logins")})
+
+		// WHEN
+		plain, subqueries := splitSubqueryConditions(&ast.WhereClause{Condition: exists})
+
+		// THEN
+		assert.Nil(t, plain)
+		assert.Equal(t, []ast.Expr{exists}, subqueries)
+	})
+}
+
+func TestDecorrelate(t *testing.T) {
+	t.Run("相関条件が等値条件の EXISTS は準結合に変換され、サブクエリ側は結合キーのみを返す", func(t *testing.T) {
+		// GIVEN
+		setupLoginsTable(t)
+		defer handler.Reset()
+		scope := usersScope(t)
+
+		// WHEN
+		semi, err := decorrelate(0, ast.NewExistsExpr(loginsOfUser(nil)), scope)
+
+		// THEN: 結合キーは users.username (位置 4) と logins.username
+		require.NoError(t, err)
+		require.NotNil(t, semi)
+		assert.False(t, semi.anti)
+		assert.Equal(t, []int{4}, semi.probeKeys)
+		assert.Equal(t, []int{0}, semi.buildKeys)
+		assert.Equal(t, []executor.Record{
+			{[]byte("johndoe")},
+			{[]byte("johndoe")},
+			{[]byte("tombrown")},
+		}, fetchAll(t, semi.inner))
+	})
+
+	t.Run("NOT EXISTS は反結合に変換される", func(t *testing.T) {
+		// GIVEN
+		setupLoginsTable(t)
+		defer handler.Reset()
+		scope := usersScope(t)
+
+		// WHEN
+		semi, err := decorrelate(0, ast.NewUnaryExpr("NOT", ast.NewExistsExpr(loginsOfUser(nil))), scope)
+
+		// THEN
+		require.NoError(t, err)
+		require.NotNil(t, semi)
+		assert.True(t, semi.anti)
+	})
+
+	t.Run("IN はサブクエリの SELECT リストの値を先頭の結合キーにする", func(t *testing.T) {
+		// GIVEN: id IN (SELECT id FROM logins WHERE logins.username = users.username)
+		setupLoginsTable(t)
+		defer handler.Reset()
+		scope := usersScope(t)
+		sub := loginsOfUser(nil)
+		sub.Columns = []ast.ColumnId{*ast.NewColumnId("id")}
+
+		// WHEN
+		semi, err := decorrelate(0, ast.NewInSubqueryExpr(*ast.NewColumnId("id"), sub, false), scope)
+
+		// THEN
+		require.NoError(t, err)
+		require.NotNil(t, semi)
+		assert.Equal(t, []int{0, 4}, semi.probeKeys)
+		assert.Equal(t, []int{0, 1}, semi.buildKeys)
+	})
+
+	t.Run("等値条件以外で外側のカラムを参照する場合は変換しない", func(t *testing.T) {
+		// GIVEN: ... AND logins.id > users.id
+		setupLoginsTable(t)
+		defer handler.Reset()
+		scope := usersScope(t)
+		extra := ast.NewBinaryExpr(">",
+			ast.NewLhsColumn(ast.ColumnId{TableName: "logins", ColName: "id"}),
+			ast.NewRhsColumn(ast.ColumnId{TableName: "users", ColName: "id"}),
+		)
+
+		// WHEN
+		semi, err := decorrelate(0, ast.NewExistsExpr(loginsOfUser(extra)), scope)
+
+		// THEN
+		require.NoError(t, err)
+		assert.Nil(t, semi)
+	})
+
+	t.Run("相関のないサブクエリと NOT IN は変換しない", func(t *testing.T) {
+		// GIVEN
+		setupLoginsTable(t)
+		defer handler.Reset()
+		scope := usersScope(t)
+		uncorrelated := &ast.SelectStmt{
+			From: *ast.NewTableId("logins"),
+			Where: &ast.WhereClause{Condition: ast.NewBinaryExpr("=",
+				ast.NewLhsColumn(*ast.NewColumnId("id")), ast.NewRhsLiteral(ast.NewStringLiteral("1")),
+			)},
+		}
+		notIn := loginsOfUser(nil)
+		notIn.Columns = []ast.ColumnId{*ast.NewColumnId("id")}
+
+		// WHEN
+		semi1, err1 := decorrelate(0, ast.NewExistsExpr(uncorrelated), scope)
+		semi2, err2 := decorrelate(0, ast.NewInSubqueryExpr(*ast.NewColumnId("id"), notIn, true), scope)
+
+		// THEN
+		require.NoError(t, err1)
+		require.NoError(t, err2)
+		assert.Nil(t, semi1)
+		assert.Nil(t, semi2)
+	})
+}
+
+func TestPlanSubquery(t *testing.T) {
+	t.Run("相関サブクエリは外側のレコードの値を使って実行される", func(t *testing.T) {
+		// GIVEN: (SELECT COUNT(*) FROM logins WHERE logins.username = users.username)
+		setupLoginsTable(t)
+		defer handler.Reset()
+		scope := usersScope(t)
+		sub := loginsOfUser(nil)
+		sub.Aggregates = []*ast.AggregateExpr{ast.NewAggregateExpr(ast.AggregateCount, nil)}
+
+		// WHEN
+		te, err := compileExpr(ast.NewSubqueryExpr(sub), scope)
+		require.NoError(t, err)
+		johndoe, err := te.expr.Eval(executor.Record{[]byte("1"), nil, nil, nil, []byte("johndoe")})
+		require.NoError(t, err)
+		janedoe, err := te.expr.Eval(executor.Record{[]byte("4"), nil, nil, nil, []byte("janedoe")})
+		require.NoError(t, err)
+
+		// THEN
+		assert.Equal(t, handler.ColumnTypeBigInt, te.meta.Type)
+		assert.Equal(t, "2", formatTyped(te, johndoe))
+		assert.Equal(t, "0", formatTyped(te, janedoe))
+	})
+
+	t.Run("サブクエリを使用できないスコープではエラーになる", func(t *testing.T) {
+		// GIVEN
+		setupLoginsTable(t)
+		defer handler.Reset()
+		usersMeta, ok := handler.Get().Catalog.GetTableMetaByName("users")
+		require.True(t, ok)
+
+		// WHEN
+		_, err := compileExpr(ast.NewExistsExpr(loginsOfUser(nil)), newTableExprScope(usersMeta))
+
+		// THEN
+		assert.ErrorContains(t, err, "subquery is not supported in this statement")
+	})
+
+	t.Run("スカラーサブクエリが 2 カラム以上を返す場合はエラーになる", func(t *testing.T) {
+		// GIVEN
+		setupLoginsTable(t)
+		defer handler.Reset()
+		scope := usersScope(t)
+
+		// WHEN
+		_, err := compileExpr(ast.NewSubqueryExpr(&ast.SelectStmt{From: *ast.NewTableId("logins")}), scope)
+
+		// THEN
+		assert.ErrorContains(t, err, "subquery must return 1 column, but returns 2 columns")
+	})
+}
+
+func TestPlanDerivedTable(t *testing.T) {
+	t.Run("サブクエリの結果のカラムをカラムとし、推定行数を統計情報に持つ", func(t *testing.T) {
+		// GIVEN: (SELECT username, COUNT(*) FROM logins GROUP BY username LIMIT 1) AS t
+		setupLoginsTable(t)
+		defer handler.Reset()
+		table := ast.TableId{TableName: "t", Subquery: &ast.SelectStmt{
+			Columns:    []ast.ColumnId{*ast.NewColumnId("username")},
+			Aggregates: []*ast.AggregateExpr{{Func: ast.AggregateCount, Pos: 1}},
+			From:       *ast.NewTableId("logins"),
+			GroupBy:    []ast.ColumnId{*ast.NewColumnId("username")},
+			Limit:      &ast.LimitClause{Count: 1},
+		}}
+
+		// WHEN
+		derived, err := planDerivedTable(0, table)
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, "t", derived.tblMeta.Name)
+		assert.Equal(t, uint8(2), derived.tblMeta.NCols)
+		assert.Equal(t, "username", derived.tblMeta.Cols[0].Name)
+		assert.Equal(t, "COUNT(*)", derived.tblMeta.Cols[1].Name)
+		assert.Equal(t, uint16(1), derived.tblMeta.Cols[1].Pos)
+		assert.Equal(t, uint64(1), derived.stats.RecordCount)
+	})
+
+	t.Run("カラム名が重複する場合はエラーになる", func(t *testing.T) {
+		// GIVEN: (SELECT * FROM users JOIN logins ON users.username = logins.username) AS t
+		setupLoginsTable(t)
+		defer handler.Reset()
+		table := ast.TableId{TableName: "t", Subquery: &ast.SelectStmt{
+			From: *ast.NewTableId("users"),
+			Joins: []*ast.JoinClause{{
+				Table: *ast.NewTableId("logins"),
+				Condition: ast.NewBinaryExpr("=",
+					ast.NewLhsColumn(ast.ColumnId{TableName: "users", ColName: "username"}),
+					ast.NewRhsColumn(ast.ColumnId{TableName: "logins", ColName: "username"}),
+				),
+			}},
+		}}
+
+		// WHEN
+		_, err := planDerivedTable(0, table)
+
+		// THEN
+		assert.ErrorContains(t, err, "duplicate column name id in derived table t")
+	})
+}
+
+// formatTyped は式の結果の値をテキスト表現に変換する
+func formatTyped(te typedExpr, value []byte) string {
+	return ColumnMeta{Type: te.meta.Type, Scale: te.meta.Scale}.FormatValue(value)
+}
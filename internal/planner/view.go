@@ -0,0 +1,422 @@
+package planner
+
+import (
+	"fmt"
+
+	"github.com/ren-yamanashi/minesql/internal/ast"
+	"github.com/ren-yamanashi/minesql/internal/executor"
+	"github.com/ren-yamanashi/minesql/internal/parser"
+	"github.com/ren-yamanashi/minesql/internal/storage/dictionary"
+	"github.com/ren-yamanashi/minesql/internal/storage/handler"
+)
+
+// PlanCreateView は CREATE VIEW 文のバリデーションを行い、CreateView executor を構築する
+//
+// ビューの問い合わせを計画して、参照するテーブル・カラムが存在すること、ビューが自身を参照しないことを検証する
+func PlanCreateView(trxId handler.TrxId, stmt *ast.CreateViewStmt) (executor.Executor, error) {
+	hdl := handler.Get()
+
+	if _, ok := hdl.Catalog.GetTableMetaByName(stmt.ViewName); ok {
+		return nil, fmt.Errorf("table %s already exists", stmt.ViewName)
+	}
+	if _, ok := hdl.Catalog.GetViewByName(stmt.ViewName); ok && !stmt.OrReplace {
+		return nil, fmt.Errorf("view %s already exists", stmt.ViewName)
+	}
+
+	// ビューの問い合わせを計画する (作成するビュー自身への参照は、同じ名前の既存のビューを展開せずにエラーにする)
+	if err := bindViews(stmt.Query, map[string]bool{stmt.ViewName: true}); err != nil {
+		return nil, err
+	}
+	var result *PlanResult
+	var err error
+	switch q := stmt.Query.(type) {
+	case *ast.SelectStmt:
+		result, err = PlanSelect(trxId, q)
+	case *ast.SetOperationStmt:
+		result, err = PlanSetOperation(trxId, q)
+	default:
+		return nil, fmt.Errorf("unsupported view query: %T", q)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// ビューのカラム名は重複できない
+	names := stmt.Columns
+	if names == nil {
+		for _, col := range result.Columns {
+			names = append(names, col.ColName)
+		}
+	} else if len(names) != len(result.Columns) {
+		return nil, fmt.Errorf("the number of columns of view %s does not match its column list", stmt.ViewName)
+	}
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		if seen[name] {
+			return nil, fmt.Errorf("duplicate column name %s in view %s", name, stmt.ViewName)
+		}
+		seen[name] = true
+	}
+
+	return executor.NewCreateView(stmt.ViewName, stmt.Columns, stmt.Definition, stmt.OrReplace), nil
+}
+
+// PlanDropView は DROP VIEW 文のバリデーションを行い、DropView executor を構築する
+func PlanDropView(stmt *ast.DropViewStmt) (executor.Executor, error) {
+	hdl := handler.Get()
+
+	// IF EXISTS が指定されていない場合、対象ビューがカタログに存在するか検証
+	if _, ok := hdl.Catalog.GetViewByName(stmt.ViewName); !ok && !stmt.IfExists {
+		return nil, fmt.Errorf("view %s not found", stmt.ViewName)
+	}
+
+	return executor.NewDropView(stmt.ViewName, stmt.IfExists), nil
+}
+
+// bindViews はビューへの参照を解決する
+//
+// 文 (入れ子の集合演算・導出テーブル・サブクエリ・WITH 句の共通テーブル式を含む) の FROM 句・JOIN 句のテーブルのうち、
+// ビューと同じ名前のテーブルに、ビューの問い合わせを共通テーブル式として設定する
+//   - ビューの定義は参照ごとにパースし、ビューの問い合わせが参照するビューも再帰的に解決する
+//   - 共通テーブル式への参照 (bindWith で解決済み) は同じ名前のビューより優先する
+//   - visiting は解決中のビューの名前で、ビューが自身を (他のビューを介して) 参照する場合はエラーになる
+func bindViews(stmt ast.Statement, visiting map[string]bool) error {
+	if with := withClause(stmt); with != nil {
+		for _, cte := range with.CTEs {
+			if err := bindViews(cte.Query, visiting); err != nil {
+				return err
+			}
+		}
+	}
+
+	hdl := handler.Get()
+	var err error
+	walkTables(stmt, func(table *ast.TableId) {
+		if err != nil || table.IsDerived() {
+			return
+		}
+		view, ok := hdl.Catalog.GetViewByName(table.TableName)
+		if !ok {
+			return
+		}
+		var query ast.Statement
+		if query, err = expandView(view, visiting); err != nil {
+			return
+		}
+		table.CTE = &ast.CommonTableExpr{Name: view.Name, Columns: view.Columns, Query: query}
+	})
+	return err
+}
+
+// expandView はビューの定義をパースし、問い合わせの共通テーブル式・ビューへの参照を解決した問い合わせを返す
+func expandView(view *handler.ViewMetadata, visiting map[string]bool) (ast.Statement, error) {
+	if visiting[view.Name] {
+		return nil, fmt.Errorf("view %s references itself", view.Name)
+	}
+	query, err := parseViewDefinition(view)
+	if err != nil {
+		return nil, err
+	}
+	if err := bindWith(withClause(query), query); err != nil {
+		return nil, err
+	}
+	if err := bindViews(query, visitView(visiting, view.Name)); err != nil {
+		return nil, err
+	}
+	return query, nil
+}
+
+// parseViewDefinition はビューの定義 (問い合わせの SQL 表現) をパースする
+//
+// 問い合わせのテーブル名は、ビューが属するデータベースの名前で修飾する
+func parseViewDefinition(view *handler.ViewMetadata) (ast.Statement, error) {
+	stmt, err := parser.NewParser().Parse(view.Definition + ";")
+	if err != nil {
+		return nil, fmt.Errorf("invalid definition of view %s: %w", view.Name, err)
+	}
+	switch stmt.(type) {
+	case *ast.SelectStmt, *ast.SetOperationStmt:
+		database, _ := dictionary.SplitName(view.Name)
+		BindDatabase(stmt, database)
+		return stmt, nil
+	default:
+		return nil, fmt.Errorf("invalid definition of view %s: %T", view.Name, stmt)
+	}
+}
+
+// visitView は visiting にビューの名前を追加した新しいマップを返す
+func visitView(visiting map[string]bool, name string) map[string]bool {
+	visited := make(map[string]bool, len(visiting)+1)
+	for k, v := range visiting {
+		visited[k] = v
+	}
+	visited[name] = true
+	return visited
+}
+
+// withClause は SELECT 文・集合演算の WITH 句を返す (WITH 句がない場合は nil)
+func withClause(stmt ast.Statement) *ast.WithClause {
+	switch s := stmt.(type) {
+	case *ast.SelectStmt:
+		return s.With
+	case *ast.SetOperationStmt:
+		return s.With
+	default:
+		return nil
+	}
+}
+
+// -------------------------------------------------
+// 更新可能なビュー
+// -------------------------------------------------
+
+// updatableView は更新可能なビューを、ビューが参照するテーブルに対する式に変換するための情報
+type updatableView struct {
+	name    string
+	table   ast.TableId  // ビューが (他のビューを介して) 参照するテーブル
+	columns []viewColumn // ビューのカラム (SELECT リストの順)
+	where   ast.Expr     // ビューの WHERE 句の条件 (nil なら条件なし)
+}
+
+// viewColumn は更新可能なビューのカラム
+type viewColumn struct {
+	name string
+	expr ast.Expr // テーブルのカラムに対する式
+}
+
+// resolveUpdatableView はビューを更新可能なビューとして解決する
+//
+// 更新可能なビューは 1 つのテーブル (または更新可能なビュー) を参照する SELECT 文で、
+// WITH 句・DISTINCT・JOIN 句・導出テーブル・集約・ウィンドウ関数・LIMIT を含まないもの
+// ビューが更新可能なビューを参照する場合は、参照先のビューの定義で置き換えて 1 つのテーブルに対する定義にする
+func resolveUpdatableView(view *handler.ViewMetadata, visiting map[string]bool) (*updatableView, error) {
+	if visiting[view.Name] {
+		return nil, fmt.Errorf("view %s references itself", view.Name)
+	}
+	query, err := parseViewDefinition(view)
+	if err != nil {
+		return nil, err
+	}
+	q, ok := query.(*ast.SelectStmt)
+	if !ok || q.With != nil || q.Distinct || len(q.Joins) > 0 || q.From.Subquery != nil ||
+		q.HasAggregation() || q.HasWindow() || q.Limit != nil {
+		return nil, fmt.Errorf("view %s is not updatable", view.Name)
+	}
+
+	// 参照先のテーブル (またはビュー) のカラム
+	hdl := handler.Get()
+	base := &updatableView{name: q.From.TableName, table: q.From}
+	if inner, ok := hdl.Catalog.GetViewByName(q.From.TableName); ok {
+		if base, err = resolveUpdatableView(inner, visitView(visiting, view.Name)); err != nil {
+			return nil, err
+		}
+	} else {
+		tblMeta, ok := hdl.Catalog.GetTableMetaByName(q.From.TableName)
+		if !ok {
+			return nil, fmt.Errorf("table %s not found", q.From.TableName)
+		}
+		for _, col := range tblMeta.Cols {
+			base.columns = append(base.columns, viewColumn{name: col.Name, expr: ast.ColumnId{ColName: col.Name}})
+		}
+	}
+
+	resolved := &updatableView{name: view.Name, table: base.table}
+	if q.IsSelectAll() {
+		resolved.columns = base.columns
+	} else {
+		for pos, item := range q.SelectItems() {
+			expr, err := base.replaceColumns(item)
+			if err != nil {
+				return nil, err
+			}
+			name, ok := q.Aliases[pos]
+			if !ok {
+				name = ast.ExprString(item)
+				if col, isCol := item.(ast.ColumnId); isCol {
+					name = col.ColName
+				}
+			}
+			resolved.columns = append(resolved.columns, viewColumn{name: name, expr: expr})
+		}
+	}
+	if view.Columns != nil {
+		if len(view.Columns) != len(resolved.columns) {
+			return nil, fmt.Errorf("the number of columns of view %s does not match its column list", view.Name)
+		}
+		for i, name := range view.Columns {
+			resolved.columns[i].name = name
+		}
+	}
+
+	var conds []ast.Expr
+	if base.where != nil {
+		conds = append(conds, base.where)
+	}
+	if q.Where != nil {
+		cond, err := base.replaceColumns(q.Where.Condition)
+		if err != nil {
+			return nil, err
+		}
+		conds = append(conds, cond)
+	}
+	if len(conds) > 0 {
+		resolved.where = combineExprsWithAND(conds)
+	}
+	return resolved, nil
+}
+
+// column はビューのカラムを、テーブルのカラムに対する式に変換する
+func (v *updatableView) column(col ast.ColumnId) (ast.Expr, error) {
+	if col.TableName == "" || refersToTable(col.TableName, v.name) {
+		for _, c := range v.columns {
+			if c.name == col.ColName {
+				return c.expr, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("column %s not found in view %s", ast.ExprString(col), v.name)
+}
+
+// withWhere はビューの WHERE 句の条件と cond を AND で結合した WHERE 句を返す
+func (v *updatableView) withWhere(cond ast.Expr) *ast.WhereClause {
+	var conds []ast.Expr
+	if v.where != nil {
+		conds = append(conds, v.where)
+	}
+	if cond != nil {
+		conds = append(conds, cond)
+	}
+	if len(conds) == 0 {
+		return nil
+	}
+	return &ast.WhereClause{Condition: combineExprsWithAND(conds)}
+}
+
+// rewriteUpdate はビューに対する UPDATE 文を、ビューが参照するテーブルに対する UPDATE 文に変換する
+//
+// SET 句で更新できるのは、テーブルのカラムをそのまま参照するビューのカラムのみ
+func (v *updatableView) rewriteUpdate(stmt *ast.UpdateStmt) (*ast.UpdateStmt, error) {
+	setClauses := make([]*ast.SetClause, len(stmt.SetClauses))
+	for i, setClause := range stmt.SetClauses {
+		expr, err := v.column(setClause.Column)
+		if err != nil {
+			return nil, err
+		}
+		col, ok := expr.(ast.ColumnId)
+		if !ok {
+			return nil, fmt.Errorf("column %s of view %s is not updatable", setClause.Column.ColName, v.name)
+		}
+		value, err := v.replaceColumns(setClause.Value)
+		if err != nil {
+			return nil, err
+		}
+		setClauses[i] = &ast.SetClause{Column: col, Value: value}
+	}
+
+	var cond ast.Expr
+	if stmt.Where != nil {
+		var err error
+		if cond, err = v.replaceColumns(stmt.Where.Condition); err != nil {
+			return nil, err
+		}
+	}
+	return &ast.UpdateStmt{Table: v.table, SetClauses: setClauses, Where: v.withWhere(cond)}, nil
+}
+
+// rewriteDelete はビューに対する DELETE 文を、ビューが参照するテーブルに対する DELETE 文に変換する
+func (v *updatableView) rewriteDelete(stmt *ast.DeleteStmt) (*ast.DeleteStmt, error) {
+	var cond ast.Expr
+	if stmt.Where != nil {
+		var err error
+		if cond, err = v.replaceColumns(stmt.Where.Condition); err != nil {
+			return nil, err
+		}
+	}
+	return &ast.DeleteStmt{From: v.table, Where: v.withWhere(cond)}, nil
+}
+
+// replaceColumns は式のビューのカラムを、テーブルのカラムに対する式に置き換えた式を返す
+//
+// サブクエリの中のカラムはサブクエリのテーブルのカラムとして扱い、置き換えない
+func (v *updatableView) replaceColumns(expr ast.Expr) (ast.Expr, error) {
+	replace := func(e ast.Expr) (ast.Expr, error) {
+		if e == nil {
+			return nil, nil
+		}
+		return v.replaceColumns(e)
+	}
+	replaceAll := func(exprs []ast.Expr) ([]ast.Expr, error) {
+		result := make([]ast.Expr, len(exprs))
+		for i, e := range exprs {
+			r, err := replace(e)
+			if err != nil {
+				return nil, err
+			}
+			result[i] = r
+		}
+		return result, nil
+	}
+
+	switch e := expr.(type) {
+	case ast.ColumnId:
+		return v.column(e)
+	case *ast.BinaryExpr:
+		operands, err := replaceAll([]ast.Expr{ast.LeftOperand(e.Left), ast.RightOperand(e.Right)})
+		if err != nil {
+			return nil, err
+		}
+		return ast.NewBinaryExpr(e.Operator, conditionLHS(operands[0]), conditionRHS(operands[1])), nil
+	case *ast.UnaryExpr:
+		operand, err := replace(e.Operand)
+		if err != nil {
+			return nil, err
+		}
+		return ast.NewUnaryExpr(e.Operator, operand), nil
+	case *ast.InExpr:
+		operand, err := replace(e.Operand)
+		if err != nil {
+			return nil, err
+		}
+		if e.Subquery != nil {
+			return ast.NewInSubqueryExpr(operand, e.Subquery, e.Not), nil
+		}
+		values, err := replaceAll(e.Values)
+		if err != nil {
+			return nil, err
+		}
+		return ast.NewInExpr(operand, values, e.Not), nil
+	case *ast.BetweenExpr:
+		operands, err := replaceAll([]ast.Expr{e.Operand, e.Lower, e.Upper})
+		if err != nil {
+			return nil, err
+		}
+		return ast.NewBetweenExpr(operands[0], operands[1], operands[2], e.Not), nil
+	case *ast.FuncCallExpr:
+		args, err := replaceAll(e.Args)
+		if err != nil {
+			return nil, err
+		}
+		return ast.NewFuncCallExpr(e.Name, args), nil
+	case *ast.CaseExpr:
+		operand, err := replace(e.Operand)
+		if err != nil {
+			return nil, err
+		}
+		whens := make([]*ast.WhenClause, len(e.Whens))
+		for i, when := range e.Whens {
+			parts, err := replaceAll([]ast.Expr{when.Condition, when.Result})
+			if err != nil {
+				return nil, err
+			}
+			whens[i] = &ast.WhenClause{Condition: parts[0], Result: parts[1]}
+		}
+		elseExpr, err := replace(e.Else)
+		if err != nil {
+			return nil, err
+		}
+		return &ast.CaseExpr{Operand: operand, Whens: whens, Else: elseExpr}, nil
+	default:
+		// リテラル・サブクエリ
+		return expr, nil
+	}
+}
@@ -0,0 +1,274 @@
+package planner
+
+import (
+	"testing"
+
+	"github.com/ren-yamanashi/minesql/internal/ast"
+	"github.com/ren-yamanashi/minesql/internal/executor"
+	"github.com/ren-yamanashi/minesql/internal/storage/handler"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlanCreateView(t *testing.T) {
+	t.Run("ビューを作成し、SELECT 文で参照できる", func(t *testing.T) {
+		// GIVEN
+		setupItemsTable(t)
+		defer handler.Reset()
+		executePlan(t, parseStatementForTest(t, "CREATE VIEW cheap (item_id, cost) AS SELECT id, price FROM items WHERE price < 25;"))
+		stmt := parseStatementForTest(t, "SELECT item_id, cost FROM cheap WHERE cost > 5 ORDER BY item_id;").(*ast.SelectStmt)
+
+		// WHEN
+		plan, err := PlanSelect(0, stmt)
+		require.NoError(t, err)
+		results := fetchAll(t, plan.Exec)
+
+		// THEN
+		assert.Equal(t, []int64{1, 2}, int64Column(results, 0))
+		assert.Equal(t, []int64{10, 20}, int64Column(results, 1))
+		assert.Equal(t, "item_id", plan.Columns[0].ColName)
+		assert.Equal(t, "cost", plan.Columns[1].ColName)
+	})
+
+	t.Run("ビューを参照するビュー・ビューとテーブルの結合・サブクエリ内のビューを展開する", func(t *testing.T) {
+		// GIVEN
+		setupItemsTable(t)
+		defer handler.Reset()
+		executePlan(t, parseStatementForTest(t, "CREATE VIEW priced AS SELECT id, category, price FROM items WHERE price IS NOT NULL;"))
+		executePlan(t, parseStatementForTest(t, "CREATE VIEW cheap AS SELECT id, price FROM priced WHERE price < 25;"))
+		tests := []struct {
+			name string
+			sql  string
+			ids  []int64
+		}{
+			{"ビューを参照するビュー", "SELECT id FROM cheap ORDER BY id;", []int64{1, 2, 5}},
+			{"ビューとテーブルの結合", "SELECT items.id FROM items JOIN cheap ON items.id = cheap.id WHERE items.category = 'a' ORDER BY items.id;", []int64{2, 5}},
+			{"サブクエリ内のビュー", "SELECT id FROM items WHERE id NOT IN (SELECT id FROM priced) ORDER BY id;", []int64{4}},
+			{"集合演算のオペランドのビュー", "SELECT id FROM cheap UNION SELECT id FROM items WHERE id = 4 ORDER BY id;", []int64{1, 2, 4, 5}},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				// WHEN
+				plan, err := Start(0, parseStatementForTest(t, tt.sql))
+				require.NoError(t, err)
+				results := fetchAll(t, plan.Exec)
+
+				// THEN
+				assert.Equal(t, tt.ids, int64Column(results, 0))
+			})
+		}
+	})
+
+	t.Run("WITH 句の共通テーブル式は同じ名前のビューより優先する", func(t *testing.T) {
+		// GIVEN
+		setupItemsTable(t)
+		defer handler.Reset()
+		executePlan(t, parseStatementForTest(t, "CREATE VIEW v AS SELECT id FROM items WHERE id = 1;"))
+		stmt := parseStatementForTest(t, "WITH v AS (SELECT id FROM items WHERE id = 2) SELECT id FROM v;").(*ast.SelectStmt)
+
+		// WHEN
+		plan, err := PlanSelect(0, stmt)
+		require.NoError(t, err)
+		results := fetchAll(t, plan.Exec)
+
+		// THEN
+		assert.Equal(t, []int64{2}, int64Column(results, 0))
+	})
+
+	t.Run("OR REPLACE を指定した場合はビューの定義を置き換える", func(t *testing.T) {
+		// GIVEN
+		setupItemsTable(t)
+		defer handler.Reset()
+		executePlan(t, parseStatementForTest(t, "CREATE VIEW v AS SELECT id FROM items WHERE id = 1;"))
+
+		// WHEN
+		executePlan(t, parseStatementForTest(t, "CREATE OR REPLACE VIEW v AS SELECT id FROM items WHERE id >= 4;"))
+
+		// THEN
+		plan, err := Start(0, parseStatementForTest(t, "SELECT id FROM v ORDER BY id;"))
+		require.NoError(t, err)
+		assert.Equal(t, []int64{4, 5}, int64Column(fetchAll(t, plan.Exec), 0))
+	})
+
+	t.Run("不正なビューはエラーになる", func(t *testing.T) {
+		tests := []struct {
+			name string
+			sql  string
+			err  string
+		}{
+			{
+				name: "同じ名前のビューがある",
+				sql:  "CREATE VIEW v AS SELECT id FROM items;",
+				err:  "view v already exists",
+			},
+			{
+				name: "同じ名前のテーブルがある",
+				sql:  "CREATE OR REPLACE VIEW items AS SELECT id FROM items;",
+				err:  "table items already exists",
+			},
+			{
+				name: "存在しないテーブルを参照する",
+				sql:  "CREATE VIEW x AS SELECT id FROM orders;",
+				err:  "table orders not found",
+			},
+			{
+				name: "ビュー自身を参照する",
+				sql:  "CREATE OR REPLACE VIEW v AS SELECT id FROM w;",
+				err:  "view v references itself",
+			},
+			{
+				name: "カラム名のリストの数が結果セットのカラム数と一致しない",
+				sql:  "CREATE VIEW x (a, b) AS SELECT id FROM items;",
+				err:  "the number of columns of view x does not match its column list",
+			},
+			{
+				name: "カラム名が重複する",
+				sql:  "CREATE VIEW x AS SELECT id, price AS id FROM items;",
+				err:  "duplicate column name id in view x",
+			},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				// GIVEN
+				setupItemsTable(t)
+				defer handler.Reset()
+				executePlan(t, parseStatementForTest(t, "CREATE VIEW v AS SELECT id FROM items;"))
+				executePlan(t, parseStatementForTest(t, "CREATE VIEW w AS SELECT id FROM v;"))
+
+				// WHEN
+				_, err := Start(0, parseStatementForTest(t, tt.sql))
+
+				// THEN
+				assert.EqualError(t, err, tt.err)
+			})
+		}
+	})
+}
+
+func TestPlanDropView(t *testing.T) {
+	t.Run("ビューを削除すると参照できなくなる", func(t *testing.T) {
+		// GIVEN
+		setupItemsTable(t)
+		defer handler.Reset()
+		executePlan(t, parseStatementForTest(t, "CREATE VIEW v AS SELECT id FROM items;"))
+
+		// WHEN
+		exec, err := PlanDropView(&ast.DropViewStmt{ViewName: "v"})
+		require.NoError(t, err)
+		fetchAll(t, exec)
+
+		// THEN
+		assert.IsType(t, &executor.DropView{}, exec)
+		_, err = Start(0, parseStatementForTest(t, "SELECT id FROM v;"))
+		assert.EqualError(t, err, "table v not found")
+	})
+
+	t.Run("存在しないビューの場合エラーを返す (IF EXISTS の場合はエラーを返さない)", func(t *testing.T) {
+		// GIVEN
+		setupItemsTable(t)
+		defer handler.Reset()
+
+		// WHEN
+		_, err := PlanDropView(&ast.DropViewStmt{ViewName: "v"})
+		_, ifExistsErr := PlanDropView(&ast.DropViewStmt{ViewName: "v", IfExists: true})
+
+		// THEN
+		assert.EqualError(t, err, "view v not found")
+		assert.NoError(t, ifExistsErr)
+	})
+}
+
+func TestUpdatableView(t *testing.T) {
+	// selectPrices は items テーブルの price を id の順に返す
+	selectPrices := func(t *testing.T) []int64 {
+		t.Helper()
+		plan, err := Start(0, parseStatementForTest(t, "SELECT id, COALESCE(price, -1) FROM items ORDER BY id;"))
+		require.NoError(t, err)
+		return int64Column(fetchAll(t, plan.Exec), 1)
+	}
+
+	t.Run("ビューに対する UPDATE はビューの条件を満たすテーブルの行を更新する", func(t *testing.T) {
+		// GIVEN
+		setupItemsTable(t)
+		defer handler.Reset()
+		executePlan(t, parseStatementForTest(t, "CREATE VIEW cheap (item_id, cost) AS SELECT id, price FROM items WHERE price < 25;"))
+
+		// WHEN
+		executePlan(t, parseStatementForTest(t, "UPDATE cheap SET cost = cost + 100 WHERE item_id >= 2;"))
+
+		// THEN: id 2, 5 のみ更新される (id 3 は price が 25 以上のためビューに含まれない)
+		assert.Equal(t, []int64{10, 120, 30, -1, 105}, selectPrices(t))
+	})
+
+	t.Run("ビューに対する DELETE はビューの条件を満たすテーブルの行を削除する", func(t *testing.T) {
+		// GIVEN
+		setupItemsTable(t)
+		defer handler.Reset()
+		executePlan(t, parseStatementForTest(t, "CREATE VIEW a_items AS SELECT * FROM items WHERE category = 'a';"))
+
+		// WHEN
+		executePlan(t, parseStatementForTest(t, "DELETE FROM a_items WHERE price > 10;"))
+
+		// THEN
+		assert.Equal(t, []int64{10, 30, -1, 5}, selectPrices(t))
+	})
+
+	t.Run("更新可能なビューを参照するビューも更新できる", func(t *testing.T) {
+		// GIVEN
+		setupItemsTable(t)
+		defer handler.Reset()
+		executePlan(t, parseStatementForTest(t, "CREATE VIEW priced AS SELECT id, price AS p FROM items WHERE price IS NOT NULL;"))
+		executePlan(t, parseStatementForTest(t, "CREATE VIEW cheap AS SELECT id, p FROM priced WHERE p < 25;"))
+
+		// WHEN
+		executePlan(t, parseStatementForTest(t, "UPDATE cheap SET p = 0;"))
+
+		// THEN
+		assert.Equal(t, []int64{0, 0, 30, -1, 0}, selectPrices(t))
+	})
+
+	t.Run("更新できないビュー・カラムはエラーになる", func(t *testing.T) {
+		tests := []struct {
+			name string
+			sql  string
+			err  string
+		}{
+			{
+				name: "集約を含むビューの更新",
+				sql:  "UPDATE totals SET total = 0;",
+				err:  "view totals is not updatable",
+			},
+			{
+				name: "DISTINCT を含むビューの削除",
+				sql:  "DELETE FROM categories;",
+				err:  "view categories is not updatable",
+			},
+			{
+				name: "式のカラムの更新",
+				sql:  "UPDATE doubled SET double_price = 0;",
+				err:  "column double_price of view doubled is not updatable",
+			},
+			{
+				name: "ビューにないカラムの参照",
+				sql:  "UPDATE doubled SET id = 0 WHERE category = 'a';",
+				err:  "column category not found in view doubled",
+			},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				// GIVEN
+				setupItemsTable(t)
+				defer handler.Reset()
+				executePlan(t, parseStatementForTest(t, "CREATE VIEW totals (category, total) AS SELECT category, SUM(price) FROM items GROUP BY category;"))
+				executePlan(t, parseStatementForTest(t, "CREATE VIEW categories AS SELECT DISTINCT category FROM items;"))
+				executePlan(t, parseStatementForTest(t, "CREATE VIEW doubled AS SELECT id, price * 2 AS double_price FROM items;"))
+
+				// WHEN
+				_, err := Start(0, parseStatementForTest(t, tt.sql))
+
+				// THEN
+				assert.EqualError(t, err, tt.err)
+			})
+		}
+	})
+}
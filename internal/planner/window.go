@@ -0,0 +1,217 @@
+package planner
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/ren-yamanashi/minesql/internal/ast"
+	"github.com/ren-yamanashi/minesql/internal/executor"
+	"github.com/ren-yamanashi/minesql/internal/storage/handler"
+)
+
+// planWindows は SELECT リストのウィンドウ関数を計算する Window を計画する
+//
+// exec は WHERE 句 (集約を含む場合は HAVING 句) まで適用済みの Executor で、scope はそのレコードに対するスコープ、sortOrder はその並び順
+// 同じ PARTITION BY・ORDER BY のウィンドウ関数は 1 つの Window にまとめ、Window の前でパーティションキー・ORDER BY のキーの順に並べ替える
+// Window はレコードの後ろにウィンドウ関数の結果を追加するため、ウィンドウ関数を結果のカラムの位置に解決するスコープを返す
+// ウィンドウ関数がない場合は exec・scope・sortOrder をそのまま返す
+func planWindows(exec executor.Executor, stmt *ast.SelectStmt, scope *exprScope, sortOrder []int) (executor.Executor, *exprScope, []int, error) {
+	groups := groupWindowFuncs(stmt)
+	if len(groups) == 0 {
+		return exec, scope, sortOrder, nil
+	}
+
+	columns := slices.Clone(scope.columns)
+	positions := map[string]int{} // ウィンドウ関数の表記 -> 結果のカラムの位置
+	for _, group := range groups {
+		window := group[0].Window
+		partitionPos := make([]int, len(window.PartitionBy))
+		keys := make([]executor.SortKey, 0, len(window.PartitionBy)+len(window.OrderBy))
+		for i, col := range window.PartitionBy {
+			pos, err := scope.column(col)
+			if err != nil {
+				return nil, nil, nil, err
+			}
+			partitionPos[i] = pos
+			keys = append(keys, executor.SortKey{Pos: pos})
+		}
+		orderKeys := make([]executor.SortKey, len(window.OrderBy))
+		for i, item := range window.OrderBy {
+			pos, err := scope.column(item.Column)
+			if err != nil {
+				return nil, nil, nil, err
+			}
+			orderKeys[i] = executor.SortKey{Pos: pos, Desc: item.Desc}
+		}
+		keys = append(keys, orderKeys...)
+
+		// パーティションキー・ORDER BY のキーの順に並べ替える (既にその順に並んでいる場合は省略する)
+		if !isSortedBy(keys, sortOrder) {
+			exec = executor.NewSort(exec, keys)
+			sortOrder = ascendingKeyPrefix(keys)
+		}
+
+		specs := make([]executor.WindowFuncSpec, len(group))
+		for i, w := range group {
+			spec, meta, err := windowFuncSpec(w, scope)
+			if err != nil {
+				return nil, nil, nil, err
+			}
+			specs[i] = spec
+			positions[w.String()] = len(columns)
+			columns = append(columns, joinedColumn{colName: meta.Name, pos: len(columns), colMeta: meta})
+		}
+		exec = executor.NewWindow(exec, partitionPos, orderKeys, specs)
+	}
+
+	windowScope := *scope
+	windowScope.columns = columns
+	windowScope.window = func(w *ast.WindowFuncExpr) (int, error) {
+		pos, ok := positions[w.String()]
+		if !ok {
+			return -1, fmt.Errorf("invalid use of window function %s", w.String())
+		}
+		return pos, nil
+	}
+	return exec, &windowScope, sortOrder, nil
+}
+
+// groupWindowFuncs は SELECT リストのウィンドウ関数を、PARTITION BY・ORDER BY が同じものごとにまとめて返す
+//
+// 同じ表記のウィンドウ関数は 1 つにまとめる。グループ・グループ内の順序は SELECT リストに現れた順
+func groupWindowFuncs(stmt *ast.SelectStmt) [][]*ast.WindowFuncExpr {
+	var groups [][]*ast.WindowFuncExpr
+	groupIndex := map[string]int{} // PARTITION BY・ORDER BY の表記 -> groups 内の位置
+	seen := map[string]struct{}{}
+	for _, e := range stmt.Exprs {
+		ast.WalkExpr(e.Expr, func(expr ast.Expr) bool {
+			w, ok := expr.(*ast.WindowFuncExpr)
+			if !ok {
+				return true
+			}
+			if _, ok := seen[w.String()]; ok {
+				return false
+			}
+			seen[w.String()] = struct{}{}
+
+			key := (&ast.WindowSpec{PartitionBy: w.Window.PartitionBy, OrderBy: w.Window.OrderBy}).String()
+			i, ok := groupIndex[key]
+			if !ok {
+				i = len(groups)
+				groupIndex[key] = i
+				groups = append(groups, nil)
+			}
+			groups[i] = append(groups[i], w)
+			return false
+		})
+	}
+	return groups
+}
+
+// windowFuncSpec はウィンドウ関数を Executor のウィンドウ関数に変換し、結果のカラムメタデータを返す
+//
+// 結果のデータ型:
+//   - ROW_NUMBER / RANK / DENSE_RANK: BIGINT
+//   - LAG / LEAD: 対象のカラムと同じデータ型 (デフォルト値はそのデータ型に変換する)
+//   - 集約関数: GROUP BY の集約と同じ (aggregateOutput を参照)
+func windowFuncSpec(w *ast.WindowFuncExpr, scope *exprScope) (executor.WindowFuncSpec, *handler.ColumnMetadata, error) {
+	if w.Aggregate != nil {
+		pos := -1
+		var colMeta *handler.ColumnMetadata
+		if w.Aggregate.Column != nil {
+			var err error
+			if pos, err = scope.column(*w.Aggregate.Column); err != nil {
+				return executor.WindowFuncSpec{}, nil, err
+			}
+			colMeta = scope.columns[pos].colMeta
+		}
+		fn, meta, err := aggregateOutput(w.Aggregate, colMeta)
+		if err != nil {
+			return executor.WindowFuncSpec{}, nil, err
+		}
+		meta.Name = w.String()
+		spec := executor.WindowFuncSpec{
+			Func:      executor.WindowAggregate,
+			Aggregate: executor.AggregateSpec{Func: fn, Pos: pos},
+			Frame:     windowFrame(w.Window.Frame),
+		}
+		return spec, meta, nil
+	}
+
+	switch w.Func {
+	case ast.WindowLag, ast.WindowLead:
+		pos, err := scope.column(*w.Column)
+		if err != nil {
+			return executor.WindowFuncSpec{}, nil, err
+		}
+		colMeta := scope.columns[pos].colMeta
+		meta := &handler.ColumnMetadata{Name: w.String(), Type: colMeta.Type, Precision: colMeta.Precision, Scale: colMeta.Scale}
+		spec := executor.WindowFuncSpec{Func: executor.WindowLag, Pos: pos, Offset: int(w.Offset)}
+		if w.Func == ast.WindowLead {
+			spec.Func = executor.WindowLead
+		}
+		if w.Default != nil {
+			te, err := castTo(typedExpr{lit: w.Default}, meta)
+			if err != nil {
+				return executor.WindowFuncSpec{}, nil, err
+			}
+			spec.Default = te.expr.(*executor.Constant).Value
+		}
+		return spec, meta, nil
+	case ast.WindowRank:
+		return executor.WindowFuncSpec{Func: executor.WindowRank}, rankingMeta(w), nil
+	case ast.WindowDenseRank:
+		return executor.WindowFuncSpec{Func: executor.WindowDenseRank}, rankingMeta(w), nil
+	default:
+		return executor.WindowFuncSpec{Func: executor.WindowRowNumber}, rankingMeta(w), nil
+	}
+}
+
+// rankingMeta は ROW_NUMBER / RANK / DENSE_RANK の結果のカラムメタデータ (BIGINT) を返す
+func rankingMeta(w *ast.WindowFuncExpr) *handler.ColumnMetadata {
+	meta := bigintMeta()
+	meta.Name = w.String()
+	return meta
+}
+
+// windowFrame は ROWS のフレームを Executor のフレームに変換する
+//
+// フレームを省略した場合は、パーティションの先頭から現在行のピアまで (ORDER BY がない場合はパーティション全体) とする
+func windowFrame(frame *ast.WindowFrame) executor.WindowFrame {
+	if frame == nil {
+		return executor.WindowFrame{
+			Start: executor.FrameBound{Type: executor.FrameUnboundedPreceding},
+			End:   executor.FrameBound{Type: executor.FrameCurrentRow},
+			Peers: true,
+		}
+	}
+	return executor.WindowFrame{Start: frameBound(frame.Start), End: frameBound(frame.End)}
+}
+
+// frameBound はフレームの境界を Executor の境界に変換する
+func frameBound(bound ast.FrameBound) executor.FrameBound {
+	switch bound.Type {
+	case ast.FrameUnboundedPreceding:
+		return executor.FrameBound{Type: executor.FrameUnboundedPreceding}
+	case ast.FramePreceding:
+		return executor.FrameBound{Type: executor.FramePreceding, Offset: int(bound.Offset)}
+	case ast.FrameFollowing:
+		return executor.FrameBound{Type: executor.FrameFollowing, Offset: int(bound.Offset)}
+	case ast.FrameUnboundedFollowing:
+		return executor.FrameBound{Type: executor.FrameUnboundedFollowing}
+	default:
+		return executor.FrameBound{Type: executor.FrameCurrentRow}
+	}
+}
+
+// ascendingKeyPrefix は keys で並べ替えたレコードの並び順 (先頭から続く昇順のキーのカラム位置) を返す
+func ascendingKeyPrefix(keys []executor.SortKey) []int {
+	var order []int
+	for _, key := range keys {
+		if key.Desc {
+			break
+		}
+		order = append(order, key.Pos)
+	}
+	return order
+}
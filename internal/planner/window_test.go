@@ -0,0 +1,235 @@
+package planner
+
+import (
+	"testing"
+
+	"github.com/ren-yamanashi/minesql/internal/ast"
+	"github.com/ren-yamanashi/minesql/internal/executor"
+	"github.com/ren-yamanashi/minesql/internal/storage/encode"
+	"github.com/ren-yamanashi/minesql/internal/storage/handler"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlanSelectWindow(t *testing.T) {
+	t.Run("PARTITION BY・ORDER BY ごとに行番号を付ける", func(t *testing.T) {
+		// GIVEN
+		setupItemsTable(t)
+		defer handler.Reset()
+		stmt := parseStatementForTest(t, "SELECT id, ROW_NUMBER() OVER (PARTITION BY category ORDER BY price DESC) FROM items ORDER BY id;").(*ast.SelectStmt)
+
+		// WHEN
+		plan, err := PlanSelect(0, stmt)
+		require.NoError(t, err)
+		results := fetchAll(t, plan.Exec)
+
+		// THEN
+		assert.Equal(t, []int64{1, 2, 3, 4, 5}, int64Column(results, 0))
+		assert.Equal(t, []int64{2, 1, 1, 1, 2}, int64Column(results, 1))
+		assert.Equal(t, "ROW_NUMBER() OVER (PARTITION BY category ORDER BY price DESC)", plan.Columns[1].ColName)
+		assert.Equal(t, handler.ColumnTypeBigInt, plan.Columns[1].Type)
+	})
+
+	t.Run("RANK は同順位の後の順位を飛ばし、DENSE_RANK は飛ばさない", func(t *testing.T) {
+		// GIVEN
+		setupItemsTable(t)
+		defer handler.Reset()
+		stmt := parseStatementForTest(t, "SELECT id, RANK() OVER (ORDER BY category), DENSE_RANK() OVER (ORDER BY category) FROM items ORDER BY id;").(*ast.SelectStmt)
+
+		// WHEN
+		plan, err := PlanSelect(0, stmt)
+		require.NoError(t, err)
+		results := fetchAll(t, plan.Exec)
+
+		// THEN: a (id 2, 5), b (id 1, 3), c (id 4)
+		assert.Equal(t, []int64{3, 1, 3, 5, 1}, int64Column(results, 1))
+		assert.Equal(t, []int64{2, 1, 2, 3, 1}, int64Column(results, 2))
+	})
+
+	t.Run("ORDER BY を指定した集約関数は累計を返す", func(t *testing.T) {
+		// GIVEN
+		setupItemsTable(t)
+		defer handler.Reset()
+		stmt := parseStatementForTest(t, "SELECT id, SUM(price) OVER (ORDER BY id), COUNT(price) OVER () FROM items;").(*ast.SelectStmt)
+
+		// WHEN
+		plan, err := PlanSelect(0, stmt)
+		require.NoError(t, err)
+		results := fetchAll(t, plan.Exec)
+
+		// THEN
+		assert.Equal(t, []int64{1, 2, 3, 4, 5}, int64Column(results, 0))
+		assert.Equal(t, []int64{10, 30, 60, 60, 65}, int64Column(results, 1))
+		assert.Equal(t, []int64{4, 4, 4, 4, 4}, int64Column(results, 2))
+	})
+
+	t.Run("ROWS のフレームで前後の行を集約する", func(t *testing.T) {
+		// GIVEN
+		setupItemsTable(t)
+		defer handler.Reset()
+		stmt := parseStatementForTest(t, "SELECT id, SUM(price) OVER (ORDER BY id ROWS BETWEEN 1 PRECEDING AND 1 FOLLOWING) FROM items;").(*ast.SelectStmt)
+
+		// WHEN
+		plan, err := PlanSelect(0, stmt)
+		require.NoError(t, err)
+		results := fetchAll(t, plan.Exec)
+
+		// THEN
+		assert.Equal(t, []int64{30, 60, 50, 35, 5}, int64Column(results, 1))
+	})
+
+	t.Run("LAG・LEAD で前後の行の値を参照する", func(t *testing.T) {
+		// GIVEN
+		setupItemsTable(t)
+		defer handler.Reset()
+		stmt := parseStatementForTest(t, "SELECT id, LAG(price) OVER (ORDER BY id), LEAD(price, 1, 0) OVER (ORDER BY id) FROM items;").(*ast.SelectStmt)
+
+		// WHEN
+		plan, err := PlanSelect(0, stmt)
+		require.NoError(t, err)
+		results := fetchAll(t, plan.Exec)
+
+		// THEN: 参照した行の値が NULL の場合はデフォルト値ではなく NULL を返す
+		assert.Equal(t, [][]byte{nil, encode.EncodeInt64(10), encode.EncodeInt64(20), encode.EncodeInt64(30), nil}, columnBytes(results, 1))
+		assert.Equal(t, [][]byte{encode.EncodeInt64(20), encode.EncodeInt64(30), nil, encode.EncodeInt64(5), encode.EncodeInt64(0)}, columnBytes(results, 2))
+		assert.Equal(t, handler.ColumnTypeInt, plan.Columns[1].Type)
+	})
+
+	t.Run("ウィンドウ関数を式の中で使い、別名を付けられる", func(t *testing.T) {
+		// GIVEN
+		setupItemsTable(t)
+		defer handler.Reset()
+		stmt := parseStatementForTest(t, "SELECT id, price - LAG(price) OVER (ORDER BY id) AS diff FROM items WHERE id <= 3;").(*ast.SelectStmt)
+
+		// WHEN
+		plan, err := PlanSelect(0, stmt)
+		require.NoError(t, err)
+		results := fetchAll(t, plan.Exec)
+
+		// THEN
+		assert.Equal(t, [][]byte{nil, encode.EncodeInt64(10), encode.EncodeInt64(10)}, columnBytes(results, 1))
+		assert.Equal(t, "diff", plan.Columns[1].ColName)
+	})
+
+	t.Run("LIMIT はウィンドウ関数を計算した後に適用する", func(t *testing.T) {
+		// GIVEN
+		setupItemsTable(t)
+		defer handler.Reset()
+		stmt := parseStatementForTest(t, "SELECT id, SUM(price) OVER () FROM items ORDER BY id LIMIT 2;").(*ast.SelectStmt)
+
+		// WHEN
+		plan, err := PlanSelect(0, stmt)
+		require.NoError(t, err)
+		results := fetchAll(t, plan.Exec)
+
+		// THEN
+		assert.Equal(t, []int64{1, 2}, int64Column(results, 0))
+		assert.Equal(t, []int64{65, 65}, int64Column(results, 1))
+	})
+
+	t.Run("集約後のレコードに対してウィンドウ関数を計算する", func(t *testing.T) {
+		// GIVEN
+		setupItemsTable(t)
+		defer handler.Reset()
+		stmt := parseStatementForTest(t, "SELECT category, ROW_NUMBER() OVER (ORDER BY category DESC), COUNT(*) OVER () FROM items GROUP BY category ORDER BY category;").(*ast.SelectStmt)
+
+		// WHEN
+		plan, err := PlanSelect(0, stmt)
+		require.NoError(t, err)
+		results := fetchAll(t, plan.Exec)
+
+		// THEN
+		assert.Equal(t, []string{"a", "b", "c"}, columnStrings(results, 0))
+		assert.Equal(t, []int64{3, 2, 1}, int64Column(results, 1))
+		assert.Equal(t, []int64{3, 3, 3}, int64Column(results, 2))
+	})
+
+	t.Run("導出テーブルに対してウィンドウ関数を計算する", func(t *testing.T) {
+		// GIVEN
+		setupItemsTable(t)
+		defer handler.Reset()
+		stmt := parseStatementForTest(t, "SELECT id, ROW_NUMBER() OVER (ORDER BY price) FROM (SELECT id, price FROM items WHERE price IS NOT NULL) AS t ORDER BY id;").(*ast.SelectStmt)
+
+		// WHEN
+		plan, err := PlanSelect(0, stmt)
+		require.NoError(t, err)
+		results := fetchAll(t, plan.Exec)
+
+		// THEN
+		assert.Equal(t, []int64{1, 2, 3, 5}, int64Column(results, 0))
+		assert.Equal(t, []int64{2, 3, 4, 1}, int64Column(results, 1))
+	})
+
+	t.Run("同じ PARTITION BY・ORDER BY のウィンドウ関数は 1 つの Window で計算する", func(t *testing.T) {
+		// GIVEN
+		setupItemsTable(t)
+		defer handler.Reset()
+		stmt := parseStatementForTest(t, "SELECT ROW_NUMBER() OVER (ORDER BY price), RANK() OVER (ORDER BY price), SUM(price) OVER (ORDER BY id) FROM items;").(*ast.SelectStmt)
+
+		// WHEN
+		plan, err := PlanSelect(0, stmt)
+		require.NoError(t, err)
+
+		// THEN: Window (SUM) <- Sort (id) <- Window (ROW_NUMBER, RANK) <- Sort (price) <- ...
+		project := plan.Exec.(executor.Explainer).Explain()
+		outer := (*project.Children[0]).(*executor.Window).Explain()
+		assert.Equal(t, "SUM", outer.Detail)
+		sortByID := (*outer.Children[0]).(*executor.Sort).Explain()
+		inner := (*sortByID.Children[0]).(*executor.Window).Explain()
+		assert.Equal(t, "ROW_NUMBER, RANK", inner.Detail)
+		_, ok := (*inner.Children[0]).(*executor.Sort)
+		assert.True(t, ok)
+		results := fetchAll(t, plan.Exec)
+		assert.Len(t, results, 5)
+	})
+
+	t.Run("入力が既に ORDER BY の順に並んでいる場合は Sort を省略する", func(t *testing.T) {
+		// GIVEN
+		setupItemsTable(t)
+		defer handler.Reset()
+		stmt := parseStatementForTest(t, "SELECT id, SUM(price) OVER (ORDER BY id) FROM items;").(*ast.SelectStmt)
+
+		// WHEN
+		plan, err := PlanSelect(0, stmt)
+		require.NoError(t, err)
+
+		// THEN
+		project := plan.Exec.(executor.Explainer).Explain()
+		window := (*project.Children[0]).(*executor.Window).Explain()
+		_, ok := (*window.Children[0]).(*executor.Sort)
+		assert.False(t, ok)
+	})
+
+	t.Run("不正なウィンドウ関数の使い方はエラーになる", func(t *testing.T) {
+		tests := []struct {
+			name string
+			sql  string
+			err  string
+		}{
+			{
+				name: "OVER 句がない",
+				sql:  "SELECT ROW_NUMBER() FROM items;",
+				err:  "window function ROW_NUMBER requires an OVER clause",
+			},
+			{
+				name: "集約後のレコードにないカラムを参照する",
+				sql:  "SELECT category, RANK() OVER (ORDER BY price) FROM items GROUP BY category;",
+				err:  "column price must appear in the GROUP BY clause or be used in an aggregate function",
+			},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				// GIVEN
+				setupItemsTable(t)
+				defer handler.Reset()
+				stmt := parseStatementForTest(t, tt.sql).(*ast.SelectStmt)
+
+				// WHEN
+				_, err := PlanSelect(0, stmt)
+
+				// THEN
+				assert.EqualError(t, err, tt.err)
+			})
+		}
+	})
+}
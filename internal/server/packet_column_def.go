@@ -1,9 +1,28 @@
 package server
 
+import "github.com/ren-yamanashi/minesql/internal/storage/handler"
+
+// Column Types (column_type)
+const (
+	mysqlTypeLong       byte = 0x03 // INT
+	mysqlTypeLongLong   byte = 0x08 // BIGINT
+	mysqlTypeDate       byte = 0x0A // DATE
+	mysqlTypeDatetime   byte = 0x0C // DATETIME
+	mysqlTypeNewDecimal byte = 0xF6 // DECIMAL
+	mysqlTypeVarString  byte = 0xFD // VARCHAR
+)
+
+// charsetBinary は数値型や日付型のカラムに使用する binary の collation ID
+const charsetBinary = 63
+
 // columnDefPacket は結果セットのカラムメタデータであり、Column Definition パケットを表す
 type columnDefPacket struct {
+	schema    string // テーブルが属するデータベース名 (データベースに属さないテーブル・式の場合は空文字列)
 	tableName string
 	name      string
+	colType   handler.ColumnType // カラムのデータ型 (空の場合は VARCHAR として扱う)
+	precision uint8              // DECIMAL の精度
+	scale     uint8              // DECIMAL のスケール
 }
 
 // build は Column Definition パケットのペイロードを構築する
@@ -13,8 +32,8 @@ func (c *columnDefPacket) build() []byte {
 	// catalog: "def"
 	buf = putLenEncString(buf, "def")
 
-	// schema: "" (空文字列)
-	buf = putLenEncString(buf, "")
+	// schema
+	buf = putLenEncString(buf, c.schema)
 
 	// table
 	buf = putLenEncString(buf, c.tableName)
@@ -31,27 +50,51 @@ func (c *columnDefPacket) build() []byte {
 	// fixed_fields_length: 0x0c
 	buf = putLenEncInt(buf, 0x0c)
 
-	// character_set: utf8mb4_general_ci (2 バイト)
+	columnType, columnLength, charset := c.typeInfo()
+
+	// character_set (2 バイト)
 	cs := make([]byte, 2)
-	putUint16(cs, charsetUTF8MB4)
+	putUint16(cs, charset)
 	buf = append(buf, cs...)
 
-	// column_length: 255 (4 バイト、VARCHAR のデフォルト)
+	// column_length (4 バイト)
 	cl := make([]byte, 4)
-	putUint32(cl, 255)
+	putUint32(cl, columnLength)
 	buf = append(buf, cl...)
 
-	// column_type: MYSQL_TYPE_VAR_STRING (1 バイト)
-	buf = append(buf, 0xFD)
+	// column_type (1 バイト)
+	buf = append(buf, columnType)
 
 	// flags: 0x0000 (2 バイト)
 	buf = append(buf, 0x00, 0x00)
 
-	// decimals: 0x00 (1 バイト)
-	buf = append(buf, 0x00)
+	// decimals (1 バイト)
+	buf = append(buf, c.scale)
 
 	// filler: 0x0000 (2 バイト)
 	buf = append(buf, 0x00, 0x00)
 
 	return buf
 }
+
+// typeInfo はカラムのデータ型に対応する column_type, column_length, character_set を返す
+//
+// column_length は MySQL と同様に、値のテキスト表現の最大長を返す
+func (c *columnDefPacket) typeInfo() (columnType byte, columnLength uint32, charset uint16) {
+	switch c.colType {
+	case handler.ColumnTypeInt:
+		return mysqlTypeLong, 11, charsetBinary
+	case handler.ColumnTypeBigInt:
+		return mysqlTypeLongLong, 20, charsetBinary
+	case handler.ColumnTypeDecimal:
+		// 符号と小数点の分を加える
+		return mysqlTypeNewDecimal, uint32(c.precision) + 2, charsetBinary
+	case handler.ColumnTypeDate:
+		return mysqlTypeDate, 10, charsetBinary
+	case handler.ColumnTypeDatetime:
+		return mysqlTypeDatetime, 19, charsetBinary
+	default:
+		// VARCHAR のデフォルト
+		return mysqlTypeVarString, 255, charsetUTF8MB4
+	}
+}
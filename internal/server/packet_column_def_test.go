@@ -3,6 +3,7 @@ package server
 import (
 	"testing"
 
+	"github.com/ren-yamanashi/minesql/internal/storage/handler"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -78,6 +79,68 @@ func TestColumnDefPacketBuild(t *testing.T) {
 	})
 }
 
+func TestColumnDefPacketTypeInfo(t *testing.T) {
+	t.Run("データ型に応じた column_type, column_length, character_set を返す", func(t *testing.T) {
+		tests := []struct {
+			colType      handler.ColumnType
+			precision    uint8
+			columnType   byte
+			columnLength uint32
+			charset      uint16
+		}{
+			{handler.ColumnTypeString, 0, 0xFD, 255, charsetUTF8MB4},
+			{handler.ColumnTypeInt, 0, 0x03, 11, charsetBinary},
+			{handler.ColumnTypeBigInt, 0, 0x08, 20, charsetBinary},
+			{handler.ColumnTypeDecimal, 10, 0xF6, 12, charsetBinary},
+			{handler.ColumnTypeDate, 0, 0x0A, 10, charsetBinary},
+			{handler.ColumnTypeDatetime, 0, 0x0C, 19, charsetBinary},
+		}
+		for _, tt := range tests {
+			// GIVEN
+			col := &columnDefPacket{colType: tt.colType, precision: tt.precision}
+
+			// WHEN
+			columnType, columnLength, charset := col.typeInfo()
+
+			// THEN
+			assert.Equal(t, tt.columnType, columnType, tt.colType)
+			assert.Equal(t, tt.columnLength, columnLength, tt.colType)
+			assert.Equal(t, tt.charset, charset, tt.colType)
+		}
+	})
+
+	t.Run("DECIMAL のスケールが decimals に設定される", func(t *testing.T) {
+		// GIVEN
+		col := &columnDefPacket{tableName: "t", name: "price", colType: handler.ColumnTypeDecimal, precision: 10, scale: 2}
+
+		// WHEN
+		buf := col.build()
+
+		// THEN: 末尾は [column_type 1][flags 2][decimals 1][filler 2]
+		tail := buf[len(buf)-6:]
+		assert.Equal(t, byte(0xF6), tail[0])
+		assert.Equal(t, byte(2), tail[3])
+	})
+
+	t.Run("テーブルが属するデータベース名が schema に設定される", func(t *testing.T) {
+		// GIVEN
+		col := &columnDefPacket{schema: "app", tableName: "users", name: "id"}
+
+		// WHEN
+		buf := col.build()
+
+		// THEN
+		_, pos, err := readLenEncString(buf)
+		require.NoError(t, err)
+		schema, pos, err := readLenEncString(pos)
+		require.NoError(t, err)
+		assert.Equal(t, "app", schema)
+		table, _, err := readLenEncString(pos)
+		require.NoError(t, err)
+		assert.Equal(t, "users", table)
+	})
+}
+
 func TestColumnDefPacketImplementsPacket(t *testing.T) {
 	t.Run("packet interface を実装している", func(t *testing.T) {
 		// GIVEN
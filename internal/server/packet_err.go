@@ -1,10 +1,18 @@
 package server
 
+import (
+	"errors"
+
+	"github.com/ren-yamanashi/minesql/internal/executor"
+)
+
 // エラーコード定数
 const (
-	erAccessDenied uint16 = 1045
-	erParseError   uint16 = 1064
-	erUnknownError uint16 = 1105
+	erAccessDenied            uint16 = 1045
+	erBadDb                   uint16 = 1049
+	erParseError              uint16 = 1064
+	erUnknownError            uint16 = 1105
+	erCheckConstraintViolated uint16 = 3819
 )
 
 // SQL State 定数
@@ -28,6 +36,28 @@ type errPacket struct {
 	message   string
 }
 
+// newQueryErrPacket はクエリの実行エラーから ERR_Packet を作成する
+//
+// MySQL のエラーコードに対応するエラーはそのエラーコードを使い、それ以外は erUnknownError を使う
+func newQueryErrPacket(err error) *errPacket {
+	errorCode := erUnknownError
+	sqlState := sqlStateGeneralError
+	var checkErr *executor.CheckConstraintViolationError
+	var dbErr *unknownDatabaseError
+	switch {
+	case errors.As(err, &checkErr):
+		errorCode = erCheckConstraintViolated
+	case errors.As(err, &dbErr):
+		errorCode = erBadDb
+		sqlState = sqlStateSyntaxError
+	}
+	return &errPacket{
+		errorCode: errorCode,
+		sqlState:  sqlState,
+		message:   err.Error(),
+	}
+}
+
 // build は ERR_Packet のペイロードを構築する
 func (p *errPacket) build() []byte {
 	buf := []byte{0xFF}
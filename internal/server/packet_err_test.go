@@ -1,8 +1,11 @@
 package server
 
 import (
+	"errors"
+	"fmt"
 	"testing"
 
+	"github.com/ren-yamanashi/minesql/internal/executor"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -44,6 +47,47 @@ func TestErrPacketBuild(t *testing.T) {
 	})
 }
 
+func TestNewQueryErrPacket(t *testing.T) {
+	t.Run("CHECK 制約違反のエラーは ER_CHECK_CONSTRAINT_VIOLATED になる", func(t *testing.T) {
+		// GIVEN
+		err := fmt.Errorf("insert failed: %w", &executor.CheckConstraintViolationError{Name: "users_chk_1"})
+
+		// WHEN
+		pkt := newQueryErrPacket(err)
+
+		// THEN
+		assert.Equal(t, erCheckConstraintViolated, pkt.errorCode)
+		assert.Equal(t, sqlStateGeneralError, pkt.sqlState)
+		assert.Equal(t, err.Error(), pkt.message)
+	})
+
+	t.Run("存在しないデータベースのエラーは ER_BAD_DB_ERROR になる", func(t *testing.T) {
+		// GIVEN
+		err := &unknownDatabaseError{databaseName: "app"}
+
+		// WHEN
+		pkt := newQueryErrPacket(err)
+
+		// THEN
+		assert.Equal(t, erBadDb, pkt.errorCode)
+		assert.Equal(t, sqlStateSyntaxError, pkt.sqlState)
+		assert.Equal(t, "Unknown database 'app'", pkt.message)
+	})
+
+	t.Run("それ以外のエラーは ER_UNKNOWN_ERROR になる", func(t *testing.T) {
+		// GIVEN
+		err := errors.New("table users not found")
+
+		// WHEN
+		pkt := newQueryErrPacket(err)
+
+		// THEN
+		assert.Equal(t, erUnknownError, pkt.errorCode)
+		assert.Equal(t, sqlStateGeneralError, pkt.sqlState)
+		assert.Equal(t, "table users not found", pkt.message)
+	})
+}
+
 func TestErrPacketImplementsPacket(t *testing.T) {
 	t.Run("packet interface を実装している", func(t *testing.T) {
 		// GIVEN
@@ -59,6 +59,9 @@ func readUint32(buf []byte) uint32 {
 	return binary.LittleEndian.Uint32(buf)
 }
 
+// nullField は Text Resultset の Row パケットで NULL を表す値
+const nullField byte = 0xFB
+
 // --- 長さエンコード整数 ---
 
 // putLenEncInt は長さエンコード整数を buf に追記して返す
@@ -14,6 +14,7 @@ const (
 type queryResult struct {
 	resultType   resultType
 	affectedRows uint64
+	lastInsertId uint64 // INSERT で採番した AUTO_INCREMENT の値 (採番していない場合は 0)
 	columns      []columnDefPacket
 	records      []executor.Record
 }
@@ -22,11 +23,7 @@ type queryResult struct {
 func (s *Server) onComQuery(cc *clientConn, sess *session, sql string) {
 	result, err := s.onQuery(sess, sql)
 	if err != nil {
-		_ = cc.writePacket((&errPacket{
-			errorCode: erUnknownError,
-			sqlState:  sqlStateGeneralError,
-			message:   err.Error(),
-		}).build())
+		_ = cc.writePacket(newQueryErrPacket(err).build())
 		return
 	}
 
@@ -35,6 +32,7 @@ func (s *Server) onComQuery(cc *clientConn, sess *session, sql string) {
 	case resultOK:
 		_ = cc.writePacket((&okPacket{
 			affectedRows: result.affectedRows,
+			lastInsertId: result.lastInsertId,
 			statusFlags:  statusFlags,
 		}).build())
 	case resultResultSet:
@@ -87,10 +85,14 @@ func writeResultSet(cc *clientConn, result *queryResult, statusFlags uint16, dep
 
 // buildRowPacket は Row パケットのペイロードを構築する
 //
-// 各フィールドを長さエンコード文字列で格納する
+// 各フィールドを長さエンコード文字列で格納する (NULL のフィールドは nullField で表す)
 func buildRowPacket(record executor.Record) []byte {
 	var buf []byte
 	for _, field := range record {
+		if field == nil {
+			buf = append(buf, nullField)
+			continue
+		}
 		buf = putLenEncString(buf, string(field))
 	}
 	return buf
@@ -230,6 +230,27 @@ func TestBuildRowPacket(t *testing.T) {
 		assert.Empty(t, rest)
 	})
 
+	t.Run("NULL のフィールドは 0xFB で表される", func(t *testing.T) {
+		// GIVEN
+		record := executor.Record{[]byte("1"), nil, []byte("")}
+
+		// WHEN
+		buf := buildRowPacket(record)
+
+		// THEN
+		val1, rest, err := readLenEncString(buf)
+		require.NoError(t, err)
+		assert.Equal(t, "1", val1)
+
+		assert.Equal(t, byte(0xFB), rest[0])
+		rest = rest[1:]
+
+		val3, rest, err := readLenEncString(rest)
+		require.NoError(t, err)
+		assert.Equal(t, "", val3)
+		assert.Empty(t, rest)
+	})
+
 	t.Run("空のレコード", func(t *testing.T) {
 		// GIVEN
 		record := executor.Record{}
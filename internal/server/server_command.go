@@ -9,9 +9,10 @@ import (
 
 // コマンド種別の定数
 const (
-	comQuit  byte = 0x01
-	comQuery byte = 0x03
-	comPing  byte = 0x0e
+	comQuit   byte = 0x01
+	comInitDB byte = 0x02
+	comQuery  byte = 0x03
+	comPing   byte = 0x0e
 )
 
 // onCommand は Command Phase のループを実行する
@@ -44,6 +45,13 @@ func (s *Server) onCommand(cc *clientConn, sess *session) {
 			return
 		case comPing:
 			_ = cc.writePacket((&okPacket{statusFlags: s.statusFlags(sess)}).build())
+		case comInitDB:
+			// COM_INIT_DB はデータベースを切り替える (USE 文と同じ)
+			if err := sess.useDatabase(string(payload[1:])); err != nil {
+				_ = cc.writePacket(newQueryErrPacket(err).build())
+				continue
+			}
+			_ = cc.writePacket((&okPacket{statusFlags: s.statusFlags(sess)}).build())
 		case comQuery:
 			s.onComQuery(cc, sess, string(payload[1:]))
 		default:
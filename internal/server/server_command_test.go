@@ -169,4 +169,43 @@ func TestOnCommand(t *testing.T) {
 		_ = clientConn.writePacket([]byte{comQuit})
 		<-done
 	})
+
+	t.Run("COM_INIT_DB を受信するとデータベースを切り替える", func(t *testing.T) {
+		// GIVEN
+		s := setupTestServer(t)
+		defer handler.Reset()
+		_, err := s.onQuery(newSession("", 0), "CREATE DATABASE app;")
+		require.NoError(t, err)
+		serverConn, clientConn := createConnPair(t)
+		sess := newSession("", 0)
+
+		done := make(chan struct{})
+		go func() {
+			s.onCommand(serverConn, sess)
+			close(done)
+		}()
+
+		// WHEN: COM_INIT_DB で存在するデータベースと存在しないデータベースを送信
+		err = clientConn.writePacket(append([]byte{comInitDB}, []byte("app")...))
+		require.NoError(t, err)
+		okResp, err := clientConn.readPacket()
+		require.NoError(t, err)
+		clientConn.resetSequenceId()
+		err = clientConn.writePacket(append([]byte{comInitDB}, []byte("missing")...))
+		require.NoError(t, err)
+		errResp, err := clientConn.readPacket()
+		require.NoError(t, err)
+
+		// THEN: 存在するデータベースは OK_Packet、存在しないデータベースは ER_BAD_DB_ERROR の ERR_Packet が返る
+		assert.Equal(t, byte(0x00), okResp[0])
+		assert.Equal(t, byte(0xFF), errResp[0])
+		assert.Equal(t, erBadDb, uint16(errResp[1])|uint16(errResp[2])<<8)
+		assert.Contains(t, string(errResp), "Unknown database 'missing'")
+
+		// クリーンアップ
+		clientConn.resetSequenceId()
+		_ = clientConn.writePacket([]byte{comQuit})
+		<-done
+		assert.Equal(t, "app", sess.database)
+	})
 }
@@ -124,13 +124,24 @@ func (s *Server) onConnection(conn *net.TCPConn) (*clientConn, *session) {
 		}
 	}
 
+	// 接続時にデータベース名が指定された場合は、そのデータベースを選択する
+	sess := newSession(hsResp.username, hsResp.capability)
+	if hsResp.database != "" {
+		if err := sess.useDatabase(hsResp.database); err != nil {
+			if writeErr := cc.writePacket(newQueryErrPacket(err).build()); writeErr != nil {
+				log.Printf("Failed to send ERR packet: %v", writeErr)
+			}
+			return nil, nil
+		}
+	}
+
 	// OK_Packet の送信
 	if err := cc.writePacket((&okPacket{statusFlags: serverStatusAutocommit}).build()); err != nil {
 		log.Printf("Failed to send OK after auth: %v", err)
 		return nil, nil
 	}
 
-	return cc, newSession(hsResp.username, hsResp.capability)
+	return cc, sess
 }
 
 // completeAuth は Complete Authentication (平文パスワード受信) を実行する
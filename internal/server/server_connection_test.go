@@ -254,6 +254,58 @@ func TestOnConnection(t *testing.T) {
 		<-done
 	})
 
+	t.Run("接続時に指定したデータベースが session で選択される", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			database string
+			accepted bool
+		}{
+			{"存在するデータベース", "app", true},
+			{"存在しないデータベース", "missing", false},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				// GIVEN
+				s := setupTestServer(t)
+				defer handler.Reset()
+				require.NoError(t, handler.Get().CreateDatabase("app", false))
+
+				serverTCP, clientTCP := createTCPPair(t)
+				defer func() {
+					if err := serverTCP.Close(); err != nil {
+						t.Errorf("failed to close serverTCP: %v", err)
+					}
+					if err := clientTCP.Close(); err != nil {
+						t.Errorf("failed to close clientTCP: %v", err)
+					}
+				}()
+
+				resultCh := make(chan *session, 1)
+				go func() {
+					_, sess := s.onConnection(serverTCP)
+					resultCh <- sess
+				}()
+
+				clientCC := newClientConn(clientTCP)
+
+				// WHEN
+				resp := performClientHandshakeWithDB(t, clientCC, clientTCP, tt.database)
+
+				// THEN: 存在するデータベースは OK パケット、存在しないデータベースは ER_BAD_DB_ERROR の ERR パケットが返る
+				sess := <-resultCh
+				if tt.accepted {
+					assert.Equal(t, byte(0x00), resp[0])
+					require.NotNil(t, sess)
+					assert.Equal(t, tt.database, sess.database)
+				} else {
+					assert.Equal(t, byte(0xFF), resp[0])
+					assert.Equal(t, erBadDb, readUint16(resp[1:3]))
+					assert.Nil(t, sess)
+				}
+			})
+		}
+	})
+
 	t.Run("TLS なし接続が拒否される", func(t *testing.T) {
 		// GIVEN
 		s := setupTestServer(t)
@@ -307,6 +359,18 @@ func TestOnConnection(t *testing.T) {
 func performClientHandshake(t *testing.T, clientCC *clientConn, rawConn net.Conn) {
 	t.Helper()
 
+	// OK パケットを受信
+	okPkt := performClientHandshakeWithDB(t, clientCC, rawConn, "")
+	assert.Equal(t, byte(0x00), okPkt[0])
+}
+
+// performClientHandshakeWithDB は接続時のデータベース名を指定してクライアント側のハンドシェイクを実行し、
+// 認証後にサーバーから受信したパケット (OK パケットまたは ERR パケット) を返す
+//
+// database が空の場合はデータベース名を指定しない (CLIENT_CONNECT_WITH_DB を立てない)
+func performClientHandshakeWithDB(t *testing.T, clientCC *clientConn, rawConn net.Conn, database string) []byte {
+	t.Helper()
+
 	// ハンドシェイクパケットを受信 (seq=0)
 	_, err := clientCC.readPacket()
 	require.NoError(t, err)
@@ -323,7 +387,7 @@ func performClientHandshake(t *testing.T, clientCC *clientConn, rawConn net.Conn
 
 	// ハンドシェイク応答を送信 (seq=2, TLS 上)
 	// キャッシュが空なので scramble の内容は問わない (Complete Auth になる)
-	hsResp := buildTestHandshakeResponse("root", make([]byte, 32))
+	hsResp := buildTestHandshakeResponseWithDB("root", make([]byte, 32), database)
 	err = clientCC.writePacket(hsResp)
 	require.NoError(t, err)
 
@@ -337,10 +401,10 @@ func performClientHandshake(t *testing.T, clientCC *clientConn, rawConn net.Conn
 	err = clientCC.writePacket(append([]byte("root"), 0x00))
 	require.NoError(t, err)
 
-	// OK パケットを受信
-	okPkt, err := clientCC.readPacket()
+	// OK パケット (または ERR パケット) を受信
+	resp, err := clientCC.readPacket()
 	require.NoError(t, err)
-	assert.Equal(t, byte(0x00), okPkt[0])
+	return resp
 }
 
 // buildSSLConnectionRequest は SSL 接続要求パケット (32 バイト) を構築する
@@ -399,7 +463,17 @@ func createTCPPair(t *testing.T) (serverConn *net.TCPConn, clientConn *net.TCPCo
 
 // buildTestHandshakeResponse はテスト用のハンドシェイク応答パケットを構築する
 func buildTestHandshakeResponse(username string, scramble []byte) []byte {
+	return buildTestHandshakeResponseWithDB(username, scramble, "")
+}
+
+// buildTestHandshakeResponseWithDB はテスト用に、接続時のデータベース名を指定したハンドシェイク応答パケットを構築する
+//
+// database が空の場合は CLIENT_CONNECT_WITH_DB を立てない
+func buildTestHandshakeResponseWithDB(username string, scramble []byte, database string) []byte {
 	capability := serverCapability &^ clientConnectWithDB
+	if database != "" {
+		capability |= clientConnectWithDB
+	}
 	var buf []byte
 
 	cap := make([]byte, 4)
@@ -415,6 +489,9 @@ func buildTestHandshakeResponse(username string, scramble []byte) []byte {
 	buf = putNullTermString(buf, username)
 	buf = append(buf, byte(len(scramble)))
 	buf = append(buf, scramble...)
+	if database != "" {
+		buf = putNullTermString(buf, database)
+	}
 	buf = putNullTermString(buf, authPluginName)
 
 	return buf
@@ -8,6 +8,7 @@ import (
 	"github.com/ren-yamanashi/minesql/internal/executor"
 	"github.com/ren-yamanashi/minesql/internal/parser"
 	"github.com/ren-yamanashi/minesql/internal/planner"
+	"github.com/ren-yamanashi/minesql/internal/storage/dictionary"
 	"github.com/ren-yamanashi/minesql/internal/storage/handler"
 )
 
@@ -36,8 +37,46 @@ func (s *Server) onQuery(sess *session, sql string) (*queryResult, error) {
 		return s.executeTransaction(sess, txStmt)
 	}
 
+	// USE 文はセッションの状態のみを変更するため、planner を通さず直接処理する
+	if useStmt, ok := node.(*ast.UseStmt); ok {
+		if err := sess.useDatabase(useStmt.DatabaseName); err != nil {
+			return nil, err
+		}
+		return &queryResult{resultType: resultOK}, nil
+	}
+
+	// DROP/TRUNCATE/ALTER TABLE, CREATE/DROP INDEX, CREATE/DROP VIEW, CREATE/DROP DATABASE は MySQL と同様に、実行前に進行中のトランザクションを暗黙的にコミットする
+	// (作成・削除・切り詰め・定義変更したオブジェクトへの変更が後からロールバックされないようにするため)
+	if isImplicitCommitStmt(node) && sess.trxId != 0 {
+		if err := handler.Get().CommitTrx(sess.trxId); err != nil {
+			return nil, err
+		}
+		sess.trxId = 0
+	}
+
 	// それ以外は planner を通して実行する
-	return s.executeQuery(sess, node)
+	result, err := s.executeQuery(sess, node)
+	if err != nil {
+		return nil, err
+	}
+
+	// 選択中のデータベースを削除した場合は、データベースを選択していない状態に戻す
+	if dropStmt, ok := node.(*ast.DropDatabaseStmt); ok && dropStmt.DatabaseName == sess.database {
+		sess.database = ""
+	}
+	return result, nil
+}
+
+// isImplicitCommitStmt は実行前に暗黙的にコミットする文かどうかを判定する
+func isImplicitCommitStmt(node ast.Statement) bool {
+	switch node.(type) {
+	case *ast.DropTableStmt, *ast.TruncateTableStmt, *ast.AlterTableStmt,
+		*ast.CreateIndexStmt, *ast.DropIndexStmt, *ast.CreateViewStmt, *ast.DropViewStmt,
+		*ast.CreateDatabaseStmt, *ast.DropDatabaseStmt:
+		return true
+	default:
+		return false
+	}
 }
 
 // executeTransaction はトランザクション制御文 (BEGIN/COMMIT/ROLLBACK) を実行する
@@ -83,7 +122,8 @@ func (s *Server) executeQuery(sess *session, node ast.Statement) (*queryResult,
 		trxId = hdl.BeginTrx()
 	}
 
-	// 実行計画の作成
+	// 実行計画の作成 (データベース名で修飾されていないテーブル名は、選択中のデータベースのテーブルとして扱う)
+	planner.BindDatabase(node, sess.database)
 	plan, err := planner.Start(trxId, node)
 	if err != nil {
 		if autocommit {
@@ -91,6 +131,8 @@ func (s *Server) executeQuery(sess *session, node ast.Statement) (*queryResult,
 		}
 		return nil, err
 	}
+	// 途中でエラーになった場合も、Executor が書き出した一時ファイルを削除する
+	defer plan.Exec.Close()
 
 	// クエリの実行
 	var records []executor.Record
@@ -120,7 +162,25 @@ func (s *Server) executeQuery(sess *session, node ast.Statement) (*queryResult,
 	if plan.Columns != nil {
 		columns := make([]columnDefPacket, len(plan.Columns))
 		for i, col := range plan.Columns {
-			columns[i] = columnDefPacket{tableName: col.TableName, name: col.ColName}
+			schema, tableName := dictionary.SplitName(col.TableName)
+			columns[i] = columnDefPacket{
+				schema:    schema,
+				tableName: tableName,
+				name:      col.ColName,
+				colType:   col.Type,
+				precision: col.Precision,
+				scale:     col.Scale,
+			}
+		}
+		// 格納形式の値をテキスト表現に変換する (Text Protocol では値を文字列として送信するため)
+		// NULL (nil) は Row パケットで NULL として送信するためそのまま残す
+		for _, record := range records {
+			for i, field := range record {
+				if field == nil {
+					continue
+				}
+				record[i] = []byte(plan.Columns[i].FormatValue(field))
+			}
 		}
 		return &queryResult{
 			resultType: resultResultSet,
@@ -129,8 +189,17 @@ func (s *Server) executeQuery(sess *session, node ast.Statement) (*queryResult,
 		}, nil
 	}
 
+	// INSERT で AUTO_INCREMENT の値を採番した場合は、OK パケットの last_insert_id で返す
+	// INSERT の影響を受けた行数は、MySQL と同様に ON DUPLICATE KEY UPDATE・REPLACE の動作に応じて数える
+	var lastInsertId uint64
+	if ins, ok := plan.Exec.(*executor.Insert); ok {
+		lastInsertId = uint64(ins.LastInsertId())
+		affectedRows = ins.AffectedRows()
+	}
+
 	return &queryResult{
 		resultType:   resultOK,
 		affectedRows: affectedRows,
+		lastInsertId: lastInsertId,
 	}, nil
 }
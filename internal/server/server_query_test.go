@@ -1,6 +1,9 @@
 package server
 
 import (
+	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -216,6 +219,826 @@ func TestExecuteQuery(t *testing.T) {
 	})
 }
 
+func TestExecuteQueryTypedColumns(t *testing.T) {
+	t.Run("INT の主キーは数値順でレンジスキャンされる", func(t *testing.T) {
+		// GIVEN
+		s, sess := setupTestServerWithQueries(t,
+			"CREATE TABLE items (id INT, name VARCHAR, PRIMARY KEY (id));",
+			"INSERT INTO items (id, name) VALUES (100, 'a'), (-5, 'b'), (20, 'c'), (3, 'd');",
+		)
+		defer handler.Reset()
+
+		// WHEN
+		result, err := s.onQuery(sess, "SELECT * FROM items WHERE id >= 3;")
+
+		// THEN: 文字列順 ("100" < "20" < "3") ではなく数値順で返る
+		require.NoError(t, err)
+		assert.Equal(t, "3,d\n20,c\n100,a\n", resultToCSV(result))
+	})
+
+	t.Run("DECIMAL, DATE, DATETIME の値をテキスト表現で返す", func(t *testing.T) {
+		// GIVEN
+		s, sess := setupTestServerWithQueries(t,
+			"CREATE TABLE orders (id BIGINT, price DECIMAL(8, 2), ordered_on DATE, created_at DATETIME, PRIMARY KEY (id));",
+			"INSERT INTO orders (id, price, ordered_on, created_at) VALUES (1, 12.5, '2024-02-29', '2024-03-01 09:30:00'), (2, -0.125, '2023-12-31', '2023-12-31');",
+		)
+		defer handler.Reset()
+
+		// WHEN
+		result, err := s.onQuery(sess, "SELECT * FROM orders WHERE price < 10;")
+
+		// THEN: DECIMAL はスケールの桁数で丸められ、DATETIME の時刻省略は 00:00:00 になる
+		require.NoError(t, err)
+		assert.Equal(t, "2,-0.13,2023-12-31,2023-12-31 00:00:00\n", resultToCSV(result))
+		assert.Equal(t, handler.ColumnTypeDecimal, result.columns[1].colType)
+		assert.Equal(t, uint8(2), result.columns[1].scale)
+	})
+
+	t.Run("UPDATE で型付きカラムの値を更新できる", func(t *testing.T) {
+		// GIVEN
+		s, sess := setupTestServerWithQueries(t,
+			"CREATE TABLE items (id INT, qty INT, PRIMARY KEY (id));",
+			"INSERT INTO items (id, qty) VALUES (1, 5);",
+		)
+		defer handler.Reset()
+
+		// WHEN
+		_, err := s.onQuery(sess, "UPDATE items SET qty = -12 WHERE id = 1;")
+
+		// THEN
+		require.NoError(t, err)
+		result, err := s.onQuery(sess, "SELECT * FROM items;")
+		require.NoError(t, err)
+		assert.Equal(t, "1,-12\n", resultToCSV(result))
+	})
+
+	errorTests := []struct {
+		name     string
+		sql      string
+		expected string
+	}{
+		{"INT に数値でない値を INSERT する", "INSERT INTO items (id, created_on) VALUES ('abc', '2024-01-01');", "incorrect int value: 'abc' for column 'id'"},
+		{"INT の範囲を超える値を INSERT する", "INSERT INTO items (id, created_on) VALUES (2147483648, '2024-01-01');", "incorrect int value: '2147483648' for column 'id'"},
+		{"存在しない日付を INSERT する", "INSERT INTO items (id, created_on) VALUES (2, '2024-13-01');", "incorrect date value: '2024-13-01' for column 'created_on'"},
+		{"DECIMAL(4, 2) の整数部の桁数を超える値に UPDATE する", "UPDATE items SET price = 100 WHERE id = 1;", "incorrect decimal value: '100' for column 'price'"},
+	}
+	for _, tt := range errorTests {
+		t.Run("データ型に合わない値はエラーになる: "+tt.name, func(t *testing.T) {
+			// GIVEN
+			s, sess := setupTestServerWithQueries(t,
+				"CREATE TABLE items (id INT, created_on DATE, price DECIMAL(4, 2), PRIMARY KEY (id));",
+				"INSERT INTO items (id, created_on, price) VALUES (1, '2024-01-01', 10);",
+			)
+			defer handler.Reset()
+
+			// WHEN
+			_, err := s.onQuery(sess, tt.sql)
+
+			// THEN
+			assert.ErrorContains(t, err, tt.expected)
+		})
+	}
+}
+
+func TestExecuteQueryNull(t *testing.T) {
+	setupQueries := []string{
+		"CREATE TABLE users (id INT, name VARCHAR NOT NULL, email VARCHAR, age INT, PRIMARY KEY (id), UNIQUE KEY email_uk (email));",
+		"INSERT INTO users (id, name, email, age) VALUES (1, 'Alice', 'alice@example.com', 30), (2, 'Bob', NULL, 25), (3, 'Carol', NULL, NULL);",
+		"INSERT INTO users (id, name) VALUES (4, 'Dave');",
+	}
+
+	t.Run("NULL を挿入・取得でき、省略したカラムは NULL になる", func(t *testing.T) {
+		// GIVEN
+		s, sess := setupTestServerWithQueries(t, setupQueries...)
+		defer handler.Reset()
+
+		// WHEN
+		result, err := s.onQuery(sess, "SELECT * FROM users;")
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, "1,Alice,alice@example.com,30\n2,Bob,NULL,25\n3,Carol,NULL,NULL\n4,Dave,NULL,NULL\n", resultToCSV(result))
+		assert.Nil(t, result.records[1][2])
+	})
+
+	tests := []struct {
+		name     string
+		sql      string
+		expected string
+	}{
+		{"IS NULL と IS NOT NULL を組み合わせて絞り込める", "SELECT id FROM users WHERE email IS NULL AND age IS NOT NULL;", "2\n"},
+		{"IS NOT NULL で絞り込める", "SELECT id FROM users WHERE email IS NOT NULL;", "1\n"},
+		{"NULL との比較は UNKNOWN となり、どの行にも一致しない", "SELECT id FROM users WHERE email = NULL;", ""},
+		{"NULL のカラムとの != の比較は UNKNOWN となり除外される", "SELECT id FROM users WHERE age != 30;", "2\n"},
+		{"NULL のカラムとの < の比較は UNKNOWN となり除外される", "SELECT id FROM users WHERE email < 'z';", "1\n"},
+		{"NULL のカラムとの > の比較は UNKNOWN となり除外される", "SELECT id FROM users WHERE email > 'a';", "1\n"},
+		// id = 3 の行は age > 20 が UNKNOWN だが id = 3 が TRUE
+		{"OR では一方が TRUE なら UNKNOWN を含んでも一致する", "SELECT id FROM users WHERE age > 20 OR id = 3;", "1\n2\n3\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// GIVEN
+			s, sess := setupTestServerWithQueries(t, setupQueries...)
+			defer handler.Reset()
+
+			// WHEN
+			result, err := s.onQuery(sess, tt.sql)
+
+			// THEN
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, resultToCSV(result))
+		})
+	}
+
+	t.Run("UNIQUE KEY のカラムには NULL を複数格納できる", func(t *testing.T) {
+		// GIVEN
+		s, sess := setupTestServerWithQueries(t, setupQueries...)
+		defer handler.Reset()
+
+		// WHEN
+		_, err := s.onQuery(sess, "INSERT INTO users (id, name, email) VALUES (5, 'Eve', NULL);")
+
+		// THEN
+		require.NoError(t, err)
+		_, err = s.onQuery(sess, "INSERT INTO users (id, name, email) VALUES (6, 'Frank', 'alice@example.com');")
+		assert.Error(t, err)
+	})
+
+	t.Run("UPDATE で NULL を設定・解除できる", func(t *testing.T) {
+		// GIVEN
+		s, sess := setupTestServerWithQueries(t, setupQueries...)
+		defer handler.Reset()
+
+		// WHEN
+		_, err1 := s.onQuery(sess, "UPDATE users SET email = NULL WHERE id = 1;")
+		_, err2 := s.onQuery(sess, "UPDATE users SET email = 'bob@example.com' WHERE id = 2;")
+
+		// THEN
+		require.NoError(t, err1)
+		require.NoError(t, err2)
+		result, err := s.onQuery(sess, "SELECT id, email FROM users WHERE id <= 2;")
+		require.NoError(t, err)
+		assert.Equal(t, "1,NULL\n2,bob@example.com\n", resultToCSV(result))
+		result, err = s.onQuery(sess, "SELECT id FROM users WHERE email = 'bob@example.com';")
+		require.NoError(t, err)
+		assert.Equal(t, "2\n", resultToCSV(result))
+	})
+
+	errorTests := []struct {
+		name     string
+		sql      string
+		expected string
+	}{
+		{"NULL を INSERT する", "INSERT INTO users (id, name) VALUES (5, NULL);", "column 'name' cannot be null"},
+		{"INSERT でカラムを省略する", "INSERT INTO users (id, email) VALUES (6, 'x@example.com');", "column 'name' cannot be null"},
+		{"INSERT で主キーを省略する", "INSERT INTO users (name) VALUES ('Eve');", "column 'id' cannot be null"},
+		{"NULL に UPDATE する", "UPDATE users SET name = NULL WHERE id = 1;", "column 'name' cannot be null"},
+	}
+	for _, tt := range errorTests {
+		t.Run("NOT NULL 制約のカラムに NULL を設定するとエラーになる: "+tt.name, func(t *testing.T) {
+			// GIVEN
+			s, sess := setupTestServerWithQueries(t, setupQueries...)
+			defer handler.Reset()
+
+			// WHEN
+			_, err := s.onQuery(sess, tt.sql)
+
+			// THEN
+			assert.ErrorContains(t, err, tt.expected)
+		})
+	}
+}
+
+func TestExecuteQueryNullIndexKey(t *testing.T) {
+	// a = 5 (id 1-30) と a = 7 (id 31-60) の行の b は奇数の id で NULL、id 61-64 の行は a が NULL
+	rows := make([]string, 0, 64)
+	for id := 1; id <= 64; id++ {
+		a, b := "NULL", "NULL"
+		switch {
+		case id <= 30:
+			a = "5"
+		case id <= 60:
+			a = "7"
+		}
+		if id%2 == 0 {
+			b = fmt.Sprint(id)
+		}
+		rows = append(rows, fmt.Sprintf("(%d, %s, %s)", id, a, b))
+	}
+	setupQueries := []string{
+		"CREATE TABLE t (id INT, a INT, b INT, PRIMARY KEY (id), KEY idx_ab (a, b));",
+		"INSERT INTO t (id, a, b) VALUES " + strings.Join(rows, ", ") + ";",
+	}
+
+	tests := []struct {
+		name     string
+		sql      string
+		expected string
+	}{
+		{"= でインデックスの後ろのカラムが NULL の行も一致する", "SELECT COUNT(*) FROM t WHERE a = 5;", "30\n"},
+		{"IN でインデックスの後ろのカラムが NULL の行も一致する", "SELECT COUNT(*) FROM t WHERE a IN (5, 7);", "60\n"},
+		{"インデックスを使わない条件と同じ件数になる", "SELECT COUNT(*) FROM t WHERE a + 0 = 5;", "30\n"},
+		{"< でインデックスの先頭のカラムが NULL の行は一致しない", "SELECT COUNT(*) FROM t WHERE a < 7;", "30\n"},
+		{"<= でインデックスの先頭のカラムが NULL の行は一致しない", "SELECT COUNT(*) FROM t WHERE a <= 7;", "60\n"},
+		{"IS NULL でインデックスのカラムが NULL の行が一致する", "SELECT COUNT(*) FROM t WHERE a IS NULL;", "4\n"},
+		{"インデックスの複数のカラムで絞り込める", "SELECT id FROM t WHERE a = 5 AND b = 10;", "10\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// GIVEN
+			s, sess := setupTestServerWithQueries(t, setupQueries...)
+			defer handler.Reset()
+
+			// WHEN
+			result, err := s.onQuery(sess, tt.sql)
+
+			// THEN
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, resultToCSV(result))
+		})
+	}
+}
+
+func TestExecuteQueryOrderBy(t *testing.T) {
+	setupQueries := []string{
+		"CREATE TABLE users (id INT, name VARCHAR, age INT, PRIMARY KEY (id));",
+		"INSERT INTO users (id, name, age) VALUES (1, 'Carol', 30), (2, 'Alice', 25), (3, 'Bob', 30), (4, 'Dave', NULL), (10, 'Eve', 25);",
+		"CREATE TABLE orders (id INT, user_id INT, amount INT, PRIMARY KEY (id), KEY user_id_idx (user_id));",
+		"INSERT INTO orders (id, user_id, amount) VALUES (1, 2, 500), (2, 1, 300), (3, 2, 100);",
+	}
+
+	tests := []struct {
+		name     string
+		sql      string
+		expected string
+	}{
+		// NULL は降順では末尾に並ぶ
+		{"複数カラムの昇順・降順で並べ替えられる", "SELECT name, age FROM users ORDER BY age DESC, name;", "Bob,30\nCarol,30\nAlice,25\nEve,25\nDave,NULL\n"},
+		// 文字列順 ("10" < "2") ではなく数値順で返る
+		{"数値のカラムは数値順で並べ替えられる", "SELECT id FROM users WHERE id > 1 ORDER BY id DESC;", "10\n4\n3\n2\n"},
+		{"JOIN の結果を並べ替えられる", "SELECT users.name, orders.amount FROM users JOIN orders ON users.id = orders.user_id ORDER BY orders.amount;", "Alice,100\nCarol,300\nAlice,500\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// GIVEN
+			s, sess := setupTestServerWithQueries(t, setupQueries...)
+			defer handler.Reset()
+
+			// WHEN
+			result, err := s.onQuery(sess, tt.sql)
+
+			// THEN
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, resultToCSV(result))
+		})
+	}
+
+	t.Run("ソートバッファを超える場合も一時ファイルを使って並べ替えられる", func(t *testing.T) {
+		// GIVEN
+		s, sess := setupTestServerWithQueries(t, setupQueries...)
+		defer handler.Reset()
+		t.Setenv("MINESQL_SORT_BUFFER_SIZE", "1")
+
+		// WHEN
+		result, err := s.onQuery(sess, "SELECT id FROM users ORDER BY name DESC;")
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, "10\n4\n1\n3\n2\n", resultToCSV(result))
+	})
+
+	t.Run("ソート結果を最後まで読み取らない場合も、文の実行後に一時ファイルが削除される", func(t *testing.T) {
+		// GIVEN
+		s, sess := setupTestServerWithQueries(t, setupQueries...)
+		defer handler.Reset()
+		t.Setenv("MINESQL_SORT_BUFFER_SIZE", "1")
+
+		// WHEN
+		result, err := s.onQuery(sess, "SELECT id FROM users ORDER BY name DESC LIMIT 1;")
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, "10\n", resultToCSV(result))
+		tmpFiles, err := filepath.Glob(filepath.Join(os.Getenv("MINESQL_DATA_DIR"), "minesql-*.tmp"))
+		require.NoError(t, err)
+		assert.Empty(t, tmpFiles)
+	})
+
+	t.Run("ORDER BY に存在しないカラムを指定するとエラーになる", func(t *testing.T) {
+		// GIVEN
+		s, sess := setupTestServerWithQueries(t, setupQueries...)
+		defer handler.Reset()
+
+		// WHEN
+		_, err := s.onQuery(sess, "SELECT * FROM users ORDER BY unknown;")
+
+		// THEN
+		assert.ErrorContains(t, err, "column unknown not found")
+	})
+}
+
+func TestExecuteQueryLimit(t *testing.T) {
+	setupQueries := []string{
+		"CREATE TABLE users (id INT, name VARCHAR, PRIMARY KEY (id));",
+		"INSERT INTO users (id, name) VALUES (1, 'Carol'), (2, 'Alice'), (3, 'Bob'), (4, 'Dave'), (10, 'Eve');",
+		"CREATE TABLE orders (id INT, user_id INT, amount INT, PRIMARY KEY (id), KEY user_id_idx (user_id));",
+		"INSERT INTO orders (id, user_id, amount) VALUES (1, 2, 500), (2, 1, 300), (3, 2, 100);",
+	}
+
+	tests := []struct {
+		name     string
+		sql      string
+		expected string
+	}{
+		{"LIMIT で返す件数を制限できる", "SELECT id FROM users LIMIT 2;", "1\n2\n"},
+		{"OFFSET で先頭の行を読み飛ばせる", "SELECT id FROM users ORDER BY id LIMIT 2 OFFSET 3;", "4\n10\n"},
+		{"並べ替えた結果に LIMIT を適用できる", "SELECT name FROM users ORDER BY name DESC LIMIT 3;", "Eve\nDave\nCarol\n"},
+		{"JOIN の結果に LIMIT を適用できる", "SELECT users.name, orders.amount FROM users JOIN orders ON users.id = orders.user_id ORDER BY orders.amount DESC LIMIT 1 OFFSET 1;", "Carol,300\n"},
+		{"OFFSET が行数以上の場合は空の結果を返す", "SELECT id FROM users LIMIT 10 OFFSET 5;", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// GIVEN
+			s, sess := setupTestServerWithQueries(t, setupQueries...)
+			defer handler.Reset()
+
+			// WHEN
+			result, err := s.onQuery(sess, tt.sql)
+
+			// THEN
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, resultToCSV(result))
+		})
+	}
+
+	t.Run("LIMIT に負の数を指定するとエラーになる", func(t *testing.T) {
+		// GIVEN
+		s, sess := setupTestServerWithQueries(t, setupQueries...)
+		defer handler.Reset()
+
+		// WHEN
+		_, err := s.onQuery(sess, "SELECT id FROM users LIMIT -1;")
+
+		// THEN
+		assert.ErrorContains(t, err, "LIMIT and OFFSET must be non-negative integers")
+	})
+}
+
+func TestExecuteQueryAggregate(t *testing.T) {
+	setupQueries := []string{
+		"CREATE TABLE products (id INT, category VARCHAR NOT NULL, price DECIMAL(10, 2), stock INT, PRIMARY KEY (id), KEY category_idx (category));",
+		"INSERT INTO products (id, category, price, stock) VALUES (1, 'food', 1.50, 10), (2, 'book', 12.00, NULL), (3, 'food', 2.25, 5), (4, 'toy', 8.00, 3), (5, 'book', 20.00, 7), (6, 'food', NULL, 2);",
+	}
+
+	tests := []struct {
+		name     string
+		sql      string
+		expected string
+	}{
+		{"集約関数でテーブル全体を集約でき、NULL は集約の対象にならない", "SELECT COUNT(*), COUNT(price), SUM(stock), MIN(price), MAX(price), AVG(stock) FROM products;", "6,5,27,1.50,20.00,5.4000\n"},
+		{"GROUP BY でグループごとに集約できる", "SELECT category, COUNT(*), SUM(price) FROM products GROUP BY category ORDER BY category;", "book,2,32.00\nfood,3,3.75\ntoy,1,8.00\n"},
+		// food の AVG(price) は NULL を除いた (1.50 + 2.25) / 2
+		{"HAVING で集約後のグループを絞り込める", "SELECT AVG(price), category FROM products GROUP BY category HAVING COUNT(*) >= 2 ORDER BY category DESC;", "1.875000,food\n16.000000,book\n"},
+		// category_idx の index-only scan でグループ化キーの順に読み取る
+		{"インデックスのカラムでグループ化した結果はインデックスの順に返る", "SELECT category, COUNT(*) FROM products GROUP BY category;", "book,2\nfood,3\ntoy,1\n"},
+		{"一致する行がない場合 COUNT(*) は 0、その他の集約関数は NULL を返す", "SELECT COUNT(*), SUM(stock) FROM products WHERE id > 100;", "0,NULL\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// GIVEN
+			s, sess := setupTestServerWithQueries(t, setupQueries...)
+			defer handler.Reset()
+
+			// WHEN
+			result, err := s.onQuery(sess, tt.sql)
+
+			// THEN
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, resultToCSV(result))
+		})
+	}
+
+	t.Run("グループがバッファサイズを超えても一時ファイルを使って集約できる", func(t *testing.T) {
+		// GIVEN
+		t.Setenv("MINESQL_AGGREGATE_BUFFER_SIZE", "1")
+		s, sess := setupTestServerWithQueries(t, setupQueries...)
+		defer handler.Reset()
+
+		// WHEN
+		result, err := s.onQuery(sess, "SELECT stock, COUNT(*) FROM products WHERE id < 6 GROUP BY stock HAVING stock IS NOT NULL ORDER BY stock;")
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, "3,1\n5,1\n7,1\n10,1\n", resultToCSV(result))
+	})
+
+	t.Run("JOIN の結果をグループ化できる", func(t *testing.T) {
+		// GIVEN
+		s, sess := setupTestServerWithQueries(t, append(setupQueries,
+			"CREATE TABLE sales (id INT, product_id INT, quantity INT, PRIMARY KEY (id));",
+			"INSERT INTO sales (id, product_id, quantity) VALUES (1, 1, 3), (2, 3, 4), (3, 5, 1), (4, 1, 2);",
+		)...)
+		defer handler.Reset()
+
+		// WHEN
+		result, err := s.onQuery(sess, "SELECT products.category, SUM(sales.quantity) FROM sales JOIN products ON sales.product_id = products.id GROUP BY products.category ORDER BY products.category;")
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, "book,1\nfood,9\n", resultToCSV(result))
+	})
+
+	errorTests := []struct {
+		name     string
+		sql      string
+		expected string
+	}{
+		{"GROUP BY に含まれないカラムを SELECT するとエラーになる", "SELECT id, COUNT(*) FROM products GROUP BY category;", "column id must appear in the GROUP BY clause or be used in an aggregate function"},
+		{"文字列のカラムに SUM を指定するとエラーになる", "SELECT SUM(category) FROM products;", "SUM requires a numeric column"},
+	}
+	for _, tt := range errorTests {
+		t.Run(tt.name, func(t *testing.T) {
+			// GIVEN
+			s, sess := setupTestServerWithQueries(t, setupQueries...)
+			defer handler.Reset()
+
+			// WHEN
+			_, err := s.onQuery(sess, tt.sql)
+
+			// THEN
+			assert.ErrorContains(t, err, tt.expected)
+		})
+	}
+}
+
+func TestExecuteQueryExpression(t *testing.T) {
+	setupQueries := []string{
+		"CREATE TABLE items (id INT, name VARCHAR NOT NULL, price DECIMAL(10, 2), qty INT, min_qty INT, PRIMARY KEY (id));",
+		"INSERT INTO items (id, name, price, qty, min_qty) VALUES (1, 'apple', 1.50, 10, 5), (2, 'Banana', 0.25, 3, 5), (3, 'cherry', 4.00, NULL, 1), (4, 'durian', NULL, 2, 2);",
+	}
+
+	t.Run("SELECT リストで算術演算の結果を返す", func(t *testing.T) {
+		// GIVEN
+		s, sess := setupTestServerWithQueries(t, setupQueries...)
+		defer handler.Reset()
+
+		// WHEN
+		result, err := s.onQuery(sess, "SELECT id, price * qty, qty + 1, -qty, qty % 3 FROM items;")
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, "price * qty", result.columns[1].name)
+		assert.Equal(t, "1,15.00,11,-10,1\n2,0.75,4,-3,0\n3,NULL,NULL,NULL,NULL\n4,NULL,3,-2,2\n", resultToCSV(result))
+	})
+
+	tests := []struct {
+		name     string
+		sql      string
+		expected string
+	}{
+		{"除算の結果は小数部が 4 桁増え、0 除算は NULL になる", "SELECT qty / 4, price / qty, qty / (min_qty - 5) FROM items WHERE id <= 2;", "2.5000,0.150000,NULL\n0.7500,0.083333,NULL\n"},
+		{"括弧で演算の優先順位を変更できる", "SELECT (qty + 2) * 3, qty + 2 * 3 FROM items WHERE id = 1;", "36,16\n"},
+		{"スカラー関数を呼び出せる", "SELECT UPPER(name), LOWER(name), LENGTH(name), CONCAT(name, '-', id), COALESCE(qty, 0), ABS(-price), ROUND(price, 1) FROM items WHERE id <= 2;", "APPLE,apple,5,apple-1,10,1.50,1.5\nBANANA,banana,6,Banana-2,3,0.25,0.3\n"},
+		{"CASE 式で条件に応じた値を返す", "SELECT id, CASE WHEN qty >= min_qty THEN 'ok' WHEN qty IS NULL THEN 'unknown' ELSE 'short' END, CASE id WHEN 1 THEN 'one' END FROM items;", "1,ok,one\n2,short,NULL\n3,unknown,NULL\n4,ok,NULL\n"},
+		{"WHERE 句でカラム同士を比較できる", "SELECT id FROM items WHERE qty < min_qty OR price * qty > 10;", "1\n2\n"},
+		{"WHERE 句で定数式を使える", "SELECT id, name FROM items WHERE id = 1 + 2;", "3,cherry\n"},
+		{"集約関数の結果を式で使える", "SELECT SUM(qty) * 2, MAX(price) - MIN(price) FROM items HAVING SUM(qty) + 1 > 10;", "30,3.75\n"},
+		// PK のレンジ分析でも小数を前後の整数に置き換える (> 2.5 は >= 3、<= 2.5 は <= 2)
+		{"INT のカラムと小数を比較できる", "SELECT id FROM items WHERE id > 2.5;", "3\n4\n"},
+		{"INT のカラムと小数を比較できる (<=)", "SELECT id FROM items WHERE id <= 2.5;", "1\n2\n"},
+		{"INT のカラムと INT で表せない小数は等しくならない", "SELECT id FROM items WHERE id = 2.5 OR id IN (3.5, 4);", "4\n"},
+		{"DECIMAL のカラムとスケールより桁数の多い小数を四捨五入せずに比較する", "SELECT id FROM items WHERE price BETWEEN 0.245 AND 1.499;", "2\n"},
+		{"COALESCE・IFNULL の引数のデータ型が異なる場合は文字列として返す", "SELECT COALESCE(price, 'none'), IFNULL(qty, 'n') FROM items WHERE id >= 3;", "4.00,n\nnone,2\n"},
+		{"文字列のカラムと引用符で囲んだ数字は文字列として比較する", "SELECT id FROM items WHERE name > '9' AND id <= 2;", "1\n2\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// GIVEN
+			s, sess := setupTestServerWithQueries(t, setupQueries...)
+			defer handler.Reset()
+
+			// WHEN
+			result, err := s.onQuery(sess, tt.sql)
+
+			// THEN
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, resultToCSV(result))
+		})
+	}
+
+	t.Run("DATE のカラムと時刻を含む値を比較できる", func(t *testing.T) {
+		// GIVEN
+		s, sess := setupTestServerWithQueries(t, append(setupQueries,
+			"CREATE TABLE events (day DATE, name VARCHAR, PRIMARY KEY (day));",
+			"INSERT INTO events (day, name) VALUES ('2023-12-30', 'a'), ('2023-12-31', 'b'), ('2024-01-01', 'c');",
+		)...)
+		defer handler.Reset()
+
+		// WHEN
+		after, afterErr := s.onQuery(sess, "SELECT name FROM events WHERE day > '2023-12-31 10:00:00';")
+		before, beforeErr := s.onQuery(sess, "SELECT name FROM events WHERE day <= '2023-12-31 10:00:00';")
+
+		// THEN
+		require.NoError(t, afterErr)
+		require.NoError(t, beforeErr)
+		assert.Equal(t, "c\n", resultToCSV(after))
+		assert.Equal(t, "a\nb\n", resultToCSV(before))
+	})
+
+	t.Run("UPDATE の SET 句でカラムを参照する式を使える", func(t *testing.T) {
+		// GIVEN
+		s, sess := setupTestServerWithQueries(t, setupQueries...)
+		defer handler.Reset()
+
+		// WHEN
+		_, err := s.onQuery(sess, "UPDATE items SET qty = qty + 1, price = price * 2 WHERE id <= 2;")
+		require.NoError(t, err)
+		result, err := s.onQuery(sess, "SELECT id, qty, price FROM items;")
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, "1,11,3.00\n2,4,0.50\n3,NULL,4.00\n4,2,NULL\n", resultToCSV(result))
+	})
+
+	t.Run("UPDATE の SET 句は前の SET 句で更新した値を参照する", func(t *testing.T) {
+		// GIVEN
+		s, sess := setupTestServerWithQueries(t, setupQueries...)
+		defer handler.Reset()
+
+		// WHEN
+		_, err := s.onQuery(sess, "UPDATE items SET qty = qty * 2, min_qty = qty WHERE id = 1;")
+		require.NoError(t, err)
+		result, err := s.onQuery(sess, "SELECT qty, min_qty FROM items WHERE id = 1;")
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, "20,20\n", resultToCSV(result))
+	})
+
+	errorTests := []struct {
+		name     string
+		sql      string
+		expected string
+	}{
+		{"UPDATE の式の結果が NOT NULL のカラムに NULL を代入するとエラーになる", "UPDATE items SET name = CONCAT(name, qty) WHERE id = 3;", "column 'name' cannot be null"},
+		{"UPDATE の式の結果がカラムの範囲を超えるとエラーになる", "UPDATE items SET qty = qty * 1000000000 WHERE id = 1;", "out of range value for column 'qty'"},
+		{"文字列に算術演算を指定するとエラーになる", "SELECT name + 1 FROM items;", "arithmetic operator + requires numeric operands"},
+		{"文字列のカラムと数値を比較するとエラーになる", "SELECT id FROM items WHERE name > 9;", "incompatible types: string and bigint"},
+		{"文字列のカラムと数値のリストを IN で比較するとエラーになる", "SELECT id FROM items WHERE name IN (5, 7);", "incompatible types: string and bigint"},
+		{"BIGINT の範囲を超える演算はエラーになる", "SELECT 9223372036854775807 * 2 FROM items;", "numeric value is out of range"},
+		{"DECIMAL の精度と BIGINT の範囲を超える数値はエラーになる", "SELECT 99999999999999999999 * 2 FROM items;", "numeric value 99999999999999999999 is out of range"},
+	}
+	for _, tt := range errorTests {
+		t.Run(tt.name, func(t *testing.T) {
+			// GIVEN
+			s, sess := setupTestServerWithQueries(t, setupQueries...)
+			defer handler.Reset()
+
+			// WHEN
+			_, err := s.onQuery(sess, tt.sql)
+
+			// THEN
+			assert.ErrorContains(t, err, tt.expected)
+		})
+	}
+}
+
+func TestExecuteQueryPredicate(t *testing.T) {
+	setupQueries := []string{
+		"CREATE TABLE items (id INT, name VARCHAR NOT NULL, price DECIMAL(10, 2), qty INT, PRIMARY KEY (id), UNIQUE KEY name (name));",
+		"INSERT INTO items (id, name, price, qty) VALUES (1, 'apple', 1.50, 10), (2, 'Banana', 0.25, 3), (3, 'cherry', 4.00, NULL), (4, 'durian', NULL, 2);",
+	}
+
+	tests := []struct {
+		name     string
+		sql      string
+		expected string
+	}{
+		{"IN", "SELECT id FROM items WHERE id IN (4, 1, 9);", "1\n4\n"},
+		{"NOT IN", "SELECT id FROM items WHERE qty NOT IN (3, 4);", "1\n4\n"},
+		{"NOT IN のリストに NULL を含む場合はどの行にも一致しない", "SELECT id FROM items WHERE qty NOT IN (3, NULL);", ""},
+		{"BETWEEN", "SELECT id FROM items WHERE price BETWEEN 0.25 AND 1.5;", "1\n2\n"},
+		{"NOT BETWEEN", "SELECT id FROM items WHERE id NOT BETWEEN 2 AND 3;", "1\n4\n"},
+		{"LIKE は大文字・小文字を区別する", "SELECT id FROM items WHERE name LIKE 'b%' OR name LIKE '%rr%';", "3\n"},
+		{"インデックス付きカラムの前方一致の LIKE", "SELECT name FROM items WHERE name LIKE 'ch_rry%';", "cherry\n"},
+		{"NOT LIKE", "SELECT id FROM items WHERE name NOT LIKE '%a%';", "3\n"},
+		{"NOT の対象が NULL の場合は一致しない", "SELECT id FROM items WHERE NOT (qty > 2);", "4\n"},
+		{"SELECT リストで述語を評価できる", "SELECT id, qty BETWEEN 2 AND 5, name IN ('apple', 'cherry') FROM items;", "1,0,1\n2,1,0\n3,NULL,1\n4,1,0\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// GIVEN
+			s, sess := setupTestServerWithQueries(t, setupQueries...)
+			defer handler.Reset()
+
+			// WHEN
+			result, err := s.onQuery(sess, tt.sql)
+
+			// THEN
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, resultToCSV(result))
+		})
+	}
+
+	t.Run("UPDATE / DELETE の WHERE 句で述語を使える", func(t *testing.T) {
+		// GIVEN
+		s, sess := setupTestServerWithQueries(t, setupQueries...)
+		defer handler.Reset()
+
+		// WHEN
+		_, err := s.onQuery(sess, "UPDATE items SET qty = 0 WHERE id IN (1, 2);")
+		require.NoError(t, err)
+		_, err = s.onQuery(sess, "DELETE FROM items WHERE name LIKE 'd%' OR price NOT BETWEEN 1 AND 5;")
+		require.NoError(t, err)
+		result, err := s.onQuery(sess, "SELECT id, qty FROM items;")
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, "1,0\n3,NULL\n", resultToCSV(result))
+	})
+}
+
+func TestExecuteQueryOuterJoin(t *testing.T) {
+	setupQueries := []string{
+		"CREATE TABLE users (id INT, name VARCHAR, PRIMARY KEY (id));",
+		"INSERT INTO users (id, name) VALUES (1, 'Alice'), (2, 'Bob'), (3, 'Carol');",
+		"CREATE TABLE orders (id INT, user_id INT, item_id INT, PRIMARY KEY (id), KEY user_id_idx (user_id));",
+		"INSERT INTO orders (id, user_id, item_id) VALUES (10, 1, 100), (11, 1, 101), (12, 3, 999), (13, NULL, 100);",
+		"CREATE TABLE items (id INT, title VARCHAR, PRIMARY KEY (id));",
+		"INSERT INTO items (id, title) VALUES (100, 'pen'), (101, 'ink');",
+	}
+
+	tests := []struct {
+		name     string
+		sql      string
+		expected string
+	}{
+		{
+			"LEFT JOIN で一致する行がない左側の行は右側のカラムを NULL で補完して返す",
+			"SELECT users.name, orders.id FROM users LEFT JOIN orders ON users.id = orders.user_id ORDER BY users.id, orders.id;",
+			"Alice,10\nAlice,11\nBob,NULL\nCarol,12\n",
+		},
+		{
+			"LEFT OUTER JOIN と WHERE の IS NULL で一致しない行のみを取り出せる",
+			"SELECT users.name FROM users LEFT OUTER JOIN orders ON users.id = orders.user_id WHERE orders.id IS NULL;",
+			"Bob\n",
+		},
+		{
+			"RIGHT JOIN で一致する行がない右側の行は左側のカラムを NULL で補完して返す",
+			"SELECT orders.id, users.name FROM users RIGHT JOIN orders ON users.id = orders.user_id ORDER BY orders.id;",
+			"10,Alice\n11,Alice\n12,Carol\n13,NULL\n",
+		},
+		{
+			"LEFT JOIN を連鎖でき、NULL で補完された行は後続の LEFT JOIN でも NULL で補完される",
+			"SELECT users.name, items.title FROM users LEFT JOIN orders ON users.id = orders.user_id LEFT JOIN items ON orders.item_id = items.id ORDER BY users.id, orders.id;",
+			"Alice,pen\nAlice,ink\nBob,NULL\nCarol,NULL\n",
+		},
+		{
+			"LEFT JOIN の後の INNER JOIN は NULL で補完された行を取り除く",
+			"SELECT users.name, items.title FROM users LEFT JOIN orders ON users.id = orders.user_id JOIN items ON orders.item_id = items.id ORDER BY orders.id;",
+			"Alice,pen\nAlice,ink\n",
+		},
+		{
+			"ON 条件の複数の等値条件はすべて一致の判定に使う",
+			"SELECT users.name, orders.id FROM users LEFT JOIN orders ON users.id = orders.user_id AND users.id = orders.item_id ORDER BY users.id;",
+			"Alice,NULL\nBob,NULL\nCarol,NULL\n",
+		},
+		{
+			"LEFT JOIN の結果を集約できる",
+			"SELECT users.name, COUNT(orders.id) FROM users LEFT JOIN orders ON users.id = orders.user_id GROUP BY users.name ORDER BY users.name;",
+			"Alice,2\nBob,0\nCarol,1\n",
+		},
+		{
+			"LEFT JOIN の ON 条件の等値条件以外の条件を満たさない行は一致せず、一致する行がなければ NULL で補完する",
+			"SELECT users.name, orders.id FROM users LEFT JOIN orders ON users.id = orders.user_id AND orders.item_id > 100 ORDER BY users.id, orders.id;",
+			"Alice,11\nBob,NULL\nCarol,12\n",
+		},
+		{
+			"LEFT JOIN の ON 条件で左側のテーブルのカラムを条件にすると、条件を満たさない左側の行は NULL で補完する",
+			"SELECT users.name, orders.id FROM users LEFT JOIN orders ON users.id = orders.user_id AND users.name <> 'Alice' ORDER BY users.id;",
+			"Alice,NULL\nBob,NULL\nCarol,12\n",
+		},
+		{
+			"RIGHT JOIN の ON 条件の等値条件以外の条件を満たさない行は一致せず、一致する行がなければ NULL で補完する",
+			"SELECT orders.id, users.name FROM users RIGHT JOIN orders ON users.id = orders.user_id AND users.name = 'Carol' ORDER BY orders.id;",
+			"10,NULL\n11,NULL\n12,Carol\n13,NULL\n",
+		},
+		{
+			"INNER JOIN の ON 条件の等値条件以外の条件で結合結果を絞り込める",
+			"SELECT users.name, orders.id FROM users JOIN orders ON users.id = orders.user_id AND orders.item_id > 100 ORDER BY orders.id;",
+			"Alice,11\nCarol,12\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// GIVEN
+			s, sess := setupTestServerWithQueries(t, setupQueries...)
+			defer handler.Reset()
+
+			// WHEN
+			result, err := s.onQuery(sess, tt.sql)
+
+			// THEN
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, resultToCSV(result))
+		})
+	}
+
+	t.Run("2 番目以降の JOIN に RIGHT JOIN を指定するとエラーになる", func(t *testing.T) {
+		// GIVEN
+		s, sess := setupTestServerWithQueries(t, setupQueries...)
+		defer handler.Reset()
+
+		// WHEN
+		_, err := s.onQuery(sess, "SELECT * FROM users JOIN orders ON users.id = orders.user_id RIGHT JOIN items ON orders.item_id = items.id;")
+
+		// THEN
+		assert.ErrorContains(t, err, "RIGHT JOIN items is not supported")
+	})
+
+	t.Run("外部結合の ON 条件で結合条件で結ばないテーブルを参照するとエラーになる", func(t *testing.T) {
+		// GIVEN
+		s, sess := setupTestServerWithQueries(t, setupQueries...)
+		defer handler.Reset()
+
+		// WHEN
+		_, err := s.onQuery(sess, "SELECT * FROM users JOIN orders ON users.id = orders.user_id LEFT JOIN items ON orders.item_id = items.id AND users.id > 1;")
+
+		// THEN
+		assert.ErrorContains(t, err, "ON clause of OUTER JOIN items cannot refer to table users")
+	})
+}
+
+func TestExecuteQueryHashJoin(t *testing.T) {
+	// 結合カラムにインデックスがないテーブル同士の結合
+	setupQueries := []string{
+		"CREATE TABLE customers (id INT, name VARCHAR, city VARCHAR, PRIMARY KEY (id));",
+		"INSERT INTO customers (id, name, city) VALUES (1, 'Alice', 'Tokyo'), (2, 'Bob', 'Osaka'), (3, 'Carol', NULL), (4, 'Dave', 'Nagoya');",
+		"CREATE TABLE shops (id INT, title VARCHAR, city VARCHAR, PRIMARY KEY (id));",
+		"INSERT INTO shops (id, title, city) VALUES (10, 'north', 'Tokyo'), (11, 'south', 'Tokyo'), (12, 'west', 'Osaka'), (13, 'none', NULL);",
+	}
+
+	tests := []struct {
+		name     string
+		sql      string
+		expected string
+	}{
+		{
+			"インデックスのないカラム同士の等値条件で結合でき、NULL はどの行とも一致しない",
+			"SELECT customers.name, shops.title FROM customers JOIN shops ON customers.city = shops.city ORDER BY customers.id, shops.id;",
+			"Alice,north\nAlice,south\nBob,west\n",
+		},
+		{
+			"LEFT JOIN で一致する行がない左側の行は右側のカラムを NULL で補完して返す",
+			"SELECT customers.name, shops.title FROM customers LEFT JOIN shops ON customers.city = shops.city ORDER BY customers.id, shops.id;",
+			"Alice,north\nAlice,south\nBob,west\nCarol,NULL\nDave,NULL\n",
+		},
+		{
+			"LEFT JOIN の ON 条件の等値条件以外の条件を満たさない行は一致せず、一致する行がなければ NULL で補完する",
+			"SELECT customers.name, shops.title FROM customers LEFT JOIN shops ON customers.city = shops.city AND shops.id > 10 ORDER BY customers.id, shops.id;",
+			"Alice,south\nBob,west\nCarol,NULL\nDave,NULL\n",
+		},
+		{
+			"LEFT JOIN の ON 条件の等値条件以外の条件ですべての行が一致しない場合、NULL で補完する",
+			"SELECT customers.name, shops.title FROM customers LEFT JOIN shops ON customers.city = shops.city AND shops.title = 'east' ORDER BY customers.id;",
+			"Alice,NULL\nBob,NULL\nCarol,NULL\nDave,NULL\n",
+		},
+		{
+			"結合の結果を WHERE 句で絞り込める",
+			"SELECT customers.name, shops.title FROM customers JOIN shops ON customers.city = shops.city WHERE shops.id > 10 ORDER BY customers.id;",
+			"Alice,south\nBob,west\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// GIVEN
+			s, sess := setupTestServerWithQueries(t, setupQueries...)
+			defer handler.Reset()
+
+			// WHEN
+			result, err := s.onQuery(sess, tt.sql)
+
+			// THEN
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, resultToCSV(result))
+		})
+	}
+
+	t.Run("結合バッファを超える場合も一時ファイルを使って結合できる", func(t *testing.T) {
+		// GIVEN
+		t.Setenv("MINESQL_JOIN_BUFFER_SIZE", "1")
+		s, sess := setupTestServerWithQueries(t, setupQueries...)
+		defer handler.Reset()
+
+		// WHEN
+		result, err := s.onQuery(sess, "SELECT customers.name, shops.title FROM customers LEFT JOIN shops ON customers.city = shops.city ORDER BY customers.id, shops.id;")
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, "Alice,north\nAlice,south\nBob,west\nCarol,NULL\nDave,NULL\n", resultToCSV(result))
+	})
+}
+
 func TestExecuteQueryAlterUser(t *testing.T) {
 	t.Run("ALTER USER でパスワードを変更できる", func(t *testing.T) {
 		// GIVEN
@@ -748,10 +1571,123 @@ func TestResolveColumnInfo(t *testing.T) {
 	})
 }
 
-// setupTestServer はテスト用のサーバーを初期化する
-func setupTestServer(t *testing.T) *Server {
-	t.Helper()
-	tmpdir := t.TempDir()
+func TestExecuteQuerySubquery(t *testing.T) {
+	setupQueries := []string{
+		"CREATE TABLE users (id INT, name VARCHAR, dept VARCHAR, PRIMARY KEY (id));",
+		"INSERT INTO users (id, name, dept) VALUES (1, 'Alice', 'dev'), (2, 'Bob', 'dev'), (3, 'Carol', 'ops'), (4, 'Dave', NULL);",
+		"CREATE TABLE orders (id INT, user_id INT, amount DECIMAL(10, 2), PRIMARY KEY (id));",
+		"INSERT INTO orders (id, user_id, amount) VALUES (10, 1, 100.00), (11, 1, 250.50), (12, 3, 80.00), (13, NULL, 10.00);",
+	}
+
+	tests := []struct {
+		name     string
+		sql      string
+		expected string
+	}{
+		{
+			"WHERE 句のスカラーサブクエリと比較できる",
+			"SELECT id FROM orders WHERE amount > (SELECT AVG(amount) FROM orders) ORDER BY id;",
+			"11\n",
+		},
+		{
+			"SELECT リストの相関スカラーサブクエリは外側の行ごとに評価され、一致する行がない場合は NULL になる",
+			"SELECT name, (SELECT SUM(amount) FROM orders WHERE orders.user_id = users.id) FROM users ORDER BY id;",
+			"Alice,350.50\nBob,NULL\nCarol,80.00\nDave,NULL\n",
+		},
+		{
+			"IN (SELECT ...) で絞り込める",
+			"SELECT name FROM users WHERE id IN (SELECT user_id FROM orders WHERE amount >= 80) ORDER BY id;",
+			"Alice\nCarol\n",
+		},
+		{
+			"NOT IN (SELECT ...) はサブクエリの結果に NULL を含む場合どの行にも一致しない",
+			"SELECT name FROM users WHERE id NOT IN (SELECT user_id FROM orders);",
+			"",
+		},
+		{
+			"相関サブクエリの EXISTS で絞り込める",
+			"SELECT name FROM users WHERE EXISTS (SELECT * FROM orders WHERE orders.user_id = users.id) ORDER BY id;",
+			"Alice\nCarol\n",
+		},
+		{
+			"相関サブクエリの NOT EXISTS で絞り込める",
+			"SELECT name FROM users WHERE NOT EXISTS (SELECT * FROM orders WHERE orders.user_id = users.id AND orders.amount > 90) ORDER BY id;",
+			"Bob\nCarol\nDave\n",
+		},
+		{
+			"相関サブクエリの IN で絞り込める",
+			"SELECT name FROM users WHERE id IN (SELECT user_id FROM orders WHERE orders.user_id = users.id AND amount > 90) ORDER BY id;",
+			"Alice\n",
+		},
+		{
+			"OR と組み合わせた EXISTS は行ごとに評価する",
+			"SELECT name FROM users WHERE dept = 'ops' OR EXISTS (SELECT * FROM orders WHERE orders.user_id = users.id AND amount > 200) ORDER BY id;",
+			"Alice\nCarol\n",
+		},
+		{
+			"FROM 句の導出テーブルから SELECT できる",
+			"SELECT t.user_id, t.total FROM (SELECT user_id, SUM(amount) AS total FROM orders GROUP BY user_id) AS t WHERE t.total > 50 ORDER BY t.user_id;",
+			"1,350.50\n3,80.00\n",
+		},
+		{
+			"導出テーブルとテーブルを結合できる",
+			"SELECT users.name, t.cnt FROM users JOIN (SELECT user_id, COUNT(*) AS cnt FROM orders GROUP BY user_id) AS t ON users.id = t.user_id ORDER BY users.id;",
+			"Alice,2\nCarol,1\n",
+		},
+		{
+			"入れ子のサブクエリから外側のクエリのカラムを参照でき、同じテーブル名のカラムは内側のテーブルに解決される",
+			"SELECT name FROM users WHERE EXISTS (SELECT * FROM orders WHERE orders.user_id = users.id AND orders.amount = (SELECT MIN(amount) FROM orders WHERE orders.user_id = users.id AND orders.amount > 90)) ORDER BY id;",
+			"Alice\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// GIVEN
+			s, sess := setupTestServerWithQueries(t, setupQueries...)
+			defer handler.Reset()
+
+			// WHEN
+			result, err := s.onQuery(sess, tt.sql)
+
+			// THEN
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, resultToCSV(result))
+		})
+	}
+
+	t.Run("SELECT リストの別名が結果セットのカラム名になる", func(t *testing.T) {
+		// GIVEN
+		s, sess := setupTestServerWithQueries(t, setupQueries...)
+		defer handler.Reset()
+
+		// WHEN
+		result, err := s.onQuery(sess, "SELECT id AS user_id, (SELECT COUNT(*) FROM orders) AS order_count FROM users WHERE id = 1;")
+
+		// THEN
+		require.NoError(t, err)
+		require.Len(t, result.columns, 2)
+		assert.Equal(t, "user_id", result.columns[0].name)
+		assert.Equal(t, "order_count", result.columns[1].name)
+		assert.Equal(t, "1,4\n", resultToCSV(result))
+	})
+
+	t.Run("スカラーサブクエリが 2 行以上を返すとエラーになる", func(t *testing.T) {
+		// GIVEN
+		s, sess := setupTestServerWithQueries(t, setupQueries...)
+		defer handler.Reset()
+
+		// WHEN
+		_, err := s.onQuery(sess, "SELECT name FROM users WHERE id = (SELECT user_id FROM orders);")
+
+		// THEN
+		assert.ErrorContains(t, err, "subquery returns more than 1 row")
+	})
+}
+
+// setupTestServer はテスト用のサーバーを初期化する
+func setupTestServer(t *testing.T) *Server {
+	t.Helper()
+	tmpdir := t.TempDir()
 	t.Setenv("MINESQL_DATA_DIR", tmpdir)
 	t.Setenv("MINESQL_BUFFER_SIZE", "100")
 	handler.Reset()
@@ -772,15 +1708,1030 @@ func setupTestServer(t *testing.T) *Server {
 	return &Server{tlsConfig: tlsConfig}
 }
 
+// setupTestServerWithQueries はテスト用のサーバーを初期化し、新しいセッションで準備用のクエリを順に実行する
+func setupTestServerWithQueries(t *testing.T, queries ...string) (*Server, *session) {
+	t.Helper()
+	s := setupTestServer(t)
+	sess := newSession("", 0)
+	for _, sql := range queries {
+		_, err := s.onQuery(sess, sql)
+		require.NoError(t, err)
+	}
+	return s, sess
+}
+
 // resultToCSV は queryResult のレコードを CSV 形式の文字列に変換する (テスト用)
 func resultToCSV(result *queryResult) string {
 	var sb strings.Builder
 	for _, record := range result.records {
 		line := make([]string, len(record))
 		for i, col := range record {
+			if col == nil {
+				line[i] = "NULL"
+				continue
+			}
 			line[i] = string(col)
 		}
 		sb.WriteString(strings.Join(line, ",") + "\n")
 	}
 	return sb.String()
 }
+
+func TestExecuteQueryExplain(t *testing.T) {
+	setupQueries := []string{
+		"CREATE TABLE users (id INT, name VARCHAR, PRIMARY KEY (id));",
+		"INSERT INTO users (id, name) VALUES (1, 'Alice'), (2, 'Bob'), (3, 'Carol');",
+	}
+
+	t.Run("EXPLAIN で実行計画を結果セットとして返す", func(t *testing.T) {
+		// GIVEN
+		s, sess := setupTestServerWithQueries(t, setupQueries...)
+		defer handler.Reset()
+
+		// WHEN
+		result, err := s.onQuery(sess, "EXPLAIN SELECT name FROM users WHERE id = 2;")
+
+		// THEN
+		require.NoError(t, err)
+		require.Len(t, result.columns, 5)
+		assert.Equal(t, "operation", result.columns[0].name)
+		assert.Equal(t, "Project,NULL,NULL,NULL,NULL\n└── TableScan,users,PK lookup,1.00,1.00\n", resultToCSV(result))
+	})
+
+	t.Run("EXPLAIN ANALYZE で実際の行数とループ回数を返す", func(t *testing.T) {
+		// GIVEN
+		s, sess := setupTestServerWithQueries(t, setupQueries...)
+		defer handler.Reset()
+
+		// WHEN
+		result, err := s.onQuery(sess, "EXPLAIN ANALYZE SELECT name FROM users WHERE id >= 2;")
+
+		// THEN
+		require.NoError(t, err)
+		require.Len(t, result.columns, 8)
+		// Project → Filter → TableScan の 3 行。TableScan は 3 行を読み、Filter で 2 行に絞り込む
+		require.Len(t, result.records, 3)
+		assert.Equal(t, []string{"2", "1"}, []string{string(result.records[1][5]), string(result.records[1][6])})
+		assert.Equal(t, []string{"3", "1"}, []string{string(result.records[2][5]), string(result.records[2][6])})
+	})
+
+	t.Run("EXPLAIN ANALYZE UPDATE は実際に更新する", func(t *testing.T) {
+		// GIVEN
+		s, sess := setupTestServerWithQueries(t, setupQueries...)
+		defer handler.Reset()
+
+		// WHEN
+		_, err := s.onQuery(sess, "EXPLAIN ANALYZE UPDATE users SET name = 'Bobby' WHERE id = 2;")
+
+		// THEN
+		require.NoError(t, err)
+		result, err := s.onQuery(sess, "SELECT name FROM users WHERE id = 2;")
+		require.NoError(t, err)
+		assert.Equal(t, "Bobby\n", resultToCSV(result))
+	})
+}
+
+func TestExecuteQueryDropTable(t *testing.T) {
+	t.Run("DROP TABLE でテーブルを削除できる", func(t *testing.T) {
+		// GIVEN
+		s, sess := setupTestServerWithQueries(t,
+			"CREATE TABLE users (id VARCHAR, name VARCHAR, PRIMARY KEY (id));",
+		)
+		defer handler.Reset()
+
+		// WHEN
+		result, err := s.onQuery(sess, "DROP TABLE users;")
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, resultOK, result.resultType)
+		_, err = s.onQuery(sess, "SELECT * FROM users;")
+		assert.ErrorContains(t, err, "table users not found")
+	})
+
+	t.Run("削除したテーブルと同じ名前のテーブルを再作成できる", func(t *testing.T) {
+		// GIVEN
+		s, sess := setupTestServerWithQueries(t,
+			"CREATE TABLE users (id VARCHAR, name VARCHAR, PRIMARY KEY (id));",
+			"INSERT INTO users (id, name) VALUES ('1', 'Alice');",
+			"DROP TABLE users;",
+		)
+		defer handler.Reset()
+
+		// WHEN
+		_, err := s.onQuery(sess, "CREATE TABLE users (id VARCHAR, email VARCHAR, PRIMARY KEY (id));")
+		require.NoError(t, err)
+		_, err = s.onQuery(sess, "INSERT INTO users (id, email) VALUES ('1', 'bob@example.com');")
+		require.NoError(t, err)
+
+		// THEN
+		result, err := s.onQuery(sess, "SELECT * FROM users;")
+		require.NoError(t, err)
+		assert.Equal(t, "1,bob@example.com\n", resultToCSV(result))
+	})
+
+	t.Run("DROP TABLE IF EXISTS は存在しないテーブルでもエラーにならない", func(t *testing.T) {
+		// GIVEN
+		s := setupTestServer(t)
+		defer handler.Reset()
+		sess := newSession("", 0)
+
+		// WHEN
+		_, err := s.onQuery(sess, "DROP TABLE IF EXISTS users;")
+
+		// THEN
+		assert.NoError(t, err)
+		_, err = s.onQuery(sess, "DROP TABLE users;")
+		assert.ErrorContains(t, err, "table users not found")
+	})
+
+	t.Run("外部キーから参照されているテーブルは削除できない", func(t *testing.T) {
+		// GIVEN
+		s, sess := setupTestServerWithQueries(t,
+			"CREATE TABLE users (id VARCHAR, PRIMARY KEY (id));",
+			"CREATE TABLE orders (id VARCHAR, user_id VARCHAR, PRIMARY KEY (id), KEY idx_user_id (user_id), FOREIGN KEY fk_user (user_id) REFERENCES users (id));",
+		)
+		defer handler.Reset()
+
+		// WHEN
+		_, err := s.onQuery(sess, "DROP TABLE users;")
+
+		// THEN
+		assert.ErrorContains(t, err, "referenced by foreign key fk_user on table orders")
+		_, err = s.onQuery(sess, "TRUNCATE TABLE users;")
+		assert.ErrorContains(t, err, "referenced by foreign key fk_user on table orders")
+
+		// 参照元のテーブルを削除すれば削除できる
+		_, err = s.onQuery(sess, "DROP TABLE orders;")
+		require.NoError(t, err)
+		_, err = s.onQuery(sess, "DROP TABLE users;")
+		assert.NoError(t, err)
+	})
+
+	t.Run("TRUNCATE TABLE で全レコードを削除できる", func(t *testing.T) {
+		// GIVEN
+		s, sess := setupTestServerWithQueries(t,
+			"CREATE TABLE users (id VARCHAR, name VARCHAR, PRIMARY KEY (id), UNIQUE KEY idx_name (name));",
+			"INSERT INTO users (id, name) VALUES ('1', 'Alice'), ('2', 'Bob');",
+		)
+		defer handler.Reset()
+
+		// WHEN
+		_, err := s.onQuery(sess, "TRUNCATE TABLE users;")
+
+		// THEN
+		require.NoError(t, err)
+		result, err := s.onQuery(sess, "SELECT * FROM users;")
+		require.NoError(t, err)
+		assert.Empty(t, result.records)
+
+		// 同じキーのレコードを再び挿入できる
+		_, err = s.onQuery(sess, "INSERT INTO users (id, name) VALUES ('1', 'Alice');")
+		require.NoError(t, err)
+		result, err = s.onQuery(sess, "SELECT * FROM users WHERE name = 'Alice';")
+		require.NoError(t, err)
+		assert.Equal(t, "1,Alice\n", resultToCSV(result))
+	})
+
+	t.Run("トランザクション中の DROP TABLE は進行中のトランザクションを暗黙的にコミットする", func(t *testing.T) {
+		// GIVEN
+		s, sess := setupTestServerWithQueries(t,
+			"CREATE TABLE users (id VARCHAR, PRIMARY KEY (id));",
+			"CREATE TABLE logs (id VARCHAR, PRIMARY KEY (id));",
+			"BEGIN;",
+			"INSERT INTO users (id) VALUES ('1');",
+		)
+		defer handler.Reset()
+
+		// WHEN
+		_, err := s.onQuery(sess, "DROP TABLE logs;")
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, handler.TrxId(0), sess.trxId)
+		_, err = s.onQuery(sess, "ROLLBACK;")
+		assert.ErrorContains(t, err, "no active transaction")
+		result, err := s.onQuery(sess, "SELECT * FROM users;")
+		require.NoError(t, err)
+		assert.Equal(t, "1\n", resultToCSV(result))
+	})
+
+	for _, ddl := range []string{"DROP TABLE users;", "TRUNCATE TABLE users;"} {
+		t.Run("他のセッションのトランザクションが実行中の場合は "+ddl+" がエラーになる", func(t *testing.T) {
+			// GIVEN: セッション A が BEGIN + DELETE したまま
+			s, sessA := setupTestServerWithQueries(t,
+				"CREATE TABLE users (id INT, PRIMARY KEY (id));",
+				"INSERT INTO users (id) VALUES (1), (2);",
+				"BEGIN;",
+				"DELETE FROM users WHERE id = 1;",
+			)
+			defer handler.Reset()
+			sessB := newSession("", 0)
+
+			// WHEN
+			_, err := s.onQuery(sessB, ddl)
+
+			// THEN: エラーになり、セッション A はロールバックでき、テーブルは元の状態に戻る
+			assert.ErrorContains(t, err, "while other transactions are active")
+			_, err = s.onQuery(sessA, "ROLLBACK;")
+			require.NoError(t, err)
+			result, err := s.onQuery(sessB, "SELECT id FROM users;")
+			require.NoError(t, err)
+			assert.Equal(t, "1\n2\n", resultToCSV(result))
+
+			// セッション A のトランザクションが終了すれば実行できる
+			_, err = s.onQuery(sessB, ddl)
+			assert.NoError(t, err)
+		})
+
+		t.Run("他のセッションのトランザクションが別のテーブルだけを使用している場合は "+ddl+" を実行できる", func(t *testing.T) {
+			// GIVEN: セッション A が BEGIN + DELETE で logs だけを使用したまま
+			s, sessA := setupTestServerWithQueries(t,
+				"CREATE TABLE users (id INT, PRIMARY KEY (id));",
+				"CREATE TABLE logs (id INT, PRIMARY KEY (id));",
+				"INSERT INTO users (id) VALUES (1), (2);",
+				"INSERT INTO logs (id) VALUES (1), (2);",
+				"BEGIN;",
+				"DELETE FROM logs WHERE id = 1;",
+			)
+			defer handler.Reset()
+			sessB := newSession("", 0)
+
+			// WHEN
+			_, err := s.onQuery(sessB, ddl)
+
+			// THEN: セッション A はロールバックでき、logs は元の状態に戻る
+			require.NoError(t, err)
+			_, err = s.onQuery(sessA, "ROLLBACK;")
+			require.NoError(t, err)
+			result, err := s.onQuery(sessB, "SELECT id FROM logs;")
+			require.NoError(t, err)
+			assert.Equal(t, "1\n2\n", resultToCSV(result))
+		})
+	}
+}
+
+func TestExecuteQueryAlterTable(t *testing.T) {
+	t.Run("ADD COLUMN で追加したカラムは既存の行で NULL になる", func(t *testing.T) {
+		// GIVEN
+		s, sess := setupTestServerWithQueries(t,
+			"CREATE TABLE users (id VARCHAR, name VARCHAR, PRIMARY KEY (id));",
+			"INSERT INTO users (id, name) VALUES ('1', 'Alice');",
+		)
+		defer handler.Reset()
+
+		// WHEN
+		_, err := s.onQuery(sess, "ALTER TABLE users ADD COLUMN email VARCHAR;")
+
+		// THEN
+		require.NoError(t, err)
+		_, err = s.onQuery(sess, "INSERT INTO users (id, name, email) VALUES ('2', 'Bob', 'bob@example.com');")
+		require.NoError(t, err)
+		result, err := s.onQuery(sess, "SELECT * FROM users;")
+		require.NoError(t, err)
+		assert.Equal(t, "1,Alice,NULL\n2,Bob,bob@example.com\n", resultToCSV(result))
+	})
+
+	t.Run("DROP COLUMN で削除したカラムは参照できない", func(t *testing.T) {
+		// GIVEN
+		s, sess := setupTestServerWithQueries(t,
+			"CREATE TABLE users (id VARCHAR, name VARCHAR, email VARCHAR, PRIMARY KEY (id));",
+			"INSERT INTO users (id, name, email) VALUES ('1', 'Alice', 'alice@example.com');",
+		)
+		defer handler.Reset()
+
+		// WHEN
+		_, err := s.onQuery(sess, "ALTER TABLE users DROP COLUMN name;")
+
+		// THEN
+		require.NoError(t, err)
+		result, err := s.onQuery(sess, "SELECT * FROM users;")
+		require.NoError(t, err)
+		assert.Equal(t, "1,alice@example.com\n", resultToCSV(result))
+		_, err = s.onQuery(sess, "SELECT name FROM users;")
+		assert.Error(t, err)
+	})
+
+	t.Run("ADD UNIQUE KEY で追加したインデックスで重複を検出できる", func(t *testing.T) {
+		// GIVEN
+		s, sess := setupTestServerWithQueries(t,
+			"CREATE TABLE users (id VARCHAR, name VARCHAR, PRIMARY KEY (id));",
+			"INSERT INTO users (id, name) VALUES ('1', 'Alice'), ('2', 'Bob');",
+		)
+		defer handler.Reset()
+
+		// WHEN
+		_, err := s.onQuery(sess, "ALTER TABLE users ADD UNIQUE KEY uk_name (name);")
+
+		// THEN
+		require.NoError(t, err)
+		_, err = s.onQuery(sess, "INSERT INTO users (id, name) VALUES ('3', 'Alice');")
+		assert.Error(t, err)
+		result, err := s.onQuery(sess, "SELECT * FROM users WHERE name = 'Bob';")
+		require.NoError(t, err)
+		assert.Equal(t, "2,Bob\n", resultToCSV(result))
+	})
+
+	t.Run("ADD FOREIGN KEY で追加した外部キー制約が検査される", func(t *testing.T) {
+		// GIVEN
+		s, sess := setupTestServerWithQueries(t,
+			"CREATE TABLE users (id VARCHAR, PRIMARY KEY (id));",
+			"CREATE TABLE orders (id VARCHAR, user_id VARCHAR, PRIMARY KEY (id));",
+			"INSERT INTO users (id) VALUES ('1');",
+			"INSERT INTO orders (id, user_id) VALUES ('1', '1');",
+		)
+		defer handler.Reset()
+
+		// WHEN
+		_, err := s.onQuery(sess, "ALTER TABLE orders ADD INDEX idx_user_id (user_id);")
+		require.NoError(t, err)
+		_, err = s.onQuery(sess, "ALTER TABLE orders ADD FOREIGN KEY fk_user (user_id) REFERENCES users (id);")
+
+		// THEN
+		require.NoError(t, err)
+		_, err = s.onQuery(sess, "INSERT INTO orders (id, user_id) VALUES ('2', '9');")
+		assert.ErrorContains(t, err, "foreign key constraint fails")
+
+		// 外部キー制約を削除すれば挿入できる
+		_, err = s.onQuery(sess, "ALTER TABLE orders DROP FOREIGN KEY fk_user;")
+		require.NoError(t, err)
+		_, err = s.onQuery(sess, "INSERT INTO orders (id, user_id) VALUES ('2', '9');")
+		assert.NoError(t, err)
+	})
+
+	t.Run("ADD FOREIGN KEY で参照先に存在しない値がある場合、エラーメッセージにはカラムのデータ型に従った値を表示する", func(t *testing.T) {
+		// GIVEN
+		s, sess := setupTestServerWithQueries(t,
+			"CREATE TABLE users (id INT, PRIMARY KEY (id));",
+			"CREATE TABLE orders (id INT, user_id INT, PRIMARY KEY (id), KEY idx_user_id (user_id));",
+			"INSERT INTO users (id) VALUES (1);",
+			"INSERT INTO orders (id, user_id) VALUES (1, 1), (2, 9);",
+		)
+		defer handler.Reset()
+
+		// WHEN
+		_, err := s.onQuery(sess, "ALTER TABLE orders ADD FOREIGN KEY fk_user (user_id) REFERENCES users (id);")
+
+		// THEN
+		assert.EqualError(t, err, "cannot add foreign key fk_user: a foreign key constraint fails (value '9')")
+	})
+
+	t.Run("トランザクション中の ALTER TABLE は進行中のトランザクションを暗黙的にコミットする", func(t *testing.T) {
+		// GIVEN
+		s, sess := setupTestServerWithQueries(t,
+			"CREATE TABLE users (id VARCHAR, PRIMARY KEY (id));",
+			"BEGIN;",
+			"INSERT INTO users (id) VALUES ('1');",
+		)
+		defer handler.Reset()
+
+		// WHEN
+		_, err := s.onQuery(sess, "ALTER TABLE users ADD COLUMN name VARCHAR;")
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, handler.TrxId(0), sess.trxId)
+		_, err = s.onQuery(sess, "ROLLBACK;")
+		assert.ErrorContains(t, err, "no active transaction")
+		result, err := s.onQuery(sess, "SELECT * FROM users;")
+		require.NoError(t, err)
+		assert.Equal(t, 1, len(result.records))
+	})
+}
+
+func TestExecuteQueryAutoIncrement(t *testing.T) {
+	setupQueries := []string{
+		"CREATE TABLE items (id INT NOT NULL AUTO_INCREMENT, name VARCHAR, PRIMARY KEY (id));",
+	}
+
+	t.Run("AUTO_INCREMENT カラムを省略すると採番され、last_insert_id で返す", func(t *testing.T) {
+		// GIVEN
+		s, sess := setupTestServerWithQueries(t, setupQueries...)
+		defer handler.Reset()
+
+		// WHEN
+		result1, err1 := s.onQuery(sess, "INSERT INTO items (name) VALUES ('a');")
+		result2, err2 := s.onQuery(sess, "INSERT INTO items (name) VALUES ('b'), ('c');")
+
+		// THEN
+		require.NoError(t, err1)
+		require.NoError(t, err2)
+		assert.Equal(t, uint64(1), result1.lastInsertId)
+		assert.Equal(t, uint64(2), result2.lastInsertId)
+		result, err := s.onQuery(sess, "SELECT * FROM items;")
+		require.NoError(t, err)
+		assert.Equal(t, "1,a\n2,b\n3,c\n", resultToCSV(result))
+	})
+
+	t.Run("値を指定した場合は採番せず、以降はその次の値から採番する", func(t *testing.T) {
+		// GIVEN
+		s, sess := setupTestServerWithQueries(t, setupQueries...)
+		defer handler.Reset()
+
+		// WHEN
+		result1, err1 := s.onQuery(sess, "INSERT INTO items (id, name) VALUES (10, 'a');")
+		result2, err2 := s.onQuery(sess, "INSERT INTO items (id, name) VALUES (NULL, 'b');")
+
+		// THEN
+		require.NoError(t, err1)
+		require.NoError(t, err2)
+		assert.Equal(t, uint64(0), result1.lastInsertId)
+		assert.Equal(t, uint64(11), result2.lastInsertId)
+	})
+
+	t.Run("ロールバックしたトランザクションで採番した値は再利用しない", func(t *testing.T) {
+		// GIVEN
+		s, sess := setupTestServerWithQueries(t, append(setupQueries,
+			"BEGIN;",
+			"INSERT INTO items (name) VALUES ('a');",
+			"ROLLBACK;",
+		)...)
+		defer handler.Reset()
+
+		// WHEN
+		result, err := s.onQuery(sess, "INSERT INTO items (name) VALUES ('b');")
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, uint64(2), result.lastInsertId)
+	})
+}
+
+func TestExecuteQueryDefaultAndCheck(t *testing.T) {
+	setupQueries := []string{
+		"CREATE TABLE items (id INT, name VARCHAR DEFAULT 'unknown', price INT NOT NULL DEFAULT (10 * 2), PRIMARY KEY (id), CONSTRAINT chk_price CHECK (price > 0), CHECK (name <> ''));",
+	}
+
+	t.Run("INSERT で省略したカラムには DEFAULT 値が挿入される", func(t *testing.T) {
+		// GIVEN
+		s, sess := setupTestServerWithQueries(t, setupQueries...)
+		defer handler.Reset()
+
+		// WHEN
+		_, err := s.onQuery(sess, "INSERT INTO items (id) VALUES (1);")
+
+		// THEN
+		require.NoError(t, err)
+		result, err := s.onQuery(sess, "SELECT * FROM items;")
+		require.NoError(t, err)
+		assert.Equal(t, "1,unknown,20\n", resultToCSV(result))
+	})
+
+	t.Run("CHECK 制約を満たさない INSERT はエラーになり、行は挿入されない", func(t *testing.T) {
+		// GIVEN
+		s, sess := setupTestServerWithQueries(t, setupQueries...)
+		defer handler.Reset()
+
+		// WHEN
+		_, err1 := s.onQuery(sess, "INSERT INTO items (id, price) VALUES (1, 0);")
+		_, err2 := s.onQuery(sess, "INSERT INTO items (id, name) VALUES (2, '');")
+
+		// THEN
+		assert.EqualError(t, err1, "check constraint 'chk_price' is violated")
+		assert.EqualError(t, err2, "check constraint 'items_chk_1' is violated")
+		result, err := s.onQuery(sess, "SELECT * FROM items;")
+		require.NoError(t, err)
+		assert.Equal(t, "", resultToCSV(result))
+	})
+
+	t.Run("CHECK 制約の条件式が NULL になる場合は制約を満たすものとして扱う", func(t *testing.T) {
+		// GIVEN
+		s, sess := setupTestServerWithQueries(t, setupQueries...)
+		defer handler.Reset()
+
+		// WHEN
+		_, err := s.onQuery(sess, "INSERT INTO items (id, name) VALUES (1, NULL);")
+
+		// THEN
+		require.NoError(t, err)
+	})
+
+	t.Run("CHECK 制約を満たさない UPDATE はエラーになり、行は更新されない", func(t *testing.T) {
+		// GIVEN
+		s, sess := setupTestServerWithQueries(t, append(setupQueries,
+			"INSERT INTO items (id, price) VALUES (1, 100);",
+		)...)
+		defer handler.Reset()
+
+		// WHEN
+		_, err := s.onQuery(sess, "UPDATE items SET price = price - 100 WHERE id = 1;")
+
+		// THEN
+		assert.EqualError(t, err, "check constraint 'chk_price' is violated")
+		result, err := s.onQuery(sess, "SELECT price FROM items;")
+		require.NoError(t, err)
+		assert.Equal(t, "100\n", resultToCSV(result))
+	})
+
+	t.Run("ADD COLUMN で DEFAULT 句を指定すると既存の行に DEFAULT 値が設定される", func(t *testing.T) {
+		// GIVEN
+		s, sess := setupTestServerWithQueries(t, append(setupQueries,
+			"INSERT INTO items (id) VALUES (1);",
+		)...)
+		defer handler.Reset()
+
+		// WHEN
+		_, err := s.onQuery(sess, "ALTER TABLE items ADD COLUMN stock INT NOT NULL DEFAULT 0;")
+
+		// THEN
+		require.NoError(t, err)
+		result, err := s.onQuery(sess, "SELECT id, stock FROM items;")
+		require.NoError(t, err)
+		assert.Equal(t, "1,0\n", resultToCSV(result))
+	})
+}
+
+func TestExecuteQueryForeignKeyAction(t *testing.T) {
+	setupQueries := []string{
+		"CREATE TABLE users (id INT, name VARCHAR, PRIMARY KEY (id));",
+		"CREATE TABLE orders (id INT, user_id INT, PRIMARY KEY (id), KEY idx_user_id (user_id), FOREIGN KEY fk_user (user_id) REFERENCES users (id) ON DELETE CASCADE ON UPDATE CASCADE);",
+		"CREATE TABLE order_items (id INT, order_id INT, PRIMARY KEY (id), KEY idx_order_id (order_id), FOREIGN KEY fk_order (order_id) REFERENCES orders (id) ON DELETE SET NULL);",
+		"INSERT INTO users (id, name) VALUES (1, 'Alice'), (2, 'Bob');",
+		"INSERT INTO orders (id, user_id) VALUES (10, 1), (20, 2);",
+		"INSERT INTO order_items (id, order_id) VALUES (100, 10), (200, 20);",
+	}
+
+	t.Run("ON DELETE CASCADE と ON DELETE SET NULL が連鎖して適用される", func(t *testing.T) {
+		// GIVEN
+		s, sess := setupTestServerWithQueries(t, setupQueries...)
+		defer handler.Reset()
+
+		// WHEN
+		_, err := s.onQuery(sess, "DELETE FROM users WHERE id = 1;")
+
+		// THEN
+		require.NoError(t, err)
+		result, err := s.onQuery(sess, "SELECT * FROM orders;")
+		require.NoError(t, err)
+		assert.Equal(t, "20,2\n", resultToCSV(result))
+		result, err = s.onQuery(sess, "SELECT * FROM order_items;")
+		require.NoError(t, err)
+		assert.Equal(t, "100,NULL\n200,20\n", resultToCSV(result))
+	})
+
+	t.Run("ON UPDATE CASCADE で参照元の行の FK カラムが新しい値になる", func(t *testing.T) {
+		// GIVEN
+		s, sess := setupTestServerWithQueries(t, setupQueries...)
+		defer handler.Reset()
+
+		// WHEN
+		_, err := s.onQuery(sess, "UPDATE users SET id = 3 WHERE id = 2;")
+
+		// THEN
+		require.NoError(t, err)
+		result, err := s.onQuery(sess, "SELECT * FROM orders;")
+		require.NoError(t, err)
+		assert.Equal(t, "10,1\n20,3\n", resultToCSV(result))
+	})
+
+	t.Run("RESTRICT の FK で参照されている場合は、参照アクションによる変更も含めて取り消される", func(t *testing.T) {
+		// GIVEN: order_items の ON UPDATE は RESTRICT
+		s, sess := setupTestServerWithQueries(t, append(setupQueries,
+			"CREATE TABLE shipments (id INT, order_id INT, PRIMARY KEY (id), KEY idx_order_id (order_id), FOREIGN KEY fk_shipment_order (order_id) REFERENCES orders (id));",
+			"INSERT INTO shipments (id, order_id) VALUES (1000, 10);",
+		)...)
+		defer handler.Reset()
+
+		// WHEN: users の削除が orders に連鎖し、shipments から参照されている orders の削除でエラーになる
+		_, err := s.onQuery(sess, "DELETE FROM users WHERE id = 1;")
+
+		// THEN
+		assert.EqualError(t, err, "cannot delete or update a parent row: a foreign key constraint fails")
+		result, err := s.onQuery(sess, "SELECT * FROM users;")
+		require.NoError(t, err)
+		assert.Equal(t, "1,Alice\n2,Bob\n", resultToCSV(result))
+		result, err = s.onQuery(sess, "SELECT * FROM order_items;")
+		require.NoError(t, err)
+		assert.Equal(t, "100,10\n200,20\n", resultToCSV(result))
+	})
+
+	t.Run("自己参照の FK で子孫の行に参照アクションが適用される", func(t *testing.T) {
+		// GIVEN
+		s, sess := setupTestServerWithQueries(t,
+			"CREATE TABLE categories (id INT, parent_id INT, PRIMARY KEY (id), KEY idx_parent_id (parent_id), FOREIGN KEY fk_parent (parent_id) REFERENCES categories (id) ON DELETE CASCADE ON UPDATE CASCADE);",
+			"INSERT INTO categories (id, parent_id) VALUES (1, NULL);",
+			"INSERT INTO categories (id, parent_id) VALUES (2, 1), (4, NULL);",
+			"INSERT INTO categories (id, parent_id) VALUES (3, 2);",
+		)
+		defer handler.Reset()
+
+		// WHEN
+		_, err1 := s.onQuery(sess, "UPDATE categories SET id = 5 WHERE id = 2;")
+		result1, err2 := s.onQuery(sess, "SELECT * FROM categories;")
+		_, err3 := s.onQuery(sess, "DELETE FROM categories WHERE id = 1;")
+		result2, err4 := s.onQuery(sess, "SELECT * FROM categories;")
+
+		// THEN
+		require.NoError(t, err1)
+		require.NoError(t, err2)
+		require.NoError(t, err3)
+		require.NoError(t, err4)
+		assert.Equal(t, "1,NULL\n3,5\n4,NULL\n5,1\n", resultToCSV(result1))
+		assert.Equal(t, "4,NULL\n", resultToCSV(result2))
+	})
+}
+
+func TestExecuteQueryCompositeForeignKey(t *testing.T) {
+	setupQueries := []string{
+		"CREATE TABLE users (tenant_id INT, id INT, name VARCHAR, PRIMARY KEY (tenant_id, id));",
+		"CREATE TABLE orders (id INT, tenant_id INT, user_id INT, PRIMARY KEY (id), KEY idx_tenant_user (tenant_id, user_id), FOREIGN KEY fk_user (tenant_id, user_id) REFERENCES users (tenant_id, id) ON DELETE CASCADE);",
+		"INSERT INTO users (tenant_id, id, name) VALUES (1, 1, 'Alice'), (2, 1, 'Bob');",
+		"INSERT INTO orders (id, tenant_id, user_id) VALUES (10, 1, 1), (20, 2, 1);",
+	}
+
+	t.Run("参照先の複合主キーに存在しない値の組は INSERT できない", func(t *testing.T) {
+		// GIVEN
+		s, sess := setupTestServerWithQueries(t, setupQueries...)
+		defer handler.Reset()
+
+		// WHEN: tenant_id = 1 と user_id = 2 はそれぞれ users に存在するが、(1, 2) の組は存在しない
+		_, err := s.onQuery(sess, "INSERT INTO orders (id, tenant_id, user_id) VALUES (30, 1, 2);")
+
+		// THEN
+		assert.ErrorContains(t, err, "a foreign key constraint fails")
+	})
+
+	t.Run("ON DELETE CASCADE で値の組が一致する参照元の行だけが削除される", func(t *testing.T) {
+		// GIVEN
+		s, sess := setupTestServerWithQueries(t, setupQueries...)
+		defer handler.Reset()
+
+		// WHEN
+		_, err := s.onQuery(sess, "DELETE FROM users WHERE tenant_id = 1 AND id = 1;")
+
+		// THEN
+		require.NoError(t, err)
+		result, err := s.onQuery(sess, "SELECT * FROM orders;")
+		require.NoError(t, err)
+		assert.Equal(t, "20,2,1\n", resultToCSV(result))
+	})
+
+	t.Run("ON DELETE CASCADE でインデックスの後ろのカラムが NULL の参照元の行も削除される", func(t *testing.T) {
+		// GIVEN: FK カラム a を先頭のカラムとするインデックス idx_ab (a, b) で参照元の行を検索する
+		s, sess := setupTestServerWithQueries(t,
+			"CREATE TABLE parents (id INT, PRIMARY KEY (id));",
+			"CREATE TABLE children (id INT, a INT, b INT, PRIMARY KEY (id), KEY idx_ab (a, b), FOREIGN KEY fk_parent (a) REFERENCES parents (id) ON DELETE CASCADE);",
+			"INSERT INTO parents (id) VALUES (5), (6);",
+			"INSERT INTO children (id, a, b) VALUES (1, 5, NULL), (2, 5, 10), (3, 6, NULL);",
+		)
+		defer handler.Reset()
+
+		// WHEN
+		_, err := s.onQuery(sess, "DELETE FROM parents WHERE id = 5;")
+
+		// THEN
+		require.NoError(t, err)
+		result, err := s.onQuery(sess, "SELECT * FROM children;")
+		require.NoError(t, err)
+		assert.Equal(t, "3,6,NULL\n", resultToCSV(result))
+	})
+}
+
+func TestExecuteQueryInsertVariants(t *testing.T) {
+	setupQueries := []string{
+		"CREATE TABLE users (id INT, email VARCHAR, name VARCHAR, visits INT NOT NULL DEFAULT 0, PRIMARY KEY (id), UNIQUE KEY uk_email (email));",
+		"INSERT INTO users (id, email, name) VALUES (1, 'a@example.com', 'Alice'), (2, 'b@example.com', 'Bob');",
+	}
+
+	tests := []struct {
+		name         string
+		sql          string
+		affectedRows uint64
+		selectSQL    string // 実行後のテーブルの内容を確認するクエリ
+		expected     string
+	}{
+		{
+			"INSERT の影響を受けた行数は追加した行数になる",
+			"INSERT INTO users (id, email, name) VALUES (3, 'c@example.com', 'Carol'), (4, 'd@example.com', 'Dave');",
+			2, "SELECT id FROM users;", "1\n2\n3\n4\n",
+		},
+		{
+			"カラムリストを省略して同じテーブルから INSERT ... SELECT できる",
+			"INSERT INTO users SELECT id + 10, CONCAT('x', email), name, visits FROM users;",
+			2, "SELECT id, email FROM users;", "1,a@example.com\n2,b@example.com\n11,xa@example.com\n12,xb@example.com\n",
+		},
+		{
+			"ON DUPLICATE KEY UPDATE で重複した行を更新し、影響を受けた行数は 2 になる",
+			"INSERT INTO users (id, email, name) VALUES (1, 'new@example.com', 'Alicia') ON DUPLICATE KEY UPDATE name = VALUES(name), visits = visits + 1;",
+			2, "SELECT * FROM users;", "1,a@example.com,Alicia,1\n2,b@example.com,Bob,0\n",
+		},
+		{
+			// id = 3 は存在しないが、email が id = 2 の行と重複する。更新した行は 2、追加した行は 1 として数える
+			"ON DUPLICATE KEY UPDATE は UNIQUE KEY の重複でも既存の行を更新する",
+			"INSERT INTO users (id, email, name) VALUES (3, 'b@example.com', 'Bobby'), (4, 'd@example.com', 'Dave') ON DUPLICATE KEY UPDATE visits = visits + 10;",
+			3, "SELECT * FROM users;", "1,a@example.com,Alice,0\n2,b@example.com,Bob,10\n4,d@example.com,Dave,0\n",
+		},
+		{
+			"ON DUPLICATE KEY UPDATE で値が変わらない場合、影響を受けた行数は 0 になる",
+			"INSERT INTO users (id, email, name) VALUES (1, 'a@example.com', 'Alice') ON DUPLICATE KEY UPDATE name = VALUES(name);",
+			0, "SELECT * FROM users;", "1,a@example.com,Alice,0\n2,b@example.com,Bob,0\n",
+		},
+		{
+			// id が id = 1 の行と、email が id = 2 の行と重複する
+			"REPLACE で重複した行を削除してから追加し、削除した行も影響を受けた行数に数える",
+			"REPLACE INTO users (id, email, name) VALUES (1, 'b@example.com', 'Carol');",
+			3, "SELECT * FROM users;", "1,b@example.com,Carol,0\n",
+		},
+		{
+			"REPLACE で重複しない行は通常通り追加する",
+			"REPLACE INTO users (id, email, name) VALUES (3, 'c@example.com', 'Carol');",
+			1, "SELECT id, name FROM users;", "1,Alice\n2,Bob\n3,Carol\n",
+		},
+		{
+			"INSERT IGNORE で重複した行を読み飛ばす",
+			"INSERT IGNORE INTO users (id, email, name) VALUES (1, 'x@example.com', 'X'), (3, 'a@example.com', 'Y'), (4, 'd@example.com', 'Dave');",
+			1, "SELECT id, name FROM users;", "1,Alice\n2,Bob\n4,Dave\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// GIVEN
+			s, sess := setupTestServerWithQueries(t, setupQueries...)
+			defer handler.Reset()
+
+			// WHEN
+			result, err := s.onQuery(sess, tt.sql)
+
+			// THEN
+			require.NoError(t, err)
+			assert.Equal(t, tt.affectedRows, result.affectedRows)
+			rows, err := s.onQuery(sess, tt.selectSQL)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, resultToCSV(rows))
+		})
+	}
+
+	t.Run("INSERT ... SELECT で SELECT の結果を追加できる", func(t *testing.T) {
+		// GIVEN
+		s, sess := setupTestServerWithQueries(t, append(setupQueries,
+			"CREATE TABLE archived (id BIGINT, name VARCHAR, note VARCHAR DEFAULT 'archived', PRIMARY KEY (id));",
+		)...)
+		defer handler.Reset()
+
+		// WHEN: 指定しなかった note には DEFAULT の値が設定され、INT の id は BIGINT に変換される
+		result, err := s.onQuery(sess, "INSERT INTO archived (id, name) SELECT id, UPPER(name) FROM users WHERE id > 1;")
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, uint64(1), result.affectedRows)
+		rows, err := s.onQuery(sess, "SELECT * FROM archived;")
+		require.NoError(t, err)
+		assert.Equal(t, "2,BOB,archived\n", resultToCSV(rows))
+	})
+
+	t.Run("INSERT IGNORE で CHECK 制約・FK 制約に違反する行を読み飛ばす", func(t *testing.T) {
+		// GIVEN
+		s, sess := setupTestServerWithQueries(t, append(setupQueries,
+			"CREATE TABLE orders (id INT, user_id INT, amount INT, PRIMARY KEY (id), KEY idx_user (user_id), FOREIGN KEY fk_user (user_id) REFERENCES users (id), CONSTRAINT chk_amount CHECK (amount > 0));",
+		)...)
+		defer handler.Reset()
+
+		// WHEN
+		result, err := s.onQuery(sess, "INSERT IGNORE INTO orders (id, user_id, amount) VALUES (10, 1, 100), (20, 9, 100), (30, 2, -1);")
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, uint64(1), result.affectedRows)
+		rows, err := s.onQuery(sess, "SELECT * FROM orders;")
+		require.NoError(t, err)
+		assert.Equal(t, "10,1,100\n", resultToCSV(rows))
+	})
+
+	errorTests := []struct {
+		name     string
+		sql      string
+		expected string
+	}{
+		{"INSERT ... SELECT で NOT NULL のカラムに NULL を追加しようとするとエラーになる", "INSERT INTO users (id, visits) SELECT id + 10, NULL FROM users;", "column 'visits' cannot be null"},
+		{"INSERT で重複した行がある場合はエラーになる", "INSERT INTO users (id, email, name) VALUES (1, 'x@example.com', 'X');", "duplicate key"},
+	}
+	for _, tt := range errorTests {
+		t.Run(tt.name, func(t *testing.T) {
+			// GIVEN
+			s, sess := setupTestServerWithQueries(t, setupQueries...)
+			defer handler.Reset()
+
+			// WHEN
+			_, err := s.onQuery(sess, tt.sql)
+
+			// THEN
+			assert.ErrorContains(t, err, tt.expected)
+		})
+	}
+}
+
+func TestExecuteQueryView(t *testing.T) {
+	setupQueries := []string{
+		"CREATE TABLE users (id INT, name VARCHAR, age INT, PRIMARY KEY (id));",
+		"INSERT INTO users (id, name, age) VALUES (1, 'Alice', 30), (2, 'Bob', 17), (3, 'Carol', 25);",
+		"CREATE VIEW adults (user_id, user_name) AS SELECT id, name FROM users WHERE age >= 20;",
+	}
+
+	t.Run("ビューを参照する SELECT はビューの問い合わせの結果を返す", func(t *testing.T) {
+		// GIVEN
+		s, sess := setupTestServerWithQueries(t, setupQueries...)
+		defer handler.Reset()
+
+		// WHEN
+		result, err := s.onQuery(sess, "SELECT user_name FROM adults ORDER BY user_id;")
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, "Alice\nCarol\n", resultToCSV(result))
+	})
+
+	t.Run("ビューを介して UPDATE・DELETE できる", func(t *testing.T) {
+		// GIVEN
+		s, sess := setupTestServerWithQueries(t, setupQueries...)
+		defer handler.Reset()
+
+		// WHEN
+		_, updateErr := s.onQuery(sess, "UPDATE adults SET user_name = 'Carla' WHERE user_id = 3;")
+		_, deleteErr := s.onQuery(sess, "DELETE FROM adults WHERE user_name = 'Alice';")
+
+		// THEN
+		require.NoError(t, updateErr)
+		require.NoError(t, deleteErr)
+		rows, err := s.onQuery(sess, "SELECT * FROM users;")
+		require.NoError(t, err)
+		assert.Equal(t, "2,Bob,17\n3,Carla,25\n", resultToCSV(rows))
+	})
+
+	t.Run("DROP VIEW でビューを削除できる", func(t *testing.T) {
+		// GIVEN
+		s, sess := setupTestServerWithQueries(t, setupQueries...)
+		defer handler.Reset()
+
+		// WHEN
+		result, err := s.onQuery(sess, "DROP VIEW adults;")
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, resultOK, result.resultType)
+		_, err = s.onQuery(sess, "SELECT * FROM adults;")
+		assert.ErrorContains(t, err, "table adults not found")
+	})
+}
+
+func TestExecuteQuerySelectWithoutFrom(t *testing.T) {
+	t.Run("再帰 CTE の非再帰部分に FROM 句のない SELECT 文を指定できる", func(t *testing.T) {
+		// GIVEN
+		s := setupTestServer(t)
+		defer handler.Reset()
+		sess := newSession("", 0)
+
+		// WHEN
+		result, err := s.onQuery(sess, "WITH RECURSIVE c (n) AS (SELECT 1 UNION ALL SELECT n + 1 FROM c WHERE n < 5) SELECT n FROM c;")
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, "1\n2\n3\n4\n5\n", resultToCSV(result))
+	})
+
+	t.Run("集合演算のオペランドに FROM 句のない SELECT 文を指定でき、ビューの定義にも使える", func(t *testing.T) {
+		// GIVEN
+		s, sess := setupTestServerWithQueries(t,
+			"CREATE TABLE users (id BIGINT, name VARCHAR, PRIMARY KEY (id));",
+			"INSERT INTO users (id, name) VALUES (1, 'Alice'), (2, 'Bob');",
+			"CREATE VIEW members (id, name) AS SELECT id, name FROM users UNION ALL SELECT 0, 'guest';",
+		)
+		defer handler.Reset()
+
+		// WHEN
+		direct, directErr := s.onQuery(sess, "SELECT id, name FROM users WHERE id = 2 UNION SELECT 9, 'admin' ORDER BY id DESC;")
+		view, viewErr := s.onQuery(sess, "SELECT name FROM members ORDER BY id;")
+
+		// THEN
+		require.NoError(t, directErr)
+		assert.Equal(t, "9,admin\n2,Bob\n", resultToCSV(direct))
+		require.NoError(t, viewErr)
+		assert.Equal(t, "guest\nAlice\nBob\n", resultToCSV(view))
+	})
+
+	t.Run("集合演算のオペランドでない SELECT 文は FROM 句を省略できない", func(t *testing.T) {
+		// GIVEN
+		s := setupTestServer(t)
+		defer handler.Reset()
+		sess := newSession("", 0)
+
+		// WHEN
+		_, err := s.onQuery(sess, "SELECT 1;")
+
+		// THEN
+		assert.ErrorContains(t, err, "missing FROM clause")
+	})
+}
+
+func TestExecuteQueryDatabase(t *testing.T) {
+	setupQueries := []string{
+		"CREATE DATABASE app;",
+		"CREATE DATABASE other;",
+		"CREATE TABLE app.users (id INT, name VARCHAR, PRIMARY KEY (id));",
+		"CREATE TABLE other.users (id INT, name VARCHAR, PRIMARY KEY (id));",
+		"INSERT INTO app.users (id, name) VALUES (1, 'Alice');",
+		"INSERT INTO other.users (id, name) VALUES (2, 'Bob');",
+	}
+
+	t.Run("USE で選択したデータベースのテーブルを非修飾名で参照できる", func(t *testing.T) {
+		// GIVEN
+		s, sess := setupTestServerWithQueries(t, setupQueries...)
+		defer handler.Reset()
+
+		// WHEN
+		_, useErr := s.onQuery(sess, "USE app")
+		appRows, appErr := s.onQuery(sess, "SELECT * FROM users;")
+		otherRows, otherErr := s.onQuery(sess, "SELECT users.name FROM other.users;")
+
+		// THEN
+		require.NoError(t, useErr)
+		require.NoError(t, appErr)
+		require.NoError(t, otherErr)
+		assert.Equal(t, "app", sess.database)
+		assert.Equal(t, "1,Alice\n", resultToCSV(appRows))
+		assert.Equal(t, "Bob\n", resultToCSV(otherRows))
+	})
+
+	t.Run("結果セットのカラムにテーブルが属するデータベース名を設定する", func(t *testing.T) {
+		// GIVEN
+		s, sess := setupTestServerWithQueries(t, setupQueries...)
+		defer handler.Reset()
+
+		// WHEN
+		result, err := s.onQuery(sess, "SELECT id FROM app.users;")
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, "app", result.columns[0].schema)
+		assert.Equal(t, "users", result.columns[0].tableName)
+	})
+
+	t.Run("存在しないデータベースを USE するとエラーになる", func(t *testing.T) {
+		// GIVEN
+		s, sess := setupTestServerWithQueries(t, setupQueries...)
+		defer handler.Reset()
+
+		// WHEN
+		_, err := s.onQuery(sess, "USE missing;")
+
+		// THEN
+		assert.EqualError(t, err, "Unknown database 'missing'")
+		assert.Equal(t, "", sess.database)
+	})
+
+	t.Run("選択中のデータベースを削除するとデータベースを選択していない状態に戻る", func(t *testing.T) {
+		// GIVEN
+		s, sess := setupTestServerWithQueries(t, append(setupQueries,
+			"USE app;",
+		)...)
+		defer handler.Reset()
+
+		// WHEN
+		_, err := s.onQuery(sess, "DROP DATABASE app;")
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, "", sess.database)
+		_, err = s.onQuery(sess, "SELECT * FROM app.users;")
+		assert.ErrorContains(t, err, "table app.users not found")
+	})
+
+	t.Run("CREATE DATABASE は進行中のトランザクションを暗黙的にコミットする", func(t *testing.T) {
+		// GIVEN
+		s, sess := setupTestServerWithQueries(t, append(setupQueries,
+			"BEGIN;",
+			"INSERT INTO app.users (id, name) VALUES (3, 'Carol');",
+		)...)
+		defer handler.Reset()
+
+		// WHEN
+		_, err := s.onQuery(sess, "CREATE DATABASE billing;")
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, handler.TrxId(0), sess.trxId)
+		rows, err := s.onQuery(sess, "SELECT id FROM app.users;")
+		require.NoError(t, err)
+		assert.Equal(t, "1\n3\n", resultToCSV(rows))
+	})
+
+	t.Run("他のセッションのトランザクションが実行中の場合は DROP DATABASE がエラーになる", func(t *testing.T) {
+		// GIVEN: セッション A が BEGIN + DELETE したまま
+		s, sessA := setupTestServerWithQueries(t, append(setupQueries,
+			"BEGIN;",
+			"DELETE FROM app.users WHERE id = 1;",
+		)...)
+		defer handler.Reset()
+		sessB := newSession("", 0)
+
+		// WHEN
+		_, err := s.onQuery(sessB, "DROP DATABASE app;")
+
+		// THEN: エラーになり、セッション A はロールバックでき、テーブルは元の状態に戻る
+		assert.ErrorContains(t, err, "cannot drop database app while other transactions are active")
+		_, err = s.onQuery(sessA, "ROLLBACK;")
+		require.NoError(t, err)
+		rows, err := s.onQuery(sessB, "SELECT id FROM app.users;")
+		require.NoError(t, err)
+		assert.Equal(t, "1\n", resultToCSV(rows))
+	})
+}
@@ -1,12 +1,17 @@
 package server
 
-import "github.com/ren-yamanashi/minesql/internal/storage/handler"
+import (
+	"fmt"
+
+	"github.com/ren-yamanashi/minesql/internal/storage/handler"
+)
 
 // session はクライアントごとの接続状態を管理する
 type session struct {
 	trxId      handler.TrxId // 現在のトランザクション ID
 	username   string        // 認証時に設定
 	capability uint32        // クライアントとのネゴシエーション結果 (共通 capability)
+	database   string        // 選択中のデータベース (空の場合はデータベースを選択していない)
 }
 
 func newSession(username string, capability uint32) *session {
@@ -15,3 +20,21 @@ func newSession(username string, capability uint32) *session {
 		capability: capability,
 	}
 }
+
+// useDatabase はセッションで選択するデータベースを切り替える (USE 文、COM_INIT_DB、接続時のデータベース名の指定)
+func (sess *session) useDatabase(databaseName string) error {
+	if _, ok := handler.Get().Catalog.GetDatabaseByName(databaseName); !ok {
+		return &unknownDatabaseError{databaseName: databaseName}
+	}
+	sess.database = databaseName
+	return nil
+}
+
+// unknownDatabaseError は存在しないデータベースを選択しようとした場合のエラー
+type unknownDatabaseError struct {
+	databaseName string
+}
+
+func (e *unknownDatabaseError) Error() string {
+	return fmt.Sprintf("Unknown database '%s'", e.databaseName)
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/ren-yamanashi/minesql/internal/storage/handler"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNewSession(t *testing.T) {
@@ -17,5 +18,40 @@ func TestNewSession(t *testing.T) {
 		assert.Equal(t, handler.TrxId(0), sess.trxId)
 		assert.Equal(t, "root", sess.username)
 		assert.Equal(t, serverCapability, sess.capability)
+		assert.Equal(t, "", sess.database)
+	})
+}
+
+func TestSessionUseDatabase(t *testing.T) {
+	t.Run("存在するデータベースを選択できる", func(t *testing.T) {
+		// GIVEN
+		setupTestServer(t)
+		defer handler.Reset()
+		require.NoError(t, handler.Get().CreateDatabase("app", false))
+		sess := newSession("root", serverCapability)
+
+		// WHEN
+		err := sess.useDatabase("app")
+
+		// THEN
+		assert.NoError(t, err)
+		assert.Equal(t, "app", sess.database)
+	})
+
+	t.Run("存在しないデータベースを選択するとエラーになり、選択中のデータベースは変わらない", func(t *testing.T) {
+		// GIVEN
+		setupTestServer(t)
+		defer handler.Reset()
+		require.NoError(t, handler.Get().CreateDatabase("app", false))
+		sess := newSession("root", serverCapability)
+		require.NoError(t, sess.useDatabase("app"))
+
+		// WHEN
+		err := sess.useDatabase("missing")
+
+		// THEN
+		var dbErr *unknownDatabaseError
+		assert.ErrorAs(t, err, &dbErr)
+		assert.Equal(t, "app", sess.database)
 	})
 }
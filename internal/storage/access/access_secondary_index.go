@@ -16,19 +16,19 @@ import (
 // Unique が true の場合はユニーク制約を適用する
 type SecondaryIndex struct {
 	Name       string      // インデックス名
-	ColName    string      // インデックスを構成するカラム名
+	ColNames   []string    // インデックスを構成するカラム名 (複合インデックスの場合はキーの順)
 	MetaPageId page.PageId // インデックスの内容が入っている B+Tree のメタページの ID
-	ColIdx     uint16      // インデックスカラムの位置 (0 始まりの列番号)
+	ColIdxs    []uint16    // インデックスカラムの位置 (0 始まりの列番号、ColNames と同じ順)
 	PkCount    uint8       // PK のカラム数 (key からセカンダリキーと PK を分離するために必要)
 	Unique     bool        // ユニーク制約の有無
 }
 
-func NewSecondaryIndex(name string, colName string, metaPageId page.PageId, colIdx uint16, pkCount uint8, unique bool) *SecondaryIndex {
+func NewSecondaryIndex(name string, colNames []string, metaPageId page.PageId, colIdxs []uint16, pkCount uint8, unique bool) *SecondaryIndex {
 	return &SecondaryIndex{
 		Name:       name,
-		ColName:    colName,
+		ColNames:   colNames,
 		MetaPageId: metaPageId,
-		ColIdx:     colIdx,
+		ColIdxs:    colIdxs,
 		PkCount:    pkCount,
 		Unique:     unique,
 	}
@@ -37,12 +37,12 @@ func NewSecondaryIndex(name string, colName string, metaPageId page.PageId, colI
 // Search は指定した検索モードでインデックスを検索し、SecondaryIndexIterator を返す
 func (si *SecondaryIndex) Search(bp *buffer.BufferPool, table *Table, mode RecordSearchMode) (*SecondaryIndexIterator, error) {
 	indexBTree := btree.NewBTree(si.MetaPageId)
-	indexIter, err := indexBTree.Search(bp, mode.encode())
+	indexIter, err := indexBTree.Search(bp, mode.encodeNullable())
 	if err != nil {
 		return nil, err
 	}
 	tableBTree := btree.NewBTree(table.MetaPageId)
-	return newSecondaryIndexIterator(indexIter, tableBTree, bp, si.PkCount), nil
+	return newSecondaryIndexIterator(indexIter, tableBTree, bp, len(si.ColIdxs)), nil
 }
 
 // Create は空のセカンダリインデックスを新規作成する
@@ -60,15 +60,16 @@ func (si *SecondaryIndex) Create(bp *buffer.BufferPool) error {
 // Key = concat(encodedSecondaryKey, encodedPK), NonKey = nil, Header = []byte{0}
 //
 // ソフトデリート済みの同一キーが存在する場合は Update で上書きする
+//
+// インデックスカラムが NULL の行も NULL をキーとして登録する (NULL はどの値とも一致しないため、ユニーク制約の対象にはならない)
 func (si *SecondaryIndex) Insert(bp *buffer.BufferPool, encodedPK []byte, columns [][]byte) error {
 	btr := btree.NewBTree(si.MetaPageId)
 
 	// セカンダリキーをエンコード
-	var encodedSecKey []byte
-	encode.Encode([][]byte{columns[si.ColIdx]}, &encodedSecKey)
+	encodedSecKey := si.encodeSecondaryKey(columns)
 
 	// ユニーク制約チェック (Unique の場合のみ)
-	if si.Unique {
+	if si.Unique && !si.hasNullKey(columns) {
 		if err := si.checkUniqueConstraint(bp, btr, encodedSecKey); err != nil {
 			return err
 		}
@@ -134,10 +135,32 @@ func (si *SecondaryIndex) Height(bp *buffer.BufferPool) (uint64, error) {
 	return btr.Height(bp)
 }
 
+// hasNullKey はインデックスカラムのいずれかが NULL かどうかを判定する
+func (si *SecondaryIndex) hasNullKey(columns [][]byte) bool {
+	for _, colIdx := range si.ColIdxs {
+		if columns[colIdx] == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// encodeSecondaryKey は行のインデックスカラムの値を、インデックスのカラム順にエンコードする
+//
+// NULL は NULL 以外の値と区別してエンコードする (encode.EncodeNullable を参照)
+func (si *SecondaryIndex) encodeSecondaryKey(columns [][]byte) []byte {
+	keyColumns := make([][]byte, len(si.ColIdxs))
+	for i, colIdx := range si.ColIdxs {
+		keyColumns[i] = columns[colIdx]
+	}
+	var encodedSecKey []byte
+	encode.EncodeNullable(keyColumns, &encodedSecKey)
+	return encodedSecKey
+}
+
 func (si *SecondaryIndex) getFullKey(encodedPK []byte, columns [][]byte) []byte {
 	// セカンダリキーをエンコード
-	var encodedSecKey []byte
-	encode.Encode([][]byte{columns[si.ColIdx]}, &encodedSecKey)
+	encodedSecKey := si.encodeSecondaryKey(columns)
 
 	// fullKey = concat(encodedSecKey, encodedPK)
 	fullKey := make([]byte, 0, len(encodedSecKey)+len(encodedPK))
@@ -164,15 +187,14 @@ func (si *SecondaryIndex) checkUniqueConstraint(bp *buffer.BufferPool, btr *btre
 		}
 
 		// レコードのキーからセカンダリキー部分を取り出して比較
-		var keyColumns [][]byte
-		encode.Decode(record.KeyBytes(), &keyColumns)
-		if len(keyColumns) == 0 {
+		keyColumns, _ := encode.DecodeNullableFirstN(record.KeyBytes(), len(si.ColIdxs))
+		if len(keyColumns) < len(si.ColIdxs) {
 			break
 		}
 
 		// セカンダリキーカラムだけ再エンコードして比較
 		var existingSecKey []byte
-		encode.Encode(keyColumns[:1], &existingSecKey)
+		encode.EncodeNullable(keyColumns, &existingSecKey)
 		if !bytes.Equal(existingSecKey, encodedSecKey) {
 			// セカンダリキーが異なる → これ以上のレコードは一致しない
 			break
@@ -17,7 +17,7 @@ func TestSecondaryIndex(t *testing.T) {
 
 		indexMetapageId, err := bp.AllocatePageId(metaPageId.FileId)
 		assert.NoError(t, err)
-		uniqueIndex := NewSecondaryIndex("test_index", "test", indexMetapageId, 0, 1, true)
+		uniqueIndex := NewSecondaryIndex("test_index", []string{"test"}, indexMetapageId, []uint16{0}, 1, true)
 
 		// WHEN: ユニークインデックスを作成
 		err = uniqueIndex.Create(bp)
@@ -65,8 +65,8 @@ func TestSecondaryIndex(t *testing.T) {
 			expected := expectedRecords[i]
 
 			// Key をデコードしてセカンダリキーと PK を分離
-			var keyColumns [][]byte
-			encode.Decode(record.KeyBytes(), &keyColumns)
+			keyColumns, encodedPK := encode.DecodeNullableFirstN(record.KeyBytes(), 1)
+			encode.Decode(encodedPK, &keyColumns)
 
 			assert.Equal(t, expected.secondaryKey, string(keyColumns[0]))
 			assert.Equal(t, expected.pk, string(keyColumns[1]))
@@ -91,7 +91,7 @@ func TestSecondaryIndexDelete(t *testing.T) {
 
 		indexMetapageId, err := bp.AllocatePageId(metaPageId.FileId)
 		assert.NoError(t, err)
-		uniqueIndex := NewSecondaryIndex("test_index", "test", indexMetapageId, 0, 1, true)
+		uniqueIndex := NewSecondaryIndex("test_index", []string{"test"}, indexMetapageId, []uint16{0}, 1, true)
 
 		err = uniqueIndex.Create(bp)
 		assert.NoError(t, err)
@@ -129,8 +129,8 @@ func TestSecondaryIndexDelete(t *testing.T) {
 			if !ok {
 				break
 			}
-			var keyColumns [][]byte
-			encode.Decode(record.KeyBytes(), &keyColumns)
+			keyColumns, encodedPK := encode.DecodeNullableFirstN(record.KeyBytes(), 1)
+			encode.Decode(encodedPK, &keyColumns)
 			entries = append(entries, indexEntry{
 				secondaryKey: string(keyColumns[0]),
 				deleteMark:   record.HeaderBytes()[0],
@@ -154,7 +154,7 @@ func TestSecondaryIndexDelete(t *testing.T) {
 
 		indexMetapageId, err := bp.AllocatePageId(metaPageId.FileId)
 		assert.NoError(t, err)
-		uniqueIndex := NewSecondaryIndex("test_index", "test", indexMetapageId, 0, 1, true)
+		uniqueIndex := NewSecondaryIndex("test_index", []string{"test"}, indexMetapageId, []uint16{0}, 1, true)
 
 		err = uniqueIndex.Create(bp)
 		assert.NoError(t, err)
@@ -187,7 +187,7 @@ func TestSecondaryIndexDelete(t *testing.T) {
 
 		indexMetapageId, err := bp.AllocatePageId(metaPageId.FileId)
 		assert.NoError(t, err)
-		uniqueIndex := NewSecondaryIndex("test_index", "test", indexMetapageId, 0, 1, true)
+		uniqueIndex := NewSecondaryIndex("test_index", []string{"test"}, indexMetapageId, []uint16{0}, 1, true)
 
 		err = uniqueIndex.Create(bp)
 		assert.NoError(t, err)
@@ -225,8 +225,8 @@ func TestSecondaryIndexDelete(t *testing.T) {
 			if !ok {
 				break
 			}
-			var keyColumns [][]byte
-			encode.Decode(record.KeyBytes(), &keyColumns)
+			keyColumns, encodedPK := encode.DecodeNullableFirstN(record.KeyBytes(), 1)
+			encode.Decode(encodedPK, &keyColumns)
 			entries = append(entries, indexEntry{
 				secondaryKey: string(keyColumns[0]),
 				deleteMark:   record.HeaderBytes()[0],
@@ -248,7 +248,7 @@ func TestSecondaryIndexDelete(t *testing.T) {
 
 		indexMetapageId, err := bp.AllocatePageId(metaPageId.FileId)
 		assert.NoError(t, err)
-		uniqueIndex := NewSecondaryIndex("test_index", "test", indexMetapageId, 0, 1, true)
+		uniqueIndex := NewSecondaryIndex("test_index", []string{"test"}, indexMetapageId, []uint16{0}, 1, true)
 
 		err = uniqueIndex.Create(bp)
 		assert.NoError(t, err)
@@ -283,7 +283,7 @@ func TestSecondaryIndexConstraint(t *testing.T) {
 
 		indexMetapageId, err := bp.AllocatePageId(metaPageId.FileId)
 		assert.NoError(t, err)
-		uniqueIndex := NewSecondaryIndex("test_index", "test", indexMetapageId, 0, 1, true)
+		uniqueIndex := NewSecondaryIndex("test_index", []string{"test"}, indexMetapageId, []uint16{0}, 1, true)
 
 		err = uniqueIndex.Create(bp)
 		assert.NoError(t, err)
@@ -308,7 +308,7 @@ func TestSecondaryIndexConstraint(t *testing.T) {
 
 		indexMetapageId, err := bp.AllocatePageId(metaPageId.FileId)
 		assert.NoError(t, err)
-		uniqueIndex := NewSecondaryIndex("test_index", "test", indexMetapageId, 0, 1, true)
+		uniqueIndex := NewSecondaryIndex("test_index", []string{"test"}, indexMetapageId, []uint16{0}, 1, true)
 
 		err = uniqueIndex.Create(bp)
 		assert.NoError(t, err)
@@ -336,7 +336,7 @@ func TestSecondaryIndexConstraint(t *testing.T) {
 		bp, metaPageId, _ := InitDisk(t, "test.db")
 		indexMetapageId, err := bp.AllocatePageId(metaPageId.FileId)
 		assert.NoError(t, err)
-		uniqueIndex := NewSecondaryIndex("test_index", "test", indexMetapageId, 0, 1, true)
+		uniqueIndex := NewSecondaryIndex("test_index", []string{"test"}, indexMetapageId, []uint16{0}, 1, true)
 		err = uniqueIndex.Create(bp)
 		assert.NoError(t, err)
 
@@ -362,6 +362,48 @@ func TestSecondaryIndexConstraint(t *testing.T) {
 		// THEN: ソフトデリート済みのみなのでユニーク制約違反にならない
 		assert.NoError(t, err)
 	})
+
+	t.Run("NULL は複数挿入してもユニーク制約違反にならず、NULL をキーとしてインデックスに登録される", func(t *testing.T) {
+		// GIVEN
+		bp, metaPageId, _ := InitDisk(t, "test.db")
+		indexMetapageId, err := bp.AllocatePageId(metaPageId.FileId)
+		assert.NoError(t, err)
+		uniqueIndex := NewSecondaryIndex("test_index", []string{"test"}, indexMetapageId, []uint16{0}, 1, true)
+		err = uniqueIndex.Create(bp)
+		assert.NoError(t, err)
+
+		var encodedPK0, encodedPK1 []byte
+		encode.Encode([][]byte{[]byte("pk0")}, &encodedPK0)
+		encode.Encode([][]byte{[]byte("pk1")}, &encodedPK1)
+
+		// WHEN
+		err0 := uniqueIndex.Insert(bp, encodedPK0, [][]byte{nil})
+		err1 := uniqueIndex.Insert(bp, encodedPK1, [][]byte{nil})
+
+		// THEN
+		assert.NoError(t, err0)
+		assert.NoError(t, err1)
+		tree := btree.NewBTree(uniqueIndex.MetaPageId)
+		iter, err := tree.Search(bp, btree.SearchModeStart{})
+		assert.NoError(t, err)
+		var pks []string
+		for {
+			record, ok, err := iter.Next(bp)
+			assert.NoError(t, err)
+			if !ok {
+				break
+			}
+			keyColumns, encodedPK := encode.DecodeNullableFirstN(record.KeyBytes(), 1)
+			assert.Nil(t, keyColumns[0])
+			encode.Decode(encodedPK, &keyColumns)
+			pks = append(pks, string(keyColumns[1]))
+		}
+		assert.Equal(t, []string{"pk0", "pk1"}, pks)
+
+		// NULL のキーも削除できる
+		assert.NoError(t, uniqueIndex.Delete(bp, encodedPK0, [][]byte{nil}))
+		assert.NoError(t, uniqueIndex.SoftDelete(bp, encodedPK1, [][]byte{nil}))
+	})
 }
 
 func TestSecondaryIndexLeafPageCount(t *testing.T) {
@@ -370,7 +412,7 @@ func TestSecondaryIndexLeafPageCount(t *testing.T) {
 		bp, metaPageId, _ := InitDisk(t, "test.db")
 		indexMetaPageId, err := bp.AllocatePageId(metaPageId.FileId)
 		assert.NoError(t, err)
-		uniqueIndex := NewSecondaryIndex("test_index", "test", indexMetaPageId, 0, 1, true)
+		uniqueIndex := NewSecondaryIndex("test_index", []string{"test"}, indexMetaPageId, []uint16{0}, 1, true)
 
 		err = uniqueIndex.Create(bp)
 		assert.NoError(t, err)
@@ -388,7 +430,7 @@ func TestSecondaryIndexLeafPageCount(t *testing.T) {
 		bp, metaPageId, _ := InitDisk(t, "test.db")
 		indexMetaPageId, err := bp.AllocatePageId(metaPageId.FileId)
 		assert.NoError(t, err)
-		uniqueIndex := NewSecondaryIndex("test_index", "test", indexMetaPageId, 0, 1, true)
+		uniqueIndex := NewSecondaryIndex("test_index", []string{"test"}, indexMetaPageId, []uint16{0}, 1, true)
 
 		err = uniqueIndex.Create(bp)
 		assert.NoError(t, err)
@@ -414,7 +456,7 @@ func TestSecondaryIndexHeight(t *testing.T) {
 		bp, metaPageId, _ := InitDisk(t, "test.db")
 		indexMetaPageId, err := bp.AllocatePageId(metaPageId.FileId)
 		assert.NoError(t, err)
-		uniqueIndex := NewSecondaryIndex("test_index", "test", indexMetaPageId, 0, 1, true)
+		uniqueIndex := NewSecondaryIndex("test_index", []string{"test"}, indexMetaPageId, []uint16{0}, 1, true)
 		err = uniqueIndex.Create(bp)
 		assert.NoError(t, err)
 
@@ -431,7 +473,7 @@ func TestSecondaryIndexHeight(t *testing.T) {
 		bp, metaPageId, _ := InitDisk(t, "test.db")
 		indexMetaPageId, err := bp.AllocatePageId(metaPageId.FileId)
 		assert.NoError(t, err)
-		uniqueIndex := NewSecondaryIndex("test_index", "test", indexMetaPageId, 0, 1, true)
+		uniqueIndex := NewSecondaryIndex("test_index", []string{"test"}, indexMetaPageId, []uint16{0}, 1, true)
 		err = uniqueIndex.Create(bp)
 		assert.NoError(t, err)
 
@@ -24,6 +24,7 @@ type Table struct {
 	SecondaryIndexes []*SecondaryIndex // テーブルに紐づくセカンダリインデックス群
 	undoLog          *UndoManager      // Undo ログ (nil の場合は Undo 記録をスキップ)
 	redoLog          *log.RedoLog      // REDO ログ (nil の場合は REDO 記録をスキップ)
+	rowLogs          *RowLogRegistry   // オンライン作成中のインデックスの行ログ (nil の場合は行の変更を記録しない)
 }
 
 func NewTable(name string, metaPageId page.PageId, primaryKeyCount uint8, secondaryIndexes []*SecondaryIndex, undoLog *UndoManager, redoLog *log.RedoLog) Table {
@@ -37,6 +38,13 @@ func NewTable(name string, metaPageId page.PageId, primaryKeyCount uint8, second
 	}
 }
 
+// SetRowLogs はオンライン作成中のインデックスの行ログを設定する
+//
+// 設定した場合、行の挿入・削除・更新をテーブル名で登録されている行ログに記録する
+func (t *Table) SetRowLogs(rowLogs *RowLogRegistry) {
+	t.rowLogs = rowLogs
+}
+
 // Search は指定した検索モードでテーブルを検索し、TableIterator を返す
 //
 // ReadView に基づく Consistent Read を行う。ロックは取得しない。
@@ -165,6 +173,42 @@ func (t *Table) UpdateInplace(bp *buffer.BufferPool, trxId lock.TrxId, lockMgr *
 	return t.appendRedoRecords(bp, trxId)
 }
 
+// Rebuild はテーブルの全レコードを convert でカラム構成を変換しながら dest の B+Tree にコピーする
+//
+// ALTER TABLE の ADD COLUMN / DROP COLUMN で使用する。dest の B+Tree は作成済みである必要がある。
+// DeleteMark・lastModified・rollPtr はそのままコピーする。
+// セカンダリインデックスのキー (セカンダリキー + PK) はカラムの位置に依存しないため、コピーしない
+func (t *Table) Rebuild(bp *buffer.BufferPool, dest *Table, convert func(columns [][]byte) ([][]byte, error)) error {
+	iter, err := btree.NewBTree(t.MetaPageId).Search(bp, btree.SearchModeStart{})
+	if err != nil {
+		return err
+	}
+	destBTree := btree.NewBTree(dest.MetaPageId)
+
+	for {
+		record, ok, err := iter.Next(bp)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return nil
+		}
+
+		var columns [][]byte
+		encode.Decode(record.KeyBytes(), &columns)
+		lastModified, rollPtr, nonKeyColumns := decodeRecordNonKey(record.NonKeyBytes())
+		decodeNonKeyColumns(nonKeyColumns, &columns)
+
+		newColumns, err := convert(columns)
+		if err != nil {
+			return err
+		}
+		if err := destBTree.Insert(bp, dest.encodeBTreeRecord(newColumns, record.HeaderBytes()[0], lastModified, rollPtr)); err != nil {
+			return err
+		}
+	}
+}
+
 // GetSecondaryIndexByName はインデックス名からセカンダリインデックスを取得する
 func (t *Table) GetSecondaryIndexByName(indexName string) (*SecondaryIndex, error) {
 	for _, si := range t.SecondaryIndexes {
@@ -196,13 +240,13 @@ func (t *Table) EncodeKey(columns [][]byte) []byte {
 
 // encodeBTreeRecord はカラム値を B+Tree レコードに変換する
 //
-// Non-key 領域のレイアウト: [lastModified (8B)] [rollPtr (4B)] [非キーカラム (memcomparable)]
+// Non-key 領域のレイアウト: [lastModified (8B)] [rollPtr (4B)] [非キーカラム (NULL ビットマップ + memcomparable)]
 func (t *Table) encodeBTreeRecord(columns [][]byte, deleteMark byte, lastModified lock.TrxId, rollPtr UndoPtr) node.Record {
 	var key []byte
 	encode.Encode(columns[:t.PrimaryKeyCount], &key)
 
 	nonKey := encodeRecordNonKeyPrefix(lastModified, rollPtr)
-	encode.Encode(columns[t.PrimaryKeyCount:], &nonKey)
+	encodeNonKeyColumns(columns[t.PrimaryKeyCount:], &nonKey)
 
 	return node.NewRecord([]byte{deleteMark}, key, nonKey)
 }
@@ -246,6 +290,8 @@ func (t *Table) insert(bp *buffer.BufferPool, trxId lock.TrxId, lockMgr *lock.Ma
 	if err := lockMgr.Lock(trxId, pos, lock.Exclusive); err != nil {
 		return err
 	}
+	unlock := t.latchRowChange()
+	defer unlock()
 
 	// ユニークインデックスに挿入
 	for _, si := range t.SecondaryIndexes {
@@ -255,6 +301,7 @@ func (t *Table) insert(bp *buffer.BufferPool, trxId lock.TrxId, lockMgr *lock.Ma
 		}
 	}
 
+	t.recordRowLog(RowLogInsert, encodedKey, columns)
 	return nil
 }
 
@@ -273,6 +320,8 @@ func (t *Table) delete(bp *buffer.BufferPool, trxId lock.TrxId, lockMgr *lock.Ma
 	if err := lockMgr.Lock(trxId, pos, lock.Exclusive); err != nil {
 		return err
 	}
+	unlock := t.latchRowChange()
+	defer unlock()
 
 	if err := btr.Delete(bp, encodedKey); err != nil {
 		return err
@@ -286,6 +335,7 @@ func (t *Table) delete(bp *buffer.BufferPool, trxId lock.TrxId, lockMgr *lock.Ma
 		}
 	}
 
+	t.recordRowLog(RowLogDelete, encodedKey, columns)
 	return nil
 }
 
@@ -304,6 +354,8 @@ func (t *Table) softDelete(bp *buffer.BufferPool, trxId lock.TrxId, lockMgr *loc
 	if err := lockMgr.Lock(trxId, pos, lock.Exclusive); err != nil {
 		return err
 	}
+	unlock := t.latchRowChange()
+	defer unlock()
 
 	btrRecord := t.encodeBTreeRecord(columns, 1, trxId, undoPtr)
 	if err := btr.Update(bp, btrRecord); err != nil {
@@ -318,6 +370,7 @@ func (t *Table) softDelete(bp *buffer.BufferPool, trxId lock.TrxId, lockMgr *loc
 		}
 	}
 
+	t.recordRowLog(RowLogSoftDelete, encodedKey, columns)
 	return nil
 }
 
@@ -340,6 +393,8 @@ func (t *Table) updateInplace(bp *buffer.BufferPool, trxId lock.TrxId, lockMgr *
 	if err := lockMgr.Lock(trxId, pos, lock.Exclusive); err != nil {
 		return err
 	}
+	unlock := t.latchRowChange()
+	defer unlock()
 
 	btrRecord := t.encodeBTreeRecord(newColumns, 0, lastModified, undoPtr)
 	if err := btr.Update(bp, btrRecord); err != nil {
@@ -360,9 +415,29 @@ func (t *Table) updateInplace(bp *buffer.BufferPool, trxId lock.TrxId, lockMgr *
 		}
 	}
 
+	t.recordRowLog(RowLogDelete, encodedOldKey, oldColumns)
+	t.recordRowLog(RowLogInsert, encodedNewKey, newColumns)
 	return nil
 }
 
+// latchRowChange は行の変更 (セカンダリインデックスの更新と行ログへの記録) の間、インデックスのカタログへの登録を待たせる
+//
+// 登録中の場合は登録が終わるまで待つ。変更の終了時に返した関数を呼ぶ
+func (t *Table) latchRowChange() (unlock func()) {
+	if t.rowLogs == nil {
+		return func() {}
+	}
+	return t.rowLogs.RLockTable(t.Name)
+}
+
+// recordRowLog はオンライン作成中のインデックスがある場合、行の変更を行ログに記録する
+func (t *Table) recordRowLog(op RowLogOp, encodedPK []byte, columns [][]byte) {
+	if t.rowLogs == nil {
+		return
+	}
+	t.rowLogs.record(t.Name, op, encodedPK, columns)
+}
+
 // readCurrentVersion は B+Tree 上の既存行から lastModified と rollPtr を読み取る
 func (t *Table) readCurrentVersion(bp *buffer.BufferPool, columns [][]byte) (lock.TrxId, UndoPtr, error) {
 	btr := btree.NewBTree(t.MetaPageId)
@@ -23,7 +23,7 @@ func TestCreateAndInsert(t *testing.T) {
 
 		indexMetaPageId, err := bp.AllocatePageId(metaPageId.FileId)
 		assert.NoError(t, err)
-		uniqueIndex := NewSecondaryIndex("idx_last_name", "last_name", indexMetaPageId, 2, 1, true)
+		uniqueIndex := NewSecondaryIndex("idx_last_name", []string{"last_name"}, indexMetaPageId, []uint16{2}, 1, true)
 
 		table := NewTable("users", metaPageId, 1, []*SecondaryIndex{uniqueIndex}, nil, nil)
 
@@ -70,7 +70,7 @@ func TestCreateAndInsert(t *testing.T) {
 			keyBytes := record.KeyBytes()
 			_, _, nonKeyColumns := decodeRecordNonKey(record.NonKeyBytes())
 			encode.Decode(keyBytes, &decodedKey)
-			encode.Decode(nonKeyColumns, &decodedValue)
+			decodeNonKeyColumns(nonKeyColumns, &decodedValue)
 
 			assert.Equal(t, expected.key, decodedKey)
 			assert.Equal(t, expected.value, decodedValue)
@@ -107,8 +107,8 @@ func TestCreateAndInsert(t *testing.T) {
 			expected := expectedSecondaryIndexRecords[j]
 
 			// Key をデコードしてセカンダリキーと PK を分離
-			var keyColumns [][]byte
-			encode.Decode(record.KeyBytes(), &keyColumns)
+			keyColumns, encodedPK := encode.DecodeNullableFirstN(record.KeyBytes(), 1)
+			encode.Decode(encodedPK, &keyColumns)
 
 			assert.Equal(t, expected.secondaryKey, string(keyColumns[0]))
 			assert.Equal(t, expected.pk, string(keyColumns[1]))
@@ -131,10 +131,10 @@ func TestCreateAndInsert(t *testing.T) {
 
 		indexMetaPageId1, err := bp.AllocatePageId(metaPageId.FileId)
 		assert.NoError(t, err)
-		uniqueIndex1 := NewSecondaryIndex("idx_first_name", "first_name", indexMetaPageId1, 1, 1, true)
+		uniqueIndex1 := NewSecondaryIndex("idx_first_name", []string{"first_name"}, indexMetaPageId1, []uint16{1}, 1, true)
 		indexMetaPageId2, err := bp.AllocatePageId(metaPageId.FileId)
 		assert.NoError(t, err)
-		uniqueIndex2 := NewSecondaryIndex("idx_last_name", "last_name", indexMetaPageId2, 2, 1, true)
+		uniqueIndex2 := NewSecondaryIndex("idx_last_name", []string{"last_name"}, indexMetaPageId2, []uint16{2}, 1, true)
 
 		table := NewTable("users", metaPageId, 1, []*SecondaryIndex{uniqueIndex1, uniqueIndex2}, nil, nil)
 
@@ -215,7 +215,7 @@ func TestCreateAndInsert(t *testing.T) {
 
 		var decodedValue [][]byte
 		_, _, nonKeyColumns := decodeRecordNonKey(record.NonKeyBytes())
-		encode.Decode(nonKeyColumns, &decodedValue)
+		decodeNonKeyColumns(nonKeyColumns, &decodedValue)
 		assert.Equal(t, "Jane", string(decodedValue[0])) // 新しい値で上書きされている
 
 		// 2 件目は存在しない (物理的にレコードが増えていない)
@@ -251,7 +251,7 @@ func TestSoftDelete(t *testing.T) {
 
 		indexMetaPageId, err := bp.AllocatePageId(metaPageId.FileId)
 		assert.NoError(t, err)
-		uniqueIndex := NewSecondaryIndex("idx_last_name", "last_name", indexMetaPageId, 2, 1, true)
+		uniqueIndex := NewSecondaryIndex("idx_last_name", []string{"last_name"}, indexMetaPageId, []uint16{2}, 1, true)
 
 		table := NewTable("users", metaPageId, 1, []*SecondaryIndex{uniqueIndex}, nil, nil)
 		err = table.Create(bp)
@@ -291,8 +291,7 @@ func TestSoftDelete(t *testing.T) {
 			}
 			// ソフトデリート済みはスキップ
 			if record.HeaderBytes()[0] != 1 {
-				var decodedKey [][]byte
-				encode.Decode(record.KeyBytes(), &decodedKey)
+				decodedKey, _ := encode.DecodeNullableFirstN(record.KeyBytes(), 1)
 				indexKeys = append(indexKeys, string(decodedKey[0]))
 			}
 			_, _, err = indexIter.Next(bp)
@@ -454,7 +453,7 @@ func TestDelete(t *testing.T) {
 
 		indexMetaPageId, err := bp.AllocatePageId(metaPageId.FileId)
 		assert.NoError(t, err)
-		uniqueIndex := NewSecondaryIndex("idx_last_name", "last_name", indexMetaPageId, 2, 1, true)
+		uniqueIndex := NewSecondaryIndex("idx_last_name", []string{"last_name"}, indexMetaPageId, []uint16{2}, 1, true)
 
 		table := NewTable("users", metaPageId, 1, []*SecondaryIndex{uniqueIndex}, nil, nil)
 		err = table.Create(bp)
@@ -485,8 +484,8 @@ func TestDelete(t *testing.T) {
 			if !ok {
 				break
 			}
-			var keyColumns [][]byte
-			encode.Decode(record.KeyBytes(), &keyColumns)
+			keyColumns, encodedPK := encode.DecodeNullableFirstN(record.KeyBytes(), 1)
+			encode.Decode(encodedPK, &keyColumns)
 			indexKeys = append(indexKeys, string(keyColumns[0]))
 			_, _, err = indexIter.Next(bp)
 			assert.NoError(t, err)
@@ -607,7 +606,7 @@ func TestUpdate(t *testing.T) {
 
 		var decodedValue [][]byte
 		_, _, nonKeyColumns := decodeRecordNonKey(record.NonKeyBytes())
-		encode.Decode(nonKeyColumns, &decodedValue)
+		decodeNonKeyColumns(nonKeyColumns, &decodedValue)
 		assert.Equal(t, "Jane", string(decodedValue[0]))
 
 		// 2 件目は存在しない (ソフトデリートされたレコードが残っていない)
@@ -703,7 +702,7 @@ func TestUpdate(t *testing.T) {
 
 		indexMetaPageId, err := bp.AllocatePageId(metaPageId.FileId)
 		assert.NoError(t, err)
-		uniqueIndex := NewSecondaryIndex("idx_last_name", "last_name", indexMetaPageId, 2, 1, true)
+		uniqueIndex := NewSecondaryIndex("idx_last_name", []string{"last_name"}, indexMetaPageId, []uint16{2}, 1, true)
 
 		table := NewTable("users", metaPageId, 1, []*SecondaryIndex{uniqueIndex}, nil, nil)
 		err = table.Create(bp)
@@ -732,7 +731,7 @@ func TestUpdate(t *testing.T) {
 
 		indexMetaPageId, err := bp.AllocatePageId(metaPageId.FileId)
 		assert.NoError(t, err)
-		uniqueIndex := NewSecondaryIndex("idx_last_name", "last_name", indexMetaPageId, 2, 1, true)
+		uniqueIndex := NewSecondaryIndex("idx_last_name", []string{"last_name"}, indexMetaPageId, []uint16{2}, 1, true)
 
 		table := NewTable("users", metaPageId, 1, []*SecondaryIndex{uniqueIndex}, nil, nil)
 		err = table.Create(bp)
@@ -768,8 +767,8 @@ func TestUpdate(t *testing.T) {
 				break
 			}
 			if record.HeaderBytes()[0] != 1 {
-				var keyColumns [][]byte
-				encode.Decode(record.KeyBytes(), &keyColumns)
+				keyColumns, encodedPK := encode.DecodeNullableFirstN(record.KeyBytes(), 1)
+				encode.Decode(encodedPK, &keyColumns)
 				assert.Equal(t, "Doe", string(keyColumns[0]))
 				assert.Equal(t, "x", string(keyColumns[1]))
 				assert.Equal(t, 0, len(record.NonKeyBytes()))
@@ -810,7 +809,7 @@ func TestUpdate(t *testing.T) {
 
 		indexMetaPageId, err := bp.AllocatePageId(metaPageId.FileId)
 		assert.NoError(t, err)
-		uniqueIndex := NewSecondaryIndex("idx_last_name", "last_name", indexMetaPageId, 2, 1, true)
+		uniqueIndex := NewSecondaryIndex("idx_last_name", []string{"last_name"}, indexMetaPageId, []uint16{2}, 1, true)
 
 		table := NewTable("users", metaPageId, 1, []*SecondaryIndex{uniqueIndex}, nil, nil)
 		err = table.Create(bp)
@@ -836,10 +835,10 @@ func TestUpdate(t *testing.T) {
 
 		indexMetaPageId1, err := bp.AllocatePageId(metaPageId.FileId)
 		assert.NoError(t, err)
-		uniqueIndex1 := NewSecondaryIndex("idx_first_name", "first_name", indexMetaPageId1, 1, 1, true)
+		uniqueIndex1 := NewSecondaryIndex("idx_first_name", []string{"first_name"}, indexMetaPageId1, []uint16{1}, 1, true)
 		indexMetaPageId2, err := bp.AllocatePageId(metaPageId.FileId)
 		assert.NoError(t, err)
-		uniqueIndex2 := NewSecondaryIndex("idx_last_name", "last_name", indexMetaPageId2, 2, 1, true)
+		uniqueIndex2 := NewSecondaryIndex("idx_last_name", []string{"last_name"}, indexMetaPageId2, []uint16{2}, 1, true)
 
 		table := NewTable("users", metaPageId, 1, []*SecondaryIndex{uniqueIndex1, uniqueIndex2}, nil, nil)
 		err = table.Create(bp)
@@ -1041,8 +1040,8 @@ func TestSearch(t *testing.T) {
 func TestGetSecondaryIndexByName(t *testing.T) {
 	t.Run("インデックス名からユニークインデックスを取得できる", func(t *testing.T) {
 		// GIVEN
-		uniqueIndex1 := NewSecondaryIndex("idx_first_name", "first_name", page.PageId{}, 1, 1, true)
-		uniqueIndex2 := NewSecondaryIndex("idx_last_name", "last_name", page.PageId{}, 2, 1, true)
+		uniqueIndex1 := NewSecondaryIndex("idx_first_name", []string{"first_name"}, page.PageId{}, []uint16{1}, 1, true)
+		uniqueIndex2 := NewSecondaryIndex("idx_last_name", []string{"last_name"}, page.PageId{}, []uint16{2}, 1, true)
 		table := NewTable("users", page.PageId{}, 1, []*SecondaryIndex{uniqueIndex1, uniqueIndex2}, nil, nil)
 
 		// WHEN
@@ -1055,7 +1054,7 @@ func TestGetSecondaryIndexByName(t *testing.T) {
 
 	t.Run("存在しないインデックス名を指定するとエラーになる", func(t *testing.T) {
 		// GIVEN
-		uniqueIndex := NewSecondaryIndex("idx_first_name", "first_name", page.PageId{}, 1, 1, true)
+		uniqueIndex := NewSecondaryIndex("idx_first_name", []string{"first_name"}, page.PageId{}, []uint16{1}, 1, true)
 		table := NewTable("users", page.PageId{}, 1, []*SecondaryIndex{uniqueIndex}, nil, nil)
 
 		// WHEN
@@ -1066,6 +1065,101 @@ func TestGetSecondaryIndexByName(t *testing.T) {
 	})
 }
 
+func TestRebuild(t *testing.T) {
+	t.Run("カラム構成を変換しながら全レコードを別の B+Tree にコピーできる", func(t *testing.T) {
+		// GIVEN
+		bp, metaPageId, _ := InitDisk(t, "users.db")
+		table := NewTable("users", metaPageId, 1, nil, nil, nil)
+		assert.NoError(t, table.Create(bp))
+		lockMgr := lock.NewManager(5000)
+		assert.NoError(t, table.Insert(bp, 1, lockMgr, [][]byte{[]byte("a"), []byte("John")}))
+		assert.NoError(t, table.Insert(bp, 1, lockMgr, [][]byte{[]byte("b"), []byte("Alice")}))
+
+		destMetaPageId, err := bp.AllocatePageId(metaPageId.FileId)
+		assert.NoError(t, err)
+		dest := NewTable("users", destMetaPageId, 1, nil, nil, nil)
+		assert.NoError(t, dest.Create(bp))
+
+		// WHEN: 末尾に NULL のカラムを追加する
+		err = table.Rebuild(bp, &dest, func(columns [][]byte) ([][]byte, error) {
+			return append(columns, nil), nil
+		})
+
+		// THEN
+		assert.NoError(t, err)
+		iter, err := dest.Search(bp, NewReadView(0, nil, ^uint64(0)), NewVersionReader(nil), RecordSearchModeStart{})
+		assert.NoError(t, err)
+		var records [][][]byte
+		for {
+			record, ok, err := iter.Next()
+			assert.NoError(t, err)
+			if !ok {
+				break
+			}
+			records = append(records, record)
+		}
+		assert.Equal(t, [][][]byte{
+			{[]byte("a"), []byte("John"), nil},
+			{[]byte("b"), []byte("Alice"), nil},
+		}, records)
+	})
+
+	t.Run("DeleteMark と lastModified はそのままコピーされる", func(t *testing.T) {
+		// GIVEN
+		bp, metaPageId, _ := InitDisk(t, "users.db")
+		table := NewTable("users", metaPageId, 1, nil, nil, nil)
+		assert.NoError(t, table.Create(bp))
+		lockMgr := lock.NewManager(5000)
+		assert.NoError(t, table.Insert(bp, 3, lockMgr, [][]byte{[]byte("a"), []byte("John"), []byte("Doe")}))
+		assert.NoError(t, table.SoftDelete(bp, 3, lockMgr, [][]byte{[]byte("a"), []byte("John"), []byte("Doe")}))
+
+		destMetaPageId, err := bp.AllocatePageId(metaPageId.FileId)
+		assert.NoError(t, err)
+		dest := NewTable("users", destMetaPageId, 1, nil, nil, nil)
+		assert.NoError(t, dest.Create(bp))
+
+		// WHEN: 2 番目のカラムを削除する
+		err = table.Rebuild(bp, &dest, func(columns [][]byte) ([][]byte, error) {
+			return [][]byte{columns[0], columns[2]}, nil
+		})
+
+		// THEN
+		assert.NoError(t, err)
+		iter, err := btree.NewBTree(destMetaPageId).Search(bp, btree.SearchModeStart{})
+		assert.NoError(t, err)
+		record, ok, err := iter.Next(bp)
+		assert.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, uint8(1), record.HeaderBytes()[0])
+		lastModified, _, nonKeyColumns := decodeRecordNonKey(record.NonKeyBytes())
+		assert.Equal(t, lock.TrxId(3), lastModified)
+		var columns [][]byte
+		decodeNonKeyColumns(nonKeyColumns, &columns)
+		assert.Equal(t, [][]byte{[]byte("Doe")}, columns)
+	})
+
+	t.Run("convert がエラーを返すとエラーになる", func(t *testing.T) {
+		// GIVEN
+		bp, metaPageId, _ := InitDisk(t, "users.db")
+		table := NewTable("users", metaPageId, 1, nil, nil, nil)
+		assert.NoError(t, table.Create(bp))
+		assert.NoError(t, table.Insert(bp, 1, lock.NewManager(5000), [][]byte{[]byte("a"), []byte("John")}))
+
+		destMetaPageId, err := bp.AllocatePageId(metaPageId.FileId)
+		assert.NoError(t, err)
+		dest := NewTable("users", destMetaPageId, 1, nil, nil, nil)
+		assert.NoError(t, dest.Create(bp))
+
+		// WHEN
+		err = table.Rebuild(bp, &dest, func(columns [][]byte) ([][]byte, error) {
+			return nil, fmt.Errorf("convert failed")
+		})
+
+		// THEN
+		assert.ErrorContains(t, err, "convert failed")
+	})
+}
+
 func InitDisk(t *testing.T, pathname string) (bufferPool *buffer.BufferPool, metaPageId page.PageId, tmpdir string) {
 	tmpdir = t.TempDir()
 	filePath := filepath.Join(tmpdir, pathname)
@@ -1565,8 +1659,8 @@ func collectActiveSecondaryIndexKeys(t *testing.T, bp *buffer.BufferPool, ui *Se
 			break
 		}
 		if record.HeaderBytes()[0] != 1 {
-			var keyColumns [][]byte
-			encode.Decode(record.KeyBytes(), &keyColumns)
+			keyColumns, encodedPK := encode.DecodeNullableFirstN(record.KeyBytes(), 1)
+			encode.Decode(encodedPK, &keyColumns)
 			keys = append(keys, string(keyColumns[0]))
 		}
 		_, _, err = indexIter.Next(bp)
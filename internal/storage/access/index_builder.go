@@ -0,0 +1,151 @@
+package access
+
+import (
+	"bytes"
+	"errors"
+	"slices"
+
+	"github.com/ren-yamanashi/minesql/internal/storage/btree"
+	"github.com/ren-yamanashi/minesql/internal/storage/btree/node"
+	"github.com/ren-yamanashi/minesql/internal/storage/buffer"
+	"github.com/ren-yamanashi/minesql/internal/storage/encode"
+)
+
+// IndexBuilder は既存のテーブルにセカンダリインデックスをオンラインで作成する
+//
+// テーブルへの書き込みを止めずにインデックスを作成するため、以下の流れで作成する
+//  1. 作成開始前に行ログを登録しておく (以降のテーブルへの行の変更が記録される)
+//  2. Build: テーブルの全レコードからインデックスのエントリを集めてソートし、B+Tree をボトムアップで構築する
+//  3. ApplyRowLog: 構築中に行ログに記録された行の変更を B+Tree に適用する
+//  4. CheckUnique: Unique の場合、構築した B+Tree に重複するセカンダリキーがないか確認する
+type IndexBuilder struct {
+	index  *SecondaryIndex // 作成するインデックス (B+Tree のメタページ ID は採番済み)
+	table  *Table          // インデックスを作成するテーブル
+	rowLog *RowLog         // 作成中のテーブルへの行の変更を記録する行ログ
+}
+
+func NewIndexBuilder(index *SecondaryIndex, table *Table, rowLog *RowLog) *IndexBuilder {
+	return &IndexBuilder{
+		index:  index,
+		table:  table,
+		rowLog: rowLog,
+	}
+}
+
+// Build はテーブルの全レコードからインデックスのエントリを作成し、インデックスの B+Tree を構築する
+//
+// エントリをキー (セカンダリキー + PK) の順にソートしてから、B+Tree をリーフノードから順にボトムアップで構築する。
+// delete-marked レコードはソフトデリート済みのエントリとして登録する (パージでレコードと一緒に物理削除されるため)
+func (b *IndexBuilder) Build(bp *buffer.BufferPool) error {
+	iter, err := btree.NewBTree(b.table.MetaPageId).Search(bp, btree.SearchModeStart{})
+	if err != nil {
+		return err
+	}
+
+	var entries []node.Record
+	for {
+		record, ok, err := iter.Next(bp)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			break
+		}
+
+		var columns [][]byte
+		encode.Decode(record.KeyBytes(), &columns)
+		_, _, nonKeyColumns := decodeRecordNonKey(record.NonKeyBytes())
+		decodeNonKeyColumns(nonKeyColumns, &columns)
+
+		fullKey := b.index.getFullKey(b.table.EncodeKey(columns), columns)
+		entries = append(entries, node.NewRecord([]byte{record.HeaderBytes()[0]}, fullKey, nil))
+	}
+
+	slices.SortFunc(entries, func(a, b node.Record) int {
+		return bytes.Compare(a.KeyBytes(), b.KeyBytes())
+	})
+	_, err = btree.BulkLoad(bp, b.index.MetaPageId, entries)
+	return err
+}
+
+// ApplyRowLog は行ログに記録された行の変更を記録順にインデックスに適用する
+//
+// 行の変更はエントリの最終的な状態 (active・ソフトデリート済み・削除済み) として適用するため、
+// Build の走査で既に反映済みの変更を再度適用しても結果は変わらない
+func (b *IndexBuilder) ApplyRowLog(bp *buffer.BufferPool) error {
+	btr := btree.NewBTree(b.index.MetaPageId)
+	for _, record := range b.rowLog.Drain() {
+		fullKey := b.index.getFullKey(record.EncodedPK, record.Columns)
+
+		switch record.Op {
+		case RowLogInsert:
+			if err := upsertIndexEntry(bp, btr, node.NewRecord([]byte{0}, fullKey, nil)); err != nil {
+				return err
+			}
+		case RowLogSoftDelete:
+			if err := upsertIndexEntry(bp, btr, node.NewRecord([]byte{1}, fullKey, nil)); err != nil {
+				return err
+			}
+		case RowLogDelete:
+			if err := btr.Delete(bp, fullKey); err != nil && !errors.Is(err, btree.ErrKeyNotFound) {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// CheckUnique は Unique の場合に、インデックスに同じセカンダリキーを持つ active なエントリが複数ないか確認する
+//
+// 重複がある場合は ErrDuplicateKey を返す
+func (b *IndexBuilder) CheckUnique(bp *buffer.BufferPool) error {
+	if !b.index.Unique {
+		return nil
+	}
+	iter, err := btree.NewBTree(b.index.MetaPageId).Search(bp, btree.SearchModeStart{})
+	if err != nil {
+		return err
+	}
+
+	// エントリはセカンダリキー順に並んでいるため、同じセカンダリキーのエントリは連続する
+	var prevSecKey []byte
+	prevActive := false
+	for {
+		record, ok, err := iter.Next(bp)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return nil
+		}
+
+		// NULL を含むセカンダリキーはどのエントリとも重複しない
+		secKey, _ := encode.DecodeNullableFirstN(record.KeyBytes(), len(b.index.ColIdxs))
+		if slices.ContainsFunc(secKey, func(v []byte) bool { return v == nil }) {
+			prevSecKey = nil
+			continue
+		}
+		var encodedSecKey []byte
+		encode.EncodeNullable(secKey, &encodedSecKey)
+		active := record.HeaderBytes()[0] != 1
+
+		if !bytes.Equal(encodedSecKey, prevSecKey) {
+			prevSecKey = encodedSecKey
+			prevActive = active
+			continue
+		}
+		if active && prevActive {
+			return btree.ErrDuplicateKey
+		}
+		prevActive = prevActive || active
+	}
+}
+
+// upsertIndexEntry はインデックスにエントリを挿入し、同じキーのエントリが既にある場合は上書きする
+func upsertIndexEntry(bp *buffer.BufferPool, btr *btree.BTree, record node.Record) error {
+	err := btr.Insert(bp, record)
+	if errors.Is(err, btree.ErrDuplicateKey) {
+		return btr.Update(bp, record)
+	}
+	return err
+}
@@ -0,0 +1,237 @@
+package access
+
+import (
+	"testing"
+
+	"github.com/ren-yamanashi/minesql/internal/storage/btree"
+	"github.com/ren-yamanashi/minesql/internal/storage/buffer"
+	"github.com/ren-yamanashi/minesql/internal/storage/encode"
+	"github.com/ren-yamanashi/minesql/internal/storage/lock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIndexBuilderBuild(t *testing.T) {
+	t.Run("テーブルの既存レコードからインデックスを作成できる", func(t *testing.T) {
+		// GIVEN: name が NULL の行とソフトデリート済みの行を含むテーブル
+		bp, table, lockMgr := setupIndexBuilderTable(t, nil)
+		require.NoError(t, table.Insert(bp, 1, lockMgr, [][]byte{[]byte("a"), []byte("John"), []byte("x")}))
+		require.NoError(t, table.Insert(bp, 1, lockMgr, [][]byte{[]byte("b"), []byte("Alice"), []byte("x")}))
+		require.NoError(t, table.Insert(bp, 1, lockMgr, [][]byte{[]byte("c"), nil, []byte("x")}))
+		require.NoError(t, table.Insert(bp, 1, lockMgr, [][]byte{[]byte("d"), []byte("Bob"), []byte("x")}))
+		require.NoError(t, table.SoftDelete(bp, 1, lockMgr, [][]byte{[]byte("d"), []byte("Bob"), []byte("x")}))
+		si := newBuilderIndex(t, bp, table, []string{"name"}, []uint16{1}, false)
+
+		// WHEN
+		err := NewIndexBuilder(si, table, &RowLog{}).Build(bp)
+
+		// THEN: NULL の行は NULL をキーとして先頭に登録され、ソフトデリート済みの行は DeleteMark=1 で登録される
+		assert.NoError(t, err)
+		assert.Equal(t, []indexEntry{
+			{[]string{"NULL", "c"}, 0},
+			{[]string{"Alice", "b"}, 0},
+			{[]string{"Bob", "d"}, 1},
+			{[]string{"John", "a"}, 0},
+		}, collectIndexEntries(t, bp, si))
+	})
+
+	t.Run("複合インデックスはカラムの順にソートして作成する", func(t *testing.T) {
+		// GIVEN
+		bp, table, lockMgr := setupIndexBuilderTable(t, nil)
+		require.NoError(t, table.Insert(bp, 1, lockMgr, [][]byte{[]byte("a"), []byte("John"), []byte("Tokyo")}))
+		require.NoError(t, table.Insert(bp, 1, lockMgr, [][]byte{[]byte("b"), []byte("Alice"), []byte("Osaka")}))
+		require.NoError(t, table.Insert(bp, 1, lockMgr, [][]byte{[]byte("c"), []byte("Alice"), []byte("Kyoto")}))
+		require.NoError(t, table.Insert(bp, 1, lockMgr, [][]byte{[]byte("d"), []byte("Bob"), nil}))
+		si := newBuilderIndex(t, bp, table, []string{"name", "city"}, []uint16{1, 2}, false)
+
+		// WHEN
+		err := NewIndexBuilder(si, table, &RowLog{}).Build(bp)
+
+		// THEN: いずれかのカラムが NULL の行も登録され、NULL は NULL 以外の値より前に並ぶ
+		assert.NoError(t, err)
+		assert.Equal(t, []indexEntry{
+			{[]string{"Alice", "Kyoto", "c"}, 0},
+			{[]string{"Alice", "Osaka", "b"}, 0},
+			{[]string{"Bob", "NULL", "d"}, 0},
+			{[]string{"John", "Tokyo", "a"}, 0},
+		}, collectIndexEntries(t, bp, si))
+	})
+
+	t.Run("作成したインデックスで検索できる", func(t *testing.T) {
+		// GIVEN
+		bp, table, lockMgr := setupIndexBuilderTable(t, nil)
+		require.NoError(t, table.Insert(bp, 1, lockMgr, [][]byte{[]byte("a"), []byte("John"), []byte("Tokyo")}))
+		require.NoError(t, table.Insert(bp, 1, lockMgr, [][]byte{[]byte("b"), []byte("Alice"), []byte("Osaka")}))
+		si := newBuilderIndex(t, bp, table, []string{"name"}, []uint16{1}, true)
+		require.NoError(t, NewIndexBuilder(si, table, &RowLog{}).Build(bp))
+
+		// WHEN
+		iter, err := si.Search(bp, table, RecordSearchModeKey{Key: [][]byte{[]byte("John")}})
+		require.NoError(t, err)
+		result, ok, err := iter.Next()
+
+		// THEN
+		assert.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, "a", string(result.Record[0]))
+	})
+}
+
+func TestIndexBuilderApplyRowLog(t *testing.T) {
+	t.Run("作成中に行われた行の変更をインデックスに適用できる", func(t *testing.T) {
+		// GIVEN: 行ログを登録してからインデックスを作成する
+		rowLogs := NewRowLogRegistry()
+		bp, table, lockMgr := setupIndexBuilderTable(t, rowLogs)
+		require.NoError(t, table.Insert(bp, 1, lockMgr, [][]byte{[]byte("a"), []byte("John"), []byte("x")}))
+		require.NoError(t, table.Insert(bp, 1, lockMgr, [][]byte{[]byte("b"), []byte("Alice"), []byte("x")}))
+		require.NoError(t, table.Insert(bp, 1, lockMgr, [][]byte{[]byte("c"), []byte("Bob"), []byte("x")}))
+		rowLog := rowLogs.Register("users")
+		si := newBuilderIndex(t, bp, table, []string{"name"}, []uint16{1}, false)
+		builder := NewIndexBuilder(si, table, rowLog)
+		require.NoError(t, builder.Build(bp))
+
+		// 作成後の変更はインデックスに反映されず、行ログに記録される
+		require.NoError(t, table.Insert(bp, 1, lockMgr, [][]byte{[]byte("d"), []byte("Carol"), []byte("x")}))
+		require.NoError(t, table.SoftDelete(bp, 1, lockMgr, [][]byte{[]byte("b"), []byte("Alice"), []byte("x")}))
+		require.NoError(t, table.UpdateInplace(bp, 1, lockMgr,
+			[][]byte{[]byte("a"), []byte("John"), []byte("x")},
+			[][]byte{[]byte("a"), []byte("Johnny"), []byte("x")},
+		))
+		require.NoError(t, table.delete(bp, 1, lockMgr, [][]byte{[]byte("c"), []byte("Bob"), []byte("x")}))
+
+		// WHEN
+		err := builder.ApplyRowLog(bp)
+
+		// THEN
+		assert.NoError(t, err)
+		assert.Equal(t, []indexEntry{
+			{[]string{"Alice", "b"}, 1},
+			{[]string{"Carol", "d"}, 0},
+			{[]string{"Johnny", "a"}, 0},
+		}, collectIndexEntries(t, bp, si))
+		assert.Empty(t, rowLog.Drain())
+	})
+
+	t.Run("Build の走査で反映済みの変更を再度適用しても結果は変わらない", func(t *testing.T) {
+		// GIVEN: Build の前に行の変更が行ログに記録されている
+		rowLogs := NewRowLogRegistry()
+		bp, table, lockMgr := setupIndexBuilderTable(t, rowLogs)
+		rowLog := rowLogs.Register("users")
+		require.NoError(t, table.Insert(bp, 1, lockMgr, [][]byte{[]byte("a"), []byte("John"), []byte("x")}))
+		require.NoError(t, table.Insert(bp, 1, lockMgr, [][]byte{[]byte("b"), []byte("Alice"), []byte("x")}))
+		require.NoError(t, table.SoftDelete(bp, 1, lockMgr, [][]byte{[]byte("b"), []byte("Alice"), []byte("x")}))
+		si := newBuilderIndex(t, bp, table, []string{"name"}, []uint16{1}, false)
+		builder := NewIndexBuilder(si, table, rowLog)
+		require.NoError(t, builder.Build(bp))
+
+		// WHEN
+		err := builder.ApplyRowLog(bp)
+
+		// THEN
+		assert.NoError(t, err)
+		assert.Equal(t, []indexEntry{
+			{[]string{"Alice", "b"}, 1},
+			{[]string{"John", "a"}, 0},
+		}, collectIndexEntries(t, bp, si))
+	})
+}
+
+func TestIndexBuilderCheckUnique(t *testing.T) {
+	t.Run("ユニークインデックスで値が重複している場合は ErrDuplicateKey を返す", func(t *testing.T) {
+		// GIVEN
+		bp, table, lockMgr := setupIndexBuilderTable(t, nil)
+		require.NoError(t, table.Insert(bp, 1, lockMgr, [][]byte{[]byte("a"), []byte("John"), []byte("x")}))
+		require.NoError(t, table.Insert(bp, 1, lockMgr, [][]byte{[]byte("b"), []byte("John"), []byte("x")}))
+		si := newBuilderIndex(t, bp, table, []string{"name"}, []uint16{1}, true)
+		builder := NewIndexBuilder(si, table, &RowLog{})
+		require.NoError(t, builder.Build(bp))
+
+		// WHEN
+		err := builder.CheckUnique(bp)
+
+		// THEN
+		assert.ErrorIs(t, err, btree.ErrDuplicateKey)
+	})
+
+	t.Run("重複する値の一方がソフトデリート済みの場合はエラーにならない", func(t *testing.T) {
+		// GIVEN
+		bp, table, lockMgr := setupIndexBuilderTable(t, nil)
+		require.NoError(t, table.Insert(bp, 1, lockMgr, [][]byte{[]byte("a"), []byte("John"), []byte("x")}))
+		require.NoError(t, table.Insert(bp, 1, lockMgr, [][]byte{[]byte("b"), []byte("John"), []byte("x")}))
+		require.NoError(t, table.SoftDelete(bp, 1, lockMgr, [][]byte{[]byte("a"), []byte("John"), []byte("x")}))
+		si := newBuilderIndex(t, bp, table, []string{"name"}, []uint16{1}, true)
+		builder := NewIndexBuilder(si, table, &RowLog{})
+		require.NoError(t, builder.Build(bp))
+
+		// WHEN
+		err := builder.CheckUnique(bp)
+
+		// THEN
+		assert.NoError(t, err)
+	})
+
+	t.Run("複合ユニークインデックスはすべてのカラムの組み合わせで重複を判定する", func(t *testing.T) {
+		// GIVEN: name は重複しているが (name, city) の組み合わせは重複していない
+		bp, table, lockMgr := setupIndexBuilderTable(t, nil)
+		require.NoError(t, table.Insert(bp, 1, lockMgr, [][]byte{[]byte("a"), []byte("John"), []byte("Tokyo")}))
+		require.NoError(t, table.Insert(bp, 1, lockMgr, [][]byte{[]byte("b"), []byte("John"), []byte("Osaka")}))
+		si := newBuilderIndex(t, bp, table, []string{"name", "city"}, []uint16{1, 2}, true)
+		builder := NewIndexBuilder(si, table, &RowLog{})
+		require.NoError(t, builder.Build(bp))
+
+		// WHEN
+		err := builder.CheckUnique(bp)
+
+		// THEN
+		assert.NoError(t, err)
+	})
+}
+
+// indexEntry はインデックスのエントリ (キーのカラム値と DeleteMark)
+type indexEntry struct {
+	key        []string
+	deleteMark uint8
+}
+
+// setupIndexBuilderTable は users (id, name, city) テーブルを作成する
+func setupIndexBuilderTable(t *testing.T, rowLogs *RowLogRegistry) (*buffer.BufferPool, *Table, *lock.Manager) {
+	t.Helper()
+	bp, metaPageId, _ := InitDisk(t, "test.db")
+	table := NewTable("users", metaPageId, 1, nil, nil, nil)
+	table.SetRowLogs(rowLogs)
+	require.NoError(t, table.Create(bp))
+	return bp, &table, lock.NewManager(5000)
+}
+
+// newBuilderIndex はメタページ ID を採番したセカンダリインデックスを生成する (B+Tree は Build で作成する)
+func newBuilderIndex(t *testing.T, bp *buffer.BufferPool, table *Table, colNames []string, colIdxs []uint16, unique bool) *SecondaryIndex {
+	t.Helper()
+	indexMetaPageId, err := bp.AllocatePageId(table.MetaPageId.FileId)
+	require.NoError(t, err)
+	return NewSecondaryIndex("idx_"+colNames[0], colNames, indexMetaPageId, colIdxs, 1, unique)
+}
+
+// collectIndexEntries はインデックスの全エントリをキー順に返す
+func collectIndexEntries(t *testing.T, bp *buffer.BufferPool, si *SecondaryIndex) []indexEntry {
+	t.Helper()
+	iter, err := btree.NewBTree(si.MetaPageId).Search(bp, btree.SearchModeStart{})
+	require.NoError(t, err)
+	var entries []indexEntry
+	for {
+		record, ok, err := iter.Next(bp)
+		require.NoError(t, err)
+		if !ok {
+			return entries
+		}
+		keyColumns, encodedPK := encode.DecodeNullableFirstN(record.KeyBytes(), len(si.ColIdxs))
+		encode.Decode(encodedPK, &keyColumns)
+		key := make([]string, len(keyColumns))
+		for i, col := range keyColumns {
+			key[i] = string(col)
+			if col == nil {
+				key[i] = "NULL"
+			}
+		}
+		entries = append(entries, indexEntry{key, record.HeaderBytes()[0]})
+	}
+}
@@ -17,15 +17,15 @@ type SecondaryIndexIterator struct {
 	iterator   *btree.Iterator    // セカンダリインデックスの B+Tree イテレータ
 	tableBTree *btree.BTree       // テーブル本体の B+Tree (インデックス検索 → テーブル検索の流れで使用)
 	bp         *buffer.BufferPool // バッファプール
-	pkCount    uint8              // PK のカラム数
+	keyCount   int                // セカンダリキーのカラム数 (複合インデックスの場合は 2 以上)
 }
 
-func newSecondaryIndexIterator(iterator *btree.Iterator, tableBTree *btree.BTree, bp *buffer.BufferPool, pkCount uint8) *SecondaryIndexIterator {
+func newSecondaryIndexIterator(iterator *btree.Iterator, tableBTree *btree.BTree, bp *buffer.BufferPool, keyCount int) *SecondaryIndexIterator {
 	return &SecondaryIndexIterator{
 		iterator:   iterator,
 		tableBTree: tableBTree,
 		bp:         bp,
-		pkCount:    pkCount,
+		keyCount:   keyCount,
 	}
 }
 
@@ -49,9 +49,9 @@ func (sii *SecondaryIndexIterator) Next() (*SearchResult, bool, error) {
 			continue
 		}
 
-		// Key = concat(encodedSecKey, encodedPK) から先頭のセカンダリキーだけをデコードし、
+		// Key = concat(encodedSecKey, encodedPK) から先頭のセカンダリキーのカラムだけをデコードし、
 		// 残りのエンコード済み PK バイト列はそのままテーブル検索に使う (再エンコード不要)
-		secondaryKey, encodedPK := encode.DecodeFirstN(indexRecord.KeyBytes(), 1)
+		secondaryKey, encodedPK := encode.DecodeNullableFirstN(indexRecord.KeyBytes(), sii.keyCount)
 
 		// テーブル本体を検索してレコードを取得
 		tableIterator, err := sii.tableBTree.Search(sii.bp, btree.SearchModeKey{Key: encodedPK})
@@ -70,7 +70,7 @@ func (sii *SecondaryIndexIterator) Next() (*SearchResult, bool, error) {
 		var record [][]byte
 		encode.Decode(tableRecord.KeyBytes(), &record)
 		_, _, nonKeyColumns := decodeRecordNonKey(tableRecord.NonKeyBytes())
-		encode.Decode(nonKeyColumns, &record)
+		decodeNonKeyColumns(nonKeyColumns, &record)
 
 		return &SearchResult{
 			SecondaryKey: secondaryKey,
@@ -98,7 +98,7 @@ func (sii *SecondaryIndexIterator) NextIndexOnly() (*SearchResult, bool, error)
 		}
 
 		// Key = concat(encodedSecKey, encodedPK) からセカンダリキーと PK をデコード
-		secondaryKey, encodedPK := encode.DecodeFirstN(indexRecord.KeyBytes(), 1)
+		secondaryKey, encodedPK := encode.DecodeNullableFirstN(indexRecord.KeyBytes(), sii.keyCount)
 
 		var pkValues [][]byte
 		encode.Decode(encodedPK, &pkValues)
@@ -14,7 +14,7 @@ func TestSecondaryIndexIterator(t *testing.T) {
 
 		indexMetaPageId, err := bp.AllocatePageId(metaPageId.FileId)
 		assert.NoError(t, err)
-		uniqueIndex := NewSecondaryIndex("idx_last_name", "last_name", indexMetaPageId, 2, 1, true)
+		uniqueIndex := NewSecondaryIndex("idx_last_name", []string{"last_name"}, indexMetaPageId, []uint16{2}, 1, true)
 
 		table := NewTable("users", metaPageId, 1, []*SecondaryIndex{uniqueIndex}, nil, nil)
 		err = table.Create(bp)
@@ -63,7 +63,7 @@ func TestSecondaryIndexIterator(t *testing.T) {
 
 		indexMetaPageId, err := bp.AllocatePageId(metaPageId.FileId)
 		assert.NoError(t, err)
-		uniqueIndex := NewSecondaryIndex("idx_last_name", "last_name", indexMetaPageId, 2, 1, true)
+		uniqueIndex := NewSecondaryIndex("idx_last_name", []string{"last_name"}, indexMetaPageId, []uint16{2}, 1, true)
 
 		table := NewTable("users", metaPageId, 1, []*SecondaryIndex{uniqueIndex}, nil, nil)
 		err = table.Create(bp)
@@ -93,7 +93,7 @@ func TestSecondaryIndexIterator(t *testing.T) {
 
 		indexMetaPageId, err := bp.AllocatePageId(metaPageId.FileId)
 		assert.NoError(t, err)
-		uniqueIndex := NewSecondaryIndex("idx_col", "col", indexMetaPageId, 0, 1, true)
+		uniqueIndex := NewSecondaryIndex("idx_col", []string{"col"}, indexMetaPageId, []uint16{0}, 1, true)
 
 		table := NewTable("test", metaPageId, 1, []*SecondaryIndex{uniqueIndex}, nil, nil)
 		err = table.Create(bp)
@@ -117,7 +117,7 @@ func TestSecondaryIndexIterator(t *testing.T) {
 
 		indexMetaPageId, err := bp.AllocatePageId(metaPageId.FileId)
 		assert.NoError(t, err)
-		uniqueIndex := NewSecondaryIndex("idx_last_name", "last_name", indexMetaPageId, 2, 1, true)
+		uniqueIndex := NewSecondaryIndex("idx_last_name", []string{"last_name"}, indexMetaPageId, []uint16{2}, 1, true)
 
 		table := NewTable("users", metaPageId, 1, []*SecondaryIndex{uniqueIndex}, nil, nil)
 		err = table.Create(bp)
@@ -162,7 +162,7 @@ func TestSecondaryIndexIterator(t *testing.T) {
 
 		indexMetaPageId, err := bp.AllocatePageId(metaPageId.FileId)
 		assert.NoError(t, err)
-		uniqueIndex := NewSecondaryIndex("idx_last_name", "last_name", indexMetaPageId, 2, 1, true)
+		uniqueIndex := NewSecondaryIndex("idx_last_name", []string{"last_name"}, indexMetaPageId, []uint16{2}, 1, true)
 
 		table := NewTable("users", metaPageId, 1, []*SecondaryIndex{uniqueIndex}, nil, nil)
 		err = table.Create(bp)
@@ -196,7 +196,7 @@ func TestSecondaryIndexIterator(t *testing.T) {
 
 		indexMetaPageId, err := bp.AllocatePageId(metaPageId.FileId)
 		assert.NoError(t, err)
-		uniqueIndex := NewSecondaryIndex("idx_last_name", "last_name", indexMetaPageId, 2, 1, true)
+		uniqueIndex := NewSecondaryIndex("idx_last_name", []string{"last_name"}, indexMetaPageId, []uint16{2}, 1, true)
 
 		table := NewTable("users", metaPageId, 1, []*SecondaryIndex{uniqueIndex}, nil, nil)
 		err = table.Create(bp)
@@ -241,7 +241,7 @@ func TestSecondaryIndexIterator(t *testing.T) {
 
 		indexMetaPageId, err := bp.AllocatePageId(metaPageId.FileId)
 		assert.NoError(t, err)
-		uniqueIndex := NewSecondaryIndex("idx_last_name", "last_name", indexMetaPageId, 2, 1, true)
+		uniqueIndex := NewSecondaryIndex("idx_last_name", []string{"last_name"}, indexMetaPageId, []uint16{2}, 1, true)
 
 		table := NewTable("users", metaPageId, 1, []*SecondaryIndex{uniqueIndex}, nil, nil)
 		err = table.Create(bp)
@@ -282,7 +282,7 @@ func TestSecondaryIndexIterator(t *testing.T) {
 
 		indexMetaPageId, err := bp.AllocatePageId(metaPageId.FileId)
 		assert.NoError(t, err)
-		uniqueIndex := NewSecondaryIndex("idx_last_name", "last_name", indexMetaPageId, 2, 1, true)
+		uniqueIndex := NewSecondaryIndex("idx_last_name", []string{"last_name"}, indexMetaPageId, []uint16{2}, 1, true)
 
 		table := NewTable("users", metaPageId, 1, []*SecondaryIndex{uniqueIndex}, nil, nil)
 		err = table.Create(bp)
@@ -313,7 +313,7 @@ func TestSecondaryIndexIterator(t *testing.T) {
 
 		indexMetaPageId, err := bp.AllocatePageId(metaPageId.FileId)
 		assert.NoError(t, err)
-		uniqueIndex := NewSecondaryIndex("idx_col", "col", indexMetaPageId, 0, 1, true)
+		uniqueIndex := NewSecondaryIndex("idx_col", []string{"col"}, indexMetaPageId, []uint16{0}, 1, true)
 
 		table := NewTable("test", metaPageId, 1, []*SecondaryIndex{uniqueIndex}, nil, nil)
 		err = table.Create(bp)
@@ -337,7 +337,7 @@ func TestSecondaryIndexIterator(t *testing.T) {
 
 		indexMetaPageId, err := bp.AllocatePageId(metaPageId.FileId)
 		assert.NoError(t, err)
-		uniqueIndex := NewSecondaryIndex("idx_last_name", "last_name", indexMetaPageId, 2, 1, true)
+		uniqueIndex := NewSecondaryIndex("idx_last_name", []string{"last_name"}, indexMetaPageId, []uint16{2}, 1, true)
 
 		table := NewTable("users", metaPageId, 1, []*SecondaryIndex{uniqueIndex}, nil, nil)
 		err = table.Create(bp)
@@ -45,7 +45,7 @@ func (ri *TableIterator) Next() ([][]byte, bool, error) {
 		// カラムデータをデコード
 		var columns [][]byte
 		encode.Decode(btrRecord.KeyBytes(), &columns)
-		encode.Decode(nonKeyColumns, &columns)
+		decodeNonKeyColumns(nonKeyColumns, &columns)
 
 		// 可視なバージョンを探す
 		current := RecordVersion{
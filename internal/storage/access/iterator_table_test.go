@@ -190,6 +190,27 @@ func TestTableIterator(t *testing.T) {
 		assert.Equal(t, [][]byte{[]byte("a"), []byte("2"), []byte("value2")}, record2)
 	})
 
+	t.Run("NULL のカラムは nil としてデコードされる", func(t *testing.T) {
+		// GIVEN
+		bp, metaPageId, _ := InitDisk(t, "iter_test.db")
+		table := NewTable("test", metaPageId, 1, nil, nil, nil)
+		err := table.Create(bp)
+		assert.NoError(t, err)
+
+		err = table.Insert(bp, 0, lock.NewManager(5000), [][]byte{[]byte("a"), nil, []byte("value1")})
+		assert.NoError(t, err)
+
+		// WHEN
+		iter, err := table.Search(bp, allVisibleReadView(), nilVersionReader(), RecordSearchModeStart{})
+		assert.NoError(t, err)
+		record, ok, err := iter.Next()
+
+		// THEN
+		assert.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, [][]byte{[]byte("a"), nil, []byte("value1")}, record)
+	})
+
 	t.Run("不可視なトランザクションのレコードはスキップされる", func(t *testing.T) {
 		// GIVEN: T1 が INSERT、T2 が INSERT。T2 はアクティブ (不可視)
 		bp, metaPageId, _ := InitDisk(t, "iter_test.db")
@@ -2,6 +2,7 @@ package access
 
 import (
 	stdlog "log"
+	"sync"
 	"time"
 
 	"github.com/ren-yamanashi/minesql/internal/storage/btree"
@@ -26,6 +27,10 @@ type PurgeThread struct {
 	ticker     *time.Ticker       // 定期実行用タイマー
 	done       chan struct{}      // 停止シグナル
 	stopped    chan struct{}      // goroutine 終了通知用
+	mutex      sync.Mutex         // started・pauseCount と goroutine の起動・停止を保護する
+	started    bool               // Start されてから Stop されるまで true
+	pauseCount int                // Pause されている数 (0 より大きい間は goroutine を停止する)
+	purgeMutex sync.Mutex         // RunPurge を直列化する (DDL から直接呼ばれる場合がある)
 }
 
 // NewPurgeThread は PurgeThread を生成する
@@ -41,15 +46,58 @@ func NewPurgeThread(bp *buffer.BufferPool, trxManager *TrxManager, undoLog *Undo
 }
 
 // Start はバックグラウンド goroutine を起動する
+//
+// Pause されている場合は、すべて Resume されるまで起動を遅らせる
 func (pt *PurgeThread) Start() {
+	pt.mutex.Lock()
+	defer pt.mutex.Unlock()
+	pt.started = true
+	if pt.pauseCount == 0 {
+		pt.startLoop()
+	}
+}
+
+// Stop はバックグラウンド goroutine を停止し、終了を待つ
+func (pt *PurgeThread) Stop() {
+	pt.mutex.Lock()
+	defer pt.mutex.Unlock()
+	pt.started = false
+	pt.stopLoop()
+}
+
+// Pause はバックグラウンド goroutine を一時停止し、実行中のパージの終了を待つ
+//
+// DDL ごとに呼び出し、対応する Resume がすべて呼ばれるまで停止したままにする (複数の DDL が同時に実行される場合も、最後の DDL の終了まで再開しない)
+func (pt *PurgeThread) Pause() {
+	pt.mutex.Lock()
+	defer pt.mutex.Unlock()
+	pt.pauseCount++
+	pt.stopLoop()
+}
+
+// Resume は Pause を解除し、すべての Pause が解除された場合は (Start 済みであれば) バックグラウンド goroutine を再開する
+func (pt *PurgeThread) Resume() {
+	pt.mutex.Lock()
+	defer pt.mutex.Unlock()
+	pt.pauseCount--
+	if pt.pauseCount == 0 && pt.started {
+		pt.startLoop()
+	}
+}
+
+// startLoop は goroutine が起動していなければ起動する (mutex を保持して呼び出す)
+func (pt *PurgeThread) startLoop() {
+	if pt.done != nil {
+		return
+	}
 	pt.ticker = time.NewTicker(pt.interval)
 	pt.done = make(chan struct{})
 	pt.stopped = make(chan struct{})
-	go pt.loop()
+	go pt.loop(pt.ticker, pt.done, pt.stopped)
 }
 
-// Stop はバックグラウンド goroutine を停止し、終了を待つ
-func (pt *PurgeThread) Stop() {
+// stopLoop は goroutine が起動していれば停止し、終了を待つ (mutex を保持して呼び出す)
+func (pt *PurgeThread) stopLoop() {
 	if pt.done == nil {
 		return
 	}
@@ -60,13 +108,15 @@ func (pt *PurgeThread) Stop() {
 }
 
 // loop はバックグラウンドで定期的にパージを実行する
-func (pt *PurgeThread) loop() {
-	defer close(pt.stopped)
+//
+// 停止後に再起動した goroutine とフィールドを共有しないよう、タイマーとチャネルは引数で受け取る
+func (pt *PurgeThread) loop(ticker *time.Ticker, done <-chan struct{}, stopped chan<- struct{}) {
+	defer close(stopped)
 	for {
 		select {
-		case <-pt.done:
+		case <-done:
 			return
-		case <-pt.ticker.C:
+		case <-ticker.C:
 			purgeLimit := pt.trxManager.PurgeLimit()
 			committedIds := pt.trxManager.CommittedTrxIds()
 			if err := pt.RunPurge(purgeLimit, committedIds); err != nil {
@@ -78,6 +128,9 @@ func (pt *PurgeThread) loop() {
 
 // RunPurge はパージ閾値に基づいて delete-marked レコードの物理削除と undo ログの破棄を行う
 func (pt *PurgeThread) RunPurge(purgeLimit lock.TrxId, committedTrxIds []lock.TrxId) error {
+	pt.purgeMutex.Lock()
+	defer pt.purgeMutex.Unlock()
+
 	// delete-marked レコードの物理削除
 	for _, table := range pt.tables() {
 		if err := pt.purgeDeleteMarked(table, purgeLimit); err != nil {
@@ -136,7 +189,7 @@ func (pt *PurgeThread) collectPurgeTargets(table *Table, purgeLimit lock.TrxId)
 		var columns [][]byte
 		encode.Decode(record.KeyBytes(), &columns)
 		_, _, nonKeyColumns := decodeRecordNonKey(record.NonKeyBytes())
-		encode.Decode(nonKeyColumns, &columns)
+		decodeNonKeyColumns(nonKeyColumns, &columns)
 		targets = append(targets, columns)
 	}
 
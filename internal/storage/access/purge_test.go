@@ -1,6 +1,7 @@
 package access
 
 import (
+	"sync"
 	"testing"
 	"time"
 
@@ -216,6 +217,65 @@ func TestPurgeThread(t *testing.T) {
 		assert.NotPanics(t, func() { pt.Stop() })
 	})
 
+	t.Run("Pause した数だけ Resume されるまで停止したままになる", func(t *testing.T) {
+		// GIVEN
+		pt := newTestPurgeThread(nil, lock.NewManager(5000), nil)
+		pt.interval = time.Hour
+		pt.Start()
+		defer pt.Stop()
+
+		// WHEN
+		pt.Pause()
+		pt.Pause()
+		pt.Resume()
+
+		// THEN
+		assert.Nil(t, pt.done)
+		pt.Resume()
+		assert.NotNil(t, pt.done)
+	})
+
+	t.Run("Pause 中に Start しても、Resume されるまで起動しない", func(t *testing.T) {
+		// GIVEN
+		pt := newTestPurgeThread(nil, lock.NewManager(5000), nil)
+		pt.interval = time.Hour
+		defer pt.Stop()
+
+		// WHEN
+		pt.Pause()
+		pt.Start()
+
+		// THEN
+		assert.Nil(t, pt.done)
+		pt.Resume()
+		assert.NotNil(t, pt.done)
+	})
+
+	t.Run("複数の goroutine から同時に Pause・Resume してもパニックしない", func(t *testing.T) {
+		// GIVEN
+		pt := newTestPurgeThread(nil, lock.NewManager(5000), nil)
+		pt.interval = time.Hour
+		pt.Start()
+		defer pt.Stop()
+
+		// WHEN
+		var wg sync.WaitGroup
+		for range 10 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				pt.Pause()
+				time.Sleep(time.Millisecond)
+				pt.Resume()
+			}()
+		}
+		wg.Wait()
+
+		// THEN: すべて Resume されたので再開している
+		assert.Equal(t, 0, pt.pauseCount)
+		assert.NotNil(t, pt.done)
+	})
+
 	t.Run("パージが実行されて delete-marked レコードが物理削除される", func(t *testing.T) {
 		// GIVEN
 		bp := initUndoTestDisk(t)
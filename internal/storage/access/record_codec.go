@@ -3,11 +3,15 @@ package access
 import (
 	"encoding/binary"
 
+	"github.com/ren-yamanashi/minesql/internal/storage/encode"
 	"github.com/ren-yamanashi/minesql/internal/storage/lock"
 )
 
 const lastModifiedSize = 8
 
+// NULL ビットマップの先頭に格納するカラム数のサイズ
+const nullBitmapCountSize = 2
+
 // encodeRecordNonKeyPrefix は lastModified と rollPtr を Non-key 領域の先頭に付与するバイト列を返す
 func encodeRecordNonKeyPrefix(lastModified lock.TrxId, rollPtr UndoPtr) []byte {
 	buf := make([]byte, lastModifiedSize+UndoPtrSize)
@@ -23,3 +27,50 @@ func decodeRecordNonKey(nonKeyBytes []byte) (lastModified lock.TrxId, rollPtr Un
 	nonKeyColumns = nonKeyBytes[lastModifiedSize+UndoPtrSize:]
 	return
 }
+
+// encodeNonKeyColumns は非キーカラムを NULL ビットマップ付きでエンコードし、dest に追記する
+//
+// レイアウト: [カラム数 (2B)] [NULL ビットマップ (ceil(カラム数 / 8) B)] [NULL 以外のカラム (memcomparable)]
+//   - カラム値が nil の場合は NULL として扱い、ビットマップの対応するビットを 1 にする
+func encodeNonKeyColumns(columns [][]byte, dest *[]byte) {
+	bitmap := make([]byte, nullBitmapCountSize+(len(columns)+7)/8)
+	binary.BigEndian.PutUint16(bitmap[0:nullBitmapCountSize], uint16(len(columns)))
+
+	values := make([][]byte, 0, len(columns))
+	for i, col := range columns {
+		if col == nil {
+			bitmap[nullBitmapCountSize+i/8] |= 1 << (i % 8)
+			continue
+		}
+		values = append(values, col)
+	}
+
+	*dest = append(*dest, bitmap...)
+	encode.Encode(values, dest)
+}
+
+// decodeNonKeyColumns は encodeNonKeyColumns でエンコードされた非キーカラムをデコードし、dest に追記する
+//
+// NULL のカラムは nil として追記する
+func decodeNonKeyColumns(src []byte, dest *[][]byte) {
+	if len(src) < nullBitmapCountSize {
+		return
+	}
+	count := int(binary.BigEndian.Uint16(src[0:nullBitmapCountSize]))
+	bitmap := src[nullBitmapCountSize : nullBitmapCountSize+(count+7)/8]
+	rest := src[nullBitmapCountSize+len(bitmap):]
+
+	for i := range count {
+		if bitmap[i/8]&(1<<(i%8)) != 0 {
+			*dest = append(*dest, nil)
+			continue
+		}
+		values, remaining := encode.DecodeFirstN(rest, 1)
+		value := []byte{}
+		if len(values) > 0 {
+			value = values[0]
+		}
+		*dest = append(*dest, value)
+		rest = remaining
+	}
+}
@@ -69,3 +69,52 @@ func TestDecodeRecordNonKey(t *testing.T) {
 		assert.Equal(t, 0, len(decodedNonKeyColumns))
 	})
 }
+
+func TestEncodeNonKeyColumns(t *testing.T) {
+	t.Run("NULL を含むカラムをエンコードしてデコードできる", func(t *testing.T) {
+		// GIVEN
+		columns := [][]byte{[]byte("Alice"), nil, []byte("Tokyo"), nil}
+
+		// WHEN
+		var encoded []byte
+		encodeNonKeyColumns(columns, &encoded)
+		var decoded [][]byte
+		decodeNonKeyColumns(encoded, &decoded)
+
+		// THEN
+		assert.Equal(t, 4, len(decoded))
+		assert.Equal(t, []byte("Alice"), decoded[0])
+		assert.Nil(t, decoded[1])
+		assert.Equal(t, []byte("Tokyo"), decoded[2])
+		assert.Nil(t, decoded[3])
+	})
+
+	t.Run("全カラムが NULL でもデコードできる", func(t *testing.T) {
+		// GIVEN
+		columns := make([][]byte, 10)
+
+		// WHEN
+		var encoded []byte
+		encodeNonKeyColumns(columns, &encoded)
+		var decoded [][]byte
+		decodeNonKeyColumns(encoded, &decoded)
+
+		// THEN
+		assert.Equal(t, 10, len(decoded))
+		for _, col := range decoded {
+			assert.Nil(t, col)
+		}
+	})
+
+	t.Run("非キーカラムがない場合はカラム数のみがエンコードされる", func(t *testing.T) {
+		// WHEN
+		var encoded []byte
+		encodeNonKeyColumns(nil, &encoded)
+		var decoded [][]byte
+		decodeNonKeyColumns(encoded, &decoded)
+
+		// THEN
+		assert.Equal(t, []byte{0x00, 0x00}, encoded)
+		assert.Equal(t, 0, len(decoded))
+	})
+}
@@ -0,0 +1,128 @@
+package access
+
+import (
+	"slices"
+	"sync"
+)
+
+// RowLogOp は行ログに記録する行の変更の種類
+type RowLogOp byte
+
+const (
+	RowLogInsert     RowLogOp = iota // 行の挿入 (ソフトデリート済みの行の上書きと、更新後の行を含む)
+	RowLogSoftDelete                 // 行のソフトデリート
+	RowLogDelete                     // 行の物理削除 (更新前の行を含む)
+)
+
+// RowLogRecord は行ログに記録された 1 件の行の変更
+type RowLogRecord struct {
+	Op        RowLogOp
+	EncodedPK []byte   // エンコード済みプライマリキー
+	Columns   [][]byte // 行の全カラム値
+}
+
+// RowLog はインデックスのオンライン作成中に、テーブルに対して行われた行の変更を記録する
+//
+// インデックスの作成中もテーブルへの書き込みは止めないため、作成中のインデックスに反映されない変更をここに溜めておき、
+// 作成後に IndexBuilder.ApplyRowLog でまとめて適用する
+type RowLog struct {
+	mutex   sync.Mutex
+	records []RowLogRecord
+}
+
+// Drain は記録された行の変更を記録順に取り出す (取り出した変更はログから削除される)
+func (l *RowLog) Drain() []RowLogRecord {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	records := l.records
+	l.records = nil
+	return records
+}
+
+func (l *RowLog) append(record RowLogRecord) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.records = append(l.records, record)
+}
+
+// RowLogRegistry はテーブルごとに、オンライン作成中のインデックスの行ログを管理する
+//
+// Table は自身のテーブル名で登録されている行ログに行の変更を記録する。
+// Table はトランザクションの undo レコードなどに保持され続けるため、作成開始前に構築された Table からの変更も記録できるように、
+// 行ログを Table ではなくテーブル名に紐づけて管理する。
+// また、インデックスをカタログに登録する間、テーブルの行の変更と Table の構築を止めるためのラッチもテーブル名ごとに管理する
+type RowLogRegistry struct {
+	mutex   sync.Mutex
+	logs    map[string][]*RowLog     // key: テーブル名
+	latches map[string]*sync.RWMutex // key: テーブル名
+}
+
+func NewRowLogRegistry() *RowLogRegistry {
+	return &RowLogRegistry{logs: make(map[string][]*RowLog), latches: make(map[string]*sync.RWMutex)}
+}
+
+// LockTable はテーブルの行の変更と Table の構築 (RLockTable で保護される処理) を止め、再開する関数を返す
+func (r *RowLogRegistry) LockTable(tableName string) (unlock func()) {
+	latch := r.latch(tableName)
+	latch.Lock()
+	return latch.Unlock
+}
+
+// RLockTable はテーブルの行の変更または Table の構築の間、LockTable で止められていれば再開まで待ち、終了を通知する関数を返す
+func (r *RowLogRegistry) RLockTable(tableName string) (unlock func()) {
+	latch := r.latch(tableName)
+	latch.RLock()
+	return latch.RUnlock
+}
+
+// latch はテーブルのラッチを返す (ない場合は作成する)
+func (r *RowLogRegistry) latch(tableName string) *sync.RWMutex {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	latch, ok := r.latches[tableName]
+	if !ok {
+		latch = &sync.RWMutex{}
+		r.latches[tableName] = latch
+	}
+	return latch
+}
+
+// Register はテーブルの行ログを新しく登録する (以降のテーブルへの行の変更が記録される)
+func (r *RowLogRegistry) Register(tableName string) *RowLog {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	rowLog := &RowLog{}
+	r.logs[tableName] = append(r.logs[tableName], rowLog)
+	return rowLog
+}
+
+// Unregister はテーブルの行ログの登録を解除する
+func (r *RowLogRegistry) Unregister(tableName string, rowLog *RowLog) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.logs[tableName] = slices.DeleteFunc(r.logs[tableName], func(l *RowLog) bool { return l == rowLog })
+	if len(r.logs[tableName]) == 0 {
+		delete(r.logs, tableName)
+	}
+}
+
+// record はテーブルに登録されているすべての行ログに行の変更を記録する
+func (r *RowLogRegistry) record(tableName string, op RowLogOp, encodedPK []byte, columns [][]byte) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	logs := r.logs[tableName]
+	if len(logs) == 0 {
+		return
+	}
+
+	// 呼び出し元がカラム値を再利用しても影響を受けないように複製する
+	copied := make([][]byte, len(columns))
+	for i, col := range columns {
+		if col != nil {
+			copied[i] = slices.Clone(col)
+		}
+	}
+	for _, rowLog := range logs {
+		rowLog.append(RowLogRecord{Op: op, EncodedPK: slices.Clone(encodedPK), Columns: copied})
+	}
+}
@@ -0,0 +1,80 @@
+package access
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRowLogRegistry(t *testing.T) {
+	t.Run("登録されている行ログに行の変更が記録される", func(t *testing.T) {
+		// GIVEN
+		registry := NewRowLogRegistry()
+		rowLog := registry.Register("users")
+
+		// WHEN
+		registry.record("users", RowLogInsert, []byte("pk1"), [][]byte{[]byte("a"), nil})
+		registry.record("users", RowLogDelete, []byte("pk2"), [][]byte{[]byte("b"), []byte("c")})
+
+		// THEN
+		assert.Equal(t, []RowLogRecord{
+			{Op: RowLogInsert, EncodedPK: []byte("pk1"), Columns: [][]byte{[]byte("a"), nil}},
+			{Op: RowLogDelete, EncodedPK: []byte("pk2"), Columns: [][]byte{[]byte("b"), []byte("c")}},
+		}, rowLog.Drain())
+		assert.Empty(t, rowLog.Drain())
+	})
+
+	t.Run("他のテーブルの行の変更は記録されない", func(t *testing.T) {
+		// GIVEN
+		registry := NewRowLogRegistry()
+		rowLog := registry.Register("users")
+
+		// WHEN
+		registry.record("orders", RowLogInsert, []byte("pk1"), [][]byte{[]byte("a")})
+
+		// THEN
+		assert.Empty(t, rowLog.Drain())
+	})
+
+	t.Run("同じテーブルに複数の行ログが登録されている場合はすべてに記録される", func(t *testing.T) {
+		// GIVEN
+		registry := NewRowLogRegistry()
+		rowLog1 := registry.Register("users")
+		rowLog2 := registry.Register("users")
+
+		// WHEN
+		registry.record("users", RowLogSoftDelete, []byte("pk1"), [][]byte{[]byte("a")})
+
+		// THEN
+		assert.Len(t, rowLog1.Drain(), 1)
+		assert.Len(t, rowLog2.Drain(), 1)
+	})
+
+	t.Run("登録を解除した行ログには記録されない", func(t *testing.T) {
+		// GIVEN
+		registry := NewRowLogRegistry()
+		rowLog := registry.Register("users")
+		registry.Unregister("users", rowLog)
+
+		// WHEN
+		registry.record("users", RowLogInsert, []byte("pk1"), [][]byte{[]byte("a")})
+
+		// THEN
+		assert.Empty(t, rowLog.Drain())
+		assert.Empty(t, registry.logs)
+	})
+
+	t.Run("記録したカラム値は呼び出し元の変更の影響を受けない", func(t *testing.T) {
+		// GIVEN
+		registry := NewRowLogRegistry()
+		rowLog := registry.Register("users")
+		column := []byte("a")
+
+		// WHEN
+		registry.record("users", RowLogInsert, []byte("pk1"), [][]byte{column})
+		column[0] = 'z'
+
+		// THEN
+		assert.Equal(t, "a", string(rowLog.Drain()[0].Columns[0]))
+	})
+}
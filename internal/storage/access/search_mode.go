@@ -8,6 +8,7 @@ import (
 // RecordSearchMode は検索方法を表すインターフェース
 type RecordSearchMode interface {
 	encode() btree.SearchMode
+	encodeNullable() btree.SearchMode // セカンダリインデックスの検索用 (キーの NULL を区別する)
 }
 
 // RecordSearchModeStart は先頭から検索する
@@ -17,6 +18,10 @@ func (RecordSearchModeStart) encode() btree.SearchMode {
 	return btree.SearchModeStart{}
 }
 
+func (RecordSearchModeStart) encodeNullable() btree.SearchMode {
+	return btree.SearchModeStart{}
+}
+
 // RecordSearchModeKey は指定したキーから検索する
 type RecordSearchModeKey struct {
 	Key [][]byte
@@ -27,3 +32,9 @@ func (k RecordSearchModeKey) encode() btree.SearchMode {
 	encode.Encode(k.Key, &key)
 	return btree.SearchModeKey{Key: key}
 }
+
+func (k RecordSearchModeKey) encodeNullable() btree.SearchMode {
+	var key []byte
+	encode.EncodeNullable(k.Key, &key)
+	return btree.SearchModeKey{Key: key}
+}
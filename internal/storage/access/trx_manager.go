@@ -1,6 +1,8 @@
 package access
 
 import (
+	"sync"
+
 	"github.com/ren-yamanashi/minesql/internal/storage/buffer"
 	"github.com/ren-yamanashi/minesql/internal/storage/lock"
 	"github.com/ren-yamanashi/minesql/internal/storage/log"
@@ -14,12 +16,14 @@ const (
 )
 
 type TrxManager struct {
+	mutex        sync.Mutex // Transactions・readViews・usedTables・nextTrxId への同時アクセスを防ぐための mutex
 	undoLog      *UndoManager
 	lockMgr      *lock.Manager
 	redoLog      *log.RedoLog
 	Transactions map[lock.TrxId]State
-	readViews    map[lock.TrxId]*ReadView // トランザクションごとの ReadView キャッシュ
-	nextTrxId    lock.TrxId               // 次に払い出すトランザクション ID (単調増加)
+	readViews    map[lock.TrxId]*ReadView           // トランザクションごとの ReadView キャッシュ
+	usedTables   map[lock.TrxId]map[string]struct{} // トランザクションごとに使用したテーブル名
+	nextTrxId    lock.TrxId                         // 次に払い出すトランザクション ID (単調増加)
 }
 
 func NewTrxManager(undoLog *UndoManager, lockMgr *lock.Manager, redoLog *log.RedoLog) *TrxManager {
@@ -29,12 +33,15 @@ func NewTrxManager(undoLog *UndoManager, lockMgr *lock.Manager, redoLog *log.Red
 		redoLog:      redoLog,
 		Transactions: make(map[lock.TrxId]State),
 		readViews:    make(map[lock.TrxId]*ReadView),
+		usedTables:   make(map[lock.TrxId]map[string]struct{}),
 		nextTrxId:    1,
 	}
 }
 
 // Begin は新しいトランザクションを開始し、トランザクション ID を返す
 func (m *TrxManager) Begin() lock.TrxId {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
 	trxId := m.allocateTrxId()
 	m.Transactions[trxId] = StateActive
 	return trxId
@@ -54,8 +61,7 @@ func (m *TrxManager) Commit(trxId lock.TrxId) error {
 	// UPDATE/DELETE の undo レコードは他トランザクションの ReadView から undo チェーン辿りに必要なため保持する
 	m.lockMgr.ReleaseAll(trxId)
 	m.undoLog.DiscardInsertRecords(trxId)
-	delete(m.readViews, trxId)
-	m.Transactions[trxId] = StateInactive
+	m.deactivate(trxId)
 	return nil
 }
 
@@ -75,15 +81,25 @@ func (m *TrxManager) Rollback(bp *buffer.BufferPool, trxId lock.TrxId) error {
 	// ロールバック後はロックを解放して Undo ログを破棄
 	m.lockMgr.ReleaseAll(trxId)
 	m.undoLog.Discard(trxId)
+	m.deactivate(trxId)
+	return nil
+}
+
+// deactivate はトランザクションを終了した状態にし、ReadView キャッシュと使用したテーブルの記録を破棄する
+func (m *TrxManager) deactivate(trxId lock.TrxId) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
 	delete(m.readViews, trxId)
+	delete(m.usedTables, trxId)
 	m.Transactions[trxId] = StateInactive
-	return nil
 }
 
 // CreateReadView は指定したトランザクション用の ReadView を返す
 //
 // REPEATABLE READ のため、同一トランザクション内では最初に作成した ReadView をキャッシュして使い回す
 func (m *TrxManager) CreateReadView(trxId lock.TrxId) *ReadView {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
 	if rv, ok := m.readViews[trxId]; ok {
 		return rv
 	}
@@ -103,6 +119,8 @@ func (m *TrxManager) CreateReadView(trxId lock.TrxId) *ReadView {
 // この値より小さい trxId のコミット済み undo ログおよび delete-marked レコードはパージ可能。
 // アクティブな ReadView がない場合は nextTrxId を返す (全コミット済みトランザクションがパージ可能)
 func (m *TrxManager) PurgeLimit() lock.TrxId {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
 	if len(m.readViews) == 0 {
 		return m.nextTrxId
 	}
@@ -117,6 +135,8 @@ func (m *TrxManager) PurgeLimit() lock.TrxId {
 
 // CommittedTrxIds はコミット済みトランザクションの ID 一覧を返す
 func (m *TrxManager) CommittedTrxIds() []lock.TrxId {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
 	var ids []lock.TrxId
 	for id, state := range m.Transactions {
 		if state == StateInactive {
@@ -126,11 +146,54 @@ func (m *TrxManager) CommittedTrxIds() []lock.TrxId {
 	return ids
 }
 
+// HasActiveTrxExcept は指定したトランザクション以外にアクティブなトランザクションが存在するかを返す
+func (m *TrxManager) HasActiveTrxExcept(trxId lock.TrxId) bool {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	for id, state := range m.Transactions {
+		if state == StateActive && id != trxId {
+			return true
+		}
+	}
+	return false
+}
+
+// UseTable はトランザクションがテーブルを使用したことを記録する
+//
+// 記録はトランザクションの終了まで残り、DDL が実行中のトランザクションの終了を待つかどうかの判定に使う
+func (m *TrxManager) UseTable(trxId lock.TrxId, tableName string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	tables, ok := m.usedTables[trxId]
+	if !ok {
+		tables = make(map[string]struct{})
+		m.usedTables[trxId] = tables
+	}
+	tables[tableName] = struct{}{}
+}
+
+// HasActiveTrxUsingTable は指定したトランザクション以外に、指定したテーブルを使用したアクティブなトランザクションが存在するかを返す
+func (m *TrxManager) HasActiveTrxUsingTable(trxId lock.TrxId, tableName string) bool {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	for id, tables := range m.usedTables {
+		if id == trxId || m.Transactions[id] != StateActive {
+			continue
+		}
+		if _, ok := tables[tableName]; ok {
+			return true
+		}
+	}
+	return false
+}
+
 // SetNextTrxId は nextTrxId を指定した値以上に設定する
 //
 // サーバー再起動時に、既存レコードの lastModified の最大値に基づいて
 // nextTrxId を復元するために使用する
 func (m *TrxManager) SetNextTrxId(minNextTrxId lock.TrxId) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
 	if minNextTrxId > m.nextTrxId {
 		m.nextTrxId = minNextTrxId
 	}
@@ -343,6 +343,99 @@ func TestCommittedTrxIds(t *testing.T) {
 	})
 }
 
+func TestHasActiveTrxExcept(t *testing.T) {
+	t.Run("指定したトランザクション以外にアクティブなトランザクションがある場合は true を返す", func(t *testing.T) {
+		// GIVEN
+		_, undoLog, _ := initManagerTest(t)
+		manager := NewTrxManager(undoLog, lock.NewManager(5000), nil)
+		trx1 := manager.Begin()
+		_ = manager.Begin() // trx2 (アクティブ)
+
+		// WHEN
+		result := manager.HasActiveTrxExcept(trx1)
+
+		// THEN
+		assert.True(t, result)
+	})
+
+	t.Run("指定したトランザクション以外がすべてコミット済みの場合は false を返す", func(t *testing.T) {
+		// GIVEN
+		_, undoLog, _ := initManagerTest(t)
+		manager := NewTrxManager(undoLog, lock.NewManager(5000), nil)
+		trx1 := manager.Begin()
+		trx2 := manager.Begin()
+		_ = manager.Commit(trx2)
+
+		// WHEN
+		result := manager.HasActiveTrxExcept(trx1)
+
+		// THEN
+		assert.False(t, result)
+	})
+}
+
+func TestHasActiveTrxUsingTable(t *testing.T) {
+	t.Run("指定したテーブルを使用したアクティブなトランザクションがある場合は true を返す", func(t *testing.T) {
+		// GIVEN
+		_, undoLog, _ := initManagerTest(t)
+		manager := NewTrxManager(undoLog, lock.NewManager(5000), nil)
+		trx1 := manager.Begin()
+		trx2 := manager.Begin()
+		manager.UseTable(trx2, "users")
+
+		// WHEN
+		result := manager.HasActiveTrxUsingTable(trx1, "users")
+
+		// THEN
+		assert.True(t, result)
+	})
+
+	t.Run("他のテーブルだけを使用したアクティブなトランザクションは対象にならない", func(t *testing.T) {
+		// GIVEN
+		_, undoLog, _ := initManagerTest(t)
+		manager := NewTrxManager(undoLog, lock.NewManager(5000), nil)
+		trx1 := manager.Begin()
+		trx2 := manager.Begin()
+		manager.UseTable(trx2, "orders")
+
+		// WHEN
+		result := manager.HasActiveTrxUsingTable(trx1, "users")
+
+		// THEN
+		assert.False(t, result)
+	})
+
+	t.Run("指定したトランザクション自身の使用は対象にならない", func(t *testing.T) {
+		// GIVEN
+		_, undoLog, _ := initManagerTest(t)
+		manager := NewTrxManager(undoLog, lock.NewManager(5000), nil)
+		trx1 := manager.Begin()
+		manager.UseTable(trx1, "users")
+
+		// WHEN
+		result := manager.HasActiveTrxUsingTable(trx1, "users")
+
+		// THEN
+		assert.False(t, result)
+	})
+
+	t.Run("テーブルを使用したトランザクションがコミット済みの場合は false を返す", func(t *testing.T) {
+		// GIVEN
+		_, undoLog, _ := initManagerTest(t)
+		manager := NewTrxManager(undoLog, lock.NewManager(5000), nil)
+		trx1 := manager.Begin()
+		trx2 := manager.Begin()
+		manager.UseTable(trx2, "users")
+		_ = manager.Commit(trx2)
+
+		// WHEN
+		result := manager.HasActiveTrxUsingTable(trx1, "users")
+
+		// THEN
+		assert.False(t, result)
+	})
+}
+
 func TestSetNextTrxId(t *testing.T) {
 	t.Run("指定値が現在の nextTrxId より大きい場合に更新される", func(t *testing.T) {
 		// GIVEN
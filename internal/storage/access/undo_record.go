@@ -6,6 +6,7 @@ import (
 
 	"github.com/ren-yamanashi/minesql/internal/storage/buffer"
 	"github.com/ren-yamanashi/minesql/internal/storage/lock"
+	"github.com/ren-yamanashi/minesql/internal/storage/page"
 )
 
 // UndoRecord は Undo ログの各レコードが実装するインターフェース
@@ -27,13 +28,17 @@ const undoRecordHeaderSize = 19
 
 var ErrInvalidUndoRecord = errors.New("invalid undo record")
 
+// NULL のカラムを表す colLen の値
+const nullColumnLen = 0xFFFF
+
 // UndoRecordFields は UNDO レコードのシリアライズ/デシリアライズに使用するフィールド群
 type UndoRecordFields struct {
 	TrxId            uint64
 	UndoNo           uint64
 	RecordType       UndoRecordType
-	PrevLastModified lock.TrxId // 上書き前の行の lastModified
-	PrevRollPtr      UndoPtr    // 上書き前の行の rollPtr
+	PrevLastModified lock.TrxId  // 上書き前の行の lastModified
+	PrevRollPtr      UndoPtr     // 上書き前の行の rollPtr
+	MetaPageId       page.PageId // 変更したテーブルの B+Tree のメタページの ID (リカバリ時に、テーブルの削除・作り直し後の B+Tree に適用しないため)
 	TableName        string
 	ColumnSets       [][][]byte // INSERT/DELETE は 1 セット、UPDATE_INPLACE は 2 セット (prevRecord, newRecord)
 }
@@ -41,7 +46,8 @@ type UndoRecordFields struct {
 // SerializeUndoRecord は UNDO レコードをバイト列にシリアライズする
 //
 // Data のフォーマット:
-//   - prevLastModified (8B) + prevRollPtr (4B) + tableNameLen (2B) + tableName + numColumns (2B) + [colLen (2B) + colData]...
+//   - prevLastModified (8B) + prevRollPtr (4B) + metaPageId (8B) + tableNameLen (2B) + tableName + numColumns (2B) + [colLen (2B) + colData]...
+//   - NULL のカラムは colLen を nullColumnLen とし、colData を持たない
 func SerializeUndoRecord(uFields UndoRecordFields) []byte {
 	// Data 部分をシリアライズ
 	var data []byte
@@ -51,6 +57,9 @@ func SerializeUndoRecord(uFields UndoRecordFields) []byte {
 	data = binary.BigEndian.AppendUint64(data, uFields.PrevLastModified)
 	data = append(data, uFields.PrevRollPtr.Encode()...)
 
+	// テーブルの B+Tree のメタページの ID
+	data = append(data, uFields.MetaPageId.ToBytes()...)
+
 	// テーブル名
 	tableNameBytes := []byte(uFields.TableName)
 	data = binary.BigEndian.AppendUint16(data, uint16(len(tableNameBytes)))
@@ -60,6 +69,10 @@ func SerializeUndoRecord(uFields UndoRecordFields) []byte {
 	for _, columns := range uFields.ColumnSets {
 		data = binary.BigEndian.AppendUint16(data, uint16(len(columns)))
 		for _, col := range columns {
+			if col == nil {
+				data = binary.BigEndian.AppendUint16(data, nullColumnLen)
+				continue
+			}
 			data = binary.BigEndian.AppendUint16(data, uint16(len(col)))
 			data = append(data, col...)
 		}
@@ -104,6 +117,13 @@ func DeserializeUndoRecord(buf []byte) (UndoRecordFields, error) {
 	uFields.PrevRollPtr, _ = DecodeUndoPtr(data[offset+8 : offset+prevFieldsSize])
 	offset += prevFieldsSize
 
+	// テーブルの B+Tree のメタページの ID
+	if offset+8 > len(data) {
+		return UndoRecordFields{}, ErrInvalidUndoRecord
+	}
+	uFields.MetaPageId = page.ReadPageIdFromPageData(data, offset)
+	offset += 8
+
 	// テーブル名
 	if offset+2 > len(data) {
 		return UndoRecordFields{}, ErrInvalidUndoRecord
@@ -145,6 +165,9 @@ func parseColumnSet(data []byte) ([][]byte, int, error) {
 		}
 		colLen := int(binary.BigEndian.Uint16(data[offset : offset+2]))
 		offset += 2
+		if colLen == nullColumnLen {
+			continue
+		}
 		if offset+colLen > len(data) {
 			return nil, 0, ErrInvalidUndoRecord
 		}
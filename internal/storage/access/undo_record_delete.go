@@ -34,6 +34,7 @@ func (r UndoDeleteRecord) Serialize(trxId uint64, undoNo uint64) []byte {
 		RecordType:       UndoDelete,
 		PrevLastModified: r.PrevLastModified,
 		PrevRollPtr:      r.PrevRollPtr,
+		MetaPageId:       r.table.MetaPageId,
 		TableName:        r.table.Name,
 		ColumnSets:       [][][]byte{r.Record},
 	})
@@ -33,7 +33,7 @@ func TestUndoDeleteRecord_Undo(t *testing.T) {
 
 	t.Run("SoftDelete した行のユニークインデックスも復元される", func(t *testing.T) {
 		// GIVEN
-		uniqueIndex := NewSecondaryIndex("idx_name", "name", page.PageId{}, 1, 1, true)
+		uniqueIndex := NewSecondaryIndex("idx_name", []string{"name"}, page.PageId{}, []uint16{1}, 1, true)
 		table, bp := setupTestTableForUndo(t, []*SecondaryIndex{uniqueIndex})
 
 		record := [][]byte{[]byte("a"), []byte("John")}
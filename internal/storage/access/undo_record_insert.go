@@ -34,6 +34,7 @@ func (r UndoInsertRecord) Serialize(trxId uint64, undoNo uint64) []byte {
 		RecordType:       UndoInsert,
 		PrevLastModified: r.PrevLastModified,
 		PrevRollPtr:      r.PrevRollPtr,
+		MetaPageId:       r.table.MetaPageId,
 		TableName:        r.table.Name,
 		ColumnSets:       [][][]byte{r.Record},
 	})
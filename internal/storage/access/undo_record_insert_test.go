@@ -34,7 +34,7 @@ func TestUndoInsertRecord_Undo(t *testing.T) {
 
 	t.Run("Insert した行のユニークインデックスも物理削除される", func(t *testing.T) {
 		// GIVEN
-		uniqueIndex := NewSecondaryIndex("idx_name", "name", page.PageId{}, 1, 1, true)
+		uniqueIndex := NewSecondaryIndex("idx_name", []string{"name"}, page.PageId{}, []uint16{1}, 1, true)
 		table, bp := setupTestTableForUndo(t, []*SecondaryIndex{uniqueIndex})
 
 		record := [][]byte{[]byte("a"), []byte("John")}
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/ren-yamanashi/minesql/internal/storage/lock"
+	"github.com/ren-yamanashi/minesql/internal/storage/page"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -88,6 +89,7 @@ func TestDeserializeUndoRecord(t *testing.T) {
 			RecordType:       UndoDelete,
 			PrevLastModified: 99,
 			PrevRollPtr:      prevRollPtr,
+			MetaPageId:       page.NewPageId(3, 1),
 			TableName:        "users",
 			ColumnSets:       [][][]byte{columns},
 		})
@@ -101,6 +103,7 @@ func TestDeserializeUndoRecord(t *testing.T) {
 		assert.Equal(t, UndoDelete, f.RecordType)
 		assert.Equal(t, lock.TrxId(99), f.PrevLastModified)
 		assert.Equal(t, prevRollPtr, f.PrevRollPtr)
+		assert.Equal(t, page.NewPageId(3, 1), f.MetaPageId)
 		assert.Equal(t, "users", f.TableName)
 		assert.Equal(t, 1, len(f.ColumnSets))
 		assert.Equal(t, []byte("b"), f.ColumnSets[0][0])
@@ -133,6 +136,27 @@ func TestDeserializeUndoRecord(t *testing.T) {
 		assert.Equal(t, []byte("Bob"), f.ColumnSets[1][1])
 	})
 
+	t.Run("NULL のカラムをデシリアライズすると nil になる", func(t *testing.T) {
+		// GIVEN
+		columns := [][]byte{[]byte("a"), nil, []byte("")}
+		buf := SerializeUndoRecord(UndoRecordFields{
+			TrxId:      1,
+			UndoNo:     0,
+			RecordType: UndoInsert,
+			TableName:  "users",
+			ColumnSets: [][][]byte{columns},
+		})
+
+		// WHEN
+		f, err := DeserializeUndoRecord(buf)
+
+		// THEN
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("a"), f.ColumnSets[0][0])
+		assert.Nil(t, f.ColumnSets[0][1])
+		assert.Equal(t, []byte{}, f.ColumnSets[0][2])
+	})
+
 	t.Run("データが不足している場合はエラーを返す", func(t *testing.T) {
 		// GIVEN
 		buf := make([]byte, undoRecordHeaderSize-1)
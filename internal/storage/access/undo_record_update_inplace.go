@@ -39,6 +39,7 @@ func (r UndoUpdateInplaceRecord) Serialize(trxId uint64, undoNo uint64) []byte {
 		RecordType:       UndoUpdateInplace,
 		PrevLastModified: r.PrevLastModified,
 		PrevRollPtr:      r.PrevRollPtr,
+		MetaPageId:       r.table.MetaPageId,
 		TableName:        r.table.Name,
 		ColumnSets:       [][][]byte{r.PrevRecord, r.NewRecord},
 	})
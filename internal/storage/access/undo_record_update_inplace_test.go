@@ -34,7 +34,7 @@ func TestUndoUpdateInplaceRecord_Undo(t *testing.T) {
 
 	t.Run("ユニークインデックスも元の値に戻る", func(t *testing.T) {
 		// GIVEN
-		uniqueIndex := NewSecondaryIndex("idx_name", "name", page.PageId{}, 1, 1, true)
+		uniqueIndex := NewSecondaryIndex("idx_name", []string{"name"}, page.PageId{}, []uint16{1}, 1, true)
 		table, bp := setupTestTableForUndo(t, []*SecondaryIndex{uniqueIndex})
 
 		prevRecord := [][]byte{[]byte("a"), []byte("John")}
@@ -16,7 +16,7 @@ import (
 func TestUndoIntegration(t *testing.T) {
 	t.Run("複数操作を逆順に Undo すると元の状態に戻る", func(t *testing.T) {
 		// GIVEN: ユニークインデックス付きテーブルにデータを投入
-		uniqueIndex := NewSecondaryIndex("idx_name", "name", page.PageId{}, 1, 1, true)
+		uniqueIndex := NewSecondaryIndex("idx_name", []string{"name"}, page.PageId{}, []uint16{1}, 1, true)
 		table, bp := setupTestTableForUndo(t, []*SecondaryIndex{uniqueIndex})
 
 		// 初期データ: ("a", "Alice"), ("b", "Bob")
@@ -173,8 +173,8 @@ func collectUndoActiveSecondaryIndexKeys(t *testing.T, ui *SecondaryIndex, bp *b
 			break
 		}
 		if record.HeaderBytes()[0] != 1 {
-			var keyColumns [][]byte
-			encode.Decode(record.KeyBytes(), &keyColumns)
+			keyColumns, encodedPK := encode.DecodeNullableFirstN(record.KeyBytes(), 1)
+			encode.Decode(encodedPK, &keyColumns)
 			keys = append(keys, string(keyColumns[0]))
 		}
 		_, _, err = indexIter.Next(bp)
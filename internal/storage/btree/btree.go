@@ -164,34 +164,72 @@ func (bt *BTree) FindByKey(bp *buffer.BufferPool, key []byte) (node.Record, page
 	return record, pos, nil
 }
 
+// LastRecord は B+Tree の最大のキーを持つレコードを返す
+//
+// 全件を走査せず、ルートから右端の子を辿って右端のリーフノードの末尾のレコードを返す。
+// B+Tree が空の場合は ok が false になる
+func (bt *BTree) LastRecord(bp *buffer.BufferPool) (record node.Record, ok bool, err error) {
+	pos, err := bt.findEdgeLeafPosition(bp, true)
+	if err != nil {
+		return nil, false, err
+	}
+	if pos.numRecords == 0 {
+		return nil, false, nil
+	}
+	data, err := bp.GetReadPageData(pos.leafPageId)
+	if err != nil {
+		return nil, false, err
+	}
+	leaf := node.NewLeaf(page.NewPage(data).Body)
+	return leaf.RecordAt(pos.slotNum), true, nil
+}
+
 // LeafPageIds はブランチページのみを辿り、全リーフページの PageId を収集する
 //
 // リーフページ自体は読まないため、バッファプールのキャッシュ状態に影響しない
 // (page_read_cost の in_mem 算出で使用する)
 func (bt *BTree) LeafPageIds(bp *buffer.BufferPool) ([]page.PageId, error) {
+	var leafPageIds []page.PageId
+	err := bt.walkLevels(bp, func(level []page.PageId, isLeaf bool) {
+		if isLeaf {
+			leafPageIds = level
+		}
+	})
+	return leafPageIds, err
+}
+
+// PageIds はメタページと全ノード (ブランチ・リーフ) のページの PageId を収集する
+//
+// 作成に失敗したインデックスの B+Tree のページを解放するために使用する
+func (bt *BTree) PageIds(bp *buffer.BufferPool) ([]page.PageId, error) {
+	pageIds := []page.PageId{bt.MetaPageId}
+	err := bt.walkLevels(bp, func(level []page.PageId, _ bool) {
+		pageIds = append(pageIds, level...)
+	})
+	return pageIds, err
+}
+
+// walkLevels はルートから幅優先でブランチレベルを 1 つずつ降り、各レベルのノードの PageId を fn に渡す
+//
+// 最後に渡すレベル (isLeaf が true) はリーフページで、リーフページ自体は読まない
+func (bt *BTree) walkLevels(bp *buffer.BufferPool, fn func(level []page.PageId, isLeaf bool)) error {
 	defer bp.UnRefPage(bt.MetaPageId)
 	metaData, err := bp.GetReadPageData(bt.MetaPageId)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	meta := newMetaPage(page.NewPage(metaData))
-	rootPageId := meta.rootPageId()
 	height := meta.height()
 
-	// 高さ 1: ルートがリーフ
-	if height <= 1 {
-		return []page.PageId{rootPageId}, nil
-	}
-
-	// 高さ 2 以上: ブランチを辿ってリーフの PageId を収集
-	// 幅優先でブランチレベルを 1 つずつ降りていく
-	currentLevel := []page.PageId{rootPageId}
+	// 高さ 1 の場合はルートがリーフ
+	currentLevel := []page.PageId{meta.rootPageId()}
 	for level := uint64(1); level < height; level++ {
+		fn(currentLevel, false)
 		var nextLevel []page.PageId
 		for _, nodePageId := range currentLevel {
 			data, err := bp.GetReadPageData(nodePageId)
 			if err != nil {
-				return nil, err
+				return err
 			}
 			bp.UnRefPage(nodePageId)
 			branch := node.NewBranch(page.NewPage(data).Body)
@@ -201,8 +239,8 @@ func (bt *BTree) LeafPageIds(bp *buffer.BufferPool) ([]page.PageId, error) {
 		}
 		currentLevel = nextLevel
 	}
-
-	return currentLevel, nil
+	fn(currentLevel, true)
+	return nil
 }
 
 // LeafPageCount はメタページからリーフページ数を取得する
@@ -0,0 +1,221 @@
+package btree
+
+import (
+	"bytes"
+	"errors"
+
+	"github.com/ren-yamanashi/minesql/internal/storage/btree/node"
+	"github.com/ren-yamanashi/minesql/internal/storage/buffer"
+	"github.com/ren-yamanashi/minesql/internal/storage/page"
+)
+
+// childNode はボトムアップ構築中の 1 つ下のレベルのノードを表す
+type childNode struct {
+	pageId   page.PageId // ノードのページ ID
+	firstKey []byte      // ノード (を根とする部分木) の最小キー
+}
+
+// BulkLoad はキーの昇順に並んだレコードから、新しい B+Tree をボトムアップで構築する
+//
+// レコードを 1 件ずつ Insert する場合と異なり、ノード分割を行わずにリーフノードを先頭から順に埋めていき、
+// 埋まったリーフノードの先頭キーを境界キーとしてブランチノードを 1 レベルずつ上に向かって作成する。
+// records はキーの昇順に並んでおり、キーが重複していない必要がある
+func BulkLoad(bp *buffer.BufferPool, metaPageId page.PageId, records []node.Record) (*BTree, error) {
+	if len(records) == 0 {
+		return CreateBTree(bp, metaPageId)
+	}
+	for i := 1; i < len(records); i++ {
+		if bytes.Compare(records[i-1].KeyBytes(), records[i].KeyBytes()) >= 0 {
+			return nil, errors.New("bulk load records must be sorted by key without duplicates")
+		}
+	}
+
+	// メタページを初期化
+	if err := bp.AddPage(metaPageId); err != nil {
+		return nil, err
+	}
+
+	// 構築に失敗した場合は、作成途中のノードのページを解放する
+	alloc := &nodeAllocator{bp: bp, fileId: metaPageId.FileId}
+	rootPageId, leafPageCount, height, err := bulkLoadNodes(bp, alloc, records)
+	if err != nil {
+		_ = bp.FreePages(alloc.pageIds)
+		return nil, err
+	}
+
+	// メタページにルートページID・リーフページ数・高さを設定
+	metaData, err := bp.GetWritePageData(metaPageId)
+	if err != nil {
+		return nil, err
+	}
+	meta := createMetaPage(page.NewPage(metaData))
+	meta.setRootPageId(rootPageId)
+	meta.setLeafPageCount(leafPageCount)
+	meta.setHeight(height)
+
+	return NewBTree(metaPageId), nil
+}
+
+// nodeAllocator はボトムアップ構築でノード用のページ ID を採番し、採番したページ ID を記録する
+type nodeAllocator struct {
+	bp      *buffer.BufferPool
+	fileId  page.FileId
+	pageIds []page.PageId // 採番したページ ID (構築に失敗した場合に解放する)
+}
+
+// allocate はノード用のページ ID を採番する
+func (a *nodeAllocator) allocate() (page.PageId, error) {
+	pageId, err := a.bp.AllocatePageId(a.fileId)
+	if err != nil {
+		return page.InvalidPageId, err
+	}
+	a.pageIds = append(a.pageIds, pageId)
+	return pageId, nil
+}
+
+// bulkLoadNodes はリーフノードを作成し、ノードが 1 つになるまでブランチノードのレベルを積み上げる
+//
+// ルートノードのページ ID・リーフノードの数・B+Tree の高さを返す
+func bulkLoadNodes(bp *buffer.BufferPool, alloc *nodeAllocator, records []node.Record) (rootPageId page.PageId, leafPageCount uint64, height uint64, err error) {
+	level, err := bulkLoadLeaves(bp, alloc, records)
+	if err != nil {
+		return page.InvalidPageId, 0, 0, err
+	}
+	leafPageCount = uint64(len(level))
+
+	height = 1
+	for len(level) > 1 {
+		level, err = bulkLoadBranches(bp, alloc, level)
+		if err != nil {
+			return page.InvalidPageId, 0, 0, err
+		}
+		height++
+	}
+	return level[0].pageId, leafPageCount, height, nil
+}
+
+// bulkLoadLeaves はレコードをリーフノードに先頭から詰めていき、作成したリーフノードの一覧を返す
+//
+// リーフノードが満杯になったら次のリーフノードを作成し、前後のリーフノードのポインタを繋ぐ
+func bulkLoadLeaves(bp *buffer.BufferPool, alloc *nodeAllocator, records []node.Record) ([]childNode, error) {
+	var leaves []childNode
+
+	leafPageId, leaf, err := allocateLeaf(bp, alloc, nil)
+	if err != nil {
+		return nil, err
+	}
+	leaves = append(leaves, childNode{pageId: leafPageId, firstKey: records[0].KeyBytes()})
+
+	for _, record := range records {
+		if leaf.Insert(leaf.NumRecords(), record) {
+			continue
+		}
+		if leaf.NumRecords() == 0 {
+			return nil, errors.New("record is too large for a leaf node")
+		}
+
+		// 満杯になったリーフノードの次のリーフノードを作成する
+		// (古いリーフノードのページデータは新しいページの追加で追い出される可能性があるため、先に次のページ ID を書き込む)
+		nextPageId, err := alloc.allocate()
+		if err != nil {
+			return nil, err
+		}
+		leaf.SetNextPageId(&nextPageId)
+		bp.UnRefPage(leafPageId)
+
+		prevPageId := leafPageId
+		leafPageId, leaf, err = allocateLeaf(bp, alloc, &nextPageId)
+		if err != nil {
+			return nil, err
+		}
+		leaf.SetPrevPageId(&prevPageId)
+		leaves = append(leaves, childNode{pageId: leafPageId, firstKey: record.KeyBytes()})
+
+		if !leaf.Insert(0, record) {
+			return nil, errors.New("record is too large for a leaf node")
+		}
+	}
+	bp.UnRefPage(leafPageId)
+
+	return leaves, nil
+}
+
+// allocateLeaf は空のリーフノードを作成する
+//   - pageId: 作成するページの ID (nil の場合は新しく採番する)
+func allocateLeaf(bp *buffer.BufferPool, alloc *nodeAllocator, pageId *page.PageId) (page.PageId, *node.Leaf, error) {
+	data, leafPageId, err := allocateNodePage(bp, alloc, pageId)
+	if err != nil {
+		return page.InvalidPageId, nil, err
+	}
+	leaf := node.NewLeaf(data)
+	leaf.Initialize()
+	return leafPageId, leaf, nil
+}
+
+// bulkLoadBranches は子ノードの一覧を先頭から順にブランチノードに詰めていき、作成したブランチノードの一覧を返す
+//
+// ブランチノードは「境界キー (右隣の子ノードの最小キー) → 左の子ページ ID」のレコードと右端の子ページ ID で構成されるため、
+// 子ノードを追加するたびに、それまでの右端の子をレコードに移して新しい子を右端の子にする
+func bulkLoadBranches(bp *buffer.BufferPool, alloc *nodeAllocator, children []childNode) ([]childNode, error) {
+	var branches []childNode
+
+	for i := 0; i < len(children); {
+		data, branchPageId, err := allocateNodePage(bp, alloc, nil)
+		if err != nil {
+			return nil, err
+		}
+		branch := node.NewBranch(data)
+
+		// ブランチノードは少なくとも 2 つの子を持つ
+		if err := branch.Initialize(children[i+1].firstKey, children[i].pageId, children[i+1].pageId); err != nil {
+			return nil, err
+		}
+		branches = append(branches, childNode{pageId: branchPageId, firstKey: children[i].firstKey})
+		i += 2
+
+		for i < len(children) {
+			rightChildPageId := branch.RightChildPageId()
+			record := node.NewRecord(nil, children[i].firstKey, rightChildPageId.ToBytes())
+			if !branch.Insert(branch.NumRecords(), record) {
+				break
+			}
+			branch.SetRightChildPageId(children[i].pageId)
+			i++
+		}
+
+		// 残りの子が 1 つだけの場合、次のブランチノードが 2 つの子を持てるように、右端の子を次のブランチノードに回す
+		if len(children)-i == 1 {
+			lastSlotNum := branch.NumRecords() - 1
+			leftChildPageId := branch.ChildPageIdAt(lastSlotNum)
+			branch.Delete(lastSlotNum)
+			branch.SetRightChildPageId(leftChildPageId)
+			i--
+		}
+		bp.UnRefPage(branchPageId)
+	}
+
+	return branches, nil
+}
+
+// allocateNodePage はノード用のページをバッファプールに追加し、書き込み用のページデータ (ボディ部分) を返す
+//   - pageId: 追加するページの ID (nil の場合は新しく採番する)
+func allocateNodePage(bp *buffer.BufferPool, alloc *nodeAllocator, pageId *page.PageId) ([]byte, page.PageId, error) {
+	var nodePageId page.PageId
+	if pageId != nil {
+		nodePageId = *pageId
+	} else {
+		allocated, err := alloc.allocate()
+		if err != nil {
+			return nil, page.InvalidPageId, err
+		}
+		nodePageId = allocated
+	}
+	if err := bp.AddPage(nodePageId); err != nil {
+		return nil, page.InvalidPageId, err
+	}
+	data, err := bp.GetWritePageData(nodePageId)
+	if err != nil {
+		return nil, page.InvalidPageId, err
+	}
+	return page.NewPage(data).Body, nodePageId, nil
+}
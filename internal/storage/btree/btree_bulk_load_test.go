@@ -0,0 +1,171 @@
+package btree
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/ren-yamanashi/minesql/internal/storage/btree/node"
+	"github.com/ren-yamanashi/minesql/internal/storage/buffer"
+	"github.com/ren-yamanashi/minesql/internal/storage/page"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBulkLoad(t *testing.T) {
+	t.Run("レコードがない場合は空のリーフノードだけの B+Tree を作成する", func(t *testing.T) {
+		// GIVEN
+		bp, metaPageId := setupBulkLoad(t)
+
+		// WHEN
+		tree, err := BulkLoad(bp, metaPageId, nil)
+
+		// THEN
+		require.NoError(t, err)
+		assert.Equal(t, 0, len(tree.collectAllRecords(bp)))
+		height, err := tree.Height(bp)
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(1), height)
+	})
+
+	t.Run("1 つのリーフノードに収まる場合はルートがリーフノードになる", func(t *testing.T) {
+		// GIVEN
+		bp, metaPageId := setupBulkLoad(t)
+		records := bulkLoadRecords(5, 1)
+
+		// WHEN
+		tree, err := BulkLoad(bp, metaPageId, records)
+
+		// THEN
+		require.NoError(t, err)
+		var w strings.Builder
+		writeRootInfo(&w, bp, tree)
+		assert.Equal(t, "Leaf[keys=5]: [key_0000, key_0001, key_0002, key_0003, key_0004]\n", w.String())
+	})
+
+	t.Run("リーフノードを先頭から埋めてブランチノードを積み上げる", func(t *testing.T) {
+		// GIVEN
+		bp, metaPageId := setupBulkLoad(t)
+		records := bulkLoadRecords(1000, 200)
+
+		// WHEN
+		tree, err := BulkLoad(bp, metaPageId, records)
+
+		// THEN
+		require.NoError(t, err)
+		all := tree.collectAllRecords(bp)
+		assert.Equal(t, len(records), len(all))
+		for i, record := range all {
+			assert.Equal(t, string(records[i].KeyBytes()), string(record.KeyBytes()))
+		}
+
+		// 1 件ずつ挿入する場合と異なり、最後以外のリーフノードは満杯になる (18 件で 1 ページ)
+		leafPageCount, err := tree.LeafPageCount(bp)
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(56), leafPageCount)
+		height, err := tree.Height(bp)
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(2), height)
+	})
+
+	t.Run("ブランチノードが複数必要な場合は高さが 3 以上になり、すべてのキーを検索できる", func(t *testing.T) {
+		// GIVEN
+		bp, metaPageId := setupBulkLoad(t)
+		keyFn := func(i int) string {
+			return fmt.Sprintf("%s_%04d", strings.Repeat("k", 100), i)
+		}
+		records := make([]node.Record, 0, 500)
+		for i := range 500 {
+			records = append(records, node.NewRecord(nil, []byte(keyFn(i)), []byte(strings.Repeat("x", 200))))
+		}
+
+		// WHEN
+		tree, err := BulkLoad(bp, metaPageId, records)
+
+		// THEN
+		require.NoError(t, err)
+		height, err := tree.Height(bp)
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(3), height)
+		for i := range 500 {
+			record, _, err := tree.FindByKey(bp, []byte(keyFn(i)))
+			require.NoError(t, err)
+			assert.Equal(t, keyFn(i), string(record.KeyBytes()))
+		}
+	})
+
+	t.Run("構築後に挿入・削除ができる", func(t *testing.T) {
+		// GIVEN
+		bp, metaPageId := setupBulkLoad(t)
+		tree, err := BulkLoad(bp, metaPageId, bulkLoadRecords(100, 200))
+		require.NoError(t, err)
+
+		// WHEN
+		tree.mustInsert(bp, "key_0050_a", strings.Repeat("y", 200))
+		for i := range 50 {
+			require.NoError(t, tree.Delete(bp, fmt.Appendf(nil, "key_%04d", i)))
+		}
+
+		// THEN
+		all := tree.collectAllRecords(bp)
+		assert.Equal(t, 51, len(all))
+		assert.Equal(t, "key_0050", string(all[0].KeyBytes()))
+		assert.Equal(t, "key_0050_a", string(all[1].KeyBytes()))
+	})
+
+	t.Run("キーの昇順に並んでいない場合はエラーを返す", func(t *testing.T) {
+		// GIVEN
+		bp, metaPageId := setupBulkLoad(t)
+		records := []node.Record{
+			node.NewRecord(nil, []byte("b"), nil),
+			node.NewRecord(nil, []byte("a"), nil),
+		}
+
+		// WHEN
+		tree, err := BulkLoad(bp, metaPageId, records)
+
+		// THEN
+		assert.Nil(t, tree)
+		assert.ErrorContains(t, err, "bulk load records must be sorted by key without duplicates")
+	})
+
+	t.Run("構築の途中で失敗した場合は、作成したノードのページを解放する", func(t *testing.T) {
+		// GIVEN: 複数のリーフノードを作成した後に、リーフノードに収まらないレコードがある
+		bp, metaPageId := setupBulkLoad(t)
+		records := append(bulkLoadRecords(100, 200), node.NewRecord(nil, []byte("key_9999"), []byte(strings.Repeat("z", page.PageSize))))
+
+		// WHEN
+		tree, err := BulkLoad(bp, metaPageId, records)
+
+		// THEN
+		assert.Nil(t, tree)
+		assert.ErrorContains(t, err, "record is too large for a leaf node")
+
+		// 解放したページ ID が後に採番したものから順に再利用される (新しく採番する場合は昇順になる)
+		first, err := bp.AllocatePageId(metaPageId.FileId)
+		require.NoError(t, err)
+		second, err := bp.AllocatePageId(metaPageId.FileId)
+		require.NoError(t, err)
+		assert.Greater(t, first.PageNumber, second.PageNumber)
+		assert.Greater(t, second.PageNumber, metaPageId.PageNumber)
+		assert.False(t, bp.IsPageCached(first))
+	})
+}
+
+// BulkLoad 用のバッファプールと、B+Tree のメタページ ID を用意する
+func setupBulkLoad(t *testing.T) (*buffer.BufferPool, page.PageId) {
+	t.Helper()
+	tree, bp := setupBTree(t)
+	metaPageId, err := bp.AllocatePageId(tree.MetaPageId.FileId)
+	require.NoError(t, err)
+	return bp, metaPageId
+}
+
+// key_0000 から昇順に並んだ n 件のレコードを生成する
+func bulkLoadRecords(n int, valueSize int) []node.Record {
+	records := make([]node.Record, 0, n)
+	for i := range n {
+		records = append(records, node.NewRecord(nil, fmt.Appendf(nil, "key_%04d", i), []byte(strings.Repeat("x", valueSize))))
+	}
+	return records
+}
@@ -329,6 +329,60 @@ func TestFindByKey(t *testing.T) {
 	})
 }
 
+func TestLastRecord(t *testing.T) {
+	t.Run("空の B+Tree では ok が false になる", func(t *testing.T) {
+		// GIVEN
+		bt, bp := setupBTree(t)
+
+		// WHEN
+		_, ok, err := bt.LastRecord(bp)
+
+		// THEN
+		assert.NoError(t, err)
+		assert.False(t, ok)
+	})
+
+	t.Run("最大のキーを持つレコードを取得できる", func(t *testing.T) {
+		// GIVEN
+		bt, bp := setupBTree(t)
+		bt.mustInsert(bp, "bbb", "v2")
+		bt.mustInsert(bp, "ccc", "v3")
+		bt.mustInsert(bp, "aaa", "v1")
+
+		// WHEN
+		record, ok, err := bt.LastRecord(bp)
+
+		// THEN
+		assert.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, "ccc", string(record.KeyBytes()))
+		assert.Equal(t, "v3", string(record.NonKeyBytes()))
+	})
+
+	t.Run("高さ 2 以上の B+Tree でも、削除後の最大のキーを持つレコードを取得できる", func(t *testing.T) {
+		// GIVEN
+		bt, bp := setupBTree(t)
+		numRecords := 500
+		for i := range numRecords {
+			bt.mustInsert(bp, fmt.Sprintf("key%04d", i), fmt.Sprintf("val%04d", i))
+		}
+		for i := 400; i < numRecords; i++ {
+			assert.NoError(t, bt.Delete(bp, fmt.Appendf(nil, "key%04d", i)))
+		}
+		h, err := bt.Height(bp)
+		assert.NoError(t, err)
+		assert.Greater(t, h, uint64(1))
+
+		// WHEN
+		record, ok, err := bt.LastRecord(bp)
+
+		// THEN
+		assert.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, "key0399", string(record.KeyBytes()))
+	})
+}
+
 func TestNewBTree(t *testing.T) {
 	t.Run("既存の B+Tree を NewBTree で開いてデータを読み取れる", func(t *testing.T) {
 		// GIVEN: CreateBTree でツリーを作成しレコードを挿入する
@@ -512,6 +566,46 @@ func TestHeight(t *testing.T) {
 	})
 }
 
+func TestPageIds(t *testing.T) {
+	t.Run("高さ 1 のツリーではメタページとルートの PageId が返される", func(t *testing.T) {
+		// GIVEN
+		bt, bp := setupBTree(t)
+		bt.mustInsert(bp, "aaa", "v1")
+
+		// WHEN
+		pageIds, err := bt.PageIds(bp)
+
+		// THEN
+		assert.NoError(t, err)
+		assert.Len(t, pageIds, 2)
+		assert.Equal(t, bt.MetaPageId, pageIds[0])
+	})
+
+	t.Run("高さ 2 以上のツリーではメタページ・ブランチ・リーフの PageId が重複なく返される", func(t *testing.T) {
+		// GIVEN
+		bt, bp := setupBTree(t)
+		for i := range 500 {
+			bt.mustInsert(bp, fmt.Sprintf("key%04d", i), fmt.Sprintf("val%04d", i))
+		}
+		leafPageIds, err := bt.LeafPageIds(bp)
+		assert.NoError(t, err)
+
+		// WHEN
+		pageIds, err := bt.PageIds(bp)
+
+		// THEN: メタページとリーフに加えて、少なくとも 1 つのブランチ (ルート) を含む
+		assert.NoError(t, err)
+		assert.Equal(t, bt.MetaPageId, pageIds[0])
+		assert.Subset(t, pageIds, leafPageIds)
+		assert.Greater(t, len(pageIds), len(leafPageIds)+1)
+		seen := make(map[page.PageId]bool)
+		for _, pageId := range pageIds {
+			assert.False(t, seen[pageId])
+			seen[pageId] = true
+		}
+	})
+}
+
 // テスト用の B+Tree とバッファプールマネージャをセットアップする
 func setupBTree(t *testing.T) (*BTree, *buffer.BufferPool) {
 	t.Helper()
@@ -146,6 +146,84 @@ func (bp *BufferPool) GetDisk(fileId page.FileId) (*file.Disk, error) {
 	return bp.getDisk(fileId)
 }
 
+// UnregisterDisk は指定された FileId に対応する Disk の登録を解除し、解除した Disk を返す
+//
+// 対象ファイルのページは事前に DiscardPages でバッファプールから破棄しておく必要がある
+func (bp *BufferPool) UnregisterDisk(fileId page.FileId) (*file.Disk, error) {
+	bp.mutex.Lock()
+	defer bp.mutex.Unlock()
+	disk, err := bp.getDisk(fileId)
+	if err != nil {
+		return nil, err
+	}
+	delete(bp.disks, fileId)
+	return disk, nil
+}
+
+// DiscardPages は指定された FileId のページをディスクに書き出さずにバッファプールから破棄する
+//
+// ダーティーページもフラッシュリストから除外して破棄するため、テーブルの削除・切り詰めでのみ使用する。
+// 破棄したバッファページは優先的に再利用される
+func (bp *BufferPool) DiscardPages(fileId page.FileId) {
+	bp.mutex.Lock()
+	defer bp.mutex.Unlock()
+
+	for pageId, bufferId := range bp.pageTable {
+		if pageId.FileId != fileId {
+			continue
+		}
+		delete(bp.pageTable, pageId)
+		bp.flushList.Remove(pageId)
+		bp.bufferPages[bufferId].PageId = page.InvalidPageId
+		bp.bufferPages[bufferId].IsDirty = false
+		bp.evictionAlgorithm.Remove(bufferId)
+	}
+
+	// REDO ログに記録する必要もないため newlyDirtied からも除外する
+	newlyDirtied := bp.newlyDirtied[:0]
+	for _, pageId := range bp.newlyDirtied {
+		if pageId.FileId != fileId {
+			newlyDirtied = append(newlyDirtied, pageId)
+		}
+	}
+	bp.newlyDirtied = newlyDirtied
+}
+
+// FreePages は指定されたページをディスクに書き出さずにバッファプールから破棄し、ページ ID を解放する
+//
+// 解放したページ ID は以降の AllocatePageId で再利用される。
+// どこからも参照されなくなったページ (作成に失敗したインデックスの B+Tree など) でのみ使用する
+func (bp *BufferPool) FreePages(pageIds []page.PageId) error {
+	bp.mutex.Lock()
+	defer bp.mutex.Unlock()
+
+	freed := make(map[page.PageId]bool, len(pageIds))
+	for _, pageId := range pageIds {
+		disk, err := bp.getDisk(pageId.FileId)
+		if err != nil {
+			return err
+		}
+		if bufferId, ok := bp.pageTable[pageId]; ok {
+			delete(bp.pageTable, pageId)
+			bp.flushList.Remove(pageId)
+			bp.bufferPages[bufferId].PageId = page.InvalidPageId
+			bp.bufferPages[bufferId].IsDirty = false
+			bp.evictionAlgorithm.Remove(bufferId)
+		}
+		disk.FreePage(pageId)
+		freed[pageId] = true
+	}
+
+	newlyDirtied := bp.newlyDirtied[:0]
+	for _, pageId := range bp.newlyDirtied {
+		if !freed[pageId] {
+			newlyDirtied = append(newlyDirtied, pageId)
+		}
+	}
+	bp.newlyDirtied = newlyDirtied
+	return nil
+}
+
 // AllocatePageId は指定された FileId に対して新しい PageId を割り当てる
 func (bp *BufferPool) AllocatePageId(fileId page.FileId) (page.PageId, error) {
 	bp.mutex.Lock()
@@ -2,6 +2,7 @@ package buffer
 
 import (
 	"encoding/binary"
+	"fmt"
 	"path/filepath"
 	"testing"
 
@@ -96,6 +97,127 @@ func TestGetDisk(t *testing.T) {
 	})
 }
 
+func TestUnregisterDisk(t *testing.T) {
+	t.Run("登録を解除した Disk が返され、以降は取得できない", func(t *testing.T) {
+		// GIVEN
+		tmpdir := t.TempDir()
+		fileId := page.FileId(1)
+		disk, err := file.NewDisk(fileId, filepath.Join(tmpdir, "test.db"))
+		assert.NoError(t, err)
+		bp := NewBufferPool(5, nil)
+		bp.RegisterDisk(fileId, disk)
+
+		// WHEN
+		unregistered, err := bp.UnregisterDisk(fileId)
+
+		// THEN
+		assert.NoError(t, err)
+		assert.Equal(t, disk, unregistered)
+		_, err = bp.GetDisk(fileId)
+		assert.Error(t, err)
+	})
+
+	t.Run("登録されていない FileId を指定するとエラーが発生する", func(t *testing.T) {
+		// GIVEN
+		bp := NewBufferPool(5, nil)
+
+		// WHEN
+		_, err := bp.UnregisterDisk(page.FileId(999))
+
+		// THEN
+		assert.ErrorContains(t, err, "disk for FileId 999 not found")
+	})
+}
+
+func TestDiscardPages(t *testing.T) {
+	t.Run("指定した FileId のページだけをディスクに書き出さずに破棄する", func(t *testing.T) {
+		// GIVEN: FileId 1 と 2 のダーティーページがバッファプールにある
+		tmpdir := t.TempDir()
+		bp := NewBufferPool(5, nil)
+		var pageIds []page.PageId
+		for _, fileId := range []page.FileId{1, 2} {
+			disk, err := file.NewDisk(fileId, filepath.Join(tmpdir, fmt.Sprintf("%d.db", fileId)))
+			assert.NoError(t, err)
+			bp.RegisterDisk(fileId, disk)
+			pageId, err := bp.AllocatePageId(fileId)
+			assert.NoError(t, err)
+			assert.NoError(t, bp.AddPage(pageId))
+			data, err := bp.GetWritePageData(pageId)
+			assert.NoError(t, err)
+			data[0] = 0xff
+			pageIds = append(pageIds, pageId)
+		}
+		discarded, kept := pageIds[0], pageIds[1]
+
+		// WHEN
+		bp.DiscardPages(discarded.FileId)
+
+		// THEN
+		assert.False(t, bp.IsPageCached(discarded))
+		assert.False(t, bp.flushList.Contains(discarded))
+		assert.True(t, bp.IsPageCached(kept))
+		assert.True(t, bp.flushList.Contains(kept))
+		assert.Equal(t, []page.PageId{kept}, bp.PopNewlyDirtied())
+
+		// 破棄したページはフラッシュしてもディスクに書き出されない
+		assert.NoError(t, bp.FlushAllPages())
+		disk, err := bp.GetDisk(discarded.FileId)
+		assert.NoError(t, err)
+		readData := directio.AlignedBlock(directio.BlockSize)
+		assert.Error(t, disk.ReadPageData(discarded, readData))
+	})
+}
+
+func TestFreePages(t *testing.T) {
+	t.Run("指定したページだけを破棄し、解放したページ ID を再利用する", func(t *testing.T) {
+		// GIVEN: 同じ FileId のダーティーページが 2 つバッファプールにある
+		tmpdir := t.TempDir()
+		bp := NewBufferPool(5, nil)
+		fileId := page.FileId(1)
+		disk, err := file.NewDisk(fileId, filepath.Join(tmpdir, "test.db"))
+		assert.NoError(t, err)
+		bp.RegisterDisk(fileId, disk)
+		var pageIds []page.PageId
+		for range 2 {
+			pageId, err := bp.AllocatePageId(fileId)
+			assert.NoError(t, err)
+			assert.NoError(t, bp.AddPage(pageId))
+			data, err := bp.GetWritePageData(pageId)
+			assert.NoError(t, err)
+			data[0] = 0xff
+			pageIds = append(pageIds, pageId)
+		}
+		freed, kept := pageIds[0], pageIds[1]
+
+		// WHEN
+		err = bp.FreePages([]page.PageId{freed})
+
+		// THEN
+		assert.NoError(t, err)
+		assert.False(t, bp.IsPageCached(freed))
+		assert.False(t, bp.flushList.Contains(freed))
+		assert.True(t, bp.IsPageCached(kept))
+		assert.True(t, bp.flushList.Contains(kept))
+		assert.Equal(t, []page.PageId{kept}, bp.PopNewlyDirtied())
+
+		// 解放したページ ID は次の採番で再利用される
+		reused, err := bp.AllocatePageId(fileId)
+		assert.NoError(t, err)
+		assert.Equal(t, freed, reused)
+	})
+
+	t.Run("Disk が登録されていない FileId のページを指定するとエラーを返す", func(t *testing.T) {
+		// GIVEN
+		bp := NewBufferPool(5, nil)
+
+		// WHEN
+		err := bp.FreePages([]page.PageId{page.NewPageId(page.FileId(9), page.PageNumber(0))})
+
+		// THEN
+		assert.Error(t, err)
+	})
+}
+
 func TestAllocatePageId(t *testing.T) {
 	t.Run("登録された FileId に対して PageId が正しく割り当てられる", func(t *testing.T) {
 		// GIVEN
@@ -40,6 +40,41 @@ func GetMaxDirtyPagesPct() int {
 	return getEnvInt("MINESQL_MAX_DIRTY_PAGES_PCT", 90)
 }
 
+// GetSortBufferSize はソート時にメモリ上に保持するレコードの合計サイズの上限 (バイト) を取得する
+//
+// 環境変数 MINESQL_SORT_BUFFER_SIZE が設定されていればその値を、なければデフォルト値を返す
+func GetSortBufferSize() int {
+	return getEnvInt("MINESQL_SORT_BUFFER_SIZE", 262144) // 256KB
+}
+
+// GetAggregateBufferSize はハッシュ集約時にメモリ上に保持するグループの合計サイズの上限 (バイト) を取得する
+//
+// 環境変数 MINESQL_AGGREGATE_BUFFER_SIZE が設定されていればその値を、なければデフォルト値を返す
+func GetAggregateBufferSize() int {
+	return getEnvInt("MINESQL_AGGREGATE_BUFFER_SIZE", 262144) // 256KB
+}
+
+// GetJoinBufferSize はハッシュ結合時にメモリ上に保持するビルド側のレコードの合計サイズの上限 (バイト) を取得する
+//
+// 環境変数 MINESQL_JOIN_BUFFER_SIZE が設定されていればその値を、なければデフォルト値を返す
+func GetJoinBufferSize() int {
+	return getEnvInt("MINESQL_JOIN_BUFFER_SIZE", 262144) // 256KB
+}
+
+// GetSetOperationBufferSize は DISTINCT・集合演算で重複の判定のためにメモリ上に保持する行の合計サイズの上限 (バイト) を取得する
+//
+// 環境変数 MINESQL_SET_OPERATION_BUFFER_SIZE が設定されていればその値を、なければデフォルト値を返す
+func GetSetOperationBufferSize() int {
+	return getEnvInt("MINESQL_SET_OPERATION_BUFFER_SIZE", 262144) // 256KB
+}
+
+// GetCTEMaxRecursionDepth は再帰 CTE の再帰の深さ (行を返した再帰の反復の回数) の上限を取得する
+//
+// 環境変数 MINESQL_CTE_MAX_RECURSION_DEPTH が設定されていればその値を、なければデフォルト値を返す
+func GetCTEMaxRecursionDepth() int {
+	return getEnvInt("MINESQL_CTE_MAX_RECURSION_DEPTH", 1000)
+}
+
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
@@ -169,3 +169,145 @@ func TestGetMaxDirtyPagesPct(t *testing.T) {
 		assert.Equal(t, 90, result)
 	})
 }
+
+func TestGetSortBufferSize(t *testing.T) {
+	t.Run("環境変数が設定されていない場合、デフォルト値を返す", func(t *testing.T) {
+		// GIVEN
+		t.Setenv("MINESQL_SORT_BUFFER_SIZE", "")
+
+		// WHEN
+		result := GetSortBufferSize()
+
+		// THEN
+		assert.Equal(t, 262144, result)
+	})
+
+	t.Run("環境変数が設定されている場合、その値を返す", func(t *testing.T) {
+		// GIVEN
+		t.Setenv("MINESQL_SORT_BUFFER_SIZE", "1024")
+
+		// WHEN
+		result := GetSortBufferSize()
+
+		// THEN
+		assert.Equal(t, 1024, result)
+	})
+
+	t.Run("環境変数が数値でない場合、デフォルト値を返す", func(t *testing.T) {
+		// GIVEN
+		t.Setenv("MINESQL_SORT_BUFFER_SIZE", "abc")
+
+		// WHEN
+		result := GetSortBufferSize()
+
+		// THEN
+		assert.Equal(t, 262144, result)
+	})
+}
+
+func TestGetAggregateBufferSize(t *testing.T) {
+	t.Run("環境変数が設定されていない場合、デフォルト値を返す", func(t *testing.T) {
+		// GIVEN
+		t.Setenv("MINESQL_AGGREGATE_BUFFER_SIZE", "")
+
+		// WHEN
+		result := GetAggregateBufferSize()
+
+		// THEN
+		assert.Equal(t, 262144, result)
+	})
+
+	t.Run("環境変数が設定されている場合、その値を返す", func(t *testing.T) {
+		// GIVEN
+		t.Setenv("MINESQL_AGGREGATE_BUFFER_SIZE", "1024")
+
+		// WHEN
+		result := GetAggregateBufferSize()
+
+		// THEN
+		assert.Equal(t, 1024, result)
+	})
+
+	t.Run("環境変数が数値でない場合、デフォルト値を返す", func(t *testing.T) {
+		// GIVEN
+		t.Setenv("MINESQL_AGGREGATE_BUFFER_SIZE", "abc")
+
+		// WHEN
+		result := GetAggregateBufferSize()
+
+		// THEN
+		assert.Equal(t, 262144, result)
+	})
+}
+
+func TestGetJoinBufferSize(t *testing.T) {
+	t.Run("環境変数が設定されていない場合、デフォルト値を返す", func(t *testing.T) {
+		// GIVEN
+		t.Setenv("MINESQL_JOIN_BUFFER_SIZE", "")
+
+		// WHEN
+		result := GetJoinBufferSize()
+
+		// THEN
+		assert.Equal(t, 262144, result)
+	})
+
+	t.Run("環境変数が設定されている場合、その値を返す", func(t *testing.T) {
+		// GIVEN
+		t.Setenv("MINESQL_JOIN_BUFFER_SIZE", "1024")
+
+		// WHEN
+		result := GetJoinBufferSize()
+
+		// THEN
+		assert.Equal(t, 1024, result)
+	})
+}
+
+func TestGetSetOperationBufferSize(t *testing.T) {
+	t.Run("環境変数が設定されていない場合、デフォルト値を返す", func(t *testing.T) {
+		// GIVEN
+		t.Setenv("MINESQL_SET_OPERATION_BUFFER_SIZE", "")
+
+		// WHEN
+		result := GetSetOperationBufferSize()
+
+		// THEN
+		assert.Equal(t, 262144, result)
+	})
+
+	t.Run("環境変数が設定されている場合、その値を返す", func(t *testing.T) {
+		// GIVEN
+		t.Setenv("MINESQL_SET_OPERATION_BUFFER_SIZE", "1024")
+
+		// WHEN
+		result := GetSetOperationBufferSize()
+
+		// THEN
+		assert.Equal(t, 1024, result)
+	})
+}
+
+func TestGetCTEMaxRecursionDepth(t *testing.T) {
+	t.Run("環境変数が設定されていない場合、デフォルト値を返す", func(t *testing.T) {
+		// GIVEN
+		t.Setenv("MINESQL_CTE_MAX_RECURSION_DEPTH", "")
+
+		// WHEN
+		result := GetCTEMaxRecursionDepth()
+
+		// THEN
+		assert.Equal(t, 1000, result)
+	})
+
+	t.Run("環境変数が設定されている場合、その値を返す", func(t *testing.T) {
+		// GIVEN
+		t.Setenv("MINESQL_CTE_MAX_RECURSION_DEPTH", "10")
+
+		// WHEN
+		result := GetCTEMaxRecursionDepth()
+
+		// THEN
+		assert.Equal(t, 10, result)
+	})
+}
@@ -13,16 +13,20 @@ var (
 	ErrInvalidCatalogFile = fmt.Errorf("invalid database catalog file: magic number mismatch")
 )
 
-// Catalog はテーブルのメタデータ (テーブル情報、インデックス情報、カラム情報、制約情報) を管理する
+// Catalog はテーブルのメタデータ (テーブル情報、インデックス情報、カラム情報、制約情報) とビュー・データベース・ユーザーのメタデータを管理する
 type Catalog struct {
 	TableMetaPageId      page.PageId
 	IndexMetaPageId      page.PageId
 	ColumnMetaPageId     page.PageId
 	ConstraintMetaPageId page.PageId
 	UserMetaPageId       page.PageId
+	ViewMetaPageId       page.PageId
+	DatabaseMetaPageId   page.PageId
 	NextFileId           page.FileId
 	UndoFileId           page.FileId
 	metadata             []*TableMeta
+	views                []*ViewMeta
+	databases            []*DatabaseMeta
 	Users                []*UserMeta
 }
 
@@ -49,6 +53,8 @@ func NewCatalog(bp *buffer.BufferPool) (*Catalog, error) {
 	userMetaPageNum := binary.BigEndian.Uint32(data[20:24])
 	nextFileId := page.FileId(binary.BigEndian.Uint32(data[24:28]))
 	undoFileId := page.FileId(binary.BigEndian.Uint32(data[28:32]))
+	viewMetaPageNum := binary.BigEndian.Uint32(data[32:36])
+	databaseMetaPageNum := binary.BigEndian.Uint32(data[36:40])
 
 	catalog := &Catalog{
 		TableMetaPageId:      page.NewPageId(fileId, page.PageNumber(tblMetaPageNum)),
@@ -56,11 +62,31 @@ func NewCatalog(bp *buffer.BufferPool) (*Catalog, error) {
 		ColumnMetaPageId:     page.NewPageId(fileId, page.PageNumber(colMetaPageNum)),
 		ConstraintMetaPageId: page.NewPageId(fileId, page.PageNumber(conMetaPageNum)),
 		UserMetaPageId:       page.NewPageId(fileId, page.PageNumber(userMetaPageNum)),
+		ViewMetaPageId:       page.NewPageId(fileId, page.PageNumber(viewMetaPageNum)),
+		DatabaseMetaPageId:   page.NewPageId(fileId, page.PageNumber(databaseMetaPageNum)),
 		NextFileId:           nextFileId,
 		UndoFileId:           undoFileId,
 		metadata:             nil,
 	}
 
+	// ビューに対応する前に作成したカタログ (ヘッダーページに保存したメタページの位置が 0) には、ビューメタデータ用の B+Tree を作成する
+	// (ページ 0 はヘッダーページのため、B+Tree のメタページになることはない)
+	if viewMetaPageNum == 0 {
+		viewMetaPageId, err := createMetaTree(bp, 32)
+		if err != nil {
+			return nil, err
+		}
+		catalog.ViewMetaPageId = viewMetaPageId
+	}
+	// 同様に、データベースに対応する前に作成したカタログには、データベースメタデータ用の B+Tree を作成する
+	if databaseMetaPageNum == 0 {
+		databaseMetaPageId, err := createMetaTree(bp, 36)
+		if err != nil {
+			return nil, err
+		}
+		catalog.DatabaseMetaPageId = databaseMetaPageId
+	}
+
 	// ディスクから既存のメタデータを読み込む
 	tableMeta, err := loadTableMeta(bp, catalog.TableMetaPageId, catalog.IndexMetaPageId, catalog.ColumnMetaPageId, catalog.ConstraintMetaPageId)
 	if err != nil {
@@ -75,9 +101,44 @@ func NewCatalog(bp *buffer.BufferPool) (*Catalog, error) {
 	}
 	catalog.Users = users
 
+	// ビューメタデータを読み込む
+	views, err := loadViewMeta(bp, catalog.ViewMetaPageId)
+	if err != nil {
+		return nil, err
+	}
+	catalog.views = views
+
+	// データベースメタデータを読み込む
+	databases, err := loadDatabaseMeta(bp, catalog.DatabaseMetaPageId)
+	if err != nil {
+		return nil, err
+	}
+	catalog.databases = databases
+
 	return catalog, nil
 }
 
+// createMetaTree はメタデータ用の B+Tree を作成し、そのメタページ ID をヘッダーページの offset の位置に保存する
+func createMetaTree(bp *buffer.BufferPool, offset int) (page.PageId, error) {
+	metaPageId, err := bp.AllocatePageId(page.FileId(0))
+	if err != nil {
+		return page.PageId{}, err
+	}
+	metaTree, err := btree.CreateBTree(bp, metaPageId)
+	if err != nil {
+		return page.PageId{}, err
+	}
+
+	headerPageId := page.NewPageId(page.FileId(0), 0)
+	data, err := bp.GetWritePageData(headerPageId)
+	if err != nil {
+		return page.PageId{}, err
+	}
+	defer bp.UnRefPage(headerPageId)
+	binary.BigEndian.PutUint32(data[offset:offset+4], uint32(metaTree.MetaPageId.PageNumber))
+	return metaTree.MetaPageId, nil
+}
+
 // CreateCatalog はカタログを新規作成する
 func CreateCatalog(bp *buffer.BufferPool) (*Catalog, error) {
 	fileId := page.FileId(0) // カタログ専用の FileId を使用
@@ -143,6 +204,26 @@ func CreateCatalog(bp *buffer.BufferPool) (*Catalog, error) {
 		return nil, err
 	}
 
+	// ビューメタデータ用の B+Tree を作成
+	viewMetaPageId, err := bp.AllocatePageId(fileId)
+	if err != nil {
+		return nil, err
+	}
+	viewMetaTree, err := btree.CreateBTree(bp, viewMetaPageId)
+	if err != nil {
+		return nil, err
+	}
+
+	// データベースメタデータ用の B+Tree を作成
+	databaseMetaPageId, err := bp.AllocatePageId(fileId)
+	if err != nil {
+		return nil, err
+	}
+	databaseMetaTree, err := btree.CreateBTree(bp, databaseMetaPageId)
+	if err != nil {
+		return nil, err
+	}
+
 	// ヘッダーページに各メタデータのメタページIDを保存
 	data, err := bp.GetWritePageData(headerPageId)
 	if err != nil {
@@ -160,6 +241,8 @@ func CreateCatalog(bp *buffer.BufferPool) (*Catalog, error) {
 	binary.BigEndian.PutUint32(data[20:24], uint32(userMetaTree.MetaPageId.PageNumber))
 	binary.BigEndian.PutUint32(data[24:28], uint32(nextFileId))
 	binary.BigEndian.PutUint32(data[28:32], uint32(undoFileId))
+	binary.BigEndian.PutUint32(data[32:36], uint32(viewMetaTree.MetaPageId.PageNumber))
+	binary.BigEndian.PutUint32(data[36:40], uint32(databaseMetaTree.MetaPageId.PageNumber))
 
 	return &Catalog{
 		TableMetaPageId:      tblMetaTree.MetaPageId,
@@ -167,6 +250,8 @@ func CreateCatalog(bp *buffer.BufferPool) (*Catalog, error) {
 		ColumnMetaPageId:     colMetaTree.MetaPageId,
 		ConstraintMetaPageId: conMetaTree.MetaPageId,
 		UserMetaPageId:       userMetaTree.MetaPageId,
+		ViewMetaPageId:       viewMetaTree.MetaPageId,
+		DatabaseMetaPageId:   databaseMetaTree.MetaPageId,
 		NextFileId:           nextFileId,
 		UndoFileId:           undoFileId,
 		metadata:             nil,
@@ -175,7 +260,61 @@ func CreateCatalog(bp *buffer.BufferPool) (*Catalog, error) {
 
 // Insert はカタログにテーブルメタデータを挿入する
 func (c *Catalog) Insert(bp *buffer.BufferPool, tableMeta TableMeta) error {
-	// 各メタデータに MetaPageId を設定する
+	c.setMetaPageIds(&tableMeta)
+	if err := tableMeta.Insert(bp); err != nil {
+		return err
+	}
+
+	c.metadata = append(c.metadata, &tableMeta)
+	return nil
+}
+
+// Delete はカタログからテーブルメタデータと関連メタデータ (インデックス、カラム、制約) を削除する
+func (c *Catalog) Delete(bp *buffer.BufferPool, tableName string) error {
+	for i, tableMeta := range c.metadata {
+		if tableMeta.Name != tableName {
+			continue
+		}
+
+		// ディスクから読み込んだメタデータには MetaPageId が設定されていないため設定する
+		c.setMetaPageIds(tableMeta)
+		if err := tableMeta.Delete(bp); err != nil {
+			return err
+		}
+
+		c.metadata = append(c.metadata[:i:i], c.metadata[i+1:]...)
+		return nil
+	}
+	return fmt.Errorf("table %s not found in catalog", tableName)
+}
+
+// Update は同じテーブル名のテーブルメタデータを置き換える
+//
+// 既存のメタデータと関連メタデータ (インデックス、カラム、制約) を B+Tree から削除し、新しいメタデータを挿入する。
+// ALTER TABLE で使用する
+func (c *Catalog) Update(bp *buffer.BufferPool, tableMeta TableMeta) error {
+	for i, oldMeta := range c.metadata {
+		if oldMeta.Name != tableMeta.Name {
+			continue
+		}
+
+		c.setMetaPageIds(oldMeta)
+		if err := oldMeta.Delete(bp); err != nil {
+			return err
+		}
+		c.setMetaPageIds(&tableMeta)
+		if err := tableMeta.Insert(bp); err != nil {
+			return err
+		}
+
+		c.metadata[i] = &tableMeta
+		return nil
+	}
+	return fmt.Errorf("table %s not found in catalog", tableMeta.Name)
+}
+
+// setMetaPageIds はテーブルメタデータと関連メタデータに、それぞれを格納する B+Tree の MetaPageId を設定する
+func (c *Catalog) setMetaPageIds(tableMeta *TableMeta) {
 	tableMeta.MetaPageId = c.TableMetaPageId
 	for _, indexMeta := range tableMeta.Indexes {
 		indexMeta.MetaPageId = c.IndexMetaPageId
@@ -186,13 +325,6 @@ func (c *Catalog) Insert(bp *buffer.BufferPool, tableMeta TableMeta) error {
 	for _, conMeta := range tableMeta.Constraints {
 		conMeta.MetaPageId = c.ConstraintMetaPageId
 	}
-
-	if err := tableMeta.Insert(bp); err != nil {
-		return err
-	}
-
-	c.metadata = append(c.metadata, &tableMeta)
-	return nil
 }
 
 // GetTableMetaByName はテーブル名からテーブルメタデータを取得する
@@ -235,6 +367,110 @@ func (c *Catalog) GetForeignKeysReferencingTable(tableName string) []ChildForeig
 	return result
 }
 
+// InsertView はカタログにビューメタデータを挿入する
+func (c *Catalog) InsertView(bp *buffer.BufferPool, viewMeta ViewMeta) error {
+	viewMeta.MetaPageId = c.ViewMetaPageId
+	if err := viewMeta.Insert(bp); err != nil {
+		return err
+	}
+
+	c.views = append(c.views, &viewMeta)
+	return nil
+}
+
+// UpdateView は同じ名前のビューメタデータを置き換える (CREATE OR REPLACE VIEW で使用する)
+func (c *Catalog) UpdateView(bp *buffer.BufferPool, viewMeta ViewMeta) error {
+	for i, oldMeta := range c.views {
+		if oldMeta.Name != viewMeta.Name {
+			continue
+		}
+
+		viewMeta.MetaPageId = c.ViewMetaPageId
+		if err := viewMeta.Update(bp); err != nil {
+			return err
+		}
+
+		c.views[i] = &viewMeta
+		return nil
+	}
+	return fmt.Errorf("view %s not found in catalog", viewMeta.Name)
+}
+
+// DeleteView はカタログからビューメタデータを削除する
+func (c *Catalog) DeleteView(bp *buffer.BufferPool, viewName string) error {
+	for i, viewMeta := range c.views {
+		if viewMeta.Name != viewName {
+			continue
+		}
+
+		if err := viewMeta.Delete(bp); err != nil {
+			return err
+		}
+
+		c.views = append(c.views[:i:i], c.views[i+1:]...)
+		return nil
+	}
+	return fmt.Errorf("view %s not found in catalog", viewName)
+}
+
+// GetViewByName はビュー名からビューメタデータを取得する
+func (c *Catalog) GetViewByName(viewName string) (*ViewMeta, bool) {
+	for _, viewMeta := range c.views {
+		if viewMeta.Name == viewName {
+			return viewMeta, true
+		}
+	}
+	return nil, false
+}
+
+// GetAllViews はすべてのビューメタデータを取得する
+func (c *Catalog) GetAllViews() []*ViewMeta {
+	return c.views
+}
+
+// InsertDatabase はカタログにデータベースメタデータを挿入する
+func (c *Catalog) InsertDatabase(bp *buffer.BufferPool, databaseMeta DatabaseMeta) error {
+	databaseMeta.MetaPageId = c.DatabaseMetaPageId
+	if err := databaseMeta.Insert(bp); err != nil {
+		return err
+	}
+
+	c.databases = append(c.databases, &databaseMeta)
+	return nil
+}
+
+// DeleteDatabase はカタログからデータベースメタデータを削除する
+func (c *Catalog) DeleteDatabase(bp *buffer.BufferPool, databaseName string) error {
+	for i, databaseMeta := range c.databases {
+		if databaseMeta.Name != databaseName {
+			continue
+		}
+
+		if err := databaseMeta.Delete(bp); err != nil {
+			return err
+		}
+
+		c.databases = append(c.databases[:i:i], c.databases[i+1:]...)
+		return nil
+	}
+	return fmt.Errorf("database %s not found in catalog", databaseName)
+}
+
+// GetDatabaseByName はデータベース名からデータベースメタデータを取得する
+func (c *Catalog) GetDatabaseByName(databaseName string) (*DatabaseMeta, bool) {
+	for _, databaseMeta := range c.databases {
+		if databaseMeta.Name == databaseName {
+			return databaseMeta, true
+		}
+	}
+	return nil, false
+}
+
+// GetAllDatabases はすべてのデータベースメタデータを取得する
+func (c *Catalog) GetAllDatabases() []*DatabaseMeta {
+	return c.databases
+}
+
 // GetUserByName はユーザー名からユーザーメタデータを取得する
 func (c *Catalog) GetUserByName(username string) (*UserMeta, bool) {
 	for _, user := range c.Users {
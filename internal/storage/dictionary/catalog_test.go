@@ -115,7 +115,7 @@ func TestNewCatalog(t *testing.T) {
 			NewColumnMeta(fileId, "email", 1, ColumnTypeString),
 		}
 		idxMeta := []*IndexMeta{
-			NewIndexMeta(fileId, "idx_email", "email", IndexTypeUnique, indexMetaPageId),
+			NewIndexMeta(fileId, "idx_email", []string{"email"}, IndexTypeUnique, indexMetaPageId),
 		}
 		tableMeta := NewTableMeta(fileId, "users", 2, 1, colMeta, idxMeta, metaPageId)
 		err = cat.Insert(bp, tableMeta)
@@ -140,7 +140,7 @@ func TestNewCatalog(t *testing.T) {
 		assert.Equal(t, 1, len(cat2.metadata))
 		assert.Equal(t, 1, len(cat2.metadata[0].Indexes))
 		assert.Equal(t, "idx_email", cat2.metadata[0].Indexes[0].Name)
-		assert.Equal(t, "email", cat2.metadata[0].Indexes[0].ColName)
+		assert.Equal(t, []string{"email"}, cat2.metadata[0].Indexes[0].ColNames)
 		assert.Equal(t, IndexTypeUnique, cat2.metadata[0].Indexes[0].Type)
 		assert.Equal(t, indexMetaPageId, cat2.metadata[0].Indexes[0].DataMetaPageId)
 	})
@@ -518,7 +518,7 @@ func TestInsert(t *testing.T) {
 			NewColumnMeta(fileId, "email", 1, ColumnTypeString),
 		}
 		idxMeta := []*IndexMeta{
-			NewIndexMeta(fileId, "idx_email", "email", IndexTypeUnique, indexMetaPageId),
+			NewIndexMeta(fileId, "idx_email", []string{"email"}, IndexTypeUnique, indexMetaPageId),
 		}
 		tableMeta := NewTableMeta(fileId, "users", 2, 1, colMeta, idxMeta, metaPageId)
 
@@ -533,6 +533,151 @@ func TestInsert(t *testing.T) {
 	})
 }
 
+func TestDelete(t *testing.T) {
+	t.Run("テーブルメタデータと関連メタデータを削除でき、他のテーブルのメタデータは残る", func(t *testing.T) {
+		// GIVEN: users (インデックス・制約付き) と orders が登録されている
+		bp, tmpdir := InitCatalogDisk(t)
+		defer removeTmpdir(t, tmpdir)
+
+		cat, err := CreateCatalog(bp)
+		assert.NoError(t, err)
+
+		usersFileId := page.FileId(1)
+		usersMeta := NewTableMeta(usersFileId, "users", 2, 1, []*ColumnMeta{
+			NewColumnMeta(usersFileId, "id", 0, ColumnTypeString),
+			NewColumnMeta(usersFileId, "email", 1, ColumnTypeString),
+		}, []*IndexMeta{
+			NewIndexMeta(usersFileId, "idx_email", []string{"email"}, IndexTypeUnique, page.NewPageId(usersFileId, 1)),
+		}, page.NewPageId(usersFileId, 0))
+		usersMeta.Constraints = []*ConstraintMeta{
+			NewConstraintMeta(usersFileId, "id", "PRIMARY", "", ""),
+			NewConstraintMeta(usersFileId, "email", "idx_email", "", ""),
+		}
+		assert.NoError(t, cat.Insert(bp, usersMeta))
+
+		ordersFileId := page.FileId(2)
+		ordersMeta := NewTableMeta(ordersFileId, "orders", 1, 1, []*ColumnMeta{
+			NewColumnMeta(ordersFileId, "id", 0, ColumnTypeString),
+		}, []*IndexMeta{}, page.NewPageId(ordersFileId, 0))
+		ordersMeta.Constraints = []*ConstraintMeta{NewConstraintMeta(ordersFileId, "id", "PRIMARY", "", "")}
+		assert.NoError(t, cat.Insert(bp, ordersMeta))
+		assert.NoError(t, bp.FlushAllPages())
+
+		// ディスクから読み込み直したカタログで削除する
+		bp2 := buffer.NewBufferPool(10, nil)
+		dm2, err := file.NewDisk(page.FileId(0), filepath.Join(tmpdir, "minesql.db"))
+		assert.NoError(t, err)
+		bp2.RegisterDisk(page.FileId(0), dm2)
+		cat2, err := NewCatalog(bp2)
+		assert.NoError(t, err)
+
+		// WHEN
+		err = cat2.Delete(bp2, "users")
+
+		// THEN
+		assert.NoError(t, err)
+		_, ok := cat2.GetTableMetaByName("users")
+		assert.False(t, ok)
+		assert.Equal(t, 1, len(cat2.GetAllTables()))
+
+		// B+Tree からも削除されている
+		tables, err := loadTableMeta(bp2, cat2.TableMetaPageId, cat2.IndexMetaPageId, cat2.ColumnMetaPageId, cat2.ConstraintMetaPageId)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, len(tables))
+		assert.Equal(t, "orders", tables[0].Name)
+		assert.Equal(t, 1, len(tables[0].Cols))
+		assert.Equal(t, 1, len(tables[0].Constraints))
+		indexes, err := loadIndexMeta(bp2, usersFileId, cat2.IndexMetaPageId)
+		assert.NoError(t, err)
+		assert.Empty(t, indexes)
+		cols, err := loadColumnMeta(bp2, usersFileId, cat2.ColumnMetaPageId)
+		assert.NoError(t, err)
+		assert.Empty(t, cols)
+		constraints, err := loadConstraintMeta(bp2, usersFileId, cat2.ConstraintMetaPageId)
+		assert.NoError(t, err)
+		assert.Empty(t, constraints)
+	})
+
+	t.Run("存在しないテーブル名を指定するとエラーを返す", func(t *testing.T) {
+		// GIVEN
+		bp, tmpdir := InitCatalogDisk(t)
+		defer removeTmpdir(t, tmpdir)
+
+		cat, err := CreateCatalog(bp)
+		assert.NoError(t, err)
+
+		// WHEN
+		err = cat.Delete(bp, "users")
+
+		// THEN
+		assert.ErrorContains(t, err, "table users not found in catalog")
+	})
+}
+
+func TestUpdate(t *testing.T) {
+	t.Run("テーブルメタデータと関連メタデータを置き換えられる", func(t *testing.T) {
+		// GIVEN: users (インデックス・制約付き) が登録されている
+		bp, tmpdir := InitCatalogDisk(t)
+		defer removeTmpdir(t, tmpdir)
+
+		cat, err := CreateCatalog(bp)
+		assert.NoError(t, err)
+
+		fileId := page.FileId(1)
+		usersMeta := NewTableMeta(fileId, "users", 2, 1, []*ColumnMeta{
+			NewColumnMeta(fileId, "id", 0, ColumnTypeString),
+			NewColumnMeta(fileId, "email", 1, ColumnTypeString),
+		}, []*IndexMeta{
+			NewIndexMeta(fileId, "idx_email", []string{"email"}, IndexTypeUnique, page.NewPageId(fileId, 1)),
+		}, page.NewPageId(fileId, 0))
+		usersMeta.Constraints = []*ConstraintMeta{
+			NewConstraintMeta(fileId, "id", "PRIMARY", "", ""),
+			NewConstraintMeta(fileId, "email", "idx_email", "", ""),
+		}
+		assert.NoError(t, cat.Insert(bp, usersMeta))
+
+		// WHEN: email を削除して name を追加し、テーブルの B+Tree を差し替える
+		newMeta := NewTableMeta(fileId, "users", 2, 1, []*ColumnMeta{
+			NewColumnMeta(fileId, "id", 0, ColumnTypeString),
+			NewColumnMeta(fileId, "name", 1, ColumnTypeString),
+		}, nil, page.NewPageId(fileId, 2))
+		newMeta.Constraints = []*ConstraintMeta{NewConstraintMeta(fileId, "id", "PRIMARY", "", "")}
+		err = cat.Update(bp, newMeta)
+
+		// THEN
+		assert.NoError(t, err)
+		tblMeta, ok := cat.GetTableMetaByName("users")
+		assert.True(t, ok)
+		assert.Equal(t, page.NewPageId(fileId, 2), tblMeta.DataMetaPageId)
+
+		// B+Tree も置き換えられている
+		tables, err := loadTableMeta(bp, cat.TableMetaPageId, cat.IndexMetaPageId, cat.ColumnMetaPageId, cat.ConstraintMetaPageId)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, len(tables))
+		assert.Equal(t, page.NewPageId(fileId, 2), tables[0].DataMetaPageId)
+		sortedCols := tables[0].GetSortedCols()
+		assert.Equal(t, "id", sortedCols[0].Name)
+		assert.Equal(t, "name", sortedCols[1].Name)
+		assert.Empty(t, tables[0].Indexes)
+		assert.Equal(t, 1, len(tables[0].Constraints))
+	})
+
+	t.Run("存在しないテーブル名を指定するとエラーを返す", func(t *testing.T) {
+		// GIVEN
+		bp, tmpdir := InitCatalogDisk(t)
+		defer removeTmpdir(t, tmpdir)
+
+		cat, err := CreateCatalog(bp)
+		assert.NoError(t, err)
+
+		// WHEN
+		err = cat.Update(bp, NewTableMeta(page.FileId(1), "users", 1, 1, nil, nil, page.NewPageId(page.FileId(1), 0)))
+
+		// THEN
+		assert.ErrorContains(t, err, "table users not found in catalog")
+	})
+}
+
 func TestGetTableMetadataByName(t *testing.T) {
 	t.Run("テーブル名からテーブルメタデータを取得できる", func(t *testing.T) {
 		// GIVEN
@@ -848,6 +993,210 @@ func TestHasUsers(t *testing.T) {
 	})
 }
 
+func TestViews(t *testing.T) {
+	// reopenCatalog はページをフラッシュし、ディスクからカタログを開き直す
+	reopenCatalog := func(t *testing.T, bp *buffer.BufferPool, tmpdir string) (*buffer.BufferPool, *Catalog) {
+		assert.NoError(t, bp.FlushAllPages())
+		bp2 := buffer.NewBufferPool(10, nil)
+		dm2, err := file.NewDisk(page.FileId(0), filepath.Join(tmpdir, "minesql.db"))
+		assert.NoError(t, err)
+		bp2.RegisterDisk(page.FileId(0), dm2)
+		cat2, err := NewCatalog(bp2)
+		assert.NoError(t, err)
+		return bp2, cat2
+	}
+
+	t.Run("ビューメタデータを挿入でき、カタログを開き直しても読み込まれる", func(t *testing.T) {
+		// GIVEN
+		bp, tmpdir := InitCatalogDisk(t)
+		defer removeTmpdir(t, tmpdir)
+
+		cat, err := CreateCatalog(bp)
+		assert.NoError(t, err)
+
+		// WHEN
+		err = cat.InsertView(bp, *NewViewMeta("adults", []string{"user_id", "user_name"}, "SELECT id, name FROM users WHERE age >= 20"))
+		assert.NoError(t, err)
+		err = cat.InsertView(bp, *NewViewMeta("all_users", nil, "SELECT * FROM users"))
+		assert.NoError(t, err)
+		_, cat2 := reopenCatalog(t, bp, tmpdir)
+
+		// THEN
+		assert.Equal(t, 2, len(cat2.GetAllViews()))
+		view, ok := cat2.GetViewByName("adults")
+		assert.True(t, ok)
+		assert.Equal(t, []string{"user_id", "user_name"}, view.Columns)
+		assert.Equal(t, "SELECT id, name FROM users WHERE age >= 20", view.Definition)
+		view, ok = cat2.GetViewByName("all_users")
+		assert.True(t, ok)
+		assert.Nil(t, view.Columns)
+		assert.Equal(t, "SELECT * FROM users", view.Definition)
+	})
+
+	t.Run("同じ名前のビューメタデータを置き換えられる", func(t *testing.T) {
+		// GIVEN
+		bp, tmpdir := InitCatalogDisk(t)
+		defer removeTmpdir(t, tmpdir)
+
+		cat, err := CreateCatalog(bp)
+		assert.NoError(t, err)
+		assert.NoError(t, cat.InsertView(bp, *NewViewMeta("v", nil, "SELECT id FROM users")))
+
+		// WHEN
+		err = cat.UpdateView(bp, *NewViewMeta("v", []string{"n"}, "SELECT name FROM users"))
+
+		// THEN
+		assert.NoError(t, err)
+		_, cat2 := reopenCatalog(t, bp, tmpdir)
+		assert.Equal(t, 1, len(cat2.GetAllViews()))
+		view, ok := cat2.GetViewByName("v")
+		assert.True(t, ok)
+		assert.Equal(t, []string{"n"}, view.Columns)
+		assert.Equal(t, "SELECT name FROM users", view.Definition)
+	})
+
+	t.Run("ビューメタデータを削除でき、他のビューは残る", func(t *testing.T) {
+		// GIVEN
+		bp, tmpdir := InitCatalogDisk(t)
+		defer removeTmpdir(t, tmpdir)
+
+		cat, err := CreateCatalog(bp)
+		assert.NoError(t, err)
+		assert.NoError(t, cat.InsertView(bp, *NewViewMeta("v1", nil, "SELECT id FROM users")))
+		assert.NoError(t, cat.InsertView(bp, *NewViewMeta("v2", nil, "SELECT name FROM users")))
+
+		// WHEN
+		err = cat.DeleteView(bp, "v1")
+
+		// THEN
+		assert.NoError(t, err)
+		_, ok := cat.GetViewByName("v1")
+		assert.False(t, ok)
+		_, cat2 := reopenCatalog(t, bp, tmpdir)
+		assert.Equal(t, 1, len(cat2.GetAllViews()))
+		assert.Equal(t, "v2", cat2.GetAllViews()[0].Name)
+	})
+
+	t.Run("存在しないビュー名を指定するとエラーを返す", func(t *testing.T) {
+		// GIVEN
+		bp, tmpdir := InitCatalogDisk(t)
+		defer removeTmpdir(t, tmpdir)
+
+		cat, err := CreateCatalog(bp)
+		assert.NoError(t, err)
+
+		// WHEN
+		updateErr := cat.UpdateView(bp, *NewViewMeta("v", nil, "SELECT id FROM users"))
+		deleteErr := cat.DeleteView(bp, "v")
+
+		// THEN
+		assert.ErrorContains(t, updateErr, "view v not found in catalog")
+		assert.ErrorContains(t, deleteErr, "view v not found in catalog")
+	})
+
+	t.Run("ビューメタデータ用の B+Tree がないカタログを開くと、B+Tree を作成する", func(t *testing.T) {
+		// GIVEN: ヘッダーページのビューメタデータの位置を 0 にする (ビューに対応する前のカタログ)
+		bp, tmpdir := InitCatalogDisk(t)
+		defer removeTmpdir(t, tmpdir)
+
+		_, err := CreateCatalog(bp)
+		assert.NoError(t, err)
+		headerPageId := page.NewPageId(page.FileId(0), 0)
+		data, err := bp.GetWritePageData(headerPageId)
+		assert.NoError(t, err)
+		binary.BigEndian.PutUint32(data[32:36], 0)
+		bp.UnRefPage(headerPageId)
+
+		// WHEN
+		bp2, cat2 := reopenCatalog(t, bp, tmpdir)
+
+		// THEN: 作成した B+Tree にビューを登録でき、開き直しても読み込まれる
+		assert.NotEqual(t, page.PageNumber(0), cat2.ViewMetaPageId.PageNumber)
+		assert.Empty(t, cat2.GetAllViews())
+		assert.NoError(t, cat2.InsertView(bp2, *NewViewMeta("v", nil, "SELECT id FROM users")))
+		_, cat3 := reopenCatalog(t, bp2, tmpdir)
+		assert.Equal(t, cat2.ViewMetaPageId, cat3.ViewMetaPageId)
+		assert.Equal(t, 1, len(cat3.GetAllViews()))
+	})
+}
+
+func TestDatabases(t *testing.T) {
+	// reopenCatalog はページをフラッシュし、ディスクからカタログを開き直す
+	reopenCatalog := func(t *testing.T, bp *buffer.BufferPool, tmpdir string) (*buffer.BufferPool, *Catalog) {
+		assert.NoError(t, bp.FlushAllPages())
+		bp2 := buffer.NewBufferPool(10, nil)
+		dm2, err := file.NewDisk(page.FileId(0), filepath.Join(tmpdir, "minesql.db"))
+		assert.NoError(t, err)
+		bp2.RegisterDisk(page.FileId(0), dm2)
+		cat2, err := NewCatalog(bp2)
+		assert.NoError(t, err)
+		return bp2, cat2
+	}
+
+	t.Run("データベースメタデータを挿入・削除でき、カタログを開き直しても読み込まれる", func(t *testing.T) {
+		// GIVEN
+		bp, tmpdir := InitCatalogDisk(t)
+		defer removeTmpdir(t, tmpdir)
+
+		cat, err := CreateCatalog(bp)
+		assert.NoError(t, err)
+
+		// WHEN
+		assert.NoError(t, cat.InsertDatabase(bp, *NewDatabaseMeta("app")))
+		assert.NoError(t, cat.InsertDatabase(bp, *NewDatabaseMeta("tenant1")))
+		err = cat.DeleteDatabase(bp, "app")
+
+		// THEN
+		assert.NoError(t, err)
+		_, ok := cat.GetDatabaseByName("app")
+		assert.False(t, ok)
+		_, cat2 := reopenCatalog(t, bp, tmpdir)
+		assert.Equal(t, 1, len(cat2.GetAllDatabases()))
+		_, ok = cat2.GetDatabaseByName("tenant1")
+		assert.True(t, ok)
+	})
+
+	t.Run("存在しないデータベース名を指定するとエラーを返す", func(t *testing.T) {
+		// GIVEN
+		bp, tmpdir := InitCatalogDisk(t)
+		defer removeTmpdir(t, tmpdir)
+
+		cat, err := CreateCatalog(bp)
+		assert.NoError(t, err)
+
+		// WHEN
+		err = cat.DeleteDatabase(bp, "app")
+
+		// THEN
+		assert.ErrorContains(t, err, "database app not found in catalog")
+	})
+
+	t.Run("データベースメタデータ用の B+Tree がないカタログを開くと、B+Tree を作成する", func(t *testing.T) {
+		// GIVEN: ヘッダーページのデータベースメタデータの位置を 0 にする (データベースに対応する前のカタログ)
+		bp, tmpdir := InitCatalogDisk(t)
+		defer removeTmpdir(t, tmpdir)
+
+		_, err := CreateCatalog(bp)
+		assert.NoError(t, err)
+		headerPageId := page.NewPageId(page.FileId(0), 0)
+		data, err := bp.GetWritePageData(headerPageId)
+		assert.NoError(t, err)
+		binary.BigEndian.PutUint32(data[36:40], 0)
+		bp.UnRefPage(headerPageId)
+
+		// WHEN
+		bp2, cat2 := reopenCatalog(t, bp, tmpdir)
+
+		// THEN: 作成した B+Tree にデータベースを登録でき、開き直しても読み込まれる
+		assert.NotEqual(t, page.PageNumber(0), cat2.DatabaseMetaPageId.PageNumber)
+		assert.Empty(t, cat2.GetAllDatabases())
+		assert.NoError(t, cat2.InsertDatabase(bp2, *NewDatabaseMeta("app")))
+		_, cat3 := reopenCatalog(t, bp2, tmpdir)
+		assert.Equal(t, cat2.DatabaseMetaPageId, cat3.DatabaseMetaPageId)
+		assert.Equal(t, 1, len(cat3.GetAllDatabases()))
+	})
+}
+
 func InitCatalogDisk(t *testing.T) (bp *buffer.BufferPool, tmpdir string) {
 	tmpdir = t.TempDir()
 	filePath := filepath.Join(tmpdir, "minesql.db")
@@ -0,0 +1,171 @@
+package dictionary
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ren-yamanashi/minesql/internal/storage/encode"
+)
+
+// DECIMAL で指定できる最大の精度 (int64 に収まる桁数)
+const MaxDecimalPrecision = 18
+
+const (
+	dateLayout     = "2006-01-02"
+	datetimeLayout = "2006-01-02 15:04:05"
+)
+
+// ParseValue はテキスト表現の値を、カラムのデータ型に応じた格納形式に変換する
+//
+// 格納形式はバイト列の辞書順と値の大小関係が一致する (順序保存) ため、
+// B+Tree のキーやレコード同士の比較にそのまま使用できる
+//   - 文字列型: テキストそのもの
+//   - INT, BIGINT: encode.EncodeInt64 でエンコードした 8 バイト
+//   - DECIMAL: 10^Scale 倍した整数を encode.EncodeInt64 でエンコードした 8 バイト
+//   - DATE, DATETIME: UNIX 時間 (秒, UTC) を encode.EncodeInt64 でエンコードした 8 バイト
+func (cm *ColumnMeta) ParseValue(text string) ([]byte, error) {
+	switch cm.Type {
+	case ColumnTypeInt, ColumnTypeBigInt:
+		v, err := strconv.ParseInt(text, 10, 64)
+		if err != nil || (cm.Type == ColumnTypeInt && (v < math.MinInt32 || v > math.MaxInt32)) {
+			return nil, cm.incorrectValueError(text)
+		}
+		return encode.EncodeInt64(v), nil
+	case ColumnTypeDecimal:
+		v, err := parseDecimal(text, cm.Precision, cm.Scale)
+		if err != nil {
+			return nil, cm.incorrectValueError(text)
+		}
+		return encode.EncodeInt64(v), nil
+	case ColumnTypeDate:
+		t, err := time.Parse(dateLayout, text)
+		if err != nil {
+			return nil, cm.incorrectValueError(text)
+		}
+		return encode.EncodeInt64(t.Unix()), nil
+	case ColumnTypeDatetime:
+		t, err := time.Parse(datetimeLayout, text)
+		if err != nil {
+			// 時刻部分を省略した場合は 00:00:00 として扱う
+			t, err = time.Parse(dateLayout, text)
+			if err != nil {
+				return nil, cm.incorrectValueError(text)
+			}
+		}
+		return encode.EncodeInt64(t.Unix()), nil
+	default:
+		return []byte(text), nil
+	}
+}
+
+// FormatValue は格納形式の値をテキスト表現に変換する
+func (cm *ColumnMeta) FormatValue(value []byte) string {
+	return FormatValue(cm.Type, cm.Scale, value)
+}
+
+// FormatValue は格納形式の値を、データ型に応じたテキスト表現に変換する
+func FormatValue(colType ColumnType, scale uint8, value []byte) string {
+	if !colType.IsFixedSize() || len(value) != encode.INT_SIZE {
+		return string(value)
+	}
+	v := encode.DecodeInt64(value)
+	switch colType {
+	case ColumnTypeDecimal:
+		return formatDecimal(v, scale)
+	case ColumnTypeDate:
+		return time.Unix(v, 0).UTC().Format(dateLayout)
+	case ColumnTypeDatetime:
+		return time.Unix(v, 0).UTC().Format(datetimeLayout)
+	default:
+		return strconv.FormatInt(v, 10)
+	}
+}
+
+// IsFixedSize はデータ型の格納形式が固定長 (8 バイト) かどうかを返す
+func (ct ColumnType) IsFixedSize() bool {
+	switch ct {
+	case ColumnTypeInt, ColumnTypeBigInt, ColumnTypeDecimal, ColumnTypeDate, ColumnTypeDatetime:
+		return true
+	default:
+		return false
+	}
+}
+
+// incorrectValueError はカラムのデータ型に合わない値が指定された場合のエラーを返す
+func (cm *ColumnMeta) incorrectValueError(text string) error {
+	return fmt.Errorf("incorrect %s value: '%s' for column '%s'", cm.Type, text, cm.Name)
+}
+
+// parseDecimal は 10 進数のテキストを 10^scale 倍した整数に変換する
+//
+// 小数部が scale 桁を超える場合は四捨五入し、整数部が (precision - scale) 桁を超える場合はエラーを返す
+func parseDecimal(text string, precision, scale uint8) (int64, error) {
+	s := text
+	negative := false
+	if strings.HasPrefix(s, "-") || strings.HasPrefix(s, "+") {
+		negative = s[0] == '-'
+		s = s[1:]
+	}
+	intPart, fracPart, _ := strings.Cut(s, ".")
+	if intPart == "" && fracPart == "" {
+		return 0, fmt.Errorf("invalid decimal: %s", text)
+	}
+	for _, part := range []string{intPart, fracPart} {
+		for _, ch := range part {
+			if ch < '0' || ch > '9' {
+				return 0, fmt.Errorf("invalid decimal: %s", text)
+			}
+		}
+	}
+
+	// 小数部を scale 桁に揃える (超過分は四捨五入)
+	roundUp := false
+	if len(fracPart) > int(scale) {
+		roundUp = fracPart[scale] >= '5'
+		fracPart = fracPart[:scale]
+	} else {
+		fracPart += strings.Repeat("0", int(scale)-len(fracPart))
+	}
+
+	intPart = strings.TrimLeft(intPart, "0")
+	digits := intPart + fracPart
+	if digits == "" {
+		digits = "0"
+	}
+	v, err := strconv.ParseInt(digits, 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	if roundUp {
+		v++
+	}
+	if v >= int64(math.Pow10(int(precision))) {
+		return 0, fmt.Errorf("decimal out of range: %s", text)
+	}
+	if negative {
+		v = -v
+	}
+	return v, nil
+}
+
+// formatDecimal は 10^scale 倍された整数を 10 進数のテキストに変換する
+func formatDecimal(v int64, scale uint8) string {
+	sign := ""
+	u := uint64(v)
+	if v < 0 {
+		sign = "-"
+		u = uint64(-v)
+	}
+	digits := strconv.FormatUint(u, 10)
+	if scale == 0 {
+		return sign + digits
+	}
+	if len(digits) <= int(scale) {
+		digits = strings.Repeat("0", int(scale)-len(digits)+1) + digits
+	}
+	point := len(digits) - int(scale)
+	return sign + digits[:point] + "." + digits[point:]
+}
@@ -0,0 +1,114 @@
+package dictionary
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestColumnMeta_ParseValue(t *testing.T) {
+	t.Run("文字列型はテキストをそのまま格納形式にする", func(t *testing.T) {
+		// GIVEN
+		col := NewColumnMeta(1, "name", 0, ColumnTypeString)
+
+		// WHEN
+		value, err := col.ParseValue("Alice")
+
+		// THEN
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("Alice"), value)
+	})
+
+	t.Run("数値型・日付型の格納形式は値の大小関係とバイト列の辞書順が一致する", func(t *testing.T) {
+		decimal := NewColumnMeta(1, "price", 0, ColumnTypeDecimal)
+		decimal.Precision = 10
+		decimal.Scale = 2
+		tests := []struct {
+			col    *ColumnMeta
+			values []string // 昇順
+		}{
+			{NewColumnMeta(1, "n", 0, ColumnTypeInt), []string{"-2147483648", "-10", "-9", "0", "9", "10", "2147483647"}},
+			{NewColumnMeta(1, "n", 0, ColumnTypeBigInt), []string{"-9223372036854775808", "-1", "2", "100", "9223372036854775807"}},
+			{decimal, []string{"-10.5", "-1", "0.01", "0.1", "2", "10.25"}},
+			{NewColumnMeta(1, "d", 0, ColumnTypeDate), []string{"1969-12-31", "1970-01-01", "2024-02-29", "2024-10-01"}},
+			{NewColumnMeta(1, "dt", 0, ColumnTypeDatetime), []string{"2024-01-01", "2024-01-01 00:00:01", "2024-01-01 23:59:59"}},
+		}
+		for _, tt := range tests {
+			var prev []byte
+			for _, text := range tt.values {
+				// WHEN
+				value, err := tt.col.ParseValue(text)
+
+				// THEN
+				assert.NoError(t, err, text)
+				if prev != nil {
+					assert.Equal(t, -1, bytes.Compare(prev, value), "%s: %s", tt.col.Type, text)
+				}
+				prev = value
+			}
+		}
+	})
+
+	t.Run("データ型に合わない値はエラーを返す", func(t *testing.T) {
+		decimal := NewColumnMeta(1, "price", 0, ColumnTypeDecimal)
+		decimal.Precision = 4
+		decimal.Scale = 2
+		tests := []struct {
+			col  *ColumnMeta
+			text string
+		}{
+			{NewColumnMeta(1, "n", 0, ColumnTypeInt), "abc"},
+			{NewColumnMeta(1, "n", 0, ColumnTypeInt), "1.5"},
+			{NewColumnMeta(1, "n", 0, ColumnTypeInt), "2147483648"},
+			{NewColumnMeta(1, "n", 0, ColumnTypeBigInt), "9223372036854775808"},
+			{decimal, "100"},
+			{decimal, "1.2.3"},
+			{decimal, "-"},
+			{NewColumnMeta(1, "d", 0, ColumnTypeDate), "2023-02-29"},
+			{NewColumnMeta(1, "dt", 0, ColumnTypeDatetime), "2024-01-01 25:00:00"},
+		}
+		for _, tt := range tests {
+			// WHEN
+			_, err := tt.col.ParseValue(tt.text)
+
+			// THEN
+			assert.Error(t, err, "%s: %s", tt.col.Type, tt.text)
+			assert.Contains(t, err.Error(), "for column '"+tt.col.Name+"'")
+		}
+	})
+}
+
+func TestFormatValue(t *testing.T) {
+	t.Run("格納形式の値をテキスト表現に戻せる", func(t *testing.T) {
+		decimal := NewColumnMeta(1, "price", 0, ColumnTypeDecimal)
+		decimal.Precision = 10
+		decimal.Scale = 3
+		tests := []struct {
+			col      *ColumnMeta
+			text     string
+			expected string
+		}{
+			{NewColumnMeta(1, "s", 0, ColumnTypeString), "hello", "hello"},
+			{NewColumnMeta(1, "n", 0, ColumnTypeInt), "-42", "-42"},
+			{NewColumnMeta(1, "n", 0, ColumnTypeBigInt), "+7", "7"},
+			{decimal, "1.5", "1.500"},
+			{decimal, "-0.0005", "-0.001"},
+			{decimal, "0.0004", "0.000"},
+			{decimal, "12", "12.000"},
+			{NewColumnMeta(1, "d", 0, ColumnTypeDate), "2024-02-29", "2024-02-29"},
+			{NewColumnMeta(1, "dt", 0, ColumnTypeDatetime), "2024-02-29 13:04:05", "2024-02-29 13:04:05"},
+		}
+		for _, tt := range tests {
+			// GIVEN
+			value, err := tt.col.ParseValue(tt.text)
+			assert.NoError(t, err)
+
+			// WHEN
+			result := tt.col.FormatValue(value)
+
+			// THEN
+			assert.Equal(t, tt.expected, result)
+		}
+	})
+}
@@ -14,16 +14,26 @@ import (
 type ColumnType string
 
 const (
-	ColumnTypeString ColumnType = "string"
+	ColumnTypeString   ColumnType = "string"
+	ColumnTypeInt      ColumnType = "int"
+	ColumnTypeBigInt   ColumnType = "bigint"
+	ColumnTypeDecimal  ColumnType = "decimal"
+	ColumnTypeDate     ColumnType = "date"
+	ColumnTypeDatetime ColumnType = "datetime"
 )
 
 // ColumnMeta はカラムのメタデータを表す
 type ColumnMeta struct {
-	MetaPageId page.PageId // カラムのメタデータが格納される B+Tree のメタページID
-	FileId     page.FileId // カラムが属するテーブルの FileId
-	Name       string      // カラム名
-	Pos        uint16      // 0 から始まり連続的に増加する、テーブル内のカラムの順序位置
-	Type       ColumnType  // カラムのデータ型
+	MetaPageId    page.PageId // カラムのメタデータが格納される B+Tree のメタページID
+	FileId        page.FileId // カラムが属するテーブルの FileId
+	Name          string      // カラム名
+	Pos           uint16      // 0 から始まり連続的に増加する、テーブル内のカラムの順序位置
+	Type          ColumnType  // カラムのデータ型
+	Precision     uint8       // DECIMAL の精度 (全体の桁数)。DECIMAL 以外では 0
+	Scale         uint8       // DECIMAL のスケール (小数部の桁数)。DECIMAL 以外では 0
+	NotNull       bool        // NOT NULL 制約の有無
+	AutoIncrement bool        // AUTO_INCREMENT 属性の有無
+	Default       string      // DEFAULT 句の式の SQL 表現 (DEFAULT 句を指定していない場合は空文字)
 }
 
 func NewColumnMeta(fileId page.FileId, name string, pos uint16, columnType ColumnType) *ColumnMeta {
@@ -39,18 +49,36 @@ func NewColumnMeta(fileId page.FileId, name string, pos uint16, columnType Colum
 func (cm *ColumnMeta) Insert(bp *buffer.BufferPool) error {
 	btr := btree.NewBTree(cm.MetaPageId)
 
-	// キーフィールドをエンコード (FileId + ColName)
-	var encodedKey []byte
-	keyBuf := binary.BigEndian.AppendUint32(nil, uint32(cm.FileId))
-	encode.Encode([][]byte{keyBuf, []byte(cm.Name)}, &encodedKey)
-
-	// 非キーフィールドをエンコード (Pos, Type)
+	// 非キーフィールドをエンコード (Pos, Type, Precision + Scale, NotNull, AutoIncrement, Default)
+	// Default が空文字の場合 (DEFAULT 句を指定していない場合) は、エンコード後のバイト列に含まれない
 	var encodedNonKey []byte
 	posBuf := binary.BigEndian.AppendUint16(nil, cm.Pos)
-	encode.Encode([][]byte{posBuf, []byte(cm.Type)}, &encodedNonKey)
+	typeAttrBuf := []byte{cm.Precision, cm.Scale}
+	notNullBuf := []byte{0}
+	if cm.NotNull {
+		notNullBuf[0] = 1
+	}
+	autoIncrementBuf := []byte{0}
+	if cm.AutoIncrement {
+		autoIncrementBuf[0] = 1
+	}
+	encode.Encode([][]byte{posBuf, []byte(cm.Type), typeAttrBuf, notNullBuf, autoIncrementBuf, []byte(cm.Default)}, &encodedNonKey)
 
 	// B+Tree に挿入
-	return btr.Insert(bp, node.NewRecord(nil, encodedKey, encodedNonKey))
+	return btr.Insert(bp, node.NewRecord(nil, cm.encodeKey(), encodedNonKey))
+}
+
+// Delete はカラムメタデータを B+Tree から削除する
+func (cm *ColumnMeta) Delete(bp *buffer.BufferPool) error {
+	return btree.NewBTree(cm.MetaPageId).Delete(bp, cm.encodeKey())
+}
+
+// encodeKey は B+Tree に格納するキーフィールド (FileId + ColName) をエンコードする
+func (cm *ColumnMeta) encodeKey() []byte {
+	var encodedKey []byte
+	keyBuf := binary.BigEndian.AppendUint32(nil, uint32(cm.FileId))
+	encode.Encode([][]byte{keyBuf, []byte(cm.Name)}, &encodedKey)
+	return encodedKey
 }
 
 // loadColumnMeta は指定されたテーブルのカラムメタデータを読み込む
@@ -84,18 +112,36 @@ func loadColumnMeta(bp *buffer.BufferPool, fileId page.FileId, metaPageId page.P
 		if colFileId == fileId {
 			colName := string(keyParts[1])
 
-			// 非キーフィールドをデコード (Pos, Type)
+			// 非キーフィールドをデコード (Pos, Type, Precision + Scale, NotNull, AutoIncrement, Default)
 			var nonKeyParts [][]byte
 			encode.Decode(record.NonKeyBytes(), &nonKeyParts)
 			pos := binary.BigEndian.Uint16(nonKeyParts[0])
 			colType := ColumnType(string(nonKeyParts[1]))
 
-			cols = append(cols, &ColumnMeta{
+			colMeta := &ColumnMeta{
 				FileId: fileId,
 				Name:   colName,
 				Pos:    pos,
 				Type:   colType,
-			})
+			}
+			// 型属性を持たない (データ型導入前に作成された) カラムは文字列型として扱われる
+			if len(nonKeyParts) > 2 && len(nonKeyParts[2]) >= 2 {
+				colMeta.Precision = nonKeyParts[2][0]
+				colMeta.Scale = nonKeyParts[2][1]
+			}
+			// NOT NULL 制約を持たない (NULL 導入前に作成された) カラムは NULL を許容する
+			if len(nonKeyParts) > 3 && len(nonKeyParts[3]) > 0 {
+				colMeta.NotNull = nonKeyParts[3][0] == 1
+			}
+			// AUTO_INCREMENT 属性を持たない (AUTO_INCREMENT 導入前に作成された) カラムは自動採番しない
+			if len(nonKeyParts) > 4 && len(nonKeyParts[4]) > 0 {
+				colMeta.AutoIncrement = nonKeyParts[4][0] == 1
+			}
+			// DEFAULT 句を指定していないカラムは、値を省略すると NULL になる
+			if len(nonKeyParts) > 5 {
+				colMeta.Default = string(nonKeyParts[5])
+			}
+			cols = append(cols, colMeta)
 		}
 
 		if err := iter.Advance(bp); err != nil {
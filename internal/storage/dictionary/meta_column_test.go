@@ -130,6 +130,114 @@ func TestLoadColumnMetadata(t *testing.T) {
 		assert.Equal(t, uint16(2), result[2].Pos)
 	})
 
+	t.Run("データ型と DECIMAL の精度・スケールを読み込める", func(t *testing.T) {
+		// GIVEN
+		bp, tmpdir := InitCatalogDisk(t)
+		defer removeTmpdir(t, tmpdir)
+
+		cat, err := CreateCatalog(bp)
+		assert.NoError(t, err)
+
+		price := NewColumnMeta(1, "price", 1, ColumnTypeDecimal)
+		price.Precision = 10
+		price.Scale = 2
+		cols := []*ColumnMeta{NewColumnMeta(1, "id", 0, ColumnTypeBigInt), price}
+		for _, col := range cols {
+			col.MetaPageId = cat.ColumnMetaPageId
+			assert.NoError(t, col.Insert(bp))
+		}
+
+		// WHEN
+		result, err := loadColumnMeta(bp, 1, cat.ColumnMetaPageId)
+
+		// THEN
+		assert.NoError(t, err)
+		assert.Equal(t, 2, len(result))
+		assert.Equal(t, ColumnTypeBigInt, result[0].Type)
+		assert.Equal(t, ColumnTypeDecimal, result[1].Type)
+		assert.Equal(t, uint8(10), result[1].Precision)
+		assert.Equal(t, uint8(2), result[1].Scale)
+	})
+
+	t.Run("NOT NULL 制約を読み込める", func(t *testing.T) {
+		// GIVEN
+		bp, tmpdir := InitCatalogDisk(t)
+		defer removeTmpdir(t, tmpdir)
+
+		cat, err := CreateCatalog(bp)
+		assert.NoError(t, err)
+
+		id := NewColumnMeta(1, "id", 0, ColumnTypeInt)
+		id.NotNull = true
+		cols := []*ColumnMeta{id, NewColumnMeta(1, "name", 1, ColumnTypeString)}
+		for _, col := range cols {
+			col.MetaPageId = cat.ColumnMetaPageId
+			assert.NoError(t, col.Insert(bp))
+		}
+
+		// WHEN
+		result, err := loadColumnMeta(bp, 1, cat.ColumnMetaPageId)
+
+		// THEN
+		assert.NoError(t, err)
+		assert.Equal(t, 2, len(result))
+		assert.True(t, result[0].NotNull)
+		assert.False(t, result[1].NotNull)
+	})
+
+	t.Run("AUTO_INCREMENT 属性を読み込める", func(t *testing.T) {
+		// GIVEN
+		bp, tmpdir := InitCatalogDisk(t)
+		defer removeTmpdir(t, tmpdir)
+
+		cat, err := CreateCatalog(bp)
+		assert.NoError(t, err)
+
+		id := NewColumnMeta(1, "id", 0, ColumnTypeBigInt)
+		id.NotNull = true
+		id.AutoIncrement = true
+		cols := []*ColumnMeta{id, NewColumnMeta(1, "name", 1, ColumnTypeString)}
+		for _, col := range cols {
+			col.MetaPageId = cat.ColumnMetaPageId
+			assert.NoError(t, col.Insert(bp))
+		}
+
+		// WHEN
+		result, err := loadColumnMeta(bp, 1, cat.ColumnMetaPageId)
+
+		// THEN
+		assert.NoError(t, err)
+		assert.Equal(t, 2, len(result))
+		assert.True(t, result[0].AutoIncrement)
+		assert.False(t, result[1].AutoIncrement)
+	})
+
+	t.Run("DEFAULT 句の式を読み込める", func(t *testing.T) {
+		// GIVEN
+		bp, tmpdir := InitCatalogDisk(t)
+		defer removeTmpdir(t, tmpdir)
+
+		cat, err := CreateCatalog(bp)
+		assert.NoError(t, err)
+
+		status := NewColumnMeta(1, "status", 0, ColumnTypeString)
+		status.Default = "'active'"
+		cols := []*ColumnMeta{status, NewColumnMeta(1, "name", 1, ColumnTypeString)}
+		for _, col := range cols {
+			col.MetaPageId = cat.ColumnMetaPageId
+			assert.NoError(t, col.Insert(bp))
+		}
+
+		// WHEN
+		result, err := loadColumnMeta(bp, 1, cat.ColumnMetaPageId)
+
+		// THEN
+		assert.NoError(t, err)
+		assert.Equal(t, 2, len(result))
+		assert.Equal(t, "'active'", result[0].Default)
+		assert.Equal(t, "", result[1].Default)
+	})
+
 	t.Run("B+Tree のキー順ではなく Pos 順にソートされる", func(t *testing.T) {
 		// GIVEN
 		bp, tmpdir := InitCatalogDisk(t)
@@ -17,49 +17,127 @@ const (
 	ConstraintTypePrimaryKey ConstraintType = "PRIMARY"
 	ConstraintTypeUniqueKey  ConstraintType = "UNIQUE"
 	ConstraintTypeForeignKey ConstraintType = "FOREIGN KEY"
+	ConstraintTypeCheck      ConstraintType = "CHECK"
 )
 
+// ReferentialAction は外部キーの参照先の行を削除・更新したときの動作 (ON DELETE / ON UPDATE)
+type ReferentialAction byte
+
+const (
+	ReferentialActionRestrict ReferentialAction = iota // 参照元の行がある場合はエラーにする (デフォルト)
+	ReferentialActionCascade                           // 参照元の行も削除・更新する
+	ReferentialActionSetNull                           // 参照元の行の外部キーカラムを NULL にする
+)
+
+func (a ReferentialAction) String() string {
+	switch a {
+	case ReferentialActionCascade:
+		return "CASCADE"
+	case ReferentialActionSetNull:
+		return "SET NULL"
+	default:
+		return "RESTRICT"
+	}
+}
+
 // ConstraintMeta はカラムの制約情報を表すメタデータ
 type ConstraintMeta struct {
-	MetaPageId     page.PageId // 制約メタデータが格納される B+Tree のメタページ ID
-	FileId         page.FileId // 制約が属するテーブルの FileId
-	ColName        string      // カラム名
-	ConstraintName string      // 制約名 (主キーの場合は "PRIMARY")
-	RefTableName   string      // 参照先テーブル名 (主キー/ユニークキーの場合は空文字)
-	RefColName     string      // 参照先カラム名 (主キー/ユニークキーの場合は空文字)
+	MetaPageId     page.PageId       // 制約メタデータが格納される B+Tree のメタページ ID
+	FileId         page.FileId       // 制約が属するテーブルの FileId
+	ColName        string            // カラム名 (複合外部キーの場合は先頭のカラム名)
+	ConstraintName string            // 制約名 (主キーの場合は "PRIMARY")
+	RefTableName   string            // 参照先テーブル名 (主キー/ユニークキーの場合は空文字)
+	RefColName     string            // 参照先カラム名 (主キー/ユニークキーの場合は空文字、複合外部キーの場合は先頭のカラム名)
+	ColNames       []string          // 外部キーを構成するカラム名 (キーの順。外部キー制約以外の場合は nil)
+	RefColNames    []string          // 参照先カラム名 (ColNames と同じ順。外部キー制約以外の場合は nil)
+	CheckExpr      string            // CHECK 制約の条件式の SQL 表現 (CHECK 制約以外の場合は空文字)
+	OnDelete       ReferentialAction // 参照先の行を削除したときの動作 (外部キー制約以外の場合は RESTRICT)
+	OnUpdate       ReferentialAction // 参照先の行を更新したときの動作 (外部キー制約以外の場合は RESTRICT)
 }
 
 func NewConstraintMeta(fileId page.FileId, colName string, constraintName string, refTableName string, refColName string) *ConstraintMeta {
+	if refTableName != "" {
+		return NewForeignKeyConstraintMeta(fileId, []string{colName}, constraintName, refTableName, []string{refColName})
+	}
 	return &ConstraintMeta{
 		FileId:         fileId,
 		ColName:        colName,
 		ConstraintName: constraintName,
+	}
+}
+
+// NewForeignKeyConstraintMeta は外部キー制約のメタデータを作成する
+//
+// colNames と refColNames は同じ長さで、先頭のカラム名を ColName / RefColName に設定する
+func NewForeignKeyConstraintMeta(fileId page.FileId, colNames []string, constraintName string, refTableName string, refColNames []string) *ConstraintMeta {
+	return &ConstraintMeta{
+		FileId:         fileId,
+		ColName:        colNames[0],
+		ConstraintName: constraintName,
 		RefTableName:   refTableName,
-		RefColName:     refColName,
+		RefColName:     refColNames[0],
+		ColNames:       colNames,
+		RefColNames:    refColNames,
+	}
+}
+
+// NewCheckConstraintMeta は CHECK 制約のメタデータを作成する
+//
+// CHECK 制約は特定のカラムに属さないため、ColName は空文字になる
+func NewCheckConstraintMeta(fileId page.FileId, constraintName string, checkExpr string) *ConstraintMeta {
+	return &ConstraintMeta{
+		FileId:         fileId,
+		ConstraintName: constraintName,
+		CheckExpr:      checkExpr,
 	}
 }
 
+// IsCheck は CHECK 制約かどうかを返す
+func (cm *ConstraintMeta) IsCheck() bool {
+	return cm.CheckExpr != ""
+}
+
 // Insert は制約メタデータを B+Tree に挿入する
 func (cm *ConstraintMeta) Insert(bp *buffer.BufferPool) error {
 	btr := btree.NewBTree(cm.MetaPageId)
 
-	// キーフィールドをエンコード (FileId + ColName + ConstraintName)
-	var encodedKey []byte
-	keyBuf := binary.BigEndian.AppendUint32(nil, uint32(cm.FileId))
-	encode.Encode([][]byte{keyBuf, []byte(cm.ColName), []byte(cm.ConstraintName)}, &encodedKey)
-
 	// 非キーフィールドをエンコード
-	// 参照先の有無を示すフラグ (1 バイト) を先頭に配置する
+	// 制約の種類を示すフラグ (1 バイト) を先頭に配置する (0: PK/UK, 1: FK, 2: CHECK)
 	// memcomparable は空のバイト列をエンコードできないため、参照先がない場合 (PK/UK) はフラグのみ
+	// FK の場合は参照先の後に ON DELETE / ON UPDATE の動作 (1 バイトずつ) を配置し、
+	// 複合外部キーの場合はその後に 2 番目以降のカラム名と参照先カラム名を組にして配置する
 	var encodedNonKey []byte
-	if cm.RefTableName != "" {
-		encode.Encode([][]byte{{1}, []byte(cm.RefTableName), []byte(cm.RefColName)}, &encodedNonKey)
-	} else {
+	switch {
+	case cm.RefTableName != "":
+		actions := []byte{byte(cm.OnDelete), byte(cm.OnUpdate)}
+		parts := [][]byte{{1}, []byte(cm.RefTableName), []byte(cm.RefColName), actions}
+		for i := 1; i < len(cm.ColNames); i++ {
+			parts = append(parts, []byte(cm.ColNames[i]), []byte(cm.RefColNames[i]))
+		}
+		encode.Encode(parts, &encodedNonKey)
+	case cm.IsCheck():
+		encode.Encode([][]byte{{2}, []byte(cm.CheckExpr)}, &encodedNonKey)
+	default:
 		encode.Encode([][]byte{{0}}, &encodedNonKey)
 	}
 
 	// B+Tree に挿入
-	return btr.Insert(bp, node.NewRecord(nil, encodedKey, encodedNonKey))
+	return btr.Insert(bp, node.NewRecord(nil, cm.encodeKey(), encodedNonKey))
+}
+
+// Delete は制約メタデータを B+Tree から削除する
+func (cm *ConstraintMeta) Delete(bp *buffer.BufferPool) error {
+	return btree.NewBTree(cm.MetaPageId).Delete(bp, cm.encodeKey())
+}
+
+// encodeKey は B+Tree に格納するキーフィールド (FileId + ColName + ConstraintName) をエンコードする
+//
+// CHECK 制約は ColName が空文字のため、エンコード後のバイト列は FileId + ConstraintName になる
+func (cm *ConstraintMeta) encodeKey() []byte {
+	var encodedKey []byte
+	keyBuf := binary.BigEndian.AppendUint32(nil, uint32(cm.FileId))
+	encode.Encode([][]byte{keyBuf, []byte(cm.ColName), []byte(cm.ConstraintName)}, &encodedKey)
+	return encodedKey
 }
 
 // loadConstraintMeta は指定されたテーブルの制約メタデータを読み込む
@@ -91,25 +169,39 @@ func loadConstraintMeta(bp *buffer.BufferPool, fileId page.FileId, metaPageId pa
 
 		// 指定されたテーブルの制約のみを収集
 		if conFileId == fileId {
-			colName := string(keyParts[1])
-			constraintName := string(keyParts[2])
+			// CHECK 制約はカラム名を持たないため、キーフィールドは FileId と ConstraintName のみ
+			var colName, constraintName string
+			if len(keyParts) > 2 {
+				colName = string(keyParts[1])
+				constraintName = string(keyParts[2])
+			} else {
+				constraintName = string(keyParts[1])
+			}
 
-			// 非キーフィールドをデコード (フラグ, RefTableName, RefColName)
+			// 非キーフィールドをデコード (フラグ, RefTableName, RefColName, 参照アクション, 2 番目以降のカラムの組 または フラグ, CheckExpr)
 			var nonKeyParts [][]byte
 			encode.Decode(record.NonKeyBytes(), &nonKeyParts)
-			var refTableName, refColName string
-			if nonKeyParts[0][0] == 1 {
-				refTableName = string(nonKeyParts[1])
-				refColName = string(nonKeyParts[2])
-			}
-
-			constraints = append(constraints, &ConstraintMeta{
+			conMeta := &ConstraintMeta{
 				FileId:         fileId,
 				ColName:        colName,
 				ConstraintName: constraintName,
-				RefTableName:   refTableName,
-				RefColName:     refColName,
-			})
+			}
+			switch nonKeyParts[0][0] {
+			case 1:
+				conMeta = NewForeignKeyConstraintMeta(fileId, []string{colName}, constraintName, string(nonKeyParts[1]), []string{string(nonKeyParts[2])})
+				// 参照アクションを持たないレコード (参照アクションの導入前に作成された FK) は RESTRICT として扱う
+				if len(nonKeyParts) > 3 {
+					conMeta.OnDelete = ReferentialAction(nonKeyParts[3][0])
+					conMeta.OnUpdate = ReferentialAction(nonKeyParts[3][1])
+				}
+				for i := 4; i+1 < len(nonKeyParts); i += 2 {
+					conMeta.ColNames = append(conMeta.ColNames, string(nonKeyParts[i]))
+					conMeta.RefColNames = append(conMeta.RefColNames, string(nonKeyParts[i+1]))
+				}
+			case 2:
+				conMeta.CheckExpr = string(nonKeyParts[1])
+			}
+			constraints = append(constraints, conMeta)
 		}
 
 		if err := iter.Advance(bp); err != nil {
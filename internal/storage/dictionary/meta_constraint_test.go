@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/ren-yamanashi/minesql/internal/storage/btree"
+	"github.com/ren-yamanashi/minesql/internal/storage/btree/node"
 	"github.com/ren-yamanashi/minesql/internal/storage/encode"
 	"github.com/ren-yamanashi/minesql/internal/storage/page"
 	"github.com/stretchr/testify/assert"
@@ -207,6 +208,117 @@ func TestLoadConstraintMeta(t *testing.T) {
 		assert.Equal(t, "fk_user", result[0].ConstraintName)
 		assert.Equal(t, "users", result[0].RefTableName)
 		assert.Equal(t, "id", result[0].RefColName)
+		assert.Equal(t, []string{"user_id"}, result[0].ColNames)
+		assert.Equal(t, []string{"id"}, result[0].RefColNames)
+	})
+
+	t.Run("複合外部キーのカラム名と参照先カラム名が正しく読み込める", func(t *testing.T) {
+		// GIVEN
+		bp, tmpdir := InitCatalogDisk(t)
+		defer removeTmpdir(t, tmpdir)
+
+		cat, err := CreateCatalog(bp)
+		assert.NoError(t, err)
+
+		con := NewForeignKeyConstraintMeta(1, []string{"tenant_id", "user_id"}, "fk_user", "users", []string{"tenant_id", "id"})
+		con.OnDelete = ReferentialActionCascade
+		con.MetaPageId = cat.ConstraintMetaPageId
+		assert.NoError(t, con.Insert(bp))
+
+		// WHEN
+		result, err := loadConstraintMeta(bp, 1, cat.ConstraintMetaPageId)
+
+		// THEN
+		assert.NoError(t, err)
+		assert.Equal(t, 1, len(result))
+		assert.Equal(t, "tenant_id", result[0].ColName)
+		assert.Equal(t, "tenant_id", result[0].RefColName)
+		assert.Equal(t, []string{"tenant_id", "user_id"}, result[0].ColNames)
+		assert.Equal(t, []string{"tenant_id", "id"}, result[0].RefColNames)
+		assert.Equal(t, ReferentialActionCascade, result[0].OnDelete)
+	})
+
+	t.Run("FK 制約の参照アクションが正しく読み込める", func(t *testing.T) {
+		// GIVEN
+		bp, tmpdir := InitCatalogDisk(t)
+		defer removeTmpdir(t, tmpdir)
+
+		cat, err := CreateCatalog(bp)
+		assert.NoError(t, err)
+
+		con := NewConstraintMeta(1, "user_id", "fk_user", "users", "id")
+		con.OnDelete = ReferentialActionCascade
+		con.OnUpdate = ReferentialActionSetNull
+		con.MetaPageId = cat.ConstraintMetaPageId
+		assert.NoError(t, con.Insert(bp))
+
+		// WHEN
+		result, err := loadConstraintMeta(bp, 1, cat.ConstraintMetaPageId)
+
+		// THEN
+		assert.NoError(t, err)
+		assert.Equal(t, 1, len(result))
+		assert.Equal(t, ReferentialActionCascade, result[0].OnDelete)
+		assert.Equal(t, ReferentialActionSetNull, result[0].OnUpdate)
+	})
+
+	t.Run("参照アクションを持たない FK 制約は RESTRICT として読み込む", func(t *testing.T) {
+		// GIVEN: 参照アクションの導入前の形式で FK 制約を挿入する
+		bp, tmpdir := InitCatalogDisk(t)
+		defer removeTmpdir(t, tmpdir)
+
+		cat, err := CreateCatalog(bp)
+		assert.NoError(t, err)
+
+		con := NewConstraintMeta(1, "user_id", "fk_user", "users", "id")
+		var encodedNonKey []byte
+		encode.Encode([][]byte{{1}, []byte("users"), []byte("id")}, &encodedNonKey)
+		btr := btree.NewBTree(cat.ConstraintMetaPageId)
+		assert.NoError(t, btr.Insert(bp, node.NewRecord(nil, con.encodeKey(), encodedNonKey)))
+
+		// WHEN
+		result, err := loadConstraintMeta(bp, 1, cat.ConstraintMetaPageId)
+
+		// THEN
+		assert.NoError(t, err)
+		assert.Equal(t, 1, len(result))
+		assert.Equal(t, "users", result[0].RefTableName)
+		assert.Equal(t, []string{"id"}, result[0].RefColNames)
+		assert.Equal(t, ReferentialActionRestrict, result[0].OnDelete)
+		assert.Equal(t, ReferentialActionRestrict, result[0].OnUpdate)
+	})
+
+	t.Run("CHECK 制約の条件式が正しく読み込める", func(t *testing.T) {
+		// GIVEN
+		bp, tmpdir := InitCatalogDisk(t)
+		defer removeTmpdir(t, tmpdir)
+
+		cat, err := CreateCatalog(bp)
+		assert.NoError(t, err)
+
+		check := NewCheckConstraintMeta(1, "users_chk_1", "age >= 0")
+		check.MetaPageId = cat.ConstraintMetaPageId
+		assert.NoError(t, check.Insert(bp))
+		pk := NewConstraintMeta(1, "id", "PRIMARY", "", "")
+		pk.MetaPageId = cat.ConstraintMetaPageId
+		assert.NoError(t, pk.Insert(bp))
+
+		// WHEN
+		result, err := loadConstraintMeta(bp, 1, cat.ConstraintMetaPageId)
+
+		// THEN
+		assert.NoError(t, err)
+		assert.Equal(t, 2, len(result))
+		var checks []*ConstraintMeta
+		for _, con := range result {
+			if con.IsCheck() {
+				checks = append(checks, con)
+			}
+		}
+		assert.Equal(t, 1, len(checks))
+		assert.Equal(t, "users_chk_1", checks[0].ConstraintName)
+		assert.Equal(t, "age >= 0", checks[0].CheckExpr)
+		assert.Equal(t, "", checks[0].ColName)
 	})
 
 	t.Run("該当する制約がない場合は空のスライスを返す", func(t *testing.T) {
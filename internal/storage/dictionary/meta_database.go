@@ -0,0 +1,113 @@
+package dictionary
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/ren-yamanashi/minesql/internal/storage/btree"
+	"github.com/ren-yamanashi/minesql/internal/storage/btree/node"
+	"github.com/ren-yamanashi/minesql/internal/storage/buffer"
+	"github.com/ren-yamanashi/minesql/internal/storage/encode"
+	"github.com/ren-yamanashi/minesql/internal/storage/page"
+)
+
+// DatabaseMeta はデータベースのメタデータを表す
+//
+// データベースに属するテーブル・ビューは、データベース名で修飾した名前 (`db.table`) でカタログに登録する
+type DatabaseMeta struct {
+	MetaPageId page.PageId // データベースメタデータが格納される B+Tree のメタページ ID
+	Name       string
+}
+
+func NewDatabaseMeta(name string) *DatabaseMeta {
+	return &DatabaseMeta{Name: name}
+}
+
+// Insert はデータベースメタデータを B+Tree に挿入する
+func (dm *DatabaseMeta) Insert(bp *buffer.BufferPool) error {
+	btr := btree.NewBTree(dm.MetaPageId)
+	return btr.Insert(bp, node.NewRecord(nil, dm.encodeKey(), nil))
+}
+
+// Delete はデータベースメタデータを B+Tree から削除する
+func (dm *DatabaseMeta) Delete(bp *buffer.BufferPool) error {
+	return btree.NewBTree(dm.MetaPageId).Delete(bp, dm.encodeKey())
+}
+
+// encodeKey は B+Tree に格納するキーフィールド (Name) をエンコードする
+func (dm *DatabaseMeta) encodeKey() []byte {
+	var encodedKey []byte
+	encode.Encode([][]byte{[]byte(dm.Name)}, &encodedKey)
+	return encodedKey
+}
+
+// loadDatabaseMeta はデータベースメタデータを B+Tree から読み込む
+func loadDatabaseMeta(bp *buffer.BufferPool, metaPageId page.PageId) ([]*DatabaseMeta, error) {
+	databaseMetaTree := btree.NewBTree(metaPageId)
+	iter, err := databaseMetaTree.Search(bp, btree.SearchModeStart{})
+	if err != nil {
+		return nil, err
+	}
+
+	var databases []*DatabaseMeta
+	for {
+		record, ok := iter.Get()
+		if !ok {
+			break
+		}
+
+		// キーをデコード (Name)
+		var keyParts [][]byte
+		encode.Decode(record.KeyBytes(), &keyParts)
+
+		databases = append(databases, &DatabaseMeta{
+			MetaPageId: metaPageId,
+			Name:       string(keyParts[0]),
+		})
+
+		if err := iter.Advance(bp); err != nil {
+			return nil, err
+		}
+	}
+
+	return databases, nil
+}
+
+// QualifyName はテーブル名・ビュー名をデータベース名で修飾する
+//
+// データベース名が空の場合や、既にデータベース名で修飾されている場合はそのまま返す
+func QualifyName(database, name string) string {
+	if database == "" || strings.Contains(name, ".") {
+		return name
+	}
+	return database + "." + name
+}
+
+// SplitName はデータベース名で修飾したテーブル名・ビュー名を、データベース名と修飾しない名前に分割する
+//
+// 修飾されていない名前 (データベースに属さないテーブル) の場合、データベース名は空文字になる
+func SplitName(name string) (database, object string) {
+	if idx := strings.Index(name, "."); idx >= 0 {
+		return name[:idx], name[idx+1:]
+	}
+	return "", name
+}
+
+// ValidateDatabaseName はデータベース名として使える名前かどうかを検証する
+//
+// データベース名はデータディレクトリのサブディレクトリ名に使うため、"." やパスの区切り文字を含む名前は使えない
+func ValidateDatabaseName(name string) error {
+	if name == "" || strings.ContainsAny(name, `./\`) {
+		return fmt.Errorf("invalid database name %s", name)
+	}
+	return nil
+}
+
+// TableFilePath はテーブルのデータが格納されるファイルのパスを返す
+//
+// データベースに属するテーブル (`db.table`) は `${dataDir}/db/table.db`、それ以外は `${dataDir}/table.db` になる
+func TableFilePath(dataDir, tableName string) string {
+	database, table := SplitName(tableName)
+	return filepath.Join(dataDir, database, fmt.Sprintf("%s.db", table))
+}
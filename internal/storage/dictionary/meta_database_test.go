@@ -0,0 +1,75 @@
+package dictionary
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/ren-yamanashi/minesql/internal/storage/btree"
+	"github.com/ren-yamanashi/minesql/internal/storage/page"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDatabaseMeta(t *testing.T) {
+	t.Run("データベースメタデータを挿入・削除し、B+Tree から読み込める", func(t *testing.T) {
+		// GIVEN
+		bp, tmpdir := InitCatalogDisk(t)
+		defer removeTmpdir(t, tmpdir)
+		metaPageId, err := bp.AllocatePageId(page.FileId(0))
+		assert.NoError(t, err)
+		_, err = btree.CreateBTree(bp, metaPageId)
+		assert.NoError(t, err)
+		app := NewDatabaseMeta("app")
+		app.MetaPageId = metaPageId
+		tenant := NewDatabaseMeta("tenant1")
+		tenant.MetaPageId = metaPageId
+
+		// WHEN
+		assert.NoError(t, app.Insert(bp))
+		assert.NoError(t, tenant.Insert(bp))
+		err = app.Delete(bp)
+
+		// THEN
+		assert.NoError(t, err)
+		databases, err := loadDatabaseMeta(bp, metaPageId)
+		assert.NoError(t, err)
+		assert.Equal(t, []*DatabaseMeta{tenant}, databases)
+	})
+}
+
+func TestQualifyName(t *testing.T) {
+	t.Run("修飾されていない名前をデータベース名で修飾する", func(t *testing.T) {
+		assert.Equal(t, "app.users", QualifyName("app", "users"))
+		assert.Equal(t, "other.users", QualifyName("app", "other.users"))
+		assert.Equal(t, "users", QualifyName("", "users"))
+	})
+}
+
+func TestSplitName(t *testing.T) {
+	t.Run("データベース名と修飾しない名前に分割する", func(t *testing.T) {
+		// WHEN
+		database, table := SplitName("app.users")
+		noDatabase, noTable := SplitName("users")
+
+		// THEN
+		assert.Equal(t, "app", database)
+		assert.Equal(t, "users", table)
+		assert.Equal(t, "", noDatabase)
+		assert.Equal(t, "users", noTable)
+	})
+}
+
+func TestValidateDatabaseName(t *testing.T) {
+	t.Run("\".\" やパスの区切り文字を含む名前はエラーになる", func(t *testing.T) {
+		assert.NoError(t, ValidateDatabaseName("tenant_1"))
+		assert.EqualError(t, ValidateDatabaseName("a.b"), "invalid database name a.b")
+		assert.EqualError(t, ValidateDatabaseName("a/b"), "invalid database name a/b")
+		assert.EqualError(t, ValidateDatabaseName(""), "invalid database name ")
+	})
+}
+
+func TestTableFilePath(t *testing.T) {
+	t.Run("データベースに属するテーブルはデータベースのサブディレクトリに格納する", func(t *testing.T) {
+		assert.Equal(t, filepath.Join("data", "app", "users.db"), TableFilePath("data", "app.users"))
+		assert.Equal(t, filepath.Join("data", "users.db"), TableFilePath("data", "users"))
+	})
+}
@@ -2,6 +2,7 @@ package dictionary
 
 import (
 	"encoding/binary"
+	"slices"
 
 	"github.com/ren-yamanashi/minesql/internal/storage/btree"
 	"github.com/ren-yamanashi/minesql/internal/storage/btree/node"
@@ -22,16 +23,16 @@ type IndexMeta struct {
 	MetaPageId     page.PageId // インデックスのメタデータが格納される B+Tree のメタページID
 	FileId         page.FileId // インデックスが属するテーブルの FileId
 	Name           string      // インデックス名
-	ColName        string      // インデックスを構成するカラム名
+	ColNames       []string    // インデックスを構成するカラム名 (複合インデックスの場合はキーの順)
 	Type           IndexType   // インデックスの種類
 	DataMetaPageId page.PageId // 実データが格納される B+Tree のメタページID
 }
 
-func NewIndexMeta(fileId page.FileId, name string, colName string, indexType IndexType, dataMetaPageId page.PageId) *IndexMeta {
+func NewIndexMeta(fileId page.FileId, name string, colNames []string, indexType IndexType, dataMetaPageId page.PageId) *IndexMeta {
 	return &IndexMeta{
 		FileId:         fileId,
 		Name:           name,
-		ColName:        colName,
+		ColNames:       colNames,
 		Type:           indexType,
 		DataMetaPageId: dataMetaPageId,
 	}
@@ -41,17 +42,47 @@ func NewIndexMeta(fileId page.FileId, name string, colName string, indexType Ind
 func (im *IndexMeta) Insert(bp *buffer.BufferPool) error {
 	btr := btree.NewBTree(im.MetaPageId)
 
-	// キーフィールドをエンコード (FileId + Name)
-	var encodedKey []byte
-	keyBuf := binary.BigEndian.AppendUint32(nil, uint32(im.FileId))
-	encode.Encode([][]byte{keyBuf, []byte(im.Name)}, &encodedKey)
-
-	// 非キーフィールドをエンコード (ColName, Type, DataMetaPageId)
+	// 非キーフィールドをエンコード (Type, ColNames..., DataMetaPageId)
+	nonKey := [][]byte{[]byte(im.Type)}
+	for _, colName := range im.ColNames {
+		nonKey = append(nonKey, []byte(colName))
+	}
+	nonKey = append(nonKey, im.DataMetaPageId.ToBytes())
 	var encodedNonKey []byte
-	encode.Encode([][]byte{[]byte(im.Type), []byte(im.ColName), im.DataMetaPageId.ToBytes()}, &encodedNonKey)
+	encode.Encode(nonKey, &encodedNonKey)
 
 	// B+Tree に挿入
-	return btr.Insert(bp, node.NewRecord(nil, encodedKey, encodedNonKey))
+	return btr.Insert(bp, node.NewRecord(nil, im.encodeKey(), encodedNonKey))
+}
+
+// LeadingColName はインデックスの先頭のカラム名を返す
+func (im *IndexMeta) LeadingColName() string {
+	return im.ColNames[0]
+}
+
+// IsUniqueColumn は単一カラムの UNIQUE インデックスかどうかを判定する
+//
+// 複合 UNIQUE インデックスはカラムの組み合わせに対する制約のため、先頭のカラム単体では値が重複しうる
+func (im *IndexMeta) IsUniqueColumn() bool {
+	return im.Type == IndexTypeUnique && len(im.ColNames) == 1
+}
+
+// HasLeadingColNames は colNames がインデックスの先頭のカラム (キーの順) と一致するかを判定する
+func (im *IndexMeta) HasLeadingColNames(colNames []string) bool {
+	return len(colNames) <= len(im.ColNames) && slices.Equal(im.ColNames[:len(colNames)], colNames)
+}
+
+// Delete はインデックスメタデータを B+Tree から削除する
+func (im *IndexMeta) Delete(bp *buffer.BufferPool) error {
+	return btree.NewBTree(im.MetaPageId).Delete(bp, im.encodeKey())
+}
+
+// encodeKey は B+Tree に格納するキーフィールド (FileId + Name) をエンコードする
+func (im *IndexMeta) encodeKey() []byte {
+	var encodedKey []byte
+	keyBuf := binary.BigEndian.AppendUint32(nil, uint32(im.FileId))
+	encode.Encode([][]byte{keyBuf, []byte(im.Name)}, &encodedKey)
+	return encodedKey
 }
 
 // loadIndexMeta は指定されたテーブルのインデックスメタデータを読み込む
@@ -85,17 +116,20 @@ func loadIndexMeta(bp *buffer.BufferPool, fileId page.FileId, metaPageId page.Pa
 		if idxFileId == fileId {
 			idxName := string(keyParts[1])
 
-			// 非キーフィールドをデコード (Type, ColName, DataMetaPageId)
+			// 非キーフィールドをデコード (Type, ColNames..., DataMetaPageId)
 			var nonKeyParts [][]byte
 			encode.Decode(record.NonKeyBytes(), &nonKeyParts)
 			idxType := IndexType(string(nonKeyParts[0]))
-			colName := string(nonKeyParts[1])
-			dataMetaPageId := page.RestorePageIdFromBytes(nonKeyParts[2])
+			var colNames []string
+			for _, part := range nonKeyParts[1 : len(nonKeyParts)-1] {
+				colNames = append(colNames, string(part))
+			}
+			dataMetaPageId := page.RestorePageIdFromBytes(nonKeyParts[len(nonKeyParts)-1])
 
 			indexes = append(indexes, &IndexMeta{
 				FileId:         fileId,
 				Name:           idxName,
-				ColName:        colName,
+				ColNames:       colNames,
 				Type:           idxType,
 				DataMetaPageId: dataMetaPageId,
 			})
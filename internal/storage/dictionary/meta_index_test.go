@@ -23,7 +23,7 @@ func TestIndexMeta_Insert(t *testing.T) {
 		assert.NoError(t, err)
 
 		dataMetaPageId := page.NewPageId(page.FileId(1), 5)
-		idxMeta := NewIndexMeta(1, "idx_email", "email", IndexTypeUnique, dataMetaPageId)
+		idxMeta := NewIndexMeta(1, "idx_email", []string{"email"}, IndexTypeUnique, dataMetaPageId)
 		idxMeta.MetaPageId = metaPageId
 
 		// WHEN
@@ -64,9 +64,9 @@ func TestIndexMeta_Insert(t *testing.T) {
 		_, err = btree.CreateBTree(bp, metaPageId)
 		assert.NoError(t, err)
 
-		idx1 := NewIndexMeta(1, "idx_email", "email", IndexTypeUnique, page.NewPageId(page.FileId(1), 5))
+		idx1 := NewIndexMeta(1, "idx_email", []string{"email"}, IndexTypeUnique, page.NewPageId(page.FileId(1), 5))
 		idx1.MetaPageId = metaPageId
-		idx2 := NewIndexMeta(2, "idx_title", "title", IndexTypeUnique, page.NewPageId(page.FileId(2), 6))
+		idx2 := NewIndexMeta(2, "idx_title", []string{"title"}, IndexTypeUnique, page.NewPageId(page.FileId(2), 6))
 		idx2.MetaPageId = metaPageId
 
 		// WHEN
@@ -103,9 +103,9 @@ func TestIndexMeta_Insert(t *testing.T) {
 		_, err = btree.CreateBTree(bp, metaPageId)
 		assert.NoError(t, err)
 
-		idx1 := NewIndexMeta(1, "idx_email", "email", IndexTypeUnique, page.NewPageId(page.FileId(1), 5))
+		idx1 := NewIndexMeta(1, "idx_email", []string{"email"}, IndexTypeUnique, page.NewPageId(page.FileId(1), 5))
 		idx1.MetaPageId = metaPageId
-		idx2 := NewIndexMeta(1, "idx_username", "username", IndexTypeUnique, page.NewPageId(page.FileId(1), 6))
+		idx2 := NewIndexMeta(1, "idx_username", []string{"username"}, IndexTypeUnique, page.NewPageId(page.FileId(1), 6))
 		idx2.MetaPageId = metaPageId
 
 		// WHEN
@@ -145,8 +145,8 @@ func TestLoadIndexMeta(t *testing.T) {
 		dataMetaPageId1 := page.NewPageId(page.FileId(1), 5)
 		dataMetaPageId2 := page.NewPageId(page.FileId(1), 6)
 		indexes := []*IndexMeta{
-			NewIndexMeta(1, "idx_email", "email", IndexTypeUnique, dataMetaPageId1),
-			NewIndexMeta(1, "idx_username", "username", IndexTypeUnique, dataMetaPageId2),
+			NewIndexMeta(1, "idx_email", []string{"email"}, IndexTypeUnique, dataMetaPageId1),
+			NewIndexMeta(1, "idx_username", []string{"username"}, IndexTypeUnique, dataMetaPageId2),
 		}
 		for _, idx := range indexes {
 			idx.MetaPageId = cat.IndexMetaPageId
@@ -161,11 +161,11 @@ func TestLoadIndexMeta(t *testing.T) {
 		assert.NoError(t, err)
 		assert.Equal(t, 2, len(result))
 		assert.Equal(t, "idx_email", result[0].Name)
-		assert.Equal(t, "email", result[0].ColName)
+		assert.Equal(t, []string{"email"}, result[0].ColNames)
 		assert.Equal(t, IndexTypeUnique, result[0].Type)
 		assert.Equal(t, dataMetaPageId1, result[0].DataMetaPageId)
 		assert.Equal(t, "idx_username", result[1].Name)
-		assert.Equal(t, "username", result[1].ColName)
+		assert.Equal(t, []string{"username"}, result[1].ColNames)
 		assert.Equal(t, dataMetaPageId2, result[1].DataMetaPageId)
 	})
 
@@ -179,9 +179,9 @@ func TestLoadIndexMeta(t *testing.T) {
 
 		// テーブル 1 と テーブル 2 のインデックスを混在させて挿入
 		indexes := []*IndexMeta{
-			NewIndexMeta(1, "idx_email", "email", IndexTypeUnique, page.NewPageId(page.FileId(1), 5)),
-			NewIndexMeta(2, "idx_title", "title", IndexTypeUnique, page.NewPageId(page.FileId(2), 6)),
-			NewIndexMeta(1, "idx_username", "username", IndexTypeUnique, page.NewPageId(page.FileId(1), 7)),
+			NewIndexMeta(1, "idx_email", []string{"email"}, IndexTypeUnique, page.NewPageId(page.FileId(1), 5)),
+			NewIndexMeta(2, "idx_title", []string{"title"}, IndexTypeUnique, page.NewPageId(page.FileId(2), 6)),
+			NewIndexMeta(1, "idx_username", []string{"username"}, IndexTypeUnique, page.NewPageId(page.FileId(1), 7)),
 		}
 		for _, idx := range indexes {
 			idx.MetaPageId = cat.IndexMetaPageId
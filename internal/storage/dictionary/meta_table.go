@@ -2,6 +2,8 @@ package dictionary
 
 import (
 	"encoding/binary"
+	"fmt"
+	"slices"
 
 	"github.com/ren-yamanashi/minesql/internal/storage/btree"
 	"github.com/ren-yamanashi/minesql/internal/storage/btree/node"
@@ -54,6 +56,29 @@ func (tm *TableMeta) GetColByName(colName string) (*ColumnMeta, bool) {
 	return nil, false
 }
 
+// GetAutoIncrementCol は AUTO_INCREMENT 属性を持つカラムを取得する (テーブルに 1 つまで)
+func (tm *TableMeta) GetAutoIncrementCol() (*ColumnMeta, bool) {
+	for _, col := range tm.Cols {
+		if col.AutoIncrement {
+			return col, true
+		}
+	}
+	return nil, false
+}
+
+// GetColPositions は指定されたカラムの位置 (0 始まりの列番号) を、指定された順に取得する
+func (tm *TableMeta) GetColPositions(colNames []string) ([]uint16, error) {
+	positions := make([]uint16, len(colNames))
+	for i, colName := range colNames {
+		colMeta, ok := tm.GetColByName(colName)
+		if !ok {
+			return nil, fmt.Errorf("column %s not found in table %s", colName, tm.Name)
+		}
+		positions[i] = colMeta.Pos
+	}
+	return positions, nil
+}
+
 // GetForeignKeyConstraints はテーブルの外部キー制約を取得する
 func (tm *TableMeta) GetForeignKeyConstraints() []*ConstraintMeta {
 	var fks []*ConstraintMeta
@@ -65,25 +90,121 @@ func (tm *TableMeta) GetForeignKeyConstraints() []*ConstraintMeta {
 	return fks
 }
 
-// GetIndexByColName は指定されたカラム名で構成されるインデックスを取得する
+// GetCheckConstraints はテーブルの CHECK 制約を取得する
+func (tm *TableMeta) GetCheckConstraints() []*ConstraintMeta {
+	var checks []*ConstraintMeta
+	for _, con := range tm.Constraints {
+		if con.IsCheck() {
+			checks = append(checks, con)
+		}
+	}
+	return checks
+}
+
+// GetIndexByColName は指定されたカラムを先頭のカラムとするインデックスを取得する
+//
+// 複数ある場合は、単一カラムの UNIQUE インデックス、単一カラムのインデックス、複合インデックスの順に優先する
 func (tm *TableMeta) GetIndexByColName(colName string) (*IndexMeta, bool) {
+	indexes := tm.GetIndexesByLeadingColName(colName)
+	if len(indexes) == 0 {
+		return nil, false
+	}
+	best := indexes[0]
+	for _, idx := range indexes[1:] {
+		if indexPriority(idx) < indexPriority(best) {
+			best = idx
+		}
+	}
+	return best, true
+}
+
+// GetIndexesByLeadingColName は指定されたカラムを先頭のカラムとするインデックスをすべて取得する
+func (tm *TableMeta) GetIndexesByLeadingColName(colName string) []*IndexMeta {
+	var indexes []*IndexMeta
 	for _, idx := range tm.Indexes {
-		if idx.ColName == colName {
+		if idx.LeadingColName() == colName {
+			indexes = append(indexes, idx)
+		}
+	}
+	return indexes
+}
+
+// IsPrimaryKey は colNames がプライマリキーのカラム (キーの順) と一致するかを判定する
+func (tm *TableMeta) IsPrimaryKey(colNames []string) bool {
+	if len(colNames) != int(tm.PKCount) {
+		return false
+	}
+	for i, colName := range colNames {
+		col, ok := tm.GetColByName(colName)
+		if !ok || col.Pos != uint16(i) {
+			return false
+		}
+	}
+	return true
+}
+
+// GetUniqueIndexByColNames は colNames (キーの順) で構成される UNIQUE インデックスを取得する
+func (tm *TableMeta) GetUniqueIndexByColNames(colNames []string) (*IndexMeta, bool) {
+	for _, idx := range tm.Indexes {
+		if idx.Type == IndexTypeUnique && slices.Equal(idx.ColNames, colNames) {
 			return idx, true
 		}
 	}
 	return nil, false
 }
 
+// GetIndexByName はインデックス名からインデックスを取得する
+func (tm *TableMeta) GetIndexByName(indexName string) (*IndexMeta, bool) {
+	for _, idx := range tm.Indexes {
+		if idx.Name == indexName {
+			return idx, true
+		}
+	}
+	return nil, false
+}
+
+// indexPriority は GetIndexByColName でインデックスを選ぶ優先度を返す (小さいほど優先)
+func indexPriority(idx *IndexMeta) int {
+	switch {
+	case idx.IsUniqueColumn():
+		return 0
+	case len(idx.ColNames) == 1:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// Clone はテーブルメタデータを関連メタデータ (インデックス、カラム、制約) ごと複製する
+//
+// ALTER TABLE で、カタログが保持するメタデータを変更せずに新しいメタデータを作成するために使用する
+func (tm *TableMeta) Clone() TableMeta {
+	clone := *tm
+	clone.Cols = make([]*ColumnMeta, len(tm.Cols))
+	for i, col := range tm.Cols {
+		c := *col
+		clone.Cols[i] = &c
+	}
+	clone.Indexes = make([]*IndexMeta, len(tm.Indexes))
+	for i, idx := range tm.Indexes {
+		c := *idx
+		c.ColNames = slices.Clone(idx.ColNames)
+		clone.Indexes[i] = &c
+	}
+	clone.Constraints = make([]*ConstraintMeta, len(tm.Constraints))
+	for i, con := range tm.Constraints {
+		c := *con
+		c.ColNames = slices.Clone(con.ColNames)
+		c.RefColNames = slices.Clone(con.RefColNames)
+		clone.Constraints[i] = &c
+	}
+	return clone
+}
+
 // Insert はテーブルメタデータと関連メタデータ (インデックス、カラム、制約) を B+Tree に挿入する
 func (tm *TableMeta) Insert(bp *buffer.BufferPool) error {
 	btr := btree.NewBTree(tm.MetaPageId)
 
-	// キーフィールドをエンコード (FileId)
-	var encodedKey []byte
-	keyBuf := binary.BigEndian.AppendUint32(nil, uint32(tm.FileId))
-	encode.Encode([][]byte{keyBuf}, &encodedKey)
-
 	// 非キーフィールドをエンコード (Name, NCols, PKCount, DataMetaPageId)
 	var encodedNonKey []byte
 	nColsBuf := binary.BigEndian.AppendUint64(nil, uint64(tm.NCols))
@@ -91,7 +212,7 @@ func (tm *TableMeta) Insert(bp *buffer.BufferPool) error {
 	encode.Encode([][]byte{[]byte(tm.Name), nColsBuf, pkCountBuf, tm.DataMetaPageId.ToBytes()}, &encodedNonKey)
 
 	// テーブルメタデータを B+Tree に挿入
-	if err := btr.Insert(bp, node.NewRecord(nil, encodedKey, encodedNonKey)); err != nil {
+	if err := btr.Insert(bp, node.NewRecord(nil, tm.encodeKey(), encodedNonKey)); err != nil {
 		return err
 	}
 
@@ -119,6 +240,41 @@ func (tm *TableMeta) Insert(bp *buffer.BufferPool) error {
 	return nil
 }
 
+// Delete はテーブルメタデータと関連メタデータ (インデックス、カラム、制約) を B+Tree から削除する
+func (tm *TableMeta) Delete(bp *buffer.BufferPool) error {
+	// インデックスメタデータを削除
+	for _, indexMeta := range tm.Indexes {
+		if err := indexMeta.Delete(bp); err != nil {
+			return err
+		}
+	}
+
+	// カラムメタデータを削除
+	for _, colMeta := range tm.Cols {
+		if err := colMeta.Delete(bp); err != nil {
+			return err
+		}
+	}
+
+	// 制約メタデータを削除
+	for _, conMeta := range tm.Constraints {
+		if err := conMeta.Delete(bp); err != nil {
+			return err
+		}
+	}
+
+	// テーブルメタデータを削除
+	return btree.NewBTree(tm.MetaPageId).Delete(bp, tm.encodeKey())
+}
+
+// encodeKey は B+Tree に格納するキーフィールド (FileId) をエンコードする
+func (tm *TableMeta) encodeKey() []byte {
+	var encodedKey []byte
+	keyBuf := binary.BigEndian.AppendUint32(nil, uint32(tm.FileId))
+	encode.Encode([][]byte{keyBuf}, &encodedKey)
+	return encodedKey
+}
+
 // loadTableMeta は指定されたテーブルのメタデータを読み込む
 //
 // テーブルメタデータの B+Tree を走査して、指定されたテーブルのメタデータを読み込む
@@ -113,6 +113,34 @@ func TestGetColByName(t *testing.T) {
 	})
 }
 
+func TestGetAutoIncrementCol(t *testing.T) {
+	t.Run("AUTO_INCREMENT 属性を持つカラムを取得できる", func(t *testing.T) {
+		// GIVEN
+		id := NewColumnMeta(1, "id", 0, ColumnTypeInt)
+		id.AutoIncrement = true
+		tblMeta := &TableMeta{Cols: []*ColumnMeta{id, NewColumnMeta(1, "name", 1, ColumnTypeString)}}
+
+		// WHEN
+		col, ok := tblMeta.GetAutoIncrementCol()
+
+		// THEN
+		assert.True(t, ok)
+		assert.Equal(t, "id", col.Name)
+	})
+
+	t.Run("AUTO_INCREMENT 属性を持つカラムがない場合、false を返す", func(t *testing.T) {
+		// GIVEN
+		tblMeta := &TableMeta{Cols: []*ColumnMeta{NewColumnMeta(1, "id", 0, ColumnTypeInt)}}
+
+		// WHEN
+		col, ok := tblMeta.GetAutoIncrementCol()
+
+		// THEN
+		assert.False(t, ok)
+		assert.Nil(t, col)
+	})
+}
+
 func TestGetIndexByColName(t *testing.T) {
 	t.Run("指定したカラム名のインデックスメタデータを取得できる", func(t *testing.T) {
 		// GIVEN
@@ -121,7 +149,7 @@ func TestGetIndexByColName(t *testing.T) {
 			NewColumnMeta(1, "email", 1, ColumnTypeString),
 		}
 		idxMeta := []*IndexMeta{
-			NewIndexMeta(1, "idx_email", "email", IndexTypeUnique, page.NewPageId(page.FileId(1), 1)),
+			NewIndexMeta(1, "idx_email", []string{"email"}, IndexTypeUnique, page.NewPageId(page.FileId(1), 1)),
 		}
 		tableMeta := NewTableMeta(1, "users", 2, 1, colMeta, idxMeta, page.NewPageId(page.FileId(1), 0))
 
@@ -132,7 +160,7 @@ func TestGetIndexByColName(t *testing.T) {
 		assert.True(t, found)
 		assert.NotNil(t, idx)
 		assert.Equal(t, "idx_email", idx.Name)
-		assert.Equal(t, "email", idx.ColName)
+		assert.Equal(t, []string{"email"}, idx.ColNames)
 		assert.Equal(t, IndexTypeUnique, idx.Type)
 	})
 
@@ -144,8 +172,8 @@ func TestGetIndexByColName(t *testing.T) {
 			NewColumnMeta(1, "username", 2, ColumnTypeString),
 		}
 		idxMeta := []*IndexMeta{
-			NewIndexMeta(1, "idx_email", "email", IndexTypeUnique, page.NewPageId(page.FileId(1), 1)),
-			NewIndexMeta(1, "idx_username", "username", IndexTypeUnique, page.NewPageId(page.FileId(1), 2)),
+			NewIndexMeta(1, "idx_email", []string{"email"}, IndexTypeUnique, page.NewPageId(page.FileId(1), 1)),
+			NewIndexMeta(1, "idx_username", []string{"username"}, IndexTypeUnique, page.NewPageId(page.FileId(1), 2)),
 		}
 		tableMeta := NewTableMeta(1, "users", 3, 1, colMeta, idxMeta, page.NewPageId(page.FileId(1), 0))
 
@@ -156,7 +184,7 @@ func TestGetIndexByColName(t *testing.T) {
 		assert.True(t, found)
 		assert.NotNil(t, idx)
 		assert.Equal(t, "idx_username", idx.Name)
-		assert.Equal(t, "username", idx.ColName)
+		assert.Equal(t, []string{"username"}, idx.ColNames)
 	})
 
 	t.Run("インデックスが設定されていないカラムを指定した場合、false を返す", func(t *testing.T) {
@@ -167,7 +195,7 @@ func TestGetIndexByColName(t *testing.T) {
 			NewColumnMeta(1, "email", 2, ColumnTypeString),
 		}
 		idxMeta := []*IndexMeta{
-			NewIndexMeta(1, "idx_email", "email", IndexTypeUnique, page.NewPageId(page.FileId(1), 1)),
+			NewIndexMeta(1, "idx_email", []string{"email"}, IndexTypeUnique, page.NewPageId(page.FileId(1), 1)),
 		}
 		tableMeta := NewTableMeta(1, "users", 3, 1, colMeta, idxMeta, page.NewPageId(page.FileId(1), 0))
 
@@ -185,7 +213,7 @@ func TestGetIndexByColName(t *testing.T) {
 			NewColumnMeta(1, "id", 0, ColumnTypeString),
 		}
 		idxMeta := []*IndexMeta{
-			NewIndexMeta(1, "idx_id", "id", IndexTypeUnique, page.NewPageId(page.FileId(1), 1)),
+			NewIndexMeta(1, "idx_id", []string{"id"}, IndexTypeUnique, page.NewPageId(page.FileId(1), 1)),
 		}
 		tableMeta := NewTableMeta(1, "users", 1, 1, colMeta, idxMeta, page.NewPageId(page.FileId(1), 0))
 
@@ -213,6 +241,36 @@ func TestGetIndexByColName(t *testing.T) {
 	})
 }
 
+func TestClone(t *testing.T) {
+	t.Run("複製したメタデータを変更しても元のメタデータは変更されない", func(t *testing.T) {
+		// GIVEN
+		colMeta := []*ColumnMeta{
+			NewColumnMeta(1, "id", 0, ColumnTypeString),
+			NewColumnMeta(1, "email", 1, ColumnTypeString),
+		}
+		idxMeta := []*IndexMeta{
+			NewIndexMeta(1, "idx_email", []string{"email"}, IndexTypeUnique, page.NewPageId(page.FileId(1), 1)),
+		}
+		tableMeta := NewTableMeta(1, "users", 2, 1, colMeta, idxMeta, page.NewPageId(page.FileId(1), 0))
+		tableMeta.Constraints = []*ConstraintMeta{NewConstraintMeta(1, "email", "idx_email", "", "")}
+
+		// WHEN
+		clone := tableMeta.Clone()
+		clone.NCols = 3
+		clone.Cols[1].Pos = 2
+		clone.Cols = append(clone.Cols, NewColumnMeta(1, "name", 1, ColumnTypeString))
+		clone.Indexes[0].Name = "idx_email2"
+		clone.Constraints[0].ConstraintName = "idx_email2"
+
+		// THEN
+		assert.Equal(t, uint8(2), tableMeta.NCols)
+		assert.Equal(t, 2, len(tableMeta.Cols))
+		assert.Equal(t, uint16(1), tableMeta.Cols[1].Pos)
+		assert.Equal(t, "idx_email", tableMeta.Indexes[0].Name)
+		assert.Equal(t, "idx_email", tableMeta.Constraints[0].ConstraintName)
+	})
+}
+
 func TestTableMeta_Insert(t *testing.T) {
 	t.Run("テーブルメタデータを B+Tree に挿入できる", func(t *testing.T) {
 		// GIVEN
@@ -310,7 +368,7 @@ func TestTableMeta_Insert(t *testing.T) {
 			NewColumnMeta(fileId, "email", 1, ColumnTypeString),
 		}
 		idxMeta := []*IndexMeta{
-			NewIndexMeta(fileId, "idx_email", "email", IndexTypeUnique, indexDataPageId),
+			NewIndexMeta(fileId, "idx_email", []string{"email"}, IndexTypeUnique, indexDataPageId),
 		}
 		tableMeta := NewTableMeta(fileId, "users", 2, 1, colMeta, idxMeta, page.NewPageId(fileId, 0))
 		tableMeta.MetaPageId = cat.TableMetaPageId
@@ -331,7 +389,7 @@ func TestTableMeta_Insert(t *testing.T) {
 		assert.NoError(t, err)
 		assert.Equal(t, 1, len(indexes))
 		assert.Equal(t, "idx_email", indexes[0].Name)
-		assert.Equal(t, "email", indexes[0].ColName)
+		assert.Equal(t, []string{"email"}, indexes[0].ColNames)
 		assert.Equal(t, IndexTypeUnique, indexes[0].Type)
 	})
 
@@ -451,7 +509,7 @@ func TestLoadTableMeta(t *testing.T) {
 		}
 		idxDataPageId := page.NewPageId(page.FileId(1), 5)
 		idxMeta := []*IndexMeta{
-			NewIndexMeta(1, "idx_email", "email", IndexTypeUnique, idxDataPageId),
+			NewIndexMeta(1, "idx_email", []string{"email"}, IndexTypeUnique, idxDataPageId),
 		}
 		tableMeta := NewTableMeta(1, "users", 2, 1, colMeta, idxMeta, page.NewPageId(page.FileId(1), 0))
 		err = cat.Insert(bp, tableMeta)
@@ -465,7 +523,7 @@ func TestLoadTableMeta(t *testing.T) {
 		assert.Equal(t, 1, len(result))
 		assert.Equal(t, 1, len(result[0].Indexes))
 		assert.Equal(t, "idx_email", result[0].Indexes[0].Name)
-		assert.Equal(t, "email", result[0].Indexes[0].ColName)
+		assert.Equal(t, []string{"email"}, result[0].Indexes[0].ColNames)
 		assert.Equal(t, IndexTypeUnique, result[0].Indexes[0].Type)
 		assert.Equal(t, idxDataPageId, result[0].Indexes[0].DataMetaPageId)
 	})
@@ -0,0 +1,104 @@
+package dictionary
+
+import (
+	"github.com/ren-yamanashi/minesql/internal/storage/btree"
+	"github.com/ren-yamanashi/minesql/internal/storage/btree/node"
+	"github.com/ren-yamanashi/minesql/internal/storage/buffer"
+	"github.com/ren-yamanashi/minesql/internal/storage/encode"
+	"github.com/ren-yamanashi/minesql/internal/storage/page"
+)
+
+// ViewMeta はビューのメタデータを表す
+//
+// ビューの問い合わせは SQL 表現のまま格納し、参照されるたびにプランナーがパースして展開する
+type ViewMeta struct {
+	MetaPageId page.PageId // ビューメタデータが格納される B+Tree のメタページ ID
+	Name       string
+	Columns    []string // カラム名のリスト (nil なら問い合わせの結果セットのカラム名を使う)
+	Definition string   // 問い合わせ (SELECT 文・集合演算) の SQL 表現
+}
+
+func NewViewMeta(name string, columns []string, definition string) *ViewMeta {
+	return &ViewMeta{
+		Name:       name,
+		Columns:    columns,
+		Definition: definition,
+	}
+}
+
+// Insert はビューメタデータを B+Tree に挿入する
+func (vm *ViewMeta) Insert(bp *buffer.BufferPool) error {
+	btr := btree.NewBTree(vm.MetaPageId)
+	return btr.Insert(bp, node.NewRecord(nil, vm.encodeKey(), vm.encodeNonKey()))
+}
+
+// Update はビューメタデータを B+Tree 上で更新する
+func (vm *ViewMeta) Update(bp *buffer.BufferPool) error {
+	btr := btree.NewBTree(vm.MetaPageId)
+	return btr.Update(bp, node.NewRecord(nil, vm.encodeKey(), vm.encodeNonKey()))
+}
+
+// Delete はビューメタデータを B+Tree から削除する
+func (vm *ViewMeta) Delete(bp *buffer.BufferPool) error {
+	return btree.NewBTree(vm.MetaPageId).Delete(bp, vm.encodeKey())
+}
+
+// encodeKey は B+Tree に格納するキーフィールド (Name) をエンコードする
+func (vm *ViewMeta) encodeKey() []byte {
+	var encodedKey []byte
+	encode.Encode([][]byte{[]byte(vm.Name)}, &encodedKey)
+	return encodedKey
+}
+
+// encodeNonKey は B+Tree に格納する非キーフィールド (Definition, Columns...) をエンコードする
+func (vm *ViewMeta) encodeNonKey() []byte {
+	fields := [][]byte{[]byte(vm.Definition)}
+	for _, col := range vm.Columns {
+		fields = append(fields, []byte(col))
+	}
+	var encodedNonKey []byte
+	encode.Encode(fields, &encodedNonKey)
+	return encodedNonKey
+}
+
+// loadViewMeta はビューメタデータを B+Tree から読み込む
+func loadViewMeta(bp *buffer.BufferPool, metaPageId page.PageId) ([]*ViewMeta, error) {
+	viewMetaTree := btree.NewBTree(metaPageId)
+	iter, err := viewMetaTree.Search(bp, btree.SearchModeStart{})
+	if err != nil {
+		return nil, err
+	}
+
+	var views []*ViewMeta
+	for {
+		record, ok := iter.Get()
+		if !ok {
+			break
+		}
+
+		// キーをデコード (Name)
+		var keyParts [][]byte
+		encode.Decode(record.KeyBytes(), &keyParts)
+
+		// 非キーをデコード (Definition, Columns...)
+		var nonKeyParts [][]byte
+		encode.Decode(record.NonKeyBytes(), &nonKeyParts)
+		var columns []string
+		for _, col := range nonKeyParts[1:] {
+			columns = append(columns, string(col))
+		}
+
+		views = append(views, &ViewMeta{
+			MetaPageId: metaPageId,
+			Name:       string(keyParts[0]),
+			Columns:    columns,
+			Definition: string(nonKeyParts[0]),
+		})
+
+		if err := iter.Advance(bp); err != nil {
+			return nil, err
+		}
+	}
+
+	return views, nil
+}
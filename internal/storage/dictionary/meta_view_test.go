@@ -0,0 +1,79 @@
+package dictionary
+
+import (
+	"testing"
+
+	"github.com/ren-yamanashi/minesql/internal/storage/btree"
+	"github.com/ren-yamanashi/minesql/internal/storage/buffer"
+	"github.com/ren-yamanashi/minesql/internal/storage/page"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestViewMeta(t *testing.T) {
+	// createViewMetaBTree はビューメタデータ用の B+Tree を作成する
+	createViewMetaBTree := func(t *testing.T) (*buffer.BufferPool, page.PageId, string) {
+		bp, tmpdir := InitCatalogDisk(t)
+		metaPageId, err := bp.AllocatePageId(page.FileId(0))
+		assert.NoError(t, err)
+		_, err = btree.CreateBTree(bp, metaPageId)
+		assert.NoError(t, err)
+		return bp, metaPageId, tmpdir
+	}
+
+	t.Run("ビューメタデータを挿入し、B+Tree から読み込める", func(t *testing.T) {
+		// GIVEN
+		bp, metaPageId, tmpdir := createViewMetaBTree(t)
+		defer removeTmpdir(t, tmpdir)
+		view := NewViewMeta("adults", []string{"user_id", "user_name"}, "SELECT id, name FROM users WHERE age >= 20")
+		view.MetaPageId = metaPageId
+
+		// WHEN
+		err := view.Insert(bp)
+
+		// THEN
+		assert.NoError(t, err)
+		views, err := loadViewMeta(bp, metaPageId)
+		assert.NoError(t, err)
+		assert.Equal(t, []*ViewMeta{view}, views)
+	})
+
+	t.Run("ビューメタデータを更新できる", func(t *testing.T) {
+		// GIVEN
+		bp, metaPageId, tmpdir := createViewMetaBTree(t)
+		defer removeTmpdir(t, tmpdir)
+		view := NewViewMeta("v", []string{"a"}, "SELECT id FROM users")
+		view.MetaPageId = metaPageId
+		assert.NoError(t, view.Insert(bp))
+
+		// WHEN
+		view.Columns = nil
+		view.Definition = "SELECT id, name FROM users"
+		err := view.Update(bp)
+
+		// THEN
+		assert.NoError(t, err)
+		views, err := loadViewMeta(bp, metaPageId)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, len(views))
+		assert.Nil(t, views[0].Columns)
+		assert.Equal(t, "SELECT id, name FROM users", views[0].Definition)
+	})
+
+	t.Run("ビューメタデータを削除できる", func(t *testing.T) {
+		// GIVEN
+		bp, metaPageId, tmpdir := createViewMetaBTree(t)
+		defer removeTmpdir(t, tmpdir)
+		view := NewViewMeta("v", nil, "SELECT id FROM users")
+		view.MetaPageId = metaPageId
+		assert.NoError(t, view.Insert(bp))
+
+		// WHEN
+		err := view.Delete(bp)
+
+		// THEN
+		assert.NoError(t, err)
+		views, err := loadViewMeta(bp, metaPageId)
+		assert.NoError(t, err)
+		assert.Empty(t, views)
+	})
+}
@@ -2,7 +2,6 @@ package dictionary
 
 import (
 	"bytes"
-	"fmt"
 	"sync"
 
 	"github.com/ren-yamanashi/minesql/internal/storage/access"
@@ -137,6 +136,13 @@ func (sc *StatsCollector) IncrementDirtyCount(tableName string, count uint64) {
 	state.dirtyCount += count
 }
 
+// Invalidate はテーブルのキャッシュ済み統計情報と dirtyCount を破棄する (テーブルの削除・切り詰め時に呼ぶ)
+func (sc *StatsCollector) Invalidate(tableName string) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	delete(sc.states, tableName)
+}
+
 // shouldAnalyze は Analyze を実行すべきかを判定する
 func (sc *StatsCollector) shouldAnalyze(state *tableState) bool {
 	threshold := float64(state.lastAnalyzeRowCount) * analyzeThreshold
@@ -195,11 +201,11 @@ func buildTable(meta *TableMeta) (*access.Table, error) {
 	for _, idxMeta := range meta.Indexes {
 		isUnique := idxMeta.Type == IndexTypeUnique
 		if idxMeta.Type == IndexTypeUnique || idxMeta.Type == IndexTypeNonUnique {
-			colMeta, ok := meta.GetColByName(idxMeta.ColName)
-			if !ok {
-				return nil, fmt.Errorf("column %s not found in table %s", idxMeta.ColName, meta.Name)
+			colIdxs, err := meta.GetColPositions(idxMeta.ColNames)
+			if err != nil {
+				return nil, err
 			}
-			si := access.NewSecondaryIndex(idxMeta.Name, idxMeta.ColName, idxMeta.DataMetaPageId, colMeta.Pos, meta.PKCount, isUnique)
+			si := access.NewSecondaryIndex(idxMeta.Name, idxMeta.ColNames, idxMeta.DataMetaPageId, colIdxs, meta.PKCount, isUnique)
 			secondaryIndexes = append(secondaryIndexes, si)
 		}
 	}
@@ -220,8 +226,9 @@ func (sc *StatsCollector) analyzeIndex(tbl *access.Table) (map[string]IndexStats
 			return nil, err
 		}
 
+		// 複合インデックスの場合は、先頭のカラムの値あたりの平均マッチ行数を算出する (UNIQUE でも先頭のカラムの値は重複しうるため)
 		recPerKey := 1.0
-		if !si.Unique {
+		if !si.Unique || len(si.ColIdxs) > 1 {
 			recPerKey, err = sc.calcRecPerKey(si)
 			if err != nil {
 				return nil, err
@@ -237,9 +244,9 @@ func (sc *StatsCollector) analyzeIndex(tbl *access.Table) (map[string]IndexStats
 	return idxStats, nil
 }
 
-// calcRecPerKey は非ユニークインデックスの RecPerKey を算出する
+// calcRecPerKey は非ユニークインデックス・複合インデックスの RecPerKey を算出する
 //
-// インデックス B+Tree を先頭から走査し、active レコード数と distinct なセカンダリキー数を数える
+// インデックス B+Tree を先頭から走査し、active レコード数と distinct なセカンダリキーの先頭のカラムの値の数を数える
 func (sc *StatsCollector) calcRecPerKey(si *access.SecondaryIndex) (float64, error) {
 	indexBTree := btree.NewBTree(si.MetaPageId)
 	iter, err := indexBTree.Search(sc.bufferPool, btree.SearchModeStart{})
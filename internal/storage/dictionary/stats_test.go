@@ -444,7 +444,7 @@ func TestBuildTable(t *testing.T) {
 			NewColumnMeta(1, "email", 1, ColumnTypeString),
 		}
 		idxMeta := []*IndexMeta{
-			NewIndexMeta(1, "idx_email", "email", IndexTypeUnique, page.NewPageId(page.FileId(1), 1)),
+			NewIndexMeta(1, "idx_email", []string{"email"}, IndexTypeUnique, page.NewPageId(page.FileId(1), 1)),
 		}
 		tableMeta := NewTableMeta(1, "users", 2, 1, colMeta, idxMeta, page.NewPageId(page.FileId(1), 0))
 
@@ -457,8 +457,8 @@ func TestBuildTable(t *testing.T) {
 		assert.Equal(t, "users", tbl.Name)
 		assert.Equal(t, 1, len(tbl.SecondaryIndexes))
 		assert.Equal(t, "idx_email", tbl.SecondaryIndexes[0].Name)
-		assert.Equal(t, "email", tbl.SecondaryIndexes[0].ColName)
-		assert.Equal(t, uint16(1), tbl.SecondaryIndexes[0].ColIdx)
+		assert.Equal(t, []string{"email"}, tbl.SecondaryIndexes[0].ColNames)
+		assert.Equal(t, []uint16{1}, tbl.SecondaryIndexes[0].ColIdxs)
 		assert.Equal(t, uint8(1), tbl.SecondaryIndexes[0].PkCount)
 		assert.Equal(t, page.NewPageId(page.FileId(1), 1), tbl.SecondaryIndexes[0].MetaPageId)
 	})
@@ -471,8 +471,8 @@ func TestBuildTable(t *testing.T) {
 			NewColumnMeta(1, "username", 2, ColumnTypeString),
 		}
 		idxMeta := []*IndexMeta{
-			NewIndexMeta(1, "idx_email", "email", IndexTypeUnique, page.NewPageId(page.FileId(1), 1)),
-			NewIndexMeta(1, "idx_username", "username", IndexTypeUnique, page.NewPageId(page.FileId(1), 2)),
+			NewIndexMeta(1, "idx_email", []string{"email"}, IndexTypeUnique, page.NewPageId(page.FileId(1), 1)),
+			NewIndexMeta(1, "idx_username", []string{"username"}, IndexTypeUnique, page.NewPageId(page.FileId(1), 2)),
 		}
 		tableMeta := NewTableMeta(1, "users", 3, 1, colMeta, idxMeta, page.NewPageId(page.FileId(1), 0))
 
@@ -494,7 +494,7 @@ func TestBuildTable(t *testing.T) {
 			NewColumnMeta(1, "name", 1, ColumnTypeString),
 		}
 		idxMeta := []*IndexMeta{
-			NewIndexMeta(1, "idx_email", "email", IndexTypeUnique, page.NewPageId(page.FileId(1), 1)),
+			NewIndexMeta(1, "idx_email", []string{"email"}, IndexTypeUnique, page.NewPageId(page.FileId(1), 1)),
 		}
 		tableMeta := NewTableMeta(1, "users", 2, 1, colMeta, idxMeta, page.NewPageId(page.FileId(1), 0))
 
@@ -514,7 +514,7 @@ func TestBuildTable(t *testing.T) {
 			NewColumnMeta(1, "category", 1, ColumnTypeString),
 		}
 		idxMeta := []*IndexMeta{
-			NewIndexMeta(1, "idx_category", "category", IndexTypeNonUnique, page.NewPageId(page.FileId(1), 1)),
+			NewIndexMeta(1, "idx_category", []string{"category"}, IndexTypeNonUnique, page.NewPageId(page.FileId(1), 1)),
 		}
 		tableMeta := NewTableMeta(1, "items", 2, 1, colMeta, idxMeta, page.NewPageId(page.FileId(1), 0))
 
@@ -537,8 +537,8 @@ func TestBuildTable(t *testing.T) {
 			NewColumnMeta(1, "category", 2, ColumnTypeString),
 		}
 		idxMeta := []*IndexMeta{
-			NewIndexMeta(1, "idx_email", "email", IndexTypeUnique, page.NewPageId(page.FileId(1), 1)),
-			NewIndexMeta(1, "idx_category", "category", IndexTypeNonUnique, page.NewPageId(page.FileId(1), 2)),
+			NewIndexMeta(1, "idx_email", []string{"email"}, IndexTypeUnique, page.NewPageId(page.FileId(1), 1)),
+			NewIndexMeta(1, "idx_category", []string{"category"}, IndexTypeNonUnique, page.NewPageId(page.FileId(1), 2)),
 		}
 		tableMeta := NewTableMeta(1, "items", 3, 1, colMeta, idxMeta, page.NewPageId(page.FileId(1), 0))
 
@@ -633,6 +633,29 @@ func TestGetOrAnalyze(t *testing.T) {
 	})
 }
 
+func TestInvalidate(t *testing.T) {
+	t.Run("キャッシュを破棄すると次の GetOrAnalyze で再 Analyze が実行される", func(t *testing.T) {
+		// GIVEN: 3 レコードで GetOrAnalyze 済み
+		env := setupStatsTable(t)
+		sc := NewStatsCollector(env.bp)
+		meta, ok := env.catalog.GetTableMetaByName("products")
+		assert.True(t, ok)
+		_, err := sc.GetOrAnalyze(meta)
+		assert.NoError(t, err)
+		tbl := env.tables["products"]
+		err = tbl.Insert(env.bp, 0, lock.NewManager(5000), [][]byte{[]byte("4"), []byte("Donut"), []byte("Snack")})
+		assert.NoError(t, err)
+
+		// WHEN: dirty_count は加算せずにキャッシュを破棄する
+		sc.Invalidate("products")
+		result, err := sc.GetOrAnalyze(meta)
+
+		// THEN
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(4), result.RecordCount)
+	})
+}
+
 // testEnv はテスト用の環境を保持する
 type testEnv struct {
 	bp      *buffer.BufferPool
@@ -700,7 +723,7 @@ func createTable(t *testing.T, env *testEnv, tableName string, pkCount uint8, in
 	for i, idx := range indexes {
 		indexMetaPageId, err := env.bp.AllocatePageId(fileId)
 		assert.NoError(t, err)
-		si := access.NewSecondaryIndex(idx.name, idx.colName, indexMetaPageId, idx.secondaryKey, pkCount, idx.unique)
+		si := access.NewSecondaryIndex(idx.name, []string{idx.colName}, indexMetaPageId, []uint16{idx.secondaryKey}, pkCount, idx.unique)
 		err = si.Create(env.bp)
 		assert.NoError(t, err)
 		secondaryIndexes[i] = si
@@ -718,7 +741,7 @@ func createTable(t *testing.T, env *testEnv, tableName string, pkCount uint8, in
 		if indexes[i].unique {
 			idxType = IndexTypeUnique
 		}
-		idxMeta[i] = NewIndexMeta(fileId, si.Name, si.ColName, idxType, si.MetaPageId)
+		idxMeta[i] = NewIndexMeta(fileId, si.Name, si.ColNames, idxType, si.MetaPageId)
 	}
 
 	// カラムのメタデータを作成
@@ -0,0 +1,21 @@
+package encode
+
+import "encoding/binary"
+
+// 符号付き整数をエンコードした際のバイト長
+const INT_SIZE = 8
+
+// EncodeInt64 は符号付き整数を順序保存形式 (8 バイトの big endian + 符号ビット反転) にエンコードする
+//
+// 符号ビットを反転することで、負の値が正の値より小さいバイト列になり、
+// バイト列の辞書順比較 (bytes.Compare) と数値の大小関係が一致する
+func EncodeInt64(v int64) []byte {
+	buf := make([]byte, INT_SIZE)
+	binary.BigEndian.PutUint64(buf, uint64(v)^(1<<63))
+	return buf
+}
+
+// DecodeInt64 は EncodeInt64 でエンコードされたバイト列を符号付き整数にデコードする
+func DecodeInt64(src []byte) int64 {
+	return int64(binary.BigEndian.Uint64(src) ^ (1 << 63))
+}
@@ -0,0 +1,48 @@
+package encode
+
+import (
+	"bytes"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncodeInt64(t *testing.T) {
+	t.Run("エンコード後のバイト列の辞書順が数値の大小関係と一致する", func(t *testing.T) {
+		// GIVEN
+		values := []int64{math.MinInt64, -100, -1, 0, 1, 9, 10, 100, math.MaxInt64}
+
+		// WHEN & THEN
+		for i := 1; i < len(values); i++ {
+			prev := EncodeInt64(values[i-1])
+			curr := EncodeInt64(values[i])
+			assert.Equal(t, -1, bytes.Compare(prev, curr), "%d < %d", values[i-1], values[i])
+		}
+	})
+
+	t.Run("memcomparable でエンコードしても順序が保たれる", func(t *testing.T) {
+		// GIVEN
+		var neg, pos []byte
+		Encode([][]byte{EncodeInt64(-3)}, &neg)
+		Encode([][]byte{EncodeInt64(2)}, &pos)
+
+		// WHEN
+		result := bytes.Compare(neg, pos)
+
+		// THEN
+		assert.Equal(t, -1, result)
+	})
+}
+
+func TestDecodeInt64(t *testing.T) {
+	t.Run("エンコードした値を元に戻せる", func(t *testing.T) {
+		for _, v := range []int64{math.MinInt64, -42, 0, 42, math.MaxInt64} {
+			// WHEN
+			decoded := DecodeInt64(EncodeInt64(v))
+
+			// THEN
+			assert.Equal(t, v, decoded)
+		}
+	})
+}
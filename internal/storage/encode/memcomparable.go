@@ -58,6 +58,46 @@ func DecodeFirstN(src []byte, n int) (decoded [][]byte, rest []byte) {
 	return decoded, rest
 }
 
+// NULL かどうかを表すフラグ (EncodeNullable で各要素の先頭に付ける)
+//
+// NULL のフラグを小さくすることで、NULL は NULL 以外のどの値 (空のバイト列を含む) よりも前に並ぶ
+const (
+	NULL_FLAG     byte = 0
+	NOT_NULL_FLAG byte = 1
+)
+
+// EncodeNullable は NULL (nil) と空のバイト列を区別して、複数のバイト列を連結してエンコードする
+//
+// 各要素の先頭に NULL かどうかを表すフラグを付けてから Encode でエンコードする (NULL の要素はフラグのみ)
+//   - elements: エンコード対象のバイト列のスライス (nil の要素は NULL)
+//   - dest: エンコード結果の格納先のポインタ
+func EncodeNullable(elements [][]byte, dest *[]byte) {
+	flagged := make([][]byte, len(elements))
+	for i, element := range elements {
+		if element == nil {
+			flagged[i] = []byte{NULL_FLAG}
+			continue
+		}
+		flagged[i] = append([]byte{NOT_NULL_FLAG}, element...)
+	}
+	Encode(flagged, dest)
+}
+
+// DecodeNullableFirstN は EncodeNullable でエンコードされたバイト列の先頭 n カラムをデコードし、残りのエンコード済みバイト列を返す
+//
+// NULL のカラムは nil にデコードする
+func DecodeNullableFirstN(src []byte, n int) (decoded [][]byte, rest []byte) {
+	flagged, rest := DecodeFirstN(src, n)
+	for _, element := range flagged {
+		if len(element) == 0 || element[0] == NULL_FLAG {
+			decoded = append(decoded, nil)
+			continue
+		}
+		decoded = append(decoded, element[1:])
+	}
+	return decoded, rest
+}
+
 // encodedSize はエンコード後のサイズを計測する
 //
 // size: エンコード前のバイト列のサイズ
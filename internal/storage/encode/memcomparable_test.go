@@ -167,6 +167,56 @@ func TestDecodeFirstN(t *testing.T) {
 	})
 }
 
+func TestEncodeNullable(t *testing.T) {
+	t.Run("NULL と空のバイト列を区別してエンコード・デコードできる", func(t *testing.T) {
+		// GIVEN
+		var encoded []byte
+		EncodeNullable([][]byte{nil, {}, []byte("abc")}, &encoded)
+		Encode([][]byte{[]byte("pk")}, &encoded)
+
+		// WHEN
+		decoded, rest := DecodeNullableFirstN(encoded, 3)
+
+		// THEN
+		assert.Equal(t, 3, len(decoded))
+		assert.Nil(t, decoded[0])
+		assert.NotNil(t, decoded[1])
+		assert.Equal(t, 0, len(decoded[1]))
+		assert.Equal(t, []byte("abc"), decoded[2])
+
+		var restDecoded [][]byte
+		Decode(rest, &restDecoded)
+		assert.Equal(t, [][]byte{[]byte("pk")}, restDecoded)
+	})
+
+	t.Run("NULL は NULL 以外のどの値よりも前に並び、NULL 以外の値はソート順が保たれる", func(t *testing.T) {
+		testCases := []struct {
+			name     string
+			a, b     []byte
+			expected int // -1: a < b, 0: a == b, 1: a > b
+		}{
+			{"NULL < 空文字列", nil, []byte(""), -1},
+			{"NULL < 0x00", nil, []byte{0}, -1},
+			{"NULL == NULL", nil, nil, 0},
+			{"空文字列 < a", []byte(""), []byte("a"), -1},
+			{"a < b", []byte("apple"), []byte("banana"), -1},
+			{"複数ブロックにまたがる", []byte("helloworld456"), []byte("helloworld123"), 1},
+		}
+
+		for _, tc := range testCases {
+			t.Run(tc.name, func(t *testing.T) {
+				// WHEN
+				var encodedA, encodedB []byte
+				EncodeNullable([][]byte{tc.a}, &encodedA)
+				EncodeNullable([][]byte{tc.b}, &encodedB)
+
+				// THEN
+				assert.Equal(t, tc.expected, sign(bytes.Compare(encodedA, encodedB)))
+			})
+		}
+	})
+}
+
 func TestEncodedSize(t *testing.T) {
 	t.Run("エンコード後のサイズが正しく計算できる", func(t *testing.T) {
 		// GIVEN
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"slices"
 
 	"github.com/ncw/directio"
 	"github.com/ren-yamanashi/minesql/internal/storage/page"
@@ -11,9 +12,10 @@ import (
 
 // Disk はディスク上のヒープファイルを管理する
 type Disk struct {
-	fileId     page.FileId // このディスクの FileId
-	heapFile   *os.File    // ヒープファイルのファイルディスクリプタ
-	nextPageId page.PageId // 次に採番するページ ID
+	fileId      page.FileId   // このディスクの FileId
+	heapFile    *os.File      // ヒープファイルのファイルディスクリプタ
+	nextPageId  page.PageId   // 次に採番するページ ID
+	freePageIds []page.PageId // 解放されたページ ID (採番時に優先して再利用する)
 }
 
 // NewDisk は指定されたパスのヒープファイルを開き、Disk を生成する (ファイルが存在しない場合は新規作成する)
@@ -41,12 +43,26 @@ func NewDisk(fileId page.FileId, path string) (*Disk, error) {
 }
 
 // AllocatePage は新しいページ ID を採番する
+//
+// 解放されたページ ID がある場合は、それを再利用する
 func (disk *Disk) AllocatePage() page.PageId {
+	if n := len(disk.freePageIds); n > 0 {
+		id := disk.freePageIds[n-1]
+		disk.freePageIds = disk.freePageIds[:n-1]
+		return id
+	}
 	id := disk.nextPageId
 	disk.nextPageId = page.NewPageId(disk.fileId, disk.nextPageId.PageNumber+1)
 	return id
 }
 
+// FreePage は使用しなくなったページ ID を解放し、以降の採番で再利用できるようにする
+//
+// 解放したページ ID はメモリ上でのみ管理するため、再起動後は再利用されない
+func (disk *Disk) FreePage(id page.PageId) {
+	disk.freePageIds = append(disk.freePageIds, id)
+}
+
 // ReadPageData は指定されたページ ID のページデータを data に読み込む (読み込んだデータは data に格納される)
 //
 // data の長さは PageSize と等しい必要がある
@@ -90,6 +106,16 @@ func (disk *Disk) Sync() error {
 	return disk.heapFile.Sync()
 }
 
+// Truncate はヒープファイルを先頭から nPages ページ分に切り詰め、以降のページ ID を nPages から採番し直す
+func (disk *Disk) Truncate(nPages page.PageNumber) error {
+	if err := disk.heapFile.Truncate(int64(page.PageSize * uint64(nPages))); err != nil {
+		return err
+	}
+	disk.nextPageId = page.NewPageId(disk.fileId, nPages)
+	disk.freePageIds = slices.DeleteFunc(disk.freePageIds, func(id page.PageId) bool { return id.PageNumber >= nPages })
+	return nil
+}
+
 // Close はヒープファイルのファイルディスクリプタを閉じる
 func (disk *Disk) Close() error {
 	return disk.heapFile.Close()
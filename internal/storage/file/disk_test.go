@@ -80,6 +80,27 @@ func TestAllocatePage(t *testing.T) {
 		assert.Equal(t, page.NewPageId(fileId, page.PageNumber(2)), pageId3)
 		assert.Equal(t, page.NewPageId(fileId, page.PageNumber(3)), disk.nextPageId)
 	})
+
+	t.Run("解放したページ ID を優先して再利用する", func(t *testing.T) {
+		// GIVEN
+		tmpDir := t.TempDir()
+		dbPath := filepath.Join(tmpDir, "test.db")
+		fileId := page.FileId(0)
+		disk, err := NewDisk(fileId, dbPath)
+		assert.NoError(t, err)
+		disk.AllocatePage()
+		pageId := disk.AllocatePage()
+		disk.AllocatePage()
+
+		// WHEN
+		disk.FreePage(pageId)
+		reused := disk.AllocatePage()
+		next := disk.AllocatePage()
+
+		// THEN
+		assert.Equal(t, pageId, reused)
+		assert.Equal(t, page.NewPageId(fileId, page.PageNumber(3)), next)
+	})
 }
 
 func TestReadPageData(t *testing.T) {
@@ -239,6 +260,61 @@ func TestSync(t *testing.T) {
 	})
 }
 
+func TestTruncate(t *testing.T) {
+	t.Run("指定したページ数に切り詰め、以降のページ ID を採番し直す", func(t *testing.T) {
+		// GIVEN: 3 ページ分のデータを書き込んだファイル
+		tmpDir := t.TempDir()
+		dbPath := filepath.Join(tmpDir, "test.db")
+		fileId := page.FileId(1)
+		disk, err := NewDisk(fileId, dbPath)
+		assert.NoError(t, err)
+		for range 3 {
+			err = disk.WritePageData(disk.AllocatePage(), createDataBuffer())
+			assert.NoError(t, err)
+		}
+
+		// WHEN
+		err = disk.Truncate(page.PageNumber(1))
+
+		// THEN
+		assert.NoError(t, err)
+		assert.Equal(t, page.NewPageId(fileId, page.PageNumber(1)), disk.AllocatePage())
+
+		// 切り詰めたページは読み込めない
+		readData := directio.AlignedBlock(directio.BlockSize)
+		err = disk.ReadPageData(page.NewPageId(fileId, page.PageNumber(2)), readData)
+		assert.Error(t, err)
+
+		// 再オープンしてもファイルサイズに基づいて採番される
+		reopened, err := NewDisk(fileId, dbPath)
+		assert.NoError(t, err)
+		assert.Equal(t, page.NewPageId(fileId, page.PageNumber(1)), reopened.nextPageId)
+	})
+
+	t.Run("切り詰めたページの解放済みページ ID は再利用しない", func(t *testing.T) {
+		// GIVEN: 3 ページ分のページ ID を採番し、2 ページ目以降を解放したディスク
+		tmpDir := t.TempDir()
+		dbPath := filepath.Join(tmpDir, "test.db")
+		fileId := page.FileId(1)
+		disk, err := NewDisk(fileId, dbPath)
+		assert.NoError(t, err)
+		for range 3 {
+			disk.AllocatePage()
+		}
+		disk.FreePage(page.NewPageId(fileId, page.PageNumber(1)))
+		disk.FreePage(page.NewPageId(fileId, page.PageNumber(2)))
+
+		// WHEN
+		err = disk.Truncate(page.PageNumber(2))
+
+		// THEN: 切り詰め後も残るページ ID のみ再利用する
+		assert.NoError(t, err)
+		assert.Equal(t, page.NewPageId(fileId, page.PageNumber(1)), disk.AllocatePage())
+		assert.Equal(t, page.NewPageId(fileId, page.PageNumber(2)), disk.AllocatePage())
+		assert.Equal(t, page.NewPageId(fileId, page.PageNumber(3)), disk.AllocatePage())
+	})
+}
+
 func TestClose(t *testing.T) {
 	t.Run("Close が正常に実行できる", func(t *testing.T) {
 		// GIVEN
@@ -5,8 +5,10 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
 
 	"github.com/ren-yamanashi/minesql/internal/storage/access"
@@ -22,13 +24,29 @@ import (
 	"github.com/ren-yamanashi/minesql/internal/storage/recovery"
 )
 
-const ColumnTypeString = dictionary.ColumnTypeString
+const (
+	ColumnTypeString   = dictionary.ColumnTypeString
+	ColumnTypeInt      = dictionary.ColumnTypeInt
+	ColumnTypeBigInt   = dictionary.ColumnTypeBigInt
+	ColumnTypeDecimal  = dictionary.ColumnTypeDecimal
+	ColumnTypeDate     = dictionary.ColumnTypeDate
+	ColumnTypeDatetime = dictionary.ColumnTypeDatetime
+)
+
+const (
+	ReferentialActionRestrict = dictionary.ReferentialActionRestrict
+	ReferentialActionCascade  = dictionary.ReferentialActionCascade
+	ReferentialActionSetNull  = dictionary.ReferentialActionSetNull
+)
 
 type TrxId = lock.TrxId
 type UndoManager = access.UndoManager
 type TableMetadata = dictionary.TableMeta
 type IndexMetadata = dictionary.IndexMeta
+type ColumnMetadata = dictionary.ColumnMeta
+type ViewMetadata = dictionary.ViewMeta
 type ColumnType = dictionary.ColumnType
+type ReferentialAction = dictionary.ReferentialAction
 type TableStatistics = dictionary.TableStats
 type IndexStatistics = dictionary.IndexStats
 type ColumnStatistics = dictionary.ColumnStats
@@ -47,9 +65,12 @@ type Handler struct {
 	ACL            *acl.ACL
 	undoLog        *access.UndoManager
 	redoLog        *log.RedoLog
+	ddlLog         *log.DDLLog
 	trxManager     *access.TrxManager
 	pageCleaner    *buffer.PageCleaner
 	purgeThread    *access.PurgeThread
+	rowLogs        *access.RowLogRegistry // オンライン作成中のインデックスの行ログ
+	autoIncrements *autoIncrementCounters // テーブルごとの AUTO_INCREMENT の採番状況
 	baseDirectory  string
 }
 
@@ -128,7 +149,7 @@ func (h *Handler) Shutdown() error {
 
 // RegisterDmToBp は BufferPool に Disk を登録する
 func (h *Handler) RegisterDmToBp(fileId page.FileId, tableName string) error {
-	path := filepath.Join(h.baseDirectory, fmt.Sprintf("%s.db", tableName))
+	path := dictionary.TableFilePath(h.baseDirectory, tableName)
 	dm, err := file.NewDisk(fileId, path)
 	if err != nil {
 		return err
@@ -145,6 +166,11 @@ func newHandler() (*Handler, error) {
 		return nil, err
 	}
 
+	// 異常終了などで残った Executor の一時ファイルを削除
+	if err := removeTempFiles(dataDir); err != nil {
+		return nil, err
+	}
+
 	// REDO ログを初期化
 	redoLog, err := log.NewRedoLog(dataDir)
 	if err != nil {
@@ -176,6 +202,15 @@ func newHandler() (*Handler, error) {
 		}
 	}
 
+	// 実行途中の DROP TABLE / TRUNCATE TABLE があれば最後まで実行する
+	ddlLog, err := log.NewDDLLog(dataDir)
+	if err != nil {
+		return nil, err
+	}
+	if err := rec.ResumeDDL(ddlLog, dataDir); err != nil {
+		return nil, fmt.Errorf("crash recovery failed: %w", err)
+	}
+
 	// ロックマネージャを初期化
 	lockMgr := lock.NewManager(config.GetLockWaitTimeout())
 
@@ -193,9 +228,16 @@ func newHandler() (*Handler, error) {
 	}
 	trxManager.SetNextTrxId(maxTrxId + 1)
 
+	// 既存レコードの最大値に基づいて AUTO_INCREMENT の採番を復元
+	maxAutoIncrements, err := findMaxAutoIncrements(bp, catalog)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find max auto-increment values: %w", err)
+	}
+
 	// パージスレッドを初期化・起動
+	rowLogs := access.NewRowLogRegistry()
 	pt := access.NewPurgeThread(bp, trxManager, undoLog, lockMgr, func() []*access.Table {
-		return buildAllTables(catalog, undoLog, redoLog)
+		return buildAllTables(catalog, undoLog, redoLog, rowLogs)
 	})
 	pt.Start()
 
@@ -206,13 +248,36 @@ func newHandler() (*Handler, error) {
 		StatsCollector: dictionary.NewStatsCollector(bp),
 		undoLog:        undoLog,
 		redoLog:        redoLog,
+		ddlLog:         ddlLog,
 		trxManager:     trxManager,
 		pageCleaner:    pc,
 		purgeThread:    pt,
+		rowLogs:        rowLogs,
+		autoIncrements: newAutoIncrementCounters(maxAutoIncrements),
 		baseDirectory:  dataDir,
 	}, nil
 }
 
+// removeTempFiles はデータディレクトリに残っている Executor の一時ファイル (外部ソートのランやハッシュ結合の分割など) を削除する
+//
+// 一時ファイルは Executor を閉じる際に削除するが、実行中に異常終了した場合はデータディレクトリに残る
+func removeTempFiles(dataDir string) error {
+	entries, err := os.ReadDir(dataDir)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, "minesql-") || !strings.HasSuffix(name, ".tmp") {
+			continue
+		}
+		if err := os.Remove(filepath.Join(dataDir, name)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+	}
+	return nil
+}
+
 // initCatalog はカタログを初期化する
 func initCatalog(baseDir string, bp *buffer.BufferPool) (*dictionary.Catalog, error) {
 	fileId := page.FileId(0)
@@ -282,7 +347,7 @@ func registerTableDisks(cat *dictionary.Catalog, baseDir string, bp *buffer.Buff
 	for _, tableMeta := range tables {
 		fileId := tableMeta.DataMetaPageId.FileId
 		tableName := tableMeta.Name
-		path := filepath.Join(baseDir, fmt.Sprintf("%s.db", tableName))
+		path := dictionary.TableFilePath(baseDir, tableName)
 
 		// Disk を作成して登録
 		dm, err := file.NewDisk(fileId, path)
@@ -300,7 +365,7 @@ func registerTableDisks(cat *dictionary.Catalog, baseDir string, bp *buffer.Buff
 // nextTrxId が過去のコミット済み trxId 以下だと、ReadView が過去のレコードを
 // 不可視と判定してしまうバグを防ぐ。
 func findMaxTrxId(bp *buffer.BufferPool, catalog *dictionary.Catalog, undoLog *access.UndoManager, redoLog *log.RedoLog) (lock.TrxId, error) {
-	tables := buildAllTables(catalog, undoLog, redoLog)
+	tables := buildAllTables(catalog, undoLog, redoLog, nil)
 	var maxTrxId lock.TrxId
 
 	for _, tbl := range tables {
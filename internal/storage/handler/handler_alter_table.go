@@ -0,0 +1,259 @@
+package handler
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/ren-yamanashi/minesql/internal/storage/access"
+	"github.com/ren-yamanashi/minesql/internal/storage/buffer"
+	"github.com/ren-yamanashi/minesql/internal/storage/dictionary"
+	"github.com/ren-yamanashi/minesql/internal/storage/encode"
+	"github.com/ren-yamanashi/minesql/internal/storage/page"
+)
+
+// AddColumn はテーブルの末尾にカラムを追加する
+//
+// テーブルを新しい B+Tree に作り直し、既存の行の追加したカラムは DEFAULT 句の値 (DEFAULT 句がない場合は NULL) にする。
+// DEFAULT 句の値が NULL の NOT NULL のカラムは空のテーブルにのみ追加できる
+func (h *Handler) AddColumn(trxId TrxId, tableName string, colParam CreateColumnParam) error {
+	return h.alterTable(trxId, tableName, func(tblMeta *dictionary.TableMeta) error {
+		if _, ok := tblMeta.GetColByName(colParam.Name); ok {
+			return fmt.Errorf("duplicate column name: %s", colParam.Name)
+		}
+
+		dataMetaPageId, err := h.rebuildTable(tblMeta, func(columns [][]byte) ([][]byte, error) {
+			if colParam.NotNull && colParam.DefaultValue == nil {
+				return nil, fmt.Errorf("cannot add NOT NULL column %s: table %s is not empty", colParam.Name, tableName)
+			}
+			return append(columns, colParam.DefaultValue), nil
+		})
+		if err != nil {
+			return err
+		}
+
+		colMeta := dictionary.NewColumnMeta(tblMeta.FileId, colParam.Name, uint16(len(tblMeta.Cols)), colParam.Type)
+		colMeta.Precision = colParam.Precision
+		colMeta.Scale = colParam.Scale
+		colMeta.NotNull = colParam.NotNull
+		colMeta.Default = colParam.Default
+		tblMeta.Cols = append(tblMeta.Cols, colMeta)
+		tblMeta.NCols++
+		tblMeta.DataMetaPageId = dataMetaPageId
+		return nil
+	})
+}
+
+// DropColumn はテーブルからカラムを削除する
+//
+// テーブルを新しい B+Tree に作り直し、カラムのインデックスと UNIQUE 制約も削除する。
+// プライマリキーのカラムと、外部キーで使用されているカラムは削除できない
+func (h *Handler) DropColumn(trxId TrxId, tableName string, colName string) error {
+	return h.alterTable(trxId, tableName, func(tblMeta *dictionary.TableMeta) error {
+		colMeta, ok := tblMeta.GetColByName(colName)
+		if !ok {
+			return fmt.Errorf("column %s not found in table %s", colName, tableName)
+		}
+		if colMeta.Pos < uint16(tblMeta.PKCount) {
+			return fmt.Errorf("cannot drop column %s: primary key column", colName)
+		}
+		for _, fk := range tblMeta.GetForeignKeyConstraints() {
+			if slices.Contains(fk.ColNames, colName) {
+				return fmt.Errorf("cannot drop column %s: used by foreign key %s", colName, fk.ConstraintName)
+			}
+		}
+		for _, fk := range h.Catalog.GetForeignKeysReferencingTable(tableName) {
+			if slices.Contains(fk.Constraint.RefColNames, colName) {
+				return fmt.Errorf("cannot drop column %s: referenced by foreign key %s on table %s", colName, fk.Constraint.ConstraintName, fk.TableName)
+			}
+		}
+		for _, idx := range tblMeta.Indexes {
+			if len(idx.ColNames) > 1 && slices.Contains(idx.ColNames, colName) {
+				return fmt.Errorf("cannot drop column %s: used by composite index %s", colName, idx.Name)
+			}
+		}
+
+		pos := colMeta.Pos
+		dataMetaPageId, err := h.rebuildTable(tblMeta, func(columns [][]byte) ([][]byte, error) {
+			return slices.Delete(columns, int(pos), int(pos)+1), nil
+		})
+		if err != nil {
+			return err
+		}
+
+		// カラムを削除し、後ろのカラムの位置を詰める
+		tblMeta.Cols = slices.DeleteFunc(tblMeta.Cols, func(col *dictionary.ColumnMeta) bool { return col.Name == colName })
+		for _, col := range tblMeta.Cols {
+			if col.Pos > pos {
+				col.Pos--
+			}
+		}
+		tblMeta.NCols--
+		tblMeta.DataMetaPageId = dataMetaPageId
+
+		// カラムのインデックスと UNIQUE 制約を削除する (外部キーで使用されておらず、複合インデックスに含まれないことは確認済み)
+		tblMeta.Indexes = slices.DeleteFunc(tblMeta.Indexes, func(idx *dictionary.IndexMeta) bool { return idx.LeadingColName() == colName })
+		tblMeta.Constraints = slices.DeleteFunc(tblMeta.Constraints, func(con *dictionary.ConstraintMeta) bool { return con.ColName == colName })
+		return nil
+	})
+}
+
+// AddForeignKey はテーブルに外部キー制約を追加する
+//
+// 既存の行の外部キーカラムの値 (いずれかのカラムが NULL の行を除く) が参照先テーブルに存在しない場合はエラーを返す。
+// 参照先テーブルを使用した他のトランザクションが実行中の場合もエラーを返す (検査した後に参照先の行が削除・更新されないようにするため)
+func (h *Handler) AddForeignKey(trxId TrxId, tableName string, conParam CreateConstraintParam) error {
+	if h.trxManager.HasActiveTrxUsingTable(trxId, conParam.RefTableName) {
+		return fmt.Errorf("cannot alter table %s while other transactions are using table %s", tableName, conParam.RefTableName)
+	}
+	return h.alterTable(trxId, tableName, func(tblMeta *dictionary.TableMeta) error {
+		refTblMeta, ok := h.Catalog.GetTableMetaByName(conParam.RefTableName)
+		if !ok {
+			return fmt.Errorf("table %s not found", conParam.RefTableName)
+		}
+
+		refValues, err := h.scanColumnValues(trxId, refTblMeta, conParam.RefColNames)
+		if err != nil {
+			return err
+		}
+		refValueSet := make(map[string]bool, len(refValues))
+		for _, value := range refValues {
+			refValueSet[encodeValues(value)] = true
+		}
+		values, err := h.scanColumnValues(trxId, tblMeta, conParam.ColNames)
+		if err != nil {
+			return err
+		}
+		for _, value := range values {
+			if refValueSet[encodeValues(value)] {
+				continue
+			}
+			// 格納形式の値をカラムのデータ型に従った文字列に変換して表示する
+			formatted := make([]string, len(value))
+			for i, colName := range conParam.ColNames {
+				colMeta, _ := tblMeta.GetColByName(colName)
+				formatted[i] = colMeta.FormatValue(value[i])
+			}
+			return fmt.Errorf("cannot add foreign key %s: a foreign key constraint fails (value '%s')", conParam.ConstraintName, strings.Join(formatted, ", "))
+		}
+
+		tblMeta.Constraints = append(tblMeta.Constraints, newForeignKeyConstraintMeta(tblMeta.FileId, conParam))
+		return nil
+	})
+}
+
+// DropForeignKey はテーブルから外部キー制約を削除する (外部キーカラムのインデックスは削除しない)
+func (h *Handler) DropForeignKey(trxId TrxId, tableName string, constraintName string) error {
+	return h.alterTable(trxId, tableName, func(tblMeta *dictionary.TableMeta) error {
+		idx := slices.IndexFunc(tblMeta.Constraints, func(con *dictionary.ConstraintMeta) bool {
+			return con.ConstraintName == constraintName && con.RefTableName != ""
+		})
+		if idx < 0 {
+			return fmt.Errorf("foreign key %s not found in table %s", constraintName, tableName)
+		}
+		tblMeta.Constraints = slices.Delete(tblMeta.Constraints, idx, idx+1)
+		return nil
+	})
+}
+
+// alterTable は ALTER TABLE を実行する
+//
+//  1. テーブルを使用した他のトランザクションが実行中の場合はエラーを返す (変更中のテーブルを読み書きされないようにするため)
+//  2. パージスレッドを停止し、パージを同期的に実行する (作り直すテーブルに不要な delete-marked レコードをコピーしないため)
+//  3. テーブルメタデータの複製を alter で変更し、カタログのテーブルメタデータを置き換える
+//  4. 変更したページをすべてフラッシュし、チェックポイントを実行する
+//
+// テーブルを作り直した場合、古い B+Tree のページは再利用されずにヒープファイルに残る
+func (h *Handler) alterTable(trxId TrxId, tableName string, alter func(tblMeta *dictionary.TableMeta) error) error {
+	tblMeta, ok := h.Catalog.GetTableMetaByName(tableName)
+	if !ok {
+		return fmt.Errorf("table %s not found", tableName)
+	}
+	if h.trxManager.HasActiveTrxUsingTable(trxId, tableName) {
+		return fmt.Errorf("cannot alter table %s while other transactions are active", tableName)
+	}
+
+	h.purgeThread.Pause()
+	defer h.purgeThread.Resume()
+	if err := h.purgeThread.RunPurge(h.trxManager.PurgeLimit(), h.trxManager.CommittedTrxIds()); err != nil {
+		return err
+	}
+
+	newMeta := tblMeta.Clone()
+	if err := alter(&newMeta); err != nil {
+		return err
+	}
+	if err := h.Catalog.Update(h.BufferPool, newMeta); err != nil {
+		return err
+	}
+	h.StatsCollector.Invalidate(tableName)
+
+	if err := h.BufferPool.FlushAllPages(); err != nil {
+		return err
+	}
+	return buffer.NewCheckpoint(h.BufferPool, h.redoLog).Execute()
+}
+
+// rebuildTable はテーブルの全レコードを convert でカラム構成を変換しながら新しい B+Tree にコピーし、そのメタページ ID を返す
+func (h *Handler) rebuildTable(tblMeta *dictionary.TableMeta, convert func(columns [][]byte) ([][]byte, error)) (page.PageId, error) {
+	tbl, err := buildTable(tblMeta, h.undoLog, h.redoLog, h.rowLogs)
+	if err != nil {
+		return page.InvalidPageId, err
+	}
+	metaPageId, err := h.BufferPool.AllocatePageId(tblMeta.FileId)
+	if err != nil {
+		return page.InvalidPageId, err
+	}
+	dest := access.NewTable(tblMeta.Name, metaPageId, tblMeta.PKCount, nil, h.undoLog, h.redoLog)
+	if err := dest.Create(h.BufferPool); err != nil {
+		return page.InvalidPageId, err
+	}
+	if err := tbl.Rebuild(h.BufferPool, &dest, convert); err != nil {
+		return page.InvalidPageId, err
+	}
+	return metaPageId, nil
+}
+
+// scanColumnValues はテーブルの全行から指定したカラムの値の組 (いずれかのカラムが NULL の行を除く) を返す
+//
+// 他のトランザクションが実行中でない (= すべての変更がコミット済み) ことを前提に、最新の行を読む
+func (h *Handler) scanColumnValues(trxId TrxId, tblMeta *dictionary.TableMeta, colNames []string) ([][][]byte, error) {
+	positions, err := tblMeta.GetColPositions(colNames)
+	if err != nil {
+		return nil, err
+	}
+	tbl, err := buildTable(tblMeta, h.undoLog, h.redoLog, h.rowLogs)
+	if err != nil {
+		return nil, err
+	}
+	rv := access.NewReadView(trxId, nil, ^uint64(0))
+	iter, err := tbl.Search(h.BufferPool, rv, access.NewVersionReader(h.undoLog), access.RecordSearchModeStart{})
+	if err != nil {
+		return nil, err
+	}
+
+	var values [][][]byte
+	for {
+		record, ok, err := iter.Next()
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return values, nil
+		}
+		value := make([][]byte, len(positions))
+		for i, pos := range positions {
+			value[i] = record[pos]
+		}
+		if !slices.ContainsFunc(value, func(v []byte) bool { return v == nil }) {
+			values = append(values, value)
+		}
+	}
+}
+
+// encodeValues はカラムの値の組を、組ごとに一意な文字列にエンコードする
+func encodeValues(values [][]byte) string {
+	var encoded []byte
+	encode.Encode(values, &encoded)
+	return string(encoded)
+}
@@ -0,0 +1,400 @@
+package handler
+
+import (
+	"testing"
+
+	"github.com/ren-yamanashi/minesql/internal/storage/access"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddColumn(t *testing.T) {
+	t.Run("カラムを追加でき、既存の行の追加したカラムは NULL になる", func(t *testing.T) {
+		// GIVEN
+		h := setupAlterTable(t)
+		insertUsers(t, h, "1", "2")
+
+		// WHEN
+		err := h.AddColumn(0, "users", CreateColumnParam{Name: "age", Type: ColumnTypeInt})
+
+		// THEN
+		assert.NoError(t, err)
+		meta, ok := h.Catalog.GetTableMetaByName("users")
+		require.True(t, ok)
+		assert.Equal(t, uint8(3), meta.NCols)
+		col, ok := meta.GetColByName("age")
+		require.True(t, ok)
+		assert.Equal(t, uint16(2), col.Pos)
+		assert.Equal(t, [][][]byte{
+			{[]byte("1"), []byte("user-1"), nil},
+			{[]byte("2"), []byte("user-2"), nil},
+		}, scanUsers(t, h))
+
+		// 追加したカラムに値を入れた行を挿入できる
+		tbl, err := h.GetTable("users")
+		require.NoError(t, err)
+		trxId := h.BeginTrx()
+		require.NoError(t, tbl.Insert(h.BufferPool, trxId, h.LockMgr, [][]byte{[]byte("3"), []byte("user-3"), []byte("30")}))
+		require.NoError(t, h.CommitTrx(trxId))
+		assert.Len(t, scanUsers(t, h), 3)
+	})
+
+	t.Run("再起動後も追加したカラムと既存の行を読み取れる", func(t *testing.T) {
+		// GIVEN
+		h := setupAlterTable(t)
+		insertUsers(t, h, "1")
+		require.NoError(t, h.AddColumn(0, "users", CreateColumnParam{Name: "age", Type: ColumnTypeInt}))
+
+		// WHEN
+		require.NoError(t, h.Shutdown())
+		Reset()
+		h2 := Init()
+		defer func() { assert.NoError(t, h2.Shutdown()) }()
+
+		// THEN
+		meta, ok := h2.Catalog.GetTableMetaByName("users")
+		require.True(t, ok)
+		assert.Equal(t, uint8(3), meta.NCols)
+		assert.Equal(t, [][][]byte{{[]byte("1"), []byte("user-1"), nil}}, scanUsers(t, h2))
+	})
+
+	t.Run("空のテーブルには NOT NULL のカラムを追加できる", func(t *testing.T) {
+		// GIVEN
+		h := setupAlterTable(t)
+
+		// WHEN
+		err := h.AddColumn(0, "users", CreateColumnParam{Name: "age", Type: ColumnTypeInt, NotNull: true})
+
+		// THEN
+		assert.NoError(t, err)
+		meta, _ := h.Catalog.GetTableMetaByName("users")
+		col, ok := meta.GetColByName("age")
+		require.True(t, ok)
+		assert.True(t, col.NotNull)
+	})
+
+	t.Run("行があるテーブルに NOT NULL のカラムを追加するとエラーを返し、テーブルは変更されない", func(t *testing.T) {
+		// GIVEN
+		h := setupAlterTable(t)
+		insertUsers(t, h, "1")
+
+		// WHEN
+		err := h.AddColumn(0, "users", CreateColumnParam{Name: "age", Type: ColumnTypeInt, NotNull: true})
+
+		// THEN
+		assert.EqualError(t, err, "cannot add NOT NULL column age: table users is not empty")
+		meta, _ := h.Catalog.GetTableMetaByName("users")
+		assert.Equal(t, uint8(2), meta.NCols)
+		assert.Equal(t, [][][]byte{{[]byte("1"), []byte("user-1")}}, scanUsers(t, h))
+	})
+
+	t.Run("DEFAULT 値を指定した場合、既存の行の追加したカラムは DEFAULT 値になる", func(t *testing.T) {
+		// GIVEN
+		h := setupAlterTable(t)
+		insertUsers(t, h, "1", "2")
+
+		// WHEN
+		err := h.AddColumn(0, "users", CreateColumnParam{Name: "age", Type: ColumnTypeInt, NotNull: true, Default: "20", DefaultValue: []byte("20")})
+
+		// THEN
+		assert.NoError(t, err)
+		meta, _ := h.Catalog.GetTableMetaByName("users")
+		col, ok := meta.GetColByName("age")
+		require.True(t, ok)
+		assert.Equal(t, "20", col.Default)
+		assert.Equal(t, [][][]byte{
+			{[]byte("1"), []byte("user-1"), []byte("20")},
+			{[]byte("2"), []byte("user-2"), []byte("20")},
+		}, scanUsers(t, h))
+	})
+
+	t.Run("既存のカラムと同じ名前のカラムは追加できない", func(t *testing.T) {
+		// GIVEN
+		h := setupAlterTable(t)
+
+		// WHEN
+		err := h.AddColumn(0, "users", CreateColumnParam{Name: "name", Type: ColumnTypeString})
+
+		// THEN
+		assert.EqualError(t, err, "duplicate column name: name")
+	})
+
+	t.Run("テーブルを使用した他のトランザクションが実行中の場合はエラーを返す", func(t *testing.T) {
+		// GIVEN
+		h := setupAlterTable(t)
+		trxId := h.BeginTrx()
+		_, err := h.OpenTable(trxId, "users")
+		require.NoError(t, err)
+
+		// WHEN
+		err = h.AddColumn(0, "users", CreateColumnParam{Name: "age", Type: ColumnTypeInt})
+
+		// THEN
+		assert.EqualError(t, err, "cannot alter table users while other transactions are active")
+	})
+
+	t.Run("他のテーブルだけを使用したトランザクションが実行中でも変更できる", func(t *testing.T) {
+		// GIVEN
+		h := setupAlterTable(t)
+		trxId := h.BeginTrx()
+		_, err := h.OpenTable(trxId, "orders")
+		require.NoError(t, err)
+
+		// WHEN
+		err = h.AddColumn(0, "users", CreateColumnParam{Name: "age", Type: ColumnTypeInt})
+
+		// THEN
+		assert.NoError(t, err)
+		assert.NoError(t, h.CommitTrx(trxId))
+	})
+
+	t.Run("存在しないテーブルを指定するとエラーを返す", func(t *testing.T) {
+		// GIVEN
+		h := setupAlterTable(t)
+
+		// WHEN
+		err := h.AddColumn(0, "products", CreateColumnParam{Name: "age", Type: ColumnTypeInt})
+
+		// THEN
+		assert.EqualError(t, err, "table products not found")
+	})
+}
+
+func TestDropColumn(t *testing.T) {
+	t.Run("カラムを削除でき、後ろのカラムの位置が詰められる", func(t *testing.T) {
+		// GIVEN: users (id, name, age) の age にインデックスがある
+		h := setupAlterTable(t)
+		insertUsers(t, h, "1", "2")
+		require.NoError(t, h.AddColumn(0, "users", CreateColumnParam{Name: "age", Type: ColumnTypeInt}))
+		require.NoError(t, h.AddIndex(0, "users", CreateIndexParam{Name: "idx_age", ColNames: []string{"age"}}))
+
+		// WHEN
+		err := h.DropColumn(0, "users", "name")
+
+		// THEN
+		assert.NoError(t, err)
+		meta, _ := h.Catalog.GetTableMetaByName("users")
+		assert.Equal(t, uint8(2), meta.NCols)
+		_, ok := meta.GetColByName("name")
+		assert.False(t, ok)
+		col, ok := meta.GetColByName("age")
+		require.True(t, ok)
+		assert.Equal(t, uint16(1), col.Pos)
+		assert.Equal(t, [][][]byte{
+			{[]byte("1"), nil},
+			{[]byte("2"), nil},
+		}, scanUsers(t, h))
+
+		// 位置が変わったカラムのインデックスも更新できる
+		tbl, err := h.GetTable("users")
+		require.NoError(t, err)
+		trxId := h.BeginTrx()
+		require.NoError(t, tbl.Insert(h.BufferPool, trxId, h.LockMgr, [][]byte{[]byte("3"), []byte("30")}))
+		require.NoError(t, h.CommitTrx(trxId))
+		si, err := tbl.GetSecondaryIndexByName("idx_age")
+		require.NoError(t, err)
+		iter, err := si.Search(h.BufferPool, tbl, access.RecordSearchModeKey{Key: [][]byte{[]byte("30")}})
+		require.NoError(t, err)
+		result, ok, err := iter.Next()
+		require.NoError(t, err)
+		require.True(t, ok)
+		assert.Equal(t, [][]byte{[]byte("3"), []byte("30")}, result.Record)
+	})
+
+	t.Run("カラムのインデックスと UNIQUE 制約も削除される", func(t *testing.T) {
+		// GIVEN
+		h := setupAlterTable(t)
+		require.NoError(t, h.AddIndex(0, "users", CreateIndexParam{Name: "idx_name", ColNames: []string{"name"}, Unique: true}))
+
+		// WHEN
+		err := h.DropColumn(0, "users", "name")
+
+		// THEN
+		assert.NoError(t, err)
+		meta, _ := h.Catalog.GetTableMetaByName("users")
+		assert.Empty(t, meta.Indexes)
+		assert.Len(t, meta.Constraints, 1)
+		assert.Equal(t, "PRIMARY", meta.Constraints[0].ConstraintName)
+	})
+
+	t.Run("複数カラムのインデックスに含まれるカラムは削除できない", func(t *testing.T) {
+		// GIVEN
+		h := setupAlterTable(t)
+		require.NoError(t, h.AddIndex(0, "users", CreateIndexParam{Name: "idx_name_id", ColNames: []string{"name", "id"}}))
+
+		// WHEN
+		err := h.DropColumn(0, "users", "name")
+
+		// THEN
+		assert.EqualError(t, err, "cannot drop column name: used by composite index idx_name_id")
+		meta, _ := h.Catalog.GetTableMetaByName("users")
+		assert.Len(t, meta.Indexes, 1)
+	})
+
+	t.Run("プライマリキーのカラムは削除できない", func(t *testing.T) {
+		// GIVEN
+		h := setupAlterTable(t)
+
+		// WHEN
+		err := h.DropColumn(0, "users", "id")
+
+		// THEN
+		assert.EqualError(t, err, "cannot drop column id: primary key column")
+	})
+
+	t.Run("外部キーのカラムは削除できない", func(t *testing.T) {
+		// GIVEN
+		h := setupAlterTable(t)
+
+		// WHEN
+		err := h.DropColumn(0, "orders", "user_id")
+
+		// THEN
+		assert.EqualError(t, err, "cannot drop column user_id: used by foreign key fk_user")
+	})
+
+	t.Run("他のテーブルの外部キーから参照されているカラムは削除できない", func(t *testing.T) {
+		// GIVEN: orders.user_id が users.name を参照する
+		h := setupAlterTable(t)
+		require.NoError(t, h.AddIndex(0, "users", CreateIndexParam{Name: "idx_name", ColNames: []string{"name"}, Unique: true}))
+		require.NoError(t, h.DropForeignKey(0, "orders", "fk_user"))
+		require.NoError(t, h.AddForeignKey(0, "orders", CreateConstraintParam{ConstraintName: "fk_user_name", ColNames: []string{"user_id"}, RefTableName: "users", RefColNames: []string{"name"}}))
+
+		// WHEN
+		err := h.DropColumn(0, "users", "name")
+
+		// THEN
+		assert.EqualError(t, err, "cannot drop column name: referenced by foreign key fk_user_name on table orders")
+	})
+
+	t.Run("存在しないカラムを指定するとエラーを返す", func(t *testing.T) {
+		// GIVEN
+		h := setupAlterTable(t)
+
+		// WHEN
+		err := h.DropColumn(0, "users", "age")
+
+		// THEN
+		assert.EqualError(t, err, "column age not found in table users")
+	})
+}
+
+func TestAddForeignKey(t *testing.T) {
+	t.Run("既存の行の値がすべて参照先に存在する場合は外部キーを追加できる", func(t *testing.T) {
+		// GIVEN: NULL の行は検査しない
+		h := setupAlterTable(t)
+		insertUsers(t, h, "1")
+		require.NoError(t, h.DropForeignKey(0, "orders", "fk_user"))
+		insertOrders(t, h, [][]byte{[]byte("o1"), []byte("1")}, [][]byte{[]byte("o2"), nil})
+
+		// WHEN
+		err := h.AddForeignKey(0, "orders", CreateConstraintParam{ConstraintName: "fk_user2", ColNames: []string{"user_id"}, RefTableName: "users", RefColNames: []string{"id"}})
+
+		// THEN
+		assert.NoError(t, err)
+		meta, _ := h.Catalog.GetTableMetaByName("orders")
+		fks := meta.GetForeignKeyConstraints()
+		require.Len(t, fks, 1)
+		assert.Equal(t, "fk_user2", fks[0].ConstraintName)
+		assert.Len(t, h.Catalog.GetForeignKeysReferencingTable("users"), 1)
+	})
+
+	t.Run("参照先に存在しない値がある場合はエラーを返す", func(t *testing.T) {
+		// GIVEN
+		h := setupAlterTable(t)
+		insertUsers(t, h, "1")
+		require.NoError(t, h.DropForeignKey(0, "orders", "fk_user"))
+		insertOrders(t, h, [][]byte{[]byte("o1"), []byte("1")}, [][]byte{[]byte("o2"), []byte("2")})
+
+		// WHEN
+		err := h.AddForeignKey(0, "orders", CreateConstraintParam{ConstraintName: "fk_user2", ColNames: []string{"user_id"}, RefTableName: "users", RefColNames: []string{"id"}})
+
+		// THEN
+		assert.EqualError(t, err, "cannot add foreign key fk_user2: a foreign key constraint fails (value '2')")
+		meta, _ := h.Catalog.GetTableMetaByName("orders")
+		assert.Empty(t, meta.GetForeignKeyConstraints())
+	})
+
+	t.Run("参照先テーブルを使用した他のトランザクションが実行中の場合はエラーを返す", func(t *testing.T) {
+		// GIVEN
+		h := setupAlterTable(t)
+		require.NoError(t, h.DropForeignKey(0, "orders", "fk_user"))
+		trxId := h.BeginTrx()
+		_, err := h.OpenTable(trxId, "users")
+		require.NoError(t, err)
+
+		// WHEN
+		err = h.AddForeignKey(0, "orders", CreateConstraintParam{ConstraintName: "fk_user2", ColNames: []string{"user_id"}, RefTableName: "users", RefColNames: []string{"id"}})
+
+		// THEN
+		assert.EqualError(t, err, "cannot alter table orders while other transactions are using table users")
+		meta, _ := h.Catalog.GetTableMetaByName("orders")
+		assert.Empty(t, meta.GetForeignKeyConstraints())
+	})
+
+	t.Run("複合外部キーの値の組が参照先に存在しない場合はエラーを返す", func(t *testing.T) {
+		// GIVEN: orders (user_id, id) が users (id, name) を参照する。(1, user-2) の組は users に存在しない
+		h := setupAlterTable(t)
+		insertUsers(t, h, "1", "2")
+		require.NoError(t, h.AddIndex(0, "users", CreateIndexParam{Name: "uk_id_name", ColNames: []string{"id", "name"}, Unique: true}))
+		require.NoError(t, h.AddIndex(0, "orders", CreateIndexParam{Name: "idx_user_id_id", ColNames: []string{"user_id", "id"}}))
+		insertOrders(t, h, [][]byte{[]byte("user-1"), []byte("1")}, [][]byte{[]byte("user-2"), []byte("1")})
+
+		// WHEN
+		err := h.AddForeignKey(0, "orders", CreateConstraintParam{ConstraintName: "fk_user_pair", ColNames: []string{"user_id", "id"}, RefTableName: "users", RefColNames: []string{"id", "name"}})
+
+		// THEN
+		assert.EqualError(t, err, "cannot add foreign key fk_user_pair: a foreign key constraint fails (value '1, user-2')")
+	})
+}
+
+func TestDropForeignKey(t *testing.T) {
+	t.Run("外部キーを削除でき、インデックスは残る", func(t *testing.T) {
+		// GIVEN
+		h := setupAlterTable(t)
+
+		// WHEN
+		err := h.DropForeignKey(0, "orders", "fk_user")
+
+		// THEN
+		assert.NoError(t, err)
+		meta, _ := h.Catalog.GetTableMetaByName("orders")
+		assert.Empty(t, meta.GetForeignKeyConstraints())
+		assert.Len(t, meta.Indexes, 1)
+		assert.Empty(t, h.Catalog.GetForeignKeysReferencingTable("users"))
+	})
+
+	t.Run("存在しない外部キーを指定するとエラーを返す", func(t *testing.T) {
+		// GIVEN
+		h := setupAlterTable(t)
+
+		// WHEN
+		err := h.DropForeignKey(0, "orders", "PRIMARY")
+
+		// THEN
+		assert.EqualError(t, err, "foreign key PRIMARY not found in table orders")
+	})
+}
+
+// setupAlterTable は Handler を初期化し、users テーブルと users を参照する orders テーブルを作成する
+func setupAlterTable(t *testing.T) *Handler {
+	t.Helper()
+	t.Setenv("MINESQL_DATA_DIR", t.TempDir())
+	t.Setenv("MINESQL_BUFFER_SIZE", "100")
+	Reset()
+	h := Init()
+	createUsersAndOrders(t, h)
+	return h
+}
+
+// insertOrders は orders テーブルにレコードを挿入してコミットする (外部キーの検査は行わない)
+func insertOrders(t *testing.T, h *Handler, records ...[][]byte) {
+	t.Helper()
+	tbl, err := h.GetTable("orders")
+	require.NoError(t, err)
+	trxId := h.BeginTrx()
+	for _, record := range records {
+		require.NoError(t, tbl.Insert(h.BufferPool, trxId, h.LockMgr, record))
+	}
+	require.NoError(t, h.CommitTrx(trxId))
+}
@@ -0,0 +1,105 @@
+package handler
+
+import (
+	"fmt"
+	"math"
+	"sync"
+
+	"github.com/ren-yamanashi/minesql/internal/storage/btree"
+	"github.com/ren-yamanashi/minesql/internal/storage/buffer"
+	"github.com/ren-yamanashi/minesql/internal/storage/dictionary"
+	"github.com/ren-yamanashi/minesql/internal/storage/encode"
+)
+
+// autoIncrementCounters はテーブルごとに、AUTO_INCREMENT カラムに格納された値の最大値を保持する
+//
+// 値はメモリ上にのみ保持し、再起動時はテーブルの最大のプライマリキーから復元する (findMaxAutoIncrements)
+type autoIncrementCounters struct {
+	mutex     sync.Mutex
+	maxValues map[string]int64 // key: テーブル名
+}
+
+func newAutoIncrementCounters(maxValues map[string]int64) *autoIncrementCounters {
+	return &autoIncrementCounters{maxValues: maxValues}
+}
+
+// NextAutoIncrement は AUTO_INCREMENT カラムに格納する次の値 (これまでの最大値 + 1) を採番する
+//
+// 採番した値は、トランザクションがロールバックされても再利用しない。
+// カラムのデータ型の最大値を超える場合はエラーを返す
+func (h *Handler) NextAutoIncrement(tableName string, colMeta *ColumnMetadata) (int64, error) {
+	c := h.autoIncrements
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	current := c.maxValues[tableName]
+	if current >= maxAutoIncrementValue(colMeta.Type) {
+		return 0, fmt.Errorf("auto-increment value out of range for column '%s'", colMeta.Name)
+	}
+	c.maxValues[tableName] = current + 1
+	return current + 1, nil
+}
+
+// UpdateAutoIncrement は AUTO_INCREMENT カラムに明示的に指定された値を反映する
+//
+// 指定された値がこれまでの最大値より大きい場合、以降はその次の値から採番する
+func (h *Handler) UpdateAutoIncrement(tableName string, value int64) {
+	c := h.autoIncrements
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if value > c.maxValues[tableName] {
+		c.maxValues[tableName] = value
+	}
+}
+
+// resetAutoIncrement はテーブルの採番をリセットする (次に採番する値は 1 になる)
+func (h *Handler) resetAutoIncrement(tableName string) {
+	c := h.autoIncrements
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	delete(c.maxValues, tableName)
+}
+
+// maxAutoIncrementValue は AUTO_INCREMENT カラムのデータ型で格納できる最大値を返す
+func maxAutoIncrementValue(colType ColumnType) int64 {
+	if colType == ColumnTypeInt {
+		return math.MaxInt32
+	}
+	return math.MaxInt64
+}
+
+// findMaxAutoIncrements は AUTO_INCREMENT カラムを持つテーブルごとに、カラムの最大値を返す
+//
+// サーバー再起動時に、AUTO_INCREMENT の採番を復元するために使用する。
+// AUTO_INCREMENT カラムはプライマリキーの先頭のカラムのため、全件を走査せずにクラスタ化インデックスの最大のキーからデコードする。
+// delete-marked のレコードも対象とするため、パージされていない削除済みの行の値は再利用しない
+func findMaxAutoIncrements(bp *buffer.BufferPool, catalog *dictionary.Catalog) (map[string]int64, error) {
+	maxValues := make(map[string]int64)
+
+	for _, tblMeta := range catalog.GetAllTables() {
+		colMeta, ok := tblMeta.GetAutoIncrementCol()
+		if !ok {
+			continue
+		}
+
+		record, ok, err := btree.NewBTree(tblMeta.DataMetaPageId).LastRecord(bp)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+
+		pk, _ := encode.DecodeFirstN(record.KeyBytes(), int(colMeta.Pos)+1)
+		if len(pk) <= int(colMeta.Pos) || len(pk[colMeta.Pos]) != encode.INT_SIZE {
+			continue
+		}
+		if value := encode.DecodeInt64(pk[colMeta.Pos]); value > 0 {
+			maxValues[tblMeta.Name] = value
+		}
+	}
+
+	return maxValues, nil
+}
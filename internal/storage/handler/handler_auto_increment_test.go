@@ -0,0 +1,188 @@
+package handler
+
+import (
+	"math"
+	"testing"
+
+	"github.com/ren-yamanashi/minesql/internal/storage/btree"
+	"github.com/ren-yamanashi/minesql/internal/storage/encode"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNextAutoIncrement(t *testing.T) {
+	t.Run("1 から順に採番する", func(t *testing.T) {
+		// GIVEN
+		h := setupAutoIncrementTable(t)
+		colMeta := getAutoIncrementCol(t, h)
+
+		// WHEN
+		id1, err1 := h.NextAutoIncrement("items", colMeta)
+		id2, err2 := h.NextAutoIncrement("items", colMeta)
+
+		// THEN
+		assert.NoError(t, err1)
+		assert.NoError(t, err2)
+		assert.Equal(t, int64(1), id1)
+		assert.Equal(t, int64(2), id2)
+	})
+
+	t.Run("明示的に指定された値より大きい値から採番する", func(t *testing.T) {
+		// GIVEN
+		h := setupAutoIncrementTable(t)
+		colMeta := getAutoIncrementCol(t, h)
+		h.UpdateAutoIncrement("items", 10)
+		h.UpdateAutoIncrement("items", 5)
+
+		// WHEN
+		id, err := h.NextAutoIncrement("items", colMeta)
+
+		// THEN
+		assert.NoError(t, err)
+		assert.Equal(t, int64(11), id)
+	})
+
+	t.Run("INT の最大値を超える場合はエラーを返す", func(t *testing.T) {
+		// GIVEN
+		h := setupAutoIncrementTable(t)
+		colMeta := getAutoIncrementCol(t, h)
+		h.UpdateAutoIncrement("items", math.MaxInt32)
+
+		// WHEN
+		_, err := h.NextAutoIncrement("items", colMeta)
+
+		// THEN
+		assert.EqualError(t, err, "auto-increment value out of range for column 'id'")
+	})
+
+	t.Run("再起動後はテーブルの最大値の次の値から採番する", func(t *testing.T) {
+		// GIVEN
+		h := setupAutoIncrementTable(t)
+		insertItems(t, h, 3, 7)
+		require.NoError(t, h.Shutdown())
+		Reset()
+		h2 := Init()
+		defer func() { assert.NoError(t, h2.Shutdown()) }()
+
+		// WHEN
+		id, err := h2.NextAutoIncrement("items", getAutoIncrementCol(t, h2))
+
+		// THEN
+		assert.NoError(t, err)
+		assert.Equal(t, int64(8), id)
+	})
+
+	t.Run("再起動後、複数のリーフノードにまたがるテーブルでも最大値の次の値から採番する", func(t *testing.T) {
+		// GIVEN: 負の値を含む多数の行を挿入したテーブル
+		h := setupAutoIncrementTable(t)
+		ids := []int64{-5}
+		for id := int64(1); id <= 1000; id++ {
+			ids = append(ids, id)
+		}
+		insertItems(t, h, ids...)
+		require.NoError(t, h.Shutdown())
+		Reset()
+		h2 := Init()
+		defer func() { assert.NoError(t, h2.Shutdown()) }()
+		tblMeta, ok := h2.Catalog.GetTableMetaByName("items")
+		require.True(t, ok)
+		leafPageCount, err := btree.NewBTree(tblMeta.DataMetaPageId).LeafPageCount(h2.BufferPool)
+		require.NoError(t, err)
+		require.Greater(t, leafPageCount, uint64(1))
+
+		// WHEN
+		id, err := h2.NextAutoIncrement("items", getAutoIncrementCol(t, h2))
+
+		// THEN
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1001), id)
+	})
+
+	t.Run("再起動後、行がないテーブルは 1 から採番する", func(t *testing.T) {
+		// GIVEN
+		h := setupAutoIncrementTable(t)
+		require.NoError(t, h.Shutdown())
+		Reset()
+		h2 := Init()
+		defer func() { assert.NoError(t, h2.Shutdown()) }()
+
+		// WHEN
+		id, err := h2.NextAutoIncrement("items", getAutoIncrementCol(t, h2))
+
+		// THEN
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), id)
+	})
+
+	t.Run("TRUNCATE TABLE 後は 1 から採番し直す", func(t *testing.T) {
+		// GIVEN
+		h := setupAutoIncrementTable(t)
+		insertItems(t, h, 3)
+		h.UpdateAutoIncrement("items", 3)
+
+		// WHEN
+		require.NoError(t, h.TruncateTable(0, "items"))
+		id, err := h.NextAutoIncrement("items", getAutoIncrementCol(t, h))
+
+		// THEN
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), id)
+	})
+
+	t.Run("DROP TABLE 後に同じ名前で作成したテーブルは 1 から採番する", func(t *testing.T) {
+		// GIVEN
+		h := setupAutoIncrementTable(t)
+		h.UpdateAutoIncrement("items", 3)
+
+		// WHEN
+		require.NoError(t, h.DropTable(0, "items", false))
+		createItems(t, h)
+		id, err := h.NextAutoIncrement("items", getAutoIncrementCol(t, h))
+
+		// THEN
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), id)
+	})
+}
+
+// setupAutoIncrementTable は Handler を初期化し、AUTO_INCREMENT カラムを持つ items テーブルを作成する
+func setupAutoIncrementTable(t *testing.T) *Handler {
+	t.Helper()
+	t.Setenv("MINESQL_DATA_DIR", t.TempDir())
+	t.Setenv("MINESQL_BUFFER_SIZE", "100")
+	Reset()
+	h := Init()
+	createItems(t, h)
+	return h
+}
+
+// createItems は items (id INT AUTO_INCREMENT PK, name) テーブルを作成する
+func createItems(t *testing.T, h *Handler) {
+	t.Helper()
+	require.NoError(t, h.CreateTable("items", 1, nil, []CreateColumnParam{
+		{Name: "id", Type: ColumnTypeInt, NotNull: true, AutoIncrement: true},
+		{Name: "name", Type: ColumnTypeString},
+	}, nil))
+}
+
+// insertItems は items テーブルに指定した id のレコードを挿入してコミットする
+func insertItems(t *testing.T, h *Handler, ids ...int64) {
+	t.Helper()
+	tbl, err := h.GetTable("items")
+	require.NoError(t, err)
+	trxId := h.BeginTrx()
+	for _, id := range ids {
+		require.NoError(t, tbl.Insert(h.BufferPool, trxId, h.LockMgr, [][]byte{encode.EncodeInt64(id), []byte("item")}))
+	}
+	require.NoError(t, h.CommitTrx(trxId))
+}
+
+// getAutoIncrementCol は items テーブルの AUTO_INCREMENT カラムのメタデータを取得する
+func getAutoIncrementCol(t *testing.T, h *Handler) *ColumnMetadata {
+	t.Helper()
+	tblMeta, ok := h.Catalog.GetTableMetaByName("items")
+	require.True(t, ok)
+	colMeta, ok := tblMeta.GetAutoIncrementCol()
+	require.True(t, ok)
+	return colMeta
+}
@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/ren-yamanashi/minesql/internal/storage/dictionary"
+	"github.com/ren-yamanashi/minesql/internal/storage/log"
+)
+
+// CreateDatabase はデータベースを作成し、カタログに登録する
+//
+// データディレクトリにデータベースのサブディレクトリを作成する (データベースのテーブルのファイルはサブディレクトリに格納する)
+//   - ifNotExists: true の場合、同じ名前のデータベースがあってもエラーにしない
+func (h *Handler) CreateDatabase(databaseName string, ifNotExists bool) error {
+	if err := dictionary.ValidateDatabaseName(databaseName); err != nil {
+		return err
+	}
+	if _, ok := h.Catalog.GetDatabaseByName(databaseName); ok {
+		if ifNotExists {
+			return nil
+		}
+		return fmt.Errorf("database %s already exists", databaseName)
+	}
+	if err := os.MkdirAll(filepath.Join(h.baseDirectory, databaseName), 0750); err != nil {
+		return err
+	}
+	return h.Catalog.InsertDatabase(h.BufferPool, *dictionary.NewDatabaseMeta(databaseName))
+}
+
+// DropDatabase はデータベースを、データベースのテーブル・ビューとともに削除する
+//
+// 他のデータベースのテーブルの外部キーから参照されているテーブルがある場合は削除できない。
+// 他のトランザクションが実行中の場合はエラーを返す (ロールバック時に削除したテーブルへ UNDO が適用されないようにするため)
+// DROP TABLE と同様に DDL ログに記録してから実行するため、途中で異常終了しても再起動時のリカバリで最後まで実行される
+//   - trxId: DROP DATABASE を実行するトランザクション
+//   - ifExists: true の場合、データベースが存在しなくてもエラーにしない
+func (h *Handler) DropDatabase(trxId TrxId, databaseName string, ifExists bool) error {
+	if _, ok := h.Catalog.GetDatabaseByName(databaseName); !ok {
+		if ifExists {
+			return nil
+		}
+		return fmt.Errorf("database %s not found", databaseName)
+	}
+
+	var tables []*dictionary.TableMeta
+	for _, tblMeta := range h.Catalog.GetAllTables() {
+		if database, _ := dictionary.SplitName(tblMeta.Name); database == databaseName {
+			tables = append(tables, tblMeta)
+		}
+	}
+	for _, tblMeta := range tables {
+		for _, fk := range h.Catalog.GetForeignKeysReferencingTable(tblMeta.Name) {
+			if database, _ := dictionary.SplitName(fk.TableName); database != databaseName {
+				return fmt.Errorf("cannot drop database %s: table %s is referenced by foreign key %s on table %s", databaseName, tblMeta.Name, fk.Constraint.ConstraintName, fk.TableName)
+			}
+		}
+	}
+
+	if h.trxManager.HasActiveTrxExcept(trxId) {
+		return fmt.Errorf("cannot drop database %s while other transactions are active", databaseName)
+	}
+
+	// データベース内のテーブル同士の外部キーは削除の妨げにならないため、参照の有無を確認せずに削除する
+	if err := h.executeDDL(log.DDLRecord{Op: log.DDLDropDatabase, TableName: databaseName}); err != nil {
+		return err
+	}
+	for _, tblMeta := range tables {
+		h.StatsCollector.Invalidate(tblMeta.Name)
+		h.resetAutoIncrement(tblMeta.Name)
+	}
+	return nil
+}
+
+// checkDatabaseExists はデータベース名で修飾したテーブル名・ビュー名のデータベースが存在することを確認する
+func (h *Handler) checkDatabaseExists(name string) error {
+	database, _ := dictionary.SplitName(name)
+	if database == "" {
+		return nil
+	}
+	if _, ok := h.Catalog.GetDatabaseByName(database); !ok {
+		return fmt.Errorf("database %s not found", database)
+	}
+	return nil
+}
@@ -0,0 +1,208 @@
+package handler
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ren-yamanashi/minesql/internal/storage/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateDatabase(t *testing.T) {
+	setup := func(t *testing.T) (*Handler, string) {
+		tmpdir := t.TempDir()
+		t.Setenv("MINESQL_DATA_DIR", tmpdir)
+		t.Setenv("MINESQL_BUFFER_SIZE", "100")
+		Reset()
+		return Init(), tmpdir
+	}
+
+	t.Run("データベースを作成すると、データベースのテーブルはサブディレクトリに格納される", func(t *testing.T) {
+		// GIVEN
+		h, tmpdir := setup(t)
+		defer Reset()
+
+		// WHEN
+		err := h.CreateDatabase("app", false)
+		require.NoError(t, err)
+		err = h.CreateTable("app.users", 1, nil, []CreateColumnParam{
+			{Name: "id", Type: ColumnTypeString},
+		}, nil)
+
+		// THEN
+		assert.NoError(t, err)
+		_, ok := h.Catalog.GetDatabaseByName("app")
+		assert.True(t, ok)
+		_, err = os.Stat(filepath.Join(tmpdir, "app", "users.db"))
+		assert.NoError(t, err)
+
+		// 再起動後もデータベースとテーブルを参照できる
+		assert.NoError(t, h.Shutdown())
+		Reset()
+		h2 := Init()
+		_, ok = h2.Catalog.GetDatabaseByName("app")
+		assert.True(t, ok)
+		_, err = h2.GetTable("app.users")
+		assert.NoError(t, err)
+		assert.NoError(t, h2.Shutdown())
+	})
+
+	t.Run("同じ名前のデータベースがある場合はエラーになる (IF NOT EXISTS の場合はエラーにしない)", func(t *testing.T) {
+		// GIVEN
+		h, _ := setup(t)
+		defer Reset()
+		require.NoError(t, h.CreateDatabase("app", false))
+
+		// WHEN
+		err := h.CreateDatabase("app", false)
+		ifNotExistsErr := h.CreateDatabase("app", true)
+
+		// THEN
+		assert.EqualError(t, err, "database app already exists")
+		assert.NoError(t, ifNotExistsErr)
+	})
+
+	t.Run("不正なデータベース名・存在しないデータベースのテーブルはエラーになる", func(t *testing.T) {
+		// GIVEN
+		h, _ := setup(t)
+		defer Reset()
+
+		// WHEN
+		err := h.CreateDatabase("a.b", false)
+		tableErr := h.CreateTable("app.users", 1, nil, []CreateColumnParam{{Name: "id", Type: ColumnTypeString}}, nil)
+		viewErr := h.CreateView("app.v", nil, "SELECT 1", false)
+
+		// THEN
+		assert.EqualError(t, err, "invalid database name a.b")
+		assert.EqualError(t, tableErr, "database app not found")
+		assert.EqualError(t, viewErr, "database app not found")
+	})
+}
+
+func TestDropDatabase(t *testing.T) {
+	setup := func(t *testing.T) (*Handler, string) {
+		tmpdir := t.TempDir()
+		t.Setenv("MINESQL_DATA_DIR", tmpdir)
+		t.Setenv("MINESQL_BUFFER_SIZE", "100")
+		Reset()
+		h := Init()
+		require.NoError(t, h.CreateDatabase("app", false))
+		require.NoError(t, h.CreateTable("app.users", 1, nil, []CreateColumnParam{
+			{Name: "id", Type: ColumnTypeString},
+		}, nil))
+		require.NoError(t, h.CreateTable("app.orders", 1, nil,
+			[]CreateColumnParam{
+				{Name: "id", Type: ColumnTypeString},
+				{Name: "user_id", Type: ColumnTypeString},
+			},
+			[]CreateConstraintParam{{ConstraintName: "fk_user", ColNames: []string{"user_id"}, RefTableName: "app.users", RefColNames: []string{"id"}}},
+		))
+		require.NoError(t, h.CreateView("app.v", nil, "SELECT id FROM users", false))
+		return h, tmpdir
+	}
+
+	t.Run("データベースを削除すると、テーブル・ビュー・サブディレクトリも削除される", func(t *testing.T) {
+		// GIVEN
+		h, tmpdir := setup(t)
+		defer Reset()
+		require.NoError(t, h.CreateTable("users", 1, nil, []CreateColumnParam{{Name: "id", Type: ColumnTypeString}}, nil))
+
+		// WHEN
+		err := h.DropDatabase(0, "app", false)
+
+		// THEN
+		assert.NoError(t, err)
+		_, ok := h.Catalog.GetDatabaseByName("app")
+		assert.False(t, ok)
+		_, ok = h.Catalog.GetTableMetaByName("app.users")
+		assert.False(t, ok)
+		_, ok = h.Catalog.GetTableMetaByName("app.orders")
+		assert.False(t, ok)
+		_, ok = h.Catalog.GetViewByName("app.v")
+		assert.False(t, ok)
+		_, err = os.Stat(filepath.Join(tmpdir, "app"))
+		assert.True(t, os.IsNotExist(err))
+
+		// 他のデータベースのテーブルは残る
+		_, ok = h.Catalog.GetTableMetaByName("users")
+		assert.True(t, ok)
+	})
+
+	t.Run("他のデータベースのテーブルの外部キーから参照されている場合はエラーになる", func(t *testing.T) {
+		// GIVEN
+		h, _ := setup(t)
+		defer Reset()
+		require.NoError(t, h.CreateTable("payments", 1, nil,
+			[]CreateColumnParam{
+				{Name: "id", Type: ColumnTypeString},
+				{Name: "user_id", Type: ColumnTypeString},
+			},
+			[]CreateConstraintParam{{ConstraintName: "fk_payment_user", ColNames: []string{"user_id"}, RefTableName: "app.users", RefColNames: []string{"id"}}},
+		))
+
+		// WHEN
+		err := h.DropDatabase(0, "app", false)
+
+		// THEN
+		assert.EqualError(t, err, "cannot drop database app: table app.users is referenced by foreign key fk_payment_user on table payments")
+		_, ok := h.Catalog.GetTableMetaByName("app.users")
+		assert.True(t, ok)
+	})
+
+	t.Run("存在しないデータベースを削除するとエラーになる (IF EXISTS の場合はエラーにしない)", func(t *testing.T) {
+		// GIVEN
+		h, _ := setup(t)
+		defer Reset()
+
+		// WHEN
+		err := h.DropDatabase(0, "other", false)
+		ifExistsErr := h.DropDatabase(0, "other", true)
+
+		// THEN
+		assert.EqualError(t, err, "database other not found")
+		assert.NoError(t, ifExistsErr)
+	})
+	t.Run("他のトランザクションが実行中の場合はエラーになる", func(t *testing.T) {
+		// GIVEN
+		h, _ := setup(t)
+		defer Reset()
+		h.BeginTrx()
+
+		// WHEN
+		err := h.DropDatabase(0, "app", false)
+
+		// THEN
+		assert.EqualError(t, err, "cannot drop database app while other transactions are active")
+		_, ok := h.Catalog.GetDatabaseByName("app")
+		assert.True(t, ok)
+		_, ok = h.Catalog.GetViewByName("app.v")
+		assert.True(t, ok)
+	})
+
+	t.Run("DDL ログを記録した直後にクラッシュしても、再起動時に削除が完了する", func(t *testing.T) {
+		// GIVEN
+		h, tmpdir := setup(t)
+		assert.NoError(t, h.BufferPool.FlushAllPages())
+		assert.NoError(t, h.ddlLog.Write(log.DDLRecord{Op: log.DDLDropDatabase, TableName: "app"}))
+
+		// WHEN: Shutdown を呼ばずに再初期化
+		Reset()
+		h2 := Init()
+		defer Reset()
+
+		// THEN: データベース・テーブル・ビューとサブディレクトリが削除され、DDL ログがクリアされている
+		_, ok := h2.Catalog.GetDatabaseByName("app")
+		assert.False(t, ok)
+		_, ok = h2.Catalog.GetTableMetaByName("app.users")
+		assert.False(t, ok)
+		_, ok = h2.Catalog.GetViewByName("app.v")
+		assert.False(t, ok)
+		_, err := os.Stat(filepath.Join(tmpdir, "app"))
+		assert.True(t, os.IsNotExist(err))
+		_, ok, err = h2.ddlLog.Read()
+		assert.NoError(t, err)
+		assert.False(t, ok)
+	})
+}
@@ -1,34 +1,56 @@
 package handler
 
 import (
+	"fmt"
+
 	"github.com/ren-yamanashi/minesql/internal/storage/access"
+	"github.com/ren-yamanashi/minesql/internal/storage/buffer"
 	"github.com/ren-yamanashi/minesql/internal/storage/dictionary"
+	"github.com/ren-yamanashi/minesql/internal/storage/log"
+	"github.com/ren-yamanashi/minesql/internal/storage/page"
+	"github.com/ren-yamanashi/minesql/internal/storage/recovery"
 )
 
 // CreateIndexParam はインデックス作成パラメータ
 type CreateIndexParam struct {
-	Name    string // インデックス名
-	ColName string // インデックスを構成するカラム名
-	ColIdx  uint16 // インデックスカラムの位置 (0 始まりの列番号)
-	Unique  bool   // ユニーク制約の有無
+	Name     string   // インデックス名
+	ColNames []string // インデックスを構成するカラム名 (複合インデックスの場合はキーの順)
+	ColIdxs  []uint16 // インデックスカラムの位置 (0 始まりの列番号、ColNames と同じ順)
+	Unique   bool     // ユニーク制約の有無
 }
 
 // CreateColumnParam はカラム作成パラメータ
 type CreateColumnParam struct {
-	Name string
-	Type ColumnType
+	Name          string
+	Type          ColumnType
+	Precision     uint8  // DECIMAL の精度 (DECIMAL 以外では 0)
+	Scale         uint8  // DECIMAL のスケール (DECIMAL 以外では 0)
+	NotNull       bool   // NOT NULL 制約の有無
+	AutoIncrement bool   // AUTO_INCREMENT 属性の有無
+	Default       string // DEFAULT 句の式の SQL 表現 (DEFAULT 句を指定していない場合は空文字)
+	DefaultValue  []byte // DEFAULT 句の式を評価した値 (格納形式)。ALTER TABLE ADD COLUMN で既存の行に設定する (nil の場合は NULL)
 }
 
-// CreateConstraintParam は外部キー制約の作成パラメータ
+// CreateConstraintParam は外部キー制約・CHECK 制約の作成パラメータ
 type CreateConstraintParam struct {
-	ConstraintName string // 制約名
-	ColName        string // 外部キーカラム名
-	RefTableName   string // 参照先テーブル名
-	RefColName     string // 参照先カラム名
+	ConstraintName string            // 制約名
+	ColNames       []string          // 外部キーを構成するカラム名 (複合外部キーの場合はキーの順。CHECK 制約の場合は nil)
+	RefTableName   string            // 参照先テーブル名 (CHECK 制約の場合は空文字)
+	RefColNames    []string          // 参照先カラム名 (ColNames と同じ順。CHECK 制約の場合は nil)
+	CheckExpr      string            // CHECK 制約の条件式の SQL 表現 (外部キー制約の場合は空文字)
+	OnDelete       ReferentialAction // 参照先の行を削除したときの動作 (CHECK 制約の場合は RESTRICT)
+	OnUpdate       ReferentialAction // 参照先の行を更新したときの動作 (CHECK 制約の場合は RESTRICT)
 }
 
 // CreateTable はテーブルを新規作成し、カタログに登録する
 func (h *Handler) CreateTable(tableName string, pkCount uint8, idxParams []CreateIndexParam, colParams []CreateColumnParam, constraintParams []CreateConstraintParam) error {
+	if _, ok := h.Catalog.GetViewByName(tableName); ok {
+		return fmt.Errorf("view %s already exists", tableName)
+	}
+	if err := h.checkDatabaseExists(tableName); err != nil {
+		return err
+	}
+
 	// FileId を採番
 	fileId, err := h.Catalog.AllocateFileId(h.BufferPool)
 	if err != nil {
@@ -53,7 +75,7 @@ func (h *Handler) CreateTable(tableName string, pkCount uint8, idxParams []Creat
 		if err != nil {
 			return err
 		}
-		si := access.NewSecondaryIndex(param.Name, param.ColName, indexMetaPageId, param.ColIdx, pkCount, param.Unique)
+		si := access.NewSecondaryIndex(param.Name, param.ColNames, indexMetaPageId, param.ColIdxs, pkCount, param.Unique)
 		if err := si.Create(h.BufferPool); err != nil {
 			return err
 		}
@@ -73,13 +95,18 @@ func (h *Handler) CreateTable(tableName string, pkCount uint8, idxParams []Creat
 		if param.Unique {
 			idxType = dictionary.IndexTypeUnique
 		}
-		idxMeta[i] = dictionary.NewIndexMeta(fileId, secondaryIndexes[i].Name, secondaryIndexes[i].ColName, idxType, secondaryIndexes[i].MetaPageId)
+		idxMeta[i] = dictionary.NewIndexMeta(fileId, secondaryIndexes[i].Name, secondaryIndexes[i].ColNames, idxType, secondaryIndexes[i].MetaPageId)
 	}
 
 	// カラムメタデータを作成
 	colMeta := make([]*dictionary.ColumnMeta, len(colParams))
 	for i, col := range colParams {
 		colMeta[i] = dictionary.NewColumnMeta(fileId, col.Name, uint16(i), col.Type)
+		colMeta[i].Precision = col.Precision
+		colMeta[i].Scale = col.Scale
+		colMeta[i].NotNull = col.NotNull
+		colMeta[i].AutoIncrement = col.AutoIncrement
+		colMeta[i].Default = col.Default
 	}
 
 	// 制約メタデータを作成
@@ -90,20 +117,133 @@ func (h *Handler) CreateTable(tableName string, pkCount uint8, idxParams []Creat
 		conMeta = append(conMeta, dictionary.NewConstraintMeta(fileId, colParams[i].Name, string(dictionary.ConstraintTypePrimaryKey), "", ""))
 	}
 
-	// UK 制約を自動生成
+	// UK 制約を自動生成 (複合 UNIQUE インデックスの場合はカラムごとに作成する)
 	for _, param := range idxParams {
 		if param.Unique {
-			conMeta = append(conMeta, dictionary.NewConstraintMeta(fileId, param.ColName, param.Name, "", ""))
+			conMeta = append(conMeta, uniqueConstraintMetas(fileId, param)...)
 		}
 	}
 
-	// FK 制約を追加
+	// FK 制約・CHECK 制約を追加
 	for _, param := range constraintParams {
-		conMeta = append(conMeta, dictionary.NewConstraintMeta(fileId, param.ColName, param.ConstraintName, param.RefTableName, param.RefColName))
+		if param.CheckExpr != "" {
+			conMeta = append(conMeta, dictionary.NewCheckConstraintMeta(fileId, param.ConstraintName, param.CheckExpr))
+			continue
+		}
+		conMeta = append(conMeta, newForeignKeyConstraintMeta(fileId, param))
 	}
 
 	// テーブルメタデータを作成してカタログに登録
 	tblMeta := dictionary.NewTableMeta(fileId, tableName, uint8(len(colParams)), pkCount, colMeta, idxMeta, metaPageId)
 	tblMeta.Constraints = conMeta
-	return h.Catalog.Insert(h.BufferPool, tblMeta)
+	if err := h.Catalog.Insert(h.BufferPool, tblMeta); err != nil {
+		return err
+	}
+	h.resetAutoIncrement(tableName)
+	return nil
+}
+
+// uniqueConstraintMetas は UNIQUE インデックスの UK 制約メタデータを、インデックスのカラムごとに作成する
+func uniqueConstraintMetas(fileId page.FileId, param CreateIndexParam) []*dictionary.ConstraintMeta {
+	conMeta := make([]*dictionary.ConstraintMeta, len(param.ColNames))
+	for i, colName := range param.ColNames {
+		conMeta[i] = dictionary.NewConstraintMeta(fileId, colName, param.Name, "", "")
+	}
+	return conMeta
+}
+
+// newForeignKeyConstraintMeta は FK 制約のパラメータから FK 制約メタデータを作成する
+func newForeignKeyConstraintMeta(fileId page.FileId, param CreateConstraintParam) *dictionary.ConstraintMeta {
+	conMeta := dictionary.NewForeignKeyConstraintMeta(fileId, param.ColNames, param.ConstraintName, param.RefTableName, param.RefColNames)
+	conMeta.OnDelete = param.OnDelete
+	conMeta.OnUpdate = param.OnUpdate
+	return conMeta
+}
+
+// DropTable はテーブルを削除する
+//
+// カタログからメタデータを削除し、バッファプールからテーブルのページを破棄してヒープファイルを削除する。
+// 他のテーブルの外部キーから参照されているテーブルは削除できない。
+// テーブルを使用した他のトランザクションが実行中の場合はエラーを返す (ロールバック時に削除したテーブルへ UNDO が適用されないようにするため)
+//   - trxId: DROP TABLE を実行するトランザクション
+//   - ifExists: true の場合、テーブルが存在しなくてもエラーにしない
+func (h *Handler) DropTable(trxId TrxId, tableName string, ifExists bool) error {
+	tblMeta, ok := h.Catalog.GetTableMetaByName(tableName)
+	if !ok {
+		if ifExists {
+			return nil
+		}
+		return fmt.Errorf("table %s not found", tableName)
+	}
+	if err := h.checkNotReferenced("drop", tableName); err != nil {
+		return err
+	}
+	if h.trxManager.HasActiveTrxUsingTable(trxId, tableName) {
+		return fmt.Errorf("cannot drop table %s while other transactions are active", tableName)
+	}
+	if err := h.executeDDL(log.DDLRecord{Op: log.DDLDropTable, FileId: tblMeta.FileId, TableName: tableName}); err != nil {
+		return err
+	}
+	h.resetAutoIncrement(tableName)
+	return nil
+}
+
+// TruncateTable はテーブルの全レコードを削除する
+//
+// レコードを 1 件ずつ削除するのではなく、ヒープファイルを切り詰めてテーブルとインデックスの B+Tree を空の状態で作り直す。
+// 他のテーブルの外部キーから参照されているテーブルは切り詰められない。
+// テーブルを使用した他のトランザクションが実行中の場合はエラーを返す (ロールバック時に切り詰めたテーブルへ UNDO が適用されないようにするため)
+//   - trxId: TRUNCATE TABLE を実行するトランザクション
+func (h *Handler) TruncateTable(trxId TrxId, tableName string) error {
+	tblMeta, ok := h.Catalog.GetTableMetaByName(tableName)
+	if !ok {
+		return fmt.Errorf("table %s not found", tableName)
+	}
+	if err := h.checkNotReferenced("truncate", tableName); err != nil {
+		return err
+	}
+	if h.trxManager.HasActiveTrxUsingTable(trxId, tableName) {
+		return fmt.Errorf("cannot truncate table %s while other transactions are active", tableName)
+	}
+	if err := h.executeDDL(log.DDLRecord{Op: log.DDLTruncateTable, FileId: tblMeta.FileId, TableName: tableName}); err != nil {
+		return err
+	}
+	// MySQL と同様に、切り詰めたテーブルの AUTO_INCREMENT は 1 から採番し直す
+	h.resetAutoIncrement(tableName)
+	return nil
+}
+
+// checkNotReferenced は指定されたテーブルが他のテーブルの外部キーから参照されていないことを確認する (自己参照は除く)
+func (h *Handler) checkNotReferenced(op string, tableName string) error {
+	for _, fk := range h.Catalog.GetForeignKeysReferencingTable(tableName) {
+		if fk.TableName != tableName {
+			return fmt.Errorf("cannot %s table %s: referenced by foreign key %s on table %s", op, tableName, fk.Constraint.ConstraintName, fk.TableName)
+		}
+	}
+	return nil
+}
+
+// executeDDL は DROP TABLE / TRUNCATE TABLE / DROP DATABASE を実行する
+//
+//  1. DDL ログに対象を記録 (以降でクラッシュした場合、再起動時のリカバリで最後まで実行される)
+//  2. DDL を実行 (実行後、変更したページはすべてフラッシュされている)
+//  3. チェックポイントを実行 (削除・切り詰め前のページの REDO レコードがリカバリで適用されないようにする)
+//  4. DDL ログをクリア
+func (h *Handler) executeDDL(record log.DDLRecord) error {
+	// パージスレッドが削除・切り詰め中のテーブルを走査しないように停止する
+	h.purgeThread.Pause()
+	defer h.purgeThread.Resume()
+
+	if err := h.ddlLog.Write(record); err != nil {
+		return err
+	}
+	if err := recovery.ApplyDDL(h.BufferPool, h.Catalog, h.baseDirectory, record); err != nil {
+		return err
+	}
+	h.StatsCollector.Invalidate(record.TableName)
+
+	if err := buffer.NewCheckpoint(h.BufferPool, h.redoLog).Execute(); err != nil {
+		return err
+	}
+	return h.ddlLog.Clear()
 }
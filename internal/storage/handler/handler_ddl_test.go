@@ -1,10 +1,17 @@
 package handler
 
 import (
+	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
 
+	"github.com/ren-yamanashi/minesql/internal/storage/access"
+	"github.com/ren-yamanashi/minesql/internal/storage/dictionary"
 	"github.com/ren-yamanashi/minesql/internal/storage/lock"
+	"github.com/ren-yamanashi/minesql/internal/storage/page"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestCreateTable(t *testing.T) {
@@ -48,7 +55,7 @@ func TestCreateTable(t *testing.T) {
 		// WHEN
 		err := h.CreateTable("users", 1,
 			[]CreateIndexParam{
-				{Name: "idx_email", ColName: "email", ColIdx: 1, Unique: true},
+				{Name: "idx_email", ColNames: []string{"email"}, ColIdxs: []uint16{1}, Unique: true},
 			},
 			[]CreateColumnParam{
 				{Name: "id", Type: ColumnTypeString},
@@ -83,7 +90,7 @@ func TestCreateTable(t *testing.T) {
 		// WHEN
 		err := h.CreateTable("products", 1,
 			[]CreateIndexParam{
-				{Name: "idx_category", ColName: "category", ColIdx: 1, Unique: false},
+				{Name: "idx_category", ColNames: []string{"category"}, ColIdxs: []uint16{1}, Unique: false},
 			},
 			[]CreateColumnParam{
 				{Name: "id", Type: ColumnTypeString},
@@ -116,8 +123,8 @@ func TestCreateTable(t *testing.T) {
 		// WHEN
 		err := h.CreateTable("products", 1,
 			[]CreateIndexParam{
-				{Name: "idx_name", ColName: "name", ColIdx: 1, Unique: true},
-				{Name: "idx_category", ColName: "category", ColIdx: 2, Unique: false},
+				{Name: "idx_name", ColNames: []string{"name"}, ColIdxs: []uint16{1}, Unique: true},
+				{Name: "idx_category", ColNames: []string{"category"}, ColIdxs: []uint16{2}, Unique: false},
 			},
 			[]CreateColumnParam{
 				{Name: "id", Type: ColumnTypeString},
@@ -151,7 +158,7 @@ func TestCreateTable(t *testing.T) {
 
 		err := h.CreateTable("products", 1,
 			[]CreateIndexParam{
-				{Name: "idx_category", ColName: "category", ColIdx: 1, Unique: false},
+				{Name: "idx_category", ColNames: []string{"category"}, ColIdxs: []uint16{1}, Unique: false},
 			},
 			[]CreateColumnParam{
 				{Name: "id", Type: ColumnTypeString},
@@ -216,8 +223,8 @@ func TestCreateTable(t *testing.T) {
 		// WHEN: PK + UK + FK 制約を持つテーブルを作成
 		err = h.CreateTable("orders", 1,
 			[]CreateIndexParam{
-				{Name: "idx_code", ColName: "code", ColIdx: 1, Unique: true},
-				{Name: "idx_user_id", ColName: "user_id", ColIdx: 2, Unique: false},
+				{Name: "idx_code", ColNames: []string{"code"}, ColIdxs: []uint16{1}, Unique: true},
+				{Name: "idx_user_id", ColNames: []string{"user_id"}, ColIdxs: []uint16{2}, Unique: false},
 			},
 			[]CreateColumnParam{
 				{Name: "id", Type: ColumnTypeString},
@@ -225,7 +232,7 @@ func TestCreateTable(t *testing.T) {
 				{Name: "user_id", Type: ColumnTypeString},
 			},
 			[]CreateConstraintParam{
-				{ConstraintName: "fk_user", ColName: "user_id", RefTableName: "users", RefColName: "id"},
+				{ConstraintName: "fk_user", ColNames: []string{"user_id"}, RefTableName: "users", RefColNames: []string{"id"}},
 			},
 		)
 
@@ -277,14 +284,14 @@ func TestCreateTable(t *testing.T) {
 		// WHEN: FK 制約付きの子テーブルを作成
 		err = h.CreateTable("orders", 1,
 			[]CreateIndexParam{
-				{Name: "idx_user_id", ColName: "user_id", ColIdx: 1, Unique: false},
+				{Name: "idx_user_id", ColNames: []string{"user_id"}, ColIdxs: []uint16{1}, Unique: false},
 			},
 			[]CreateColumnParam{
 				{Name: "id", Type: ColumnTypeString},
 				{Name: "user_id", Type: ColumnTypeString},
 			},
 			[]CreateConstraintParam{
-				{ConstraintName: "fk_user", ColName: "user_id", RefTableName: "users", RefColName: "id"},
+				{ConstraintName: "fk_user", ColNames: []string{"user_id"}, RefTableName: "users", RefColNames: []string{"id"}},
 			},
 		)
 
@@ -309,4 +316,393 @@ func TestCreateTable(t *testing.T) {
 		assert.Equal(t, 1, len(fks))
 		assert.Equal(t, "fk_user", fks[0].ConstraintName)
 	})
+
+	t.Run("DEFAULT 値と CHECK 制約付きのテーブルを作成できる", func(t *testing.T) {
+		// GIVEN
+		tmpdir := t.TempDir()
+		t.Setenv("MINESQL_DATA_DIR", tmpdir)
+		t.Setenv("MINESQL_BUFFER_SIZE", "100")
+		Reset()
+		h := Init()
+
+		// WHEN
+		err := h.CreateTable("users", 1, nil, []CreateColumnParam{
+			{Name: "id", Type: ColumnTypeString},
+			{Name: "age", Type: ColumnTypeInt, Default: "20", DefaultValue: []byte("20")},
+		}, []CreateConstraintParam{
+			{ConstraintName: "users_chk_1", CheckExpr: "age >= 0"},
+		})
+
+		// THEN
+		assert.NoError(t, err)
+		meta, ok := h.Catalog.GetTableMetaByName("users")
+		assert.True(t, ok)
+		col, ok := meta.GetColByName("age")
+		assert.True(t, ok)
+		assert.Equal(t, "20", col.Default)
+
+		// GetCheckConstraints で CHECK 制約のみ取得できる
+		checks := meta.GetCheckConstraints()
+		assert.Equal(t, 1, len(checks))
+		assert.Equal(t, "users_chk_1", checks[0].ConstraintName)
+		assert.Equal(t, "age >= 0", checks[0].CheckExpr)
+		assert.Empty(t, meta.GetForeignKeyConstraints())
+	})
+}
+
+func TestDropTable(t *testing.T) {
+	t.Run("テーブルを削除でき、カタログ・Disk の登録・ヒープファイルが削除される", func(t *testing.T) {
+		// GIVEN
+		tmpdir := t.TempDir()
+		t.Setenv("MINESQL_DATA_DIR", tmpdir)
+		t.Setenv("MINESQL_BUFFER_SIZE", "100")
+		Reset()
+		h := Init()
+		createUsersAndOrders(t, h)
+		insertUsers(t, h, "1", "2", "3")
+		usersMeta, ok := h.Catalog.GetTableMetaByName("users")
+		require.True(t, ok)
+		fileId := usersMeta.FileId
+
+		// WHEN: 子テーブルから先に削除する
+		err := h.DropTable(0, "orders", false)
+		assert.NoError(t, err)
+		err = h.DropTable(0, "users", false)
+
+		// THEN
+		assert.NoError(t, err)
+		_, ok = h.Catalog.GetTableMetaByName("users")
+		assert.False(t, ok)
+		_, err = h.BufferPool.GetDisk(fileId)
+		assert.Error(t, err)
+		_, err = os.Stat(filepath.Join(tmpdir, "users.db"))
+		assert.True(t, os.IsNotExist(err))
+
+		// 再起動後もテーブルは存在しない
+		assert.NoError(t, h.Shutdown())
+		Reset()
+		h2 := Init()
+		_, ok = h2.Catalog.GetTableMetaByName("users")
+		assert.False(t, ok)
+		assert.NoError(t, h2.Shutdown())
+	})
+
+	t.Run("削除したテーブルと同じ名前のテーブルを作成できる", func(t *testing.T) {
+		// GIVEN
+		tmpdir := t.TempDir()
+		t.Setenv("MINESQL_DATA_DIR", tmpdir)
+		t.Setenv("MINESQL_BUFFER_SIZE", "100")
+		Reset()
+		h := Init()
+		createUsersAndOrders(t, h)
+		insertUsers(t, h, "1", "2", "3")
+		require.NoError(t, h.DropTable(0, "orders", false))
+		require.NoError(t, h.DropTable(0, "users", false))
+
+		// WHEN
+		err := h.CreateTable("users", 1, nil, []CreateColumnParam{
+			{Name: "id", Type: ColumnTypeString},
+			{Name: "name", Type: ColumnTypeString},
+		}, nil)
+
+		// THEN: 新しいテーブルは空
+		assert.NoError(t, err)
+		assert.Empty(t, scanUsers(t, h))
+	})
+
+	t.Run("存在しないテーブルを削除するとエラーになる", func(t *testing.T) {
+		// GIVEN
+		tmpdir := t.TempDir()
+		t.Setenv("MINESQL_DATA_DIR", tmpdir)
+		t.Setenv("MINESQL_BUFFER_SIZE", "100")
+		Reset()
+		h := Init()
+
+		// WHEN
+		err := h.DropTable(0, "users", false)
+
+		// THEN
+		assert.ErrorContains(t, err, "table users not found")
+	})
+
+	t.Run("IF EXISTS 指定時は存在しないテーブルを削除してもエラーにならない", func(t *testing.T) {
+		// GIVEN
+		tmpdir := t.TempDir()
+		t.Setenv("MINESQL_DATA_DIR", tmpdir)
+		t.Setenv("MINESQL_BUFFER_SIZE", "100")
+		Reset()
+		h := Init()
+
+		// WHEN
+		err := h.DropTable(0, "users", true)
+
+		// THEN
+		assert.NoError(t, err)
+	})
+
+	t.Run("外部キーから参照されているテーブルは削除できない", func(t *testing.T) {
+		// GIVEN
+		tmpdir := t.TempDir()
+		t.Setenv("MINESQL_DATA_DIR", tmpdir)
+		t.Setenv("MINESQL_BUFFER_SIZE", "100")
+		Reset()
+		h := Init()
+		createUsersAndOrders(t, h)
+
+		// WHEN
+		err := h.DropTable(0, "users", false)
+
+		// THEN
+		assert.EqualError(t, err, "cannot drop table users: referenced by foreign key fk_user on table orders")
+		_, ok := h.Catalog.GetTableMetaByName("users")
+		assert.True(t, ok)
+	})
+
+	t.Run("テーブルを使用した他のトランザクションが実行中の場合はエラーになる", func(t *testing.T) {
+		// GIVEN
+		tmpdir := t.TempDir()
+		t.Setenv("MINESQL_DATA_DIR", tmpdir)
+		t.Setenv("MINESQL_BUFFER_SIZE", "100")
+		Reset()
+		h := Init()
+		createUsersAndOrders(t, h)
+		trxId := h.BeginTrx()
+		_, err := h.OpenTable(trxId, "orders")
+		require.NoError(t, err)
+
+		// WHEN
+		err = h.DropTable(0, "orders", false)
+
+		// THEN
+		assert.EqualError(t, err, "cannot drop table orders while other transactions are active")
+		_, ok := h.Catalog.GetTableMetaByName("orders")
+		assert.True(t, ok)
+	})
+
+	t.Run("他のテーブルだけを使用したトランザクションが実行中でも削除できる", func(t *testing.T) {
+		// GIVEN
+		tmpdir := t.TempDir()
+		t.Setenv("MINESQL_DATA_DIR", tmpdir)
+		t.Setenv("MINESQL_BUFFER_SIZE", "100")
+		Reset()
+		h := Init()
+		createUsersAndOrders(t, h)
+		trxId := h.BeginTrx()
+		_, err := h.OpenTable(trxId, "users")
+		require.NoError(t, err)
+
+		// WHEN
+		err = h.DropTable(0, "orders", false)
+
+		// THEN
+		assert.NoError(t, err)
+		_, ok := h.Catalog.GetTableMetaByName("orders")
+		assert.False(t, ok)
+		assert.NoError(t, h.CommitTrx(trxId))
+	})
+}
+
+func TestTruncateTable(t *testing.T) {
+	t.Run("テーブルの全レコードを削除でき、再びレコードを挿入できる", func(t *testing.T) {
+		// GIVEN
+		tmpdir := t.TempDir()
+		t.Setenv("MINESQL_DATA_DIR", tmpdir)
+		t.Setenv("MINESQL_BUFFER_SIZE", "100")
+		Reset()
+		h := Init()
+		require.NoError(t, h.CreateTable("users", 1,
+			[]CreateIndexParam{{Name: "idx_name", ColNames: []string{"name"}, ColIdxs: []uint16{1}, Unique: true}},
+			[]CreateColumnParam{
+				{Name: "id", Type: ColumnTypeString},
+				{Name: "name", Type: ColumnTypeString},
+			}, nil))
+		insertUsers(t, h, "1", "2", "3")
+
+		// WHEN
+		err := h.TruncateTable(0, "users")
+
+		// THEN
+		assert.NoError(t, err)
+		assert.Empty(t, scanUsers(t, h))
+
+		// ユニークインデックスも空になっているため、同じ値を再び挿入できる
+		insertUsers(t, h, "1")
+		assert.Equal(t, [][]byte{[]byte("1"), []byte("user-1")}, scanUsers(t, h)[0])
+
+		// 再起動後も切り詰め後の状態が保たれる
+		assert.NoError(t, h.Shutdown())
+		Reset()
+		h2 := Init()
+		assert.Len(t, scanUsers(t, h2), 1)
+		assert.NoError(t, h2.Shutdown())
+	})
+
+	t.Run("後から追加したインデックスがあっても、ヒープファイルはメタページとルートノードの分まで縮む", func(t *testing.T) {
+		// GIVEN
+		tmpdir := t.TempDir()
+		t.Setenv("MINESQL_DATA_DIR", tmpdir)
+		t.Setenv("MINESQL_BUFFER_SIZE", "100")
+		Reset()
+		h := Init()
+		require.NoError(t, h.CreateTable("users", 1, nil, []CreateColumnParam{
+			{Name: "id", Type: ColumnTypeString},
+			{Name: "name", Type: ColumnTypeString},
+		}, nil))
+		ids := make([]string, 500)
+		for i := range ids {
+			ids[i] = fmt.Sprintf("%04d", i)
+		}
+		insertUsers(t, h, ids...)
+		require.NoError(t, h.AddIndex(0, "users", CreateIndexParam{Name: "idx_name", ColNames: []string{"name"}, Unique: true}))
+		path := dictionary.TableFilePath(tmpdir, "users")
+
+		// WHEN
+		err := h.TruncateTable(0, "users")
+
+		// THEN
+		assert.NoError(t, err)
+		// テーブルとインデックスそれぞれのメタページとルートノード
+		info, err := os.Stat(path)
+		require.NoError(t, err)
+		assert.Equal(t, int64(4*page.PageSize), info.Size())
+
+		// 再起動後もインデックスを含めて利用できる
+		assert.NoError(t, h.Shutdown())
+		Reset()
+		h2 := Init()
+		insertUsers(t, h2, "1")
+		assert.Len(t, scanUsers(t, h2), 1)
+		tbl, err := h2.GetTable("users")
+		require.NoError(t, err)
+		si, err := tbl.GetSecondaryIndexByName("idx_name")
+		require.NoError(t, err)
+		iter, err := si.Search(h2.BufferPool, tbl, access.RecordSearchModeKey{Key: [][]byte{[]byte("user-1")}})
+		require.NoError(t, err)
+		result, ok, err := iter.Next()
+		require.NoError(t, err)
+		require.True(t, ok)
+		assert.Equal(t, [][]byte{[]byte("1"), []byte("user-1")}, result.Record)
+		assert.NoError(t, h2.Shutdown())
+	})
+
+	t.Run("存在しないテーブルを切り詰めるとエラーになる", func(t *testing.T) {
+		// GIVEN
+		tmpdir := t.TempDir()
+		t.Setenv("MINESQL_DATA_DIR", tmpdir)
+		t.Setenv("MINESQL_BUFFER_SIZE", "100")
+		Reset()
+		h := Init()
+
+		// WHEN
+		err := h.TruncateTable(0, "users")
+
+		// THEN
+		assert.ErrorContains(t, err, "table users not found")
+	})
+
+	t.Run("外部キーから参照されているテーブルは切り詰められない", func(t *testing.T) {
+		// GIVEN
+		tmpdir := t.TempDir()
+		t.Setenv("MINESQL_DATA_DIR", tmpdir)
+		t.Setenv("MINESQL_BUFFER_SIZE", "100")
+		Reset()
+		h := Init()
+		createUsersAndOrders(t, h)
+		insertUsers(t, h, "1")
+
+		// WHEN
+		err := h.TruncateTable(0, "users")
+
+		// THEN
+		assert.EqualError(t, err, "cannot truncate table users: referenced by foreign key fk_user on table orders")
+		assert.Len(t, scanUsers(t, h), 1)
+	})
+
+	t.Run("テーブルを使用した他のトランザクションが実行中の場合はエラーになる", func(t *testing.T) {
+		// GIVEN
+		tmpdir := t.TempDir()
+		t.Setenv("MINESQL_DATA_DIR", tmpdir)
+		t.Setenv("MINESQL_BUFFER_SIZE", "100")
+		Reset()
+		h := Init()
+		createUsersAndOrders(t, h)
+		insertUsers(t, h, "1")
+		trxId := h.BeginTrx()
+		_, err := h.OpenTable(trxId, "orders")
+		require.NoError(t, err)
+
+		// WHEN
+		err = h.TruncateTable(0, "orders")
+
+		// THEN
+		assert.EqualError(t, err, "cannot truncate table orders while other transactions are active")
+	})
+
+	t.Run("他のテーブルだけを使用したトランザクションが実行中でも切り詰められる", func(t *testing.T) {
+		// GIVEN
+		tmpdir := t.TempDir()
+		t.Setenv("MINESQL_DATA_DIR", tmpdir)
+		t.Setenv("MINESQL_BUFFER_SIZE", "100")
+		Reset()
+		h := Init()
+		createUsersAndOrders(t, h)
+		insertUsers(t, h, "1")
+		trxId := h.BeginTrx()
+		_, err := h.OpenTable(trxId, "users")
+		require.NoError(t, err)
+
+		// WHEN
+		err = h.TruncateTable(0, "orders")
+
+		// THEN
+		assert.NoError(t, err)
+		assert.NoError(t, h.CommitTrx(trxId))
+	})
+}
+
+// createUsersAndOrders は users テーブルと、users を外部キーで参照する orders テーブルを作成する
+func createUsersAndOrders(t *testing.T, h *Handler) {
+	t.Helper()
+	require.NoError(t, h.CreateTable("users", 1, nil, []CreateColumnParam{
+		{Name: "id", Type: ColumnTypeString},
+		{Name: "name", Type: ColumnTypeString},
+	}, nil))
+	require.NoError(t, h.CreateTable("orders", 1,
+		[]CreateIndexParam{{Name: "idx_user_id", ColNames: []string{"user_id"}, ColIdxs: []uint16{1}, Unique: false}},
+		[]CreateColumnParam{
+			{Name: "id", Type: ColumnTypeString},
+			{Name: "user_id", Type: ColumnTypeString},
+		},
+		[]CreateConstraintParam{{ConstraintName: "fk_user", ColNames: []string{"user_id"}, RefTableName: "users", RefColNames: []string{"id"}}},
+	))
+}
+
+// insertUsers は users テーブルに (id, "user-<id>") のレコードを挿入してコミットする
+func insertUsers(t *testing.T, h *Handler, ids ...string) {
+	t.Helper()
+	tbl, err := h.GetTable("users")
+	require.NoError(t, err)
+	trxId := h.BeginTrx()
+	for _, id := range ids {
+		require.NoError(t, tbl.Insert(h.BufferPool, trxId, h.LockMgr, [][]byte{[]byte(id), []byte("user-" + id)}))
+	}
+	require.NoError(t, h.CommitTrx(trxId))
+}
+
+// scanUsers は users テーブルの全レコードを返す
+func scanUsers(t *testing.T, h *Handler) [][][]byte {
+	t.Helper()
+	tbl, err := h.GetTable("users")
+	require.NoError(t, err)
+	iter, err := tbl.Search(h.BufferPool, access.NewReadView(0, nil, ^uint64(0)), access.NewVersionReader(nil), access.RecordSearchModeStart{})
+	require.NoError(t, err)
+	var records [][][]byte
+	for {
+		record, ok, err := iter.Next()
+		require.NoError(t, err)
+		if !ok {
+			return records
+		}
+		records = append(records, record)
+	}
 }
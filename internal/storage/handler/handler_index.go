@@ -0,0 +1,197 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ren-yamanashi/minesql/internal/storage/access"
+	"github.com/ren-yamanashi/minesql/internal/storage/btree"
+	"github.com/ren-yamanashi/minesql/internal/storage/buffer"
+	"github.com/ren-yamanashi/minesql/internal/storage/config"
+	"github.com/ren-yamanashi/minesql/internal/storage/dictionary"
+	"github.com/ren-yamanashi/minesql/internal/storage/page"
+)
+
+// indexBuildPollInterval はインデックスのオンライン作成で、他のトランザクションの終了を確認する間隔
+const indexBuildPollInterval = 10 * time.Millisecond
+
+// AddIndex はテーブルにセカンダリインデックスをオンラインで追加する
+//
+// インデックスの作成中もテーブルへの読み書きは止めず、作成中の行の変更は行ログに記録して作成後に適用する
+//  1. パージスレッドを停止し、テーブルの行ログを登録する (以降のテーブルへの行の変更が記録される)
+//  2. テーブルの全レコードからインデックスの B+Tree をボトムアップで構築する
+//  3. テーブルを使用した他のトランザクションがすべて終了するまで、行ログを適用しながら待つ
+//     (実行中のトランザクションはインデックスを含まないテーブル定義で行を変更・ロールバックするため)
+//  4. テーブルの行の変更と Table の構築を止めたまま、行ログを適用して UNIQUE の重複を確認し、カタログにインデックスを登録して行ログの登録を解除する
+//     (止めている間に開始したトランザクションも、再開後はインデックスを含むテーブル定義で行を変更する)
+//  5. 変更したページをすべてフラッシュし、チェックポイントを実行する
+//
+// UNIQUE の場合は UNIQUE 制約も追加し、重複する値がある場合はエラーを返す。
+// カタログへの登録までに失敗した場合は、構築したインデックスの B+Tree のページを解放する
+func (h *Handler) AddIndex(trxId TrxId, tableName string, idxParam CreateIndexParam) error {
+	tblMeta, ok := h.Catalog.GetTableMetaByName(tableName)
+	if !ok {
+		return fmt.Errorf("table %s not found", tableName)
+	}
+	if _, exists := tblMeta.GetIndexByName(idxParam.Name); exists {
+		return fmt.Errorf("duplicate index name: %s", idxParam.Name)
+	}
+	colIdxs, err := tblMeta.GetColPositions(idxParam.ColNames)
+	if err != nil {
+		return err
+	}
+
+	// 作成中に delete-marked レコードが物理削除されると、構築済みのエントリが残ってしまうためパージを止める
+	h.purgeThread.Pause()
+	defer h.purgeThread.Resume()
+	rowLog := h.rowLogs.Register(tableName)
+	defer h.rowLogs.Unregister(tableName, rowLog)
+
+	tbl, err := buildTable(tblMeta, h.undoLog, h.redoLog, nil)
+	if err != nil {
+		return err
+	}
+	indexMetaPageId, err := h.BufferPool.AllocatePageId(tblMeta.FileId)
+	if err != nil {
+		return err
+	}
+	si := access.NewSecondaryIndex(idxParam.Name, idxParam.ColNames, indexMetaPageId, colIdxs, tblMeta.PKCount, idxParam.Unique)
+	builder := access.NewIndexBuilder(si, tbl, rowLog)
+	if err := builder.Build(h.BufferPool); err != nil {
+		// 構築途中のノードのページは Build 内で解放されるため、メタページのみ解放する
+		_ = h.BufferPool.FreePages([]page.PageId{indexMetaPageId})
+		return err
+	}
+	unlock, err := h.waitForOtherTrxs(trxId, tableName, builder)
+	if err != nil {
+		h.freeIndexPages(indexMetaPageId)
+		return err
+	}
+	err = h.registerIndex(tableName, tblMeta, idxParam, indexMetaPageId, builder)
+	// 行ログの登録を解除してから行の変更を再開する (以降の変更はインデックスを含むテーブル定義で行われる)
+	h.rowLogs.Unregister(tableName, rowLog)
+	unlock()
+	if err != nil {
+		h.freeIndexPages(indexMetaPageId)
+		return err
+	}
+	h.StatsCollector.Invalidate(tableName)
+
+	if err := h.BufferPool.FlushAllPages(); err != nil {
+		return err
+	}
+	return buffer.NewCheckpoint(h.BufferPool, h.redoLog).Execute()
+}
+
+// registerIndex は行ログを適用し、UNIQUE の重複を確認してからカタログにインデックスを登録する
+//
+// テーブルの行の変更を止めた状態で呼び出す (適用後に記録された行の変更がインデックスに反映されなくなるため)
+func (h *Handler) registerIndex(tableName string, tblMeta *dictionary.TableMeta, idxParam CreateIndexParam, indexMetaPageId page.PageId, builder *access.IndexBuilder) error {
+	if err := builder.ApplyRowLog(h.BufferPool); err != nil {
+		return err
+	}
+	if err := builder.CheckUnique(h.BufferPool); err != nil {
+		if errors.Is(err, btree.ErrDuplicateKey) {
+			return fmt.Errorf("cannot add unique key %s: duplicate values in %s", idxParam.Name, describeColumns(idxParam.ColNames))
+		}
+		return err
+	}
+
+	newMeta := tblMeta.Clone()
+	idxType := dictionary.IndexTypeNonUnique
+	if idxParam.Unique {
+		idxType = dictionary.IndexTypeUnique
+		newMeta.Constraints = append(newMeta.Constraints, uniqueConstraintMetas(newMeta.FileId, idxParam)...)
+	}
+	newMeta.Indexes = append(newMeta.Indexes, dictionary.NewIndexMeta(newMeta.FileId, idxParam.Name, idxParam.ColNames, idxType, indexMetaPageId))
+	return h.Catalog.Update(h.BufferPool, newMeta)
+}
+
+// freeIndexPages は作成に失敗したインデックスの B+Tree のページ (メタページを含む) を解放する
+func (h *Handler) freeIndexPages(metaPageId page.PageId) {
+	pageIds, err := btree.NewBTree(metaPageId).PageIds(h.BufferPool)
+	if err != nil {
+		pageIds = []page.PageId{metaPageId}
+	}
+	_ = h.BufferPool.FreePages(pageIds)
+}
+
+// DropIndex はテーブルからセカンダリインデックスを削除する
+//
+// UNIQUE の場合は UNIQUE 制約も削除する。外部キーの検査に必要なインデックス (代わりになるインデックスがないもの) は削除できない
+func (h *Handler) DropIndex(trxId TrxId, tableName string, indexName string) error {
+	return h.alterTable(trxId, tableName, func(tblMeta *dictionary.TableMeta) error {
+		idx := slices.IndexFunc(tblMeta.Indexes, func(idx *dictionary.IndexMeta) bool { return idx.Name == indexName })
+		if idx < 0 {
+			return fmt.Errorf("index %s not found in table %s", indexName, tableName)
+		}
+		idxMeta := tblMeta.Indexes[idx]
+		others := slices.Delete(slices.Clone(tblMeta.Indexes), idx, idx+1)
+
+		// 外部キーカラムのインデックスは、INSERT 時の参照先の検査と親テーブルの DELETE 時の参照元の検索に使用する
+		for _, fk := range tblMeta.GetForeignKeyConstraints() {
+			if !idxMeta.HasLeadingColNames(fk.ColNames) {
+				continue
+			}
+			hasOther := slices.ContainsFunc(others, func(other *dictionary.IndexMeta) bool { return other.HasLeadingColNames(fk.ColNames) })
+			if !hasOther {
+				return fmt.Errorf("cannot drop index %s: needed by foreign key %s", indexName, fk.ConstraintName)
+			}
+		}
+		// 参照先カラムがプライマリキーでない場合、UNIQUE インデックスで参照先の値を検索する
+		if idxMeta.Type == dictionary.IndexTypeUnique {
+			for _, fk := range h.Catalog.GetForeignKeysReferencingTable(tableName) {
+				if !slices.Equal(idxMeta.ColNames, fk.Constraint.RefColNames) || tblMeta.IsPrimaryKey(fk.Constraint.RefColNames) {
+					continue
+				}
+				hasOther := slices.ContainsFunc(others, func(other *dictionary.IndexMeta) bool {
+					return other.Type == dictionary.IndexTypeUnique && slices.Equal(other.ColNames, fk.Constraint.RefColNames)
+				})
+				if !hasOther {
+					return fmt.Errorf("cannot drop index %s: referenced by foreign key %s on table %s", indexName, fk.Constraint.ConstraintName, fk.TableName)
+				}
+			}
+		}
+
+		tblMeta.Indexes = others
+		tblMeta.Constraints = slices.DeleteFunc(tblMeta.Constraints, func(con *dictionary.ConstraintMeta) bool {
+			return con.ConstraintName == indexName && con.RefTableName == ""
+		})
+		return nil
+	})
+}
+
+// waitForOtherTrxs はテーブルを使用した他のトランザクションがすべて終了するまで、行ログを適用しながら待つ
+//
+// 終了を確認したら、テーブルの行の変更と Table の構築を止めたまま、再開する関数を返す
+// (確認後にテーブルを使用し始めたトランザクションがインデックスを含まないテーブル定義で行を変更すると、その変更がインデックスに反映されないため)
+// lock wait timeout を超えても終了しない場合はエラーを返す
+func (h *Handler) waitForOtherTrxs(trxId TrxId, tableName string, builder *access.IndexBuilder) (unlock func(), err error) {
+	deadline := time.Now().Add(time.Duration(config.GetLockWaitTimeout()) * time.Millisecond)
+	for {
+		unlock := h.rowLogs.LockTable(tableName)
+		if !h.trxManager.HasActiveTrxUsingTable(trxId, tableName) {
+			return unlock, nil
+		}
+		unlock()
+
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("cannot add index to table %s: lock wait timeout exceeded while waiting for active transactions", tableName)
+		}
+		if err := builder.ApplyRowLog(h.BufferPool); err != nil {
+			return nil, err
+		}
+		time.Sleep(indexBuildPollInterval)
+	}
+}
+
+// describeColumns はエラーメッセージ用にカラム名の一覧を表す
+func describeColumns(colNames []string) string {
+	if len(colNames) == 1 {
+		return "column " + colNames[0]
+	}
+	return "columns (" + strings.Join(colNames, ", ") + ")"
+}
@@ -0,0 +1,390 @@
+package handler
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ren-yamanashi/minesql/internal/storage/access"
+	"github.com/ren-yamanashi/minesql/internal/storage/page"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddIndex(t *testing.T) {
+	t.Run("既存の行からインデックスを作成できる", func(t *testing.T) {
+		// GIVEN
+		h := setupAlterTable(t)
+		insertUsers(t, h, "1", "2")
+
+		// WHEN
+		err := h.AddIndex(0, "users", CreateIndexParam{Name: "idx_name", ColNames: []string{"name"}, Unique: true})
+
+		// THEN
+		assert.NoError(t, err)
+		meta, _ := h.Catalog.GetTableMetaByName("users")
+		idx, ok := meta.GetIndexByColName("name")
+		require.True(t, ok)
+		assert.Equal(t, "idx_name", idx.Name)
+		assert.Len(t, meta.Constraints, 2)
+		assert.Equal(t, "idx_name", meta.Constraints[1].ConstraintName)
+
+		tbl, err := h.GetTable("users")
+		require.NoError(t, err)
+		si, err := tbl.GetSecondaryIndexByName("idx_name")
+		require.NoError(t, err)
+		iter, err := si.Search(h.BufferPool, tbl, access.RecordSearchModeKey{Key: [][]byte{[]byte("user-2")}})
+		require.NoError(t, err)
+		result, ok, err := iter.Next()
+		require.NoError(t, err)
+		require.True(t, ok)
+		assert.Equal(t, [][]byte{[]byte("2"), []byte("user-2")}, result.Record)
+	})
+
+	t.Run("UNIQUE のインデックスで値が重複している場合はエラーを返し、テーブルは変更されない", func(t *testing.T) {
+		// GIVEN
+		h := setupAlterTable(t)
+		tbl, err := h.GetTable("users")
+		require.NoError(t, err)
+		trxId := h.BeginTrx()
+		require.NoError(t, tbl.Insert(h.BufferPool, trxId, h.LockMgr, [][]byte{[]byte("1"), []byte("alice")}))
+		require.NoError(t, tbl.Insert(h.BufferPool, trxId, h.LockMgr, [][]byte{[]byte("2"), []byte("alice")}))
+		require.NoError(t, h.CommitTrx(trxId))
+
+		// WHEN
+		err = h.AddIndex(0, "users", CreateIndexParam{Name: "idx_name", ColNames: []string{"name"}, Unique: true})
+
+		// THEN
+		assert.EqualError(t, err, "cannot add unique key idx_name: duplicate values in column name")
+		meta, _ := h.Catalog.GetTableMetaByName("users")
+		assert.Empty(t, meta.Indexes)
+	})
+
+	t.Run("UNIQUE の重複で作成に失敗した場合は、構築した B+Tree のページを解放する", func(t *testing.T) {
+		// GIVEN
+		h := setupAlterTable(t)
+		tbl, err := h.GetTable("users")
+		require.NoError(t, err)
+		trxId := h.BeginTrx()
+		require.NoError(t, tbl.Insert(h.BufferPool, trxId, h.LockMgr, [][]byte{[]byte("1"), []byte("alice")}))
+		require.NoError(t, tbl.Insert(h.BufferPool, trxId, h.LockMgr, [][]byte{[]byte("2"), []byte("alice")}))
+		require.NoError(t, h.CommitTrx(trxId))
+
+		// WHEN
+		err = h.AddIndex(0, "users", CreateIndexParam{Name: "idx_name", ColNames: []string{"name"}, Unique: true})
+
+		// THEN: 解放したページ ID (メタページ・ルート) が後に採番したものから順に再利用される (新しく採番する場合は昇順になる)
+		require.Error(t, err)
+		meta, _ := h.Catalog.GetTableMetaByName("users")
+		first, err := h.BufferPool.AllocatePageId(meta.FileId)
+		require.NoError(t, err)
+		second, err := h.BufferPool.AllocatePageId(meta.FileId)
+		require.NoError(t, err)
+		assert.Greater(t, first.PageNumber, second.PageNumber)
+		assert.False(t, h.BufferPool.IsPageCached(first))
+		assert.False(t, h.BufferPool.IsPageCached(second))
+		require.NoError(t, h.BufferPool.FreePages([]page.PageId{second, first}))
+
+		// 解放したページを再利用して、同じカラムにインデックスを作成できる
+		require.NoError(t, h.AddIndex(0, "users", CreateIndexParam{Name: "idx_name", ColNames: []string{"name"}}))
+		tbl, err = h.GetTable("users")
+		require.NoError(t, err)
+		si, err := tbl.GetSecondaryIndexByName("idx_name")
+		require.NoError(t, err)
+		iter, err := si.Search(h.BufferPool, tbl, access.RecordSearchModeKey{Key: [][]byte{[]byte("alice")}})
+		require.NoError(t, err)
+		count := 0
+		for {
+			_, ok, err := iter.Next()
+			require.NoError(t, err)
+			if !ok {
+				break
+			}
+			count++
+		}
+		assert.Equal(t, 2, count)
+	})
+
+	t.Run("複数カラムのインデックスを作成でき、先頭カラムから検索できる", func(t *testing.T) {
+		// GIVEN
+		h := setupAlterTable(t)
+		insertOrders(t, h,
+			[][]byte{[]byte("1"), []byte("10")},
+			[][]byte{[]byte("2"), []byte("20")},
+			[][]byte{[]byte("3"), []byte("10")},
+		)
+
+		// WHEN
+		err := h.AddIndex(0, "orders", CreateIndexParam{Name: "idx_user_id_id", ColNames: []string{"user_id", "id"}})
+
+		// THEN
+		assert.NoError(t, err)
+		meta, _ := h.Catalog.GetTableMetaByName("orders")
+		idx, ok := meta.GetIndexByName("idx_user_id_id")
+		require.True(t, ok)
+		assert.Equal(t, []string{"user_id", "id"}, idx.ColNames)
+
+		tbl, err := h.GetTable("orders")
+		require.NoError(t, err)
+		si, err := tbl.GetSecondaryIndexByName("idx_user_id_id")
+		require.NoError(t, err)
+		assert.Equal(t, []uint16{1, 0}, si.ColIdxs)
+		iter, err := si.Search(h.BufferPool, tbl, access.RecordSearchModeKey{Key: [][]byte{[]byte("10"), []byte("3")}})
+		require.NoError(t, err)
+		result, ok, err := iter.Next()
+		require.NoError(t, err)
+		require.True(t, ok)
+		assert.Equal(t, [][]byte{[]byte("3"), []byte("10")}, result.Record)
+	})
+
+	t.Run("複数カラムの UNIQUE インデックスは、値の組み合わせが重複している場合のみエラーを返す", func(t *testing.T) {
+		// GIVEN
+		h := setupAlterTable(t)
+		insertOrders(t, h,
+			[][]byte{[]byte("1"), []byte("10")},
+			[][]byte{[]byte("2"), []byte("10")},
+		)
+		require.NoError(t, h.AddColumn(0, "orders", CreateColumnParam{Name: "item", Type: ColumnTypeString}))
+
+		// WHEN
+		err := h.AddIndex(0, "orders", CreateIndexParam{Name: "uk_user_id_id", ColNames: []string{"user_id", "id"}, Unique: true})
+
+		// THEN
+		assert.NoError(t, err)
+
+		// WHEN
+		err = h.AddIndex(0, "orders", CreateIndexParam{Name: "uk_user_id_item", ColNames: []string{"user_id", "item"}, Unique: true})
+
+		// THEN: NULL を含む行はインデックスに含まれないため重複しない
+		assert.NoError(t, err)
+	})
+
+	t.Run("テーブルを使用した他のトランザクションが lock wait timeout を超えて実行中の場合はエラーを返し、インデックスは作成されない", func(t *testing.T) {
+		// GIVEN
+		h := setupAlterTable(t)
+		t.Setenv("MINESQL_LOCK_WAIT_TIMEOUT", "50")
+		insertUsers(t, h, "1")
+		trxId := h.BeginTrx()
+		defer func() { assert.NoError(t, h.RollbackTrx(trxId)) }()
+		_, err := h.OpenTable(trxId, "users")
+		require.NoError(t, err)
+
+		// WHEN
+		err = h.AddIndex(0, "users", CreateIndexParam{Name: "idx_name", ColNames: []string{"name"}})
+
+		// THEN
+		assert.EqualError(t, err, "cannot add index to table users: lock wait timeout exceeded while waiting for active transactions")
+		meta, _ := h.Catalog.GetTableMetaByName("users")
+		assert.Empty(t, meta.Indexes)
+	})
+
+	t.Run("他のテーブルだけを使用したトランザクションが実行中でも、終了を待たずに作成できる", func(t *testing.T) {
+		// GIVEN
+		h := setupAlterTable(t)
+		t.Setenv("MINESQL_LOCK_WAIT_TIMEOUT", "50")
+		insertUsers(t, h, "1")
+		trxId := h.BeginTrx()
+		defer func() { assert.NoError(t, h.RollbackTrx(trxId)) }()
+		_, err := h.OpenTable(trxId, "orders")
+		require.NoError(t, err)
+
+		// WHEN
+		err = h.AddIndex(0, "users", CreateIndexParam{Name: "idx_name", ColNames: []string{"name"}})
+
+		// THEN
+		assert.NoError(t, err)
+		meta, _ := h.Catalog.GetTableMetaByName("users")
+		assert.Len(t, meta.Indexes, 1)
+	})
+
+	t.Run("インデックスを作成するトランザクション自身が実行中でも作成できる", func(t *testing.T) {
+		// GIVEN
+		h := setupAlterTable(t)
+		insertUsers(t, h, "1")
+		trxId := h.BeginTrx()
+
+		// WHEN
+		err := h.AddIndex(trxId, "users", CreateIndexParam{Name: "idx_name", ColNames: []string{"name"}})
+
+		// THEN
+		assert.NoError(t, err)
+		assert.NoError(t, h.CommitTrx(trxId))
+	})
+
+	t.Run("作成中に並行して挿入した行もすべてインデックスに登録される", func(t *testing.T) {
+		// GIVEN: 作成の開始時に実行中のトランザクションがある (カタログへの登録に時間がかかるように、行を多めに挿入しておく)
+		h := setupAlterTable(t)
+		ids := make([]string, 5000)
+		for i := range ids {
+			ids[i] = "x" + strconv.Itoa(i)
+		}
+		insertUsers(t, h, ids...)
+		trxId := h.BeginTrx()
+		_, err := h.OpenTable(trxId, "users")
+		require.NoError(t, err)
+
+		// WHEN: 作成中と作成後に、別のトランザクションが行を挿入し続ける
+		addErr := make(chan error, 1)
+		go func() {
+			addErr <- h.AddIndex(0, "users", CreateIndexParam{Name: "idx_name", ColNames: []string{"name"}, Unique: true})
+		}()
+		time.Sleep(50 * time.Millisecond)
+
+		done := make(chan struct{})
+		var wg sync.WaitGroup
+		var insertErr error
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 1; ; i++ {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				// サーバーと同様に、トランザクションを開始してからテーブルを取得し、実行計画の作成から実行までの間を空けて挿入する
+				writer := h.BeginTrx()
+				tbl, err := h.OpenTable(writer, "users")
+				time.Sleep(10 * time.Millisecond)
+				if err == nil {
+					id := strconv.Itoa(i)
+					err = tbl.Insert(h.BufferPool, writer, h.LockMgr, [][]byte{[]byte(id), []byte("user-" + id)})
+				}
+				if err == nil {
+					err = h.CommitTrx(writer)
+				}
+				if err != nil {
+					insertErr = err
+					return
+				}
+				time.Sleep(time.Millisecond)
+			}
+		}()
+
+		time.Sleep(50 * time.Millisecond)
+		require.NoError(t, h.CommitTrx(trxId))
+		err = <-addErr
+		time.Sleep(30 * time.Millisecond)
+		close(done)
+		wg.Wait()
+
+		// THEN: インデックスのエントリ数がテーブルの行数と一致する
+		require.NoError(t, err)
+		require.NoError(t, insertErr)
+		tbl, err := h.GetTable("users")
+		require.NoError(t, err)
+		si, err := tbl.GetSecondaryIndexByName("idx_name")
+		require.NoError(t, err)
+		iter, err := si.Search(h.BufferPool, tbl, access.RecordSearchModeStart{})
+		require.NoError(t, err)
+		count := 0
+		for {
+			_, ok, err := iter.Next()
+			require.NoError(t, err)
+			if !ok {
+				break
+			}
+			count++
+		}
+		assert.Equal(t, len(scanUsers(t, h)), count)
+	})
+
+	t.Run("同じ名前のインデックスがある場合はエラーを返す", func(t *testing.T) {
+		// GIVEN
+		h := setupAlterTable(t)
+
+		// WHEN
+		err := h.AddIndex(0, "orders", CreateIndexParam{Name: "idx_user_id", ColNames: []string{"id"}})
+
+		// THEN
+		assert.EqualError(t, err, "duplicate index name: idx_user_id")
+	})
+}
+
+func TestDropIndex(t *testing.T) {
+	t.Run("インデックスと UNIQUE 制約を削除できる", func(t *testing.T) {
+		// GIVEN
+		h := setupAlterTable(t)
+		require.NoError(t, h.AddIndex(0, "users", CreateIndexParam{Name: "idx_name", ColNames: []string{"name"}, Unique: true}))
+
+		// WHEN
+		err := h.DropIndex(0, "users", "idx_name")
+
+		// THEN
+		assert.NoError(t, err)
+		meta, _ := h.Catalog.GetTableMetaByName("users")
+		assert.Empty(t, meta.Indexes)
+		assert.Len(t, meta.Constraints, 1)
+		tbl, err := h.GetTable("users")
+		require.NoError(t, err)
+		assert.Empty(t, tbl.SecondaryIndexes)
+	})
+
+	t.Run("外部キーのカラムのインデックスは削除できない", func(t *testing.T) {
+		// GIVEN
+		h := setupAlterTable(t)
+
+		// WHEN
+		err := h.DropIndex(0, "orders", "idx_user_id")
+
+		// THEN
+		assert.EqualError(t, err, "cannot drop index idx_user_id: needed by foreign key fk_user")
+	})
+
+	t.Run("外部キーから参照されているカラムの UNIQUE インデックスは削除できない", func(t *testing.T) {
+		// GIVEN
+		h := setupAlterTable(t)
+		require.NoError(t, h.AddIndex(0, "users", CreateIndexParam{Name: "idx_name", ColNames: []string{"name"}, Unique: true}))
+		require.NoError(t, h.DropForeignKey(0, "orders", "fk_user"))
+		require.NoError(t, h.AddForeignKey(0, "orders", CreateConstraintParam{ConstraintName: "fk_user_name", ColNames: []string{"user_id"}, RefTableName: "users", RefColNames: []string{"name"}}))
+
+		// WHEN
+		err := h.DropIndex(0, "users", "idx_name")
+
+		// THEN
+		assert.EqualError(t, err, "cannot drop index idx_name: referenced by foreign key fk_user_name on table orders")
+	})
+
+	t.Run("同じカラムを先頭に持つ他のインデックスがあれば、外部キーのカラムのインデックスも削除できる", func(t *testing.T) {
+		// GIVEN
+		h := setupAlterTable(t)
+		require.NoError(t, h.AddIndex(0, "orders", CreateIndexParam{Name: "idx_user_id_id", ColNames: []string{"user_id", "id"}}))
+
+		// WHEN
+		err := h.DropIndex(0, "orders", "idx_user_id")
+
+		// THEN
+		assert.NoError(t, err)
+		meta, _ := h.Catalog.GetTableMetaByName("orders")
+		require.Len(t, meta.Indexes, 1)
+		assert.Equal(t, "idx_user_id_id", meta.Indexes[0].Name)
+	})
+
+	t.Run("複合外部キーのカラムを先頭に持つインデックスと、参照先の複合 UNIQUE インデックスは削除できない", func(t *testing.T) {
+		// GIVEN: orders (user_id, id) が users (id, name) を参照する
+		h := setupAlterTable(t)
+		require.NoError(t, h.AddIndex(0, "users", CreateIndexParam{Name: "uk_id_name", ColNames: []string{"id", "name"}, Unique: true}))
+		require.NoError(t, h.AddIndex(0, "orders", CreateIndexParam{Name: "idx_user_id_id", ColNames: []string{"user_id", "id"}}))
+		require.NoError(t, h.AddForeignKey(0, "orders", CreateConstraintParam{ConstraintName: "fk_user_pair", ColNames: []string{"user_id", "id"}, RefTableName: "users", RefColNames: []string{"id", "name"}}))
+
+		// WHEN
+		errChild := h.DropIndex(0, "orders", "idx_user_id_id")
+		errParent := h.DropIndex(0, "users", "uk_id_name")
+
+		// THEN: 先頭のカラムだけのインデックス (idx_user_id) は複合外部キーの代わりにならない
+		assert.EqualError(t, errChild, "cannot drop index idx_user_id_id: needed by foreign key fk_user_pair")
+		assert.EqualError(t, errParent, "cannot drop index uk_id_name: referenced by foreign key fk_user_pair on table orders")
+	})
+
+	t.Run("存在しないインデックスを指定するとエラーを返す", func(t *testing.T) {
+		// GIVEN
+		h := setupAlterTable(t)
+
+		// WHEN
+		err := h.DropIndex(0, "users", "idx_name")
+
+		// THEN
+		assert.EqualError(t, err, "index idx_name not found in table users")
+	})
+}
@@ -10,18 +10,31 @@ import (
 
 // GetTable はテーブルメタデータからテーブルを取得し、UndoManager をセットして返す
 func (h *Handler) GetTable(tableName string) (*access.Table, error) {
+	// インデックスをカタログに登録している間は、登録後のテーブル定義で構築するために登録が終わるまで待つ
+	unlock := h.rowLogs.RLockTable(tableName)
+	defer unlock()
+
 	tblMeta, ok := h.Catalog.GetTableMetaByName(tableName)
 	if !ok {
 		return nil, fmt.Errorf("table %s not found", tableName)
 	}
-	return buildTable(tblMeta, h.undoLog, h.redoLog)
+	return buildTable(tblMeta, h.undoLog, h.redoLog, h.rowLogs)
+}
+
+// OpenTable はトランザクションが使用するテーブルを取得する
+//
+// テーブルを使用したことをトランザクションの終了まで記録し、そのテーブルに対する DDL が実行中のトランザクションを待てるようにする
+// (Table を構築する前に記録するため、DDL が記録を確認した時点で構築済みの Table を持つトランザクションは必ず対象になる)
+func (h *Handler) OpenTable(trxId TrxId, tableName string) (*access.Table, error) {
+	h.trxManager.UseTable(trxId, tableName)
+	return h.GetTable(tableName)
 }
 
 // buildAllTables はカタログに登録されている全テーブルを構築する
-func buildAllTables(catalog *dictionary.Catalog, undoLog *access.UndoManager, redoLog *log.RedoLog) []*access.Table {
+func buildAllTables(catalog *dictionary.Catalog, undoLog *access.UndoManager, redoLog *log.RedoLog, rowLogs *access.RowLogRegistry) []*access.Table {
 	var tables []*access.Table
 	for _, tblMeta := range catalog.GetAllTables() {
-		tbl, err := buildTable(tblMeta, undoLog, redoLog)
+		tbl, err := buildTable(tblMeta, undoLog, redoLog, rowLogs)
 		if err != nil {
 			continue
 		}
@@ -31,20 +44,23 @@ func buildAllTables(catalog *dictionary.Catalog, undoLog *access.UndoManager, re
 }
 
 // buildTable はテーブルメタデータから Table を構築する
-func buildTable(tblMeta *dictionary.TableMeta, undoLog *access.UndoManager, redoLog *log.RedoLog) (*access.Table, error) {
+//
+// rowLogs を設定し、オンライン作成中のインデックスがある場合は行の変更を行ログに記録するようにする
+func buildTable(tblMeta *dictionary.TableMeta, undoLog *access.UndoManager, redoLog *log.RedoLog, rowLogs *access.RowLogRegistry) (*access.Table, error) {
 	var secondaryIndexes []*access.SecondaryIndex
 	for _, idxMeta := range tblMeta.Indexes {
 		isUnique := idxMeta.Type == dictionary.IndexTypeUnique
 		if idxMeta.Type == dictionary.IndexTypeUnique || idxMeta.Type == dictionary.IndexTypeNonUnique {
-			colMeta, ok := tblMeta.GetColByName(idxMeta.ColName)
-			if !ok {
-				return nil, fmt.Errorf("column %s not found in table %s", idxMeta.ColName, tblMeta.Name)
+			colIdxs, err := tblMeta.GetColPositions(idxMeta.ColNames)
+			if err != nil {
+				return nil, err
 			}
-			si := access.NewSecondaryIndex(idxMeta.Name, idxMeta.ColName, idxMeta.DataMetaPageId, colMeta.Pos, tblMeta.PKCount, isUnique)
+			si := access.NewSecondaryIndex(idxMeta.Name, idxMeta.ColNames, idxMeta.DataMetaPageId, colIdxs, tblMeta.PKCount, isUnique)
 			secondaryIndexes = append(secondaryIndexes, si)
 		}
 	}
 
 	tbl := access.NewTable(tblMeta.Name, tblMeta.DataMetaPageId, tblMeta.PKCount, secondaryIndexes, undoLog, redoLog)
+	tbl.SetRowLogs(rowLogs)
 	return &tbl, nil
 }
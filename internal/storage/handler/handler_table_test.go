@@ -41,7 +41,7 @@ func TestGetTable(t *testing.T) {
 
 		err := h.CreateTable("users", 1,
 			[]CreateIndexParam{
-				{Name: "idx_email", ColName: "email", ColIdx: 1, Unique: true},
+				{Name: "idx_email", ColNames: []string{"email"}, ColIdxs: []uint16{1}, Unique: true},
 			},
 			[]CreateColumnParam{
 				{Name: "id", Type: ColumnTypeString},
@@ -58,7 +58,7 @@ func TestGetTable(t *testing.T) {
 		assert.NoError(t, err)
 		assert.Equal(t, 1, len(tbl.SecondaryIndexes))
 		assert.Equal(t, "idx_email", tbl.SecondaryIndexes[0].Name)
-		assert.Equal(t, "email", tbl.SecondaryIndexes[0].ColName)
+		assert.Equal(t, []string{"email"}, tbl.SecondaryIndexes[0].ColNames)
 	})
 
 	t.Run("複数のユニークインデックスが構築される", func(t *testing.T) {
@@ -71,8 +71,8 @@ func TestGetTable(t *testing.T) {
 
 		err := h.CreateTable("users", 1,
 			[]CreateIndexParam{
-				{Name: "idx_email", ColName: "email", ColIdx: 1, Unique: true},
-				{Name: "idx_username", ColName: "username", ColIdx: 2, Unique: true},
+				{Name: "idx_email", ColNames: []string{"email"}, ColIdxs: []uint16{1}, Unique: true},
+				{Name: "idx_username", ColNames: []string{"username"}, ColIdxs: []uint16{2}, Unique: true},
 			},
 			[]CreateColumnParam{
 				{Name: "id", Type: ColumnTypeString},
@@ -103,7 +103,7 @@ func TestGetTable(t *testing.T) {
 
 		err := h.CreateTable("products", 1,
 			[]CreateIndexParam{
-				{Name: "idx_category", ColName: "category", ColIdx: 1, Unique: false},
+				{Name: "idx_category", ColNames: []string{"category"}, ColIdxs: []uint16{1}, Unique: false},
 			},
 			[]CreateColumnParam{
 				{Name: "id", Type: ColumnTypeString},
@@ -133,8 +133,8 @@ func TestGetTable(t *testing.T) {
 
 		err := h.CreateTable("products", 1,
 			[]CreateIndexParam{
-				{Name: "idx_category", ColName: "category", ColIdx: 1, Unique: false},
-				{Name: "idx_brand", ColName: "brand", ColIdx: 2, Unique: false},
+				{Name: "idx_category", ColNames: []string{"category"}, ColIdxs: []uint16{1}, Unique: false},
+				{Name: "idx_brand", ColNames: []string{"brand"}, ColIdxs: []uint16{2}, Unique: false},
 			},
 			[]CreateColumnParam{
 				{Name: "id", Type: ColumnTypeString},
@@ -167,8 +167,8 @@ func TestGetTable(t *testing.T) {
 
 		err := h.CreateTable("products", 1,
 			[]CreateIndexParam{
-				{Name: "idx_name", ColName: "name", ColIdx: 1, Unique: true},
-				{Name: "idx_category", ColName: "category", ColIdx: 2, Unique: false},
+				{Name: "idx_name", ColNames: []string{"name"}, ColIdxs: []uint16{1}, Unique: true},
+				{Name: "idx_category", ColNames: []string{"category"}, ColIdxs: []uint16{2}, Unique: false},
 			},
 			[]CreateColumnParam{
 				{Name: "id", Type: ColumnTypeString},
@@ -227,7 +227,7 @@ func TestBuildTable(t *testing.T) {
 		assert.True(t, ok)
 
 		// WHEN
-		tbl, err := buildTable(tblMeta, h.undoLog, h.redoLog)
+		tbl, err := buildTable(tblMeta, h.undoLog, h.redoLog, h.rowLogs)
 
 		// THEN
 		assert.NoError(t, err)
@@ -244,7 +244,7 @@ func TestBuildTable(t *testing.T) {
 		h := Init()
 
 		err := h.CreateTable("users", 1,
-			[]CreateIndexParam{{Name: "idx_email", ColName: "email", ColIdx: 1, Unique: true}},
+			[]CreateIndexParam{{Name: "idx_email", ColNames: []string{"email"}, ColIdxs: []uint16{1}, Unique: true}},
 			[]CreateColumnParam{
 				{Name: "id", Type: ColumnTypeString},
 				{Name: "email", Type: ColumnTypeString},
@@ -256,7 +256,7 @@ func TestBuildTable(t *testing.T) {
 		assert.True(t, ok)
 
 		// WHEN
-		tbl, err := buildTable(tblMeta, h.undoLog, h.redoLog)
+		tbl, err := buildTable(tblMeta, h.undoLog, h.redoLog, h.rowLogs)
 
 		// THEN
 		assert.NoError(t, err)
@@ -284,7 +284,7 @@ func TestBuildAllTables(t *testing.T) {
 		assert.NoError(t, err)
 
 		// WHEN
-		tables := buildAllTables(h.Catalog, h.undoLog, h.redoLog)
+		tables := buildAllTables(h.Catalog, h.undoLog, h.redoLog, h.rowLogs)
 
 		// THEN
 		assert.Equal(t, 2, len(tables))
@@ -302,7 +302,7 @@ func TestBuildAllTables(t *testing.T) {
 		h := Init()
 
 		// WHEN
-		tables := buildAllTables(h.Catalog, h.undoLog, h.redoLog)
+		tables := buildAllTables(h.Catalog, h.undoLog, h.redoLog, h.rowLogs)
 
 		// THEN
 		assert.Equal(t, 0, len(tables))
@@ -9,6 +9,7 @@ import (
 	"github.com/ren-yamanashi/minesql/internal/storage/access"
 	"github.com/ren-yamanashi/minesql/internal/storage/dictionary"
 	"github.com/ren-yamanashi/minesql/internal/storage/lock"
+	"github.com/ren-yamanashi/minesql/internal/storage/log"
 	"github.com/ren-yamanashi/minesql/internal/storage/page"
 	"github.com/stretchr/testify/assert"
 )
@@ -42,6 +43,26 @@ func TestInit(t *testing.T) {
 		// THEN
 		assert.Same(t, handler1, handler2)
 	})
+
+	t.Run("データディレクトリに残っている一時ファイルを削除する", func(t *testing.T) {
+		// GIVEN
+		tmpdir := t.TempDir()
+		t.Setenv("MINESQL_DATA_DIR", tmpdir)
+		t.Setenv("MINESQL_BUFFER_SIZE", "10")
+		Reset()
+		for _, name := range []string{"minesql-sort-1.tmp", "minesql-join-2.tmp", "other.tmp"} {
+			assert.NoError(t, os.WriteFile(filepath.Join(tmpdir, name), []byte("x"), 0600))
+		}
+
+		// WHEN
+		Init()
+		defer Reset()
+
+		// THEN
+		paths, err := filepath.Glob(filepath.Join(tmpdir, "*.tmp"))
+		assert.NoError(t, err)
+		assert.Equal(t, []string{filepath.Join(tmpdir, "other.tmp")}, paths)
+	})
 }
 
 func TestGet(t *testing.T) {
@@ -462,6 +483,97 @@ func TestCrashRecovery(t *testing.T) {
 		err = h2.Shutdown()
 		assert.NoError(t, err)
 	})
+
+	t.Run("DROP TABLE の DDL ログを記録した直後にクラッシュしても、再起動時に削除が完了する", func(t *testing.T) {
+		// GIVEN
+		tmpdir := t.TempDir()
+		t.Setenv("MINESQL_DATA_DIR", tmpdir)
+		t.Setenv("MINESQL_BUFFER_SIZE", "100")
+		Reset()
+		h := Init()
+		setupTable(t, h)
+		insertUsers(t, h, "1")
+		assert.NoError(t, h.BufferPool.FlushAllPages())
+		meta, ok := h.Catalog.GetTableMetaByName("users")
+		assert.True(t, ok)
+		assert.NoError(t, h.ddlLog.Write(log.DDLRecord{Op: log.DDLDropTable, FileId: meta.FileId, TableName: "users"}))
+
+		// WHEN: Shutdown を呼ばずに再初期化
+		Reset()
+		h2 := Init()
+
+		// THEN: テーブルとヒープファイルが削除され、DDL ログがクリアされている
+		_, ok = h2.Catalog.GetTableMetaByName("users")
+		assert.False(t, ok)
+		_, err := os.Stat(filepath.Join(tmpdir, "users.db"))
+		assert.True(t, os.IsNotExist(err))
+		_, ok, err = h2.ddlLog.Read()
+		assert.NoError(t, err)
+		assert.False(t, ok)
+
+		err = h2.Shutdown()
+		assert.NoError(t, err)
+	})
+
+	t.Run("DROP TABLE でカタログからの削除を永続化した後にクラッシュしても、再起動時にヒープファイルが削除される", func(t *testing.T) {
+		// GIVEN
+		tmpdir := t.TempDir()
+		t.Setenv("MINESQL_DATA_DIR", tmpdir)
+		t.Setenv("MINESQL_BUFFER_SIZE", "100")
+		Reset()
+		h := Init()
+		setupTable(t, h)
+		insertUsers(t, h, "1")
+		meta, ok := h.Catalog.GetTableMetaByName("users")
+		assert.True(t, ok)
+		assert.NoError(t, h.ddlLog.Write(log.DDLRecord{Op: log.DDLDropTable, FileId: meta.FileId, TableName: "users"}))
+		assert.NoError(t, h.Catalog.Delete(h.BufferPool, "users"))
+		assert.NoError(t, h.BufferPool.FlushAllPages())
+
+		// WHEN: ヒープファイルを削除する前に Shutdown を呼ばずに再初期化
+		Reset()
+		h2 := Init()
+
+		// THEN
+		_, ok = h2.Catalog.GetTableMetaByName("users")
+		assert.False(t, ok)
+		_, err := os.Stat(filepath.Join(tmpdir, "users.db"))
+		assert.True(t, os.IsNotExist(err))
+
+		err = h2.Shutdown()
+		assert.NoError(t, err)
+	})
+
+	t.Run("TRUNCATE TABLE の DDL ログを記録した直後にクラッシュしても、再起動時に切り詰めが完了する", func(t *testing.T) {
+		// GIVEN: コミット済みの変更は REDO ログにのみ記録されている
+		tmpdir := t.TempDir()
+		t.Setenv("MINESQL_DATA_DIR", tmpdir)
+		t.Setenv("MINESQL_BUFFER_SIZE", "100")
+		Reset()
+		h := Init()
+		setupTable(t, h)
+		err := h.BufferPool.FlushAllPages()
+		assert.NoError(t, err)
+		err = h.redoLog.Reset()
+		assert.NoError(t, err)
+		insertUsers(t, h, "1", "2")
+		meta, ok := h.Catalog.GetTableMetaByName("users")
+		assert.True(t, ok)
+		assert.NoError(t, h.ddlLog.Write(log.DDLRecord{Op: log.DDLTruncateTable, FileId: meta.FileId, TableName: "users"}))
+
+		// WHEN: Shutdown を呼ばずに再初期化
+		Reset()
+		h2 := Init()
+
+		// THEN: REDO ログで復元されたレコードも含めて削除されている
+		assert.Empty(t, scanUsers(t, h2))
+		_, ok, err = h2.ddlLog.Read()
+		assert.NoError(t, err)
+		assert.False(t, ok)
+
+		err = h2.Shutdown()
+		assert.NoError(t, err)
+	})
 }
 
 func TestRegisterDmToBp(t *testing.T) {
@@ -0,0 +1,44 @@
+package handler
+
+import (
+	"fmt"
+
+	"github.com/ren-yamanashi/minesql/internal/storage/dictionary"
+)
+
+// CreateView はビューをカタログに登録する
+//
+// テーブルと同じ名前のビューは作成できない
+//   - columns: カラム名のリスト (nil なら問い合わせの結果セットのカラム名を使う)
+//   - definition: 問い合わせの SQL 表現
+//   - orReplace: true の場合、同じ名前のビューがあれば定義を置き換える
+func (h *Handler) CreateView(viewName string, columns []string, definition string, orReplace bool) error {
+	if _, ok := h.Catalog.GetTableMetaByName(viewName); ok {
+		return fmt.Errorf("table %s already exists", viewName)
+	}
+	if err := h.checkDatabaseExists(viewName); err != nil {
+		return err
+	}
+	viewMeta := dictionary.NewViewMeta(viewName, columns, definition)
+	if _, ok := h.Catalog.GetViewByName(viewName); ok {
+		if !orReplace {
+			return fmt.Errorf("view %s already exists", viewName)
+		}
+		return h.Catalog.UpdateView(h.BufferPool, *viewMeta)
+	}
+	return h.Catalog.InsertView(h.BufferPool, *viewMeta)
+}
+
+// DropView はビューをカタログから削除する
+//
+// ビューを参照する他のビューは削除しない (参照した時点でエラーになる)
+//   - ifExists: true の場合、ビューが存在しなくてもエラーにしない
+func (h *Handler) DropView(viewName string, ifExists bool) error {
+	if _, ok := h.Catalog.GetViewByName(viewName); !ok {
+		if ifExists {
+			return nil
+		}
+		return fmt.Errorf("view %s not found", viewName)
+	}
+	return h.Catalog.DeleteView(h.BufferPool, viewName)
+}
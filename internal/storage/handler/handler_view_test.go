@@ -0,0 +1,112 @@
+package handler
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateView(t *testing.T) {
+	setup := func(t *testing.T) *Handler {
+		tmpdir := t.TempDir()
+		t.Setenv("MINESQL_DATA_DIR", tmpdir)
+		t.Setenv("MINESQL_BUFFER_SIZE", "100")
+		Reset()
+		h := Init()
+		require.NoError(t, h.CreateTable("users", 1, nil, []CreateColumnParam{
+			{Name: "id", Type: ColumnTypeString},
+			{Name: "name", Type: ColumnTypeString},
+		}, nil))
+		return h
+	}
+
+	t.Run("ビューを作成できる", func(t *testing.T) {
+		// GIVEN
+		h := setup(t)
+		defer Reset()
+
+		// WHEN
+		err := h.CreateView("user_names", []string{"n"}, "SELECT name FROM users", false)
+
+		// THEN
+		assert.NoError(t, err)
+		view, ok := h.Catalog.GetViewByName("user_names")
+		assert.True(t, ok)
+		assert.Equal(t, []string{"n"}, view.Columns)
+		assert.Equal(t, "SELECT name FROM users", view.Definition)
+	})
+
+	t.Run("OR REPLACE を指定した場合、同じ名前のビューの定義を置き換える", func(t *testing.T) {
+		// GIVEN
+		h := setup(t)
+		defer Reset()
+		require.NoError(t, h.CreateView("v", nil, "SELECT id FROM users", false))
+
+		// WHEN
+		err := h.CreateView("v", nil, "SELECT name FROM users", true)
+
+		// THEN
+		assert.NoError(t, err)
+		view, ok := h.Catalog.GetViewByName("v")
+		assert.True(t, ok)
+		assert.Equal(t, "SELECT name FROM users", view.Definition)
+		assert.Equal(t, 1, len(h.Catalog.GetAllViews()))
+	})
+
+	t.Run("同じ名前のビュー・テーブルがある場合はエラーになる", func(t *testing.T) {
+		// GIVEN
+		h := setup(t)
+		defer Reset()
+		require.NoError(t, h.CreateView("v", nil, "SELECT id FROM users", false))
+
+		// WHEN
+		viewErr := h.CreateView("v", nil, "SELECT name FROM users", false)
+		tableErr := h.CreateView("users", nil, "SELECT name FROM users", true)
+		createTableErr := h.CreateTable("v", 1, nil, []CreateColumnParam{{Name: "id", Type: ColumnTypeString}}, nil)
+
+		// THEN
+		assert.EqualError(t, viewErr, "view v already exists")
+		assert.EqualError(t, tableErr, "table users already exists")
+		assert.EqualError(t, createTableErr, "view v already exists")
+	})
+}
+
+func TestDropView(t *testing.T) {
+	t.Run("ビューを削除できる", func(t *testing.T) {
+		// GIVEN
+		tmpdir := t.TempDir()
+		t.Setenv("MINESQL_DATA_DIR", tmpdir)
+		t.Setenv("MINESQL_BUFFER_SIZE", "100")
+		Reset()
+		h := Init()
+		defer Reset()
+		require.NoError(t, h.CreateView("v", nil, "SELECT 1 FROM users", false))
+
+		// WHEN
+		err := h.DropView("v", false)
+
+		// THEN
+		assert.NoError(t, err)
+		_, ok := h.Catalog.GetViewByName("v")
+		assert.False(t, ok)
+	})
+
+	t.Run("存在しないビューを削除するとエラーになる (IF EXISTS の場合はエラーにしない)", func(t *testing.T) {
+		// GIVEN
+		tmpdir := t.TempDir()
+		t.Setenv("MINESQL_DATA_DIR", tmpdir)
+		t.Setenv("MINESQL_BUFFER_SIZE", "100")
+		Reset()
+		h := Init()
+		defer Reset()
+
+		// WHEN
+		err := h.DropView("v", false)
+		ifExistsErr := h.DropView("v", true)
+
+		// THEN
+		assert.EqualError(t, err, "view v not found")
+		assert.NoError(t, ifExistsErr)
+	})
+}
@@ -0,0 +1,150 @@
+package log
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/ren-yamanashi/minesql/internal/storage/page"
+)
+
+const ddlLogFileName = "ddl.log"
+
+type DDLOperation uint8
+
+const (
+	DDLDropTable     DDLOperation = 1 // DROP TABLE
+	DDLTruncateTable DDLOperation = 2 // TRUNCATE TABLE
+	DDLDropDatabase  DDLOperation = 3 // DROP DATABASE
+)
+
+// DDLRecord は実行中の DDL (ファイルの削除や切り詰めを伴う操作) を表す
+type DDLRecord struct {
+	Op        DDLOperation // DDL の種類
+	FileId    page.FileId  // 対象テーブルの FileId (DROP DATABASE の場合は 0)
+	TableName string       // 対象テーブル名 (DROP DATABASE の場合はデータベース名)
+}
+
+// シリアライズ形式:
+//   - Op:        1 バイト (uint8)
+//   - FileId:    4 バイト (uint32)
+//   - NameLen:   2 バイト (uint16)
+//   - TableName: 可変長
+//   - Checksum:  4 バイト (先頭からテーブル名までの CRC32)
+
+const ddlRecordHeaderSize = 1 + 4 + 2 // 7 バイト
+
+var ErrInvalidDDLRecord = errors.New("invalid ddl record")
+
+// DDLLog は DROP TABLE / TRUNCATE TABLE / DROP DATABASE の実行前に対象を記録する DDL ログを管理する
+//
+// ログには実行中の DDL を 1 件だけ記録する。DDL の完了後にクリアするため、
+// 起動時にレコードが残っていれば DDL の途中でクラッシュしたことを示す
+type DDLLog struct {
+	mutex sync.Mutex
+	file  *os.File // ddl.log ファイル
+}
+
+// NewDDLLog は ddl.log を開く (存在しない場合は新規作成する)
+func NewDDLLog(dataDir string) (*DDLLog, error) {
+	filePath := filepath.Join(dataDir, ddlLogFileName)
+	file, err := os.OpenFile(filepath.Clean(filePath), os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open ddl log: %w", err)
+	}
+	return &DDLLog{file: file}, nil
+}
+
+// Write は DDL レコードをファイルに書き込み、fsync する (既存のレコードは上書きされる)
+func (dl *DDLLog) Write(record DDLRecord) error {
+	dl.mutex.Lock()
+	defer dl.mutex.Unlock()
+
+	if err := dl.file.Truncate(0); err != nil {
+		return err
+	}
+	if _, err := dl.file.WriteAt(record.Serialize(), 0); err != nil {
+		return err
+	}
+	return dl.file.Sync()
+}
+
+// Read は記録されている DDL レコードを返す
+//
+// レコードがない場合、またはレコードの書き込み途中でクラッシュした (チェックサムが一致しない) 場合は false を返す
+func (dl *DDLLog) Read() (DDLRecord, bool, error) {
+	dl.mutex.Lock()
+	defer dl.mutex.Unlock()
+
+	stat, err := dl.file.Stat()
+	if err != nil {
+		return DDLRecord{}, false, err
+	}
+	if stat.Size() == 0 {
+		return DDLRecord{}, false, nil
+	}
+
+	data := make([]byte, stat.Size())
+	if _, err := dl.file.ReadAt(data, 0); err != nil {
+		return DDLRecord{}, false, err
+	}
+	record, err := DeserializeDDLRecord(data)
+	if err != nil {
+		return DDLRecord{}, false, nil
+	}
+	return record, true, nil
+}
+
+// Clear は DDL レコードを削除する (DDL の完了後に呼ぶ)
+func (dl *DDLLog) Clear() error {
+	dl.mutex.Lock()
+	defer dl.mutex.Unlock()
+
+	if err := dl.file.Truncate(0); err != nil {
+		return err
+	}
+	return dl.file.Sync()
+}
+
+// Serialize は DDLRecord をバイト列にシリアライズする
+func (r *DDLRecord) Serialize() []byte {
+	nameLen := len(r.TableName)
+	buf := make([]byte, ddlRecordHeaderSize+nameLen+4)
+
+	buf[0] = byte(r.Op)
+	binary.BigEndian.PutUint32(buf[1:5], uint32(r.FileId))
+	binary.BigEndian.PutUint16(buf[5:7], uint16(nameLen))
+	copy(buf[7:], r.TableName)
+
+	checksum := crc32.ChecksumIEEE(buf[:ddlRecordHeaderSize+nameLen])
+	binary.BigEndian.PutUint32(buf[ddlRecordHeaderSize+nameLen:], checksum)
+
+	return buf
+}
+
+// DeserializeDDLRecord はバイト列から DDLRecord をデシリアライズする
+func DeserializeDDLRecord(data []byte) (DDLRecord, error) {
+	if len(data) < ddlRecordHeaderSize {
+		return DDLRecord{}, ErrInvalidDDLRecord
+	}
+
+	nameLen := int(binary.BigEndian.Uint16(data[5:7]))
+	if len(data) < ddlRecordHeaderSize+nameLen+4 {
+		return DDLRecord{}, ErrInvalidDDLRecord
+	}
+
+	checksum := binary.BigEndian.Uint32(data[ddlRecordHeaderSize+nameLen:])
+	if crc32.ChecksumIEEE(data[:ddlRecordHeaderSize+nameLen]) != checksum {
+		return DDLRecord{}, ErrInvalidDDLRecord
+	}
+
+	return DDLRecord{
+		Op:        DDLOperation(data[0]),
+		FileId:    page.FileId(binary.BigEndian.Uint32(data[1:5])),
+		TableName: string(data[7 : ddlRecordHeaderSize+nameLen]),
+	}, nil
+}
@@ -0,0 +1,110 @@
+package log
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ren-yamanashi/minesql/internal/storage/page"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDDLLog(t *testing.T) {
+	t.Run("新規ファイルにはレコードがない", func(t *testing.T) {
+		// GIVEN
+		dl, err := NewDDLLog(t.TempDir())
+		require.NoError(t, err)
+
+		// WHEN
+		_, ok, err := dl.Read()
+
+		// THEN
+		assert.NoError(t, err)
+		assert.False(t, ok)
+	})
+
+	t.Run("書き込んだレコードを再オープン後に読み取れる", func(t *testing.T) {
+		// GIVEN
+		tmpDir := t.TempDir()
+		dl1, err := NewDDLLog(tmpDir)
+		require.NoError(t, err)
+		record := DDLRecord{Op: DDLDropTable, FileId: page.FileId(3), TableName: "users"}
+		require.NoError(t, dl1.Write(record))
+
+		// WHEN
+		dl2, err := NewDDLLog(tmpDir)
+		require.NoError(t, err)
+		got, ok, err := dl2.Read()
+
+		// THEN
+		assert.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, record, got)
+	})
+
+	t.Run("書き込みは既存のレコードを上書きする", func(t *testing.T) {
+		// GIVEN
+		dl, err := NewDDLLog(t.TempDir())
+		require.NoError(t, err)
+		require.NoError(t, dl.Write(DDLRecord{Op: DDLDropTable, FileId: page.FileId(3), TableName: "departments"}))
+
+		// WHEN
+		record := DDLRecord{Op: DDLTruncateTable, FileId: page.FileId(4), TableName: "users"}
+		require.NoError(t, dl.Write(record))
+
+		// THEN
+		got, ok, err := dl.Read()
+		assert.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, record, got)
+	})
+
+	t.Run("クリア後はレコードがない", func(t *testing.T) {
+		// GIVEN
+		dl, err := NewDDLLog(t.TempDir())
+		require.NoError(t, err)
+		require.NoError(t, dl.Write(DDLRecord{Op: DDLDropTable, FileId: page.FileId(3), TableName: "users"}))
+
+		// WHEN
+		err = dl.Clear()
+
+		// THEN
+		assert.NoError(t, err)
+		_, ok, err := dl.Read()
+		assert.NoError(t, err)
+		assert.False(t, ok)
+	})
+
+	t.Run("書き込み途中のレコードは無視される", func(t *testing.T) {
+		// GIVEN: レコードの末尾 (チェックサム) が欠けたファイル
+		tmpDir := t.TempDir()
+		record := DDLRecord{Op: DDLDropTable, FileId: page.FileId(3), TableName: "users"}
+		data := record.Serialize()
+		require.NoError(t, os.WriteFile(filepath.Join(tmpDir, ddlLogFileName), data[:len(data)-2], 0600))
+		dl, err := NewDDLLog(tmpDir)
+		require.NoError(t, err)
+
+		// WHEN
+		_, ok, err := dl.Read()
+
+		// THEN
+		assert.NoError(t, err)
+		assert.False(t, ok)
+	})
+}
+
+func TestDeserializeDDLRecord(t *testing.T) {
+	t.Run("チェックサムが一致しない場合はエラーになる", func(t *testing.T) {
+		// GIVEN
+		record := DDLRecord{Op: DDLTruncateTable, FileId: page.FileId(5), TableName: "users"}
+		data := record.Serialize()
+		data[1] ^= 0xff
+
+		// WHEN
+		_, err := DeserializeDDLRecord(data)
+
+		// THEN
+		assert.ErrorIs(t, err, ErrInvalidDDLRecord)
+	})
+}
@@ -0,0 +1,200 @@
+package recovery
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/ren-yamanashi/minesql/internal/storage/btree"
+	"github.com/ren-yamanashi/minesql/internal/storage/buffer"
+	"github.com/ren-yamanashi/minesql/internal/storage/dictionary"
+	"github.com/ren-yamanashi/minesql/internal/storage/log"
+	"github.com/ren-yamanashi/minesql/internal/storage/page"
+)
+
+// ResumeDDL は DDL ログに DDL が残っている (= DDL の途中で異常終了した) 場合、その DDL を最後まで実行する
+//
+// REDO 適用・UNDO ロールバックの後に呼び出す
+func (r *Recovery) ResumeDDL(ddlLog *log.DDLLog, dataDir string) error {
+	record, ok, err := ddlLog.Read()
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return nil
+	}
+
+	if err := ApplyDDL(r.bufferPool, r.catalog, dataDir, record); err != nil {
+		return err
+	}
+
+	// 適用前の REDO レコードが再び適用されないように REDO ログをクリアしてから DDL ログをクリアする
+	if err := r.redoLog.Reset(); err != nil {
+		return err
+	}
+	return ddlLog.Clear()
+}
+
+// ApplyDDL は DDL ログのレコードが表す DROP TABLE / TRUNCATE TABLE / DROP DATABASE を実行する
+//
+// どの段階まで実行済みでも同じ結果になる (冪等) ため、通常の DDL 実行とクラッシュ後の再実行の両方で使用する。
+// 実行後、変更したページはすべてディスクにフラッシュされている
+func ApplyDDL(bp *buffer.BufferPool, catalog *dictionary.Catalog, dataDir string, record log.DDLRecord) error {
+	switch record.Op {
+	case log.DDLDropTable:
+		return dropTable(bp, catalog, dataDir, record)
+	case log.DDLTruncateTable:
+		return truncateTable(bp, catalog, record)
+	case log.DDLDropDatabase:
+		return dropDatabase(bp, catalog, dataDir, record)
+	default:
+		return fmt.Errorf("recovery: unknown ddl operation: %d", record.Op)
+	}
+}
+
+// dropTable はカタログからテーブルを削除し、テーブルのページを破棄してヒープファイルを削除する
+//
+//  1. カタログからメタデータを削除 (削除済みならスキップ)
+//  2. バッファプールからテーブルのページを破棄し、Disk の登録を解除して閉じる
+//  3. カタログの変更をフラッシュ
+//  4. ヒープファイルを削除 (削除済みならスキップ)
+func dropTable(bp *buffer.BufferPool, catalog *dictionary.Catalog, dataDir string, record log.DDLRecord) error {
+	if err := discardTable(bp, catalog, record.FileId, record.TableName); err != nil {
+		return err
+	}
+
+	// ヒープファイルを削除する前にカタログの変更を永続化する (先にファイルを削除すると、カタログに残ったテーブルのファイルが失われる)
+	if err := bp.FlushAllPages(); err != nil {
+		return err
+	}
+
+	path := dictionary.TableFilePath(dataDir, record.TableName)
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// dropDatabase はカタログからデータベースと、データベースのテーブル・ビューを削除し、データベースのディレクトリを削除する
+//
+//  1. データベースのテーブルごとに、カタログからメタデータを削除し、テーブルのページを破棄して Disk の登録を解除する
+//  2. カタログからデータベースのビューとデータベースを削除 (削除済みならスキップ)
+//  3. カタログの変更をフラッシュ
+//  4. データベースのディレクトリを削除 (削除済みならスキップ)
+func dropDatabase(bp *buffer.BufferPool, catalog *dictionary.Catalog, dataDir string, record log.DDLRecord) error {
+	databaseName := record.TableName
+
+	var tables []*dictionary.TableMeta
+	for _, tblMeta := range catalog.GetAllTables() {
+		if database, _ := dictionary.SplitName(tblMeta.Name); database == databaseName {
+			tables = append(tables, tblMeta)
+		}
+	}
+	for _, tblMeta := range tables {
+		if err := discardTable(bp, catalog, tblMeta.FileId, tblMeta.Name); err != nil {
+			return err
+		}
+	}
+
+	var views []string
+	for _, viewMeta := range catalog.GetAllViews() {
+		if database, _ := dictionary.SplitName(viewMeta.Name); database == databaseName {
+			views = append(views, viewMeta.Name)
+		}
+	}
+	for _, viewName := range views {
+		if err := catalog.DeleteView(bp, viewName); err != nil {
+			return err
+		}
+	}
+
+	if _, ok := catalog.GetDatabaseByName(databaseName); ok {
+		if err := catalog.DeleteDatabase(bp, databaseName); err != nil {
+			return err
+		}
+	}
+
+	// ディレクトリを削除する前にカタログの変更を永続化する (先にディレクトリを削除すると、カタログに残ったテーブルのファイルが失われる)
+	if err := bp.FlushAllPages(); err != nil {
+		return err
+	}
+	return os.RemoveAll(filepath.Join(dataDir, databaseName))
+}
+
+// discardTable はカタログからテーブルのメタデータを削除し (削除済みならスキップ)、バッファプールからテーブルのページを破棄して Disk の登録を解除する
+func discardTable(bp *buffer.BufferPool, catalog *dictionary.Catalog, fileId page.FileId, tableName string) error {
+	if tblMeta, ok := catalog.GetTableMetaByName(tableName); ok && tblMeta.FileId == fileId {
+		if err := catalog.Delete(bp, tableName); err != nil {
+			return err
+		}
+	}
+
+	bp.DiscardPages(fileId)
+	if disk, err := bp.UnregisterDisk(fileId); err == nil {
+		if err := disk.Close(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// truncateTable はテーブルのページを破棄してヒープファイルを切り詰め、テーブルとインデックスの B+Tree を空の状態で作り直す
+//
+// B+Tree のメタページはファイルの先頭 (テーブル、インデックスの順) に詰めて作り直し、新しいメタページの位置をカタログに記録する。
+// 後から追加したインデックスのメタページがファイルの後方にあっても、ファイルはメタページとルートノードの分まで縮む
+func truncateTable(bp *buffer.BufferPool, catalog *dictionary.Catalog, record log.DDLRecord) error {
+	tblMeta, ok := catalog.GetTableMetaByName(record.TableName)
+	if !ok || tblMeta.FileId != record.FileId {
+		return fmt.Errorf("recovery: table %s not found in catalog", record.TableName)
+	}
+
+	newMeta := tblMeta.Clone()
+	newMeta.DataMetaPageId = page.NewPageId(record.FileId, 0)
+	for i, idxMeta := range newMeta.Indexes {
+		idxMeta.DataMetaPageId = page.NewPageId(record.FileId, page.PageNumber(i+1))
+	}
+	nPages := page.PageNumber(len(newMeta.Indexes) + 1)
+
+	// メタページより後ろを切り詰め、以降のページ (ルートノードなど) はメタページの直後から採番する
+	bp.DiscardPages(record.FileId)
+	disk, err := bp.GetDisk(record.FileId)
+	if err != nil {
+		return err
+	}
+	if err := disk.Truncate(nPages); err != nil {
+		return err
+	}
+
+	metaPageIds := []page.PageId{newMeta.DataMetaPageId}
+	for _, idxMeta := range newMeta.Indexes {
+		metaPageIds = append(metaPageIds, idxMeta.DataMetaPageId)
+	}
+	for _, metaPageId := range metaPageIds {
+		if _, err := btree.CreateBTree(bp, metaPageId); err != nil {
+			return err
+		}
+	}
+
+	// カタログの更新前にクラッシュした場合は、再起動時に同じ位置へ作り直してからカタログを更新する
+	if !metaPageIdsEqual(tblMeta, &newMeta) {
+		if err := catalog.Update(bp, newMeta); err != nil {
+			return err
+		}
+	}
+	return bp.FlushAllPages()
+}
+
+// metaPageIdsEqual は 2 つのテーブルメタデータでテーブルとインデックスの B+Tree のメタページの位置が一致するかを判定する
+func metaPageIdsEqual(a, b *dictionary.TableMeta) bool {
+	if a.DataMetaPageId != b.DataMetaPageId || len(a.Indexes) != len(b.Indexes) {
+		return false
+	}
+	for i := range a.Indexes {
+		if a.Indexes[i].DataMetaPageId != b.Indexes[i].DataMetaPageId {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,261 @@
+package recovery
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ren-yamanashi/minesql/internal/storage/access"
+	"github.com/ren-yamanashi/minesql/internal/storage/buffer"
+	"github.com/ren-yamanashi/minesql/internal/storage/dictionary"
+	"github.com/ren-yamanashi/minesql/internal/storage/file"
+	"github.com/ren-yamanashi/minesql/internal/storage/lock"
+	"github.com/ren-yamanashi/minesql/internal/storage/log"
+	"github.com/ren-yamanashi/minesql/internal/storage/page"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyDDL(t *testing.T) {
+	t.Run("DROP TABLE でカタログからテーブルを削除し、ヒープファイルを削除する", func(t *testing.T) {
+		// GIVEN
+		env := setupDDLEnv(t)
+
+		// WHEN
+		err := ApplyDDL(env.bp, env.catalog, env.dataDir, log.DDLRecord{Op: log.DDLDropTable, FileId: env.fileId, TableName: "users"})
+
+		// THEN
+		assert.NoError(t, err)
+		_, ok := env.catalog.GetTableMetaByName("users")
+		assert.False(t, ok)
+		_, err = env.bp.GetDisk(env.fileId)
+		assert.Error(t, err)
+		_, err = os.Stat(filepath.Join(env.dataDir, "users.db"))
+		assert.True(t, os.IsNotExist(err))
+
+		// カタログの変更はディスクに永続化されている
+		catalog2 := reopenCatalog(t, env)
+		_, ok = catalog2.GetTableMetaByName("users")
+		assert.False(t, ok)
+	})
+
+	t.Run("DROP TABLE を再実行しても同じ結果になる", func(t *testing.T) {
+		// GIVEN: DROP TABLE 済み
+		env := setupDDLEnv(t)
+		record := log.DDLRecord{Op: log.DDLDropTable, FileId: env.fileId, TableName: "users"}
+		require.NoError(t, ApplyDDL(env.bp, env.catalog, env.dataDir, record))
+
+		// WHEN
+		err := ApplyDDL(env.bp, env.catalog, env.dataDir, record)
+
+		// THEN
+		assert.NoError(t, err)
+	})
+
+	t.Run("TRUNCATE TABLE でテーブルとインデックスを空にし、ヒープファイルを切り詰める", func(t *testing.T) {
+		// GIVEN
+		env := setupDDLEnv(t)
+		sizeBefore := fileSize(t, filepath.Join(env.dataDir, "users.db"))
+
+		// WHEN
+		err := ApplyDDL(env.bp, env.catalog, env.dataDir, log.DDLRecord{Op: log.DDLTruncateTable, FileId: env.fileId, TableName: "users"})
+
+		// THEN
+		assert.NoError(t, err)
+		assert.Less(t, fileSize(t, filepath.Join(env.dataDir, "users.db")), sizeBefore)
+		_, ok := env.catalog.GetTableMetaByName("users")
+		assert.True(t, ok)
+
+		// ディスクから読み直してもテーブルが空で、同じキーのレコードを再び挿入できる
+		bp2 := buffer.NewBufferPool(100, nil)
+		bp2.RegisterDisk(env.fileId, mustGetDisk(t, env.bp, env.fileId))
+		table := env.newTable()
+		assert.Empty(t, scanAll(t, bp2, table))
+		require.NoError(t, table.Insert(bp2, 2, lock.NewManager(5000), [][]byte{[]byte("1"), []byte("Alice")}))
+		assert.Equal(t, [][][]byte{{[]byte("1"), []byte("Alice")}}, scanAll(t, bp2, table))
+	})
+
+	t.Run("TRUNCATE TABLE を再実行しても同じ結果になる", func(t *testing.T) {
+		// GIVEN: TRUNCATE TABLE 済み
+		env := setupDDLEnv(t)
+		record := log.DDLRecord{Op: log.DDLTruncateTable, FileId: env.fileId, TableName: "users"}
+		require.NoError(t, ApplyDDL(env.bp, env.catalog, env.dataDir, record))
+		sizeBefore := fileSize(t, filepath.Join(env.dataDir, "users.db"))
+		metaBefore, ok := env.catalog.GetTableMetaByName("users")
+		require.True(t, ok)
+		metaPageIdBefore := metaBefore.DataMetaPageId
+
+		// WHEN
+		err := ApplyDDL(env.bp, env.catalog, env.dataDir, record)
+
+		// THEN
+		assert.NoError(t, err)
+		assert.Equal(t, sizeBefore, fileSize(t, filepath.Join(env.dataDir, "users.db")))
+		meta, ok := env.catalog.GetTableMetaByName("users")
+		require.True(t, ok)
+		assert.Equal(t, metaPageIdBefore, meta.DataMetaPageId)
+	})
+
+	t.Run("TRUNCATE TABLE の対象がカタログにない場合はエラーを返す", func(t *testing.T) {
+		// GIVEN
+		env := setupDDLEnv(t)
+
+		// WHEN
+		err := ApplyDDL(env.bp, env.catalog, env.dataDir, log.DDLRecord{Op: log.DDLTruncateTable, FileId: env.fileId, TableName: "orders"})
+
+		// THEN
+		assert.ErrorContains(t, err, "table orders not found in catalog")
+	})
+}
+
+func TestResumeDDL(t *testing.T) {
+	t.Run("DDL ログに残っている DDL を実行し、DDL ログをクリアする", func(t *testing.T) {
+		// GIVEN: DROP TABLE の DDL ログを書き込んだ直後にクラッシュした状態
+		env := setupDDLEnv(t)
+		ddlLog, err := log.NewDDLLog(env.dataDir)
+		require.NoError(t, err)
+		require.NoError(t, ddlLog.Write(log.DDLRecord{Op: log.DDLDropTable, FileId: env.fileId, TableName: "users"}))
+		rec := NewRecovery(env.redoLog, env.bp, env.catalog, env.catalog.UndoFileId)
+
+		// WHEN
+		err = rec.ResumeDDL(ddlLog, env.dataDir)
+
+		// THEN
+		assert.NoError(t, err)
+		_, ok := env.catalog.GetTableMetaByName("users")
+		assert.False(t, ok)
+		_, ok, err = ddlLog.Read()
+		assert.NoError(t, err)
+		assert.False(t, ok)
+	})
+
+	t.Run("DDL ログが空の場合は何もしない", func(t *testing.T) {
+		// GIVEN
+		env := setupDDLEnv(t)
+		ddlLog, err := log.NewDDLLog(env.dataDir)
+		require.NoError(t, err)
+		rec := NewRecovery(env.redoLog, env.bp, env.catalog, env.catalog.UndoFileId)
+
+		// WHEN
+		err = rec.ResumeDDL(ddlLog, env.dataDir)
+
+		// THEN
+		assert.NoError(t, err)
+		_, ok := env.catalog.GetTableMetaByName("users")
+		assert.True(t, ok)
+	})
+}
+
+// ddlTestEnv は DDL のテスト用の環境を保持する
+type ddlTestEnv struct {
+	dataDir         string
+	redoLog         *log.RedoLog
+	bp              *buffer.BufferPool
+	catalog         *dictionary.Catalog
+	fileId          page.FileId
+	tableMetaPageId page.PageId
+	indexMetaPageId page.PageId
+}
+
+// setupDDLEnv はセカンダリインデックス付きの users テーブルを作成し、レコードを挿入してフラッシュした環境を返す
+func setupDDLEnv(t *testing.T) *ddlTestEnv {
+	t.Helper()
+	dataDir := t.TempDir()
+	rl, err := log.NewRedoLog(dataDir)
+	require.NoError(t, err)
+	bp := buffer.NewBufferPool(100, rl)
+
+	catalogDisk, err := file.NewDisk(page.FileId(0), filepath.Join(dataDir, "minesql.db"))
+	require.NoError(t, err)
+	bp.RegisterDisk(page.FileId(0), catalogDisk)
+	catalog, err := dictionary.CreateCatalog(bp)
+	require.NoError(t, err)
+
+	fileId, err := catalog.AllocateFileId(bp)
+	require.NoError(t, err)
+	tableDisk, err := file.NewDisk(fileId, filepath.Join(dataDir, "users.db"))
+	require.NoError(t, err)
+	bp.RegisterDisk(fileId, tableDisk)
+
+	tableMetaPageId, err := bp.AllocatePageId(fileId)
+	require.NoError(t, err)
+	indexMetaPageId, err := bp.AllocatePageId(fileId)
+	require.NoError(t, err)
+	env := &ddlTestEnv{
+		dataDir:         dataDir,
+		redoLog:         rl,
+		bp:              bp,
+		catalog:         catalog,
+		fileId:          fileId,
+		tableMetaPageId: tableMetaPageId,
+		indexMetaPageId: indexMetaPageId,
+	}
+
+	table := env.newTable()
+	require.NoError(t, table.Create(bp))
+	colMeta := []*dictionary.ColumnMeta{
+		dictionary.NewColumnMeta(fileId, "id", 0, dictionary.ColumnTypeString),
+		dictionary.NewColumnMeta(fileId, "name", 1, dictionary.ColumnTypeString),
+	}
+	idxMeta := []*dictionary.IndexMeta{
+		dictionary.NewIndexMeta(fileId, "idx_name", []string{"name"}, dictionary.IndexTypeUnique, indexMetaPageId),
+	}
+	require.NoError(t, catalog.Insert(bp, dictionary.NewTableMeta(fileId, "users", 2, 1, colMeta, idxMeta, tableMetaPageId)))
+
+	// 複数ページにまたがるようにレコードを挿入する
+	lockMgr := lock.NewManager(5000)
+	for i := range 200 {
+		id := []byte{byte('a' + i/26), byte('a' + i%26)}
+		require.NoError(t, table.Insert(bp, 1, lockMgr, [][]byte{id, append([]byte("name-"), id...)}))
+	}
+	require.NoError(t, bp.FlushAllPages())
+	require.NoError(t, rl.Reset())
+	return env
+}
+
+// newTable は users テーブルの Table を構築する
+func (env *ddlTestEnv) newTable() *access.Table {
+	si := access.NewSecondaryIndex("idx_name", []string{"name"}, env.indexMetaPageId, []uint16{1}, 1, true)
+	table := access.NewTable("users", env.tableMetaPageId, 1, []*access.SecondaryIndex{si}, nil, nil)
+	return &table
+}
+
+// reopenCatalog はディスクからカタログを読み直す
+func reopenCatalog(t *testing.T, env *ddlTestEnv) *dictionary.Catalog {
+	t.Helper()
+	bp := buffer.NewBufferPool(100, nil)
+	bp.RegisterDisk(page.FileId(0), mustGetDisk(t, env.bp, page.FileId(0)))
+	catalog, err := dictionary.NewCatalog(bp)
+	require.NoError(t, err)
+	return catalog
+}
+
+func mustGetDisk(t *testing.T, bp *buffer.BufferPool, fileId page.FileId) *file.Disk {
+	t.Helper()
+	disk, err := bp.GetDisk(fileId)
+	require.NoError(t, err)
+	return disk
+}
+
+// scanAll はテーブルの全レコードを返す
+func scanAll(t *testing.T, bp *buffer.BufferPool, table *access.Table) [][][]byte {
+	t.Helper()
+	iter, err := table.Search(bp, access.NewReadView(0, nil, ^uint64(0)), access.NewVersionReader(nil), access.RecordSearchModeStart{})
+	require.NoError(t, err)
+	var records [][][]byte
+	for {
+		record, ok, err := iter.Next()
+		require.NoError(t, err)
+		if !ok {
+			return records
+		}
+		records = append(records, record)
+	}
+}
+
+func fileSize(t *testing.T, path string) int64 {
+	t.Helper()
+	stat, err := os.Stat(path)
+	require.NoError(t, err)
+	return stat.Size()
+}
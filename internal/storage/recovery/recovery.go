@@ -17,6 +17,7 @@ type undoRecordEntry struct {
 	recordType       access.UndoRecordType
 	prevLastModified lock.TrxId
 	prevRollPtr      access.UndoPtr
+	metaPageId       page.PageId
 	tableName        string
 	columns          [][][]byte
 }
@@ -85,6 +86,11 @@ func (r *Recovery) redoApply(records []log.RedoRecord) error {
 			continue
 		}
 
+		// Disk が登録されていない (= DROP TABLE 済みのテーブルの) ページはスキップ
+		if _, err := r.bufferPool.GetDisk(rec.PageId.FileId); err != nil {
+			continue
+		}
+
 		// REDO レコードの PageId からページを取得
 		readData, err := r.bufferPool.GetReadPageData(rec.PageId)
 		if err != nil {
@@ -144,8 +150,15 @@ func (r *Recovery) rollbackTransaction(trxId uint64) error {
 	for i := len(undoRecords) - 1; i >= 0; i-- {
 		rec := undoRecords[i]
 
+		// 削除済み・作り直し済みの B+Tree への変更はロールバック不要
+		// (同じ名前で作成し直したテーブルは FileId が異なり、ALTER TABLE で作り直したテーブルはメタページが異なる)
+		tblMeta, ok := r.findTableMeta(rec.metaPageId)
+		if !ok {
+			continue
+		}
+
 		// カタログからテーブルを構築 (undoLog=nil, redoLog=nil でリカバリ中に新たな UNDO/REDO を記録しない)
-		table, err := r.buildTable(rec.tableName)
+		table, err := r.buildTable(tblMeta)
 		if err != nil {
 			return fmt.Errorf("recovery: failed to build table %s: %w", rec.tableName, err)
 		}
@@ -200,6 +213,7 @@ func (r *Recovery) collectUndoRecords(trxId uint64) []undoRecordEntry {
 					recordType:       f.RecordType,
 					prevLastModified: f.PrevLastModified,
 					prevRollPtr:      f.PrevRollPtr,
+					metaPageId:       f.MetaPageId,
 					tableName:        f.TableName,
 					columns:          f.ColumnSets,
 				})
@@ -218,22 +232,32 @@ func (r *Recovery) collectUndoRecords(trxId uint64) []undoRecordEntry {
 	return records
 }
 
-// buildTable はカタログからテーブルメタデータを取得し、リカバリ用の Table を構築する
-func (r *Recovery) buildTable(tableName string) (*access.Table, error) {
-	tblMeta, ok := r.catalog.GetTableMetaByName(tableName)
-	if !ok {
-		return nil, fmt.Errorf("table %s not found in catalog", tableName)
+// findTableMeta は B+Tree のメタページの ID が一致するテーブルメタデータを取得する
+//
+// FileId が登録されていない (テーブルが削除済みの) 場合や、メタページが一致するテーブルがない場合は false を返す
+func (r *Recovery) findTableMeta(metaPageId page.PageId) (*dictionary.TableMeta, bool) {
+	if _, err := r.bufferPool.GetDisk(metaPageId.FileId); err != nil {
+		return nil, false
+	}
+	for _, tblMeta := range r.catalog.GetAllTables() {
+		if tblMeta.DataMetaPageId == metaPageId {
+			return tblMeta, true
+		}
 	}
+	return nil, false
+}
 
+// buildTable はテーブルメタデータから、リカバリ用の Table を構築する
+func (r *Recovery) buildTable(tblMeta *dictionary.TableMeta) (*access.Table, error) {
 	var secondaryIndexes []*access.SecondaryIndex
 	for _, idxMeta := range tblMeta.Indexes {
 		isUnique := idxMeta.Type == dictionary.IndexTypeUnique
 		if idxMeta.Type == dictionary.IndexTypeUnique || idxMeta.Type == dictionary.IndexTypeNonUnique {
-			colMeta, ok := tblMeta.GetColByName(idxMeta.ColName)
-			if !ok {
-				return nil, fmt.Errorf("column %s not found in table %s", idxMeta.ColName, tableName)
+			colIdxs, err := tblMeta.GetColPositions(idxMeta.ColNames)
+			if err != nil {
+				return nil, err
 			}
-			si := access.NewSecondaryIndex(idxMeta.Name, idxMeta.ColName, idxMeta.DataMetaPageId, colMeta.Pos, tblMeta.PKCount, isUnique)
+			si := access.NewSecondaryIndex(idxMeta.Name, idxMeta.ColNames, idxMeta.DataMetaPageId, colIdxs, tblMeta.PKCount, isUnique)
 			secondaryIndexes = append(secondaryIndexes, si)
 		}
 	}
@@ -150,6 +150,26 @@ func TestRedoApply(t *testing.T) {
 		assert.NoError(t, err)
 		assert.Equal(t, byte(0xAA), restoredData[page.PageHeaderSize])
 	})
+
+	t.Run("Disk が登録されていない (DROP TABLE 済みの) ページの REDO レコードはスキップ", func(t *testing.T) {
+		// GIVEN
+		tmpdir := t.TempDir()
+		rl, err := log.NewRedoLog(tmpdir)
+		assert.NoError(t, err)
+		rl.AppendPageCopy(1, page.NewPageId(page.FileId(5), 0), make([]byte, page.PageSize))
+		err = rl.Flush()
+		assert.NoError(t, err)
+
+		bp := buffer.NewBufferPool(10, nil)
+		rec := NewRecovery(rl, bp, nil, page.FileId(0))
+
+		// WHEN
+		err = rec.Run()
+
+		// THEN
+		assert.NoError(t, err)
+		assert.False(t, bp.IsPageCached(page.NewPageId(page.FileId(5), 0)))
+	})
 }
 
 func TestUndoRollback(t *testing.T) {
@@ -351,7 +371,7 @@ func TestUndoRollback(t *testing.T) {
 		// セカンダリインデックスの B+Tree を作成
 		idxMetaPageId, err := bp.AllocatePageId(tableFileId)
 		assert.NoError(t, err)
-		si := access.NewSecondaryIndex("idx_name", "name", idxMetaPageId, 1, 1, true)
+		si := access.NewSecondaryIndex("idx_name", []string{"name"}, idxMetaPageId, []uint16{1}, 1, true)
 		err = si.Create(bp)
 		assert.NoError(t, err)
 
@@ -368,7 +388,7 @@ func TestUndoRollback(t *testing.T) {
 			dictionary.NewColumnMeta(tableFileId, "name", 1, dictionary.ColumnTypeString),
 		}
 		idxMeta := []*dictionary.IndexMeta{
-			dictionary.NewIndexMeta(tableFileId, "idx_name", "name", dictionary.IndexTypeUnique, si.MetaPageId),
+			dictionary.NewIndexMeta(tableFileId, "idx_name", []string{"name"}, dictionary.IndexTypeUnique, si.MetaPageId),
 		}
 		tblMeta := dictionary.NewTableMeta(tableFileId, "users", 2, 1, colMeta, idxMeta, metaPageId)
 		err = catalog.Insert(bp, tblMeta)
@@ -403,7 +423,7 @@ func TestUndoRollback(t *testing.T) {
 
 		// THEN: テーブルが空になっている
 		table2 := access.NewTable("users", metaPageId, 1, []*access.SecondaryIndex{
-			access.NewSecondaryIndex("idx_name", "name", si.MetaPageId, 1, 1, true),
+			access.NewSecondaryIndex("idx_name", []string{"name"}, si.MetaPageId, []uint16{1}, 1, true),
 		}, nil, nil)
 		iter, err := table2.Search(bp2, access.NewReadView(0, nil, ^uint64(0)), access.NewVersionReader(nil), access.RecordSearchModeStart{})
 		assert.NoError(t, err)
@@ -411,4 +431,154 @@ func TestUndoRollback(t *testing.T) {
 		assert.NoError(t, err)
 		assert.False(t, ok)
 	})
+	t.Run("カタログにない (DROP TABLE 済みの) テーブルの変更はロールバックしない", func(t *testing.T) {
+		// GIVEN: テーブル作成 → INSERT → COMMIT なしで REDO ログにレコードを残す
+		tmpdir := t.TempDir()
+		rl, err := log.NewRedoLog(tmpdir)
+		assert.NoError(t, err)
+		bp := buffer.NewBufferPool(100, rl)
+
+		catalogDisk, err := file.NewDisk(page.FileId(0), filepath.Join(tmpdir, "minesql.db"))
+		assert.NoError(t, err)
+		bp.RegisterDisk(page.FileId(0), catalogDisk)
+		catalog, err := dictionary.CreateCatalog(bp)
+		assert.NoError(t, err)
+
+		tableFileId, err := catalog.AllocateFileId(bp)
+		assert.NoError(t, err)
+		tableDisk, err := file.NewDisk(tableFileId, filepath.Join(tmpdir, "users.db"))
+		assert.NoError(t, err)
+		bp.RegisterDisk(tableFileId, tableDisk)
+
+		undoFileId := catalog.UndoFileId
+		undoDisk, err := file.NewDisk(undoFileId, filepath.Join(tmpdir, "undo.db"))
+		assert.NoError(t, err)
+		bp.RegisterDisk(undoFileId, undoDisk)
+		undoLog, err := access.NewUndoManager(bp, rl, undoFileId)
+		assert.NoError(t, err)
+
+		metaPageId, err := bp.AllocatePageId(tableFileId)
+		assert.NoError(t, err)
+		table := access.NewTable("users", metaPageId, 1, nil, undoLog, rl)
+		err = table.Create(bp)
+		assert.NoError(t, err)
+		colMeta := []*dictionary.ColumnMeta{
+			dictionary.NewColumnMeta(tableFileId, "id", 0, dictionary.ColumnTypeString),
+			dictionary.NewColumnMeta(tableFileId, "name", 1, dictionary.ColumnTypeString),
+		}
+		err = catalog.Insert(bp, dictionary.NewTableMeta(tableFileId, "users", 2, 1, colMeta, nil, metaPageId))
+		assert.NoError(t, err)
+		err = bp.FlushAllPages()
+		assert.NoError(t, err)
+		err = rl.Reset()
+		assert.NoError(t, err)
+
+		err = table.Insert(bp, 1, lock.NewManager(5000), [][]byte{[]byte("a"), []byte("Alice")})
+		assert.NoError(t, err)
+		err = rl.Flush()
+		assert.NoError(t, err)
+
+		// テーブルを削除した状態で再起動する (テーブルの Disk は登録しない)
+		bp2 := buffer.NewBufferPool(100, nil)
+		bp2.RegisterDisk(page.FileId(0), catalogDisk)
+		bp2.RegisterDisk(undoFileId, undoDisk)
+		catalog2, err := dictionary.NewCatalog(bp2)
+		assert.NoError(t, err)
+		err = catalog2.Delete(bp2, "users")
+		assert.NoError(t, err)
+
+		rec := NewRecovery(rl, bp2, catalog2, undoFileId)
+
+		// WHEN
+		err = rec.Run()
+
+		// THEN
+		assert.NoError(t, err)
+	})
+	t.Run("同じ名前で作成し直したテーブルには、削除前のテーブルの変更をロールバックしない", func(t *testing.T) {
+		// GIVEN: テーブル作成 → INSERT → COMMIT なしで REDO ログにレコードを残す
+		tmpdir := t.TempDir()
+		rl, err := log.NewRedoLog(tmpdir)
+		assert.NoError(t, err)
+		bp := buffer.NewBufferPool(100, rl)
+
+		catalogDisk, err := file.NewDisk(page.FileId(0), filepath.Join(tmpdir, "minesql.db"))
+		assert.NoError(t, err)
+		bp.RegisterDisk(page.FileId(0), catalogDisk)
+		catalog, err := dictionary.CreateCatalog(bp)
+		assert.NoError(t, err)
+
+		oldFileId, err := catalog.AllocateFileId(bp)
+		assert.NoError(t, err)
+		oldDisk, err := file.NewDisk(oldFileId, filepath.Join(tmpdir, "users.db"))
+		assert.NoError(t, err)
+		bp.RegisterDisk(oldFileId, oldDisk)
+
+		undoFileId := catalog.UndoFileId
+		undoDisk, err := file.NewDisk(undoFileId, filepath.Join(tmpdir, "undo.db"))
+		assert.NoError(t, err)
+		bp.RegisterDisk(undoFileId, undoDisk)
+		undoLog, err := access.NewUndoManager(bp, rl, undoFileId)
+		assert.NoError(t, err)
+
+		oldMetaPageId, err := bp.AllocatePageId(oldFileId)
+		assert.NoError(t, err)
+		oldTable := access.NewTable("users", oldMetaPageId, 1, nil, undoLog, rl)
+		err = oldTable.Create(bp)
+		assert.NoError(t, err)
+		err = bp.FlushAllPages()
+		assert.NoError(t, err)
+		err = rl.Reset()
+		assert.NoError(t, err)
+
+		err = oldTable.Insert(bp, 1, lock.NewManager(5000), [][]byte{[]byte("a"), []byte("Alice")})
+		assert.NoError(t, err)
+		err = rl.Flush()
+		assert.NoError(t, err)
+
+		// 同じ名前のテーブルを別のファイルに作成し、同じキーのレコードを挿入する
+		newFileId, err := catalog.AllocateFileId(bp)
+		assert.NoError(t, err)
+		newDisk, err := file.NewDisk(newFileId, filepath.Join(tmpdir, "users2.db"))
+		assert.NoError(t, err)
+		bp.RegisterDisk(newFileId, newDisk)
+		newMetaPageId, err := bp.AllocatePageId(newFileId)
+		assert.NoError(t, err)
+		newTable := access.NewTable("users", newMetaPageId, 1, nil, nil, nil)
+		err = newTable.Create(bp)
+		assert.NoError(t, err)
+		err = newTable.Insert(bp, 2, lock.NewManager(5000), [][]byte{[]byte("a"), []byte("Bob")})
+		assert.NoError(t, err)
+		err = bp.FlushAllPages()
+		assert.NoError(t, err)
+
+		// 削除前のテーブルの Disk は登録せずに再起動し、カタログには作成し直したテーブルだけを登録する
+		bp2 := buffer.NewBufferPool(100, nil)
+		bp2.RegisterDisk(page.FileId(0), catalogDisk)
+		bp2.RegisterDisk(undoFileId, undoDisk)
+		bp2.RegisterDisk(newFileId, newDisk)
+		catalog2, err := dictionary.NewCatalog(bp2)
+		assert.NoError(t, err)
+		colMeta := []*dictionary.ColumnMeta{
+			dictionary.NewColumnMeta(newFileId, "id", 0, dictionary.ColumnTypeString),
+			dictionary.NewColumnMeta(newFileId, "name", 1, dictionary.ColumnTypeString),
+		}
+		err = catalog2.Insert(bp2, dictionary.NewTableMeta(newFileId, "users", 2, 1, colMeta, nil, newMetaPageId))
+		assert.NoError(t, err)
+
+		rec := NewRecovery(rl, bp2, catalog2, undoFileId)
+
+		// WHEN
+		err = rec.Run()
+
+		// THEN: 作成し直したテーブルのレコードは削除されない
+		assert.NoError(t, err)
+		table2 := access.NewTable("users", newMetaPageId, 1, nil, nil, nil)
+		iter, err := table2.Search(bp2, access.NewReadView(0, nil, ^uint64(0)), access.NewVersionReader(nil), access.RecordSearchModeStart{})
+		assert.NoError(t, err)
+		record, ok, err := iter.Next()
+		assert.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, [][]byte{[]byte("a"), []byte("Bob")}, record)
+	})
 }